@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	pq "github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
+)
+
+const (
+	usageDayLayout = "2006-01-02"
+
+	// the span id column has the same path in all vParquet versions
+	usageSpanIDColumnPath = "rs.list.element.ss.list.element.Spans.list.element.SpanID"
+)
+
+type analyseTenantUsageCmd struct {
+	backendOptions
+
+	TenantIDs  []string `arg:"" optional:"" help:"tenant-ids within the bucket, all tenants if empty"`
+	Start      string   `help:"Start of the time range, blocks that end before are skipped. RFC3339 format '2006-01-02T15:04:05Z07:00'" default:""`
+	End        string   `help:"End of the time range, blocks that start after are skipped. RFC3339 format '2006-01-02T15:04:05Z07:00'" default:""`
+	Format     string   `help:"Output format of the report" enum:"csv,json" default:"csv"`
+	CountSpans bool     `name:"count-spans" help:"Count the spans of vParquet blocks from the parquet footers, reads the footer of every block" default:"false"`
+}
+
+// tenantUsage is the usage of a tenant on a day. Blocks are attributed to the UTC day they start on.
+type tenantUsage struct {
+	Tenant string `json:"tenant"`
+	Day    string `json:"day"`
+	Blocks int    `json:"blocks"`
+	Bytes  uint64 `json:"bytes"`
+	Traces int64  `json:"traces"`
+	Spans  int64  `json:"spans"`
+}
+
+func (cmd *analyseTenantUsageCmd) Run(opts *globalOptions) error {
+	var start, end time.Time
+	var err error
+	if cmd.Start != "" {
+		if start, err = time.Parse(time.RFC3339, cmd.Start); err != nil {
+			return err
+		}
+	}
+	if cmd.End != "" {
+		if end, err = time.Parse(time.RFC3339, cmd.End); err != nil {
+			return err
+		}
+	}
+
+	r, _, _, err := loadBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+	defer r.Shutdown()
+
+	ctx := context.Background()
+
+	tenants := cmd.TenantIDs
+	if len(tenants) == 0 {
+		tenants, err = r.Tenants(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	var usage []tenantUsage
+	for _, tenant := range tenants {
+		metas, err := loadBlockMetas(ctx, r, tenant)
+		if err != nil {
+			return fmt.Errorf("failed to load blocks of tenant %s: %w", tenant, err)
+		}
+
+		var spans map[backend.UUID]int64
+		if cmd.CountSpans {
+			spans = map[backend.UUID]int64{}
+			for _, meta := range metas {
+				if !blockInRange(meta, start, end) {
+					continue
+				}
+				n, err := countBlockSpans(ctx, r, meta)
+				if err != nil {
+					return fmt.Errorf("failed to count spans of block %s: %w", meta.BlockID, err)
+				}
+				spans[meta.BlockID] = n
+			}
+		}
+
+		usage = append(usage, aggregateTenantUsage(tenant, metas, spans, start, end)...)
+	}
+
+	return writeTenantUsage(os.Stdout, usage, cmd.Format)
+}
+
+// aggregateTenantUsage sums the blocks of a tenant in the time range per day. The spans are added if counted.
+func aggregateTenantUsage(tenant string, metas []*backend.BlockMeta, spans map[backend.UUID]int64, start, end time.Time) []tenantUsage {
+	days := map[string]*tenantUsage{}
+	for _, meta := range metas {
+		if !blockInRange(meta, start, end) {
+			continue
+		}
+
+		day := meta.StartTime.UTC().Format(usageDayLayout)
+		u, ok := days[day]
+		if !ok {
+			u = &tenantUsage{Tenant: tenant, Day: day}
+			days[day] = u
+		}
+		u.Blocks++
+		u.Bytes += meta.Size_
+		u.Traces += meta.TotalObjects
+		u.Spans += spans[meta.BlockID]
+	}
+
+	usage := make([]tenantUsage, 0, len(days))
+	for _, u := range days {
+		usage = append(usage, *u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Day < usage[j].Day })
+
+	return usage
+}
+
+func blockInRange(meta *backend.BlockMeta, start, end time.Time) bool {
+	if !start.IsZero() && meta.EndTime.Before(start) {
+		return false
+	}
+	if !end.IsZero() && meta.StartTime.After(end) {
+		return false
+	}
+	return true
+}
+
+// countBlockSpans returns the number of span ids in the footer of a vParquet block. Other encodings return 0.
+func countBlockSpans(ctx context.Context, r backend.Reader, meta *backend.BlockMeta) (int64, error) {
+	if !strings.HasPrefix(meta.Version, "vParquet") {
+		return 0, nil
+	}
+
+	rr := vparquet4.NewBackendReaderAt(ctx, r, vparquet4.DataFileName, meta)
+	pf, err := parquet.OpenFile(rr, int64(meta.Size_), parquet.SkipPageIndex(true), parquet.SkipBloomFilters(true))
+	if err != nil {
+		return 0, err
+	}
+
+	idx, _ := pq.GetColumnIndexByPath(pf, usageSpanIDColumnPath)
+	if idx < 0 {
+		return 0, fmt.Errorf("column %s not found", usageSpanIDColumnPath)
+	}
+
+	var spans int64
+	for _, rg := range pf.Metadata().RowGroups {
+		md := rg.Columns[idx].MetaData
+		// traces without spans have a null span id
+		spans += md.NumValues - md.Statistics.NullCount
+	}
+
+	return spans, nil
+}
+
+func writeTenantUsage(w io.Writer, usage []tenantUsage, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(usage)
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"tenant", "day", "blocks", "bytes", "traces", "spans"}); err != nil {
+		return err
+	}
+	for _, u := range usage {
+		if err := cw.Write([]string{
+			u.Tenant,
+			u.Day,
+			strconv.Itoa(u.Blocks),
+			strconv.FormatUint(u.Bytes, 10),
+			strconv.FormatInt(u.Traces, 10),
+			strconv.FormatInt(u.Spans, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestAggregateTenantUsage(t *testing.T) {
+	day1 := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	meta := func(start time.Time, size uint64, traces int64) *backend.BlockMeta {
+		m := backend.NewBlockMeta("foo", uuid.New(), "vParquet4", backend.EncNone, "")
+		m.StartTime = start
+		m.EndTime = start.Add(time.Hour)
+		m.Size_ = size
+		m.TotalObjects = traces
+		return m
+	}
+	metas := []*backend.BlockMeta{
+		meta(day2, 100, 10),
+		meta(day1, 10, 1),
+		meta(day1.Add(time.Hour), 20, 2),
+		meta(day2.Add(24*time.Hour), 1000, 100),
+	}
+	spans := map[backend.UUID]int64{metas[1].BlockID: 5, metas[2].BlockID: 7}
+
+	usage := aggregateTenantUsage("foo", metas, spans, time.Time{}, day2.Add(2*time.Hour))
+	require.Equal(t, []tenantUsage{
+		{Tenant: "foo", Day: "2024-01-01", Blocks: 2, Bytes: 30, Traces: 3, Spans: 12},
+		{Tenant: "foo", Day: "2024-01-02", Blocks: 1, Bytes: 100, Traces: 10},
+	}, usage)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, writeTenantUsage(buf, usage, "csv"))
+	require.Equal(t, "tenant,day,blocks,bytes,traces,spans\nfoo,2024-01-01,2,30,3,12\nfoo,2024-01-02,1,100,10,0\n", buf.String())
+}
+
+func TestCountBlockSpans(t *testing.T) {
+	const tenantID = "single-tenant"
+	dir := t.TempDir()
+
+	generateTestBlocks(t, dir, tenantID, 1, 5)
+
+	rawR, _, _, err := local.New(&local.Config{Path: dir})
+	require.NoError(t, err)
+	r := backend.NewReader(rawR)
+	ctx := context.Background()
+
+	metas, err := loadBlockMetas(ctx, r, tenantID)
+	require.NoError(t, err)
+	require.Len(t, metas, 1)
+
+	spans, err := countBlockSpans(ctx, r, metas[0])
+	require.NoError(t, err)
+
+	blockIDs, _, err := r.Blocks(ctx, tenantID)
+	require.NoError(t, err)
+	summary, err := summarizeBlocks(ctx, r, nil, tenantID, blockIDs[0].String())
+	require.NoError(t, err)
+	require.Equal(t, int64(summary.spans), spans)
+}
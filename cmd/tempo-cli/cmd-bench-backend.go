@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"math"
+	mathrand "math/rand"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+	"github.com/olekukonko/tablewriter"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type benchBackendCmd struct {
+	backendOptions
+
+	Prefix      string `help:"prefix in the bucket under which the benchmark objects are written" default:"tempo-cli-bench"`
+	Objects     int    `help:"number of objects to write, read and delete" default:"100"`
+	ObjectSize  int64  `name:"object-size" help:"size of the objects in bytes" default:"1048576"`
+	RangeSize   int    `name:"range-size" help:"size of the ranges read from the objects in bytes" default:"65536"`
+	Lists       int    `help:"number of list requests" default:"10"`
+	Concurrency int    `help:"number of concurrent requests" default:"10"`
+	Keep        bool   `help:"keep the benchmark objects instead of deleting them" default:"false"`
+}
+
+type benchResult struct {
+	workload  string
+	ops       int
+	errors    int
+	firstErr  error
+	bytes     int64
+	duration  time.Duration
+	latencies []time.Duration
+}
+
+func (cmd *benchBackendCmd) Run(opts *globalOptions) error {
+	if cmd.Objects <= 0 || cmd.ObjectSize <= 0 || cmd.Concurrency <= 0 {
+		return fmt.Errorf("objects, object size and concurrency must be greater than 0")
+	}
+	rangeSize := int64(cmd.RangeSize)
+	if rangeSize <= 0 || rangeSize > cmd.ObjectSize {
+		rangeSize = cmd.ObjectSize
+	}
+
+	r, w, _, err := loadRawBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+	defer r.Shutdown()
+
+	ctx := context.Background()
+
+	// every run writes to its own keypath so runs don't interfere
+	keypath := backend.KeyPath{cmd.Prefix, uuid.New().String()}
+	name := func(i int) string { return fmt.Sprintf("object-%06d", i) }
+
+	data := make([]byte, cmd.ObjectSize)
+	if _, err := rand.Read(data); err != nil {
+		return err
+	}
+
+	fmt.Printf("Benchmarking %d objects of %s with %d concurrent requests in %s\n", cmd.Objects, humanize.IBytes(uint64(cmd.ObjectSize)), cmd.Concurrency, backend.ObjectFileName(keypath, ""))
+
+	results := []benchResult{
+		cmd.run(ctx, "write", cmd.Objects, func(ctx context.Context, i int) (int64, error) {
+			err := w.Write(ctx, name(i), keypath, bytes.NewReader(data), cmd.ObjectSize, nil)
+			return cmd.ObjectSize, err
+		}),
+		cmd.run(ctx, "read", cmd.Objects, func(ctx context.Context, i int) (int64, error) {
+			rc, _, err := r.Read(ctx, name(i), keypath, nil)
+			if err != nil {
+				return 0, err
+			}
+			defer rc.Close()
+			return io.Copy(io.Discard, rc)
+		}),
+		cmd.run(ctx, "read-range", cmd.Objects, func(ctx context.Context, i int) (int64, error) {
+			buffer := make([]byte, rangeSize)
+			offset := mathrand.Int63n(cmd.ObjectSize - rangeSize + 1)
+			err := r.ReadRange(ctx, name(i), keypath, uint64(offset), buffer, nil)
+			return rangeSize, err
+		}),
+		cmd.run(ctx, "list", cmd.Lists, func(ctx context.Context, _ int) (int64, error) {
+			found := 0
+			err := r.Find(ctx, keypath, func(backend.FindMatch) { found++ })
+			if err == nil && found != cmd.Objects {
+				err = fmt.Errorf("listed %d objects, expected %d", found, cmd.Objects)
+			}
+			return 0, err
+		}),
+	}
+
+	if !cmd.Keep {
+		results = append(results, cmd.run(ctx, "delete", cmd.Objects, func(ctx context.Context, i int) (int64, error) {
+			return 0, w.Delete(ctx, name(i), keypath, nil)
+		}))
+	}
+
+	printBenchResults(results)
+
+	failed := 0
+	for _, res := range results {
+		if res.firstErr != nil {
+			fmt.Printf("%s: %d requests failed, first error: %v\n", res.workload, res.errors, res.firstErr)
+			failed += res.errors
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d requests failed", failed)
+	}
+
+	return nil
+}
+
+// run executes the request n times with the configured concurrency and measures the latency of every request.
+func (cmd *benchBackendCmd) run(ctx context.Context, workload string, n int, req func(context.Context, int) (int64, error)) benchResult {
+	res := benchResult{
+		workload:  workload,
+		ops:       n,
+		latencies: make([]time.Duration, 0, n),
+	}
+
+	var (
+		mtx sync.Mutex
+		wg  sync.WaitGroup
+	)
+	ch := make(chan int)
+
+	start := time.Now()
+	for j := 0; j < cmd.Concurrency; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range ch {
+				reqStart := time.Now()
+				b, err := req(ctx, i)
+				latency := time.Since(reqStart)
+
+				mtx.Lock()
+				res.latencies = append(res.latencies, latency)
+				res.bytes += b
+				if err != nil {
+					res.errors++
+					if res.firstErr == nil {
+						res.firstErr = err
+					}
+				}
+				mtx.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		ch <- i
+	}
+	close(ch)
+	wg.Wait()
+	res.duration = time.Since(start)
+
+	return res
+}
+
+func printBenchResults(results []benchResult) {
+	out := make([][]string, 0, len(results))
+	for _, res := range results {
+		sort.Slice(res.latencies, func(i, j int) bool { return res.latencies[i] < res.latencies[j] })
+
+		seconds := res.duration.Seconds()
+		out = append(out, []string{
+			res.workload,
+			fmt.Sprint(res.ops),
+			fmt.Sprint(res.errors),
+			fmt.Sprintf("%.1f", float64(res.ops)/seconds),
+			humanize.IBytes(uint64(float64(res.bytes)/seconds)) + "/s",
+			percentile(res.latencies, 0.5).String(),
+			percentile(res.latencies, 0.9).String(),
+			percentile(res.latencies, 0.99).String(),
+			percentile(res.latencies, 1).String(),
+		})
+	}
+
+	w := tablewriter.NewWriter(os.Stdout)
+	w.SetHeader([]string{"workload", "requests", "errors", "requests/s", "throughput", "p50", "p90", "p99", "max"})
+	w.AppendBulk(out)
+	w.Render()
+}
+
+// percentile returns the q-th percentile of the sorted latencies.
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return sorted[i]
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBenchBackendCmd(t *testing.T) {
+	dir := t.TempDir()
+
+	cmd := benchBackendCmd{
+		backendOptions: backendOptions{
+			Backend: "local",
+			Bucket:  dir,
+		},
+		Prefix:      "bench",
+		Objects:     10,
+		ObjectSize:  1000,
+		RangeSize:   100,
+		Lists:       2,
+		Concurrency: 3,
+	}
+	require.NoError(t, cmd.Run(&globalOptions{}))
+
+	// the objects are deleted after the benchmark
+	var objects []string
+	require.NoError(t, filepath.WalkDir(filepath.Join(dir, "bench"), func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			objects = append(objects, path)
+		}
+		return err
+	}))
+	require.Empty(t, objects)
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	require.Equal(t, time.Duration(0), percentile(nil, 0.5))
+	require.Equal(t, time.Duration(5), percentile(latencies, 0.5))
+	require.Equal(t, time.Duration(9), percentile(latencies, 0.9))
+	require.Equal(t, time.Duration(10), percentile(latencies, 0.99))
+	require.Equal(t, time.Duration(10), percentile(latencies, 1))
+	require.Equal(t, time.Duration(1), percentile(latencies, 0))
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type compactorPlanCmd struct {
+	TenantID string `arg:"" help:"tenant-id within the bucket"`
+	backendOptions
+
+	CompactionWindow     time.Duration `name:"compaction-window" help:"time window across which blocks are compacted" default:"1h"`
+	MaxBlockBytes        uint64        `name:"max-block-bytes" help:"maximum size of a compacted block in bytes" default:"107374182400"`
+	MaxCompactionObjects int           `name:"max-compaction-objects" help:"maximum number of traces in a compacted block" default:"6000000"`
+	MaxJobMemoryBytes    uint64        `name:"max-job-memory-bytes" help:"maximum estimated memory of a compaction job in bytes, 0 to disable" default:"0"`
+	MaxBytesPerTrace     int           `name:"max-bytes-per-trace" help:"maximum size of a trace in bytes, used to estimate the memory of a job" default:"5000000"`
+}
+
+func (cmd *compactorPlanCmd) Run(opts *globalOptions) error {
+	if cmd.CompactionWindow <= 0 {
+		return errors.New("compaction window must be greater than 0")
+	}
+
+	r, _, _, err := loadBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+	defer r.Shutdown()
+
+	metas, err := loadBlockMetas(context.Background(), r, cmd.TenantID)
+	if err != nil {
+		return err
+	}
+
+	budget := tempodb.JobMemoryBudget{
+		MaxBytes:           cmd.MaxJobMemoryBytes,
+		IteratorBufferSize: tempodb.DefaultIteratorBufferSize,
+		FlushSizeBytes:     tempodb.DefaultFlushSizeBytes,
+		MaxBytesPerTrace:   cmd.MaxBytesPerTrace,
+	}
+
+	plan := tempodb.PlanCompaction(cmd.TenantID, metas, cmd.CompactionWindow, cmd.MaxCompactionObjects, cmd.MaxBlockBytes, budget, nil)
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(plan)
+}
+
+// loadBlockMetas returns the metas of the tenant index, which the compactors select the blocks from. If the tenant
+// has no index the metas of all blocks are read instead.
+func loadBlockMetas(ctx context.Context, r backend.Reader, tenantID string) ([]*backend.BlockMeta, error) {
+	index, err := r.TenantIndex(ctx, tenantID)
+	if err == nil {
+		return index.Meta, nil
+	}
+	if !errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, fmt.Errorf("failed to read tenant index: %w", err)
+	}
+
+	blockIDs, _, err := r.Blocks(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]*backend.BlockMeta, 0, len(blockIDs))
+	for _, id := range blockIDs {
+		meta, err := r.BlockMeta(ctx, id, tenantID)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			// compacted or deleted since listing
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read meta of block %s: %w", id, err)
+		}
+		metas = append(metas, meta)
+	}
+
+	return metas, nil
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/olekukonko/tablewriter"
+
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+type diffBlocksCmd struct {
+	backendOptions
+
+	TenantID string `arg:"" help:"tenant-id within the bucket"`
+	BlocksA  string `arg:"" help:"block ID, or comma-separated block IDs, of the first side (e.g. the blocks before compaction)"`
+	BlocksB  string `arg:"" help:"block ID, or comma-separated block IDs, of the second side (e.g. the block after compaction)"`
+	MaxIDs   int    `name:"max-ids" help:"maximum number of differing trace IDs and attributes to print per category" default:"20"`
+}
+
+// blockSetSummary is the content of one side of the diff. A side can be made up of multiple blocks so that the inputs
+// of a compaction can be compared with its output.
+type blockSetSummary struct {
+	blocks     int
+	spans      int
+	traces     map[string]int // trace ID -> span count
+	attributes map[string]struct{}
+}
+
+func newBlockSetSummary() *blockSetSummary {
+	return &blockSetSummary{
+		traces:     map[string]int{},
+		attributes: map[string]struct{}{},
+	}
+}
+
+func (cmd *diffBlocksCmd) Run(opts *globalOptions) error {
+	r, _, c, err := loadBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	a, err := summarizeBlocks(ctx, r, c, cmd.TenantID, cmd.BlocksA)
+	if err != nil {
+		return err
+	}
+	b, err := summarizeBlocks(ctx, r, c, cmd.TenantID, cmd.BlocksB)
+	if err != nil {
+		return err
+	}
+
+	printBlocksDiff(a, b, cmd.MaxIDs)
+	return nil
+}
+
+func summarizeBlocks(ctx context.Context, r backend.Reader, c backend.Compactor, tenantID, blockIDs string) (*blockSetSummary, error) {
+	summary := newBlockSetSummary()
+
+	for _, blockID := range strings.Split(blockIDs, ",") {
+		id, err := uuid.Parse(strings.TrimSpace(blockID))
+		if err != nil {
+			return nil, fmt.Errorf("invalid block id %q: %w", blockID, err)
+		}
+
+		meta, err := loadBlockMeta(ctx, r, c, tenantID, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := summarizeBlock(ctx, r, meta, summary); err != nil {
+			return nil, fmt.Errorf("failed to read block %s: %w", id, err)
+		}
+		summary.blocks++
+	}
+
+	return summary, nil
+}
+
+// loadBlockMeta returns the meta of a block, falling back to the compacted meta so blocks that were already
+// compacted can still be compared with their compaction output.
+func loadBlockMeta(ctx context.Context, r backend.Reader, c backend.Compactor, tenantID string, id uuid.UUID) (*backend.BlockMeta, error) {
+	meta, err := r.BlockMeta(ctx, id, tenantID)
+	if err == nil {
+		return meta, nil
+	}
+	if !errors.Is(err, backend.ErrDoesNotExist) {
+		return nil, err
+	}
+
+	compactedMeta, err := c.CompactedBlockMeta(id, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load meta for block %s: %w", id, err)
+	}
+
+	return &compactedMeta.BlockMeta, nil
+}
+
+func summarizeBlock(ctx context.Context, r backend.Reader, meta *backend.BlockMeta, summary *blockSetSummary) error {
+	block, err := encoding.OpenBlock(meta, r)
+	if err != nil {
+		return err
+	}
+
+	iterable, ok := block.(common.TraceIterable)
+	if !ok {
+		return fmt.Errorf("block version %s does not support iterating traces", meta.Version)
+	}
+
+	iter, err := iterable.TraceIterator(ctx)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	for {
+		id, tr, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if id == nil {
+			break
+		}
+
+		spans := 0
+		for _, rs := range tr.ResourceSpans {
+			if rs.Resource != nil {
+				addAttributeKeys(summary.attributes, "resource.", rs.Resource.Attributes)
+			}
+			for _, ss := range rs.ScopeSpans {
+				spans += len(ss.Spans)
+				for _, s := range ss.Spans {
+					addAttributeKeys(summary.attributes, "span.", s.Attributes)
+				}
+			}
+		}
+
+		summary.traces[util.TraceIDToHexString(id)] += spans
+		summary.spans += spans
+	}
+
+	return nil
+}
+
+func addAttributeKeys(keys map[string]struct{}, scope string, attrs []*v1_common.KeyValue) {
+	for _, a := range attrs {
+		keys[scope+a.Key] = struct{}{}
+	}
+}
+
+func printBlocksDiff(a, b *blockSetSummary, maxIDs int) {
+	w := tablewriter.NewWriter(os.Stdout)
+	w.SetHeader([]string{"", "a", "b", "diff"})
+	w.AppendBulk([][]string{
+		{"blocks", fmt.Sprint(a.blocks), fmt.Sprint(b.blocks), fmt.Sprint(b.blocks - a.blocks)},
+		{"traces", fmt.Sprint(len(a.traces)), fmt.Sprint(len(b.traces)), fmt.Sprint(len(b.traces) - len(a.traces))},
+		{"spans", fmt.Sprint(a.spans), fmt.Sprint(b.spans), fmt.Sprint(b.spans - a.spans)},
+		{"attributes", fmt.Sprint(len(a.attributes)), fmt.Sprint(len(b.attributes)), fmt.Sprint(len(b.attributes) - len(a.attributes))},
+	})
+	w.Render()
+
+	missingInB, missingInA, spanCountDiffers := diffTraces(a.traces, b.traces)
+	printList("traces missing in b", missingInB, maxIDs)
+	printList("traces missing in a", missingInA, maxIDs)
+	// duplicate spans across replicas are removed by compaction, so fewer spans in b is not necessarily data loss
+	printList("traces with different span counts", spanCountDiffers, maxIDs)
+
+	printList("attributes missing in b", diffKeys(a.attributes, b.attributes), maxIDs)
+	printList("attributes missing in a", diffKeys(b.attributes, a.attributes), maxIDs)
+}
+
+func diffTraces(a, b map[string]int) (missingInB, missingInA, spanCountDiffers []string) {
+	for id, spansA := range a {
+		spansB, ok := b[id]
+		if !ok {
+			missingInB = append(missingInB, id)
+			continue
+		}
+		if spansA != spansB {
+			spanCountDiffers = append(spanCountDiffers, fmt.Sprintf("%s (a: %d b: %d)", id, spansA, spansB))
+		}
+	}
+	for id := range b {
+		if _, ok := a[id]; !ok {
+			missingInA = append(missingInA, id)
+		}
+	}
+
+	sort.Strings(missingInB)
+	sort.Strings(missingInA)
+	sort.Strings(spanCountDiffers)
+	return missingInB, missingInA, spanCountDiffers
+}
+
+func diffKeys(a, b map[string]struct{}) []string {
+	var missing []string
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			missing = append(missing, k)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+func printList(title string, values []string, maxValues int) {
+	fmt.Printf("\n%s: %d\n", title, len(values))
+	for i, v := range values {
+		if maxValues > 0 && i >= maxValues {
+			fmt.Printf("  ... %d more\n", len(values)-maxValues)
+			break
+		}
+		fmt.Println(" ", v)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestSummarizeBlocks(t *testing.T) {
+	const tenantID = "single-tenant"
+	dir := t.TempDir()
+
+	generateTestBlocks(t, dir, tenantID, 2, 5)
+
+	rawR, _, c, err := local.New(&local.Config{Path: dir})
+	require.NoError(t, err)
+	r := backend.NewReader(rawR)
+	ctx := context.Background()
+
+	blockIDs, _, err := r.Blocks(ctx, tenantID)
+	require.NoError(t, err)
+	require.Len(t, blockIDs, 2)
+
+	both, err := summarizeBlocks(ctx, r, c, tenantID, blockIDs[0].String()+","+blockIDs[1].String())
+	require.NoError(t, err)
+	require.Equal(t, 2, both.blocks)
+	require.Len(t, both.traces, 10)
+	require.NotZero(t, both.spans)
+	require.Contains(t, both.attributes, "resource.service.name")
+
+	// a compacted block is still read
+	require.NoError(t, c.MarkBlockCompacted(blockIDs[1], tenantID))
+	second, err := summarizeBlocks(ctx, r, c, tenantID, blockIDs[1].String())
+	require.NoError(t, err)
+	require.Len(t, second.traces, 5)
+
+	first, err := summarizeBlocks(ctx, r, c, tenantID, blockIDs[0].String())
+	require.NoError(t, err)
+
+	missingInB, missingInA, spanCountDiffers := diffTraces(both.traces, first.traces)
+	require.Len(t, missingInB, 5)
+	require.Empty(t, missingInA)
+	require.Empty(t, spanCountDiffers)
+	for _, id := range missingInB {
+		require.Contains(t, second.traces, id)
+	}
+
+	_, err = summarizeBlocks(ctx, r, c, tenantID, "not-a-uuid")
+	require.Error(t, err)
+}
+
+func TestDiffTraces(t *testing.T) {
+	a := map[string]int{"1": 1, "2": 2, "3": 3}
+	b := map[string]int{"2": 2, "3": 4, "4": 1}
+
+	missingInB, missingInA, spanCountDiffers := diffTraces(a, b)
+	require.Equal(t, []string{"1"}, missingInB)
+	require.Equal(t, []string{"4"}, missingInA)
+	require.Len(t, spanCountDiffers, 1)
+	require.True(t, strings.HasPrefix(spanCountDiffers[0], "3 "))
+}
+
+func TestDiffKeys(t *testing.T) {
+	a := map[string]struct{}{"span.foo": {}, "span.bar": {}}
+	b := map[string]struct{}{"span.foo": {}, "resource.baz": {}}
+
+	require.Equal(t, []string{"span.bar"}, diffKeys(a, b))
+	require.Equal(t, []string{"resource.baz"}, diffKeys(b, a))
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+type dumpBlockCmd struct {
+	backendOptions
+
+	TenantID string `arg:"" help:"tenant-id within the bucket"`
+	BlockID  string `arg:"" help:"block ID to dump"`
+	Format   string `name:"format" help:"output format. otlp-json writes one OTLP JSON object per trace and line" enum:"otlp-json" default:"otlp-json"`
+}
+
+func (cmd *dumpBlockCmd) Run(opts *globalOptions) error {
+	r, _, c, err := loadBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+
+	id, err := uuid.Parse(cmd.BlockID)
+	if err != nil {
+		return fmt.Errorf("invalid block id %q: %w", cmd.BlockID, err)
+	}
+
+	ctx := context.Background()
+	meta, err := loadBlockMeta(ctx, r, c, cmd.TenantID, id)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(os.Stdout)
+	if err := dumpBlockOTLPJSON(ctx, r, meta, w); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// dumpBlockOTLPJSON writes every trace of the block to w as a line of OTLP JSON, e.g. to be processed with jq or duckdb.
+func dumpBlockOTLPJSON(ctx context.Context, r backend.Reader, meta *backend.BlockMeta, w io.Writer) error {
+	block, err := encoding.OpenBlock(meta, r)
+	if err != nil {
+		return err
+	}
+
+	iterable, ok := block.(common.TraceIterable)
+	if !ok {
+		return fmt.Errorf("block version %s does not support iterating traces", meta.Version)
+	}
+
+	iter, err := iterable.TraceIterator(ctx)
+	if err != nil {
+		return err
+	}
+	defer iter.Close()
+
+	var (
+		unmarshaler ptrace.ProtoUnmarshaler
+		marshaler   ptrace.JSONMarshaler
+	)
+	for {
+		id, tr, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if id == nil {
+			break
+		}
+
+		// tempopb.Trace is wire compatible with OTLP, the OTLP JSON encoding has hex trace and span ids
+		b, err := tr.Marshal()
+		if err != nil {
+			return err
+		}
+		traces, err := unmarshaler.UnmarshalTraces(b)
+		if err != nil {
+			return fmt.Errorf("failed to convert trace %x to OTLP: %w", id, err)
+		}
+		b, err = marshaler.MarshalTraces(traces)
+		if err != nil {
+			return fmt.Errorf("failed to marshal trace %x: %w", id, err)
+		}
+
+		if _, err := w.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestDumpBlock(t *testing.T) {
+	const tenantID = "single-tenant"
+	dir := t.TempDir()
+
+	generateTestBlocks(t, dir, tenantID, 1, 5)
+
+	rawR, _, c, err := local.New(&local.Config{Path: dir})
+	require.NoError(t, err)
+	r := backend.NewReader(rawR)
+	ctx := context.Background()
+
+	blockIDs, _, err := r.Blocks(ctx, tenantID)
+	require.NoError(t, err)
+	require.Len(t, blockIDs, 1)
+
+	meta, err := loadBlockMeta(ctx, r, c, tenantID, blockIDs[0])
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, dumpBlockOTLPJSON(ctx, r, meta, buf))
+
+	// every line is a trace in OTLP JSON
+	ids := map[string]struct{}{}
+	scanner := bufio.NewScanner(buf)
+	scanner.Buffer(nil, 10*1024*1024)
+	for scanner.Scan() {
+		traces, err := (&ptrace.JSONUnmarshaler{}).UnmarshalTraces(scanner.Bytes())
+		require.NoError(t, err)
+		require.NotZero(t, traces.SpanCount())
+		require.Contains(t, scanner.Text(), `"traceId":"`+hex.EncodeToString(traceIDOf(traces))+`"`)
+		ids[hex.EncodeToString(traceIDOf(traces))] = struct{}{}
+	}
+	require.NoError(t, scanner.Err())
+	require.Len(t, ids, 5)
+}
+
+func traceIDOf(traces ptrace.Traces) []byte {
+	id := traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).TraceID()
+	return id[:]
+}
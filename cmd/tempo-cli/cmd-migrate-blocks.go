@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/dustin/go-humanize"
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+)
+
+type migrateBlocksCmd struct {
+	backendOptions
+
+	SourceTenantID string   `arg:"" help:"source tenant-id"`
+	DestTenantID   string   `arg:"" help:"dest tenant-id"`
+	BlockIDs       []string `arg:"" optional:"" help:"IDs of the blocks to move, all blocks of the source tenant if not set"`
+	Move           bool     `name:"move" help:"actually move the blocks" default:"false"`
+}
+
+func (cmd *migrateBlocksCmd) Run(opts *globalOptions) error {
+	fmt.Printf("beginning process to move blocks from tenant %v to tenant %v\n", cmd.SourceTenantID, cmd.DestTenantID)
+	fmt.Println("**warning**: compaction must be disabled or a compactor may duplicate a block as this process is moving it")
+	fmt.Println("")
+	if cmd.Move {
+		fmt.Println("*********************************************************************************")
+		fmt.Println("**this is not a dry run. blocks will be copied and the sources marked compacted**")
+		fmt.Println("*********************************************************************************")
+		fmt.Println("")
+	}
+
+	if cmd.SourceTenantID == cmd.DestTenantID {
+		return errors.New("source and dest tenant must be different")
+	}
+
+	ctx := context.Background()
+
+	r, w, c, err := loadBackend(&cmd.backendOptions, opts)
+	if err != nil {
+		return err
+	}
+	defer r.Shutdown()
+
+	blockIDs, err := cmd.sourceBlocks(ctx, r)
+	if err != nil {
+		return err
+	}
+
+	blocksDest, _, err := r.Blocks(ctx, cmd.DestTenantID)
+	if err != nil {
+		return err
+	}
+
+	var (
+		moved         []*backend.BlockMeta
+		compacted     []*backend.CompactedBlockMeta
+		movedSize     uint64
+		skippedBlocks int
+	)
+
+	for _, id := range blockIDs {
+		// check for collisions
+		if slices.Contains(blocksDest, id) {
+			fmt.Printf("UUID %s exists in source and destination, skipping block\n", id)
+			skippedBlocks++
+			continue
+		}
+
+		sourceMeta, err := r.BlockMeta(ctx, id, cmd.SourceTenantID)
+		if err != nil {
+			return fmt.Errorf("reading block meta %s: %w", id, err)
+		}
+		fmt.Printf("block: %v, size: %s, total traces: %d\n", id, humanize.Bytes(sourceMeta.Size_), sourceMeta.TotalObjects)
+
+		if !cmd.Move {
+			fmt.Println("  **not moving block, use --move to actually move**")
+			continue
+		}
+
+		// the block keeps its id. only the tenant changes
+		destMeta := *sourceMeta
+		destMeta.TenantID = cmd.DestTenantID
+
+		encoder, err := encoding.FromVersion(sourceMeta.Version)
+		if err != nil {
+			return fmt.Errorf("creating encoder from version: %w", err)
+		}
+
+		fmt.Printf("  copying %v\n", id)
+		err = encoder.MigrateBlock(ctx, sourceMeta, &destMeta, r, w)
+		if err != nil {
+			return fmt.Errorf("copying block %s: %w", id, err)
+		}
+
+		fmt.Printf("  marking %v compacted in tenant %v\n", id, cmd.SourceTenantID)
+		err = c.MarkBlockCompacted(id, cmd.SourceTenantID)
+		if err != nil {
+			return err
+		}
+		compactedMeta, err := c.CompactedBlockMeta(id, cmd.SourceTenantID)
+		if err != nil {
+			return err
+		}
+
+		moved = append(moved, &destMeta)
+		compacted = append(compacted, compactedMeta)
+		movedSize += sourceMeta.Size_
+	}
+
+	if !cmd.Move {
+		return nil
+	}
+
+	// update the tenant indexes so queriers see the moved blocks before the next poll
+	err = cmd.updateTenantIndexes(ctx, r, w, moved, compacted)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Finished moving blocks. Moved %d blocks, %s. Skipped %d blocks\n", len(moved), humanize.Bytes(movedSize), skippedBlocks)
+	return nil
+}
+
+// sourceBlocks returns the blocks to move. All live blocks of the source tenant if none were passed.
+func (cmd *migrateBlocksCmd) sourceBlocks(ctx context.Context, r backend.Reader) ([]uuid.UUID, error) {
+	blocks, _, err := r.Blocks(ctx, cmd.SourceTenantID)
+	if err != nil {
+		return nil, err
+	}
+	if len(cmd.BlockIDs) == 0 {
+		return blocks, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(cmd.BlockIDs))
+	for _, s := range cmd.BlockIDs {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("parsing block id %s: %w", s, err)
+		}
+		if !slices.Contains(blocks, id) {
+			return nil, fmt.Errorf("block %s not found in tenant %s", id, cmd.SourceTenantID)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// updateTenantIndexes adds the moved blocks to the dest index and marks them compacted in the source index.
+// Tenants without an index are skipped, the poller builds one from the blocks.
+func (cmd *migrateBlocksCmd) updateTenantIndexes(ctx context.Context, r backend.Reader, w backend.Writer, moved []*backend.BlockMeta, compacted []*backend.CompactedBlockMeta) error {
+	if len(moved) == 0 {
+		return nil
+	}
+
+	destIndex, err := r.TenantIndex(ctx, cmd.DestTenantID)
+	switch {
+	case errors.Is(err, backend.ErrDoesNotExist):
+		fmt.Printf("no tenant index for tenant %v, skipping\n", cmd.DestTenantID)
+	case err != nil:
+		return fmt.Errorf("reading dest tenant index: %w", err)
+	default:
+		err = w.WriteTenantIndex(ctx, cmd.DestTenantID, append(destIndex.Meta, moved...), destIndex.CompactedMeta)
+		if err != nil {
+			return fmt.Errorf("writing dest tenant index: %w", err)
+		}
+	}
+
+	sourceIndex, err := r.TenantIndex(ctx, cmd.SourceTenantID)
+	switch {
+	case errors.Is(err, backend.ErrDoesNotExist):
+		fmt.Printf("no tenant index for tenant %v, skipping\n", cmd.SourceTenantID)
+		return nil
+	case err != nil:
+		return fmt.Errorf("reading source tenant index: %w", err)
+	}
+
+	meta := slices.DeleteFunc(sourceIndex.Meta, func(m *backend.BlockMeta) bool {
+		return slices.ContainsFunc(moved, func(moved *backend.BlockMeta) bool { return moved.BlockID == m.BlockID })
+	})
+	err = w.WriteTenantIndex(ctx, cmd.SourceTenantID, meta, append(sourceIndex.CompactedMeta, compacted...))
+	if err != nil {
+		return fmt.Errorf("writing source tenant index: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestMigrateBlocksCmd(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	generateTestBlocks(t, dir, "source", 3, 5)
+
+	rawR, rawW, _, err := local.New(&local.Config{Path: dir})
+	require.NoError(t, err)
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	sourceBlocks, _, err := r.Blocks(ctx, "source")
+	require.NoError(t, err)
+	require.Len(t, sourceBlocks, 3)
+
+	var sourceMetas []*backend.BlockMeta
+	for _, id := range sourceBlocks {
+		meta, err := r.BlockMeta(ctx, id, "source")
+		require.NoError(t, err)
+		sourceMetas = append(sourceMetas, meta)
+	}
+	require.NoError(t, w.WriteTenantIndex(ctx, "source", sourceMetas, nil))
+
+	cmd := migrateBlocksCmd{
+		backendOptions: backendOptions{
+			Backend: "local",
+			Bucket:  dir,
+		},
+		SourceTenantID: "source",
+		DestTenantID:   "dest",
+		BlockIDs:       []string{sourceBlocks[0].String(), sourceBlocks[1].String()},
+	}
+
+	// dry run
+	require.NoError(t, cmd.Run(&globalOptions{}))
+	destBlocks, _, err := r.Blocks(ctx, "dest")
+	require.NoError(t, err)
+	require.Empty(t, destBlocks)
+
+	cmd.Move = true
+	require.NoError(t, cmd.Run(&globalOptions{}))
+
+	destBlocks, _, err = r.Blocks(ctx, "dest")
+	require.NoError(t, err)
+	require.ElementsMatch(t, sourceBlocks[:2], destBlocks)
+	for _, id := range destBlocks {
+		meta, err := r.BlockMeta(ctx, id, "dest")
+		require.NoError(t, err)
+		require.Equal(t, "dest", meta.TenantID)
+	}
+
+	remaining, compacted, err := r.Blocks(ctx, "source")
+	require.NoError(t, err)
+	require.Equal(t, []uuid.UUID{sourceBlocks[2]}, remaining)
+	require.ElementsMatch(t, sourceBlocks[:2], compacted)
+
+	index, err := r.TenantIndex(ctx, "source")
+	require.NoError(t, err)
+	require.Len(t, index.Meta, 1)
+	require.Equal(t, backend.UUID(sourceBlocks[2]), index.Meta[0].BlockID)
+	require.Len(t, index.CompactedMeta, 2)
+
+	// moving again is a no-op because the blocks are compacted in the source
+	cmd.BlockIDs = []string{sourceBlocks[0].String()}
+	require.EqualError(t, cmd.Run(&globalOptions{}), "block "+sourceBlocks[0].String()+" not found in tenant source")
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/grafana/dskit/ring"
+	"github.com/olekukonko/tablewriter"
+
+	tempo_ring "github.com/grafana/tempo/pkg/ring"
+)
+
+type ringOwnershipCmd struct {
+	HostPort string `arg:"" help:"tempo host and port of a component that reads the ring, e.g. localhost:3200"`
+	Ring     string `arg:"" help:"name of the ring, e.g. ingester or metrics-generator"`
+
+	PathPrefix        string `help:"string to prefix all http paths with"`
+	TokensPerInstance int    `help:"generate a token reassignment plan with this many tokens per instance"`
+	PlanDir           string `help:"directory to write a tokens file per instance to, requires --tokens-per-instance"`
+}
+
+// nolint: goconst // goconst wants us to make http:// a const
+func (cmd *ringOwnershipCmd) Run(_ *globalOptions) error {
+	if cmd.PlanDir != "" && cmd.TokensPerInstance <= 0 {
+		return errors.New("--plan-dir requires --tokens-per-instance")
+	}
+
+	u := "http://" + path.Join(cmd.HostPort, cmd.PathPrefix, cmd.Ring, "ring", "ownership")
+	if cmd.TokensPerInstance > 0 {
+		u += "?" + url.Values{"tokens_per_instance": []string{strconv.Itoa(cmd.TokensPerInstance)}}.Encode()
+	}
+
+	resp, err := http.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return errors.New("failed to query. body: " + string(body) + " status: " + resp.Status)
+	}
+
+	report := tempo_ring.OwnershipReport{}
+	if err := json.Unmarshal(body, &report); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	printRingOwnership(report)
+
+	if cmd.PlanDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(cmd.PlanDir, 0o755); err != nil {
+		return err
+	}
+	for id, tokens := range report.Plan {
+		filename := filepath.Join(cmd.PlanDir, id+".tokens")
+		if err := ring.Tokens(tokens).StoreToFile(filename); err != nil {
+			return fmt.Errorf("failed to write tokens file for %s: %w", id, err)
+		}
+		fmt.Println("wrote", filename)
+	}
+
+	return nil
+}
+
+func printRingOwnership(report tempo_ring.OwnershipReport) {
+	out := make([][]string, 0, len(report.Instances))
+	for _, inst := range report.Instances {
+		planned := ""
+		if tokens, ok := report.Plan[inst.ID]; ok {
+			planned = strconv.Itoa(len(tokens))
+		}
+
+		out = append(out, []string{
+			inst.ID,
+			inst.Zone,
+			inst.State,
+			strconv.Itoa(inst.Tokens),
+			fmt.Sprintf("%.2f%%", inst.Ownership),
+			fmt.Sprintf("%.2f", inst.Imbalance),
+			planned,
+		})
+	}
+
+	fmt.Printf("ring: %s, zone aware: %t, max imbalance: %.2f\n", report.Ring, report.ZoneAware, report.MaxImbalance)
+	fmt.Println()
+
+	w := tablewriter.NewWriter(os.Stdout)
+	w.SetHeader([]string{"id", "zone", "state", "tokens", "ownership", "imbalance", "planned tokens"})
+	w.AppendBulk(out)
+	w.Render()
+}
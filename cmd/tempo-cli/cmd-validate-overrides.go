@@ -0,0 +1,58 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafana/tempo/cmd/tempo/app"
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+type validateOverridesCmd struct {
+	OverridesFile string `arg:"" help:"Path to the per-tenant overrides file to validate"`
+
+	ExpandEnv bool `name:"expand-env" help:"expand environment variables in the overrides file" default:"false"`
+}
+
+func (cmd *validateOverridesCmd) Run(opts *globalOptions) error {
+	// Defaults
+	cfg := app.Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	cfg.Overrides.ConfigType = overrides.ConfigTypeNew
+
+	// Existing config, the overrides are validated against its limits
+	if opts.ConfigFile != "" {
+		buff, err := os.ReadFile(opts.ConfigFile)
+		if err != nil {
+			return fmt.Errorf("failed to read configFile %s: %w", opts.ConfigFile, err)
+		}
+
+		if err := yaml.UnmarshalStrict(buff, &cfg); err != nil {
+			return fmt.Errorf("failed to parse configFile %s: %w", opts.ConfigFile, err)
+		}
+	}
+	cfg.Overrides.ExpandEnv = cmd.ExpandEnv
+
+	buff, err := os.ReadFile(cmd.OverridesFile)
+	if err != nil {
+		return fmt.Errorf("failed to read overrides file %s: %w", cmd.OverridesFile, err)
+	}
+
+	result := overrides.ValidatePerTenantOverrides(buff, cfg.Overrides, app.NewRuntimeConfigValidator(&cfg))
+	for _, w := range result.Warnings {
+		fmt.Println("warning:", w)
+	}
+	for _, e := range result.Errors {
+		fmt.Println("error:", e)
+	}
+
+	if !result.Valid {
+		return errors.New("overrides file is not valid")
+	}
+	fmt.Println("overrides file is valid")
+	return nil
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/swift"
 )
 
 const (
@@ -29,7 +30,7 @@ type globalOptions struct {
 }
 
 type backendOptions struct {
-	Backend string `help:"backend to connect to (s3/gcs/local/azure), optional, overrides backend in config file" enum:",s3,gcs,local,azure" default:""`
+	Backend string `help:"backend to connect to (s3/gcs/local/azure/swift), optional, overrides backend in config file" enum:",s3,gcs,local,azure,swift" default:""`
 	Bucket  string `help:"bucket (or path on local backend) to scan, optional, overrides bucket in config file"`
 
 	S3Endpoint         string `name:"s3-endpoint" help:"s3 endpoint (s3.dualstack.us-east-2.amazonaws.com), optional, overrides endpoint in config file"`
@@ -51,8 +52,9 @@ var cli struct {
 	} `cmd:""`
 
 	Analyse struct {
-		Block  analyseBlockCmd  `cmd:"" help:"Analyse block in a bucket"`
-		Blocks analyseBlocksCmd `cmd:"" help:"Analyse blocks in a bucket"`
+		Block       analyseBlockCmd       `cmd:"" help:"Analyse block in a bucket"`
+		Blocks      analyseBlocksCmd      `cmd:"" help:"Analyse blocks in a bucket"`
+		TenantUsage analyseTenantUsageCmd `cmd:"" help:"Report the blocks, bytes, traces and spans of tenants per day"`
 	} `cmd:""`
 
 	View struct {
@@ -78,6 +80,14 @@ var cli struct {
 		Search       searchBlocksCmd      `cmd:"" help:"search for a traceid directly from backend blocks"`
 	} `cmd:""`
 
+	Dump struct {
+		Block dumpBlockCmd `cmd:"" help:"Stream the traces of a block to stdout, e.g. as OTLP JSON lines"`
+	} `cmd:""`
+
+	Diff struct {
+		Blocks diffBlocksCmd `cmd:"" help:"Compare the traces, spans and attributes of two blocks or sets of blocks"`
+	} `cmd:""`
+
 	RewriteBlocks struct {
 		DropTraces dropTracesCmd `cmd:"" help:"rewrite blocks with given trace ids redacted"`
 	} `cmd:""`
@@ -87,10 +97,27 @@ var cli struct {
 		Convert3to4 convertParquet3to4 `cmd:"" help:"convert an existing vParquet3 file to vParquet4 block"`
 	} `cmd:""`
 
+	Ring struct {
+		Ownership ringOwnershipCmd `cmd:"" help:"report the token ownership of a ring and generate a token reassignment plan"`
+	} `cmd:""`
+
 	Migrate struct {
 		Tenant          migrateTenantCmd          `cmd:"" help:"migrate tenant between two backends"`
+		Blocks          migrateBlocksCmd          `cmd:"" help:"move blocks between tenants in the same backend"`
 		OverridesConfig migrateOverridesConfigCmd `cmd:"" help:"migrate overrides config"`
 	} `cmd:""`
+
+	Validate struct {
+		Overrides validateOverridesCmd `cmd:"" help:"validate a per-tenant overrides file"`
+	} `cmd:""`
+
+	Compactor struct {
+		Plan compactorPlanCmd `cmd:"" help:"output the blocks that would be selected for compaction as JSON"`
+	} `cmd:""`
+
+	Bench struct {
+		Backend benchBackendCmd `cmd:"" help:"benchmark write, read, list and delete requests against a backend"`
+	} `cmd:""`
 }
 
 func main() {
@@ -105,6 +132,15 @@ func main() {
 }
 
 func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.Writer, backend.Compactor, error) {
+	r, w, c, err := loadRawBackend(b, g)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return backend.NewReader(r), backend.NewWriter(w), c, nil
+}
+
+func loadRawBackend(b *backendOptions, g *globalOptions) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
 	// Defaults
 	cfg := app.Config{}
 	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
@@ -132,6 +168,7 @@ func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.W
 		cfg.StorageConfig.Trace.GCS.BucketName = b.Bucket
 		cfg.StorageConfig.Trace.S3.Bucket = b.Bucket
 		cfg.StorageConfig.Trace.Azure.ContainerName = b.Bucket
+		cfg.StorageConfig.Trace.Swift.ContainerName = b.Bucket
 	}
 
 	cfg.StorageConfig.Trace.S3.InsecureSkipVerify = b.InsecureSkipVerify
@@ -163,6 +200,8 @@ func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.W
 		r, w, c, err = s3.New(cfg.StorageConfig.Trace.S3)
 	case backend.Azure:
 		r, w, c, err = azure.New(cfg.StorageConfig.Trace.Azure)
+	case backend.Swift:
+		r, w, c, err = swift.New(cfg.StorageConfig.Trace.Swift)
 	default:
 		err = fmt.Errorf("unknown backend %s", cfg.StorageConfig.Trace.Backend)
 	}
@@ -171,5 +210,5 @@ func loadBackend(b *backendOptions, g *globalOptions) (backend.Reader, backend.W
 		return nil, nil, nil, err
 	}
 
-	return backend.NewReader(r), backend.NewWriter(w), c, nil
+	return r, w, c, nil
 }
@@ -19,6 +19,9 @@ type Config struct {
 	QueryServicesDuration string           `yaml:"services_query_duration"`
 	// FindTracesConcurrentRequests defines how many concurrent requests trace search submits to get a trace.
 	FindTracesConcurrentRequests int `yaml:"find_traces_concurrent_requests"`
+	// OTLPNativeTraces makes the v2 trace reader return traces as stored in Tempo. If disabled the traces are
+	// converted to the Jaeger model and back, as the v1 span reader returns them.
+	OTLPNativeTraces bool `yaml:"otlp_native_traces"`
 }
 
 // InitFromViper initializes the options struct with values from Viper
@@ -41,6 +44,8 @@ func (c *Config) InitFromViper(v *viper.Viper) {
 	c.QueryServicesDuration = v.GetString("services_query_duration")
 	c.FindTracesConcurrentRequests = v.GetInt("find_traces_concurrent_requests")
 
+	c.OTLPNativeTraces = !v.IsSet("otlp_native_traces") || v.GetBool("otlp_native_traces")
+
 	if c.FindTracesConcurrentRequests == 0 {
 		c.FindTracesConcurrentRequests = 1
 	}
@@ -71,6 +71,7 @@ type Backend struct {
 	tenantHeaderKey              string
 	QueryServicesDuration        *time.Duration
 	findTracesConcurrentRequests int
+	otlpNativeTraces             bool
 }
 
 func New(logger *zap.Logger, cfg *Config) (*Backend, error) {
@@ -99,6 +100,7 @@ func New(logger *zap.Logger, cfg *Config) (*Backend, error) {
 		tenantHeaderKey:              cfg.TenantHeaderKey,
 		QueryServicesDuration:        queryServiceDuration,
 		findTracesConcurrentRequests: cfg.FindTracesConcurrentRequests,
+		otlpNativeTraces:             cfg.OTLPNativeTraces,
 	}, nil
 }
 
@@ -207,43 +209,14 @@ func (b *Backend) GetTrace(req *storage_v1.GetTraceRequest, stream storage_v1.Sp
 }
 
 func (b *Backend) getTrace(ctx context.Context, traceID jaeger.TraceID) (*jaeger.Trace, error) {
-	url := fmt.Sprintf("%s://%s/api/traces/%s", b.apiSchema(), b.tempoBackend, traceID)
-
 	ctx, span := tracer.Start(ctx, "tempo-query.GetTrace")
 	defer span.End()
 
-	req, err := b.newGetRequest(ctx, url)
+	otTrace, err := b.fetchTrace(ctx, traceID.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	// Set content type to GRPC
-	req.Header.Set(AcceptHeaderKey, ProtobufTypeHeaderValue)
-
-	resp, err := b.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed GET to tempo: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, jaeger_spanstore.ErrTraceNotFound
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error reading response from tempo: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%s", body)
-	}
-
-	otTrace, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(body)
-	if err != nil {
-		return nil, fmt.Errorf("error unmarshalling body to otlp trace %v: %w", traceID, err)
-	}
-
 	jaegerBatches := ot_jaeger.ProtoFromTraces(otTrace)
 
 	jaegerTrace := &jaeger.Trace{
@@ -268,6 +241,48 @@ func (b *Backend) getTrace(ctx context.Context, traceID jaeger.TraceID) (*jaeger
 	return jaegerTrace, nil
 }
 
+// fetchTrace returns the trace as it is stored in Tempo. The params are added to the query of the trace by id request.
+func (b *Backend) fetchTrace(ctx context.Context, traceID string, params url.Values) (ptrace.Traces, error) {
+	reqURL := fmt.Sprintf("%s://%s/api/traces/%s", b.apiSchema(), b.tempoBackend, traceID)
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+
+	req, err := b.newGetRequest(ctx, reqURL)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+
+	// Set content type to GRPC
+	req.Header.Set(AcceptHeaderKey, ProtobufTypeHeaderValue)
+
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("failed GET to tempo: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ptrace.Traces{}, jaeger_spanstore.ErrTraceNotFound
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("error reading response from tempo: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return ptrace.Traces{}, fmt.Errorf("%s", body)
+	}
+
+	otTrace, err := (&ptrace.ProtoUnmarshaler{}).UnmarshalTraces(body)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("error unmarshalling body to otlp trace %v: %w", traceID, err)
+	}
+
+	return otTrace, nil
+}
+
 func (b *Backend) calculateTimeRange() (int64, int64) {
 	now := time.Now()
 	start := now.Add(*b.QueryServicesDuration * -1)
@@ -0,0 +1,90 @@
+package tempo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"net/url"
+	"strconv"
+	"time"
+
+	jaeger "github.com/jaegertracing/jaeger/model"
+	jaeger_spanstore "github.com/jaegertracing/jaeger/storage/spanstore"
+	ot_jaeger "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/jaeger"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// GetTraceParams identifies a trace to fetch with the v2 trace reader. Start and End are optional and narrow the
+// blocks Tempo searches for the trace.
+type GetTraceParams struct {
+	TraceID pcommon.TraceID
+	Start   time.Time
+	End     time.Time
+}
+
+// GetTraces implements the GetTraces method of the Jaeger v2 trace reader. Each trace is yielded as one chunk,
+// traces that are not found are skipped.
+//
+// With otlp_native_traces enabled the traces are returned as stored in Tempo, keeping span events, links, status,
+// trace state and instrumentation scope that don't survive the conversion to the Jaeger model.
+func (b *Backend) GetTraces(ctx context.Context, traceIDs ...GetTraceParams) iter.Seq2[[]ptrace.Traces, error] {
+	return func(yield func([]ptrace.Traces, error) bool) {
+		for _, p := range traceIDs {
+			tr, err := b.getOTLPTrace(ctx, p)
+			if errors.Is(err, jaeger_spanstore.ErrTraceNotFound) {
+				continue
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield([]ptrace.Traces{tr}, nil) {
+				return
+			}
+		}
+	}
+}
+
+func (b *Backend) getOTLPTrace(ctx context.Context, p GetTraceParams) (ptrace.Traces, error) {
+	ctx, span := tracer.Start(ctx, "tempo-query.GetTraces")
+	defer span.End()
+
+	if !b.otlpNativeTraces {
+		traceID, err := jaeger.TraceIDFromBytes(p.TraceID[:])
+		if err != nil {
+			return ptrace.Traces{}, err
+		}
+		jt, err := b.getTrace(ctx, traceID)
+		if err != nil {
+			return ptrace.Traces{}, err
+		}
+		return jaegerToOTLP(jt)
+	}
+
+	params := url.Values{}
+	if !p.Start.IsZero() {
+		params.Set(startTimeMinTag, strconv.FormatInt(p.Start.Unix(), 10))
+	}
+	if !p.End.IsZero() {
+		// the end is in seconds, round up to include the last second
+		params.Set(startTimeMaxTag, strconv.FormatInt(p.End.Add(time.Second-1).Unix(), 10))
+	}
+
+	return b.fetchTrace(ctx, p.TraceID.String(), params)
+}
+
+// jaegerToOTLP converts a trace returned by the v1 span reader back to OTLP.
+func jaegerToOTLP(jt *jaeger.Trace) (ptrace.Traces, error) {
+	batches := make([]*jaeger.Batch, 0, len(jt.Spans))
+	for _, s := range jt.Spans {
+		batches = append(batches, &jaeger.Batch{Spans: []*jaeger.Span{s}, Process: s.Process})
+	}
+
+	tr, err := ot_jaeger.ProtoToTraces(batches)
+	if err != nil {
+		return ptrace.Traces{}, fmt.Errorf("error converting jaeger trace to otlp: %w", err)
+	}
+	return tr, nil
+}
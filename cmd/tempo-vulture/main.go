@@ -27,6 +27,7 @@ import (
 	"github.com/grafana/tempo/pkg/httpclient"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1common "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	"github.com/grafana/tempo/pkg/util"
 )
 
@@ -44,7 +45,11 @@ var (
 	tempoRetentionDuration        time.Duration
 	tempoPushTLS                  bool
 
+	slaPath    string
+	slaWindows string
+
 	logger *zap.Logger
+	sla    *slaTracker
 )
 
 type traceMetrics struct {
@@ -56,6 +61,8 @@ type traceMetrics struct {
 	requested               int
 	requestFailed           int
 	notFoundSearchAttribute int
+	notFoundTagKey          int
+	notFoundTagValue        int
 }
 
 const (
@@ -87,6 +94,8 @@ func init() {
 	flag.DurationVar(&tempoReadBackoffDuration, "tempo-read-backoff-duration", 30*time.Second, "The amount of time to pause between read Tempo calls")
 	flag.DurationVar(&tempoSearchBackoffDuration, "tempo-search-backoff-duration", 60*time.Second, "The amount of time to pause between search Tempo calls.  Set to 0s to disable search.")
 	flag.DurationVar(&tempoRetentionDuration, "tempo-retention-duration", 336*time.Hour, "The block retention that Tempo is using")
+	flag.StringVar(&slaPath, "sla-path", "/sla", "The path to publish the success ratios of the checks to.")
+	flag.StringVar(&slaWindows, "sla-windows", "1h,24h,168h", "Comma separated list of the rolling windows to compute the success ratios of the checks over.")
 }
 
 func main() {
@@ -113,6 +122,12 @@ func main() {
 		tempoPushTLS:                  tempoPushTLS,
 	}
 
+	windows, err := parseSLAWindows(slaWindows)
+	if err != nil {
+		panic(err)
+	}
+	sla = newSLATracker(windows, metricSLASuccessRatio)
+
 	jaegerClient, err := newJaegerGRPCClient(vultureConfig, logger)
 	if err != nil {
 		panic(err)
@@ -132,6 +147,7 @@ func main() {
 	doSearch(httpClient, tickerSearch, startTime, interval, r, vultureConfig, logger)
 
 	http.Handle(prometheusPath, promhttp.Handler())
+	http.Handle(slaPath, sla)
 	log.Fatal(http.ListenAndServe(prometheusListenAddress, nil))
 }
 
@@ -263,7 +279,7 @@ func doRead(httpClient httpclient.TempoHTTPClient, tickerRead *time.Ticker, star
 						zap.Error(err),
 					)
 				}
-				pushMetrics(queryMetrics)
+				pushMetrics(checkTraceByID, config, queryMetrics)
 			}
 		}()
 	}
@@ -294,7 +310,7 @@ func doSearch(httpClient httpclient.TempoHTTPClient, tickerSearch *time.Ticker,
 						zap.Error(err),
 					)
 				}
-				pushMetrics(searchMetrics)
+				pushMetrics(checkSearch, config, searchMetrics)
 
 				// traceql query
 				traceqlSearchMetrics, err := searchTraceql(httpClient, seed, config, l)
@@ -304,13 +320,27 @@ func doSearch(httpClient httpclient.TempoHTTPClient, tickerSearch *time.Ticker,
 						zap.Error(err),
 					)
 				}
-				pushMetrics(traceqlSearchMetrics)
+				pushMetrics(checkTraceQL, config, traceqlSearchMetrics)
+
+				// tag autocomplete
+				autocompleteMetrics, err := searchTagAutocomplete(httpClient, seed, config, l)
+				if err != nil {
+					metricErrorTotal.Inc()
+					logger.Error("tag autocomplete for metrics failed",
+						zap.Error(err),
+					)
+				}
+				pushMetrics(checkAutocomplete, config, autocompleteMetrics)
 			}
 		}()
 	}
 }
 
-func pushMetrics(metrics traceMetrics) {
+func pushMetrics(check string, config vultureConfiguration, metrics traceMetrics) {
+	if sla != nil {
+		sla.record(check, config.tempoOrgID, metrics, time.Now())
+	}
+
 	metricTracesInspected.Add(float64(metrics.requested))
 	metricTracesErrors.WithLabelValues("incorrectresult").Add(float64(metrics.incorrectResult))
 	metricTracesErrors.WithLabelValues("missingspans").Add(float64(metrics.missingSpans))
@@ -319,6 +349,8 @@ func pushMetrics(metrics traceMetrics) {
 	metricTracesErrors.WithLabelValues("notfound_byid").Add(float64(metrics.notFoundByID))
 	metricTracesErrors.WithLabelValues("requestfailed").Add(float64(metrics.requestFailed))
 	metricTracesErrors.WithLabelValues("notfound_search_attribute").Add(float64(metrics.notFoundSearchAttribute))
+	metricTracesErrors.WithLabelValues("notfound_tag_key").Add(float64(metrics.notFoundTagKey))
+	metricTracesErrors.WithLabelValues("notfound_tag_value").Add(float64(metrics.notFoundTagValue))
 }
 
 func selectPastTimestamp(start, stop time.Time, interval, retention time.Duration, r *rand.Rand) (newStart, ts time.Time) {
@@ -490,6 +522,97 @@ func searchTraceql(client httpclient.TempoHTTPClient, seed time.Time, config vul
 	return tm, nil
 }
 
+// searchTagAutocomplete checks that the tags and tag values endpoints used for autocomplete return an attribute of
+// the trace, filtered by that attribute.
+func searchTagAutocomplete(client httpclient.TempoHTTPClient, seed time.Time, config vultureConfiguration, l *zap.Logger) (traceMetrics, error) {
+	tm := traceMetrics{
+		requested: 1,
+	}
+
+	info := util.NewTraceInfo(seed, config.tempoOrgID)
+	hexID := info.HexID()
+
+	// Get the expected
+	expected, err := info.ConstructTraceFromEpoch()
+	if err != nil {
+		l.Error("unable to construct trace from epoch", zap.Error(err))
+		return traceMetrics{}, err
+	}
+
+	attr := util.RandomAttrFromTrace(expected)
+	if attr == nil {
+		tm.notFoundSearchAttribute++
+		return tm, fmt.Errorf("no search attr selected from trace")
+	}
+
+	value := util.StringifyAnyValue(attr.Value)
+	logger := l.With(
+		zap.Int64("seed", seed.Unix()),
+		zap.String("hexID", hexID),
+		zap.Duration("ago", time.Since(seed)),
+		zap.String("key", attr.Key),
+		zap.String("value", value),
+	)
+	logger.Info("searching Tempo via tag autocomplete")
+
+	// the attribute is either a resource or a span attribute, unscoped covers both
+	query := fmt.Sprintf(`{.%s = "%s"}`, attr.Key, value)
+	if _, ok := attr.Value.Value.(*v1common.AnyValue_StringValue); !ok {
+		query = fmt.Sprintf(`{.%s = %s}`, attr.Key, value)
+	}
+
+	start := seed.Add(-30 * time.Minute).Unix()
+	end := seed.Add(30 * time.Minute).Unix()
+
+	tagsResp, err := client.SearchTagsV2WithQueryAndRange(query, start, end)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to search tags with query %s: %s", query, err.Error()))
+		tm.requestFailed++
+		return tm, err
+	}
+
+	if !tagInScopes(attr.Key, tagsResp.GetScopes()) {
+		tm.notFoundTagKey++
+		return tm, fmt.Errorf("tag %s not found in search tags response: %+v", attr.Key, tagsResp.GetScopes())
+	}
+
+	valuesResp, err := client.SearchTagValuesV2WithQueryAndRange("."+attr.Key, query, start, end)
+	if err != nil {
+		logger.Error(fmt.Sprintf("failed to search tag values of %s with query %s: %s", attr.Key, query, err.Error()))
+		tm.requestFailed++
+		return tm, err
+	}
+
+	if !tagValueInValues(value, valuesResp.GetTagValues()) {
+		tm.notFoundTagValue++
+		return tm, fmt.Errorf("value %s of tag %s not found in search tag values response: %+v", value, attr.Key, valuesResp.GetTagValues())
+	}
+
+	return tm, nil
+}
+
+func tagInScopes(key string, scopes []*tempopb.SearchTagsV2Scope) bool {
+	for _, s := range scopes {
+		for _, tag := range s.Tags {
+			if tag == key {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func tagValueInValues(value string, values []*tempopb.TagValue) bool {
+	for _, v := range values {
+		if v.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
+
 func queryTrace(client httpclient.TempoHTTPClient, info *util.TraceInfo, l *zap.Logger) (traceMetrics, error) {
 	tm := traceMetrics{
 		requested: 1,
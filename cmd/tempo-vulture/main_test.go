@@ -536,6 +536,70 @@ func TestSearchTag(t *testing.T) {
 	}, metrics)
 }
 
+func TestSearchTagAutocomplete(t *testing.T) {
+	seed := time.Date(2008, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	config := vultureConfiguration{
+		tempoOrgID:                "orgID",
+		tempoWriteBackoffDuration: time.Second,
+	}
+
+	// the attribute is selected randomly, respond with all attributes of the trace
+	expected, err := util.NewTraceInfo(seed, config.tempoOrgID).ConstructTraceFromEpoch()
+	require.NoError(t, err)
+
+	tagsResponse := &tempopb.SearchTagsV2Response{}
+	valuesResponse := &tempopb.SearchTagValuesV2Response{}
+	addAttrs := func(scope string, attrs []*v1_common.KeyValue) {
+		s := &tempopb.SearchTagsV2Scope{Name: scope}
+		for _, a := range attrs {
+			s.Tags = append(s.Tags, a.Key)
+			valuesResponse.TagValues = append(valuesResponse.TagValues, &tempopb.TagValue{Value: util.StringifyAnyValue(a.Value)})
+		}
+		tagsResponse.Scopes = append(tagsResponse.Scopes, s)
+	}
+	for _, rs := range expected.ResourceSpans {
+		addAttrs("resource", rs.Resource.Attributes)
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				addAttrs("span", span.Attributes)
+			}
+		}
+	}
+
+	logger = zap.NewNop()
+
+	mockHTTPClient := MockHTTPClient{tagsResponse: tagsResponse, valuesResponse: valuesResponse}
+	metrics, err := searchTagAutocomplete(&mockHTTPClient, seed, config, logger)
+	assert.NoError(t, err)
+	assert.Equal(t, traceMetrics{requested: 1}, metrics)
+	assert.Equal(t, 2, mockHTTPClient.GetSearchesCount())
+
+	mockHTTPClient = MockHTTPClient{tagsResponse: &tempopb.SearchTagsV2Response{}, valuesResponse: valuesResponse}
+	metrics, err = searchTagAutocomplete(&mockHTTPClient, seed, config, logger)
+	assert.Error(t, err)
+	assert.Equal(t, traceMetrics{
+		requested:      1,
+		notFoundTagKey: 1,
+	}, metrics)
+
+	mockHTTPClient = MockHTTPClient{tagsResponse: tagsResponse, valuesResponse: &tempopb.SearchTagValuesV2Response{}}
+	metrics, err = searchTagAutocomplete(&mockHTTPClient, seed, config, logger)
+	assert.Error(t, err)
+	assert.Equal(t, traceMetrics{
+		requested:        1,
+		notFoundTagValue: 1,
+	}, metrics)
+
+	mockHTTPClient = MockHTTPClient{err: errors.New("something wrong happened")}
+	metrics, err = searchTagAutocomplete(&mockHTTPClient, seed, config, logger)
+	assert.Error(t, err)
+	assert.Equal(t, traceMetrics{
+		requested:     1,
+		requestFailed: 1,
+	}, metrics)
+}
+
 func TestDoSearch(t *testing.T) {
 	seed := time.Date(2008, 1, 1, 12, 0, 0, 0, time.UTC)
 	traceInfo := util.NewTraceInfo(seed, "test")
@@ -34,10 +34,21 @@ var (
 		},
 		[]string{"error"},
 	)
+
+	// metricSLASuccessRatio is a prometheus gauge that indicates the ratio of successful checks over a rolling window.
+	metricSLASuccessRatio = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sla_success_ratio",
+			Help:      "ratio of checks without issues with traces over a rolling window",
+		},
+		[]string{"check", "tenant", "window"},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(metricErrorTotal)
 	prometheus.MustRegister(metricTracesInspected)
 	prometheus.MustRegister(metricTracesErrors)
+	prometheus.MustRegister(metricSLASuccessRatio)
 }
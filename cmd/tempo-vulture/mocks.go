@@ -46,6 +46,8 @@ type MockHTTPClient struct {
 	requestsCount  int
 	searchResponse []*tempopb.TraceSearchMetadata
 	searchesCount  int
+	tagsResponse   *tempopb.SearchTagsV2Response
+	valuesResponse *tempopb.SearchTagValuesV2Response
 	// We need the lock to control concurrent accesses to shared variables in the tests
 	m sync.Mutex
 }
@@ -123,6 +125,17 @@ func (m *MockHTTPClient) SearchTagValuesV2WithRange(tag string, start int64, end
 	panic("unimplemented")
 }
 
+//nolint:all
+func (m *MockHTTPClient) SearchTagValuesV2WithQueryAndRange(tag string, query string, start int64, end int64) (*tempopb.SearchTagValuesV2Response, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.searchesCount++
+	return m.valuesResponse, m.err
+}
+
 //nolint:all
 func (m *MockHTTPClient) SearchTags() (*tempopb.SearchTagsResponse, error) {
 	panic("unimplemented")
@@ -138,6 +151,17 @@ func (m *MockHTTPClient) SearchTagsV2WithRange(start int64, end int64) (*tempopb
 	panic("unimplemented")
 }
 
+//nolint:all
+func (m *MockHTTPClient) SearchTagsV2WithQueryAndRange(query string, start int64, end int64) (*tempopb.SearchTagsV2Response, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.m.Lock()
+	defer m.m.Unlock()
+	m.searchesCount++
+	return m.tagsResponse, m.err
+}
+
 //nolint:all
 func (m *MockHTTPClient) SearchTagsWithRange(start int64, end int64) (*tempopb.SearchTagsResponse, error) {
 	panic("unimplemented")
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	checkTraceByID    = "trace_by_id"
+	checkSearch       = "search"
+	checkTraceQL      = "traceql"
+	checkAutocomplete = "autocomplete"
+
+	// slaBucketDuration is the resolution of the rolling windows.
+	slaBucketDuration = time.Minute
+)
+
+// slaKey identifies the results of one check type for one tenant.
+type slaKey struct {
+	check  string
+	tenant string
+}
+
+type slaBucket struct {
+	start      time.Time
+	total      int
+	successful int
+}
+
+// slaTracker aggregates the results of the checks into success ratios over rolling windows.
+type slaTracker struct {
+	mtx     sync.Mutex
+	windows []time.Duration
+	buckets map[slaKey][]slaBucket
+
+	ratio *prometheus.GaugeVec
+}
+
+type slaReport struct {
+	Windows []slaWindowReport `json:"windows"`
+}
+
+type slaWindowReport struct {
+	Window string           `json:"window"`
+	Checks []slaCheckReport `json:"checks"`
+}
+
+type slaCheckReport struct {
+	Check        string  `json:"check"`
+	Tenant       string  `json:"tenant"`
+	Total        int     `json:"total"`
+	Successful   int     `json:"successful"`
+	SuccessRatio float64 `json:"successRatio"`
+}
+
+func newSLATracker(windows []time.Duration, ratio *prometheus.GaugeVec) *slaTracker {
+	windows = append([]time.Duration(nil), windows...)
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+
+	return &slaTracker{
+		windows: windows,
+		buckets: map[slaKey][]slaBucket{},
+		ratio:   ratio,
+	}
+}
+
+// parseSLAWindows parses a comma separated list of durations.
+func parseSLAWindows(s string) ([]time.Duration, error) {
+	var windows []time.Duration
+	for _, w := range strings.Split(s, ",") {
+		w = strings.TrimSpace(w)
+		if w == "" {
+			continue
+		}
+		d, err := time.ParseDuration(w)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sla window %q: %w", w, err)
+		}
+		if d < slaBucketDuration {
+			return nil, fmt.Errorf("sla window %s must be at least %s", d, slaBucketDuration)
+		}
+		windows = append(windows, d)
+	}
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("at least one sla window is required")
+	}
+	return windows, nil
+}
+
+// record adds the result of a check. Checks that didn't request a trace from Tempo are ignored, a check is successful
+// if none of the issues with traces were found.
+func (s *slaTracker) record(check, tenant string, metrics traceMetrics, now time.Time) {
+	if metrics.requested == 0 {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	key := slaKey{check: check, tenant: tenant}
+	start := now.Truncate(slaBucketDuration)
+
+	buckets := s.prune(s.buckets[key], now)
+	if len(buckets) == 0 || !buckets[len(buckets)-1].start.Equal(start) {
+		buckets = append(buckets, slaBucket{start: start})
+	}
+	b := &buckets[len(buckets)-1]
+	b.total++
+	if metrics.successful() {
+		b.successful++
+	}
+	s.buckets[key] = buckets
+
+	for _, w := range s.windows {
+		total, successful := sumBuckets(buckets, now, w)
+		s.ratio.WithLabelValues(check, tenant, w.String()).Set(successRatio(total, successful))
+	}
+}
+
+// report returns the success ratios of all checks in every window.
+func (s *slaTracker) report(now time.Time) slaReport {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	keys := make([]slaKey, 0, len(s.buckets))
+	for k, buckets := range s.buckets {
+		s.buckets[k] = s.prune(buckets, now)
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].check != keys[j].check {
+			return keys[i].check < keys[j].check
+		}
+		return keys[i].tenant < keys[j].tenant
+	})
+
+	r := slaReport{Windows: make([]slaWindowReport, 0, len(s.windows))}
+	for _, w := range s.windows {
+		wr := slaWindowReport{Window: w.String(), Checks: []slaCheckReport{}}
+		for _, k := range keys {
+			total, successful := sumBuckets(s.buckets[k], now, w)
+			if total == 0 {
+				continue
+			}
+			wr.Checks = append(wr.Checks, slaCheckReport{
+				Check:        k.check,
+				Tenant:       k.tenant,
+				Total:        total,
+				Successful:   successful,
+				SuccessRatio: successRatio(total, successful),
+			})
+		}
+		r.Windows = append(r.Windows, wr)
+	}
+
+	return r
+}
+
+// ServeHTTP writes the report as JSON.
+func (s *slaTracker) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(s.report(time.Now())); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// prune drops the buckets that are older than the largest window.
+func (s *slaTracker) prune(buckets []slaBucket, now time.Time) []slaBucket {
+	oldest := now.Add(-s.windows[len(s.windows)-1])
+	i := 0
+	for i < len(buckets) && !buckets[i].start.Add(slaBucketDuration).After(oldest) {
+		i++
+	}
+	return buckets[i:]
+}
+
+func sumBuckets(buckets []slaBucket, now time.Time, window time.Duration) (total, successful int) {
+	oldest := now.Add(-window)
+	for _, b := range buckets {
+		if !b.start.Add(slaBucketDuration).After(oldest) {
+			continue
+		}
+		total += b.total
+		successful += b.successful
+	}
+	return total, successful
+}
+
+func successRatio(total, successful int) float64 {
+	if total == 0 {
+		return 1
+	}
+	return float64(successful) / float64(total)
+}
+
+// successful returns true if no issues with the traces were found.
+func (m traceMetrics) successful() bool {
+	return m.incorrectResult == 0 &&
+		m.missingSpans == 0 &&
+		m.notFoundByID == 0 &&
+		m.notFoundSearch == 0 &&
+		m.notFoundTraceQL == 0 &&
+		m.requestFailed == 0 &&
+		m.notFoundSearchAttribute == 0 &&
+		m.notFoundTagKey == 0 &&
+		m.notFoundTagValue == 0
+}
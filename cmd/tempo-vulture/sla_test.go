@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSLAWindows(t *testing.T) {
+	windows, err := parseSLAWindows("1h, 24h,")
+	require.NoError(t, err)
+	require.Equal(t, []time.Duration{time.Hour, 24 * time.Hour}, windows)
+
+	_, err = parseSLAWindows("")
+	require.Error(t, err)
+	_, err = parseSLAWindows("1s")
+	require.Error(t, err)
+	_, err = parseSLAWindows("foo")
+	require.Error(t, err)
+}
+
+func TestSLATracker(t *testing.T) {
+	ratio := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "ratio"}, []string{"check", "tenant", "window"})
+	s := newSLATracker([]time.Duration{24 * time.Hour, time.Hour}, ratio)
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	// a failure two hours ago only counts towards the larger window
+	s.record(checkTraceByID, "tenant", traceMetrics{requested: 1, notFoundByID: 1}, now.Add(-2*time.Hour))
+	s.record(checkTraceByID, "tenant", traceMetrics{requested: 1}, now.Add(-time.Minute))
+	s.record(checkTraceByID, "tenant", traceMetrics{requested: 1}, now)
+	s.record(checkSearch, "tenant", traceMetrics{requested: 1, requestFailed: 1}, now)
+	// checks that didn't request anything are ignored
+	s.record(checkTraceQL, "tenant", traceMetrics{}, now)
+
+	require.Equal(t, slaReport{Windows: []slaWindowReport{
+		{
+			Window: "1h0m0s",
+			Checks: []slaCheckReport{
+				{Check: checkSearch, Tenant: "tenant", Total: 1, Successful: 0, SuccessRatio: 0},
+				{Check: checkTraceByID, Tenant: "tenant", Total: 2, Successful: 2, SuccessRatio: 1},
+			},
+		},
+		{
+			Window: "24h0m0s",
+			Checks: []slaCheckReport{
+				{Check: checkSearch, Tenant: "tenant", Total: 1, Successful: 0, SuccessRatio: 0},
+				{Check: checkTraceByID, Tenant: "tenant", Total: 3, Successful: 2, SuccessRatio: 2.0 / 3},
+			},
+		},
+	}}, s.report(now))
+
+	require.Equal(t, 1.0, testutil.ToFloat64(ratio.WithLabelValues(checkTraceByID, "tenant", "1h0m0s")))
+	require.Equal(t, 2.0/3, testutil.ToFloat64(ratio.WithLabelValues(checkTraceByID, "tenant", "24h0m0s")))
+	require.Equal(t, 0.0, testutil.ToFloat64(ratio.WithLabelValues(checkSearch, "tenant", "1h0m0s")))
+
+	// results older than the largest window are dropped
+	r := s.report(now.Add(25 * time.Hour))
+	require.Empty(t, r.Windows[0].Checks)
+	require.Empty(t, r.Windows[1].Checks)
+}
+
+func TestSLATrackerServeHTTP(t *testing.T) {
+	s := newSLATracker([]time.Duration{time.Hour}, prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "ratio"}, []string{"check", "tenant", "window"}))
+	s.record(checkSearch, "tenant", traceMetrics{requested: 1}, time.Now())
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sla", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var r slaReport
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&r))
+	require.Equal(t, []slaCheckReport{{Check: checkSearch, Tenant: "tenant", Total: 1, Successful: 1, SuccessRatio: 1}}, r.Windows[0].Checks)
+}
@@ -325,6 +325,11 @@ func (t *App) readyHandler(sm *services.Manager, shutdownRequested *atomic.Bool)
 				msg.WriteString(fmt.Sprintf("%v: %d\n", st, len(ls)))
 			}
 
+			// the ingester is starting until the wal is replayed, report the progress to tell it from being stuck
+			if t.ingester != nil {
+				msg.WriteString("Ingester " + t.ingester.WALReplayStatus() + "\n")
+			}
+
 			http.Error(w, msg.String(), http.StatusServiceUnavailable)
 			return
 		}
@@ -168,10 +168,15 @@ func (c *Config) CheckConfig() []ConfigWarning {
 		warnings = append(warnings, warnLogReceivedTraces)
 	}
 
-	if c.Distributor.LogDiscardedSpans.Enabled {
+	// discarded spans written to a separate sink are rate limited and don't flood the process log
+	if c.Distributor.LogDiscardedSpans.Enabled && !c.Distributor.LogDiscardedSpans.Sink.Enabled() {
 		warnings = append(warnings, warnLogDiscardedTraces)
 	}
 
+	if len(c.Distributor.ReceiverDefaultTenants) > 0 && !c.MultitenancyIsEnabled() {
+		warnings = append(warnings, warnReceiverDefaultTenants)
+	}
+
 	if c.StorageConfig.Trace.Backend == backend.Local && c.Target != SingleBinary {
 		warnings = append(warnings, warnStorageTraceBackendLocal)
 	}
@@ -231,6 +236,12 @@ func (c *Config) CheckConfig() []ConfigWarning {
 		warnings = append(warnings, warnBlockAndWALVersionMismatch)
 	}
 
+	// ingesters without a zone are placed as if they were in a zone of their own
+	runsIngester := c.Target == Ingester || c.Target == SingleBinary || c.Target == ScalableSingleBinary
+	if runsIngester && c.Ingester.LifecyclerConfig.RingConfig.ZoneAwarenessEnabled && c.Ingester.LifecyclerConfig.Zone == "" {
+		warnings = append(warnings, warnIngesterZoneNotSet)
+	}
+
 	return warnings
 }
 
@@ -267,6 +278,10 @@ var (
 	warnLogDiscardedTraces = ConfigWarning{
 		Message: "Span logging for discarded traces is enabled. This is for debugging only and not recommended for production deployments.",
 	}
+	warnReceiverDefaultTenants = ConfigWarning{
+		Message: "c.Distributor.ReceiverDefaultTenants is set but multitenancy is disabled.",
+		Explain: "All requests are assigned to the single tenant and the default tenants of the receivers are ignored.",
+	}
 	warnStorageTraceBackendLocal = ConfigWarning{
 		Message: "Local backend will not correctly retrieve traces with a distributed deployment unless all components have access to the same disk. You should probably be using object storage as a backend.",
 	}
@@ -297,6 +312,11 @@ var (
 		Message: "c.BlockConfig.BlockCfg.Version != c.WAL.Version",
 		Explain: "Block version and WAL version must match. WAL version will be set to block version",
 	}
+
+	warnIngesterZoneNotSet = ConfigWarning{
+		Message: "ingester.lifecycler.ring.zone_awareness_enabled is set but ingester.lifecycler.availability_zone is not",
+		Explain: "Replicas of a trace are only guaranteed to be in distinct availability zones if every ingester sets its zone",
+	}
 )
 
 func newV2Warning(setting string) ConfigWarning {
@@ -49,8 +49,10 @@ func TestConfig_CheckConfig(t *testing.T) {
 					LogReceivedSpans: distributor.LogSpansConfig{
 						Enabled: true,
 					},
-					LogDiscardedSpans: distributor.LogSpansConfig{
-						Enabled: true,
+					LogDiscardedSpans: distributor.LogDiscardedSpansConfig{
+						LogSpansConfig: distributor.LogSpansConfig{
+							Enabled: true,
+						},
 					},
 				},
 				Frontend: frontend.Config{
@@ -161,6 +163,44 @@ func TestConfig_CheckConfig(t *testing.T) {
 			}(),
 			expect: nil,
 		},
+		{
+			name: "receiver default tenants without multitenancy",
+			config: func() *Config {
+				cfg := NewDefaultConfig()
+				cfg.Distributor.ReceiverDefaultTenants = map[string]string{"otlp/internal": "internal"}
+				return cfg
+			}(),
+			expect: []ConfigWarning{warnReceiverDefaultTenants},
+		},
+		{
+			name: "receiver default tenants with multitenancy",
+			config: func() *Config {
+				cfg := NewDefaultConfig()
+				cfg.MultitenancyEnabled = true
+				cfg.Distributor.ReceiverDefaultTenants = map[string]string{"otlp/internal": "internal"}
+				return cfg
+			}(),
+			expect: nil,
+		},
+		{
+			name: "zone awareness without ingester zone",
+			config: func() *Config {
+				cfg := NewDefaultConfig()
+				cfg.Ingester.LifecyclerConfig.RingConfig.ZoneAwarenessEnabled = true
+				return cfg
+			}(),
+			expect: []ConfigWarning{warnIngesterZoneNotSet},
+		},
+		{
+			name: "zone awareness with ingester zone",
+			config: func() *Config {
+				cfg := NewDefaultConfig()
+				cfg.Ingester.LifecyclerConfig.RingConfig.ZoneAwarenessEnabled = true
+				cfg.Ingester.LifecyclerConfig.Zone = "zone-a"
+				return cfg
+			}(),
+			expect: nil,
+		},
 	}
 
 	for _, tc := range tt {
@@ -34,6 +34,7 @@ import (
 	"github.com/grafana/tempo/modules/ingester"
 	"github.com/grafana/tempo/modules/overrides"
 	userconfigurableoverridesapi "github.com/grafana/tempo/modules/overrides/userconfigurable/api"
+	userconfigurableoverridesclient "github.com/grafana/tempo/modules/overrides/userconfigurable/client"
 	"github.com/grafana/tempo/modules/querier"
 	tempo_storage "github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/api"
@@ -47,6 +48,7 @@ import (
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/swift"
 )
 
 // The various modules that make up tempo.
@@ -111,11 +113,18 @@ func (t *App) initServer() (services.Service, error) {
 		return svs
 	}
 
-	// add unary and stream timeout interceptors for the query-frontend if configured
-	// this same timeout is enforced for http in the initQueryFrontend() function
-	if t.cfg.Frontend.APITimeout > 0 && t.isModuleActive(QueryFrontend) {
-		t.cfg.Server.GRPCMiddleware = append(t.cfg.Server.GRPCMiddleware, interceptor.NewFrontendAPIUnaryTimeout(t.cfg.Frontend.APITimeout))
-		t.cfg.Server.GRPCStreamMiddleware = append(t.cfg.Server.GRPCStreamMiddleware, interceptor.NewFrontendAPIStreamTimeout(t.cfg.Frontend.APITimeout))
+	// add unary and stream timeout interceptors for the query-frontend. per tenant timeouts take precedence
+	// over the api timeout. the same timeouts are enforced for http in the initQueryFrontend() function
+	if t.isModuleActive(QueryFrontend) {
+		methodTimeout := func(fullMethod string) interceptor.TenantTimeoutFunc {
+			// overrides are initialized after the server
+			if t.Overrides == nil {
+				return nil
+			}
+			return frontend.StreamingTimeout(t.Overrides)(fullMethod)
+		}
+		t.cfg.Server.GRPCMiddleware = append(t.cfg.Server.GRPCMiddleware, interceptor.NewFrontendAPIUnaryTenantTimeout(t.cfg.Frontend.APITimeout, methodTimeout))
+		t.cfg.Server.GRPCStreamMiddleware = append(t.cfg.Server.GRPCStreamMiddleware, interceptor.NewFrontendAPIStreamTenantTimeout(t.cfg.Frontend.APITimeout, methodTimeout))
 	}
 
 	return t.Server.StartAndReturnService(t.cfg.Server, t.cfg.StreamOverHTTPEnabled, servicesToWaitFor)
@@ -176,6 +185,7 @@ func (t *App) initReadRing(cfg ring.Config, name, key string) (*ring.Ring, error
 	}
 
 	t.Server.HTTPRouter().Handle("/"+name+"/ring", ring)
+	t.Server.HTTPRouter().Handle("/"+name+"/ring/ownership", tempo_ring.NewOwnershipHandler(name, ring, cfg.ZoneAwarenessEnabled))
 	t.readRings[name] = ring
 
 	return ring, nil
@@ -201,7 +211,8 @@ func (t *App) initPartitionRing() (services.Service, error) {
 }
 
 func (t *App) initOverrides() (services.Service, error) {
-	o, err := overrides.NewOverrides(t.cfg.Overrides, newRuntimeConfigValidator(&t.cfg), prometheus.DefaultRegisterer)
+	validator := NewRuntimeConfigValidator(&t.cfg)
+	o, err := overrides.NewOverrides(t.cfg.Overrides, validator, prometheus.DefaultRegisterer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create overrides: %w", err)
 	}
@@ -215,6 +226,7 @@ func (t *App) initOverrides() (services.Service, error) {
 
 	t.Server.HTTPRouter().Path("/status/overrides").HandlerFunc(overrides.TenantsHandler(t.Overrides)).Methods("GET")
 	t.Server.HTTPRouter().Path("/status/overrides/{tenant}").HandlerFunc(overrides.TenantStatusHandler(t.Overrides)).Methods("GET")
+	t.Server.HTTPRouter().Path(addHTTPAPIPrefix(&t.cfg, api.PathOverridesValidate)).HandlerFunc(overrides.ValidateHandler(t.cfg.Overrides, validator)).Methods("POST")
 
 	return t.Overrides, nil
 }
@@ -240,6 +252,7 @@ func (t *App) initOverridesAPI() (services.Service, error) {
 	t.Server.HTTPRouter().Path(overridesPath).Methods(http.MethodPost).Handler(wrapHandler(userConfigOverridesAPI.PostHandler))
 	t.Server.HTTPRouter().Path(overridesPath).Methods(http.MethodPatch).Handler(wrapHandler(userConfigOverridesAPI.PatchHandler))
 	t.Server.HTTPRouter().Path(overridesPath).Methods(http.MethodDelete).Handler(wrapHandler(userConfigOverridesAPI.DeleteHandler))
+	t.Server.HTTPRouter().Path(addHTTPAPIPrefix(&t.cfg, api.PathOverridesAudit)).Methods(http.MethodGet).Handler(wrapHandler(userConfigOverridesAPI.AuditLogHandler))
 
 	return userConfigOverridesAPI, nil
 }
@@ -248,6 +261,17 @@ func (t *App) initDistributor() (services.Service, error) {
 	t.cfg.Distributor.KafkaConfig = t.cfg.Ingest.Kafka
 	t.cfg.Distributor.KafkaWritePathEnabled = t.cfg.Ingest.Enabled // TODO: Don't mix config params
 
+	// overrides returned by the tenant provisioning hook are stored as user-configurable overrides
+	provisioningCfg := &t.cfg.Distributor.TenantProvisioning
+	if t.cfg.Overrides.UserConfigurableOverridesConfig.Enabled && (provisioningCfg.WebhookURL != "" || provisioningCfg.Provisioner != nil) {
+		client, err := userconfigurableoverridesclient.New(&t.cfg.Overrides.UserConfigurableOverridesConfig.Client)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create user-configurable overrides client: %w", err)
+		}
+		provisioningCfg.OverridesClient = client
+		provisioningCfg.OverridesValidator = newOverridesValidator(&t.cfg)
+	}
+
 	// todo: make ingester client a module instead of passing the config everywhere
 	distributor, err := distributor.New(t.cfg.Distributor,
 		t.cfg.IngesterClient,
@@ -293,6 +317,8 @@ func (t *App) initIngester() (services.Service, error) {
 	tempopb.RegisterQuerierServer(t.Server.GRPC(), t.ingester)
 	t.Server.HTTPRouter().Path("/flush").Handler(http.HandlerFunc(t.ingester.FlushHandler))
 	t.Server.HTTPRouter().Path("/shutdown").Handler(http.HandlerFunc(t.ingester.ShutdownHandler))
+	t.Server.HTTPRouter().Path("/ingester/ingest-latency").Handler(http.HandlerFunc(t.ingester.IngestLatencyHandler))
+	t.Server.HTTPRouter().Path("/ingester/wal-replay").Handler(http.HandlerFunc(t.ingester.WALReplayHandler))
 	return t.ingester, nil
 }
 
@@ -420,7 +446,7 @@ func (t *App) initQuerier() (services.Service, error) {
 func (t *App) initQueryFrontend() (services.Service, error) {
 	// cortexTripper is a bridge between http and httpgrpc.
 	// It does the job of passing data to the cortex frontend code.
-	cortexTripper, v1, err := frontend.InitFrontend(t.cfg.Frontend.Config, log.Logger, prometheus.DefaultRegisterer)
+	cortexTripper, v1, err := frontend.InitFrontend(t.cfg.Frontend.Config, t.Overrides, log.Logger, prometheus.DefaultRegisterer)
 	if err != nil {
 		return nil, err
 	}
@@ -432,41 +458,54 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 		return nil, err
 	}
 
+	// close the audit log when the frontend stops
+	t.frontend.AddListener(services.NewListener(nil, nil, nil, func(services.State) { queryFrontend.Shutdown() }, nil))
+
 	// register grpc server for queriers to connect to
 	frontend_v1pb.RegisterFrontendServer(t.Server.GRPC(), t.frontend)
 	// we register the streaming querier service on both the http and grpc servers. Grafana expects
 	// this GRPC service to be available on the HTTP server.
 	tempopb.RegisterStreamingQuerierServer(t.Server.GRPC(), queryFrontend)
+	tempopb.RegisterTailServer(t.Server.GRPC(), queryFrontend)
+	tempopb.RegisterTempoQueryServer(t.Server.GRPC(), queryFrontend.TempoQueryServer())
 
 	httpAPIMiddleware := []middleware.Interface{
 		t.HTTPAuthMiddleware,
 		httpGzipMiddleware(),
 	}
 
-	// use the api timeout for http requests if set. note that this is set in initServer() for
-	// grpc requests
-	if t.cfg.Frontend.APITimeout > 0 {
-		httpAPIMiddleware = append(httpAPIMiddleware, middleware.NewTimeoutMiddleware(t.cfg.Frontend.APITimeout, "unable to process request in the configured timeout", kitlog.NewNopLogger()))
-	}
-
 	// wrap handlers with auth
 	base := middleware.Merge(httpAPIMiddleware...)
 
+	// wrap handlers with the per tenant timeout of the endpoint, falling back to the api timeout. note that
+	// this is set in initServer() for grpc requests
+	withTimeout := func(tenantTimeout interceptor.TenantTimeoutFunc, h http.Handler) http.Handler {
+		return base.Wrap(frontend.NewTenantTimeoutMiddleware(t.cfg.Frontend.APITimeout, tenantTimeout, kitlog.NewNopLogger()).Wrap(h))
+	}
+
 	// http trace by id endpoint
-	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraces), base.Wrap(queryFrontend.TraceByIDHandler))
-	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTracesV2), base.Wrap(queryFrontend.TraceByIDHandlerV2))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTraces), withTimeout(t.Overrides.TraceByIDTimeout, queryFrontend.TraceByIDHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTracesV2), withTimeout(t.Overrides.TraceByIDTimeout, queryFrontend.TraceByIDHandlerV2))
 
 	// http search endpoints
-	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearch), base.Wrap(queryFrontend.SearchHandler))
-	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTags), base.Wrap(queryFrontend.SearchTagsHandler))
-	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagsV2), base.Wrap(queryFrontend.SearchTagsV2Handler))
-	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValues), base.Wrap(queryFrontend.SearchTagsValuesHandler))
-	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValuesV2), base.Wrap(queryFrontend.SearchTagsValuesV2Handler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearch), withTimeout(t.Overrides.SearchTimeout, queryFrontend.SearchHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchSpans), withTimeout(t.Overrides.SearchTimeout, queryFrontend.SearchSpansHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTags), withTimeout(t.Overrides.SearchTagsTimeout, queryFrontend.SearchTagsHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagsV2), withTimeout(t.Overrides.SearchTagsTimeout, queryFrontend.SearchTagsV2Handler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValues), withTimeout(t.Overrides.SearchTagsTimeout, queryFrontend.SearchTagsValuesHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSearchTagValuesV2), withTimeout(t.Overrides.SearchTagsTimeout, queryFrontend.SearchTagsValuesV2Handler))
 
 	// http metrics endpoints
-	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSpanMetricsSummary), base.Wrap(queryFrontend.MetricsSummaryHandler))
-	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryInstant), base.Wrap(queryFrontend.MetricsQueryInstantHandler))
-	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRange), base.Wrap(queryFrontend.MetricsQueryRangeHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathSpanMetricsSummary), withTimeout(t.Overrides.MetricsTimeout, queryFrontend.MetricsSummaryHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryInstant), withTimeout(t.Overrides.MetricsTimeout, queryFrontend.MetricsQueryInstantHandler))
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathMetricsQueryRange), withTimeout(t.Overrides.MetricsTimeout, queryFrontend.MetricsQueryRangeHandler))
+
+	// cache warming. the queries run in the background so the request isn't subject to the query timeouts
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathCacheWarming), base.Wrap(queryFrontend.CacheWarmingHandler))
+
+	// live tail. the timeout middleware buffers the response, so the stream is limited by the tail max_duration
+	// instead and every poll is subject to the per tenant search timeout
+	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTail), base.Wrap(queryFrontend.TailHandler))
 
 	// the query frontend needs to have knowledge of the blocks so it can shard search jobs
 	if t.cfg.Target == QueryFrontend {
@@ -479,6 +518,17 @@ func (t *App) initQueryFrontend() (services.Service, error) {
 	// http endpoint to see usage stats data
 	t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathUsageStats), usageStatsHandler(t.cfg.UsageReport))
 
+	// built-in ui for search and trace viewing. the page itself is public, the api calls it makes are authenticated
+	if t.cfg.Frontend.UI.Enabled {
+		if !t.isModuleActive(MemberlistKV) {
+			t.Server.HTTPRouter().PathPrefix("/static/").HandlerFunc(http.FileServer(http.FS(staticFiles)).ServeHTTP).Methods("GET")
+		}
+		t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathUI), uiHandler("", uiPageData{
+			SearchPath: addHTTPAPIPrefix(&t.cfg, api.PathSearch),
+			TracePath:  addHTTPAPIPrefix(&t.cfg, api.PathTracesV2),
+		}))
+	}
+
 	// todo: queryFrontend should implement service.Service and take the cortex frontend a submodule
 	return t.frontend, nil
 }
@@ -501,6 +551,11 @@ func (t *App) initCompactor() (services.Service, error) {
 		t.Server.HTTPRouter().Handle("/compactor/ring", t.compactor.Ring)
 	}
 
+	// deletion requests. they are written to the backend and applied by the compactors
+	if t.cfg.Compactor.TombstonesAPIEnabled {
+		t.Server.HTTPRouter().Handle(addHTTPAPIPrefix(&t.cfg, api.PathTombstones), t.HTTPAuthMiddleware.Wrap(t.compactor.TombstonesHandler())).Methods(http.MethodGet, http.MethodPost)
+	}
+
 	return t.compactor, nil
 }
 
@@ -522,6 +577,20 @@ func (t *App) initStore() (services.Service, error) {
 		t.cfg.StorageConfig.Trace.Pool.QueueDepth = 0
 	}
 
+	// objects of tenants with their own KMS key are encrypted with it
+	if t.cfg.StorageConfig.Trace.S3 != nil {
+		t.cfg.StorageConfig.Trace.S3.SSEKMSKeyIDForTenant = t.Overrides.S3SSEKMSKeyID
+	}
+	if t.cfg.StorageConfig.Trace.GCS != nil {
+		t.cfg.StorageConfig.Trace.GCS.KMSKeyNameForTenant = t.Overrides.GCSKMSKeyName
+	}
+
+	// tenants can be migrated to another block version one at a time
+	t.cfg.StorageConfig.Trace.BlockVersionForTenant = t.Overrides.BlockVersion
+	t.cfg.StorageConfig.Trace.RowGroupSizeBytesForTenant = t.Overrides.RowGroupSizeBytes
+	t.cfg.StorageConfig.Trace.RowGroupSizeSpansForTenant = t.Overrides.RowGroupSizeSpans
+	t.cfg.StorageConfig.Trace.RowGroupAutoTuneForTenant = t.Overrides.RowGroupAutoTune
+
 	store, err := tempo_storage.NewStore(t.cfg.StorageConfig, t.cacheProvider, log.Logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create store: %w", err)
@@ -557,7 +626,7 @@ func (t *App) initMemberlistKV() (services.Service, error) {
 	t.cfg.Distributor.DistributorRing.KVStore.MemberlistKV = t.MemberlistKV.GetMemberlistKV
 	t.cfg.Compactor.ShardingRing.KVStore.MemberlistKV = t.MemberlistKV.GetMemberlistKV
 
-	// Only the memberlist endpoint uses static files currently
+	// The memberlist status page and the built-in ui use static files
 	t.Server.HTTPRouter().PathPrefix("/static/").HandlerFunc(http.FileServer(http.FS(staticFiles)).ServeHTTP).Methods("GET")
 
 	t.Server.HTTPRouter().Handle("/memberlist", memberlistStatusHandler("", t.MemberlistKV))
@@ -590,6 +659,8 @@ func (t *App) initUsageReport() (services.Service, error) {
 		reader, writer, _, err = s3.New(t.cfg.StorageConfig.Trace.S3)
 	case backend.Azure:
 		reader, writer, _, err = azure.New(t.cfg.StorageConfig.Trace.Azure)
+	case backend.Swift:
+		reader, writer, _, err = swift.New(t.cfg.StorageConfig.Trace.Swift)
 	default:
 		err = fmt.Errorf("unknown backend %s", t.cfg.StorageConfig.Trace.Backend)
 	}
@@ -653,7 +724,7 @@ func (t *App) setupModuleManager() error {
 	deps := map[string][]string{
 		// InternalServer: nil,
 		// CacheProvider:  nil,
-		Store:                 {CacheProvider},
+		Store:                 {CacheProvider, Overrides},
 		Server:                {InternalServer},
 		Overrides:             {Server},
 		OverridesAPI:          {Server, Overrides},
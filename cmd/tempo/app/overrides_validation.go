@@ -1,16 +1,21 @@
 package app
 
 import (
+	"encoding/json"
 	"fmt"
 	"slices"
+	"strings"
 	"time"
 
+	"github.com/grafana/tempo/modules/frontend"
 	"github.com/grafana/tempo/modules/generator"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/overrides/userconfigurable/api"
 	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
 	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/encoding"
 )
 
 type runtimeConfigValidator struct {
@@ -19,7 +24,8 @@ type runtimeConfigValidator struct {
 
 var _ overrides.Validator = (*runtimeConfigValidator)(nil)
 
-func newRuntimeConfigValidator(cfg *Config) overrides.Validator {
+// NewRuntimeConfigValidator returns the validator applied to the per-tenant overrides file when it is loaded.
+func NewRuntimeConfigValidator(cfg *Config) overrides.Validator {
 	return &runtimeConfigValidator{
 		cfg: cfg,
 	}
@@ -33,19 +39,87 @@ func (r *runtimeConfigValidator) Validate(config *overrides.Overrides) error {
 		}
 	}
 
+	for i, transform := range config.Ingestion.AttributeTransforms {
+		if err := transform.Validate(); err != nil {
+			return fmt.Errorf("ingestion.attribute_transforms[%d] is not valid: %w", i, err)
+		}
+		if transform.Action == overrides.AttributeTransformHash && r.cfg.Distributor.AttributeHashSecret.String() == "" {
+			return fmt.Errorf("ingestion.attribute_transforms[%d] is not valid: action hash requires distributor.attribute_hash_secret", i)
+		}
+	}
+
 	if _, ok := registry.HistogramModeToValue[string(config.MetricsGenerator.GenerateNativeHistograms)]; !ok {
 		if config.MetricsGenerator.GenerateNativeHistograms != "" {
 			return fmt.Errorf("metrics_generator.generate_native_histograms \"%s\" is not a valid value, valid values: classic, native, both", config.MetricsGenerator.GenerateNativeHistograms)
 		}
 	}
 
+	if endpoints := config.MetricsGenerator.RemoteWriteEndpoints; len(endpoints) > 0 {
+		names := r.cfg.Generator.Storage.RemoteWriteEndpointNames()
+		for _, e := range endpoints {
+			if !slices.Contains(names, e) {
+				return fmt.Errorf("metrics_generator.remote_write_endpoints \"%s\" is not a configured remote write endpoint, valid values: %v", e, names)
+			}
+		}
+	}
+
+	if err := config.Compaction.SpanCombineStrategy.Validate(); err != nil {
+		return fmt.Errorf("compaction.span_combine_strategy is not valid: %w", err)
+	}
+
+	for i, rule := range config.Compaction.RetentionRules {
+		if _, err := traceql.Parse(rule.Query); err != nil {
+			return fmt.Errorf("compaction.retention_rules[%d].query is not a valid TraceQL query: %w", i, err)
+		}
+		if rule.Retention <= 0 {
+			return fmt.Errorf("compaction.retention_rules[%d].retention must be greater than 0", i)
+		}
+	}
+
+	if config.Storage.BlockVersion != "" {
+		if _, err := encoding.FromVersion(config.Storage.BlockVersion); err != nil {
+			return fmt.Errorf("storage.block_version is not valid: %w", err)
+		}
+	}
+
+	if config.Storage.RowGroupSizeBytes < 0 {
+		return fmt.Errorf("storage.parquet_row_group_size_bytes can't be negative")
+	}
+	if config.Storage.RowGroupSizeSpans < 0 {
+		return fmt.Errorf("storage.parquet_row_group_size_spans can't be negative")
+	}
+
+	if config.Storage.ProfileIDColumn {
+		if err := config.Storage.DedicatedColumns.WithProfileID().Validate(); err != nil {
+			return fmt.Errorf("storage.parquet_profile_id_column can't be enabled: %w", err)
+		}
+	}
+
+	for name, v := range map[string]int{
+		"search_concurrent_jobs":        config.Read.SearchConcurrentJobs,
+		"search_target_bytes_per_job":   config.Read.SearchTargetBytesPerJob,
+		"metrics_concurrent_jobs":       config.Read.MetricsConcurrentJobs,
+		"metrics_target_bytes_per_job":  config.Read.MetricsTargetBytesPerJob,
+		"trace_by_id_concurrent_shards": config.Read.TraceByIDConcurrentShards,
+	} {
+		if v < 0 {
+			return fmt.Errorf("read.%s can't be negative", name)
+		}
+	}
+	if config.Read.TraceByIDQueryShards != 0 {
+		if err := frontend.ValidateQueryShards(config.Read.TraceByIDQueryShards); err != nil {
+			return fmt.Errorf("read.trace_by_id_query_shards is not valid: %w", err)
+		}
+	}
+
 	return nil
 }
 
 type overridesValidator struct {
 	cfg *Config
 
-	validForwarders map[string]struct{}
+	validForwarders  map[string]struct{}
+	runtimeValidator overrides.Validator
 }
 
 var _ api.Validator = (*overridesValidator)(nil)
@@ -59,7 +133,8 @@ func newOverridesValidator(cfg *Config) api.Validator {
 	return &overridesValidator{
 		cfg: cfg,
 
-		validForwarders: validForwarders,
+		validForwarders:  validForwarders,
+		runtimeValidator: NewRuntimeConfigValidator(cfg),
 	}
 }
 
@@ -94,5 +169,27 @@ func (v *overridesValidator) Validate(limits *client.Limits) error {
 		}
 	}
 
+	if o, ok := limits.GetOverrides(); ok {
+		if disallowed := overrides.DisallowedOverrides(o, v.cfg.Overrides.UserConfigurableOverridesConfig.AllowedOverrides); len(disallowed) > 0 {
+			return fmt.Errorf("overrides %s are not allowed, contact your system administrator", strings.Join(disallowed, ", "))
+		}
+		b, err := json.Marshal(o)
+		if err != nil {
+			return err
+		}
+		runtimeOverrides, err := overrides.ParseUserConfigurableOverrides(b)
+		if err != nil {
+			return fmt.Errorf("overrides are not valid: %w", err)
+		}
+		if err := v.runtimeValidator.Validate(runtimeOverrides); err != nil {
+			return fmt.Errorf("overrides are not valid: %w", err)
+		}
+		for _, f := range runtimeOverrides.Forwarders {
+			if _, ok := v.validForwarders[f]; !ok {
+				return fmt.Errorf("forwarder \"%s\" is not a known forwarder, contact your system administrator", f)
+			}
+		}
+	}
+
 	return nil
 }
@@ -5,16 +5,22 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/dskit/flagext"
 	"github.com/grafana/dskit/ring"
+	"github.com/prometheus/common/model"
+	prometheus_config "github.com/prometheus/prometheus/config"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/grafana/tempo/modules/distributor"
 	"github.com/grafana/tempo/modules/distributor/forwarder"
 	"github.com/grafana/tempo/modules/generator"
+	"github.com/grafana/tempo/modules/generator/storage"
 	"github.com/grafana/tempo/modules/ingester"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/overrides/userconfigurable/client"
 	filterconfig "github.com/grafana/tempo/pkg/spanfilter/config"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 func Test_runtimeOverridesValidator(t *testing.T) {
@@ -80,11 +86,195 @@ func Test_runtimeOverridesValidator(t *testing.T) {
 				GenerateNativeHistograms: "both",
 			}},
 		},
+		{
+			name: "compaction.span_combine_strategy invalid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Compaction: overrides.CompactionOverrides{
+				SpanCombineStrategy: "invalid",
+			}},
+			expErr: "compaction.span_combine_strategy is not valid: unknown span combine strategy \"invalid\", valid values: [first newest merge_attributes keep_all]",
+		},
+		{
+			name: "compaction.span_combine_strategy merge_attributes",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Compaction: overrides.CompactionOverrides{
+				SpanCombineStrategy: common.SpanCombineStrategyMergeAttributes,
+			}},
+		},
+		{
+			name: "compaction.retention_rules",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Compaction: overrides.CompactionOverrides{
+				RetentionRules: []overrides.RetentionRule{
+					{Query: `{ resource.deployment.environment = "prod" }`, Retention: model.Duration(30 * 24 * time.Hour)},
+				},
+			}},
+		},
+		{
+			name: "compaction.retention_rules invalid query",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Compaction: overrides.CompactionOverrides{
+				RetentionRules: []overrides.RetentionRule{
+					{Query: `{ resource.deployment.environment = "prod" }`, Retention: model.Duration(time.Hour)},
+					{Query: `{ resource.deployment.environment = }`, Retention: model.Duration(time.Hour)},
+				},
+			}},
+			expErr: "compaction.retention_rules[1].query is not a valid TraceQL query: parse error at line 1, col 37: syntax error: unexpected }",
+		},
+		{
+			name: "compaction.retention_rules missing retention",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Compaction: overrides.CompactionOverrides{
+				RetentionRules: []overrides.RetentionRule{
+					{Query: `{ resource.deployment.environment = "prod" }`},
+				},
+			}},
+			expErr: "compaction.retention_rules[0].retention must be greater than 0",
+		},
+		{
+			name: "storage.parquet_profile_id_column",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{
+				ProfileIDColumn: true,
+			}},
+		},
+		{
+			name: "storage.parquet_profile_id_column exceeds span columns",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{
+				ProfileIDColumn:  true,
+				DedicatedColumns: spanColumns(10),
+			}},
+			expErr: "storage.parquet_profile_id_column can't be enabled: number of dedicated columns with scope 'span' must be <= 10 but was 11",
+		},
+		{
+			name: "storage.block_version",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{
+				BlockVersion: "vParquet4",
+			}},
+		},
+		{
+			name: "storage.block_version invalid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{
+				BlockVersion: "vParquet0",
+			}},
+			expErr: "storage.block_version is not valid: vParquet0 is not a valid block version",
+		},
+		{
+			name: "storage.parquet_row_group_size_spans negative",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Storage: overrides.StorageOverrides{
+				RowGroupSizeSpans: -1,
+			}},
+			expErr: "storage.parquet_row_group_size_spans can't be negative",
+		},
+		{
+			name: "read query-frontend sharding",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Read: overrides.ReadOverrides{
+				SearchConcurrentJobs:      100,
+				MetricsTargetBytesPerJob:  1024,
+				TraceByIDQueryShards:      10,
+				TraceByIDConcurrentShards: 5,
+			}},
+		},
+		{
+			name: "read.search_concurrent_jobs negative",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Read: overrides.ReadOverrides{
+				SearchConcurrentJobs: -1,
+			}},
+			expErr: "read.search_concurrent_jobs can't be negative",
+		},
+		{
+			name: "read.trace_by_id_query_shards invalid",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Read: overrides.ReadOverrides{
+				TraceByIDQueryShards: 1,
+			}},
+			expErr: "read.trace_by_id_query_shards is not valid: frontend query shards should be between 2 and 100000 (both inclusive)",
+		},
+		{
+			name: "ingestion.attribute_transforms",
+			cfg: Config{Distributor: distributor.Config{
+				AttributeHashSecret: flagext.SecretWithValue("secret"),
+			}},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				AttributeTransforms: []overrides.AttributeTransform{
+					{Action: overrides.AttributeTransformDrop, Keys: []string{"http.request.header.authorization"}},
+					{Action: overrides.AttributeTransformHash, Scope: overrides.AttributeTransformScopeSpan, Keys: []string{"user.email"}},
+					{Action: overrides.AttributeTransformRename, Regex: "^legacy\\.(.*)$", NewKey: "$1"},
+				},
+			}},
+		},
+		{
+			name: "ingestion.attribute_transforms rename without new_key",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				AttributeTransforms: []overrides.AttributeTransform{
+					{Action: overrides.AttributeTransformRename, Keys: []string{"foo"}},
+				},
+			}},
+			expErr: "ingestion.attribute_transforms[0] is not valid: new_key must be set for action rename",
+		},
+		{
+			name: "ingestion.attribute_transforms hash without secret",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				AttributeTransforms: []overrides.AttributeTransform{
+					{Action: overrides.AttributeTransformDrop, Keys: []string{"foo"}},
+					{Action: overrides.AttributeTransformHash, Keys: []string{"user.email"}},
+				},
+			}},
+			expErr: "ingestion.attribute_transforms[1] is not valid: action hash requires distributor.attribute_hash_secret",
+		},
+		{
+			name: "ingestion.attribute_transforms invalid regex",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				AttributeTransforms: []overrides.AttributeTransform{
+					{Action: overrides.AttributeTransformDrop, Keys: []string{"foo"}},
+					{Action: overrides.AttributeTransformDrop, Regex: "("},
+				},
+			}},
+			expErr: "ingestion.attribute_transforms[1] is not valid: regex is not valid: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "ingestion.attribute_transforms invalid action",
+			cfg:  Config{},
+			overrides: overrides.Overrides{Ingestion: overrides.IngestionOverrides{
+				AttributeTransforms: []overrides.AttributeTransform{
+					{Action: "mask", Keys: []string{"foo"}},
+				},
+			}},
+			expErr: "ingestion.attribute_transforms[0] is not valid: action \"mask\" is not valid, valid values: drop, hash, rename",
+		},
+		{
+			name: "metrics_generator.remote_write_endpoints configured",
+			cfg: Config{Generator: generator.Config{Storage: storage.Config{RemoteWrite: []prometheus_config.RemoteWriteConfig{
+				{Name: "mimir-1"}, {Name: "mimir-2"},
+			}}}},
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{
+				RemoteWriteEndpoints: []string{"mimir-2"},
+			}},
+		},
+		{
+			name: "metrics_generator.remote_write_endpoints unknown",
+			cfg: Config{Generator: generator.Config{Storage: storage.Config{RemoteWrite: []prometheus_config.RemoteWriteConfig{
+				{Name: "mimir-1"}, {Name: "mimir-2"},
+			}}}},
+			overrides: overrides.Overrides{MetricsGenerator: overrides.MetricsGeneratorOverrides{
+				RemoteWriteEndpoints: []string{"mimir-3"},
+			}},
+			expErr: "metrics_generator.remote_write_endpoints \"mimir-3\" is not a configured remote write endpoint, valid values: [mimir-1 mimir-2]",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			validator := newRuntimeConfigValidator(&tc.cfg)
+			validator := NewRuntimeConfigValidator(&tc.cfg)
 
 			err := validator.Validate(&tc.overrides)
 			if tc.expErr != "" {
@@ -97,6 +287,16 @@ func Test_runtimeOverridesValidator(t *testing.T) {
 }
 
 func Test_overridesValidator(t *testing.T) {
+	allowOverrides := func(allowed ...string) Config {
+		return Config{
+			Overrides: overrides.Config{
+				UserConfigurableOverridesConfig: overrides.UserConfigurableOverridesConfig{
+					AllowedOverrides: allowed,
+				},
+			},
+		}
+	}
+
 	testCases := []struct {
 		name   string
 		cfg    Config
@@ -208,6 +408,68 @@ func Test_overridesValidator(t *testing.T) {
 			},
 			expErr: "metrics_generator.collection_interval \"10m0s\" is outside acceptable range of 15s to 5m",
 		},
+		{
+			name: "overrides valid",
+			cfg:  allowOverrides("ingestion", "global.max_bytes_per_trace", "read"),
+			limits: client.Limits{
+				Overrides: map[string]any{
+					"ingestion": map[string]any{"rate_limit_bytes": 1000},
+					"global":    map[string]any{"max_bytes_per_trace": 5000},
+					"read":      map[string]any{"max_search_duration": "1h"},
+				},
+			},
+		},
+		{
+			name: "overrides unknown field",
+			cfg:  allowOverrides("ingestion"),
+			limits: client.Limits{
+				Overrides: map[string]any{
+					"ingestion": map[string]any{"rate_limit": 1000},
+				},
+			},
+			expErr: "overrides are not valid: json: unknown field \"rate_limit\"",
+		},
+		{
+			name: "overrides invalid",
+			cfg:  allowOverrides("storage"),
+			limits: client.Limits{
+				Overrides: map[string]any{
+					"storage": map[string]any{"parquet_row_group_size_bytes": -1},
+				},
+			},
+			expErr: "overrides are not valid: storage.parquet_row_group_size_bytes can't be negative",
+		},
+		{
+			name: "overrides unknown forwarder",
+			cfg:  allowOverrides("forwarders"),
+			limits: client.Limits{
+				Overrides: map[string]any{
+					"forwarders": []string{"some-forwarder"},
+				},
+			},
+			expErr: "forwarder \"some-forwarder\" is not a known forwarder, contact your system administrator",
+		},
+		{
+			name: "overrides not allowed",
+			cfg:  allowOverrides("ingestion.burst_size_bytes"),
+			limits: client.Limits{
+				Overrides: map[string]any{
+					"ingestion": map[string]any{"rate_limit_bytes": 1000, "burst_size_bytes": 1000},
+					"global":    map[string]any{"max_bytes_per_trace": 5000},
+				},
+			},
+			expErr: "overrides global.max_bytes_per_trace, ingestion.rate_limit_bytes are not allowed, contact your system administrator",
+		},
+		{
+			name: "overrides not allowed by default",
+			cfg:  Config{},
+			limits: client.Limits{
+				Overrides: map[string]any{
+					"ingestion": map[string]any{"rate_limit_bytes": 1000},
+				},
+			},
+			expErr: "overrides ingestion.rate_limit_bytes are not allowed, contact your system administrator",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -223,3 +485,11 @@ func Test_overridesValidator(t *testing.T) {
 		})
 	}
 }
+
+func spanColumns(n int) backend.DedicatedColumns {
+	cols := make(backend.DedicatedColumns, 0, n)
+	for i := 0; i < n; i++ {
+		cols = append(cols, backend.DedicatedColumn{Scope: backend.DedicatedColumnScopeSpan, Name: fmt.Sprintf("attr.%d", i), Type: backend.DedicatedColumnTypeString})
+	}
+	return cols
+}
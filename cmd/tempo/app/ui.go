@@ -0,0 +1,39 @@
+package app
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"net/http"
+	"path"
+)
+
+//go:embed ui.gohtml
+var uiPageHTML string
+
+type uiPageData struct {
+	SearchPath string
+	TracePath  string
+}
+
+// uiHandler serves a minimal page to search for and view traces. The page calls the frontend's
+// http api directly from the browser.
+func uiHandler(httpPathPrefix string, data uiPageData) http.Handler {
+	templ := template.New("ui")
+	templ.Funcs(map[string]interface{}{
+		"AddPathPrefix": func(link string) string { return path.Join(httpPathPrefix, link) },
+	})
+	template.Must(templ.Parse(uiPageHTML))
+
+	// the page is static so render it once
+	var buf bytes.Buffer
+	if err := templ.Execute(&buf, data); err != nil {
+		panic(err)
+	}
+	page := buf.Bytes()
+
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(page)
+	})
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUIHandler(t *testing.T) {
+	h := uiHandler("", uiPageData{
+		SearchPath: "/tempo/api/search",
+		TracePath:  "/tempo/api/v2/traces/{traceID}",
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tempo/ui", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	body := rec.Body.String()
+	require.Contains(t, body, `const searchPath = "/tempo/api/search";`)
+	require.Contains(t, body, `const tracePath = "/tempo/api/v2/traces/{traceID}";`)
+	require.Contains(t, body, `href="/static/bootstrap-5.1.3.min.css"`)
+}
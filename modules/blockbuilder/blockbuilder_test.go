@@ -243,7 +243,7 @@ func TestBlockbuilder_receivesOldRecords(t *testing.T) {
 // On encountering a commit failure, the block-builder retries the operation and eventually succeeds.
 //
 // This would cause two blocks to be written, one for each cycle (one cycle fails at commit, the other succeeds).
-// The block-builder deterministically generates the block ID based on the cycle end timestamp,
+// The block-builder deterministically generates the block ID based on the partition and the section's start offset,
 // so the block ID for the failed cycle is the same from the block ID for the successful cycle,
 // and the failed block is overwritten by the successful one.
 func TestBlockbuilder_committingFails(t *testing.T) {
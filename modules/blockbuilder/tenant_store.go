@@ -63,10 +63,14 @@ type tenantStore struct {
 	traceSizes *tracesizes.Tracker
 }
 
-func newTenantStore(tenantID string, partitionID, endTimestamp uint64, cfg BlockConfig, logger log.Logger, wal *wal.WAL, enc encoding.VersionedEncoding, o Overrides) (*tenantStore, error) {
+// newTenantStore creates a store for the tenant's traces in a section of the partition starting at startOffset.
+// Block IDs are derived from the tenant, partition and start offset so that re-consuming the same section after
+// a failure before commit produces the same block IDs and overwrites the blocks already written, instead of
+// duplicating them.
+func newTenantStore(tenantID string, partitionID, startOffset uint64, cfg BlockConfig, logger log.Logger, wal *wal.WAL, enc encoding.VersionedEncoding, o Overrides) (*tenantStore, error) {
 	s := &tenantStore{
 		tenantID:     tenantID,
-		idGenerator:  util.NewDeterministicIDGenerator(tenantID, partitionID, endTimestamp),
+		idGenerator:  util.NewDeterministicIDGenerator(tenantID, partitionID, startOffset),
 		cfg:          cfg,
 		logger:       logger,
 		overrides:    o,
@@ -78,3 +78,34 @@ func TestAdjustTimeRangeForSlack(t *testing.T) {
 		})
 	}
 }
+
+func TestTenantStoreDeterministicBlockIDs(t *testing.T) {
+	newStore := func(tenant string, partition, offset uint64) *tenantStore {
+		w, err := wal.New(&wal.Config{
+			Filepath:       t.TempDir(),
+			Encoding:       backend.EncNone,
+			IngestionSlack: 3 * time.Minute,
+			Version:        encoding.DefaultEncoding().Version(),
+		})
+		require.NoError(t, err)
+
+		s, err := newTenantStore(tenant, partition, offset, BlockConfig{}, log.NewNopLogger(), w, encoding.DefaultEncoding(), &mockOverrides{})
+		require.NoError(t, err)
+		return s
+	}
+	blockID := func(s *tenantStore) backend.UUID {
+		return s.headBlock.BlockMeta().BlockID
+	}
+
+	// re-processing the same section produces the same block IDs
+	first, second := newStore("test-tenant", 1, 100), newStore("test-tenant", 1, 100)
+	require.Equal(t, blockID(first), blockID(second))
+	require.NoError(t, first.resetHeadBlock())
+	require.NoError(t, second.resetHeadBlock())
+	require.Equal(t, blockID(first), blockID(second))
+
+	// a different tenant, partition or start offset produces a different block ID
+	require.NotEqual(t, blockID(newStore("test-tenant", 1, 100)), blockID(newStore("other-tenant", 1, 100)))
+	require.NotEqual(t, blockID(newStore("test-tenant", 1, 100)), blockID(newStore("test-tenant", 2, 100)))
+	require.NotEqual(t, blockID(newStore("test-tenant", 1, 100)), blockID(newStore("test-tenant", 1, 101)))
+}
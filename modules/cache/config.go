@@ -40,6 +40,12 @@ func (cfg *Config) Validate() error {
 			return errors.New("configured caches require a valid role")
 		}
 
+		if cacheCfg.RedisConfig != nil {
+			if err := cacheCfg.RedisConfig.ClientConfig.Validate(); err != nil {
+				return fmt.Errorf("cache config for role %s: %w", cacheCfg.Role, err)
+			}
+		}
+
 		// check that all roles are unique
 		for _, role := range cacheCfg.Role {
 			if role == cache.RoleNone {
@@ -85,6 +91,8 @@ func allRoles() map[cache.Role]struct{} {
 		cache.RoleTraceIDIdx,
 		cache.RoleFrontendSearch,
 		cache.RoleParquetPage,
+		cache.RoleTagValuesIndex,
+		cache.RoleFrontendSearchResults,
 	}
 
 	roles := map[cache.Role]struct{}{}
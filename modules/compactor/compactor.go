@@ -17,6 +17,8 @@ import (
 	"github.com/grafana/tempo/pkg/model"
 	tempoUtil "github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 const (
@@ -52,6 +54,9 @@ type Compactor struct {
 
 // New makes a new Compactor.
 func New(cfg Config, store storage.Store, overrides overrides.Interface, reg prometheus.Registerer) (*Compactor, error) {
+	// nothing is written to the backend in dry run mode
+	cfg.Compactor.DryRun = cfg.DryRun
+
 	c := &Compactor{
 		cfg:       &cfg,
 		store:     store,
@@ -254,11 +259,21 @@ func (c *Compactor) BlockRetentionForTenant(tenantID string) time.Duration {
 	return c.overrides.BlockRetention(tenantID)
 }
 
+// ConfirmDeleteAllForTenant implements CompactorOverrides
+func (c *Compactor) ConfirmDeleteAllForTenant(tenantID string) bool {
+	return c.overrides.ConfirmDeleteAll(tenantID)
+}
+
 // CompactionDisabledForTenant implements CompactorOverrides
 func (c *Compactor) CompactionDisabledForTenant(tenantID string) bool {
 	return c.overrides.CompactionDisabled(tenantID)
 }
 
+// MaxConcurrentCompactionsForTenant implements CompactorOverrides
+func (c *Compactor) MaxConcurrentCompactionsForTenant(tenantID string) int {
+	return c.overrides.MaxConcurrentCompactions(tenantID)
+}
+
 func (c *Compactor) MaxBytesPerTraceForTenant(tenantID string) int {
 	return c.overrides.MaxBytesPerTrace(tenantID)
 }
@@ -267,6 +282,27 @@ func (c *Compactor) MaxCompactionRangeForTenant(tenantID string) time.Duration {
 	return c.overrides.MaxCompactionRange(tenantID)
 }
 
+func (c *Compactor) SpanCombineStrategyForTenant(tenantID string) common.SpanCombineStrategy {
+	return c.overrides.SpanCombineStrategy(tenantID)
+}
+
+// RetentionRulesForTenant implements CompactorOverrides
+func (c *Compactor) RetentionRulesForTenant(tenantID string) []tempodb.RetentionRule {
+	rules := c.overrides.RetentionRules(tenantID)
+	if len(rules) == 0 {
+		return nil
+	}
+
+	retentionRules := make([]tempodb.RetentionRule, 0, len(rules))
+	for _, r := range rules {
+		retentionRules = append(retentionRules, tempodb.RetentionRule{
+			Query:     r.Query,
+			Retention: time.Duration(r.Retention),
+		})
+	}
+	return retentionRules
+}
+
 func (c *Compactor) isSharded() bool {
 	return c.cfg.ShardingRing.KVStore.Store != ""
 }
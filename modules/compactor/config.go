@@ -15,9 +15,13 @@ import (
 
 type Config struct {
 	Disabled        bool                    `yaml:"disabled,omitempty"`
+	DryRun          bool                    `yaml:"dry_run,omitempty"`
 	ShardingRing    RingConfig              `yaml:"ring,omitempty"`
 	Compactor       tempodb.CompactorConfig `yaml:"compaction"`
 	OverrideRingKey string                  `yaml:"override_ring_key"`
+
+	// TombstonesAPIEnabled serves the API to delete traces from the compactor
+	TombstonesAPIEnabled bool `yaml:"tombstones_api_enabled,omitempty"`
 }
 
 // RegisterFlagsAndApplyDefaults registers the flags.
@@ -27,19 +31,27 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 		FlushSizeBytes:          tempodb.DefaultFlushSizeBytes,
 		CompactedBlockRetention: time.Hour,
 		RetentionConcurrency:    tempodb.DefaultRetentionConcurrency,
+		CompactionConcurrency:   tempodb.DefaultCompactionConcurrency,
 		IteratorBufferSize:      tempodb.DefaultIteratorBufferSize,
 		MaxTimePerTenant:        tempodb.DefaultMaxTimePerTenant,
 		CompactionCycle:         tempodb.DefaultCompactionCycle,
+		TombstoneGracePeriod:    tempodb.DefaultTombstoneGracePeriod,
 	}
 
 	flagext.DefaultValues(&cfg.ShardingRing)
 	cfg.ShardingRing.KVStore.Store = "" // by default compactor is not sharded
 
 	f.DurationVar(&cfg.Compactor.BlockRetention, util.PrefixConfig(prefix, "compaction.block-retention"), 14*24*time.Hour, "Duration to keep blocks/traces.")
+	f.DurationVar(&cfg.Compactor.RetentionFloor, util.PrefixConfig(prefix, "compaction.retention-floor"), 0, "Minimum block retention. Per-tenant block retention overrides below this value are raised to it. 0 to disable.")
 	f.IntVar(&cfg.Compactor.MaxCompactionObjects, util.PrefixConfig(prefix, "compaction.max-objects-per-block"), 6000000, "Maximum number of traces in a compacted block.")
 	f.Uint64Var(&cfg.Compactor.MaxBlockBytes, util.PrefixConfig(prefix, "compaction.max-block-bytes"), 100*1024*1024*1024 /* 100GB */, "Maximum size of a compacted block.")
+	f.Uint64Var(&cfg.Compactor.MaxJobMemoryBytes, util.PrefixConfig(prefix, "compaction.max-job-memory-bytes"), 0, "Maximum estimated memory of a compaction job. Jobs are compacted with fewer blocks to stay within it. 0 to disable.")
+	f.StringVar(&cfg.Compactor.PartitionAttribute, util.PrefixConfig(prefix, "compaction.partition-attribute"), "", "Resource attribute to partition compacted blocks by, e.g. service.name. Empty to disable.")
+	f.IntVar(&cfg.Compactor.MaxPartitions, util.PrefixConfig(prefix, "compaction.max-partitions"), 10, "Maximum number of attribute values with their own compacted blocks per compaction job.")
 	f.DurationVar(&cfg.Compactor.MaxCompactionRange, util.PrefixConfig(prefix, "compaction.compaction-window"), time.Hour, "Maximum time window across which to compact blocks.")
 	f.BoolVar(&cfg.Disabled, util.PrefixConfig(prefix, "disabled"), false, "Disable compaction.")
+	f.BoolVar(&cfg.DryRun, util.PrefixConfig(prefix, "dry-run"), false, "Log the compaction plan of every tenant instead of compacting. Retention is disabled as well.")
+	f.BoolVar(&cfg.TombstonesAPIEnabled, util.PrefixConfig(prefix, "tombstones-api-enabled"), false, "Serve the API to delete traces from the compactor.")
 	cfg.OverrideRingKey = compactorRingKey
 }
 
@@ -0,0 +1,67 @@
+package compactor
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/dskit/tenant"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// TombstonesHandler lists the tombstones of the tenant on GET and creates a tombstone on POST. Tombstones are
+// applied by the compactors, which record their progress and the completion time in the tombstone.
+func (c *Compactor) TombstonesHandler() http.Handler {
+	return tombstonesHandler(c.store)
+}
+
+// tombstonesHandler serves the tombstones of a single tenant. Requests for multiple tenants are rejected.
+func tombstonesHandler(store tempodb.Compactor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tenantID, err := tenant.TenantID(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var resp any
+		switch r.Method {
+		case http.MethodGet:
+			tombstones, err := store.Tombstones(r.Context(), tenantID)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("failed to list tombstones: %v", err), http.StatusInternalServerError)
+				return
+			}
+			if tombstones == nil {
+				tombstones = []*backend.Tombstone{}
+			}
+			resp = tombstones
+		case http.MethodPost:
+			tombstone, err := api.ParseTombstoneRequest(r, tenantID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := store.CreateTombstone(r.Context(), tombstone); err != nil {
+				http.Error(w, fmt.Sprintf("failed to create tombstone: %v", err), http.StatusInternalServerError)
+				return
+			}
+			resp = tombstone
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		data, err := json.Marshal(resp)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+		_, _ = w.Write(data)
+	}
+}
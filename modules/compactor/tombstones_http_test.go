@@ -0,0 +1,46 @@
+package compactor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type mockTombstoneStore struct {
+	tempodb.Compactor
+	tombstones []*backend.Tombstone
+}
+
+func (m *mockTombstoneStore) Tombstones(context.Context, string) ([]*backend.Tombstone, error) {
+	return m.tombstones, nil
+}
+
+func TestTombstonesHandlerRejectsMultipleTenants(t *testing.T) {
+	handler := tombstonesHandler(&mockTombstoneStore{})
+
+	tcs := []struct {
+		orgID      string
+		statusCode int
+	}{
+		{orgID: "single-tenant", statusCode: http.StatusOK},
+		{orgID: "a|b", statusCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.orgID, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/tombstones", nil)
+			req = req.WithContext(user.InjectOrgID(req.Context(), tc.orgID))
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			require.Equal(t, tc.statusCode, rec.Code)
+		})
+	}
+}
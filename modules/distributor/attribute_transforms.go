@@ -0,0 +1,271 @@
+package distributor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"reflect"
+	"regexp"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+var metricAttributesTransformed = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "distributor_attributes_transformed_total",
+	Help:      "The total number of attributes dropped, hashed or renamed per tenant.",
+}, []string{"tenant", "action"})
+
+// attributeTransform is an overrides.AttributeTransform with the regex compiled.
+type attributeTransform struct {
+	action   string
+	span     bool
+	resource bool
+	keys     map[string]struct{}
+	regex    *regexp.Regexp
+	newKey   string
+	// hashKey is the HMAC key of hashed values. Hashed attributes are dropped if it's empty.
+	hashKey []byte
+}
+
+func newAttributeTransform(cfg overrides.AttributeTransform, hashKey []byte) (*attributeTransform, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	t := &attributeTransform{
+		action:   cfg.Action,
+		span:     cfg.Scope != overrides.AttributeTransformScopeResource,
+		resource: cfg.Scope != overrides.AttributeTransformScopeSpan,
+		keys:     make(map[string]struct{}, len(cfg.Keys)),
+		newKey:   cfg.NewKey,
+		hashKey:  hashKey,
+	}
+	for _, k := range cfg.Keys {
+		t.keys[k] = struct{}{}
+	}
+	if cfg.Regex != "" {
+		// validated above
+		t.regex = regexp.MustCompile(cfg.Regex)
+	}
+	return t, nil
+}
+
+// match returns true if the transform applies to the key, and the key the attribute is moved to if it's renamed.
+func (t *attributeTransform) match(key string) (bool, string) {
+	if _, ok := t.keys[key]; ok {
+		return true, t.newKey
+	}
+	if t.regex == nil {
+		return false, ""
+	}
+	submatches := t.regex.FindStringSubmatchIndex(key)
+	if submatches == nil {
+		return false, ""
+	}
+	if t.action != overrides.AttributeTransformRename {
+		return true, ""
+	}
+	return true, string(t.regex.ExpandString(nil, t.newKey, key, submatches))
+}
+
+type renamedAttribute struct {
+	key   string
+	value pcommon.Value
+}
+
+// apply transforms the attributes and returns the number of attributes that were transformed.
+func (t *attributeTransform) apply(attrs pcommon.Map, renamed []renamedAttribute) (int, []renamedAttribute) {
+	count := 0
+	renamed = renamed[:0]
+
+	attrs.RemoveIf(func(k string, v pcommon.Value) bool {
+		ok, newKey := t.match(k)
+		if !ok {
+			return false
+		}
+
+		switch t.action {
+		case overrides.AttributeTransformDrop:
+			count++
+			return true
+		case overrides.AttributeTransformHash:
+			count++
+			if len(t.hashKey) == 0 {
+				// without a secret the value can't be hashed safely
+				return true
+			}
+			v.SetStr(hashAttributeValue(t.hashKey, v.AsString()))
+			return false
+		case overrides.AttributeTransformRename:
+			if newKey == k {
+				return false
+			}
+			moved := pcommon.NewValueEmpty()
+			v.CopyTo(moved)
+			renamed = append(renamed, renamedAttribute{key: newKey, value: moved})
+			count++
+			return true
+		}
+		return false
+	})
+
+	// renamed attributes are added after iterating, so they can't be matched again
+	for _, r := range renamed {
+		r.value.CopyTo(attrs.PutEmpty(r.key))
+	}
+
+	return count, renamed
+}
+
+// hashAttributeValue returns the hex encoded HMAC-SHA256 of the value. Unlike a plain digest, it can't be reversed
+// by hashing a dictionary of likely values without knowing the key.
+func hashAttributeValue(key []byte, value string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// tenantHashKey derives the HMAC key of a tenant from the secret, so equal values hash differently for each tenant.
+func tenantHashKey(secret, userID string) []byte {
+	if secret == "" {
+		return nil
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(userID))
+	return mac.Sum(nil)
+}
+
+type tenantAttributeTransforms struct {
+	cfg        []overrides.AttributeTransform
+	transforms []*attributeTransform
+}
+
+// attributeTransformer applies the attribute transforms of the tenants. The transforms are compiled once and
+// recompiled when the overrides of a tenant change.
+type attributeTransformer struct {
+	mtx     sync.Mutex
+	tenants map[string]*tenantAttributeTransforms
+
+	cfgFn      func(userID string) []overrides.AttributeTransform
+	hashSecret string
+	logger     log.Logger
+}
+
+// newAttributeTransformer returns an attributeTransformer. hashSecret is the secret the HMAC keys of hashed values
+// are derived from. If it's empty, attributes that should be hashed are dropped.
+func newAttributeTransformer(cfgFn func(userID string) []overrides.AttributeTransform, hashSecret string, logger log.Logger) *attributeTransformer {
+	return &attributeTransformer{
+		tenants:    map[string]*tenantAttributeTransforms{},
+		cfgFn:      cfgFn,
+		hashSecret: hashSecret,
+		logger:     logger,
+	}
+}
+
+func (a *attributeTransformer) forTenant(userID string) []*attributeTransform {
+	cfg := a.cfgFn(userID)
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	if len(cfg) == 0 {
+		delete(a.tenants, userID)
+		return nil
+	}
+
+	t := a.tenants[userID]
+	if t != nil && reflect.DeepEqual(t.cfg, cfg) {
+		return t.transforms
+	}
+
+	t = &tenantAttributeTransforms{cfg: cfg}
+	hashKey := tenantHashKey(a.hashSecret, userID)
+	for i, c := range cfg {
+		if c.Action == overrides.AttributeTransformHash && hashKey == nil {
+			level.Warn(a.logger).Log("msg", "dropping attributes of hash transform, distributor.attribute_hash_secret is not set", "tenant", userID, "index", i)
+		}
+		transform, err := newAttributeTransform(c, hashKey)
+		if err != nil {
+			level.Warn(a.logger).Log("msg", "ignoring invalid attribute transform", "tenant", userID, "index", i, "err", err)
+			continue
+		}
+		t.transforms = append(t.transforms, transform)
+	}
+	a.tenants[userID] = t
+
+	return t.transforms
+}
+
+// transform applies the attribute transforms of the tenant to the resource, span, span event and span link
+// attributes of the traces. The traces are copied first if they are read-only.
+func (a *attributeTransformer) transform(userID string, traces ptrace.Traces) ptrace.Traces {
+	transforms := a.forTenant(userID)
+	if len(transforms) == 0 {
+		return traces
+	}
+
+	if traces.IsReadOnly() {
+		cpy := ptrace.NewTraces()
+		traces.CopyTo(cpy)
+		traces = cpy
+	}
+
+	counts := make([]int, len(transforms))
+	var renamed []renamedAttribute
+	var n int
+
+	rss := traces.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		for j, t := range transforms {
+			if !t.resource {
+				continue
+			}
+			n, renamed = t.apply(rs.Resource().Attributes(), renamed)
+			counts[j] += n
+		}
+
+		sss := rs.ScopeSpans()
+		for k := 0; k < sss.Len(); k++ {
+			spans := sss.At(k).Spans()
+			for l := 0; l < spans.Len(); l++ {
+				span := spans.At(l)
+				for j, t := range transforms {
+					if !t.span {
+						continue
+					}
+					n, renamed = t.apply(span.Attributes(), renamed)
+					counts[j] += n
+
+					events := span.Events()
+					for e := 0; e < events.Len(); e++ {
+						n, renamed = t.apply(events.At(e).Attributes(), renamed)
+						counts[j] += n
+					}
+					links := span.Links()
+					for e := 0; e < links.Len(); e++ {
+						n, renamed = t.apply(links.At(e).Attributes(), renamed)
+						counts[j] += n
+					}
+				}
+			}
+		}
+	}
+
+	for j, t := range transforms {
+		if counts[j] > 0 {
+			metricAttributesTransformed.WithLabelValues(userID, t.action).Add(float64(counts[j]))
+		}
+	}
+
+	return traces
+}
@@ -0,0 +1,141 @@
+package distributor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+func TestAttributeTransformer(t *testing.T) {
+	cfg := []overrides.AttributeTransform{
+		{Action: overrides.AttributeTransformDrop, Keys: []string{"http.request.header.authorization"}},
+		{Action: overrides.AttributeTransformHash, Scope: overrides.AttributeTransformScopeSpan, Keys: []string{"user.email"}},
+		{Action: overrides.AttributeTransformRename, Scope: overrides.AttributeTransformScopeResource, Regex: `^legacy\.(.*)$`, NewKey: "$1"},
+		{Action: overrides.AttributeTransformDrop, Regex: `^secret\.`},
+	}
+	transformer := newAttributeTransformer(func(string) []overrides.AttributeTransform { return cfg }, "secret", log.NewNopLogger())
+
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("legacy.service.name", "foo")
+	rs.Resource().Attributes().PutStr("user.email", "resource@example.com")
+	rs.Resource().Attributes().PutStr("secret.token", "abc")
+	span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.Attributes().PutStr("http.request.header.authorization", "Bearer abc")
+	span.Attributes().PutStr("user.email", "span@example.com")
+	span.Attributes().PutStr("legacy.foo", "bar")
+	span.Attributes().PutInt("secret.pin", 1234)
+	span.Attributes().PutStr("http.method", "GET")
+	event := span.Events().AppendEmpty()
+	event.Attributes().PutStr("user.email", "event@example.com")
+	event.Attributes().PutStr("secret.token", "abc")
+	link := span.Links().AppendEmpty()
+	link.Attributes().PutStr("user.email", "link@example.com")
+	link.Attributes().PutStr("http.request.header.authorization", "Bearer abc")
+
+	traces = transformer.transform("test", traces)
+
+	rs = traces.ResourceSpans().At(0)
+	require.Equal(t, map[string]any{
+		"service.name": "foo",
+		// hashing is limited to spans
+		"user.email": "resource@example.com",
+	}, rs.Resource().Attributes().AsRaw())
+
+	hash := func(value string) string {
+		key := hmac.New(sha256.New, []byte("secret"))
+		key.Write([]byte("test"))
+		mac := hmac.New(sha256.New, key.Sum(nil))
+		mac.Write([]byte(value))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	span = rs.ScopeSpans().At(0).Spans().At(0)
+	require.Equal(t, map[string]any{
+		"user.email": hash("span@example.com"),
+		// renaming is limited to resources
+		"legacy.foo":  "bar",
+		"http.method": "GET",
+	}, span.Attributes().AsRaw())
+
+	// span transforms apply to the attributes of events and links
+	require.Equal(t, map[string]any{"user.email": hash("event@example.com")}, span.Events().At(0).Attributes().AsRaw())
+	require.Equal(t, map[string]any{"user.email": hash("link@example.com")}, span.Links().At(0).Attributes().AsRaw())
+}
+
+func TestAttributeTransformerHash(t *testing.T) {
+	cfg := []overrides.AttributeTransform{
+		{Action: overrides.AttributeTransformHash, Keys: []string{"user.email"}},
+	}
+	hashed := func(secret, userID string) any {
+		transformer := newAttributeTransformer(func(string) []overrides.AttributeTransform { return cfg }, secret, log.NewNopLogger())
+
+		traces := ptrace.NewTraces()
+		attrs := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().Attributes()
+		attrs.PutStr("user.email", "foo@example.com")
+
+		return transformer.transform(userID, traces).ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().AsRaw()["user.email"]
+	}
+
+	plain := sha256.Sum256([]byte("foo@example.com"))
+	require.NotEqual(t, hex.EncodeToString(plain[:]), hashed("secret", "test"))
+	require.Equal(t, hashed("secret", "test"), hashed("secret", "test"))
+
+	// the key depends on the secret and the tenant
+	require.NotEqual(t, hashed("secret", "test"), hashed("other", "test"))
+	require.NotEqual(t, hashed("secret", "test"), hashed("secret", "other"))
+
+	// without a secret the attribute is dropped
+	require.Nil(t, hashed("", "test"))
+}
+
+func TestAttributeTransformerReloadsOverrides(t *testing.T) {
+	cfg := []overrides.AttributeTransform{
+		{Action: overrides.AttributeTransformDrop, Keys: []string{"foo"}},
+	}
+	transformer := newAttributeTransformer(func(string) []overrides.AttributeTransform { return cfg }, "secret", log.NewNopLogger())
+
+	newTraces := func() ptrace.Traces {
+		traces := ptrace.NewTraces()
+		attrs := traces.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().Attributes()
+		attrs.PutStr("foo", "1")
+		attrs.PutStr("bar", "2")
+		return traces
+	}
+	spanAttrs := func(traces ptrace.Traces) map[string]any {
+		return traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes().AsRaw()
+	}
+
+	require.Equal(t, map[string]any{"bar": "2"}, spanAttrs(transformer.transform("test", newTraces())))
+
+	cfg = []overrides.AttributeTransform{
+		{Action: overrides.AttributeTransformRename, Keys: []string{"bar"}, NewKey: "baz"},
+		// invalid transforms are ignored
+		{Action: overrides.AttributeTransformDrop, Regex: "("},
+	}
+	require.Equal(t, map[string]any{"foo": "1", "baz": "2"}, spanAttrs(transformer.transform("test", newTraces())))
+
+	cfg = nil
+	require.Equal(t, map[string]any{"foo": "1", "bar": "2"}, spanAttrs(transformer.transform("test", newTraces())))
+}
+
+func TestAttributeTransformerReadOnlyTraces(t *testing.T) {
+	cfg := []overrides.AttributeTransform{
+		{Action: overrides.AttributeTransformDrop, Keys: []string{"foo"}},
+	}
+	transformer := newAttributeTransformer(func(string) []overrides.AttributeTransform { return cfg }, "secret", log.NewNopLogger())
+
+	traces := ptrace.NewTraces()
+	traces.ResourceSpans().AppendEmpty().Resource().Attributes().PutStr("foo", "bar")
+	traces.MarkReadOnly()
+
+	transformed := transformer.transform("test", traces)
+	require.Equal(t, map[string]any{}, transformed.ResourceSpans().At(0).Resource().Attributes().AsRaw())
+	require.Equal(t, map[string]any{"foo": "bar"}, traces.ResourceSpans().At(0).Resource().Attributes().AsRaw())
+}
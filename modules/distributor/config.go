@@ -1,6 +1,7 @@
 package distributor
 
 import (
+	"errors"
 	"flag"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/grafana/tempo/pkg/ingest"
 
 	"github.com/grafana/tempo/modules/distributor/forwarder"
+	"github.com/grafana/tempo/modules/distributor/receiver"
 	"github.com/grafana/tempo/modules/distributor/usage"
 	"github.com/grafana/tempo/pkg/util"
 )
@@ -37,15 +39,22 @@ type Config struct {
 	Receivers           map[string]interface{}    `yaml:"receivers"`
 	OverrideRingKey     string                    `yaml:"override_ring_key"`
 	LogReceivedSpans    LogSpansConfig            `yaml:"log_received_spans,omitempty"`
-	LogDiscardedSpans   LogSpansConfig            `yaml:"log_discarded_spans,omitempty"`
+	LogDiscardedSpans   LogDiscardedSpansConfig   `yaml:"log_discarded_spans,omitempty"`
 	MetricReceivedSpans MetricReceivedSpansConfig `yaml:"metric_received_spans,omitempty"`
 	Forwarders          forwarder.ConfigList      `yaml:"forwarders"`
 	Usage               usage.Config              `yaml:"usage,omitempty"`
 
+	// maps receiver names, e.g. otlp/internal, to the tenant ID assigned to requests on that receiver that don't
+	// carry a tenant ID. only used when multitenancy is enabled.
+	ReceiverDefaultTenants map[string]string `yaml:"receiver_default_tenants,omitempty"`
+
 	// Kafka
 	KafkaWritePathEnabled bool               `yaml:"kafka_write_path_enabled"`
 	KafkaConfig           ingest.KafkaConfig `yaml:"kafka_config"`
 
+	// KafkaReceiver consumes traces from a Kafka topic in addition to the receivers.
+	KafkaReceiver receiver.KafkaReceiverConfig `yaml:"kafka_receiver,omitempty"`
+
 	// disables write extension with inactive ingesters. Use this along with ingester.lifecycler.unregister_on_shutdown = true
 	//  note that setting these two config values reduces tolerance to failures on rollout b/c there is always one guaranteed to be failing replica
 	ExtendWrites bool `yaml:"extend_writes"`
@@ -54,10 +63,16 @@ type Config struct {
 	// provided duration
 	RetryAfterOnResourceExhausted time.Duration `yaml:"retry_after_on_resource_exhausted"`
 
+	// TenantProvisioning is asked whether to accept a tenant the first time its traces are received
+	TenantProvisioning TenantProvisioningConfig `yaml:"tenant_provisioning,omitempty"`
+
 	// For testing.
 	factory ring_client.PoolAddrFunc `yaml:"-"`
 
 	MaxAttributeBytes int `yaml:"max_attribute_bytes"`
+
+	// AttributeHashSecret is the secret the HMAC keys of the hash attribute transform are derived from
+	AttributeHashSecret flagext.Secret `yaml:"attribute_hash_secret"`
 }
 
 type LogSpansConfig struct {
@@ -66,6 +81,30 @@ type LogSpansConfig struct {
 	FilterByStatusError  bool `yaml:"filter_by_status_error"`
 }
 
+type LogDiscardedSpansConfig struct {
+	LogSpansConfig `yaml:",inline"`
+
+	// Sink writes structured records of the discarded spans to a file and/or an OTLP logs endpoint instead of the
+	// process log
+	Sink DiscardedSpansSinkConfig `yaml:"sink,omitempty"`
+}
+
+type DiscardedSpansSinkConfig struct {
+	File           string            `yaml:"file,omitempty"`
+	OTLPEndpoint   string            `yaml:"otlp_endpoint,omitempty"`
+	OTLPHeaders    map[string]string `yaml:"otlp_headers,omitempty"`
+	OTLPTimeout    time.Duration     `yaml:"otlp_timeout,omitempty"`
+	RateLimit      float64           `yaml:"rate_limit,omitempty"`
+	RateLimitBurst int               `yaml:"rate_limit_burst,omitempty"`
+	QueueSize      int               `yaml:"queue_size,omitempty"`
+	FlushInterval  time.Duration     `yaml:"flush_interval,omitempty"`
+}
+
+// Enabled returns true if discarded spans are written to the sink instead of the process log
+func (cfg *DiscardedSpansSinkConfig) Enabled() bool {
+	return cfg.File != "" || cfg.OTLPEndpoint != ""
+}
+
 type MetricReceivedSpansConfig struct {
 	Enabled  bool `yaml:"enabled"`
 	RootOnly bool `yaml:"root_only"`
@@ -90,11 +129,34 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	f.BoolVar(&cfg.LogDiscardedSpans.Enabled, util.PrefixConfig(prefix, "log-discarded-spans.enabled"), false, "Enable to log every discarded span to help debug ingestion or calculate span error distributions using the logs.")
 	f.BoolVar(&cfg.LogDiscardedSpans.IncludeAllAttributes, util.PrefixConfig(prefix, "log-discarded-spans.include-attributes"), false, "Enable to include span attributes in the logs.")
 	f.BoolVar(&cfg.LogDiscardedSpans.FilterByStatusError, util.PrefixConfig(prefix, "log-discarded-spans.filter-by-status-error"), false, "Enable to filter out spans without status error.")
+	f.StringVar(&cfg.LogDiscardedSpans.Sink.File, util.PrefixConfig(prefix, "log-discarded-spans.sink.file"), "", "File to write structured records of discarded spans to instead of the process log.")
+	f.StringVar(&cfg.LogDiscardedSpans.Sink.OTLPEndpoint, util.PrefixConfig(prefix, "log-discarded-spans.sink.otlp-endpoint"), "", "OTLP HTTP logs endpoint to export structured records of discarded spans to instead of the process log.")
+	f.DurationVar(&cfg.LogDiscardedSpans.Sink.OTLPTimeout, util.PrefixConfig(prefix, "log-discarded-spans.sink.otlp-timeout"), 10*time.Second, "Timeout of requests to the OTLP logs endpoint.")
+	f.Float64Var(&cfg.LogDiscardedSpans.Sink.RateLimit, util.PrefixConfig(prefix, "log-discarded-spans.sink.rate-limit"), 1000, "Maximum number of discarded span records written per second. 0 disables the limit.")
+	f.IntVar(&cfg.LogDiscardedSpans.Sink.RateLimitBurst, util.PrefixConfig(prefix, "log-discarded-spans.sink.rate-limit-burst"), 1000, "Maximum burst of discarded span records.")
+	f.IntVar(&cfg.LogDiscardedSpans.Sink.QueueSize, util.PrefixConfig(prefix, "log-discarded-spans.sink.queue-size"), 10000, "Number of discarded span records that can be waiting to be written. Further records are dropped.")
+	f.DurationVar(&cfg.LogDiscardedSpans.Sink.FlushInterval, util.PrefixConfig(prefix, "log-discarded-spans.sink.flush-interval"), time.Second, "Interval at which discarded span records are written.")
+
+	f.Var(&cfg.AttributeHashSecret, util.PrefixConfig(prefix, "attribute-hash-secret"), "Secret the per tenant HMAC keys of the hash attribute transform are derived from. Hashed attributes are dropped if it's not set.")
 
+	cfg.TenantProvisioning.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "tenant-provisioning"), f)
 	cfg.Usage.RegisterFlagsAndApplyDefaults(prefix, f)
+	cfg.KafkaReceiver.RegisterFlagsAndApplyDefaults(util.PrefixConfig(prefix, "kafka-receiver"), f)
 }
 
 func (cfg *Config) Validate() error {
+	if cfg.LogDiscardedSpans.Sink.Enabled() && cfg.LogDiscardedSpans.Sink.FlushInterval <= 0 {
+		return errors.New("log discarded spans sink flush interval must be greater than 0")
+	}
+
+	if err := cfg.KafkaReceiver.Validate(); err != nil {
+		return err
+	}
+
+	if err := cfg.TenantProvisioning.Validate(); err != nil {
+		return err
+	}
+
 	if cfg.KafkaWritePathEnabled {
 		if err := cfg.KafkaConfig.Validate(); err != nil {
 			return err
@@ -0,0 +1,324 @@
+package distributor
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	tempo_util "github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	// discardReasonInvalidTraceID indicates that the request was refused because of an invalid trace id
+	discardReasonInvalidTraceID = "invalid_trace_id"
+	// discardReasonInternalError indicates that the spans could not be pushed to the ingesters, for example because
+	// the request was canceled
+	discardReasonInternalError = "internal_error"
+
+	discardedSpansSinkScopeName = "tempo-distributor-discarded-spans"
+
+	// discardedSpansSinkMaxBatchSize is the number of records after which a batch is flushed before the flush interval
+	discardedSpansSinkMaxBatchSize = 1000
+)
+
+var metricDiscardedSpanRecordsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "distributor_discarded_span_records_dropped_total",
+	Help:      "The total number of discarded span records that were not written to the discarded spans sink.",
+}, []string{"reason"})
+
+// discardedSpanRecord is a structured record of a single discarded span
+type discardedSpanRecord struct {
+	Timestamp time.Time `json:"ts"`
+	Tenant    string    `json:"tenant"`
+	Reason    string    `json:"reason"`
+	Service   string    `json:"service"`
+	SpanName  string    `json:"span_name"`
+	TraceID   string    `json:"trace_id"`
+	SpanID    string    `json:"span_id"`
+
+	// only set if include_all_attributes is enabled. the attributes are the resource and span attributes
+	SpanKind        string            `json:"span_kind,omitempty"`
+	SpanStatus      string            `json:"span_status,omitempty"`
+	DurationSeconds float64           `json:"span_duration_seconds,omitempty"`
+	Attributes      map[string]string `json:"attributes,omitempty"`
+}
+
+// discardedSpansSink writes structured records of discarded spans to a file and/or an OTLP logs endpoint instead of
+// the process log. records are rate limited and written in the background, records that can't keep up are dropped.
+type discardedSpansSink struct {
+	services.Service
+
+	cfg     DiscardedSpansSinkConfig
+	limiter *rate.Limiter
+	records chan discardedSpanRecord
+	client  *http.Client
+	file    *os.File
+	logger  log.Logger
+}
+
+func newDiscardedSpansSink(cfg DiscardedSpansSinkConfig, logger log.Logger) (*discardedSpansSink, error) {
+	s := &discardedSpansSink{
+		cfg:     cfg,
+		limiter: rate.NewLimiter(rate.Inf, 0),
+		records: make(chan discardedSpanRecord, cfg.QueueSize),
+		client:  &http.Client{Timeout: cfg.OTLPTimeout},
+		logger:  log.With(logger, "component", "discarded-spans-sink"),
+	}
+	if cfg.RateLimit > 0 {
+		s.limiter = rate.NewLimiter(rate.Limit(cfg.RateLimit), cfg.RateLimitBurst)
+	}
+
+	if cfg.File != "" {
+		f, err := os.OpenFile(cfg.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open discarded spans file: %w", err)
+		}
+		s.file = f
+	}
+
+	s.Service = services.NewBasicService(nil, s.running, s.stopping)
+
+	return s, nil
+}
+
+// recordSpans queues a record for every span in the batches. it never blocks.
+func (s *discardedSpansSink) recordSpans(batches []*v1.ResourceSpans, userID, reason string, cfg *LogSpansConfig) {
+	now := time.Now()
+
+	for _, b := range batches {
+		service := serviceName(b)
+
+		for _, ils := range b.ScopeSpans {
+			for _, span := range ils.Spans {
+				if cfg.FilterByStatusError && span.Status.Code != v1.Status_STATUS_CODE_ERROR {
+					continue
+				}
+
+				r := discardedSpanRecord{
+					Timestamp: now,
+					Tenant:    userID,
+					Reason:    reason,
+					Service:   service,
+					SpanName:  span.Name,
+					TraceID:   hex.EncodeToString(span.TraceId),
+					SpanID:    hex.EncodeToString(span.SpanId),
+				}
+				if cfg.IncludeAllAttributes {
+					r.SpanKind = span.GetKind().String()
+					r.SpanStatus = span.GetStatus().GetCode().String()
+					r.DurationSeconds = float64(span.GetEndTimeUnixNano()-span.GetStartTimeUnixNano()) / float64(time.Second.Nanoseconds())
+					r.Attributes = make(map[string]string, len(b.Resource.GetAttributes())+len(span.GetAttributes()))
+					for _, a := range b.Resource.GetAttributes() {
+						r.Attributes[a.GetKey()] = tempo_util.StringifyAnyValue(a.GetValue())
+					}
+					for _, a := range span.GetAttributes() {
+						r.Attributes[a.GetKey()] = tempo_util.StringifyAnyValue(a.GetValue())
+					}
+				}
+
+				s.record(r)
+			}
+		}
+	}
+}
+
+func (s *discardedSpansSink) record(r discardedSpanRecord) {
+	if !s.limiter.Allow() {
+		metricDiscardedSpanRecordsDropped.WithLabelValues("rate_limited").Inc()
+		return
+	}
+
+	select {
+	case s.records <- r:
+	default:
+		metricDiscardedSpanRecordsDropped.WithLabelValues("queue_full").Inc()
+	}
+}
+
+func (s *discardedSpansSink) running(ctx context.Context) error {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]discardedSpanRecord, 0, discardedSpansSinkMaxBatchSize)
+	for {
+		select {
+		case r := <-s.records:
+			batch = append(batch, r)
+			if len(batch) >= discardedSpansSinkMaxBatchSize {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			s.flush(batch)
+			batch = batch[:0]
+		case <-ctx.Done():
+			// drain the queue, the distributor no longer pushes at this point
+			for {
+				select {
+				case r := <-s.records:
+					batch = append(batch, r)
+				default:
+					s.flush(batch)
+					return nil
+				}
+			}
+		}
+	}
+}
+
+func (s *discardedSpansSink) stopping(_ error) error {
+	if s.file != nil {
+		return s.file.Close()
+	}
+	return nil
+}
+
+func (s *discardedSpansSink) flush(batch []discardedSpanRecord) {
+	if len(batch) == 0 {
+		return
+	}
+
+	if s.file != nil {
+		if err := s.writeFile(batch); err != nil {
+			metricDiscardedSpanRecordsDropped.WithLabelValues("file").Add(float64(len(batch)))
+			level.Error(s.logger).Log("msg", "failed to write discarded spans to file", "records", len(batch), "err", err)
+		}
+	}
+
+	if s.cfg.OTLPEndpoint != "" {
+		if err := s.export(batch); err != nil {
+			metricDiscardedSpanRecordsDropped.WithLabelValues("otlp").Add(float64(len(batch)))
+			level.Error(s.logger).Log("msg", "failed to export discarded spans to OTLP endpoint", "records", len(batch), "err", err)
+		}
+	}
+}
+
+// writeFile writes the records as JSON lines
+func (s *discardedSpansSink) writeFile(batch []discardedSpanRecord) error {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	for _, r := range batch {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+
+	_, err := s.file.Write(buf.Bytes())
+	return err
+}
+
+func (s *discardedSpansSink) export(batch []discardedSpanRecord) error {
+	// LogsData and ExportLogsServiceRequest have the same wire format
+	body, err := (&plog.ProtoMarshaler{}).MarshalLogs(recordsToOTLP(batch))
+	if err != nil {
+		return fmt.Errorf("failed to marshal logs: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.cfg.OTLPEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range s.cfg.OTLPHeaders {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
+
+// recordsToOTLP converts the records into OTLP log records. the fields and attributes of the record are added as
+// attributes and the trace and span ID are set on the log record, so the records can be correlated with the spans.
+func recordsToOTLP(batch []discardedSpanRecord) plog.Logs {
+	ld := plog.NewLogs()
+	sl := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	sl.Scope().SetName(discardedSpansSinkScopeName)
+
+	for _, r := range batch {
+		lr := sl.LogRecords().AppendEmpty()
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(r.Timestamp))
+		lr.SetSeverityNumber(plog.SeverityNumberInfo)
+		lr.Body().SetStr("discarded")
+
+		var traceID pcommon.TraceID
+		if b, err := hex.DecodeString(r.TraceID); err == nil && len(b) == len(traceID) {
+			copy(traceID[:], b)
+			lr.SetTraceID(traceID)
+		}
+		var spanID pcommon.SpanID
+		if b, err := hex.DecodeString(r.SpanID); err == nil && len(b) == len(spanID) {
+			copy(spanID[:], b)
+			lr.SetSpanID(spanID)
+		}
+
+		attrs := lr.Attributes()
+		for k, v := range r.Attributes {
+			attrs.PutStr(k, v)
+		}
+		if r.SpanKind != "" {
+			attrs.PutStr("span_kind", r.SpanKind)
+			attrs.PutStr("span_status", r.SpanStatus)
+			attrs.PutDouble("span_duration_seconds", r.DurationSeconds)
+		}
+		attrs.PutStr("tenant", r.Tenant)
+		attrs.PutStr("reason", r.Reason)
+		attrs.PutStr("service", r.Service)
+		attrs.PutStr("span_name", r.SpanName)
+		attrs.PutStr("trace_id", r.TraceID)
+	}
+
+	return ld
+}
+
+// discardReason returns the reason recorded for spans that were refused by the ingesters
+func discardReason(r tempopb.PushErrorReason) string {
+	switch r {
+	case tempopb.PushErrorReason_MAX_LIVE_TRACES:
+		return reasonLiveTracesExceeded
+	case tempopb.PushErrorReason_MAX_LIVE_TRACES_BYTES:
+		return reasonLiveTracesBytesExceeded
+	case tempopb.PushErrorReason_TRACE_TOO_LARGE:
+		return reasonTraceTooLarge
+	default:
+		return reasonUnknown
+	}
+}
+
+func serviceName(b *v1.ResourceSpans) string {
+	for _, a := range b.Resource.GetAttributes() {
+		if a.Key == "service.name" {
+			return a.GetValue().GetStringValue()
+		}
+	}
+	return ""
+}
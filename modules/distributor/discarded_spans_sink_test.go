@@ -0,0 +1,228 @@
+package distributor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	kitlog "github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/grafana/tempo/modules/overrides"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+func testDiscardedSpansSinkConfig() DiscardedSpansSinkConfig {
+	return DiscardedSpansSinkConfig{
+		OTLPTimeout:   time.Second,
+		QueueSize:     100,
+		FlushInterval: 10 * time.Millisecond,
+	}
+}
+
+func TestDiscardedSpansSinkFile(t *testing.T) {
+	cfg := testDiscardedSpansSinkConfig()
+	cfg.File = filepath.Join(t.TempDir(), "discarded.log")
+
+	sink, err := newDiscardedSpansSink(cfg, kitlog.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), sink))
+
+	batches := []*v1.ResourceSpans{
+		makeResourceSpans("test-service", []*v1.ScopeSpans{
+			makeScope(
+				makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b370", "ok", nil),
+				makeSpan("e3210a2b38097332d1fe43083ea93d29", "6c21c48da4dbd1a7", "error", &v1.Status{Code: v1.Status_STATUS_CODE_ERROR})),
+		}),
+	}
+	sink.recordSpans(batches, "test", reasonTraceTooLarge, &LogSpansConfig{FilterByStatusError: true})
+
+	// stopping flushes the pending records
+	require.NoError(t, services.StopAndAwaitTerminated(context.Background(), sink))
+
+	f, err := os.Open(cfg.File)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var records []discardedSpanRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r discardedSpanRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+		records = append(records, r)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, records, 1)
+	r := records[0]
+	require.Equal(t, "test", r.Tenant)
+	require.Equal(t, reasonTraceTooLarge, r.Reason)
+	require.Equal(t, "test-service", r.Service)
+	require.Equal(t, "error", r.SpanName)
+	require.Equal(t, "e3210a2b38097332d1fe43083ea93d29", r.TraceID)
+	require.Equal(t, "6c21c48da4dbd1a7", r.SpanID)
+}
+
+func TestDiscardedSpansSinkOTLP(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		received []plog.Logs
+		header   string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		ld, err := (&plog.ProtoUnmarshaler{}).UnmarshalLogs(body)
+		assert.NoError(t, err)
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		received = append(received, ld)
+		header = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	cfg := testDiscardedSpansSinkConfig()
+	cfg.OTLPEndpoint = server.URL + "/v1/logs"
+	cfg.OTLPHeaders = map[string]string{"Authorization": "Bearer token"}
+
+	sink, err := newDiscardedSpansSink(cfg, kitlog.NewNopLogger())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), sink))
+	defer func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), sink))
+	}()
+
+	batches := []*v1.ResourceSpans{
+		makeResourceSpans("test-service", []*v1.ScopeSpans{
+			makeScope(makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b370", "span", nil)),
+		}),
+	}
+	sink.recordSpans(batches, "test", discardReasonInternalError, &LogSpansConfig{})
+
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(received) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.Equal(t, "Bearer token", header)
+	require.Equal(t, 1, received[0].LogRecordCount())
+
+	lr := received[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	require.Equal(t, "0a0102030405060708090a0b0c0d0e0f", lr.TraceID().String())
+	require.Equal(t, "dad44adc9a83b370", lr.SpanID().String())
+	require.Equal(t, map[string]any{
+		"tenant":    "test",
+		"reason":    discardReasonInternalError,
+		"service":   "test-service",
+		"span_name": "span",
+		"trace_id":  "0a0102030405060708090a0b0c0d0e0f",
+	}, lr.Attributes().AsRaw())
+}
+
+func TestDiscardedSpansSinkIncludeAllAttributes(t *testing.T) {
+	sink, err := newDiscardedSpansSink(testDiscardedSpansSinkConfig(), kitlog.NewNopLogger())
+	require.NoError(t, err)
+
+	span := makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b370", "span", nil, makeAttribute("http.method", "GET"))
+	span.StartTimeUnixNano = uint64(time.Second)
+	span.EndTimeUnixNano = uint64(3 * time.Second)
+	batches := []*v1.ResourceSpans{
+		makeResourceSpans("test-service", []*v1.ScopeSpans{makeScope(span)}),
+	}
+
+	// without include_all_attributes only the fixed fields are recorded
+	sink.recordSpans(batches, "test", discardReasonInternalError, &LogSpansConfig{})
+	r := <-sink.records
+	require.Empty(t, r.Attributes)
+	require.Empty(t, r.SpanKind)
+
+	sink.recordSpans(batches, "test", discardReasonInternalError, &LogSpansConfig{IncludeAllAttributes: true})
+	r = <-sink.records
+	require.Equal(t, map[string]string{"service.name": "test-service", "http.method": "GET"}, r.Attributes)
+	require.Equal(t, "SPAN_KIND_SERVER", r.SpanKind)
+	require.Equal(t, "STATUS_CODE_OK", r.SpanStatus)
+	require.Equal(t, 2.0, r.DurationSeconds)
+
+	lr := recordsToOTLP([]discardedSpanRecord{r}).ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	require.Equal(t, map[string]any{
+		"tenant":                "test",
+		"reason":                discardReasonInternalError,
+		"service":               "test-service",
+		"span_name":             "span",
+		"trace_id":              "0a0102030405060708090a0b0c0d0e0f",
+		"span_kind":             "SPAN_KIND_SERVER",
+		"span_status":           "STATUS_CODE_OK",
+		"span_duration_seconds": 2.0,
+		"service.name":          "test-service",
+		"http.method":           "GET",
+	}, lr.Attributes().AsRaw())
+}
+
+func TestDiscardedSpansSinkRateLimit(t *testing.T) {
+	cfg := testDiscardedSpansSinkConfig()
+	cfg.File = filepath.Join(t.TempDir(), "discarded.log")
+	cfg.RateLimit = 0.0001
+	cfg.RateLimitBurst = 2
+
+	sink, err := newDiscardedSpansSink(cfg, kitlog.NewNopLogger())
+	require.NoError(t, err)
+
+	for i := 0; i < 5; i++ {
+		sink.record(discardedSpanRecord{Tenant: "test"})
+	}
+	require.Len(t, sink.records, 2)
+}
+
+func TestLogDiscardedSpansToSink(t *testing.T) {
+	limits := overrides.Config{}
+	limits.RegisterFlagsAndApplyDefaults(&flag.FlagSet{})
+
+	buf := &bytes.Buffer{}
+	logger := kitlog.NewJSONLogger(kitlog.NewSyncWriter(buf))
+
+	d, _ := prepare(t, limits, logger)
+	d.cfg.LogDiscardedSpans = LogDiscardedSpansConfig{
+		LogSpansConfig: LogSpansConfig{Enabled: true},
+	}
+
+	cfg := testDiscardedSpansSinkConfig()
+	cfg.File = filepath.Join(t.TempDir(), "discarded.log")
+	sink, err := newDiscardedSpansSink(cfg, kitlog.NewNopLogger())
+	require.NoError(t, err)
+	d.discardedSpansSink = sink
+
+	traces := batchesToTraces(t, []*v1.ResourceSpans{
+		makeResourceSpans("test", []*v1.ScopeSpans{
+			makeScope(makeSpan("0a0102030405060708090a0b0c0d0e0f", "dad44adc9a83b370", "Test Span", nil)),
+		}),
+	})
+	ctx, cancel := context.WithCancel(ctx)
+	cancel() // cancel to force all spans to be discarded
+
+	_, err = d.PushTraces(ctx, traces)
+	require.Error(t, err)
+
+	// the span is recorded in the sink instead of the process log
+	require.Empty(t, actualLogSpan(t, buf))
+	require.Len(t, sink.records, 1)
+	r := <-sink.records
+	require.Equal(t, discardReasonInternalError, r.Reason)
+	require.Equal(t, "0a0102030405060708090a0b0c0d0e0f", r.TraceID)
+}
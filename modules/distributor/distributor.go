@@ -54,10 +54,15 @@ const (
 	reasonTraceTooLarge = "trace_too_large"
 	// reasonLiveTracesExceeded indicates that tempo is already tracking too many live traces in the ingesters for this user
 	reasonLiveTracesExceeded = "live_traces_exceeded"
+	// reasonLiveTracesBytesExceeded indicates that the live traces in the ingesters for this user exceed max_live_traces_bytes
+	reasonLiveTracesBytesExceeded = "live_traces_bytes_exceeded"
 	// reasonUnknown indicates a pushByte error at the ingester level not related to GRPC
 	reasonUnknown = "unknown_error"
 
 	distributorRingKey = "distributor"
+
+	// maxPooledSegmentBufferSize is the largest marshalled segment buffer that is returned to the pool
+	maxPooledSegmentBufferSize = 1 << 20
 )
 
 var (
@@ -151,6 +156,9 @@ var (
 
 var tracer = otel.Tracer("modules/distributor")
 
+// segmentBufferPool reuses the buffers trace segments are marshalled into before they are pushed to the ingesters
+var segmentBufferPool = sync.Pool{}
+
 // rebatchedTrace is used to more cleanly pass the set of data
 type rebatchedTrace struct {
 	id        []byte
@@ -194,6 +202,14 @@ type Distributor struct {
 
 	usage *usage.Tracker
 
+	// discardedSpansSink is set if discarded spans are written to a separate sink instead of the process log
+	discardedSpansSink *discardedSpansSink
+
+	// tenantProvisioning is set if new tenants are provisioned by a hook
+	tenantProvisioning *tenantProvisioning
+
+	attributeTransformer *attributeTransformer
+
 	logger log.Logger
 }
 
@@ -215,6 +231,10 @@ func New(
 		return nil, err
 	}
 
+	if err := clientCfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	factory := cfg.factory
 	if factory == nil {
 		factory = func(addr string) (ring_client.PoolClient, error) {
@@ -268,9 +288,14 @@ func New(
 		partitionRing:        partitionRing,
 		overrides:            o,
 		traceEncoder:         model.MustNewSegmentDecoder(model.CurrentEncoding),
+		attributeTransformer: newAttributeTransformer(o.IngestionAttributeTransforms, cfg.AttributeHashSecret.String(), logger),
 		logger:               logger,
 	}
 
+	if cfg.TenantProvisioning.enabled() {
+		d.tenantProvisioning = newTenantProvisioning(cfg.TenantProvisioning, o, logger)
+	}
+
 	if cfg.Usage.CostAttribution.Enabled {
 		usage, err := usage.NewTracker(cfg.Usage.CostAttribution, "cost-attribution", o.CostAttributionDimensions, o.CostAttributionMaxCardinality)
 		if err != nil {
@@ -293,6 +318,15 @@ func New(
 
 	subservices = append(subservices, d.generatorsPool)
 
+	if cfg.LogDiscardedSpans.Enabled && cfg.LogDiscardedSpans.Sink.Enabled() {
+		sink, err := newDiscardedSpansSink(cfg.LogDiscardedSpans.Sink, logger)
+		if err != nil {
+			return nil, err
+		}
+		d.discardedSpansSink = sink
+		subservices = append(subservices, sink)
+	}
+
 	d.generatorForwarder = newGeneratorForwarder(logger, d.sendToGenerators, o)
 	subservices = append(subservices, d.generatorForwarder)
 
@@ -309,12 +343,20 @@ func New(
 		cfgReceivers = defaultReceivers
 	}
 
-	receivers, err := receiver.New(cfgReceivers, d, middleware, cfg.RetryAfterOnResourceExhausted, loggingLevel, reg)
+	receivers, err := receiver.New(cfgReceivers, cfg.ReceiverDefaultTenants, d, middleware, cfg.RetryAfterOnResourceExhausted, loggingLevel, reg)
 	if err != nil {
 		return nil, err
 	}
 	subservices = append(subservices, receivers)
 
+	if cfg.KafkaReceiver.Enabled {
+		kafkaReceiver, err := receiver.NewKafkaReceiver(cfg.KafkaReceiver, d, middleware, logger, reg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kafka receiver: %w", err)
+		}
+		subservices = append(subservices, kafkaReceiver)
+	}
+
 	if cfg.KafkaWritePathEnabled {
 		client, err := ingest.NewWriterClient(cfg.KafkaConfig, 10, logger, prometheus.WrapRegistererWithPrefix("tempo_distributor_", reg))
 		if err != nil {
@@ -392,6 +434,9 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 	ctx, span := tracer.Start(ctx, "distributor.PushBytes")
 	defer span.End()
 
+	// ingesters measure the ingest latency from this point
+	ctx = ingester_client.InjectReceivedTime(ctx, time.Now())
+
 	userID, spanCount, size, err := d.extractBasicInfo(ctx, traces)
 	if err != nil {
 		// can't record discarded spans here b/c there's no tenant
@@ -400,6 +445,11 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 	if spanCount == 0 {
 		return &tempopb.PushResponse{}, nil
 	}
+	if d.tenantProvisioning != nil {
+		if err := d.tenantProvisioning.admit(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
 	// check limits
 	// todo - usage tracker include discarded bytes?
 	err = d.checkForRateLimits(size, spanCount, userID)
@@ -407,6 +457,9 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 		return nil, err
 	}
 
+	// Drop, hash and rename attributes before the traces are logged, forwarded or written
+	traces = d.attributeTransformer.transform(userID, traces)
+
 	// Convert to bytes and back. This is unfortunate for efficiency, but it works
 	// around the otel-collector internalization of otel-proto which Tempo also uses.
 	convert, err := (&ptrace.ProtoMarshaler{}).MarshalTraces(traces)
@@ -443,7 +496,7 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 
 	keys, rebatchedTraces, truncatedAttributeCount, err := requestsByTraceID(batches, userID, spanCount, maxAttributeBytes)
 	if err != nil {
-		logDiscardedResourceSpans(batches, userID, &d.cfg.LogDiscardedSpans, d.logger)
+		d.logDiscardedResourceSpans(batches, userID, discardReasonInvalidTraceID, d.logger)
 		return nil, err
 	}
 
@@ -479,8 +532,9 @@ func (d *Distributor) PushTraces(ctx context.Context, traces ptrace.Traces) (*te
 func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string, totalSpanCount int, traces []*rebatchedTrace, keys []uint32) error {
 	marshalledTraces := make([][]byte, len(traces))
 	for i, t := range traces {
-		b, err := d.traceEncoder.PrepareForWrite(t.trace, t.start, t.end)
+		b, err := d.traceEncoder.PrepareForWriteTo(getSegmentBuffer(), t.trace, t.start, t.end)
 		if err != nil {
+			putSegmentBuffers(marshalledTraces[:i])
 			return fmt.Errorf("failed to marshal PushRequest: %w", err)
 		}
 		marshalledTraces[i] = b
@@ -497,6 +551,7 @@ func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string
 
 	var mu sync.Mutex
 
+	// the shuffle shard excludes read-only ingesters. ingesters mark themselves read-only when under backpressure
 	writeRing := d.ingestersRing.ShuffleShard(userID, d.overrides.IngestionTenantShardSize(userID))
 
 	err := ring.DoBatchWithOptions(ctx, op, writeRing, keys, func(ingester ring.InstanceDesc, indexes []int) error {
@@ -533,10 +588,13 @@ func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string
 		d.processPushResponse(pushResponse, numSuccessByTraceIndex, lastErrorReasonByTraceIndex, numOfTraces, indexes)
 
 		return nil
-	}, ring.DoBatchOptions{})
+	}, ring.DoBatchOptions{
+		// the gRPC client has serialized the requests once all batches are finished so the segments can be reused
+		Cleanup: func() { putSegmentBuffers(marshalledTraces) },
+	})
 	// if err != nil, we discarded everything because of an internal error (like "context cancelled")
 	if err != nil {
-		logDiscardedRebatchedSpans(traces, userID, &d.cfg.LogDiscardedSpans, d.logger)
+		d.logDiscardedRebatchedSpans(traces, userID)
 		return err
 	}
 
@@ -544,11 +602,31 @@ func (d *Distributor) sendToIngestersViaBytes(ctx context.Context, userID string
 	mu.Lock()
 	defer mu.Unlock()
 	recordDiscardedSpans(numSuccessByTraceIndex, lastErrorReasonByTraceIndex, traces, writeRing, userID)
-	logDiscardedSpans(numSuccessByTraceIndex, lastErrorReasonByTraceIndex, traces, writeRing, userID, &d.cfg.LogDiscardedSpans, d.logger)
+	d.logDiscardedSpans(numSuccessByTraceIndex, lastErrorReasonByTraceIndex, traces, writeRing, userID)
 
 	return nil
 }
 
+// getSegmentBuffer returns a buffer from the pool to marshal a trace segment into.
+func getSegmentBuffer() []byte {
+	if b, ok := segmentBufferPool.Get().(*[]byte); ok {
+		return (*b)[:0]
+	}
+	return nil
+}
+
+// putSegmentBuffers returns the segment buffers to the pool. Oversized buffers are dropped so that a single
+// large trace doesn't pin memory in the pool.
+func putSegmentBuffers(buffs [][]byte) {
+	for _, b := range buffs {
+		if b == nil || cap(b) > maxPooledSegmentBufferSize {
+			continue
+		}
+		b = b[:0]
+		segmentBufferPool.Put(&b)
+	}
+}
+
 func (d *Distributor) sendToGenerators(ctx context.Context, userID string, keys []uint32, traces []*rebatchedTrace) error {
 	// If an instance is unhealthy write to the next one (i.e. write extend is enabled)
 	op := ring.Write
@@ -817,7 +895,7 @@ func newDiscardedPredicate(repFactor int) discardedPredicate {
 	}
 }
 
-func countDiscardedSpans(numSuccessByTraceIndex []int, lastErrorReasonByTraceIndex []tempopb.PushErrorReason, traces []*rebatchedTrace, repFactor int) (maxLiveDiscardedCount, traceTooLargeDiscardedCount, unknownErrorCount int) {
+func countDiscardedSpans(numSuccessByTraceIndex []int, lastErrorReasonByTraceIndex []tempopb.PushErrorReason, traces []*rebatchedTrace, repFactor int) (maxLiveDiscardedCount, maxLiveBytesDiscardedCount, traceTooLargeDiscardedCount, unknownErrorCount int) {
 	discarded := newDiscardedPredicate(repFactor)
 
 	for traceIndex, numSuccess := range numSuccessByTraceIndex {
@@ -828,6 +906,8 @@ func countDiscardedSpans(numSuccessByTraceIndex []int, lastErrorReasonByTraceInd
 		switch lastErrorReasonByTraceIndex[traceIndex] {
 		case tempopb.PushErrorReason_MAX_LIVE_TRACES:
 			maxLiveDiscardedCount += spanCount
+		case tempopb.PushErrorReason_MAX_LIVE_TRACES_BYTES:
+			maxLiveBytesDiscardedCount += spanCount
 		case tempopb.PushErrorReason_TRACE_TOO_LARGE:
 			traceTooLargeDiscardedCount += spanCount
 		case tempopb.PushErrorReason_UNKNOWN_ERROR:
@@ -835,7 +915,7 @@ func countDiscardedSpans(numSuccessByTraceIndex []int, lastErrorReasonByTraceInd
 		}
 	}
 
-	return maxLiveDiscardedCount, traceTooLargeDiscardedCount, unknownErrorCount
+	return maxLiveDiscardedCount, maxLiveBytesDiscardedCount, traceTooLargeDiscardedCount, unknownErrorCount
 }
 
 func (d *Distributor) processPushResponse(pushResponse *tempopb.PushResponse, numSuccessByTraceIndex []int, lastErrorReasonByTraceIndex []tempopb.PushErrorReason, numOfTraces int, indexes []int) {
@@ -896,14 +976,15 @@ func metricSpans(batches []*v1.ResourceSpans, tenantID string, cfg *MetricReceiv
 }
 
 func recordDiscardedSpans(numSuccessByTraceIndex []int, lastErrorReasonByTraceIndex []tempopb.PushErrorReason, traces []*rebatchedTrace, writeRing ring.ReadRing, userID string) {
-	maxLiveDiscardedCount, traceTooLargeDiscardedCount, unknownErrorCount := countDiscardedSpans(numSuccessByTraceIndex, lastErrorReasonByTraceIndex, traces, writeRing.ReplicationFactor())
+	maxLiveDiscardedCount, maxLiveBytesDiscardedCount, traceTooLargeDiscardedCount, unknownErrorCount := countDiscardedSpans(numSuccessByTraceIndex, lastErrorReasonByTraceIndex, traces, writeRing.ReplicationFactor())
 	overrides.RecordDiscardedSpans(maxLiveDiscardedCount, reasonLiveTracesExceeded, userID)
+	overrides.RecordDiscardedSpans(maxLiveBytesDiscardedCount, reasonLiveTracesBytesExceeded, userID)
 	overrides.RecordDiscardedSpans(traceTooLargeDiscardedCount, reasonTraceTooLarge, userID)
 	overrides.RecordDiscardedSpans(unknownErrorCount, reasonUnknown, userID)
 }
 
-func logDiscardedSpans(numSuccessByTraceIndex []int, lastErrorReasonByTraceIndex []tempopb.PushErrorReason, traces []*rebatchedTrace, writeRing ring.ReadRing, userID string, cfg *LogSpansConfig, logger log.Logger) {
-	if !cfg.Enabled {
+func (d *Distributor) logDiscardedSpans(numSuccessByTraceIndex []int, lastErrorReasonByTraceIndex []tempopb.PushErrorReason, traces []*rebatchedTrace, writeRing ring.ReadRing, userID string) {
+	if !d.cfg.LogDiscardedSpans.Enabled {
 		return
 	}
 	discarded := newDiscardedPredicate(writeRing.ReplicationFactor())
@@ -913,36 +994,41 @@ func logDiscardedSpans(numSuccessByTraceIndex []int, lastErrorReasonByTraceIndex
 		}
 		errorReason := lastErrorReasonByTraceIndex[traceIndex]
 		if errorReason != tempopb.PushErrorReason_NO_ERROR {
-			loggerWithAtts := logger
+			loggerWithAtts := d.logger
 			loggerWithAtts = log.With(
 				loggerWithAtts,
 				"push_error_reason", fmt.Sprintf("%v", errorReason),
 			)
-			logDiscardedResourceSpans(traces[traceIndex].trace.ResourceSpans, userID, cfg, loggerWithAtts)
+			d.logDiscardedResourceSpans(traces[traceIndex].trace.ResourceSpans, userID, discardReason(errorReason), loggerWithAtts)
 		}
 	}
 }
 
-func logDiscardedRebatchedSpans(batches []*rebatchedTrace, userID string, cfg *LogSpansConfig, logger log.Logger) {
-	if !cfg.Enabled {
+func (d *Distributor) logDiscardedRebatchedSpans(batches []*rebatchedTrace, userID string) {
+	if !d.cfg.LogDiscardedSpans.Enabled {
 		return
 	}
 	for _, b := range batches {
-		logDiscardedResourceSpans(b.trace.ResourceSpans, userID, cfg, logger)
+		d.logDiscardedResourceSpans(b.trace.ResourceSpans, userID, discardReasonInternalError, d.logger)
 	}
 }
 
-func logDiscardedResourceSpans(batches []*v1.ResourceSpans, userID string, cfg *LogSpansConfig, logger log.Logger) {
+func (d *Distributor) logDiscardedResourceSpans(batches []*v1.ResourceSpans, userID, reason string, logger log.Logger) {
+	cfg := &d.cfg.LogDiscardedSpans
 	if !cfg.Enabled {
 		return
 	}
+	if d.discardedSpansSink != nil {
+		d.discardedSpansSink.recordSpans(batches, userID, reason, &cfg.LogSpansConfig)
+		return
+	}
 	loggerWithAtts := logger
 	loggerWithAtts = log.With(
 		loggerWithAtts,
 		"msg", "discarded",
 		"tenant", userID,
 	)
-	logSpans(batches, cfg, loggerWithAtts)
+	logSpans(batches, &cfg.LogSpansConfig, loggerWithAtts)
 }
 
 func logReceivedSpans(batches []*v1.ResourceSpans, cfg *LogSpansConfig, logger log.Logger) {
@@ -1215,10 +1215,12 @@ func TestLogDiscardedSpansWhenContextCancelled(t *testing.T) {
 			logger := kitlog.NewJSONLogger(kitlog.NewSyncWriter(buf))
 
 			d, _ := prepare(t, limits, logger)
-			d.cfg.LogDiscardedSpans = LogSpansConfig{
-				Enabled:              tc.LogDiscardedSpansEnabled,
-				FilterByStatusError:  tc.filterByStatusError,
-				IncludeAllAttributes: tc.includeAllAttributes,
+			d.cfg.LogDiscardedSpans = LogDiscardedSpansConfig{
+				LogSpansConfig: LogSpansConfig{
+					Enabled:              tc.LogDiscardedSpansEnabled,
+					FilterByStatusError:  tc.filterByStatusError,
+					IncludeAllAttributes: tc.includeAllAttributes,
+				},
 			}
 
 			traces := batchesToTraces(t, tc.batches)
@@ -1283,10 +1285,12 @@ func TestLogDiscardedSpansWhenPushToIngesterFails(t *testing.T) {
 			logger := kitlog.NewJSONLogger(kitlog.NewSyncWriter(buf))
 
 			d, ingesters := prepare(t, limits, logger)
-			d.cfg.LogDiscardedSpans = LogSpansConfig{
-				Enabled:              tc.LogDiscardedSpansEnabled,
-				FilterByStatusError:  tc.filterByStatusError,
-				IncludeAllAttributes: tc.includeAllAttributes,
+			d.cfg.LogDiscardedSpans = LogDiscardedSpansConfig{
+				LogSpansConfig: LogSpansConfig{
+					Enabled:              tc.LogDiscardedSpansEnabled,
+					FilterByStatusError:  tc.filterByStatusError,
+					IncludeAllAttributes: tc.includeAllAttributes,
+				},
 			}
 
 			// mock ingester errors
@@ -1510,7 +1514,7 @@ func TestDiscardCountReplicationFactor(t *testing.T) {
 				}
 			}
 
-			liveTraceDiscardedCount, traceTooLongDiscardedCount, _ := countDiscardedSpans(numSuccessByTraceIndex, lastErrorReasonByTraceIndex, traceByID, tc.replicationFactor)
+			liveTraceDiscardedCount, _, traceTooLongDiscardedCount, _ := countDiscardedSpans(numSuccessByTraceIndex, lastErrorReasonByTraceIndex, traceByID, tc.replicationFactor)
 
 			require.Equal(t, tc.expectedLiveTracesDiscardedCount, liveTraceDiscardedCount)
 			require.Equal(t, tc.expectedTraceTooLargeDiscardedCount, traceTooLongDiscardedCount)
@@ -1636,7 +1640,7 @@ func TestIngesterPushBytes(t *testing.T) {
 		d.processPushResponse(pushResponse, numSuccessByTraceIndex, lastErrorReasonByTraceIndex, numOfTraces, indexes)
 	}
 
-	maxLiveDiscardedCount, traceTooLargeDiscardedCount, _ := countDiscardedSpans(numSuccessByTraceIndex, lastErrorReasonByTraceIndex, traces, 3)
+	maxLiveDiscardedCount, _, traceTooLargeDiscardedCount, _ := countDiscardedSpans(numSuccessByTraceIndex, lastErrorReasonByTraceIndex, traces, 3)
 	assert.Equal(t, traceTooLargeDiscardedCount, 6)
 	assert.Equal(t, maxLiveDiscardedCount, 35)
 }
@@ -1885,3 +1889,18 @@ func (r mockRing) InstancesWithTokensInZoneCount(_ string) int {
 func (r mockRing) ZonesCount() int {
 	return 0
 }
+
+func TestSegmentBufferPool(t *testing.T) {
+	small := make([]byte, 10, 100)
+	large := make([]byte, 10, maxPooledSegmentBufferSize+1)
+
+	putSegmentBuffers([][]byte{nil, large, small})
+
+	// only the small buffer is pooled. sync.Pool gives no guarantee an item is retained so just
+	// confirm that whatever is returned is empty and not the oversized buffer
+	for i := 0; i < 3; i++ {
+		b := getSegmentBuffer()
+		require.Len(t, b, 0)
+		require.LessOrEqual(t, cap(b), maxPooledSegmentBufferSize)
+	}
+}
@@ -0,0 +1,55 @@
+package awsxray
+
+import (
+	"errors"
+	"net"
+)
+
+const (
+	defaultUDPEndpoint = "localhost:2000"
+
+	// maxUDPPacketBytes is the maximum size of a segment sent to the UDP endpoint. The X-Ray SDKs never send
+	// larger packets.
+	maxUDPPacketBytes = 64 * 1024
+	// maxHTTPBodyBytes is the maximum size of a PutTraceSegments request.
+	maxHTTPBodyBytes = 5 * 1024 * 1024
+)
+
+// Config configures the X-Ray receiver.
+type Config struct {
+	// Endpoint is the UDP address the segments of the X-Ray SDKs are received on, like the X-Ray daemon. Empty
+	// disables the UDP endpoint.
+	Endpoint string `mapstructure:"endpoint"`
+	// HTTP configures the endpoint of the PutTraceSegments API.
+	HTTP HTTPConfig `mapstructure:"http"`
+}
+
+// HTTPConfig configures the HTTP endpoint of the X-Ray receiver.
+type HTTPConfig struct {
+	// Endpoint is the address of the PutTraceSegments API. Empty disables the HTTP endpoint.
+	Endpoint string `mapstructure:"endpoint"`
+}
+
+func createDefaultConfig() *Config {
+	return &Config{
+		Endpoint: defaultUDPEndpoint,
+	}
+}
+
+// Validate implements component.ConfigValidator
+func (c *Config) Validate() error {
+	if c.Endpoint == "" && c.HTTP.Endpoint == "" {
+		return errors.New("at least one of endpoint and http.endpoint must be set")
+	}
+	if c.Endpoint != "" {
+		if _, err := net.ResolveUDPAddr("udp", c.Endpoint); err != nil {
+			return err
+		}
+	}
+	if c.HTTP.Endpoint != "" {
+		if _, _, err := net.SplitHostPort(c.HTTP.Endpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
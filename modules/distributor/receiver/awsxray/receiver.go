@@ -0,0 +1,309 @@
+package awsxray
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// typeStr is the type of the receiver in the receivers config
+	typeStr = "awsxray"
+
+	transportUDP  = "udp"
+	transportHTTP = "http"
+	format        = "xray"
+
+	// errorCodeInvalidSegment is returned for segments of a PutTraceSegments request that can't be converted
+	errorCodeInvalidSegment = "InvalidSegment"
+)
+
+// NewFactory returns a factory of the X-Ray receiver.
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		component.MustNewType(typeStr),
+		func() component.Config { return createDefaultConfig() },
+		receiver.WithTraces(createTraces, component.StabilityLevelAlpha),
+	)
+}
+
+func createTraces(_ context.Context, set receiver.Settings, cfg component.Config, next consumer.Traces) (receiver.Traces, error) {
+	return newReceiver(cfg.(*Config), set, next)
+}
+
+// xrayReceiver receives X-Ray segment documents on a UDP endpoint like the X-Ray daemon and on an HTTP endpoint
+// that implements the PutTraceSegments API. Segments are converted to spans.
+type xrayReceiver struct {
+	cfg    *Config
+	next   consumer.Traces
+	logger *zap.Logger
+
+	udpObsreport  *receiverhelper.ObsReport
+	httpObsreport *receiverhelper.ObsReport
+
+	conn   net.PacketConn
+	server *http.Server
+	wg     sync.WaitGroup
+}
+
+func newReceiver(cfg *Config, set receiver.Settings, next consumer.Traces) (*xrayReceiver, error) {
+	udpObsreport, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             set.ID,
+		Transport:              transportUDP,
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+	httpObsreport, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             set.ID,
+		Transport:              transportHTTP,
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &xrayReceiver{
+		cfg:           cfg,
+		next:          next,
+		logger:        set.Logger,
+		udpObsreport:  udpObsreport,
+		httpObsreport: httpObsreport,
+	}, nil
+}
+
+// Start implements component.Component
+func (r *xrayReceiver) Start(_ context.Context, host component.Host) error {
+	if r.cfg.Endpoint != "" {
+		conn, err := net.ListenPacket("udp", r.cfg.Endpoint)
+		if err != nil {
+			return fmt.Errorf("listening on %s: %w", r.cfg.Endpoint, err)
+		}
+		r.conn = conn
+
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			r.serveUDP()
+		}()
+	}
+
+	if r.cfg.HTTP.Endpoint != "" {
+		ln, err := net.Listen("tcp", r.cfg.HTTP.Endpoint)
+		if err != nil {
+			if r.conn != nil {
+				_ = r.conn.Close()
+			}
+			return fmt.Errorf("listening on %s: %w", r.cfg.HTTP.Endpoint, err)
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /TraceSegments", r.handlePutTraceSegments)
+		r.server = &http.Server{
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			if err := r.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				componentstatus.ReportStatus(host, componentstatus.NewFatalErrorEvent(err))
+			}
+		}()
+	}
+
+	return nil
+}
+
+// Shutdown implements component.Component
+func (r *xrayReceiver) Shutdown(ctx context.Context) error {
+	var errs []error
+	if r.conn != nil {
+		errs = append(errs, r.conn.Close())
+	}
+	if r.server != nil {
+		errs = append(errs, r.server.Shutdown(ctx))
+	}
+	r.wg.Wait()
+	return errors.Join(errs...)
+}
+
+// serveUDP receives segments sent by the X-Ray SDKs. Every packet is a header followed by a single segment document:
+//
+//	{"format": "json", "version": 1}
+//	{"name": "example", ...}
+func (r *xrayReceiver) serveUDP() {
+	buf := make([]byte, maxUDPPacketBytes)
+	for {
+		n, addr, err := r.conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			r.logger.Warn("failed to read segment", zap.Error(err))
+			continue
+		}
+
+		ctx := client.NewContext(context.Background(), client.Info{Addr: addr})
+		ctx = r.udpObsreport.StartTracesOp(ctx)
+
+		td := ptrace.NewTraces()
+		spans, err := r.convertPacket(td, buf[:n])
+		if err != nil {
+			r.logger.Debug("dropping invalid segment", zap.Error(err), zap.Stringer("client", addr))
+			r.udpObsreport.EndTracesOp(ctx, format, 0, err)
+			continue
+		}
+		if spans == 0 {
+			r.udpObsreport.EndTracesOp(ctx, format, 0, nil)
+			continue
+		}
+
+		err = r.next.ConsumeTraces(ctx, td)
+		r.udpObsreport.EndTracesOp(ctx, format, spans, err)
+	}
+}
+
+func (r *xrayReceiver) convertPacket(td ptrace.Traces, packet []byte) (int, error) {
+	header, doc, ok := bytes.Cut(packet, []byte("\n"))
+	if !ok {
+		return 0, errors.New("missing header")
+	}
+
+	var h struct {
+		Format  string `json:"format"`
+		Version int    `json:"version"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil {
+		return 0, fmt.Errorf("invalid header: %w", err)
+	}
+	if h.Format != "json" || h.Version != 1 {
+		return 0, fmt.Errorf("unsupported header format %q version %d", h.Format, h.Version)
+	}
+
+	seg, err := parseSegment(doc)
+	if err != nil {
+		return 0, err
+	}
+	return appendSegment(td, seg)
+}
+
+type putTraceSegmentsRequest struct {
+	TraceSegmentDocuments []string `json:"TraceSegmentDocuments"`
+}
+
+type putTraceSegmentsResponse struct {
+	UnprocessedTraceSegments []unprocessedTraceSegment `json:"UnprocessedTraceSegments"`
+}
+
+type unprocessedTraceSegment struct {
+	ID        string `json:"Id,omitempty"`
+	ErrorCode string `json:"ErrorCode"`
+	Message   string `json:"Message"`
+}
+
+// handlePutTraceSegments implements the PutTraceSegments API. Segments that can't be converted are returned as
+// unprocessed, the others are accepted.
+func (r *xrayReceiver) handlePutTraceSegments(w http.ResponseWriter, req *http.Request) {
+	// headers are added to the client metadata. the tenant ID is taken from the X-Scope-OrgID header
+	ctx := client.NewContext(req.Context(), client.Info{
+		Addr:     tcpAddr(req.RemoteAddr),
+		Metadata: client.NewMetadata(req.Header),
+	})
+	ctx = r.httpObsreport.StartTracesOp(ctx)
+
+	var body putTraceSegmentsRequest
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxHTTPBodyBytes)).Decode(&body); err != nil {
+		r.httpObsreport.EndTracesOp(ctx, format, 0, err)
+		http.Error(w, fmt.Sprintf("invalid request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	resp := putTraceSegmentsResponse{UnprocessedTraceSegments: []unprocessedTraceSegment{}}
+	td := ptrace.NewTraces()
+	spans := 0
+	for _, doc := range body.TraceSegmentDocuments {
+		seg, err := parseSegment([]byte(doc))
+		if err == nil {
+			var n int
+			n, err = appendSegment(td, seg)
+			spans += n
+		}
+		if err != nil {
+			resp.UnprocessedTraceSegments = append(resp.UnprocessedTraceSegments, unprocessedTraceSegment{
+				ID:        segmentID(doc),
+				ErrorCode: errorCodeInvalidSegment,
+				Message:   err.Error(),
+			})
+		}
+	}
+
+	var err error
+	if spans > 0 {
+		err = r.next.ConsumeTraces(ctx, td)
+	}
+	r.httpObsreport.EndTracesOp(ctx, format, spans, err)
+	if err != nil {
+		http.Error(w, err.Error(), httpStatusCode(err))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// segmentID returns the id of a segment document that failed to parse, if it has one.
+func segmentID(doc string) string {
+	var seg struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal([]byte(doc), &seg)
+	return seg.ID
+}
+
+// httpStatusCode returns the status code of an error returned by the distributor.
+func httpStatusCode(err error) int {
+	s, ok := status.FromError(err)
+	if !ok {
+		return http.StatusInternalServerError
+	}
+
+	switch s.Code() {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func tcpAddr(addr string) net.Addr {
+	a, err := net.ResolveTCPAddr("tcp", addr)
+	if err != nil {
+		return nil
+	}
+	return a
+}
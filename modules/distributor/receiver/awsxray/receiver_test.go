@@ -0,0 +1,119 @@
+package awsxray
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReceiverUDP(t *testing.T) {
+	sink := &consumertest.TracesSink{}
+	r := newTestReceiver(t, &Config{Endpoint: "127.0.0.1:0"}, sink)
+
+	conn, err := net.Dial("udp", r.conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// invalid packets are dropped
+	_, err = conn.Write([]byte(`{"name": "no header"}`))
+	require.NoError(t, err)
+	_, err = conn.Write([]byte("{\"format\": \"json\", \"version\": 1}\n{\"name\": \"invalid\"}"))
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte("{\"format\": \"json\", \"version\": 1}\n" + testSegment))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return sink.SpanCount() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Len(t, sink.AllTraces(), 1)
+}
+
+func TestReceiverHTTP(t *testing.T) {
+	var tenants []string
+	sink := &consumertest.TracesSink{}
+	next, err := consumer.NewTraces(func(ctx context.Context, td ptrace.Traces) error {
+		tenants = append(tenants, client.FromContext(ctx).Metadata.Get("X-Scope-OrgID")...)
+		return sink.ConsumeTraces(ctx, td)
+	})
+	require.NoError(t, err)
+
+	r := newTestReceiver(t, &Config{HTTP: HTTPConfig{Endpoint: "127.0.0.1:0"}}, next)
+
+	body, err := json.Marshal(putTraceSegmentsRequest{TraceSegmentDocuments: []string{
+		testSegment,
+		`{"name": "invalid", "id": "53995c3f42cd8ad8"}`,
+	}})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/TraceSegments", strings.NewReader(string(body)))
+	req.Header.Set("X-Scope-OrgID", "tenant-a")
+	rec := httptest.NewRecorder()
+	r.server.Handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp putTraceSegmentsResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp.UnprocessedTraceSegments, 1)
+	require.Equal(t, "53995c3f42cd8ad8", resp.UnprocessedTraceSegments[0].ID)
+	require.Equal(t, errorCodeInvalidSegment, resp.UnprocessedTraceSegments[0].ErrorCode)
+
+	require.Equal(t, 3, sink.SpanCount())
+	require.Equal(t, []string{"tenant-a"}, tenants)
+}
+
+func TestReceiverHTTPErrors(t *testing.T) {
+	next := consumertest.NewErr(status.Error(codes.ResourceExhausted, "rate limited"))
+	r := newTestReceiver(t, &Config{HTTP: HTTPConfig{Endpoint: "127.0.0.1:0"}}, next)
+
+	// invalid body
+	rec := httptest.NewRecorder()
+	r.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/TraceSegments", strings.NewReader("{")))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// errors of the distributor are returned
+	body, err := json.Marshal(putTraceSegmentsRequest{TraceSegmentDocuments: []string{testSegment}})
+	require.NoError(t, err)
+
+	rec = httptest.NewRecorder()
+	r.server.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/TraceSegments", strings.NewReader(string(body))))
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	require.Equal(t, http.StatusInternalServerError, httpStatusCode(errors.New("unknown")))
+}
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, createDefaultConfig().Validate())
+	require.NoError(t, (&Config{HTTP: HTTPConfig{Endpoint: ":2001"}}).Validate())
+	require.Error(t, (&Config{}).Validate())
+	require.Error(t, (&Config{Endpoint: "no-port"}).Validate())
+	require.Error(t, (&Config{HTTP: HTTPConfig{Endpoint: "no-port"}}).Validate())
+}
+
+func newTestReceiver(t *testing.T, cfg *Config, next consumer.Traces) *xrayReceiver {
+	r, err := newReceiver(cfg, receivertest.NewNopSettings(), next)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, r.Shutdown(context.Background()))
+	})
+	return r
+}
@@ -0,0 +1,127 @@
+package awsxray
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// segment is an X-Ray segment or subsegment document.
+// https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html
+type segment struct {
+	Name       string       `json:"name"`
+	ID         string       `json:"id"`
+	TraceID    string       `json:"trace_id"`
+	ParentID   string       `json:"parent_id"`
+	StartTime  float64      `json:"start_time"`
+	EndTime    float64      `json:"end_time"`
+	InProgress bool         `json:"in_progress"`
+	Type       string       `json:"type"`
+	Namespace  string       `json:"namespace"`
+	Origin     string       `json:"origin"`
+	User       string       `json:"user"`
+	Error      bool         `json:"error"`
+	Fault      bool         `json:"fault"`
+	Throttle   bool         `json:"throttle"`
+	HTTP       *httpData    `json:"http"`
+	SQL        *sqlData     `json:"sql"`
+	Service    *serviceData `json:"service"`
+
+	AWS         map[string]any            `json:"aws"`
+	Annotations map[string]any            `json:"annotations"`
+	Metadata    map[string]map[string]any `json:"metadata"`
+	// Cause is either an exception ID or the cause object
+	Cause       json.RawMessage `json:"cause"`
+	Subsegments []segment       `json:"subsegments"`
+}
+
+type httpData struct {
+	Request *struct {
+		Method    string `json:"method"`
+		URL       string `json:"url"`
+		UserAgent string `json:"user_agent"`
+		ClientIP  string `json:"client_ip"`
+	} `json:"request"`
+	Response *struct {
+		Status        *int64 `json:"status"`
+		ContentLength any    `json:"content_length"`
+	} `json:"response"`
+}
+
+type sqlData struct {
+	URL            string `json:"url"`
+	DatabaseType   string `json:"database_type"`
+	User           string `json:"user"`
+	SanitizedQuery string `json:"sanitized_query"`
+}
+
+type serviceData struct {
+	Version string `json:"version"`
+}
+
+type cause struct {
+	Exceptions []exception `json:"exceptions"`
+}
+
+type exception struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Stack   []struct {
+		Path  string `json:"path"`
+		Line  int    `json:"line"`
+		Label string `json:"label"`
+	} `json:"stack"`
+}
+
+// parseSegment parses and validates a segment document.
+func parseSegment(doc []byte) (*segment, error) {
+	var seg segment
+	if err := json.Unmarshal(doc, &seg); err != nil {
+		return nil, fmt.Errorf("invalid segment document: %w", err)
+	}
+	if seg.TraceID == "" {
+		return nil, errors.New("invalid segment document: trace_id is missing")
+	}
+	if seg.Type == "subsegment" && seg.ParentID == "" {
+		return nil, errors.New("invalid segment document: parent_id of subsegment is missing")
+	}
+	if err := seg.validate(); err != nil {
+		return nil, err
+	}
+	return &seg, nil
+}
+
+func (s *segment) validate() error {
+	if s.Name == "" {
+		return errors.New("invalid segment document: name is missing")
+	}
+	if s.ID == "" {
+		return fmt.Errorf("invalid segment document %s: id is missing", s.Name)
+	}
+	if s.StartTime == 0 {
+		return fmt.Errorf("invalid segment document %s: start_time is missing", s.ID)
+	}
+	if s.EndTime == 0 && !s.InProgress {
+		return fmt.Errorf("invalid segment document %s: end_time or in_progress must be set", s.ID)
+	}
+
+	for i := range s.Subsegments {
+		if err := s.Subsegments[i].validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exceptions returns the exceptions of the cause. Causes that reference an exception by ID return none.
+func (s *segment) exceptions() []exception {
+	if len(s.Cause) == 0 || s.Cause[0] != '{' {
+		return nil
+	}
+
+	var c cause
+	if err := json.Unmarshal(s.Cause, &c); err != nil {
+		return nil
+	}
+	return c.Exceptions
+}
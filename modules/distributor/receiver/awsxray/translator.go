@@ -0,0 +1,276 @@
+package awsxray
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	attrMetadataPrefix = "aws.xray.metadata."
+	attrThrottle       = "aws.xray.throttle"
+
+	namespaceAWS    = "aws"
+	namespaceRemote = "remote"
+)
+
+// cloudPlatforms maps the origin of a segment to the cloud.platform resource attribute.
+var cloudPlatforms = map[string]string{
+	"AWS::EC2::Instance":                 "aws_ec2",
+	"AWS::ECS::Container":                "aws_ecs",
+	"AWS::EKS::Container":                "aws_eks",
+	"AWS::ElasticBeanstalk::Environment": "aws_elastic_beanstalk",
+	"AWS::Lambda::Function":              "aws_lambda",
+	"AWS::AppRunner::Service":            "aws_app_runner",
+}
+
+// appendSegment converts the segment and its subsegments to spans and appends them to td. Segments that are still in
+// progress are skipped, the X-Ray SDKs send them again once they are complete. It returns the number of spans
+// appended.
+func appendSegment(td ptrace.Traces, seg *segment) (int, error) {
+	if seg.InProgress {
+		return 0, nil
+	}
+
+	traceID, err := parseTraceID(seg.TraceID)
+	if err != nil {
+		return 0, err
+	}
+
+	// convert to a separate batch, nothing is appended if the segment is invalid
+	batch := ptrace.NewTraces()
+	rs := batch.ResourceSpans().AppendEmpty()
+	// independent subsegments are sent separately from their segment and don't describe the service
+	if seg.Type != "subsegment" {
+		resourceAttributes(rs.Resource().Attributes(), seg)
+	}
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+
+	var parentID pcommon.SpanID
+	if seg.ParentID != "" {
+		if parentID, err = parseSpanID(seg.ParentID); err != nil {
+			return 0, err
+		}
+	}
+
+	kind := ptrace.SpanKindServer
+	if seg.Type == "subsegment" {
+		kind = subsegmentKind(seg)
+	}
+	if err := appendSpan(spans, seg, traceID, parentID, kind); err != nil {
+		return 0, err
+	}
+
+	n := spans.Len()
+	batch.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+	return n, nil
+}
+
+func appendSpan(spans ptrace.SpanSlice, seg *segment, traceID pcommon.TraceID, parentID pcommon.SpanID, kind ptrace.SpanKind) error {
+	if seg.InProgress {
+		return nil
+	}
+
+	spanID, err := parseSpanID(seg.ID)
+	if err != nil {
+		return err
+	}
+
+	span := spans.AppendEmpty()
+	span.SetTraceID(traceID)
+	span.SetSpanID(spanID)
+	span.SetParentSpanID(parentID)
+	span.SetName(seg.Name)
+	span.SetKind(kind)
+	span.SetStartTimestamp(toTimestamp(seg.StartTime))
+	span.SetEndTimestamp(toTimestamp(seg.EndTime))
+
+	spanAttributes(span.Attributes(), seg)
+
+	exceptions := seg.exceptions()
+	for _, e := range exceptions {
+		event := span.Events().AppendEmpty()
+		event.SetName("exception")
+		event.SetTimestamp(span.EndTimestamp())
+		putString(event.Attributes(), "exception.type", e.Type)
+		putString(event.Attributes(), "exception.message", e.Message)
+		putString(event.Attributes(), "exception.stacktrace", stacktrace(e))
+	}
+
+	if seg.Fault || seg.Error {
+		span.Status().SetCode(ptrace.StatusCodeError)
+		if len(exceptions) > 0 {
+			span.Status().SetMessage(exceptions[0].Message)
+		}
+	}
+
+	for i := range seg.Subsegments {
+		if err := appendSpan(spans, &seg.Subsegments[i], traceID, spanID, subsegmentKind(&seg.Subsegments[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subsegmentKind returns the kind of a subsegment. Subsegments of calls to AWS services and other remote services
+// are clients.
+func subsegmentKind(seg *segment) ptrace.SpanKind {
+	if seg.Namespace == namespaceAWS || seg.Namespace == namespaceRemote {
+		return ptrace.SpanKindClient
+	}
+	return ptrace.SpanKindInternal
+}
+
+func resourceAttributes(attrs pcommon.Map, seg *segment) {
+	attrs.PutStr("service.name", seg.Name)
+	if seg.Service != nil {
+		putString(attrs, "service.version", seg.Service.Version)
+	}
+
+	if seg.Origin != "" || seg.AWS != nil {
+		attrs.PutStr("cloud.provider", "aws")
+	}
+	if platform, ok := cloudPlatforms[seg.Origin]; ok {
+		attrs.PutStr("cloud.platform", platform)
+	}
+	if accountID, ok := seg.AWS["account_id"].(string); ok {
+		attrs.PutStr("cloud.account.id", accountID)
+	}
+}
+
+func spanAttributes(attrs pcommon.Map, seg *segment) {
+	putString(attrs, "enduser.id", seg.User)
+	if seg.Throttle {
+		attrs.PutBool(attrThrottle, true)
+	}
+
+	if h := seg.HTTP; h != nil {
+		if req := h.Request; req != nil {
+			putString(attrs, "http.method", req.Method)
+			putString(attrs, "http.url", req.URL)
+			putString(attrs, "http.user_agent", req.UserAgent)
+			putString(attrs, "http.client_ip", req.ClientIP)
+		}
+		if resp := h.Response; resp != nil {
+			if resp.Status != nil {
+				attrs.PutInt("http.status_code", *resp.Status)
+			}
+			// the SDKs send the content length either as number or string
+			switch v := resp.ContentLength.(type) {
+			case float64:
+				attrs.PutInt("http.response_content_length", int64(v))
+			case string:
+				if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+					attrs.PutInt("http.response_content_length", n)
+				}
+			}
+		}
+	}
+
+	if s := seg.SQL; s != nil {
+		putString(attrs, "db.connection_string", s.URL)
+		putString(attrs, "db.system", s.DatabaseType)
+		putString(attrs, "db.user", s.User)
+		putString(attrs, "db.statement", s.SanitizedQuery)
+	}
+
+	// nested aws fields like ec2.instance_id are flattened into aws.ec2.instance_id
+	putFlattened(attrs, namespaceAWS, seg.AWS)
+
+	for k, v := range seg.Annotations {
+		putValue(attrs, k, v)
+	}
+
+	for namespace, metadata := range seg.Metadata {
+		b, err := json.Marshal(metadata)
+		if err != nil {
+			continue
+		}
+		attrs.PutStr(attrMetadataPrefix+namespace, string(b))
+	}
+}
+
+func putFlattened(attrs pcommon.Map, prefix string, m map[string]any) {
+	for k, v := range m {
+		key := prefix + "." + k
+		if nested, ok := v.(map[string]any); ok {
+			putFlattened(attrs, key, nested)
+			continue
+		}
+		putValue(attrs, key, v)
+	}
+}
+
+func putValue(attrs pcommon.Map, key string, v any) {
+	switch v := v.(type) {
+	case string:
+		attrs.PutStr(key, v)
+	case bool:
+		attrs.PutBool(key, v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			attrs.PutInt(key, int64(v))
+		} else {
+			attrs.PutDouble(key, v)
+		}
+	case nil:
+	default:
+		// lists and other values are kept as JSON
+		if b, err := json.Marshal(v); err == nil {
+			attrs.PutStr(key, string(b))
+		}
+	}
+}
+
+func putString(attrs pcommon.Map, key, value string) {
+	if value != "" {
+		attrs.PutStr(key, value)
+	}
+}
+
+func stacktrace(e exception) string {
+	sb := strings.Builder{}
+	for _, frame := range e.Stack {
+		fmt.Fprintf(&sb, "%s (%s:%d)\n", frame.Label, frame.Path, frame.Line)
+	}
+	return sb.String()
+}
+
+// parseTraceID parses an X-Ray trace ID like 1-5759e988-bd862e3fe1be46a994272793. The epoch and the unique part are
+// joined to a 16 byte trace ID.
+func parseTraceID(id string) (pcommon.TraceID, error) {
+	parts := strings.Split(id, "-")
+	if len(parts) != 3 || parts[0] != "1" || len(parts[1]) != 8 || len(parts[2]) != 24 {
+		return pcommon.TraceID{}, fmt.Errorf("invalid trace id %q", id)
+	}
+
+	var traceID pcommon.TraceID
+	if _, err := hex.Decode(traceID[:], []byte(parts[1]+parts[2])); err != nil {
+		return pcommon.TraceID{}, fmt.Errorf("invalid trace id %q: %w", id, err)
+	}
+	return traceID, nil
+}
+
+func parseSpanID(id string) (pcommon.SpanID, error) {
+	var spanID pcommon.SpanID
+	if len(id) != 16 {
+		return spanID, fmt.Errorf("invalid segment id %q", id)
+	}
+	if _, err := hex.Decode(spanID[:], []byte(id)); err != nil {
+		return spanID, fmt.Errorf("invalid segment id %q: %w", id, err)
+	}
+	return spanID, nil
+}
+
+// toTimestamp converts the seconds since the epoch of X-Ray segments to a timestamp.
+func toTimestamp(seconds float64) pcommon.Timestamp {
+	sec, frac := math.Modf(seconds)
+	return pcommon.NewTimestampFromTime(time.Unix(int64(sec), int64(math.Round(frac*1e6))*1e3))
+}
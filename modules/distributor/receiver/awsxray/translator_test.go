@@ -0,0 +1,213 @@
+package awsxray
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const testSegment = `{
+	"name": "checkout",
+	"id": "70de5b6f19ff9a0a",
+	"trace_id": "1-581cf771-a006649127e371903a2de979",
+	"start_time": 1478293361.271,
+	"end_time": 1478293361.449,
+	"origin": "AWS::Lambda::Function",
+	"user": "alice",
+	"fault": true,
+	"service": {"version": "1.2.3"},
+	"http": {
+		"request": {"method": "POST", "url": "https://example.com/checkout", "client_ip": "78.255.233.48"},
+		"response": {"status": 500, "content_length": "42"}
+	},
+	"aws": {"account_id": "123456789012", "lambda": {"function_arn": "arn:aws:lambda:us-east-1:123456789012:function:checkout"}},
+	"annotations": {"customer_tier": "gold", "items": 3, "express": true},
+	"metadata": {"debug": {"cart": {"id": 7}}},
+	"cause": {"exceptions": [{"type": "TimeoutError", "message": "payment timed out", "stack": [{"path": "pay.py", "line": 12, "label": "charge"}]}]},
+	"subsegments": [
+		{
+			"name": "DynamoDB",
+			"id": "53995c3f42cd8ad8",
+			"start_time": 1478293361.3,
+			"end_time": 1478293361.35,
+			"namespace": "aws",
+			"throttle": true,
+			"aws": {"operation": "PutItem", "table_name": "orders"},
+			"subsegments": [
+				{"name": "marshal", "id": "6b0f8e3b9d3a1c2e", "start_time": 1478293361.31, "end_time": 1478293361.32}
+			]
+		},
+		{"name": "pending", "id": "1a2b3c4d5e6f7a8b", "start_time": 1478293361.4, "in_progress": true}
+	]
+}`
+
+func TestAppendSegment(t *testing.T) {
+	seg, err := parseSegment([]byte(testSegment))
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	n, err := appendSegment(td, seg)
+	require.NoError(t, err)
+	// the in progress subsegment is skipped
+	require.Equal(t, 3, n)
+	require.Equal(t, 1, td.ResourceSpans().Len())
+
+	rs := td.ResourceSpans().At(0)
+	require.Equal(t, map[string]any{
+		"service.name":     "checkout",
+		"service.version":  "1.2.3",
+		"cloud.provider":   "aws",
+		"cloud.platform":   "aws_lambda",
+		"cloud.account.id": "123456789012",
+	}, rs.Resource().Attributes().AsRaw())
+
+	spans := rs.ScopeSpans().At(0).Spans()
+	traceID := pcommon.TraceID(mustDecode(t, "581cf771a006649127e371903a2de979"))
+
+	root := spans.At(0)
+	require.Equal(t, "checkout", root.Name())
+	require.Equal(t, traceID, root.TraceID())
+	require.Equal(t, pcommon.SpanID(mustDecode(t, "70de5b6f19ff9a0a")), root.SpanID())
+	require.True(t, root.ParentSpanID().IsEmpty())
+	require.Equal(t, ptrace.SpanKindServer, root.Kind())
+	require.Equal(t, time.Unix(1478293361, 271_000_000).UTC(), root.StartTimestamp().AsTime())
+	require.Equal(t, time.Unix(1478293361, 449_000_000).UTC(), root.EndTimestamp().AsTime())
+	require.Equal(t, ptrace.StatusCodeError, root.Status().Code())
+	require.Equal(t, "payment timed out", root.Status().Message())
+	require.Equal(t, map[string]any{
+		"enduser.id":                   "alice",
+		"http.method":                  "POST",
+		"http.url":                     "https://example.com/checkout",
+		"http.client_ip":               "78.255.233.48",
+		"http.status_code":             int64(500),
+		"http.response_content_length": int64(42),
+		"aws.account_id":               "123456789012",
+		"aws.lambda.function_arn":      "arn:aws:lambda:us-east-1:123456789012:function:checkout",
+		"customer_tier":                "gold",
+		"items":                        int64(3),
+		"express":                      true,
+		"aws.xray.metadata.debug":      `{"cart":{"id":7}}`,
+	}, root.Attributes().AsRaw())
+
+	require.Equal(t, 1, root.Events().Len())
+	require.Equal(t, "exception", root.Events().At(0).Name())
+	require.Equal(t, map[string]any{
+		"exception.type":       "TimeoutError",
+		"exception.message":    "payment timed out",
+		"exception.stacktrace": "charge (pay.py:12)\n",
+	}, root.Events().At(0).Attributes().AsRaw())
+
+	dynamo := spans.At(1)
+	require.Equal(t, "DynamoDB", dynamo.Name())
+	require.Equal(t, traceID, dynamo.TraceID())
+	require.Equal(t, root.SpanID(), dynamo.ParentSpanID())
+	require.Equal(t, ptrace.SpanKindClient, dynamo.Kind())
+	require.Equal(t, ptrace.StatusCodeUnset, dynamo.Status().Code())
+	require.Equal(t, map[string]any{
+		"aws.xray.throttle": true,
+		"aws.operation":     "PutItem",
+		"aws.table_name":    "orders",
+	}, dynamo.Attributes().AsRaw())
+
+	marshal := spans.At(2)
+	require.Equal(t, "marshal", marshal.Name())
+	require.Equal(t, dynamo.SpanID(), marshal.ParentSpanID())
+	require.Equal(t, ptrace.SpanKindInternal, marshal.Kind())
+}
+
+func TestAppendIndependentSubsegment(t *testing.T) {
+	seg, err := parseSegment([]byte(`{
+		"name": "downstream",
+		"id": "53995c3f42cd8ad8",
+		"trace_id": "1-581cf771-a006649127e371903a2de979",
+		"parent_id": "70de5b6f19ff9a0a",
+		"type": "subsegment",
+		"namespace": "remote",
+		"start_time": 1478293361.3,
+		"end_time": 1478293361.35
+	}`))
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	n, err := appendSegment(td, seg)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	// the subsegment doesn't describe the service
+	rs := td.ResourceSpans().At(0)
+	require.Equal(t, 0, rs.Resource().Attributes().Len())
+
+	span := rs.ScopeSpans().At(0).Spans().At(0)
+	require.Equal(t, pcommon.SpanID(mustDecode(t, "70de5b6f19ff9a0a")), span.ParentSpanID())
+	require.Equal(t, ptrace.SpanKindClient, span.Kind())
+}
+
+func TestAppendSegmentInvalid(t *testing.T) {
+	tcs := []struct {
+		name string
+		doc  string
+	}{
+		{
+			name: "not json",
+			doc:  `{"name":`,
+		},
+		{
+			name: "missing trace id",
+			doc:  `{"name": "a", "id": "70de5b6f19ff9a0a", "start_time": 1, "end_time": 2}`,
+		},
+		{
+			name: "missing end time",
+			doc:  `{"name": "a", "id": "70de5b6f19ff9a0a", "trace_id": "1-581cf771-a006649127e371903a2de979", "start_time": 1}`,
+		},
+		{
+			name: "subsegment without parent",
+			doc:  `{"name": "a", "id": "70de5b6f19ff9a0a", "trace_id": "1-581cf771-a006649127e371903a2de979", "type": "subsegment", "start_time": 1, "end_time": 2}`,
+		},
+		{
+			name: "invalid subsegment",
+			doc:  `{"name": "a", "id": "70de5b6f19ff9a0a", "trace_id": "1-581cf771-a006649127e371903a2de979", "start_time": 1, "end_time": 2, "subsegments": [{"name": "b"}]}`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseSegment([]byte(tc.doc))
+			require.Error(t, err)
+		})
+	}
+
+	// ids are validated during conversion. nothing is appended for an invalid segment
+	for _, doc := range []string{
+		`{"name": "a", "id": "70de5b6f19ff9a0a", "trace_id": "581cf771a006649127e371903a2de979", "start_time": 1, "end_time": 2}`,
+		`{"name": "a", "id": "70de5b6f19ff9a0a", "trace_id": "1-581cf771-a006649127e371903a2de979", "start_time": 1, "end_time": 2, "subsegments": [{"name": "b", "id": "xyz", "start_time": 1, "end_time": 2}]}`,
+	} {
+		seg, err := parseSegment([]byte(doc))
+		require.NoError(t, err)
+
+		td := ptrace.NewTraces()
+		_, err = appendSegment(td, seg)
+		require.Error(t, err)
+		require.Equal(t, 0, td.SpanCount())
+	}
+}
+
+func TestAppendSegmentInProgress(t *testing.T) {
+	seg, err := parseSegment([]byte(`{"name": "a", "id": "70de5b6f19ff9a0a", "trace_id": "1-581cf771-a006649127e371903a2de979", "start_time": 1, "in_progress": true}`))
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	n, err := appendSegment(td, seg)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+	require.Equal(t, 0, td.ResourceSpans().Len())
+}
+
+func mustDecode(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,295 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/backoff"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/pkg/ingest"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	KafkaEncodingOTLPProto = "otlp_proto"
+	KafkaEncodingOTLPJSON  = "otlp_json"
+
+	kafkaReceiverServiceName = "distributor-kafka-receiver"
+	kafkaCommitTimeout       = 10 * time.Second
+)
+
+var (
+	metricKafkaRecords = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_kafka_receiver_records_total",
+		Help:      "The total number of records consumed by the Kafka receiver by status.",
+	}, []string{"status"})
+	metricKafkaCommitFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "distributor_kafka_receiver_commit_failures_total",
+		Help:      "The total number of failed offset commits of the Kafka receiver.",
+	})
+)
+
+// KafkaReceiverConfig configures the Kafka receiver. The receiver consumes OTLP encoded traces from a Kafka topic
+// as a member of a consumer group and commits the offset of a partition only after its records have been written
+// to the ingesters.
+type KafkaReceiverConfig struct {
+	Enabled bool               `yaml:"enabled"`
+	Kafka   ingest.KafkaConfig `yaml:"kafka"`
+
+	// Encoding of the records, either otlp_proto or otlp_json.
+	Encoding string `yaml:"encoding"`
+	// TenantHeader is the record header that carries the tenant ID. Records without the header are written to the
+	// default tenant.
+	TenantHeader  string `yaml:"tenant_header"`
+	DefaultTenant string `yaml:"default_tenant"`
+
+	// Backoff before records of a partition that failed to be written are consumed again.
+	MinBackoff time.Duration `yaml:"min_backoff"`
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+}
+
+// RegisterFlagsAndApplyDefaults registers flags and applies defaults
+func (cfg *KafkaReceiverConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	cfg.Kafka.RegisterFlagsWithPrefix(util.PrefixConfig(prefix, "kafka"), f)
+	cfg.Kafka.ConsumerGroup = "tempo-distributor"
+	// the topic is owned by the producers
+	cfg.Kafka.AutoCreateTopicEnabled = false
+
+	f.BoolVar(&cfg.Enabled, util.PrefixConfig(prefix, "enabled"), false, "Enable to consume OTLP traces from a Kafka topic.")
+	f.StringVar(&cfg.Encoding, util.PrefixConfig(prefix, "encoding"), KafkaEncodingOTLPProto, "Encoding of the records. Either otlp_proto or otlp_json.")
+	f.StringVar(&cfg.TenantHeader, util.PrefixConfig(prefix, "tenant-header"), user.OrgIDHeaderName, "Record header that carries the tenant ID.")
+	f.StringVar(&cfg.DefaultTenant, util.PrefixConfig(prefix, "default-tenant"), util.FakeTenantID, "Tenant ID of records without the tenant header.")
+	f.DurationVar(&cfg.MinBackoff, util.PrefixConfig(prefix, "min-backoff"), 100*time.Millisecond, "Minimum backoff before records that failed to be written are consumed again.")
+	f.DurationVar(&cfg.MaxBackoff, util.PrefixConfig(prefix, "max-backoff"), 10*time.Second, "Maximum backoff before records that failed to be written are consumed again.")
+}
+
+func (cfg *KafkaReceiverConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if err := cfg.Kafka.Validate(); err != nil {
+		return fmt.Errorf("invalid kafka receiver config: %w", err)
+	}
+	if cfg.Kafka.ConsumerGroup == "" {
+		return errors.New("kafka receiver requires a consumer group")
+	}
+	if _, err := kafkaUnmarshaler(cfg.Encoding); err != nil {
+		return err
+	}
+	if cfg.DefaultTenant == "" && cfg.TenantHeader == "" {
+		return errors.New("kafka receiver requires a tenant header or a default tenant")
+	}
+
+	return nil
+}
+
+func kafkaUnmarshaler(encoding string) (ptrace.Unmarshaler, error) {
+	switch encoding {
+	case KafkaEncodingOTLPProto:
+		return &ptrace.ProtoUnmarshaler{}, nil
+	case KafkaEncodingOTLPJSON:
+		return &ptrace.JSONUnmarshaler{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported kafka receiver encoding %q", encoding)
+	}
+}
+
+type kafkaReceiver struct {
+	services.Service
+
+	cfg         KafkaReceiverConfig
+	next        consumer.Traces
+	unmarshaler ptrace.Unmarshaler
+	logger      log.Logger
+	reg         prometheus.Registerer
+
+	client *kgo.Client
+}
+
+// NewKafkaReceiver returns a service that consumes traces from Kafka and pushes them through the middleware. The
+// partitions of the topic are balanced between the distributors in the consumer group. Records of a partition are
+// pushed in order and its offset is committed once they have been written successfully. A record that fails with a
+// retryable error is consumed again after a backoff, so no record is lost if the ingesters are unavailable.
+//
+// A record that was written but whose offset wasn't committed, e.g. because the distributor crashed, is written
+// again after the partition has been reassigned. The duplicated spans are deduplicated when the trace is combined.
+func NewKafkaReceiver(cfg KafkaReceiverConfig, pusher TracesPusher, middleware Middleware, logger log.Logger, reg prometheus.Registerer) (services.Service, error) {
+	unmarshaler, err := kafkaUnmarshaler(cfg.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &kafkaReceiver{
+		cfg: cfg,
+		next: middleware.Wrap(ConsumeTracesFunc(func(ctx context.Context, td ptrace.Traces) error {
+			_, err := pusher.PushTraces(ctx, td)
+			return err
+		})),
+		unmarshaler: unmarshaler,
+		logger:      log.With(logger, "component", kafkaReceiverServiceName),
+		reg:         reg,
+	}
+	r.Service = services.NewBasicService(r.starting, r.running, r.stopping)
+
+	return r, nil
+}
+
+func (r *kafkaReceiver) starting(context.Context) error {
+	var err error
+	r.client, err = ingest.NewReaderClient(
+		r.cfg.Kafka,
+		ingest.NewReaderClientMetrics(kafkaReceiverServiceName, r.reg),
+		r.logger,
+		kgo.ConsumerGroup(r.cfg.Kafka.ConsumerGroup),
+		kgo.ConsumeTopics(r.cfg.Kafka.Topic),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		// offsets are committed after the records have been written
+		kgo.DisableAutoCommit(),
+		// partitions aren't revoked while their records are written and committed
+		kgo.BlockRebalanceOnPoll(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create kafka reader client: %w", err)
+	}
+
+	return nil
+}
+
+func (r *kafkaReceiver) running(ctx context.Context) error {
+	boff := backoff.New(ctx, backoff.Config{
+		MinBackoff: r.cfg.MinBackoff,
+		MaxBackoff: r.cfg.MaxBackoff,
+	})
+
+	for ctx.Err() == nil {
+		fetches := r.client.PollFetches(ctx)
+		if fetches.IsClientClosed() || ctx.Err() != nil {
+			break
+		}
+
+		fetches.EachError(func(topic string, partition int32, err error) {
+			level.Error(r.logger).Log("msg", "failed to fetch records", "topic", topic, "partition", partition, "err", err)
+		})
+
+		// partitions are consumed independently, a partition that fails to be written doesn't hold back the others
+		rewind := map[string]map[int32]kgo.EpochOffset{}
+		fetches.EachPartition(func(p kgo.FetchTopicPartition) {
+			if failed := r.consumePartition(ctx, p); failed != nil {
+				if rewind[p.Topic] == nil {
+					rewind[p.Topic] = map[int32]kgo.EpochOffset{}
+				}
+				rewind[p.Topic][p.Partition] = kgo.EpochOffset{Epoch: failed.LeaderEpoch, Offset: failed.Offset}
+			}
+		})
+
+		// failed records are consumed again by the next poll
+		r.client.SetOffsets(rewind)
+		r.client.AllowRebalance()
+
+		if len(rewind) > 0 {
+			boff.Wait()
+		} else {
+			boff.Reset()
+		}
+	}
+
+	return nil
+}
+
+func (r *kafkaReceiver) stopping(error) error {
+	if r.client != nil {
+		r.client.Close()
+	}
+	return nil
+}
+
+// consumePartition writes the records of the partition in order and commits the offset of the last written record.
+// It returns the first record that failed with a retryable error.
+func (r *kafkaReceiver) consumePartition(ctx context.Context, p kgo.FetchTopicPartition) *kgo.Record {
+	var (
+		last   *kgo.Record
+		failed *kgo.Record
+	)
+	for _, rec := range p.Records {
+		if err := r.consume(ctx, rec); err != nil {
+			level.Warn(r.logger).Log("msg", "failed to write record, retrying", "topic", rec.Topic, "partition", rec.Partition, "offset", rec.Offset, "err", err)
+			failed = rec
+			break
+		}
+		last = rec
+	}
+
+	if last != nil {
+		// commit even if the receiver is stopping, the records have been written
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), kafkaCommitTimeout)
+		defer cancel()
+
+		if err := r.client.CommitRecords(ctx, last); err != nil {
+			level.Error(r.logger).Log("msg", "failed to commit offset", "topic", last.Topic, "partition", last.Partition, "offset", last.Offset, "err", err)
+			metricKafkaCommitFailures.Inc()
+		}
+	}
+
+	return failed
+}
+
+// consume pushes the traces of the record. Records that can't be written at all are dropped, an error is only
+// returned if writing the record should be retried.
+func (r *kafkaReceiver) consume(ctx context.Context, rec *kgo.Record) error {
+	traces, err := r.unmarshaler.UnmarshalTraces(rec.Value)
+	if err != nil {
+		level.Error(r.logger).Log("msg", "dropping record that failed to decode", "topic", rec.Topic, "partition", rec.Partition, "offset", rec.Offset, "err", err)
+		metricKafkaRecords.WithLabelValues("invalid").Inc()
+		return nil
+	}
+
+	tenantID := r.cfg.DefaultTenant
+	for _, h := range rec.Headers {
+		if h.Key == r.cfg.TenantHeader {
+			tenantID = string(h.Value)
+		}
+	}
+	if tenantID == "" {
+		level.Error(r.logger).Log("msg", "dropping record without tenant", "topic", rec.Topic, "partition", rec.Partition, "offset", rec.Offset)
+		metricKafkaRecords.WithLabelValues("invalid").Inc()
+		return nil
+	}
+
+	// the tenant is passed like the tenant of an HTTP request to the receivers
+	ctx = client.NewContext(ctx, client.Info{
+		Metadata: client.NewMetadata(map[string][]string{user.OrgIDHeaderName: {tenantID}}),
+	})
+
+	err = r.next.ConsumeTraces(ctx, traces)
+	if err == nil {
+		metricKafkaRecords.WithLabelValues("written").Inc()
+		return nil
+	}
+
+	if status.Code(err) == codes.InvalidArgument {
+		level.Error(r.logger).Log("msg", "dropping record that was rejected", "topic", rec.Topic, "partition", rec.Partition, "offset", rec.Offset, "tenant", tenantID, "err", err)
+		metricKafkaRecords.WithLabelValues("rejected").Inc()
+		return nil
+	}
+
+	metricKafkaRecords.WithLabelValues("failed").Inc()
+	return err
+}
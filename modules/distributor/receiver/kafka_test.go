@@ -0,0 +1,162 @@
+package receiver
+
+import (
+	"context"
+	"flag"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/pkg/ingest/testkafka"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestKafkaReceiver(t *testing.T) {
+	const (
+		topic = "traces"
+		group = "tempo-distributor"
+	)
+
+	_, address := testkafka.CreateCluster(t, 1, topic)
+
+	cfg := KafkaReceiverConfig{}
+	cfg.RegisterFlagsAndApplyDefaults("", flag.NewFlagSet("", flag.PanicOnError))
+	cfg.Enabled = true
+	cfg.Kafka.Address = address
+	cfg.Kafka.Topic = topic
+	cfg.Kafka.ConsumerGroup = group
+	cfg.MinBackoff = 10 * time.Millisecond
+	cfg.MaxBackoff = 10 * time.Millisecond
+	require.NoError(t, cfg.Validate())
+
+	producer, err := kgo.NewClient(
+		kgo.SeedBrokers(address),
+		kgo.DefaultProduceTopic(topic),
+		kgo.RecordPartitioner(kgo.ManualPartitioner()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(producer.Close)
+
+	// every other record carries a tenant, the others are written to the default tenant
+	expected := map[string]string{}
+	for i := 0; i < 10; i++ {
+		id := test.ValidTraceID(nil)
+		data, err := test.MakeTrace(1, id).Marshal()
+		require.NoError(t, err)
+
+		rec := &kgo.Record{Value: data}
+		tenant := util.FakeTenantID
+		if i%2 == 0 {
+			tenant = "test"
+			rec.Headers = []kgo.RecordHeader{{Key: user.OrgIDHeaderName, Value: []byte(tenant)}}
+		}
+		require.NoError(t, producer.ProduceSync(context.Background(), rec).FirstErr())
+		expected[util.TraceIDToHexString(id)] = tenant
+	}
+	// records that can't be decoded are skipped
+	require.NoError(t, producer.ProduceSync(context.Background(), &kgo.Record{Value: []byte("invalid")}).FirstErr())
+
+	// the fake cluster only assigns partitions of the group with a committed offset
+	adm := kadm.NewClient(producer)
+	offsets := make(kadm.Offsets)
+	offsets.Add(kadm.Offset{Topic: topic, Partition: 0, At: 0})
+	require.NoError(t, adm.CommitAllOffsets(context.Background(), group, offsets))
+
+	// the first pushes fail and are retried
+	pusher := &failingPusher{failures: 3, pushed: map[string]string{}}
+
+	r, err := NewKafkaReceiver(cfg, pusher, MultiTenancyMiddleware(), log.NewNopLogger(), prometheus.NewRegistry())
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), r))
+	t.Cleanup(func() {
+		require.NoError(t, services.StopAndAwaitTerminated(context.Background(), r))
+	})
+
+	require.Eventually(t, func() bool {
+		return len(pusher.getPushed()) == len(expected)
+	}, 30*time.Second, 100*time.Millisecond)
+	require.Equal(t, expected, pusher.getPushed())
+
+	// the offsets are committed after the records have been written
+	require.Eventually(t, func() bool {
+		offsets, err := adm.FetchOffsets(context.Background(), group)
+		if err != nil {
+			return false
+		}
+		o, ok := offsets.Lookup(topic, 0)
+		return ok && o.At == 11
+	}, 30*time.Second, 100*time.Millisecond)
+}
+
+func TestKafkaReceiverConfigValidate(t *testing.T) {
+	cfg := KafkaReceiverConfig{}
+	cfg.RegisterFlagsAndApplyDefaults("", flag.NewFlagSet("", flag.PanicOnError))
+	require.NoError(t, cfg.Validate())
+
+	cfg.Enabled = true
+	cfg.Kafka.Topic = "traces"
+	require.NoError(t, cfg.Validate())
+
+	cfg.Encoding = "jaeger_proto"
+	require.EqualError(t, cfg.Validate(), `unsupported kafka receiver encoding "jaeger_proto"`)
+}
+
+type failingPusher struct {
+	mtx      sync.Mutex
+	failures int
+	// trace id to tenant
+	pushed map[string]string
+}
+
+func (p *failingPusher) PushTraces(ctx context.Context, traces ptrace.Traces) (*tempopb.PushResponse, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.failures > 0 {
+		p.failures--
+		return nil, status.Error(codes.Unavailable, "ingesters unavailable")
+	}
+
+	tenantID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rss := traces.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		sss := rss.At(i).ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				traceID := spans.At(k).TraceID()
+				p.pushed[util.TraceIDToHexString(traceID[:])] = tenantID
+			}
+		}
+	}
+
+	return &tempopb.PushResponse{}, nil
+}
+
+func (p *failingPusher) getPushed() map[string]string {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	pushed := make(map[string]string, len(p.pushed))
+	for k, v := range p.pushed {
+		pushed[k] = v
+	}
+	return pushed
+}
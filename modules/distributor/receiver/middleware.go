@@ -7,6 +7,7 @@ import (
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/grpc/metadata"
 
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
@@ -80,3 +81,26 @@ func (m *multiTenancyMiddleware) Wrap(next consumer.Traces) consumer.Traces {
 		return next.ConsumeTraces(ctx, td)
 	})
 }
+
+type defaultTenantMiddleware struct {
+	tenantID string
+}
+
+// DefaultTenantMiddleware assigns the tenant ID to requests that don't carry a tenant ID themselves. It has to wrap
+// the multi-tenancy middleware, which extracts the tenant ID from the request.
+func DefaultTenantMiddleware(tenantID string) Middleware {
+	return &defaultTenantMiddleware{tenantID: tenantID}
+}
+
+func (m *defaultTenantMiddleware) Wrap(next consumer.Traces) consumer.Traces {
+	return ConsumeTracesFunc(func(ctx context.Context, td ptrace.Traces) error {
+		if len(metadata.ValueFromIncomingContext(ctx, user.OrgIDHeaderName)) == 0 &&
+			len(client.FromContext(ctx).Metadata.Get(user.OrgIDHeaderName)) == 0 {
+			md, _ := metadata.FromIncomingContext(ctx)
+			md = md.Copy()
+			md.Set(user.OrgIDHeaderName, m.tenantID)
+			ctx = metadata.NewIncomingContext(ctx, md)
+		}
+		return next.ConsumeTraces(ctx, td)
+	})
+}
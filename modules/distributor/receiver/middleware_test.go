@@ -97,3 +97,39 @@ func TestMultiTenancyMiddleware(t *testing.T) {
 		require.EqualError(t, m.Wrap(consumer).ConsumeTraces(ctx, ptrace.Traces{}), "no org id")
 	})
 }
+
+func TestDefaultTenantMiddleware(t *testing.T) {
+	m := MultiTenancyMiddleware()
+	d := DefaultTenantMiddleware("default")
+
+	assertTenant := func(expected string) consumer.Traces {
+		return newAssertingConsumer(t, func(t *testing.T, ctx context.Context) {
+			orgID, err := user.ExtractOrgID(ctx)
+			require.NoError(t, err)
+			require.Equal(t, expected, orgID)
+		})
+	}
+
+	t.Run("injects default org id", func(t *testing.T) {
+		require.NoError(t, d.Wrap(m.Wrap(assertTenant("default"))).ConsumeTraces(context.Background(), ptrace.Traces{}))
+	})
+
+	t.Run("keeps grpc org id", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("X-Scope-OrgID", "test-tenant-id", "other", "value"),
+		)
+		require.NoError(t, d.Wrap(m.Wrap(assertTenant("test-tenant-id"))).ConsumeTraces(ctx, ptrace.Traces{}))
+	})
+
+	t.Run("keeps http org id", func(t *testing.T) {
+		info := client.Info{
+			Metadata: client.NewMetadata(map[string][]string{
+				"x-scope-OrgID": {"test-tenant-id"},
+			}),
+		}
+
+		ctx := client.NewContext(context.Background(), info)
+		require.NoError(t, d.Wrap(m.Wrap(assertTenant("test-tenant-id"))).ConsumeTraces(ctx, ptrace.Traces{}))
+	})
+}
@@ -0,0 +1,86 @@
+package otlphttp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// resourceSpansField is the field number of resource_spans in ExportTraceServiceRequest. It's the same in
+// TracesData, which ptrace unmarshals.
+const resourceSpansField = 1
+
+var errRequestTooLarge = errors.New("request body too large")
+
+var protoUnmarshaler = &ptrace.ProtoUnmarshaler{}
+
+// decodeTracesProto decodes an ExportTraceServiceRequest from r one resource spans at a time, so the encoded
+// request is never held in memory as a whole next to the decoded traces. maxBytes is the maximum size of the
+// request. Larger resource spans are rejected before they are read.
+func decodeTracesProto(r io.Reader, maxBytes int64) (ptrace.Traces, error) {
+	br := bufio.NewReader(r)
+	td := ptrace.NewTraces()
+
+	var buf []byte
+	for {
+		tag, err := binary.ReadUvarint(br)
+		if errors.Is(err, io.EOF) {
+			return td, nil
+		}
+		if err != nil {
+			return td, err
+		}
+
+		num, typ := protowire.DecodeTag(tag)
+		switch typ {
+		case protowire.VarintType:
+			_, err = binary.ReadUvarint(br)
+		case protowire.Fixed32Type:
+			_, err = br.Discard(4)
+		case protowire.Fixed64Type:
+			_, err = br.Discard(8)
+		case protowire.BytesType:
+			var n uint64
+			if n, err = binary.ReadUvarint(br); err != nil {
+				break
+			}
+			if n > uint64(maxBytes) {
+				return td, errRequestTooLarge
+			}
+			if num != resourceSpansField {
+				_, err = br.Discard(int(n))
+				break
+			}
+
+			// the field is unmarshalled as TracesData with a single resource spans
+			buf = protowire.AppendTag(buf[:0], num, typ)
+			buf = protowire.AppendVarint(buf, n)
+			start := len(buf)
+			buf = slices.Grow(buf, int(n))[:start+int(n)]
+			if _, err = io.ReadFull(br, buf[start:]); err != nil {
+				break
+			}
+
+			var rs ptrace.Traces
+			if rs, err = protoUnmarshaler.UnmarshalTraces(buf); err != nil {
+				return td, fmt.Errorf("invalid resource spans: %w", err)
+			}
+			rs.ResourceSpans().MoveAndAppendTo(td.ResourceSpans())
+		default:
+			return td, fmt.Errorf("unsupported wire type %d of field %d", typ, num)
+		}
+
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return td, err
+		}
+	}
+}
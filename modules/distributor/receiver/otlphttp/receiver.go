@@ -0,0 +1,297 @@
+// Package otlphttp serves the HTTP protocol of the OTLP receiver. Unlike the collector's receiver it decodes
+// protobuf requests while they are read and rejects requests larger than max_request_body_size with a 413, so
+// a single large request can't exhaust the memory of a distributor.
+package otlphttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componentstatus"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	transport = "http"
+
+	pbContentType   = "application/x-protobuf"
+	jsonContentType = "application/json"
+
+	// defaultMaxRequestBodySize is the default of confighttp
+	defaultMaxRequestBodySize = 20 * 1024 * 1024
+)
+
+var metricRequestsTooLarge = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "distributor_otlp_http_requests_too_large_total",
+	Help:      "The total number of OTLP/HTTP requests rejected because the body exceeded max_request_body_size.",
+}, []string{"receiver"})
+
+// Receiver receives traces on the OTLP/HTTP traces path. The server is configured by the HTTP protocol of the OTLP
+// receiver config, including TLS, CORS, authentication and decompression.
+type Receiver struct {
+	cfg       *otlpreceiver.HTTPConfig
+	settings  receiver.Settings
+	next      consumer.Traces
+	obsreport *receiverhelper.ObsReport
+
+	server *http.Server
+	wg     sync.WaitGroup
+}
+
+func New(cfg *otlpreceiver.HTTPConfig, set receiver.Settings, next consumer.Traces) (*Receiver, error) {
+	obsreport, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             set.ID,
+		Transport:              transport,
+		ReceiverCreateSettings: set,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Receiver{
+		cfg:       cfg,
+		settings:  set,
+		next:      next,
+		obsreport: obsreport,
+	}, nil
+}
+
+// Start implements component.Component
+func (r *Receiver) Start(ctx context.Context, host component.Host) error {
+	tracesPath := r.cfg.TracesURLPath
+	if tracesPath == "" {
+		tracesPath = "/v1/traces"
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(tracesPath, r.handleTraces)
+
+	var err error
+	if r.server, err = r.cfg.ToServer(ctx, host, r.settings.TelemetrySettings, mux, confighttp.WithErrorHandler(errorHandler)); err != nil {
+		return err
+	}
+
+	r.settings.Logger.Info("Starting HTTP server", zap.String("endpoint", r.cfg.Endpoint))
+	ln, err := r.cfg.ToListener(ctx)
+	if err != nil {
+		return err
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		if err := r.server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			componentstatus.ReportStatus(host, componentstatus.NewFatalErrorEvent(err))
+		}
+	}()
+
+	return nil
+}
+
+// Shutdown implements component.Component
+func (r *Receiver) Shutdown(ctx context.Context) error {
+	var err error
+	if r.server != nil {
+		err = r.server.Shutdown(ctx)
+	}
+	r.wg.Wait()
+	return err
+}
+
+func (r *Receiver) handleTraces(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		writeText(w, http.StatusMethodNotAllowed, fmt.Sprintf("%d method not allowed, supported: [POST]", http.StatusMethodNotAllowed))
+		return
+	}
+
+	contentType := mediaType(req.Header.Get("Content-Type"))
+	if contentType != pbContentType && contentType != jsonContentType {
+		writeText(w, http.StatusUnsupportedMediaType, fmt.Sprintf("%d unsupported media type, supported: [%s, %s]", http.StatusUnsupportedMediaType, jsonContentType, pbContentType))
+		return
+	}
+
+	maxBytes := r.maxRequestBodySize()
+	// requests that announce their size are rejected before the body is read
+	if req.ContentLength > maxBytes {
+		r.writeTooLarge(w, contentType, maxBytes)
+		return
+	}
+
+	td, err := decodeTraces(req.Body, contentType, maxBytes)
+	_ = req.Body.Close()
+	var maxBytesErr *http.MaxBytesError
+	if errors.Is(err, errRequestTooLarge) || errors.As(err, &maxBytesErr) {
+		r.writeTooLarge(w, contentType, maxBytes)
+		return
+	}
+	if err != nil {
+		writeStatus(w, contentType, http.StatusBadRequest, status.New(codes.InvalidArgument, err.Error()))
+		return
+	}
+
+	spans := td.SpanCount()
+	if spans > 0 {
+		ctx := r.obsreport.StartTracesOp(req.Context())
+		err = r.next.ConsumeTraces(ctx, td)
+		r.obsreport.EndTracesOp(ctx, "protobuf", spans, err)
+	}
+	if err != nil {
+		s := statusFromError(err)
+		writeStatus(w, contentType, httpStatusCode(s), s)
+		return
+	}
+
+	resp := ptraceotlp.NewExportResponse()
+	var msg []byte
+	if contentType == jsonContentType {
+		msg, err = resp.MarshalJSON()
+	} else {
+		msg, err = resp.MarshalProto()
+	}
+	if err != nil {
+		writeStatus(w, contentType, http.StatusInternalServerError, status.New(codes.Internal, err.Error()))
+		return
+	}
+	writeResponse(w, contentType, http.StatusOK, msg)
+}
+
+func (r *Receiver) maxRequestBodySize() int64 {
+	if r.cfg.MaxRequestBodySize > 0 {
+		return r.cfg.MaxRequestBodySize
+	}
+	return defaultMaxRequestBodySize
+}
+
+func (r *Receiver) writeTooLarge(w http.ResponseWriter, contentType string, maxBytes int64) {
+	metricRequestsTooLarge.WithLabelValues(r.settings.ID.String()).Inc()
+	writeStatus(w, contentType, http.StatusRequestEntityTooLarge,
+		status.New(codes.InvalidArgument, fmt.Sprintf("request body exceeds the limit of %d bytes", maxBytes)))
+}
+
+// decodeTraces decodes the request body. Protobuf is decoded while it is read, JSON is read as a whole first.
+func decodeTraces(body io.Reader, contentType string, maxBytes int64) (ptrace.Traces, error) {
+	if contentType == pbContentType {
+		return decodeTracesProto(body, maxBytes)
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return ptrace.Traces{}, err
+	}
+	req := ptraceotlp.NewExportRequest()
+	if err := req.UnmarshalJSON(b); err != nil {
+		return ptrace.Traces{}, err
+	}
+	return req.Traces(), nil
+}
+
+// statusFromError returns the status of an error of the distributor. Errors without a status are retryable unless
+// they are permanent, as in the collector's receiver.
+func statusFromError(err error) *status.Status {
+	if s, ok := status.FromError(err); ok {
+		return s
+	}
+	if consumererror.IsPermanent(err) {
+		return status.New(codes.Internal, err.Error())
+	}
+	return status.New(codes.Unavailable, err.Error())
+}
+
+// httpStatusCode maps a status to the HTTP status codes of the OTLP specification, which tells clients if they
+// can retry.
+func httpStatusCode(s *status.Status) int {
+	switch s.Code() {
+	case codes.Canceled, codes.DeadlineExceeded, codes.Aborted, codes.OutOfRange, codes.Unavailable, codes.DataLoss:
+		return http.StatusServiceUnavailable
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unimplemented:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeStatus writes the status as a google.rpc.Status in the encoding of the request, as required by OTLP.
+func writeStatus(w http.ResponseWriter, contentType string, statusCode int, s *status.Status) {
+	var (
+		msg []byte
+		err error
+	)
+	if contentType == jsonContentType {
+		msg, err = protojson.Marshal(s.Proto())
+	} else {
+		msg, err = proto.Marshal(s.Proto())
+	}
+	if err != nil {
+		writeText(w, http.StatusInternalServerError, "failed to marshal error message")
+		return
+	}
+	writeResponse(w, contentType, statusCode, msg)
+}
+
+// errorHandler writes the errors of the handlers wrapped around the receiver by confighttp, for example of
+// unsupported content encodings.
+func errorHandler(w http.ResponseWriter, req *http.Request, errMsg string, statusCode int) {
+	contentType := mediaType(req.Header.Get("Content-Type"))
+	if contentType != pbContentType && contentType != jsonContentType {
+		writeText(w, statusCode, errMsg)
+		return
+	}
+
+	code := codes.Unknown
+	switch statusCode {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	}
+	writeStatus(w, contentType, statusCode, status.New(code, errMsg))
+}
+
+func writeResponse(w http.ResponseWriter, contentType string, statusCode int, msg []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(msg)
+}
+
+func writeText(w http.ResponseWriter, statusCode int, msg string) {
+	writeResponse(w, "text/plain", statusCode, []byte(msg))
+}
+
+func mediaType(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return mt
+}
@@ -0,0 +1,233 @@
+package otlphttp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/collector/receiver/otlpreceiver"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDecodeTracesProto(t *testing.T) {
+	td := testTraces(3, 4)
+	b, err := ptraceotlp.NewExportRequestFromTraces(td).MarshalProto()
+	require.NoError(t, err)
+
+	decoded, err := decodeTracesProto(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+	require.Equal(t, td, decoded)
+
+	// unknown fields are skipped
+	withUnknown := append([]byte{}, b...)
+	withUnknown = append(withUnknown, 0x10, 0x01)       // field 2, varint
+	withUnknown = append(withUnknown, 0x1a, 0x02, 1, 2) // field 3, bytes
+	withUnknown = append(withUnknown, 0x25, 1, 2, 3, 4) // field 4, fixed32
+	decoded, err = decodeTracesProto(bytes.NewReader(withUnknown), int64(len(withUnknown)))
+	require.NoError(t, err)
+	require.Equal(t, td, decoded)
+
+	// truncated requests are rejected
+	_, err = decodeTracesProto(bytes.NewReader(b[:len(b)-1]), int64(len(b)))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// resource spans larger than the request limit are rejected before they are read
+	_, err = decodeTracesProto(bytes.NewReader(b), 10)
+	require.ErrorIs(t, err, errRequestTooLarge)
+
+	decoded, err = decodeTracesProto(bytes.NewReader(nil), 10)
+	require.NoError(t, err)
+	require.Equal(t, 0, decoded.SpanCount())
+}
+
+func TestReceiverTraces(t *testing.T) {
+	td := testTraces(2, 5)
+
+	for _, contentType := range []string{pbContentType, jsonContentType} {
+		t.Run(contentType, func(t *testing.T) {
+			sink := &consumertest.TracesSink{}
+			r := newTestReceiver(t, &otlpreceiver.HTTPConfig{ServerConfig: &confighttp.ServerConfig{}}, sink)
+
+			rec := httptest.NewRecorder()
+			r.handleTraces(rec, newRequest(t, contentType, td))
+
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, contentType, rec.Header().Get("Content-Type"))
+			require.Equal(t, 10, sink.SpanCount())
+		})
+	}
+}
+
+func TestReceiverErrors(t *testing.T) {
+	td := testTraces(1, 1)
+
+	tcs := []struct {
+		name           string
+		next           consumer.Traces
+		req            *http.Request
+		expectedStatus int
+		expectedCode   codes.Code
+	}{
+		{
+			name:           "invalid body",
+			next:           consumertest.NewNop(),
+			req:            httptest.NewRequest(http.MethodPost, "/v1/traces", bytes.NewReader([]byte{0x0a, 0x05, 1})),
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   codes.InvalidArgument,
+		},
+		{
+			name:           "rate limited",
+			next:           consumertest.NewErr(status.Error(codes.ResourceExhausted, "rate limited")),
+			req:            newRequest(t, pbContentType, td),
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCode:   codes.ResourceExhausted,
+		},
+		{
+			name:           "retryable",
+			next:           consumertest.NewErr(fmt.Errorf("unknown")),
+			req:            newRequest(t, pbContentType, td),
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedCode:   codes.Unavailable,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newTestReceiver(t, &otlpreceiver.HTTPConfig{ServerConfig: &confighttp.ServerConfig{}}, tc.next)
+			tc.req.Header.Set("Content-Type", pbContentType)
+
+			rec := httptest.NewRecorder()
+			r.handleTraces(rec, tc.req)
+
+			require.Equal(t, tc.expectedStatus, rec.Code)
+			s := &spb.Status{}
+			require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), s))
+			require.Equal(t, int32(tc.expectedCode), s.Code)
+		})
+	}
+
+	// unsupported methods and content types
+	r := newTestReceiver(t, &otlpreceiver.HTTPConfig{ServerConfig: &confighttp.ServerConfig{}}, consumertest.NewNop())
+	rec := httptest.NewRecorder()
+	r.handleTraces(rec, httptest.NewRequest(http.MethodGet, "/v1/traces", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	rec = httptest.NewRecorder()
+	r.handleTraces(rec, newRequest(t, "text/plain", td))
+	require.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+}
+
+func TestReceiverRequestTooLarge(t *testing.T) {
+	sink := &consumertest.TracesSink{}
+	cfg := &otlpreceiver.HTTPConfig{ServerConfig: &confighttp.ServerConfig{
+		Endpoint:           "127.0.0.1:0",
+		MaxRequestBodySize: 1024,
+	}}
+	r := newTestReceiver(t, cfg, sink)
+
+	td := testTraces(10, 10)
+	b, err := ptraceotlp.NewExportRequestFromTraces(td).MarshalJSON()
+	require.NoError(t, err)
+	require.Greater(t, len(b), 1024)
+
+	// the content length is checked before the body is read
+	rec := httptest.NewRecorder()
+	r.handleTraces(rec, newRequest(t, jsonContentType, td))
+	require.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+	s := &spb.Status{}
+	require.NoError(t, protojson.Unmarshal(rec.Body.Bytes(), s))
+	require.Equal(t, int32(codes.InvalidArgument), s.Code)
+	require.Equal(t, "request body exceeds the limit of 1024 bytes", s.Message)
+
+	// requests without a content length are limited while they are read
+	for _, contentType := range []string{pbContentType, jsonContentType} {
+		req := newRequest(t, contentType, td)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+
+		url := fmt.Sprintf("http://%s/v1/traces", r.cfg.Endpoint)
+		httpReq, err := http.NewRequest(http.MethodPost, url, io.MultiReader(bytes.NewReader(body)))
+		require.NoError(t, err)
+		httpReq.Header.Set("Content-Type", contentType)
+		require.Equal(t, int64(0), httpReq.ContentLength)
+
+		resp, err := http.DefaultClient.Do(httpReq)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode, contentType)
+	}
+
+	require.Equal(t, 0, sink.SpanCount())
+}
+
+func newTestReceiver(t *testing.T, cfg *otlpreceiver.HTTPConfig, next consumer.Traces) *Receiver {
+	r, err := New(cfg, receivertest.NewNopSettings(), next)
+	require.NoError(t, err)
+
+	if cfg.Endpoint != "" {
+		// listen on a free port so the test can send requests to it
+		ln, err := net.Listen("tcp", cfg.Endpoint)
+		require.NoError(t, err)
+		cfg.Endpoint = ln.Addr().String()
+		require.NoError(t, ln.Close())
+
+		require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+		t.Cleanup(func() {
+			require.NoError(t, r.Shutdown(context.Background()))
+		})
+	}
+	return r
+}
+
+func newRequest(t *testing.T, contentType string, td ptrace.Traces) *http.Request {
+	req := ptraceotlp.NewExportRequestFromTraces(td)
+
+	var (
+		b   []byte
+		err error
+	)
+	if contentType == jsonContentType {
+		b, err = req.MarshalJSON()
+	} else {
+		b, err = req.MarshalProto()
+	}
+	require.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/traces", bytes.NewReader(b))
+	r.Header.Set("Content-Type", contentType)
+	return r
+}
+
+func testTraces(resourceSpans, spans int) ptrace.Traces {
+	td := ptrace.NewTraces()
+	for i := 0; i < resourceSpans; i++ {
+		rs := td.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr("service.name", fmt.Sprintf("service-%d", i))
+		ss := rs.ScopeSpans().AppendEmpty()
+		for j := 0; j < spans; j++ {
+			span := ss.Spans().AppendEmpty()
+			span.SetName(fmt.Sprintf("span-%d", j))
+			span.SetTraceID([16]byte{byte(i), byte(j), 1})
+			span.SetSpanID([8]byte{byte(i), byte(j), 1})
+		}
+	}
+	return td
+}
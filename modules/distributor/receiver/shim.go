@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log/level"
@@ -37,6 +38,8 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
 
+	"github.com/grafana/tempo/modules/distributor/receiver/awsxray"
+	"github.com/grafana/tempo/modules/distributor/receiver/otlphttp"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/usagestats"
 	"github.com/grafana/tempo/pkg/util/log"
@@ -62,6 +65,7 @@ var (
 	statReceiverZipkin     = usagestats.NewInt("receiver_enabled_zipkin")
 	statReceiverOpencensus = usagestats.NewInt("receiver_enabled_opencensus")
 	statReceiverKafka      = usagestats.NewInt("receiver_enabled_kafka")
+	statReceiverAWSXRay    = usagestats.NewInt("receiver_enabled_awsxray")
 )
 
 var tracer = otel.Tracer("modules/distributor/receiver")
@@ -143,7 +147,9 @@ func (m *mapProvider) Scheme() string { return "mock" }
 
 func (m *mapProvider) Shutdown(context.Context) error { return nil }
 
-func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Middleware, retryAfterDuration time.Duration, logLevel dslog.Level, reg prometheus.Registerer) (services.Service, error) {
+// New creates the receivers configured in receiverCfg. receiverTenants maps receiver names, like otlp or otlp/internal,
+// to the tenant ID assigned to requests on that receiver that don't carry a tenant ID.
+func New(receiverCfg map[string]interface{}, receiverTenants map[string]string, pusher TracesPusher, middleware Middleware, retryAfterDuration time.Duration, logLevel dslog.Level, reg prometheus.Registerer) (services.Service, error) {
 	shim := &receiversShim{
 		pusher: pusher,
 		logger: log.NewRateLimitedLogger(logsPerSecond, level.Error(log.Logger)),
@@ -164,13 +170,16 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 		opencensusreceiver.NewFactory(),
 		otlpreceiver.NewFactory(),
 		kafkareceiver.NewFactory(),
+		awsxray.NewFactory(),
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	for recv := range receiverCfg {
-		switch recv {
+		// receivers can be named to run multiple instances of the same type, e.g. otlp/internal
+		recvType, _, _ := strings.Cut(recv, "/")
+		switch recvType {
 		case "otlp":
 			statReceiverOtlp.Set(1)
 		case "jaeger":
@@ -181,6 +190,8 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 			statReceiverOpencensus.Set(1)
 		case "kafka":
 			statReceiverKafka.Set(1)
+		case "awsxray":
+			statReceiverAWSXRay.Set(1)
 		}
 	}
 
@@ -189,6 +200,12 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 		receivers = append(receivers, k)
 	}
 
+	for recv := range receiverTenants {
+		if _, ok := receiverCfg[recv]; !ok {
+			return nil, fmt.Errorf("default tenant configured for unknown receiver: %s", recv)
+		}
+	}
+
 	// Define a factory function to create the mock provider
 	mockProviderFactory := confmap.NewProviderFactory(func(confmap.ProviderSettings) confmap.Provider {
 		return &mapProvider{
@@ -246,14 +263,47 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 			return nil, fmt.Errorf("receiver factory not found for type: %s", componentID.Type())
 		}
 
+		// keep the ID of unnamed receivers unchanged, it's used as label of the receiver metrics
+		receiverName := fmt.Sprintf("%s_receiver", componentID.Type().String())
+		if componentID.Name() != "" {
+			receiverName = fmt.Sprintf("%s_%s_receiver", componentID.Type().String(), componentID.Name())
+		}
+
+		next := middleware.Wrap(shim)
+		if tenantID, ok := receiverTenants[componentID.String()]; ok {
+			next = DefaultTenantMiddleware(tenantID).Wrap(next)
+		}
+
+		params := receiver.Settings{
+			ID: component.NewIDWithName(nopType, receiverName),
+			TelemetrySettings: component.TelemetrySettings{
+				Logger:         zapLogger,
+				TracerProvider: traceProvider,
+				MeterProvider:  meterProvider,
+			},
+		}
+
 		// Make sure that the headers are added to context. Required for Authentication.
 		switch componentID.Type().String() {
 		case "otlp":
 			otlpRecvCfg := cfg.(*otlpreceiver.Config)
 
+			// OTLP/HTTP is served by our own receiver, which limits the size of requests while decoding them
 			if otlpRecvCfg.HTTP != nil {
 				otlpRecvCfg.HTTP.IncludeMetadata = true
-				cfg = otlpRecvCfg
+
+				httpReceiver, err := otlphttp.New(otlpRecvCfg.HTTP, params, next)
+				if err != nil {
+					return nil, err
+				}
+				shim.receivers = append(shim.receivers, httpReceiver)
+
+				grpcCfg := *otlpRecvCfg
+				grpcCfg.HTTP = nil
+				cfg = &grpcCfg
+			}
+			if otlpRecvCfg.GRPC == nil {
+				continue
 			}
 
 		case "zipkin":
@@ -272,15 +322,7 @@ func New(receiverCfg map[string]interface{}, pusher TracesPusher, middleware Mid
 			cfg = jaegerRecvCfg
 		}
 
-		params := receiver.Settings{
-			ID: component.NewIDWithName(nopType, fmt.Sprintf("%s_receiver", componentID.Type().String())),
-			TelemetrySettings: component.TelemetrySettings{
-				Logger:         zapLogger,
-				TracerProvider: traceProvider,
-				MeterProvider:  meterProvider,
-			},
-		}
-		receiver, err := factoryBase.CreateTraces(ctx, params, cfg, middleware.Wrap(shim))
+		receiver, err := factoryBase.CreateTraces(ctx, params, cfg, next)
 		if err != nil {
 			return nil, err
 		}
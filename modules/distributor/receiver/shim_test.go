@@ -9,6 +9,7 @@ import (
 
 	dslog "github.com/grafana/dskit/log"
 	"github.com/grafana/dskit/services"
+	"github.com/grafana/dskit/user"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
@@ -16,6 +17,7 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/config/configopaque"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
@@ -141,11 +143,100 @@ tempo_receiver_refused_spans{receiver="tempo/otlp_receiver", transport="<transpo
 	}
 }
 
+func TestShim_namedReceivers(t *testing.T) {
+	receiverCfg := map[string]interface{}{
+		"otlp": map[string]interface{}{
+			"protocols": map[string]interface{}{
+				"grpc": map[string]interface{}{
+					"endpoint": "127.0.0.1:4317",
+				},
+			},
+		},
+		"otlp/internal": map[string]interface{}{
+			"protocols": map[string]interface{}{
+				"http": map[string]interface{}{
+					"endpoint": "127.0.0.1:4318",
+				},
+			},
+		},
+	}
+
+	level := dslog.Level{}
+	_ = level.Set("info")
+
+	_, err := New(receiverCfg, map[string]string{"otlp/unknown": "internal"}, &tenantPusher{}, MultiTenancyMiddleware(), 0, level, prometheus.NewPedanticRegistry())
+	require.EqualError(t, err, "default tenant configured for unknown receiver: otlp/unknown")
+
+	pusher := &tenantPusher{}
+	reg := prometheus.NewPedanticRegistry()
+	shim, err := New(receiverCfg, map[string]string{"otlp/internal": "internal"}, pusher, MultiTenancyMiddleware(), 0, level, reg)
+	require.NoError(t, err)
+	require.NoError(t, services.StartAndAwaitRunning(context.Background(), shim))
+	defer func() {
+		err := services.StopAndAwaitTerminated(context.Background(), shim)
+		if !errors.Is(err, context.Canceled) {
+			assert.NoError(t, err)
+		}
+	}()
+
+	// requests on the internal receiver are assigned the default tenant
+	exporter, stopExporter := runOTelExporter(t, otlphttpexporter.NewFactory(), &otlphttpexporter.Config{
+		ClientConfig: confighttp.ClientConfig{
+			Endpoint: "http://127.0.0.1:4318",
+		},
+		Encoding: otlphttpexporter.EncodingProto,
+	})
+	require.NoError(t, exporter.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+	stopExporter()
+
+	// the tenant of the request takes precedence over the default tenant
+	exporter, stopExporter = runOTelExporter(t, otlphttpexporter.NewFactory(), &otlphttpexporter.Config{
+		ClientConfig: confighttp.ClientConfig{
+			Endpoint: "http://127.0.0.1:4318",
+			Headers:  map[string]configopaque.String{"X-Scope-OrgID": "other"},
+		},
+		Encoding: otlphttpexporter.EncodingProto,
+	})
+	require.NoError(t, exporter.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+	stopExporter()
+
+	// requests on the receiver without a default tenant need a tenant
+	exporter, stopExporter = runOTelExporter(t, otlpexporter.NewFactory(), &otlpexporter.Config{
+		ClientConfig: configgrpc.ClientConfig{
+			Endpoint: "127.0.0.1:4317",
+			TLSSetting: configtls.ClientConfig{
+				Insecure: true,
+			},
+		},
+	})
+	require.Error(t, exporter.ConsumeTraces(context.Background(), testdata.GenerateTraces(1)))
+	stopExporter()
+
+	require.Equal(t, []string{"internal", "other"}, pusher.tenants)
+
+	count, err := testutil.GatherAndCount(reg, "tempo_receiver_accepted_spans")
+	require.NoError(t, err)
+	require.Equal(t, 2, count, "expected a series per receiver")
+}
+
+type tenantPusher struct {
+	tenants []string
+}
+
+func (p *tenantPusher) PushTraces(ctx context.Context, _ ptrace.Traces) (*tempopb.PushResponse, error) {
+	tenant, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.tenants = append(p.tenants, tenant)
+	return &tempopb.PushResponse{}, nil
+}
+
 func runReceiverShim(t *testing.T, receiverCfg map[string]interface{}, pusher TracesPusher, reg prometheus.Registerer) func() {
 	level := dslog.Level{}
 	_ = level.Set("info")
 
-	shim, err := New(receiverCfg, pusher, FakeTenantMiddleware(), 0, level, reg)
+	shim, err := New(receiverCfg, nil, pusher, FakeTenantMiddleware(), 0, level, reg)
 	require.NoError(t, err)
 
 	err = services.StartAndAwaitRunning(context.Background(), shim)
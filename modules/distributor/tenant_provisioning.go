@@ -0,0 +1,303 @@
+package distributor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/modules/overrides"
+	userconfigurableoverrides "github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+var metricTenantProvisioning = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "distributor_tenant_provisioning_total",
+	Help:      "The total number of times a tenant was seen for the first time, by the result of provisioning it.",
+}, []string{"result"})
+
+// TenantProvisioner decides whether to accept the traces of a tenant the first time a distributor sees the tenant.
+type TenantProvisioner interface {
+	// ProvisionTenant is called once per distributor for every tenant that doesn't have overrides. It must be
+	// idempotent, every distributor asks for the same tenant and a tenant is asked again after it was rejected.
+	ProvisionTenant(ctx context.Context, tenantID string) (TenantProvisioningDecision, error)
+}
+
+// TenantProvisionerFunc is a TenantProvisioner implemented by a function.
+type TenantProvisionerFunc func(ctx context.Context, tenantID string) (TenantProvisioningDecision, error)
+
+func (f TenantProvisionerFunc) ProvisionTenant(ctx context.Context, tenantID string) (TenantProvisioningDecision, error) {
+	return f(ctx, tenantID)
+}
+
+// TenantProvisioningDecision is the response of a TenantProvisioner, and the body of the response of the webhook.
+type TenantProvisioningDecision struct {
+	Accept bool `json:"accept"`
+	// Reason is returned to the client if the tenant is rejected.
+	Reason string `json:"reason,omitempty"`
+	// Overrides are stored as the user-configurable overrides of an accepted tenant, unless it already has some.
+	Overrides *userconfigurableoverrides.Limits `json:"overrides,omitempty"`
+}
+
+// OverridesValidator validates the overrides a TenantProvisioner seeds for a tenant.
+type OverridesValidator interface {
+	Validate(limits *userconfigurableoverrides.Limits) error
+}
+
+// TenantProvisioningConfig configures the hook that is called the first time a distributor sees traces of a tenant
+// without overrides.
+type TenantProvisioningConfig struct {
+	// WebhookURL is sent a POST request with the tenant ID and responds with a TenantProvisioningDecision.
+	WebhookURL string `yaml:"webhook_url"`
+	// Timeout of a call to the hook.
+	Timeout time.Duration `yaml:"timeout"`
+	// RecheckInterval is how long a tenant that was rejected, or that the hook failed for, is remembered before the
+	// hook is called again.
+	RecheckInterval time.Duration `yaml:"recheck_interval"`
+	// FailOpen accepts the traces of a tenant if the hook fails.
+	FailOpen bool `yaml:"fail_open"`
+
+	// Provisioner is a custom hook. It can only be set in code and cannot be used with WebhookURL.
+	Provisioner TenantProvisioner `yaml:"-"`
+	// OverridesClient stores the overrides returned by the hook. It is set if user-configurable overrides are enabled.
+	OverridesClient userconfigurableoverrides.Client `yaml:"-"`
+	// OverridesValidator validates the overrides returned by the hook before they are stored.
+	OverridesValidator OverridesValidator `yaml:"-"`
+}
+
+func (cfg *TenantProvisioningConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.WebhookURL, util.PrefixConfig(prefix, "webhook-url"), "", "URL that is called the first time traces of a tenant without overrides are received, to accept or reject the tenant.")
+	f.DurationVar(&cfg.Timeout, util.PrefixConfig(prefix, "timeout"), 5*time.Second, "Timeout of calls to the tenant provisioning webhook.")
+	f.DurationVar(&cfg.RecheckInterval, util.PrefixConfig(prefix, "recheck-interval"), time.Minute, "How long a rejected tenant is remembered before the webhook is called again.")
+	f.BoolVar(&cfg.FailOpen, util.PrefixConfig(prefix, "fail-open"), false, "Accept traces of a tenant if the tenant provisioning webhook fails.")
+}
+
+func (cfg *TenantProvisioningConfig) Validate() error {
+	if cfg.WebhookURL != "" && cfg.Provisioner != nil {
+		return errors.New("tenant provisioning webhook url cannot be used with a custom provisioner")
+	}
+	if cfg.enabled() && (cfg.Timeout <= 0 || cfg.RecheckInterval <= 0) {
+		return errors.New("tenant provisioning timeout and recheck interval must be greater than 0")
+	}
+	return nil
+}
+
+func (cfg *TenantProvisioningConfig) enabled() bool {
+	return cfg.WebhookURL != "" || cfg.Provisioner != nil
+}
+
+// webhookProvisioner provisions tenants by calling a webhook.
+type webhookProvisioner struct {
+	url    string
+	client *http.Client
+}
+
+func (w *webhookProvisioner) ProvisionTenant(ctx context.Context, tenantID string) (TenantProvisioningDecision, error) {
+	body, err := json.Marshal(struct {
+		TenantID string `json:"tenant_id"`
+	}{tenantID})
+	if err != nil {
+		return TenantProvisioningDecision{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return TenantProvisioningDecision{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return TenantProvisioningDecision{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return TenantProvisioningDecision{}, fmt.Errorf("unexpected status %d from tenant provisioning webhook: %s", resp.StatusCode, msg)
+	}
+
+	var decision TenantProvisioningDecision
+	if err := json.NewDecoder(resp.Body).Decode(&decision); err != nil {
+		return TenantProvisioningDecision{}, fmt.Errorf("failed to decode response of tenant provisioning webhook: %w", err)
+	}
+	return decision, nil
+}
+
+// provisionedTenant is the result of provisioning a tenant. The other fields are set when done is closed.
+type provisionedTenant struct {
+	done chan struct{}
+	err  error
+	// expires is when the tenant is provisioned again, zero if the result is kept forever
+	expires time.Time
+}
+
+func (t *provisionedTenant) expired(now time.Time) bool {
+	select {
+	case <-t.done:
+		return !t.expires.IsZero() && now.After(t.expires)
+	default:
+		return false
+	}
+}
+
+// tenantProvisioning calls the provisioner the first time a tenant is seen and remembers its decision. Concurrent
+// pushes of a new tenant wait for a single call of the provisioner.
+type tenantProvisioning struct {
+	cfg         TenantProvisioningConfig
+	provisioner TenantProvisioner
+	overrides   overrides.Interface
+	logger      log.Logger
+
+	mtx       sync.Mutex
+	tenants   map[string]*provisionedTenant
+	lastPrune time.Time
+}
+
+func newTenantProvisioning(cfg TenantProvisioningConfig, o overrides.Interface, logger log.Logger) *tenantProvisioning {
+	provisioner := cfg.Provisioner
+	if provisioner == nil {
+		provisioner = &webhookProvisioner{url: cfg.WebhookURL, client: &http.Client{}}
+	}
+
+	return &tenantProvisioning{
+		cfg:         cfg,
+		provisioner: provisioner,
+		overrides:   o,
+		logger:      log.With(logger, "component", "tenant-provisioning"),
+		tenants:     map[string]*provisionedTenant{},
+		lastPrune:   time.Now(),
+	}
+}
+
+// admit returns an error if the traces of the tenant are not accepted.
+func (p *tenantProvisioning) admit(ctx context.Context, tenantID string) error {
+	now := time.Now()
+
+	p.mtx.Lock()
+	t, ok := p.tenants[tenantID]
+	if !ok || t.expired(now) {
+		p.pruneExpired(now)
+		t = &provisionedTenant{done: make(chan struct{})}
+		p.tenants[tenantID] = t
+		p.mtx.Unlock()
+
+		p.provision(tenantID, t)
+	} else {
+		p.mtx.Unlock()
+	}
+
+	select {
+	case <-t.done:
+		return t.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pruneExpired removes the tenants that will be provisioned again, so tenants that are rejected don't accumulate.
+// Must be called with mtx held.
+func (p *tenantProvisioning) pruneExpired(now time.Time) {
+	if now.Sub(p.lastPrune) < p.cfg.RecheckInterval {
+		return
+	}
+	p.lastPrune = now
+
+	for tenantID, t := range p.tenants {
+		if t.expired(now) {
+			delete(p.tenants, tenantID)
+		}
+	}
+}
+
+func (p *tenantProvisioning) provision(tenantID string, t *provisionedTenant) {
+	defer close(t.done)
+
+	// tenants that are configured by hand are known
+	if overrides.HasTenantOverrides(p.overrides, tenantID) {
+		metricTenantProvisioning.WithLabelValues("known").Inc()
+		return
+	}
+
+	// the call is not canceled with the push, other pushes of the tenant are waiting for it
+	ctx, cancel := context.WithTimeout(context.Background(), p.cfg.Timeout)
+	defer cancel()
+
+	decision, err := p.provisioner.ProvisionTenant(ctx, tenantID)
+	if err != nil {
+		metricTenantProvisioning.WithLabelValues("error").Inc()
+		level.Warn(p.logger).Log("msg", "failed to provision tenant", "tenant", tenantID, "fail_open", p.cfg.FailOpen, "err", err)
+
+		t.expires = time.Now().Add(p.cfg.RecheckInterval)
+		if !p.cfg.FailOpen {
+			t.err = status.Errorf(codes.Unavailable, "failed to provision tenant %s", tenantID)
+		}
+		return
+	}
+
+	if !decision.Accept {
+		metricTenantProvisioning.WithLabelValues("rejected").Inc()
+		level.Info(p.logger).Log("msg", "tenant rejected", "tenant", tenantID, "reason", decision.Reason)
+
+		t.expires = time.Now().Add(p.cfg.RecheckInterval)
+		t.err = status.Errorf(codes.PermissionDenied, "tenant %s was rejected: %s", tenantID, decision.Reason)
+		return
+	}
+
+	metricTenantProvisioning.WithLabelValues("accepted").Inc()
+	level.Info(p.logger).Log("msg", "tenant accepted", "tenant", tenantID)
+
+	if decision.Overrides == nil {
+		return
+	}
+	if err := p.seedOverrides(ctx, tenantID, decision.Overrides); err != nil {
+		// the tenant is accepted, the overrides are stored the next time it is provisioned
+		level.Error(p.logger).Log("msg", "failed to store overrides of provisioned tenant", "tenant", tenantID, "err", err)
+		t.expires = time.Now().Add(p.cfg.RecheckInterval)
+	}
+}
+
+// seedOverrides stores limits as the user-configurable overrides of the tenant if it doesn't have any. The overrides
+// are applied the next time the overrides are polled.
+func (p *tenantProvisioning) seedOverrides(ctx context.Context, tenantID string, limits *userconfigurableoverrides.Limits) error {
+	if p.cfg.OverridesClient == nil {
+		return errors.New("user-configurable overrides are not enabled")
+	}
+
+	if p.cfg.OverridesValidator != nil {
+		if err := p.cfg.OverridesValidator.Validate(limits); err != nil {
+			return fmt.Errorf("invalid overrides: %w", err)
+		}
+	}
+
+	_, _, err := p.cfg.OverridesClient.Get(ctx, tenantID)
+	if err == nil {
+		// overrides that were set already are kept
+		return nil
+	}
+	if !errors.Is(err, backend.ErrDoesNotExist) {
+		return err
+	}
+
+	_, err = p.cfg.OverridesClient.Set(ctx, tenantID, limits, backend.VersionNew)
+	if errors.Is(err, backend.ErrVersionDoesNotMatch) {
+		// another distributor stored them first
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,128 @@
+package distributor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/tempo/modules/overrides"
+	userconfigurableoverrides "github.com/grafana/tempo/modules/overrides/userconfigurable/client"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+)
+
+func TestTenantProvisioning(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	client, err := userconfigurableoverrides.New(&userconfigurableoverrides.Config{
+		Backend: backend.Local,
+		Local:   &local.Config{Path: t.TempDir()},
+	})
+	require.NoError(t, err)
+
+	calls := map[string]*atomic.Int32{"accepted": atomic.NewInt32(0), "rejected": atomic.NewInt32(0), "failing": atomic.NewInt32(0)}
+	seeded := &userconfigurableoverrides.Limits{Forwarders: &[]string{"my-forwarder"}}
+
+	cfg := TenantProvisioningConfig{
+		Timeout:         time.Second,
+		RecheckInterval: time.Hour,
+		Provisioner: TenantProvisionerFunc(func(_ context.Context, tenantID string) (TenantProvisioningDecision, error) {
+			calls[tenantID].Inc()
+			switch tenantID {
+			case "accepted":
+				return TenantProvisioningDecision{Accept: true, Overrides: seeded}, nil
+			case "rejected":
+				return TenantProvisioningDecision{Reason: "unknown team"}, nil
+			default:
+				return TenantProvisioningDecision{}, errors.New("webhook unavailable")
+			}
+		}),
+		OverridesClient: client,
+	}
+	p := newTenantProvisioning(cfg, o, log.NewNopLogger())
+	ctx := context.Background()
+
+	// concurrent pushes of a new tenant call the provisioner once
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, p.admit(ctx, "accepted"))
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, int32(1), calls["accepted"].Load())
+
+	limits, _, err := client.Get(ctx, "accepted")
+	require.NoError(t, err)
+	require.Equal(t, seeded, limits)
+
+	// rejected tenants are remembered until they are rechecked
+	for i := 0; i < 2; i++ {
+		err = p.admit(ctx, "rejected")
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		require.ErrorContains(t, err, "unknown team")
+	}
+	require.Equal(t, int32(1), calls["rejected"].Load())
+
+	p.tenants["rejected"].expires = time.Now().Add(-time.Second)
+	require.Error(t, p.admit(ctx, "rejected"))
+	require.Equal(t, int32(2), calls["rejected"].Load())
+
+	// tenants the provisioner failed for are rejected unless failing open
+	err = p.admit(ctx, "failing")
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	cfg.FailOpen = true
+	p = newTenantProvisioning(cfg, o, log.NewNopLogger())
+	require.NoError(t, p.admit(ctx, "failing"))
+	require.Equal(t, int32(2), calls["failing"].Load())
+}
+
+func TestTenantProvisioningWebhook(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			TenantID string `json:"tenant_id"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		switch req.TenantID {
+		case "accepted":
+			_, _ = w.Write([]byte(`{"accept": true, "overrides": {"forwarders": ["my-forwarder"]}}`))
+		case "rejected":
+			_, _ = w.Write([]byte(`{"accept": false, "reason": "unknown team"}`))
+		default:
+			http.Error(w, "internal error", http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	w := &webhookProvisioner{url: server.URL, client: server.Client()}
+	ctx := context.Background()
+
+	decision, err := w.ProvisionTenant(ctx, "accepted")
+	require.NoError(t, err)
+	require.True(t, decision.Accept)
+	require.Equal(t, &userconfigurableoverrides.Limits{Forwarders: &[]string{"my-forwarder"}}, decision.Overrides)
+
+	decision, err = w.ProvisionTenant(ctx, "rejected")
+	require.NoError(t, err)
+	require.Equal(t, TenantProvisioningDecision{Reason: "unknown team"}, decision)
+
+	_, err = w.ProvisionTenant(ctx, "failing")
+	require.ErrorContains(t, err, "unexpected status 500")
+}
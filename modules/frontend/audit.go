@@ -0,0 +1,278 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/gogo/status"
+	"github.com/grafana/dskit/grpcutil"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"google.golang.org/grpc/codes"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	AuditOutputFile = "file"
+	AuditOutputOTLP = "otlp"
+
+	auditScopeName = "tempo-query-frontend-audit"
+)
+
+var metricAuditRecordsFailed = promauto.NewCounter(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "query_frontend_audit_records_failed_total",
+	Help:      "Total number of query audit records that failed to be written.",
+})
+
+// AuditConfig configures the audit log of queries.
+type AuditConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Header that identifies the user that initiated the query.
+	UserHeader string `yaml:"user_header"`
+	// Output is where records are written: file or otlp.
+	Output string `yaml:"output"`
+	// Path of the file records are appended to as JSON lines.
+	Path string `yaml:"path"`
+	// OTLP HTTP endpoint records are sent to as logs.
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	OTLPInsecure bool   `yaml:"otlp_insecure"`
+}
+
+func (cfg *AuditConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	switch cfg.Output {
+	case AuditOutputFile:
+		if cfg.Path == "" {
+			return errors.New("audit path is required when the output is file")
+		}
+	case AuditOutputOTLP:
+		if cfg.OTLPEndpoint == "" {
+			return errors.New("audit otlp_endpoint is required when the output is otlp")
+		}
+	default:
+		return fmt.Errorf("unknown audit output %q, valid values: %s, %s", cfg.Output, AuditOutputFile, AuditOutputOTLP)
+	}
+
+	return nil
+}
+
+// auditRecord is a single query in the audit log.
+type auditRecord struct {
+	Time           time.Time `json:"time"`
+	Tenant         string    `json:"tenant"`
+	User           string    `json:"user,omitempty"`
+	Op             string    `json:"op"`
+	Path           string    `json:"path"`
+	Query          string    `json:"query,omitempty"`
+	Start          string    `json:"start,omitempty"`
+	End            string    `json:"end,omitempty"`
+	Status         int       `json:"status"`
+	LatencySeconds float64   `json:"latency_seconds"`
+	BytesProcessed uint64    `json:"bytes_processed"`
+}
+
+type auditWriter interface {
+	write(r auditRecord) error
+	close() error
+}
+
+// auditLogger records every query handled by the frontend. A nil *auditLogger is valid and records nothing.
+type auditLogger struct {
+	userHeader string
+	writer     auditWriter
+	logger     log.Logger
+}
+
+func newAuditLogger(cfg AuditConfig, logger log.Logger) (*auditLogger, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	var (
+		w   auditWriter
+		err error
+	)
+	switch cfg.Output {
+	case AuditOutputFile:
+		w, err = newFileAuditWriter(cfg.Path)
+	case AuditOutputOTLP:
+		w, err = newOTLPAuditWriter(cfg)
+	default:
+		err = fmt.Errorf("unknown audit output %q", cfg.Output)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &auditLogger{
+		userHeader: cfg.UserHeader,
+		writer:     w,
+		logger:     logger,
+	}, nil
+}
+
+// wrap returns a post hook that records the query and then calls next.
+func (a *auditLogger) wrap(op string, next handlerPostHook) handlerPostHook {
+	if a == nil {
+		return next
+	}
+
+	return func(req *http.Request, resp *http.Response, tenant string, bytesProcessed uint64, latency time.Duration, err error) {
+		next(req, resp, tenant, bytesProcessed, latency, err)
+
+		r := auditRecord{
+			Time:           time.Now().Add(-latency),
+			Tenant:         tenant,
+			Op:             op,
+			Status:         auditStatusCode(resp, err),
+			LatencySeconds: latency.Seconds(),
+			BytesProcessed: bytesProcessed,
+		}
+		if req != nil {
+			query := req.URL.Query()
+			r.User = req.Header.Get(a.userHeader)
+			r.Path = req.URL.Path
+			r.Query = query.Get("q")
+			r.Start = query.Get("start")
+			r.End = query.Get("end")
+		}
+
+		if err := a.writer.write(r); err != nil {
+			metricAuditRecordsFailed.Inc()
+			level.Error(a.logger).Log("msg", "failed to write audit record", "err", err)
+		}
+	}
+}
+
+func (a *auditLogger) close() error {
+	if a == nil {
+		return nil
+	}
+	return a.writer.close()
+}
+
+// auditStatusCode returns the http status code of the query. gRPC handlers only have the error.
+func auditStatusCode(resp *http.Response, err error) int {
+	if resp != nil {
+		return resp.StatusCode
+	}
+	if err == nil {
+		return http.StatusOK
+	}
+	if grpcutil.IsCanceled(err) {
+		return util.StatusClientClosedRequest
+	}
+
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	}
+	return http.StatusInternalServerError
+}
+
+// fileAuditWriter appends records to a file as JSON lines.
+type fileAuditWriter struct {
+	mtx sync.Mutex
+	f   *os.File
+}
+
+func newFileAuditWriter(path string) (*fileAuditWriter, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	return &fileAuditWriter{f: f}, nil
+}
+
+func (w *fileAuditWriter) write(r auditRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	_, err = w.f.Write(b)
+	return err
+}
+
+func (w *fileAuditWriter) close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	return w.f.Close()
+}
+
+// otlpAuditWriter sends records as OTLP logs.
+type otlpAuditWriter struct {
+	provider *sdklog.LoggerProvider
+	logger   otellog.Logger
+}
+
+func newOTLPAuditWriter(cfg AuditConfig) (*otlpAuditWriter, error) {
+	opts := []otlploghttp.Option{otlploghttp.WithEndpoint(cfg.OTLPEndpoint)}
+	if cfg.OTLPInsecure {
+		opts = append(opts, otlploghttp.WithInsecure())
+	}
+
+	exporter, err := otlploghttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create audit otlp exporter: %w", err)
+	}
+
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+	return &otlpAuditWriter{
+		provider: provider,
+		logger:   provider.Logger(auditScopeName),
+	}, nil
+}
+
+func (w *otlpAuditWriter) write(r auditRecord) error {
+	var rec otellog.Record
+	rec.SetTimestamp(r.Time)
+	rec.SetObservedTimestamp(time.Now())
+	rec.SetSeverity(otellog.SeverityInfo)
+	rec.SetBody(otellog.StringValue(r.Query))
+	rec.AddAttributes(
+		otellog.String("tenant", r.Tenant),
+		otellog.String("user", r.User),
+		otellog.String("op", r.Op),
+		otellog.String("path", r.Path),
+		otellog.String("start", r.Start),
+		otellog.String("end", r.End),
+		otellog.Int("status", r.Status),
+		otellog.Float64("latency_seconds", r.LatencySeconds),
+		otellog.Int64("bytes_processed", int64(r.BytesProcessed)),
+	)
+
+	w.logger.Emit(context.Background(), rec)
+	return nil
+}
+
+func (w *otlpAuditWriter) close() error {
+	return w.provider.Shutdown(context.Background())
+}
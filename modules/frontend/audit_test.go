@@ -0,0 +1,114 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/status"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+func TestAuditLoggerFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	audit, err := newAuditLogger(AuditConfig{
+		Enabled:    true,
+		UserHeader: "X-Grafana-User",
+		Output:     AuditOutputFile,
+		Path:       path,
+	}, log.NewNopLogger())
+	require.NoError(t, err)
+
+	var nextCalled bool
+	hook := audit.wrap(searchOp, func(*http.Request, *http.Response, string, uint64, time.Duration, error) {
+		nextCalled = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q=%7B%7D&start=10&end=20", nil)
+	req.Header.Set("X-Grafana-User", "jane")
+	hook(req, &http.Response{StatusCode: http.StatusOK}, "tenant-1", 1024, 2*time.Second, nil)
+	hook(nil, nil, "tenant-2", 0, time.Second, status.Error(codes.InvalidArgument, "bad query"))
+
+	require.True(t, nextCalled)
+	require.NoError(t, audit.close())
+
+	b, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	require.Len(t, lines, 2)
+
+	var r auditRecord
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &r))
+	require.Equal(t, "tenant-1", r.Tenant)
+	require.Equal(t, "jane", r.User)
+	require.Equal(t, searchOp, r.Op)
+	require.Equal(t, "/api/search", r.Path)
+	require.Equal(t, "{}", r.Query)
+	require.Equal(t, "10", r.Start)
+	require.Equal(t, "20", r.End)
+	require.Equal(t, http.StatusOK, r.Status)
+	require.Equal(t, 2.0, r.LatencySeconds)
+	require.Equal(t, uint64(1024), r.BytesProcessed)
+
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &r))
+	require.Equal(t, "tenant-2", r.Tenant)
+	require.Equal(t, http.StatusBadRequest, r.Status)
+}
+
+func TestAuditLoggerDisabled(t *testing.T) {
+	audit, err := newAuditLogger(AuditConfig{}, log.NewNopLogger())
+	require.NoError(t, err)
+	require.Nil(t, audit)
+
+	var nextCalled bool
+	hook := audit.wrap(searchOp, func(*http.Request, *http.Response, string, uint64, time.Duration, error) {
+		nextCalled = true
+	})
+	hook(nil, nil, "tenant", 0, 0, nil)
+
+	require.True(t, nextCalled)
+	require.NoError(t, audit.close())
+}
+
+func TestAuditConfigValidate(t *testing.T) {
+	tcs := []struct {
+		cfg    AuditConfig
+		expErr string
+	}{
+		{cfg: AuditConfig{}},
+		{cfg: AuditConfig{Enabled: true, Output: AuditOutputFile, Path: "audit.log"}},
+		{cfg: AuditConfig{Enabled: true, Output: AuditOutputFile}, expErr: "audit path is required when the output is file"},
+		{cfg: AuditConfig{Enabled: true, Output: AuditOutputOTLP, OTLPEndpoint: "localhost:4318"}},
+		{cfg: AuditConfig{Enabled: true, Output: AuditOutputOTLP}, expErr: "audit otlp_endpoint is required when the output is otlp"},
+		{cfg: AuditConfig{Enabled: true, Output: "stdout"}, expErr: `unknown audit output "stdout", valid values: file, otlp`},
+	}
+
+	for _, tc := range tcs {
+		err := tc.cfg.Validate()
+		if tc.expErr != "" {
+			require.EqualError(t, err, tc.expErr)
+			continue
+		}
+		require.NoError(t, err)
+	}
+}
+
+func TestAuditStatusCode(t *testing.T) {
+	require.Equal(t, http.StatusTeapot, auditStatusCode(&http.Response{StatusCode: http.StatusTeapot}, nil))
+	require.Equal(t, http.StatusOK, auditStatusCode(nil, nil))
+	require.Equal(t, util.StatusClientClosedRequest, auditStatusCode(nil, context.Canceled))
+	require.Equal(t, http.StatusTooManyRequests, auditStatusCode(nil, status.Error(codes.ResourceExhausted, "")))
+	require.Equal(t, http.StatusInternalServerError, auditStatusCode(nil, errors.New("oops")))
+}
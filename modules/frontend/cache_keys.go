@@ -12,6 +12,7 @@ const (
 	cacheKeyPrefixSearchTag       = "st:"
 	cacheKeyPrefixSearchTagValues = "stv:"
 	cacheKeyPrefixQueryRange      = "qr:"
+	cacheKeyPrefixSearchResults   = "sr:"
 )
 
 func searchJobCacheKey(tenant string, queryHash uint64, start int64, end int64, meta *backend.BlockMeta, startPage, pagesToSearch int) string {
@@ -22,6 +23,25 @@ func queryRangeCacheKey(tenant string, queryHash uint64, start int64, end int64,
 	return cacheKey(cacheKeyPrefixQueryRange, tenant, queryHash, start, end, meta, startPage, pagesToSearch)
 }
 
+// searchResultsCacheKey returns the cache key of the complete response of a search. the org id may contain several
+// tenants
+func searchResultsCacheKey(orgID string, queryHash uint64, start, end uint32, format string) string {
+	sb := strings.Builder{}
+	sb.Grow(len(cacheKeyPrefixSearchResults) + len(orgID) + 1 + 20 + 1 + 10 + 1 + 10 + 1 + len(format))
+	sb.WriteString(cacheKeyPrefixSearchResults)
+	sb.WriteString(orgID)
+	sb.WriteString(":")
+	sb.WriteString(strconv.FormatUint(queryHash, 10))
+	sb.WriteString(":")
+	sb.WriteString(strconv.FormatUint(uint64(start), 10))
+	sb.WriteString(":")
+	sb.WriteString(strconv.FormatUint(uint64(end), 10))
+	sb.WriteString(":")
+	sb.WriteString(format)
+
+	return sb.String()
+}
+
 // cacheKey returns a string that can be used as a cache key for a backend search job. if a valid key cannot be calculated
 // it returns an empty string.
 func cacheKey(prefix string, tenant string, queryHash uint64, start int64, end int64, meta *backend.BlockMeta, startPage, pagesToSearch int) string {
@@ -0,0 +1,328 @@
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level" //nolint:all //deprecated
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/common/model"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+)
+
+const (
+	cacheWarmingTypeSearch  = "search"
+	cacheWarmingTypeMetrics = "metrics"
+
+	cacheWarmingResultFailed = "failed"
+)
+
+var metricCacheWarmingQueries = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "query_frontend_cache_warming_queries_total",
+	Help:      "Total number of queries executed to warm the frontend cache.",
+}, []string{"tenant", "type", "result"})
+
+type CacheWarmingConfig struct {
+	// the number of jobs a warming query runs concurrently. keep it below the concurrency of user queries so
+	// warming runs at a lower priority. 0 uses the concurrency of user queries
+	ConcurrentJobs int `yaml:"concurrent_jobs,omitempty"`
+	// the maximum number of warming requests that can be waiting to be executed. warming requests are executed one at
+	// a time
+	MaxPendingRequests int `yaml:"max_pending_requests,omitempty"`
+	// the maximum number of queries in a single warming request
+	MaxQueries int `yaml:"max_queries,omitempty"`
+	// the time limit for a single warming query
+	QueryTimeout time.Duration `yaml:"query_timeout,omitempty"`
+}
+
+// cacheWarmingRequest is the body of a cache warming request
+type cacheWarmingRequest struct {
+	Queries []cacheWarmingQuery `json:"queries"`
+}
+
+// cacheWarmingQuery is a query to execute to warm the cache. the time range is either start and end in unix epoch
+// seconds or since, which is resolved relative to the time the query is executed.
+type cacheWarmingQuery struct {
+	Type            string `json:"type"`
+	Query           string `json:"query"`
+	Start           int64  `json:"start,omitempty"`
+	End             int64  `json:"end,omitempty"`
+	Since           string `json:"since,omitempty"`
+	Step            string `json:"step,omitempty"`
+	Limit           uint32 `json:"limit,omitempty"`
+	SpansPerSpanSet uint32 `json:"spss,omitempty"`
+}
+
+func (q *cacheWarmingQuery) validate() error {
+	switch q.Type {
+	case cacheWarmingTypeSearch, cacheWarmingTypeMetrics:
+	default:
+		return fmt.Errorf("invalid type %q, must be %q or %q", q.Type, cacheWarmingTypeSearch, cacheWarmingTypeMetrics)
+	}
+
+	if _, err := traceql.Parse(q.Query); err != nil {
+		return fmt.Errorf("invalid query %q: %w", q.Query, err)
+	}
+
+	if q.Since != "" {
+		if q.Start != 0 || q.End != 0 {
+			return errors.New("since can't be combined with start and end")
+		}
+		if _, err := model.ParseDuration(q.Since); err != nil {
+			return fmt.Errorf("invalid since: %w", err)
+		}
+	} else if q.End <= q.Start {
+		return fmt.Errorf("start must be before end. received start=%d end=%d", q.Start, q.End)
+	}
+
+	if q.Step != "" {
+		if q.Type != cacheWarmingTypeMetrics {
+			return errors.New("step is only supported for metrics queries")
+		}
+		if _, err := model.ParseDuration(q.Step); err != nil {
+			return fmt.Errorf("invalid step: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// bounds returns the time range of the query. a relative time range is resolved against now.
+func (q *cacheWarmingQuery) bounds(now time.Time) (time.Time, time.Time) {
+	if q.Since == "" {
+		return time.Unix(q.Start, 0), time.Unix(q.End, 0)
+	}
+
+	since, _ := model.ParseDuration(q.Since) // validated
+	return now.Add(-time.Duration(since)), now
+}
+
+// cacheWarmer executes queries in the background to populate the frontend cache. warming requests are executed one
+// at a time through dedicated pipelines with a lower job concurrency than user queries.
+type cacheWarmer struct {
+	cfg               CacheWarmingConfig
+	responseConsumers int
+	defaultLimit      uint32
+	maxLimit          uint32
+	search            pipeline.AsyncRoundTripper[combiner.PipelineResponse]
+	queryRange        pipeline.AsyncRoundTripper[combiner.PipelineResponse]
+	searchPath        string
+	queryRangePath    string
+	logger            log.Logger
+
+	sem     chan struct{}
+	pending *atomic.Int32
+}
+
+func newCacheWarmer(cfg Config, search, queryRange pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, logger log.Logger) *cacheWarmer {
+	return &cacheWarmer{
+		cfg:               cfg.CacheWarming,
+		responseConsumers: cfg.ResponseConsumers,
+		defaultLimit:      cfg.Search.Sharder.DefaultLimit,
+		maxLimit:          cfg.Search.Sharder.MaxLimit,
+		search:            search,
+		queryRange:        queryRange,
+		searchPath:        path.Join(apiPrefix, api.PathSearch),
+		queryRangePath:    path.Join(apiPrefix, api.PathMetricsQueryRange),
+		logger:            log.With(logger, "component", "cache-warmer"),
+		sem:               make(chan struct{}, 1),
+		pending:           atomic.NewInt32(0),
+	}
+}
+
+// newCacheWarmingHTTPHandler returns a handler that accepts a list of queries and executes them in the background. the
+// handler returns as soon as the request is queued.
+func newCacheWarmingHTTPHandler(w *cacheWarmer, enabled bool, logger log.Logger) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost {
+			return httpError(http.StatusMethodNotAllowed, "cache warming requires a POST request"), nil
+		}
+
+		if !enabled {
+			return httpError(http.StatusBadRequest, "cache warming requires the frontend search cache to be configured"), nil
+		}
+
+		tenant, err := user.ExtractOrgID(req.Context())
+		if err != nil {
+			level.Error(logger).Log("msg", "cache warming: failed to extract tenant id", "err", err)
+			return httpError(http.StatusBadRequest, err.Error()), nil
+		}
+
+		warmReq := &cacheWarmingRequest{}
+		if err := json.NewDecoder(req.Body).Decode(warmReq); err != nil {
+			return httpError(http.StatusBadRequest, fmt.Sprintf("failed to parse cache warming request: %s", err)), nil
+		}
+
+		if len(warmReq.Queries) == 0 {
+			return httpError(http.StatusBadRequest, "cache warming request has no queries"), nil
+		}
+		if w.cfg.MaxQueries > 0 && len(warmReq.Queries) > w.cfg.MaxQueries {
+			return httpError(http.StatusBadRequest, fmt.Sprintf("cache warming request has %d queries, the maximum is %d", len(warmReq.Queries), w.cfg.MaxQueries)), nil
+		}
+		for i := range warmReq.Queries {
+			q := &warmReq.Queries[i]
+			if err := q.validate(); err != nil {
+				return httpError(http.StatusBadRequest, fmt.Sprintf("query %d: %s", i, err)), nil
+			}
+
+			// apply the default limit up front, so the combiner stops at the same limit as the query that is warmed
+			if q.Type == cacheWarmingTypeSearch {
+				if q.Limit, err = adjustLimit(q.Limit, w.defaultLimit, w.maxLimit); err != nil {
+					return httpError(http.StatusBadRequest, fmt.Sprintf("query %d: %s", i, err)), nil
+				}
+			}
+		}
+
+		if !w.enqueue(tenant, warmReq.Queries) {
+			return httpError(http.StatusTooManyRequests, "too many pending cache warming requests"), nil
+		}
+
+		level.Info(logger).Log("msg", "cache warming request queued", "tenant", tenant, "queries", len(warmReq.Queries))
+
+		return &http.Response{
+			StatusCode: http.StatusAccepted,
+			Status:     http.StatusText(http.StatusAccepted),
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	})
+}
+
+// enqueue starts executing the queries in the background. it returns false if there are too many pending requests.
+func (w *cacheWarmer) enqueue(tenant string, queries []cacheWarmingQuery) bool {
+	if pending := w.pending.Inc(); w.cfg.MaxPendingRequests > 0 && int(pending) > w.cfg.MaxPendingRequests {
+		w.pending.Dec()
+		return false
+	}
+
+	go func() {
+		defer w.pending.Dec()
+
+		w.sem <- struct{}{}
+		defer func() { <-w.sem }()
+
+		for i := range queries {
+			w.warm(tenant, &queries[i])
+		}
+	}()
+
+	return true
+}
+
+// warm executes a single query. the response is discarded, the cache is populated by the pipeline.
+func (w *cacheWarmer) warm(tenant string, q *cacheWarmingQuery) {
+	ctx := user.InjectOrgID(context.Background(), tenant)
+	if w.cfg.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.cfg.QueryTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := w.execute(ctx, q, start)
+
+	result := resultCompleted
+	if err != nil {
+		result = cacheWarmingResultFailed
+		level.Error(w.logger).Log("msg", "cache warming query failed", "tenant", tenant, "type", q.Type, "query", q.Query, "err", err)
+	}
+	metricCacheWarmingQueries.WithLabelValues(tenant, q.Type, result).Inc()
+
+	level.Info(w.logger).Log("msg", "cache warming query executed", "tenant", tenant, "type", q.Type, "query", q.Query, "duration_seconds", time.Since(start).Seconds(), "result", result)
+}
+
+func (w *cacheWarmer) execute(ctx context.Context, q *cacheWarmingQuery, now time.Time) error {
+	start, end := q.bounds(now)
+
+	var (
+		httpReq *http.Request
+		rt      http.RoundTripper
+	)
+
+	switch q.Type {
+	case cacheWarmingTypeSearch:
+		searchReq := &tempopb.SearchRequest{
+			Query:           q.Query,
+			Start:           uint32(start.Unix()),
+			End:             uint32(end.Unix()),
+			Limit:           q.Limit,
+			SpansPerSpanSet: q.SpansPerSpanSet,
+		}
+
+		var err error
+		httpReq, err = api.BuildSearchRequest(newCacheWarmingHTTPRequest(ctx, w.searchPath), searchReq)
+		if err != nil {
+			return err
+		}
+
+		rt = pipeline.NewHTTPCollector(w.search, w.responseConsumers, combiner.NewTypedSearch(int(q.Limit), api.IsMostRecentSearch(searchReq), 0))
+	case cacheWarmingTypeMetrics:
+		queryRangeReq := &tempopb.QueryRangeRequest{
+			Query: q.Query,
+			Start: uint64(start.UnixNano()),
+			End:   uint64(end.UnixNano()),
+		}
+		if q.Step != "" {
+			step, _ := model.ParseDuration(q.Step) // validated
+			queryRangeReq.Step = uint64(time.Duration(step).Nanoseconds())
+		} else {
+			queryRangeReq.Step = traceql.DefaultQueryRangeStep(queryRangeReq.Start, queryRangeReq.End)
+		}
+
+		httpReq = api.BuildQueryRangeRequest(newCacheWarmingHTTPRequest(ctx, w.queryRangePath), queryRangeReq, "")
+
+		// the series limit protects callers from large responses. warming only fills the cache, so it's not applied
+		comb, err := combiner.NewTypedQueryRange(queryRangeReq, 0)
+		if err != nil {
+			return err
+		}
+		rt = pipeline.NewHTTPCollector(w.queryRange, w.responseConsumers, comb)
+	}
+
+	resp, err := rt.RoundTrip(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+func newCacheWarmingHTTPRequest(ctx context.Context, p string) *http.Request {
+	return (&http.Request{
+		Method: http.MethodGet,
+		URL:    &url.URL{Path: p},
+		Header: http.Header{},
+		Body:   http.NoBody,
+	}).WithContext(ctx)
+}
+
+func httpError(statusCode int, msg string) *http.Response {
+	return &http.Response{
+		StatusCode: statusCode,
+		Status:     http.StatusText(statusCode),
+		Body:       io.NopCloser(strings.NewReader(msg)),
+	}
+}
@@ -0,0 +1,179 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestCacheWarmingHandlerBadRequests(t *testing.T) {
+	p := test.NewMockProvider()
+	require.NoError(t, p.AddCache(cache.RoleFrontendSearch, test.NewMockClient()))
+
+	withCache := frontendWithSettings(t, nil, nil, nil, p, func(c *Config) {
+		c.CacheWarming.MaxQueries = 2
+	})
+	withoutCache := frontendWithSettings(t, nil, nil, nil, nil)
+
+	tcs := []struct {
+		name           string
+		f              *QueryFrontend
+		method         string
+		body           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "not a post",
+			f:              withCache,
+			method:         http.MethodGet,
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+		{
+			name:           "no cache",
+			f:              withoutCache,
+			method:         http.MethodPost,
+			body:           `{"queries":[{"type":"search","query":"{}","since":"1h"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "frontend search cache",
+		},
+		{
+			name:           "invalid json",
+			f:              withCache,
+			method:         http.MethodPost,
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "no queries",
+			f:              withCache,
+			method:         http.MethodPost,
+			body:           `{"queries":[]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "no queries",
+		},
+		{
+			name:           "too many queries",
+			f:              withCache,
+			method:         http.MethodPost,
+			body:           `{"queries":[{"type":"search","query":"{}","since":"1h"},{"type":"search","query":"{}","since":"1h"},{"type":"search","query":"{}","since":"1h"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "the maximum is 2",
+		},
+		{
+			name:           "invalid type",
+			f:              withCache,
+			method:         http.MethodPost,
+			body:           `{"queries":[{"type":"traces","query":"{}","since":"1h"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid type",
+		},
+		{
+			name:           "invalid query",
+			f:              withCache,
+			method:         http.MethodPost,
+			body:           `{"queries":[{"type":"search","query":"{ .foo = }","since":"1h"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid query",
+		},
+		{
+			name:           "since and start",
+			f:              withCache,
+			method:         http.MethodPost,
+			body:           `{"queries":[{"type":"search","query":"{}","since":"1h","start":10}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "since can't be combined",
+		},
+		{
+			name:           "end before start",
+			f:              withCache,
+			method:         http.MethodPost,
+			body:           `{"queries":[{"type":"search","query":"{}","start":20,"end":10}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "start must be before end",
+		},
+		{
+			name:           "step on search",
+			f:              withCache,
+			method:         http.MethodPost,
+			body:           `{"queries":[{"type":"search","query":"{}","since":"1h","step":"1m"}]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "step is only supported",
+		},
+		{
+			name:           "accepted",
+			f:              withCache,
+			method:         http.MethodPost,
+			body:           `{"queries":[{"type":"metrics","query":"{} | rate()","since":"1h","step":"1m"}]}`,
+			expectedStatus: http.StatusAccepted,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/api/cache/warm", strings.NewReader(tc.body))
+			req = req.WithContext(user.InjectOrgID(req.Context(), "foo"))
+
+			respWriter := httptest.NewRecorder()
+			tc.f.CacheWarmingHandler.ServeHTTP(respWriter, req)
+
+			resp := respWriter.Result()
+			require.Equal(t, tc.expectedStatus, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Contains(t, string(body), tc.expectedBody)
+		})
+	}
+}
+
+func TestCacheWarmingPopulatesCache(t *testing.T) {
+	tenant := "foo"
+	meta := &backend.BlockMeta{
+		StartTime:    time.Unix(15, 0),
+		EndTime:      time.Unix(16, 0),
+		Size_:        defaultTargetBytesPerRequest,
+		TotalRecords: 1,
+		BlockID:      backend.MustParse("00000000-0000-0000-0000-000000000123"),
+	}
+
+	c := test.NewMockClient()
+	p := test.NewMockProvider()
+	require.NoError(t, p.AddCache(cache.RoleFrontendSearch, c))
+
+	f := frontendWithSettings(t, nil, &mockReader{metas: []*backend.BlockMeta{meta}}, nil, p, func(c *Config) {
+		c.Search.Sharder.DefaultLimit = 5
+	})
+
+	// the default limit is applied the same way as for a search request without a limit
+	hash := hashForSearchRequest(&tempopb.SearchRequest{Query: "{}", Limit: 5, SpansPerSpanSet: 2})
+	cacheKey := searchJobCacheKey(tenant, hash, 10, 20, meta, 0, 1)
+
+	_, bufs, _ := c.Fetch(context.Background(), []string{cacheKey})
+	require.Empty(t, bufs)
+
+	body := `{"queries":[{"type":"search","query":"{}","start":10,"end":20,"spss":2}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/cache/warm", strings.NewReader(body))
+	req = req.WithContext(user.InjectOrgID(req.Context(), tenant))
+
+	respWriter := httptest.NewRecorder()
+	f.CacheWarmingHandler.ServeHTTP(respWriter, req)
+	require.Equal(t, http.StatusAccepted, respWriter.Result().StatusCode)
+
+	require.Eventually(t, func() bool {
+		_, bufs, _ := c.Fetch(context.Background(), []string{cacheKey})
+		return len(bufs) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -26,6 +26,20 @@ type PipelineResponse interface {
 	RequestData() any
 }
 
+// TenantResponse is implemented by responses of multi-tenant queries. Tenant returns the tenant the response
+// was requested for.
+type TenantResponse interface {
+	Tenant() string
+}
+
+// tenantOf returns the tenant of a response of a multi-tenant query or "" otherwise.
+func tenantOf(resp PipelineResponse) string {
+	if t, ok := resp.(TenantResponse); ok {
+		return t.Tenant()
+	}
+	return ""
+}
+
 type genericCombiner[T TResponse] struct {
 	mu sync.Mutex
 
@@ -1,7 +1,9 @@
 package combiner
 
 import (
+	"fmt"
 	"math"
+	"net/http"
 	"slices"
 	"sort"
 	"strings"
@@ -13,8 +15,9 @@ import (
 
 var _ GRPCCombiner[*tempopb.QueryRangeResponse] = (*genericCombiner[*tempopb.QueryRangeResponse])(nil)
 
-// NewQueryRange returns a query range combiner.
-func NewQueryRange(req *tempopb.QueryRangeRequest) (Combiner, error) {
+// NewQueryRange returns a query range combiner. If maxSeries is greater than 0 the combiner
+// fails the query with a 400 once the combined response has more series than that.
+func NewQueryRange(req *tempopb.QueryRangeRequest, maxSeries int) (Combiner, error) {
 	combiner, err := traceql.QueryRangeCombinerFor(req, traceql.AggregateModeFinal)
 	if err != nil {
 		return nil, err
@@ -22,7 +25,8 @@ func NewQueryRange(req *tempopb.QueryRangeRequest) (Combiner, error) {
 
 	var prevResp *tempopb.QueryRangeResponse
 
-	c := &genericCombiner[*tempopb.QueryRangeResponse]{
+	var c *genericCombiner[*tempopb.QueryRangeResponse]
+	c = &genericCombiner[*tempopb.QueryRangeResponse]{
 		httpStatusCode: 200,
 		new:            func() *tempopb.QueryRangeResponse { return &tempopb.QueryRangeResponse{} },
 		current:        &tempopb.QueryRangeResponse{Metrics: &tempopb.SearchMetrics{}},
@@ -38,6 +42,15 @@ func NewQueryRange(req *tempopb.QueryRangeRequest) (Combiner, error) {
 
 			combiner.Combine(partial)
 
+			if maxSeries > 0 {
+				if series := combiner.SeriesCount(); series > maxSeries {
+					// the combine func is called under lock so it's safe to set the response code.
+					// a 4xx makes the combiner quit so no further responses are combined.
+					c.httpStatusCode = http.StatusBadRequest
+					c.httpRespBody = fmt.Sprintf("query exceeds the maximum number of series (%d > %d), reduce the number of by() attributes or use the by_missing=\"skip\" hint", series, maxSeries)
+				}
+			}
+
 			return nil
 		},
 		finalize: func(_ *tempopb.QueryRangeResponse) (*tempopb.QueryRangeResponse, error) {
@@ -72,8 +85,8 @@ func NewQueryRange(req *tempopb.QueryRangeRequest) (Combiner, error) {
 	return c, nil
 }
 
-func NewTypedQueryRange(req *tempopb.QueryRangeRequest) (GRPCCombiner[*tempopb.QueryRangeResponse], error) {
-	c, err := NewQueryRange(req)
+func NewTypedQueryRange(req *tempopb.QueryRangeRequest, maxSeries int) (GRPCCombiner[*tempopb.QueryRangeResponse], error) {
+	c, err := NewQueryRange(req, maxSeries)
 	if err != nil {
 		return nil, err
 	}
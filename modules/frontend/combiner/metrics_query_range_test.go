@@ -397,6 +397,51 @@ func TestDiffSeries(t *testing.T) {
 	}
 }
 
+func TestQueryRangeMaxSeries(t *testing.T) {
+	req := &tempopb.QueryRangeRequest{
+		Query: "{} | rate() by (span.foo)",
+		Start: uint64(10 * time.Second),
+		End:   uint64(20 * time.Second),
+		Step:  uint64(10 * time.Second),
+	}
+	samples := []tempopb.Sample{{TimestampMs: 10_000, Value: 1}}
+
+	for _, tc := range []struct {
+		name       string
+		maxSeries  int
+		expectedOK bool
+	}{
+		{name: "unlimited", maxSeries: 0, expectedOK: true},
+		{name: "within limit", maxSeries: 3, expectedOK: true},
+		{name: "exceeds limit", maxSeries: 2, expectedOK: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewTypedQueryRange(req, tc.maxSeries)
+			require.NoError(t, err)
+
+			for _, resp := range []*tempopb.QueryRangeResponse{
+				{Series: []*tempopb.TimeSeries{ts(samples, nil, "span.foo", "a"), ts(samples, nil, "span.foo", "b")}},
+				{Series: []*tempopb.TimeSeries{ts(samples, nil, "span.foo", "b"), ts(samples, nil, "span.foo", "c")}},
+			} {
+				require.NoError(t, c.AddResponse(toHTTPResponse(t, resp, 200)))
+			}
+
+			httpResp, err := c.HTTPFinal()
+			require.NoError(t, err)
+			_, grpcErr := c.GRPCFinal()
+
+			if tc.expectedOK {
+				require.Equal(t, 200, httpResp.StatusCode)
+				require.NoError(t, grpcErr)
+				return
+			}
+
+			require.Equal(t, 400, httpResp.StatusCode)
+			require.ErrorContains(t, grpcErr, "query exceeds the maximum number of series (3 > 2)")
+		})
+	}
+}
+
 func TestDiffExemplars(t *testing.T) {
 	tcs := []struct {
 		name     string
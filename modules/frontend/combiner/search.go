@@ -2,6 +2,7 @@ package combiner
 
 import (
 	"sort"
+	"time"
 
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/search"
@@ -11,44 +12,94 @@ import (
 
 var _ GRPCCombiner[*tempopb.SearchResponse] = (*genericCombiner[*tempopb.SearchResponse])(nil)
 
-// NewSearch returns a search combiner
-func NewSearch(limit int) Combiner {
+// SearchShards describes one shard of the jobs of a most recent search. The sharder attaches the list of shards,
+// newest first, as request data to its metrics response. Once all jobs of a shard and of every shard before it
+// have completed, no trace that has not been seen yet can start at or after CompletedThroughSeconds.
+type SearchShards struct {
+	TotalJobs               uint32
+	CompletedThroughSeconds uint32
+}
+
+// SearchJobShard is attached as request data to every job of a most recent search. It is the index of the
+// job's shard.
+type SearchJobShard int
+
+// NewSearch returns a search combiner. If keepMostRecent is set the combiner returns the limit most recent traces
+// instead of the first limit traces found and only quits once no newer trace can be found. maxSpanSetsPerTrace bounds
+// the spansets returned for each trace, 0 is unlimited.
+func NewSearch(limit int, keepMostRecent bool, maxSpanSetsPerTrace int) Combiner {
 	metadataCombiner := traceql.NewMetadataCombiner()
+	if keepMostRecent && limit > 0 {
+		metadataCombiner = traceql.NewMostRecentMetadataCombiner(limit)
+	} else {
+		keepMostRecent = false
+	}
+	metadataCombiner.SetMaxSpanSetsPerTrace(maxSpanSetsPerTrace)
 	diffTraces := map[string]struct{}{}
+	skippedBlocks := map[string]struct{}{}
+	shards := &shardTracker{}
 
 	c := &genericCombiner[*tempopb.SearchResponse]{
 		httpStatusCode: 200,
 		new:            func() *tempopb.SearchResponse { return &tempopb.SearchResponse{} },
 		current:        &tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{}},
-		combine: func(partial *tempopb.SearchResponse, final *tempopb.SearchResponse, _ PipelineResponse) error {
+		combine: func(partial *tempopb.SearchResponse, final *tempopb.SearchResponse, resp PipelineResponse) error {
+			if keepMostRecent {
+				switch data := resp.RequestData().(type) {
+				case []SearchShards:
+					shards.addShards(data)
+				case SearchJobShard:
+					shards.addJob(int(data))
+				}
+			}
+
+			tenant := tenantOf(resp)
 			for _, t := range partial.Traces {
+				if tenant != "" {
+					t.Tenants = []string{tenant}
+				}
+
 				// if we've reached the limit and this is NOT a new trace then skip it
-				if limit > 0 &&
+				if !keepMostRecent &&
+					limit > 0 &&
 					metadataCombiner.Count() >= limit &&
 					!metadataCombiner.Exists(t.TraceID) {
 					continue
 				}
 
-				metadataCombiner.AddMetadata(t)
 				// record modified traces
-				diffTraces[t.TraceID] = struct{}{}
+				if metadataCombiner.AddMetadata(t) {
+					diffTraces[t.TraceID] = struct{}{}
+				}
+			}
+
+			// a block can be skipped by several jobs
+			for _, b := range partial.SkippedBlocks {
+				if _, ok := skippedBlocks[b]; !ok {
+					skippedBlocks[b] = struct{}{}
+					final.SkippedBlocks = append(final.SkippedBlocks, b)
+				}
 			}
 
+			final.Analysis = append(final.Analysis, partial.Analysis...)
+
 			if partial.Metrics != nil {
 				// there is a coordination with the search sharder here. normal responses
 				// will never have total jobs set, but they will have valid Inspected* values
 				// a special response is sent back from the sharder with no traces but valid Total* values
-				// if TotalJobs is nonzero then assume its the special response
+				// if TotalJobs is nonzero then assume its the special response. complete responses of remote clusters
+				// have both
 				if partial.Metrics.TotalJobs == 0 {
 					final.Metrics.CompletedJobs++
-
-					final.Metrics.InspectedBytes += partial.Metrics.InspectedBytes
-					final.Metrics.InspectedTraces += partial.Metrics.InspectedTraces
 				} else {
 					final.Metrics.TotalBlocks += partial.Metrics.TotalBlocks
 					final.Metrics.TotalJobs += partial.Metrics.TotalJobs
 					final.Metrics.TotalBlockBytes += partial.Metrics.TotalBlockBytes
+					final.Metrics.CompletedJobs += partial.Metrics.CompletedJobs
 				}
+
+				final.Metrics.InspectedBytes += partial.Metrics.InspectedBytes
+				final.Metrics.InspectedTraces += partial.Metrics.InspectedTraces
 			}
 
 			return nil
@@ -56,6 +107,8 @@ func NewSearch(limit int) Combiner {
 		finalize: func(final *tempopb.SearchResponse) (*tempopb.SearchResponse, error) {
 			// metrics are already combined on the passed in final
 			final.Traces = metadataCombiner.Metadata()
+			sort.Strings(final.SkippedBlocks)
+			sortSearchAnalysis(final.Analysis)
 
 			addRootSpanNotReceivedText(final.Traces)
 			return final, nil
@@ -63,8 +116,21 @@ func NewSearch(limit int) Combiner {
 		diff: func(current *tempopb.SearchResponse) (*tempopb.SearchResponse, error) {
 			// wipe out any existing traces and recreate from the map
 			diff := &tempopb.SearchResponse{
-				Traces:  make([]*tempopb.TraceSearchMetadata, 0, len(diffTraces)),
-				Metrics: current.Metrics,
+				Traces:        make([]*tempopb.TraceSearchMetadata, 0, len(diffTraces)),
+				Metrics:       current.Metrics,
+				SkippedBlocks: current.SkippedBlocks,
+				Analysis:      current.Analysis,
+			}
+
+			// most recent traces can still be replaced by newer ones. only send the ones that are
+			// known to be in the final results
+			var sendAfter uint64
+			if keepMostRecent && !shards.conflict {
+				ts, ok := shards.completedThroughSeconds()
+				if !ok {
+					return diff, nil
+				}
+				sendAfter = uint64(ts) * uint64(time.Second)
 			}
 
 			for _, tr := range metadataCombiner.Metadata() {
@@ -72,8 +138,12 @@ func NewSearch(limit int) Combiner {
 				if _, ok := diffTraces[tr.TraceID]; !ok {
 					continue
 				}
+				if tr.StartTimeUnixNano < sendAfter {
+					continue
+				}
 
 				diff.Traces = append(diff.Traces, tr)
+				delete(diffTraces, tr.TraceID)
 			}
 
 			sort.Slice(diff.Traces, func(i, j int) bool {
@@ -82,8 +152,11 @@ func NewSearch(limit int) Combiner {
 
 			addRootSpanNotReceivedText(diff.Traces)
 
-			// wipe out diff traces for the next time
-			clear(diffTraces)
+			// wipe out diff traces for the next time. anything left was either dropped from the combiner or
+			// is held back until it is known to be in the final results
+			if sendAfter == 0 {
+				clear(diffTraces)
+			}
 
 			return diff, nil
 		},
@@ -94,13 +167,86 @@ func NewSearch(limit int) Combiner {
 				return false
 			}
 
-			return metadataCombiner.Count() >= limit
+			if metadataCombiner.Count() < limit {
+				return false
+			}
+
+			if !keepMostRecent {
+				return true
+			}
+
+			// we have enough traces. we can stop if the oldest one is newer than every trace we haven't seen yet
+			ts, ok := shards.completedThroughSeconds()
+			if !ok {
+				return false
+			}
+			return metadataCombiner.OldestStartTimeUnixNano() >= uint64(ts)*uint64(time.Second)
 		},
 	}
 	initHTTPCombiner(c, api.HeaderAcceptJSON)
 	return c
 }
 
+// sortSearchAnalysis sorts the analysis of the searched blocks by block and page.
+func sortSearchAnalysis(analysis []*tempopb.SearchBlockAnalysis) {
+	sort.SliceStable(analysis, func(i, j int) bool {
+		if analysis[i].BlockID != analysis[j].BlockID {
+			return analysis[i].BlockID < analysis[j].BlockID
+		}
+		return analysis[i].StartPage < analysis[j].StartPage
+	})
+}
+
+// shardTracker counts the completed jobs of a most recent search to know up to what time all traces have been
+// found.
+type shardTracker struct {
+	shards    []SearchShards
+	completed []int
+
+	// the shards were sent more than once. multi-tenant searches shard each tenant separately and the job
+	// indexes can't be told apart
+	conflict bool
+}
+
+func (t *shardTracker) addShards(shards []SearchShards) {
+	if t.shards != nil {
+		t.conflict = true
+		return
+	}
+	t.shards = shards
+}
+
+func (t *shardTracker) addJob(shard int) {
+	if shard >= len(t.completed) {
+		t.completed = append(t.completed, make([]int, shard-len(t.completed)+1)...)
+	}
+	t.completed[shard]++
+}
+
+// completedThroughSeconds returns the time after which every trace has been found. 0 means the search is complete.
+// It returns false if it is not known yet.
+func (t *shardTracker) completedThroughSeconds() (uint32, bool) {
+	if t.shards == nil || t.conflict {
+		return 0, false
+	}
+
+	var (
+		ts uint32
+		ok bool
+	)
+	for i, s := range t.shards {
+		completed := 0
+		if i < len(t.completed) {
+			completed = t.completed[i]
+		}
+		if completed < int(s.TotalJobs) {
+			break
+		}
+		ts, ok = s.CompletedThroughSeconds, true
+	}
+	return ts, ok
+}
+
 func addRootSpanNotReceivedText(results []*tempopb.TraceSearchMetadata) {
 	for _, tr := range results {
 		if tr.RootServiceName == "" {
@@ -109,6 +255,6 @@ func addRootSpanNotReceivedText(results []*tempopb.TraceSearchMetadata) {
 	}
 }
 
-func NewTypedSearch(limit int) GRPCCombiner[*tempopb.SearchResponse] {
-	return NewSearch(limit).(GRPCCombiner[*tempopb.SearchResponse])
+func NewTypedSearch(limit int, keepMostRecent bool, maxSpanSetsPerTrace int) GRPCCombiner[*tempopb.SearchResponse] {
+	return NewSearch(limit, keepMostRecent, maxSpanSetsPerTrace).(GRPCCombiner[*tempopb.SearchResponse])
 }
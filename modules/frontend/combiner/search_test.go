@@ -4,6 +4,7 @@ import (
 	"io"
 	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -19,33 +20,33 @@ import (
 
 func TestSearchProgressShouldQuit(t *testing.T) {
 	// new combiner should not quit
-	c := NewSearch(0)
+	c := NewSearch(0, false, 0)
 	should := c.ShouldQuit()
 	require.False(t, should)
 
 	// 500 response should quit
-	c = NewSearch(0)
+	c = NewSearch(0, false, 0)
 	err := c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{}, 500))
 	require.NoError(t, err)
 	should = c.ShouldQuit()
 	require.True(t, should)
 
 	// 429 response should quit
-	c = NewSearch(0)
+	c = NewSearch(0, false, 0)
 	err = c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{}, 429))
 	require.NoError(t, err)
 	should = c.ShouldQuit()
 	require.True(t, should)
 
 	// unparseable body should not quit, but should return an error
-	c = NewSearch(0)
-	err = c.AddResponse(&pipelineResponse{&http.Response{Body: io.NopCloser(strings.NewReader("foo")), StatusCode: 200}})
+	c = NewSearch(0, false, 0)
+	err = c.AddResponse(&pipelineResponse{r: &http.Response{Body: io.NopCloser(strings.NewReader("foo")), StatusCode: 200}})
 	require.Error(t, err)
 	should = c.ShouldQuit()
 	require.False(t, should)
 
 	// under limit should not quit
-	c = NewSearch(2)
+	c = NewSearch(2, false, 0)
 	err = c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -58,7 +59,7 @@ func TestSearchProgressShouldQuit(t *testing.T) {
 	require.False(t, should)
 
 	// over limit should quit
-	c = NewSearch(1)
+	c = NewSearch(1, false, 0)
 	err = c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -74,11 +75,76 @@ func TestSearchProgressShouldQuit(t *testing.T) {
 	require.True(t, should)
 }
 
+func TestSearchMostRecentShouldQuit(t *testing.T) {
+	traces := func(startSeconds ...uint64) *tempopb.SearchResponse {
+		resp := &tempopb.SearchResponse{}
+		for _, s := range startSeconds {
+			resp.Traces = append(resp.Traces, &tempopb.TraceSearchMetadata{
+				TraceID:           strconv.FormatUint(s, 10),
+				StartTimeUnixNano: s * uint64(time.Second),
+			})
+		}
+		return resp
+	}
+
+	// ingesters, a block ending at 100s and a block ending at 50s
+	shards := []SearchShards{
+		{TotalJobs: 1, CompletedThroughSeconds: 101},
+		{TotalJobs: 2, CompletedThroughSeconds: 51},
+		{TotalJobs: 1},
+	}
+
+	c := NewTypedSearch(2, true, 0)
+	require.NoError(t, c.AddResponse(toHTTPResponseWithRequestData(t, &tempopb.SearchResponse{Metrics: &tempopb.SearchMetrics{TotalJobs: 4}}, 200, shards)))
+
+	// the limit is reached, but the ingesters are not done
+	require.NoError(t, c.AddResponse(toHTTPResponseWithRequestData(t, traces(80, 90), 200, SearchJobShard(1))))
+	require.False(t, c.ShouldQuit())
+
+	// nothing is sent until the results are known to be final
+	diff, err := c.GRPCDiff()
+	require.NoError(t, err)
+	require.Empty(t, diff.Traces)
+
+	// newer traces replace the older ones. the first block may still hold traces newer than 95s
+	require.NoError(t, c.AddResponse(toHTTPResponseWithRequestData(t, traces(95, 200), 200, SearchJobShard(0))))
+	require.False(t, c.ShouldQuit())
+
+	diff, err = c.GRPCDiff()
+	require.NoError(t, err)
+	require.Equal(t, []string{"200"}, traceIDs(diff.Traces))
+
+	// the first block is done, nothing can be newer than 51s
+	require.NoError(t, c.AddResponse(toHTTPResponseWithRequestData(t, traces(), 200, SearchJobShard(1))))
+	require.True(t, c.ShouldQuit())
+
+	diff, err = c.GRPCDiff()
+	require.NoError(t, err)
+	require.Equal(t, []string{"95"}, traceIDs(diff.Traces))
+
+	final, err := c.GRPCFinal()
+	require.NoError(t, err)
+	require.Equal(t, []string{"200", "95"}, traceIDs(final.Traces))
+
+	// without shards the combiner can't quit early
+	c = NewTypedSearch(1, true, 0)
+	require.NoError(t, c.AddResponse(toHTTPResponseWithRequestData(t, traces(10), 200, SearchJobShard(0))))
+	require.False(t, c.ShouldQuit())
+}
+
+func traceIDs(traces []*tempopb.TraceSearchMetadata) []string {
+	ids := make([]string, 0, len(traces))
+	for _, tr := range traces {
+		ids = append(ids, tr.TraceID)
+	}
+	return ids
+}
+
 func TestSearchCombinesResults(t *testing.T) {
 	start := time.Date(1, 2, 3, 4, 5, 6, 7, time.UTC)
 	traceID := "traceID"
 
-	c := NewSearch(10)
+	c := NewSearch(10, false, 0)
 	sr := toHTTPResponse(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -125,6 +191,94 @@ func TestSearchCombinesResults(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestSearchCombinesSkippedBlocks(t *testing.T) {
+	c := NewSearch(10, false, 0)
+
+	for _, skipped := range [][]string{{"b"}, nil, {"a", "b"}} {
+		err := c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
+			Metrics:       &tempopb.SearchMetrics{},
+			SkippedBlocks: skipped,
+		}, 200))
+		require.NoError(t, err)
+	}
+
+	resp, err := c.HTTPFinal()
+	require.NoError(t, err)
+
+	actual := &tempopb.SearchResponse{}
+	fromHTTPResponse(t, resp, actual)
+
+	require.Equal(t, &tempopb.SearchResponse{
+		Traces: []*tempopb.TraceSearchMetadata{},
+		Metrics: &tempopb.SearchMetrics{
+			CompletedJobs: 3,
+		},
+		SkippedBlocks: []string{"a", "b"},
+	}, actual)
+}
+
+func TestSearchCombinesAnalysis(t *testing.T) {
+	c := NewSearch(10, false, 0)
+
+	for _, analysis := range [][]*tempopb.SearchBlockAnalysis{
+		{{BlockID: "b", StartPage: 10, FetchNanos: 1}},
+		nil,
+		{{BlockID: "b", FetchNanos: 2}, {BlockID: "a", FilterNanos: 3}},
+	} {
+		err := c.AddResponse(toHTTPResponse(t, &tempopb.SearchResponse{
+			Metrics:  &tempopb.SearchMetrics{},
+			Analysis: analysis,
+		}, 200))
+		require.NoError(t, err)
+	}
+
+	resp, err := c.HTTPFinal()
+	require.NoError(t, err)
+
+	actual := &tempopb.SearchResponse{}
+	fromHTTPResponse(t, resp, actual)
+
+	require.Equal(t, []*tempopb.SearchBlockAnalysis{
+		{BlockID: "a", FilterNanos: 3},
+		{BlockID: "b", FetchNanos: 2},
+		{BlockID: "b", StartPage: 10, FetchNanos: 1},
+	}, actual.Analysis)
+}
+
+func TestSearchAddsTenants(t *testing.T) {
+	c := NewSearch(10, false, 0)
+
+	for _, tenant := range []string{"tenant-b", "tenant-a"} {
+		err := c.AddResponse(&tenantPipelineResponse{
+			PipelineResponse: toHTTPResponse(t, &tempopb.SearchResponse{
+				Traces: []*tempopb.TraceSearchMetadata{
+					{TraceID: "shared", RootServiceName: "svc"},
+					{TraceID: tenant, RootServiceName: "svc"},
+				},
+				Metrics: &tempopb.SearchMetrics{},
+			}, 200),
+			tenant: tenant,
+		})
+		require.NoError(t, err)
+	}
+
+	resp, err := c.HTTPFinal()
+	require.NoError(t, err)
+
+	actual := &tempopb.SearchResponse{}
+	fromHTTPResponse(t, resp, actual)
+
+	tenants := map[string][]string{}
+	for _, tr := range actual.Traces {
+		tenants[tr.TraceID] = tr.Tenants
+	}
+	require.Equal(t, map[string][]string{
+		"shared":   {"tenant-a", "tenant-b"},
+		"tenant-a": {"tenant-a"},
+		"tenant-b": {"tenant-b"},
+	}, tenants)
+}
+
 func TestSearchResponseCombiner(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -291,7 +445,7 @@ func TestSearchResponseCombiner(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			combiner := NewTypedSearch(20)
+			combiner := NewTypedSearch(20, false, 0)
 
 			err := combiner.AddResponse(tc.response1)
 			require.NoError(t, err)
@@ -312,7 +466,7 @@ func TestSearchResponseCombiner(t *testing.T) {
 func TestSearchDiffsResults(t *testing.T) {
 	traceID := "traceID"
 
-	c := NewTypedSearch(10)
+	c := NewTypedSearch(10, false, 0)
 	sr := toHTTPResponse(t, &tempopb.SearchResponse{
 		Traces: []*tempopb.TraceSearchMetadata{
 			{
@@ -387,7 +541,8 @@ func TestSearchDiffsResults(t *testing.T) {
 }
 
 type pipelineResponse struct {
-	r *http.Response
+	r           *http.Response
+	requestData any
 }
 
 func (p *pipelineResponse) HTTPResponse() *http.Response {
@@ -395,7 +550,16 @@ func (p *pipelineResponse) HTTPResponse() *http.Response {
 }
 
 func (p *pipelineResponse) RequestData() any {
-	return nil
+	return p.requestData
+}
+
+type tenantPipelineResponse struct {
+	PipelineResponse
+	tenant string
+}
+
+func (p *tenantPipelineResponse) Tenant() string {
+	return p.tenant
 }
 
 func toHTTPResponse(t *testing.T, pb proto.Message, statusCode int) PipelineResponse {
@@ -408,19 +572,25 @@ func toHTTPResponse(t *testing.T, pb proto.Message, statusCode int) PipelineResp
 		require.NoError(t, err)
 	}
 
-	return &pipelineResponse{&http.Response{
+	return &pipelineResponse{r: &http.Response{
 		Body:       io.NopCloser(strings.NewReader(body)),
 		StatusCode: statusCode,
 	}}
 }
 
+func toHTTPResponseWithRequestData(t *testing.T, pb proto.Message, statusCode int, requestData any) PipelineResponse {
+	resp := toHTTPResponse(t, pb, statusCode).(*pipelineResponse)
+	resp.requestData = requestData
+	return resp
+}
+
 func fromHTTPResponse(t *testing.T, r *http.Response, pb proto.Message) {
 	err := jsonpb.Unmarshal(r.Body, pb)
 	require.NoError(t, err)
 }
 
 func TestCombinerDiffs(t *testing.T) {
-	combiner := NewTypedSearch(100)
+	combiner := NewTypedSearch(100, false, 0)
 
 	// first request should be empty
 	resp, err := combiner.GRPCDiff()
@@ -563,7 +733,7 @@ func TestSearchCombinerDoesNotRace(t *testing.T) {
 	}
 
 	traceID := "1234"
-	combiner := NewTypedSearch(10)
+	combiner := NewTypedSearch(10, false, 0)
 	i := 0
 	go concurrent(func() {
 		i++
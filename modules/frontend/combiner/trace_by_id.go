@@ -14,10 +14,16 @@ import (
 	tempo_io "github.com/grafana/tempo/pkg/io"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
 )
 
 const (
 	internalErrorMsg = "internal error"
+
+	// TenantAttribute is the resource attribute added to the resources of traces returned by multi-tenant
+	// queries. Its value is the tenant the resource was found in.
+	TenantAttribute = "tempo.tenant"
 )
 
 type traceByIDCombiner struct {
@@ -83,6 +89,8 @@ func (c *traceByIDCombiner) AddResponse(r PipelineResponse) error {
 		return fmt.Errorf("error unmarshalling response body: %w", err)
 	}
 
+	addTenantAttribute(resp.Trace, tenantOf(r))
+
 	// Consume the trace
 	_, err = c.c.Consume(resp.Trace)
 
@@ -178,3 +186,20 @@ func (c *traceByIDCombiner) shouldQuit() bool {
 	// 2xx and 404 are OK
 	return false
 }
+
+// addTenantAttribute adds the tenant of a multi-tenant query to all resources of the trace
+func addTenantAttribute(tr *tempopb.Trace, tenant string) {
+	if tr == nil || tenant == "" {
+		return
+	}
+
+	for _, rs := range tr.ResourceSpans {
+		if rs.Resource == nil {
+			rs.Resource = &v1_resource.Resource{}
+		}
+		rs.Resource.Attributes = append(rs.Resource.Attributes, &v1_common.KeyValue{
+			Key:   TenantAttribute,
+			Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: tenant}},
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package combiner
+
+import (
+	"fmt"
+
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+type streamedSpan struct {
+	id   string
+	kind v1.Span_SpanKind
+}
+
+// NewTypedTraceByIDStreaming returns a combiner for streaming trace by id lookups. Instead of buffering the whole
+// trace every diff contains the spans that were combined since the previous diff. Only the ids of the spans that were
+// already returned are kept, spans that are returned by more than one job are dropped.
+func NewTypedTraceByIDStreaming(maxBytes int) GRPCCombiner[*tempopb.TraceByIDResponse] {
+	seen := map[streamedSpan]struct{}{}
+	totalBytes := 0
+	exceeded := false
+
+	c := &genericCombiner[*tempopb.TraceByIDResponse]{
+		combine: func(partial *tempopb.TraceByIDResponse, current *tempopb.TraceByIDResponse, resp PipelineResponse) error {
+			if partial.Status == tempopb.TraceByIDResponse_PARTIAL {
+				current.Status = partial.Status
+				current.Message = partial.Message
+			}
+			if partial.Trace == nil {
+				return nil
+			}
+			addTenantAttribute(partial.Trace, tenantOf(resp))
+
+			for _, rs := range partial.Trace.ResourceSpans {
+				scopeSpans := rs.ScopeSpans[:0]
+				for _, ss := range rs.ScopeSpans {
+					spans := ss.Spans[:0]
+					for _, s := range ss.Spans {
+						key := streamedSpan{id: string(s.SpanId), kind: s.Kind}
+						if _, ok := seen[key]; ok {
+							continue
+						}
+
+						size := s.Size()
+						if maxBytes > 0 && totalBytes+size > maxBytes {
+							exceeded = true
+							continue
+						}
+						totalBytes += size
+						seen[key] = struct{}{}
+
+						spans = append(spans, s)
+					}
+					if len(spans) > 0 {
+						ss.Spans = spans
+						scopeSpans = append(scopeSpans, ss)
+					}
+				}
+				if len(scopeSpans) > 0 {
+					rs.ScopeSpans = scopeSpans
+					current.Trace.ResourceSpans = append(current.Trace.ResourceSpans, rs)
+				}
+			}
+
+			if exceeded {
+				current.Status = tempopb.TraceByIDResponse_PARTIAL
+				current.Message = fmt.Sprintf("Trace exceeds maximum size of %d bytes, a partial trace is returned", maxBytes)
+			}
+
+			return nil
+		},
+		finalize: func(current *tempopb.TraceByIDResponse) (*tempopb.TraceByIDResponse, error) {
+			return current, nil
+		},
+		diff: func(current *tempopb.TraceByIDResponse) (*tempopb.TraceByIDResponse, error) {
+			diff := &tempopb.TraceByIDResponse{
+				Trace:   current.Trace,
+				Status:  current.Status,
+				Message: current.Message,
+			}
+			current.Trace = &tempopb.Trace{}
+			return diff, nil
+		},
+		quit: func(*tempopb.TraceByIDResponse) bool {
+			return exceeded
+		},
+		new:     func() *tempopb.TraceByIDResponse { return &tempopb.TraceByIDResponse{} },
+		current: &tempopb.TraceByIDResponse{Trace: &tempopb.Trace{}},
+	}
+	initHTTPCombiner(c, api.HeaderAcceptProtobuf)
+	return c
+}
@@ -0,0 +1,67 @@
+package combiner
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceByIDStreamingDiffs(t *testing.T) {
+	tr := test.MakeTrace(2, []byte{0x01, 0x02})
+	spans := countSpans(tr)
+
+	c := NewTypedTraceByIDStreaming(0)
+
+	err := c.AddResponse(traceByIDStreamingResponse(t, &tempopb.TraceByIDResponse{Trace: proto.Clone(tr).(*tempopb.Trace)}))
+	require.NoError(t, err)
+
+	diff, err := c.GRPCDiff()
+	require.NoError(t, err)
+	require.Equal(t, spans, countSpans(diff.Trace))
+
+	// spans that were already sent are dropped
+	err = c.AddResponse(traceByIDStreamingResponse(t, &tempopb.TraceByIDResponse{Trace: proto.Clone(tr).(*tempopb.Trace)}))
+	require.NoError(t, err)
+
+	diff, err = c.GRPCDiff()
+	require.NoError(t, err)
+	require.Equal(t, 0, countSpans(diff.Trace))
+	require.Equal(t, tempopb.TraceByIDResponse_COMPLETE, diff.Status)
+}
+
+func TestTraceByIDStreamingMaxBytes(t *testing.T) {
+	tr := test.MakeTrace(10, []byte{0x01, 0x02})
+	maxBytes := tr.ResourceSpans[0].ScopeSpans[0].Spans[0].Size()
+
+	c := NewTypedTraceByIDStreaming(maxBytes)
+
+	err := c.AddResponse(traceByIDStreamingResponse(t, &tempopb.TraceByIDResponse{Trace: tr}))
+	require.NoError(t, err)
+	require.True(t, c.ShouldQuit())
+
+	diff, err := c.GRPCDiff()
+	require.NoError(t, err)
+	require.Equal(t, 1, countSpans(diff.Trace))
+	require.Equal(t, tempopb.TraceByIDResponse_PARTIAL, diff.Status)
+}
+
+func traceByIDStreamingResponse(t *testing.T, resp *tempopb.TraceByIDResponse) PipelineResponse {
+	r := toHTTPProtoResponse(t, resp, 200)
+	r.HTTPResponse().Header = http.Header{api.HeaderContentType: {api.HeaderAcceptProtobuf}}
+	return r
+}
+
+func countSpans(tr *tempopb.Trace) int {
+	n := 0
+	for _, rs := range tr.GetResourceSpans() {
+		for _, ss := range rs.ScopeSpans {
+			n += len(ss.Spans)
+		}
+	}
+	return n
+}
@@ -43,7 +43,7 @@ func TestTraceByIDShouldQuit(t *testing.T) {
 
 	// unparseable body should not quit, but should return an error
 	c = NewTraceByID(0, api.HeaderAcceptJSON)
-	err = c.AddResponse(&pipelineResponse{&http.Response{Body: io.NopCloser(strings.NewReader("foo")), StatusCode: 200}})
+	err = c.AddResponse(&pipelineResponse{r: &http.Response{Body: io.NopCloser(strings.NewReader("foo")), StatusCode: 200}})
 	require.Error(t, err)
 	should = c.ShouldQuit()
 	require.False(t, should)
@@ -92,6 +92,31 @@ func TestTraceByIDHonorsContentType(t *testing.T) {
 	require.Equal(t, expected, actual)
 }
 
+func TestTraceByIDTagsTenant(t *testing.T) {
+	tr := test.MakeTrace(2, nil)
+
+	c := NewTraceByID(0, api.HeaderAcceptProtobuf)
+	err := c.AddResponse(&tenantPipelineResponse{
+		PipelineResponse: toHTTPProtoResponse(t, &tempopb.TraceByIDResponse{Trace: tr}, 200),
+		tenant:           "tenant-a",
+	})
+	require.NoError(t, err)
+
+	resp, err := c.HTTPFinal()
+	require.NoError(t, err)
+
+	actual := &tempopb.Trace{}
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	require.NoError(t, proto.Unmarshal(bodyBytes, actual))
+
+	require.NotEmpty(t, actual.ResourceSpans)
+	for _, rs := range actual.ResourceSpans {
+		attr := rs.Resource.Attributes[len(rs.Resource.Attributes)-1]
+		require.Equal(t, TenantAttribute, attr.Key)
+		require.Equal(t, "tenant-a", attr.Value.GetStringValue())
+	}
+}
+
 func toHTTPProtoResponse(t *testing.T, pb proto.Message, statusCode int) PipelineResponse {
 	var body []byte
 
@@ -101,7 +126,7 @@ func toHTTPProtoResponse(t *testing.T, pb proto.Message, statusCode int) Pipelin
 		require.NoError(t, err)
 	}
 
-	return &pipelineResponse{&http.Response{
+	return &pipelineResponse{r: &http.Response{
 		Body:       io.NopCloser(bytes.NewReader(body)),
 		StatusCode: statusCode,
 	}}
@@ -11,10 +11,11 @@ func NewTraceByIDV2(maxBytes int, marshalingFormat string) Combiner {
 	combiner := trace.NewCombiner(maxBytes, true)
 	var partialTrace bool
 	gc := &genericCombiner[*tempopb.TraceByIDResponse]{
-		combine: func(partial *tempopb.TraceByIDResponse, _ *tempopb.TraceByIDResponse, _ PipelineResponse) error {
+		combine: func(partial *tempopb.TraceByIDResponse, _ *tempopb.TraceByIDResponse, resp PipelineResponse) error {
 			if partial.Status == tempopb.TraceByIDResponse_PARTIAL {
 				partialTrace = true
 			}
+			addTenantAttribute(partial.Trace, tenantOf(resp))
 			_, err := combiner.Consume(partial.Trace)
 			return err
 		},
@@ -9,6 +9,7 @@ import (
 
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	v1 "github.com/grafana/tempo/modules/frontend/v1"
+	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/usagestats"
 )
 
@@ -23,6 +24,11 @@ type Config struct {
 	MultiTenantQueriesEnabled bool                   `yaml:"multi_tenant_queries_enabled"`
 	ResponseConsumers         int                    `yaml:"response_consumers"`
 	Weights                   pipeline.WeightsConfig `yaml:"weights"`
+	CacheWarming              CacheWarmingConfig     `yaml:"cache_warming"`
+	Audit                     AuditConfig            `yaml:"audit"`
+	UI                        UIConfig               `yaml:"ui"`
+	Tail                      TailConfig             `yaml:"tail"`
+	RemoteClusters            RemoteClustersConfig   `yaml:"remote_clusters"`
 	// the maximum time limit that tempo will work on an api request. this includes both
 	// grpc and http requests and applies to all "api" frontend query endpoints such as
 	// traceql, tag search, tag value search, trace by id and all streaming gRPC endpoints.
@@ -44,6 +50,20 @@ type SearchConfig struct {
 	Sharder     SearchSharderConfig `yaml:",inline"`
 	SLO         SLOConfig           `yaml:",inline"`
 	MetadataSLO SLOConfig           `yaml:"metadata_slo,omitempty"`
+
+	ResultsCache SearchResultsCacheConfig `yaml:"results_cache,omitempty"`
+}
+
+// SearchResultsCacheConfig configures the cache of complete search responses. It is used if a cache with the
+// frontend-search-results role is configured.
+type SearchResultsCacheConfig struct {
+	// TTL is how long a response is served from the cache. The tenant override search_results_cache_ttl takes
+	// precedence. 0 disables the cache.
+	TTL time.Duration `yaml:"ttl,omitempty"`
+	// TimeAlignment is the interval the start and end of searches are aligned to, so searches of a moving time range
+	// share cache entries. Traces outside the searched range are removed from the responses. If the limit may have cut
+	// off traces of the searched range, the searched range is searched again without alignment. 0 disables the alignment.
+	TimeAlignment time.Duration `yaml:"time_alignment,omitempty"`
 }
 
 type TraceByIDConfig struct {
@@ -57,6 +77,11 @@ type MetricsConfig struct {
 	SLO     SLOConfig               `yaml:",inline"`
 }
 
+// UIConfig configures the built-in web UI for search and trace viewing.
+type UIConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
 type SLOConfig struct {
 	DurationSLO        time.Duration `yaml:"duration_slo,omitempty"`
 	ThroughputBytesSLO float64       `yaml:"throughput_bytes_slo,omitempty"`
@@ -70,6 +95,11 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 
 	cfg.Config.MaxOutstandingPerTenant = 2000
 	cfg.Config.MaxBatchSize = 5
+	cfg.Config.SmallJobBatchFactor = 10
+	cfg.Config.QoS = v1.QoSConfig{
+		InteractiveSearchMaxRange: time.Hour,
+		StarvationLimit:           10,
+	}
 	cfg.MaxRetries = 2
 	cfg.ResponseConsumers = 10
 	cfg.Search = SearchConfig{
@@ -85,6 +115,10 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 			MaxSpansPerSpanSet:    100,
 		},
 		SLO: slo,
+		ResultsCache: SearchResultsCacheConfig{
+			TTL:           time.Minute,
+			TimeAlignment: time.Minute,
+		},
 	}
 	cfg.TraceByID = TraceByIDConfig{
 		QueryShards: 50,
@@ -108,6 +142,26 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 		MaxTraceQLConditions: 4,
 	}
 
+	cfg.Audit = AuditConfig{
+		UserHeader: "X-Grafana-User",
+		Output:     AuditOutputFile,
+	}
+
+	cfg.CacheWarming = CacheWarmingConfig{
+		ConcurrentJobs:     50,
+		MaxPendingRequests: 10,
+		MaxQueries:         100,
+		QueryTimeout:       5 * time.Minute,
+	}
+
+	cfg.Tail = TailConfig{
+		Enabled:           false,
+		PollInterval:      time.Second,
+		Lookback:          time.Minute,
+		MaxDuration:       time.Hour,
+		MaxTailsPerTenant: 10,
+	}
+
 	// set default max query size to 128 KiB, queries larger than this will be rejected
 	cfg.MaxQueryExpressionSizeBytes = 128 * 1024
 	// enable multi tenant queries by default
@@ -121,10 +175,10 @@ type CortexNoQuerierLimits struct{}
 // Returned RoundTripper can be wrapped in more round-tripper middlewares, and then eventually registered
 // into HTTP server using the Handler from this package. Returned RoundTripper is always non-nil
 // (if there are no errors), and it uses the returned frontend (if any).
-func InitFrontend(cfg v1.Config, log log.Logger, reg prometheus.Registerer) (pipeline.RoundTripper, *v1.Frontend, error) {
+func InitFrontend(cfg v1.Config, o overrides.Interface, log log.Logger, reg prometheus.Registerer) (pipeline.RoundTripper, *v1.Frontend, error) {
 	statVersion.Set("v1")
 	// No scheduler = use original frontend.
-	fr, err := v1.New(cfg, log, reg)
+	fr, err := v1.New(cfg, o, log, reg)
 	if err != nil {
 		return nil, nil, err
 	}
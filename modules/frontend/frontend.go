@@ -1,6 +1,7 @@
 package frontend
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -40,20 +41,28 @@ type (
 	streamingTagValuesV2Handler  func(req *tempopb.SearchTagValuesRequest, srv tempopb.StreamingQuerier_SearchTagValuesV2Server) error
 	streamingQueryRangeHandler   func(req *tempopb.QueryRangeRequest, srv tempopb.StreamingQuerier_MetricsQueryRangeServer) error
 	streamingQueryInstantHandler func(req *tempopb.QueryInstantRequest, srv tempopb.StreamingQuerier_MetricsQueryInstantServer) error
+	streamingTailHandler         func(req *tempopb.SearchRequest, srv tempopb.Tail_TailServer) error
+	traceByIDHandler             func(ctx context.Context, req *tempopb.TraceByIDRequest) (*tempopb.TraceByIDResponse, error)
+	streamingTraceByIDHandler    func(req *tempopb.TraceByIDRequest, srv tempopb.TempoQuery_StreamTraceByIDServer) error
 )
 
 type QueryFrontend struct {
-	TraceByIDHandler, TraceByIDHandlerV2, SearchHandler, MetricsSummaryHandler, MetricsQueryInstantHandler, MetricsQueryRangeHandler http.Handler
-	SearchTagsHandler, SearchTagsV2Handler, SearchTagsValuesHandler, SearchTagsValuesV2Handler                                       http.Handler
-	cacheProvider                                                                                                                    cache.Provider
-	streamingSearch                                                                                                                  streamingSearchHandler
-	streamingTags                                                                                                                    streamingTagsHandler
-	streamingTagsV2                                                                                                                  streamingTagsV2Handler
-	streamingTagValues                                                                                                               streamingTagValuesHandler
-	streamingTagValuesV2                                                                                                             streamingTagValuesV2Handler
-	streamingQueryRange                                                                                                              streamingQueryRangeHandler
-	streamingQueryInstant                                                                                                            streamingQueryInstantHandler
-	logger                                                                                                                           log.Logger
+	TraceByIDHandler, TraceByIDHandlerV2, SearchHandler, SearchSpansHandler, MetricsSummaryHandler, MetricsQueryInstantHandler, MetricsQueryRangeHandler http.Handler
+	SearchTagsHandler, SearchTagsV2Handler, SearchTagsValuesHandler, SearchTagsValuesV2Handler                                                           http.Handler
+	CacheWarmingHandler, TailHandler                                                                                                                     http.Handler
+	cacheProvider                                                                                                                                        cache.Provider
+	streamingSearch                                                                                                                                      streamingSearchHandler
+	streamingTags                                                                                                                                        streamingTagsHandler
+	streamingTagsV2                                                                                                                                      streamingTagsV2Handler
+	streamingTagValues                                                                                                                                   streamingTagValuesHandler
+	streamingTagValuesV2                                                                                                                                 streamingTagValuesV2Handler
+	streamingQueryRange                                                                                                                                  streamingQueryRangeHandler
+	streamingQueryInstant                                                                                                                                streamingQueryInstantHandler
+	streamingTail                                                                                                                                        streamingTailHandler
+	traceByID                                                                                                                                            traceByIDHandler
+	streamingTraceByID                                                                                                                                   streamingTraceByIDHandler
+	audit                                                                                                                                                *auditLogger
+	logger                                                                                                                                               log.Logger
 }
 
 var tracer = otel.Tracer("modules/frontend")
@@ -62,8 +71,8 @@ var tracer = otel.Tracer("modules/frontend")
 func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader tempodb.Reader, cacheProvider cache.Provider, apiPrefix string, logger log.Logger, registerer prometheus.Registerer) (*QueryFrontend, error) {
 	level.Info(logger).Log("msg", "creating middleware in query frontend")
 
-	if cfg.TraceByID.QueryShards < minQueryShards || cfg.TraceByID.QueryShards > maxQueryShards {
-		return nil, fmt.Errorf("frontend query shards should be between %d and %d (both inclusive)", minQueryShards, maxQueryShards)
+	if err := ValidateQueryShards(cfg.TraceByID.QueryShards); err != nil {
+		return nil, err
 	}
 
 	if cfg.Search.Sharder.ConcurrentRequests <= 0 {
@@ -90,6 +99,23 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 		return nil, fmt.Errorf("frontend metrics interval should be greater than 0")
 	}
 
+	if err := cfg.Audit.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Tail.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.RemoteClusters.Validate(); err != nil {
+		return nil, err
+	}
+
+	audit, err := newAuditLogger(cfg.Audit, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	retryWare := pipeline.NewRetryWare(cfg.MaxRetries, cfg.Weights.RetryWithWeights, registerer)
 	cacheWare := pipeline.NewCachingWare(cacheProvider, cache.RoleFrontendSearch, logger)
 	statusCodeWare := pipeline.NewStatusCodeAdjustWare()
@@ -97,6 +123,7 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 	urlDenyListWare := pipeline.NewURLDenyListWare(cfg.URLDenyList)
 	queryValidatorWare := pipeline.NewQueryValidatorWare(cfg.MaxQueryExpressionSizeBytes)
 	headerStripWare := pipeline.NewStripHeadersWare(cfg.AllowedHeaders)
+	remoteClustersWare := newRemoteClustersMiddleware(cfg.RemoteClusters, remotePathWithoutPrefix(apiPrefix), http.DefaultClient, logger)
 
 	tracePipeline := pipeline.Build(
 		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
@@ -104,7 +131,9 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 			urlDenyListWare,
 			pipeline.NewWeightRequestWare(pipeline.TraceByID, cfg.Weights),
 			multiTenantMiddleware(cfg, logger),
-			newAsyncTraceIDSharder(&cfg.TraceByID, logger),
+			newRemoteClustersMiddleware(cfg.RemoteClusters, remoteTraceByIDPath, http.DefaultClient, logger),
+			pipeline.NewPriorityWare(pipeline.TraceByID, cfg.Config.QoS.Enabled, cfg.Config.QoS.InteractiveSearchMaxRange),
+			newAsyncTraceIDSharder(&cfg.TraceByID, o, logger),
 		},
 		[]pipeline.Middleware{traceIDStatusCodeWare, retryWare},
 		next)
@@ -116,6 +145,8 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 			queryValidatorWare,
 			pipeline.NewWeightRequestWare(pipeline.TraceQLSearch, cfg.Weights),
 			multiTenantMiddleware(cfg, logger),
+			remoteClustersWare,
+			pipeline.NewPriorityWare(pipeline.TraceQLSearch, cfg.Config.QoS.Enabled, cfg.Config.QoS.InteractiveSearchMaxRange),
 			newAsyncSearchSharder(reader, o, cfg.Search.Sharder, logger),
 		},
 		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
@@ -127,6 +158,8 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 			urlDenyListWare,
 			pipeline.NewWeightRequestWare(pipeline.Default, cfg.Weights),
 			multiTenantMiddleware(cfg, logger),
+			remoteClustersWare,
+			pipeline.NewPriorityWare(pipeline.Default, cfg.Config.QoS.Enabled, cfg.Config.QoS.InteractiveSearchMaxRange),
 			newAsyncTagSharder(reader, o, cfg.Search.Sharder, parseTagsRequest, logger),
 		},
 		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
@@ -138,6 +171,8 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 			urlDenyListWare,
 			pipeline.NewWeightRequestWare(pipeline.Default, cfg.Weights),
 			multiTenantMiddleware(cfg, logger),
+			remoteClustersWare,
+			pipeline.NewPriorityWare(pipeline.Default, cfg.Config.QoS.Enabled, cfg.Config.QoS.InteractiveSearchMaxRange),
 			newAsyncTagSharder(reader, o, cfg.Search.Sharder, parseTagValuesRequest, logger),
 		},
 		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
@@ -150,6 +185,7 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 			queryValidatorWare,
 			pipeline.NewWeightRequestWare(pipeline.Default, cfg.Weights),
 			multiTenantUnsupportedMiddleware(cfg, logger),
+			pipeline.NewPriorityWare(pipeline.Default, cfg.Config.QoS.Enabled, cfg.Config.QoS.InteractiveSearchMaxRange),
 		},
 		[]pipeline.Middleware{statusCodeWare, retryWare},
 		next)
@@ -162,6 +198,7 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 			queryValidatorWare,
 			pipeline.NewWeightRequestWare(pipeline.TraceQLMetrics, cfg.Weights),
 			multiTenantMiddleware(cfg, logger),
+			pipeline.NewPriorityWare(pipeline.TraceQLMetrics, cfg.Config.QoS.Enabled, cfg.Config.QoS.InteractiveSearchMaxRange),
 			newAsyncQueryRangeSharder(reader, o, cfg.Metrics.Sharder, false, logger),
 		},
 		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
@@ -174,27 +211,81 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 			queryValidatorWare,
 			pipeline.NewWeightRequestWare(pipeline.TraceQLMetrics, cfg.Weights),
 			multiTenantMiddleware(cfg, logger),
+			pipeline.NewPriorityWare(pipeline.TraceQLMetrics, cfg.Config.QoS.Enabled, cfg.Config.QoS.InteractiveSearchMaxRange),
 			newAsyncQueryRangeSharder(reader, o, cfg.Metrics.Sharder, true, logger),
 		},
 		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
 		next)
 
-	traces := newTraceIDHandler(cfg, tracePipeline, o, combiner.NewTraceByID, logger)
-	tracesV2 := newTraceIDHandler(cfg, tracePipeline, o, combiner.NewTraceByIDV2, logger)
-	search := newSearchHTTPHandler(cfg, searchPipeline, logger)
-	searchTags := newTagsHTTPHandler(cfg, searchTagsPipeline, o, logger)
-	searchTagsV2 := newTagsV2HTTPHandler(cfg, searchTagsPipeline, o, logger)
-	searchTagValues := newTagValuesHTTPHandler(cfg, searchTagValuesPipeline, o, logger)
-	searchTagValuesV2 := newTagValuesV2HTTPHandler(cfg, searchTagValuesPipeline, o, logger)
+	// live tail. every poll is a single job that searches the recent data in the ingesters, so there is no sharding
+	// and nothing is cached
+	tailPipeline := pipeline.Build(
+		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
+			headerStripWare,
+			urlDenyListWare,
+			queryValidatorWare,
+			pipeline.NewWeightRequestWare(pipeline.TraceQLSearch, cfg.Weights),
+			multiTenantUnsupportedMiddleware(cfg, logger),
+			pipeline.NewPriorityWare(pipeline.TraceQLSearch, cfg.Config.QoS.Enabled, cfg.Config.QoS.InteractiveSearchMaxRange),
+		},
+		[]pipeline.Middleware{statusCodeWare, retryWare},
+		next)
+
+	// cache warming. the pipelines are the same as the search and traceql metrics pipelines except for the job
+	// concurrency which is lowered and the qos class so warming queries have less impact on user queries
+	warmingCfg := cfg
+	if cfg.CacheWarming.ConcurrentJobs > 0 {
+		warmingCfg.Search.Sharder.ConcurrentRequests = cfg.CacheWarming.ConcurrentJobs
+		warmingCfg.Search.Sharder.lowerConcurrencyOnly = true
+		warmingCfg.Metrics.Sharder.ConcurrentRequests = cfg.CacheWarming.ConcurrentJobs
+		warmingCfg.Metrics.Sharder.lowerConcurrencyOnly = true
+	}
+
+	warmingSearchPipeline := pipeline.Build(
+		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
+			queryValidatorWare,
+			pipeline.NewWeightRequestWare(pipeline.TraceQLSearch, cfg.Weights),
+			multiTenantMiddleware(cfg, logger),
+			pipeline.NewPriorityWare(pipeline.CacheWarming, cfg.Config.QoS.Enabled, cfg.Config.QoS.InteractiveSearchMaxRange),
+			newAsyncSearchSharder(reader, o, warmingCfg.Search.Sharder, logger),
+		},
+		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
+		next)
+
+	warmingQueryRangePipeline := pipeline.Build(
+		[]pipeline.AsyncMiddleware[combiner.PipelineResponse]{
+			queryValidatorWare,
+			pipeline.NewWeightRequestWare(pipeline.TraceQLMetrics, cfg.Weights),
+			multiTenantMiddleware(cfg, logger),
+			pipeline.NewPriorityWare(pipeline.CacheWarming, cfg.Config.QoS.Enabled, cfg.Config.QoS.InteractiveSearchMaxRange),
+			newAsyncQueryRangeSharder(reader, o, warmingCfg.Metrics.Sharder, false, logger),
+		},
+		[]pipeline.Middleware{cacheWare, statusCodeWare, retryWare},
+		next)
+
+	traces := newTraceIDHandler(cfg, tracePipeline, o, combiner.NewTraceByID, audit, logger)
+	tracesV2 := newTraceIDHandler(cfg, tracePipeline, o, combiner.NewTraceByIDV2, audit, logger)
+	search := newSearchHTTPHandler(cfg, searchPipeline, o, newSearchResultsCache(cfg.Search.ResultsCache, cacheProvider, o, logger), audit, logger)
+	searchSpans := newSearchSpansHTTPHandler(search, logger) // Reuses the search handler
+	searchTags := newTagsHTTPHandler(cfg, searchTagsPipeline, o, audit, logger)
+	searchTagsV2 := newTagsV2HTTPHandler(cfg, searchTagsPipeline, o, audit, logger)
+	searchTagValues := newTagValuesHTTPHandler(cfg, searchTagValuesPipeline, o, audit, logger)
+	searchTagValuesV2 := newTagValuesV2HTTPHandler(cfg, searchTagValuesPipeline, o, audit, logger)
 	metrics := newMetricsSummaryHandler(metricsPipeline, logger)
-	queryInstant := newMetricsQueryInstantHTTPHandler(cfg, queryInstantPipeline, logger) // Reuses the same pipeline
-	queryRange := newMetricsQueryRangeHTTPHandler(cfg, queryRangePipeline, logger)
+	queryInstant := newMetricsQueryInstantHTTPHandler(cfg, queryInstantPipeline, o, audit, logger) // Reuses the same pipeline
+	queryRange := newMetricsQueryRangeHTTPHandler(cfg, queryRangePipeline, o, audit, logger)
+	cacheWarming := newCacheWarmingHTTPHandler(
+		newCacheWarmer(cfg, warmingSearchPipeline, warmingQueryRangePipeline, apiPrefix, logger),
+		cacheProvider != nil && cacheProvider.CacheFor(cache.RoleFrontendSearch) != nil,
+		logger)
+	tail := newTailer(cfg, tailPipeline, o, audit, apiPrefix, logger)
 
 	return &QueryFrontend{
 		// http/discrete
 		TraceByIDHandler:           newHandler(cfg.Config.LogQueryRequestHeaders, traces, logger),
 		TraceByIDHandlerV2:         newHandler(cfg.Config.LogQueryRequestHeaders, tracesV2, logger),
 		SearchHandler:              newHandler(cfg.Config.LogQueryRequestHeaders, search, logger),
+		SearchSpansHandler:         newHandler(cfg.Config.LogQueryRequestHeaders, searchSpans, logger),
 		SearchTagsHandler:          newHandler(cfg.Config.LogQueryRequestHeaders, searchTags, logger),
 		SearchTagsV2Handler:        newHandler(cfg.Config.LogQueryRequestHeaders, searchTagsV2, logger),
 		SearchTagsValuesHandler:    newHandler(cfg.Config.LogQueryRequestHeaders, searchTagValues, logger),
@@ -202,21 +293,34 @@ func New(cfg Config, next pipeline.RoundTripper, o overrides.Interface, reader t
 		MetricsSummaryHandler:      newHandler(cfg.Config.LogQueryRequestHeaders, metrics, logger),
 		MetricsQueryInstantHandler: newHandler(cfg.Config.LogQueryRequestHeaders, queryInstant, logger),
 		MetricsQueryRangeHandler:   newHandler(cfg.Config.LogQueryRequestHeaders, queryRange, logger),
+		CacheWarmingHandler:        newHandler(cfg.Config.LogQueryRequestHeaders, cacheWarming, logger),
+		TailHandler:                newTailHTTPHandler(tail, logger),
 
 		// grpc/streaming
-		streamingSearch:       newSearchStreamingGRPCHandler(cfg, searchPipeline, apiPrefix, logger),
-		streamingTags:         newTagsStreamingGRPCHandler(cfg, searchTagsPipeline, apiPrefix, o, logger),
-		streamingTagsV2:       newTagsV2StreamingGRPCHandler(cfg, searchTagsPipeline, apiPrefix, o, logger),
-		streamingTagValues:    newTagValuesStreamingGRPCHandler(cfg, searchTagValuesPipeline, apiPrefix, o, logger),
-		streamingTagValuesV2:  newTagValuesV2StreamingGRPCHandler(cfg, searchTagValuesPipeline, apiPrefix, o, logger),
-		streamingQueryRange:   newQueryRangeStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, logger),
-		streamingQueryInstant: newQueryInstantStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, logger), // Reuses the same pipeline
+		streamingSearch:       newSearchStreamingGRPCHandler(cfg, searchPipeline, apiPrefix, o, audit, logger),
+		streamingTags:         newTagsStreamingGRPCHandler(cfg, searchTagsPipeline, apiPrefix, o, audit, logger),
+		streamingTagsV2:       newTagsV2StreamingGRPCHandler(cfg, searchTagsPipeline, apiPrefix, o, audit, logger),
+		streamingTagValues:    newTagValuesStreamingGRPCHandler(cfg, searchTagValuesPipeline, apiPrefix, o, audit, logger),
+		streamingTagValuesV2:  newTagValuesV2StreamingGRPCHandler(cfg, searchTagValuesPipeline, apiPrefix, o, audit, logger),
+		streamingQueryRange:   newQueryRangeStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, o, audit, logger),
+		streamingQueryInstant: newQueryInstantStreamingGRPCHandler(cfg, queryRangePipeline, apiPrefix, o, audit, logger), // Reuses the same pipeline
+		streamingTail:         newTailStreamingGRPCHandler(tail, logger),
+		traceByID:             newTraceIDGRPCHandler(tracesV2, apiPrefix, logger), // Reuses the v2 trace by id handler
+		streamingTraceByID:    newTraceIDStreamingGRPCHandler(cfg, tracePipeline, o, apiPrefix, audit, logger),
 
 		cacheProvider: cacheProvider,
+		audit:         audit,
 		logger:        logger,
 	}, nil
 }
 
+// Shutdown flushes and closes the audit log.
+func (q *QueryFrontend) Shutdown() {
+	if err := q.audit.close(); err != nil {
+		level.Error(q.logger).Log("msg", "failed to close audit log", "err", err)
+	}
+}
+
 // Search implements StreamingQuerierServer interface for streaming search
 func (q *QueryFrontend) Search(req *tempopb.SearchRequest, srv tempopb.StreamingQuerier_SearchServer) error {
 	return q.streamingSearch(req, srv)
@@ -246,6 +350,68 @@ func (q *QueryFrontend) MetricsQueryInstant(req *tempopb.QueryInstantRequest, sr
 	return q.streamingQueryInstant(req, srv)
 }
 
+// FindTraceByID implements the TempoQueryServer interface for trace by id lookups
+func (q *QueryFrontend) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequest) (*tempopb.TraceByIDResponse, error) {
+	return q.traceByID(ctx, req)
+}
+
+// StreamTraceByID implements the TempoQueryServer interface for trace by id lookups that stream the spans as they are
+// combined
+func (q *QueryFrontend) StreamTraceByID(req *tempopb.TraceByIDRequest, srv tempopb.TempoQuery_StreamTraceByIDServer) error {
+	return q.streamingTraceByID(req, srv)
+}
+
+// TempoQueryServer returns the implementation of the versioned TempoQuery service. It shares the handlers of the
+// StreamingQuerier service, whose stream types it can't implement on the QueryFrontend itself.
+func (q *QueryFrontend) TempoQueryServer() tempopb.TempoQueryServer {
+	return &tempoQueryServer{q: q}
+}
+
+type tempoQueryServer struct {
+	q *QueryFrontend
+}
+
+func (s *tempoQueryServer) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequest) (*tempopb.TraceByIDResponse, error) {
+	return s.q.FindTraceByID(ctx, req)
+}
+
+func (s *tempoQueryServer) Search(req *tempopb.SearchRequest, srv tempopb.TempoQuery_SearchServer) error {
+	return s.q.streamingSearch(req, srv)
+}
+
+func (s *tempoQueryServer) SearchTags(req *tempopb.SearchTagsRequest, srv tempopb.TempoQuery_SearchTagsServer) error {
+	return s.q.streamingTags(req, srv)
+}
+
+func (s *tempoQueryServer) SearchTagsV2(req *tempopb.SearchTagsRequest, srv tempopb.TempoQuery_SearchTagsV2Server) error {
+	return s.q.streamingTagsV2(req, srv)
+}
+
+func (s *tempoQueryServer) SearchTagValues(req *tempopb.SearchTagValuesRequest, srv tempopb.TempoQuery_SearchTagValuesServer) error {
+	return s.q.streamingTagValues(req, srv)
+}
+
+func (s *tempoQueryServer) SearchTagValuesV2(req *tempopb.SearchTagValuesRequest, srv tempopb.TempoQuery_SearchTagValuesV2Server) error {
+	return s.q.streamingTagValuesV2(req, srv)
+}
+
+func (s *tempoQueryServer) MetricsQueryRange(req *tempopb.QueryRangeRequest, srv tempopb.TempoQuery_MetricsQueryRangeServer) error {
+	return s.q.streamingQueryRange(req, srv)
+}
+
+func (s *tempoQueryServer) MetricsQueryInstant(req *tempopb.QueryInstantRequest, srv tempopb.TempoQuery_MetricsQueryInstantServer) error {
+	return s.q.streamingQueryInstant(req, srv)
+}
+
+func (s *tempoQueryServer) StreamTraceByID(req *tempopb.TraceByIDRequest, srv tempopb.TempoQuery_StreamTraceByIDServer) error {
+	return s.q.StreamTraceByID(req, srv)
+}
+
+// Tail implements TailServer interface for streaming the results of a search as spans arrive
+func (q *QueryFrontend) Tail(req *tempopb.SearchRequest, srv tempopb.Tail_TailServer) error {
+	return q.streamingTail(req, srv)
+}
+
 // newSpanMetricsMiddleware creates a new frontend middleware to handle metrics-generator requests.
 func newMetricsSummaryHandler(next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
 	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
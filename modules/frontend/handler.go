@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"runtime/pprof"
 	"strings"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/grafana/dskit/httpgrpc"
 	"github.com/grafana/dskit/user"
 
+	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/util/tracing"
 )
 
@@ -53,13 +55,21 @@ func newHandler(LogQueryRequestHeaders flagext.StringSliceCSV, rt http.RoundTrip
 
 // ServeHTTP implements http.Handler
 func (f *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	orgID, _ := user.ExtractOrgID(r.Context())
+
+	// the goroutines of the sharders inherit the labels
+	pprof.Do(r.Context(), api.ProfileLabels(orgID, r.URL), func(ctx context.Context) {
+		f.serveHTTP(w, r.WithContext(ctx), orgID)
+	})
+}
+
+func (f *handler) serveHTTP(w http.ResponseWriter, r *http.Request, orgID string) {
 	defer func() {
 		_ = r.Body.Close()
 	}()
 
 	ctx := r.Context()
 	start := time.Now()
-	orgID, _ := user.ExtractOrgID(ctx)
 	traceID, _ := tracing.ExtractTraceID(ctx)
 
 	// add orgid to existing spans
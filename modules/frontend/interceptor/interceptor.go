@@ -5,17 +5,39 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/dskit/tenant"
+	"github.com/grafana/dskit/user"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	"google.golang.org/grpc"
 )
 
-const streamingQuerierPrefix = "/tempopb.StreamingQuerier/"
+const (
+	streamingQuerierPrefix = "/tempopb.StreamingQuerier/"
+	tempoQueryPrefix       = "/tempopb.query.v1.TempoQuery/"
+)
+
+// TenantTimeoutFunc returns the timeout for a single tenant. A value of 0 means the tenant has no
+// specific timeout and the default applies.
+type TenantTimeoutFunc func(tenantID string) time.Duration
+
+// MethodTimeoutFunc returns the per tenant timeout of the given gRPC method or nil if the method has none.
+type MethodTimeoutFunc func(fullMethod string) TenantTimeoutFunc
 
 func NewFrontendAPIUnaryTimeout(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return NewFrontendAPIUnaryTenantTimeout(timeout, nil)
+}
+
+func NewFrontendAPIStreamTimeout(timeout time.Duration) grpc.StreamServerInterceptor {
+	return NewFrontendAPIStreamTenantTimeout(timeout, nil)
+}
+
+// NewFrontendAPIUnaryTenantTimeout is like NewFrontendAPIUnaryTimeout but prefers the per tenant timeout
+// returned by methodTimeout over the default timeout. It must be installed after the auth interceptor.
+func NewFrontendAPIUnaryTenantTimeout(timeout time.Duration, methodTimeout MethodTimeoutFunc) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-		if strings.HasPrefix(info.FullMethod, streamingQuerierPrefix) {
+		if t := methodTimeoutFor(ctx, info.FullMethod, timeout, methodTimeout); t > 0 {
 			var cancel context.CancelFunc
-			ctx, cancel = context.WithTimeout(ctx, timeout)
+			ctx, cancel = context.WithTimeout(ctx, t)
 			defer cancel()
 		}
 
@@ -23,12 +45,14 @@ func NewFrontendAPIUnaryTimeout(timeout time.Duration) grpc.UnaryServerIntercept
 	}
 }
 
-func NewFrontendAPIStreamTimeout(timeout time.Duration) grpc.StreamServerInterceptor {
+// NewFrontendAPIStreamTenantTimeout is like NewFrontendAPIStreamTimeout but prefers the per tenant timeout
+// returned by methodTimeout over the default timeout. It must be installed after the auth interceptor.
+func NewFrontendAPIStreamTenantTimeout(timeout time.Duration, methodTimeout MethodTimeoutFunc) grpc.StreamServerInterceptor {
 	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
 		ctx := ss.Context()
-		if strings.HasPrefix(info.FullMethod, streamingQuerierPrefix) {
+		if t := methodTimeoutFor(ctx, info.FullMethod, timeout, methodTimeout); t > 0 {
 			var cancel context.CancelFunc
-			ctx, cancel = context.WithTimeout(ss.Context(), timeout)
+			ctx, cancel = context.WithTimeout(ss.Context(), t)
 			defer cancel()
 		}
 
@@ -38,3 +62,58 @@ func NewFrontendAPIStreamTimeout(timeout time.Duration) grpc.StreamServerInterce
 		})
 	}
 }
+
+// QueryMethod returns the method name of a StreamingQuerier or TempoQuery gRPC method. It returns false
+// for methods of other services.
+func QueryMethod(fullMethod string) (string, bool) {
+	switch {
+	case strings.HasPrefix(fullMethod, streamingQuerierPrefix):
+		return strings.TrimPrefix(fullMethod, streamingQuerierPrefix), true
+	case strings.HasPrefix(fullMethod, tempoQueryPrefix):
+		return strings.TrimPrefix(fullMethod, tempoQueryPrefix), true
+	default:
+		return "", false
+	}
+}
+
+// ResolveTimeout returns the timeout for the given org id. For multi-tenant queries the shortest
+// timeout of all involved tenants applies. If no tenant has a specific timeout defaultTimeout is returned.
+func ResolveTimeout(orgID string, defaultTimeout time.Duration, tenantTimeout TenantTimeoutFunc) time.Duration {
+	if orgID == "" || tenantTimeout == nil {
+		return defaultTimeout
+	}
+
+	tenantIDs, err := tenant.TenantIDsFromOrgID(orgID)
+	if err != nil {
+		return defaultTimeout
+	}
+
+	timeout := time.Duration(0)
+	for _, tenantID := range tenantIDs {
+		t := tenantTimeout(tenantID)
+		if t > 0 && (timeout == 0 || t < timeout) {
+			timeout = t
+		}
+	}
+
+	if timeout == 0 {
+		return defaultTimeout
+	}
+	return timeout
+}
+
+// methodTimeoutFor returns the timeout to enforce for the method. Only methods of the StreamingQuerier
+// and TempoQuery services are time limited.
+func methodTimeoutFor(ctx context.Context, fullMethod string, timeout time.Duration, methodTimeout MethodTimeoutFunc) time.Duration {
+	if _, ok := QueryMethod(fullMethod); !ok {
+		return 0
+	}
+
+	var tenantTimeout TenantTimeoutFunc
+	if methodTimeout != nil {
+		tenantTimeout = methodTimeout(fullMethod)
+	}
+
+	orgID, _ := user.ExtractOrgID(ctx)
+	return ResolveTimeout(orgID, timeout, tenantTimeout)
+}
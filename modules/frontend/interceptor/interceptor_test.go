@@ -62,6 +62,36 @@ func TestInterceptorsCancelContextForStreaming(t *testing.T) {
 	require.GreaterOrEqual(t, time.Since(start), apiTimeout) // confirm that we did wait for the full api timeout
 }
 
+func TestResolveTimeout(t *testing.T) {
+	tenantTimeouts := map[string]time.Duration{
+		"fast": time.Second,
+		"slow": time.Hour,
+	}
+	fn := func(tenantID string) time.Duration {
+		return tenantTimeouts[tenantID]
+	}
+
+	tcs := []struct {
+		name     string
+		orgID    string
+		fn       TenantTimeoutFunc
+		expected time.Duration
+	}{
+		{name: "no tenant", orgID: "", fn: fn, expected: time.Minute},
+		{name: "no func", orgID: "fast", fn: nil, expected: time.Minute},
+		{name: "tenant without override", orgID: "other", fn: fn, expected: time.Minute},
+		{name: "tenant with shorter override", orgID: "fast", fn: fn, expected: time.Second},
+		{name: "tenant with longer override", orgID: "slow", fn: fn, expected: time.Hour},
+		{name: "multi tenant takes shortest", orgID: "slow|fast|other", fn: fn, expected: time.Second},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, ResolveTimeout(tc.orgID, time.Minute, tc.fn))
+		})
+	}
+}
+
 type mockService struct {
 	apiTimeout time.Duration
 }
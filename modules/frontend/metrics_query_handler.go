@@ -16,12 +16,13 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/tempopb"
 )
 
-func newQueryInstantStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, logger log.Logger) streamingQueryInstantHandler {
-	postSLOHook := metricsSLOPostHook(cfg.Metrics.SLO)
+func newQueryInstantStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, audit *auditLogger, logger log.Logger) streamingQueryInstantHandler {
+	postSLOHook := audit.wrap(metricsOp, metricsSLOPostHook(cfg.Metrics.SLO))
 	downstreamPath := path.Join(apiPrefix, api.PathMetricsQueryRange)
 
 	return func(req *tempopb.QueryInstantRequest, srv tempopb.StreamingQuerier_MetricsQueryInstantServer) error {
@@ -51,7 +52,7 @@ func newQueryInstantStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTri
 		httpReq = httpReq.Clone(ctx)
 
 		var finalResponse *tempopb.QueryInstantResponse
-		c, err := combiner.NewTypedQueryRange(qr)
+		c, err := combiner.NewTypedQueryRange(qr, resolveTenantLimit(tenant, cfg.Metrics.Sharder.MaxSeries, o.MetricsMaxSeries))
 		if err != nil {
 			return err
 		}
@@ -71,7 +72,7 @@ func newQueryInstantStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTri
 		if finalResponse != nil && finalResponse.Metrics != nil {
 			bytesProcessed = finalResponse.Metrics.InspectedBytes
 		}
-		postSLOHook(nil, tenant, bytesProcessed, duration, err)
+		postSLOHook(httpReq, nil, tenant, bytesProcessed, duration, err)
 		logQueryInstantResult(logger, tenant, duration.Seconds(), req, finalResponse, err)
 		return err
 	}
@@ -79,8 +80,8 @@ func newQueryInstantStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTri
 
 // newMetricsQueryInstantHTTPHandler handles instant queries.  Internally these are rewritten as query_range with single step
 // to make use of the existing pipeline.
-func newMetricsQueryInstantHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
-	postSLOHook := metricsSLOPostHook(cfg.Metrics.SLO)
+func newMetricsQueryInstantHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, audit *auditLogger, logger log.Logger) http.RoundTripper {
+	postSLOHook := audit.wrap(metricsOp, metricsSLOPostHook(cfg.Metrics.SLO))
 
 	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		tenant, _ := user.ExtractOrgID(req.Context())
@@ -114,7 +115,7 @@ func newMetricsQueryInstantHTTPHandler(cfg Config, next pipeline.AsyncRoundTripp
 		req.URL.Path = strings.ReplaceAll(req.URL.Path, api.PathMetricsQueryInstant, api.PathMetricsQueryRange)
 		req = api.BuildQueryRangeRequest(req, qr, "") // dedicated cols are never passed from the caller
 
-		combiner, err := combiner.NewTypedQueryRange(qr)
+		combiner, err := combiner.NewTypedQueryRange(qr, resolveTenantLimit(tenant, cfg.Metrics.Sharder.MaxSeries, o.MetricsMaxSeries))
 		if err != nil {
 			level.Error(logger).Log("msg", "query instant: query range combiner failed", "err", err)
 			return &http.Response{
@@ -163,7 +164,7 @@ func newMetricsQueryInstantHTTPHandler(cfg Config, next pipeline.AsyncRoundTripp
 		if qiResp.Metrics != nil {
 			bytesProcessed = qiResp.Metrics.InspectedBytes
 		}
-		postSLOHook(resp, tenant, bytesProcessed, duration, err)
+		postSLOHook(req, resp, tenant, bytesProcessed, duration, err)
 		logQueryInstantResult(logger, tenant, duration.Seconds(), i, &qiResp, err)
 
 		return resp, nil
@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/modules/overrides"
 
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/tempopb"
@@ -21,8 +22,8 @@ import (
 )
 
 // newQueryRangeStreamingGRPCHandler returns a handler that streams results from the HTTP handler
-func newQueryRangeStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, logger log.Logger) streamingQueryRangeHandler {
-	postSLOHook := metricsSLOPostHook(cfg.Metrics.SLO)
+func newQueryRangeStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, audit *auditLogger, logger log.Logger) streamingQueryRangeHandler {
+	postSLOHook := audit.wrap(metricsOp, metricsSLOPostHook(cfg.Metrics.SLO))
 	downstreamPath := path.Join(apiPrefix, api.PathMetricsQueryRange)
 
 	return func(req *tempopb.QueryRangeRequest, srv tempopb.StreamingQuerier_MetricsQueryRangeServer) error {
@@ -46,7 +47,7 @@ func newQueryRangeStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripp
 		start := time.Now()
 
 		var finalResponse *tempopb.QueryRangeResponse
-		c, err := combiner.NewTypedQueryRange(req)
+		c, err := combiner.NewTypedQueryRange(req, resolveTenantLimit(tenant, cfg.Metrics.Sharder.MaxSeries, o.MetricsMaxSeries))
 		if err != nil {
 			return err
 		}
@@ -64,15 +65,15 @@ func newQueryRangeStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripp
 		if finalResponse != nil && finalResponse.Metrics != nil {
 			bytesProcessed = finalResponse.Metrics.InspectedBytes
 		}
-		postSLOHook(nil, tenant, bytesProcessed, duration, err)
+		postSLOHook(httpReq, nil, tenant, bytesProcessed, duration, err)
 		logQueryRangeResult(logger, tenant, duration.Seconds(), req, finalResponse, err)
 		return err
 	}
 }
 
 // newMetricsQueryRangeHTTPHandler returns a handler that returns a single response from the HTTP handler
-func newMetricsQueryRangeHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
-	postSLOHook := metricsSLOPostHook(cfg.Metrics.SLO)
+func newMetricsQueryRangeHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, audit *auditLogger, logger log.Logger) http.RoundTripper {
+	postSLOHook := audit.wrap(metricsOp, metricsSLOPostHook(cfg.Metrics.SLO))
 
 	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		tenant, _ := user.ExtractOrgID(req.Context())
@@ -92,7 +93,7 @@ func newMetricsQueryRangeHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper
 		logQueryRangeRequest(logger, tenant, queryRangeReq)
 
 		// build and use roundtripper
-		combiner, err := combiner.NewTypedQueryRange(queryRangeReq)
+		combiner, err := combiner.NewTypedQueryRange(queryRangeReq, resolveTenantLimit(tenant, cfg.Metrics.Sharder.MaxSeries, o.MetricsMaxSeries))
 		if err != nil {
 			level.Error(logger).Log("msg", "query range: query range combiner failed", "err", err)
 			return &http.Response{
@@ -114,7 +115,7 @@ func newMetricsQueryRangeHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper
 		}
 
 		duration := time.Since(start)
-		postSLOHook(resp, tenant, bytesProcessed, duration, err)
+		postSLOHook(req, resp, tenant, bytesProcessed, duration, err)
 		logQueryRangeResult(logger, tenant, duration.Seconds(), queryRangeReq, queryRangeResp, err)
 		return resp, err
 	})
@@ -42,6 +42,11 @@ type QueryRangeSharderConfig struct {
 	QueryBackendAfter     time.Duration `yaml:"query_backend_after,omitempty"`
 	Interval              time.Duration `yaml:"interval,omitempty"`
 	MaxExemplars          int           `yaml:"max_exemplars,omitempty"`
+	MaxSeries             int           `yaml:"max_series,omitempty"`
+
+	// lowerConcurrencyOnly is set for cache warming. The concurrency override of a tenant can only lower the
+	// concurrency of its queries.
+	lowerConcurrencyOnly bool
 }
 
 // newAsyncQueryRangeSharder creates a sharding middleware for search
@@ -118,7 +123,7 @@ func (s queryRangeSharder) RoundTrip(pipelineRequest pipeline.Request) (pipeline
 
 	var (
 		allowUnsafe           = s.overrides.UnsafeQueryHints(tenantID)
-		targetBytesPerRequest = s.jobSize(expr, allowUnsafe)
+		targetBytesPerRequest = s.jobSize(tenantID, expr, allowUnsafe)
 		cutoff                = time.Now().Add(-s.cfg.QueryBackendAfter)
 	)
 
@@ -155,7 +160,8 @@ func (s queryRangeSharder) RoundTrip(pipelineRequest pipeline.Request) (pipeline
 		jobMetricsResponse = pipeline.NewSuccessfulResponse(body)
 	}
 
-	return pipeline.NewAsyncSharderChan(ctx, s.cfg.ConcurrentRequests, reqCh, jobMetricsResponse, s.next), nil
+	concurrentRequests := concurrentJobs(s.overrides.MetricsConcurrentJobs(tenantID), s.cfg.ConcurrentRequests, s.cfg.lowerConcurrencyOnly)
+	return pipeline.NewAsyncSharderChan(ctx, concurrentRequests, reqCh, jobMetricsResponse, s.next), nil
 }
 
 // blockMetas returns all relevant blockMetas given a start/end
@@ -299,6 +305,7 @@ func (s *queryRangeSharder) buildBackendRequests(ctx context.Context, tenantID s
 			if len(key) > 0 {
 				pipelineR.SetCacheKey(key)
 			}
+			pipelineR.SetSize(jobBytes(m, startPage, pages))
 
 			select {
 			case reqCh <- pipelineR:
@@ -343,12 +350,17 @@ func (s *queryRangeSharder) maxDuration(tenantID string) time.Duration {
 	return s.cfg.MaxDuration
 }
 
-func (s *queryRangeSharder) jobSize(expr *traceql.RootExpr, allowUnsafe bool) int {
+func (s *queryRangeSharder) jobSize(tenantID string, expr *traceql.RootExpr, allowUnsafe bool) int {
 	// If we have a query hint then use it
 	if v, ok := expr.Hints.GetInt(traceql.HintJobSize, allowUnsafe); ok && v > 0 {
 		return v
 	}
 
+	// Then the tenant override
+	if v := s.overrides.MetricsTargetBytesPerJob(tenantID); v > 0 {
+		return v
+	}
+
 	// Else use configured value.
 	size := s.cfg.TargetBytesPerRequest
 
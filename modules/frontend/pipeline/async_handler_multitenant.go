@@ -1,6 +1,7 @@
 package pipeline
 
 import (
+	"context"
 	"errors"
 	"strings"
 
@@ -54,7 +55,44 @@ func (t *tenantRoundTripper) RoundTrip(req Request) (Responses[combiner.Pipeline
 			return nil
 		}
 		return requestForTenant(req, tenants[tenantIdx])
-	}, t.next), nil
+	}, AsyncRoundTripperFunc[combiner.PipelineResponse](func(r Request) (Responses[combiner.PipelineResponse], error) {
+		resps, err := t.next.RoundTrip(r)
+		if err != nil {
+			return nil, err
+		}
+
+		// tag the responses with their tenant so the combiners can tell them apart
+		tenantID, err := user.ExtractOrgID(r.Context())
+		if err != nil {
+			return nil, err
+		}
+		return &tenantResponses{next: resps, tenant: tenantID}, nil
+	})), nil
+}
+
+// tenantResponses wraps the responses of one tenant of a multi-tenant query
+type tenantResponses struct {
+	next   Responses[combiner.PipelineResponse]
+	tenant string
+}
+
+func (t *tenantResponses) Next(ctx context.Context) (combiner.PipelineResponse, bool, error) {
+	resp, done, err := t.next.Next(ctx)
+	if resp != nil {
+		resp = tenantResponse{PipelineResponse: resp, tenant: t.tenant}
+	}
+	return resp, done, err
+}
+
+var _ combiner.TenantResponse = tenantResponse{}
+
+type tenantResponse struct {
+	combiner.PipelineResponse
+	tenant string
+}
+
+func (t tenantResponse) Tenant() string {
+	return t.tenant
 }
 
 // requestForTenant makes a copy of request and injects the tenant id into context and Header.
@@ -94,6 +94,7 @@ func TestMultiTenant(t *testing.T) {
 			resps, err := rt.RoundTrip(NewHTTPRequest(req))
 			require.NoError(t, err)
 
+			respTenants := map[string]struct{}{}
 			for {
 				res, done, err := resps.Next(context.Background())
 				if done {
@@ -102,9 +103,17 @@ func TestMultiTenant(t *testing.T) {
 
 				require.NotNil(t, res)
 				require.NoError(t, err)
+
+				// responses of multi-tenant queries are tagged with their tenant
+				tenantResp, ok := res.(combiner.TenantResponse)
+				require.True(t, ok)
+				respTenants[tenantResp.Tenant()] = struct{}{}
 			}
 
 			require.Equal(t, len(tenants), int(reqCount.Load()))
+			if len(tenants) > 1 {
+				require.Equal(t, tenantsMap, respTenants)
+			}
 		})
 	}
 }
@@ -0,0 +1,74 @@
+package pipeline
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/queue"
+)
+
+type priorityRequestWare struct {
+	requestType RequestType
+	enabled     bool
+	next        AsyncRoundTripper[combiner.PipelineResponse]
+
+	interactiveSearchMaxRange time.Duration
+}
+
+// NewPriorityWare returns a middleware that sets the QoS class of the requests of the type. Trace by id lookups and
+// searches of at most interactiveSearchMaxRange are interactive, TraceQL metrics and cache warming queries are
+// background. If disabled, all requests are of the default class.
+func NewPriorityWare(rt RequestType, enabled bool, interactiveSearchMaxRange time.Duration) AsyncMiddleware[combiner.PipelineResponse] {
+	return AsyncMiddlewareFunc[combiner.PipelineResponse](func(next AsyncRoundTripper[combiner.PipelineResponse]) AsyncRoundTripper[combiner.PipelineResponse] {
+		return &priorityRequestWare{
+			requestType:               rt,
+			enabled:                   enabled,
+			next:                      next,
+			interactiveSearchMaxRange: interactiveSearchMaxRange,
+		}
+	})
+}
+
+func (c priorityRequestWare) RoundTrip(req Request) (Responses[combiner.PipelineResponse], error) {
+	if c.enabled {
+		req.SetPriority(c.priority(req))
+	}
+	return c.next.RoundTrip(req)
+}
+
+func (c priorityRequestWare) priority(req Request) queue.Priority {
+	switch c.requestType {
+	case TraceByID:
+		return queue.PriorityInteractive
+	case TraceQLSearch:
+		if c.isShortSearch(req) {
+			return queue.PriorityInteractive
+		}
+		return queue.PriorityDefault
+	case TraceQLMetrics, CacheWarming:
+		return queue.PriorityBackground
+	default:
+		return queue.PriorityDefault
+	}
+}
+
+// isShortSearch returns true if the search covers at most the interactive range. Searches without a range only
+// search recent data in the ingesters.
+func (c priorityRequestWare) isShortSearch(req Request) bool {
+	query := req.HTTPRequest().URL.Query()
+	if !query.Has("start") || !query.Has("end") {
+		return true
+	}
+
+	start, err := strconv.ParseInt(query.Get("start"), 10, 64)
+	if err != nil {
+		return false
+	}
+	end, err := strconv.ParseInt(query.Get("end"), 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return time.Duration(end-start)*time.Second <= c.interactiveSearchMaxRange
+}
@@ -0,0 +1,85 @@
+package pipeline
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/tempo/modules/frontend/queue"
+)
+
+func TestPriorityMiddleware(t *testing.T) {
+	tcs := []struct {
+		name        string
+		requestType RequestType
+		url         string
+		enabled     bool
+		expected    queue.Priority
+	}{
+		{
+			name:        "trace by id",
+			requestType: TraceByID,
+			url:         "http://localhost:8080/api/v2/traces/123345",
+			enabled:     true,
+			expected:    queue.PriorityInteractive,
+		},
+		{
+			name:        "search of recent data",
+			requestType: TraceQLSearch,
+			url:         "http://localhost:8080/api/search?q={}",
+			enabled:     true,
+			expected:    queue.PriorityInteractive,
+		},
+		{
+			name:        "short search",
+			requestType: TraceQLSearch,
+			url:         "http://localhost:8080/api/search?q={}&start=1000&end=4600",
+			enabled:     true,
+			expected:    queue.PriorityInteractive,
+		},
+		{
+			name:        "long search",
+			requestType: TraceQLSearch,
+			url:         "http://localhost:8080/api/search?q={}&start=1000&end=4601",
+			enabled:     true,
+			expected:    queue.PriorityDefault,
+		},
+		{
+			name:        "tags",
+			requestType: Default,
+			url:         "http://localhost:8080/api/v2/search/tags",
+			enabled:     true,
+			expected:    queue.PriorityDefault,
+		},
+		{
+			name:        "traceql metrics",
+			requestType: TraceQLMetrics,
+			url:         "http://localhost:8080/api/metrics/query_range?q={}|rate()",
+			enabled:     true,
+			expected:    queue.PriorityBackground,
+		},
+		{
+			name:        "cache warming",
+			requestType: CacheWarming,
+			url:         "http://localhost:8080/api/search?q={}",
+			enabled:     true,
+			expected:    queue.PriorityBackground,
+		},
+		{
+			name:        "disabled",
+			requestType: TraceByID,
+			url:         "http://localhost:8080/api/v2/traces/123345",
+			expected:    queue.PriorityDefault,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			roundTrip := NewPriorityWare(tc.requestType, tc.enabled, time.Hour).Wrap(nextRequest)
+			req := DoWeightedRequest(t, tc.url, roundTrip)
+
+			assert.Equal(t, tc.expected, req.Priority())
+		})
+	}
+}
@@ -32,6 +32,8 @@ const (
 	TraceByID
 	TraceQLSearch
 	TraceQLMetrics
+	// CacheWarming is only used to classify the requests of the cache warmer
+	CacheWarming
 )
 
 type weightRequestWare struct {
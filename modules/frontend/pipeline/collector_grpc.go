@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"runtime/pprof"
 	"time"
 
 	"github.com/gogo/status"
+	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/pkg/api"
 	"google.golang.org/grpc/codes"
 )
 
@@ -29,7 +32,17 @@ func NewGRPCCollector[T combiner.TResponse](next AsyncRoundTripper[combiner.Pipe
 }
 
 // RoundTrip implements the http.RoundTripper interface
-func (c GRPCCollector[T]) RoundTrip(req *http.Request) error {
+func (c GRPCCollector[T]) RoundTrip(req *http.Request) (err error) {
+	tenant, _ := user.ExtractOrgID(req.Context())
+
+	// the goroutines of the sharders inherit the labels
+	pprof.Do(req.Context(), api.ProfileLabels(tenant, req.URL), func(ctx context.Context) {
+		err = c.roundTrip(req.WithContext(ctx))
+	})
+	return err
+}
+
+func (c GRPCCollector[T]) roundTrip(req *http.Request) error {
 	ctx := req.Context()
 	ctx, cancel := context.WithCancel(ctx) // create a new context with a cancel function
 	defer cancel()
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/queue"
 	"go.opentelemetry.io/otel"
 )
 
@@ -20,6 +21,12 @@ type Request interface {
 	SetWeight(int)
 	Weight() int
 
+	SetPriority(queue.Priority) // the QoS class of the request in the frontend queue
+	Priority() queue.Priority
+
+	SetSize(uint64) // the number of bytes the job searches, 0 if unknown
+	Size() uint64
+
 	SetCacheKey(string)
 	CacheKey() string
 
@@ -33,6 +40,8 @@ type HTTPRequest struct {
 	cacheKey     string
 	responseData any
 	weight       int
+	priority     queue.Priority
+	size         uint64
 }
 
 func NewHTTPRequest(req *http.Request) *HTTPRequest {
@@ -79,10 +88,28 @@ func (r *HTTPRequest) SetWeight(w int) {
 	r.weight = w
 }
 
+func (r *HTTPRequest) Priority() queue.Priority {
+	return r.priority
+}
+
+func (r *HTTPRequest) SetPriority(p queue.Priority) {
+	r.priority = p
+}
+
+func (r *HTTPRequest) Size() uint64 {
+	return r.size
+}
+
+func (r *HTTPRequest) SetSize(s uint64) {
+	r.size = s
+}
+
 func (r *HTTPRequest) CloneFromHTTPRequest(request *http.Request) Request {
 	return &HTTPRequest{
 		req:          request,
 		weight:       r.weight,
+		priority:     r.priority,
+		size:         r.size,
 		cacheKey:     r.cacheKey,
 		responseData: r.responseData,
 	}
@@ -68,8 +68,8 @@ func (c cachingWare) RoundTrip(req Request) (*http.Response, error) {
 		return resp, err
 	}
 
-	// do not cache if response is not HTTP 2xx
-	if !shouldCache(resp.StatusCode) {
+	// do not cache if response is not HTTP 2xx or the querier asked not to
+	if !shouldCache(resp.StatusCode) || resp.Header.Get(api.HeaderCacheControl) == api.HeaderNoStore {
 		return resp, nil
 	}
 
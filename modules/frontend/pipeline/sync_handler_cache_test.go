@@ -3,10 +3,16 @@ package pipeline
 import (
 	"bytes"
 	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/go-kit/log"
 	"github.com/gogo/protobuf/jsonpb"
+	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/cache"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util/test"
@@ -45,3 +51,31 @@ func TestCacheCaches(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, expected, actual)
 }
+
+func TestCachingWareDoesNotCacheNoStore(t *testing.T) {
+	p := test.NewMockProvider()
+
+	for _, noStore := range []bool{false, true} {
+		key := fmt.Sprintf("key-%t", noStore)
+
+		rt := NewCachingWare(p, cache.RoleFrontendSearch, log.NewNopLogger()).Wrap(RoundTripperFunc(func(Request) (*http.Response, error) {
+			resp := &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader("{}")),
+			}
+			if noStore {
+				resp.Header.Set(api.HeaderCacheControl, api.HeaderNoStore)
+			}
+			return resp, nil
+		}))
+
+		req := NewHTTPRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		req.SetCacheKey(key)
+		_, err := rt.RoundTrip(req)
+		require.NoError(t, err)
+
+		c := newFrontendCache(p, cache.RoleFrontendSearch, log.NewNopLogger())
+		require.Equal(t, noStore, len(c.fetchBytes(context.Background(), key)) == 0)
+	}
+}
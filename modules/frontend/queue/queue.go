@@ -19,28 +19,63 @@ var (
 	ErrStopped         = errors.New("queue is stopped")
 )
 
+// Priority is the QoS class of a request. Each class has its own user queues and requests of higher classes are
+// dequeued first.
+type Priority int
+
+const (
+	// PriorityDefault is the class of requests that are not classified.
+	PriorityDefault Priority = iota
+	// PriorityInteractive is the class of requests a user is waiting for, like trace by id lookups.
+	PriorityInteractive
+	// PriorityBackground is the class of long running requests, like TraceQL metrics queries.
+	PriorityBackground
+
+	numPriorities
+)
+
+// priorities are the classes from highest to lowest priority.
+var priorities = [numPriorities]Priority{PriorityInteractive, PriorityDefault, PriorityBackground}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	case PriorityBackground:
+		return "background"
+	default:
+		return "default"
+	}
+}
+
 // UserIndex is opaque type that allows to resume iteration over users between successive calls
 // of RequestQueue.GetNextRequestForQuerier method.
 type UserIndex struct {
-	last int
+	last     [numPriorities]int
+	priority Priority
 }
 
 // Modify index to start iteration on the same user, for which last queue was returned.
 func (ui UserIndex) ReuseLastUser() UserIndex {
-	if ui.last >= 0 {
-		return UserIndex{last: ui.last - 1}
+	if ui.last[ui.priority] >= 0 {
+		ui.last[ui.priority]--
 	}
 	return ui
 }
 
 // FirstUser returns UserIndex that starts iteration over user queues from the very first user.
 func FirstUser() UserIndex {
-	return UserIndex{last: -1}
+	ui := UserIndex{}
+	for i := range ui.last {
+		ui.last[i] = -1
+	}
+	return ui
 }
 
 // Request stored into the queue.
 type Request interface {
 	Weight() int
+	Priority() Priority
 }
 
 // RequestQueue holds incoming requests in per-user queues.
@@ -49,19 +84,32 @@ type RequestQueue struct {
 
 	mtx     sync.RWMutex
 	cond    contextCond // Notified when request is enqueued or dequeued, or querier is disconnected.
-	queues  *queues
+	queues  [numPriorities]*queues
 	stopped bool
 
+	// starvationLimit is the number of times a class with pending requests is passed over for higher classes before
+	// it is served. 0 serves a class only if all higher classes are empty.
+	starvationLimit int
+	passedOver      [numPriorities]int
+	// tenantWeight returns the number of requests dequeued for a tenant before moving on to the next tenant.
+	tenantWeight func(userID string) int
+
 	queueLength       *prometheus.GaugeVec   // Per user and reason.
 	discardedRequests *prometheus.CounterVec // Per user.
 }
 
-func NewRequestQueue(maxOutstandingPerTenant int, queueLength *prometheus.GaugeVec, discardedRequests *prometheus.CounterVec) *RequestQueue {
+// NewRequestQueue returns a queue with a limit of maxOutstandingPerTenant requests per tenant and class. tenantWeight
+// can be nil to give all tenants the same weight.
+func NewRequestQueue(maxOutstandingPerTenant int, starvationLimit int, tenantWeight func(userID string) int, queueLength *prometheus.GaugeVec, discardedRequests *prometheus.CounterVec) *RequestQueue {
 	q := &RequestQueue{
-		queues:            newUserQueues(maxOutstandingPerTenant),
+		starvationLimit:   starvationLimit,
+		tenantWeight:      tenantWeight,
 		queueLength:       queueLength,
 		discardedRequests: discardedRequests,
 	}
+	for i := range q.queues {
+		q.queues[i] = newUserQueues(maxOutstandingPerTenant)
+	}
 
 	q.cond = contextCond{Cond: sync.NewCond(&q.mtx)}
 	q.Service = services.NewTimerService(queueCleanupPeriod, nil, q.cleanupQueues, q.stopping).WithName("request queue")
@@ -82,7 +130,7 @@ func (q *RequestQueue) EnqueueRequest(userID string, req Request) error {
 	}
 
 	// try to grab the user queue under read lock
-	queue, cleanup, err := q.getQueueUnderRlock(userID)
+	queue, cleanup, err := q.getQueueUnderRlock(userID, req.Priority())
 	defer cleanup()
 	if err != nil {
 		return err
@@ -102,12 +150,17 @@ func (q *RequestQueue) EnqueueRequest(userID string, req Request) error {
 // getQueueUnderRlock attempts to get the queue for the given user under read lock. if it is not
 // possible it upgrades the RLock to a Lock. This method also returns a cleanup function that
 // will release whichever lock it had to acquire to get the queue.
-func (q *RequestQueue) getQueueUnderRlock(userID string) (chan Request, func(), error) {
+func (q *RequestQueue) getQueueUnderRlock(userID string, priority Priority) (chan Request, func(), error) {
 	cleanup := func() {
 		q.mtx.RUnlock()
 	}
 
-	uq := q.queues.userQueues[userID]
+	if priority < 0 || priority >= numPriorities {
+		priority = PriorityDefault
+	}
+	queues := q.queues[priority]
+
+	uq := queues.userQueues[userID]
 	if uq != nil {
 		return uq.ch, cleanup, nil
 	}
@@ -121,7 +174,7 @@ func (q *RequestQueue) getQueueUnderRlock(userID string) (chan Request, func(),
 		q.mtx.Unlock()
 	}
 
-	queue := q.queues.getOrAddQueue(userID)
+	queue := queues.getOrAddQueue(userID)
 	if queue == nil {
 		// This can only happen if userID is "".
 		return nil, cleanup, errors.New("no queue found")
@@ -145,7 +198,7 @@ func (q *RequestQueue) GetNextRequestForQuerier(ctx context.Context, last UserIn
 
 FindQueue:
 	// We need to wait if there are no users, or no pending requests for given querier.
-	for (q.queues.len() == 0 || querierWait) && ctx.Err() == nil && !q.stopped {
+	for (q.len() == 0 || querierWait) && ctx.Err() == nil && !q.stopped {
 		querierWait = false
 		q.cond.Wait(ctx)
 	}
@@ -158,11 +211,14 @@ FindQueue:
 		return nil, last, err
 	}
 
-	queue, userID, idx := q.queues.getNextQueueForQuerier(last.last)
-	last.last = idx
+	queue, userID, priority, idx := q.nextQueue(last)
 	if queue != nil {
+		last.last[priority] = idx
+		last.priority = priority
+
 		// this is all threadsafe b/c all users queues are blocked by q.mtx
 		batchBuffer := q.getBatchBuffer(batchBuffer, userID, queue)
+		q.queueLength.WithLabelValues(userID).Set(float64(q.userQueueLength(userID)))
 		return batchBuffer, last, nil
 	}
 
@@ -193,15 +249,82 @@ func (q *RequestQueue) getBatchBuffer(batchBuffer []Request, userID string, queu
 	}
 	batchBuffer = batchBuffer[:actuallyInBatch]
 
-	q.queueLength.WithLabelValues(userID).Set(float64(len(queue)))
 	return batchBuffer
 }
 
+// nextQueue returns the next user queue to dequeue from. Classes are served from highest to lowest priority, but
+// the highest class that was passed over starvationLimit times is served first. Must be called with mtx held.
+func (q *RequestQueue) nextQueue(last UserIndex) (chan Request, string, Priority, int) {
+	if q.starvationLimit > 0 {
+		for _, p := range priorities[1:] {
+			if q.passedOver[p] < q.starvationLimit {
+				continue
+			}
+			if queue, userID, idx := q.queues[p].getNextQueueForQuerier(last.last[p], q.tenantWeight); queue != nil {
+				q.served(p)
+				return queue, userID, p, idx
+			}
+		}
+	}
+
+	for _, p := range priorities {
+		if queue, userID, idx := q.queues[p].getNextQueueForQuerier(last.last[p], q.tenantWeight); queue != nil {
+			q.served(p)
+			return queue, userID, p, idx
+		}
+	}
+
+	return nil, "", PriorityDefault, -1
+}
+
+// served records that a request of the class was dequeued and passes over the lower classes with pending requests.
+func (q *RequestQueue) served(priority Priority) {
+	q.passedOver[priority] = 0
+
+	lower := false
+	for _, p := range priorities {
+		if lower {
+			if q.queues[p].hasPending() {
+				q.passedOver[p]++
+			} else {
+				q.passedOver[p] = 0
+			}
+		}
+		lower = lower || p == priority
+	}
+}
+
+// len returns the number of user queues of all classes.
+func (q *RequestQueue) len() int {
+	n := 0
+	for _, queues := range q.queues {
+		n += queues.len()
+	}
+	return n
+}
+
+// userQueueLength returns the number of requests of the user in all classes.
+func (q *RequestQueue) userQueueLength(userID string) int {
+	n := 0
+	for _, queues := range q.queues {
+		if uq := queues.userQueues[userID]; uq != nil {
+			n += len(uq.ch)
+		}
+	}
+	return n
+}
+
 func (q *RequestQueue) cleanupQueues(_ context.Context) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
 
-	if q.queues.deleteEmptyQueues() {
+	removedQueue := false
+	for _, queues := range q.queues {
+		if queues.deleteEmptyQueues() {
+			removedQueue = true
+		}
+	}
+	if removedQueue {
 		q.cond.Broadcast()
 	}
 
@@ -212,7 +335,7 @@ func (q *RequestQueue) stopping(_ error) error {
 	q.mtx.Lock()
 	defer q.mtx.Unlock()
 
-	for q.queues.len() > 0 {
+	for q.len() > 0 {
 		q.cond.Wait(context.Background())
 	}
 
@@ -2,6 +2,7 @@ package queue
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -17,7 +18,9 @@ import (
 const messages = 50_000
 
 type mockRequest struct {
-	weight int
+	weight   int
+	priority Priority
+	id       string
 }
 
 func (r *mockRequest) Invalid() bool { return false }
@@ -27,6 +30,7 @@ func (r *mockRequest) Weight() int {
 	}
 	return 1
 }
+func (r *mockRequest) Priority() Priority { return r.priority }
 
 func TestGetNextForQuerierOneUser(t *testing.T) {
 	messages := 10
@@ -168,7 +172,7 @@ func queueWithListeners(ctx context.Context, listeners int, batchSize int, liste
 		Name: "test_discarded",
 	}, []string{"user"})
 
-	q := NewRequestQueue(100_000, g, c)
+	q := NewRequestQueue(100_000, 0, nil, g, c)
 	start := make(chan struct{})
 
 	for i := 0; i < listeners; i++ {
@@ -331,6 +335,79 @@ func TestContextCond(t *testing.T) {
 	})
 }
 
+func TestGetNextByPriority(t *testing.T) {
+	tcs := []struct {
+		name            string
+		starvationLimit int
+		expected        []string
+	}{
+		{
+			name:     "higher classes first",
+			expected: []string{"interactive-1", "interactive-2", "default-1", "default-2", "background-1", "background-2"},
+		},
+		{
+			name:            "starving classes are served",
+			starvationLimit: 1,
+			expected:        []string{"interactive-1", "default-1", "background-1", "interactive-2", "default-2", "background-2"},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			q := newTestQueue(tc.starvationLimit, nil)
+
+			for _, p := range []Priority{PriorityBackground, PriorityDefault, PriorityInteractive} {
+				for i := 1; i <= 2; i++ {
+					require.NoError(t, q.EnqueueRequest("user", &mockRequest{priority: p, id: fmt.Sprintf("%s-%d", p, i)}))
+				}
+			}
+
+			require.Equal(t, tc.expected, dequeueIDs(t, q, len(tc.expected)))
+		})
+	}
+}
+
+func TestGetNextByTenantWeight(t *testing.T) {
+	q := newTestQueue(0, func(userID string) int {
+		if userID == "heavy" {
+			return 3
+		}
+		return 1
+	})
+
+	for i := 1; i <= 4; i++ {
+		require.NoError(t, q.EnqueueRequest("heavy", &mockRequest{id: fmt.Sprintf("heavy-%d", i)}))
+		require.NoError(t, q.EnqueueRequest("light", &mockRequest{id: fmt.Sprintf("light-%d", i)}))
+	}
+
+	require.Equal(t, []string{"heavy-1", "heavy-2", "heavy-3", "light-1", "heavy-4", "light-2", "light-3", "light-4"}, dequeueIDs(t, q, 8))
+}
+
+func newTestQueue(starvationLimit int, tenantWeight func(string) int) *RequestQueue {
+	g := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "test_len",
+	}, []string{"user"})
+	c := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "test_discarded",
+	}, []string{"user"})
+
+	return NewRequestQueue(100, starvationLimit, tenantWeight, g, c)
+}
+
+// dequeueIDs dequeues n requests one by one with a single querier and returns their ids
+func dequeueIDs(t *testing.T, q *RequestQueue, n int) []string {
+	ids := make([]string, 0, n)
+	last := FirstUser()
+	for i := 0; i < n; i++ {
+		reqs, idx, err := q.GetNextRequestForQuerier(context.Background(), last, make([]Request, 1))
+		require.NoError(t, err)
+		require.Len(t, reqs, 1)
+		last = idx
+		ids = append(ids, reqs[0].(*mockRequest).id)
+	}
+	return ids
+}
+
 func TestGetBatchBuffer(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -358,7 +435,7 @@ func TestGetBatchBuffer(t *testing.T) {
 		},
 		{
 			name:           "less than requested count due to biggest weight",
-			queueContents:  []Request{&mockRequest{10}},
+			queueContents:  []Request{&mockRequest{weight: 10}},
 			requestedCount: 3,
 			expectedCount:  1,
 		},
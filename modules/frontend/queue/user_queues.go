@@ -18,6 +18,9 @@ type userQueue struct {
 
 	// Points back to 'users' field in queues. Enables quick cleanup.
 	index int
+
+	// Number of further requests dequeued for the user before moving on to the next user.
+	credits int
 }
 
 func newUserQueues(maxUserQueueSize int) *queues {
@@ -32,6 +35,16 @@ func (q *queues) len() int {
 	return len(q.userQueues)
 }
 
+// hasPending returns true if any user has requests in its queue.
+func (q *queues) hasPending() bool {
+	for _, uq := range q.userQueues {
+		if len(uq.ch) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
 func (q *queues) deleteQueue(userID string) {
 	uq := q.userQueues[userID]
 	if uq == nil {
@@ -100,8 +113,17 @@ func (q *queues) getOrAddQueue(userID string) chan Request {
 
 // Finds next queue for the querier. To support fair scheduling between users, client is expected
 // to pass last user index returned by this function as argument. Is there was no previous
-// last user index, use -1.
-func (q *queues) getNextQueueForQuerier(lastUserIndex int) (chan Request, string, int) {
+// last user index, use -1. A user is served weight times in a row before moving on to the next
+// user, weight can be nil to serve every user once.
+func (q *queues) getNextQueueForQuerier(lastUserIndex int, weight func(userID string) int) (chan Request, string, int) {
+	// keep serving the last user until it used up its credits
+	if lastUserIndex >= 0 && lastUserIndex < len(q.users) && q.users[lastUserIndex] != "" {
+		if uq := q.userQueues[q.users[lastUserIndex]]; uq.credits > 0 && len(uq.ch) > 0 {
+			uq.credits--
+			return uq.ch, q.users[lastUserIndex], lastUserIndex
+		}
+	}
+
 	uid := lastUserIndex
 
 	for iters := 0; iters < len(q.users); iters++ {
@@ -124,6 +146,11 @@ func (q *queues) getNextQueueForQuerier(lastUserIndex int) (chan Request, string
 			continue
 		}
 
+		q.credits = 0
+		if weight != nil {
+			q.credits = weight(u) - 1
+		}
+
 		return q.ch, u, uid
 	}
 	return nil, "", uid
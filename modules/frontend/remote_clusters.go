@@ -0,0 +1,336 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+)
+
+var (
+	metricRemoteClusterRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_remote_cluster_requests_total",
+		Help:      "Total number of requests sent to the replicas of remote clusters by status code.",
+	}, []string{"cluster", "status_code"})
+	metricRemoteClusterHedgedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_remote_cluster_hedged_requests_total",
+		Help:      "Total number of requests sent to another replica of a remote cluster because the previous ones were slow.",
+	}, []string{"cluster"})
+)
+
+// RemoteClustersConfig configures remote Tempo clusters that are queried together with the local cluster. The
+// responses of the remote query frontends are combined with the local responses.
+type RemoteClustersConfig struct {
+	// HedgeAfter is how long a request to a replica of a remote cluster can take before it's also sent to the next
+	// replica. The first response is used and the others are discarded. 0 disables hedging.
+	HedgeAfter time.Duration `yaml:"hedge_after,omitempty"`
+	// Timeout of the requests to a remote cluster including reading the response. 0 disables the timeout.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+	// Tenants maps the local tenants to the remote clusters their queries are also sent to.
+	Tenants map[string][]RemoteCluster `yaml:"tenants,omitempty"`
+}
+
+// RemoteCluster is a remote Tempo cluster.
+type RemoteCluster struct {
+	Name string `yaml:"name"`
+	// URLs of the query frontends of the cluster including the http api prefix. Requests are sent to the first one
+	// and hedged to the others.
+	URLs []string `yaml:"urls"`
+	// TenantID is the tenant queried in the remote cluster. Defaults to the local tenant.
+	TenantID string `yaml:"tenant_id,omitempty"`
+	// Headers are added to the requests, e.g. for authentication.
+	Headers map[string]string `yaml:"headers,omitempty"`
+}
+
+func (cfg *RemoteClustersConfig) Validate() error {
+	if cfg.HedgeAfter < 0 {
+		return errors.New("remote clusters hedge_after should not be negative")
+	}
+	if cfg.Timeout < 0 {
+		return errors.New("remote clusters timeout should not be negative")
+	}
+
+	for tenant, clusters := range cfg.Tenants {
+		names := map[string]struct{}{}
+		for _, c := range clusters {
+			if c.Name == "" {
+				return fmt.Errorf("remote cluster of tenant %s has no name", tenant)
+			}
+			if _, ok := names[c.Name]; ok {
+				return fmt.Errorf("remote cluster %s of tenant %s is configured more than once", c.Name, tenant)
+			}
+			names[c.Name] = struct{}{}
+
+			if len(c.URLs) == 0 {
+				return fmt.Errorf("remote cluster %s of tenant %s has no urls", c.Name, tenant)
+			}
+			for _, u := range c.URLs {
+				parsed, err := url.Parse(u)
+				if err != nil {
+					return fmt.Errorf("remote cluster %s of tenant %s has an invalid url: %w", c.Name, tenant, err)
+				}
+				if parsed.Scheme != "http" && parsed.Scheme != "https" {
+					return fmt.Errorf("remote cluster %s of tenant %s has an invalid url %q: scheme must be http or https", c.Name, tenant, u)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// remoteClusterRequest is the request to a remote cluster in the fan out of a query.
+type remoteClusterRequest struct {
+	pipeline.Request
+	cluster *RemoteCluster
+}
+
+type remoteClustersRoundTripper struct {
+	next       pipeline.AsyncRoundTripper[combiner.PipelineResponse]
+	cfg        RemoteClustersConfig
+	remotePath func(*http.Request) string
+	client     *http.Client
+	logger     log.Logger
+}
+
+// newRemoteClustersMiddleware returns a middleware that sends the queries of tenants with remote clusters to the
+// local pipeline and to the remote clusters and returns all responses to be combined. remotePath returns the path of
+// the request to the remote clusters relative to their urls.
+func newRemoteClustersMiddleware(cfg RemoteClustersConfig, remotePath func(*http.Request) string, client *http.Client, logger log.Logger) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
+	return pipeline.AsyncMiddlewareFunc[combiner.PipelineResponse](func(next pipeline.AsyncRoundTripper[combiner.PipelineResponse]) pipeline.AsyncRoundTripper[combiner.PipelineResponse] {
+		if len(cfg.Tenants) == 0 {
+			return next
+		}
+
+		return &remoteClustersRoundTripper{
+			next:       next,
+			cfg:        cfg,
+			remotePath: remotePath,
+			client:     client,
+			logger:     logger,
+		}
+	})
+}
+
+func (r *remoteClustersRoundTripper) RoundTrip(req pipeline.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+	tenant, err := user.ExtractOrgID(req.Context())
+	if err != nil {
+		return pipeline.NewBadRequest(err), nil
+	}
+
+	clusters := r.cfg.Tenants[tenant]
+	if len(clusters) == 0 {
+		return r.next.RoundTrip(req)
+	}
+
+	return pipeline.NewAsyncSharderFunc(req.Context(), 0, len(clusters)+1, func(i int) pipeline.Request {
+		switch {
+		case i == 0:
+			return req
+		case i <= len(clusters):
+			return &remoteClusterRequest{Request: req, cluster: &clusters[i-1]}
+		}
+		return nil
+	}, pipeline.AsyncRoundTripperFunc[combiner.PipelineResponse](func(req pipeline.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+		remoteReq, ok := req.(*remoteClusterRequest)
+		if !ok {
+			return r.next.RoundTrip(req)
+		}
+		return pipeline.NewHTTPToAsyncResponse(r.query(remoteReq.HTTPRequest(), remoteReq.cluster, tenant)), nil
+	})), nil
+}
+
+type remoteClusterResult struct {
+	replica int
+	resp    *http.Response
+	err     error
+}
+
+// query sends the request to the first replica of the cluster and to the next one every time a request fails or
+// takes longer than the hedging delay. The first successful response is returned, the other requests are cancelled
+// and their responses discarded.
+func (r *remoteClustersRoundTripper) query(req *http.Request, cluster *RemoteCluster, tenant string) *http.Response {
+	ctx := req.Context()
+	results := make(chan remoteClusterResult, len(cluster.URLs))
+	cancels := make([]context.CancelFunc, 0, len(cluster.URLs))
+
+	send := func() {
+		replica := len(cancels)
+
+		var reqCtx context.Context
+		var cancel context.CancelFunc
+		if r.cfg.Timeout > 0 {
+			reqCtx, cancel = context.WithTimeout(ctx, r.cfg.Timeout)
+		} else {
+			reqCtx, cancel = context.WithCancel(ctx)
+		}
+		cancels = append(cancels, cancel)
+
+		go func() {
+			resp, err := r.send(reqCtx, req, cluster.URLs[replica], cluster, tenant)
+			results <- remoteClusterResult{replica: replica, resp: resp, err: err}
+		}()
+	}
+
+	var hedge <-chan time.Time
+	resetHedge := func() {
+		hedge = nil
+		if r.cfg.HedgeAfter > 0 && len(cancels) < len(cluster.URLs) {
+			hedge = time.After(r.cfg.HedgeAfter)
+		}
+	}
+
+	send()
+	resetHedge()
+
+	var failed *remoteClusterResult
+	for pending := 1; pending > 0; {
+		select {
+		case <-hedge:
+			metricRemoteClusterHedgedRequests.WithLabelValues(cluster.Name).Inc()
+			send()
+			pending++
+			resetHedge()
+
+		case res := <-results:
+			pending--
+
+			if res.err == nil && res.resp.StatusCode < http.StatusInternalServerError {
+				for i, cancel := range cancels {
+					if i != res.replica {
+						cancel()
+					}
+				}
+				go discardRemoteClusterResults(results, pending)
+
+				res.resp.Body = &cancelOnClose{ReadCloser: res.resp.Body, cancel: cancels[res.replica]}
+				return res.resp
+			}
+
+			level.Warn(r.logger).Log("msg", "remote cluster request failed", "cluster", cluster.Name, "tenant", tenant, "url", cluster.URLs[res.replica], "status", statusOf(res), "err", res.err)
+
+			// keep the last failure to return it if every replica fails
+			if failed != nil {
+				closeRemoteClusterResult(*failed)
+				cancels[failed.replica]()
+			}
+			failed = &res
+
+			if len(cancels) < len(cluster.URLs) {
+				send()
+				pending++
+				resetHedge()
+			}
+		}
+	}
+
+	if failed.resp != nil {
+		failed.resp.Body = &cancelOnClose{ReadCloser: failed.resp.Body, cancel: cancels[failed.replica]}
+		return failed.resp
+	}
+	cancels[failed.replica]()
+
+	return &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Status:     http.StatusText(http.StatusBadGateway),
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(fmt.Sprintf("remote cluster %s: %v", cluster.Name, failed.err))),
+	}
+}
+
+func (r *remoteClustersRoundTripper) send(ctx context.Context, req *http.Request, baseURL string, cluster *RemoteCluster, tenant string) (*http.Response, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	u = u.JoinPath(r.remotePath(req))
+	u.RawQuery = req.URL.RawQuery
+
+	remoteReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if accept := req.Header.Get(api.HeaderAccept); accept != "" {
+		remoteReq.Header.Set(api.HeaderAccept, accept)
+	}
+	for k, v := range cluster.Headers {
+		remoteReq.Header.Set(k, v)
+	}
+	if cluster.TenantID != "" {
+		tenant = cluster.TenantID
+	}
+	remoteReq.Header.Set(user.OrgIDHeaderName, tenant)
+
+	resp, err := r.client.Do(remoteReq)
+	if err != nil {
+		metricRemoteClusterRequests.WithLabelValues(cluster.Name, "error").Inc()
+		return nil, err
+	}
+	metricRemoteClusterRequests.WithLabelValues(cluster.Name, strconv.Itoa(resp.StatusCode)).Inc()
+
+	return resp, nil
+}
+
+// discardRemoteClusterResults drains the cancelled requests to other replicas.
+func discardRemoteClusterResults(results <-chan remoteClusterResult, pending int) {
+	for ; pending > 0; pending-- {
+		closeRemoteClusterResult(<-results)
+	}
+}
+
+func closeRemoteClusterResult(res remoteClusterResult) {
+	if res.resp != nil {
+		_ = res.resp.Body.Close()
+	}
+}
+
+func statusOf(res remoteClusterResult) int {
+	if res.resp == nil {
+		return 0
+	}
+	return res.resp.StatusCode
+}
+
+// cancelOnClose cancels the context of a request once its response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// remotePathWithoutPrefix returns the path of requests to remote clusters. The local http api prefix is removed.
+func remotePathWithoutPrefix(apiPrefix string) func(*http.Request) string {
+	return func(req *http.Request) string {
+		return strings.TrimPrefix(req.URL.Path, apiPrefix)
+	}
+}
+
+// remoteTraceByIDPath returns the path of trace by id requests to remote clusters. The v2 endpoint is always used
+// because it returns the trace by id responses the trace by id combiners expect.
+func remoteTraceByIDPath(req *http.Request) string {
+	return strings.Replace(api.PathTracesV2, "{"+api.URLParamTraceID+"}", path.Base(req.URL.Path), 1)
+}
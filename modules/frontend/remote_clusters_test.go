@@ -0,0 +1,260 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/search"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestRemoteClustersConfigValidate(t *testing.T) {
+	tcs := []struct {
+		name        string
+		cfg         RemoteClustersConfig
+		expectedErr string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "valid",
+			cfg: RemoteClustersConfig{
+				HedgeAfter: time.Second,
+				Tenants: map[string][]RemoteCluster{
+					"foo": {{Name: "eu", URLs: []string{"http://tempo-eu-0:3200", "https://tempo-eu-1:3200/tempo"}}},
+				},
+			},
+		},
+		{
+			name:        "negative hedge_after",
+			cfg:         RemoteClustersConfig{HedgeAfter: -time.Second},
+			expectedErr: "remote clusters hedge_after should not be negative",
+		},
+		{
+			name: "no name",
+			cfg: RemoteClustersConfig{Tenants: map[string][]RemoteCluster{
+				"foo": {{URLs: []string{"http://tempo-eu:3200"}}},
+			}},
+			expectedErr: "remote cluster of tenant foo has no name",
+		},
+		{
+			name: "duplicate name",
+			cfg: RemoteClustersConfig{Tenants: map[string][]RemoteCluster{
+				"foo": {{Name: "eu", URLs: []string{"http://tempo-eu:3200"}}, {Name: "eu", URLs: []string{"http://tempo-us:3200"}}},
+			}},
+			expectedErr: "remote cluster eu of tenant foo is configured more than once",
+		},
+		{
+			name: "no urls",
+			cfg: RemoteClustersConfig{Tenants: map[string][]RemoteCluster{
+				"foo": {{Name: "eu"}},
+			}},
+			expectedErr: "remote cluster eu of tenant foo has no urls",
+		},
+		{
+			name: "invalid scheme",
+			cfg: RemoteClustersConfig{Tenants: map[string][]RemoteCluster{
+				"foo": {{Name: "eu", URLs: []string{"tempo-eu:3200"}}},
+			}},
+			expectedErr: `remote cluster eu of tenant foo has an invalid url "tempo-eu:3200": scheme must be http or https`,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+// replica returns a remote cluster replica that responds after the delay or when the request is cancelled.
+func replica(t *testing.T, statusCode int, body string, delay time.Duration) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(delay):
+		case <-r.Context().Done():
+			return
+		}
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRemoteClustersMiddleware(t *testing.T) {
+	tcs := []struct {
+		name           string
+		replicas       func(t *testing.T) []string
+		tenant         string
+		expectedBodies []string
+		expectedCode   int
+	}{
+		{
+			name:           "tenant without remote clusters",
+			tenant:         "bar",
+			replicas:       func(*testing.T) []string { return nil },
+			expectedBodies: []string{"local"},
+		},
+		{
+			name: "first replica",
+			replicas: func(t *testing.T) []string {
+				return []string{replica(t, 200, "first", 0).URL, replica(t, 200, "second", 0).URL}
+			},
+			expectedBodies: []string{"local", "first"},
+		},
+		{
+			name: "hedged to slow replica",
+			replicas: func(t *testing.T) []string {
+				return []string{replica(t, 200, "first", time.Minute).URL, replica(t, 200, "second", 0).URL}
+			},
+			expectedBodies: []string{"local", "second"},
+		},
+		{
+			name: "failed replica",
+			replicas: func(t *testing.T) []string {
+				return []string{replica(t, 503, "first", 0).URL, replica(t, 200, "second", 0).URL}
+			},
+			expectedBodies: []string{"local", "second"},
+		},
+		{
+			name: "client errors aren't retried",
+			replicas: func(t *testing.T) []string {
+				return []string{replica(t, 404, "first", 0).URL, replica(t, 200, "second", 0).URL}
+			},
+			expectedBodies: []string{"local", "first"},
+			expectedCode:   404,
+		},
+		{
+			name: "all replicas failed",
+			replicas: func(t *testing.T) []string {
+				return []string{replica(t, 500, "first", 0).URL, replica(t, 503, "second", 0).URL}
+			},
+			expectedBodies: []string{"local", "second"},
+			expectedCode:   503,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := RemoteClustersConfig{
+				HedgeAfter: 50 * time.Millisecond,
+				Tenants: map[string][]RemoteCluster{
+					"foo": {{Name: "remote", URLs: tc.replicas(t)}},
+				},
+			}
+			next := pipeline.AsyncRoundTripperFunc[combiner.PipelineResponse](func(pipeline.Request) (pipeline.Responses[combiner.PipelineResponse], error) {
+				return pipeline.NewSuccessfulResponse("local"), nil
+			})
+			rt := newRemoteClustersMiddleware(cfg, remotePathWithoutPrefix(""), http.DefaultClient, log.NewNopLogger()).Wrap(next)
+
+			tenant := tc.tenant
+			if tenant == "" {
+				tenant = "foo"
+			}
+			req := httptest.NewRequest("GET", "/api/search", nil)
+			req = req.WithContext(user.InjectOrgID(req.Context(), tenant))
+
+			resps, err := rt.RoundTrip(pipeline.NewHTTPRequest(req))
+			require.NoError(t, err)
+
+			var bodies []string
+			for {
+				resp, done, err := resps.Next(context.Background())
+				require.NoError(t, err)
+				if resp != nil {
+					body, err := io.ReadAll(resp.HTTPResponse().Body)
+					require.NoError(t, err)
+					require.NoError(t, resp.HTTPResponse().Body.Close())
+					bodies = append(bodies, string(body))
+
+					if string(body) != "local" && tc.expectedCode != 0 {
+						require.Equal(t, tc.expectedCode, resp.HTTPResponse().StatusCode)
+					}
+				}
+				if done {
+					break
+				}
+			}
+			require.ElementsMatch(t, tc.expectedBodies, bodies)
+		})
+	}
+}
+
+func TestFrontendSearchRemoteClusters(t *testing.T) {
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/tempo/api/search", r.URL.Path)
+		require.Equal(t, "remote-tenant", r.Header.Get(user.OrgIDHeaderName))
+		require.Equal(t, "secret", r.Header.Get("Authorization"))
+		require.Equal(t, "{}", r.URL.Query().Get("q"))
+
+		w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+		require.NoError(t, (&jsonpb.Marshaler{}).Marshal(w, &tempopb.SearchResponse{
+			Traces: []*tempopb.TraceSearchMetadata{{TraceID: "2", RootServiceName: "remote"}},
+			Metrics: &tempopb.SearchMetrics{
+				InspectedTraces: 5,
+				InspectedBytes:  50,
+				TotalBlocks:     1,
+				TotalJobs:       2,
+				CompletedJobs:   2,
+				TotalBlockBytes: 100,
+			},
+		}))
+	}))
+	defer remote.Close()
+
+	f := frontendWithSettings(t, nil, nil, nil, nil, func(cfg *Config) {
+		cfg.RemoteClusters = RemoteClustersConfig{
+			Tenants: map[string][]RemoteCluster{
+				"foo": {{
+					Name:     "remote",
+					URLs:     []string{remote.URL + "/tempo"},
+					TenantID: "remote-tenant",
+					Headers:  map[string]string{"Authorization": "secret"},
+				}},
+			},
+		}
+	})
+
+	httpReq := httptest.NewRequest("GET", "/api/search", nil)
+	httpReq, err := api.BuildSearchRequest(httpReq, &tempopb.SearchRequest{Query: "{}", Start: 1, End: 100000, Limit: 10})
+	require.NoError(t, err)
+	httpReq = httpReq.WithContext(user.InjectOrgID(httpReq.Context(), "foo"))
+
+	httpResp := httptest.NewRecorder()
+	f.SearchHandler.ServeHTTP(httpResp, httpReq)
+	require.Equal(t, 200, httpResp.Code)
+
+	actualResp := &tempopb.SearchResponse{}
+	require.NoError(t, jsonpb.Unmarshal(httpResp.Body, actualResp))
+	require.ElementsMatch(t, []*tempopb.TraceSearchMetadata{
+		{TraceID: "1", RootServiceName: search.RootSpanNotYetReceivedText},
+		{TraceID: "2", RootServiceName: "remote"},
+	}, actualResp.Traces)
+	require.Equal(t, &tempopb.SearchMetrics{
+		InspectedTraces: 4 + 5,
+		InspectedBytes:  4 + 50,
+		TotalBlocks:     2 + 1,
+		TotalJobs:       4 + 2,
+		CompletedJobs:   4 + 2,
+		TotalBlockBytes: 4*defaultTargetBytesPerRequest + 100,
+	}, actualResp.Metrics)
+}
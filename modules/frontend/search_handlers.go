@@ -13,9 +13,11 @@ import (
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level" //nolint:all //deprecated
 	"github.com/gogo/status"
+	"github.com/grafana/dskit/tenant"
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/modules/overrides"
 	"google.golang.org/grpc/codes"
 
 	"github.com/grafana/tempo/pkg/api"
@@ -23,8 +25,8 @@ import (
 )
 
 // newSearchStreamingGRPCHandler returns a handler that streams results from the HTTP handler
-func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, logger log.Logger) streamingSearchHandler {
-	postSLOHook := searchSLOPostHook(cfg.Search.SLO)
+func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, audit *auditLogger, logger log.Logger) streamingSearchHandler {
+	postSLOHook := audit.wrap(searchOp, searchSLOPostHook(cfg.Search.SLO))
 	downstreamPath := path.Join(apiPrefix, api.PathSearch)
 
 	return func(req *tempopb.SearchRequest, srv tempopb.StreamingQuerier_SearchServer) error {
@@ -53,7 +55,7 @@ func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[c
 		}
 
 		var finalResponse *tempopb.SearchResponse
-		comb := combiner.NewTypedSearch(int(limit))
+		comb := combiner.NewTypedSearch(int(limit), api.IsMostRecentSearch(req), resolveTenantLimit(tenant, 0, o.MaxSpanSetsPerTrace))
 		collector := pipeline.NewGRPCCollector[*tempopb.SearchResponse](next, cfg.ResponseConsumers, comb, func(sr *tempopb.SearchResponse) error {
 			finalResponse = sr // sadly we can't srv.Send directly into the collector. we need bytesProcessed for the SLO calculations
 			return srv.Send(sr)
@@ -67,15 +69,16 @@ func newSearchStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[c
 		if finalResponse != nil && finalResponse.Metrics != nil {
 			bytesProcessed = finalResponse.Metrics.InspectedBytes
 		}
-		postSLOHook(nil, tenant, bytesProcessed, duration, err)
+		postSLOHook(httpReq, nil, tenant, bytesProcessed, duration, err)
 		logResult(logger, tenant, duration.Seconds(), req, finalResponse, nil, err)
 		return err
 	}
 }
 
-// newSearchHTTPHandler returns a handler that returns a single response from the HTTP handler
-func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], logger log.Logger) http.RoundTripper {
-	postSLOHook := searchSLOPostHook(cfg.Search.SLO)
+// newSearchHTTPHandler returns a handler that returns a single response from the HTTP handler. complete responses are
+// cached in the results cache, if one is passed
+func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, resultsCache *searchResultsCache, audit *auditLogger, logger log.Logger) http.RoundTripper {
+	postSLOHook := audit.wrap(searchOp, searchSLOPostHook(cfg.Search.SLO))
 
 	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		tenant, _ := user.ExtractOrgID(req.Context())
@@ -105,26 +108,88 @@ func newSearchHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.P
 
 		logRequest(logger, tenant, searchReq)
 
-		// build and use roundtripper
-		comb := combiner.NewTypedSearch(int(limit))
-		rt := pipeline.NewHTTPCollector(next, cfg.ResponseConsumers, comb)
+		execute := func(req *http.Request) (*http.Response, *tempopb.SearchResponse, error) {
+			// build and use roundtripper
+			comb := combiner.NewTypedSearch(int(limit), api.IsMostRecentSearch(searchReq), resolveTenantLimit(tenant, 0, o.MaxSpanSetsPerTrace))
+			rt := pipeline.NewHTTPCollector(next, cfg.ResponseConsumers, comb)
 
-		resp, err := rt.RoundTrip(req)
+			resp, err := rt.RoundTrip(req)
+
+			// ask for the typed diff and use that for the SLO hook. it will have up to date metrics
+			searchResp, _ := comb.GRPCDiff()
+			return resp, searchResp, err
+		}
+
+		alignedReq, cacheKey, cacheTTL := resultsCache.prepare(req, tenant, searchReq)
+		resp, truncated := resultsCache.fetch(req.Context(), tenant, cacheKey, searchReq, limit)
+		if resp != nil {
+			postSLOHook(req, resp, tenant, 0, time.Since(start), nil)
+			return resp, nil
+		}
+		if truncated {
+			// the cached response of the aligned range can't serve this range, search the original range
+			alignedReq, cacheKey = req, ""
+		}
+
+		resp, searchResp, err := execute(alignedReq)
 
-		// ask for the typed diff and use that for the SLO hook. it will have up to date metrics
 		var bytesProcessed uint64
-		searchResp, _ := comb.GRPCDiff()
 		if searchResp != nil && searchResp.Metrics != nil {
 			bytesProcessed = searchResp.Metrics.InspectedBytes
 		}
 
+		if err == nil {
+			complete, cacheErr := resultsCache.store(req.Context(), cacheKey, cacheTTL, resp, searchResp, searchReq, limit)
+			if cacheErr != nil {
+				level.Error(logger).Log("msg", "search: failed to cache search results", "err", cacheErr)
+			}
+			if !complete {
+				// the limit may have cut off traces of the original range in the response of the aligned range
+				resp, searchResp, err = execute(req)
+				if searchResp != nil && searchResp.Metrics != nil {
+					bytesProcessed += searchResp.Metrics.InspectedBytes
+				}
+			}
+		}
+
 		duration := time.Since(start)
-		postSLOHook(resp, tenant, bytesProcessed, duration, err)
+		postSLOHook(req, resp, tenant, bytesProcessed, duration, err)
 		logResult(logger, tenant, duration.Seconds(), searchReq, searchResp, resp, err)
 		return resp, err
 	})
 }
 
+// newSearchSpansHTTPHandler returns a handler for the search spans endpoint. the request is rewritten into a tag based
+// search request and passed on to the search handler
+func newSearchSpansHTTPHandler(next http.RoundTripper, logger log.Logger) http.RoundTripper {
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		searchReq, err := api.ParseSearchSpansRequest(req)
+		if err != nil {
+			level.Error(logger).Log("msg", "search spans: parse search spans request failed", "err", err)
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Status:     http.StatusText(http.StatusBadRequest),
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+			}, nil
+		}
+
+		// Clone existing to keep it unaltered.
+		req = req.Clone(req.Context())
+		req.URL.Path = strings.ReplaceAll(req.URL.Path, api.PathSearchSpans, api.PathSearch)
+		req, err = api.BuildSearchRequest(req, searchReq)
+		if err != nil {
+			level.Error(logger).Log("msg", "search spans: build search request failed", "err", err)
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Status:     http.StatusText(http.StatusBadRequest),
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+			}, nil
+		}
+
+		return next.RoundTrip(req)
+	})
+}
+
 // adjusts the limit based on provided config
 func adjustLimit(limit, defaultLimit, maxLimit uint32) (uint32, error) {
 	if limit == 0 {
@@ -138,6 +203,28 @@ func adjustLimit(limit, defaultLimit, maxLimit uint32) (uint32, error) {
 	return limit, nil
 }
 
+// resolveTenantLimit returns the limit for the given org id. For multi-tenant queries the lowest limit of all involved
+// tenants applies. Tenants without a limit fall back to the default.
+func resolveTenantLimit(orgID string, defaultLimit int, tenantLimit func(string) int) int {
+	tenantIDs, err := tenant.TenantIDsFromOrgID(orgID)
+	if err != nil {
+		return defaultLimit
+	}
+
+	limit := 0
+	for _, tenantID := range tenantIDs {
+		l := tenantLimit(tenantID)
+		if l > 0 && (limit == 0 || l < limit) {
+			limit = l
+		}
+	}
+
+	if limit == 0 {
+		return defaultLimit
+	}
+	return limit
+}
+
 func logResult(logger log.Logger, tenantID string, durationSeconds float64, req *tempopb.SearchRequest, resp *tempopb.SearchResponse, httpResp *http.Response, err error) {
 	statusCode := -1
 	if httpResp != nil {
@@ -214,6 +214,7 @@ func runnerRequests(t *testing.T, f *QueryFrontend) {
 				Traces: []*tempopb.TraceSearchMetadata{{
 					TraceID:         "1",
 					RootServiceName: search.RootSpanNotYetReceivedText,
+					Tenants:         []string{"tenant-1", "tenant-2"},
 				}},
 				Metrics: &tempopb.SearchMetrics{
 					InspectedTraces: 8,
@@ -298,6 +299,41 @@ func runnerClientCancelContext(t *testing.T, f *QueryFrontend) {
 	require.Equal(t, status.Error(codes.Canceled, "context canceled"), err)
 }
 
+func TestSearchSpansHandler(t *testing.T) {
+	var forwarded *http.Request
+	next := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		forwarded = req
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+	handler := newSearchSpansHTTPHandler(next, log.NewNopLogger())
+
+	req := httptest.NewRequest("GET", "/tempo/api/search/spans?service=svc&operation=GET%20%2Fapi&start=10&end=20&limit=5", nil)
+	resp, err := handler.RoundTrip(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// forwarded as a tag based search to the search endpoint
+	require.NotNil(t, forwarded)
+	require.Equal(t, "/tempo/api/search", forwarded.URL.Path)
+	searchReq, err := api.ParseSearchRequest(forwarded)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"service.name": "svc", "name": "GET /api"}, searchReq.Tags)
+	require.Empty(t, searchReq.Query)
+	require.Equal(t, uint32(10), searchReq.Start)
+	require.Equal(t, uint32(20), searchReq.End)
+	require.Equal(t, uint32(5), searchReq.Limit)
+
+	// the original request is unaltered
+	require.Equal(t, "/tempo/api/search/spans", req.URL.Path)
+
+	// bad requests are not forwarded
+	forwarded = nil
+	resp, err = handler.RoundTrip(httptest.NewRequest("GET", "/api/search/spans?start=10&end=20", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Nil(t, forwarded)
+}
+
 func TestSearchLimitHonored(t *testing.T) {
 	f := frontendWithSettings(t, &mockRoundTripper{
 		responseFn: func() proto.Message {
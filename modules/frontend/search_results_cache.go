@@ -0,0 +1,249 @@
+package frontend
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level" //nolint:all //deprecated
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/tenant"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+const (
+	searchResultsCacheHit  = "hit"
+	searchResultsCacheMiss = "miss"
+
+	// cached responses are prefixed with their expiry in unix nanoseconds. the cache has no per item ttl
+	searchResultsExpirySize = 8
+)
+
+var metricSearchResultsCacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "query_frontend_search_results_cache_requests_total",
+	Help:      "Total number of search requests looked up in the search results cache.",
+}, []string{"tenant", "result"})
+
+// searchResultsCache caches complete search responses. Dashboards repeat the same searches on every refresh, a cached
+// response is returned without executing any jobs.
+type searchResultsCache struct {
+	c   cache.Cache
+	cfg SearchResultsCacheConfig
+	o   overrides.Interface
+
+	now func() time.Time
+}
+
+// newSearchResultsCache returns a search results cache or nil if no cache with the frontend-search-results role is
+// configured.
+func newSearchResultsCache(cfg SearchResultsCacheConfig, cacheProvider cache.Provider, o overrides.Interface, logger log.Logger) *searchResultsCache {
+	var c cache.Cache
+	if cacheProvider != nil {
+		c = cacheProvider.CacheFor(cache.RoleFrontendSearchResults)
+	}
+
+	level.Info(logger).Log("msg", "init frontend search results cache", "enabled", c != nil)
+
+	if c == nil {
+		return nil
+	}
+
+	return &searchResultsCache{
+		c:   c,
+		cfg: cfg,
+		o:   o,
+		now: time.Now,
+	}
+}
+
+// prepare aligns the time range of the search and returns the request to execute, its cache key and the ttl of the
+// response. If the search can't be cached it returns the original request and an empty key. Searches without a time
+// range are never cached, they only search the most recent data. searchReq keeps the original range, fetch and store
+// remove the traces outside of it from the responses of the aligned range.
+func (c *searchResultsCache) prepare(req *http.Request, orgID string, searchReq *tempopb.SearchRequest) (*http.Request, string, time.Duration) {
+	if c == nil || searchReq.Start == 0 || searchReq.End == 0 {
+		return req, "", 0
+	}
+
+	ttl := c.ttl(orgID)
+	if ttl <= 0 {
+		return req, "", 0
+	}
+
+	hash := hashForSearchRequest(searchReq)
+	if hash == 0 {
+		return req, "", 0
+	}
+
+	start, end := searchReq.Start, searchReq.End
+	if alignment := uint32(c.cfg.TimeAlignment.Seconds()); alignment > 0 {
+		start = start / alignment * alignment
+		if end%alignment != 0 {
+			end = (end/alignment + 1) * alignment
+		}
+
+		req = req.Clone(req.Context())
+		query := req.URL.Query()
+		query.Set("start", strconv.FormatUint(uint64(start), 10))
+		query.Set("end", strconv.FormatUint(uint64(end), 10))
+		req.URL.RawQuery = query.Encode()
+	}
+
+	format := api.HeaderAcceptJSON
+	if req.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
+		format = api.HeaderAcceptProtobuf
+	}
+
+	return req, searchResultsCacheKey(orgID, hash, start, end, format), ttl
+}
+
+// ttl returns how long the responses of the org are cached. The override of a tenant takes precedence over the
+// configured ttl. For multi-tenant searches the shortest ttl of the tenants applies.
+func (c *searchResultsCache) ttl(orgID string) time.Duration {
+	tenantIDs, err := tenant.TenantIDsFromOrgID(orgID)
+	if err != nil {
+		return 0
+	}
+
+	var ttl time.Duration
+	for i, tenantID := range tenantIDs {
+		t := c.o.SearchResultsCacheTTL(tenantID)
+		if t <= 0 {
+			t = c.cfg.TTL
+		}
+		if i == 0 || t < ttl {
+			ttl = t
+		}
+	}
+	return ttl
+}
+
+// fetch returns the cached response restricted to the range of searchReq or nil if there is none or it expired.
+// truncated is true if a cached response exists but the limit may have cut off traces of the range, the original
+// range must be searched instead.
+func (c *searchResultsCache) fetch(ctx context.Context, tenant, key string, searchReq *tempopb.SearchRequest, limit uint32) (resp *http.Response, truncated bool) {
+	if c == nil || key == "" {
+		return nil, false
+	}
+
+	buf, found := c.c.FetchKey(ctx, key)
+	if !found || len(buf) <= searchResultsExpirySize || c.now().UnixNano() >= int64(binary.BigEndian.Uint64(buf)) {
+		metricSearchResultsCacheRequests.WithLabelValues(tenant, searchResultsCacheMiss).Inc()
+		return nil, false
+	}
+
+	body := buf[searchResultsExpirySize:]
+	contentType := api.HeaderAcceptProtobuf
+	if body[0] == '{' {
+		contentType = api.HeaderAcceptJSON
+	}
+
+	body, complete, err := restrictSearchResponse(body, contentType, searchReq.Start, searchReq.End, limit)
+	if err != nil || !complete {
+		metricSearchResultsCacheRequests.WithLabelValues(tenant, searchResultsCacheMiss).Inc()
+		return nil, err == nil
+	}
+	metricSearchResultsCacheRequests.WithLabelValues(tenant, searchResultsCacheHit).Inc()
+
+	return &http.Response{
+		Header:        http.Header{api.HeaderContentType: {contentType}},
+		StatusCode:    http.StatusOK,
+		Status:        http.StatusText(http.StatusOK),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}, false
+}
+
+// store restricts the response of the aligned range to the range of searchReq and caches the response of the
+// aligned range if it is complete. The body of the response is replaced with the restricted body. It returns false
+// if the limit may have cut off traces of the range or the response can't be restricted, the original range must
+// then be searched instead.
+func (c *searchResultsCache) store(ctx context.Context, key string, ttl time.Duration, resp *http.Response, searchResp *tempopb.SearchResponse, searchReq *tempopb.SearchRequest, limit uint32) (bool, error) {
+	if c == nil || key == "" || resp == nil || resp.StatusCode != http.StatusOK {
+		return true, nil
+	}
+
+	buffer, err := api.ReadBodyToBuffer(resp)
+	if err != nil {
+		return false, err
+	}
+
+	body := buffer.Bytes()
+	if len(body) == 0 {
+		resp.Body = io.NopCloser(buffer)
+		return true, nil
+	}
+
+	restricted, complete, err := restrictSearchResponse(body, resp.Header.Get(api.HeaderContentType), searchReq.Start, searchReq.End, limit)
+	if err != nil {
+		return false, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(restricted))
+	resp.ContentLength = int64(len(restricted))
+
+	// partial results must not be served to later searches
+	cacheable := searchResp != nil && len(searchResp.SkippedBlocks) == 0
+	if maxItemSize := c.c.MaxItemSize(); maxItemSize > 0 && len(body)+searchResultsExpirySize > maxItemSize {
+		cacheable = false
+	}
+
+	if cacheable {
+		item := make([]byte, searchResultsExpirySize, searchResultsExpirySize+len(body))
+		binary.BigEndian.PutUint64(item, uint64(c.now().Add(ttl).UnixNano()))
+		item = append(item, body...)
+
+		c.c.Store(ctx, []string{key}, [][]byte{item})
+	}
+
+	return complete, nil
+}
+
+// restrictSearchResponse removes the traces that don't overlap start and end from a marshalled search response of
+// the aligned range. The body is returned unchanged if all traces overlap the range. complete is false if the
+// response of the aligned range hit the limit but fewer than limit traces overlap the range, traces of the range
+// may then be missing.
+func restrictSearchResponse(body []byte, contentType string, start, end, limit uint32) (restricted []byte, complete bool, err error) {
+	searchResp := &tempopb.SearchResponse{}
+	if contentType == api.HeaderAcceptProtobuf {
+		err = proto.Unmarshal(body, searchResp)
+	} else {
+		err = jsonpb.Unmarshal(bytes.NewReader(body), searchResp)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	traces := make([]*tempopb.TraceSearchMetadata, 0, len(searchResp.Traces))
+	for _, tr := range searchResp.Traces {
+		traceStart := tr.StartTimeUnixNano / uint64(time.Second)
+		traceEnd := (tr.StartTimeUnixNano + uint64(tr.DurationMs)*uint64(time.Millisecond)) / uint64(time.Second)
+		if traceStart <= uint64(end) && traceEnd >= uint64(start) {
+			traces = append(traces, tr)
+		}
+	}
+	if len(traces) == len(searchResp.Traces) {
+		return body, true, nil
+	}
+	complete = limit == 0 || len(searchResp.Traces) < int(limit) || len(traces) >= int(limit)
+	searchResp.Traces = traces
+
+	if contentType == api.HeaderAcceptProtobuf {
+		restricted, err = proto.Marshal(searchResp)
+		return restricted, complete, err
+	}
+	s, err := new(jsonpb.Marshaler).MarshalToString(searchResp)
+	return []byte(s), complete, err
+}
@@ -0,0 +1,211 @@
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestSearchResultsCache(t *testing.T) {
+	c := test.NewMockClient()
+	p := test.NewMockProvider()
+	require.NoError(t, p.AddCache(cache.RoleFrontendSearchResults, c))
+
+	// the mock provider returns the same cache for all roles. the searched ranges don't cover the blocks so the job
+	// responses aren't cached. trace 2 is only within the aligned range of the searches
+	jobs := atomic.Int32{}
+	next := &mockRoundTripper{
+		responseFn: func() proto.Message {
+			jobs.Add(1)
+			return &tempopb.SearchResponse{
+				Traces: []*tempopb.TraceSearchMetadata{
+					{TraceID: "1", StartTimeUnixNano: uint64(1100 * time.Second), DurationMs: 1000},
+					{TraceID: "2", StartTimeUnixNano: uint64(1081 * time.Second), DurationMs: 1000},
+				},
+				Metrics: &tempopb.SearchMetrics{InspectedTraces: 1, InspectedBytes: 1},
+			}
+		},
+	}
+
+	f := frontendWithSettings(t, next, nil, nil, p, func(c *Config) {
+		c.Search.ResultsCache = SearchResultsCacheConfig{
+			TTL:           time.Minute,
+			TimeAlignment: time.Minute,
+		}
+	})
+
+	search := func(params string) string {
+		req := httptest.NewRequest(http.MethodGet, "/api/search?"+params, nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "foo"))
+
+		respWriter := httptest.NewRecorder()
+		f.SearchHandler.ServeHTTP(respWriter, req)
+
+		resp := respWriter.Result()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	// the first search executes the jobs and caches the response
+	first := search("q={}&start=1090&end=1150")
+	executed := jobs.Load()
+	require.Positive(t, executed)
+
+	require.Contains(t, first, `"traceID":"1"`)
+	require.NotContains(t, first, `"traceID":"2"`)
+
+	// searches with the same aligned range are served from the cache, restricted to their range
+	require.Equal(t, first, search("q={}&start=1085&end=1190"))
+	require.Contains(t, search("q={%20}&start=1080&end=1200"), `"traceID":"2"`)
+	require.Equal(t, executed, jobs.Load())
+
+	// a different query or range executes the jobs again
+	search("q={}&start=1150&end=1190")
+	require.Greater(t, jobs.Load(), executed)
+	executed = jobs.Load()
+
+	search("q={status=error}&start=1090&end=1150")
+	require.Greater(t, jobs.Load(), executed)
+	executed = jobs.Load()
+
+	// searches of the most recent data are never cached
+	search("q={}")
+	search("q={}")
+	require.Greater(t, jobs.Load(), executed+1)
+
+	// the aligned response reaches the limit with a trace outside of the range, so the range is searched again. the
+	// mock ignores the range, trace 2 is only returned by searches of the unaligned range
+	require.Contains(t, search("q={}&start=1090&end=1150&limit=2"), `"traceID":"2"`)
+
+	// the cached aligned response can't serve the range either
+	require.Contains(t, search("q={}&start=1090&end=1150&limit=2"), `"traceID":"2"`)
+}
+
+func TestSearchResultsCacheExpiry(t *testing.T) {
+	c := test.NewMockClient()
+	p := test.NewMockProvider()
+	require.NoError(t, p.AddCache(cache.RoleFrontendSearchResults, c))
+
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, nil)
+	require.NoError(t, err)
+
+	now := time.Unix(1000, 0)
+	resultsCache := newSearchResultsCache(SearchResultsCacheConfig{TTL: time.Minute}, p, o, log.NewNopLogger())
+	resultsCache.now = func() time.Time { return now }
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q={}&start=10&end=20", nil)
+	searchReq := &tempopb.SearchRequest{Query: "{}", Start: 10, End: 20}
+
+	// without alignment the request is unchanged
+	prepared, key, ttl := resultsCache.prepare(req, "foo", searchReq)
+	require.Same(t, req, prepared)
+	require.NotEmpty(t, key)
+	require.Equal(t, time.Minute, ttl)
+
+	cached, truncated := resultsCache.fetch(context.Background(), "foo", key, searchReq, 20)
+	require.Nil(t, cached)
+	require.False(t, truncated)
+
+	resp := &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"traces":[]}`)), ContentLength: -1}
+	complete, err := resultsCache.store(context.Background(), key, ttl, resp, &tempopb.SearchResponse{}, searchReq, 20)
+	require.NoError(t, err)
+	require.True(t, complete)
+
+	// the body can still be read after storing
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, `{"traces":[]}`, string(body))
+
+	cached, _ = resultsCache.fetch(context.Background(), "foo", key, searchReq, 20)
+	require.NotNil(t, cached)
+	require.Equal(t, api.HeaderAcceptJSON, cached.Header.Get(api.HeaderContentType))
+	body, err = io.ReadAll(cached.Body)
+	require.NoError(t, err)
+	require.Equal(t, `{"traces":[]}`, string(body))
+
+	now = now.Add(time.Minute)
+	cached, _ = resultsCache.fetch(context.Background(), "foo", key, searchReq, 20)
+	require.Nil(t, cached)
+
+	// partial results are not cached
+	resp = &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"traces":[]}`)), ContentLength: -1}
+	_, err = resultsCache.store(context.Background(), key, ttl, resp, &tempopb.SearchResponse{SkippedBlocks: []string{"block"}}, searchReq, 20)
+	require.NoError(t, err)
+	cached, _ = resultsCache.fetch(context.Background(), "foo", key, searchReq, 20)
+	require.Nil(t, cached)
+}
+
+func TestSearchResultsCacheRestrictsResponses(t *testing.T) {
+	c := test.NewMockClient()
+	p := test.NewMockProvider()
+	require.NoError(t, p.AddCache(cache.RoleFrontendSearchResults, c))
+
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, nil)
+	require.NoError(t, err)
+
+	resultsCache := newSearchResultsCache(SearchResultsCacheConfig{TTL: time.Minute, TimeAlignment: time.Minute}, p, o, log.NewNopLogger())
+
+	// trace 1 is within the searched range, trace 2 only within the aligned range
+	body := `{"traces":[{"traceID":"1","startTimeUnixNano":"1100000000000","durationMs":1000},{"traceID":"2","startTimeUnixNano":"1081000000000","durationMs":1000}]}`
+	newResp := func() *http.Response {
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{api.HeaderContentType: {api.HeaderAcceptJSON}}, Body: io.NopCloser(strings.NewReader(body)), ContentLength: -1}
+	}
+	readBody := func(resp *http.Response) string {
+		b, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/search?q={}&start=1090&end=1150", nil)
+	searchReq := &tempopb.SearchRequest{Query: "{}", Start: 1090, End: 1150}
+	_, key, ttl := resultsCache.prepare(req, "foo", searchReq)
+
+	// partial responses are restricted but not cached
+	resp := newResp()
+	complete, err := resultsCache.store(context.Background(), key, ttl, resp, &tempopb.SearchResponse{SkippedBlocks: []string{"block"}}, searchReq, 20)
+	require.NoError(t, err)
+	require.True(t, complete)
+	restricted := readBody(resp)
+	require.Contains(t, restricted, `"traceID":"1"`)
+	require.NotContains(t, restricted, `"traceID":"2"`)
+	cached, truncated := resultsCache.fetch(context.Background(), "foo", key, searchReq, 20)
+	require.Nil(t, cached)
+	require.False(t, truncated)
+
+	// the aligned response reached the limit but only one trace is within the searched range
+	resp = newResp()
+	complete, err = resultsCache.store(context.Background(), key, ttl, resp, &tempopb.SearchResponse{}, searchReq, 2)
+	require.NoError(t, err)
+	require.False(t, complete)
+	require.NotContains(t, readBody(resp), `"traceID":"2"`)
+
+	// the cached response can't serve the range with this limit
+	cached, truncated = resultsCache.fetch(context.Background(), "foo", key, searchReq, 2)
+	require.Nil(t, cached)
+	require.True(t, truncated)
+
+	// but it can serve ranges that contain all of its traces
+	wideReq := &tempopb.SearchRequest{Query: "{}", Start: 1080, End: 1200}
+	cached, truncated = resultsCache.fetch(context.Background(), "foo", key, wideReq, 2)
+	require.NotNil(t, cached)
+	require.False(t, truncated)
+	require.Equal(t, body, readBody(cached))
+}
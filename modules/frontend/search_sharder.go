@@ -3,7 +3,10 @@ package frontend
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-kit/log" //nolint:all deprecated
@@ -36,6 +39,10 @@ type SearchSharderConfig struct {
 	QueryIngestersUntil   time.Duration `yaml:"query_ingesters_until,omitempty"`
 	IngesterShards        int           `yaml:"ingester_shards,omitempty"`
 	MaxSpansPerSpanSet    uint32        `yaml:"max_spans_per_span_set,omitempty"`
+
+	// lowerConcurrencyOnly is set for cache warming. The concurrency override of a tenant can only lower the
+	// concurrency of its searches.
+	lowerConcurrencyOnly bool
 }
 
 type asyncSearchSharder struct {
@@ -92,8 +99,9 @@ func (s asyncSearchSharder) RoundTrip(pipelineRequest pipeline.Request) (pipelin
 		return pipeline.NewBadRequest(fmt.Errorf("range specified by start and end exceeds %s. received start=%d end=%d", maxDuration, searchReq.Start, searchReq.End)), nil
 	}
 
-	if s.cfg.MaxSpansPerSpanSet != 0 && searchReq.SpansPerSpanSet > s.cfg.MaxSpansPerSpanSet {
-		return pipeline.NewBadRequest(fmt.Errorf("spans per span set exceeds %d. received %d", s.cfg.MaxSpansPerSpanSet, searchReq.SpansPerSpanSet)), nil
+	maxSpansPerSpanSet := s.maxSpansPerSpanSet(tenantID)
+	if maxSpansPerSpanSet != 0 && searchReq.SpansPerSpanSet > maxSpansPerSpanSet {
+		return pipeline.NewBadRequest(fmt.Errorf("spans per span set exceeds %d. received %d", maxSpansPerSpanSet, searchReq.SpansPerSpanSet)), nil
 	}
 
 	// buffer of shards+1 allows us to insert ingestReq and metrics
@@ -111,12 +119,20 @@ func (s asyncSearchSharder) RoundTrip(pipelineRequest pipeline.Request) (pipelin
 	ingesterJobs := len(reqCh)
 
 	// pass subCtx in requests so we can cancel and exit early
-	totalJobs, totalBlocks, totalBlockBytes := s.backendRequests(ctx, tenantID, pipelineRequest, searchReq, reqCh, func(err error) {
+	totalJobs, totalBlocks, totalBlockBytes, shards := s.backendRequests(ctx, tenantID, pipelineRequest, searchReq, reqCh, func(err error) {
 		// todo: actually find a way to return this error to the user
 		s.logger.Log("msg", "search: failed to build backend requests", "err", err)
 	})
 	totalJobs += ingesterJobs
 
+	// the ingesters are always the first shard of a most recent search
+	if shards != nil || api.IsMostRecentSearch(searchReq) {
+		if shards == nil {
+			shards = []combiner.SearchShards{{}}
+		}
+		shards[0].TotalJobs = uint32(ingesterJobs)
+	}
+
 	// send a job to communicate the search metrics. this is consumed by the combiner to calculate totalblocks/bytes/jobs
 	var jobMetricsResponse pipeline.Responses[combiner.PipelineResponse]
 	if totalJobs > 0 {
@@ -135,10 +151,18 @@ func (s asyncSearchSharder) RoundTrip(pipelineRequest pipeline.Request) (pipelin
 		}
 
 		jobMetricsResponse = pipeline.NewSuccessfulResponse(body)
+		if shards != nil {
+			// the combiner needs the shards to know when it has found the most recent traces
+			jobMetricsResponse = pipeline.NewHTTPToAsyncResponseWithRequestData(&http.Response{
+				StatusCode: http.StatusOK,
+				Status:     http.StatusText(http.StatusOK),
+				Body:       io.NopCloser(strings.NewReader(body)),
+			}, shards)
+		}
 	}
 
 	// execute requests
-	return pipeline.NewAsyncSharderChan(ctx, s.cfg.ConcurrentRequests, reqCh, jobMetricsResponse, s.next), nil
+	return pipeline.NewAsyncSharderChan(ctx, s.concurrentRequests(tenantID), reqCh, jobMetricsResponse, s.next), nil
 }
 
 // blockMetas returns all relevant blockMetas given a start/end
@@ -158,8 +182,9 @@ func (s *asyncSearchSharder) blockMetas(start, end int64, tenantID string) []*ba
 }
 
 // backendRequest builds backend requests to search backend blocks. backendRequest takes ownership of reqCh and closes it.
-// it returns 3 int values: totalBlocks, totalBlockBytes, and estimated jobs
-func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID string, parent pipeline.Request, searchReq *tempopb.SearchRequest, reqCh chan<- pipeline.Request, errFn func(error)) (totalJobs, totalBlocks int, totalBlockBytes uint64) {
+// it returns 3 int values: totalBlocks, totalBlockBytes, and estimated jobs. for most recent searches the blocks are
+// searched newest first and it also returns the shards of the search. the first shard is left for the ingesters.
+func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID string, parent pipeline.Request, searchReq *tempopb.SearchRequest, reqCh chan<- pipeline.Request, errFn func(error)) (totalJobs, totalBlocks int, totalBlockBytes uint64, shards []combiner.SearchShards) {
 	var blocks []*backend.BlockMeta
 
 	// request without start or end, search only in ingester
@@ -180,18 +205,36 @@ func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID strin
 	// get block metadata of blocks in start, end duration
 	blocks = s.blockMetas(int64(start), int64(end), tenantID)
 
-	targetBytesPerRequest := s.cfg.TargetBytesPerRequest
+	targetBytesPerRequest := s.targetBytesPerRequest(tenantID)
+
+	mostRecent := api.IsMostRecentSearch(searchReq)
+	if mostRecent {
+		sort.Slice(blocks, func(i, j int) bool {
+			return blocks[i].EndTime.After(blocks[j].EndTime)
+		})
+		shards = make([]combiner.SearchShards, 1, len(blocks)+1)
+	}
 
 	// calculate metrics to return to the caller
 	totalBlocks = len(blocks)
 	for _, b := range blocks {
 		p := pagesPerRequest(b, targetBytesPerRequest)
 
-		totalJobs += int(b.TotalRecords) / p
-		if int(b.TotalRecords)%p != 0 {
-			totalJobs++
+		blockJobs := 0
+		if p != 0 {
+			blockJobs = int(b.TotalRecords) / p
+			if int(b.TotalRecords)%p != 0 {
+				blockJobs++
+			}
 		}
+		totalJobs += blockJobs
 		totalBlockBytes += b.Size_
+
+		if mostRecent {
+			// once the previous shards are done no unseen trace can start after this block ends
+			shards[len(shards)-1].CompletedThroughSeconds = uint32(b.EndTime.Unix()) + 1
+			shards = append(shards, combiner.SearchShards{TotalJobs: uint32(blockJobs)})
+		}
 	}
 
 	go func() {
@@ -206,9 +249,11 @@ func (s *asyncSearchSharder) backendRequests(ctx context.Context, tenantID strin
 // since this function modifies searchReq.Start and End we are taking a value instead of a pointer to prevent it from
 // unexpectedly changing the passed searchReq.
 func (s *asyncSearchSharder) ingesterRequests(tenantID string, parent pipeline.Request, searchReq tempopb.SearchRequest, reqCh chan pipeline.Request) error {
+	mostRecent := api.IsMostRecentSearch(&searchReq)
+
 	// request without start or end, search only in ingester
 	if searchReq.Start == 0 || searchReq.End == 0 {
-		return buildIngesterRequest(tenantID, parent, &searchReq, reqCh)
+		return buildIngesterRequest(tenantID, parent, &searchReq, mostRecent, reqCh)
 	}
 
 	ingesterUntil := uint32(time.Now().Add(-s.cfg.QueryIngestersUntil).Unix())
@@ -263,7 +308,7 @@ func (s *asyncSearchSharder) ingesterRequests(tenantID string, parent pipeline.R
 		subReq.Start = shardStart
 		subReq.End = shardEnd
 
-		err := buildIngesterRequest(tenantID, parent, &subReq, reqCh)
+		err := buildIngesterRequest(tenantID, parent, &subReq, mostRecent, reqCh)
 		if err != nil {
 			return err
 		}
@@ -283,6 +328,38 @@ func (s *asyncSearchSharder) maxDuration(tenantID string) time.Duration {
 	return s.cfg.MaxDuration
 }
 
+func (s *asyncSearchSharder) concurrentRequests(tenantID string) int {
+	return concurrentJobs(s.overrides.SearchConcurrentJobs(tenantID), s.cfg.ConcurrentRequests, s.cfg.lowerConcurrencyOnly)
+}
+
+func (s *asyncSearchSharder) targetBytesPerRequest(tenantID string) int {
+	// check overrides first, if no overrides then grab from our config
+	if targetBytes := s.overrides.SearchTargetBytesPerJob(tenantID); targetBytes > 0 {
+		return targetBytes
+	}
+
+	return s.cfg.TargetBytesPerRequest
+}
+
+func (s *asyncSearchSharder) maxSpansPerSpanSet(tenantID string) uint32 {
+	// check overrides first, if no overrides then grab from our config
+	maxSpans := s.overrides.MaxSpansPerSpanSet(tenantID)
+	if maxSpans > 0 {
+		return uint32(maxSpans)
+	}
+
+	return s.cfg.MaxSpansPerSpanSet
+}
+
+// concurrentJobs returns the number of jobs of a query to run concurrently. The tenant override takes precedence over
+// the configured value, unless lowerOnly is set and the override is higher.
+func concurrentJobs(override, configured int, lowerOnly bool) int {
+	if override <= 0 || (lowerOnly && override > configured) {
+		return configured
+	}
+	return override
+}
+
 // backendRange returns a new start/end range for the backend based on the config parameter
 // query_backend_after. If the returned start == the returned end then backend querying is not necessary.
 func backendRange(start, end uint32, queryBackendAfter time.Duration) (uint32, uint32) {
@@ -308,8 +385,9 @@ func buildBackendRequests(ctx context.Context, tenantID string, parent pipeline.
 
 	queryHash := hashForSearchRequest(searchReq)
 	colsToJSON := api.NewDedicatedColumnsToJSON()
+	mostRecent := api.IsMostRecentSearch(searchReq)
 
-	for _, m := range metas {
+	for i, m := range metas {
 		pages := pagesPerRequest(m, bytesPerRequest)
 		if pages == 0 {
 			continue
@@ -348,6 +426,11 @@ func buildBackendRequests(ctx context.Context, tenantID string, parent pipeline.
 
 			key := searchJobCacheKey(tenantID, queryHash, int64(searchReq.Start), int64(searchReq.End), m, startPage, pages)
 			pipelineR.SetCacheKey(key)
+			pipelineR.SetSize(jobBytes(m, startPage, pages))
+			if mostRecent {
+				// shard 0 is the ingesters
+				pipelineR.SetResponseData(combiner.SearchJobShard(i + 1))
+			}
 
 			select {
 			case reqCh <- pipelineR:
@@ -363,7 +446,7 @@ func buildBackendRequests(ctx context.Context, tenantID string, parent pipeline.
 // before hashing the query is forced into a canonical form so equivalent queries will hash to the same value.
 func hashForSearchRequest(searchRequest *tempopb.SearchRequest) uint64 {
 	if searchRequest.Query == "" {
-		return 0
+		return hashForTagsSearchRequest(searchRequest)
 	}
 
 	ast, err := traceql.Parse(searchRequest.Query)
@@ -371,6 +454,11 @@ func hashForSearchRequest(searchRequest *tempopb.SearchRequest) uint64 {
 		return 0
 	}
 
+	// analyzed searches time the search of every block. cached responses would return the timing of an earlier search
+	if analyze, _ := ast.Hints.GetBool(traceql.HintAnalyze, false); analyze {
+		return 0
+	}
+
 	// forces the query into a canonical form
 	query := ast.String()
 
@@ -382,6 +470,37 @@ func hashForSearchRequest(searchRequest *tempopb.SearchRequest) uint64 {
 	return hash
 }
 
+// hashForTagsSearchRequest returns a uint64 hash of a tag based search request such as the ones created by the search
+// spans endpoint. if there are no tags it returns a 0 hash.
+func hashForTagsSearchRequest(searchRequest *tempopb.SearchRequest) uint64 {
+	if len(searchRequest.Tags) == 0 {
+		return 0
+	}
+
+	keys := make([]string, 0, len(searchRequest.Tags))
+	for k := range searchRequest.Tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	// prefix the hash so a tags search can't collide with an equivalent TraceQL query
+	hash := fnv1a.HashString64("tags")
+	for _, k := range keys {
+		// lengths are added so "ab"="c" and "a"="bc" hash differently
+		v := searchRequest.Tags[k]
+		hash = fnv1a.AddUint64(hash, uint64(len(k)))
+		hash = fnv1a.AddString64(hash, k)
+		hash = fnv1a.AddUint64(hash, uint64(len(v)))
+		hash = fnv1a.AddString64(hash, v)
+	}
+	hash = fnv1a.AddUint64(hash, uint64(searchRequest.MinDurationMs))
+	hash = fnv1a.AddUint64(hash, uint64(searchRequest.MaxDurationMs))
+	hash = fnv1a.AddUint64(hash, uint64(searchRequest.Limit))
+	hash = fnv1a.AddUint64(hash, uint64(searchRequest.SpansPerSpanSet))
+
+	return hash
+}
+
 // pagesPerRequest returns an integer value that indicates the number of pages
 // that should be searched per query. This value is based on the target number of bytes
 // 0 is returned if there is no valid answer
@@ -403,7 +522,17 @@ func pagesPerRequest(m *backend.BlockMeta, bytesPerRequest int) int {
 	return pagesPerQuery
 }
 
-func buildIngesterRequest(tenantID string, parent pipeline.Request, searchReq *tempopb.SearchRequest, reqCh chan pipeline.Request) error {
+// jobBytes estimates the number of bytes a job searches that starts at startPage of the block.
+func jobBytes(m *backend.BlockMeta, startPage, pages int) uint64 {
+	if m.TotalRecords == 0 {
+		return 0
+	}
+
+	pages = min(pages, int(m.TotalRecords)-startPage)
+	return m.Size_ / uint64(m.TotalRecords) * uint64(pages)
+}
+
+func buildIngesterRequest(tenantID string, parent pipeline.Request, searchReq *tempopb.SearchRequest, mostRecent bool, reqCh chan pipeline.Request) error {
 	subR, err := cloneRequestforQueriers(parent, tenantID, func(r *http.Request) (*http.Request, error) {
 		return api.BuildSearchRequest(r, searchReq)
 	})
@@ -411,6 +540,10 @@ func buildIngesterRequest(tenantID string, parent pipeline.Request, searchReq *t
 		return err
 	}
 
+	if mostRecent {
+		subR.SetResponseData(combiner.SearchJobShard(0))
+	}
+
 	reqCh <- subR
 	return nil
 }
@@ -82,6 +82,15 @@ func (m *mockReader) Shutdown()                                           {}
 
 //nolint:all deprecated
 
+func TestJobBytes(t *testing.T) {
+	m := &backend.BlockMeta{Size_: 1000, TotalRecords: 10}
+
+	assert.Equal(t, uint64(300), jobBytes(m, 0, 3))
+	// the last job covers the remaining pages
+	assert.Equal(t, uint64(100), jobBytes(m, 9, 3))
+	assert.Equal(t, uint64(0), jobBytes(&backend.BlockMeta{}, 0, 1))
+}
+
 func TestBuildBackendRequests(t *testing.T) {
 	tests := []struct {
 		targetBytesPerRequest int
@@ -249,9 +258,13 @@ func TestBackendRequests(t *testing.T) {
 	bm.Size_ = defaultTargetBytesPerRequest * 2
 	bm.TotalRecords = 2
 
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
 	s := &asyncSearchSharder{
-		cfg:    SearchSharderConfig{},
-		reader: &mockReader{metas: []*backend.BlockMeta{bm}},
+		cfg:       SearchSharderConfig{},
+		reader:    &mockReader{metas: []*backend.BlockMeta{bm}},
+		overrides: o,
 	}
 
 	tests := []struct {
@@ -317,7 +330,7 @@ func TestBackendRequests(t *testing.T) {
 
 			ctx, cancelCause := context.WithCancelCause(context.Background())
 			pipelineRequest := pipeline.NewHTTPRequest(r)
-			jobs, blocks, blockBytes := s.backendRequests(ctx, "test", pipelineRequest, searchReq, reqCh, cancelCause)
+			jobs, blocks, blockBytes, _ := s.backendRequests(ctx, "test", pipelineRequest, searchReq, reqCh, cancelCause)
 			require.Equal(t, tc.expectedJobs, jobs)
 			require.Equal(t, tc.expectedBlocks, blocks)
 			require.Equal(t, tc.expectedBlockBytes, blockBytes)
@@ -334,6 +347,60 @@ func TestBackendRequests(t *testing.T) {
 	}
 }
 
+func TestBackendRequestsMostRecent(t *testing.T) {
+	newBlock := func(start, end int64, records uint32) *backend.BlockMeta {
+		bm := backend.NewBlockMeta("test", uuid.New(), "wdwad", backend.EncGZIP, "asdf")
+		bm.StartTime = time.Unix(start, 0)
+		bm.EndTime = time.Unix(end, 0)
+		bm.Size_ = defaultTargetBytesPerRequest * uint64(records)
+		bm.TotalRecords = records
+		return bm
+	}
+	older := newBlock(100, 200, 1)
+	newest := newBlock(250, 400, 2)
+	newer := newBlock(150, 300, 1)
+
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	s := &asyncSearchSharder{
+		cfg:       SearchSharderConfig{},
+		reader:    &mockReader{metas: []*backend.BlockMeta{older, newest, newer}},
+		overrides: o,
+	}
+
+	r := httptest.NewRequest("GET", "/?q="+url.QueryEscape("{} with (most_recent=true)")+"&start=100&end=400", nil)
+	searchReq, err := api.ParseSearchRequest(r)
+	require.NoError(t, err)
+
+	reqCh := make(chan pipeline.Request)
+	ctx, cancelCause := context.WithCancelCause(context.Background())
+	jobs, _, _, shards := s.backendRequests(ctx, "test", pipeline.NewHTTPRequest(r), searchReq, reqCh, cancelCause)
+	require.Equal(t, 4, jobs)
+
+	// the first shard is left for the ingesters. every shard is complete through the end of the next block
+	require.Equal(t, []combiner.SearchShards{
+		{TotalJobs: 0, CompletedThroughSeconds: 401},
+		{TotalJobs: 2, CompletedThroughSeconds: 301},
+		{TotalJobs: 1, CompletedThroughSeconds: 201},
+		{TotalJobs: 1, CompletedThroughSeconds: 0},
+	}, shards)
+
+	// jobs are sent newest block first and tagged with their shard
+	var actual []string
+	for req := range reqCh {
+		blockID := req.HTTPRequest().URL.Query().Get("blockID")
+		actual = append(actual, fmt.Sprintf("%s-%d", blockID, req.ResponseData()))
+	}
+	require.NoError(t, ctx.Err())
+	require.Equal(t, []string{
+		newest.BlockID.String() + "-1",
+		newest.BlockID.String() + "-1",
+		newer.BlockID.String() + "-2",
+		older.BlockID.String() + "-3",
+	}, actual)
+}
+
 func TestIngesterRequests(t *testing.T) {
 	nownow := time.Now()
 
@@ -747,7 +814,8 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	o, err = overrides.NewOverrides(overrides.Config{
 		Defaults: overrides.Overrides{
 			Read: overrides.ReadOverrides{
-				MaxSearchDuration: model.Duration(time.Minute),
+				MaxSearchDuration:  model.Duration(time.Minute),
+				MaxSpansPerSpanSet: 20,
 			},
 		},
 	}, nil, prometheus.DefaultRegisterer)
@@ -757,6 +825,7 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 		ConcurrentRequests:    defaultConcurrentRequests,
 		TargetBytesPerRequest: defaultTargetBytesPerRequest,
 		MaxDuration:           5 * time.Minute,
+		MaxSpansPerSpanSet:    100,
 	}, log.NewNopLogger())
 	testRT = sharder.Wrap(next)
 
@@ -764,6 +833,12 @@ func TestSearchSharderRoundTripBadRequest(t *testing.T) {
 	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
 	resp, err = testRT.RoundTrip(pipeline.NewHTTPRequest(req))
 	testBadRequestFromResponses(t, resp, err, "range specified by start and end exceeds 1m0s. received start=1000 end=1500")
+
+	// test spans per span set error with overrides
+	req = httptest.NewRequest("GET", "/?spss=50", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+	resp, err = testRT.RoundTrip(pipeline.NewHTTPRequest(req))
+	testBadRequestFromResponses(t, resp, err, "spans per span set exceeds 20. received 50")
 }
 
 func testBadRequestFromResponses(t *testing.T, resp pipeline.Responses[combiner.PipelineResponse], err error, expectedBody string) {
@@ -802,6 +877,16 @@ func TestAdjustLimit(t *testing.T) {
 	require.EqualError(t, err, "limit 25 exceeds max limit 20")
 }
 
+func TestResolveTenantLimit(t *testing.T) {
+	limits := map[string]int{"a": 5, "b": 3}
+	tenantLimit := func(tenantID string) int { return limits[tenantID] }
+
+	require.Equal(t, 10, resolveTenantLimit("c", 10, tenantLimit))
+	require.Equal(t, 5, resolveTenantLimit("a", 10, tenantLimit))
+	require.Equal(t, 5, resolveTenantLimit("a", 0, tenantLimit))
+	require.Equal(t, 3, resolveTenantLimit("a|b|c", 10, tenantLimit))
+}
+
 func TestMaxDuration(t *testing.T) {
 	//
 	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.DefaultRegisterer)
@@ -833,6 +918,40 @@ func TestMaxDuration(t *testing.T) {
 	assert.Equal(t, 10*time.Minute, actual)
 }
 
+func TestSearchSharderJobOverrides(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Read: overrides.ReadOverrides{
+				SearchConcurrentJobs:    20,
+				SearchTargetBytesPerJob: 1024,
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	sharder := asyncSearchSharder{
+		cfg: SearchSharderConfig{
+			ConcurrentRequests:    10,
+			TargetBytesPerRequest: 2048,
+		},
+		overrides: o,
+	}
+	require.Equal(t, 20, sharder.concurrentRequests("test"))
+	require.Equal(t, 1024, sharder.targetBytesPerRequest("test"))
+
+	// cache warming uses the override only to lower the concurrency
+	sharder.cfg.lowerConcurrencyOnly = true
+	require.Equal(t, 10, sharder.concurrentRequests("test"))
+	sharder.cfg.ConcurrentRequests = 30
+	require.Equal(t, 20, sharder.concurrentRequests("test"))
+
+	o, err = overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+	sharder.overrides = o
+	require.Equal(t, 30, sharder.concurrentRequests("test"))
+	require.Equal(t, 2048, sharder.targetBytesPerRequest("test"))
+}
+
 func TestHashTraceQLQuery(t *testing.T) {
 	// exact same queries should have the same hash
 	h1 := hashForSearchRequest(&tempopb.SearchRequest{Query: "{ span.foo = `bar` }"})
@@ -860,6 +979,10 @@ func TestHashTraceQLQuery(t *testing.T) {
 	h1 = hashForSearchRequest(&tempopb.SearchRequest{Query: ""})
 	require.Equal(t, uint64(0), h1)
 
+	// analyzed queries are not cached
+	h1 = hashForSearchRequest(&tempopb.SearchRequest{Query: "{ span.foo = `bar` } with (analyze=true)"})
+	require.Equal(t, uint64(0), h1)
+
 	// same queries with different spss and limit should have the different hash
 	h1 = hashForSearchRequest(&tempopb.SearchRequest{Query: "{ span.foo = `bar` }", Limit: 1})
 	h2 = hashForSearchRequest(&tempopb.SearchRequest{Query: "{ span.foo = `bar` }", Limit: 2})
@@ -870,6 +993,29 @@ func TestHashTraceQLQuery(t *testing.T) {
 	require.NotEqual(t, h1, h2)
 }
 
+func TestHashTagsSearchRequest(t *testing.T) {
+	// same tags should have the same hash
+	h1 := hashForSearchRequest(&tempopb.SearchRequest{Tags: map[string]string{"service.name": "foo", "name": "bar"}})
+	h2 := hashForSearchRequest(&tempopb.SearchRequest{Tags: map[string]string{"name": "bar", "service.name": "foo"}})
+	require.NotEqual(t, uint64(0), h1)
+	require.Equal(t, h1, h2)
+
+	// different tags should have different hashes
+	h2 = hashForSearchRequest(&tempopb.SearchRequest{Tags: map[string]string{"service.name": "foo", "name": "baz"}})
+	require.NotEqual(t, h1, h2)
+
+	h2 = hashForSearchRequest(&tempopb.SearchRequest{Tags: map[string]string{"service.namefoo": "", "name": "bar"}})
+	require.NotEqual(t, h1, h2)
+
+	// duration filters are part of the hash
+	h2 = hashForSearchRequest(&tempopb.SearchRequest{Tags: map[string]string{"service.name": "foo", "name": "bar"}, MinDurationMs: 10})
+	require.NotEqual(t, h1, h2)
+
+	// no tags and no query should return 0
+	h1 = hashForSearchRequest(&tempopb.SearchRequest{Tags: map[string]string{}})
+	require.Equal(t, uint64(0), h1)
+}
+
 func urisEqual(t *testing.T, expectedURIs, actualURIs []string) {
 	require.Equal(t, len(expectedURIs), len(actualURIs))
 
@@ -67,7 +67,7 @@ var (
 )
 
 type (
-	handlerPostHook func(resp *http.Response, tenant string, bytesProcessed uint64, latency time.Duration, err error)
+	handlerPostHook func(req *http.Request, resp *http.Response, tenant string, bytesProcessed uint64, latency time.Duration, err error)
 )
 
 // todo: remove post hooks and implement as a handler
@@ -88,7 +88,7 @@ func metricsSLOPostHook(cfg SLOConfig) handlerPostHook {
 }
 
 func sloHook(allByTenantCounter, withinSLOByTenantCounter *prometheus.CounterVec, throughputVec prometheus.ObserverVec, cfg SLOConfig) handlerPostHook {
-	return func(resp *http.Response, tenant string, bytesProcessed uint64, latency time.Duration, err error) {
+	return func(_ *http.Request, resp *http.Response, tenant string, bytesProcessed uint64, latency time.Duration, err error) {
 		// most errors are SLO violations but we have few exceptions.
 		if err != nil {
 			// However, gRPC resource exhausted error (429), invalid argument (400), not found (404) and
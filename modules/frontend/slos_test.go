@@ -143,7 +143,7 @@ func TestSLOHook(t *testing.T) {
 				StatusCode: tc.httpStatusCode,
 			}
 
-			hook(resp, "test", uint64(tc.bytesProcessed), tc.latency, tc.err)
+			hook(nil, resp, "test", uint64(tc.bytesProcessed), tc.latency, tc.err)
 
 			actualCompleted, err := test.GetCounterValue(allCounter.WithLabelValues("test", resultCompleted))
 			require.NoError(t, err)
@@ -178,7 +178,7 @@ func TestBadRequest(t *testing.T) {
 		Body:       io.NopCloser(strings.NewReader("foo")),
 	}
 
-	hook(res, "test", 0, 0, nil)
+	hook(nil, res, "test", 0, 0, nil)
 
 	actualCompleted, err := test.GetCounterValue(allCounter.WithLabelValues("test", resultCompleted))
 	require.NoError(t, err)
@@ -374,7 +374,7 @@ func TestCanceledRequest(t *testing.T) {
 				Body:       io.NopCloser(strings.NewReader("foo")),
 			}
 
-			hook(res, "test", 0, tt.latency, tt.err)
+			hook(nil, res, "test", 0, tt.latency, tt.err)
 
 			actualCompleted, err := test.GetCounterValue(allCounter.WithLabelValues("test", resultCompleted))
 			require.NoError(t, err)
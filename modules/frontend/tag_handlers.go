@@ -36,9 +36,9 @@ var (
 // streaming grpc handlers
 
 // newTagsStreamingGRPCHandler returns a handler that streams results from the HTTP handler
-func newTagsStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, logger log.Logger) streamingTagsHandler {
+func newTagsStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, audit *auditLogger, logger log.Logger) streamingTagsHandler {
 	downstreamPath := path.Join(apiPrefix, api.PathSearchTags)
-	postSLOHook := metadataSLOPostHook(cfg.Search.MetadataSLO)
+	postSLOHook := audit.wrap(metadataOp, metadataSLOPostHook(cfg.Search.MetadataSLO))
 
 	return func(req *tempopb.SearchTagsRequest, srv tempopb.StreamingQuerier_SearchTagsServer) error {
 		httpReq, tenant, err := buildTagsRequestAndExtractTenant(srv.Context(), req, downstreamPath, logger)
@@ -62,16 +62,16 @@ func newTagsStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[com
 		if finalResponse != nil && finalResponse.Metrics != nil {
 			bytesProcessed = finalResponse.Metrics.InspectedBytes
 		}
-		postSLOHook(nil, tenant, bytesProcessed, duration, err)
+		postSLOHook(httpReq, nil, tenant, bytesProcessed, duration, err)
 		logTagsResult(logger, tenant, "SearchTagsStreaming", req.Scope, req.End-req.Start, duration.Seconds(), bytesProcessed, err)
 
 		return err
 	}
 }
 
-func newTagsV2StreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, logger log.Logger) streamingTagsV2Handler {
+func newTagsV2StreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, audit *auditLogger, logger log.Logger) streamingTagsV2Handler {
 	downstreamPath := path.Join(apiPrefix, api.PathSearchTagsV2)
-	postSLOHook := metadataSLOPostHook(cfg.Search.MetadataSLO)
+	postSLOHook := audit.wrap(metadataOp, metadataSLOPostHook(cfg.Search.MetadataSLO))
 
 	return func(req *tempopb.SearchTagsRequest, srv tempopb.StreamingQuerier_SearchTagsV2Server) error {
 		httpReq, tenant, err := buildTagsRequestAndExtractTenant(srv.Context(), req, downstreamPath, logger)
@@ -95,15 +95,15 @@ func newTagsV2StreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[c
 		if finalResponse != nil && finalResponse.Metrics != nil {
 			bytesProcessed = finalResponse.Metrics.InspectedBytes
 		}
-		postSLOHook(nil, tenant, bytesProcessed, duration, err)
+		postSLOHook(httpReq, nil, tenant, bytesProcessed, duration, err)
 		logTagsResult(logger, tenant, "SearchTagsV2Streaming", req.Scope, req.End-req.Start, duration.Seconds(), bytesProcessed, err)
 
 		return err
 	}
 }
 
-func newTagValuesStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, logger log.Logger) streamingTagValuesHandler {
-	postSLOHook := metadataSLOPostHook(cfg.Search.MetadataSLO)
+func newTagValuesStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, audit *auditLogger, logger log.Logger) streamingTagValuesHandler {
+	postSLOHook := audit.wrap(metadataOp, metadataSLOPostHook(cfg.Search.MetadataSLO))
 
 	return func(req *tempopb.SearchTagValuesRequest, srv tempopb.StreamingQuerier_SearchTagValuesServer) error {
 		// we have to interpolate the tag name into the path so that when it is routed to the queriers
@@ -132,15 +132,15 @@ func newTagValuesStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTrippe
 		if finalResponse != nil && finalResponse.Metrics != nil {
 			bytesProcessed = finalResponse.Metrics.InspectedBytes
 		}
-		postSLOHook(nil, tenant, bytesProcessed, duration, err)
+		postSLOHook(httpReq, nil, tenant, bytesProcessed, duration, err)
 		logTagValuesResult(logger, tenant, "SearchTagValuesStreaming", req.TagName, req.Query, req.End-req.Start, duration.Seconds(), bytesProcessed, err)
 
 		return err
 	}
 }
 
-func newTagValuesV2StreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, logger log.Logger) streamingTagValuesV2Handler {
-	postSLOHook := metadataSLOPostHook(cfg.Search.MetadataSLO)
+func newTagValuesV2StreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], apiPrefix string, o overrides.Interface, audit *auditLogger, logger log.Logger) streamingTagValuesV2Handler {
+	postSLOHook := audit.wrap(metadataOp, metadataSLOPostHook(cfg.Search.MetadataSLO))
 
 	return func(req *tempopb.SearchTagValuesRequest, srv tempopb.StreamingQuerier_SearchTagValuesV2Server) error {
 		// we have to interpolate the tag name into the path so that when it is routed to the queriers
@@ -169,7 +169,7 @@ func newTagValuesV2StreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTrip
 		if finalResponse != nil && finalResponse.Metrics != nil {
 			bytesProcessed = finalResponse.Metrics.InspectedBytes
 		}
-		postSLOHook(nil, tenant, bytesProcessed, duration, err)
+		postSLOHook(httpReq, nil, tenant, bytesProcessed, duration, err)
 		logTagValuesResult(logger, tenant, "SearchTagValuesV2Streaming", req.TagName, req.Query, req.End-req.Start, duration.Seconds(), bytesProcessed, err)
 
 		return err
@@ -177,8 +177,8 @@ func newTagValuesV2StreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTrip
 }
 
 // HTTP Handlers
-func newTagsHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, logger log.Logger) http.RoundTripper {
-	postSLOHook := metadataSLOPostHook(cfg.Search.MetadataSLO)
+func newTagsHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, audit *auditLogger, logger log.Logger) http.RoundTripper {
+	postSLOHook := audit.wrap(metadataOp, metadataSLOPostHook(cfg.Search.MetadataSLO))
 
 	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		// if error is not nil, return error Response but suppress the error
@@ -204,15 +204,15 @@ func newTagsHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.Pip
 		}
 
 		duration := time.Since(start)
-		postSLOHook(resp, tenant, bytesProcessed, duration, err)
+		postSLOHook(req, resp, tenant, bytesProcessed, duration, err)
 		logTagsResult(logger, tenant, "SearchTags", scope, rangeDur, duration.Seconds(), bytesProcessed, err)
 
 		return resp, err
 	})
 }
 
-func newTagsV2HTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, logger log.Logger) http.RoundTripper {
-	postSLOHook := metadataSLOPostHook(cfg.Search.MetadataSLO)
+func newTagsV2HTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, audit *auditLogger, logger log.Logger) http.RoundTripper {
+	postSLOHook := audit.wrap(metadataOp, metadataSLOPostHook(cfg.Search.MetadataSLO))
 
 	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		// if error is not nil, return error Response but suppress the error
@@ -238,15 +238,15 @@ func newTagsV2HTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.P
 		}
 
 		duration := time.Since(start)
-		postSLOHook(resp, tenant, bytesProcessed, duration, err)
+		postSLOHook(req, resp, tenant, bytesProcessed, duration, err)
 		logTagsResult(logger, tenant, "SearchTagsV2", scope, rangeDur, duration.Seconds(), bytesProcessed, err)
 
 		return resp, err
 	})
 }
 
-func newTagValuesHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, logger log.Logger) http.RoundTripper {
-	postSLOHook := metadataSLOPostHook(cfg.Search.MetadataSLO)
+func newTagValuesHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, audit *auditLogger, logger log.Logger) http.RoundTripper {
+	postSLOHook := audit.wrap(metadataOp, metadataSLOPostHook(cfg.Search.MetadataSLO))
 
 	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		// if error is not nil, return error Response but suppress the error
@@ -274,15 +274,15 @@ func newTagValuesHTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combine
 		}
 
 		duration := time.Since(start)
-		postSLOHook(resp, tenant, bytesProcessed, duration, err)
+		postSLOHook(req, resp, tenant, bytesProcessed, duration, err)
 		logTagValuesResult(logger, tenant, "SearchTagValues", tagName, query, rangeDur, duration.Seconds(), bytesProcessed, err)
 
 		return resp, err
 	})
 }
 
-func newTagValuesV2HTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, logger log.Logger) http.RoundTripper {
-	postSLOHook := metadataSLOPostHook(cfg.Search.MetadataSLO)
+func newTagValuesV2HTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, audit *auditLogger, logger log.Logger) http.RoundTripper {
+	postSLOHook := audit.wrap(metadataOp, metadataSLOPostHook(cfg.Search.MetadataSLO))
 
 	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		// if error is not nil, return error Response but suppress the error
@@ -310,7 +310,7 @@ func newTagValuesV2HTTPHandler(cfg Config, next pipeline.AsyncRoundTripper[combi
 		}
 
 		duration := time.Since(start)
-		postSLOHook(resp, tenant, bytesProcessed, duration, err)
+		postSLOHook(req, resp, tenant, bytesProcessed, duration, err)
 		logTagValuesResult(logger, tenant, "SearchTagValuesV2", tagName, query, rangeDur, duration.Seconds(), bytesProcessed, err)
 
 		return resp, err
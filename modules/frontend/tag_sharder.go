@@ -86,7 +86,7 @@ func (r *tagValueSearchRequest) end() uint32 {
 
 func (r *tagValueSearchRequest) hash() uint64 {
 	hash := fnv1a.HashString64(r.request.TagName)
-	hash = fnv1a.AddString64(hash, traceql.ExtractMatchers(r.request.Query))
+	hash = fnv1a.AddString64(hash, traceql.TagValuesQuery(r.request.Query))
 
 	return hash
 }
@@ -233,7 +233,7 @@ func (s searchTagSharder) RoundTrip(pipelineRequest pipeline.Request) (pipeline.
 	// combiners, and log these metrics in the logger like we do in search_handlers.go
 
 	// execute requests
-	return pipeline.NewAsyncSharderChan(ctx, s.cfg.ConcurrentRequests, reqCh, nil, s.next), nil
+	return pipeline.NewAsyncSharderChan(ctx, concurrentJobs(s.overrides.SearchConcurrentJobs(tenantID), s.cfg.ConcurrentRequests, s.cfg.lowerConcurrencyOnly), reqCh, nil, s.next), nil
 }
 
 // blockMetas returns all relevant blockMetas given a start/end
@@ -275,6 +275,9 @@ func (s searchTagSharder) backendRequests(ctx context.Context, tenantID string,
 	blocks = s.blockMetas(int64(start), int64(end), tenantID)
 
 	targetBytesPerRequest := s.cfg.TargetBytesPerRequest
+	if targetBytes := s.overrides.SearchTargetBytesPerJob(tenantID); targetBytes > 0 {
+		targetBytesPerRequest = targetBytes
+	}
 
 	go func() {
 		s.buildBackendRequests(ctx, tenantID, parent, blocks, targetBytesPerRequest, reqCh, errFn, searchReq)
@@ -307,6 +310,7 @@ func (s searchTagSharder) buildBackendRequests(ctx context.Context, tenantID str
 
 			key := cacheKey(keyPrefix, tenantID, hash, int64(searchReq.start()), int64(searchReq.end()), m, startPage, pages)
 			pipelineR.SetCacheKey(key)
+			pipelineR.SetSize(jobBytes(m, startPage, pages))
 
 			select {
 			case reqCh <- pipelineR:
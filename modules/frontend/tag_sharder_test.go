@@ -92,9 +92,13 @@ func TestTagsBackendRequests(t *testing.T) {
 	bm.Size_ = defaultTargetBytesPerRequest * 2
 	bm.TotalRecords = 2
 
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
 	s := &searchTagSharder{
-		cfg:    SearchSharderConfig{},
-		reader: &mockReader{metas: []*backend.BlockMeta{bm}},
+		cfg:       SearchSharderConfig{},
+		reader:    &mockReader{metas: []*backend.BlockMeta{bm}},
+		overrides: o,
 	}
 
 	type params struct {
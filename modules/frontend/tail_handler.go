@@ -0,0 +1,394 @@
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level" //nolint:all //deprecated
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/status"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/codes"
+
+	"github.com/grafana/tempo/modules/frontend/combiner"
+	"github.com/grafana/tempo/modules/frontend/interceptor"
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+)
+
+const (
+	headerContentTypeNDJSON = "application/x-ndjson"
+
+	tailOp = "tail"
+)
+
+var (
+	metricActiveTails = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "query_frontend_active_tails",
+		Help:      "The number of live tails currently streaming search results.",
+	}, []string{"tenant"})
+
+	errTooManyTails = errors.New("too many live tails for the tenant")
+	errTailDisabled = errors.New("live tail is disabled")
+)
+
+type TailConfig struct {
+	// enables the tail endpoints. every tail queries the ingesters each poll interval, so it's off by default
+	Enabled bool `yaml:"enabled"`
+	// how often the recently ingested traces are searched for new matches
+	PollInterval time.Duration `yaml:"poll_interval,omitempty"`
+	// the time range before each poll that is searched. it must cover the delay between a span ending and
+	// becoming searchable in the ingesters
+	Lookback time.Duration `yaml:"lookback,omitempty"`
+	// the maximum time a tail streams results before it's ended by the frontend. 0 is no limit
+	MaxDuration time.Duration `yaml:"max_duration,omitempty"`
+	// the maximum number of concurrent tails of a tenant. 0 is no limit
+	MaxTailsPerTenant int `yaml:"max_tails_per_tenant,omitempty"`
+}
+
+func (cfg *TailConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.PollInterval <= 0 {
+		return errors.New("frontend tail poll interval should be greater than 0")
+	}
+	if cfg.Lookback <= 0 {
+		return errors.New("frontend tail lookback should be greater than 0")
+	}
+	return nil
+}
+
+// tailer streams the results of a search as matching spans arrive. every poll it sends a single search of the
+// recently ingested traces to the ingesters and sends the traces that are new or have more matching spans than
+// before. the backend is never searched.
+type tailer struct {
+	cfg               TailConfig
+	responseConsumers int
+	defaultLimit      uint32
+	maxLimit          uint32
+	apiTimeout        time.Duration
+	searchTimeout     interceptor.TenantTimeoutFunc
+	next              pipeline.AsyncRoundTripper[combiner.PipelineResponse]
+	searchPath        string
+	postHook          handlerPostHook
+	logger            log.Logger
+
+	mtx    sync.Mutex
+	active map[string]int
+}
+
+func newTailer(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, audit *auditLogger, apiPrefix string, logger log.Logger) *tailer {
+	t := &tailer{
+		cfg:               cfg.Tail,
+		responseConsumers: cfg.ResponseConsumers,
+		defaultLimit:      cfg.Search.Sharder.DefaultLimit,
+		maxLimit:          cfg.Search.Sharder.MaxLimit,
+		apiTimeout:        cfg.APITimeout,
+		next:              next,
+		searchPath:        path.Join(apiPrefix, api.PathSearch),
+		postHook:          audit.wrap(tailOp, func(*http.Request, *http.Response, string, uint64, time.Duration, error) {}),
+		logger:            log.With(logger, "component", "tail"),
+		active:            map[string]int{},
+	}
+	if o != nil {
+		t.searchTimeout = o.SearchTimeout
+	}
+	return t
+}
+
+// newTailHTTPHandler returns a handler that streams the results of a tail as newline delimited JSON search
+// responses.
+func newTailHTTPHandler(t *tailer, logger log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !t.cfg.Enabled {
+			http.Error(w, errTailDisabled.Error(), http.StatusNotFound)
+			return
+		}
+
+		searchReq, err := api.ParseSearchRequest(req)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := t.validate(searchReq); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		// a tail outlives the write timeout of the server. ignore the error if the writer doesn't support it
+		_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+		marshaler := &jsonpb.Marshaler{}
+		started := false
+		err = t.tail(req.Context(), searchReq, func(resp *tempopb.SearchResponse) error {
+			if !started {
+				w.Header().Set(api.HeaderContentType, headerContentTypeNDJSON)
+				w.WriteHeader(http.StatusOK)
+				started = true
+			}
+			if err := marshaler.Marshal(w, resp); err != nil {
+				return err
+			}
+			if _, err := w.Write([]byte("\n")); err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		})
+
+		switch {
+		case errors.Is(err, errTooManyTails):
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+		case err != nil && !started:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		case err != nil:
+			// the status has already been sent, the stream ends early
+			level.Error(logger).Log("msg", "tail: search failed", "err", err)
+		case !started:
+			w.WriteHeader(http.StatusOK)
+		}
+	})
+}
+
+// newTailStreamingGRPCHandler returns a handler that streams the results of a tail.
+func newTailStreamingGRPCHandler(t *tailer, logger log.Logger) func(req *tempopb.SearchRequest, srv tempopb.Tail_TailServer) error {
+	return func(req *tempopb.SearchRequest, srv tempopb.Tail_TailServer) error {
+		if !t.cfg.Enabled {
+			return status.Error(codes.Unimplemented, errTailDisabled.Error())
+		}
+		if err := t.validate(req); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		err := t.tail(srv.Context(), req, srv.Send)
+		if errors.Is(err, errTooManyTails) {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		if err != nil {
+			level.Error(logger).Log("msg", "tail streaming: search failed", "err", err)
+			return grpcErrorFromTail(err)
+		}
+		return nil
+	}
+}
+
+func grpcErrorFromTail(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// validate checks the request and applies the default limit.
+func (t *tailer) validate(req *tempopb.SearchRequest) error {
+	if req.Query == "" {
+		return errors.New("tail requires a TraceQL query")
+	}
+	if _, err := traceql.Parse(req.Query); err != nil {
+		return fmt.Errorf("invalid TraceQL query: %w", err)
+	}
+
+	limit, err := adjustLimit(req.Limit, t.defaultLimit, t.maxLimit)
+	if err != nil {
+		return err
+	}
+	req.Limit = limit
+	return nil
+}
+
+// tail searches for new matches every poll interval and sends them until the context is done or the maximum
+// duration has passed. the request must be validated.
+func (t *tailer) tail(ctx context.Context, req *tempopb.SearchRequest, send func(*tempopb.SearchResponse) error) (err error) {
+	tenant, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return err
+	}
+
+	if !t.acquire(tenant) {
+		return errTooManyTails
+	}
+	defer t.release(tenant)
+
+	// the whole tail is a single record in the audit log
+	var bytesProcessed uint64
+	start := time.Now()
+	defer func() {
+		auditReq, _ := t.buildRequest(ctx, &tempopb.SearchRequest{Query: req.Query, Limit: req.Limit, SpansPerSpanSet: req.SpansPerSpanSet})
+		t.postHook(auditReq, nil, tenant, bytesProcessed, time.Since(start), err)
+	}()
+
+	if t.cfg.MaxDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.cfg.MaxDuration)
+		defer cancel()
+	}
+
+	level.Info(t.logger).Log("msg", "tail started", "tenant", tenant, "query", req.Query)
+	defer level.Info(t.logger).Log("msg", "tail ended", "tenant", tenant, "query", req.Query)
+
+	ticker := time.NewTicker(t.cfg.PollInterval)
+	defer ticker.Stop()
+
+	seen := tailedTraces{}
+	for {
+		now := time.Now()
+		resp, err := t.poll(ctx, tenant, req, now)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if resp.Metrics != nil {
+			bytesProcessed += resp.Metrics.InspectedBytes
+		}
+
+		if traces := seen.update(resp.Traces, now, t.cfg.Lookback); len(traces) > 0 {
+			if err := send(&tempopb.SearchResponse{Traces: traces}); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll searches the traces of the lookback before now. the search is a single job for the queriers without block
+// parameters, so it only searches the recent data in the ingesters. like any other search, it's subject to the
+// search timeout of the tenant.
+func (t *tailer) poll(ctx context.Context, tenant string, req *tempopb.SearchRequest, now time.Time) (*tempopb.SearchResponse, error) {
+	if timeout := interceptor.ResolveTimeout(tenant, t.apiTimeout, t.searchTimeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	httpReq, err := t.buildRequest(ctx, &tempopb.SearchRequest{
+		Query:           req.Query,
+		Start:           uint32(now.Add(-t.cfg.Lookback).Unix()),
+		End:             uint32(now.Unix()) + 1,
+		Limit:           req.Limit,
+		SpansPerSpanSet: req.SpansPerSpanSet,
+	})
+	if err != nil {
+		return nil, err
+	}
+	prepareRequestForQueriers(httpReq, tenant)
+
+	// only the final response is used, the diffs are discarded
+	comb := combiner.NewTypedSearch(int(req.Limit), true, 0)
+	collector := pipeline.NewGRPCCollector[*tempopb.SearchResponse](t.next, t.responseConsumers, comb, func(*tempopb.SearchResponse) error {
+		return nil
+	})
+	if err := collector.RoundTrip(httpReq); err != nil {
+		return nil, err
+	}
+
+	return comb.GRPCFinal()
+}
+
+func (t *tailer) buildRequest(ctx context.Context, searchReq *tempopb.SearchRequest) (*http.Request, error) {
+	return api.BuildSearchRequest((&http.Request{
+		Method: http.MethodGet,
+		URL:    &url.URL{Path: t.searchPath},
+		Header: http.Header{},
+		Body:   http.NoBody,
+	}).WithContext(ctx), searchReq)
+}
+
+func (t *tailer) acquire(tenant string) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.cfg.MaxTailsPerTenant > 0 && t.active[tenant] >= t.cfg.MaxTailsPerTenant {
+		return false
+	}
+	t.active[tenant]++
+	metricActiveTails.WithLabelValues(tenant).Set(float64(t.active[tenant]))
+	return true
+}
+
+func (t *tailer) release(tenant string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.active[tenant]--
+	if t.active[tenant] <= 0 {
+		delete(t.active, tenant)
+		metricActiveTails.DeleteLabelValues(tenant)
+		return
+	}
+	metricActiveTails.WithLabelValues(tenant).Set(float64(t.active[tenant]))
+}
+
+// tailedTraces are the traces sent by a tail with the number of matching spans they were sent with.
+type tailedTraces map[string]tailedTrace
+
+type tailedTrace struct {
+	matched  uint32
+	lastSeen time.Time
+}
+
+// update returns the traces that haven't been sent or have more matching spans than when they were sent. traces
+// that haven't been seen for the lookback are forgotten, they are only found again if new spans arrive.
+func (s tailedTraces) update(traces []*tempopb.TraceSearchMetadata, now time.Time, lookback time.Duration) []*tempopb.TraceSearchMetadata {
+	var updated []*tempopb.TraceSearchMetadata
+	for _, tr := range traces {
+		matched := matchedSpans(tr)
+
+		prev, ok := s[tr.TraceID]
+		if !ok || matched > prev.matched {
+			updated = append(updated, tr)
+			prev.matched = matched
+		}
+		prev.lastSeen = now
+		s[tr.TraceID] = prev
+	}
+
+	for id, tr := range s {
+		if tr.lastSeen.Before(now.Add(-lookback)) {
+			delete(s, id)
+		}
+	}
+
+	return updated
+}
+
+func matchedSpans(tr *tempopb.TraceSearchMetadata) uint32 {
+	if len(tr.SpanSets) == 0 {
+		if tr.SpanSet != nil {
+			return tr.SpanSet.Matched
+		}
+		return 0
+	}
+
+	var matched uint32
+	for _, ss := range tr.SpanSets {
+		matched += ss.Matched
+	}
+	return matched
+}
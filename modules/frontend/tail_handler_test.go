@@ -0,0 +1,185 @@
+package frontend
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/status"
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"google.golang.org/grpc/codes"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+func TestTail(t *testing.T) {
+	// the second and later polls find an additional matching span in the trace
+	newNext := func(polls *atomic.Int32) *mockRoundTripper {
+		return &mockRoundTripper{
+			responseFn: func() proto.Message {
+				matched := uint32(1)
+				if polls.Inc() > 1 {
+					matched = 2
+				}
+				return &tempopb.SearchResponse{
+					Traces: []*tempopb.TraceSearchMetadata{
+						{TraceID: "1", SpanSets: []*tempopb.SpanSet{{Matched: matched}}},
+					},
+					Metrics: &tempopb.SearchMetrics{},
+				}
+			},
+		}
+	}
+
+	withTail := func(cfg *Config) {
+		cfg.Tail = TailConfig{
+			Enabled:      true,
+			PollInterval: 10 * time.Millisecond,
+			Lookback:     time.Minute,
+			MaxDuration:  2 * time.Second,
+		}
+	}
+
+	req := &tempopb.SearchRequest{Query: "{ status = error }"}
+
+	t.Run("grpc", func(t *testing.T) {
+		polls := atomic.NewInt32(0)
+		f := frontendWithSettings(t, newNext(polls), nil, nil, nil, withTail)
+
+		var responses []*tempopb.SearchResponse
+		srv := newMockStreamingServer[*tempopb.SearchResponse]("foo", func(_ int, resp *tempopb.SearchResponse) {
+			responses = append(responses, resp)
+		})
+		require.NoError(t, f.Tail(req, srv))
+
+		// the trace is sent when it's found and again when it has more matching spans
+		require.Len(t, responses, 2)
+		require.Equal(t, uint32(1), responses[0].Traces[0].SpanSets[0].Matched)
+		require.Equal(t, uint32(2), responses[1].Traces[0].SpanSets[0].Matched)
+		require.Greater(t, polls.Load(), int32(2))
+	})
+
+	t.Run("http", func(t *testing.T) {
+		f := frontendWithSettings(t, newNext(atomic.NewInt32(0)), nil, nil, nil, withTail)
+
+		httpReq := httptest.NewRequest(http.MethodGet, "/api/tail?q="+url.QueryEscape(req.Query), nil)
+		httpReq = httpReq.WithContext(user.InjectOrgID(httpReq.Context(), "foo"))
+		rec := httptest.NewRecorder()
+		f.TailHandler.ServeHTTP(rec, httpReq)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, headerContentTypeNDJSON, rec.Header().Get("Content-Type"))
+
+		var responses []*tempopb.SearchResponse
+		scanner := bufio.NewScanner(rec.Body)
+		for scanner.Scan() {
+			resp := &tempopb.SearchResponse{}
+			require.NoError(t, jsonpb.UnmarshalString(scanner.Text(), resp))
+			responses = append(responses, resp)
+		}
+		require.Len(t, responses, 2)
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		f := frontendWithSettings(t, newNext(atomic.NewInt32(0)), nil, nil, nil, withTail)
+
+		srv := newMockStreamingServer[*tempopb.SearchResponse]("foo", nil)
+		err := f.Tail(&tempopb.SearchRequest{Query: "{ foo"}, srv)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		httpReq := httptest.NewRequest(http.MethodGet, "/api/tail", nil)
+		httpReq = httpReq.WithContext(user.InjectOrgID(httpReq.Context(), "foo"))
+		rec := httptest.NewRecorder()
+		f.TailHandler.ServeHTTP(rec, httpReq)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		f := frontendWithSettings(t, nil, nil, nil, nil)
+
+		srv := newMockStreamingServer[*tempopb.SearchResponse]("foo", nil)
+		err := f.Tail(req, srv)
+		require.Equal(t, codes.Unimplemented, status.Code(err))
+
+		rec := httptest.NewRecorder()
+		f.TailHandler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/tail", nil))
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestTailPollSearchesIngesters(t *testing.T) {
+	var reqs []*http.Request
+	next := pipeline.Build(nil, nil, pipeline.RoundTripperFunc(func(r pipeline.Request) (*http.Response, error) {
+		reqs = append(reqs, r.HTTPRequest())
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader("{}")),
+		}, nil
+	}))
+
+	tl := newTailer(Config{Tail: TailConfig{Enabled: true, PollInterval: time.Second, Lookback: time.Minute}}, next, nil, nil, "", log.NewNopLogger())
+
+	now := time.Unix(1000, 0)
+	_, err := tl.poll(context.Background(), "foo", &tempopb.SearchRequest{Query: "{ status = error }", Limit: 20}, now)
+	require.NoError(t, err)
+
+	// a poll is a single job for the queriers without block parameters, so only the ingesters are searched
+	require.Len(t, reqs, 1)
+	require.False(t, api.IsSearchBlock(reqs[0]))
+	require.True(t, strings.HasPrefix(reqs[0].RequestURI, "/querier/api/search?"))
+	require.Equal(t, "foo", reqs[0].Header.Get(user.OrgIDHeaderName))
+
+	searchReq, err := api.ParseSearchRequest(reqs[0])
+	require.NoError(t, err)
+	require.Equal(t, uint32(940), searchReq.Start)
+	require.Equal(t, uint32(1001), searchReq.End)
+}
+
+func TestTailMaxTailsPerTenant(t *testing.T) {
+	tl := newTailer(Config{Tail: TailConfig{Enabled: true, PollInterval: time.Second, Lookback: time.Minute, MaxTailsPerTenant: 1}}, nil, nil, nil, "", log.NewNopLogger())
+
+	require.True(t, tl.acquire("foo"))
+	require.False(t, tl.acquire("foo"))
+	require.True(t, tl.acquire("bar"))
+
+	err := tl.tail(user.InjectOrgID(context.Background(), "foo"), &tempopb.SearchRequest{}, nil)
+	require.ErrorIs(t, err, errTooManyTails)
+
+	tl.release("foo")
+	require.True(t, tl.acquire("foo"))
+}
+
+func TestTailedTracesUpdate(t *testing.T) {
+	now := time.Now()
+	seen := tailedTraces{}
+
+	tr := func(id string, matched uint32) *tempopb.TraceSearchMetadata {
+		return &tempopb.TraceSearchMetadata{TraceID: id, SpanSet: &tempopb.SpanSet{Matched: matched}}
+	}
+
+	updated := seen.update([]*tempopb.TraceSearchMetadata{tr("1", 1), tr("2", 1)}, now, time.Minute)
+	require.Len(t, updated, 2)
+
+	// only traces with more matching spans are sent again
+	updated = seen.update([]*tempopb.TraceSearchMetadata{tr("1", 1), tr("2", 3)}, now.Add(time.Second), time.Minute)
+	require.Equal(t, []*tempopb.TraceSearchMetadata{tr("2", 3)}, updated)
+
+	// traces that haven't been seen for the lookback are forgotten
+	updated = seen.update([]*tempopb.TraceSearchMetadata{tr("2", 3)}, now.Add(2*time.Minute), time.Minute)
+	require.Empty(t, updated)
+	require.Len(t, seen, 1)
+	require.Contains(t, seen, "2")
+}
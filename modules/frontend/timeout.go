@@ -0,0 +1,59 @@
+package frontend
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/middleware"
+	"github.com/grafana/dskit/user"
+
+	"github.com/grafana/tempo/modules/frontend/interceptor"
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+const apiTimeoutMessage = "unable to process request in the configured timeout"
+
+// NewTenantTimeoutMiddleware returns an http middleware that enforces the per tenant timeout returned by
+// tenantTimeout and falls back to defaultTimeout if the tenant has none. If neither is set, requests are not
+// time limited. The timeout is enforced the same way as the global api_timeout, see middleware.NewTimeoutMiddleware.
+// The middleware must be installed after the auth middleware.
+func NewTenantTimeoutMiddleware(defaultTimeout time.Duration, tenantTimeout interceptor.TenantTimeoutFunc, logger log.Logger) middleware.Interface {
+	return middleware.Func(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			orgID, _ := user.ExtractOrgID(r.Context())
+
+			timeout := interceptor.ResolveTimeout(orgID, defaultTimeout, tenantTimeout)
+			if timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			middleware.NewTimeoutMiddleware(timeout, apiTimeoutMessage, logger).Wrap(next).ServeHTTP(w, r)
+		})
+	})
+}
+
+// StreamingTimeout returns the per tenant timeout for StreamingQuerier and TempoQuery gRPC methods. It
+// can be passed to the interceptor.NewFrontendAPI*TenantTimeout interceptors.
+func StreamingTimeout(o overrides.Interface) interceptor.MethodTimeoutFunc {
+	return func(fullMethod string) interceptor.TenantTimeoutFunc {
+		method, ok := interceptor.QueryMethod(fullMethod)
+		if !ok {
+			return nil
+		}
+
+		switch method {
+		case "FindTraceByID", "StreamTraceByID":
+			return o.TraceByIDTimeout
+		case "Search":
+			return o.SearchTimeout
+		case "SearchTags", "SearchTagsV2", "SearchTagValues", "SearchTagValuesV2":
+			return o.SearchTagsTimeout
+		case "MetricsQueryRange", "MetricsQueryInstant":
+			return o.MetricsTimeout
+		default:
+			return nil
+		}
+	}
+}
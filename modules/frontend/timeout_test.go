@@ -0,0 +1,80 @@
+package frontend
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+)
+
+func TestTenantTimeoutMiddleware(t *testing.T) {
+	fn := func(tenantID string) time.Duration {
+		if tenantID == "fast" {
+			return 10 * time.Millisecond
+		}
+		return 0
+	}
+
+	slow := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+		}
+	})
+
+	handler := NewTenantTimeoutMiddleware(0, fn, log.NewNopLogger()).Wrap(slow)
+
+	// tenant with a timeout is cut off
+	req := httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "fast"))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Equal(t, apiTimeoutMessage, rec.Body.String())
+
+	// tenant without a timeout runs to completion
+	req = httptest.NewRequest("GET", "/", nil)
+	req = req.WithContext(user.InjectOrgID(req.Context(), "other"))
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestStreamingTimeout(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Read: overrides.ReadOverrides{
+				SearchTimeout:     model.Duration(time.Second),
+				SearchTagsTimeout: model.Duration(2 * time.Second),
+				MetricsTimeout:    model.Duration(3 * time.Second),
+				TraceByIDTimeout:  model.Duration(4 * time.Second),
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	timeout := func(fullMethod string) time.Duration {
+		fn := StreamingTimeout(o)(fullMethod)
+		if fn == nil {
+			return 0
+		}
+		return fn("test")
+	}
+
+	require.Equal(t, time.Second, timeout("/tempopb.StreamingQuerier/Search"))
+	require.Equal(t, 2*time.Second, timeout("/tempopb.StreamingQuerier/SearchTagValuesV2"))
+	require.Equal(t, 3*time.Second, timeout("/tempopb.StreamingQuerier/MetricsQueryRange"))
+	require.Equal(t, time.Second, timeout("/tempopb.query.v1.TempoQuery/Search"))
+	require.Equal(t, 4*time.Second, timeout("/tempopb.query.v1.TempoQuery/FindTraceByID"))
+	require.Equal(t, 4*time.Second, timeout("/tempopb.query.v1.TempoQuery/StreamTraceByID"))
+	require.Equal(t, time.Duration(0), timeout("/tempopb.Pusher/PushBytesV2"))
+}
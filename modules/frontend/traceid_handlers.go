@@ -1,23 +1,34 @@
 package frontend
 
 import (
+	"bytes"
+	"context"
 	"io"
 	"net/http"
+	"net/url"
+	"path"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level" //nolint:all //deprecated
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/status"
+	"github.com/gorilla/mux"
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/api"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
+	"google.golang.org/grpc/codes"
 )
 
 // newTraceIDHandler creates a http.handler for trace by id requests
-func newTraceIDHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, combinerFn func(int, string) combiner.Combiner, logger log.Logger) http.RoundTripper {
-	postSLOHook := traceByIDSLOPostHook(cfg.TraceByID.SLO)
+func newTraceIDHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, combinerFn func(int, string) combiner.Combiner, audit *auditLogger, logger log.Logger) http.RoundTripper {
+	postSLOHook := audit.wrap(traceByIDOp, traceByIDSLOPostHook(cfg.TraceByID.SLO))
 
 	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		tenant, err := user.ExtractOrgID(req.Context())
@@ -50,6 +61,16 @@ func newTraceIDHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.Pipe
 			}, nil
 		}
 
+		// the client can request a lower maximum trace size than the tenant limit. it's passed on to the queriers
+		maxBytes, err := api.ParseMaxBytesPerTrace(req)
+		if err != nil {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body:       io.NopCloser(strings.NewReader(err.Error())),
+				Header:     http.Header{},
+			}, nil
+		}
+
 		// check marshalling format
 		marshallingFormat := api.HeaderAcceptJSON
 		if req.Header.Get(api.HeaderAccept) == api.HeaderAcceptProtobuf {
@@ -64,14 +85,14 @@ func newTraceIDHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.Pipe
 			"tenant", tenant,
 			"path", req.URL.Path)
 
-		comb := combinerFn(o.MaxBytesPerTrace(tenant), marshallingFormat)
+		comb := combinerFn(api.MaxBytesPerTrace(maxBytes, o.MaxBytesPerTrace(tenant)), marshallingFormat)
 		rt := pipeline.NewHTTPCollector(next, cfg.ResponseConsumers, comb)
 
 		start := time.Now()
 		resp, err := rt.RoundTrip(req)
 		elapsed := time.Since(start)
 
-		postSLOHook(resp, tenant, 0, elapsed, err)
+		postSLOHook(req, resp, tenant, 0, elapsed, err)
 
 		level.Info(logger).Log(
 			"msg", "trace id response",
@@ -83,3 +104,127 @@ func newTraceIDHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.Pipe
 		return resp, err
 	})
 }
+
+// newTraceIDGRPCHandler returns a handler for trace by id lookups of the TempoQuery gRPC service. the request is
+// passed on to the v2 trace by id HTTP handler and the protobuf response is returned
+func newTraceIDGRPCHandler(next http.RoundTripper, apiPrefix string, logger log.Logger) traceByIDHandler {
+	downstreamPath := path.Join(apiPrefix, strings.TrimSuffix(api.PathTracesV2, "{traceID}"))
+
+	return func(ctx context.Context, req *tempopb.TraceByIDRequest) (*tempopb.TraceByIDResponse, error) {
+		httpReq, err := buildTraceByIDRequest(ctx, downstreamPath, req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := next.RoundTrip(httpReq)
+		if err != nil {
+			level.Error(logger).Log("msg", "trace id grpc: request failed", "err", err)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+		case http.StatusNotFound:
+			return nil, status.Error(codes.NotFound, string(body))
+		case http.StatusBadRequest:
+			return nil, status.Error(codes.InvalidArgument, string(body))
+		case http.StatusTooManyRequests:
+			return nil, status.Error(codes.ResourceExhausted, string(body))
+		default:
+			return nil, status.Error(codes.Internal, string(body))
+		}
+
+		traceResp := &tempopb.TraceByIDResponse{}
+		if err := proto.Unmarshal(body, traceResp); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to unmarshal trace: %s", err.Error())
+		}
+		return traceResp, nil
+	}
+}
+
+// newTraceIDStreamingGRPCHandler returns a handler for streaming trace by id lookups of the TempoQuery gRPC service.
+// the spans are sent as the responses of the jobs are combined, so the trace is never buffered as a whole
+func newTraceIDStreamingGRPCHandler(cfg Config, next pipeline.AsyncRoundTripper[combiner.PipelineResponse], o overrides.Interface, apiPrefix string, audit *auditLogger, logger log.Logger) streamingTraceByIDHandler {
+	postSLOHook := audit.wrap(traceByIDOp, traceByIDSLOPostHook(cfg.TraceByID.SLO))
+	downstreamPath := path.Join(apiPrefix, strings.TrimSuffix(api.PathTracesV2, "{traceID}"))
+
+	return func(req *tempopb.TraceByIDRequest, srv tempopb.TempoQuery_StreamTraceByIDServer) error {
+		ctx := srv.Context()
+
+		httpReq, err := buildTraceByIDRequest(ctx, downstreamPath, req)
+		if err != nil {
+			return err
+		}
+
+		tenant, _ := user.ExtractOrgID(ctx)
+		start := time.Now()
+
+		level.Info(logger).Log(
+			"msg", "trace id streaming request",
+			"tenant", tenant,
+			"path", httpReq.URL.Path)
+
+		sent := 0
+		comb := combiner.NewTypedTraceByIDStreaming(api.MaxBytesPerTrace(int(req.MaxBytesPerTrace), o.MaxBytesPerTrace(tenant)))
+		collector := pipeline.NewGRPCCollector[*tempopb.TraceByIDResponse](next, cfg.ResponseConsumers, comb, func(resp *tempopb.TraceByIDResponse) error {
+			// diffs without spans are only sent to report a partial trace
+			if len(resp.Trace.GetResourceSpans()) == 0 && resp.Status != tempopb.TraceByIDResponse_PARTIAL {
+				return nil
+			}
+			sent++
+			return srv.Send(resp)
+		})
+
+		err = collector.RoundTrip(httpReq)
+		if err == nil && sent == 0 {
+			err = status.Error(codes.NotFound, "trace not found")
+		}
+		elapsed := time.Since(start)
+
+		postSLOHook(httpReq, nil, tenant, 0, elapsed, err)
+
+		level.Info(logger).Log(
+			"msg", "trace id streaming response",
+			"tenant", tenant,
+			"path", httpReq.URL.Path,
+			"duration_seconds", elapsed.Seconds(),
+			"messages", sent,
+			"err", err)
+
+		return err
+	}
+}
+
+// buildTraceByIDRequest builds the http request for a trace by id lookup of the TempoQuery gRPC service.
+func buildTraceByIDRequest(ctx context.Context, downstreamPath string, req *tempopb.TraceByIDRequest) (*http.Request, error) {
+	if len(req.TraceID) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "please provide a traceID")
+	}
+	traceID := util.TraceIDToHexString(req.TraceID)
+
+	params := map[string]string{}
+	for k, v := range map[string]string{api.QueryModeKey: req.QueryMode, api.BlockStartKey: req.BlockStart, api.BlockEndKey: req.BlockEnd} {
+		if v != "" {
+			params[k] = v
+		}
+	}
+	// passed on to the queriers, which bound it by the limit of the tenant as well
+	if req.MaxBytesPerTrace > 0 {
+		params[api.MaxBytesPerTraceKey] = strconv.FormatUint(req.MaxBytesPerTrace, 10)
+	}
+
+	httpReq := api.BuildQueryRequest(&http.Request{
+		URL:    &url.URL{Path: path.Join(downstreamPath, traceID)},
+		Header: headersFromGrpcContext(ctx),
+		Body:   io.NopCloser(bytes.NewReader([]byte{})),
+	}, params)
+	httpReq.Header.Set(api.HeaderAccept, api.HeaderAcceptProtobuf)
+
+	return mux.SetURLVars(httpReq.WithContext(ctx), map[string]string{api.URLParamTraceID: traceID}), nil
+}
@@ -2,6 +2,7 @@ package frontend
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -12,14 +13,17 @@ import (
 
 	"github.com/gogo/protobuf/jsonpb"
 	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/status"
 	"github.com/gorilla/mux"
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/model/trace"
 	"github.com/grafana/tempo/pkg/tempopb"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
 )
 
 var config = &Config{
@@ -269,6 +273,42 @@ func TestTraceIDHandlerForJSONResponse(t *testing.T) {
 	assert.True(t, strings.Contains(bodyString, "batches"))
 }
 
+func TestTraceIDHandlerMaxBytesPerTrace(t *testing.T) {
+	next := pipeline.RoundTripperFunc(func(_ pipeline.Request) (*http.Response, error) {
+		resBytes, _ := proto.Marshal(&tempopb.TraceByIDResponse{
+			Trace:   test.MakeTrace(2, []byte{0x01, 0x02}),
+			Metrics: &tempopb.TraceByIDMetrics{},
+		})
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(resBytes)),
+			StatusCode: 200,
+		}, nil
+	})
+
+	f := frontendWithSettings(t, next, nil, config, nil)
+
+	tests := []struct {
+		query        string
+		expectedCode int
+	}{
+		{query: "", expectedCode: http.StatusOK},
+		{query: "?maxBytesPerTrace=100000000", expectedCode: http.StatusOK},
+		// the trace exceeds the requested maximum size
+		{query: "?maxBytesPerTrace=10", expectedCode: http.StatusUnprocessableEntity},
+		{query: "?maxBytesPerTrace=-1", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range tests {
+		req := httptest.NewRequest("GET", "/api/traces/1234"+tc.query, nil)
+		req = req.WithContext(user.InjectOrgID(req.Context(), "blerg"))
+		req = mux.SetURLVars(req, map[string]string{"traceID": "1234"})
+
+		httpResp := httptest.NewRecorder()
+		f.TraceByIDHandler.ServeHTTP(httpResp, req)
+		require.Equal(t, tc.expectedCode, httpResp.Code, tc.query)
+	}
+}
+
 func TestTraceIDHandlerV2(t *testing.T) {
 	// create and split a splitTrace
 	splitTrace := test.MakeTrace(2, []byte{0x01, 0x02})
@@ -485,3 +525,184 @@ func TestTraceIDHandlerV2WithJSONResponse(t *testing.T) {
 	err := new(jsonpb.Unmarshaler).Unmarshal(resp.Body, actualResp)
 	require.NoError(t, err)
 }
+
+func TestTraceIDGRPCHandler(t *testing.T) {
+	splitTrace := test.MakeTrace(2, []byte{0x01, 0x02})
+
+	tests := []struct {
+		name          string
+		status        int
+		req           *tempopb.TraceByIDRequest
+		expectedCode  codes.Code
+		expectedTrace *tempopb.Trace
+	}{
+		{
+			name:          "found",
+			status:        http.StatusOK,
+			req:           &tempopb.TraceByIDRequest{TraceID: []byte{0x12, 0x34}},
+			expectedTrace: splitTrace,
+		},
+		{
+			name:         "not found",
+			status:       http.StatusNotFound,
+			req:          &tempopb.TraceByIDRequest{TraceID: []byte{0x12, 0x34}},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "invalid mode",
+			status:       http.StatusOK,
+			req:          &tempopb.TraceByIDRequest{TraceID: []byte{0x12, 0x34}, QueryMode: "foo"},
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "no trace id",
+			status:       http.StatusOK,
+			req:          &tempopb.TraceByIDRequest{},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			next := pipeline.RoundTripperFunc(func(r pipeline.Request) (*http.Response, error) {
+				resBytes, err := proto.Marshal(&tempopb.TraceByIDResponse{
+					Metrics: &tempopb.TraceByIDMetrics{},
+				})
+				require.NoError(t, err)
+
+				// the whole trace is returned by the ingesters
+				if strings.HasSuffix(r.HTTPRequest().URL.Path, "/1234") && r.HTTPRequest().URL.Query().Get("mode") == "ingesters" {
+					resBytes, err = proto.Marshal(&tempopb.TraceByIDResponse{
+						Trace:   splitTrace,
+						Metrics: &tempopb.TraceByIDMetrics{},
+					})
+					require.NoError(t, err)
+				}
+
+				return &http.Response{
+					Body:       io.NopCloser(bytes.NewReader(resBytes)),
+					StatusCode: tc.status,
+					Header: map[string][]string{
+						"Content-Type": {"application/protobuf"},
+					},
+				}, nil
+			})
+
+			f := frontendWithSettings(t, next, nil, config, nil)
+
+			resp, err := f.FindTraceByID(user.InjectOrgID(context.Background(), "blerg"), tc.req)
+			if tc.expectedCode != codes.OK {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedCode, status.Code(err))
+				return
+			}
+
+			require.NoError(t, err)
+			trace.SortTrace(tc.expectedTrace)
+			trace.SortTrace(resp.Trace)
+			assert.True(t, proto.Equal(tc.expectedTrace, resp.Trace))
+		})
+	}
+}
+
+func TestTraceIDStreamingGRPCHandler(t *testing.T) {
+	fullTrace := test.MakeTrace(2, []byte{0x01, 0x02})
+
+	tests := []struct {
+		name         string
+		found        bool
+		req          *tempopb.TraceByIDRequest
+		expectedCode codes.Code
+	}{
+		{
+			name:  "found",
+			found: true,
+			req:   &tempopb.TraceByIDRequest{TraceID: []byte{0x12, 0x34}},
+		},
+		{
+			name:         "not found",
+			req:          &tempopb.TraceByIDRequest{TraceID: []byte{0x12, 0x34}},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name:         "no trace id",
+			req:          &tempopb.TraceByIDRequest{},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// every job returns the whole trace, the spans must only be sent once
+			next := pipeline.RoundTripperFunc(func(pipeline.Request) (*http.Response, error) {
+				resp := &tempopb.TraceByIDResponse{Metrics: &tempopb.TraceByIDMetrics{}}
+				if tc.found {
+					resp.Trace = proto.Clone(fullTrace).(*tempopb.Trace)
+				}
+				resBytes, err := proto.Marshal(resp)
+				require.NoError(t, err)
+
+				return &http.Response{
+					Body:       io.NopCloser(bytes.NewReader(resBytes)),
+					StatusCode: http.StatusOK,
+					Header: map[string][]string{
+						"Content-Type": {"application/protobuf"},
+					},
+				}, nil
+			})
+
+			f := frontendWithSettings(t, next, nil, config, nil)
+
+			received := &tempopb.Trace{}
+			srv := newMockStreamingServer[*tempopb.TraceByIDResponse]("blerg", func(_ int, resp *tempopb.TraceByIDResponse) {
+				received.ResourceSpans = append(received.ResourceSpans, resp.Trace.ResourceSpans...)
+			})
+
+			err := f.StreamTraceByID(tc.req, srv)
+			if tc.expectedCode != codes.OK {
+				require.Error(t, err)
+				require.Equal(t, tc.expectedCode, status.Code(err))
+				return
+			}
+			require.NoError(t, err)
+
+			trace.SortTrace(fullTrace)
+			trace.SortTrace(received)
+			assert.True(t, proto.Equal(fullTrace, received))
+		})
+	}
+}
+
+func TestTraceIDStreamingGRPCHandlerMaxBytesPerTrace(t *testing.T) {
+	next := pipeline.RoundTripperFunc(func(r pipeline.Request) (*http.Response, error) {
+		// the requested maximum is passed on to the queriers
+		require.Equal(t, "10", r.HTTPRequest().URL.Query().Get(api.MaxBytesPerTraceKey))
+
+		resBytes, err := proto.Marshal(&tempopb.TraceByIDResponse{
+			Trace:   test.MakeTrace(2, []byte{0x01, 0x02}),
+			Metrics: &tempopb.TraceByIDMetrics{},
+		})
+		require.NoError(t, err)
+
+		return &http.Response{
+			Body:       io.NopCloser(bytes.NewReader(resBytes)),
+			StatusCode: http.StatusOK,
+			Header: map[string][]string{
+				"Content-Type": {"application/protobuf"},
+			},
+		}, nil
+	})
+
+	f := frontendWithSettings(t, next, nil, config, nil)
+
+	var statuses []tempopb.TraceByIDResponse_Status
+	srv := newMockStreamingServer[*tempopb.TraceByIDResponse]("blerg", func(_ int, resp *tempopb.TraceByIDResponse) {
+		statuses = append(statuses, resp.Status)
+	})
+
+	// the trace exceeds the requested maximum size
+	err := f.StreamTraceByID(&tempopb.TraceByIDRequest{TraceID: []byte{0x12, 0x34}, MaxBytesPerTrace: 10}, srv)
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	require.Equal(t, tempopb.TraceByIDResponse_PARTIAL, statuses[len(statuses)-1])
+}
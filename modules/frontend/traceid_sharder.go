@@ -2,6 +2,7 @@ package frontend
 
 import (
 	"encoding/hex"
+	"fmt"
 	"net/http"
 
 	"github.com/go-kit/log" //nolint:all //deprecated
@@ -9,6 +10,7 @@ import (
 	"github.com/grafana/dskit/user"
 	"github.com/grafana/tempo/modules/frontend/combiner"
 	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/querier"
 	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/blockboundary"
@@ -19,18 +21,28 @@ const (
 	maxQueryShards = 100_000
 )
 
+// ValidateQueryShards returns an error if the number of shards of trace by id lookups is out of range.
+func ValidateQueryShards(shards int) error {
+	if shards < minQueryShards || shards > maxQueryShards {
+		return fmt.Errorf("frontend query shards should be between %d and %d (both inclusive)", minQueryShards, maxQueryShards)
+	}
+	return nil
+}
+
 type asyncTraceSharder struct {
 	next            pipeline.AsyncRoundTripper[combiner.PipelineResponse]
 	cfg             *TraceByIDConfig
+	overrides       overrides.Interface
 	logger          log.Logger
 	blockBoundaries [][]byte
 }
 
-func newAsyncTraceIDSharder(cfg *TraceByIDConfig, logger log.Logger) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
+func newAsyncTraceIDSharder(cfg *TraceByIDConfig, o overrides.Interface, logger log.Logger) pipeline.AsyncMiddleware[combiner.PipelineResponse] {
 	return pipeline.AsyncMiddlewareFunc[combiner.PipelineResponse](func(next pipeline.AsyncRoundTripper[combiner.PipelineResponse]) pipeline.AsyncRoundTripper[combiner.PipelineResponse] {
 		return asyncTraceSharder{
 			next:            next,
 			cfg:             cfg,
+			overrides:       o,
 			logger:          logger,
 			blockBoundaries: blockboundary.CreateBlockBoundaries(cfg.QueryShards - 1), // one shard will be used to query ingesters
 		}
@@ -48,18 +60,25 @@ func (s asyncTraceSharder) RoundTrip(pipelineRequest pipeline.Request) (pipeline
 		return nil, err
 	}
 
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// execute requests
-	concurrentShards := uint(s.cfg.QueryShards)
-	// if concurrent shards is set, respect that value
-	if s.cfg.ConcurrentShards > 0 {
+	concurrentShards := uint(len(reqs))
+	// if concurrent shards is set, respect that value. the tenant override takes precedence
+	if shards := s.overrides.TraceByIDConcurrentShards(userID); shards > 0 {
+		concurrentShards = uint(shards)
+	} else if s.cfg.ConcurrentShards > 0 {
 		concurrentShards = uint(s.cfg.ConcurrentShards)
 	}
 
 	// concurrent_shards grater then query_shards should not be allowed because it would create
 	// more goroutines then the jobs to send these jobs to queriers.
-	if concurrentShards > uint(s.cfg.QueryShards) {
+	if concurrentShards > uint(len(reqs)) {
 		// set the concurrent shards to the total shards
-		concurrentShards = uint(s.cfg.QueryShards)
+		concurrentShards = uint(len(reqs))
 	}
 
 	return pipeline.NewAsyncSharderFunc(ctx, int(concurrentShards), len(reqs), func(i int) pipeline.Request {
@@ -69,14 +88,19 @@ func (s asyncTraceSharder) RoundTrip(pipelineRequest pipeline.Request) (pipeline
 }
 
 // buildShardedRequests returns a slice of requests sharded on the precalculated
-// block boundaries
+// block boundaries, or on the query shards of the tenant if they are overridden
 func (s *asyncTraceSharder) buildShardedRequests(parent pipeline.Request) ([]pipeline.Request, error) {
 	userID, err := user.ExtractOrgID(parent.Context())
 	if err != nil {
 		return nil, err
 	}
 
-	reqs := make([]pipeline.Request, s.cfg.QueryShards)
+	blockBoundaries := s.blockBoundaries
+	if shards := s.overrides.TraceByIDQueryShards(userID); shards > 0 && shards != s.cfg.QueryShards {
+		blockBoundaries = blockboundary.CreateBlockBoundaries(shards - 1) // one shard will be used to query ingesters
+	}
+
+	reqs := make([]pipeline.Request, len(blockBoundaries))
 	params := map[string]string{}
 
 	reqs[0], err = cloneRequestforQueriers(parent, userID, func(r *http.Request) (*http.Request, error) {
@@ -88,12 +112,12 @@ func (s *asyncTraceSharder) buildShardedRequests(parent pipeline.Request) ([]pip
 	}
 
 	// build sharded block queries
-	for i := 1; i < len(s.blockBoundaries); i++ {
+	for i := 1; i < len(blockBoundaries); i++ {
 		i := i // save the loop variable locally to make sure the closure grabs the correct var.
 		pipelineR, _ := cloneRequestforQueriers(parent, userID, func(r *http.Request) (*http.Request, error) {
 			// block queries
-			params[querier.BlockStartKey] = hex.EncodeToString(s.blockBoundaries[i-1])
-			params[querier.BlockEndKey] = hex.EncodeToString(s.blockBoundaries[i])
+			params[querier.BlockStartKey] = hex.EncodeToString(blockBoundaries[i-1])
+			params[querier.BlockEndKey] = hex.EncodeToString(blockBoundaries[i])
 			params[querier.QueryModeKey] = querier.QueryModeBlocks
 
 			return api.BuildQueryRequest(r, params), nil
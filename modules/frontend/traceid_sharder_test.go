@@ -6,19 +6,25 @@ import (
 	"testing"
 
 	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/tempo/modules/frontend/pipeline"
+	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/blockboundary"
 )
 
 func TestBuildShardedRequests(t *testing.T) {
 	queryShards := 2
 
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
 	sharder := &asyncTraceSharder{
 		cfg: &TraceByIDConfig{
 			QueryShards: queryShards,
 		},
+		overrides:       o,
 		blockBoundaries: blockboundary.CreateBlockBoundaries(queryShards - 1),
 	}
 
@@ -31,4 +37,22 @@ func TestBuildShardedRequests(t *testing.T) {
 
 	require.Equal(t, "/querier?mode=ingesters", shardedReqs[0].HTTPRequest().RequestURI)
 	urisEqual(t, []string{"/querier?blockEnd=ffffffffffffffffffffffffffffffff&blockStart=00000000000000000000000000000000&mode=blocks"}, []string{shardedReqs[1].HTTPRequest().RequestURI})
+
+	// the query shards of the tenant override the configured shards
+	sharder.overrides, err = overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Read: overrides.ReadOverrides{
+				TraceByIDQueryShards: 5,
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err)
+
+	shardedReqs, err = sharder.buildShardedRequests(pipeline.NewHTTPRequest(req))
+	require.NoError(t, err)
+	require.Len(t, shardedReqs, 5)
+	require.Equal(t, "/querier?mode=ingesters", shardedReqs[0].HTTPRequest().RequestURI)
+	for _, r := range shardedReqs[1:] {
+		require.Contains(t, r.HTTPRequest().RequestURI, "mode=blocks")
+	}
 }
@@ -24,6 +24,7 @@ import (
 	"github.com/grafana/tempo/modules/frontend/pipeline"
 	"github.com/grafana/tempo/modules/frontend/queue"
 	"github.com/grafana/tempo/modules/frontend/v1/frontendv1pb"
+	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/pkg/util"
 )
 
@@ -33,7 +34,22 @@ var tracer = otel.Tracer("modules/frontend/v1")
 type Config struct {
 	MaxOutstandingPerTenant int                    `yaml:"max_outstanding_per_tenant"`
 	MaxBatchSize            int                    `yaml:"max_batch_size"`
+	SmallJobBytes           uint64                 `yaml:"small_job_bytes"`
+	SmallJobBatchFactor     int                    `yaml:"small_job_batch_factor"`
 	LogQueryRequestHeaders  flagext.StringSliceCSV `yaml:"log_query_request_headers"`
+	QoS                     QoSConfig              `yaml:"qos"`
+}
+
+// QoSConfig configures the classes of requests in the queue. Requests of interactive queries are dequeued before
+// requests of default queries, which are dequeued before requests of background queries.
+type QoSConfig struct {
+	// Enabled classifies requests. If disabled, all requests are of the default class.
+	Enabled bool `yaml:"enabled"`
+	// InteractiveSearchMaxRange is the longest time range of a search that is interactive.
+	InteractiveSearchMaxRange time.Duration `yaml:"interactive_search_max_range"`
+	// StarvationLimit is the number of times a class with queued requests is passed over before one of its requests
+	// is dequeued. 0 dequeues requests of a class only if all higher classes are empty.
+	StarvationLimit int `yaml:"starvation_limit"`
 }
 
 // RegisterFlags adds the flags required to config this to the given FlagSet.
@@ -42,6 +58,28 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.Var(&cfg.LogQueryRequestHeaders, "query-frontend.log-query-request-headers", "Comma-separated list of request header names to include in query logs. Applies to both query stats and slow queries logs.")
 }
 
+// maxBatchJobs returns the maximum number of jobs in a batch. Batches are limited to a weight of max_batch_size
+// regular jobs, which is the weight of small_job_batch_factor times as many small jobs.
+func (cfg *Config) maxBatchJobs() int {
+	if cfg.SmallJobBytes == 0 {
+		return cfg.MaxBatchSize
+	}
+	return cfg.MaxBatchSize * cfg.SmallJobBatchFactor
+}
+
+// batchWeight returns the weight of the request in a batch. Jobs that search less than small_job_bytes, e.g. jobs
+// for tiny blocks, weigh a fraction of regular jobs so more of them are sent to a querier in a single batch.
+func (cfg *Config) batchWeight(req pipeline.Request) int {
+	if cfg.SmallJobBytes == 0 {
+		return req.Weight()
+	}
+
+	if size := req.Size(); size > 0 && size < cfg.SmallJobBytes {
+		return req.Weight()
+	}
+	return req.Weight() * cfg.SmallJobBatchFactor
+}
+
 // Frontend queues HTTP requests, dispatches them to backends, and handles retries
 // for requests which failed.
 type Frontend struct {
@@ -71,13 +109,18 @@ type request struct {
 	enqueueTime time.Time
 	queueSpan   trace.Span
 
-	request  pipeline.Request
-	err      chan error
-	response chan *http.Response
+	request     pipeline.Request
+	batchWeight int
+	err         chan error
+	response    chan *http.Response
 }
 
 func (r *request) Weight() int {
-	return r.request.Weight()
+	return r.batchWeight
+}
+
+func (r *request) Priority() queue.Priority {
+	return r.request.Priority()
 }
 
 func (r *request) OriginalContext() context.Context {
@@ -85,12 +128,18 @@ func (r *request) OriginalContext() context.Context {
 }
 
 // New creates a new frontend. Frontend implements service, and must be started and stopped.
-func New(cfg Config, log log.Logger, registerer prometheus.Registerer) (*Frontend, error) {
+func New(cfg Config, o overrides.Interface, log log.Logger, registerer prometheus.Registerer) (*Frontend, error) {
 	const batchBucketCount = 5
 	if cfg.MaxBatchSize <= 0 {
 		return nil, errors.New("max_batch_size must be positive")
 	}
-	batchBucketSize := float64(cfg.MaxBatchSize) / float64(batchBucketCount)
+	if cfg.SmallJobBytes > 0 && cfg.SmallJobBatchFactor <= 0 {
+		return nil, errors.New("small_job_batch_factor must be positive")
+	}
+	if cfg.QoS.StarvationLimit < 0 {
+		return nil, errors.New("qos.starvation_limit must not be negative")
+	}
+	batchBucketSize := float64(cfg.maxBatchJobs()) / float64(batchBucketCount)
 
 	f := &Frontend{
 		cfg: cfg,
@@ -119,7 +168,7 @@ func New(cfg Config, log log.Logger, registerer prometheus.Registerer) (*Fronten
 		connectedQuerierWorkers: &atomic.Int32{},
 	}
 
-	f.requestQueue = queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, f.queueLength, f.discardedRequests)
+	f.requestQueue = queue.NewRequestQueue(cfg.MaxOutstandingPerTenant, cfg.QoS.StarvationLimit, tenantWeight(o), f.queueLength, f.discardedRequests)
 	f.activeUsers = util.NewActiveUsersCleanupWithDefaultValues(f.cleanupInactiveUserMetrics)
 
 	var err error
@@ -174,7 +223,8 @@ func (f *Frontend) cleanupInactiveUserMetrics(user string) {
 // RoundTrip a HTTP request
 func (f *Frontend) RoundTrip(req pipeline.Request) (*http.Response, error) {
 	request := request{
-		request: req,
+		request:     req,
+		batchWeight: f.cfg.batchWeight(req),
 
 		// Buffer of 1 to ensure response can be written by the server side
 		// of the Process stream, even if this goroutine goes away due to
@@ -215,7 +265,7 @@ func (f *Frontend) Process(server frontendv1pb.Frontend_ProcessServer) error {
 	reqBatch := &requestBatch{}
 	batchSize := 1
 	if querierSupportsBatching(querierFeatures) {
-		batchSize = f.cfg.MaxBatchSize
+		batchSize = f.cfg.maxBatchJobs()
 	}
 	for {
 		reqSlice := make([]queue.Request, batchSize)
@@ -393,3 +443,26 @@ func (f *Frontend) CheckReady(_ context.Context) error {
 func querierSupportsBatching(features int32) bool {
 	return features&int32(frontendv1pb.Feature_REQUEST_BATCHING) != 0
 }
+
+// tenantWeight returns the weight of the tenants of a request in the queue. Requests of multiple tenants are queued
+// with the lowest weight of their tenants.
+func tenantWeight(o overrides.Interface) func(string) int {
+	if o == nil {
+		return nil
+	}
+
+	return func(userID string) int {
+		tenantIDs, err := tenant.TenantIDsFromOrgID(userID)
+		if err != nil {
+			return 1
+		}
+
+		weight := 0
+		for _, tenantID := range tenantIDs {
+			if w := o.QueryQueueWeight(tenantID); weight == 0 || w < weight {
+				weight = w
+			}
+		}
+		return max(weight, 1)
+	}
+}
@@ -0,0 +1,33 @@
+package v1
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/grafana/tempo/modules/frontend/pipeline"
+)
+
+func TestBatchWeight(t *testing.T) {
+	job := func(weight int, size uint64) pipeline.Request {
+		req := pipeline.NewHTTPRequest(httptest.NewRequest("GET", "http://example.com", nil))
+		req.SetWeight(weight)
+		req.SetSize(size)
+		return req
+	}
+
+	// small job batching disabled
+	cfg := Config{MaxBatchSize: 5, SmallJobBatchFactor: 10}
+	assert.Equal(t, 5, cfg.maxBatchJobs())
+	assert.Equal(t, 2, cfg.batchWeight(job(2, 100)))
+
+	// small jobs weigh a tenth of regular jobs, so 10 times as many fit into a batch
+	cfg.SmallJobBytes = 1000
+	assert.Equal(t, 50, cfg.maxBatchJobs())
+	assert.Equal(t, 1, cfg.batchWeight(job(1, 999)))
+	assert.Equal(t, 2, cfg.batchWeight(job(2, 999)))
+	assert.Equal(t, 10, cfg.batchWeight(job(1, 1000)))
+	// jobs of unknown size are regular jobs
+	assert.Equal(t, 10, cfg.batchWeight(job(1, 0)))
+}
@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/modules/generator/processor"
+	"github.com/grafana/tempo/pkg/tempopb"
+)
+
+var (
+	metricProcessorPushDuration = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_processor_push_duration_seconds_total",
+		Help:      "The total time spent by processors processing spans per tenant",
+	}, []string{"tenant", "processor"})
+	metricProcessorMemory = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_processor_memory_bytes",
+		Help:      "The estimated memory held by processors per tenant, besides the series in the registry",
+	}, []string{"tenant", "processor"})
+)
+
+const (
+	reasonProcessingTimeBudgetExceeded = "processing_time_budget_exceeded"
+	reasonProcessorMemoryExceeded      = "processor_memory_exceeded"
+)
+
+// processingBudgetWindow is the period over which the processing time of a tenant is averaged. A tenant may use up
+// to a window of budget at once.
+const processingBudgetWindow = 10 * time.Second
+
+// processingBudget tracks the processing time a tenant has left. Time is added at the rate of the budget and taken
+// by every push to the processors.
+type processingBudget struct {
+	mtx       sync.Mutex
+	available float64 // seconds, negative if the tenant used more than its budget
+	last      time.Time
+}
+
+// exceeded returns true if the tenant used more processing time than the budget in seconds per second allows.
+func (b *processingBudget) exceeded(now time.Time, budget float64) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	if budget <= 0 {
+		b.available = 0
+		b.last = now
+		return false
+	}
+
+	if !b.last.IsZero() {
+		b.available += now.Sub(b.last).Seconds() * budget
+	}
+	b.available = min(b.available, budget*processingBudgetWindow.Seconds())
+	b.last = now
+
+	return b.available < 0
+}
+
+func (b *processingBudget) spend(d time.Duration) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.available -= d.Seconds()
+}
+
+// overBudget returns the reason the spans of the tenant are not processed, or an empty string if the tenant is
+// within its budgets.
+func (i *instance) overBudget() string {
+	if limit := i.overrides.MetricsGeneratorMaxProcessorMemoryBytes(i.instanceID); limit > 0 && i.processorMemory.Load() > limit {
+		return reasonProcessorMemoryExceeded
+	}
+	if i.processingBudget.exceeded(time.Now(), i.overrides.MetricsGeneratorProcessingTimeBudget(i.instanceID)) {
+		return reasonProcessingTimeBudgetExceeded
+	}
+	return ""
+}
+
+// pushToProcessor pushes the spans to the processor and accounts the time it took to the tenant.
+func (i *instance) pushToProcessor(ctx context.Context, p processor.Processor, req *tempopb.PushSpansRequest) {
+	start := time.Now()
+	p.PushSpans(ctx, req)
+	elapsed := time.Since(start)
+
+	i.processingBudget.spend(elapsed)
+	metricProcessorPushDuration.WithLabelValues(i.instanceID, p.Name()).Add(elapsed.Seconds())
+}
+
+// updateProcessorMemory updates the estimated memory held by the processors. Must be called under a read lock.
+func (i *instance) updateProcessorMemory() {
+	var total uint64
+	for name, p := range i.processors {
+		r, ok := p.(processor.MemoryReporter)
+		if !ok {
+			continue
+		}
+		size := r.EstimatedMemoryBytes()
+		total += size
+		metricProcessorMemory.WithLabelValues(i.instanceID, name).Set(float64(size))
+	}
+	i.processorMemory.Store(total)
+}
+
+func countSpans(req *tempopb.PushSpansRequest) int {
+	count := 0
+	for _, b := range req.Batches {
+		for _, ss := range b.ScopeSpans {
+			count += len(ss.Spans)
+		}
+	}
+	return count
+}
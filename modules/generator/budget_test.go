@@ -0,0 +1,69 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestProcessingBudget(t *testing.T) {
+	b := &processingBudget{}
+	now := time.Now()
+
+	// no budget is never exceeded
+	b.spend(time.Minute)
+	assert.False(t, b.exceeded(now, 0))
+
+	// half a second per second
+	assert.False(t, b.exceeded(now, 0.5))
+	b.spend(2 * time.Second)
+	assert.True(t, b.exceeded(now.Add(time.Second), 0.5))
+	assert.False(t, b.exceeded(now.Add(4*time.Second), 0.5))
+
+	// unused budget is kept for one window
+	assert.False(t, b.exceeded(now.Add(time.Hour), 0.5))
+	b.spend(5 * time.Second)
+	assert.False(t, b.exceeded(now.Add(time.Hour), 0.5))
+	b.spend(time.Millisecond)
+	assert.True(t, b.exceeded(now.Add(time.Hour), 0.5))
+}
+
+func Test_instance_overBudget(t *testing.T) {
+	overrides := &mockOverrides{
+		processors:              map[string]struct{}{servicegraphs.Name: {}},
+		maxProcessorMemoryBytes: 1000,
+	}
+
+	i, err := newInstance(&Config{}, "budget-test", overrides, &noopStorage{}, prometheus.NewRegistry(), log.NewNopLogger(), nil, nil, nil)
+	require.NoError(t, err)
+	defer i.shutdown()
+
+	push := func() {
+		i.pushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*v1.ResourceSpans{test.MakeBatch(10, nil)}})
+	}
+
+	push()
+	assert.Greater(t, testutil.ToFloat64(metricProcessorPushDuration.WithLabelValues("budget-test", servicegraphs.Name)), 0.0)
+	assert.Equal(t, "", i.overBudget())
+
+	i.processorMemory.Store(1001)
+	assert.Equal(t, reasonProcessorMemoryExceeded, i.overBudget())
+	push()
+	assert.Equal(t, 10.0, testutil.ToFloat64(metricSpansDiscarded.WithLabelValues("budget-test", reasonProcessorMemoryExceeded)))
+
+	i.processorMemory.Store(0)
+	overrides.processingTimeBudget = 0.001
+	i.processingBudget.spend(time.Second)
+	assert.Equal(t, reasonProcessingTimeBudgetExceeded, i.overBudget())
+}
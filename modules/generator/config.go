@@ -8,6 +8,7 @@ import (
 
 	"github.com/grafana/tempo/modules/generator/processor/localblocks"
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/modules/generator/processor/spaneventmetrics"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/generator/storage"
@@ -70,6 +71,10 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 }
 
 func (cfg *Config) Validate() error {
+	if err := cfg.Storage.Validate(); err != nil {
+		return err
+	}
+
 	if cfg.Ingest.Enabled {
 		if err := cfg.Ingest.Kafka.Validate(); err != nil {
 			return err
@@ -92,14 +97,16 @@ func (cfg *Config) Validate() error {
 }
 
 type ProcessorConfig struct {
-	ServiceGraphs servicegraphs.Config `yaml:"service_graphs"`
-	SpanMetrics   spanmetrics.Config   `yaml:"span_metrics"`
-	LocalBlocks   localblocks.Config   `yaml:"local_blocks"`
+	ServiceGraphs    servicegraphs.Config    `yaml:"service_graphs"`
+	SpanMetrics      spanmetrics.Config      `yaml:"span_metrics"`
+	SpanEventMetrics spaneventmetrics.Config `yaml:"span_event_metrics"`
+	LocalBlocks      localblocks.Config      `yaml:"local_blocks"`
 }
 
 func (cfg *ProcessorConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	cfg.ServiceGraphs.RegisterFlagsAndApplyDefaults(prefix, f)
 	cfg.SpanMetrics.RegisterFlagsAndApplyDefaults(prefix, f)
+	cfg.SpanEventMetrics.RegisterFlagsAndApplyDefaults(prefix, f)
 	cfg.LocalBlocks.RegisterFlagsAndApplyDefaults(prefix, f)
 }
 
@@ -116,6 +123,9 @@ func (cfg *ProcessorConfig) copyWithOverrides(o metricsGeneratorOverrides, userI
 	if peerAttrs := o.MetricsGeneratorProcessorServiceGraphsPeerAttributes(userID); peerAttrs != nil {
 		copyCfg.ServiceGraphs.PeerAttributes = peerAttrs
 	}
+	if clusterAttrs := o.MetricsGeneratorProcessorServiceGraphsClusterAttributes(userID); clusterAttrs != nil {
+		copyCfg.ServiceGraphs.ClusterAttributes = clusterAttrs
+	}
 	if buckets := o.MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID); buckets != nil {
 		copyCfg.SpanMetrics.HistogramBuckets = buckets
 	}
@@ -173,6 +183,10 @@ func (cfg *ProcessorConfig) copyWithOverrides(o metricsGeneratorOverrides, userI
 
 	copyCfg.ServiceGraphs.EnableVirtualNodeLabel = o.MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel(userID)
 
+	copyCfg.ServiceGraphs.EnableExemplars = o.MetricsGeneratorProcessorServiceGraphsEnableExemplars(userID)
+
+	copyCfg.ServiceGraphs.EnableConnectionTypeDimensions = o.MetricsGeneratorProcessorServiceGraphsEnableConnectionTypeDimensions(userID)
+
 	copySubprocessors := make(map[spanmetrics.Subprocessor]bool)
 	for sp, enabled := range cfg.SpanMetrics.Subprocessors {
 		copySubprocessors[sp] = enabled
@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/tempo/modules/generator/processor"
 	"github.com/grafana/tempo/modules/generator/processor/localblocks"
 	"github.com/grafana/tempo/modules/generator/processor/servicegraphs"
+	"github.com/grafana/tempo/modules/generator/processor/spaneventmetrics"
 	"github.com/grafana/tempo/modules/generator/processor/spanmetrics"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/modules/generator/storage"
@@ -30,7 +31,7 @@ import (
 )
 
 var (
-	SupportedProcessors = []string{servicegraphs.Name, spanmetrics.Name, localblocks.Name}
+	SupportedProcessors = []string{servicegraphs.Name, spanmetrics.Name, spaneventmetrics.Name, localblocks.Name}
 
 	metricActiveProcessors = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "tempo",
@@ -86,6 +87,10 @@ type instance struct {
 	processors            map[string]processor.Processor
 	queuebasedLocalBlocks *localblocks.Processor
 
+	processingBudget processingBudget
+	// processorMemory is the estimated memory held by the processors, updated periodically
+	processorMemory atomic.Uint64
+
 	shutdownCh chan struct{}
 
 	reg    prometheus.Registerer
@@ -138,6 +143,10 @@ func (i *instance) watchOverrides() {
 				level.Error(i.logger).Log("msg", "updating the processors failed", "err", err)
 			}
 
+			i.processorsMtx.RLock()
+			i.updateProcessorMemory()
+			i.processorsMtx.RUnlock()
+
 		case <-i.shutdownCh:
 			return
 		}
@@ -265,6 +274,10 @@ func (i *instance) diffProcessors(desiredProcessors map[string]struct{}, desired
 			if !reflect.DeepEqual(p.Cfg, desiredCfg.SpanMetrics) {
 				toReplace = append(toReplace, processorName)
 			}
+		case *spaneventmetrics.Processor:
+			if !reflect.DeepEqual(p.Cfg, desiredCfg.SpanEventMetrics) {
+				toReplace = append(toReplace, processorName)
+			}
 		case *servicegraphs.Processor:
 			if !reflect.DeepEqual(p.Cfg, desiredCfg.ServiceGraphs) {
 				toReplace = append(toReplace, processorName)
@@ -300,6 +313,9 @@ func (i *instance) addProcessor(processorName string, cfg ProcessorConfig) error
 		if err != nil {
 			return err
 		}
+	case spaneventmetrics.Name:
+		invalidUTF8Counter := metricSpansDiscarded.WithLabelValues(i.instanceID, reasonInvalidUTF8)
+		newProcessor = spaneventmetrics.New(cfg.SpanEventMetrics, i.registry, invalidUTF8Counter)
 	case servicegraphs.Name:
 		newProcessor = servicegraphs.New(cfg.ServiceGraphs, i.instanceID, i.registry, i.logger)
 	case localblocks.Name:
@@ -347,6 +363,7 @@ func (i *instance) removeProcessor(processorName string) {
 	}
 
 	delete(i.processors, processorName)
+	metricProcessorMemory.DeleteLabelValues(i.instanceID, processorName)
 
 	deletedProcessor.Shutdown(context.Background())
 
@@ -369,16 +386,26 @@ func (i *instance) updateProcessorMetrics() {
 
 func (i *instance) pushSpans(ctx context.Context, req *tempopb.PushSpansRequest) {
 	i.preprocessSpans(req)
+	if reason := i.overBudget(); reason != "" {
+		metricSpansDiscarded.WithLabelValues(i.instanceID, reason).Add(float64(countSpans(req)))
+		return
+	}
+
 	i.processorsMtx.RLock()
 	defer i.processorsMtx.RUnlock()
 
 	for _, processor := range i.processors {
-		processor.PushSpans(ctx, req)
+		i.pushToProcessor(ctx, processor, req)
 	}
 }
 
 func (i *instance) pushSpansFromQueue(ctx context.Context, req *tempopb.PushSpansRequest) {
 	i.preprocessSpans(req)
+	if reason := i.overBudget(); reason != "" {
+		metricSpansDiscarded.WithLabelValues(i.instanceID, reason).Add(float64(countSpans(req)))
+		return
+	}
+
 	i.processorsMtx.RLock()
 	defer i.processorsMtx.RUnlock()
 
@@ -387,7 +414,7 @@ func (i *instance) pushSpansFromQueue(ctx context.Context, req *tempopb.PushSpan
 		if processor.Name() == localblocks.Name {
 			continue
 		}
-		processor.PushSpans(ctx, req)
+		i.pushToProcessor(ctx, processor, req)
 	}
 
 	// Now we push to the non-flushing local blocks if present
@@ -17,10 +17,13 @@ type metricsGeneratorOverrides interface {
 
 	MetricsGeneratorGenerateNativeHistograms(userID string) overrides.HistogramMethod
 	MetricsGeneratorIngestionSlack(userID string) time.Duration
+	MetricsGeneratorProcessingTimeBudget(userID string) float64
+	MetricsGeneratorMaxProcessorMemoryBytes(userID string) uint64
 	MetricsGeneratorProcessors(userID string) map[string]struct{}
 	MetricsGeneratorProcessorServiceGraphsHistogramBuckets(userID string) []float64
 	MetricsGeneratorProcessorServiceGraphsDimensions(userID string) []string
 	MetricsGeneratorProcessorServiceGraphsPeerAttributes(userID string) []string
+	MetricsGeneratorProcessorServiceGraphsClusterAttributes(userID string) []string
 	MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID string) []float64
 	MetricsGeneratorProcessorSpanMetricsDimensions(userID string) []string
 	MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions(userID string) map[string]bool
@@ -36,6 +39,8 @@ type metricsGeneratorOverrides interface {
 	MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix(userID string) bool
 	MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(userID string) bool
 	MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel(userID string) bool
+	MetricsGeneratorProcessorServiceGraphsEnableExemplars(userID string) bool
+	MetricsGeneratorProcessorServiceGraphsEnableConnectionTypeDimensions(userID string) bool
 	MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(userID string) []string
 	DedicatedColumns(userID string) backend.DedicatedColumns
 	MaxBytesPerTrace(userID string) int
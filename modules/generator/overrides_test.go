@@ -14,9 +14,12 @@ type mockOverrides struct {
 	serviceGraphsHistogramBuckets                      []float64
 	serviceGraphsDimensions                            []string
 	serviceGraphsPeerAttributes                        []string
+	serviceGraphsClusterAttributes                     []string
 	serviceGraphsEnableClientServerPrefix              bool
 	serviceGraphsEnableMessagingSystemLatencyHistogram bool
 	serviceGraphsEnableVirtualNodeLabel                bool
+	serviceGraphsEnableExemplars                       bool
+	serviceGraphsEnableConnectionTypeDimensions        bool
 	spanMetricsHistogramBuckets                        []float64
 	spanMetricsDimensions                              []string
 	spanMetricsIntrinsicDimensions                     map[string]bool
@@ -34,6 +37,8 @@ type mockOverrides struct {
 	maxBytesPerTrace                                   int
 	unsafeQueryHints                                   bool
 	nativeHistograms                                   overrides.HistogramMethod
+	processingTimeBudget                               float64
+	maxProcessorMemoryBytes                            uint64
 }
 
 var _ metricsGeneratorOverrides = (*mockOverrides)(nil)
@@ -42,6 +47,14 @@ func (m *mockOverrides) MetricsGeneratorIngestionSlack(string) time.Duration {
 	return 30 * time.Second
 }
 
+func (m *mockOverrides) MetricsGeneratorProcessingTimeBudget(string) float64 {
+	return m.processingTimeBudget
+}
+
+func (m *mockOverrides) MetricsGeneratorMaxProcessorMemoryBytes(string) uint64 {
+	return m.maxProcessorMemoryBytes
+}
+
 func (m *mockOverrides) MetricsGeneratorMaxActiveSeries(string) uint32 {
 	return 0
 }
@@ -70,6 +83,10 @@ func (m *mockOverrides) MetricsGeneratorRemoteWriteHeaders(string) map[string]st
 	return nil
 }
 
+func (m *mockOverrides) MetricsGeneratorRemoteWriteEndpoints(string) []string {
+	return nil
+}
+
 func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsHistogramBuckets(string) []float64 {
 	return m.serviceGraphsHistogramBuckets
 }
@@ -82,6 +99,10 @@ func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsPeerAttributes(str
 	return m.serviceGraphsPeerAttributes
 }
 
+func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsClusterAttributes(string) []string {
+	return m.serviceGraphsClusterAttributes
+}
+
 func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsHistogramBuckets(string) []float64 {
 	return m.spanMetricsHistogramBuckets
 }
@@ -144,6 +165,14 @@ func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeL
 	return m.serviceGraphsEnableVirtualNodeLabel
 }
 
+func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsEnableExemplars(string) bool {
+	return m.serviceGraphsEnableExemplars
+}
+
+func (m *mockOverrides) MetricsGeneratorProcessorServiceGraphsEnableConnectionTypeDimensions(string) bool {
+	return m.serviceGraphsEnableConnectionTypeDimensions
+}
+
 func (m *mockOverrides) MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(string) []string {
 	return m.spanMetricsTargetInfoExcludedDimensions
 }
@@ -17,3 +17,9 @@ type Processor interface {
 	// PushSpans should not be called anymore.
 	Shutdown(ctx context.Context)
 }
+
+// MemoryReporter is implemented by processors that keep state between pushes, besides the series in the registry.
+type MemoryReporter interface {
+	// EstimatedMemoryBytes returns an estimate of the memory held by the processor.
+	EstimatedMemoryBytes() uint64
+}
@@ -24,6 +24,7 @@ type Config struct {
 	MaxLiveTraces        uint64                `yaml:"max_live_traces"`
 	FilterServerSpans    bool                  `yaml:"filter_server_spans"`
 	FlushToStorage       bool                  `yaml:"flush_to_storage"`
+	DetectMissingRoot    bool                  `yaml:"detect_missing_root"`
 	Metrics              MetricsConfig         `yaml:",inline"`
 }
 
@@ -50,6 +50,12 @@ var (
 		Name:      "traces_dropped_total",
 		Help:      "Number of traces dropped",
 	}, []string{"tenant", "reason"})
+	metricTracesMissingRoot = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: subsystem,
+		Name:      "traces_missing_root_total",
+		Help:      "Number of traces cut without a root span by the service of their earliest span",
+	}, []string{"tenant", "service"})
 	metricBlockSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: subsystem,
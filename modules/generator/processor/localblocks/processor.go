@@ -21,6 +21,7 @@ import (
 	"go.opentelemetry.io/otel"
 
 	gen "github.com/grafana/tempo/modules/generator/processor"
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
 	"github.com/grafana/tempo/pkg/livetraces"
 	"github.com/grafana/tempo/pkg/model"
 	"github.com/grafana/tempo/pkg/tempopb"
@@ -39,6 +40,7 @@ var tracer = otel.Tracer("modules/generator/processor/localblocks")
 const (
 	timeBuffer       = 5 * time.Minute
 	maxFlushAttempts = 100
+	unknownService   = "unknown_service"
 )
 
 // ProcessorOverrides is just the set of overrides needed here.
@@ -77,7 +79,10 @@ type Processor struct {
 	writer tempodb.Writer
 }
 
-var _ gen.Processor = (*Processor)(nil)
+var (
+	_ gen.Processor      = (*Processor)(nil)
+	_ gen.MemoryReporter = (*Processor)(nil)
+)
 
 func New(cfg Config, tenant string, wal *wal.WAL, writer tempodb.Writer, overrides ProcessorOverrides) (p *Processor, err error) {
 	if wal == nil {
@@ -184,6 +189,14 @@ func (p *Processor) PushSpans(_ context.Context, req *tempopb.PushSpansRequest)
 	metricTotalTraces.WithLabelValues(p.tenant).Add(float64(after - before))
 }
 
+// EstimatedMemoryBytes returns the size of the live traces. Blocks are on disk and not counted.
+func (p *Processor) EstimatedMemoryBytes() uint64 {
+	p.liveTracesMtx.Lock()
+	defer p.liveTracesMtx.Unlock()
+
+	return p.liveTraces.Size()
+}
+
 func (p *Processor) Shutdown(context.Context) {
 	close(p.closeCh)
 	p.wg.Wait()
@@ -616,6 +629,12 @@ func (p *Processor) cutIdleTraces(immediate bool) error {
 	})
 
 	for _, t := range tracesToCut {
+		// traces cut immediately may still receive their root span
+		if p.Cfg.DetectMissingRoot && !immediate {
+			if service, missing := missingRoot(t.Batches); missing {
+				metricTracesMissingRoot.WithLabelValues(p.tenant, service).Inc()
+			}
+		}
 
 		tr := &tempopb.Trace{
 			ResourceSpans: t.Batches,
@@ -906,6 +925,33 @@ func filterBatches(batches []*v1.ResourceSpans) []*v1.ResourceSpans {
 	return keep
 }
 
+// missingRoot returns true if none of the spans is a root span. It also returns the service of the earliest span,
+// which is usually the closest to where the root span was lost.
+func missingRoot(batches []*v1.ResourceSpans) (string, bool) {
+	var (
+		earliest uint64
+		service  string
+	)
+	for _, batch := range batches {
+		for _, ss := range batch.ScopeSpans {
+			for _, s := range ss.Spans {
+				if len(s.ParentSpanId) == 0 {
+					return "", false
+				}
+				if service == "" || s.StartTimeUnixNano < earliest {
+					earliest = s.StartTimeUnixNano
+					service, _ = processor_util.FindServiceName(batch.Resource.GetAttributes())
+					if service == "" {
+						service = unknownService
+					}
+				}
+			}
+		}
+	}
+
+	return service, service != ""
+}
+
 type flushOp struct {
 	blockID  uuid.UUID
 	at       time.Time // When to execute
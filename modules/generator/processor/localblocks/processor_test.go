@@ -10,6 +10,8 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_resource "github.com/grafana/tempo/pkg/tempopb/resource/v1"
 	v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util/test"
@@ -19,6 +21,7 @@ import (
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
 	"github.com/grafana/tempo/tempodb/wal"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -371,3 +374,109 @@ func (m *mockBlock) FetchTagNames(context.Context, traceql.FetchTagsRequest, tra
 func (m *mockBlock) BlockMeta() *backend.BlockMeta { return m.meta }
 
 func (m *mockBlock) Validate(context.Context) error { return nil }
+
+func TestMissingRoot(t *testing.T) {
+	batch := func(service string, spans ...*v1.Span) *v1.ResourceSpans {
+		rs := &v1.ResourceSpans{
+			Resource:   &v1_resource.Resource{},
+			ScopeSpans: []*v1.ScopeSpans{{Spans: spans}},
+		}
+		if service != "" {
+			rs.Resource.Attributes = []*v1_common.KeyValue{{
+				Key:   "service.name",
+				Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: service}},
+			}}
+		}
+		return rs
+	}
+	span := func(parent []byte, start uint64) *v1.Span {
+		return &v1.Span{SpanId: []byte{1}, ParentSpanId: parent, StartTimeUnixNano: start}
+	}
+
+	tcs := []struct {
+		name            string
+		batches         []*v1.ResourceSpans
+		expectedMissing bool
+		expectedService string
+	}{
+		{
+			name:    "root",
+			batches: []*v1.ResourceSpans{batch("a", span([]byte{2}, 1)), batch("b", span(nil, 2))},
+		},
+		{
+			name:            "missing root",
+			batches:         []*v1.ResourceSpans{batch("a", span([]byte{2}, 2)), batch("b", span([]byte{3}, 1), span([]byte{4}, 3))},
+			expectedMissing: true,
+			expectedService: "b",
+		},
+		{
+			name:            "missing root without service",
+			batches:         []*v1.ResourceSpans{batch("", span([]byte{2}, 1))},
+			expectedMissing: true,
+			expectedService: unknownService,
+		},
+		{
+			name: "no spans",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			service, missing := missingRoot(tc.batches)
+			require.Equal(t, tc.expectedMissing, missing)
+			require.Equal(t, tc.expectedService, service)
+		})
+	}
+}
+
+func TestProcessorDetectsMissingRoot(t *testing.T) {
+	wal, err := wal.New(&wal.Config{
+		Filepath: t.TempDir(),
+		Version:  encoding.DefaultEncoding().Version(),
+	})
+	require.NoError(t, err)
+
+	cfg := Config{
+		FlushCheckPeriod:     time.Minute,
+		TraceIdlePeriod:      0,
+		CompleteBlockTimeout: time.Minute,
+		Block: &common.BlockConfig{
+			BloomShardSizeBytes: 100_000,
+			BloomFP:             0.05,
+			Version:             encoding.DefaultEncoding().Version(),
+		},
+		Metrics: MetricsConfig{
+			ConcurrentBlocks:  10,
+			TimeOverlapCutoff: 0.2,
+		},
+		DetectMissingRoot: true,
+	}
+
+	p, err := New(cfg, "missing-root", wal, &mockWriter{}, &mockOverrides{})
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	// the trace with a root span isn't counted
+	withRoot := test.MakeTrace(1, test.ValidTraceID(nil))
+	withoutRoot := test.MakeTrace(1, test.ValidTraceID(nil))
+	for _, rs := range withRoot.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				s.ParentSpanId = nil
+			}
+		}
+	}
+	for _, rs := range withoutRoot.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				s.ParentSpanId = []byte{1, 2, 3, 4, 5, 6, 7, 8}
+			}
+		}
+	}
+
+	p.PushSpans(context.TODO(), &tempopb.PushSpansRequest{Batches: withRoot.ResourceSpans})
+	p.PushSpans(context.TODO(), &tempopb.PushSpansRequest{Batches: withoutRoot.ResourceSpans})
+
+	require.NoError(t, p.cutIdleTraces(false))
+	require.Equal(t, 1.0, testutil.ToFloat64(metricTracesMissingRoot.WithLabelValues("missing-root", "test-service")))
+}
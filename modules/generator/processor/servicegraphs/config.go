@@ -44,11 +44,24 @@ type Config struct {
 	// Attributes are searched in the order they are provided
 	PeerAttributes []string `yaml:"peer_attributes"`
 
+	// ClusterAttributes are resource attributes that identify the cluster of a service, e.g. k8s.cluster.name.
+	// If set, the client_cluster and server_cluster labels are added to the metrics so calls between clusters
+	// can be told apart. Attributes are searched in the order they are provided
+	ClusterAttributes []string `yaml:"cluster_attributes"`
+
 	// If enabled attribute value will be used for metric calculation
 	SpanMultiplierKey string `yaml:"span_multiplier_key"`
 
 	// EnableVirtualNodeLabel enables additional labels for uninstrumented services
 	EnableVirtualNodeLabel bool `yaml:"enable_virtual_node_label"`
+
+	// EnableExemplars attaches the trace ID of an edge as exemplar to the latency histograms
+	EnableExemplars bool `yaml:"enable_exemplars"`
+
+	// EnableConnectionTypeDimensions derives the connection_type of edges that are not a messaging system, database or
+	// virtual node from the attributes of the client and server spans (database, messaging_system, rpc or http) and
+	// adds the connection_system label with the value of db.system, messaging.system or rpc.system.
+	EnableConnectionTypeDimensions bool `yaml:"enable_connection_type_dimensions"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
@@ -57,6 +57,27 @@ const (
 
 const virtualNodeLabel = "virtual_node"
 
+const (
+	clientClusterLabel = "client_cluster"
+	serverClusterLabel = "server_cluster"
+)
+
+const connectionSystemLabel = "connection_system"
+
+// connectionTypeAttributes are the span attributes the connection type of an edge is derived from, in order of
+// precedence. The value of the attribute is the connection system, http has none.
+var connectionTypeAttributes = []struct {
+	key            attribute.Key
+	connectionType store.ConnectionType
+	system         bool
+}{
+	{semconv.MessagingSystemKey, store.MessagingSystem, true},
+	{semconv.DBSystemKey, store.Database, true},
+	{semconv.RPCSystemKey, store.RPC, true},
+	{semconv.HTTPRequestMethodKey, store.HTTP, false},
+	{"http.method", store.HTTP, false}, // deprecated in favor of http.request.method
+}
+
 var defaultPeerAttributes = []attribute.Key{
 	semconv.PeerServiceKey, semconv.DBNameKey, semconv.DBSystemKey,
 }
@@ -93,6 +114,14 @@ type Processor struct {
 func New(cfg Config, tenant string, reg registry.Registry, logger log.Logger) gen.Processor {
 	labels := []string{"client", "server", "connection_type"}
 
+	if len(cfg.ClusterAttributes) > 0 {
+		labels = append(labels, clientClusterLabel, serverClusterLabel)
+	}
+
+	if cfg.EnableConnectionTypeDimensions {
+		labels = append(labels, connectionSystemLabel)
+	}
+
 	if cfg.EnableVirtualNodeLabel {
 		cfg.Dimensions = append(cfg.Dimensions, virtualNodeLabel)
 	}
@@ -180,6 +209,7 @@ func (p *Processor) consume(resourceSpans []*v1_trace.ResourceSpans) (err error)
 		if !ok {
 			continue
 		}
+		cluster := p.findCluster(rs.Resource.Attributes)
 
 		for _, ils := range rs.ScopeSpans {
 			for _, span := range ils.Spans {
@@ -196,12 +226,14 @@ func (p *Processor) consume(resourceSpans []*v1_trace.ResourceSpans) (err error)
 						e.TraceID = tempo_util.TraceIDToHexString(span.TraceId)
 						e.ConnectionType = connectionType
 						e.ClientService = svcName
+						e.ClientCluster = cluster
 						e.ClientLatencySec = spanDurationSec(span)
 						e.ClientEndTimeUnixNano = span.EndTimeUnixNano
 						e.Failed = e.Failed || p.spanFailed(span)
-						p.upsertDimensions("client_", e.Dimensions, rs.Resource.Attributes, span.Attributes)
+						p.upsertDimensions("client_", e.Dimensions, ils.Scope, rs.Resource.Attributes, span.Attributes)
 						e.SpanMultiplier = spanMultiplier
 						p.upsertPeerNode(e, span.Attributes)
+						p.upsertConnectionType(e, true, rs.Resource.Attributes, span.Attributes)
 						p.upsertDatabaseRequest(e, rs.Resource.Attributes, span)
 					})
 
@@ -215,12 +247,14 @@ func (p *Processor) consume(resourceSpans []*v1_trace.ResourceSpans) (err error)
 						e.TraceID = tempo_util.TraceIDToHexString(span.TraceId)
 						e.ConnectionType = connectionType
 						e.ServerService = svcName
+						e.ServerCluster = cluster
 						e.ServerLatencySec = spanDurationSec(span)
 						e.ServerStartTimeUnixNano = span.StartTimeUnixNano
 						e.Failed = e.Failed || p.spanFailed(span)
-						p.upsertDimensions("server_", e.Dimensions, rs.Resource.Attributes, span.Attributes)
+						p.upsertDimensions("server_", e.Dimensions, ils.Scope, rs.Resource.Attributes, span.Attributes)
 						e.SpanMultiplier = spanMultiplier
 						p.upsertPeerNode(e, span.Attributes)
+						p.upsertConnectionType(e, false, rs.Resource.Attributes, span.Attributes)
 					})
 				default:
 					// this span is not part of an edge
@@ -254,9 +288,9 @@ func (p *Processor) consume(resourceSpans []*v1_trace.ResourceSpans) (err error)
 	return nil
 }
 
-func (p *Processor) upsertDimensions(prefix string, m map[string]string, resourceAttr, spanAttr []*v1_common.KeyValue) {
+func (p *Processor) upsertDimensions(prefix string, m map[string]string, scope *v1_common.InstrumentationScope, resourceAttr, spanAttr []*v1_common.KeyValue) {
 	for _, dim := range p.Cfg.Dimensions {
-		if v, ok := processor_util.FindAttributeValue(dim, resourceAttr, spanAttr); ok {
+		if v, ok := processor_util.FindDimensionValue(dim, scope, resourceAttr, spanAttr); ok {
 			if p.Cfg.EnableClientServerPrefix {
 				m[prefix+dim] = v
 			} else {
@@ -266,6 +300,35 @@ func (p *Processor) upsertDimensions(prefix string, m map[string]string, resourc
 	}
 }
 
+// findCluster returns the value of the first cluster attribute found in the resource attributes.
+func (p *Processor) findCluster(resourceAttr []*v1_common.KeyValue) string {
+	for _, key := range p.Cfg.ClusterAttributes {
+		if v, ok := processor_util.FindAttributeValue(key, resourceAttr); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// upsertConnectionType derives the connection type and system of the edge from the first connection type attribute
+// found in the span. The client span takes precedence over the server span.
+func (p *Processor) upsertConnectionType(e *store.Edge, client bool, resourceAttr, spanAttr []*v1_common.KeyValue) {
+	if !p.Cfg.EnableConnectionTypeDimensions || (!client && e.DerivedConnectionType != store.Unknown) {
+		return
+	}
+
+	for _, a := range connectionTypeAttributes {
+		if v, ok := processor_util.FindAttributeValue(string(a.key), resourceAttr, spanAttr); ok {
+			e.DerivedConnectionType = a.connectionType
+			e.ConnectionSystem = ""
+			if a.system {
+				e.ConnectionSystem = v
+			}
+			return
+		}
+	}
+}
+
 func (p *Processor) upsertPeerNode(e *store.Edge, spanAttr []*v1_common.KeyValue) {
 	for _, peerKey := range p.Cfg.PeerAttributes {
 		if v, ok := processor_util.FindAttributeValue(peerKey, spanAttr); ok {
@@ -342,13 +405,30 @@ func (p *Processor) upsertDatabaseRequest(e *store.Edge, resourceAttr []*v1_comm
 	}
 }
 
+func (p *Processor) EstimatedMemoryBytes() uint64 {
+	return p.store.EstimatedMemoryBytes()
+}
+
 func (p *Processor) Shutdown(_ context.Context) {
 	close(p.closeCh)
 }
 
 func (p *Processor) onComplete(e *store.Edge) {
-	labelValues := make([]string, 0, 2+len(p.Cfg.Dimensions))
-	labelValues = append(labelValues, e.ClientService, e.ServerService, string(e.ConnectionType))
+	connectionType := e.ConnectionType
+	if p.Cfg.EnableConnectionTypeDimensions && connectionType == store.Unknown {
+		connectionType = e.DerivedConnectionType
+	}
+
+	labelValues := make([]string, 0, len(p.labels))
+	labelValues = append(labelValues, e.ClientService, e.ServerService, string(connectionType))
+
+	if len(p.Cfg.ClusterAttributes) > 0 {
+		labelValues = append(labelValues, e.ClientCluster, e.ServerCluster)
+	}
+
+	if p.Cfg.EnableConnectionTypeDimensions {
+		labelValues = append(labelValues, e.ConnectionSystem)
+	}
 
 	for _, dimension := range p.Cfg.Dimensions {
 		if p.Cfg.EnableClientServerPrefix {
@@ -374,8 +454,14 @@ func (p *Processor) onComplete(e *store.Edge) {
 		p.serviceGraphRequestFailedTotal.Inc(registryLabelValues, 1*e.SpanMultiplier)
 	}
 
-	p.serviceGraphRequestServerSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ServerLatencySec, e.TraceID, e.SpanMultiplier)
-	p.serviceGraphRequestClientSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ClientLatencySec, e.TraceID, e.SpanMultiplier)
+	// histograms only record exemplars with a trace ID
+	exemplarTraceID := ""
+	if p.Cfg.EnableExemplars {
+		exemplarTraceID = e.TraceID
+	}
+
+	p.serviceGraphRequestServerSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ServerLatencySec, exemplarTraceID, e.SpanMultiplier)
+	p.serviceGraphRequestClientSecondsHistogram.ObserveWithExemplar(registryLabelValues, e.ClientLatencySec, exemplarTraceID, e.SpanMultiplier)
 
 	if p.Cfg.EnableMessagingSystemLatencyHistogram && e.ConnectionType == store.MessagingSystem {
 		messagingSystemLatencySec := unixNanosDiffSec(e.ClientEndTimeUnixNano, e.ServerStartTimeUnixNano)
@@ -3,6 +3,7 @@ package servicegraphs
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
 	"os"
 	"strconv"
@@ -16,8 +17,12 @@ import (
 	"github.com/stretchr/testify/require"
 	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
 
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	tempo_util "github.com/grafana/tempo/pkg/util"
 )
 
 // NOTE: This is a way to know if the contents of the semconv package have changed.
@@ -152,6 +157,146 @@ func TestServiceGraphs_prefixDimensions(t *testing.T) {
 	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, requesterToServerLabels))
 }
 
+func TestServiceGraphs_scopeDimensions(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+
+	cfg.HistogramBuckets = []float64{0.04}
+	cfg.Dimensions = []string{"otel.scope.name", "otel.scope.version"}
+	cfg.EnableClientServerPrefix = true
+
+	p := New(cfg, "test", testRegistry, log.NewNopLogger())
+	defer p.Shutdown(context.Background())
+
+	request, err := loadTestData("testdata/trace-with-queue-database.json")
+	require.NoError(t, err)
+
+	for _, rs := range request.Batches {
+		svcName, _ := processor_util.FindServiceName(rs.Resource.Attributes)
+		for _, ss := range rs.ScopeSpans {
+			ss.Scope = &v1_common.InstrumentationScope{Name: "lib-" + svcName, Version: "1.0.0"}
+		}
+	}
+
+	p.PushSpans(context.Background(), request)
+
+	requesterToServerLabels := labels.FromMap(map[string]string{
+		"client":                    "mythical-requester",
+		"server":                    "mythical-server",
+		"connection_type":           "",
+		"client_otel_scope_name":    "lib-mythical-requester",
+		"server_otel_scope_name":    "lib-mythical-server",
+		"client_otel_scope_version": "1.0.0",
+		"server_otel_scope_version": "1.0.0",
+	})
+
+	// counters
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, requesterToServerLabels))
+}
+
+func TestServiceGraphs_clusterAttributes(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+
+	cfg.HistogramBuckets = []float64{0.04}
+	cfg.ClusterAttributes = []string{"k8s.cluster.name", "cloud.region"}
+
+	p := New(cfg, "test", testRegistry, log.NewNopLogger())
+	defer p.Shutdown(context.Background())
+
+	request, err := loadTestData("testdata/trace-with-queue-database.json")
+	require.NoError(t, err)
+
+	// the requester runs in another cluster than the server, which only has the fallback attribute
+	for _, rs := range request.Batches {
+		svcName, _ := processor_util.FindServiceName(rs.Resource.Attributes)
+		switch svcName {
+		case "mythical-requester":
+			rs.Resource.Attributes = append(rs.Resource.Attributes, &v1_common.KeyValue{
+				Key:   "k8s.cluster.name",
+				Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "dev-eu"}},
+			})
+		case "mythical-server":
+			rs.Resource.Attributes = append(rs.Resource.Attributes, &v1_common.KeyValue{
+				Key:   "cloud.region",
+				Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "us-east-1"}},
+			})
+		}
+	}
+
+	p.PushSpans(context.Background(), request)
+
+	requesterToServerLabels := labels.FromMap(map[string]string{
+		"client":          "mythical-requester",
+		"server":          "mythical-server",
+		"connection_type": "",
+		"client_cluster":  "dev-eu",
+		"server_cluster":  "us-east-1",
+	})
+
+	// counters
+	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, requesterToServerLabels))
+}
+
+func TestServiceGraphs_connectionTypeDimensions(t *testing.T) {
+	tcs := []struct {
+		name                    string
+		clientRPCSystem         string
+		expectedRequesterType   string
+		expectedRequesterSystem string
+	}{
+		{
+			name:                  "http from server span",
+			expectedRequesterType: "http",
+		},
+		{
+			name:                    "client span takes precedence",
+			clientRPCSystem:         "grpc",
+			expectedRequesterType:   "rpc",
+			expectedRequesterSystem: "grpc",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			testRegistry := registry.NewTestRegistry()
+
+			cfg := Config{}
+			cfg.RegisterFlagsAndApplyDefaults("", nil)
+			cfg.EnableConnectionTypeDimensions = true
+
+			p := New(cfg, "test", testRegistry, log.NewNopLogger())
+			defer p.Shutdown(context.Background())
+
+			request, err := loadTestData("testdata/trace-with-queue-database.json")
+			require.NoError(t, err)
+
+			if tc.clientRPCSystem != "" {
+				span := request.Batches[0].ScopeSpans[0].Spans[0]
+				require.Equal(t, v1_trace.Span_SPAN_KIND_CLIENT, span.Kind)
+				span.Attributes = append(span.Attributes, &v1_common.KeyValue{
+					Key:   "rpc.system",
+					Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: tc.clientRPCSystem}},
+				})
+			}
+
+			p.PushSpans(context.Background(), request)
+
+			for _, lbls := range []map[string]string{
+				{"client": "mythical-requester", "server": "mythical-server", "connection_type": tc.expectedRequesterType, "connection_system": tc.expectedRequesterSystem},
+				{"client": "mythical-requester", "server": "mythical-recorder", "connection_type": "messaging_system", "connection_system": "rabbitmq"},
+				{"client": "mythical-server", "server": "postgres", "connection_type": "database", "connection_system": "postgresql"},
+			} {
+				assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, labels.FromMap(lbls)), lbls)
+			}
+		})
+	}
+}
+
 func TestServiceGraphs_MessagingSystemLatencyHistogram(t *testing.T) {
 	testRegistry := registry.NewTestRegistry()
 
@@ -182,6 +327,40 @@ func TestServiceGraphs_MessagingSystemLatencyHistogram(t *testing.T) {
 	assert.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_messaging_system_seconds_count`, requesterToRecorderLabels))
 }
 
+func TestServiceGraphs_exemplars(t *testing.T) {
+	for _, enableExemplars := range []bool{false, true} {
+		t.Run(fmt.Sprintf("enableExemplars=%t", enableExemplars), func(t *testing.T) {
+			testRegistry := registry.NewTestRegistry()
+
+			cfg := Config{}
+			cfg.RegisterFlagsAndApplyDefaults("", nil)
+			cfg.EnableExemplars = enableExemplars
+
+			p := New(cfg, "test", testRegistry, log.NewNopLogger())
+			defer p.Shutdown(context.Background())
+
+			request, err := loadTestData("testdata/trace-with-queue-database.json")
+			require.NoError(t, err)
+
+			p.PushSpans(context.Background(), request)
+
+			serverToDatabaseLabels := labels.FromMap(map[string]string{
+				"client":          "mythical-server",
+				"server":          "postgres",
+				"connection_type": "database",
+			})
+			require.Equal(t, 1.0, testRegistry.Query(`traces_service_graph_request_total`, serverToDatabaseLabels))
+
+			var expected []string
+			if enableExemplars {
+				expected = []string{tempo_util.TraceIDToHexString(request.Batches[0].ScopeSpans[0].Spans[0].TraceId)}
+			}
+			assert.Equal(t, expected, testRegistry.QueryExemplars(`traces_service_graph_request_client_seconds`, serverToDatabaseLabels))
+			assert.Equal(t, expected, testRegistry.QueryExemplars(`traces_service_graph_request_server_seconds`, serverToDatabaseLabels))
+		})
+	}
+}
+
 func TestServiceGraphs_failedRequests(t *testing.T) {
 	testRegistry := registry.NewTestRegistry()
 
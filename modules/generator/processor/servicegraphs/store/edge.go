@@ -1,6 +1,12 @@
 package store
 
-import "time"
+import (
+	"time"
+	"unsafe"
+)
+
+// edgeOverhead is the size of an Edge and of its entries in the list and map of the store
+const edgeOverhead = uint64(unsafe.Sizeof(Edge{})) + 64
 
 type ConnectionType string
 
@@ -9,6 +15,8 @@ const (
 	MessagingSystem ConnectionType = "messaging_system"
 	Database        ConnectionType = "database"
 	VirtualNode     ConnectionType = "virtual_node"
+	RPC             ConnectionType = "rpc"
+	HTTP            ConnectionType = "http"
 )
 
 // Edge is an Edge between two nodes in the graph
@@ -18,6 +26,7 @@ type Edge struct {
 	TraceID                                        string
 	ConnectionType                                 ConnectionType
 	ServerService, ClientService                   string
+	ServerCluster, ClientCluster                   string
 	ServerLatencySec, ClientLatencySec             float64
 	ServerStartTimeUnixNano, ClientEndTimeUnixNano uint64
 
@@ -31,6 +40,11 @@ type Edge struct {
 	// PeerNode is the attribute that will be used to create a peer edge
 	PeerNode string
 
+	// DerivedConnectionType and ConnectionSystem are derived from the attributes of the client and server spans,
+	// e.g. db.system or rpc.system.
+	DerivedConnectionType ConnectionType
+	ConnectionSystem      string
+
 	// expiration is the time at which the Edge expires, expressed as Unix time
 	expiration int64
 
@@ -45,6 +59,10 @@ func resetEdge(e *Edge) {
 	e.ConnectionType = Unknown
 	e.ServerService = ""
 	e.ClientService = ""
+	e.ServerCluster = ""
+	e.ClientCluster = ""
+	e.DerivedConnectionType = Unknown
+	e.ConnectionSystem = ""
 	e.ServerLatencySec = 0
 	e.ClientLatencySec = 0
 	e.Failed = false
@@ -63,6 +81,17 @@ func (e *Edge) isExpired() bool {
 	return time.Now().Unix() >= e.expiration
 }
 
+// estimatedSize returns an estimate of the memory held by the Edge. Strings that are shared with the spans
+// the Edge was built from are counted too.
+func (e *Edge) estimatedSize() uint64 {
+	size := edgeOverhead + uint64(len(e.key)+len(e.TraceID)+len(e.ServerService)+len(e.ClientService)+
+		len(e.ServerCluster)+len(e.ClientCluster)+len(e.ConnectionSystem)+len(e.PeerNode))
+	for k, v := range e.Dimensions {
+		size += uint64(len(k) + len(v) + 2*int(unsafe.Sizeof("")))
+	}
+	return size
+}
+
 func (e *Edge) Key() string {
 	return e.key
 }
@@ -8,4 +8,6 @@ type Store interface {
 	UpsertEdge(key string, update Callback) (isNew bool, err error)
 	// Expire evicts expired edges from the store.
 	Expire()
+	// EstimatedMemoryBytes returns an estimate of the memory held by the edges in the store.
+	EstimatedMemoryBytes() uint64
 }
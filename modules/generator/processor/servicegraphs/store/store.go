@@ -48,6 +48,17 @@ func (s *store) len() int {
 	return s.l.Len()
 }
 
+func (s *store) EstimatedMemoryBytes() uint64 {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var size uint64
+	for e := s.l.Front(); e != nil; e = e.Next() {
+		size += e.Value.(*Edge).estimatedSize()
+	}
+	return size
+}
+
 // tryEvictHead checks if the oldest item (head of list) can be evicted and will delete it if so.
 // Returns true if the head was evicted.
 //
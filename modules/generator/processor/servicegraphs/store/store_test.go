@@ -30,6 +30,7 @@ func TestStoreUpsertEdge(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, true, isNew)
 	assert.Equal(t, 1, s.len())
+	assert.Equal(t, edgeOverhead+uint64(len(keyStr)+len(clientService)), s.EstimatedMemoryBytes())
 
 	// Nothing should be evicted as TTL is set to 1h
 	assert.False(t, s.tryEvictHead())
@@ -45,6 +46,7 @@ func TestStoreUpsertEdge(t *testing.T) {
 	require.Equal(t, false, isNew)
 	// Edge is complete and should have been removed
 	assert.Equal(t, 0, s.len())
+	assert.Equal(t, uint64(0), s.EstimatedMemoryBytes())
 
 	assert.Equal(t, 1, onCompletedCount)
 	assert.Equal(t, 0, onExpireCount)
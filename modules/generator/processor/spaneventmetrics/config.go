@@ -0,0 +1,42 @@
+package spaneventmetrics
+
+import (
+	"flag"
+)
+
+const (
+	Name = "span-event-metrics"
+
+	dimService    = "service"
+	dimSpanName   = "span_name"
+	dimSpanKind   = "span_kind"
+	dimStatusCode = "status_code"
+	dimEventName  = "event_name"
+)
+
+var intrinsicLabels = []string{dimService, dimSpanName, dimSpanKind, dimStatusCode, dimEventName}
+
+type Config struct {
+	// Names of the events to count, e.g. exception. Events of all names are counted if empty.
+	EventNames []string `yaml:"event_names"`
+
+	// Intrinsic dimensions (labels) added to the metric, that are generated from fixed span data. The
+	// dimension event_name is always added. The dimension service is enabled by default, whereas the
+	// dimensions span_name, span_kind and status_code must be enabled explicitly.
+	IntrinsicDimensions IntrinsicDimensions `yaml:"intrinsic_dimensions"`
+
+	// Additional dimensions (labels) added to the metric. The dimensions are looked up in the event
+	// attributes first, then in the attributes of the span and its resource.
+	Dimensions []string `yaml:"dimensions"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
+	cfg.IntrinsicDimensions.Service = true
+}
+
+type IntrinsicDimensions struct {
+	Service    bool `yaml:"service"`
+	SpanName   bool `yaml:"span_name"`
+	SpanKind   bool `yaml:"span_kind"`
+	StatusCode bool `yaml:"status_code"`
+}
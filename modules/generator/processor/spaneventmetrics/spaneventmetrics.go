@@ -0,0 +1,146 @@
+package spaneventmetrics
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel"
+
+	gen "github.com/grafana/tempo/modules/generator/processor"
+	processor_util "github.com/grafana/tempo/modules/generator/processor/util"
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
+	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+)
+
+const (
+	metricEventsTotal = "traces_spanevents_total"
+)
+
+var tracer = otel.Tracer("modules/generator/processor/spaneventmetrics")
+
+// Processor counts span events by event name, e.g. to alert on exceptions recorded on spans.
+type Processor struct {
+	Cfg Config
+
+	registry registry.Registry
+
+	eventsTotal registry.Counter
+	labels      []string
+	eventNames  map[string]struct{}
+
+	invalidUTF8Counter prometheus.Counter
+}
+
+func New(cfg Config, reg registry.Registry, invalidUTF8Counter prometheus.Counter) gen.Processor {
+	labels := make([]string, 0, 5+len(cfg.Dimensions))
+
+	if cfg.IntrinsicDimensions.Service {
+		labels = append(labels, dimService)
+	}
+	if cfg.IntrinsicDimensions.SpanName {
+		labels = append(labels, dimSpanName)
+	}
+	if cfg.IntrinsicDimensions.SpanKind {
+		labels = append(labels, dimSpanKind)
+	}
+	if cfg.IntrinsicDimensions.StatusCode {
+		labels = append(labels, dimStatusCode)
+	}
+	labels = append(labels, dimEventName)
+
+	for _, d := range cfg.Dimensions {
+		labels = append(labels, processor_util.SanitizeLabelNameWithCollisions(d, intrinsicLabels))
+	}
+
+	var eventNames map[string]struct{}
+	if len(cfg.EventNames) > 0 {
+		eventNames = make(map[string]struct{}, len(cfg.EventNames))
+		for _, n := range cfg.EventNames {
+			eventNames[n] = struct{}{}
+		}
+	}
+
+	return &Processor{
+		Cfg:                cfg,
+		registry:           reg,
+		eventsTotal:        reg.NewCounter(metricEventsTotal),
+		labels:             labels,
+		eventNames:         eventNames,
+		invalidUTF8Counter: invalidUTF8Counter,
+	}
+}
+
+func (p *Processor) Name() string {
+	return Name
+}
+
+func (p *Processor) PushSpans(ctx context.Context, req *tempopb.PushSpansRequest) {
+	_, span := tracer.Start(ctx, "spaneventmetrics.PushSpans")
+	defer span.End()
+
+	p.aggregateMetrics(req.Batches)
+}
+
+func (p *Processor) Shutdown(_ context.Context) {
+}
+
+func (p *Processor) aggregateMetrics(resourceSpans []*v1_trace.ResourceSpans) {
+	for _, rs := range resourceSpans {
+		svcName, _ := processor_util.FindServiceName(rs.Resource.Attributes)
+		for _, ils := range rs.ScopeSpans {
+			for _, span := range ils.Spans {
+				for _, e := range span.Events {
+					if !p.counts(e.GetName()) {
+						continue
+					}
+					p.aggregateMetricsForEvent(svcName, rs.Resource, ils.Scope, span, e)
+				}
+			}
+		}
+	}
+}
+
+func (p *Processor) counts(eventName string) bool {
+	if p.eventNames == nil {
+		return true
+	}
+	_, ok := p.eventNames[eventName]
+	return ok
+}
+
+func (p *Processor) aggregateMetricsForEvent(svcName string, rs *v1.Resource, scope *v1_common.InstrumentationScope, span *v1_trace.Span, event *v1_trace.Span_Event) {
+	labelValues := make([]string, 0, len(p.labels))
+
+	// important: the order of labelValues must correspond to the order of labels / intrinsic dimensions
+	if p.Cfg.IntrinsicDimensions.Service {
+		labelValues = append(labelValues, svcName)
+	}
+	if p.Cfg.IntrinsicDimensions.SpanName {
+		labelValues = append(labelValues, span.GetName())
+	}
+	if p.Cfg.IntrinsicDimensions.SpanKind {
+		labelValues = append(labelValues, span.GetKind().String())
+	}
+	if p.Cfg.IntrinsicDimensions.StatusCode {
+		labelValues = append(labelValues, span.GetStatus().GetCode().String())
+	}
+	labelValues = append(labelValues, event.GetName())
+
+	for _, d := range p.Cfg.Dimensions {
+		value, _ := processor_util.FindDimensionValue(d, scope, event.Attributes, span.Attributes, rs.Attributes)
+		labelValues = append(labelValues, value)
+	}
+
+	for _, v := range labelValues {
+		if !utf8.ValidString(v) {
+			p.invalidUTF8Counter.Inc()
+			return
+		}
+	}
+
+	p.eventsTotal.Inc(p.registry.NewLabelValueCombo(p.labels, labelValues), 1)
+}
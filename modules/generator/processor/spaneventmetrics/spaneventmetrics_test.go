@@ -0,0 +1,104 @@
+package spaneventmetrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/generator/registry"
+	"github.com/grafana/tempo/pkg/tempopb"
+	common_v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
+	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+var metricSpansDiscarded = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempo",
+	Name:      "metrics_generator_spans_discarded_total",
+	Help:      "The total number of discarded spans received per tenant",
+}, []string{"tenant", "reason"})
+
+func TestSpanEventMetrics(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+	invalidUTF8Counter := metricSpansDiscarded.WithLabelValues("test-tenant", "invalid")
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.IntrinsicDimensions.SpanName = true
+	cfg.Dimensions = []string{"exception.type", "http.method"}
+
+	p := New(cfg, testRegistry, invalidUTF8Counter)
+	defer p.Shutdown(context.Background())
+
+	require.Equal(t, "span-event-metrics", p.Name())
+
+	batch := test.MakeBatch(3, nil)
+	for _, ss := range batch.ScopeSpans {
+		for _, s := range ss.Spans {
+			s.Attributes = append(s.Attributes, stringKV("http.method", "GET"))
+			s.Events = []*trace_v1.Span_Event{
+				{Name: "exception", Attributes: []*common_v1.KeyValue{stringKV("exception.type", "java.lang.NullPointerException")}},
+				{Name: "exception", Attributes: []*common_v1.KeyValue{stringKV("exception.type", "java.io.IOException")}},
+				{Name: "cache-miss"},
+			}
+		}
+	}
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch}})
+
+	lbls := func(eventName, exceptionType string) labels.Labels {
+		return labels.FromMap(map[string]string{
+			"service":        "test-service",
+			"span_name":      "test",
+			"event_name":     eventName,
+			"exception_type": exceptionType,
+			"http_method":    "GET",
+		})
+	}
+
+	assert.Equal(t, 3.0, testRegistry.Query("traces_spanevents_total", lbls("exception", "java.lang.NullPointerException")))
+	assert.Equal(t, 3.0, testRegistry.Query("traces_spanevents_total", lbls("exception", "java.io.IOException")))
+	assert.Equal(t, 3.0, testRegistry.Query("traces_spanevents_total", lbls("cache-miss", "")))
+}
+
+func TestSpanEventMetricsEventNames(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+	invalidUTF8Counter := metricSpansDiscarded.WithLabelValues("test-tenant", "invalid")
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.EventNames = []string{"exception"}
+
+	p := New(cfg, testRegistry, invalidUTF8Counter)
+	defer p.Shutdown(context.Background())
+
+	batch := test.MakeBatch(2, nil)
+	for _, ss := range batch.ScopeSpans {
+		for _, s := range ss.Spans {
+			s.Events = []*trace_v1.Span_Event{{Name: "exception"}, {Name: "cache-miss"}}
+		}
+	}
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch}})
+
+	assert.Equal(t, 2.0, testRegistry.Query("traces_spanevents_total", labels.FromMap(map[string]string{
+		"service":    "test-service",
+		"event_name": "exception",
+	})))
+	assert.Equal(t, 0.0, testRegistry.Query("traces_spanevents_total", labels.FromMap(map[string]string{
+		"service":    "test-service",
+		"event_name": "cache-miss",
+	})))
+}
+
+func stringKV(k, v string) *common_v1.KeyValue {
+	return &common_v1.KeyValue{
+		Key:   k,
+		Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: v}},
+	}
+}
@@ -3,6 +3,7 @@ package spanmetrics
 import (
 	"flag"
 	"fmt"
+	"slices"
 
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/pkg/sharedconfig"
@@ -18,12 +19,23 @@ const (
 	dimSpanKind      = "span_kind"
 	dimStatusCode    = "status_code"
 	dimStatusMessage = "status_message"
+	dimExceptionType = "exception_type"
 	dimJob           = "job"
 	dimInstance      = "instance"
 )
 
+// intrinsicLabels are reserved for the intrinsic dimensions. exception_type is only reserved if the dimension is
+// enabled, see reservedLabels.
 var intrinsicLabels = []string{dimService, dimSpanName, dimSpanKind, dimStatusCode, dimStatusMessage}
 
+// reservedLabels returns the label names that user dimensions are renamed away from.
+func (cfg *Config) reservedLabels() []string {
+	if !cfg.IntrinsicDimensions.ExceptionType {
+		return intrinsicLabels
+	}
+	return append(slices.Clone(intrinsicLabels), dimExceptionType)
+}
+
 type Config struct {
 	// Buckets for latency histogram in seconds.
 	HistogramBuckets []float64 `yaml:"histogram_buckets"`
@@ -33,7 +45,7 @@ type Config struct {
 
 	// Intrinsic dimensions (labels) added to the metric, that are generated from fixed span
 	// data. The dimensions service, span_name, span_kind, status_code, job and instance are enabled by
-	// default, whereas the dimension status_message must be enabled explicitly.
+	// default, whereas the dimensions status_message and exception_type must be enabled explicitly.
 	IntrinsicDimensions IntrinsicDimensions `yaml:"intrinsic_dimensions"`
 
 	// Additional dimensions (labels) to be added to the metric. The dimensions are generated
@@ -79,6 +91,7 @@ type IntrinsicDimensions struct {
 	SpanKind      bool `yaml:"span_kind"`
 	StatusCode    bool `yaml:"status_code"`
 	StatusMessage bool `yaml:"status_message,omitempty"`
+	ExceptionType bool `yaml:"exception_type,omitempty"`
 }
 
 func (ic *IntrinsicDimensions) ApplyFromMap(dimensions map[string]bool) error {
@@ -94,6 +107,8 @@ func (ic *IntrinsicDimensions) ApplyFromMap(dimensions map[string]bool) error {
 			ic.StatusCode = active
 		case dimStatusMessage:
 			ic.StatusMessage = active
+		case dimExceptionType:
+			ic.ExceptionType = active
 		default:
 			return fmt.Errorf("%s is not a valid intrinsic dimension", label)
 		}
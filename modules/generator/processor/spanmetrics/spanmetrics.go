@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/tempo/modules/generator/registry"
 	"github.com/grafana/tempo/pkg/spanfilter"
 	"github.com/grafana/tempo/pkg/tempopb"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	v1 "github.com/grafana/tempo/pkg/tempopb/resource/v1"
 	v1_trace "github.com/grafana/tempo/pkg/tempopb/trace/v1"
 	tempo_util "github.com/grafana/tempo/pkg/util"
@@ -24,6 +25,9 @@ const (
 	metricDurationSeconds = "traces_spanmetrics_latency"
 	metricSizeTotal       = "traces_spanmetrics_size_total"
 	targetInfo            = "traces_target_info"
+
+	exceptionEventName     = "exception"
+	exceptionTypeAttribute = "exception.type"
 )
 
 var tracer = otel.Tracer("modules/generator/processor/spanmetrics")
@@ -38,6 +42,7 @@ type Processor struct {
 	spanMetricsSizeTotal       registry.Counter
 	spanMetricsTargetInfo      registry.Gauge
 	labels                     []string
+	reservedLabels             []string
 
 	filter               *spanfilter.SpanFilter
 	filteredSpansCounter prometheus.Counter
@@ -65,13 +70,17 @@ func New(cfg Config, reg registry.Registry, filteredSpansCounter, invalidUTF8Cou
 	if cfg.IntrinsicDimensions.StatusMessage {
 		labels = append(labels, dimStatusMessage)
 	}
+	if cfg.IntrinsicDimensions.ExceptionType {
+		labels = append(labels, dimExceptionType)
+	}
 
+	reservedLabels := cfg.reservedLabels()
 	for _, d := range cfg.Dimensions {
-		labels = append(labels, processor_util.SanitizeLabelNameWithCollisions(d, intrinsicLabels))
+		labels = append(labels, processor_util.SanitizeLabelNameWithCollisions(d, reservedLabels))
 	}
 
 	for _, m := range cfg.DimensionMappings {
-		labels = append(labels, processor_util.SanitizeLabelNameWithCollisions(m.Name, intrinsicLabels))
+		labels = append(labels, processor_util.SanitizeLabelNameWithCollisions(m.Name, reservedLabels))
 	}
 
 	err := validateLabelValues(labels)
@@ -85,6 +94,7 @@ func New(cfg Config, reg registry.Registry, filteredSpansCounter, invalidUTF8Cou
 		spanMetricsTargetInfo: reg.NewGauge(targetInfo),
 		now:                   time.Now,
 		labels:                labels,
+		reservedLabels:        reservedLabels,
 		filteredSpansCounter:  filteredSpansCounter,
 		invalidUTF8Counter:    invalidUTF8Counter,
 	}
@@ -131,12 +141,12 @@ func (p *Processor) aggregateMetrics(resourceSpans []*v1_trace.ResourceSpans) {
 		jobName := processor_util.GetJobValue(rs.Resource.Attributes)
 		instanceID, _ := processor_util.FindInstanceID(rs.Resource.Attributes)
 		if p.Cfg.EnableTargetInfo {
-			processor_util.GetTargetInfoAttributesValues(&resourceLabels, &resourceValues, rs.Resource.Attributes, p.Cfg.TargetInfoExcludedDimensions, intrinsicLabels)
+			processor_util.GetTargetInfoAttributesValues(&resourceLabels, &resourceValues, rs.Resource.Attributes, p.Cfg.TargetInfoExcludedDimensions, p.reservedLabels)
 		}
 		for _, ils := range rs.ScopeSpans {
 			for _, span := range ils.Spans {
 				if p.filter.ApplyFilterPolicy(rs.Resource, span) {
-					p.aggregateMetricsForSpan(svcName, jobName, instanceID, rs.Resource, span, resourceLabels, resourceValues)
+					p.aggregateMetricsForSpan(svcName, jobName, instanceID, rs.Resource, ils.Scope, span, resourceLabels, resourceValues)
 					continue
 				}
 				p.filteredSpansCounter.Inc()
@@ -145,7 +155,7 @@ func (p *Processor) aggregateMetrics(resourceSpans []*v1_trace.ResourceSpans) {
 	}
 }
 
-func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, instanceID string, rs *v1.Resource, span *v1_trace.Span, resourceLabels []string, resourceValues []string) {
+func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, instanceID string, rs *v1.Resource, scope *v1_common.InstrumentationScope, span *v1_trace.Span, resourceLabels []string, resourceValues []string) {
 	// Spans with negative latency are treated as zero.
 	latencySeconds := 0.0
 	if start, end := span.GetStartTimeUnixNano(), span.GetEndTimeUnixNano(); start < end {
@@ -176,16 +186,19 @@ func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, inst
 	if p.Cfg.IntrinsicDimensions.StatusMessage {
 		labelValues = append(labelValues, span.GetStatus().GetMessage())
 	}
+	if p.Cfg.IntrinsicDimensions.ExceptionType {
+		labelValues = append(labelValues, findExceptionType(span))
+	}
 
 	for _, d := range p.Cfg.Dimensions {
-		value, _ := processor_util.FindAttributeValue(d, rs.Attributes, span.Attributes)
+		value, _ := processor_util.FindDimensionValue(d, scope, rs.Attributes, span.Attributes)
 		labelValues = append(labelValues, value)
 	}
 
 	for _, m := range p.Cfg.DimensionMappings {
 		values := ""
 		for _, s := range m.SourceLabel {
-			if value, _ := processor_util.FindAttributeValue(s, rs.Attributes, span.Attributes); value != "" {
+			if value, _ := processor_util.FindDimensionValue(s, scope, rs.Attributes, span.Attributes); value != "" {
 				if values == "" {
 					values += value
 				} else {
@@ -257,6 +270,19 @@ func (p *Processor) aggregateMetricsForSpan(svcName string, jobName string, inst
 	}
 }
 
+// findExceptionType returns the exception.type attribute of the first exception event recorded on the span,
+// following the OpenTelemetry semantic conventions for exceptions. An empty string is returned if the span
+// has no exception events.
+func findExceptionType(span *v1_trace.Span) string {
+	for _, e := range span.Events {
+		if e.GetName() == exceptionEventName {
+			value, _ := processor_util.FindAttributeValue(exceptionTypeAttribute, e.Attributes)
+			return value
+		}
+	}
+	return ""
+}
+
 func validateLabelValues(v []string) error {
 	for _, value := range v {
 		if !utf8.ValidString(value) {
@@ -165,6 +165,184 @@ func TestSpanMetrics_dimensions(t *testing.T) {
 	assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_latency_sum", lbls))
 }
 
+func TestSpanMetrics_scopeDimensions(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	filteredSpansCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "filtered")
+	invalidSpanLabelsCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "invalid")
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.HistogramBuckets = []float64{0.5, 1}
+	cfg.Dimensions = []string{"otel.scope.name", "otel.scope.version"}
+
+	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidSpanLabelsCounter)
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	batch := test.MakeBatch(10, nil)
+	for _, ss := range batch.ScopeSpans {
+		ss.Scope = &common_v1.InstrumentationScope{Name: "go.opentelemetry.io/contrib/net/http", Version: "1.2.3"}
+	}
+
+	// scope without a version falls back to a span attribute
+	unversioned := test.MakeBatch(5, nil)
+	for _, ss := range unversioned.ScopeSpans {
+		ss.Scope = &common_v1.InstrumentationScope{Name: "custom"}
+		for _, s := range ss.Spans {
+			s.Attributes = append(s.Attributes, &common_v1.KeyValue{
+				Key:   "otel.scope.version",
+				Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "from-attribute"}},
+			})
+		}
+	}
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch, unversioned}})
+
+	lbls := labels.FromMap(map[string]string{
+		"service":            "test-service",
+		"span_name":          "test",
+		"span_kind":          "SPAN_KIND_CLIENT",
+		"status_code":        "STATUS_CODE_OK",
+		"otel_scope_name":    "go.opentelemetry.io/contrib/net/http",
+		"otel_scope_version": "1.2.3",
+	})
+	assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_calls_total", lbls))
+
+	lbls = labels.FromMap(map[string]string{
+		"service":            "test-service",
+		"span_name":          "test",
+		"span_kind":          "SPAN_KIND_CLIENT",
+		"status_code":        "STATUS_CODE_OK",
+		"otel_scope_name":    "custom",
+		"otel_scope_version": "from-attribute",
+	})
+	assert.Equal(t, 5.0, testRegistry.Query("traces_spanmetrics_calls_total", lbls))
+}
+
+func TestSpanMetrics_exceptionType(t *testing.T) {
+	testRegistry := registry.NewTestRegistry()
+
+	filteredSpansCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "filtered")
+	invalidSpanLabelsCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "invalid")
+
+	cfg := Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.HistogramBuckets = []float64{0.5, 1}
+	cfg.IntrinsicDimensions.ExceptionType = true
+
+	p, err := New(cfg, testRegistry, filteredSpansCounter, invalidSpanLabelsCounter)
+	require.NoError(t, err)
+	defer p.Shutdown(context.Background())
+
+	batch := test.MakeBatch(10, nil)
+
+	// record an exception on every other span, preceded by an unrelated event
+	i := 0
+	for _, rs := range batch.ScopeSpans {
+		for _, s := range rs.Spans {
+			i++
+			if i%2 == 0 {
+				continue
+			}
+			s.Events = append(s.Events,
+				&trace_v1.Span_Event{
+					Name: "retry",
+					Attributes: []*common_v1.KeyValue{{
+						Key:   "exception.type",
+						Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "ignored"}},
+					}},
+				},
+				&trace_v1.Span_Event{
+					Name: "exception",
+					Attributes: []*common_v1.KeyValue{{
+						Key:   "exception.type",
+						Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "java.net.SocketTimeoutException"}},
+					}},
+				},
+				&trace_v1.Span_Event{
+					Name: "exception",
+					Attributes: []*common_v1.KeyValue{{
+						Key:   "exception.type",
+						Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "second"}},
+					}},
+				},
+			)
+		}
+	}
+
+	p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch}})
+
+	lblsNoException := labels.FromMap(map[string]string{
+		"service":        "test-service",
+		"span_name":      "test",
+		"span_kind":      "SPAN_KIND_CLIENT",
+		"status_code":    "STATUS_CODE_OK",
+		"exception_type": "",
+	})
+	lblsException := labels.FromMap(map[string]string{
+		"service":        "test-service",
+		"span_name":      "test",
+		"span_kind":      "SPAN_KIND_CLIENT",
+		"status_code":    "STATUS_CODE_OK",
+		"exception_type": "java.net.SocketTimeoutException",
+	})
+
+	assert.Equal(t, 5.0, testRegistry.Query("traces_spanmetrics_calls_total", lblsNoException))
+	assert.Equal(t, 5.0, testRegistry.Query("traces_spanmetrics_calls_total", lblsException))
+	assert.Equal(t, 5.0, testRegistry.Query("traces_spanmetrics_latency_count", lblsException))
+}
+
+func TestSpanMetrics_exceptionTypeDimension(t *testing.T) {
+	filteredSpansCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "filtered")
+	invalidSpanLabelsCounter := metricSpansDiscarded.WithLabelValues("test-tenant", "invalid")
+
+	// a user dimension is only renamed if the exception_type intrinsic dimension is enabled
+	for _, tc := range []struct {
+		intrinsic bool
+		label     string
+	}{
+		{intrinsic: false, label: "exception_type"},
+		{intrinsic: true, label: "__exception_type"},
+	} {
+		testRegistry := registry.NewTestRegistry()
+
+		cfg := Config{}
+		cfg.RegisterFlagsAndApplyDefaults("", nil)
+		cfg.HistogramBuckets = []float64{0.5, 1}
+		cfg.IntrinsicDimensions.ExceptionType = tc.intrinsic
+		cfg.Dimensions = []string{"exception.type"}
+
+		p, err := New(cfg, testRegistry, filteredSpansCounter, invalidSpanLabelsCounter)
+		require.NoError(t, err)
+
+		batch := test.MakeBatch(10, nil)
+		for _, ss := range batch.ScopeSpans {
+			for _, s := range ss.Spans {
+				s.Attributes = append(s.Attributes, &common_v1.KeyValue{
+					Key:   "exception.type",
+					Value: &common_v1.AnyValue{Value: &common_v1.AnyValue_StringValue{StringValue: "attr"}},
+				})
+			}
+		}
+		p.PushSpans(context.Background(), &tempopb.PushSpansRequest{Batches: []*trace_v1.ResourceSpans{batch}})
+
+		lbls := map[string]string{
+			"service":     "test-service",
+			"span_name":   "test",
+			"span_kind":   "SPAN_KIND_CLIENT",
+			"status_code": "STATUS_CODE_OK",
+			tc.label:      "attr",
+		}
+		if tc.intrinsic {
+			lbls["exception_type"] = ""
+		}
+		assert.Equal(t, 10.0, testRegistry.Query("traces_spanmetrics_calls_total", labels.FromMap(lbls)))
+
+		p.Shutdown(context.Background())
+	}
+}
+
 func TestSpanMetrics_collisions(t *testing.T) {
 	testRegistry := registry.NewTestRegistry()
 
@@ -25,6 +25,23 @@ func FindInstanceID(attributes []*v1_common.KeyValue) (string, bool) {
 	return FindAttributeValue(string(semconv.ServiceInstanceIDKey), attributes)
 }
 
+// FindDimensionValue returns the value of a configured dimension. The dimensions otel.scope.name and
+// otel.scope.version are taken from the instrumentation scope of the span, if set. All other dimensions
+// are looked up in the attributes.
+func FindDimensionValue(key string, scope *v1_common.InstrumentationScope, attributes ...[]*v1_common.KeyValue) (string, bool) {
+	switch key {
+	case string(semconv.OTelScopeNameKey):
+		if name := scope.GetName(); name != "" {
+			return name, true
+		}
+	case string(semconv.OTelScopeVersionKey):
+		if version := scope.GetVersion(); version != "" {
+			return version, true
+		}
+	}
+	return FindAttributeValue(key, attributes...)
+}
+
 func FindAttributeValue(key string, attributes ...[]*v1_common.KeyValue) (string, bool) {
 	for _, attrs := range attributes {
 		for _, kv := range attrs {
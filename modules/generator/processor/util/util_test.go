@@ -99,3 +99,27 @@ func TestFindServiceName(t *testing.T) {
 		})
 	}
 }
+
+func TestFindDimensionValue(t *testing.T) {
+	attrs := []*v1_common.KeyValue{
+		{Key: "otel.scope.version", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "from-attribute"}}},
+		{Key: "foo", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_StringValue{StringValue: "bar"}}},
+	}
+	scope := &v1_common.InstrumentationScope{Name: "my-library"}
+
+	v, ok := FindDimensionValue("otel.scope.name", scope, attrs)
+	assert.True(t, ok)
+	assert.Equal(t, "my-library", v)
+
+	// falls back to the attributes if the scope has no version
+	v, ok = FindDimensionValue("otel.scope.version", scope, attrs)
+	assert.True(t, ok)
+	assert.Equal(t, "from-attribute", v)
+
+	v, ok = FindDimensionValue("foo", scope, attrs)
+	assert.True(t, ok)
+	assert.Equal(t, "bar", v)
+
+	_, ok = FindDimensionValue("otel.scope.name", nil, attrs)
+	assert.False(t, ok)
+}
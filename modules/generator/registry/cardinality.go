@@ -0,0 +1,195 @@
+package registry
+
+import (
+	"sync"
+
+	"go.uber.org/atomic"
+)
+
+// aggregatedLabelValue replaces the values of labels that exceed the max label cardinality.
+const aggregatedLabelValue = "__aggregated__"
+
+// labelCardinality tracks the distinct values of every label name. Once a label has more distinct values than
+// maxCardinality within the stale duration, its values are replaced with aggregatedLabelValue. This merges the
+// series of the label instead of dropping random series once max active series is reached.
+//
+// Every label name is locked separately. Values that have been seen before only take read locks, so spans are
+// observed concurrently.
+type labelCardinality struct {
+	maxCardinality int
+
+	mtx    sync.RWMutex
+	labels map[string]*labelValues
+}
+
+type labelValues struct {
+	mtx sync.RWMutex
+	// lastSeenMs is the last time a value was seen, it holds at most maxCardinality+1 values.
+	lastSeenMs map[string]*atomic.Int64
+	// exceededMs is the last time a new value was seen while lastSeenMs was full.
+	exceededMs *atomic.Int64
+	aggregated *atomic.Bool
+	// removed is set once the label was removed from the tracker. observers that still hold it look it up again.
+	removed bool
+}
+
+func newLabelCardinality(maxCardinality int) *labelCardinality {
+	return &labelCardinality{
+		maxCardinality: maxCardinality,
+		labels:         map[string]*labelValues{},
+	}
+}
+
+// observe records the values and replaces the values of aggregated labels in place. It returns the labels that
+// exceeded the max cardinality with this call.
+func (c *labelCardinality) observe(labels []string, values []string, timeMs int64) (aggregated []string) {
+	for i, label := range labels {
+		lv, exceeded := c.observeValue(label, values[i], timeMs)
+		if exceeded {
+			aggregated = append(aggregated, label)
+		}
+		if lv.aggregated.Load() {
+			values[i] = aggregatedLabelValue
+		}
+	}
+
+	return aggregated
+}
+
+// observeValue records a value of the label. It returns true if the label exceeded the max cardinality with it.
+func (c *labelCardinality) observeValue(label, value string, timeMs int64) (*labelValues, bool) {
+	for {
+		lv := c.labelValues(label)
+
+		lv.mtx.RLock()
+		lastSeenMs, ok := lv.lastSeenMs[value]
+		removed := lv.removed
+		if ok && !removed {
+			lastSeenMs.Store(timeMs)
+		}
+		lv.mtx.RUnlock()
+
+		if removed {
+			continue
+		}
+		if ok {
+			return lv, false
+		}
+
+		lv.mtx.Lock()
+		if lv.removed {
+			lv.mtx.Unlock()
+			continue
+		}
+		exceeded := lv.add(value, timeMs, c.maxCardinality)
+		lv.mtx.Unlock()
+
+		return lv, exceeded
+	}
+}
+
+// labelValues returns the values of the label and adds the label if it's not tracked yet.
+func (c *labelCardinality) labelValues(label string) *labelValues {
+	c.mtx.RLock()
+	lv, ok := c.labels[label]
+	c.mtx.RUnlock()
+	if ok {
+		return lv
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if lv, ok = c.labels[label]; !ok {
+		lv = &labelValues{
+			lastSeenMs: map[string]*atomic.Int64{},
+			exceededMs: atomic.NewInt64(0),
+			aggregated: atomic.NewBool(false),
+		}
+		c.labels[label] = lv
+	}
+	return lv
+}
+
+// add records a value that may not have been seen before. It returns true if the label exceeded the max cardinality
+// with it. lv.mtx must be held.
+func (lv *labelValues) add(value string, timeMs int64, maxCardinality int) bool {
+	if lastSeenMs, ok := lv.lastSeenMs[value]; ok {
+		lastSeenMs.Store(timeMs)
+	} else if len(lv.lastSeenMs) <= maxCardinality {
+		lv.lastSeenMs[value] = atomic.NewInt64(timeMs)
+	} else {
+		lv.exceededMs.Store(timeMs)
+	}
+
+	if !lv.aggregated.Load() && len(lv.lastSeenMs) > maxCardinality {
+		lv.aggregated.Store(true)
+		return true
+	}
+	return false
+}
+
+// removeStaleValues removes values that haven't been seen since staleTimeMs. It returns the labels that are no longer
+// aggregated because they stayed within the max cardinality.
+func (c *labelCardinality) removeStaleValues(staleTimeMs int64) (restored []string) {
+	c.mtx.RLock()
+	labels := make(map[string]*labelValues, len(c.labels))
+	for label, lv := range c.labels {
+		labels[label] = lv
+	}
+	c.mtx.RUnlock()
+
+	var empty []string
+	for label, lv := range labels {
+		lv.mtx.Lock()
+		for value, lastSeenMs := range lv.lastSeenMs {
+			if lastSeenMs.Load() < staleTimeMs {
+				delete(lv.lastSeenMs, value)
+			}
+		}
+
+		if lv.aggregated.Load() && lv.exceededMs.Load() < staleTimeMs && len(lv.lastSeenMs) <= c.maxCardinality {
+			lv.aggregated.Store(false)
+			restored = append(restored, label)
+		}
+
+		if len(lv.lastSeenMs) == 0 && !lv.aggregated.Load() {
+			empty = append(empty, label)
+		}
+		lv.mtx.Unlock()
+	}
+
+	if len(empty) == 0 {
+		return restored
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// values may have been observed since the label was found to be empty
+	for _, label := range empty {
+		lv := labels[label]
+		lv.mtx.Lock()
+		if len(lv.lastSeenMs) == 0 && !lv.aggregated.Load() {
+			lv.removed = true
+			delete(c.labels, label)
+		}
+		lv.mtx.Unlock()
+	}
+
+	return restored
+}
+
+// aggregatedLabels returns the number of labels that are currently aggregated.
+func (c *labelCardinality) aggregatedLabels() int {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	count := 0
+	for _, lv := range c.labels {
+		if lv.aggregated.Load() {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,79 @@
+package registry
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/atomic"
+)
+
+func Test_labelCardinality(t *testing.T) {
+	c := newLabelCardinality(2)
+
+	observe := func(value string, timeMs int64) string {
+		values := []string{value}
+		c.observe([]string{"label"}, values, timeMs)
+		return values[0]
+	}
+
+	assert.Equal(t, "a", observe("a", 1))
+	assert.Equal(t, "b", observe("b", 1))
+	assert.Equal(t, 0, c.aggregatedLabels())
+
+	assert.Equal(t, []string{"label"}, c.observe([]string{"label"}, []string{"c"}, 2))
+	assert.Equal(t, aggregatedLabelValue, observe("a", 3))
+	assert.Equal(t, aggregatedLabelValue, observe("d", 4))
+	assert.Equal(t, 1, c.aggregatedLabels())
+
+	// b is stale, but a new value was seen while exceeding the cardinality at 4
+	assert.Empty(t, c.removeStaleValues(2))
+	assert.Equal(t, 1, c.aggregatedLabels())
+
+	assert.Equal(t, aggregatedLabelValue, observe("a", 5))
+	assert.Equal(t, aggregatedLabelValue, observe("c", 5))
+
+	// only a and c have been seen since the cardinality was exceeded
+	assert.Equal(t, []string{"label"}, c.removeStaleValues(5))
+	assert.Equal(t, 0, c.aggregatedLabels())
+	assert.Equal(t, "a", observe("a", 6))
+
+	// all values are stale
+	assert.Empty(t, c.removeStaleValues(7))
+	assert.Empty(t, c.labels)
+}
+
+func Test_labelCardinalityConcurrency(t *testing.T) {
+	c := newLabelCardinality(100)
+	exceeded := atomic.NewInt32(0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				// label-0 stays within the max cardinality, label-1 exceeds it
+				values := []string{fmt.Sprint(j % 10), fmt.Sprint(i*1000 + j)}
+				exceeded.Add(int32(len(c.observe([]string{"label-0", "label-1"}, values, int64(j)))))
+			}
+		}(i)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 100; j++ {
+			c.removeStaleValues(0)
+		}
+	}()
+	wg.Wait()
+
+	// the label is reported once when it exceeds the max cardinality
+	assert.Equal(t, int32(1), exceeded.Load())
+	assert.Equal(t, 1, c.aggregatedLabels())
+
+	values := []string{"0", "0"}
+	c.observe([]string{"label-0", "label-1"}, values, 1000)
+	assert.Equal(t, []string{"0", aggregatedLabelValue}, values)
+}
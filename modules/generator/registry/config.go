@@ -27,6 +27,11 @@ type Config struct {
 	// MaxLabelValueLength configures the maximum length of label values. Label values exceeding
 	// this limit will be truncated.
 	MaxLabelValueLength int `yaml:"max_label_value_length"`
+
+	// MaxLabelCardinality configures the maximum amount of distinct values of a label within the
+	// stale duration. Values of labels exceeding this limit will be replaced with __aggregated__.
+	// Disabled if 0.
+	MaxLabelCardinality int `yaml:"max_label_cardinality"`
 }
 
 // RegisterFlagsAndApplyDefaults registers the flags.
@@ -21,7 +21,8 @@ type Counter interface {
 type Histogram interface {
 	metric
 
-	// ObserveWithExemplar observes a datapoint with the given values. traceID will be added as exemplar.
+	// ObserveWithExemplar observes a datapoint with the given values. traceID will be added as exemplar unless it's
+	// empty.
 	ObserveWithExemplar(labelValueCombo *LabelValueCombo, value float64, traceID string, multiplier float64)
 }
 
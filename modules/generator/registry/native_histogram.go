@@ -180,6 +180,10 @@ func (h *nativeHistogram) newSeries(labelValueCombo *LabelValueCombo, value floa
 
 func (h *nativeHistogram) updateSeries(s *nativeHistogramSeries, value float64, traceID string, multiplier float64) {
 	for i := 0.0; i < multiplier; i++ {
+		if traceID == "" {
+			s.promHistogram.Observe(value)
+			continue
+		}
 		s.promHistogram.(prometheus.ExemplarObserver).ObserveWithExemplar(
 			value,
 			map[string]string{h.traceIDLabelName: traceID},
@@ -30,6 +30,19 @@ func Test_ObserveWithExemplar_duplicate(t *testing.T) {
 	assert.Equal(t, 1, seriesAdded)
 }
 
+func Test_ObserveWithExemplar_noTraceID(t *testing.T) {
+	h := newNativeHistogram("my_histogram", []float64{0.1, 0.2}, func(uint32) bool { return true }, nil, "trace_id", HistogramModeBoth, nil)
+
+	lv := newLabelValueCombo([]string{"label"}, []string{"value-1"})
+	h.ObserveWithExemplar(lv, 1.0, "", 1.0)
+
+	appender := &capturingAppender{}
+	_, err := h.collectMetrics(appender, 0)
+	require.NoError(t, err)
+	assert.NotEmpty(t, appender.samples)
+	assert.Empty(t, appender.exemplars)
+}
+
 func Test_Histograms(t *testing.T) {
 	// A single observations has a label value combo, a value, and a multiplier.
 	type observations []struct {
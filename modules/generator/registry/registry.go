@@ -53,6 +53,11 @@ var (
 		Name:      "metrics_generator_registry_collections_failed_total",
 		Help:      "The total amount of failed metrics collections per tenant",
 	}, []string{"tenant"})
+	metricAggregatedLabels = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_registry_aggregated_labels",
+		Help:      "The amount of labels whose values are aggregated because they exceeded the max label cardinality per tenant",
+	}, []string{"tenant"})
 )
 
 type ManagedRegistry struct {
@@ -67,6 +72,9 @@ type ManagedRegistry struct {
 	metrics      map[string]metric
 	activeSeries atomic.Uint32
 
+	// labelCardinality is nil if the max label cardinality is disabled
+	labelCardinality *labelCardinality
+
 	appendable storage.Appendable
 
 	logger                   log.Logger
@@ -78,6 +86,7 @@ type ManagedRegistry struct {
 	metricTotalSeriesLimited prometheus.Counter
 	metricTotalCollections   prometheus.Counter
 	metricFailedCollections  prometheus.Counter
+	metricAggregatedLabels   prometheus.Gauge
 }
 
 // metric is the interface for a metric that is managed by ManagedRegistry.
@@ -128,6 +137,11 @@ func New(cfg *Config, overrides Overrides, tenant string, appendable storage.App
 		metricTotalSeriesLimited: metricTotalSeriesLimited.WithLabelValues(tenant),
 		metricTotalCollections:   metricTotalCollections.WithLabelValues(tenant),
 		metricFailedCollections:  metricFailedCollections.WithLabelValues(tenant),
+		metricAggregatedLabels:   metricAggregatedLabels.WithLabelValues(tenant),
+	}
+
+	if cfg.MaxLabelCardinality > 0 {
+		r.labelCardinality = newLabelCardinality(cfg.MaxLabelCardinality)
 	}
 
 	go job(instanceCtx, r.CollectMetrics, r.collectionInterval)
@@ -140,7 +154,20 @@ func (r *ManagedRegistry) NewLabelValueCombo(labels []string, values []string) *
 	if len(labels) != len(values) {
 		panic(fmt.Sprintf("length of given label values does not match with labels, labels: %v, label values: %v", labels, values))
 	}
-	return newLabelValueComboWithMax(labels, values, r.cfg.MaxLabelNameLength, r.cfg.MaxLabelValueLength)
+	truncateLength(labels, r.cfg.MaxLabelNameLength)
+	truncateLength(values, r.cfg.MaxLabelValueLength)
+
+	if r.labelCardinality != nil {
+		aggregated := r.labelCardinality.observe(labels, values, time.Now().UnixMilli())
+		for _, label := range aggregated {
+			level.Warn(r.logger).Log("msg", "label exceeded max label cardinality, aggregating its values", "label", label, "max_label_cardinality", r.cfg.MaxLabelCardinality, "value", aggregatedLabelValue)
+		}
+		if len(aggregated) > 0 {
+			r.metricAggregatedLabels.Set(float64(r.labelCardinality.aggregatedLabels()))
+		}
+	}
+
+	return newLabelValueCombo(labels, values)
 }
 
 func (r *ManagedRegistry) NewCounter(name string) Counter {
@@ -273,6 +300,13 @@ func (r *ManagedRegistry) removeStaleSeries(_ context.Context) {
 		m.removeStaleSeries(timeMs)
 	}
 
+	if r.labelCardinality != nil {
+		for _, label := range r.labelCardinality.removeStaleValues(timeMs) {
+			level.Info(r.logger).Log("msg", "label is within max label cardinality again, no longer aggregating its values", "label", label, "max_label_cardinality", r.cfg.MaxLabelCardinality)
+		}
+		r.metricAggregatedLabels.Set(float64(r.labelCardinality.aggregatedLabels()))
+	}
+
 	level.Info(r.logger).Log("msg", "deleted stale series", "active_series", r.activeSeries.Load())
 }
 
@@ -252,6 +252,32 @@ func TestManagedRegistry_maxLabelNameLength(t *testing.T) {
 	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
 }
 
+func TestManagedRegistry_maxLabelCardinality(t *testing.T) {
+	appender := &capturingAppender{}
+
+	cfg := &Config{
+		MaxLabelCardinality: 2,
+	}
+	registry := New(cfg, &mockOverrides{}, "test", appender, log.NewNopLogger())
+	defer registry.Close()
+
+	counter := registry.NewCounter("counter")
+
+	for _, value := range []string{"a", "b", "c", "a"} {
+		counter.Inc(registry.NewLabelValueCombo([]string{"service", "span_id"}, []string{"svc", value}), 1.0)
+	}
+
+	expectedSamples := []sample{
+		newSample(map[string]string{"__name__": "counter", "service": "svc", "span_id": "a", "__metrics_gen_instance": mustGetHostname()}, 0, 0.0),
+		newSample(map[string]string{"__name__": "counter", "service": "svc", "span_id": "a", "__metrics_gen_instance": mustGetHostname()}, 1, 1.0),
+		newSample(map[string]string{"__name__": "counter", "service": "svc", "span_id": "b", "__metrics_gen_instance": mustGetHostname()}, 0, 0.0),
+		newSample(map[string]string{"__name__": "counter", "service": "svc", "span_id": "b", "__metrics_gen_instance": mustGetHostname()}, 1, 1.0),
+		newSample(map[string]string{"__name__": "counter", "service": "svc", "span_id": "__aggregated__", "__metrics_gen_instance": mustGetHostname()}, 0, 0.0),
+		newSample(map[string]string{"__name__": "counter", "service": "svc", "span_id": "__aggregated__", "__metrics_gen_instance": mustGetHostname()}, 1, 2.0),
+	}
+	collectRegistryMetricsAndAssert(t, registry, appender, expectedSamples)
+}
+
 func TestValidLabelValueCombo(t *testing.T) {
 	appender := &capturingAppender{}
 
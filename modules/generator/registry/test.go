@@ -14,13 +14,16 @@ import (
 type TestRegistry struct {
 	// "metric{labels}" -> value
 	metrics map[string]float64
+	// "histogram{labels}" -> trace ids of the exemplars
+	exemplars map[string][]string
 }
 
 var _ Registry = (*TestRegistry)(nil)
 
 func NewTestRegistry() *TestRegistry {
 	return &TestRegistry{
-		metrics: map[string]float64{},
+		metrics:   map[string]float64{},
+		exemplars: map[string][]string{},
 	}
 }
 
@@ -44,6 +47,7 @@ func (t *TestRegistry) NewLabelValueCombo(labels []string, values []string) *Lab
 
 func (t *TestRegistry) NewHistogram(name string, buckets []float64, histogramOverrides HistogramMode) Histogram {
 	return &testHistogram{
+		n:                  name,
 		nameSum:            name + "_sum",
 		nameCount:          name + "_count",
 		nameBucket:         name + "_bucket",
@@ -67,6 +71,19 @@ func (t *TestRegistry) setMetric(name string, lbls labels.Labels, value float64)
 	t.metrics[name+lbls.String()] = value
 }
 
+func (t *TestRegistry) addExemplar(name string, lbls labels.Labels, traceID string) {
+	if t == nil || t.exemplars == nil {
+		return
+	}
+	t.exemplars[name+lbls.String()] = append(t.exemplars[name+lbls.String()], traceID)
+}
+
+// QueryExemplars returns the trace IDs of the exemplars observed by the given histogram, using the same labels
+// as Query.
+func (t *TestRegistry) QueryExemplars(name string, lbls labels.Labels) []string {
+	return t.exemplars[name+lbls.String()]
+}
+
 // Query returns the value of the given metric. Note this is a rather naive query engine, it's only
 // possible to query metrics by using the exact same labels as they were stored with.
 func (t *TestRegistry) Query(name string, lbls labels.Labels) float64 {
@@ -165,6 +182,7 @@ func (t *testGauge) removeStaleSeries(int64) {
 }
 
 type testHistogram struct {
+	n                  string
 	nameSum            string
 	nameCount          string
 	nameBucket         string
@@ -178,7 +196,7 @@ var (
 	_ metric    = (*testHistogram)(nil)
 )
 
-func (t *testHistogram) ObserveWithExemplar(labelValueCombo *LabelValueCombo, value float64, _ string, multiplier float64) {
+func (t *testHistogram) ObserveWithExemplar(labelValueCombo *LabelValueCombo, value float64, traceID string, multiplier float64) {
 	lbls := make(labels.Labels, len(labelValueCombo.labels.names))
 	for i, label := range labelValueCombo.labels.names {
 		lbls[i] = labels.Label{Name: label, Value: labelValueCombo.labels.values[i]}
@@ -194,6 +212,10 @@ func (t *testHistogram) ObserveWithExemplar(labelValueCombo *LabelValueCombo, va
 		}
 	}
 	t.registry.addToMetric(t.nameBucket, withLe(lbls, math.Inf(1)), 1*multiplier)
+
+	if traceID != "" {
+		t.registry.addExemplar(t.n, lbls, traceID)
+	}
 }
 
 func (t *testHistogram) name() string {
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"flag"
+	"fmt"
 	"time"
 
 	prometheus_config "github.com/prometheus/prometheus/config"
@@ -21,9 +22,28 @@ type Config struct {
 	// Add X-Scope-OrgID header in remote write requests
 	RemoteWriteAddOrgIDHeader bool `yaml:"remote_write_add_org_id_header,omitempty"`
 
-	// Prometheus remote write config
+	// Prometheus remote write config. Tenants can be routed to a subset of the endpoints by name with the
+	// remote_write_endpoints override.
 	// https://prometheus.io/docs/prometheus/latest/configuration/configuration/#remote_write
 	RemoteWrite []prometheus_config.RemoteWriteConfig `yaml:"remote_write,omitempty"`
+
+	// Export the generated metrics to an OTLP endpoint, in addition to remote write
+	OTLP OTLPConfig `yaml:"otlp,omitempty"`
+}
+
+type OTLPConfig struct {
+	// Endpoint is the full URL of the OTLP/HTTP metrics endpoint, e.g. http://mimir/otlp/v1/metrics. Exporting is
+	// disabled if empty.
+	Endpoint string `yaml:"endpoint"`
+
+	// Headers are added to every export request
+	Headers map[string]string `yaml:"headers,omitempty"`
+
+	// Timeout of a single export request
+	Timeout time.Duration `yaml:"timeout"`
+
+	// Add X-Scope-OrgID header in export requests
+	AddOrgIDHeader bool `yaml:"add_org_id_header"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
@@ -32,6 +52,35 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(string, *flag.FlagSet) {
 	cfg.RemoteWriteFlushDeadline = time.Minute
 
 	cfg.RemoteWriteAddOrgIDHeader = true
+
+	cfg.OTLP.Timeout = 10 * time.Second
+	cfg.OTLP.AddOrgIDHeader = true
+}
+
+// Validate checks the remote write endpoints can be told apart by name.
+func (cfg *Config) Validate() error {
+	names := map[string]struct{}{}
+	for _, rw := range cfg.RemoteWrite {
+		if rw.Name == "" {
+			continue
+		}
+		if _, ok := names[rw.Name]; ok {
+			return fmt.Errorf("remote write endpoint %s is configured more than once", rw.Name)
+		}
+		names[rw.Name] = struct{}{}
+	}
+	return nil
+}
+
+// RemoteWriteEndpointNames returns the names of the remote write endpoints.
+func (cfg *Config) RemoteWriteEndpointNames() []string {
+	names := make([]string, 0, len(cfg.RemoteWrite))
+	for _, rw := range cfg.RemoteWrite {
+		if rw.Name != "" {
+			names = append(names, rw.Name)
+		}
+	}
+	return names
 }
 
 // agentOptions is a copy of agent.Options but with yaml struct tags. Refer to agent.Options for
@@ -22,6 +22,10 @@ remote_write:
   - url: http://prometheus/api/prom/push
     headers:
       foo: bar
+otlp:
+  endpoint: http://mimir/otlp/v1/metrics
+  headers:
+    foo: bar
 `
 
 	var cfg Config
@@ -49,6 +53,21 @@ remote_write:
 		RemoteWrite: []prometheus_config.RemoteWriteConfig{
 			remoteWriteConfig,
 		},
+		OTLP: OTLPConfig{
+			Endpoint:       "http://mimir/otlp/v1/metrics",
+			Headers:        map[string]string{"foo": "bar"},
+			Timeout:        10 * time.Second,
+			AddOrgIDHeader: true,
+		},
 	}
 	assert.Equal(t, expectedCfg, cfg)
 }
+
+func TestConfigValidate(t *testing.T) {
+	cfg := Config{RemoteWrite: []prometheus_config.RemoteWriteConfig{{Name: "mimir-1"}, {Name: "mimir-2"}, {}, {}}}
+	assert.NoError(t, cfg.Validate())
+	assert.Equal(t, []string{"mimir-1", "mimir-2"}, cfg.RemoteWriteEndpointNames())
+
+	cfg.RemoteWrite = append(cfg.RemoteWrite, prometheus_config.RemoteWriteConfig{Name: "mimir-1"})
+	assert.EqualError(t, cfg.Validate(), "remote write endpoint mimir-1 is configured more than once")
+}
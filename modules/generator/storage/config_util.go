@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/go-kit/log"
@@ -60,6 +61,29 @@ func generateTenantRemoteWriteConfigs(inputs []prometheus_config.RemoteWriteConf
 	return outputs
 }
 
+// selectRemoteWriteConfigs returns the remote write configurations with the given names. All configurations are
+// returned if no names are given. Unknown names are logged and ignored.
+func selectRemoteWriteConfigs(inputs []prometheus_config.RemoteWriteConfig, names []string, logger log.Logger) []prometheus_config.RemoteWriteConfig {
+	if len(names) == 0 {
+		return inputs
+	}
+
+	var outputs []prometheus_config.RemoteWriteConfig
+	for _, input := range inputs {
+		if input.Name != "" && slices.Contains(names, input.Name) {
+			outputs = append(outputs, input)
+		}
+	}
+
+	for _, name := range names {
+		if !slices.ContainsFunc(inputs, func(input prometheus_config.RemoteWriteConfig) bool { return input.Name == name }) {
+			level.Warn(logger).Log("msg", "remote write endpoint of tenant is not configured", "remoteWriteName", name)
+		}
+	}
+
+	return outputs
+}
+
 // copyMap creates a new map containing all values from the given map.
 func copyMap(m map[string]string) map[string]string {
 	newMap := make(map[string]string, len(m))
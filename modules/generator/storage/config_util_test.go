@@ -124,6 +124,19 @@ func Test_generateTenantRemoteWriteConfigs_sendNativeHistograms(t *testing.T) {
 	assert.Equal(t, false, result[0].SendNativeHistograms, "SendNativeHistograms should be true")
 }
 
+func Test_selectRemoteWriteConfigs(t *testing.T) {
+	original := []prometheus_config.RemoteWriteConfig{
+		{Name: "mimir-1", URL: &prometheus_common_config.URL{URL: urlMustParse("http://mimir-1/api/prom/push")}},
+		{Name: "mimir-2", URL: &prometheus_common_config.URL{URL: urlMustParse("http://mimir-2/api/prom/push")}},
+		{URL: &prometheus_common_config.URL{URL: urlMustParse("http://prometheus/api/prom/push")}},
+	}
+
+	assert.Equal(t, original, selectRemoteWriteConfigs(original, nil, log.NewNopLogger()))
+	assert.Equal(t, original[1:2], selectRemoteWriteConfigs(original, []string{"mimir-2"}, log.NewNopLogger()))
+	assert.Equal(t, original[:2], selectRemoteWriteConfigs(original, []string{"mimir-2", "mimir-1"}, log.NewNopLogger()))
+	assert.Empty(t, selectRemoteWriteConfigs(original, []string{"unknown"}, log.NewNopLogger()))
+}
+
 func Test_copyMap(t *testing.T) {
 	original := map[string]string{
 		"k1": "v1",
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/go-kit/log"
@@ -40,11 +41,13 @@ type storageImpl struct {
 	walDir  string
 	remote  *remote.Storage
 	storage storage.Storage
+	otlp    *otlpExporter
 
 	tenantID string
 
 	// Cached from the overrides
 	currentHeaders       map[string]string
+	currentEndpoints     []string
 	sendNativeHistograms bool
 
 	overrides Overrides
@@ -86,11 +89,12 @@ func New(cfg *Config, o Overrides, tenant string, reg prometheus.Registerer, log
 	remoteStorage := remote.NewStorage(log.With(logger, "component", "remote"), reg, startTimeCallback, walDir, cfg.RemoteWriteFlushDeadline, &noopScrapeManager{}, false)
 
 	headers := o.MetricsGeneratorRemoteWriteHeaders(tenant)
+	endpoints := o.MetricsGeneratorRemoteWriteEndpoints(tenant)
 	generateNativeHistograms := o.MetricsGeneratorGenerateNativeHistograms(tenant)
 	sendNativeHistograms := overrides.HasNativeHistograms(generateNativeHistograms)
 
 	remoteStorageConfig := &prometheus_config.Config{
-		RemoteWriteConfigs: generateTenantRemoteWriteConfigs(cfg.RemoteWrite, tenant, headers, cfg.RemoteWriteAddOrgIDHeader, logger, sendNativeHistograms),
+		RemoteWriteConfigs: generateTenantRemoteWriteConfigs(selectRemoteWriteConfigs(cfg.RemoteWrite, endpoints, logger), tenant, headers, cfg.RemoteWriteAddOrgIDHeader, logger, sendNativeHistograms),
 	}
 
 	err = remoteStorage.ApplyConfig(remoteStorageConfig)
@@ -112,6 +116,7 @@ func New(cfg *Config, o Overrides, tenant string, reg prometheus.Registerer, log
 
 		tenantID:             tenant,
 		currentHeaders:       headers,
+		currentEndpoints:     endpoints,
 		sendNativeHistograms: sendNativeHistograms,
 
 		overrides: o,
@@ -120,18 +125,28 @@ func New(cfg *Config, o Overrides, tenant string, reg prometheus.Registerer, log
 		logger: logger,
 	}
 
+	if cfg.OTLP.Endpoint != "" {
+		s.otlp = newOTLPExporter(&cfg.OTLP, tenant, logger)
+	}
+
 	go s.watchOverrides()
 
 	return s, nil
 }
 
 func (s *storageImpl) Appender(ctx context.Context) storage.Appender {
+	if s.otlp != nil {
+		return s.otlp.wrap(s.storage.Appender(ctx))
+	}
 	return s.storage.Appender(ctx)
 }
 
 func (s *storageImpl) Close() error {
 	level.Info(s.logger).Log("msg", "closing WAL", "dir", s.walDir)
 	close(s.closeCh)
+	if s.otlp != nil {
+		s.otlp.close()
+	}
 
 	return tsdb_errors.NewMulti(
 		s.storage.Close(),
@@ -151,15 +166,17 @@ func (s *storageImpl) watchOverrides() {
 		select {
 		case <-t.C:
 			newHeaders := s.overrides.MetricsGeneratorRemoteWriteHeaders(s.tenantID)
+			newEndpoints := s.overrides.MetricsGeneratorRemoteWriteEndpoints(s.tenantID)
 			newGenerateNativeHistograms := s.overrides.MetricsGeneratorGenerateNativeHistograms(s.tenantID)
 			newSendNativeHistograms := overrides.HasNativeHistograms(newGenerateNativeHistograms)
 
-			if !headersEqual(s.currentHeaders, newHeaders) || s.sendNativeHistograms != newSendNativeHistograms {
+			if !headersEqual(s.currentHeaders, newHeaders) || !slices.Equal(s.currentEndpoints, newEndpoints) || s.sendNativeHistograms != newSendNativeHistograms {
 				level.Info(s.logger).Log("msg", "updating remote write configuration")
 				s.currentHeaders = newHeaders
+				s.currentEndpoints = newEndpoints
 				s.sendNativeHistograms = newSendNativeHistograms
 				err := s.remote.ApplyConfig(&prometheus_config.Config{
-					RemoteWriteConfigs: generateTenantRemoteWriteConfigs(s.cfg.RemoteWrite, s.tenantID, newHeaders, s.cfg.RemoteWriteAddOrgIDHeader, s.logger, newSendNativeHistograms),
+					RemoteWriteConfigs: generateTenantRemoteWriteConfigs(selectRemoteWriteConfigs(s.cfg.RemoteWrite, newEndpoints, s.logger), s.tenantID, newHeaders, s.cfg.RemoteWriteAddOrgIDHeader, s.logger, newSendNativeHistograms),
 				})
 				if err != nil {
 					metricStorageRemoteWriteUpdateFailed.WithLabelValues(s.tenantID).Inc()
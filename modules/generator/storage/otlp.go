@@ -0,0 +1,365 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	// otlpExportQueueSize is the number of batches that can be waiting to be exported. Once the queue is full new
+	// batches are dropped.
+	otlpExportQueueSize = 10
+	otlpScopeName       = "tempo-metrics-generator"
+)
+
+var (
+	metricOTLPSamplesExported = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_storage_otlp_samples_exported_total",
+		Help:      "The total number of samples exported to the OTLP endpoint",
+	}, []string{"tenant"})
+	metricOTLPSamplesFailed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "metrics_generator_storage_otlp_samples_failed_total",
+		Help:      "The total number of samples that failed to be exported to the OTLP endpoint",
+	}, []string{"tenant", "reason"})
+)
+
+type otlpBatch struct {
+	metrics pmetric.Metrics
+	samples int
+}
+
+// otlpExporter exports the samples committed to the storage as OTLP metrics. Exporting happens in the background so
+// it never blocks the collection of the registry.
+type otlpExporter struct {
+	cfg      *OTLPConfig
+	tenantID string
+	client   *http.Client
+	queue    chan otlpBatch
+	closeCh  chan struct{}
+	doneCh   chan struct{}
+	logger   log.Logger
+}
+
+func newOTLPExporter(cfg *OTLPConfig, tenant string, logger log.Logger) *otlpExporter {
+	e := &otlpExporter{
+		cfg:      cfg,
+		tenantID: tenant,
+		client:   &http.Client{Timeout: cfg.Timeout},
+		queue:    make(chan otlpBatch, otlpExportQueueSize),
+		closeCh:  make(chan struct{}),
+		doneCh:   make(chan struct{}),
+		logger:   log.With(logger, "component", "otlp"),
+	}
+
+	go e.run()
+
+	return e
+}
+
+// wrap returns an appender that appends to the given appender and exports the samples once they are committed.
+func (e *otlpExporter) wrap(a storage.Appender) storage.Appender {
+	return &otlpAppender{Appender: a, exporter: e}
+}
+
+func (e *otlpExporter) enqueue(samples []otlpSample) {
+	batch := otlpBatch{metrics: samplesToOTLP(samples), samples: len(samples)}
+
+	select {
+	case e.queue <- batch:
+	default:
+		metricOTLPSamplesFailed.WithLabelValues(e.tenantID, "queue_full").Add(float64(batch.samples))
+		level.Warn(e.logger).Log("msg", "OTLP export queue is full, dropping samples", "samples", batch.samples)
+	}
+}
+
+func (e *otlpExporter) run() {
+	defer close(e.doneCh)
+
+	for {
+		select {
+		case batch := <-e.queue:
+			if err := e.export(batch.metrics); err != nil {
+				metricOTLPSamplesFailed.WithLabelValues(e.tenantID, "export").Add(float64(batch.samples))
+				level.Error(e.logger).Log("msg", "failed to export samples to OTLP endpoint", "samples", batch.samples, "err", err)
+				continue
+			}
+			metricOTLPSamplesExported.WithLabelValues(e.tenantID).Add(float64(batch.samples))
+		case <-e.closeCh:
+			return
+		}
+	}
+}
+
+func (e *otlpExporter) export(md pmetric.Metrics) error {
+	// MetricsData and ExportMetricsServiceRequest have the same wire format
+	body, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(md)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metrics: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, e.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range e.cfg.Headers {
+		req.Header.Set(k, v)
+	}
+	if e.cfg.AddOrgIDHeader {
+		req.Header.Set("X-Scope-OrgID", e.tenantID)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
+
+func (e *otlpExporter) close() {
+	close(e.closeCh)
+	<-e.doneCh
+}
+
+type otlpSample struct {
+	labels labels.Labels
+	t      int64
+	v      float64
+}
+
+// otlpAppender records the float samples appended to the underlying appender. Native histograms and exemplars are
+// not exported.
+type otlpAppender struct {
+	storage.Appender
+
+	exporter *otlpExporter
+	samples  []otlpSample
+}
+
+func (a *otlpAppender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	ref, err := a.Appender.Append(ref, l, t, v)
+	if err != nil {
+		return ref, err
+	}
+
+	a.samples = append(a.samples, otlpSample{labels: l, t: t, v: v})
+	return ref, nil
+}
+
+func (a *otlpAppender) Commit() error {
+	samples := a.samples
+	a.samples = nil
+
+	if err := a.Appender.Commit(); err != nil {
+		return err
+	}
+
+	if len(samples) > 0 {
+		a.exporter.enqueue(samples)
+	}
+	return nil
+}
+
+func (a *otlpAppender) Rollback() error {
+	a.samples = nil
+	return a.Appender.Rollback()
+}
+
+// otlpHistogramPoint is a classic histogram assembled from its _bucket, _sum and _count series.
+type otlpHistogramPoint struct {
+	attributes labels.Labels
+	t          int64
+	buckets    map[float64]float64 // upper bound -> cumulative count
+	sum, count float64
+}
+
+// samplesToOTLP converts Prometheus samples into OTLP metrics. The metric type is derived from the name the same way
+// the registry names its metrics: _total series become monotonic sums, classic histograms are assembled from their
+// _bucket, _sum and _count series and everything else is a gauge. Metric names are kept as they are, so they translate
+// back to the same Prometheus names with or without metric suffixes.
+func samplesToOTLP(samples []otlpSample) pmetric.Metrics {
+	// find the histograms first, so their _sum and _count series can be told apart from gauges with the same suffix
+	histogramNames := map[string]struct{}{}
+	for _, s := range samples {
+		name := s.labels.Get(labels.MetricName)
+		if strings.HasSuffix(name, "_bucket") && s.labels.Has(labels.BucketLabel) {
+			histogramNames[strings.TrimSuffix(name, "_bucket")] = struct{}{}
+		}
+	}
+
+	md := pmetric.NewMetrics()
+	sm := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(otlpScopeName)
+
+	metrics := map[string]pmetric.Metric{}
+	getMetric := func(name string, init func(m pmetric.Metric)) pmetric.Metric {
+		m, ok := metrics[name]
+		if !ok {
+			m = sm.Metrics().AppendEmpty()
+			m.SetName(name)
+			init(m)
+			metrics[name] = m
+		}
+		return m
+	}
+
+	histograms := map[string]map[string]*otlpHistogramPoint{} // name -> series and timestamp -> point
+	var histogramOrder []string
+
+	for _, s := range samples {
+		name := s.labels.Get(labels.MetricName)
+
+		if histName, part, ok := histogramPart(name, histogramNames); ok {
+			points, ok := histograms[histName]
+			if !ok {
+				points = map[string]*otlpHistogramPoint{}
+				histograms[histName] = points
+				histogramOrder = append(histogramOrder, histName)
+			}
+
+			attributes := s.labels.DropMetricName()
+			if part == "_bucket" {
+				attributes = labels.NewBuilder(attributes).Del(labels.BucketLabel).Labels()
+			}
+
+			key := attributes.String() + "@" + strconv.FormatInt(s.t, 10)
+			p, ok := points[key]
+			if !ok {
+				p = &otlpHistogramPoint{attributes: attributes, t: s.t, buckets: map[float64]float64{}}
+				points[key] = p
+			}
+
+			switch part {
+			case "_bucket":
+				le, err := strconv.ParseFloat(s.labels.Get(labels.BucketLabel), 64)
+				if err != nil {
+					continue
+				}
+				p.buckets[le] = s.v
+			case "_sum":
+				p.sum = s.v
+			case "_count":
+				p.count = s.v
+			}
+			continue
+		}
+
+		var dp pmetric.NumberDataPoint
+		if strings.HasSuffix(name, "_total") {
+			m := getMetric(name, func(m pmetric.Metric) {
+				sum := m.SetEmptySum()
+				sum.SetIsMonotonic(true)
+				sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			})
+			dp = m.Sum().DataPoints().AppendEmpty()
+		} else {
+			m := getMetric(name, func(m pmetric.Metric) { m.SetEmptyGauge() })
+			dp = m.Gauge().DataPoints().AppendEmpty()
+		}
+		dp.SetTimestamp(msToTimestamp(s.t))
+		dp.SetDoubleValue(s.v)
+		setAttributes(dp.Attributes(), s.labels)
+	}
+
+	for _, name := range histogramOrder {
+		m := getMetric(name, func(m pmetric.Metric) {
+			m.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		})
+
+		points := histograms[name]
+		keys := make([]string, 0, len(points))
+		for k := range points {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			appendHistogramDataPoint(m.Histogram().DataPoints().AppendEmpty(), points[k])
+		}
+	}
+
+	return md
+}
+
+// histogramPart returns the histogram name and the suffix if the series is part of a classic histogram
+func histogramPart(name string, histogramNames map[string]struct{}) (string, string, bool) {
+	for _, suffix := range []string{"_bucket", "_sum", "_count"} {
+		if !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		histName := strings.TrimSuffix(name, suffix)
+		if _, ok := histogramNames[histName]; ok {
+			return histName, suffix, true
+		}
+	}
+	return "", "", false
+}
+
+func appendHistogramDataPoint(dp pmetric.HistogramDataPoint, p *otlpHistogramPoint) {
+	dp.SetTimestamp(msToTimestamp(p.t))
+	dp.SetSum(p.sum)
+	dp.SetCount(uint64(p.count))
+	setAttributes(dp.Attributes(), p.attributes)
+
+	bounds := make([]float64, 0, len(p.buckets))
+	for le := range p.buckets {
+		bounds = append(bounds, le)
+	}
+	sort.Float64s(bounds)
+
+	// Prometheus buckets are cumulative and include +Inf, OTLP bucket counts are per bucket with an implicit +Inf
+	// bucket at the end
+	var previous float64
+	for _, le := range bounds {
+		cumulative := math.Max(p.buckets[le], previous)
+		dp.BucketCounts().Append(uint64(cumulative - previous))
+		previous = cumulative
+
+		if !math.IsInf(le, 1) {
+			dp.ExplicitBounds().Append(le)
+		}
+	}
+	if len(bounds) == 0 || !math.IsInf(bounds[len(bounds)-1], 1) {
+		dp.BucketCounts().Append(uint64(math.Max(p.count-previous, 0)))
+	}
+}
+
+func setAttributes(attrs pcommon.Map, lbls labels.Labels) {
+	lbls.Range(func(l labels.Label) {
+		if l.Name == labels.MetricName {
+			return
+		}
+		attrs.PutStr(l.Name, l.Value)
+	})
+}
+
+func msToTimestamp(ms int64) pcommon.Timestamp {
+	return pcommon.NewTimestampFromTime(time.UnixMilli(ms))
+}
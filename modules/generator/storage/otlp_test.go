@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestSamplesToOTLP(t *testing.T) {
+	samples := []otlpSample{
+		{labels: labels.FromStrings("__name__", "calls_total", "service", "a"), t: 1000, v: 3},
+		{labels: labels.FromStrings("__name__", "latency_bucket", "service", "a", "le", "1"), t: 1000, v: 1},
+		{labels: labels.FromStrings("__name__", "latency_bucket", "service", "a", "le", "2"), t: 1000, v: 3},
+		{labels: labels.FromStrings("__name__", "latency_bucket", "service", "a", "le", "+Inf"), t: 1000, v: 4},
+		{labels: labels.FromStrings("__name__", "latency_sum", "service", "a"), t: 1000, v: 5.5},
+		{labels: labels.FromStrings("__name__", "latency_count", "service", "a"), t: 1000, v: 4},
+		// not part of a histogram
+		{labels: labels.FromStrings("__name__", "queue_count", "service", "a"), t: 1000, v: 7},
+	}
+
+	md := samplesToOTLP(samples)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	sm := md.ResourceMetrics().At(0).ScopeMetrics().At(0)
+	require.Equal(t, otlpScopeName, sm.Scope().Name())
+
+	metrics := map[string]pmetric.Metric{}
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		m := sm.Metrics().At(i)
+		metrics[m.Name()] = m
+	}
+	require.Len(t, metrics, 3)
+
+	calls := metrics["calls_total"]
+	require.Equal(t, pmetric.MetricTypeSum, calls.Type())
+	require.True(t, calls.Sum().IsMonotonic())
+	require.Equal(t, pmetric.AggregationTemporalityCumulative, calls.Sum().AggregationTemporality())
+	dp := calls.Sum().DataPoints().At(0)
+	require.Equal(t, 3.0, dp.DoubleValue())
+	require.Equal(t, time.UnixMilli(1000).UnixNano(), int64(dp.Timestamp()))
+	require.Equal(t, map[string]any{"service": "a"}, dp.Attributes().AsRaw())
+
+	queue := metrics["queue_count"]
+	require.Equal(t, pmetric.MetricTypeGauge, queue.Type())
+	require.Equal(t, 7.0, queue.Gauge().DataPoints().At(0).DoubleValue())
+
+	latency := metrics["latency"]
+	require.Equal(t, pmetric.MetricTypeHistogram, latency.Type())
+	require.Equal(t, 1, latency.Histogram().DataPoints().Len())
+	hdp := latency.Histogram().DataPoints().At(0)
+	require.Equal(t, uint64(4), hdp.Count())
+	require.Equal(t, 5.5, hdp.Sum())
+	require.Equal(t, []float64{1, 2}, hdp.ExplicitBounds().AsRaw())
+	require.Equal(t, []uint64{1, 2, 1}, hdp.BucketCounts().AsRaw())
+	require.Equal(t, map[string]any{"service": "a"}, hdp.Attributes().AsRaw())
+}
+
+func TestInstance_otlp(t *testing.T) {
+	var (
+		mtx      sync.Mutex
+		received []pmetric.Metrics
+		orgID    string
+		header   string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		md, err := (&pmetric.ProtoUnmarshaler{}).UnmarshalMetrics(body)
+		assert.NoError(t, err)
+
+		mtx.Lock()
+		defer mtx.Unlock()
+		received = append(received, md)
+		orgID = r.Header.Get("X-Scope-OrgID")
+		header = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	var cfg Config
+	cfg.RegisterFlagsAndApplyDefaults("", nil)
+	cfg.Path = t.TempDir()
+	cfg.OTLP.Endpoint = server.URL + "/otlp/v1/metrics"
+	cfg.OTLP.Headers = map[string]string{"Authorization": "Bearer token"}
+
+	instance, err := New(&cfg, &mockOverrides{}, "test-tenant", &noopRegisterer{}, log.NewLogfmtLogger(os.Stdout))
+	require.NoError(t, err)
+
+	appender := instance.Appender(context.Background())
+	_, err = appender.Append(0, labels.FromStrings("__name__", "calls_total", "service", "a"), time.Now().UnixMilli(), 1)
+	require.NoError(t, err)
+	require.NoError(t, appender.Commit())
+
+	// rolled back samples are not exported
+	appender = instance.Appender(context.Background())
+	_, err = appender.Append(0, labels.FromStrings("__name__", "calls_total", "service", "b"), time.Now().UnixMilli(), 1)
+	require.NoError(t, err)
+	require.NoError(t, appender.Rollback())
+
+	require.Eventually(t, func() bool {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return len(received) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, instance.Close())
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	require.Len(t, received, 1)
+	require.Equal(t, 1, received[0].DataPointCount())
+	require.Equal(t, "test-tenant", orgID)
+	require.Equal(t, "Bearer token", header)
+}
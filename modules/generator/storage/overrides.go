@@ -4,6 +4,7 @@ import "github.com/grafana/tempo/modules/overrides"
 
 type Overrides interface {
 	MetricsGeneratorRemoteWriteHeaders(userID string) map[string]string
+	MetricsGeneratorRemoteWriteEndpoints(userID string) []string
 	MetricsGeneratorGenerateNativeHistograms(userID string) overrides.HistogramMethod
 }
 
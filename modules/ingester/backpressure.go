@@ -0,0 +1,110 @@
+package ingester
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+var metricUnderPressure = promauto.NewGauge(prometheus.GaugeOpts{
+	Namespace: "tempo",
+	Name:      "ingester_under_pressure",
+	Help:      "1 if the ingester is advertising backpressure in the ring, 0 otherwise.",
+})
+
+// BackpressureConfig configures the instance limits past which an ingester marks itself read-only in the ring.
+// Distributors don't write to read-only ingesters, so traffic shifts to other replicas before the per-tenant
+// limits start rejecting pushes.
+type BackpressureConfig struct {
+	PressureConfig `yaml:",inline"`
+	// The ingester is under pressure when it holds more live traces than this across all tenants. 0 disables.
+	MaxLiveTraces int `yaml:"max_live_traces"`
+	// The ingester is under pressure when the disk holding the WAL is fuller than this ratio. 0 disables.
+	MaxWALDiskUsage float64 `yaml:"max_wal_disk_usage"`
+}
+
+func (cfg *BackpressureConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+".enabled", false, "Mark the ingester read-only in the ring when it is near its instance limits.")
+	cfg.CheckPeriod = 10 * time.Second
+	cfg.ReleaseRatio = 0.9
+}
+
+func (cfg *BackpressureConfig) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if err := cfg.validate("backpressure"); err != nil {
+		return err
+	}
+	if cfg.MaxLiveTraces < 0 {
+		return errors.New("backpressure max_live_traces must not be negative")
+	}
+	if cfg.MaxWALDiskUsage < 0 || cfg.MaxWALDiskUsage > 1 {
+		return errors.New("backpressure max_wal_disk_usage must be between 0 and 1")
+	}
+	return nil
+}
+
+// checkBackpressure advertises pressure in the ring when the ingester is near its instance limits and
+// releases it once usage has dropped.
+func (i *Ingester) checkBackpressure(ctx context.Context) {
+	readOnly, _ := i.lifecycler.GetReadOnlyState()
+	pressure := i.cfg.Backpressure.Enabled && i.underPressure(readOnly)
+
+	if pressure && !readOnly {
+		// never leave fewer writable ingesters than the replication factor
+		writable := i.lifecycler.HealthyInstancesCount() - i.lifecycler.ReadOnlyInstancesCount() - 1
+		if writable < i.cfg.LifecyclerConfig.RingConfig.ReplicationFactor {
+			level.Warn(log.Logger).Log("msg", "ingester is under pressure but too few other ingesters are writable to shift traffic to", "writable", writable)
+			return
+		}
+	}
+
+	if pressure != readOnly {
+		if err := i.lifecycler.ChangeReadOnlyState(ctx, pressure); err != nil {
+			level.Error(log.Logger).Log("msg", "failed to change ingester backpressure state in the ring", "pressure", pressure, "err", err)
+			return
+		}
+		level.Info(log.Logger).Log("msg", "changed ingester backpressure state in the ring", "pressure", pressure)
+	}
+
+	if pressure {
+		metricUnderPressure.Set(1)
+	} else {
+		metricUnderPressure.Set(0)
+	}
+}
+
+// underPressure returns true if the ingester is past its limits. If it is already under pressure,
+// the limits are scaled down by the release ratio.
+func (i *Ingester) underPressure(alreadyUnder bool) bool {
+	cfg := i.cfg.Backpressure
+
+	if cfg.MaxLiveTraces > 0 {
+		liveTraces := 0
+		for _, inst := range i.getInstances() {
+			liveTraces += inst.liveTraces()
+		}
+		if cfg.pastLimit(float64(liveTraces), float64(cfg.MaxLiveTraces), alreadyUnder) {
+			return true
+		}
+	}
+
+	if cfg.MaxWALDiskUsage > 0 {
+		usage, err := diskUsage(i.store.WAL().GetFilepath())
+		if err != nil {
+			level.Warn(log.Logger).Log("msg", "failed to get wal disk usage", "err", err)
+		} else if cfg.pastLimit(usage, cfg.MaxWALDiskUsage, alreadyUnder) {
+			return true
+		}
+	}
+
+	return false
+}
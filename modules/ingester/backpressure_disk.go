@@ -0,0 +1,20 @@
+//go:build !windows
+
+package ingester
+
+import "syscall"
+
+// diskUsage returns the ratio of used space on the filesystem holding path.
+func diskUsage(path string) (float64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+	// same as df: space reserved for root counts as neither used nor available
+	used := float64(stat.Blocks - stat.Bfree)
+	total := used + float64(stat.Bavail)
+	if total == 0 {
+		return 0, nil
+	}
+	return used / total, nil
+}
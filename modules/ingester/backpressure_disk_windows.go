@@ -0,0 +1,8 @@
+package ingester
+
+import "errors"
+
+// diskUsage is not supported on windows.
+func diskUsage(string) (float64, error) {
+	return 0, errors.New("wal disk usage is not supported on windows")
+}
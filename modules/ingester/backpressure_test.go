@@ -0,0 +1,76 @@
+package ingester
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackpressureUnderPressure(t *testing.T) {
+	ingester, _, _ := defaultIngester(t, t.TempDir())
+
+	ingester.cfg.Backpressure = BackpressureConfig{
+		PressureConfig: PressureConfig{
+			Enabled:      true,
+			CheckPeriod:  time.Second,
+			ReleaseRatio: 0.5,
+		},
+		MaxLiveTraces: 10,
+	}
+	require.True(t, ingester.underPressure(false))
+
+	ingester.cfg.Backpressure.MaxLiveTraces = 15
+	require.False(t, ingester.underPressure(false))
+	// still under pressure until live traces drop below 15 * 0.5
+	require.True(t, ingester.underPressure(true))
+
+	ingester.cfg.Backpressure.MaxLiveTraces = 0
+	ingester.cfg.Backpressure.MaxWALDiskUsage = 0.0000001
+	require.True(t, ingester.underPressure(false))
+}
+
+func TestBackpressureRing(t *testing.T) {
+	ingester, _, _ := defaultIngester(t, t.TempDir())
+	ctx := context.Background()
+
+	// the only ingester in the ring never stops taking writes
+	ingester.cfg.Backpressure = BackpressureConfig{
+		PressureConfig: PressureConfig{
+			Enabled:      true,
+			CheckPeriod:  time.Second,
+			ReleaseRatio: 0.9,
+		},
+		MaxLiveTraces: 1,
+	}
+	ingester.checkBackpressure(ctx)
+	readOnly, _ := ingester.lifecycler.GetReadOnlyState()
+	require.False(t, readOnly)
+
+	// pressure advertised before a restart is released once limits are disabled
+	require.NoError(t, ingester.lifecycler.ChangeReadOnlyState(ctx, true))
+	ingester.cfg.Backpressure = BackpressureConfig{}
+	ingester.checkBackpressure(ctx)
+	readOnly, _ = ingester.lifecycler.GetReadOnlyState()
+	require.False(t, readOnly)
+}
+
+func TestBackpressureConfigValidate(t *testing.T) {
+	cfg := BackpressureConfig{}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Enabled = true
+	require.EqualError(t, cfg.Validate(), "backpressure check_period must be greater than 0")
+
+	cfg.CheckPeriod = time.Second
+	cfg.ReleaseRatio = 0.9
+	require.NoError(t, cfg.Validate())
+
+	cfg.MaxWALDiskUsage = 2
+	require.EqualError(t, cfg.Validate(), "backpressure max_wal_disk_usage must be between 0 and 1")
+
+	cfg.MaxWALDiskUsage = 0.8
+	cfg.ReleaseRatio = 0
+	require.EqualError(t, cfg.Validate(), "backpressure release_ratio must be greater than 0 and at most 1")
+}
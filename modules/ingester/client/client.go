@@ -14,6 +14,7 @@ import (
 	"google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/grpcencoding/zstd"
 )
 
 // Config for an ingester client.
@@ -32,6 +33,7 @@ type Client struct {
 
 // RegisterFlags registers flags.
 func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
+	cfg.GRPCClientConfig.CustomCompressors = []string{zstd.Name}
 	cfg.GRPCClientConfig.RegisterFlagsWithPrefix("ingester.client", f)
 
 	f.DurationVar(&cfg.PoolConfig.HealthCheckTimeout, "ingester.client.healthcheck-timeout", 1*time.Second, "Timeout for healthcheck rpcs.")
@@ -40,6 +42,11 @@ func (cfg *Config) RegisterFlags(f *flag.FlagSet) {
 	f.DurationVar(&cfg.RemoteTimeout, "ingester.client.timeout", 5*time.Second, "Timeout for ingester client RPCs.")
 }
 
+// Validate validates the ingester client config.
+func (cfg *Config) Validate() error {
+	return cfg.GRPCClientConfig.Validate()
+}
+
 // New returns a new ingester client.
 func New(addr string, cfg Config) (*Client, error) {
 	opts := []grpc.DialOption{
@@ -0,0 +1,31 @@
+package client
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// receivedTimeKey is the grpc metadata key that carries the time a distributor accepted a push.
+const receivedTimeKey = "x-tempo-received-at"
+
+// InjectReceivedTime adds the time the spans were accepted from the receiver to the outgoing grpc metadata.
+func InjectReceivedTime(ctx context.Context, t time.Time) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, receivedTimeKey, strconv.FormatInt(t.UnixNano(), 10))
+}
+
+// ExtractReceivedTime returns the time the spans were accepted from the receiver, if the distributor sent it.
+func ExtractReceivedTime(ctx context.Context) (time.Time, bool) {
+	values := metadata.ValueFromIncomingContext(ctx, receivedTimeKey)
+	if len(values) == 0 {
+		return time.Time{}, false
+	}
+
+	nanos, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
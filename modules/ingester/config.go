@@ -29,6 +29,12 @@ type Config struct {
 	CompleteBlockTimeout time.Duration `yaml:"complete_block_timeout"`
 	OverrideRingKey      string        `yaml:"override_ring_key"`
 	FlushAllOnShutdown   bool          `yaml:"flush_all_on_shutdown"`
+	WALReplayConcurrency int           `yaml:"wal_replay_concurrency"`
+
+	LiveTracesSnapshotPeriod time.Duration `yaml:"live_traces_snapshot_period"`
+
+	Backpressure   BackpressureConfig   `yaml:"backpressure"`
+	MemoryPressure MemoryPressureConfig `yaml:"memory_pressure"`
 
 	// This config is dynamically injected because defined outside the ingester config.
 	DedicatedColumns    backend.DedicatedColumns `yaml:"-"`
@@ -44,6 +50,8 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	cfg.LifecyclerConfig.RingConfig.HeartbeatTimeout = 5 * time.Minute
 
 	cfg.IngesterPartitionRing.RegisterFlags(f)
+	cfg.Backpressure.RegisterFlagsAndApplyDefaults(prefix+".backpressure", f)
+	cfg.MemoryPressure.RegisterFlagsAndApplyDefaults(prefix+".memory-pressure", f)
 
 	cfg.ConcurrentFlushes = 4
 	cfg.FlushCheckPeriod = 10 * time.Second
@@ -54,7 +62,10 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	f.DurationVar(&cfg.MaxBlockDuration, prefix+".max-block-duration", 30*time.Minute, "Maximum duration which the head block can be appended to before cutting it.")
 	f.Uint64Var(&cfg.MaxBlockBytes, prefix+".max-block-bytes", 500*1024*1024, "Maximum size of the head block before cutting it.")
 	f.DurationVar(&cfg.CompleteBlockTimeout, prefix+".complete-block-timeout", 3*tempodb.DefaultBlocklistPoll, "Duration to keep blocks in the ingester after they have been flushed.")
+	f.IntVar(&cfg.WALReplayConcurrency, prefix+".wal-replay-concurrency", 1, "Number of WAL files replayed concurrently on startup.")
+	f.DurationVar(&cfg.LiveTracesSnapshotPeriod, prefix+".live-traces-snapshot-period", 0, "Period to snapshot the traces that haven't been written to the WAL yet. The snapshots are restored after a crash. 0 to disable.")
 	f.StringVar(&cfg.LifecyclerConfig.Zone, prefix+".availability-zone", "", "Define Availability Zone in which this ingester is running.")
+	f.BoolVar(&cfg.LifecyclerConfig.RingConfig.ZoneAwarenessEnabled, prefix+".zone-awareness-enabled", false, "Place the replicas of a trace on ingesters in distinct availability zones.")
 
 	hostname, err := os.Hostname()
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/rand"
 	"net/http"
+	"runtime/pprof"
 	"strconv"
 	"time"
 
@@ -145,6 +146,9 @@ func (i *Ingester) cutToWalLoop(instance *instance) {
 	go func() {
 		defer i.cutToWalWg.Done()
 
+		// label the goroutine with the tenant so profiles attribute cutting traces to it
+		pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(), pprof.Labels("tenant", instance.instanceID)))
+
 		// wait for the signal to start. we need the wal to be completely replayed
 		// before we start cutting to WAL
 		select {
@@ -157,10 +161,20 @@ func (i *Ingester) cutToWalLoop(instance *instance) {
 		ticker := time.NewTicker(i.cfg.FlushCheckPeriod)
 		defer ticker.Stop()
 
+		// snapshots of the live traces are written from this goroutine, so there is one writer per tenant
+		var snapshotTick <-chan time.Time
+		if i.cfg.LiveTracesSnapshotPeriod > 0 {
+			snapshotTicker := time.NewTicker(i.cfg.LiveTracesSnapshotPeriod)
+			defer snapshotTicker.Stop()
+			snapshotTick = snapshotTicker.C
+		}
+
 		for {
 			select {
 			case <-ticker.C:
 				i.cutOneInstanceToWal(instance, false)
+			case <-snapshotTick:
+				i.snapshotLiveTraces(instance)
 			case <-i.cutToWalStop:
 				return
 			}
@@ -356,6 +370,7 @@ func (i *Ingester) handleFlush(ctx context.Context, userID string, blockID uuid.
 		}
 
 		metricBlocksFlushed.Inc()
+		instance.ingestLatency.flushedBlock(blockID)
 	} else {
 		return false, fmt.Errorf("error getting block to flush")
 	}
@@ -0,0 +1,174 @@
+package ingester
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	ingestStageWAL   = "wal"
+	ingestStageFlush = "flush"
+
+	// number of recent observations per tenant and stage kept for the api
+	ingestLatencyRecentSamples = 1000
+)
+
+var metricIngestLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "tempo",
+	Name:      "ingester_ingest_latency_seconds",
+	Help:      "Time from a distributor accepting spans until they are appended to the WAL or flushed to the backend, per tenant.",
+	Buckets:   []float64{1, 5, 10, 15, 30, 60, 120, 300, 600, 900, 1800, 3600},
+}, []string{"tenant", "stage"})
+
+type receivedTimeKey struct{}
+
+// withReceivedTime stores the time the distributor accepted the spans in the context.
+func withReceivedTime(ctx context.Context, t time.Time) context.Context {
+	return context.WithValue(ctx, receivedTimeKey{}, t)
+}
+
+func receivedTime(ctx context.Context) time.Time {
+	t, _ := ctx.Value(receivedTimeKey{}).(time.Time)
+	return t
+}
+
+// oldest returns the earlier of the two times. A zero time is unknown and ignored.
+func oldest(a, b time.Time) time.Time {
+	if a.IsZero() || (!b.IsZero() && b.Before(a)) {
+		return b
+	}
+	return a
+}
+
+// ingestLatency tracks the ingest latency of a tenant. The metrics are exported as histograms and
+// the most recent observations are kept to summarize them in the api.
+type ingestLatency struct {
+	tenant string
+
+	mtx    sync.Mutex
+	recent map[string]*latencySamples
+	blocks map[uuid.UUID]time.Time // oldest receive time of the spans in each block that isn't flushed yet
+}
+
+type latencySamples struct {
+	count   uint64
+	samples []float64
+	next    int
+}
+
+func newIngestLatency(tenant string) *ingestLatency {
+	return &ingestLatency{
+		tenant: tenant,
+		recent: map[string]*latencySamples{},
+		blocks: map[uuid.UUID]time.Time{},
+	}
+}
+
+func (l *ingestLatency) observe(stage string, received time.Time) {
+	if received.IsZero() {
+		return
+	}
+	// clocks of distributors and ingesters may be skewed
+	seconds := max(time.Since(received).Seconds(), 0)
+	metricIngestLatency.WithLabelValues(l.tenant, stage).Observe(seconds)
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	s, ok := l.recent[stage]
+	if !ok {
+		s = &latencySamples{samples: make([]float64, 0, ingestLatencyRecentSamples)}
+		l.recent[stage] = s
+	}
+	s.count++
+	if len(s.samples) < ingestLatencyRecentSamples {
+		s.samples = append(s.samples, seconds)
+		return
+	}
+	s.samples[s.next] = seconds
+	s.next = (s.next + 1) % ingestLatencyRecentSamples
+}
+
+// cutBlock remembers the oldest receive time of the spans in a block until it's flushed.
+func (l *ingestLatency) cutBlock(blockID uuid.UUID, received time.Time) {
+	if received.IsZero() {
+		return
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.blocks[blockID] = received
+}
+
+func (l *ingestLatency) flushedBlock(blockID uuid.UUID) {
+	l.mtx.Lock()
+	received, ok := l.blocks[blockID]
+	delete(l.blocks, blockID)
+	l.mtx.Unlock()
+
+	if ok {
+		l.observe(ingestStageFlush, received)
+	}
+}
+
+type ingestLatencySummary struct {
+	Count         uint64  `json:"count"`
+	RecentSamples int     `json:"recentSamples"`
+	P50Seconds    float64 `json:"p50Seconds"`
+	P90Seconds    float64 `json:"p90Seconds"`
+	P99Seconds    float64 `json:"p99Seconds"`
+	MaxSeconds    float64 `json:"maxSeconds"`
+}
+
+// summary returns the quantiles of the recent observations per stage.
+func (l *ingestLatency) summary() map[string]ingestLatencySummary {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	summaries := make(map[string]ingestLatencySummary, len(l.recent))
+	for stage, s := range l.recent {
+		sorted := slices.Clone(s.samples)
+		slices.Sort(sorted)
+
+		quantile := func(q float64) float64 {
+			return sorted[int(q*float64(len(sorted)-1))]
+		}
+		summaries[stage] = ingestLatencySummary{
+			Count:         s.count,
+			RecentSamples: len(sorted),
+			P50Seconds:    quantile(0.5),
+			P90Seconds:    quantile(0.9),
+			P99Seconds:    quantile(0.99),
+			MaxSeconds:    sorted[len(sorted)-1],
+		}
+	}
+	return summaries
+}
+
+// IngestLatencyHandler returns a summary of the recent ingest latency per tenant and stage.
+// Pass the tenant query parameter to limit the response to one tenant.
+func (i *Ingester) IngestLatencyHandler(w http.ResponseWriter, r *http.Request) {
+	tenant := r.URL.Query().Get("tenant")
+
+	resp := map[string]map[string]ingestLatencySummary{}
+	for _, inst := range i.getInstances() {
+		if tenant != "" && inst.instanceID != tenant {
+			continue
+		}
+		resp[inst.instanceID] = inst.ingestLatency.summary()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
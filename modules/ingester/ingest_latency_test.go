@@ -0,0 +1,70 @@
+package ingester
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/grafana/dskit/user"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestIngestLatency(t *testing.T) {
+	instance, ingester := defaultInstance(t)
+
+	received := time.Now().Add(-time.Minute)
+	ctx := user.InjectOrgID(context.Background(), testTenantID)
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-tempo-received-at", strconv.FormatInt(received.UnixNano(), 10)))
+
+	_, err := ingester.PushBytesV2(ctx, makeRequest(nil))
+	require.NoError(t, err)
+	// pushes without a receive time are not measured
+	_, err = ingester.PushBytesV2(user.InjectOrgID(context.Background(), testTenantID), makeRequest(nil))
+	require.NoError(t, err)
+
+	require.NoError(t, instance.CutCompleteTraces(0, true))
+	summary := instance.ingestLatency.summary()
+	require.Len(t, summary, 1)
+	require.Equal(t, uint64(1), summary[ingestStageWAL].Count)
+	require.InDelta(t, time.Minute.Seconds(), summary[ingestStageWAL].MaxSeconds, 5)
+
+	blockID, err := instance.CutBlockIfReady(0, 0, true)
+	require.NoError(t, err)
+	require.NoError(t, instance.CompleteBlock(context.Background(), blockID))
+	_, err = ingester.handleFlush(context.Background(), testTenantID, blockID)
+	require.NoError(t, err)
+
+	summary = instance.ingestLatency.summary()
+	require.Equal(t, uint64(1), summary[ingestStageFlush].Count)
+	require.InDelta(t, time.Minute.Seconds(), summary[ingestStageFlush].P50Seconds, 5)
+
+	rec := httptest.NewRecorder()
+	ingester.IngestLatencyHandler(rec, httptest.NewRequest(http.MethodGet, "/ingester/ingest-latency?tenant="+testTenantID, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp map[string]map[string]ingestLatencySummary
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, uint64(1), resp[testTenantID][ingestStageWAL].Count)
+	require.Equal(t, uint64(1), resp[testTenantID][ingestStageFlush].Count)
+}
+
+func TestIngestLatencyRecentSamples(t *testing.T) {
+	l := newIngestLatency("test")
+
+	now := time.Now()
+	for j := 0; j < ingestLatencyRecentSamples+100; j++ {
+		l.observe(ingestStageWAL, now.Add(-time.Duration(j)*time.Second))
+	}
+
+	summary := l.summary()[ingestStageWAL]
+	require.Equal(t, uint64(ingestLatencyRecentSamples+100), summary.Count)
+	require.Equal(t, ingestLatencyRecentSamples, summary.RecentSamples)
+	// the oldest samples were replaced
+	require.InDelta(t, float64(ingestLatencyRecentSamples+99), summary.MaxSeconds, 1)
+	require.GreaterOrEqual(t, summary.P50Seconds, 100.0)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"runtime/debug"
+	"runtime/pprof"
 	"sync"
 	"time"
 
@@ -24,6 +25,7 @@ import (
 	"go.uber.org/atomic"
 	"google.golang.org/grpc/codes"
 
+	"github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
 	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/flushqueues"
@@ -74,6 +76,8 @@ type Ingester struct {
 	local        *local.Backend
 	replayJitter bool // this var exists so tests can remove jitter
 
+	replayProgress *walReplayProgress
+
 	flushQueues     *flushqueues.ExclusiveQueues
 	flushQueuesDone sync.WaitGroup
 
@@ -94,6 +98,13 @@ type Ingester struct {
 
 // New makes a new Ingester.
 func New(cfg Config, store storage.Store, overrides overrides.Interface, reg prometheus.Registerer, singlePartition bool) (*Ingester, error) {
+	if err := cfg.Backpressure.Validate(); err != nil {
+		return nil, err
+	}
+	if err := cfg.MemoryPressure.Validate(); err != nil {
+		return nil, err
+	}
+
 	i := &Ingester{
 		cfg:          cfg,
 		instances:    map[string]*instance{},
@@ -102,6 +113,8 @@ func New(cfg Config, store storage.Store, overrides overrides.Interface, reg pro
 		replayJitter: true,
 		overrides:    overrides,
 
+		replayProgress: newWALReplayProgress(),
+
 		cutToWalStart: make(chan struct{}),
 		cutToWalStop:  make(chan struct{}),
 	}
@@ -157,16 +170,30 @@ func New(cfg Config, store storage.Store, overrides overrides.Interface, reg pro
 }
 
 func (i *Ingester) starting(ctx context.Context) error {
+	i.replayProgress.start()
 	err := i.replayWal()
 	if err != nil {
-		return fmt.Errorf("failed to replay wal: %w", err)
+		err = fmt.Errorf("failed to replay wal: %w", err)
+		i.replayProgress.finish(err)
+		return err
 	}
 
+	i.replayProgress.rediscover()
 	err = i.rediscoverLocalBlocks()
 	if err != nil {
-		return fmt.Errorf("failed to rediscover local blocks: %w", err)
+		err = fmt.Errorf("failed to rediscover local blocks: %w", err)
+		i.replayProgress.finish(err)
+		return err
 	}
 
+	err = i.restoreLiveTraces()
+	if err != nil {
+		err = fmt.Errorf("failed to restore live traces: %w", err)
+		i.replayProgress.finish(err)
+		return err
+	}
+	i.replayProgress.finish(nil)
+
 	i.flushQueuesDone.Add(i.cfg.ConcurrentFlushes)
 	for j := 0; j < i.cfg.ConcurrentFlushes; j++ {
 		go i.flushLoop(j)
@@ -200,11 +227,35 @@ func (i *Ingester) starting(ctx context.Context) error {
 }
 
 func (i *Ingester) running(ctx context.Context) error {
-	select {
-	case <-ctx.Done():
-		return nil
-	case err := <-i.subservicesWatcher.Chan():
-		return fmt.Errorf("ingester subservice failed: %w", err)
+	// check once even if disabled to release pressure advertised before a restart
+	i.checkBackpressure(ctx)
+
+	var backpressureTick <-chan time.Time
+	if i.cfg.Backpressure.Enabled {
+		ticker := time.NewTicker(i.cfg.Backpressure.CheckPeriod)
+		defer ticker.Stop()
+		backpressureTick = ticker.C
+	}
+
+	var memoryPressureTick <-chan time.Time
+	memoryPressure := false
+	if i.cfg.MemoryPressure.Enabled {
+		ticker := time.NewTicker(i.cfg.MemoryPressure.CheckPeriod)
+		defer ticker.Stop()
+		memoryPressureTick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-i.subservicesWatcher.Chan():
+			return fmt.Errorf("ingester subservice failed: %w", err)
+		case <-backpressureTick:
+			i.checkBackpressure(ctx)
+		case <-memoryPressureTick:
+			memoryPressure = i.checkMemoryPressure(memoryPressure)
+		}
 	}
 }
 
@@ -224,6 +275,9 @@ func (i *Ingester) stopping(_ error) error {
 		i.cutAllInstancesToWal()
 	}
 
+	// all live traces are in the wal now
+	i.clearLiveTracesSnapshots()
+
 	if i.flushQueues != nil {
 		i.flushQueues.Stop()
 		i.flushQueuesDone.Wait()
@@ -318,7 +372,22 @@ func (i *Ingester) PushBytesV2(ctx context.Context, req *tempopb.PushBytesReques
 		return nil, err
 	}
 
-	return instance.PushBytesRequest(ctx, req), nil
+	if received, ok := client.ExtractReceivedTime(ctx); ok {
+		ctx = withReceivedTime(ctx, received)
+	}
+
+	var resp *tempopb.PushResponse
+	withTenantProfileLabels(ctx, instanceID, func(ctx context.Context) {
+		resp = instance.PushBytesRequest(ctx, req)
+	})
+	return resp, nil
+}
+
+// withTenantProfileLabels runs f with the tenant attached as a pprof label so CPU and goroutine profiles can
+// attribute work to tenants. Go heap profiles do not record labels, memory held per tenant is reported by
+// tempo_ingester_live_trace_bytes instead.
+func withTenantProfileLabels(ctx context.Context, tenantID string, f func(context.Context)) {
+	pprof.Do(ctx, pprof.Labels("tenant", tenantID), f)
 }
 
 // FindTraceByID implements tempopb.Querier.f
@@ -417,7 +486,7 @@ func (i *Ingester) replayWal() error {
 	// of the blocks correctly. as we are scanning traces in the blocks we read their start/end times
 	// and attempt to set start/end times appropriately. we use now - max_block_duration - ingestion_slack
 	// as the minimum acceptable start time for a replayed block.
-	blocks, err := i.store.WAL().RescanBlocks(i.cfg.MaxBlockDuration, log.Logger)
+	blocks, err := i.store.WAL().RescanBlocksConcurrently(i.cfg.MaxBlockDuration, i.cfg.WALReplayConcurrency, i.replayProgress, log.Logger)
 	if err != nil {
 		return fmt.Errorf("fatal error replaying wal: %w", err)
 	}
@@ -465,36 +534,59 @@ func (i *Ingester) rediscoverLocalBlocks() error {
 
 	level.Info(log.Logger).Log("msg", "reloading local blocks", "tenants", len(tenants))
 
-	for _, t := range tenants {
-		// check if any local blocks exist for a tenant before creating the instance. this is to protect us from cases
-		// where left-over empty local tenant folders persist empty tenants
-		blocks, _, err := reader.Blocks(ctx, t)
-		if err != nil {
-			return err
-		}
-		if len(blocks) == 0 {
-			continue
-		}
+	// tenants are reloaded in parallel with the same concurrency as the wal replay
+	var (
+		errs = make([]error, len(tenants))
+		wg   sync.WaitGroup
+		next = make(chan int)
+	)
+	for j := 0; j < max(1, i.cfg.WALReplayConcurrency); j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range next {
+				errs[idx] = i.rediscoverTenantLocalBlocks(ctx, reader, tenants[idx])
+			}
+		}()
+	}
+	for idx := range tenants {
+		next <- idx
+	}
+	close(next)
+	wg.Wait()
 
-		inst, err := i.getOrCreateInstance(t)
-		if err != nil {
-			return err
-		}
+	return errors.Join(errs...)
+}
 
-		newBlocks, err := inst.rediscoverLocalBlocks(ctx)
-		if err != nil {
-			return fmt.Errorf("getting local blocks for tenant %v: %w", t, err)
-		}
+func (i *Ingester) rediscoverTenantLocalBlocks(ctx context.Context, reader backend.Reader, t string) error {
+	// check if any local blocks exist for a tenant before creating the instance. this is to protect us from cases
+	// where left-over empty local tenant folders persist empty tenants
+	blocks, _, err := reader.Blocks(ctx, t)
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
 
-		// Requeue needed flushes
-		for _, b := range newBlocks {
-			if b.FlushedTime().IsZero() {
-				i.enqueue(&flushOp{
-					kind:    opKindFlush,
-					userID:  t,
-					blockID: (uuid.UUID)(b.BlockMeta().BlockID),
-				}, i.replayJitter)
-			}
+	inst, err := i.getOrCreateInstance(t)
+	if err != nil {
+		return err
+	}
+
+	newBlocks, err := inst.rediscoverLocalBlocks(ctx)
+	if err != nil {
+		return fmt.Errorf("getting local blocks for tenant %v: %w", t, err)
+	}
+
+	// Requeue needed flushes
+	for _, b := range newBlocks {
+		if b.FlushedTime().IsZero() {
+			i.enqueue(&flushOp{
+				kind:    opKindFlush,
+				userID:  t,
+				blockID: (uuid.UUID)(b.BlockMeta().BlockID),
+			}, i.replayJitter)
 		}
 	}
 
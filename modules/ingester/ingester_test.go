@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"flag"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -183,6 +184,87 @@ func TestWal(t *testing.T) {
 	}
 }
 
+func TestWalReplayConcurrently(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	ingester := defaultIngesterModule(t, tmpDir)
+
+	// push traces into several wal blocks
+	var traces []*tempopb.Trace
+	var traceIDs [][]byte
+	for b := 0; b < 3; b++ {
+		for j := 0; j < 5; j++ {
+			id := test.ValidTraceID(nil)
+			testTrace := test.MakeTrace(10, id)
+			trace.SortTrace(testTrace)
+			for _, batch := range testTrace.ResourceSpans {
+				pushBatchV2(t, ingester, batch, id)
+			}
+			traces = append(traces, testTrace)
+			traceIDs = append(traceIDs, id)
+		}
+
+		inst := ingester.instances["test"]
+		require.NoError(t, inst.CutCompleteTraces(0, true))
+		_, err := inst.CutBlockIfReady(0, 0, true)
+		require.NoError(t, err)
+	}
+
+	// create new ingester that replays the wal concurrently
+	cfg := defaultIngesterTestConfig()
+	cfg.WALReplayConcurrency = 3
+	limits, err := overrides.NewOverrides(defaultOverridesConfig(), nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+	ingester, err = New(cfg, defaultIngesterStore(t, tmpDir), limits, prometheus.NewPedanticRegistry(), false)
+	require.NoError(t, err)
+	ingester.replayJitter = false
+	require.Equal(t, replayStatePending, ingester.replayProgress.get().State)
+
+	require.NoError(t, ingester.starting(context.Background()))
+
+	status := ingester.replayProgress.get()
+	require.Equal(t, replayStateComplete, status.State)
+	// the 3 cut blocks and the empty head block
+	require.Equal(t, 4, status.TotalFiles)
+	require.Equal(t, 4, status.ReplayedFiles)
+	require.Equal(t, status.TotalBytes, status.ReplayedBytes)
+	require.Equal(t, "complete", ingester.WALReplayStatus())
+	require.Equal(t, 100.0, status.PercentComplete)
+	require.Equal(t, 0.0, status.ETASeconds)
+
+	for i, traceID := range traceIDs {
+		foundTrace, err := ingester.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+			TraceID: traceID,
+		})
+		require.NoError(t, err, "unexpected error querying")
+		require.NotNil(t, foundTrace.Trace)
+		trace.SortTrace(foundTrace.Trace)
+		test.TracesEqual(t, traces[i], foundTrace.Trace)
+	}
+}
+
+func TestWALReplayEstimate(t *testing.T) {
+	start := time.Unix(1000, 0)
+	status := walReplayStatus{Started: start, TotalBytes: 400}
+
+	// nothing replayed yet
+	status.estimate(start.Add(10 * time.Second))
+	require.Equal(t, 0.0, status.PercentComplete)
+	require.Equal(t, 0.0, status.ETASeconds)
+
+	// a quarter of the bytes in 10s leaves 30s
+	status.ReplayedBytes = 100
+	status.estimate(start.Add(10 * time.Second))
+	require.Equal(t, 25.0, status.PercentComplete)
+	require.Equal(t, 30.0, status.ETASeconds)
+
+	status.ReplayedBytes = 400
+	status.estimate(start.Add(20 * time.Second))
+	require.Equal(t, 100.0, status.PercentComplete)
+	require.Equal(t, 0.0, status.ETASeconds)
+}
+
 func TestWalDropsZeroLength(t *testing.T) {
 	tmpDir := t.TempDir()
 	ingester, _, _ := defaultIngester(t, tmpDir)
@@ -351,6 +433,81 @@ func TestRediscoverDropsInvalidBlocks(t *testing.T) {
 	require.Len(t, instance.completeBlocks, 0)
 }
 
+func TestRediscoverEncryptedBlocks(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	newIngester := func(keyFile string) *Ingester {
+		limits, err := overrides.NewOverrides(defaultOverridesConfig(), nil, prometheus.DefaultRegisterer)
+		require.NoError(t, err)
+
+		s := ingesterStoreWithWAL(t, tmpDir, &wal.Config{
+			Filepath:               tmpDir,
+			EncryptCompletedBlocks: true,
+			EncryptionKeyFile:      keyFile,
+		})
+
+		i, err := New(defaultIngesterTestConfig(), s, limits, prometheus.NewPedanticRegistry(), false)
+		require.NoError(t, err)
+		i.replayJitter = false
+
+		require.NoError(t, i.starting(context.Background()))
+		return i
+	}
+
+	ctx := user.InjectOrgID(context.Background(), "test")
+	ingester := newIngester("")
+	inst, err := ingester.getOrCreateInstance("test")
+	require.NoError(t, err)
+	traces, traceIDs := pushTracesToInstance(t, inst, 10)
+
+	// force cut and complete all blocks
+	for _, instance := range ingester.instances {
+		err := instance.CutCompleteTraces(0, true)
+		require.NoError(t, err)
+
+		blockID, err := instance.CutBlockIfReady(0, 0, true)
+		require.NoError(t, err)
+
+		err = instance.CompleteBlock(context.Background(), blockID)
+		require.NoError(t, err)
+
+		err = instance.ClearCompletingBlock(blockID)
+		require.NoError(t, err)
+	}
+
+	assertTraces := func(ingester *Ingester) {
+		for i, traceID := range traceIDs {
+			foundTrace, err := ingester.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+				TraceID: traceID,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, foundTrace.Trace)
+			trace.SortTrace(foundTrace.Trace)
+			require.True(t, proto.Equal(traces[i], foundTrace.Trace))
+		}
+	}
+
+	// the encrypted complete block can be read by the process that wrote it
+	assertTraces(ingester)
+
+	// a restarted ingester reads the block with the persisted key
+	ingester = newIngester("")
+	require.Len(t, ingester.instances["test"].completeBlocks, 1)
+	assertTraces(ingester)
+
+	// an ingester with another key ignores the block but keeps it on disk
+	ingester = newIngester(filepath.Join(t.TempDir(), "other.key"))
+
+	instance, ok := ingester.instances["test"]
+	if ok {
+		require.Len(t, instance.completeBlocks, 0)
+	}
+
+	blocks, _, err := backend.NewReader(ingester.local).Blocks(ctx, "test")
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+}
+
 // TODO - This test is flaky and commented out until it's fixed
 // TestWalReplayDeletesLocalBlocks simulates the condition where an ingester restarts after a wal is completed
 // to the local disk, but before the wal is deleted. On startup both blocks exist, and the ingester now errs
@@ -517,6 +674,12 @@ func defaultIngesterModule(t testing.TB, tmpDir string) *Ingester {
 }
 
 func defaultIngesterStore(t testing.TB, tmpDir string) storage.Store {
+	return ingesterStoreWithWAL(t, tmpDir, &wal.Config{
+		Filepath: tmpDir,
+	})
+}
+
+func ingesterStoreWithWAL(t testing.TB, tmpDir string, walCfg *wal.Config) storage.Store {
 	s, err := storage.NewStore(storage.Config{
 		Trace: tempodb.Config{
 			Backend: backend.Local,
@@ -531,9 +694,7 @@ func defaultIngesterStore(t testing.TB, tmpDir string) storage.Store {
 				Encoding:             backend.EncLZ4_1M,
 				IndexPageSizeBytes:   1000,
 			},
-			WAL: &wal.Config{
-				Filepath: tmpDir,
-			},
+			WAL: walCfg,
 		},
 	}, nil, log.NewNopLogger())
 	require.NoError(t, err, "unexpected error store")
@@ -37,6 +37,8 @@ import (
 var (
 	errTraceTooLarge = errors.New(overrides.ErrorPrefixTraceTooLarge)
 	errMaxLiveTraces = errors.New(overrides.ErrorPrefixLiveTracesExceeded)
+	// errMaxLiveTracesBytes is returned when the live traces would exceed max_live_traces_bytes
+	errMaxLiveTracesBytes = errors.New(overrides.ErrorPrefixLiveTracesBytesExceeded + ": max_live_traces_bytes exceeded")
 )
 
 const (
@@ -83,8 +85,9 @@ type instance struct {
 	traceSizes     *tracesizes.Tracker
 	traceSizeBytes uint64
 
-	headBlockMtx sync.RWMutex
-	headBlock    common.WALBlock
+	headBlockMtx      sync.RWMutex
+	headBlock         common.WALBlock
+	headBlockReceived time.Time // oldest receive time of the spans in the head block
 
 	blocksMtx        sync.RWMutex
 	completingBlocks []common.WALBlock
@@ -95,6 +98,7 @@ type instance struct {
 	instanceID         string
 	tracesCreatedTotal prometheus.Counter
 	bytesReceivedTotal *prometheus.CounterVec
+	ingestLatency      *ingestLatency
 	limiter            Limiter
 	writer             tempodb.Writer
 
@@ -120,6 +124,7 @@ func newInstance(instanceID string, limiter Limiter, overrides ingesterOverrides
 		instanceID:         instanceID,
 		tracesCreatedTotal: metricTracesCreatedTotal.WithLabelValues(instanceID),
 		bytesReceivedTotal: metricBytesReceivedTotal,
+		ingestLatency:      newIngestLatency(instanceID),
 		limiter:            limiter,
 		writer:             writer,
 
@@ -168,12 +173,17 @@ func (i *instance) addTraceError(errorsByTrace []tempopb.PushErrorReason, pushEr
 			return errorsByTrace
 		}
 
+		if errors.Is(pushError, errMaxLiveTracesBytes) {
+			errorsByTrace = append(errorsByTrace, tempopb.PushErrorReason_MAX_LIVE_TRACES_BYTES)
+			return errorsByTrace
+		}
+
 		if errors.Is(pushError, errTraceTooLarge) {
 			errorsByTrace = append(errorsByTrace, tempopb.PushErrorReason_TRACE_TOO_LARGE)
 			return errorsByTrace
 		}
 
-		// error is not either MaxLiveTraces or TraceTooLarge
+		// error is not either MaxLiveTraces, MaxLiveTracesBytes or TraceTooLarge
 		level.Error(i.logger).Log("msg", "Unexpected error during PushBytes", "error", pushError)
 		errorsByTrace = append(errorsByTrace, tempopb.PushErrorReason_UNKNOWN_ERROR)
 		return errorsByTrace
@@ -208,6 +218,11 @@ func (i *instance) push(ctx context.Context, id, traceBytes []byte) error {
 	maxBytes := i.limiter.Limits().MaxBytesPerTrace(i.instanceID)
 	reqSize := len(traceBytes)
 
+	maxLiveBytes := i.limiter.Limits().MaxLiveTracesBytes(i.instanceID)
+	if maxLiveBytes > 0 && i.traceSizeBytes+uint64(reqSize) > maxLiveBytes {
+		return errMaxLiveTracesBytes
+	}
+
 	if maxBytes > 0 && !i.traceSizes.Allow(id, reqSize, maxBytes) {
 		i.maxTraceLogger.Log("msg", overrides.ErrorPrefixTraceTooLarge, "max", maxBytes, "size", reqSize, "trace", hex.EncodeToString(id))
 		return errTraceTooLarge
@@ -233,6 +248,14 @@ func (i *instance) measureReceivedBytes(traceBytes []byte) {
 	i.bytesReceivedTotal.WithLabelValues(i.instanceID, traceDataType).Add(float64(len(traceBytes)))
 }
 
+// liveTraces returns the number of traces that haven't been cut to the head block yet.
+func (i *instance) liveTraces() int {
+	i.tracesMtx.Lock()
+	defer i.tracesMtx.Unlock()
+
+	return len(i.traces)
+}
+
 // CutCompleteTraces moves any complete traces out of the map to complete traces.
 func (i *instance) CutCompleteTraces(cutoff time.Duration, immediate bool) error {
 	tracesToCut := i.tracesToCut(cutoff, immediate)
@@ -252,10 +275,11 @@ func (i *instance) CutCompleteTraces(cutoff time.Duration, immediate bool) error
 			return err
 		}
 
-		err = i.writeTraceToHeadBlock(t.traceID, out, t.start, t.end)
+		err = i.writeTraceToHeadBlock(t.traceID, out, t.start, t.end, t.received)
 		if err != nil {
 			return err
 		}
+		i.ingestLatency.observe(ingestStageWAL, t.received)
 
 		// return trace byte slices to be reused by proto marshalling
 		//  WARNING: can't reuse traceid's b/c the appender takes ownership of byte slices that are passed to it
@@ -289,6 +313,8 @@ func (i *instance) CutBlockIfReady(maxBlockLifetime time.Duration, maxBlockBytes
 		}
 
 		completingBlock := i.headBlock
+		i.ingestLatency.cutBlock((uuid.UUID)(completingBlock.BlockMeta().BlockID), i.headBlockReceived)
+		i.headBlockReceived = time.Time{}
 
 		// Now that we are adding a new block take the blocks mutex.
 		// A warning about deadlocks!!  This area does a hard-acquire of both mutexes.
@@ -562,7 +588,7 @@ func (i *instance) tracesToCut(cutoff time.Duration, immediate bool) []*liveTrac
 	return tracesToCut
 }
 
-func (i *instance) writeTraceToHeadBlock(id common.ID, b []byte, start, end uint32) error {
+func (i *instance) writeTraceToHeadBlock(id common.ID, b []byte, start, end uint32, received time.Time) error {
 	i.headBlockMtx.Lock()
 	defer i.headBlockMtx.Unlock()
 
@@ -571,6 +597,7 @@ func (i *instance) writeTraceToHeadBlock(id common.ID, b []byte, start, end uint
 	if err != nil {
 		return err
 	}
+	i.headBlockReceived = oldest(i.headBlockReceived, received)
 
 	return nil
 }
@@ -612,6 +639,10 @@ func (i *instance) rediscoverLocalBlocks(ctx context.Context) ([]*LocalBlock, er
 				if err != nil {
 					return nil, fmt.Errorf("deleting bad local block tenant %v block %v: %w", i.instanceID, id.String(), err)
 				}
+			} else if errors.Is(err, local.ErrUnknownEncryptionKey) {
+				// The block was encrypted with another key. Keep it so it can be recovered by restoring that key.
+				level.Error(i.logger).Log("msg", "Local block is encrypted with a different key than the configured one. Ignoring and continuing. Restore the key to recover the block.", "block", id.String())
+				metricReplayErrorsTotal.WithLabelValues(i.instanceID).Inc()
 			} else {
 				// Block with unknown error
 				level.Error(i.logger).Log("msg", "Unexpected error reloading meta for local block. Ignoring and continuing. This block should be investigated.", "block", id.String(), "error", err)
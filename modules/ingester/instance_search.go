@@ -45,9 +45,16 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 		resultsMtx = sync.Mutex{}
 		combiner   = traceql.NewMetadataCombiner()
 		metrics    = &tempopb.SearchMetrics{}
+		analysis   []*tempopb.SearchBlockAnalysis
 		opts       = common.DefaultSearchOptions()
 		anyErr     atomic.Error
+		// most recent searches can't stop at the first maxResults traces. every block is searched
+		// and only the newest traces are kept
+		mostRecent = api.IsMostRecentSearch(req)
 	)
+	if mostRecent {
+		combiner = traceql.NewMostRecentMetadataCombiner(maxResults)
+	}
 
 	search := func(blockID uuid.UUID, block common.Searcher, spanName string) {
 		ctx, span := tracer.Start(ctx, "instance.searchBlock."+spanName)
@@ -94,6 +101,17 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 			metrics.InspectedTraces += resp.Metrics.InspectedTraces
 			metrics.InspectedBytes += resp.Metrics.InspectedBytes
 		}
+		for _, a := range resp.Analysis {
+			a.BlockID = blockID.String()
+			analysis = append(analysis, a)
+		}
+
+		if mostRecent {
+			for _, tr := range resp.Traces {
+				combiner.AddMetadata(tr)
+			}
+			return
+		}
 
 		if combiner.Count() >= maxResults {
 			return
@@ -125,10 +143,11 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 	if err := anyErr.Load(); err != nil {
 		return nil, err
 	}
-	if combiner.Count() >= maxResults {
+	if !mostRecent && combiner.Count() >= maxResults {
 		return &tempopb.SearchResponse{
-			Traces:  combiner.Metadata(),
-			Metrics: metrics,
+			Traces:   combiner.Metadata(),
+			Metrics:  metrics,
+			Analysis: analysis,
 		}, nil
 	}
 
@@ -171,8 +190,9 @@ func (i *instance) Search(ctx context.Context, req *tempopb.SearchRequest) (*tem
 		return nil, err
 	}
 	return &tempopb.SearchResponse{
-		Traces:  combiner.Metadata(),
-		Metrics: metrics,
+		Traces:   combiner.Metadata(),
+		Metrics:  metrics,
+		Analysis: analysis,
 	}, nil
 }
 
@@ -428,10 +448,10 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 		return &tempopb.SearchTagValuesV2Response{}, nil
 	}
 
-	query := traceql.ExtractMatchers(req.Query)
+	query := traceql.TagValuesQuery(req.Query)
 	// cacheKey will be same for all blocks in a request so only compute it once
 	// NOTE: cacheKey tag name and query, so if we start respecting start and end, add them to the cacheKey
-	cacheKey := searchTagValuesV2CacheKey(req, limit, "cache_search_tagvaluesv2")
+	cacheKey := searchTagValuesV2CacheKey(req, query, limit, "cache_search_tagvaluesv2")
 
 	// helper functions as closures, to access local variables
 	performSearch := func(ctx context.Context, s common.Searcher, collector *collector.DistinctValue[tempopb.TagValue]) error {
@@ -444,7 +464,19 @@ func (i *instance) SearchTagValuesV2(ctx context.Context, req *tempopb.SearchTag
 			return s.FetchTagValues(ctx, req, cb, mc.Add, common.DefaultSearchOptions())
 		})
 
-		return engine.ExecuteTagValues(ctx, tag, query, traceql.MakeCollectTagValueFunc(collector.Collect), fetcher)
+		// queries the tag values fetcher can't evaluate exactly are run against the spans
+		var spansResp traceql.FetchSpansResponse
+		spansetFetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+			var err error
+			spansResp, err = s.Fetch(ctx, req, common.DefaultSearchOptions())
+			return spansResp, err
+		})
+
+		err := engine.ExecuteTagValues(ctx, tag, query, traceql.MakeCollectTagValueFunc(collector.Collect), fetcher, spansetFetcher)
+		if spansResp.Bytes != nil {
+			mc.Add(spansResp.Bytes())
+		}
+		return err
 	}
 
 	exitEarly := func() bool {
@@ -618,18 +650,14 @@ func includeBlock(b *backend.BlockMeta, req *tempopb.SearchRequest) bool {
 
 // searchTagValuesV2CacheKey generates a cache key for the searchTagValuesV2 request
 // cache key is used as the filename to store the protobuf data on disk
-func searchTagValuesV2CacheKey(req *tempopb.SearchTagValuesRequest, limit int, prefix string) string {
-	query := req.Query
-	if req.Query != "" {
-		ast, err := traceql.Parse(req.Query)
-		if err == nil {
-			// forces the query into a canonical form
-			query = ast.String()
-		} else {
-			// In case of a bad TraceQL query, we ignore the query and return unfiltered results.
-			// if we fail to parse the query, we will assume query is empty and compute the cache key.
-			query = ""
-		}
+func searchTagValuesV2CacheKey(req *tempopb.SearchTagValuesRequest, query string, limit int, prefix string) string {
+	if ast, err := traceql.Parse(query); err == nil {
+		// forces the query into a canonical form
+		query = ast.String()
+	} else {
+		// In case of a bad TraceQL query, we ignore the query and return unfiltered results.
+		// if we fail to parse the query, we will assume query is empty and compute the cache key.
+		query = ""
 	}
 
 	// NOTE: we are not adding req.Start and req.End to the cache key because we don't respect the start and end
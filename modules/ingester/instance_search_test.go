@@ -24,6 +24,7 @@ import (
 	"github.com/grafana/tempo/pkg/tempopb"
 	v1 "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	trace_v1 "github.com/grafana/tempo/pkg/tempopb/trace/v1"
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -415,7 +416,7 @@ func cacheKeysForTestSearchTagValuesV2(tagKey, query string, limit int) []string
 			TagName: fmt.Sprintf("%s.%s", prefix, tagKey),
 			Query:   query,
 		}
-		cacheKey := searchTagValuesV2CacheKey(req, limit, "cache_search_tagvaluesv2")
+		cacheKey := searchTagValuesV2CacheKey(req, traceql.TagValuesQuery(query), limit, "cache_search_tagvaluesv2")
 		cacheKeys = append(cacheKeys, cacheKey)
 	}
 
@@ -1075,7 +1076,7 @@ func Test_searchTagValuesV2CacheKey(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cacheKey := searchTagValuesV2CacheKey(tt.req, tt.limit, tt.prefix)
+			cacheKey := searchTagValuesV2CacheKey(tt.req, traceql.TagValuesQuery(tt.req.Query), tt.limit, tt.prefix)
 			require.Equal(t, tt.expectedCacheKey, cacheKey)
 		})
 	}
@@ -336,6 +336,46 @@ func TestInstanceLimits(t *testing.T) {
 	}
 }
 
+func TestInstanceMaxLiveTracesBytes(t *testing.T) {
+	first := makeRequestWithByteLimit(400, []byte{})
+	second := makeRequestWithByteLimit(400, []byte{})
+	third := makeRequestWithByteLimit(400, []byte{})
+
+	// allow the first two requests, but not the third
+	maxLiveBytes := uint64(len(first.Traces[0].Slice) + len(second.Traces[0].Slice))
+
+	limits, err := overrides.NewOverrides(overrides.Config{
+		Defaults: overrides.Overrides{
+			Ingestion: overrides.IngestionOverrides{
+				MaxLiveTracesBytes: maxLiveBytes,
+			},
+		},
+	}, nil, prometheus.NewRegistry())
+	require.NoError(t, err, "unexpected error creating limits")
+
+	ingester, _, _ := defaultIngester(t, t.TempDir())
+	ingester.limiter = NewLimiter(limits, &ringCountMock{count: 1}, 1)
+
+	i, err := ingester.getOrCreateInstance(testTenantID)
+	require.NoError(t, err, "unexpected error creating new instance")
+
+	for _, req := range []*tempopb.PushBytesRequest{first, second} {
+		errored, _, _ := CheckPushBytesError(i.PushBytesRequest(context.Background(), req))
+		require.False(t, errored)
+	}
+	require.Equal(t, maxLiveBytes, i.traceSizeBytes)
+
+	// the bytes limit is reported separately from the live traces limit
+	resp := i.PushBytesRequest(context.Background(), third)
+	require.Equal(t, []tempopb.PushErrorReason{tempopb.PushErrorReason_MAX_LIVE_TRACES_BYTES}, resp.ErrorsByTrace)
+	require.Equal(t, maxLiveBytes, i.traceSizeBytes)
+
+	// cutting the live traces frees up room
+	require.NoError(t, i.CutCompleteTraces(0, true))
+	resp = i.PushBytesRequest(context.Background(), third)
+	require.Empty(t, resp.ErrorsByTrace)
+}
+
 func TestInstanceCutCompleteTraces(t *testing.T) {
 	id := make([]byte, 16)
 	_, err := crand.Read(id)
@@ -0,0 +1,325 @@
+package ingester
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+var (
+	metricLiveTracesSnapshotFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_live_traces_snapshot_failures_total",
+		Help:      "The total number of failed snapshots of live traces per tenant.",
+	}, []string{"tenant"})
+	metricLiveTracesSnapshotBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_live_traces_snapshot_bytes",
+		Help:      "The size of the last snapshot of live traces per tenant.",
+	}, []string{"tenant"})
+	metricLiveTracesRestored = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_live_traces_restored_total",
+		Help:      "The total number of live traces restored from snapshots on startup per tenant.",
+	}, []string{"tenant"})
+)
+
+const (
+	liveTracesSnapshotMagic   = "TLTS"
+	liveTracesSnapshotVersion = 1
+	liveTracesSnapshotTmp     = ".tmp"
+)
+
+var errInvalidLiveTracesSnapshot = errors.New("invalid live traces snapshot")
+
+// snapshotLiveTraces writes the traces that haven't been cut to the head block yet to a snapshot in dir, so they
+// can be restored if the ingester crashes before they are written to the wal. The snapshot of the tenant is replaced
+// atomically and removed if there are no live traces. It returns the number of traces in the snapshot.
+func (i *instance) snapshotLiveTraces(dir string) (int, error) {
+	// encode under lock. the batches of cut traces are reused once they are removed from the map
+	i.tracesMtx.Lock()
+	n := len(i.traces)
+	var b []byte
+	if n > 0 {
+		b = encodeLiveTraces(i.traces)
+	}
+	i.tracesMtx.Unlock()
+
+	path := filepath.Join(dir, i.instanceID)
+	if n == 0 {
+		metricLiveTracesSnapshotBytes.WithLabelValues(i.instanceID).Set(0)
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return 0, err
+	}
+	tmp := path + liveTracesSnapshotTmp
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return 0, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return 0, err
+	}
+
+	metricLiveTracesSnapshotBytes.WithLabelValues(i.instanceID).Set(float64(len(b)))
+	return n, nil
+}
+
+// restoreLiveTraces adds the traces of a snapshot to the live traces. Traces that were cut to the wal after the
+// snapshot was taken are restored as well, their spans are deduplicated when the trace is combined.
+func (i *instance) restoreLiveTraces(traces []*liveTrace) {
+	i.tracesMtx.Lock()
+	defer i.tracesMtx.Unlock()
+
+	now := time.Now()
+	for _, t := range traces {
+		trace := i.getOrCreateTrace(t.traceID, i.tokenForTraceID(t.traceID))
+		trace.batches = append(trace.batches, t.batches...)
+		// the idle period starts again so the trace is given time to complete
+		trace.lastAppend = now
+		trace.received = oldest(trace.received, t.received)
+		if trace.start == 0 || t.start < trace.start {
+			trace.start = t.start
+		}
+		if trace.end == 0 || t.end > trace.end {
+			trace.end = t.end
+		}
+
+		i.traceSizeBytes += t.Size()
+	}
+
+	metricLiveTracesRestored.WithLabelValues(i.instanceID).Add(float64(len(traces)))
+}
+
+// snapshotLiveTraces snapshots the live traces of the instance to the wal folder.
+func (i *Ingester) snapshotLiveTraces(instance *instance) {
+	n, err := instance.snapshotLiveTraces(i.store.WAL().LiveTracesPath())
+	if err != nil {
+		metricLiveTracesSnapshotFailures.WithLabelValues(instance.instanceID).Inc()
+		level.Error(log.WithUserID(instance.instanceID, log.Logger)).Log("msg", "failed to snapshot live traces", "err", err)
+		return
+	}
+	level.Debug(log.WithUserID(instance.instanceID, log.Logger)).Log("msg", "live traces snapshotted", "traces", n)
+}
+
+// restoreLiveTraces restores the live traces of all tenants from the snapshots written before a crash. Snapshots that
+// can't be read are dropped. The snapshots are kept so the traces are not lost if the ingester crashes again before
+// the next snapshot, unless snapshots are disabled.
+func (i *Ingester) restoreLiveTraces() error {
+	dir := i.store.WAL().LiveTracesPath()
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading live traces snapshots: %w", err)
+	}
+
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name())
+		if e.IsDir() || strings.HasSuffix(e.Name(), liveTracesSnapshotTmp) {
+			// left over from a snapshot that didn't complete
+			_ = os.RemoveAll(path)
+			continue
+		}
+
+		tenantID := e.Name()
+		traces, err := readLiveTracesSnapshot(path)
+		if err != nil {
+			level.Warn(log.Logger).Log("msg", "failed to read live traces snapshot. removing.", "tenant", tenantID, "err", err)
+			_ = os.Remove(path)
+			continue
+		}
+
+		inst, err := i.getOrCreateInstance(tenantID)
+		if err != nil {
+			return err
+		}
+		inst.restoreLiveTraces(traces)
+		level.Info(log.Logger).Log("msg", "restored live traces from snapshot", "tenant", tenantID, "traces", len(traces))
+
+		if i.cfg.LiveTracesSnapshotPeriod <= 0 {
+			_ = os.Remove(path)
+		}
+	}
+
+	return nil
+}
+
+// clearLiveTracesSnapshots removes all snapshots. It is called once the live traces have been written to the wal.
+func (i *Ingester) clearLiveTracesSnapshots() {
+	if err := os.RemoveAll(i.store.WAL().LiveTracesPath()); err != nil {
+		level.Warn(log.Logger).Log("msg", "failed to remove live traces snapshots", "err", err)
+	}
+}
+
+func readLiveTracesSnapshot(path string) ([]*liveTrace, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return decodeLiveTraces(b)
+}
+
+// encodeLiveTraces encodes the traces into a snapshot. The snapshot is a header followed by the traces and a crc32 of
+// everything before it:
+//
+//	magic | version | encoding
+//	trace id | start | end | received | batches...
+//	crc32
+//
+// The batches are stored in the segment encoding they were pushed with.
+func encodeLiveTraces(traces map[uint32]*liveTrace) []byte {
+	b := make([]byte, 0, 1024)
+	b = append(b, liveTracesSnapshotMagic...)
+	b = append(b, liveTracesSnapshotVersion)
+	b = appendBytes(b, []byte(model.CurrentEncoding))
+
+	for _, t := range traces {
+		b = appendBytes(b, t.traceID)
+		b = binary.BigEndian.AppendUint32(b, t.start)
+		b = binary.BigEndian.AppendUint32(b, t.end)
+		var received int64
+		if !t.received.IsZero() {
+			received = t.received.UnixNano()
+		}
+		b = binary.AppendVarint(b, received)
+		b = binary.AppendUvarint(b, uint64(len(t.batches)))
+		for _, batch := range t.batches {
+			b = appendBytes(b, batch)
+		}
+	}
+
+	return binary.BigEndian.AppendUint32(b, crc32.ChecksumIEEE(b))
+}
+
+func decodeLiveTraces(b []byte) ([]*liveTrace, error) {
+	if len(b) < len(liveTracesSnapshotMagic)+1+crc32.Size {
+		return nil, fmt.Errorf("%w: too short", errInvalidLiveTracesSnapshot)
+	}
+	data, sum := b[:len(b)-crc32.Size], b[len(b)-crc32.Size:]
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(sum) {
+		return nil, fmt.Errorf("%w: checksum mismatch", errInvalidLiveTracesSnapshot)
+	}
+	if !bytes.HasPrefix(data, []byte(liveTracesSnapshotMagic)) {
+		return nil, fmt.Errorf("%w: unknown format", errInvalidLiveTracesSnapshot)
+	}
+	data = data[len(liveTracesSnapshotMagic):]
+	if data[0] != liveTracesSnapshotVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", errInvalidLiveTracesSnapshot, data[0])
+	}
+	r := snapshotReader{b: data[1:]}
+
+	if enc := string(r.bytes()); r.err == nil && enc != model.CurrentEncoding {
+		return nil, fmt.Errorf("%w: unsupported encoding %s", errInvalidLiveTracesSnapshot, enc)
+	}
+
+	var traces []*liveTrace
+	for r.err == nil && len(r.b) > 0 {
+		t := newTrace(r.bytes())
+		t.start = r.uint32()
+		t.end = r.uint32()
+		if received := r.varint(); received != 0 {
+			t.received = time.Unix(0, received)
+		}
+		batches := r.uvarint()
+		if batches > uint64(len(r.b)) {
+			r.err = fmt.Errorf("%w: truncated", errInvalidLiveTracesSnapshot)
+			break
+		}
+		t.batches = make([][]byte, 0, batches)
+		for j := uint64(0); j < batches && r.err == nil; j++ {
+			t.batches = append(t.batches, r.bytes())
+		}
+		traces = append(traces, t)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return traces, nil
+}
+
+func appendBytes(b, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// snapshotReader reads the fields of a snapshot. After the first error all reads return zero values.
+type snapshotReader struct {
+	b   []byte
+	err error
+}
+
+func (r *snapshotReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.b)
+	if n <= 0 {
+		r.err = fmt.Errorf("%w: truncated", errInvalidLiveTracesSnapshot)
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *snapshotReader) varint() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.b)
+	if n <= 0 {
+		r.err = fmt.Errorf("%w: truncated", errInvalidLiveTracesSnapshot)
+		return 0
+	}
+	r.b = r.b[n:]
+	return v
+}
+
+func (r *snapshotReader) uint32() uint32 {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.b) < 4 {
+		r.err = fmt.Errorf("%w: truncated", errInvalidLiveTracesSnapshot)
+		return 0
+	}
+	v := binary.BigEndian.Uint32(r.b)
+	r.b = r.b[4:]
+	return v
+}
+
+// bytes returns a copy of the next field, the snapshot isn't kept in memory.
+func (r *snapshotReader) bytes() []byte {
+	l := r.uvarint()
+	if r.err != nil {
+		return nil
+	}
+	if l > uint64(len(r.b)) {
+		r.err = fmt.Errorf("%w: truncated", errInvalidLiveTracesSnapshot)
+		return nil
+	}
+	v := make([]byte, l)
+	copy(v, r.b[:l])
+	r.b = r.b[l:]
+	return v
+}
@@ -0,0 +1,147 @@
+package ingester
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/grafana/dskit/user"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+)
+
+func TestLiveTracesSnapshotEncoding(t *testing.T) {
+	traces := map[uint32]*liveTrace{}
+	for j := 0; j < 3; j++ {
+		tr := newTrace(test.ValidTraceID(nil))
+		tr.batches = [][]byte{{1, 2, 3}, {}, {4}}
+		tr.start = uint32(j + 1)
+		tr.end = uint32(j + 10)
+		if j > 0 {
+			tr.received = time.Unix(0, int64(j)*1000)
+		}
+		traces[uint32(j)] = tr
+	}
+
+	b := encodeLiveTraces(traces)
+	decoded, err := decodeLiveTraces(b)
+	require.NoError(t, err)
+	require.Len(t, decoded, len(traces))
+
+	byID := map[string]*liveTrace{}
+	for _, tr := range decoded {
+		byID[string(tr.traceID)] = tr
+	}
+	for _, expected := range traces {
+		actual := byID[string(expected.traceID)]
+		require.NotNil(t, actual)
+		require.Equal(t, expected.batches, actual.batches)
+		require.Equal(t, expected.start, actual.start)
+		require.Equal(t, expected.end, actual.end)
+		require.True(t, expected.received.Equal(actual.received))
+	}
+
+	// corrupted and truncated snapshots are rejected
+	corrupted := append([]byte{}, b...)
+	corrupted[10]++
+	_, err = decodeLiveTraces(corrupted)
+	require.ErrorIs(t, err, errInvalidLiveTracesSnapshot)
+
+	_, err = decodeLiveTraces(b[:len(b)-5])
+	require.ErrorIs(t, err, errInvalidLiveTracesSnapshot)
+
+	_, err = decodeLiveTraces(nil)
+	require.ErrorIs(t, err, errInvalidLiveTracesSnapshot)
+}
+
+func TestLiveTracesSnapshotRestore(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := user.InjectOrgID(context.Background(), "test")
+
+	ingester, traces, traceIDs := defaultIngester(t, tmpDir)
+	inst := ingester.instances["test"]
+
+	// cut some traces to the wal, the others are only in the snapshot
+	for _, traceID := range traceIDs[5:] {
+		inst.traces[inst.tokenForTraceID(traceID)].lastAppend = time.Now().Add(time.Hour)
+	}
+	require.NoError(t, inst.CutCompleteTraces(0, false))
+	require.Equal(t, 5, inst.liveTraces())
+
+	ingester.snapshotLiveTraces(inst)
+	path := filepath.Join(ingester.store.WAL().LiveTracesPath(), "test")
+	require.FileExists(t, path)
+
+	// create new ingester without stopping the old one. this simulates a crash
+	cfg := defaultIngesterTestConfig()
+	cfg.LiveTracesSnapshotPeriod = time.Hour
+	limits, err := overrides.NewOverrides(defaultOverridesConfig(), nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+	ingester, err = New(cfg, defaultIngesterStore(t, tmpDir), limits, prometheus.NewPedanticRegistry(), false)
+	require.NoError(t, err)
+	ingester.replayJitter = false
+	require.NoError(t, ingester.starting(context.Background()))
+
+	inst = ingester.instances["test"]
+	require.Equal(t, 5, inst.liveTraces())
+	// the snapshot is kept until the next one replaces it
+	require.FileExists(t, path)
+
+	for i, traceID := range traceIDs {
+		foundTrace, err := ingester.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
+			TraceID: traceID,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, foundTrace.Trace)
+		trace.SortTrace(foundTrace.Trace)
+		require.True(t, proto.Equal(traces[i], foundTrace.Trace))
+	}
+
+	// restored traces are cut to the wal like any other
+	require.NoError(t, inst.CutCompleteTraces(0, true))
+	require.Equal(t, 0, inst.liveTraces())
+
+	// the snapshot is removed once there are no live traces
+	ingester.snapshotLiveTraces(inst)
+	require.NoFileExists(t, path)
+}
+
+func TestLiveTracesSnapshotClearedOnShutdown(t *testing.T) {
+	tmpDir := t.TempDir()
+	ingester, _, _ := defaultIngester(t, tmpDir)
+
+	ingester.snapshotLiveTraces(ingester.instances["test"])
+	require.FileExists(t, filepath.Join(ingester.store.WAL().LiveTracesPath(), "test"))
+
+	require.NoError(t, ingester.stopping(nil))
+
+	// the live traces were cut to the wal
+	_, err := os.Stat(ingester.store.WAL().LiveTracesPath())
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestLiveTracesSnapshotInvalid(t *testing.T) {
+	tmpDir := t.TempDir()
+	ingester := defaultIngesterModule(t, tmpDir)
+
+	dir := ingester.store.WAL().LiveTracesPath()
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test"), []byte("invalid"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "test"+liveTracesSnapshotTmp), []byte("partial"), 0o600))
+
+	// invalid snapshots are dropped and don't fail the startup
+	ingester = defaultIngesterModule(t, tmpDir)
+	require.Empty(t, ingester.instances)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
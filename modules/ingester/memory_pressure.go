@@ -0,0 +1,92 @@
+package ingester
+
+import (
+	"flag"
+	"runtime"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+var (
+	metricUnderMemoryPressure = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_under_memory_pressure",
+		Help:      "1 if the ingester is cutting blocks early because of memory pressure, 0 otherwise.",
+	})
+	metricMemoryPressureCuts = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "ingester_memory_pressure_cuts_total",
+		Help:      "The total number of times live traces and head blocks were cut because of memory pressure.",
+	})
+)
+
+// MemoryPressureConfig configures the memory usage past which the ingester cuts live traces and head blocks
+// without waiting for max_block_bytes or max_block_duration. This bounds the memory held by the ingester during
+// traffic spikes.
+type MemoryPressureConfig struct {
+	PressureConfig `yaml:",inline"`
+	// The ingester is under pressure when the Go heap in use is larger than this. 0 disables.
+	MaxHeapBytes uint64 `yaml:"max_heap_bytes"`
+	// The ingester is under pressure when the resident set size of the process is larger than this. 0 disables.
+	MaxRSSBytes uint64 `yaml:"max_rss_bytes"`
+}
+
+func (cfg *MemoryPressureConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, prefix+".enabled", false, "Cut live traces and head blocks early when the ingester is near its memory limits.")
+	cfg.CheckPeriod = 5 * time.Second
+	cfg.ReleaseRatio = 0.8
+}
+
+func (cfg *MemoryPressureConfig) Validate() error {
+	return cfg.validate("memory_pressure")
+}
+
+// checkMemoryPressure cuts all live traces and head blocks to the wal while the ingester is near its memory limits.
+// It returns whether the ingester is under memory pressure, which is passed back in on the next check.
+func (i *Ingester) checkMemoryPressure(alreadyUnder bool) bool {
+	pressure := i.underMemoryPressure(alreadyUnder)
+
+	if pressure != alreadyUnder {
+		level.Info(log.Logger).Log("msg", "changed ingester memory pressure state", "pressure", pressure)
+	}
+
+	if pressure {
+		metricUnderMemoryPressure.Set(1)
+		metricMemoryPressureCuts.Inc()
+		i.cutAllInstancesToWal()
+	} else {
+		metricUnderMemoryPressure.Set(0)
+	}
+
+	return pressure
+}
+
+// underMemoryPressure returns true if the ingester is past its memory limits. If it is already under pressure,
+// the limits are scaled down by the release ratio.
+func (i *Ingester) underMemoryPressure(alreadyUnder bool) bool {
+	cfg := i.cfg.MemoryPressure
+
+	if cfg.MaxHeapBytes > 0 {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+		if cfg.pastLimit(float64(stats.HeapInuse), float64(cfg.MaxHeapBytes), alreadyUnder) {
+			return true
+		}
+	}
+
+	if cfg.MaxRSSBytes > 0 {
+		rss, err := residentSetSize()
+		if err != nil {
+			level.Warn(log.Logger).Log("msg", "failed to get resident set size", "err", err)
+		} else if cfg.pastLimit(float64(rss), float64(cfg.MaxRSSBytes), alreadyUnder) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,28 @@
+//go:build linux
+
+package ingester
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// residentSetSize returns the resident set size of the process in bytes.
+func residentSetSize() (uint64, error) {
+	b, err := os.ReadFile("/proc/self/statm")
+	if err != nil {
+		return 0, err
+	}
+	// size resident shared text lib data dt, in pages
+	fields := bytes.Fields(b)
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("unexpected /proc/self/statm format: %q", b)
+	}
+	pages, err := strconv.ParseUint(string(fields[1]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected /proc/self/statm format: %w", err)
+	}
+	return pages * uint64(os.Getpagesize()), nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package ingester
+
+import "errors"
+
+// residentSetSize is only supported on linux.
+func residentSetSize() (uint64, error) {
+	return 0, errors.New("resident set size is only supported on linux")
+}
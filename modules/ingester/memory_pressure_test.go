@@ -0,0 +1,72 @@
+package ingester
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryPressureCutsBlocks(t *testing.T) {
+	ingester, _, _ := defaultIngester(t, t.TempDir())
+	inst := ingester.instances["test"]
+	require.Equal(t, 10, inst.liveTraces())
+
+	ingester.cfg.MemoryPressure = MemoryPressureConfig{
+		PressureConfig: PressureConfig{
+			Enabled:      true,
+			CheckPeriod:  time.Second,
+			ReleaseRatio: 0.5,
+		},
+		MaxHeapBytes: 1,
+	}
+	require.True(t, ingester.checkMemoryPressure(false))
+
+	// live traces and the head block were cut
+	require.Equal(t, 0, inst.liveTraces())
+	require.Equal(t, uint64(0), inst.headBlock.DataLength())
+	require.Len(t, inst.completingBlocks, 1)
+
+	ingester.cfg.MemoryPressure.MaxHeapBytes = 1 << 50
+	require.False(t, ingester.checkMemoryPressure(true))
+}
+
+func TestMemoryPressureUnderPressure(t *testing.T) {
+	ingester, _, _ := defaultIngester(t, t.TempDir())
+
+	ingester.cfg.MemoryPressure = MemoryPressureConfig{
+		PressureConfig: PressureConfig{
+			Enabled:      true,
+			CheckPeriod:  time.Second,
+			ReleaseRatio: 0.5,
+		},
+	}
+	require.False(t, ingester.underMemoryPressure(false))
+
+	rss, err := residentSetSize()
+	require.NoError(t, err)
+	require.NotZero(t, rss)
+
+	ingester.cfg.MemoryPressure.MaxRSSBytes = rss / 2
+	require.True(t, ingester.underMemoryPressure(false))
+
+	// still under pressure until the rss drops below the release ratio of the limit
+	ingester.cfg.MemoryPressure.MaxRSSBytes = rss * 3 / 2
+	require.False(t, ingester.underMemoryPressure(false))
+	require.True(t, ingester.underMemoryPressure(true))
+}
+
+func TestMemoryPressureConfigValidate(t *testing.T) {
+	cfg := MemoryPressureConfig{}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Enabled = true
+	require.EqualError(t, cfg.Validate(), "memory_pressure check_period must be greater than 0")
+
+	cfg.CheckPeriod = time.Second
+	cfg.ReleaseRatio = 0.8
+	require.NoError(t, cfg.Validate())
+
+	cfg.ReleaseRatio = 1.5
+	require.EqualError(t, cfg.Validate(), "memory_pressure release_ratio must be greater than 0 and at most 1")
+}
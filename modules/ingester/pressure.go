@@ -0,0 +1,36 @@
+package ingester
+
+import (
+	"fmt"
+	"time"
+)
+
+// PressureConfig holds the settings shared by the ingester pressure checks.
+type PressureConfig struct {
+	Enabled     bool          `yaml:"enabled"`
+	CheckPeriod time.Duration `yaml:"check_period"`
+	// Pressure is released once usage drops below this ratio of the limits. Avoids flapping around the limits.
+	ReleaseRatio float64 `yaml:"release_ratio"`
+}
+
+func (cfg *PressureConfig) validate(name string) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.CheckPeriod <= 0 {
+		return fmt.Errorf("%s check_period must be greater than 0", name)
+	}
+	if cfg.ReleaseRatio <= 0 || cfg.ReleaseRatio > 1 {
+		return fmt.Errorf("%s release_ratio must be greater than 0 and at most 1", name)
+	}
+	return nil
+}
+
+// pastLimit returns true if usage has reached limit. If the ingester is already under pressure, the limit is
+// scaled down by the release ratio.
+func (cfg *PressureConfig) pastLimit(usage, limit float64, alreadyUnder bool) bool {
+	if alreadyUnder {
+		limit *= cfg.ReleaseRatio
+	}
+	return usage >= limit
+}
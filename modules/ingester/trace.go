@@ -15,6 +15,9 @@ type liveTrace struct {
 	start      uint32
 	end        uint32
 	decoder    model.SegmentDecoder
+
+	// oldest time a distributor accepted spans of this trace. zero if unknown
+	received time.Time
 }
 
 func newTrace(traceID []byte) *liveTrace {
@@ -26,8 +29,9 @@ func newTrace(traceID []byte) *liveTrace {
 	}
 }
 
-func (t *liveTrace) Push(_ context.Context, instanceID string, trace []byte) error {
+func (t *liveTrace) Push(ctx context.Context, instanceID string, trace []byte) error {
 	t.lastAppend = time.Now()
+	t.received = oldest(t.received, receivedTime(ctx))
 
 	start, end, err := t.decoder.FastRange(trace)
 	if err != nil {
@@ -0,0 +1,186 @@
+package ingester
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/util/log"
+)
+
+var (
+	metricWALReplayProgress = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_wal_replay_progress_ratio",
+		Help:      "The share of the wal bytes replayed on startup.",
+	})
+	metricWALReplayETA = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempo",
+		Name:      "ingester_wal_replay_eta_seconds",
+		Help:      "The estimated time until the wal replay on startup is complete.",
+	})
+)
+
+// walReplayLogInterval is how often the progress of the wal replay is logged.
+const walReplayLogInterval = 10 * time.Second
+
+type replayState string
+
+const (
+	replayStatePending    replayState = "pending"
+	replayStateReplaying  replayState = "replaying_wal"
+	replayStateRediscover replayState = "rediscovering_local_blocks"
+	replayStateComplete   replayState = "complete"
+	replayStateFailed     replayState = "failed"
+)
+
+// walReplayStatus is the progress of the wal replay on startup.
+type walReplayStatus struct {
+	State         replayState `json:"state"`
+	TotalFiles    int         `json:"totalFiles"`
+	ReplayedFiles int         `json:"replayedFiles"`
+	TotalBytes    int64       `json:"totalBytes"`
+	ReplayedBytes int64       `json:"replayedBytes"`
+	Started       time.Time   `json:"started"`
+	LastProgress  time.Time   `json:"lastProgress"`
+	Finished      time.Time   `json:"finished"`
+	Error         string      `json:"error,omitempty"`
+
+	// PercentComplete and ETASeconds are estimated from the replayed bytes while the wal is replayed.
+	PercentComplete float64 `json:"percentComplete"`
+	ETASeconds      float64 `json:"etaSeconds"`
+}
+
+// walReplayProgress tracks the progress of the wal replay. It implements wal.ReplayProgress.
+type walReplayProgress struct {
+	mtx    sync.Mutex
+	status walReplayStatus
+
+	lastLog time.Time
+}
+
+func newWALReplayProgress() *walReplayProgress {
+	return &walReplayProgress{
+		status: walReplayStatus{State: replayStatePending},
+	}
+}
+
+func (p *walReplayProgress) start() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.status.State = replayStateReplaying
+	p.status.Started = time.Now()
+	p.status.LastProgress = p.status.Started
+}
+
+func (p *walReplayProgress) Total(files int, bytes int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.status.TotalFiles = files
+	p.status.TotalBytes = bytes
+}
+
+func (p *walReplayProgress) Replayed(bytes int64) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	now := time.Now()
+	p.status.ReplayedFiles++
+	p.status.ReplayedBytes += bytes
+	p.status.LastProgress = now
+	p.status.estimate(now)
+
+	metricWALReplayProgress.Set(p.status.PercentComplete / 100)
+	metricWALReplayETA.Set(p.status.ETASeconds)
+
+	if now.Sub(p.lastLog) >= walReplayLogInterval || p.status.ReplayedFiles == p.status.TotalFiles {
+		p.lastLog = now
+		level.Info(log.Logger).Log("msg", "wal replay progress",
+			"files", p.status.ReplayedFiles,
+			"total_files", p.status.TotalFiles,
+			"percent_complete", fmt.Sprintf("%.1f", p.status.PercentComplete),
+			"eta", (time.Duration(p.status.ETASeconds) * time.Second).String())
+	}
+}
+
+func (p *walReplayProgress) rediscover() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.status.State = replayStateRediscover
+	p.status.LastProgress = time.Now()
+	p.status.PercentComplete = 100
+	p.status.ETASeconds = 0
+
+	metricWALReplayProgress.Set(1)
+	metricWALReplayETA.Set(0)
+}
+
+func (p *walReplayProgress) finish(err error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.status.State = replayStateComplete
+	if err != nil {
+		p.status.State = replayStateFailed
+		p.status.Error = err.Error()
+	}
+	p.status.Finished = time.Now()
+}
+
+func (p *walReplayProgress) get() walReplayStatus {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	return p.status
+}
+
+// estimate updates the percent complete and the eta assuming the remaining bytes are replayed at the same rate as the
+// replayed ones.
+func (s *walReplayStatus) estimate(now time.Time) {
+	if s.TotalBytes <= 0 || s.ReplayedBytes <= 0 {
+		s.PercentComplete = 0
+		s.ETASeconds = 0
+		return
+	}
+
+	ratio := min(float64(s.ReplayedBytes)/float64(s.TotalBytes), 1)
+	elapsed := now.Sub(s.Started).Seconds()
+
+	s.PercentComplete = ratio * 100
+	s.ETASeconds = elapsed/ratio - elapsed
+}
+
+// String describes the progress for the readiness probe.
+func (s walReplayStatus) String() string {
+	switch s.State {
+	case replayStateReplaying:
+		return fmt.Sprintf("replaying wal: %d/%d files replayed (%.0f%%, eta %s), last progress %s ago", s.ReplayedFiles, s.TotalFiles, s.PercentComplete,
+			(time.Duration(s.ETASeconds) * time.Second).String(), time.Since(s.LastProgress).Round(time.Second))
+	case replayStateFailed:
+		return "wal replay failed: " + s.Error
+	default:
+		return string(s.State)
+	}
+}
+
+// WALReplayStatus describes the progress of the wal replay on startup.
+func (i *Ingester) WALReplayStatus() string {
+	return i.replayProgress.get().String()
+}
+
+// WALReplayHandler returns the progress of the wal replay on startup.
+func (i *Ingester) WALReplayHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(i.replayProgress.get()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -1,13 +1,17 @@
 package overrides
 
 import (
+	"errors"
 	"flag"
+	"fmt"
+	"regexp"
 	"time"
 
 	"github.com/prometheus/common/config"
 
 	"github.com/grafana/tempo/pkg/util/listtomap"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 
 	"github.com/prometheus/client_golang/prometheus"
 
@@ -32,6 +36,8 @@ const (
 
 	// ErrorPrefixLiveTracesExceeded is used to flag batches from the ingester that were rejected b/c they had too many traces
 	ErrorPrefixLiveTracesExceeded = "LIVE_TRACES_EXCEEDED"
+	// ErrorPrefixLiveTracesBytesExceeded is used to flag batches from the ingester that were rejected b/c the live traces exceeded the bytes limit
+	ErrorPrefixLiveTracesBytesExceeded = "LIVE_TRACES_BYTES_EXCEEDED"
 	// ErrorPrefixTraceTooLarge is used to flag batches from the ingester that were rejected b/c they exceeded the single trace limit
 	ErrorPrefixTraceTooLarge = "TRACE_TOO_LARGE"
 	// ErrorPrefixRateLimited is used to flag batches that have exceeded the spans/second of the tenant
@@ -40,6 +46,7 @@ const (
 	// metrics
 	MetricMaxLocalTracesPerUser           = "max_local_traces_per_user"
 	MetricMaxGlobalTracesPerUser          = "max_global_traces_per_user"
+	MetricMaxLiveTracesBytes              = "max_live_traces_bytes"
 	MetricMaxBytesPerTrace                = "max_bytes_per_trace"
 	MetricMaxBytesPerTagValuesQuery       = "max_bytes_per_tag_values_query"
 	MetricMaxBlocksPerTagValuesQuery      = "max_blocks_per_tag_values_query"
@@ -75,10 +82,68 @@ type IngestionOverrides struct {
 	// Ingester enforced limits.
 	MaxLocalTracesPerUser  int `yaml:"max_traces_per_user,omitempty" json:"max_traces_per_user,omitempty"`
 	MaxGlobalTracesPerUser int `yaml:"max_global_traces_per_user,omitempty" json:"max_global_traces_per_user,omitempty"`
+	// MaxLiveTracesBytes is the maximum number of bytes of live traces per user, per ingester.
+	MaxLiveTracesBytes uint64 `yaml:"max_live_traces_bytes,omitempty" json:"max_live_traces_bytes,omitempty"`
 
 	TenantShardSize int `yaml:"tenant_shard_size,omitempty" json:"tenant_shard_size,omitempty"`
 
 	MaxAttributeBytes int `yaml:"max_attribute_bytes,omitempty" json:"max_attribute_bytes,omitempty"`
+
+	// AttributeTransforms drop, hash or rename span and resource attributes in the distributor.
+	AttributeTransforms []AttributeTransform `yaml:"attribute_transforms,omitempty" json:"attribute_transforms,omitempty"`
+}
+
+const (
+	AttributeTransformDrop   = "drop"
+	AttributeTransformHash   = "hash"
+	AttributeTransformRename = "rename"
+
+	AttributeTransformScopeSpan     = "span"
+	AttributeTransformScopeResource = "resource"
+)
+
+// AttributeTransform applies the action to the attributes whose key is one of the keys or matches the regex.
+type AttributeTransform struct {
+	// Action is one of drop, hash or rename.
+	Action string `yaml:"action" json:"action"`
+	// Scope is span or resource. The attributes of both are transformed if it's empty.
+	Scope string   `yaml:"scope,omitempty" json:"scope,omitempty"`
+	Keys  []string `yaml:"keys,omitempty" json:"keys,omitempty"`
+	Regex string   `yaml:"regex,omitempty" json:"regex,omitempty"`
+	// NewKey is the key renamed attributes are moved to. Capture groups of the regex can be referenced with $1.
+	NewKey string `yaml:"new_key,omitempty" json:"new_key,omitempty"`
+}
+
+func (t AttributeTransform) Validate() error {
+	switch t.Action {
+	case AttributeTransformDrop, AttributeTransformHash:
+		if t.NewKey != "" {
+			return fmt.Errorf("new_key can't be set for action %s", t.Action)
+		}
+	case AttributeTransformRename:
+		if t.NewKey == "" {
+			return errors.New("new_key must be set for action rename")
+		}
+	default:
+		return fmt.Errorf("action %q is not valid, valid values: drop, hash, rename", t.Action)
+	}
+
+	switch t.Scope {
+	case "", AttributeTransformScopeSpan, AttributeTransformScopeResource:
+	default:
+		return fmt.Errorf("scope %q is not valid, valid values: span, resource", t.Scope)
+	}
+
+	if len(t.Keys) == 0 && t.Regex == "" {
+		return errors.New("keys or regex must be set")
+	}
+	if t.Regex != "" {
+		if _, err := regexp.Compile(t.Regex); err != nil {
+			return fmt.Errorf("regex is not valid: %w", err)
+		}
+	}
+
+	return nil
 }
 
 type ForwarderOverrides struct {
@@ -90,9 +155,12 @@ type ServiceGraphsOverrides struct {
 	HistogramBuckets                      []float64 `yaml:"histogram_buckets,omitempty" json:"histogram_buckets,omitempty"`
 	Dimensions                            []string  `yaml:"dimensions,omitempty" json:"dimensions,omitempty"`
 	PeerAttributes                        []string  `yaml:"peer_attributes,omitempty" json:"peer_attributes,omitempty"`
+	ClusterAttributes                     []string  `yaml:"cluster_attributes,omitempty" json:"cluster_attributes,omitempty"`
 	EnableClientServerPrefix              bool      `yaml:"enable_client_server_prefix,omitempty" json:"enable_client_server_prefix,omitempty"`
 	EnableMessagingSystemLatencyHistogram bool      `yaml:"enable_messaging_system_latency_histogram,omitempty" json:"enable_messaging_system_latency_histogram,omitempty"`
 	EnableVirtualNodeLabel                bool      `yaml:"enable_virtual_node_label,omitempty" json:"enable_virtual_node_label,omitempty"`
+	EnableExemplars                       bool      `yaml:"enable_exemplars,omitempty" json:"enable_exemplars,omitempty"`
+	EnableConnectionTypeDimensions        bool      `yaml:"enable_connection_type_dimensions,omitempty" json:"enable_connection_type_dimensions,omitempty"`
 }
 
 type SpanMetricsOverrides struct {
@@ -146,10 +214,18 @@ type MetricsGeneratorOverrides struct {
 	TraceIDLabelName         string              `yaml:"trace_id_label_name,omitempty" json:"trace_id_label_name,omitempty"`
 
 	RemoteWriteHeaders RemoteWriteHeaders `yaml:"remote_write_headers,omitempty" json:"remote_write_headers,omitempty"`
+	// RemoteWriteEndpoints are the names of the remote write endpoints the metrics of the tenant are sent to. All
+	// endpoints are used if empty.
+	RemoteWriteEndpoints []string `yaml:"remote_write_endpoints,omitempty" json:"remote_write_endpoints,omitempty"`
 
 	Forwarder      ForwarderOverrides `yaml:"forwarder,omitempty" json:"forwarder,omitempty"`
 	Processor      ProcessorOverrides `yaml:"processor,omitempty" json:"processor,omitempty"`
 	IngestionSlack time.Duration      `yaml:"ingestion_time_range_slack" json:"ingestion_time_range_slack"`
+
+	// ProcessingTimeBudget is the time the processors of the tenant may spend per second, e.g. 0.5 is half a core.
+	ProcessingTimeBudget float64 `yaml:"processing_time_budget,omitempty" json:"processing_time_budget,omitempty"`
+	// MaxProcessorMemoryBytes is the estimated memory the processors of the tenant may hold.
+	MaxProcessorMemoryBytes uint64 `yaml:"max_processor_memory_bytes,omitempty" json:"max_processor_memory_bytes,omitempty"`
 }
 
 type ReadOverrides struct {
@@ -161,7 +237,33 @@ type ReadOverrides struct {
 	MaxSearchDuration  model.Duration `yaml:"max_search_duration,omitempty" json:"max_search_duration,omitempty"`
 	MaxMetricsDuration model.Duration `yaml:"max_metrics_duration,omitempty" json:"max_metrics_duration,omitempty"`
 
+	// QueryFrontend enforced per endpoint timeouts. A value of 0 falls back to the frontend api_timeout.
+	TraceByIDTimeout  model.Duration `yaml:"trace_by_id_timeout,omitempty" json:"trace_by_id_timeout,omitempty"`
+	SearchTimeout     model.Duration `yaml:"search_timeout,omitempty" json:"search_timeout,omitempty"`
+	SearchTagsTimeout model.Duration `yaml:"search_tags_timeout,omitempty" json:"search_tags_timeout,omitempty"`
+	MetricsTimeout    model.Duration `yaml:"metrics_timeout,omitempty" json:"metrics_timeout,omitempty"`
+
 	UnsafeQueryHints bool `yaml:"unsafe_query_hints,omitempty" json:"unsafe_query_hints,omitempty"`
+
+	// QueryQueueWeight is the share of the tenant in the query-frontend queue relative to other tenants. 0 is a weight of 1.
+	QueryQueueWeight int `yaml:"query_queue_weight,omitempty" json:"query_queue_weight,omitempty"`
+
+	// SearchResultsCacheTTL is how long the query-frontend serves search responses from its results cache. 0 uses the
+	// query-frontend default.
+	SearchResultsCacheTTL model.Duration `yaml:"search_results_cache_ttl,omitempty" json:"search_results_cache_ttl,omitempty"`
+
+	// Search and metrics result limits. A value of 0 falls back to the query-frontend configuration.
+	MaxSpansPerSpanSet  int `yaml:"max_spans_per_span_set,omitempty" json:"max_spans_per_span_set,omitempty"`
+	MaxSpanSetsPerTrace int `yaml:"max_span_sets_per_trace,omitempty" json:"max_span_sets_per_trace,omitempty"`
+	MetricsMaxSeries    int `yaml:"metrics_max_series,omitempty" json:"metrics_max_series,omitempty"`
+
+	// Query-frontend sharding and concurrency. A value of 0 falls back to the query-frontend configuration.
+	SearchConcurrentJobs      int `yaml:"search_concurrent_jobs,omitempty" json:"search_concurrent_jobs,omitempty"`
+	SearchTargetBytesPerJob   int `yaml:"search_target_bytes_per_job,omitempty" json:"search_target_bytes_per_job,omitempty"`
+	MetricsConcurrentJobs     int `yaml:"metrics_concurrent_jobs,omitempty" json:"metrics_concurrent_jobs,omitempty"`
+	MetricsTargetBytesPerJob  int `yaml:"metrics_target_bytes_per_job,omitempty" json:"metrics_target_bytes_per_job,omitempty"`
+	TraceByIDQueryShards      int `yaml:"trace_by_id_query_shards,omitempty" json:"trace_by_id_query_shards,omitempty"`
+	TraceByIDConcurrentShards int `yaml:"trace_by_id_concurrent_shards,omitempty" json:"trace_by_id_concurrent_shards,omitempty"`
 }
 
 type CompactionOverrides struct {
@@ -169,6 +271,20 @@ type CompactionOverrides struct {
 	BlockRetention     model.Duration `yaml:"block_retention,omitempty" json:"block_retention,omitempty"`
 	CompactionWindow   model.Duration `yaml:"compaction_window,omitempty" json:"compaction_window,omitempty"`
 	CompactionDisabled bool           `yaml:"compaction_disabled,omitempty" json:"compaction_disabled,omitempty"`
+	// MaxConcurrentCompactions limits the number of compaction workers compacting the tenant at the same time
+	MaxConcurrentCompactions int `yaml:"max_concurrent_compactions,omitempty" json:"max_concurrent_compactions,omitempty"`
+	// ConfirmDeleteAll must be set to apply a block retention lower than an hour
+	ConfirmDeleteAll bool `yaml:"confirm_delete_all,omitempty" json:"confirm_delete_all,omitempty"`
+	// SpanCombineStrategy controls how duplicate spans with different contents are combined
+	SpanCombineStrategy common.SpanCombineStrategy `yaml:"span_combine_strategy,omitempty" json:"span_combine_strategy,omitempty"`
+	// RetentionRules keep the traces matching a TraceQL query longer than the block retention
+	RetentionRules []RetentionRule `yaml:"retention_rules,omitempty" json:"retention_rules,omitempty"`
+}
+
+// RetentionRule keeps the traces matching the TraceQL query for the retention.
+type RetentionRule struct {
+	Query     string         `yaml:"query" json:"query"`
+	Retention model.Duration `yaml:"retention" json:"retention"`
 }
 
 type GlobalOverrides struct {
@@ -180,6 +296,21 @@ type GlobalOverrides struct {
 type StorageOverrides struct {
 	// tempodb limits
 	DedicatedColumns backend.DedicatedColumns `yaml:"parquet_dedicated_columns" json:"parquet_dedicated_columns"`
+	// ProfileIDColumn adds a dedicated column for the profile IDs that profiling integrations set on spans.
+	ProfileIDColumn bool `yaml:"parquet_profile_id_column" json:"parquet_profile_id_column"`
+	// S3SSEKMSKeyID is the KMS key the tenant's objects are encrypted with in S3. It overrides the key of the s3
+	// backend config.
+	S3SSEKMSKeyID string `yaml:"s3_sse_kms_key_id,omitempty" json:"s3_sse_kms_key_id,omitempty"`
+	// GCSKMSKeyName is the Cloud KMS key the tenant's objects are encrypted with in GCS. It overrides the key of the gcs
+	// backend config.
+	GCSKMSKeyName string `yaml:"gcs_kms_key_name,omitempty" json:"gcs_kms_key_name,omitempty"`
+	// BlockVersion is the version new blocks of the tenant are created in. It overrides the version of the block config.
+	BlockVersion string `yaml:"block_version,omitempty" json:"block_version,omitempty"`
+	// RowGroupSizeBytes and RowGroupSizeSpans override the row group size of the block config for the tenant's blocks.
+	RowGroupSizeBytes int `yaml:"parquet_row_group_size_bytes,omitempty" json:"parquet_row_group_size_bytes,omitempty"`
+	RowGroupSizeSpans int `yaml:"parquet_row_group_size_spans,omitempty" json:"parquet_row_group_size_spans,omitempty"`
+	// RowGroupAutoTune scales the row group size of compacted blocks by the selectivity of the tenant's searches.
+	RowGroupAutoTune bool `yaml:"parquet_row_group_auto_tune,omitempty" json:"parquet_row_group_auto_tune,omitempty"`
 }
 
 type CostAttributionOverrides struct {
@@ -270,6 +401,7 @@ func (c *Config) RegisterFlagsAndApplyDefaults(f *flag.FlagSet) {
 	// Ingester limits
 	f.IntVar(&c.Defaults.Ingestion.MaxLocalTracesPerUser, "ingester.max-traces-per-user", 10e3, "Maximum number of active traces per user, per ingester. 0 to disable.")
 	f.IntVar(&c.Defaults.Ingestion.MaxGlobalTracesPerUser, "ingester.max-global-traces-per-user", 0, "Maximum number of active traces per user, across the cluster. 0 to disable.")
+	f.Uint64Var(&c.Defaults.Ingestion.MaxLiveTracesBytes, "ingester.max-live-traces-bytes", 0, "Maximum size in bytes of active traces per user, per ingester. 0 to disable.")
 	f.IntVar(&c.Defaults.Global.MaxBytesPerTrace, "ingester.max-bytes-per-trace", 50e5, "Maximum size of a trace in bytes.  0 to disable.")
 
 	// Querier limits
@@ -290,6 +422,7 @@ func (c *Config) Describe(ch chan<- *prometheus.Desc) {
 func (c *Config) Collect(ch chan<- prometheus.Metric) {
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.MaxLocalTracesPerUser), MetricMaxLocalTracesPerUser)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.MaxGlobalTracesPerUser), MetricMaxGlobalTracesPerUser)
+	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.MaxLiveTracesBytes), MetricMaxLiveTracesBytes)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.RateLimitBytes), MetricIngestionRateLimitBytes)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Ingestion.BurstSizeBytes), MetricIngestionBurstSizeBytes)
 	ch <- prometheus.MustNewConstMetric(metricLimitsDesc, prometheus.GaugeValue, float64(c.Defaults.Read.MaxBytesPerTagValuesQuery), MetricMaxBytesPerTagValuesQuery)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/grafana/tempo/pkg/util/listtomap"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 
 	"github.com/prometheus/common/model"
 
@@ -14,13 +15,15 @@ import (
 
 func (c *Overrides) toLegacy() LegacyOverrides {
 	return LegacyOverrides{
-		IngestionRateStrategy:      c.Ingestion.RateStrategy,
-		IngestionRateLimitBytes:    c.Ingestion.RateLimitBytes,
-		IngestionBurstSizeBytes:    c.Ingestion.BurstSizeBytes,
-		IngestionTenantShardSize:   c.Ingestion.TenantShardSize,
-		MaxLocalTracesPerUser:      c.Ingestion.MaxLocalTracesPerUser,
-		MaxGlobalTracesPerUser:     c.Ingestion.MaxGlobalTracesPerUser,
-		IngestionMaxAttributeBytes: c.Ingestion.MaxAttributeBytes,
+		IngestionRateStrategy:        c.Ingestion.RateStrategy,
+		IngestionRateLimitBytes:      c.Ingestion.RateLimitBytes,
+		IngestionBurstSizeBytes:      c.Ingestion.BurstSizeBytes,
+		IngestionTenantShardSize:     c.Ingestion.TenantShardSize,
+		MaxLocalTracesPerUser:        c.Ingestion.MaxLocalTracesPerUser,
+		MaxGlobalTracesPerUser:       c.Ingestion.MaxGlobalTracesPerUser,
+		MaxLiveTracesBytes:           c.Ingestion.MaxLiveTracesBytes,
+		IngestionMaxAttributeBytes:   c.Ingestion.MaxAttributeBytes,
+		IngestionAttributeTransforms: c.Ingestion.AttributeTransforms,
 
 		Forwarders: c.Forwarders,
 
@@ -32,14 +35,18 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 		MetricsGeneratorGenerateNativeHistograms:                                    c.MetricsGenerator.GenerateNativeHistograms,
 		MetricsGeneratorTraceIDLabelName:                                            c.MetricsGenerator.TraceIDLabelName,
 		MetricsGeneratorRemoteWriteHeaders:                                          c.MetricsGenerator.RemoteWriteHeaders,
+		MetricsGeneratorRemoteWriteEndpoints:                                        c.MetricsGenerator.RemoteWriteEndpoints,
 		MetricsGeneratorForwarderQueueSize:                                          c.MetricsGenerator.Forwarder.QueueSize,
 		MetricsGeneratorForwarderWorkers:                                            c.MetricsGenerator.Forwarder.Workers,
 		MetricsGeneratorProcessorServiceGraphsHistogramBuckets:                      c.MetricsGenerator.Processor.ServiceGraphs.HistogramBuckets,
 		MetricsGeneratorProcessorServiceGraphsDimensions:                            c.MetricsGenerator.Processor.ServiceGraphs.Dimensions,
 		MetricsGeneratorProcessorServiceGraphsPeerAttributes:                        c.MetricsGenerator.Processor.ServiceGraphs.PeerAttributes,
+		MetricsGeneratorProcessorServiceGraphsClusterAttributes:                     c.MetricsGenerator.Processor.ServiceGraphs.ClusterAttributes,
 		MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix:              c.MetricsGenerator.Processor.ServiceGraphs.EnableClientServerPrefix,
 		MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram: c.MetricsGenerator.Processor.ServiceGraphs.EnableMessagingSystemLatencyHistogram,
 		MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel:                c.MetricsGenerator.Processor.ServiceGraphs.EnableVirtualNodeLabel,
+		MetricsGeneratorProcessorServiceGraphsEnableExemplars:                       c.MetricsGenerator.Processor.ServiceGraphs.EnableExemplars,
+		MetricsGeneratorProcessorServiceGraphsEnableConnectionTypeDimensions:        c.MetricsGenerator.Processor.ServiceGraphs.EnableConnectionTypeDimensions,
 		MetricsGeneratorProcessorSpanMetricsHistogramBuckets:                        c.MetricsGenerator.Processor.SpanMetrics.HistogramBuckets,
 		MetricsGeneratorProcessorSpanMetricsDimensions:                              c.MetricsGenerator.Processor.SpanMetrics.Dimensions,
 		MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions:                     c.MetricsGenerator.Processor.SpanMetrics.IntrinsicDimensions,
@@ -54,18 +61,49 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 		MetricsGeneratorProcessorLocalBlocksTraceIdlePeriod:                         c.MetricsGenerator.Processor.LocalBlocks.TraceIdlePeriod,
 		MetricsGeneratorProcessorLocalBlocksCompleteBlockTimeout:                    c.MetricsGenerator.Processor.LocalBlocks.CompleteBlockTimeout,
 		MetricsGeneratorIngestionSlack:                                              c.MetricsGenerator.IngestionSlack,
+		MetricsGeneratorProcessingTimeBudget:                                        c.MetricsGenerator.ProcessingTimeBudget,
+		MetricsGeneratorMaxProcessorMemoryBytes:                                     c.MetricsGenerator.MaxProcessorMemoryBytes,
 
-		BlockRetention:   c.Compaction.BlockRetention,
-		CompactionWindow: c.Compaction.CompactionWindow,
+		BlockRetention:           c.Compaction.BlockRetention,
+		CompactionWindow:         c.Compaction.CompactionWindow,
+		ConfirmDeleteAll:         c.Compaction.ConfirmDeleteAll,
+		MaxConcurrentCompactions: c.Compaction.MaxConcurrentCompactions,
+
+		SpanCombineStrategy: c.Compaction.SpanCombineStrategy,
+		RetentionRules:      c.Compaction.RetentionRules,
 
 		MaxBytesPerTagValuesQuery:  c.Read.MaxBytesPerTagValuesQuery,
 		MaxBlocksPerTagValuesQuery: c.Read.MaxBlocksPerTagValuesQuery,
 		MaxSearchDuration:          c.Read.MaxSearchDuration,
+		MaxMetricsDuration:         c.Read.MaxMetricsDuration,
+		TraceByIDTimeout:           c.Read.TraceByIDTimeout,
+		SearchTimeout:              c.Read.SearchTimeout,
+		SearchTagsTimeout:          c.Read.SearchTagsTimeout,
+		MetricsTimeout:             c.Read.MetricsTimeout,
 		UnsafeQueryHints:           c.Read.UnsafeQueryHints,
+		QueryQueueWeight:           c.Read.QueryQueueWeight,
+		SearchResultsCacheTTL:      c.Read.SearchResultsCacheTTL,
+		MaxSpansPerSpanSet:         c.Read.MaxSpansPerSpanSet,
+		MaxSpanSetsPerTrace:        c.Read.MaxSpanSetsPerTrace,
+		MetricsMaxSeries:           c.Read.MetricsMaxSeries,
+		SearchConcurrentJobs:       c.Read.SearchConcurrentJobs,
+		SearchTargetBytesPerJob:    c.Read.SearchTargetBytesPerJob,
+		MetricsConcurrentJobs:      c.Read.MetricsConcurrentJobs,
+		MetricsTargetBytesPerJob:   c.Read.MetricsTargetBytesPerJob,
+		TraceByIDQueryShards:       c.Read.TraceByIDQueryShards,
+		TraceByIDConcurrentShards:  c.Read.TraceByIDConcurrentShards,
 
 		MaxBytesPerTrace: c.Global.MaxBytesPerTrace,
 
 		DedicatedColumns: c.Storage.DedicatedColumns,
+		ProfileIDColumn:  c.Storage.ProfileIDColumn,
+		S3SSEKMSKeyID:    c.Storage.S3SSEKMSKeyID,
+		GCSKMSKeyName:    c.Storage.GCSKMSKeyName,
+		BlockVersion:     c.Storage.BlockVersion,
+
+		RowGroupSizeBytes: c.Storage.RowGroupSizeBytes,
+		RowGroupSizeSpans: c.Storage.RowGroupSizeSpans,
+		RowGroupAutoTune:  c.Storage.RowGroupAutoTune,
 	}
 }
 
@@ -73,15 +111,17 @@ func (c *Overrides) toLegacy() LegacyOverrides {
 // limits via flags, or per-user limits via yaml config.
 type LegacyOverrides struct {
 	// Distributor enforced limits.
-	IngestionRateStrategy      string `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
-	IngestionRateLimitBytes    int    `yaml:"ingestion_rate_limit_bytes" json:"ingestion_rate_limit_bytes"`
-	IngestionBurstSizeBytes    int    `yaml:"ingestion_burst_size_bytes" json:"ingestion_burst_size_bytes"`
-	IngestionTenantShardSize   int    `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
-	IngestionMaxAttributeBytes int    `yaml:"ingestion_max_attribute_bytes" json:"ingestion_max_attribute_bytes"`
+	IngestionRateStrategy        string               `yaml:"ingestion_rate_strategy" json:"ingestion_rate_strategy"`
+	IngestionRateLimitBytes      int                  `yaml:"ingestion_rate_limit_bytes" json:"ingestion_rate_limit_bytes"`
+	IngestionBurstSizeBytes      int                  `yaml:"ingestion_burst_size_bytes" json:"ingestion_burst_size_bytes"`
+	IngestionTenantShardSize     int                  `yaml:"ingestion_tenant_shard_size" json:"ingestion_tenant_shard_size"`
+	IngestionMaxAttributeBytes   int                  `yaml:"ingestion_max_attribute_bytes" json:"ingestion_max_attribute_bytes"`
+	IngestionAttributeTransforms []AttributeTransform `yaml:"ingestion_attribute_transforms" json:"ingestion_attribute_transforms"`
 
 	// Ingester enforced limits.
-	MaxLocalTracesPerUser  int `yaml:"max_traces_per_user" json:"max_traces_per_user"`
-	MaxGlobalTracesPerUser int `yaml:"max_global_traces_per_user" json:"max_global_traces_per_user"`
+	MaxLocalTracesPerUser  int    `yaml:"max_traces_per_user" json:"max_traces_per_user"`
+	MaxGlobalTracesPerUser int    `yaml:"max_global_traces_per_user" json:"max_global_traces_per_user"`
+	MaxLiveTracesBytes     uint64 `yaml:"max_live_traces_bytes" json:"max_live_traces_bytes"`
 
 	// Forwarders
 	Forwarders []string `yaml:"forwarders" json:"forwarders"`
@@ -97,12 +137,16 @@ type LegacyOverrides struct {
 	MetricsGeneratorForwarderQueueSize                                          int                              `yaml:"metrics_generator_forwarder_queue_size" json:"metrics_generator_forwarder_queue_size"`
 	MetricsGeneratorForwarderWorkers                                            int                              `yaml:"metrics_generator_forwarder_workers" json:"metrics_generator_forwarder_workers"`
 	MetricsGeneratorRemoteWriteHeaders                                          RemoteWriteHeaders               `yaml:"metrics_generator_remote_write_headers,omitempty" json:"metrics_generator_remote_write_headers,omitempty"`
+	MetricsGeneratorRemoteWriteEndpoints                                        []string                         `yaml:"metrics_generator_remote_write_endpoints,omitempty" json:"metrics_generator_remote_write_endpoints,omitempty"`
 	MetricsGeneratorProcessorServiceGraphsHistogramBuckets                      []float64                        `yaml:"metrics_generator_processor_service_graphs_histogram_buckets" json:"metrics_generator_processor_service_graphs_histogram_buckets"`
 	MetricsGeneratorProcessorServiceGraphsDimensions                            []string                         `yaml:"metrics_generator_processor_service_graphs_dimensions" json:"metrics_generator_processor_service_graphs_dimensions"`
 	MetricsGeneratorProcessorServiceGraphsPeerAttributes                        []string                         `yaml:"metrics_generator_processor_service_graphs_peer_attributes" json:"metrics_generator_processor_service_graphs_peer_attributes"`
+	MetricsGeneratorProcessorServiceGraphsClusterAttributes                     []string                         `yaml:"metrics_generator_processor_service_graphs_cluster_attributes" json:"metrics_generator_processor_service_graphs_cluster_attributes"`
 	MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix              bool                             `yaml:"metrics_generator_processor_service_graphs_enable_client_server_prefix" json:"metrics_generator_processor_service_graphs_enable_client_server_prefix"`
 	MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram bool                             `yaml:"metrics_generator_processor_service_graphs_enable_messaging_system_latency_histogram" json:"metrics_generator_processor_service_graphs_enable_messaging_system_latency_histogram"`
 	MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel                bool                             `yaml:"metrics_generator_processor_service_graphs_enable_virtual_node_label" json:"metrics_generator_processor_service_graphs_enable_virtual_node_label"`
+	MetricsGeneratorProcessorServiceGraphsEnableExemplars                       bool                             `yaml:"metrics_generator_processor_service_graphs_enable_exemplars" json:"metrics_generator_processor_service_graphs_enable_exemplars"`
+	MetricsGeneratorProcessorServiceGraphsEnableConnectionTypeDimensions        bool                             `yaml:"metrics_generator_processor_service_graphs_enable_connection_type_dimensions" json:"metrics_generator_processor_service_graphs_enable_connection_type_dimensions"`
 	MetricsGeneratorProcessorSpanMetricsHistogramBuckets                        []float64                        `yaml:"metrics_generator_processor_span_metrics_histogram_buckets" json:"metrics_generator_processor_span_metrics_histogram_buckets"`
 	MetricsGeneratorProcessorSpanMetricsDimensions                              []string                         `yaml:"metrics_generator_processor_span_metrics_dimensions" json:"metrics_generator_processor_span_metrics_dimensions"`
 	MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions                     map[string]bool                  `yaml:"metrics_generator_processor_span_metrics_intrinsic_dimensions" json:"metrics_generator_processor_span_metrics_intrinsic_dimensions"`
@@ -117,11 +161,18 @@ type LegacyOverrides struct {
 	MetricsGeneratorProcessorLocalBlocksTraceIdlePeriod                         time.Duration                    `yaml:"metrics_generator_processor_local_blocks_trace_idle_period" json:"metrics_generator_processor_local_blocks_trace_idle_period"`
 	MetricsGeneratorProcessorLocalBlocksCompleteBlockTimeout                    time.Duration                    `yaml:"metrics_generator_processor_local_blocks_complete_block_timeout" json:"metrics_generator_processor_local_blocks_complete_block_timeout"`
 	MetricsGeneratorIngestionSlack                                              time.Duration                    `yaml:"metrics_generator_ingestion_time_range_slack" json:"metrics_generator_ingestion_time_range_slack"`
+	MetricsGeneratorProcessingTimeBudget                                        float64                          `yaml:"metrics_generator_processing_time_budget" json:"metrics_generator_processing_time_budget"`
+	MetricsGeneratorMaxProcessorMemoryBytes                                     uint64                           `yaml:"metrics_generator_max_processor_memory_bytes" json:"metrics_generator_max_processor_memory_bytes"`
 
 	// Compactor enforced limits.
-	BlockRetention     model.Duration `yaml:"block_retention" json:"block_retention"`
-	CompactionDisabled bool           `yaml:"compaction_disabled" json:"compaction_disabled"`
-	CompactionWindow   model.Duration `yaml:"compaction_window" json:"compaction_window"`
+	BlockRetention           model.Duration `yaml:"block_retention" json:"block_retention"`
+	CompactionDisabled       bool           `yaml:"compaction_disabled" json:"compaction_disabled"`
+	CompactionWindow         model.Duration `yaml:"compaction_window" json:"compaction_window"`
+	ConfirmDeleteAll         bool           `yaml:"confirm_delete_all" json:"confirm_delete_all"`
+	MaxConcurrentCompactions int            `yaml:"max_concurrent_compactions" json:"max_concurrent_compactions"`
+
+	SpanCombineStrategy common.SpanCombineStrategy `yaml:"span_combine_strategy" json:"span_combine_strategy"`
+	RetentionRules      []RetentionRule            `yaml:"retention_rules" json:"retention_rules"`
 
 	// Querier and Ingester enforced limits.
 	MaxBytesPerTagValuesQuery  int `yaml:"max_bytes_per_tag_values_query" json:"max_bytes_per_tag_values_query"`
@@ -130,7 +181,24 @@ type LegacyOverrides struct {
 	// QueryFrontend enforced limits
 	MaxSearchDuration  model.Duration `yaml:"max_search_duration" json:"max_search_duration"`
 	MaxMetricsDuration model.Duration `yaml:"max_metrics_duration" json:"max_metrics_duration"`
+	TraceByIDTimeout   model.Duration `yaml:"trace_by_id_timeout" json:"trace_by_id_timeout"`
+	SearchTimeout      model.Duration `yaml:"search_timeout" json:"search_timeout"`
+	SearchTagsTimeout  model.Duration `yaml:"search_tags_timeout" json:"search_tags_timeout"`
+	MetricsTimeout     model.Duration `yaml:"metrics_timeout" json:"metrics_timeout"`
 	UnsafeQueryHints   bool           `yaml:"unsafe_query_hints" json:"unsafe_query_hints"`
+	QueryQueueWeight   int            `yaml:"query_queue_weight,omitempty" json:"query_queue_weight,omitempty"`
+
+	SearchResultsCacheTTL model.Duration `yaml:"search_results_cache_ttl,omitempty" json:"search_results_cache_ttl,omitempty"`
+	MaxSpansPerSpanSet    int            `yaml:"max_spans_per_span_set,omitempty" json:"max_spans_per_span_set,omitempty"`
+	MaxSpanSetsPerTrace   int            `yaml:"max_span_sets_per_trace,omitempty" json:"max_span_sets_per_trace,omitempty"`
+	MetricsMaxSeries      int            `yaml:"metrics_max_series,omitempty" json:"metrics_max_series,omitempty"`
+
+	SearchConcurrentJobs      int `yaml:"search_concurrent_jobs,omitempty" json:"search_concurrent_jobs,omitempty"`
+	SearchTargetBytesPerJob   int `yaml:"search_target_bytes_per_job,omitempty" json:"search_target_bytes_per_job,omitempty"`
+	MetricsConcurrentJobs     int `yaml:"metrics_concurrent_jobs,omitempty" json:"metrics_concurrent_jobs,omitempty"`
+	MetricsTargetBytesPerJob  int `yaml:"metrics_target_bytes_per_job,omitempty" json:"metrics_target_bytes_per_job,omitempty"`
+	TraceByIDQueryShards      int `yaml:"trace_by_id_query_shards,omitempty" json:"trace_by_id_query_shards,omitempty"`
+	TraceByIDConcurrentShards int `yaml:"trace_by_id_concurrent_shards,omitempty" json:"trace_by_id_concurrent_shards,omitempty"`
 
 	// MaxBytesPerTrace is enforced in the Ingester, Compactor, Querier (Search). It
 	//  is not used when doing a trace by id lookup.
@@ -140,6 +208,14 @@ type LegacyOverrides struct {
 
 	// tempodb limits
 	DedicatedColumns backend.DedicatedColumns `yaml:"parquet_dedicated_columns" json:"parquet_dedicated_columns"`
+	ProfileIDColumn  bool                     `yaml:"parquet_profile_id_column" json:"parquet_profile_id_column"`
+	S3SSEKMSKeyID    string                   `yaml:"s3_sse_kms_key_id,omitempty" json:"s3_sse_kms_key_id,omitempty"`
+	GCSKMSKeyName    string                   `yaml:"gcs_kms_key_name,omitempty" json:"gcs_kms_key_name,omitempty"`
+	BlockVersion     string                   `yaml:"block_version,omitempty" json:"block_version,omitempty"`
+
+	RowGroupSizeBytes int  `yaml:"parquet_row_group_size_bytes,omitempty" json:"parquet_row_group_size_bytes,omitempty"`
+	RowGroupSizeSpans int  `yaml:"parquet_row_group_size_spans,omitempty" json:"parquet_row_group_size_spans,omitempty"`
+	RowGroupAutoTune  bool `yaml:"parquet_row_group_auto_tune,omitempty" json:"parquet_row_group_auto_tune,omitempty"`
 }
 
 func (l *LegacyOverrides) toNewLimits() Overrides {
@@ -150,20 +226,42 @@ func (l *LegacyOverrides) toNewLimits() Overrides {
 			BurstSizeBytes:         l.IngestionBurstSizeBytes,
 			MaxLocalTracesPerUser:  l.MaxLocalTracesPerUser,
 			MaxGlobalTracesPerUser: l.MaxGlobalTracesPerUser,
+			MaxLiveTracesBytes:     l.MaxLiveTracesBytes,
 			TenantShardSize:        l.IngestionTenantShardSize,
 			MaxAttributeBytes:      l.IngestionMaxAttributeBytes,
+			AttributeTransforms:    l.IngestionAttributeTransforms,
 		},
 		Read: ReadOverrides{
 			MaxBytesPerTagValuesQuery:  l.MaxBytesPerTagValuesQuery,
 			MaxBlocksPerTagValuesQuery: l.MaxBlocksPerTagValuesQuery,
 			MaxSearchDuration:          l.MaxSearchDuration,
 			MaxMetricsDuration:         l.MaxMetricsDuration,
+			TraceByIDTimeout:           l.TraceByIDTimeout,
+			SearchTimeout:              l.SearchTimeout,
+			SearchTagsTimeout:          l.SearchTagsTimeout,
+			MetricsTimeout:             l.MetricsTimeout,
 			UnsafeQueryHints:           l.UnsafeQueryHints,
+			QueryQueueWeight:           l.QueryQueueWeight,
+			SearchResultsCacheTTL:      l.SearchResultsCacheTTL,
+			MaxSpansPerSpanSet:         l.MaxSpansPerSpanSet,
+			MaxSpanSetsPerTrace:        l.MaxSpanSetsPerTrace,
+			MetricsMaxSeries:           l.MetricsMaxSeries,
+			SearchConcurrentJobs:       l.SearchConcurrentJobs,
+			SearchTargetBytesPerJob:    l.SearchTargetBytesPerJob,
+			MetricsConcurrentJobs:      l.MetricsConcurrentJobs,
+			MetricsTargetBytesPerJob:   l.MetricsTargetBytesPerJob,
+			TraceByIDQueryShards:       l.TraceByIDQueryShards,
+			TraceByIDConcurrentShards:  l.TraceByIDConcurrentShards,
 		},
 		Compaction: CompactionOverrides{
-			BlockRetention:     l.BlockRetention,
-			CompactionDisabled: l.CompactionDisabled,
-			CompactionWindow:   l.CompactionWindow,
+			BlockRetention:           l.BlockRetention,
+			CompactionDisabled:       l.CompactionDisabled,
+			CompactionWindow:         l.CompactionWindow,
+			ConfirmDeleteAll:         l.ConfirmDeleteAll,
+			MaxConcurrentCompactions: l.MaxConcurrentCompactions,
+
+			SpanCombineStrategy: l.SpanCombineStrategy,
+			RetentionRules:      l.RetentionRules,
 		},
 		MetricsGenerator: MetricsGeneratorOverrides{
 			RingSize:                 l.MetricsGeneratorRingSize,
@@ -173,7 +271,10 @@ func (l *LegacyOverrides) toNewLimits() Overrides {
 			DisableCollection:        l.MetricsGeneratorDisableCollection,
 			TraceIDLabelName:         l.MetricsGeneratorTraceIDLabelName,
 			IngestionSlack:           l.MetricsGeneratorIngestionSlack,
+			ProcessingTimeBudget:     l.MetricsGeneratorProcessingTimeBudget,
+			MaxProcessorMemoryBytes:  l.MetricsGeneratorMaxProcessorMemoryBytes,
 			RemoteWriteHeaders:       l.MetricsGeneratorRemoteWriteHeaders,
+			RemoteWriteEndpoints:     l.MetricsGeneratorRemoteWriteEndpoints,
 			GenerateNativeHistograms: l.MetricsGeneratorGenerateNativeHistograms,
 			Forwarder: ForwarderOverrides{
 				QueueSize: l.MetricsGeneratorForwarderQueueSize,
@@ -184,9 +285,12 @@ func (l *LegacyOverrides) toNewLimits() Overrides {
 					HistogramBuckets:                      l.MetricsGeneratorProcessorServiceGraphsHistogramBuckets,
 					Dimensions:                            l.MetricsGeneratorProcessorServiceGraphsDimensions,
 					PeerAttributes:                        l.MetricsGeneratorProcessorServiceGraphsPeerAttributes,
+					ClusterAttributes:                     l.MetricsGeneratorProcessorServiceGraphsClusterAttributes,
 					EnableClientServerPrefix:              l.MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix,
 					EnableMessagingSystemLatencyHistogram: l.MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram,
 					EnableVirtualNodeLabel:                l.MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel,
+					EnableExemplars:                       l.MetricsGeneratorProcessorServiceGraphsEnableExemplars,
+					EnableConnectionTypeDimensions:        l.MetricsGeneratorProcessorServiceGraphsEnableConnectionTypeDimensions,
 				},
 				SpanMetrics: SpanMetricsOverrides{
 					HistogramBuckets:             l.MetricsGeneratorProcessorSpanMetricsHistogramBuckets,
@@ -213,6 +317,14 @@ func (l *LegacyOverrides) toNewLimits() Overrides {
 		},
 		Storage: StorageOverrides{
 			DedicatedColumns: l.DedicatedColumns,
+			ProfileIDColumn:  l.ProfileIDColumn,
+			S3SSEKMSKeyID:    l.S3SSEKMSKeyID,
+			GCSKMSKeyName:    l.GCSKMSKeyName,
+			BlockVersion:     l.BlockVersion,
+
+			RowGroupSizeBytes: l.RowGroupSizeBytes,
+			RowGroupSizeSpans: l.RowGroupSizeSpans,
+			RowGroupAutoTune:  l.RowGroupAutoTune,
 		},
 		CostAttribution: CostAttributionOverrides{
 			Dimensions: l.CostAttribution.Dimensions,
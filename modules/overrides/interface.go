@@ -11,6 +11,7 @@ import (
 	"github.com/grafana/tempo/pkg/sharedconfig"
 	"github.com/grafana/tempo/pkg/spanfilter/config"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 type Service interface {
@@ -32,6 +33,7 @@ type Interface interface {
 	IngestionRateStrategy() string
 	MaxLocalTracesPerUser(userID string) int
 	MaxGlobalTracesPerUser(userID string) int
+	MaxLiveTracesBytes(userID string) uint64
 	MaxBytesPerTrace(userID string) int
 	MaxCompactionRange(userID string) time.Duration
 	Forwarders(userID string) []string
@@ -41,7 +43,10 @@ type Interface interface {
 	IngestionBurstSizeBytes(userID string) int
 	IngestionTenantShardSize(userID string) int
 	IngestionMaxAttributeBytes(userID string) int
+	IngestionAttributeTransforms(userID string) []AttributeTransform
 	MetricsGeneratorIngestionSlack(userID string) time.Duration
+	MetricsGeneratorProcessingTimeBudget(userID string) float64
+	MetricsGeneratorMaxProcessorMemoryBytes(userID string) uint64
 	MetricsGeneratorRingSize(userID string) int
 	MetricsGeneratorProcessors(userID string) map[string]struct{}
 	MetricsGeneratorMaxActiveSeries(userID string) uint32
@@ -50,11 +55,13 @@ type Interface interface {
 	MetricsGeneratorGenerateNativeHistograms(userID string) HistogramMethod
 	MetricsGenerationTraceIDLabelName(userID string) string
 	MetricsGeneratorRemoteWriteHeaders(userID string) map[string]string
+	MetricsGeneratorRemoteWriteEndpoints(userID string) []string
 	MetricsGeneratorForwarderQueueSize(userID string) int
 	MetricsGeneratorForwarderWorkers(userID string) int
 	MetricsGeneratorProcessorServiceGraphsHistogramBuckets(userID string) []float64
 	MetricsGeneratorProcessorServiceGraphsDimensions(userID string) []string
 	MetricsGeneratorProcessorServiceGraphsPeerAttributes(userID string) []string
+	MetricsGeneratorProcessorServiceGraphsClusterAttributes(userID string) []string
 	MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID string) []float64
 	MetricsGeneratorProcessorSpanMetricsDimensions(userID string) []string
 	MetricsGeneratorProcessorSpanMetricsIntrinsicDimensions(userID string) map[string]bool
@@ -70,13 +77,40 @@ type Interface interface {
 	MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix(userID string) bool
 	MetricsGeneratorProcessorServiceGraphsEnableMessagingSystemLatencyHistogram(userID string) bool
 	MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel(userID string) bool
+	MetricsGeneratorProcessorServiceGraphsEnableExemplars(userID string) bool
+	MetricsGeneratorProcessorServiceGraphsEnableConnectionTypeDimensions(userID string) bool
 	MetricsGeneratorProcessorSpanMetricsTargetInfoExcludedDimensions(userID string) []string
 	BlockRetention(userID string) time.Duration
 	CompactionDisabled(userID string) bool
+	MaxConcurrentCompactions(userID string) int
+	ConfirmDeleteAll(userID string) bool
+	SpanCombineStrategy(userID string) common.SpanCombineStrategy
+	RetentionRules(userID string) []RetentionRule
 	MaxSearchDuration(userID string) time.Duration
 	MaxMetricsDuration(userID string) time.Duration
+	TraceByIDTimeout(userID string) time.Duration
+	SearchTimeout(userID string) time.Duration
+	SearchTagsTimeout(userID string) time.Duration
+	MetricsTimeout(userID string) time.Duration
 	DedicatedColumns(userID string) backend.DedicatedColumns
+	S3SSEKMSKeyID(userID string) string
+	GCSKMSKeyName(userID string) string
+	BlockVersion(userID string) string
+	RowGroupSizeBytes(userID string) int
+	RowGroupSizeSpans(userID string) int
+	RowGroupAutoTune(userID string) bool
 	UnsafeQueryHints(userID string) bool
+	QueryQueueWeight(userID string) int
+	SearchResultsCacheTTL(userID string) time.Duration
+	MaxSpansPerSpanSet(userID string) int
+	MaxSpanSetsPerTrace(userID string) int
+	MetricsMaxSeries(userID string) int
+	SearchConcurrentJobs(userID string) int
+	SearchTargetBytesPerJob(userID string) int
+	MetricsConcurrentJobs(userID string) int
+	MetricsTargetBytesPerJob(userID string) int
+	TraceByIDQueryShards(userID string) int
+	TraceByIDConcurrentShards(userID string) int
 	CostAttributionMaxCardinality(userID string) uint64
 	CostAttributionDimensions(userID string) map[string]string
 
@@ -1,6 +1,8 @@
 package overrides
 
 import (
+	"slices"
+
 	"github.com/prometheus/client_golang/prometheus"
 	"go.opentelemetry.io/otel"
 )
@@ -33,3 +35,14 @@ func NewOverrides(cfg Config, validator Validator, registerer prometheus.Registe
 
 	return o, err
 }
+
+// HasTenantOverrides returns true if the tenant has runtime or user-configurable overrides.
+func HasTenantOverrides(o Interface, userID string) bool {
+	if o, ok := o.(*userConfigurableOverridesManager); ok {
+		if o.getTenantLimits(userID) != nil {
+			return true
+		}
+		return slices.Contains(o.Interface.GetTenantIDs(), userID)
+	}
+	return slices.Contains(o.GetTenantIDs(), userID)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/log"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 type Validator interface {
@@ -32,6 +33,8 @@ type Validator interface {
 // perTenantOverrides represents the overrides config file
 type perTenantOverrides struct {
 	TenantLimits map[string]*Overrides `yaml:"overrides"`
+	// Schedules replace the tenant limits during their time windows. The first active schedule wins.
+	Schedules []*ScheduledOverrides `yaml:"schedules,omitempty"`
 
 	ConfigType ConfigType `yaml:"-"` // ConfigType is the type of overrides config we are using: legacy or new
 }
@@ -60,6 +63,18 @@ func (o *perTenantOverrides) UnmarshalYAML(unmarshal func(interface{}) error) er
 
 // forUser returns limits for a given tenant, or nil if there are no tenant-specific limits.
 func (o *perTenantOverrides) forUser(userID string) *Overrides {
+	return o.forUserAt(userID, time.Now())
+}
+
+// forUserAt returns the limits for a given tenant at time t. The limits of an active schedule take precedence over
+// the tenant's regular limits.
+func (o *perTenantOverrides) forUserAt(userID string, t time.Time) *Overrides {
+	for _, s := range o.Schedules {
+		if l := s.TenantLimits[userID]; l != nil && s.Contains(t) {
+			return l
+		}
+	}
+
 	l, ok := o.TenantLimits[userID]
 	if !ok || l == nil {
 		return nil
@@ -70,25 +85,8 @@ func (o *perTenantOverrides) forUser(userID string) *Overrides {
 // loadPerTenantOverrides is of type runtimeconfig.Loader
 func loadPerTenantOverrides(validator Validator, typ ConfigType, expandEnv bool) func(r io.Reader) (interface{}, error) {
 	return func(r io.Reader) (interface{}, error) {
-		overrides := &perTenantOverrides{}
-
-		if expandEnv {
-			rr := r.(*bytes.Reader)
-			b, err := io.ReadAll(rr)
-			if err != nil {
-				return nil, err
-			}
-
-			s, err := envsubst.EvalEnv(string(b))
-			if err != nil {
-				return nil, fmt.Errorf("failed to expand env vars: %w", err)
-			}
-			r = bytes.NewReader([]byte(s))
-		}
-
-		decoder := yaml.NewDecoder(r)
-		decoder.SetStrict(true)
-		if err := decoder.Decode(&overrides); err != nil {
+		overrides, err := parsePerTenantOverrides(r, expandEnv)
+		if err != nil {
 			return nil, err
 		}
 
@@ -109,12 +107,63 @@ func loadPerTenantOverrides(validator Validator, typ ConfigType, expandEnv bool)
 					return nil, fmt.Errorf("validating overrides for %s failed: %w", tenant, err)
 				}
 			}
+			for _, s := range overrides.Schedules {
+				for tenant, tenantOverrides := range s.TenantLimits {
+					if tenantOverrides == nil {
+						continue
+					}
+					err := validator.Validate(tenantOverrides)
+					if err != nil {
+						return nil, fmt.Errorf("validating overrides for %s in schedule %s failed: %w", tenant, s.Name, err)
+					}
+				}
+			}
 		}
 
 		return overrides, nil
 	}
 }
 
+// parsePerTenantOverrides decodes an overrides file, rejecting unknown fields.
+func parsePerTenantOverrides(r io.Reader, expandEnv bool) (*perTenantOverrides, error) {
+	overrides := &perTenantOverrides{}
+
+	if expandEnv {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := envsubst.EvalEnv(string(b))
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand env vars: %w", err)
+		}
+		r = bytes.NewReader([]byte(s))
+	}
+
+	decoder := yaml.NewDecoder(r)
+	decoder.SetStrict(true)
+	if err := decoder.Decode(&overrides); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]struct{}, len(overrides.Schedules))
+	for i, s := range overrides.Schedules {
+		if s == nil {
+			return nil, fmt.Errorf("schedule %d must not be empty", i)
+		}
+		if err := s.parse(); err != nil {
+			return nil, fmt.Errorf("invalid schedule %d: %w", i, err)
+		}
+		if _, ok := names[s.Name]; ok {
+			return nil, fmt.Errorf("duplicate schedule %s", s.Name)
+		}
+		names[s.Name] = struct{}{}
+	}
+
+	return overrides, nil
+}
+
 // runtimeConfigOverridesManager periodically fetch a set of per-user overrides, and provides convenience
 // functions for fetching the correct value.
 type runtimeConfigOverridesManager struct {
@@ -123,6 +172,10 @@ type runtimeConfigOverridesManager struct {
 	defaultLimits    *Overrides
 	runtimeConfigMgr *runtimeconfig.Manager
 
+	// userOverrides returns the overrides of a tenant with its user-configurable overrides merged over the given
+	// runtime overrides, or nil if it has none. Set by the user-configurable overrides.
+	userOverrides func(userID string, runtime *Overrides) *Overrides
+
 	// Manager for subservices
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
@@ -283,7 +336,7 @@ func (o *runtimeConfigOverridesManager) GetTenantIDs() []string {
 }
 
 func (o *runtimeConfigOverridesManager) GetRuntimeOverridesFor(userID string) *Overrides {
-	return o.getOverridesForUser(userID)
+	return o.runtimeOverridesForUser(userID)
 }
 
 // IngestionRateStrategy returns whether the ingestion rate limit should be individually applied
@@ -330,6 +383,17 @@ func (o *runtimeConfigOverridesManager) IngestionMaxAttributeBytes(userID string
 	return o.getOverridesForUser(userID).Ingestion.MaxAttributeBytes
 }
 
+// IngestionAttributeTransforms are the transforms the distributor applies to the attributes of this tenant.
+func (o *runtimeConfigOverridesManager) IngestionAttributeTransforms(userID string) []AttributeTransform {
+	return o.getOverridesForUser(userID).Ingestion.AttributeTransforms
+}
+
+// MaxLiveTracesBytes returns the maximum size in bytes of live traces a user is allowed to store
+// in a single ingester.
+func (o *runtimeConfigOverridesManager) MaxLiveTracesBytes(userID string) uint64 {
+	return o.getOverridesForUser(userID).Ingestion.MaxLiveTracesBytes
+}
+
 // MaxBytesPerTrace returns the maximum size of a single trace in bytes allowed for a user.
 func (o *runtimeConfigOverridesManager) MaxBytesPerTrace(userID string) int {
 	return o.getOverridesForUser(userID).Global.MaxBytesPerTrace
@@ -354,6 +418,67 @@ func (o *runtimeConfigOverridesManager) UnsafeQueryHints(userID string) bool {
 	return o.getOverridesForUser(userID).Read.UnsafeQueryHints
 }
 
+// QueryQueueWeight is the share of the tenant in the query-frontend queue. The weight is at least 1.
+func (o *runtimeConfigOverridesManager) QueryQueueWeight(userID string) int {
+	return max(1, o.getOverridesForUser(userID).Read.QueryQueueWeight)
+}
+
+// SearchResultsCacheTTL is how long the query-frontend caches the search responses of this tenant.
+func (o *runtimeConfigOverridesManager) SearchResultsCacheTTL(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).Read.SearchResultsCacheTTL)
+}
+
+// MaxSpansPerSpanSet is the maximum spans per spanset a search of this tenant can request.
+func (o *runtimeConfigOverridesManager) MaxSpansPerSpanSet(userID string) int {
+	return o.getOverridesForUser(userID).Read.MaxSpansPerSpanSet
+}
+
+// MaxSpanSetsPerTrace is the maximum number of spansets returned for each trace of a search of this tenant.
+func (o *runtimeConfigOverridesManager) MaxSpanSetsPerTrace(userID string) int {
+	return o.getOverridesForUser(userID).Read.MaxSpanSetsPerTrace
+}
+
+// SearchConcurrentJobs is the number of jobs of a search or tag search of this tenant that the query-frontend runs
+// concurrently. 0 uses the query-frontend configuration.
+func (o *runtimeConfigOverridesManager) SearchConcurrentJobs(userID string) int {
+	return o.getOverridesForUser(userID).Read.SearchConcurrentJobs
+}
+
+// SearchTargetBytesPerJob is the number of block bytes each job of a search or tag search of this tenant searches.
+// 0 uses the query-frontend configuration.
+func (o *runtimeConfigOverridesManager) SearchTargetBytesPerJob(userID string) int {
+	return o.getOverridesForUser(userID).Read.SearchTargetBytesPerJob
+}
+
+// MetricsConcurrentJobs is the number of jobs of a metrics query of this tenant that the query-frontend runs
+// concurrently. 0 uses the query-frontend configuration.
+func (o *runtimeConfigOverridesManager) MetricsConcurrentJobs(userID string) int {
+	return o.getOverridesForUser(userID).Read.MetricsConcurrentJobs
+}
+
+// MetricsTargetBytesPerJob is the number of block bytes each job of a metrics query of this tenant reads. 0 uses the
+// query-frontend configuration.
+func (o *runtimeConfigOverridesManager) MetricsTargetBytesPerJob(userID string) int {
+	return o.getOverridesForUser(userID).Read.MetricsTargetBytesPerJob
+}
+
+// TraceByIDQueryShards is the number of shards a trace by id lookup of this tenant is split into. 0 uses the
+// query-frontend configuration.
+func (o *runtimeConfigOverridesManager) TraceByIDQueryShards(userID string) int {
+	return o.getOverridesForUser(userID).Read.TraceByIDQueryShards
+}
+
+// TraceByIDConcurrentShards is the number of shards of a trace by id lookup of this tenant that run concurrently. 0
+// uses the query-frontend configuration.
+func (o *runtimeConfigOverridesManager) TraceByIDConcurrentShards(userID string) int {
+	return o.getOverridesForUser(userID).Read.TraceByIDConcurrentShards
+}
+
+// MetricsMaxSeries is the maximum number of series a metrics query of this tenant can return.
+func (o *runtimeConfigOverridesManager) MetricsMaxSeries(userID string) int {
+	return o.getOverridesForUser(userID).Read.MetricsMaxSeries
+}
+
 func (o *runtimeConfigOverridesManager) CostAttributionMaxCardinality(userID string) uint64 {
 	return o.getOverridesForUser(userID).CostAttribution.MaxCardinality
 }
@@ -371,17 +496,52 @@ func (o *runtimeConfigOverridesManager) MaxMetricsDuration(userID string) time.D
 	return time.Duration(o.getOverridesForUser(userID).Read.MaxMetricsDuration)
 }
 
+// TraceByIDTimeout is the query-frontend timeout for trace by ID requests of this tenant.
+func (o *runtimeConfigOverridesManager) TraceByIDTimeout(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).Read.TraceByIDTimeout)
+}
+
+// SearchTimeout is the query-frontend timeout for TraceQL search requests of this tenant.
+func (o *runtimeConfigOverridesManager) SearchTimeout(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).Read.SearchTimeout)
+}
+
+// SearchTagsTimeout is the query-frontend timeout for tag name and tag value requests of this tenant.
+func (o *runtimeConfigOverridesManager) SearchTagsTimeout(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).Read.SearchTagsTimeout)
+}
+
+// MetricsTimeout is the query-frontend timeout for metrics requests of this tenant.
+func (o *runtimeConfigOverridesManager) MetricsTimeout(userID string) time.Duration {
+	return time.Duration(o.getOverridesForUser(userID).Read.MetricsTimeout)
+}
+
 // MetricsGeneratorIngestionSlack is the max amount of time passed since a span's end time
 // for the span to be considered in metrics generation
 func (o *runtimeConfigOverridesManager) MetricsGeneratorIngestionSlack(userID string) time.Duration {
 	return o.getOverridesForUser(userID).MetricsGenerator.IngestionSlack
 }
 
+// MetricsGeneratorProcessingTimeBudget is the time the processors of the tenant may spend per second. 0 is no limit.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessingTimeBudget(userID string) float64 {
+	return o.getOverridesForUser(userID).MetricsGenerator.ProcessingTimeBudget
+}
+
+// MetricsGeneratorMaxProcessorMemoryBytes is the estimated memory the processors of the tenant may hold. 0 is no limit.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorMaxProcessorMemoryBytes(userID string) uint64 {
+	return o.getOverridesForUser(userID).MetricsGenerator.MaxProcessorMemoryBytes
+}
+
 // MetricsGeneratorRemoteWriteHeaders returns the custom remote write headers for this tenant.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorRemoteWriteHeaders(userID string) map[string]string {
 	return o.getOverridesForUser(userID).MetricsGenerator.RemoteWriteHeaders.toStringStringMap()
 }
 
+// MetricsGeneratorRemoteWriteEndpoints returns the names of the remote write endpoints for this tenant.
+func (o *runtimeConfigOverridesManager) MetricsGeneratorRemoteWriteEndpoints(userID string) []string {
+	return o.getOverridesForUser(userID).MetricsGenerator.RemoteWriteEndpoints
+}
+
 // MetricsGeneratorRingSize is the desired size of the metrics-generator ring for this tenant.
 // Using shuffle sharding, a tenant can use a smaller ring than the entire ring.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorRingSize(userID string) int {
@@ -448,6 +608,12 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsPe
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.PeerAttributes
 }
 
+// MetricsGeneratorProcessorServiceGraphsClusterAttributes controls the resource attributes that are used to add
+// the cluster of the client and server to the edges
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsClusterAttributes(userID string) []string {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.ClusterAttributes
+}
+
 // MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix enables "client" and "server" prefix
 func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsEnableClientServerPrefix(userID string) bool {
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.EnableClientServerPrefix
@@ -463,6 +629,17 @@ func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsEn
 	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.EnableVirtualNodeLabel
 }
 
+// MetricsGeneratorProcessorServiceGraphsEnableExemplars attaches trace IDs as exemplars to the latency histograms
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsEnableExemplars(userID string) bool {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.EnableExemplars
+}
+
+// MetricsGeneratorProcessorServiceGraphsEnableConnectionTypeDimensions derives the connection type and system of the
+// edges from the attributes of their spans
+func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorServiceGraphsEnableConnectionTypeDimensions(userID string) bool {
+	return o.getOverridesForUser(userID).MetricsGenerator.Processor.ServiceGraphs.EnableConnectionTypeDimensions
+}
+
 // MetricsGeneratorProcessorSpanMetricsHistogramBuckets controls the histogram buckets to be used
 // by the span metrics processor.
 func (o *runtimeConfigOverridesManager) MetricsGeneratorProcessorSpanMetricsHistogramBuckets(userID string) []float64 {
@@ -529,16 +706,83 @@ func (o *runtimeConfigOverridesManager) BlockRetention(userID string) time.Durat
 	return time.Duration(o.getOverridesForUser(userID).Compaction.BlockRetention)
 }
 
+// ConfirmDeleteAll allows a block retention lower than an hour for this tenant.
+func (o *runtimeConfigOverridesManager) ConfirmDeleteAll(userID string) bool {
+	return o.getOverridesForUser(userID).Compaction.ConfirmDeleteAll
+}
+
+// SpanCombineStrategy is the strategy used to combine duplicate spans with different contents for this tenant.
+func (o *runtimeConfigOverridesManager) SpanCombineStrategy(userID string) common.SpanCombineStrategy {
+	return o.getOverridesForUser(userID).Compaction.SpanCombineStrategy
+}
+
+// RetentionRules are the rules that keep matching traces longer than the block retention for this tenant.
+func (o *runtimeConfigOverridesManager) RetentionRules(userID string) []RetentionRule {
+	return o.getOverridesForUser(userID).Compaction.RetentionRules
+}
+
 // CompactionDisabled will not compact tenants which have this enabled.
 func (o *runtimeConfigOverridesManager) CompactionDisabled(userID string) bool {
 	return o.getOverridesForUser(userID).Compaction.CompactionDisabled
 }
 
+// MaxConcurrentCompactions is the maximum number of compaction workers compacting this tenant at the same time.
+func (o *runtimeConfigOverridesManager) MaxConcurrentCompactions(userID string) int {
+	return o.getOverridesForUser(userID).Compaction.MaxConcurrentCompactions
+}
+
+// DedicatedColumns returns the dedicated attribute columns of the tenant, including the profile ID column if it's
+// enabled.
 func (o *runtimeConfigOverridesManager) DedicatedColumns(userID string) backend.DedicatedColumns {
-	return o.getOverridesForUser(userID).Storage.DedicatedColumns
+	storage := o.getOverridesForUser(userID).Storage
+	if storage.ProfileIDColumn {
+		return storage.DedicatedColumns.WithProfileID()
+	}
+	return storage.DedicatedColumns
+}
+
+// S3SSEKMSKeyID is the KMS key the tenant's objects are encrypted with in S3. Empty uses the key of the backend config.
+func (o *runtimeConfigOverridesManager) S3SSEKMSKeyID(userID string) string {
+	return o.getOverridesForUser(userID).Storage.S3SSEKMSKeyID
+}
+
+// GCSKMSKeyName is the Cloud KMS key the tenant's objects are encrypted with in GCS. Empty uses the key of the backend
+// config.
+func (o *runtimeConfigOverridesManager) GCSKMSKeyName(userID string) string {
+	return o.getOverridesForUser(userID).Storage.GCSKMSKeyName
+}
+
+// BlockVersion is the version new blocks of the tenant are created in. Empty uses the version of the block config.
+func (o *runtimeConfigOverridesManager) BlockVersion(userID string) string {
+	return o.getOverridesForUser(userID).Storage.BlockVersion
+}
+
+// RowGroupSizeBytes is the row group size in bytes of the tenant's blocks. 0 uses the size of the block config.
+func (o *runtimeConfigOverridesManager) RowGroupSizeBytes(userID string) int {
+	return o.getOverridesForUser(userID).Storage.RowGroupSizeBytes
+}
+
+// RowGroupSizeSpans is the row group size in spans of the tenant's blocks. 0 uses the size of the block config.
+func (o *runtimeConfigOverridesManager) RowGroupSizeSpans(userID string) int {
+	return o.getOverridesForUser(userID).Storage.RowGroupSizeSpans
+}
+
+// RowGroupAutoTune returns true if the row group size of the tenant's compacted blocks is tuned to its searches.
+func (o *runtimeConfigOverridesManager) RowGroupAutoTune(userID string) bool {
+	return o.getOverridesForUser(userID).Storage.RowGroupAutoTune
 }
 
 func (o *runtimeConfigOverridesManager) getOverridesForUser(userID string) *Overrides {
+	l := o.runtimeOverridesForUser(userID)
+	if o.userOverrides != nil {
+		if u := o.userOverrides(userID, l); u != nil {
+			return u
+		}
+	}
+	return l
+}
+
+func (o *runtimeConfigOverridesManager) runtimeOverridesForUser(userID string) *Overrides {
 	if tenantOverrides := o.tenantOverrides(); tenantOverrides != nil {
 		l := tenantOverrides.forUser(userID)
 		if l != nil {
@@ -564,9 +808,14 @@ func (o *runtimeConfigOverridesManager) Collect(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	for tenant, limits := range overrides.TenantLimits {
+	for tenant := range overrides.TenantLimits {
+		limits := overrides.forUser(tenant)
+		if limits == nil {
+			continue
+		}
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.MaxLocalTracesPerUser), MetricMaxLocalTracesPerUser, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.MaxGlobalTracesPerUser), MetricMaxGlobalTracesPerUser, tenant)
+		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.MaxLiveTracesBytes), MetricMaxLiveTracesBytes, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.RateLimitBytes), MetricIngestionRateLimitBytes, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Ingestion.BurstSizeBytes), MetricIngestionBurstSizeBytes, tenant)
 		ch <- prometheus.MustNewConstMetric(metricOverridesLimitsDesc, prometheus.GaugeValue, float64(limits.Global.MaxBytesPerTrace), MetricMaxBytesPerTrace, tenant)
@@ -514,6 +514,33 @@ overrides:
 				"user2": {},
 			},
 		},
+		{
+			name: "profile id column",
+			defaultLimits: Overrides{
+				Storage: StorageOverrides{
+					DedicatedColumns: backend.DedicatedColumns{
+						{Scope: "resource", Name: "namespace", Type: "string"},
+					},
+				},
+			},
+			perTenantOverrides: `
+overrides:
+  user2:
+    storage:
+      parquet_profile_id_column: true
+      parquet_dedicated_columns:
+        - scope: "span"
+          name: "http.status"
+          type: "int"
+`,
+			expectedDedicatedColumns: map[string]backend.DedicatedColumns{
+				"user1": {{Scope: "resource", Name: "namespace", Type: "string"}},
+				"user2": {
+					{Scope: "span", Name: "http.status", Type: "int"},
+					{Scope: "span", Name: "pyroscope.profile.id", Type: "string"},
+				},
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,24 @@
+package overrides
+
+import (
+	"errors"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+// ScheduledOverrides replace the overrides of tenants during a recurring time window, e.g. to raise the ingestion
+// limits during a load test. The tenants' regular overrides apply again once the window ends.
+type ScheduledOverrides struct {
+	Name            string `yaml:"name"`
+	util.TimeWindow `yaml:",inline"`
+
+	TenantLimits map[string]*Overrides `yaml:"overrides"`
+}
+
+// parse validates the schedule and prepares it to be evaluated by Contains.
+func (s *ScheduledOverrides) parse() error {
+	if s.Name == "" {
+		return errors.New("name must not be empty")
+	}
+	return s.TimeWindow.Parse()
+}
@@ -0,0 +1,112 @@
+package overrides
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerTenantOverrides_schedules(t *testing.T) {
+	overrides, err := parsePerTenantOverrides(strings.NewReader(`
+overrides:
+  user1:
+    ingestion:
+      rate_limit_bytes: 100
+  user2:
+    ingestion:
+      rate_limit_bytes: 200
+schedules:
+  - name: load-test
+    days: [saturday]
+    start: "22:00"
+    end: "02:00"
+    overrides:
+      user1:
+        ingestion:
+          rate_limit_bytes: 1000
+      user3:
+        ingestion:
+          rate_limit_bytes: 3000
+`), false)
+	require.NoError(t, err)
+
+	rateLimit := func(userID string, t time.Time) int {
+		l := overrides.forUserAt(userID, t)
+		if l == nil {
+			return 0
+		}
+		return l.Ingestion.RateLimitBytes
+	}
+
+	inactive := time.Date(2024, 1, 6, 21, 0, 0, 0, time.UTC)
+	active := time.Date(2024, 1, 7, 1, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, 100, rateLimit("user1", inactive))
+	assert.Equal(t, 1000, rateLimit("user1", active))
+	assert.Equal(t, 200, rateLimit("user2", active))
+	assert.Equal(t, 0, rateLimit("user3", inactive))
+	assert.Equal(t, 3000, rateLimit("user3", active))
+}
+
+func TestPerTenantOverrides_invalidSchedules(t *testing.T) {
+	tests := []struct {
+		name      string
+		schedules string
+		expErr    string
+	}{
+		{
+			name: "no name",
+			schedules: `
+  - start: "08:00"
+    end: "09:00"`,
+			expErr: "invalid schedule 0: name must not be empty",
+		},
+		{
+			name: "invalid start",
+			schedules: `
+  - name: s
+    start: "8am"
+    end: "09:00"`,
+			expErr: "invalid schedule 0: invalid start: \"8am\" is not in the format hh:mm",
+		},
+		{
+			name: "invalid day",
+			schedules: `
+  - name: s
+    days: [someday]
+    start: "08:00"
+    end: "09:00"`,
+			expErr: "invalid schedule 0: invalid day \"someday\"",
+		},
+		{
+			name: "invalid timezone",
+			schedules: `
+  - name: s
+    start: "08:00"
+    end: "09:00"
+    timezone: Nowhere/Nothing`,
+			expErr: "invalid schedule 0: invalid timezone: unknown time zone Nowhere/Nothing",
+		},
+		{
+			name: "duplicate name",
+			schedules: `
+  - name: s
+    start: "08:00"
+    end: "09:00"
+  - name: s
+    start: "10:00"
+    end: "11:00"`,
+			expErr: "duplicate schedule s",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parsePerTenantOverrides(strings.NewReader("overrides: {}\nschedules:"+tc.schedules), false)
+			assert.EqualError(t, err, tc.expErr)
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package overrides
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
@@ -9,9 +11,11 @@ import (
 	"maps"
 	"net/http"
 	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/services"
@@ -41,6 +45,10 @@ type UserConfigurableOverridesConfig struct {
 
 	Client userconfigurableoverrides.Config   `yaml:"client"`
 	API    UserConfigurableOverridesAPIConfig `yaml:"api"`
+
+	// AllowedOverrides are the runtime overrides tenants can set in the overrides section, for example
+	// "ingestion.rate_limit_bytes". A field allows all of its nested fields. Empty disables the overrides section.
+	AllowedOverrides []string `yaml:"allowed_overrides"`
 }
 
 type UserConfigurableOverridesAPIConfig struct {
@@ -63,6 +71,14 @@ func (c UserConfigurableOverridesAPIConfig) RegisterFlagsAndApplyDefaults(*flag.
 
 type tenantLimits map[string]*userconfigurableoverrides.Limits
 
+// mergedOverrides are the overrides of a tenant with its user-configurable overrides merged over its runtime overrides.
+// They are valid as long as neither of them is reloaded.
+type mergedOverrides struct {
+	runtime  *Overrides
+	limits   *userconfigurableoverrides.Limits
+	combined *Overrides
+}
+
 // userConfigurableOverridesManager can store user-configurable overrides on a bucket.
 type userConfigurableOverridesManager struct {
 	services.Service
@@ -77,6 +93,10 @@ type userConfigurableOverridesManager struct {
 	mtx          sync.RWMutex
 	tenantLimits tenantLimits
 
+	// mergedOverrides caches the user-configurable overrides merged over the runtime overrides per tenant
+	mergedMtx       sync.Mutex
+	mergedOverrides map[string]mergedOverrides
+
 	client userconfigurableoverrides.Client
 
 	logger log.Logger
@@ -95,11 +115,17 @@ func newUserConfigOverrides(cfg *UserConfigurableOverridesConfig, subOverrides S
 	}
 
 	mgr := userConfigurableOverridesManager{
-		Interface:    subOverrides,
-		cfg:          cfg,
-		tenantLimits: make(tenantLimits),
-		client:       client,
-		logger:       log.With(tempo_log.Logger, "component", "user-configurable overrides"),
+		Interface:       subOverrides,
+		cfg:             cfg,
+		tenantLimits:    make(tenantLimits),
+		mergedOverrides: make(map[string]mergedOverrides),
+		client:          client,
+		logger:          log.With(tempo_log.Logger, "component", "user-configurable overrides"),
+	}
+
+	// apply the overrides section of the user-configurable overrides to all limits of the runtime overrides
+	if runtime, ok := subOverrides.(*runtimeConfigOverridesManager); ok {
+		runtime.userOverrides = mgr.overridesFor
 	}
 
 	mgr.subservices, err = services.NewManager(subOverrides)
@@ -206,11 +232,46 @@ func (o *userConfigurableOverridesManager) setTenantLimit(userID string, limits
 
 	if limits == nil {
 		delete(o.tenantLimits, userID)
+
+		o.mergedMtx.Lock()
+		delete(o.mergedOverrides, userID)
+		o.mergedMtx.Unlock()
 	} else {
 		o.tenantLimits[userID] = limits
 	}
 }
 
+// overridesFor returns the overrides section of the user-configurable overrides of the tenant merged over its runtime
+// overrides. Returns nil if the tenant has no user-configurable overrides section.
+func (o *userConfigurableOverridesManager) overridesFor(userID string, runtime *Overrides) *Overrides {
+	limits := o.getTenantLimits(userID)
+	if _, ok := limits.GetOverrides(); !ok || len(o.cfg.AllowedOverrides) == 0 {
+		return nil
+	}
+
+	o.mergedMtx.Lock()
+	defer o.mergedMtx.Unlock()
+
+	if m, ok := o.mergedOverrides[userID]; ok && m.runtime == runtime && m.limits == limits {
+		return m.combined
+	}
+
+	patch := limits.Overrides
+	if disallowed := DisallowedOverrides(patch, o.cfg.AllowedOverrides); len(disallowed) > 0 {
+		level.Warn(o.logger).Log("msg", "ignoring user-configurable overrides that are not allowed", "tenant", userID, "overrides", strings.Join(disallowed, ","))
+		patch = allowedOverrides(patch, o.cfg.AllowedOverrides, "")
+	}
+
+	combined, err := mergeUserConfigurableOverrides(runtime, patch)
+	if err != nil {
+		level.Error(o.logger).Log("msg", "failed to merge user-configurable overrides, using runtime overrides", "tenant", userID, "err", err)
+		combined = runtime
+	}
+	o.mergedOverrides[userID] = mergedOverrides{runtime: runtime, limits: limits, combined: combined}
+
+	return combined
+}
+
 func (o *userConfigurableOverridesManager) GetTenantIDs() []string {
 	limits := o.getAllTenantLimits()
 	return slices.AppendSeq(make([]string, 0, len(limits)), maps.Keys(limits))
@@ -273,6 +334,20 @@ func (o *userConfigurableOverridesManager) MetricsGeneratorProcessorServiceGraph
 	return o.Interface.MetricsGeneratorProcessorServiceGraphsEnableVirtualNodeLabel(userID)
 }
 
+func (o *userConfigurableOverridesManager) MetricsGeneratorProcessorServiceGraphsEnableExemplars(userID string) bool {
+	if enableExemplars, ok := o.getTenantLimits(userID).GetMetricsGenerator().GetProcessor().GetServiceGraphs().GetEnableExemplars(); ok {
+		return enableExemplars
+	}
+	return o.Interface.MetricsGeneratorProcessorServiceGraphsEnableExemplars(userID)
+}
+
+func (o *userConfigurableOverridesManager) MetricsGeneratorProcessorServiceGraphsEnableConnectionTypeDimensions(userID string) bool {
+	if enable, ok := o.getTenantLimits(userID).GetMetricsGenerator().GetProcessor().GetServiceGraphs().GetEnableConnectionTypeDimensions(); ok {
+		return enable
+	}
+	return o.Interface.MetricsGeneratorProcessorServiceGraphsEnableConnectionTypeDimensions(userID)
+}
+
 func (o *userConfigurableOverridesManager) MetricsGeneratorProcessorServiceGraphsPeerAttributes(userID string) []string {
 	if peerAttributes, ok := o.getTenantLimits(userID).GetMetricsGenerator().GetProcessor().GetServiceGraphs().GetPeerAttributes(); ok {
 		return peerAttributes
@@ -362,3 +437,87 @@ func (o *userConfigurableOverridesManager) Collect(ch chan<- prometheus.Metric)
 	//  the user-config overrides as well
 	o.Interface.Collect(ch)
 }
+
+// mergeUserConfigurableOverrides applies the overrides section of user-configurable overrides as a JSON merge patch
+// to the runtime overrides.
+func mergeUserConfigurableOverrides(runtime *Overrides, patch map[string]any) (*Overrides, error) {
+	runtimeBytes, err := json.Marshal(runtime)
+	if err != nil {
+		return nil, err
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	merged, err := jsonpatch.MergePatch(runtimeBytes, patchBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseUserConfigurableOverrides(merged)
+}
+
+// ParseUserConfigurableOverrides parses the overrides section of user-configurable overrides. Unknown fields are
+// rejected.
+func ParseUserConfigurableOverrides(b []byte) (*Overrides, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+
+	var o Overrides
+	if err := d.Decode(&o); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// DisallowedOverrides returns the fields of the overrides section that are not in allowed, sorted by path.
+func DisallowedOverrides(patch map[string]any, allowed []string) []string {
+	var disallowed []string
+	var walk func(m map[string]any, prefix string)
+	walk = func(m map[string]any, prefix string) {
+		for k, v := range m {
+			path := prefix + k
+			if overrideAllowed(path, allowed) {
+				continue
+			}
+			if nested, ok := v.(map[string]any); ok && len(nested) > 0 {
+				walk(nested, path+".")
+				continue
+			}
+			disallowed = append(disallowed, path)
+		}
+	}
+	walk(patch, "")
+
+	slices.Sort(disallowed)
+	return disallowed
+}
+
+// allowedOverrides returns a copy of the overrides section with only the fields in allowed.
+func allowedOverrides(m map[string]any, allowed []string, prefix string) map[string]any {
+	filtered := make(map[string]any, len(m))
+	for k, v := range m {
+		path := prefix + k
+		if overrideAllowed(path, allowed) {
+			filtered[k] = v
+			continue
+		}
+		if nested, ok := v.(map[string]any); ok {
+			if n := allowedOverrides(nested, allowed, path+"."); len(n) > 0 {
+				filtered[k] = n
+			}
+		}
+	}
+	return filtered
+}
+
+// overrideAllowed returns true if path or one of its parents is in allowed.
+func overrideAllowed(path string, allowed []string) bool {
+	for _, a := range allowed {
+		if path == a || strings.HasPrefix(path, a+".") {
+			return true
+		}
+	}
+	return false
+}
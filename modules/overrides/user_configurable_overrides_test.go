@@ -363,6 +363,14 @@ func (b *badClient) Delete(context.Context, string, backend.Version) error {
 	return errors.New("no")
 }
 
+func (b *badClient) AppendAuditLog(context.Context, string, userconfigurableoverrides.AuditLogEntry) error {
+	return errors.New("no")
+}
+
+func (b *badClient) GetAuditLog(context.Context, string) ([]userconfigurableoverrides.AuditLogEntry, error) {
+	return nil, errors.New("no")
+}
+
 func (b badClient) Shutdown() {
 }
 
@@ -441,6 +449,91 @@ func TestUserConfigOverridesManager_MergeRuntimeConfig(t *testing.T) {
 	assert.Equal(t, mgr.DedicatedColumns(tenantID), baseMgr.DedicatedColumns(tenantID))
 }
 
+func TestUserConfigOverridesManager_overrides(t *testing.T) {
+	tenantID := "test"
+	pto := perTenantRuntimeOverrides(tenantID)
+
+	_, mgr, cleanup := localUserConfigOverrides(t, Overrides{}, toYamlBytes(t, pto))
+	defer cleanup()
+
+	mgr.setTenantLimit(tenantID, &userconfigurableoverrides.Limits{
+		Overrides: map[string]any{
+			"ingestion": map[string]any{"rate_limit_bytes": 1000},
+			"read":      map[string]any{"max_search_duration": "2h"},
+		},
+	})
+
+	// the overrides section is ignored unless the fields are allowed
+	assert.Equal(t, float64(400), mgr.IngestionRateLimitBytes(tenantID))
+	assert.Equal(t, 1000*time.Hour, mgr.MaxSearchDuration(tenantID))
+
+	mgr.cfg.AllowedOverrides = []string{"ingestion.rate_limit_bytes", "read"}
+
+	// the overrides section is merged over the runtime overrides
+	assert.Equal(t, float64(1000), mgr.IngestionRateLimitBytes(tenantID))
+	assert.Equal(t, 2*time.Hour, mgr.MaxSearchDuration(tenantID))
+	assert.Equal(t, 400, mgr.IngestionBurstSizeBytes(tenantID))
+	assert.Equal(t, []string{"fwd", "fwd-2"}, mgr.Forwarders(tenantID))
+	assert.Equal(t, pto.TenantLimits[tenantID].Storage.DedicatedColumns, mgr.DedicatedColumns(tenantID))
+	assert.Equal(t, 400, mgr.GetRuntimeOverridesFor(tenantID).Ingestion.RateLimitBytes)
+
+	// tenants without user-configurable overrides are not affected
+	assert.Equal(t, float64(0), mgr.IngestionRateLimitBytes(tenant1))
+
+	// fields that aren't allowed are ignored
+	mgr.setTenantLimit(tenantID, &userconfigurableoverrides.Limits{
+		Overrides: map[string]any{
+			"ingestion": map[string]any{"rate_limit_bytes": 2000, "burst_size_bytes": 3000},
+			"global":    map[string]any{"max_bytes_per_trace": 10},
+		},
+	})
+	assert.Equal(t, float64(2000), mgr.IngestionRateLimitBytes(tenantID))
+	assert.Equal(t, 400, mgr.IngestionBurstSizeBytes(tenantID))
+	assert.Equal(t, pto.TenantLimits[tenantID].Global.MaxBytesPerTrace, mgr.MaxBytesPerTrace(tenantID))
+	assert.Equal(t, 1000*time.Hour, mgr.MaxSearchDuration(tenantID))
+
+	mgr.setTenantLimit(tenantID, nil)
+	assert.Equal(t, float64(400), mgr.IngestionRateLimitBytes(tenantID))
+}
+
+func TestDisallowedOverrides(t *testing.T) {
+	patch := map[string]any{
+		"ingestion":  map[string]any{"rate_limit_bytes": 1000, "burst_size_bytes": 2000},
+		"read":       map[string]any{"max_search_duration": "1h"},
+		"forwarders": []string{"fwd"},
+	}
+
+	assert.Equal(t, []string{"forwarders", "ingestion.burst_size_bytes", "ingestion.rate_limit_bytes", "read.max_search_duration"}, DisallowedOverrides(patch, nil))
+	assert.Equal(t, []string{"forwarders", "ingestion.burst_size_bytes"}, DisallowedOverrides(patch, []string{"ingestion.rate_limit_bytes", "read"}))
+	assert.Empty(t, DisallowedOverrides(patch, []string{"ingestion", "read", "forwarders"}))
+
+	assert.Equal(t, map[string]any{
+		"ingestion": map[string]any{"rate_limit_bytes": 1000},
+		"read":      map[string]any{"max_search_duration": "1h"},
+	}, allowedOverrides(patch, []string{"ingestion.rate_limit_bytes", "read"}, ""))
+}
+
+func TestMergeUserConfigurableOverrides(t *testing.T) {
+	runtime := perTenantRuntimeOverrides("test").TenantLimits["test"]
+
+	// an empty patch keeps all runtime overrides
+	merged, err := mergeUserConfigurableOverrides(runtime, map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, runtime, merged)
+
+	merged, err = mergeUserConfigurableOverrides(runtime, map[string]any{
+		"forwarders": nil,
+		"global":     map[string]any{"max_bytes_per_trace": 10},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, merged.Forwarders)
+	assert.Equal(t, 10, merged.Global.MaxBytesPerTrace)
+	assert.Equal(t, runtime.Ingestion, merged.Ingestion)
+
+	_, err = mergeUserConfigurableOverrides(runtime, map[string]any{"unknown": 1})
+	assert.EqualError(t, err, "json: unknown field \"unknown\"")
+}
+
 func perTenantRuntimeOverrides(tenantID string) *perTenantOverrides {
 	pto := &perTenantOverrides{
 		TenantLimits: map[string]*Overrides{
@@ -511,3 +604,20 @@ func perTenantRuntimeOverrides(tenantID string) *perTenantOverrides {
 
 	return pto
 }
+
+func TestHasTenantOverrides(t *testing.T) {
+	path, mgr, cleanup := localUserConfigOverrides(t, Overrides{}, toYamlBytes(t, perTenantRuntimeOverrides("runtime")))
+	defer cleanup()
+
+	writeUserConfigurableOverridesToDisk(t, path, "user-configurable", &userconfigurableoverrides.Limits{
+		Forwarders: &[]string{"my-forwarder"},
+	})
+	require.NoError(t, mgr.reloadAllTenantLimits(context.Background()))
+
+	assert.True(t, HasTenantOverrides(mgr, "runtime"))
+	assert.True(t, HasTenantOverrides(mgr, "user-configurable"))
+	assert.False(t, HasTenantOverrides(mgr, "unknown"))
+
+	assert.True(t, HasTenantOverrides(mgr.Interface, "runtime"))
+	assert.False(t, HasTenantOverrides(mgr.Interface, "user-configurable"))
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"io"
 	"reflect"
+	"time"
 
 	jsonpatch "github.com/evanphx/json-patch"
 	"github.com/go-kit/log"
@@ -173,6 +174,22 @@ func (a *UserConfigOverridesAPI) delete(ctx context.Context, userID string, vers
 	return a.client.Delete(ctx, userID, version)
 }
 
+// recordChange appends the change to the audit log of the tenant. Failing to do so doesn't fail the change.
+func (a *UserConfigOverridesAPI) recordChange(ctx context.Context, userID string, action client.AuditAction, limits *client.Limits, version backend.Version) {
+	traceID, _ := tracing.ExtractTraceID(ctx)
+
+	err := a.client.AppendAuditLog(ctx, userID, client.AuditLogEntry{
+		Timestamp: time.Now(),
+		Action:    action,
+		Version:   version,
+		Limits:    limits,
+		TraceID:   traceID,
+	})
+	if err != nil {
+		level.Error(a.logger).Log("traceID", traceID, "msg", "failed to record change of user-configurable overrides in audit log", "userID", userID, "action", action, "err", err)
+	}
+}
+
 func (a *UserConfigOverridesAPI) parseLimits(body io.Reader) (*client.Limits, error) {
 	d := jsoniter.NewDecoder(body)
 
@@ -134,6 +134,58 @@ func Test_UserConfigOverridesAPI_overridesHandlers(t *testing.T) {
 	}
 }
 
+func Test_UserConfigOverridesAPI_auditLogHandler(t *testing.T) {
+	tenant := "my-tenant"
+
+	cfg := client.Config{
+		Backend: backend.Local,
+		Local:   &local.Config{Path: t.TempDir()},
+	}
+
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.DefaultRegisterer)
+	assert.NoError(t, err)
+
+	overridesAPI, err := New(&overrides.UserConfigurableOverridesAPIConfig{}, &cfg, o, &mockValidator{})
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	overridesAPI.AuditLogHandler(w, prepareRequest(tenant, "GET", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	overridesAPI.PostHandler(w, prepareRequest(tenant, "POST", []byte(`{"overrides":{"ingestion":{"rate_limit_bytes":1000}}}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	overridesAPI.PatchHandler(w, prepareRequest(tenant, "PATCH", []byte(`{"overrides":{"ingestion":{"burst_size_bytes":2000}}}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	overridesAPI.DeleteHandler(w, prepareRequest(tenant, "DELETE", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// a failed change isn't recorded
+	w = httptest.NewRecorder()
+	overridesAPI.PostHandler(w, prepareRequest(tenant, "POST", []byte(`{"unknown":true}`)))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	overridesAPI.AuditLogHandler(w, prepareRequest(tenant, "GET", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, api.HeaderAcceptJSON, w.Header().Get(api.HeaderContentType))
+
+	var entries []client.AuditLogEntry
+	require.NoError(t, jsoniter.Unmarshal(w.Body.Bytes(), &entries))
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, client.AuditActionSet, entries[0].Action)
+	assert.Equal(t, map[string]any{"ingestion": map[string]any{"rate_limit_bytes": float64(1000)}}, entries[0].Limits.Overrides)
+	assert.Equal(t, client.AuditActionPatch, entries[1].Action)
+	assert.Equal(t, map[string]any{"ingestion": map[string]any{"rate_limit_bytes": float64(1000), "burst_size_bytes": float64(2000)}}, entries[1].Limits.Overrides)
+	assert.Equal(t, client.AuditActionDelete, entries[2].Action)
+	assert.Nil(t, entries[2].Limits)
+}
+
 func Test_UserConfigOverridesAPI_patchOverridesHandlers(t *testing.T) {
 	tenant := "my-tenant"
 
@@ -518,6 +570,14 @@ func (t *testClient) Delete(_ context.Context, _ string, _ backend.Version) erro
 	panic("implement me")
 }
 
+func (t *testClient) AppendAuditLog(context.Context, string, client.AuditLogEntry) error {
+	return nil
+}
+
+func (t *testClient) GetAuditLog(context.Context, string) ([]client.AuditLogEntry, error) {
+	panic("implement me")
+}
+
 func (t *testClient) Shutdown() {
 }
 
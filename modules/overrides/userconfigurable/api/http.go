@@ -102,7 +102,9 @@ func (a *UserConfigOverridesAPI) PostHandler(w http.ResponseWriter, r *http.Requ
 	version, err := a.set(ctx, userID, limits, backend.Version(ifMatchVersion), skipConflictingOverridesCheck)
 	if err != nil {
 		writeError(w, err)
+		return
 	}
+	a.recordChange(ctx, userID, client.AuditActionSet, limits, version)
 
 	w.Header().Set(headerEtag, string(version))
 }
@@ -138,6 +140,7 @@ func (a *UserConfigOverridesAPI) PatchHandler(w http.ResponseWriter, r *http.Req
 		writeError(w, err)
 		return
 	}
+	a.recordChange(ctx, userID, client.AuditActionPatch, patchedLimits, version)
 
 	err = writeLimits(w, patchedLimits, version)
 }
@@ -162,7 +165,37 @@ func (a *UserConfigOverridesAPI) DeleteHandler(w http.ResponseWriter, r *http.Re
 	err = a.delete(ctx, userID, backend.Version(ifMatchVersion))
 	if err != nil {
 		writeError(w, err)
+		return
 	}
+	a.recordChange(ctx, userID, client.AuditActionDelete, nil, "")
+}
+
+// AuditLogHandler returns the most recent changes of the user-configurable overrides, oldest first.
+func (a *UserConfigOverridesAPI) AuditLogHandler(w http.ResponseWriter, r *http.Request) {
+	var err error
+	ctx, f := a.logRequest(r.Context(), "UserConfigOverridesAPI.AuditLogHandler", r)
+	defer f(&err)
+
+	userID, err := user.ExtractOrgID(ctx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := a.client.GetAuditLog(ctx, userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	data, err := jsoniter.Marshal(entries)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set(api.HeaderContentType, api.HeaderAcceptJSON)
+	_, _ = w.Write(data)
 }
 
 func writeError(w http.ResponseWriter, err error) {
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	AuditLogFileName = "audit.json"
+
+	// maxAuditLogEntries is the number of most recent changes kept in the audit log of a tenant.
+	maxAuditLogEntries = 100
+
+	// auditLogWriteAttempts is how often appending to the audit log is attempted when it's modified concurrently.
+	auditLogWriteAttempts = 3
+)
+
+type AuditAction string
+
+const (
+	AuditActionSet    AuditAction = "set"
+	AuditActionPatch  AuditAction = "patch"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// AuditLogEntry records a change of the user-configurable overrides of a tenant.
+type AuditLogEntry struct {
+	Timestamp time.Time       `json:"timestamp"`
+	Action    AuditAction     `json:"action"`
+	Version   backend.Version `json:"version,omitempty"`
+	// Limits are the overrides after the change, nil if they were deleted.
+	Limits  *Limits `json:"limits,omitempty"`
+	TraceID string  `json:"trace_id,omitempty"`
+}
+
+func (o *clientImpl) AppendAuditLog(ctx context.Context, userID string, entry AuditLogEntry) error {
+	ctx, span := tracer.Start(ctx, "clientImpl.AppendAuditLog", trace.WithAttributes(attribute.String("tenant", userID)))
+	defer span.End()
+
+	var err error
+	for i := 0; i < auditLogWriteAttempts; i++ {
+		var (
+			entries []AuditLogEntry
+			version backend.Version
+		)
+		entries, version, err = o.readAuditLog(ctx, userID)
+		if errors.Is(err, backend.ErrDoesNotExist) {
+			version = backend.VersionNew
+		} else if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry)
+		if len(entries) > maxAuditLogEntries {
+			entries = entries[len(entries)-maxAuditLogEntries:]
+		}
+
+		var data []byte
+		data, err = jsoniter.Marshal(entries)
+		if err != nil {
+			return err
+		}
+
+		_, err = o.rw.WriteVersioned(ctx, AuditLogFileName, []string{OverridesKeyPath, userID}, bytes.NewReader(data), version)
+		if !errors.Is(err, backend.ErrVersionDoesNotMatch) {
+			return err
+		}
+	}
+	return err
+}
+
+func (o *clientImpl) GetAuditLog(ctx context.Context, userID string) ([]AuditLogEntry, error) {
+	ctx, span := tracer.Start(ctx, "clientImpl.GetAuditLog", trace.WithAttributes(attribute.String("tenant", userID)))
+	defer span.End()
+
+	entries, _, err := o.readAuditLog(ctx, userID)
+	return entries, err
+}
+
+func (o *clientImpl) readAuditLog(ctx context.Context, userID string) ([]AuditLogEntry, backend.Version, error) {
+	reader, version, err := o.rw.ReadVersioned(ctx, AuditLogFileName, []string{OverridesKeyPath, userID})
+	if err != nil {
+		return nil, "", err
+	}
+	defer reader.Close()
+
+	var entries []AuditLogEntry
+	err = json.NewDecoder(reader).Decode(&entries)
+	return entries, version, err
+}
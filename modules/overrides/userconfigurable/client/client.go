@@ -90,6 +90,11 @@ type Client interface {
 	Set(context.Context, string, *Limits, backend.Version) (backend.Version, error)
 	// Delete the user-configurable overrides.
 	Delete(context.Context, string, backend.Version) error
+	// AppendAuditLog records a change of the user-configurable overrides.
+	AppendAuditLog(context.Context, string, AuditLogEntry) error
+	// GetAuditLog returns the most recent changes of the user-configurable overrides, oldest first. Returns
+	// backend.ErrDoesNotExist if they were never changed.
+	GetAuditLog(context.Context, string) ([]AuditLogEntry, error)
 	// Shutdown the client.
 	Shutdown()
 }
@@ -2,6 +2,7 @@ package client
 
 import (
 	"context"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -57,3 +58,37 @@ func TestUserConfigOverridesClient(t *testing.T) {
 	_, _, err = client.Get(ctx, tenant)
 	assert.ErrorIs(t, err, backend.ErrDoesNotExist)
 }
+
+func TestUserConfigOverridesClient_auditLog(t *testing.T) {
+	ctx := context.Background()
+	tenant := "foo"
+
+	client, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: t.TempDir(),
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = client.GetAuditLog(ctx, tenant)
+	assert.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	for i := 0; i < maxAuditLogEntries+2; i++ {
+		require.NoError(t, client.AppendAuditLog(ctx, tenant, AuditLogEntry{
+			Action:  AuditActionSet,
+			Version: backend.Version(strconv.Itoa(i)),
+		}))
+	}
+
+	// only the most recent entries are kept
+	entries, err := client.GetAuditLog(ctx, tenant)
+	require.NoError(t, err)
+	require.Len(t, entries, maxAuditLogEntries)
+	assert.Equal(t, backend.Version("2"), entries[0].Version)
+	assert.Equal(t, backend.Version(strconv.Itoa(maxAuditLogEntries+1)), entries[len(entries)-1].Version)
+
+	// the audit log isn't mistaken for overrides
+	_, _, err = client.Get(ctx, tenant)
+	assert.ErrorIs(t, err, backend.ErrDoesNotExist)
+}
@@ -11,6 +11,10 @@ type Limits struct {
 	Forwarders       *[]string              `yaml:"forwarders,omitempty" json:"forwarders,omitempty"`
 	CostAttribution  CostAttribution        `yaml:"cost_attribution,omitempty" json:"cost_attribution,omitempty"`
 	MetricsGenerator LimitsMetricsGenerator `yaml:"metrics_generator,omitempty" json:"metrics_generator,omitempty"`
+
+	// Overrides are any of the per-tenant overrides of the runtime config, for example
+	// {"ingestion": {"rate_limit_bytes": 1000}}. They are merged over the runtime overrides of the tenant.
+	Overrides map[string]any `yaml:"overrides,omitempty" json:"overrides,omitempty"`
 }
 
 func (l *Limits) GetForwarders() ([]string, bool) {
@@ -27,6 +31,13 @@ func (l *Limits) GetMetricsGenerator() *LimitsMetricsGenerator {
 	return nil
 }
 
+func (l *Limits) GetOverrides() (map[string]any, bool) {
+	if l != nil && len(l.Overrides) > 0 {
+		return l.Overrides, true
+	}
+	return nil, false
+}
+
 func (l *Limits) GetCostAttribution() *CostAttribution {
 	if l != nil {
 		return &l.CostAttribution
@@ -94,6 +105,8 @@ type LimitsMetricsGeneratorProcessorServiceGraphs struct {
 	EnableClientServerPrefix              *bool      `yaml:"enable_client_server_prefix,omitempty" json:"enable_client_server_prefix,omitempty"`
 	EnableMessagingSystemLatencyHistogram *bool      `yaml:"enable_messaging_system_latency_histogram,omitempty" json:"enable_messaging_system_latency_histogram,omitempty"`
 	EnableVirtualNodeLabel                *bool      `yaml:"enable_virtual_node_label,omitempty" json:"enable_virtual_node_label,omitempty"`
+	EnableExemplars                       *bool      `yaml:"enable_exemplars,omitempty" json:"enable_exemplars,omitempty"`
+	EnableConnectionTypeDimensions        *bool      `yaml:"enable_connection_type_dimensions,omitempty" json:"enable_connection_type_dimensions,omitempty"`
 	PeerAttributes                        *[]string  `yaml:"peer_attributes,omitempty" json:"peer_attributes,omitempty"`
 	HistogramBuckets                      *[]float64 `yaml:"histogram_buckets,omitempty" json:"histogram_buckets,omitempty"`
 }
@@ -119,6 +132,20 @@ func (l *LimitsMetricsGeneratorProcessorServiceGraphs) GetEnableVirtualNodeLabel
 	return false, false
 }
 
+func (l *LimitsMetricsGeneratorProcessorServiceGraphs) GetEnableExemplars() (bool, bool) {
+	if l != nil && l.EnableExemplars != nil {
+		return *l.EnableExemplars, true
+	}
+	return false, false
+}
+
+func (l *LimitsMetricsGeneratorProcessorServiceGraphs) GetEnableConnectionTypeDimensions() (bool, bool) {
+	if l != nil && l.EnableConnectionTypeDimensions != nil {
+		return *l.EnableConnectionTypeDimensions, true
+	}
+	return false, false
+}
+
 func (l *LimitsMetricsGeneratorProcessorServiceGraphs) GetPeerAttributes() ([]string, bool) {
 	if l != nil && l.PeerAttributes != nil {
 		return *l.PeerAttributes, true
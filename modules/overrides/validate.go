@@ -0,0 +1,112 @@
+package overrides
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// maxValidateBodySize is the largest overrides file accepted by ValidateHandler.
+const maxValidateBodySize = 10 << 20
+
+// ValidationResult is the outcome of validating a per-tenant overrides file.
+type ValidationResult struct {
+	Valid    bool              `json:"valid"`
+	Errors   []ValidationError `json:"errors,omitempty"`
+	Warnings []ValidationError `json:"warnings,omitempty"`
+}
+
+// ValidationError is a single problem found in an overrides file. Tenant is empty for problems with
+// the file as a whole.
+type ValidationError struct {
+	Tenant  string `json:"tenant,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e ValidationError) String() string {
+	if e.Tenant == "" {
+		return e.Message
+	}
+	return e.Tenant + ": " + e.Message
+}
+
+// ValidatePerTenantOverrides checks a proposed per-tenant overrides file the same way the runtime config
+// loader would. Unlike the loader it does not stop at the first invalid tenant, so every problem in the
+// file is reported at once.
+func ValidatePerTenantOverrides(b []byte, cfg Config, validator Validator) ValidationResult {
+	result := ValidationResult{}
+
+	overrides, err := parsePerTenantOverrides(bytes.NewReader(b), cfg.ExpandEnv)
+	if errors.Is(err, io.EOF) {
+		err = errors.New("overrides file is empty")
+	}
+	if err != nil {
+		result.Errors = append(result.Errors, ValidationError{Message: err.Error()})
+		return result
+	}
+
+	if overrides.ConfigType != cfg.ConfigType {
+		result.Warnings = append(result.Warnings, ValidationError{
+			Message: fmt.Sprintf("per-tenant overrides config type (%s) does not match static overrides config type (%s)", overrides.ConfigType, cfg.ConfigType),
+		})
+	}
+
+	if validator != nil {
+		for tenant, tenantOverrides := range overrides.TenantLimits {
+			if tenantOverrides == nil {
+				continue
+			}
+			if err := validator.Validate(tenantOverrides); err != nil {
+				result.Errors = append(result.Errors, ValidationError{Tenant: tenant, Message: err.Error()})
+			}
+		}
+		for _, s := range overrides.Schedules {
+			for tenant, tenantOverrides := range s.TenantLimits {
+				if tenantOverrides == nil {
+					continue
+				}
+				if err := validator.Validate(tenantOverrides); err != nil {
+					result.Errors = append(result.Errors, ValidationError{Tenant: tenant, Message: fmt.Sprintf("schedule %s: %v", s.Name, err)})
+				}
+			}
+		}
+	}
+
+	slices.SortFunc(result.Errors, func(a, b ValidationError) int {
+		return strings.Compare(a.Tenant, b.Tenant)
+	})
+
+	result.Valid = len(result.Errors) == 0
+	return result
+}
+
+// ValidateHandler validates the overrides file in the request body without loading it. It responds with
+// a ValidationResult and a 400 status code if the file is not valid.
+func ValidateHandler(cfg Config, validator Validator) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		b, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxValidateBodySize))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		result := ValidatePerTenantOverrides(b, cfg, validator)
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !result.Valid {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_, _ = w.Write(data)
+	}
+}
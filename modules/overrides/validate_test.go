@@ -0,0 +1,127 @@
+package overrides
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePerTenantOverrides(t *testing.T) {
+	validator := &mockValidator{f: func(o *Overrides) error {
+		if o.Ingestion.TenantShardSize == 1 {
+			return errors.New("shard size too low")
+		}
+		return nil
+	}}
+	cfg := Config{ConfigType: ConfigTypeNew}
+
+	tests := []struct {
+		name        string
+		file        string
+		expected    ValidationResult
+		errContains string
+	}{
+		{
+			name: "valid",
+			file: `
+overrides:
+  foo:
+    ingestion:
+      tenant_shard_size: 6
+`,
+			expected: ValidationResult{Valid: true},
+		},
+		{
+			name: "every invalid tenant is reported",
+			file: `
+overrides:
+  foo:
+    ingestion:
+      tenant_shard_size: 6
+  bzz:
+    ingestion:
+      tenant_shard_size: 1
+  bar:
+    ingestion:
+      tenant_shard_size: 1
+`,
+			expected: ValidationResult{Errors: []ValidationError{
+				{Tenant: "bar", Message: "shard size too low"},
+				{Tenant: "bzz", Message: "shard size too low"},
+			}},
+		},
+		{
+			name: "unknown field",
+			file: `
+overrides:
+  foo:
+    ingestion:
+      not_a_limit: 6
+`,
+			errContains: "line 4",
+		},
+		{
+			name: "empty",
+			file: "",
+			expected: ValidationResult{Errors: []ValidationError{
+				{Message: "overrides file is empty"},
+			}},
+		},
+		{
+			name: "legacy config type",
+			file: `
+overrides:
+  foo:
+    ingestion_burst_size_bytes: 100
+`,
+			expected: ValidationResult{Valid: true, Warnings: []ValidationError{
+				{Message: "per-tenant overrides config type (legacy) does not match static overrides config type (new)"},
+			}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result := ValidatePerTenantOverrides([]byte(tc.file), cfg, validator)
+
+			if tc.errContains != "" {
+				require.False(t, result.Valid)
+				require.Len(t, result.Errors, 1)
+				assert.Empty(t, result.Errors[0].Tenant)
+				assert.Contains(t, result.Errors[0].Message, tc.errContains)
+				return
+			}
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestValidateHandler(t *testing.T) {
+	validator := &mockValidator{f: func(o *Overrides) error {
+		if o.Ingestion.TenantShardSize == 1 {
+			return errors.New("shard size too low")
+		}
+		return nil
+	}}
+	handler := ValidateHandler(Config{ConfigType: ConfigTypeNew}, validator)
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/api/overrides/validate", strings.NewReader("overrides:\n  foo:\n    ingestion:\n      tenant_shard_size: 6\n")))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"valid":true}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/api/overrides/validate", strings.NewReader("overrides:\n  foo:\n    ingestion:\n      tenant_shard_size: 1\n")))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var result ValidationResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, ValidationResult{Errors: []ValidationError{{Tenant: "foo", Message: "shard size too low"}}}, result)
+}
@@ -27,6 +27,9 @@ type Config struct {
 
 type SearchConfig struct {
 	QueryTimeout time.Duration `yaml:"query_timeout"`
+	// BlockTimeout is the maximum time spent on a block search job. A job that times out is skipped and returned
+	// as a skipped block instead of failing the search. 0 disables the timeout.
+	BlockTimeout time.Duration `yaml:"block_timeout"`
 }
 
 type TraceByIDConfig struct {
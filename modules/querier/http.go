@@ -50,6 +50,11 @@ func (q *Querier) TraceByIDHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	maxBytes, err := api.ParseMaxBytesPerTrace(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	span.AddEvent("validated request", oteltrace.WithAttributes(
 		attribute.String("blockStart", blockStart),
 		attribute.String("blockEnd", blockEnd),
@@ -60,10 +65,11 @@ func (q *Querier) TraceByIDHandler(w http.ResponseWriter, r *http.Request) {
 	))
 
 	resp, err := q.FindTraceByID(ctx, &tempopb.TraceByIDRequest{
-		TraceID:    byteID,
-		BlockStart: blockStart,
-		BlockEnd:   blockEnd,
-		QueryMode:  queryMode,
+		TraceID:          byteID,
+		BlockStart:       blockStart,
+		BlockEnd:         blockEnd,
+		QueryMode:        queryMode,
+		MaxBytesPerTrace: uint64(maxBytes),
 	}, timeStart, timeEnd)
 	if err != nil {
 		handleError(w, err)
@@ -99,6 +105,11 @@ func (q *Querier) TraceByIDHandlerV2(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	maxBytes, err := api.ParseMaxBytesPerTrace(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	span.AddEvent("validated request", oteltrace.WithAttributes(
 		attribute.String("blockStart", blockStart),
 		attribute.String("blockEnd", blockEnd),
@@ -114,6 +125,7 @@ func (q *Querier) TraceByIDHandlerV2(w http.ResponseWriter, r *http.Request) {
 		BlockEnd:          blockEnd,
 		QueryMode:         queryMode,
 		AllowPartialTrace: true,
+		MaxBytesPerTrace:  uint64(maxBytes),
 	}, timeStart, timeEnd)
 	if err != nil {
 		handleError(w, err)
@@ -164,6 +176,11 @@ func (q *Querier) SearchHandler(w http.ResponseWriter, r *http.Request) {
 			handleError(w, err)
 			return
 		}
+
+		// the results of a skipped block must not be cached by the frontend
+		if len(resp.SkippedBlocks) > 0 {
+			w.Header().Set(api.HeaderCacheControl, api.HeaderNoStore)
+		}
 	}
 
 	writeFormattedContentForRequest(w, r, resp, span)
@@ -55,6 +55,11 @@ var (
 		Name:      "querier_metrics_generator_clients",
 		Help:      "The current number of generator clients.",
 	})
+	metricSkippedBlocks = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempo",
+		Name:      "querier_search_skipped_blocks_total",
+		Help:      "The total number of block search jobs skipped because they timed out.",
+	})
 )
 
 type (
@@ -79,6 +84,8 @@ type Querier struct {
 	store  storage.Store
 	limits overrides.Interface
 
+	searchSelectivity *searchSelectivity
+
 	subservices        *services.Manager
 	subservicesWatcher *services.FailureWatcher
 }
@@ -123,9 +130,10 @@ func New(
 			generatorClientFactory,
 			metricMetricsGeneratorClients,
 			log.Logger),
-		engine: traceql.NewEngine(),
-		store:  store,
-		limits: limits,
+		engine:            traceql.NewEngine(),
+		store:             store,
+		limits:            limits,
+		searchSelectivity: newSearchSelectivity(store),
 	}
 
 	q.Service = services.NewBasicService(q.starting, q.running, q.stopping)
@@ -144,7 +152,7 @@ func (q *Querier) CreateAndRegisterWorker(handler http.Handler) error {
 		return fmt.Errorf("failed to create frontend worker: %w", err)
 	}
 
-	subservices := []services.Service{worker, q.generatorPool}
+	subservices := []services.Service{worker, q.generatorPool, q.searchSelectivity}
 	for _, pool := range q.ingesterPools {
 		subservices = append(subservices, pool)
 	}
@@ -196,7 +204,8 @@ func (q *Querier) stopping(_ error) error {
 	return nil
 }
 
-// FindTraceByID implements tempopb.Querier.
+// FindTraceByID implements tempopb.Querier. The maximum size of the trace requested by the client is bounded by the
+// limit of the tenant.
 func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDRequest, timeStart int64, timeEnd int64) (*tempopb.TraceByIDResponse, error) {
 	if !validation.ValidTraceID(req.TraceID) {
 		return nil, errors.New("invalid trace id")
@@ -212,7 +221,7 @@ func (q *Querier) FindTraceByID(ctx context.Context, req *tempopb.TraceByIDReque
 
 	span.SetAttributes(attribute.String("queryMode", req.QueryMode))
 
-	maxBytes := q.limits.MaxBytesPerTrace(userID)
+	maxBytes := api.MaxBytesPerTrace(int(req.MaxBytesPerTrace), q.limits.MaxBytesPerTrace(userID))
 	combiner := trace.NewCombiner(maxBytes, req.AllowPartialTrace)
 
 	if req.QueryMode == QueryModeIngesters || req.QueryMode == QueryModeAll {
@@ -854,12 +863,55 @@ func (q *Querier) SearchBlock(ctx context.Context, req *tempopb.SearchBlockReque
 	opts.TotalPages = int(req.PagesToSearch)
 	opts.MaxBytes = q.limits.MaxBytesPerTrace(tenantID)
 
+	if q.cfg.Search.BlockTimeout <= 0 {
+		resp, err := q.searchBlock(ctx, meta, req, opts)
+		q.observeSearchSelectivity(meta, opts, resp, err)
+		return resp, err
+	}
+
+	blockCtx, cancel := context.WithTimeout(ctx, q.cfg.Search.BlockTimeout)
+	defer cancel()
+
+	resp, err := q.searchBlock(blockCtx, meta, req, opts)
+	q.observeSearchSelectivity(meta, opts, resp, err)
+	// a block that times out is skipped instead of failing the whole search. the timeout of the request itself
+	// is still an error
+	if err != nil && errors.Is(blockCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		level.Warn(log.Logger).Log("msg", "skipping block, search timed out", "tenant", tenantID, "block", req.BlockID, "startPage", req.StartPage, "timeout", q.cfg.Search.BlockTimeout)
+		metricSkippedBlocks.Inc()
+
+		return &tempopb.SearchResponse{
+			Metrics:       &tempopb.SearchMetrics{},
+			SkippedBlocks: []string{req.BlockID},
+		}, nil
+	}
+
+	return resp, err
+}
+
+// observeSearchSelectivity records the bytes read by a successful block search for tenants with row group auto-tuning.
+func (q *Querier) observeSearchSelectivity(meta *backend.BlockMeta, opts common.SearchOptions, resp *tempopb.SearchResponse, err error) {
+	if err != nil || resp == nil || resp.Metrics == nil || !q.limits.RowGroupAutoTune(meta.TenantID) {
+		return
+	}
+	q.searchSelectivity.observe(meta, opts.StartPage, opts.TotalPages, resp.Metrics.InspectedBytes)
+}
+
+func (q *Querier) searchBlock(ctx context.Context, meta *backend.BlockMeta, req *tempopb.SearchBlockRequest, opts common.SearchOptions) (*tempopb.SearchResponse, error) {
 	if api.IsTraceQLQuery(req.SearchReq) {
 		fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
 			return q.store.Fetch(ctx, meta, req, opts)
 		})
 
-		return q.engine.ExecuteSearch(ctx, req.SearchReq, fetcher)
+		resp, err := q.engine.ExecuteSearch(ctx, req.SearchReq, fetcher)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range resp.Analysis {
+			a.BlockID = req.BlockID
+			a.StartPage = req.StartPage
+		}
+		return resp, nil
 	}
 
 	return q.store.Search(ctx, meta, req.SearchReq, opts)
@@ -1058,7 +1110,7 @@ func (q *Querier) internalTagValuesSearchBlockV2(ctx context.Context, req *tempo
 	opts.StartPage = int(req.StartPage)
 	opts.TotalPages = int(req.PagesToSearch)
 
-	query := traceql.ExtractMatchers(req.SearchReq.Query)
+	query := traceql.TagValuesQuery(req.SearchReq.Query)
 	if traceql.IsEmptyQuery(query) {
 		return q.store.SearchTagValuesV2(ctx, meta, req.SearchReq, opts)
 	}
@@ -1078,7 +1130,18 @@ func (q *Querier) internalTagValuesSearchBlockV2(ctx context.Context, req *tempo
 		return q.store.FetchTagValues(ctx, meta, req, cb, mc.Add, opts)
 	})
 
-	err = q.engine.ExecuteTagValues(ctx, tag, query, traceql.MakeCollectTagValueFunc(valueCollector.Collect), fetcher)
+	// queries the tag values fetcher can't evaluate exactly are run against the spans
+	var spansResp traceql.FetchSpansResponse
+	spansetFetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		var err error
+		spansResp, err = q.store.Fetch(ctx, meta, req, opts)
+		return spansResp, err
+	})
+
+	err = q.engine.ExecuteTagValues(ctx, tag, query, traceql.MakeCollectTagValueFunc(valueCollector.Collect), fetcher, spansetFetcher)
+	if spansResp.Bytes != nil {
+		mc.Add(spansResp.Bytes())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1108,6 +1171,7 @@ func (q *Querier) postProcessIngesterSearchResults(req *tempopb.SearchRequest, r
 			response.Metrics.InspectedBytes += sr.Metrics.InspectedBytes
 			response.Metrics.InspectedTraces += sr.Metrics.InspectedTraces
 		}
+		response.Analysis = append(response.Analysis, sr.Analysis...)
 	}
 
 	for _, t := range traces {
@@ -4,12 +4,16 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/grafana/dskit/user"
 	generator_client "github.com/grafana/tempo/modules/generator/client"
 	ingester_client "github.com/grafana/tempo/modules/ingester/client"
 	"github.com/grafana/tempo/modules/overrides"
+	"github.com/grafana/tempo/modules/storage"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 )
@@ -104,3 +108,47 @@ func TestVirtualTagsDoesntHitBackend(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, resp)
 }
+
+func TestSearchBlockSkipsBlockOnTimeout(t *testing.T) {
+	o, err := overrides.NewOverrides(overrides.Config{}, nil, prometheus.DefaultRegisterer)
+	require.NoError(t, err)
+
+	cfg := Config{}
+	cfg.Search.BlockTimeout = 10 * time.Millisecond
+
+	q, err := New(cfg, ingester_client.Config{}, nil, generator_client.Config{}, nil, &slowStore{}, o)
+	require.NoError(t, err)
+
+	ctx := user.InjectOrgID(context.Background(), "blerg")
+	req := &tempopb.SearchBlockRequest{
+		SearchReq: &tempopb.SearchRequest{Tags: map[string]string{"foo": "bar"}},
+		BlockID:   "b7f9c11a-6d2b-4d3e-8a2f-5c1c4e8e6f01",
+		Encoding:  "none",
+		Version:   "vParquet4",
+	}
+
+	resp, err := q.SearchBlock(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, &tempopb.SearchResponse{
+		Metrics:       &tempopb.SearchMetrics{},
+		SkippedBlocks: []string{req.BlockID},
+	}, resp)
+
+	// the timeout of the request still fails the search
+	ctx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	q.cfg.Search.BlockTimeout = time.Minute
+
+	_, err = q.SearchBlock(ctx, req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// slowStore blocks searches until the context is done.
+type slowStore struct {
+	storage.Store
+}
+
+func (s *slowStore) Search(ctx context.Context, _ *backend.BlockMeta, _ *tempopb.SearchRequest, _ common.SearchOptions) (*tempopb.SearchResponse, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
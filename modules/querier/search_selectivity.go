@@ -0,0 +1,101 @@
+package querier
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/grafana/dskit/services"
+
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// searchSelectivityFlushInterval is how often the observed selectivity is added to the row group stats in the backend.
+const searchSelectivityFlushInterval = time.Minute
+
+type selectivityRecorder interface {
+	RecordSearchSelectivity(ctx context.Context, tenantID string, selectivity float64, searches int64) error
+}
+
+type tenantSelectivity struct {
+	inspectedBytes uint64
+	searchedBytes  uint64
+	searches       int64
+}
+
+// searchSelectivity accumulates the fraction of the searched bytes of parquet blocks that block searches read, and
+// periodically records it in the row group stats of the tenant. The compactors tune the row group size to it.
+type searchSelectivity struct {
+	services.Service
+
+	mtx     sync.Mutex
+	tenants map[string]*tenantSelectivity
+
+	recorder selectivityRecorder
+}
+
+func newSearchSelectivity(recorder selectivityRecorder) *searchSelectivity {
+	s := &searchSelectivity{
+		tenants:  map[string]*tenantSelectivity{},
+		recorder: recorder,
+	}
+	s.Service = services.NewTimerService(searchSelectivityFlushInterval, nil, s.iteration, s.stopping)
+	return s
+}
+
+// observe records the bytes a search of the row groups of a block read.
+func (s *searchSelectivity) observe(meta *backend.BlockMeta, startPage, pages int, inspectedBytes uint64) {
+	if !strings.HasPrefix(meta.Version, "vParquet") || meta.TotalRecords == 0 || meta.Size_ == 0 || pages <= 0 {
+		return
+	}
+
+	// the row groups of a block are assumed to be about the same size
+	pages = min(pages, int(meta.TotalRecords)-startPage)
+	if pages <= 0 {
+		return
+	}
+	searchedBytes := meta.Size_ * uint64(pages) / uint64(meta.TotalRecords)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	t := s.tenants[meta.TenantID]
+	if t == nil {
+		t = &tenantSelectivity{}
+		s.tenants[meta.TenantID] = t
+	}
+	t.inspectedBytes += min(inspectedBytes, searchedBytes)
+	t.searchedBytes += searchedBytes
+	t.searches++
+}
+
+func (s *searchSelectivity) iteration(ctx context.Context) error {
+	s.flush(ctx)
+	return nil
+}
+
+func (s *searchSelectivity) stopping(_ error) error {
+	s.flush(context.Background())
+	return nil
+}
+
+func (s *searchSelectivity) flush(ctx context.Context) {
+	s.mtx.Lock()
+	tenants := s.tenants
+	s.tenants = map[string]*tenantSelectivity{}
+	s.mtx.Unlock()
+
+	for tenantID, t := range tenants {
+		if t.searchedBytes == 0 {
+			continue
+		}
+
+		selectivity := float64(t.inspectedBytes) / float64(t.searchedBytes)
+		if err := s.recorder.RecordSearchSelectivity(ctx, tenantID, selectivity, t.searches); err != nil {
+			level.Warn(log.Logger).Log("msg", "failed to record search selectivity", "tenant", tenantID, "err", err)
+		}
+	}
+}
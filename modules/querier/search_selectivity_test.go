@@ -0,0 +1,48 @@
+package querier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+type recordedSelectivity struct {
+	selectivity float64
+	searches    int64
+}
+
+type mockSelectivityRecorder struct {
+	recorded map[string]recordedSelectivity
+}
+
+func (m *mockSelectivityRecorder) RecordSearchSelectivity(_ context.Context, tenantID string, selectivity float64, searches int64) error {
+	m.recorded[tenantID] = recordedSelectivity{selectivity: selectivity, searches: searches}
+	return nil
+}
+
+func TestSearchSelectivity(t *testing.T) {
+	recorder := &mockSelectivityRecorder{recorded: map[string]recordedSelectivity{}}
+	s := newSearchSelectivity(recorder)
+
+	meta := &backend.BlockMeta{TenantID: "a", Version: "vParquet4", Size_: 1000, TotalRecords: 10}
+
+	// 4 row groups of 100 bytes each
+	s.observe(meta, 0, 4, 100)
+	// the last 2 row groups, the pages are limited to the row groups of the block
+	s.observe(meta, 8, 4, 200)
+	// v2 blocks don't have row groups
+	s.observe(&backend.BlockMeta{TenantID: "b", Version: "v2", Size_: 1000, TotalRecords: 10}, 0, 1, 100)
+
+	s.flush(context.Background())
+	require.Equal(t, map[string]recordedSelectivity{
+		"a": {selectivity: 0.5, searches: 2},
+	}, recorder.recorded)
+
+	// observations are only recorded once
+	recorder.recorded = map[string]recordedSelectivity{}
+	s.flush(context.Background())
+	require.Empty(t, recorder.recorded)
+}
@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"runtime/pprof"
+	"strings"
 	"sync"
 	"time"
 
@@ -13,6 +16,8 @@ import (
 	"github.com/go-kit/log/level"
 	"github.com/grafana/dskit/backoff"
 	"github.com/grafana/dskit/httpgrpc"
+	"github.com/grafana/dskit/user"
+	"github.com/grafana/tempo/pkg/api"
 	"github.com/grafana/tempo/pkg/util/httpgrpcutil"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -161,7 +166,18 @@ func (fp *frontendProcessor) runRequests(ctx context.Context, requests []*httpgr
 	return responses
 }
 
-func (fp *frontendProcessor) runRequest(ctx context.Context, request *httpgrpc.HTTPRequest) *httpgrpc.HTTPResponse {
+func (fp *frontendProcessor) runRequest(ctx context.Context, request *httpgrpc.HTTPRequest) (response *httpgrpc.HTTPResponse) {
+	// label the goroutine with the tenant and query so profiles can attribute query work to them
+	if u, err := url.Parse(request.Url); err == nil {
+		pprof.Do(ctx, api.ProfileLabels(requestTenant(request), u), func(ctx context.Context) {
+			response = fp.handleRequest(ctx, request)
+		})
+		return response
+	}
+	return fp.handleRequest(ctx, request)
+}
+
+func (fp *frontendProcessor) handleRequest(ctx context.Context, request *httpgrpc.HTTPRequest) *httpgrpc.HTTPResponse {
 	carrier := (*httpgrpcutil.HttpgrpcHeadersCarrier)(request)
 	ctx = otel.GetTextMapPropagator().Extract(ctx, carrier)
 	ctx, queueSpan := tracer.Start(ctx, "querier_processor_runRequest")
@@ -194,6 +210,16 @@ func (fp *frontendProcessor) runRequest(ctx context.Context, request *httpgrpc.H
 	return response
 }
 
+// requestTenant returns the tenant of the request from the org id header.
+func requestTenant(request *httpgrpc.HTTPRequest) string {
+	for _, h := range request.Headers {
+		if strings.EqualFold(h.Key, user.OrgIDHeaderName) && len(h.Values) > 0 {
+			return h.Values[0]
+		}
+	}
+	return ""
+}
+
 func (fp *frontendProcessor) handleSendError(err error) error {
 	if err == nil {
 		return nil
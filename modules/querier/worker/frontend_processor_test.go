@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"runtime/pprof"
 	"testing"
 	"time"
 
@@ -14,6 +15,8 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/api"
 )
 
 type RequestHandlerFunc func(context.Context, *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error)
@@ -58,6 +61,27 @@ func TestRunRequests(t *testing.T) {
 	require.Equal(t, float64(totalRequests), m.Counter.GetValue())
 }
 
+func TestRunRequestProfileLabels(t *testing.T) {
+	var tenant, endpoint string
+	handler := func(ctx context.Context, _ *httpgrpc.HTTPRequest) (*httpgrpc.HTTPResponse, error) {
+		tenant, _ = pprof.Label(ctx, api.ProfileLabelTenant)
+		endpoint, _ = pprof.Label(ctx, api.ProfileLabelEndpoint)
+		return &httpgrpc.HTTPResponse{}, nil
+	}
+
+	inf := newFrontendProcessor(Config{GRPCClientConfig: grpcclient.Config{MaxSendMsgSize: 10}}, RequestHandlerFunc(handler), log.NewNopLogger())
+	fp := inf.(*frontendProcessor)
+	// unregister metric in test avoid panic due to registering it twice in tests
+	defer prometheus.Unregister(fp.metricRequestsTotal)
+
+	fp.runRequest(context.Background(), &httpgrpc.HTTPRequest{
+		Url:     "/querier/api/traces/1234",
+		Headers: []*httpgrpc.Header{{Key: "X-Scope-OrgID", Values: []string{"foo"}}},
+	})
+	require.Equal(t, "foo", tenant)
+	require.Equal(t, api.PathTraces, endpoint)
+}
+
 func TestHandleSendError(t *testing.T) {
 	inf := newFrontendProcessor(Config{}, nil, log.NewNopLogger())
 	fp := inf.(*frontendProcessor)
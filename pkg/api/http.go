@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/tempodb"
+	"github.com/grafana/tempo/tempodb/backend"
 )
 
 const (
@@ -40,6 +41,10 @@ const (
 	urlParamSince           = "since"
 	urlParamExemplars       = "exemplars"
 
+	// search spans
+	urlParamService   = "service"
+	urlParamOperation = "operation"
+
 	// backend search querier
 	urlParamStartPage        = "startPage"
 	urlParamPagesToSearch    = "pagesToSearch"
@@ -64,6 +69,8 @@ const (
 	HeaderContentType    = "Content-Type"
 	HeaderAcceptProtobuf = "application/protobuf"
 	HeaderAcceptJSON     = "application/json"
+	HeaderCacheControl   = "Cache-Control"
+	HeaderNoStore        = "no-store"
 
 	PathPrefixQuerier   = "/querier"
 	PathPrefixGenerator = "/generator"
@@ -71,6 +78,7 @@ const (
 	PathTraces              = "/api/traces/{traceID}"
 	PathSearch              = "/api/search"
 	PathSearchTags          = "/api/search/tags"
+	PathSearchSpans         = "/api/search/spans"
 	PathSearchTagValues     = "/api/search/tag/{" + MuxVarTagName + "}/values"
 	PathEcho                = "/api/echo"
 	PathBuildInfo           = "/api/status/buildinfo"
@@ -79,9 +87,19 @@ const (
 	PathSpanMetricsSummary  = "/api/metrics/summary"
 	PathMetricsQueryInstant = "/api/metrics/query"
 	PathMetricsQueryRange   = "/api/metrics/query_range"
+	PathCacheWarming        = "/api/cache/warm"
+	PathTail                = "/api/tail"
+	PathUI                  = "/ui"
 
 	// PathOverrides user configurable overrides
 	PathOverrides = "/api/overrides"
+	// PathOverridesValidate validates a per-tenant overrides file without loading it
+	PathOverridesValidate = "/api/overrides/validate"
+	// PathOverridesAudit lists the recent changes of the user configurable overrides
+	PathOverridesAudit = "/api/overrides/audit"
+
+	// PathTombstones lists and creates requests to delete traces
+	PathTombstones = "/api/tombstones"
 
 	PathSearchTagValuesV2 = "/api/v2/search/tag/{" + MuxVarTagName + "}/values"
 	PathSearchTagsV2      = "/api/v2/search/tags"
@@ -94,9 +112,16 @@ const (
 	BlockStartKey      = "blockStart"
 	BlockEndKey        = "blockEnd"
 
+	MaxBytesPerTraceKey = "maxBytesPerTrace"
+
 	defaultLimit           = 20
 	defaultSpansPerSpanSet = 3
 	defaultSince           = 1 * time.Hour
+
+	// tags the search spans endpoint maps service and operation to. these are well-known columns in all backend
+	// block formats.
+	searchTagServiceName = "service.name"
+	searchTagSpanName    = "name"
 )
 
 func ParseTraceID(r *http.Request) ([]byte, error) {
@@ -114,6 +139,36 @@ func ParseTraceID(r *http.Request) ([]byte, error) {
 	return byteID, nil
 }
 
+// ParseMaxBytesPerTrace returns the maximum size of a trace requested by the client, or 0 if the client doesn't
+// request one.
+func ParseMaxBytesPerTrace(r *http.Request) (int, error) {
+	s, ok := extractQueryParam(r.URL.Query(), MaxBytesPerTraceKey)
+	if !ok {
+		return 0, nil
+	}
+
+	maxBytes, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid maxBytesPerTrace: %w", err)
+	}
+	if maxBytes <= 0 {
+		return 0, errors.New("invalid maxBytesPerTrace: must be a positive number")
+	}
+	return maxBytes, nil
+}
+
+// MaxBytesPerTrace returns the maximum size of a trace requested by the client bounded by the limit of the tenant.
+// A request or limit of 0 means no maximum.
+func MaxBytesPerTrace(requested, limit int) int {
+	if requested <= 0 {
+		return limit
+	}
+	if limit <= 0 {
+		return requested
+	}
+	return min(requested, limit)
+}
+
 // ParseSearchRequest takes an http.Request and decodes query params to create a tempopb.SearchRequest
 func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	req := &tempopb.SearchRequest{
@@ -245,6 +300,45 @@ func ParseSearchRequest(r *http.Request) (*tempopb.SearchRequest, error) {
 	return req, nil
 }
 
+// ParseSearchSpansRequest takes an http.Request to the search spans endpoint and decodes it into a tag based
+// tempopb.SearchRequest. Service and operation are mapped directly onto the service name and span name columns, so the
+// request is served without parsing and planning a TraceQL query. All other search parameters are supported as usual.
+func ParseSearchSpansRequest(r *http.Request) (*tempopb.SearchRequest, error) {
+	vals := r.URL.Query()
+
+	if _, ok := extractQueryParam(vals, urlParamQuery); ok {
+		return nil, errors.New("invalid request: q is not supported when searching spans")
+	}
+	if _, ok := extractQueryParam(vals, urlParamTags); ok {
+		return nil, errors.New("invalid request: tags is not supported when searching spans")
+	}
+
+	tags := map[string]string{}
+	if service, ok := extractQueryParam(vals, urlParamService); ok {
+		tags[searchTagServiceName] = service
+	}
+	if operation, ok := extractQueryParam(vals, urlParamOperation); ok {
+		tags[searchTagSpanName] = operation
+	}
+	if len(tags) == 0 {
+		return nil, errors.New("invalid request: service or operation is required")
+	}
+
+	vals.Del(urlParamService)
+	vals.Del(urlParamOperation)
+
+	u := *r.URL
+	u.RawQuery = vals.Encode()
+	req, err := ParseSearchRequest(&http.Request{URL: &u})
+	if err != nil {
+		return nil, err
+	}
+
+	// overwrite any tags picked up from the old style search parameters
+	req.Tags = tags
+	return req, nil
+}
+
 func ParseSpanMetricsRequest(r *http.Request) (*tempopb.SpanMetricsRequest, error) {
 	req := &tempopb.SpanMetricsRequest{}
 	vals := r.URL.Query()
@@ -418,6 +512,39 @@ func ParseQueryRangeRequest(r *http.Request) (*tempopb.QueryRangeRequest, error)
 	return req, nil
 }
 
+// ParseTombstoneRequest takes an http.Request and decodes query params to create a tombstone for the tenant. Traces
+// are deleted by one or more traceID params or by a TraceQL query in q with start and end.
+func ParseTombstoneRequest(r *http.Request, tenantID string) (*backend.Tombstone, error) {
+	vals := r.URL.Query()
+
+	var traceIDs []string
+	for _, v := range vals[URLParamTraceID] {
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				traceIDs = append(traceIDs, id)
+			}
+		}
+	}
+
+	query, _ := extractQueryParam(vals, urlParamQuery)
+
+	start, err := parseTimestamp(vals.Get(urlParamStart), time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid start: %w", err)
+	}
+	end, err := parseTimestamp(vals.Get(urlParamEnd), time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("invalid end: %w", err)
+	}
+
+	tombstone := backend.NewTombstone(tenantID, traceIDs, query, start, end)
+	if err := tombstone.Validate(); err != nil {
+		return nil, err
+	}
+
+	return tombstone, nil
+}
+
 func BuildQueryInstantRequest(req *http.Request, searchReq *tempopb.QueryInstantRequest) *http.Request {
 	if req == nil {
 		req = &http.Request{
@@ -276,6 +276,72 @@ func TestQuerierParseSearchRequestTagsError(t *testing.T) {
 	}
 }
 
+func TestParseSearchSpansRequest(t *testing.T) {
+	tests := []struct {
+		urlQuery string
+		expected *tempopb.SearchRequest
+		err      string
+	}{
+		{
+			urlQuery: "service=foo&operation=bar",
+			expected: &tempopb.SearchRequest{
+				Tags:            map[string]string{"service.name": "foo", "name": "bar"},
+				SpansPerSpanSet: defaultSpansPerSpanSet,
+			},
+		},
+		{
+			urlQuery: "operation=GET%20%2Fapi&start=10&end=20&limit=5&minDuration=10ms",
+			expected: &tempopb.SearchRequest{
+				Tags:            map[string]string{"name": "GET /api"},
+				Start:           10,
+				End:             20,
+				Limit:           5,
+				MinDurationMs:   10,
+				SpansPerSpanSet: defaultSpansPerSpanSet,
+			},
+		},
+		{
+			// unknown parameters are not turned into tags
+			urlQuery: "service=foo&cluster=bar",
+			expected: &tempopb.SearchRequest{
+				Tags:            map[string]string{"service.name": "foo"},
+				SpansPerSpanSet: defaultSpansPerSpanSet,
+			},
+		},
+		{
+			urlQuery: "start=10&end=20",
+			err:      "invalid request: service or operation is required",
+		},
+		{
+			urlQuery: "service=foo&q=" + url.QueryEscape("{}"),
+			err:      "invalid request: q is not supported when searching spans",
+		},
+		{
+			urlQuery: "service=foo&tags=" + url.QueryEscape("name=bar"),
+			err:      "invalid request: tags is not supported when searching spans",
+		},
+		{
+			urlQuery: "service=foo&start=20&end=10",
+			err:      "http parameter start must be before end. received start=20 end=10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.urlQuery, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://tempo"+PathSearchSpans+"?"+tt.urlQuery, nil)
+
+			searchRequest, err := ParseSearchSpansRequest(r)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, searchRequest)
+		})
+	}
+}
+
 func TestParseSearchBlockRequest(t *testing.T) {
 	tests := []struct {
 		url           string
@@ -555,6 +621,31 @@ func TestValidateAndSanitizeRequest(t *testing.T) {
 	}
 }
 
+func TestParseMaxBytesPerTrace(t *testing.T) {
+	maxBytes, err := ParseMaxBytesPerTrace(httptest.NewRequest("GET", "/api/traces/1234", nil))
+	require.NoError(t, err)
+	require.Equal(t, 0, maxBytes)
+
+	maxBytes, err = ParseMaxBytesPerTrace(httptest.NewRequest("GET", "/api/traces/1234?maxBytesPerTrace=1000", nil))
+	require.NoError(t, err)
+	require.Equal(t, 1000, maxBytes)
+
+	_, err = ParseMaxBytesPerTrace(httptest.NewRequest("GET", "/api/traces/1234?maxBytesPerTrace=foo", nil))
+	require.Error(t, err)
+
+	_, err = ParseMaxBytesPerTrace(httptest.NewRequest("GET", "/api/traces/1234?maxBytesPerTrace=0", nil))
+	require.Error(t, err)
+}
+
+func TestMaxBytesPerTrace(t *testing.T) {
+	require.Equal(t, 100, MaxBytesPerTrace(0, 100))
+	require.Equal(t, 50, MaxBytesPerTrace(50, 100))
+	// the request can't raise the limit of the tenant
+	require.Equal(t, 100, MaxBytesPerTrace(200, 100))
+	require.Equal(t, 200, MaxBytesPerTrace(200, 0))
+	require.Equal(t, 0, MaxBytesPerTrace(0, 0))
+}
+
 func TestBuildSearchRequest(t *testing.T) {
 	tests := []struct {
 		req     *tempopb.SearchRequest
@@ -638,6 +729,64 @@ func TestBuildSearchRequest(t *testing.T) {
 	}
 }
 
+func TestParseTombstoneRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		urlQuery string
+		traceIDs []string
+		query    string
+		start    time.Time
+		end      time.Time
+		err      string
+	}{
+		{
+			name:     "trace ids",
+			urlQuery: "traceID=1234,abcd&traceID=5678",
+			traceIDs: []string{"1234", "abcd", "5678"},
+		},
+		{
+			name:     "query",
+			urlQuery: "q=" + url.QueryEscape(`{ resource.service.name = "foo" }`) + "&start=1700000000&end=1700003600",
+			query:    `{ resource.service.name = "foo" }`,
+			start:    time.Unix(1700000000, 0),
+			end:      time.Unix(1700003600, 0),
+		},
+		{
+			name:     "query without bounds",
+			urlQuery: "q=" + url.QueryEscape("{ }"),
+			err:      "tombstone with a query requires a start and an end",
+		},
+		{
+			name:     "invalid start",
+			urlQuery: "traceID=1234&start=foo",
+			err:      `invalid start: strconv.ParseInt: parsing "foo": invalid syntax`,
+		},
+		{
+			name: "nothing to delete",
+			err:  "tombstone requires trace ids or a query",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/tombstones?"+tc.urlQuery, nil)
+
+			tombstone, err := ParseTombstoneRequest(r, "tenant")
+			if tc.err != "" {
+				require.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "tenant", tombstone.TenantID)
+			assert.NotEmpty(t, tombstone.ID)
+			assert.Equal(t, tc.traceIDs, tombstone.TraceIDs)
+			assert.Equal(t, tc.query, tombstone.Query)
+			assert.True(t, tc.start.Equal(tombstone.Start))
+			assert.True(t, tc.end.Equal(tombstone.End))
+		})
+	}
+}
+
 func Test_parseTimestamp(t *testing.T) {
 	now := time.Now()
 
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"hash/fnv"
+	"net/url"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+)
+
+const (
+	ProfileLabelTenant    = "tenant"
+	ProfileLabelEndpoint  = "endpoint"
+	ProfileLabelQueryHash = "query_hash"
+)
+
+// ProfileLabels returns the pprof labels of a query request so CPU and goroutine profiles can be sliced by tenant,
+// endpoint and query. The endpoint is the route of the request without the api prefix and ids. The query hash is
+// the same for all shards of a TraceQL query and is omitted for requests without a query.
+func ProfileLabels(tenant string, u *url.URL) pprof.LabelSet {
+	labels := []string{ProfileLabelTenant, tenant, ProfileLabelEndpoint, profileEndpoint(u.Path)}
+
+	if q := u.Query().Get(urlParamQuery); q != "" {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(q))
+		labels = append(labels, ProfileLabelQueryHash, strconv.FormatUint(h.Sum64(), 16))
+	}
+
+	return pprof.Labels(labels...)
+}
+
+// profileEndpoint returns the route of the path. Trace ids and tag names are replaced by their route variables to
+// keep the number of label values low.
+func profileEndpoint(path string) string {
+	if i := strings.Index(path, "/api/"); i >= 0 {
+		path = path[i:]
+	}
+
+	switch {
+	case strings.HasPrefix(path, "/api/traces/"):
+		return PathTraces
+	case strings.HasPrefix(path, "/api/v2/traces/"):
+		return PathTracesV2
+	case strings.HasPrefix(path, "/api/search/tag/") && strings.HasSuffix(path, "/values"):
+		return PathSearchTagValues
+	case strings.HasPrefix(path, "/api/v2/search/tag/") && strings.HasSuffix(path, "/values"):
+		return PathSearchTagValuesV2
+	}
+	return path
+}
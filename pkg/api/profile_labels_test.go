@@ -0,0 +1,58 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileLabels(t *testing.T) {
+	tcs := []struct {
+		url              string
+		expectedEndpoint string
+		expectQueryHash  bool
+	}{
+		{url: "/api/search?q=%7B%7D&start=1", expectedEndpoint: PathSearch, expectQueryHash: true},
+		{url: "/tempo/api/search?start=1", expectedEndpoint: PathSearch},
+		{url: "/querier/api/metrics/query_range?q=%7B%7D%7Crate()", expectedEndpoint: PathMetricsQueryRange, expectQueryHash: true},
+		{url: "/api/traces/1234", expectedEndpoint: PathTraces},
+		{url: "/querier/api/v2/traces/1234?blockStart=0", expectedEndpoint: PathTracesV2},
+		{url: "/api/search/tag/service.name/values", expectedEndpoint: PathSearchTagValues},
+		{url: "/api/v2/search/tag/span.foo/values?q=%7B%7D", expectedEndpoint: PathSearchTagValuesV2, expectQueryHash: true},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.url, func(t *testing.T) {
+			u, err := url.Parse(tc.url)
+			require.NoError(t, err)
+
+			pprof.Do(context.Background(), ProfileLabels("foo", u), func(ctx context.Context) {
+				tenant, _ := pprof.Label(ctx, ProfileLabelTenant)
+				require.Equal(t, "foo", tenant)
+
+				endpoint, _ := pprof.Label(ctx, ProfileLabelEndpoint)
+				require.Equal(t, tc.expectedEndpoint, endpoint)
+
+				_, ok := pprof.Label(ctx, ProfileLabelQueryHash)
+				require.Equal(t, tc.expectQueryHash, ok)
+			})
+		})
+	}
+
+	// shards of a query have the same hash
+	hash := func(rawURL string) string {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+
+		var h string
+		pprof.Do(context.Background(), ProfileLabels("foo", u), func(ctx context.Context) {
+			h, _ = pprof.Label(ctx, ProfileLabelQueryHash)
+		})
+		return h
+	}
+	require.Equal(t, hash("/api/search?q=%7B%7D&start=1"), hash("/querier/api/search?q=%7B%7D&start=2&blockID=1"))
+	require.NotEqual(t, hash("/api/search?q=%7B%7D"), hash("/api/search?q=%7B.foo%7D"))
+}
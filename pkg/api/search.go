@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
 )
 
 // IsSearchBlock returns true if the request appears to be for backend blocks. It is not exhaustive
@@ -18,3 +19,19 @@ func IsSearchBlock(r *http.Request) bool {
 func IsTraceQLQuery(r *tempopb.SearchRequest) bool {
 	return len(r.Query) > 0
 }
+
+// IsMostRecentSearch returns true if the request is a TraceQL query with the most_recent hint set. These searches
+// return the most recent traces instead of the first ones found.
+func IsMostRecentSearch(r *tempopb.SearchRequest) bool {
+	if !IsTraceQLQuery(r) {
+		return false
+	}
+
+	expr, err := traceql.Parse(r.Query)
+	if err != nil {
+		return false
+	}
+
+	mostRecent, _ := expr.Hints.GetBool(traceql.HintMostRecent, false)
+	return mostRecent
+}
@@ -20,6 +20,9 @@ const (
 	RoleParquetOffsetIdx Role = "parquet-offset-idx"
 	RoleFrontendSearch   Role = "frontend-search"
 	RoleParquetPage      Role = "parquet-page"
+	RoleTagValuesIndex   Role = "tag-values-index"
+
+	RoleFrontendSearchResults Role = "frontend-search-results"
 )
 
 // Provider is an object that can return a cache for a requested role
@@ -2,7 +2,6 @@ package cache
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"flag"
 	"fmt"
@@ -12,25 +11,28 @@ import (
 
 	"github.com/go-redis/redis/v8"
 
+	dstls "github.com/grafana/dskit/crypto/tls"
 	"github.com/grafana/dskit/flagext"
 )
 
 // RedisConfig defines how a RedisCache should be constructed.
 type RedisConfig struct {
-	Endpoint           string         `yaml:"endpoint"`
-	MasterName         string         `yaml:"master_name"`
-	Timeout            time.Duration  `yaml:"timeout"`
-	Expiration         time.Duration  `yaml:"expiration"`
-	DB                 int            `yaml:"db"`
-	PoolSize           int            `yaml:"pool_size"`
-	Username           string         `yaml:"username"`
-	Password           flagext.Secret `yaml:"password"`
-	SentinelUsername   string         `yaml:"sentinel_username"`
-	SentinelPassword   flagext.Secret `yaml:"sentinel_password"`
-	EnableTLS          bool           `yaml:"tls_enabled"`
-	InsecureSkipVerify bool           `yaml:"tls_insecure_skip_verify"`
-	IdleTimeout        time.Duration  `yaml:"idle_timeout"`
-	MaxConnAge         time.Duration  `yaml:"max_connection_age"`
+	Endpoint         string             `yaml:"endpoint"`
+	MasterName       string             `yaml:"master_name"`
+	Timeout          time.Duration      `yaml:"timeout"`
+	Expiration       time.Duration      `yaml:"expiration"`
+	DB               int                `yaml:"db"`
+	PoolSize         int                `yaml:"pool_size"`
+	Username         string             `yaml:"username"`
+	Password         flagext.Secret     `yaml:"password"`
+	SentinelUsername string             `yaml:"sentinel_username"`
+	SentinelPassword flagext.Secret     `yaml:"sentinel_password"`
+	EnableTLS        bool               `yaml:"tls_enabled"`
+	TLS              dstls.ClientConfig `yaml:",inline"`
+	IdleTimeout      time.Duration      `yaml:"idle_timeout"`
+	MaxConnAge       time.Duration      `yaml:"max_connection_age"`
+	ClusterMode      bool               `yaml:"cluster_mode"`
+	ReadFromReplicas bool               `yaml:"read_from_replicas"`
 }
 
 // RegisterFlagsWithPrefix adds the flags required to config this to the given FlagSet
@@ -46,9 +48,24 @@ func (cfg *RedisConfig) RegisterFlagsWithPrefix(prefix, description string, f *f
 	f.StringVar(&cfg.SentinelUsername, prefix+"redis.sentinel-username", "", description+"Username to use when connecting to redis sentinel (utilizes Redis 6+ ACL-based AUTH)")
 	f.Var(&cfg.SentinelPassword, prefix+"redis.sentinel-password", description+"Password to use when connecting to redis sentinel.")
 	f.BoolVar(&cfg.EnableTLS, prefix+"redis.tls-enabled", false, description+"Enable connecting to redis with TLS.")
-	f.BoolVar(&cfg.InsecureSkipVerify, prefix+"redis.tls-insecure-skip-verify", false, description+"Skip validating server certificate.")
+	cfg.TLS.RegisterFlagsWithPrefix(prefix+"redis.", f)
 	f.DurationVar(&cfg.IdleTimeout, prefix+"redis.idle-timeout", 0, description+"Close connections after remaining idle for this duration. If the value is zero, then idle connections are not closed.")
 	f.DurationVar(&cfg.MaxConnAge, prefix+"redis.max-connection-age", 0, description+"Close connections older than this duration. If the value is zero, then the pool does not close connections based on age.")
+	f.BoolVar(&cfg.ClusterMode, prefix+"redis.cluster-mode", false, description+"Always use a Redis Cluster client, even if a single endpoint is configured. Useful for managed Redis offerings that expose a single configuration endpoint.")
+	f.BoolVar(&cfg.ReadFromReplicas, prefix+"redis.read-from-replicas", false, description+"Route read-only commands to replica nodes. Only applies to Redis Cluster.")
+}
+
+// Validate checks the redis configuration for conflicting or invalid settings.
+func (cfg *RedisConfig) Validate() error {
+	if cfg.ClusterMode && cfg.MasterName != "" {
+		return errors.New("redis cluster mode and sentinel master name are mutually exclusive")
+	}
+	if cfg.EnableTLS {
+		if _, err := cfg.TLS.GetTLSConfig(); err != nil {
+			return fmt.Errorf("invalid redis TLS configuration: %w", err)
+		}
+	}
+	return nil
 }
 
 type RedisClient struct {
@@ -57,8 +74,10 @@ type RedisClient struct {
 	rdb        redis.UniversalClient
 }
 
-// NewRedisClient creates Redis client
-func NewRedisClient(cfg *RedisConfig) *RedisClient {
+// NewRedisClient creates Redis client. Depending on the configuration the returned client talks to a single
+// Redis server, a Redis Sentinel setup (if a master name is given) or a Redis Cluster (if multiple endpoints
+// are given or cluster mode is forced).
+func NewRedisClient(cfg *RedisConfig) (*RedisClient, error) {
 	opt := &redis.UniversalOptions{
 		Addrs:            strings.Split(cfg.Endpoint, ","),
 		MasterName:       cfg.MasterName,
@@ -70,15 +89,28 @@ func NewRedisClient(cfg *RedisConfig) *RedisClient {
 		PoolSize:         cfg.PoolSize,
 		IdleTimeout:      cfg.IdleTimeout,
 		MaxConnAge:       cfg.MaxConnAge,
+		ReadOnly:         cfg.ReadFromReplicas,
 	}
 	if cfg.EnableTLS {
-		opt.TLSConfig = &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+		tlsCfg, err := cfg.TLS.GetTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't create redis TLS configuration: %w", err)
+		}
+		opt.TLSConfig = tlsCfg
+	}
+
+	var rdb redis.UniversalClient
+	if cfg.ClusterMode {
+		rdb = redis.NewClusterClient(opt.Cluster())
+	} else {
+		rdb = redis.NewUniversalClient(opt)
 	}
+
 	return &RedisClient{
 		expiration: cfg.Expiration,
 		timeout:    cfg.Timeout,
-		rdb:        redis.NewUniversalClient(opt),
-	}
+		rdb:        rdb,
+	}, nil
 }
 
 func (c *RedisClient) Ping(ctx context.Context) error {
@@ -2,11 +2,14 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
 
 	miniredis "github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	dstls "github.com/grafana/dskit/crypto/tls"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,6 +22,10 @@ func TestRedisClient(t *testing.T) {
 	require.Nil(t, err)
 	defer cluster.Close()
 
+	forcedCluster, err := mockRedisClientForcedCluster()
+	require.Nil(t, err)
+	defer forcedCluster.Close()
+
 	ctx := context.Background()
 
 	tests := []struct {
@@ -33,6 +40,10 @@ func TestRedisClient(t *testing.T) {
 			name:   "cluster redis client",
 			client: cluster,
 		},
+		{
+			name:   "forced cluster redis client",
+			client: forcedCluster,
+		},
 	}
 
 	for _, tt := range tests {
@@ -78,7 +89,7 @@ func mockRedisClientSingle() (*RedisClient, error) {
 		}, ","),
 	}
 
-	return NewRedisClient(cfg), nil
+	return NewRedisClient(cfg)
 }
 
 func mockRedisClientCluster() (*RedisClient, error) {
@@ -101,5 +112,80 @@ func mockRedisClientCluster() (*RedisClient, error) {
 		}, ","),
 	}
 
-	return NewRedisClient(cfg), nil
+	return NewRedisClient(cfg)
+}
+
+func mockRedisClientForcedCluster() (*RedisClient, error) {
+	redisServer, err := miniredis.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &RedisConfig{
+		Expiration:  time.Minute,
+		Timeout:     100 * time.Millisecond,
+		Endpoint:    redisServer.Addr(),
+		ClusterMode: true,
+	}
+
+	client, err := NewRedisClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := client.rdb.(*redis.ClusterClient); !ok {
+		return nil, errors.New("expected a cluster client")
+	}
+
+	return client, nil
+}
+
+func TestRedisConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    RedisConfig
+		expErr bool
+	}{
+		{
+			name: "default",
+			cfg:  RedisConfig{},
+		},
+		{
+			name: "sentinel",
+			cfg:  RedisConfig{MasterName: "master", SentinelUsername: "user"},
+		},
+		{
+			name: "cluster with tls",
+			cfg:  RedisConfig{ClusterMode: true, EnableTLS: true},
+		},
+		{
+			name:   "cluster and sentinel",
+			cfg:    RedisConfig{ClusterMode: true, MasterName: "master"},
+			expErr: true,
+		},
+		{
+			name: "tls with missing key",
+			cfg: RedisConfig{
+				EnableTLS: true,
+				TLS:       dstls.ClientConfig{CertPath: "/does/not/exist"},
+			},
+			expErr: true,
+		},
+		{
+			name: "tls disabled ignores tls settings",
+			cfg: RedisConfig{
+				TLS: dstls.ClientConfig{CertPath: "/does/not/exist"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.expErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
 }
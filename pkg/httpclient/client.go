@@ -41,9 +41,11 @@ type TempoHTTPClient interface {
 	SearchTagsV2() (*tempopb.SearchTagsV2Response, error)
 	SearchTagsWithRange(start int64, end int64) (*tempopb.SearchTagsResponse, error)
 	SearchTagsV2WithRange(start int64, end int64) (*tempopb.SearchTagsV2Response, error)
+	SearchTagsV2WithQueryAndRange(query string, start int64, end int64) (*tempopb.SearchTagsV2Response, error)
 	SearchTagValues(key string) (*tempopb.SearchTagValuesResponse, error)
 	SearchTagValuesV2(key, query string) (*tempopb.SearchTagValuesV2Response, error)
 	SearchTagValuesV2WithRange(tag string, start int64, end int64) (*tempopb.SearchTagValuesV2Response, error)
+	SearchTagValuesV2WithQueryAndRange(tag string, query string, start int64, end int64) (*tempopb.SearchTagValuesV2Response, error)
 	Search(tags string) (*tempopb.SearchResponse, error)
 	SearchWithRange(tags string, start int64, end int64) (*tempopb.SearchResponse, error)
 	QueryTrace(id string) (*tempopb.Trace, error)
@@ -205,6 +207,17 @@ func (c *Client) SearchTagsV2WithRange(start int64, end int64) (*tempopb.SearchT
 	return m, nil
 }
 
+// SearchTagsV2WithQueryAndRange searches tags of spans matching the TraceQL query
+func (c *Client) SearchTagsV2WithQueryAndRange(query string, start int64, end int64) (*tempopb.SearchTagsV2Response, error) {
+	m := &tempopb.SearchTagsV2Response{}
+	_, err := c.getFor(withQuery(c.buildTagsV2QueryURL(start, end), query), m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
 func (c *Client) SearchTagValues(key string) (*tempopb.SearchTagValuesResponse, error) {
 	m := &tempopb.SearchTagValuesResponse{}
 	_, err := c.getFor(c.BaseURL+"/api/search/tag/"+key+"/values", m)
@@ -237,6 +250,17 @@ func (c *Client) SearchTagValuesV2WithRange(tag string, start int64, end int64)
 	return m, nil
 }
 
+// SearchTagValuesV2WithQueryAndRange searches values of the tag of spans matching the TraceQL query
+func (c *Client) SearchTagValuesV2WithQueryAndRange(tag string, query string, start int64, end int64) (*tempopb.SearchTagValuesV2Response, error) {
+	m := &tempopb.SearchTagValuesV2Response{}
+	_, err := c.getFor(withQuery(c.buildTagValuesV2QueryURL(tag, start, end), query), m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
 // Search Tempo. tags must be in logfmt format, that is "key1=value1 key2=value2"
 func (c *Client) Search(tags string) (*tempopb.SearchResponse, error) {
 	m := &tempopb.SearchResponse{}
@@ -410,6 +434,16 @@ func (c *Client) buildTagValuesV2QueryURL(key string, start int64, end int64) st
 	return fmt.Sprint(joinURL)
 }
 
+// withQuery adds the TraceQL query used to filter tags and tag values to the URL
+func withQuery(u string, query string) string {
+	joinURL, _ := url.Parse(u)
+	q := joinURL.Query()
+	q.Set("q", query)
+	joinURL.RawQuery = q.Encode()
+
+	return fmt.Sprint(joinURL)
+}
+
 func (c *Client) GetOverrides() (*userconfigurableoverrides.Limits, string, error) {
 	req, err := http.NewRequest("GET", c.BaseURL+tempo_api.PathOverrides, nil)
 	if err != nil {
@@ -19,6 +19,9 @@ import (
 type SegmentDecoder interface {
 	// PrepareForWrite takes a trace pointer and returns a record prepared for writing to an ingester
 	PrepareForWrite(trace *tempopb.Trace, start uint32, end uint32) ([]byte, error)
+	// PrepareForWriteTo is the same as PrepareForWrite but appends the record to buff[:0]. This allows the caller
+	//  to reuse buffers across writes. The returned slice may not share memory with buff if its capacity is too small
+	PrepareForWriteTo(buff []byte, trace *tempopb.Trace, start uint32, end uint32) ([]byte, error)
 	// PrepareForRead converts a set of segments created using PrepareForWrite. These segments
 	//  are converted into a tempopb.Trace. This operation can be quite costly and should be called only for reading
 	PrepareForRead(segments [][]byte) (*tempopb.Trace, error)
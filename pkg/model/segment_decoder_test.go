@@ -105,3 +105,35 @@ func TestSegmentDecoderFastRange(t *testing.T) {
 		})
 	}
 }
+
+func TestSegmentDecoderPrepareForWriteTo(t *testing.T) {
+	for _, e := range AllEncodings {
+		t.Run(e, func(t *testing.T) {
+			start := rand.Uint32()
+			end := rand.Uint32()
+
+			segmentDecoder, err := NewSegmentDecoder(e)
+			require.NoError(t, err)
+
+			trace := test.MakeTrace(100, nil)
+
+			expected, err := segmentDecoder.PrepareForWrite(trace, start, end)
+			require.NoError(t, err)
+
+			// a buffer that is too small is replaced
+			actual, err := segmentDecoder.PrepareForWriteTo(make([]byte, 10), trace, start, end)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+
+			// a buffer that is large enough is reused and its previous contents are overwritten
+			buff := make([]byte, len(expected)*2)
+			for i := range buff {
+				buff[i] = 0xff
+			}
+			actual, err = segmentDecoder.PrepareForWriteTo(buff, trace, start, end)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+			require.Equal(t, &buff[0], &actual[0])
+		})
+	}
+}
@@ -23,6 +23,15 @@ func (d *SegmentDecoder) PrepareForWrite(trace *tempopb.Trace, _, _ uint32) ([]b
 	return proto.Marshal(trace)
 }
 
+func (d *SegmentDecoder) PrepareForWriteTo(buff []byte, trace *tempopb.Trace, _, _ uint32) ([]byte, error) {
+	buffer := proto.NewBuffer(buff[:0])
+	err := buffer.Marshal(trace)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
 func (d *SegmentDecoder) PrepareForRead(segments [][]byte) (*tempopb.Trace, error) {
 	// each slice is a marshalled tempopb.Trace, unmarshal and combine
 	combiner := trace.NewCombiner(0, false)
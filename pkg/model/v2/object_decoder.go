@@ -93,7 +93,7 @@ func (d *ObjectDecoder) Combine(objs ...[]byte) ([]byte, error) {
 	}
 	traceBytes.Traces = append(traceBytes.Traces, bytes)
 
-	return marshalWithStartEnd(traceBytes, minStart, maxEnd)
+	return marshalWithStartEnd(nil, traceBytes, minStart, maxEnd)
 }
 
 func (d *ObjectDecoder) FastRange(buff []byte) (uint32, uint32, error) {
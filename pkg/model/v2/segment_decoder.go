@@ -24,7 +24,11 @@ func NewSegmentDecoder() *SegmentDecoder {
 }
 
 func (d *SegmentDecoder) PrepareForWrite(trace *tempopb.Trace, start uint32, end uint32) ([]byte, error) {
-	return marshalWithStartEnd(trace, start, end)
+	return marshalWithStartEnd(nil, trace, start, end)
+}
+
+func (d *SegmentDecoder) PrepareForWriteTo(buff []byte, trace *tempopb.Trace, start uint32, end uint32) ([]byte, error) {
+	return marshalWithStartEnd(buff, trace, start, end)
 }
 
 func (d *SegmentDecoder) PrepareForRead(segments [][]byte) (*tempopb.Trace, error) {
@@ -75,7 +79,7 @@ func (d *SegmentDecoder) ToObject(segments [][]byte) ([]byte, error) {
 		}
 	}
 
-	return marshalWithStartEnd(&tempopb.TraceBytes{
+	return marshalWithStartEnd(nil, &tempopb.TraceBytes{
 		Traces: segments,
 	}, minStart, maxEnd)
 }
@@ -85,11 +89,14 @@ func (d *SegmentDecoder) FastRange(buff []byte) (uint32, uint32, error) {
 	return start, end, err
 }
 
-func marshalWithStartEnd(pb proto.Message, start uint32, end uint32) ([]byte, error) {
+func marshalWithStartEnd(buff []byte, pb proto.Message, start uint32, end uint32) ([]byte, error) {
 	const uint32Size = 4
 
-	sz := proto.Size(pb)
-	buff := make([]byte, 0, sz+uint32Size*2) // proto buff size + start/end uint32s
+	sz := proto.Size(pb) + uint32Size*2 // proto buff size + start/end uint32s
+	if cap(buff) < sz {
+		buff = make([]byte, 0, sz)
+	}
+	buff = buff[:0]
 
 	buffer := proto.NewBuffer(buff)
 
@@ -797,6 +797,7 @@ type SyncIterator struct {
 	rgsMax     []RowNumber // Exclusive, row number of next one past the row group
 	readSize   int
 	filter     Predicate
+	vfilter    VectorPredicate // Set when the filter can be evaluated in batches
 
 	// Status
 	span            trace.Span
@@ -811,6 +812,7 @@ type SyncIterator struct {
 	currValues      pq.ValueReader
 	currBuf         []pq.Value
 	currBufN        int
+	currKeep        []bool // Filter result for each value in currBuf when using vfilter
 	currPageN       int
 	at              IteratorResult // Current value pointed at by iterator. Returned by call Next and SeekTo, valid until next call.
 
@@ -864,6 +866,10 @@ func NewSyncIterator(ctx context.Context, rgs []pq.RowGroup, column int, columnN
 		at:         at,
 	}
 
+	if vp, ok := vectorPredicate(filter); ok {
+		i.vfilter = vp
+	}
+
 	// Apply options
 	for _, opt := range opts {
 		opt(i)
@@ -1163,6 +1169,14 @@ func (c *SyncIterator) next() (RowNumber, *pq.Value, error) {
 				c.setPage(nil)
 				continue
 			}
+			if c.vfilter != nil {
+				// Evaluate the filter for the whole batch up front.
+				if cap(c.currKeep) < n {
+					c.currKeep = make([]bool, cap(c.currBuf))
+				}
+				c.currKeep = c.currKeep[:n]
+				c.vfilter.KeepValues(c.currBuf, c.currKeep)
+			}
 		}
 
 		// Consume current buffer until empty
@@ -1175,7 +1189,11 @@ func (c *SyncIterator) next() (RowNumber, *pq.Value, error) {
 			c.currBufN++
 			c.currPageN++
 
-			if c.filter != nil && !c.filter.KeepValue(*v) {
+			if c.vfilter != nil {
+				if !c.currKeep[c.currBufN-1] {
+					continue
+				}
+			} else if c.filter != nil && !c.filter.KeepValue(*v) {
 				continue
 			}
 
@@ -1339,6 +1357,13 @@ func (c *ColumnIterator) iterate(ctx context.Context, readSize int) {
 	rn := EmptyRowNumber()
 	buffer := make([]pq.Value, readSize)
 
+	// Evaluate the filter in batches when possible.
+	var keep []bool
+	vfilter, _ := vectorPredicate(c.filter)
+	if vfilter != nil {
+		keep = make([]bool, readSize)
+	}
+
 	keepSeeking := func(numRows int64) bool {
 		c.seekToMtx.Lock()
 		seekTo := c.seekTo
@@ -1422,6 +1447,10 @@ func (c *ColumnIterator) iterate(ctx context.Context, readSize int) {
 							// Assign row numbers, filter values, and collect the results.
 							newBuffer := columnIteratorPoolGet(readSize, 0)
 
+							if vfilter != nil {
+								vfilter.KeepValues(buffer[:count], keep)
+							}
+
 							for i := 0; i < count; i++ {
 
 								v := buffer[i]
@@ -1430,7 +1459,11 @@ func (c *ColumnIterator) iterate(ctx context.Context, readSize int) {
 								// value is excluded by the predicate)
 								rn.Next(v.RepetitionLevel(), v.DefinitionLevel())
 
-								if c.filter != nil {
+								if vfilter != nil {
+									if !keep[i] {
+										continue
+									}
+								} else if c.filter != nil {
 									if !c.filter.KeepValue(v) {
 										continue
 									}
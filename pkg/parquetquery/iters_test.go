@@ -323,6 +323,48 @@ func TestColumnIteratorExitEarly(t *testing.T) {
 	})
 }
 
+func TestColumnIteratorVectorPredicate(t *testing.T) {
+	for _, tc := range iterTestCases {
+		t.Run(tc.name, func(t *testing.T) {
+			testColumnIteratorVectorPredicate(t, tc.makeIter)
+		})
+	}
+}
+
+// testColumnIteratorVectorPredicate checks that a predicate evaluated in
+// batches returns the same results as one evaluated value by value.
+func testColumnIteratorVectorPredicate(t *testing.T, makeIter makeTestIterFn) {
+	count := 10_000
+	pf := createTestFile(t, count)
+	idx, _ := GetColumnIndexByPath(pf, "A")
+
+	collect := func(pred Predicate) []RowNumber {
+		iter := makeIter(pf, idx, pred, "A")
+		defer iter.Close()
+
+		var rows []RowNumber
+		for {
+			res, err := iter.Next()
+			require.NoError(t, err)
+			if res == nil {
+				break
+			}
+			rows = append(rows, res.RowNumber)
+		}
+		return rows
+	}
+
+	vectorized := collect(NewIntGreaterEqualPredicate(5_500))
+	perValue := collect(NewGenericPredicate(
+		func(v int64) bool { return v >= 5_500 },
+		nil,
+		func(v parquet.Value) int64 { return v.Int64() },
+	))
+
+	require.Len(t, vectorized, 4_500)
+	require.Equal(t, perValue, vectorized)
+}
+
 func BenchmarkColumnIterator(b *testing.B) {
 	for _, tc := range iterTestCases {
 		b.Run(tc.name, func(b *testing.B) {
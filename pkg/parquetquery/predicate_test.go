@@ -304,3 +304,105 @@ func BenchmarkRegexInPredicate(b *testing.B) {
 		}
 	}
 }
+
+func TestVectorPredicates(t *testing.T) {
+	ints := []parquet.Value{
+		parquet.ValueOf(int64(-1)),
+		parquet.ValueOf(int64(0)),
+		parquet.ValueOf(int64(5)),
+		parquet.ValueOf(int64(10)),
+		parquet.ValueOf(nil),
+	}
+	floats := []parquet.Value{
+		parquet.ValueOf(-1.5),
+		parquet.ValueOf(1.5),
+		parquet.ValueOf(3.0),
+	}
+	bools := []parquet.Value{
+		parquet.ValueOf(true),
+		parquet.ValueOf(false),
+	}
+	strs := []parquet.Value{
+		parquet.ValueOf("abc"),
+		parquet.ValueOf("xyz"),
+		parquet.ValueOf(""),
+	}
+
+	testCases := []struct {
+		pred VectorPredicate
+		vals []parquet.Value
+	}{
+		{NewIntEqualPredicate(5), ints},
+		{NewIntNotEqualPredicate(5), ints},
+		{NewIntGreaterPredicate(0), ints},
+		{NewIntLessEqualPredicate(5), ints},
+		{NewIntBetweenPredicate(0, 5), ints},
+		{NewFloatGreaterEqualPredicate(1.5), floats},
+		{NewBoolEqualPredicate(true), bools},
+		{NewStringEqualPredicate([]byte("abc")), strs},
+		{NewStringLessPredicate([]byte("b")), strs},
+		{NewSkipNilsPredicate(), ints},
+		{&InstrumentedPredicate{}, ints},
+		{&InstrumentedPredicate{pred: NewIntGreaterEqualPredicate(5)}, ints},
+		{&InstrumentedPredicate{pred: NewSubstringPredicate("b")}, strs},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pred.String(), func(t *testing.T) {
+			keep := make([]bool, len(tc.vals))
+			tc.pred.KeepValues(tc.vals, keep)
+
+			for i, v := range tc.vals {
+				require.Equal(t, tc.pred.KeepValue(v), keep[i], "value %d: %v", i, v)
+			}
+		})
+	}
+}
+
+func TestVectorPredicateInstrumentedCounts(t *testing.T) {
+	vals := []parquet.Value{
+		parquet.ValueOf(int64(1)),
+		parquet.ValueOf(int64(2)),
+		parquet.ValueOf(int64(3)),
+	}
+
+	p := &InstrumentedPredicate{pred: NewIntGreaterPredicate(1)}
+	p.KeepValues(vals, make([]bool, len(vals)))
+
+	require.Equal(t, int64(3), p.InspectedValues)
+	require.Equal(t, int64(2), p.KeptValues)
+
+	// Only wrapped predicates that are vectorized are evaluated in batches.
+	_, ok := vectorPredicate(p)
+	require.True(t, ok)
+	_, ok = vectorPredicate(&InstrumentedPredicate{pred: NewSubstringPredicate("b")})
+	require.False(t, ok)
+	_, ok = vectorPredicate(NewSubstringPredicate("b"))
+	require.False(t, ok)
+}
+
+func BenchmarkIntPredicate(b *testing.B) {
+	p := NewIntBetweenPredicate(100, 200)
+
+	s := make([]parquet.Value, 1000)
+	for i := 0; i < 1000; i++ {
+		s[i] = parquet.ValueOf(int64(i))
+	}
+	keep := make([]bool, len(s))
+
+	b.Run("value", func(b *testing.B) {
+		var pred Predicate = p
+		for i := 0; i < b.N; i++ {
+			for j, ss := range s {
+				keep[j] = pred.KeepValue(ss)
+			}
+		}
+	})
+
+	b.Run("vector", func(b *testing.B) {
+		var pred VectorPredicate = p
+		for i := 0; i < b.N; i++ {
+			pred.KeepValues(s, keep)
+		}
+	})
+}
@@ -10,7 +10,7 @@ import (
 	pq "github.com/parquet-go/parquet-go"
 )
 
-var _ Predicate = (*IntEqualPredicate)(nil)
+var _ VectorPredicate = (*IntEqualPredicate)(nil)
 
 type IntEqualPredicate struct {
 	value int64
@@ -61,7 +61,14 @@ func (p IntEqualPredicate) KeepValue(v pq.Value) bool {
 	return vv == p.value
 }
 
-var _ Predicate = (*IntNotEqualPredicate)(nil)
+func (p IntEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Int64()
+		keep[i] = vv == p.value
+	}
+}
+
+var _ VectorPredicate = (*IntNotEqualPredicate)(nil)
 
 type IntNotEqualPredicate struct {
 	value int64
@@ -112,7 +119,14 @@ func (p IntNotEqualPredicate) KeepValue(v pq.Value) bool {
 	return vv != p.value
 }
 
-var _ Predicate = (*IntGreaterPredicate)(nil)
+func (p IntNotEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Int64()
+		keep[i] = vv != p.value
+	}
+}
+
+var _ VectorPredicate = (*IntGreaterPredicate)(nil)
 
 type IntGreaterPredicate struct {
 	value int64
@@ -163,7 +177,14 @@ func (p IntGreaterPredicate) KeepValue(v pq.Value) bool {
 	return vv > p.value
 }
 
-var _ Predicate = (*IntGreaterEqualPredicate)(nil)
+func (p IntGreaterPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Int64()
+		keep[i] = vv > p.value
+	}
+}
+
+var _ VectorPredicate = (*IntGreaterEqualPredicate)(nil)
 
 type IntGreaterEqualPredicate struct {
 	value int64
@@ -214,7 +235,14 @@ func (p IntGreaterEqualPredicate) KeepValue(v pq.Value) bool {
 	return vv >= p.value
 }
 
-var _ Predicate = (*IntLessPredicate)(nil)
+func (p IntGreaterEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Int64()
+		keep[i] = vv >= p.value
+	}
+}
+
+var _ VectorPredicate = (*IntLessPredicate)(nil)
 
 type IntLessPredicate struct {
 	value int64
@@ -265,7 +293,14 @@ func (p IntLessPredicate) KeepValue(v pq.Value) bool {
 	return vv < p.value
 }
 
-var _ Predicate = (*IntLessEqualPredicate)(nil)
+func (p IntLessPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Int64()
+		keep[i] = vv < p.value
+	}
+}
+
+var _ VectorPredicate = (*IntLessEqualPredicate)(nil)
 
 type IntLessEqualPredicate struct {
 	value int64
@@ -316,7 +351,14 @@ func (p IntLessEqualPredicate) KeepValue(v pq.Value) bool {
 	return vv <= p.value
 }
 
-var _ Predicate = (*FloatEqualPredicate)(nil)
+func (p IntLessEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Int64()
+		keep[i] = vv <= p.value
+	}
+}
+
+var _ VectorPredicate = (*FloatEqualPredicate)(nil)
 
 type FloatEqualPredicate struct {
 	value float64
@@ -367,7 +409,14 @@ func (p FloatEqualPredicate) KeepValue(v pq.Value) bool {
 	return vv == p.value
 }
 
-var _ Predicate = (*FloatNotEqualPredicate)(nil)
+func (p FloatEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Double()
+		keep[i] = vv == p.value
+	}
+}
+
+var _ VectorPredicate = (*FloatNotEqualPredicate)(nil)
 
 type FloatNotEqualPredicate struct {
 	value float64
@@ -418,7 +467,14 @@ func (p FloatNotEqualPredicate) KeepValue(v pq.Value) bool {
 	return vv != p.value
 }
 
-var _ Predicate = (*FloatGreaterPredicate)(nil)
+func (p FloatNotEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Double()
+		keep[i] = vv != p.value
+	}
+}
+
+var _ VectorPredicate = (*FloatGreaterPredicate)(nil)
 
 type FloatGreaterPredicate struct {
 	value float64
@@ -469,7 +525,14 @@ func (p FloatGreaterPredicate) KeepValue(v pq.Value) bool {
 	return vv > p.value
 }
 
-var _ Predicate = (*FloatGreaterEqualPredicate)(nil)
+func (p FloatGreaterPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Double()
+		keep[i] = vv > p.value
+	}
+}
+
+var _ VectorPredicate = (*FloatGreaterEqualPredicate)(nil)
 
 type FloatGreaterEqualPredicate struct {
 	value float64
@@ -520,7 +583,14 @@ func (p FloatGreaterEqualPredicate) KeepValue(v pq.Value) bool {
 	return vv >= p.value
 }
 
-var _ Predicate = (*FloatLessPredicate)(nil)
+func (p FloatGreaterEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Double()
+		keep[i] = vv >= p.value
+	}
+}
+
+var _ VectorPredicate = (*FloatLessPredicate)(nil)
 
 type FloatLessPredicate struct {
 	value float64
@@ -571,7 +641,14 @@ func (p FloatLessPredicate) KeepValue(v pq.Value) bool {
 	return vv < p.value
 }
 
-var _ Predicate = (*FloatLessEqualPredicate)(nil)
+func (p FloatLessPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Double()
+		keep[i] = vv < p.value
+	}
+}
+
+var _ VectorPredicate = (*FloatLessEqualPredicate)(nil)
 
 type FloatLessEqualPredicate struct {
 	value float64
@@ -622,7 +699,14 @@ func (p FloatLessEqualPredicate) KeepValue(v pq.Value) bool {
 	return vv <= p.value
 }
 
-var _ Predicate = (*BoolEqualPredicate)(nil)
+func (p FloatLessEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Double()
+		keep[i] = vv <= p.value
+	}
+}
+
+var _ VectorPredicate = (*BoolEqualPredicate)(nil)
 
 type BoolEqualPredicate struct {
 	value bool
@@ -654,7 +738,14 @@ func (p BoolEqualPredicate) KeepValue(v pq.Value) bool {
 	return vv == p.value
 }
 
-var _ Predicate = (*BoolNotEqualPredicate)(nil)
+func (p BoolEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Boolean()
+		keep[i] = vv == p.value
+	}
+}
+
+var _ VectorPredicate = (*BoolNotEqualPredicate)(nil)
 
 type BoolNotEqualPredicate struct {
 	value bool
@@ -686,7 +777,14 @@ func (p BoolNotEqualPredicate) KeepValue(v pq.Value) bool {
 	return vv != p.value
 }
 
-var _ Predicate = (*StringEqualPredicate)(nil)
+func (p BoolNotEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Boolean()
+		keep[i] = vv != p.value
+	}
+}
+
+var _ VectorPredicate = (*StringEqualPredicate)(nil)
 
 type StringEqualPredicate struct {
 	value []byte
@@ -718,7 +816,14 @@ func (p StringEqualPredicate) KeepValue(v pq.Value) bool {
 	return bytes.Equal(vv, p.value)
 }
 
-var _ Predicate = (*StringNotEqualPredicate)(nil)
+func (p StringEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].ByteArray()
+		keep[i] = bytes.Equal(vv, p.value)
+	}
+}
+
+var _ VectorPredicate = (*StringNotEqualPredicate)(nil)
 
 type StringNotEqualPredicate struct {
 	value []byte
@@ -769,7 +874,14 @@ func (p StringNotEqualPredicate) KeepValue(v pq.Value) bool {
 	return !bytes.Equal(vv, p.value)
 }
 
-var _ Predicate = (*StringGreaterPredicate)(nil)
+func (p StringNotEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].ByteArray()
+		keep[i] = !bytes.Equal(vv, p.value)
+	}
+}
+
+var _ VectorPredicate = (*StringGreaterPredicate)(nil)
 
 type StringGreaterPredicate struct {
 	value []byte
@@ -820,7 +932,14 @@ func (p StringGreaterPredicate) KeepValue(v pq.Value) bool {
 	return bytes.Compare(vv, p.value) > 0
 }
 
-var _ Predicate = (*StringGreaterEqualPredicate)(nil)
+func (p StringGreaterPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].ByteArray()
+		keep[i] = bytes.Compare(vv, p.value) > 0
+	}
+}
+
+var _ VectorPredicate = (*StringGreaterEqualPredicate)(nil)
 
 type StringGreaterEqualPredicate struct {
 	value []byte
@@ -871,7 +990,14 @@ func (p StringGreaterEqualPredicate) KeepValue(v pq.Value) bool {
 	return bytes.Compare(vv, p.value) >= 0
 }
 
-var _ Predicate = (*StringLessPredicate)(nil)
+func (p StringGreaterEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].ByteArray()
+		keep[i] = bytes.Compare(vv, p.value) >= 0
+	}
+}
+
+var _ VectorPredicate = (*StringLessPredicate)(nil)
 
 type StringLessPredicate struct {
 	value []byte
@@ -922,7 +1048,14 @@ func (p StringLessPredicate) KeepValue(v pq.Value) bool {
 	return bytes.Compare(vv, p.value) < 0
 }
 
-var _ Predicate = (*StringLessEqualPredicate)(nil)
+func (p StringLessPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].ByteArray()
+		keep[i] = bytes.Compare(vv, p.value) < 0
+	}
+}
+
+var _ VectorPredicate = (*StringLessEqualPredicate)(nil)
 
 type StringLessEqualPredicate struct {
 	value []byte
@@ -973,7 +1106,14 @@ func (p StringLessEqualPredicate) KeepValue(v pq.Value) bool {
 	return bytes.Compare(vv, p.value) <= 0
 }
 
-var _ Predicate = (*ByteEqualPredicate)(nil)
+func (p StringLessEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].ByteArray()
+		keep[i] = bytes.Compare(vv, p.value) <= 0
+	}
+}
+
+var _ VectorPredicate = (*ByteEqualPredicate)(nil)
 
 type ByteEqualPredicate struct {
 	value []byte
@@ -1024,7 +1164,14 @@ func (p ByteEqualPredicate) KeepValue(v pq.Value) bool {
 	return bytes.Equal(bytes.TrimLeft(vv, "\x00"), p.value)
 }
 
-var _ Predicate = (*ByteNotEqualPredicate)(nil)
+func (p ByteEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].ByteArray()
+		keep[i] = bytes.Equal(bytes.TrimLeft(vv, "\x00"), p.value)
+	}
+}
+
+var _ VectorPredicate = (*ByteNotEqualPredicate)(nil)
 
 type ByteNotEqualPredicate struct {
 	value []byte
@@ -1074,3 +1221,10 @@ func (p ByteNotEqualPredicate) KeepValue(v pq.Value) bool {
 	vv := v.ByteArray()
 	return !bytes.Equal(bytes.TrimLeft(vv, "\x00"), p.value)
 }
+
+func (p ByteNotEqualPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].ByteArray()
+		keep[i] = !bytes.Equal(bytes.TrimLeft(vv, "\x00"), p.value)
+	}
+}
@@ -20,6 +20,32 @@ type Predicate interface {
 	KeepValue(pq.Value) bool
 }
 
+// VectorPredicate is implemented by predicates that can evaluate a whole
+// batch of decoded values at once. This avoids an interface call per value
+// in the iterators. KeepValues must give the same result as calling
+// KeepValue for each value and stores it in keep, which is at least as
+// long as vals.
+type VectorPredicate interface {
+	Predicate
+
+	KeepValues(vals []pq.Value, keep []bool)
+}
+
+// vectorPredicate returns the predicate as a VectorPredicate if it can be
+// evaluated in batches.
+func vectorPredicate(p Predicate) (VectorPredicate, bool) {
+	if ip, ok := p.(*InstrumentedPredicate); ok && ip.pred != nil {
+		// The instrumented predicate is only worth vectorizing when the
+		// wrapped predicate is.
+		if _, ok := ip.pred.(VectorPredicate); !ok {
+			return nil, false
+		}
+	}
+
+	vp, ok := p.(VectorPredicate)
+	return vp, ok
+}
+
 // StringInPredicate checks for any of the given strings.
 // Case sensitive exact byte matching
 type StringInPredicate struct {
@@ -204,7 +230,7 @@ type IntBetweenPredicate struct {
 	min, max int64
 }
 
-var _ Predicate = (*IntBetweenPredicate)(nil)
+var _ VectorPredicate = (*IntBetweenPredicate)(nil)
 
 func NewIntBetweenPredicate(min, max int64) *IntBetweenPredicate {
 	return &IntBetweenPredicate{min, max}
@@ -235,6 +261,13 @@ func (p *IntBetweenPredicate) KeepValue(v pq.Value) bool {
 	return p.min <= vv && vv <= p.max
 }
 
+func (p *IntBetweenPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].Int64()
+		keep[i] = p.min <= vv && vv <= p.max
+	}
+}
+
 func (p *IntBetweenPredicate) KeepPage(page pq.Page) bool {
 	if min, max, ok := page.Bounds(); ok {
 		return p.max >= min.Int64() && p.min <= max.Int64()
@@ -381,7 +414,7 @@ type InstrumentedPredicate struct {
 	KeptValues            int64
 }
 
-var _ Predicate = (*InstrumentedPredicate)(nil)
+var _ VectorPredicate = (*InstrumentedPredicate)(nil)
 
 func (p *InstrumentedPredicate) String() string {
 	if p.pred == nil {
@@ -423,6 +456,29 @@ func (p *InstrumentedPredicate) KeepValue(v pq.Value) bool {
 	return false
 }
 
+func (p *InstrumentedPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	p.InspectedValues += int64(len(vals))
+
+	switch pred := p.pred.(type) {
+	case nil:
+		for i := range vals {
+			keep[i] = true
+		}
+	case VectorPredicate:
+		pred.KeepValues(vals, keep)
+	default:
+		for i := range vals {
+			keep[i] = pred.KeepValue(vals[i])
+		}
+	}
+
+	for i := range vals {
+		if keep[i] {
+			p.KeptValues++
+		}
+	}
+}
+
 // keepDictionary inspects all values using the callback and returns if any
 // matches were found.
 func keepDictionary(dict pq.Dictionary, keepValue func(pq.Value) bool) bool {
@@ -439,7 +495,7 @@ func keepDictionary(dict pq.Dictionary, keepValue func(pq.Value) bool) bool {
 
 type SkipNilsPredicate struct{}
 
-var _ Predicate = (*SkipNilsPredicate)(nil)
+var _ VectorPredicate = (*SkipNilsPredicate)(nil)
 
 func NewSkipNilsPredicate() *SkipNilsPredicate {
 	return &SkipNilsPredicate{}
@@ -461,6 +517,12 @@ func (p *SkipNilsPredicate) KeepValue(v pq.Value) bool {
 	return !v.IsNull()
 }
 
+func (p *SkipNilsPredicate) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		keep[i] = !vals[i].IsNull()
+	}
+}
+
 type CallbackPredicate struct {
 	cb func() bool
 }
@@ -33,7 +33,7 @@ import (
 {{- $minInRange := (contains .RangeCond "min") }}
 {{- $maxInRange := (contains .RangeCond "max") }}
 
-var _ Predicate = (*{{ $structName }})(nil)
+var _ VectorPredicate = (*{{ $structName }})(nil)
 
 type {{ $structName }} struct {
 	value {{$pred.Type}}
@@ -88,6 +88,13 @@ func (p {{ $structName }}) KeepValue(v pq.Value) bool {
 	vv := v.{{ $pred.ParquetFunc }}
 	return {{ .CompareCond }}
 }
+
+func (p {{ $structName }}) KeepValues(vals []pq.Value, keep []bool) {
+	for i := range vals {
+		vv := vals[i].{{ $pred.ParquetFunc }}
+		keep[i] = {{ .CompareCond }}
+	}
+}
 {{- end }}
 {{- end }}
 `
@@ -0,0 +1,205 @@
+package ring
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/grafana/dskit/ring"
+)
+
+// tokenSpace is the size of the token space of the ring
+const tokenSpace = float64(math.MaxUint32) + 1
+
+// InstanceOwnership describes the share of the token space owned by a single instance.
+type InstanceOwnership struct {
+	ID     string `json:"id"`
+	Zone   string `json:"zone,omitempty"`
+	State  string `json:"state"`
+	Tokens int    `json:"tokens"`
+	// Ownership is the percentage of the token space owned by the instance. If the ring is zone
+	// aware this is the percentage of the token space of the zone of the instance.
+	Ownership float64 `json:"ownership"`
+	// Imbalance is the ownership divided by the ownership the instance would have if all instances
+	// owned an equal share. 1 is perfectly balanced.
+	Imbalance float64 `json:"imbalance"`
+}
+
+// OwnershipReport describes the token ownership of all instances of a ring.
+type OwnershipReport struct {
+	Ring      string              `json:"ring"`
+	ZoneAware bool                `json:"zone_aware"`
+	Instances []InstanceOwnership `json:"instances"`
+	// MaxImbalance is the imbalance of the instance owning the largest share of the ring.
+	MaxImbalance float64 `json:"max_imbalance"`
+	// Plan contains the tokens that each instance should use to evenly divide the token space. It is
+	// only filled if requested.
+	Plan map[string][]uint32 `json:"plan,omitempty"`
+}
+
+// NewOwnershipReport calculates the token ownership of the instances. If zoneAware is set each zone
+// holds a full copy of the token space and ownership is calculated per zone.
+func NewOwnershipReport(name string, instances []ring.InstanceDesc, zoneAware bool) OwnershipReport {
+	report := OwnershipReport{
+		Ring:      name,
+		ZoneAware: zoneAware,
+		Instances: make([]InstanceOwnership, 0, len(instances)),
+	}
+
+	for zone, zoneInstances := range groupByZone(instances, zoneAware) {
+		owned := tokenOwnership(zoneInstances)
+		ideal := 100 / float64(len(zoneInstances))
+
+		for _, inst := range zoneInstances {
+			o := InstanceOwnership{
+				ID:        inst.Id,
+				Zone:      zone,
+				State:     inst.State.String(),
+				Tokens:    len(inst.Tokens),
+				Ownership: owned[inst.Id] / tokenSpace * 100,
+			}
+			o.Imbalance = o.Ownership / ideal
+			report.MaxImbalance = math.Max(report.MaxImbalance, o.Imbalance)
+
+			report.Instances = append(report.Instances, o)
+		}
+	}
+
+	sort.Slice(report.Instances, func(i, j int) bool {
+		if report.Instances[i].Zone != report.Instances[j].Zone {
+			return report.Instances[i].Zone < report.Instances[j].Zone
+		}
+		return report.Instances[i].ID < report.Instances[j].ID
+	})
+
+	return report
+}
+
+// NewTokenPlan generates tokensPerInstance tokens for each of the instances so that every instance
+// owns an equal share of the token space. Tokens of the instances are interleaved in order of the
+// instance IDs. If zoneAware is set each zone is planned separately. The tokens are sorted and can
+// be written to the tokens file of an instance.
+func NewTokenPlan(instances []ring.InstanceDesc, tokensPerInstance int, zoneAware bool) (map[string][]uint32, error) {
+	if tokensPerInstance <= 0 {
+		return nil, fmt.Errorf("tokens per instance must be greater than 0")
+	}
+
+	plan := make(map[string][]uint32, len(instances))
+	for _, zoneInstances := range groupByZone(instances, zoneAware) {
+		total := len(zoneInstances) * tokensPerInstance
+		if float64(total) > tokenSpace {
+			return nil, fmt.Errorf("too many tokens: %d", total)
+		}
+		step := tokenSpace / float64(total)
+
+		for i, inst := range zoneInstances {
+			tokens := make([]uint32, 0, tokensPerInstance)
+			for j := 0; j < tokensPerInstance; j++ {
+				tokens = append(tokens, uint32(float64(j*len(zoneInstances)+i)*step))
+			}
+			plan[inst.Id] = tokens
+		}
+	}
+
+	return plan, nil
+}
+
+// groupByZone groups the instances by zone and sorts them by ID. If the ring is not zone aware, all
+// instances are returned in a single group.
+func groupByZone(instances []ring.InstanceDesc, zoneAware bool) map[string][]ring.InstanceDesc {
+	zones := map[string][]ring.InstanceDesc{}
+	for _, inst := range instances {
+		zone := ""
+		if zoneAware {
+			zone = inst.Zone
+		}
+		zones[zone] = append(zones[zone], inst)
+	}
+
+	for _, zoneInstances := range zones {
+		sort.Slice(zoneInstances, func(i, j int) bool {
+			return zoneInstances[i].Id < zoneInstances[j].Id
+		})
+	}
+
+	return zones
+}
+
+// tokenOwnership returns the size of the token ranges owned by each instance. A token owns the
+// range from the preceding token (exclusive) up to and including itself.
+func tokenOwnership(instances []ring.InstanceDesc) map[string]float64 {
+	type token struct {
+		value    uint32
+		instance string
+	}
+
+	var tokens []token
+	for _, inst := range instances {
+		for _, t := range inst.Tokens {
+			tokens = append(tokens, token{value: t, instance: inst.Id})
+		}
+	}
+
+	owned := make(map[string]float64, len(instances))
+	if len(tokens) == 0 {
+		return owned
+	}
+
+	slices.SortFunc(tokens, func(a, b token) int {
+		switch {
+		case a.value < b.value:
+			return -1
+		case a.value > b.value:
+			return 1
+		}
+		return 0
+	})
+
+	for i, t := range tokens {
+		if i == 0 {
+			// the first token also owns the range wrapping around from the last token
+			owned[t.instance] += float64(t.value) + tokenSpace - float64(tokens[len(tokens)-1].value)
+			continue
+		}
+		owned[t.instance] += float64(t.value - tokens[i-1].value)
+	}
+
+	return owned
+}
+
+// NewOwnershipHandler returns a handler that reports the token ownership of the ring as JSON. If the
+// query parameter tokens_per_instance is set, the report includes a plan that evenly divides the
+// token space. Only healthy instances are included.
+func NewOwnershipHandler(name string, r ring.ReadRing, zoneAware bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		rs, err := r.GetReplicationSetForOperation(ring.Reporting)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("failed to read ring %s: %s", name, err), http.StatusInternalServerError)
+			return
+		}
+
+		report := NewOwnershipReport(name, rs.Instances, zoneAware)
+
+		if s := req.URL.Query().Get("tokens_per_instance"); s != "" {
+			tokensPerInstance, err := strconv.Atoi(s)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid tokens_per_instance: %s", err), http.StatusBadRequest)
+				return
+			}
+			report.Plan, err = NewTokenPlan(rs.Instances, tokensPerInstance, zoneAware)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(report); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package ring
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/dskit/ring"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewOwnershipReport(t *testing.T) {
+	quarter := uint32(1 << 30)
+
+	instances := []ring.InstanceDesc{
+		{Id: "b", State: ring.ACTIVE, Tokens: []uint32{quarter, 3 * quarter}},
+		{Id: "a", State: ring.LEAVING, Tokens: []uint32{2 * quarter}},
+	}
+
+	report := NewOwnershipReport("ingester", instances, false)
+	require.Equal(t, "ingester", report.Ring)
+	require.Len(t, report.Instances, 2)
+
+	// a owns (quarter, 2*quarter], b owns the rest
+	a := report.Instances[0]
+	require.Equal(t, "a", a.ID)
+	require.Equal(t, "LEAVING", a.State)
+	require.Equal(t, 1, a.Tokens)
+	require.InDelta(t, 25, a.Ownership, 0.001)
+	require.InDelta(t, 0.5, a.Imbalance, 0.001)
+
+	b := report.Instances[1]
+	require.Equal(t, "b", b.ID)
+	require.Equal(t, 2, b.Tokens)
+	require.InDelta(t, 75, b.Ownership, 0.001)
+	require.InDelta(t, 1.5, b.Imbalance, 0.001)
+
+	require.InDelta(t, 1.5, report.MaxImbalance, 0.001)
+}
+
+func TestNewOwnershipReportZoneAware(t *testing.T) {
+	instances := []ring.InstanceDesc{
+		{Id: "a-1", Zone: "a", Tokens: []uint32{1 << 31}},
+		{Id: "a-2", Zone: "a", Tokens: []uint32{math.MaxUint32}},
+		{Id: "b-1", Zone: "b", Tokens: []uint32{1000}},
+	}
+
+	report := NewOwnershipReport("ingester", instances, true)
+	require.Len(t, report.Instances, 3)
+
+	for _, inst := range report.Instances {
+		switch inst.ID {
+		case "a-1", "a-2":
+			require.Equal(t, "a", inst.Zone)
+			require.InDelta(t, 50, inst.Ownership, 0.001)
+			require.InDelta(t, 1, inst.Imbalance, 0.001)
+		case "b-1":
+			require.Equal(t, "b", inst.Zone)
+			require.InDelta(t, 100, inst.Ownership, 0.001)
+			require.InDelta(t, 1, inst.Imbalance, 0.001)
+		}
+	}
+}
+
+func TestNewTokenPlan(t *testing.T) {
+	instances := []ring.InstanceDesc{
+		{Id: "c", Zone: "a", Tokens: []uint32{1, 2, 3}},
+		{Id: "a", Zone: "a", Tokens: []uint32{4}},
+		{Id: "b", Zone: "b", Tokens: []uint32{5, 6}},
+	}
+
+	_, err := NewTokenPlan(instances, 0, false)
+	require.Error(t, err)
+
+	for _, zoneAware := range []bool{false, true} {
+		plan, err := NewTokenPlan(instances, 128, zoneAware)
+		require.NoError(t, err)
+		require.Len(t, plan, 3)
+
+		planned := make([]ring.InstanceDesc, 0, len(instances))
+		for _, inst := range instances {
+			tokens := plan[inst.Id]
+			require.Len(t, tokens, 128)
+			require.IsIncreasing(t, tokens)
+
+			planned = append(planned, ring.InstanceDesc{Id: inst.Id, Zone: inst.Zone, Tokens: tokens})
+		}
+
+		// the planned tokens are perfectly balanced
+		report := NewOwnershipReport("ingester", planned, zoneAware)
+		require.InDelta(t, 1, report.MaxImbalance, 0.001)
+	}
+}
+
+func TestOwnershipHandler(t *testing.T) {
+	r := &mockReadRing{instances: []ring.InstanceDesc{
+		{Id: "a", Tokens: []uint32{1 << 31}},
+		{Id: "b", Tokens: []uint32{math.MaxUint32}},
+	}}
+
+	h := NewOwnershipHandler("ingester", r, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/ingester/ring/ownership?tokens_per_instance=4", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var report OwnershipReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	require.Len(t, report.Instances, 2)
+	require.InDelta(t, 1, report.MaxImbalance, 0.001)
+	require.Len(t, report.Plan["a"], 4)
+	require.Len(t, report.Plan["b"], 4)
+
+	req = httptest.NewRequest(http.MethodGet, "/ingester/ring/ownership?tokens_per_instance=foo", nil)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+type mockReadRing struct {
+	ring.ReadRing
+	instances []ring.InstanceDesc
+}
+
+func (m *mockReadRing) GetReplicationSetForOperation(ring.Operation) (ring.ReplicationSet, error) {
+	return ring.ReplicationSet{Instances: m.instances}, nil
+}
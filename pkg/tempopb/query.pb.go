@@ -0,0 +1,641 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: pkg/tempopb/query.proto
+
+package tempopb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+func init() { proto.RegisterFile("pkg/tempopb/query.proto", fileDescriptor_5b4d7c59baf175c5) }
+
+var fileDescriptor_5b4d7c59baf175c5 = []byte{
+	// 322 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2f, 0xc8, 0x4e, 0xd7,
+	0x2f, 0x49, 0xcd, 0x2d, 0xc8, 0x2f, 0x48, 0xd2, 0x2f, 0x2c, 0x4d, 0x2d, 0xaa, 0xd4, 0x2b, 0x28,
+	0xca, 0x2f, 0xc9, 0x17, 0x12, 0x80, 0x0a, 0xea, 0x41, 0x04, 0xcb, 0x0c, 0xa5, 0x50, 0x94, 0x82,
+	0x69, 0x88, 0x52, 0xa3, 0xb3, 0xac, 0x5c, 0x5c, 0x21, 0x20, 0x7e, 0x20, 0x48, 0xa9, 0x90, 0x1b,
+	0x17, 0xaf, 0x5b, 0x66, 0x5e, 0x4a, 0x48, 0x51, 0x62, 0x72, 0xaa, 0x53, 0xa5, 0xa7, 0x8b, 0x90,
+	0xa4, 0x1e, 0xcc, 0x2c, 0xb8, 0x58, 0x50, 0x6a, 0x61, 0x69, 0x6a, 0x71, 0x89, 0x94, 0x14, 0x36,
+	0xa9, 0xe2, 0x82, 0xfc, 0xbc, 0xe2, 0x54, 0x21, 0x6b, 0x2e, 0xb6, 0xe0, 0xd4, 0xc4, 0xa2, 0xe4,
+	0x0c, 0x21, 0x31, 0xb8, 0x2a, 0x88, 0x00, 0x4c, 0xb7, 0x38, 0x86, 0x38, 0x44, 0xab, 0x01, 0xa3,
+	0x90, 0x3b, 0x17, 0x17, 0x44, 0x2c, 0x24, 0x31, 0xbd, 0x58, 0x48, 0x0a, 0x4d, 0x21, 0x48, 0x10,
+	0x66, 0x88, 0x34, 0x56, 0x39, 0xb8, 0x41, 0xde, 0x5c, 0x3c, 0x08, 0xf1, 0x30, 0x23, 0xbc, 0x46,
+	0xc9, 0x62, 0x91, 0x0b, 0x33, 0x42, 0x32, 0x2c, 0x8c, 0x8b, 0x1f, 0x2e, 0x13, 0x96, 0x98, 0x53,
+	0x9a, 0x5a, 0x2c, 0x24, 0x8f, 0xa9, 0x07, 0x22, 0x03, 0x33, 0x54, 0x01, 0xb7, 0x02, 0xb8, 0xb9,
+	0x51, 0x5c, 0x82, 0x68, 0x92, 0x61, 0x46, 0x84, 0x4d, 0x56, 0xc2, 0xa5, 0x00, 0xc5, 0xcd, 0x7e,
+	0x5c, 0x82, 0xbe, 0xa9, 0x25, 0x45, 0x99, 0xc9, 0xc5, 0xe0, 0xe8, 0x0d, 0x4a, 0xcc, 0x4b, 0x4f,
+	0x45, 0x0a, 0x05, 0x84, 0x20, 0x66, 0x80, 0x22, 0xcb, 0xc1, 0xcd, 0x0b, 0xe1, 0x12, 0x46, 0x36,
+	0xcf, 0x33, 0xaf, 0xb8, 0x24, 0x31, 0xaf, 0x44, 0x48, 0x06, 0x55, 0x17, 0x54, 0x18, 0x33, 0x64,
+	0x51, 0x65, 0xe1, 0xa6, 0x7a, 0x71, 0xf1, 0x07, 0x97, 0x14, 0xa5, 0x26, 0xe6, 0x52, 0x9a, 0xec,
+	0x0c, 0x18, 0x9d, 0x14, 0x4f, 0x3c, 0x92, 0x63, 0xbc, 0xf0, 0x48, 0x8e, 0xf1, 0xc1, 0x23, 0x39,
+	0xc6, 0x09, 0x8f, 0xe5, 0x18, 0x2e, 0x3c, 0x96, 0x63, 0xb8, 0xf1, 0x58, 0x8e, 0x21, 0x8a, 0x1d,
+	0xaa, 0x2d, 0x89, 0x0d, 0x9c, 0xf2, 0x8d, 0x01, 0x03, 0x00, 0xb4, 0xd1, 0xb3, 0x90, 0x3f, 0x03,
+	0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// TempoQueryClient is the client API for TempoQuery service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TempoQueryClient interface {
+	FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error)
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (TempoQuery_SearchClient, error)
+	SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (TempoQuery_SearchTagsClient, error)
+	SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (TempoQuery_SearchTagsV2Client, error)
+	SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (TempoQuery_SearchTagValuesClient, error)
+	SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (TempoQuery_SearchTagValuesV2Client, error)
+	MetricsQueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (TempoQuery_MetricsQueryRangeClient, error)
+	MetricsQueryInstant(ctx context.Context, in *QueryInstantRequest, opts ...grpc.CallOption) (TempoQuery_MetricsQueryInstantClient, error)
+	// StreamTraceByID sends the spans of the trace in chunks as they are combined, so large traces don't
+	// have to be buffered by the query-frontend or the client.
+	StreamTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (TempoQuery_StreamTraceByIDClient, error)
+}
+
+type tempoQueryClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTempoQueryClient(cc *grpc.ClientConn) TempoQueryClient {
+	return &tempoQueryClient{cc}
+}
+
+func (c *tempoQueryClient) FindTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (*TraceByIDResponse, error) {
+	out := new(TraceByIDResponse)
+	err := c.cc.Invoke(ctx, "/tempopb.query.v1.TempoQuery/FindTraceByID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tempoQueryClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (TempoQuery_SearchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TempoQuery_serviceDesc.Streams[0], "/tempopb.query.v1.TempoQuery/Search", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tempoQuerySearchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TempoQuery_SearchClient interface {
+	Recv() (*SearchResponse, error)
+	grpc.ClientStream
+}
+
+type tempoQuerySearchClient struct {
+	grpc.ClientStream
+}
+
+func (x *tempoQuerySearchClient) Recv() (*SearchResponse, error) {
+	m := new(SearchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tempoQueryClient) SearchTags(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (TempoQuery_SearchTagsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TempoQuery_serviceDesc.Streams[1], "/tempopb.query.v1.TempoQuery/SearchTags", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tempoQuerySearchTagsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TempoQuery_SearchTagsClient interface {
+	Recv() (*SearchTagsResponse, error)
+	grpc.ClientStream
+}
+
+type tempoQuerySearchTagsClient struct {
+	grpc.ClientStream
+}
+
+func (x *tempoQuerySearchTagsClient) Recv() (*SearchTagsResponse, error) {
+	m := new(SearchTagsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tempoQueryClient) SearchTagsV2(ctx context.Context, in *SearchTagsRequest, opts ...grpc.CallOption) (TempoQuery_SearchTagsV2Client, error) {
+	stream, err := c.cc.NewStream(ctx, &_TempoQuery_serviceDesc.Streams[2], "/tempopb.query.v1.TempoQuery/SearchTagsV2", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tempoQuerySearchTagsV2Client{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TempoQuery_SearchTagsV2Client interface {
+	Recv() (*SearchTagsV2Response, error)
+	grpc.ClientStream
+}
+
+type tempoQuerySearchTagsV2Client struct {
+	grpc.ClientStream
+}
+
+func (x *tempoQuerySearchTagsV2Client) Recv() (*SearchTagsV2Response, error) {
+	m := new(SearchTagsV2Response)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tempoQueryClient) SearchTagValues(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (TempoQuery_SearchTagValuesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TempoQuery_serviceDesc.Streams[3], "/tempopb.query.v1.TempoQuery/SearchTagValues", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tempoQuerySearchTagValuesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TempoQuery_SearchTagValuesClient interface {
+	Recv() (*SearchTagValuesResponse, error)
+	grpc.ClientStream
+}
+
+type tempoQuerySearchTagValuesClient struct {
+	grpc.ClientStream
+}
+
+func (x *tempoQuerySearchTagValuesClient) Recv() (*SearchTagValuesResponse, error) {
+	m := new(SearchTagValuesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tempoQueryClient) SearchTagValuesV2(ctx context.Context, in *SearchTagValuesRequest, opts ...grpc.CallOption) (TempoQuery_SearchTagValuesV2Client, error) {
+	stream, err := c.cc.NewStream(ctx, &_TempoQuery_serviceDesc.Streams[4], "/tempopb.query.v1.TempoQuery/SearchTagValuesV2", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tempoQuerySearchTagValuesV2Client{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TempoQuery_SearchTagValuesV2Client interface {
+	Recv() (*SearchTagValuesV2Response, error)
+	grpc.ClientStream
+}
+
+type tempoQuerySearchTagValuesV2Client struct {
+	grpc.ClientStream
+}
+
+func (x *tempoQuerySearchTagValuesV2Client) Recv() (*SearchTagValuesV2Response, error) {
+	m := new(SearchTagValuesV2Response)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tempoQueryClient) MetricsQueryRange(ctx context.Context, in *QueryRangeRequest, opts ...grpc.CallOption) (TempoQuery_MetricsQueryRangeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TempoQuery_serviceDesc.Streams[5], "/tempopb.query.v1.TempoQuery/MetricsQueryRange", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tempoQueryMetricsQueryRangeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TempoQuery_MetricsQueryRangeClient interface {
+	Recv() (*QueryRangeResponse, error)
+	grpc.ClientStream
+}
+
+type tempoQueryMetricsQueryRangeClient struct {
+	grpc.ClientStream
+}
+
+func (x *tempoQueryMetricsQueryRangeClient) Recv() (*QueryRangeResponse, error) {
+	m := new(QueryRangeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tempoQueryClient) MetricsQueryInstant(ctx context.Context, in *QueryInstantRequest, opts ...grpc.CallOption) (TempoQuery_MetricsQueryInstantClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TempoQuery_serviceDesc.Streams[6], "/tempopb.query.v1.TempoQuery/MetricsQueryInstant", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tempoQueryMetricsQueryInstantClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TempoQuery_MetricsQueryInstantClient interface {
+	Recv() (*QueryInstantResponse, error)
+	grpc.ClientStream
+}
+
+type tempoQueryMetricsQueryInstantClient struct {
+	grpc.ClientStream
+}
+
+func (x *tempoQueryMetricsQueryInstantClient) Recv() (*QueryInstantResponse, error) {
+	m := new(QueryInstantResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tempoQueryClient) StreamTraceByID(ctx context.Context, in *TraceByIDRequest, opts ...grpc.CallOption) (TempoQuery_StreamTraceByIDClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_TempoQuery_serviceDesc.Streams[7], "/tempopb.query.v1.TempoQuery/StreamTraceByID", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tempoQueryStreamTraceByIDClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TempoQuery_StreamTraceByIDClient interface {
+	Recv() (*TraceByIDResponse, error)
+	grpc.ClientStream
+}
+
+type tempoQueryStreamTraceByIDClient struct {
+	grpc.ClientStream
+}
+
+func (x *tempoQueryStreamTraceByIDClient) Recv() (*TraceByIDResponse, error) {
+	m := new(TraceByIDResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TempoQueryServer is the server API for TempoQuery service.
+type TempoQueryServer interface {
+	FindTraceByID(context.Context, *TraceByIDRequest) (*TraceByIDResponse, error)
+	Search(*SearchRequest, TempoQuery_SearchServer) error
+	SearchTags(*SearchTagsRequest, TempoQuery_SearchTagsServer) error
+	SearchTagsV2(*SearchTagsRequest, TempoQuery_SearchTagsV2Server) error
+	SearchTagValues(*SearchTagValuesRequest, TempoQuery_SearchTagValuesServer) error
+	SearchTagValuesV2(*SearchTagValuesRequest, TempoQuery_SearchTagValuesV2Server) error
+	MetricsQueryRange(*QueryRangeRequest, TempoQuery_MetricsQueryRangeServer) error
+	MetricsQueryInstant(*QueryInstantRequest, TempoQuery_MetricsQueryInstantServer) error
+	// StreamTraceByID sends the spans of the trace in chunks as they are combined, so large traces don't
+	// have to be buffered by the query-frontend or the client.
+	StreamTraceByID(*TraceByIDRequest, TempoQuery_StreamTraceByIDServer) error
+}
+
+// UnimplementedTempoQueryServer can be embedded to have forward compatible implementations.
+type UnimplementedTempoQueryServer struct {
+}
+
+func (*UnimplementedTempoQueryServer) FindTraceByID(ctx context.Context, req *TraceByIDRequest) (*TraceByIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindTraceByID not implemented")
+}
+func (*UnimplementedTempoQueryServer) Search(req *SearchRequest, srv TempoQuery_SearchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (*UnimplementedTempoQueryServer) SearchTags(req *SearchTagsRequest, srv TempoQuery_SearchTagsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchTags not implemented")
+}
+func (*UnimplementedTempoQueryServer) SearchTagsV2(req *SearchTagsRequest, srv TempoQuery_SearchTagsV2Server) error {
+	return status.Errorf(codes.Unimplemented, "method SearchTagsV2 not implemented")
+}
+func (*UnimplementedTempoQueryServer) SearchTagValues(req *SearchTagValuesRequest, srv TempoQuery_SearchTagValuesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchTagValues not implemented")
+}
+func (*UnimplementedTempoQueryServer) SearchTagValuesV2(req *SearchTagValuesRequest, srv TempoQuery_SearchTagValuesV2Server) error {
+	return status.Errorf(codes.Unimplemented, "method SearchTagValuesV2 not implemented")
+}
+func (*UnimplementedTempoQueryServer) MetricsQueryRange(req *QueryRangeRequest, srv TempoQuery_MetricsQueryRangeServer) error {
+	return status.Errorf(codes.Unimplemented, "method MetricsQueryRange not implemented")
+}
+func (*UnimplementedTempoQueryServer) MetricsQueryInstant(req *QueryInstantRequest, srv TempoQuery_MetricsQueryInstantServer) error {
+	return status.Errorf(codes.Unimplemented, "method MetricsQueryInstant not implemented")
+}
+func (*UnimplementedTempoQueryServer) StreamTraceByID(req *TraceByIDRequest, srv TempoQuery_StreamTraceByIDServer) error {
+	return status.Errorf(codes.Unimplemented, "method StreamTraceByID not implemented")
+}
+
+func RegisterTempoQueryServer(s *grpc.Server, srv TempoQueryServer) {
+	s.RegisterService(&_TempoQuery_serviceDesc, srv)
+}
+
+func _TempoQuery_FindTraceByID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TraceByIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TempoQueryServer).FindTraceByID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/tempopb.query.v1.TempoQuery/FindTraceByID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TempoQueryServer).FindTraceByID(ctx, req.(*TraceByIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TempoQuery_Search_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TempoQueryServer).Search(m, &tempoQuerySearchServer{stream})
+}
+
+type TempoQuery_SearchServer interface {
+	Send(*SearchResponse) error
+	grpc.ServerStream
+}
+
+type tempoQuerySearchServer struct {
+	grpc.ServerStream
+}
+
+func (x *tempoQuerySearchServer) Send(m *SearchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TempoQuery_SearchTags_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchTagsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TempoQueryServer).SearchTags(m, &tempoQuerySearchTagsServer{stream})
+}
+
+type TempoQuery_SearchTagsServer interface {
+	Send(*SearchTagsResponse) error
+	grpc.ServerStream
+}
+
+type tempoQuerySearchTagsServer struct {
+	grpc.ServerStream
+}
+
+func (x *tempoQuerySearchTagsServer) Send(m *SearchTagsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TempoQuery_SearchTagsV2_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchTagsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TempoQueryServer).SearchTagsV2(m, &tempoQuerySearchTagsV2Server{stream})
+}
+
+type TempoQuery_SearchTagsV2Server interface {
+	Send(*SearchTagsV2Response) error
+	grpc.ServerStream
+}
+
+type tempoQuerySearchTagsV2Server struct {
+	grpc.ServerStream
+}
+
+func (x *tempoQuerySearchTagsV2Server) Send(m *SearchTagsV2Response) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TempoQuery_SearchTagValues_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchTagValuesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TempoQueryServer).SearchTagValues(m, &tempoQuerySearchTagValuesServer{stream})
+}
+
+type TempoQuery_SearchTagValuesServer interface {
+	Send(*SearchTagValuesResponse) error
+	grpc.ServerStream
+}
+
+type tempoQuerySearchTagValuesServer struct {
+	grpc.ServerStream
+}
+
+func (x *tempoQuerySearchTagValuesServer) Send(m *SearchTagValuesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TempoQuery_SearchTagValuesV2_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchTagValuesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TempoQueryServer).SearchTagValuesV2(m, &tempoQuerySearchTagValuesV2Server{stream})
+}
+
+type TempoQuery_SearchTagValuesV2Server interface {
+	Send(*SearchTagValuesV2Response) error
+	grpc.ServerStream
+}
+
+type tempoQuerySearchTagValuesV2Server struct {
+	grpc.ServerStream
+}
+
+func (x *tempoQuerySearchTagValuesV2Server) Send(m *SearchTagValuesV2Response) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TempoQuery_MetricsQueryRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TempoQueryServer).MetricsQueryRange(m, &tempoQueryMetricsQueryRangeServer{stream})
+}
+
+type TempoQuery_MetricsQueryRangeServer interface {
+	Send(*QueryRangeResponse) error
+	grpc.ServerStream
+}
+
+type tempoQueryMetricsQueryRangeServer struct {
+	grpc.ServerStream
+}
+
+func (x *tempoQueryMetricsQueryRangeServer) Send(m *QueryRangeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TempoQuery_MetricsQueryInstant_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(QueryInstantRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TempoQueryServer).MetricsQueryInstant(m, &tempoQueryMetricsQueryInstantServer{stream})
+}
+
+type TempoQuery_MetricsQueryInstantServer interface {
+	Send(*QueryInstantResponse) error
+	grpc.ServerStream
+}
+
+type tempoQueryMetricsQueryInstantServer struct {
+	grpc.ServerStream
+}
+
+func (x *tempoQueryMetricsQueryInstantServer) Send(m *QueryInstantResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TempoQuery_StreamTraceByID_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TraceByIDRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TempoQueryServer).StreamTraceByID(m, &tempoQueryStreamTraceByIDServer{stream})
+}
+
+type TempoQuery_StreamTraceByIDServer interface {
+	Send(*TraceByIDResponse) error
+	grpc.ServerStream
+}
+
+type tempoQueryStreamTraceByIDServer struct {
+	grpc.ServerStream
+}
+
+func (x *tempoQueryStreamTraceByIDServer) Send(m *TraceByIDResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _TempoQuery_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.query.v1.TempoQuery",
+	HandlerType: (*TempoQueryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "FindTraceByID",
+			Handler:    _TempoQuery_FindTraceByID_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Search",
+			Handler:       _TempoQuery_Search_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SearchTags",
+			Handler:       _TempoQuery_SearchTags_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SearchTagsV2",
+			Handler:       _TempoQuery_SearchTagsV2_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SearchTagValues",
+			Handler:       _TempoQuery_SearchTagValues_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SearchTagValuesV2",
+			Handler:       _TempoQuery_SearchTagValuesV2_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "MetricsQueryRange",
+			Handler:       _TempoQuery_MetricsQueryRange_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "MetricsQueryInstant",
+			Handler:       _TempoQuery_MetricsQueryInstant_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamTraceByID",
+			Handler:       _TempoQuery_StreamTraceByID_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/tempopb/query.proto",
+}
@@ -0,0 +1,147 @@
+// Code generated by protoc-gen-gogo. DO NOT EDIT.
+// source: pkg/tempopb/tail.proto
+
+package tempopb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/gogo/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.GoGoProtoPackageIsVersion3 // please upgrade the proto package
+
+func init() { proto.RegisterFile("pkg/tempopb/tail.proto", fileDescriptor_dadb371c889d3fdd) }
+
+var fileDescriptor_dadb371c889d3fdd = []byte{
+	// 134 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x2b, 0xc8, 0x4e, 0xd7,
+	0x2f, 0x49, 0xcd, 0x2d, 0xc8, 0x2f, 0x48, 0xd2, 0x2f, 0x49, 0xcc, 0xcc, 0xd1, 0x2b, 0x28, 0xca,
+	0x2f, 0xc9, 0x17, 0x62, 0x87, 0x8a, 0x49, 0x89, 0xa3, 0x28, 0x00, 0xd1, 0x10, 0x15, 0x46, 0x8e,
+	0x5c, 0x2c, 0x21, 0x89, 0x99, 0x39, 0x42, 0x96, 0x50, 0x5a, 0x4c, 0x0f, 0xaa, 0x4a, 0x2f, 0x38,
+	0x35, 0xb1, 0x28, 0x39, 0x23, 0x28, 0xb5, 0xb0, 0x34, 0xb5, 0xb8, 0x44, 0x4a, 0x1c, 0x43, 0xbc,
+	0xb8, 0x20, 0x3f, 0xaf, 0x38, 0xd5, 0x80, 0xd1, 0x49, 0xf1, 0xc4, 0x23, 0x39, 0xc6, 0x0b, 0x8f,
+	0xe4, 0x18, 0x1f, 0x3c, 0x92, 0x63, 0x9c, 0xf0, 0x58, 0x8e, 0xe1, 0xc2, 0x63, 0x39, 0x86, 0x1b,
+	0x8f, 0xe5, 0x18, 0xa2, 0x60, 0xd6, 0x27, 0xb1, 0x81, 0x2d, 0x33, 0x06, 0x0c, 0x00, 0x3a, 0x71,
+	0x35, 0xd9, 0xa8, 0x00, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// TailClient is the client API for Tail service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TailClient interface {
+	Tail(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (Tail_TailClient, error)
+}
+
+type tailClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewTailClient(cc *grpc.ClientConn) TailClient {
+	return &tailClient{cc}
+}
+
+func (c *tailClient) Tail(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (Tail_TailClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Tail_serviceDesc.Streams[0], "/tempopb.Tail/Tail", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tailTailClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Tail_TailClient interface {
+	Recv() (*SearchResponse, error)
+	grpc.ClientStream
+}
+
+type tailTailClient struct {
+	grpc.ClientStream
+}
+
+func (x *tailTailClient) Recv() (*SearchResponse, error) {
+	m := new(SearchResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TailServer is the server API for Tail service.
+type TailServer interface {
+	Tail(*SearchRequest, Tail_TailServer) error
+}
+
+// UnimplementedTailServer can be embedded to have forward compatible implementations.
+type UnimplementedTailServer struct {
+}
+
+func (*UnimplementedTailServer) Tail(req *SearchRequest, srv Tail_TailServer) error {
+	return status.Errorf(codes.Unimplemented, "method Tail not implemented")
+}
+
+func RegisterTailServer(s *grpc.Server, srv TailServer) {
+	s.RegisterService(&_Tail_serviceDesc, srv)
+}
+
+func _Tail_Tail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TailServer).Tail(m, &tailTailServer{stream})
+}
+
+type Tail_TailServer interface {
+	Send(*SearchResponse) error
+	grpc.ServerStream
+}
+
+type tailTailServer struct {
+	grpc.ServerStream
+}
+
+func (x *tailTailServer) Send(m *SearchResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Tail_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "tempopb.Tail",
+	HandlerType: (*TailServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Tail",
+			Handler:       _Tail_Tail_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pkg/tempopb/tail.proto",
+}
@@ -37,6 +37,8 @@ const (
 	PushErrorReason_MAX_LIVE_TRACES PushErrorReason = 1
 	PushErrorReason_TRACE_TOO_LARGE PushErrorReason = 2
 	PushErrorReason_UNKNOWN_ERROR   PushErrorReason = 3
+	// the live traces of the tenant exceed max_live_traces_bytes
+	PushErrorReason_MAX_LIVE_TRACES_BYTES PushErrorReason = 4
 )
 
 var PushErrorReason_name = map[int32]string{
@@ -44,13 +46,15 @@ var PushErrorReason_name = map[int32]string{
 	1: "MAX_LIVE_TRACES",
 	2: "TRACE_TOO_LARGE",
 	3: "UNKNOWN_ERROR",
+	4: "MAX_LIVE_TRACES_BYTES",
 }
 
 var PushErrorReason_value = map[string]int32{
-	"NO_ERROR":        0,
-	"MAX_LIVE_TRACES": 1,
-	"TRACE_TOO_LARGE": 2,
-	"UNKNOWN_ERROR":   3,
+	"NO_ERROR":              0,
+	"MAX_LIVE_TRACES":       1,
+	"TRACE_TOO_LARGE":       2,
+	"UNKNOWN_ERROR":         3,
+	"MAX_LIVE_TRACES_BYTES": 4,
 }
 
 func (x PushErrorReason) String() string {
@@ -140,6 +144,8 @@ type TraceByIDRequest struct {
 	BlockEnd          string `protobuf:"bytes,3,opt,name=blockEnd,proto3" json:"blockEnd,omitempty"`
 	QueryMode         string `protobuf:"bytes,5,opt,name=queryMode,proto3" json:"queryMode,omitempty"`
 	AllowPartialTrace bool   `protobuf:"varint,6,opt,name=allowPartialTrace,proto3" json:"allowPartialTrace,omitempty"`
+	// maximum size of the trace requested by the client. it can only lower the limit of the tenant
+	MaxBytesPerTrace uint64 `protobuf:"varint,7,opt,name=maxBytesPerTrace,proto3" json:"maxBytesPerTrace,omitempty"`
 }
 
 func (m *TraceByIDRequest) Reset()         { *m = TraceByIDRequest{} }
@@ -210,6 +216,13 @@ func (m *TraceByIDRequest) GetAllowPartialTrace() bool {
 	return false
 }
 
+func (m *TraceByIDRequest) GetMaxBytesPerTrace() uint64 {
+	if m != nil {
+		return m.MaxBytesPerTrace
+	}
+	return 0
+}
+
 type TraceByIDResponse struct {
 	Trace   *Trace                   `protobuf:"bytes,1,opt,name=trace,proto3" json:"trace,omitempty"`
 	Metrics *TraceByIDMetrics        `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
@@ -615,6 +628,10 @@ func (m *DedicatedColumn) GetType() DedicatedColumn_Type {
 type SearchResponse struct {
 	Traces  []*TraceSearchMetadata `protobuf:"bytes,1,rep,name=traces,proto3" json:"traces,omitempty"`
 	Metrics *SearchMetrics         `protobuf:"bytes,2,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// blocks that were skipped because searching them timed out. results are partial if set
+	SkippedBlocks []string `protobuf:"bytes,3,rep,name=skippedBlocks,proto3" json:"skippedBlocks,omitempty"`
+	// time and bytes spent on each searched block. only set for queries with the analyze=true hint
+	Analysis []*SearchBlockAnalysis `protobuf:"bytes,4,rep,name=analysis,proto3" json:"analysis,omitempty"`
 }
 
 func (m *SearchResponse) Reset()         { *m = SearchResponse{} }
@@ -664,6 +681,109 @@ func (m *SearchResponse) GetMetrics() *SearchMetrics {
 	return nil
 }
 
+func (m *SearchResponse) GetSkippedBlocks() []string {
+	if m != nil {
+		return m.SkippedBlocks
+	}
+	return nil
+}
+
+func (m *SearchResponse) GetAnalysis() []*SearchBlockAnalysis {
+	if m != nil {
+		return m.Analysis
+	}
+	return nil
+}
+
+// SearchBlockAnalysis is the time and bytes the search of a block took split by the stages of the search.
+type SearchBlockAnalysis struct {
+	// id of the block in the backend or in an ingester
+	BlockID        string `protobuf:"bytes,1,opt,name=blockID,proto3" json:"blockID,omitempty"`
+	StartPage      uint32 `protobuf:"varint,2,opt,name=startPage,proto3" json:"startPage,omitempty"`
+	InspectedBytes uint64 `protobuf:"varint,3,opt,name=inspectedBytes,proto3" json:"inspectedBytes,omitempty"`
+	// reading and decoding the block
+	FetchNanos uint64 `protobuf:"varint,4,opt,name=fetchNanos,proto3" json:"fetchNanos,omitempty"`
+	// evaluating the query on the fetched spansets
+	FilterNanos uint64 `protobuf:"varint,5,opt,name=filterNanos,proto3" json:"filterNanos,omitempty"`
+	// combining the matching spansets into the results
+	CombineNanos uint64 `protobuf:"varint,6,opt,name=combineNanos,proto3" json:"combineNanos,omitempty"`
+}
+
+func (m *SearchBlockAnalysis) Reset()         { *m = SearchBlockAnalysis{} }
+func (m *SearchBlockAnalysis) String() string { return proto.CompactTextString(m) }
+func (*SearchBlockAnalysis) ProtoMessage()    {}
+func (*SearchBlockAnalysis) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f22805646f4f62b6, []int{7}
+}
+func (m *SearchBlockAnalysis) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SearchBlockAnalysis) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SearchBlockAnalysis.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SearchBlockAnalysis) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SearchBlockAnalysis.Merge(m, src)
+}
+func (m *SearchBlockAnalysis) XXX_Size() int {
+	return m.Size()
+}
+func (m *SearchBlockAnalysis) XXX_DiscardUnknown() {
+	xxx_messageInfo_SearchBlockAnalysis.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SearchBlockAnalysis proto.InternalMessageInfo
+
+func (m *SearchBlockAnalysis) GetBlockID() string {
+	if m != nil {
+		return m.BlockID
+	}
+	return ""
+}
+
+func (m *SearchBlockAnalysis) GetStartPage() uint32 {
+	if m != nil {
+		return m.StartPage
+	}
+	return 0
+}
+
+func (m *SearchBlockAnalysis) GetInspectedBytes() uint64 {
+	if m != nil {
+		return m.InspectedBytes
+	}
+	return 0
+}
+
+func (m *SearchBlockAnalysis) GetFetchNanos() uint64 {
+	if m != nil {
+		return m.FetchNanos
+	}
+	return 0
+}
+
+func (m *SearchBlockAnalysis) GetFilterNanos() uint64 {
+	if m != nil {
+		return m.FilterNanos
+	}
+	return 0
+}
+
+func (m *SearchBlockAnalysis) GetCombineNanos() uint64 {
+	if m != nil {
+		return m.CombineNanos
+	}
+	return 0
+}
+
 type TraceSearchMetadata struct {
 	TraceID           string                   `protobuf:"bytes,1,opt,name=traceID,proto3" json:"traceID,omitempty"`
 	RootServiceName   string                   `protobuf:"bytes,2,opt,name=rootServiceName,proto3" json:"rootServiceName,omitempty"`
@@ -673,13 +793,15 @@ type TraceSearchMetadata struct {
 	SpanSet           *SpanSet                 `protobuf:"bytes,6,opt,name=spanSet,proto3" json:"spanSet,omitempty"`
 	SpanSets          []*SpanSet               `protobuf:"bytes,7,rep,name=spanSets,proto3" json:"spanSets,omitempty"`
 	ServiceStats      map[string]*ServiceStats `protobuf:"bytes,8,rep,name=serviceStats,proto3" json:"serviceStats,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// tenants the trace was found in. only set for multi-tenant queries
+	Tenants []string `protobuf:"bytes,9,rep,name=tenants,proto3" json:"tenants,omitempty"`
 }
 
 func (m *TraceSearchMetadata) Reset()         { *m = TraceSearchMetadata{} }
 func (m *TraceSearchMetadata) String() string { return proto.CompactTextString(m) }
 func (*TraceSearchMetadata) ProtoMessage()    {}
 func (*TraceSearchMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{7}
+	return fileDescriptor_f22805646f4f62b6, []int{8}
 }
 func (m *TraceSearchMetadata) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -764,6 +886,13 @@ func (m *TraceSearchMetadata) GetServiceStats() map[string]*ServiceStats {
 	return nil
 }
 
+func (m *TraceSearchMetadata) GetTenants() []string {
+	if m != nil {
+		return m.Tenants
+	}
+	return nil
+}
+
 type ServiceStats struct {
 	SpanCount  uint32 `protobuf:"varint,1,opt,name=spanCount,proto3" json:"spanCount,omitempty"`
 	ErrorCount uint32 `protobuf:"varint,2,opt,name=errorCount,proto3" json:"errorCount,omitempty"`
@@ -773,7 +902,7 @@ func (m *ServiceStats) Reset()         { *m = ServiceStats{} }
 func (m *ServiceStats) String() string { return proto.CompactTextString(m) }
 func (*ServiceStats) ProtoMessage()    {}
 func (*ServiceStats) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{8}
+	return fileDescriptor_f22805646f4f62b6, []int{9}
 }
 func (m *ServiceStats) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -826,7 +955,7 @@ func (m *SpanSet) Reset()         { *m = SpanSet{} }
 func (m *SpanSet) String() string { return proto.CompactTextString(m) }
 func (*SpanSet) ProtoMessage()    {}
 func (*SpanSet) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{9}
+	return fileDescriptor_f22805646f4f62b6, []int{10}
 }
 func (m *SpanSet) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -888,7 +1017,7 @@ func (m *Span) Reset()         { *m = Span{} }
 func (m *Span) String() string { return proto.CompactTextString(m) }
 func (*Span) ProtoMessage()    {}
 func (*Span) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{10}
+	return fileDescriptor_f22805646f4f62b6, []int{11}
 }
 func (m *Span) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -966,7 +1095,7 @@ func (m *SearchMetrics) Reset()         { *m = SearchMetrics{} }
 func (m *SearchMetrics) String() string { return proto.CompactTextString(m) }
 func (*SearchMetrics) ProtoMessage()    {}
 func (*SearchMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{11}
+	return fileDescriptor_f22805646f4f62b6, []int{12}
 }
 func (m *SearchMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1057,7 +1186,7 @@ func (m *SearchTagsRequest) Reset()         { *m = SearchTagsRequest{} }
 func (m *SearchTagsRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsRequest) ProtoMessage()    {}
 func (*SearchTagsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{12}
+	return fileDescriptor_f22805646f4f62b6, []int{13}
 }
 func (m *SearchTagsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1151,7 +1280,7 @@ func (m *SearchTagsBlockRequest) Reset()         { *m = SearchTagsBlockRequest{}
 func (m *SearchTagsBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsBlockRequest) ProtoMessage()    {}
 func (*SearchTagsBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{13}
+	return fileDescriptor_f22805646f4f62b6, []int{14}
 }
 func (m *SearchTagsBlockRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1297,7 +1426,7 @@ func (m *SearchTagValuesBlockRequest) Reset()         { *m = SearchTagValuesBloc
 func (m *SearchTagValuesBlockRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesBlockRequest) ProtoMessage()    {}
 func (*SearchTagValuesBlockRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{14}
+	return fileDescriptor_f22805646f4f62b6, []int{15}
 }
 func (m *SearchTagValuesBlockRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1419,7 +1548,7 @@ func (m *SearchTagsResponse) Reset()         { *m = SearchTagsResponse{} }
 func (m *SearchTagsResponse) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsResponse) ProtoMessage()    {}
 func (*SearchTagsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{15}
+	return fileDescriptor_f22805646f4f62b6, []int{16}
 }
 func (m *SearchTagsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1471,7 +1600,7 @@ func (m *SearchTagsV2Response) Reset()         { *m = SearchTagsV2Response{} }
 func (m *SearchTagsV2Response) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsV2Response) ProtoMessage()    {}
 func (*SearchTagsV2Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{16}
+	return fileDescriptor_f22805646f4f62b6, []int{17}
 }
 func (m *SearchTagsV2Response) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1523,7 +1652,7 @@ func (m *SearchTagsV2Scope) Reset()         { *m = SearchTagsV2Scope{} }
 func (m *SearchTagsV2Scope) String() string { return proto.CompactTextString(m) }
 func (*SearchTagsV2Scope) ProtoMessage()    {}
 func (*SearchTagsV2Scope) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{17}
+	return fileDescriptor_f22805646f4f62b6, []int{18}
 }
 func (m *SearchTagsV2Scope) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1579,7 +1708,7 @@ func (m *SearchTagValuesRequest) Reset()         { *m = SearchTagValuesRequest{}
 func (m *SearchTagValuesRequest) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesRequest) ProtoMessage()    {}
 func (*SearchTagValuesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{18}
+	return fileDescriptor_f22805646f4f62b6, []int{19}
 }
 func (m *SearchTagValuesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1659,7 +1788,7 @@ func (m *SearchTagValuesResponse) Reset()         { *m = SearchTagValuesResponse
 func (m *SearchTagValuesResponse) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesResponse) ProtoMessage()    {}
 func (*SearchTagValuesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{19}
+	return fileDescriptor_f22805646f4f62b6, []int{20}
 }
 func (m *SearchTagValuesResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1711,7 +1840,7 @@ func (m *TagValue) Reset()         { *m = TagValue{} }
 func (m *TagValue) String() string { return proto.CompactTextString(m) }
 func (*TagValue) ProtoMessage()    {}
 func (*TagValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{20}
+	return fileDescriptor_f22805646f4f62b6, []int{21}
 }
 func (m *TagValue) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1763,7 +1892,7 @@ func (m *SearchTagValuesV2Response) Reset()         { *m = SearchTagValuesV2Resp
 func (m *SearchTagValuesV2Response) String() string { return proto.CompactTextString(m) }
 func (*SearchTagValuesV2Response) ProtoMessage()    {}
 func (*SearchTagValuesV2Response) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{21}
+	return fileDescriptor_f22805646f4f62b6, []int{22}
 }
 func (m *SearchTagValuesV2Response) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1818,7 +1947,7 @@ func (m *MetadataMetrics) Reset()         { *m = MetadataMetrics{} }
 func (m *MetadataMetrics) String() string { return proto.CompactTextString(m) }
 func (*MetadataMetrics) ProtoMessage()    {}
 func (*MetadataMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{22}
+	return fileDescriptor_f22805646f4f62b6, []int{23}
 }
 func (m *MetadataMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1890,7 +2019,7 @@ func (m *Trace) Reset()         { *m = Trace{} }
 func (m *Trace) String() string { return proto.CompactTextString(m) }
 func (*Trace) ProtoMessage()    {}
 func (*Trace) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{23}
+	return fileDescriptor_f22805646f4f62b6, []int{24}
 }
 func (m *Trace) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1935,7 +2064,7 @@ func (m *PushResponse) Reset()         { *m = PushResponse{} }
 func (m *PushResponse) String() string { return proto.CompactTextString(m) }
 func (*PushResponse) ProtoMessage()    {}
 func (*PushResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{24}
+	return fileDescriptor_f22805646f4f62b6, []int{25}
 }
 func (m *PushResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1973,7 +2102,8 @@ func (m *PushResponse) GetErrorsByTrace() []PushErrorReason {
 
 // PushBytesRequest pushes slices of traces, ids and searchdata. Traces are
 // encoded using the
-//  current BatchDecoder in ./pkg/model
+//
+//	current BatchDecoder in ./pkg/model
 type PushBytesRequest struct {
 	// pre-marshalled Traces. length must match ids
 	Traces []PreallocBytes `protobuf:"bytes,2,rep,name=traces,proto3,customtype=PreallocBytes" json:"traces"`
@@ -1985,7 +2115,7 @@ func (m *PushBytesRequest) Reset()         { *m = PushBytesRequest{} }
 func (m *PushBytesRequest) String() string { return proto.CompactTextString(m) }
 func (*PushBytesRequest) ProtoMessage()    {}
 func (*PushBytesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{25}
+	return fileDescriptor_f22805646f4f62b6, []int{26}
 }
 func (m *PushBytesRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2030,7 +2160,7 @@ func (m *PushSpansRequest) Reset()         { *m = PushSpansRequest{} }
 func (m *PushSpansRequest) String() string { return proto.CompactTextString(m) }
 func (*PushSpansRequest) ProtoMessage()    {}
 func (*PushSpansRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{26}
+	return fileDescriptor_f22805646f4f62b6, []int{27}
 }
 func (m *PushSpansRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2075,7 +2205,7 @@ func (m *TraceBytes) Reset()         { *m = TraceBytes{} }
 func (m *TraceBytes) String() string { return proto.CompactTextString(m) }
 func (*TraceBytes) ProtoMessage()    {}
 func (*TraceBytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{27}
+	return fileDescriptor_f22805646f4f62b6, []int{28}
 }
 func (m *TraceBytes) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2123,7 +2253,7 @@ func (m *LinkSlice) Reset()         { *m = LinkSlice{} }
 func (m *LinkSlice) String() string { return proto.CompactTextString(m) }
 func (*LinkSlice) ProtoMessage()    {}
 func (*LinkSlice) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{28}
+	return fileDescriptor_f22805646f4f62b6, []int{29}
 }
 func (m *LinkSlice) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2171,7 +2301,7 @@ func (m *SpanMetricsRequest) Reset()         { *m = SpanMetricsRequest{} }
 func (m *SpanMetricsRequest) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsRequest) ProtoMessage()    {}
 func (*SpanMetricsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{29}
+	return fileDescriptor_f22805646f4f62b6, []int{30}
 }
 func (m *SpanMetricsRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2247,7 +2377,7 @@ func (m *SpanMetricsSummaryRequest) Reset()         { *m = SpanMetricsSummaryReq
 func (m *SpanMetricsSummaryRequest) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummaryRequest) ProtoMessage()    {}
 func (*SpanMetricsSummaryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{30}
+	return fileDescriptor_f22805646f4f62b6, []int{31}
 }
 func (m *SpanMetricsSummaryRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2322,7 +2452,7 @@ func (m *SpanMetricsResponse) Reset()         { *m = SpanMetricsResponse{} }
 func (m *SpanMetricsResponse) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsResponse) ProtoMessage()    {}
 func (*SpanMetricsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{31}
+	return fileDescriptor_f22805646f4f62b6, []int{32}
 }
 func (m *SpanMetricsResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2388,7 +2518,7 @@ func (m *RawHistogram) Reset()         { *m = RawHistogram{} }
 func (m *RawHistogram) String() string { return proto.CompactTextString(m) }
 func (*RawHistogram) ProtoMessage()    {}
 func (*RawHistogram) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{32}
+	return fileDescriptor_f22805646f4f62b6, []int{33}
 }
 func (m *RawHistogram) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2440,7 +2570,7 @@ func (m *KeyValue) Reset()         { *m = KeyValue{} }
 func (m *KeyValue) String() string { return proto.CompactTextString(m) }
 func (*KeyValue) ProtoMessage()    {}
 func (*KeyValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{33}
+	return fileDescriptor_f22805646f4f62b6, []int{34}
 }
 func (m *KeyValue) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2493,7 +2623,7 @@ func (m *SpanMetrics) Reset()         { *m = SpanMetrics{} }
 func (m *SpanMetrics) String() string { return proto.CompactTextString(m) }
 func (*SpanMetrics) ProtoMessage()    {}
 func (*SpanMetrics) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{34}
+	return fileDescriptor_f22805646f4f62b6, []int{35}
 }
 func (m *SpanMetrics) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2557,7 +2687,7 @@ func (m *SpanMetricsSummary) Reset()         { *m = SpanMetricsSummary{} }
 func (m *SpanMetricsSummary) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummary) ProtoMessage()    {}
 func (*SpanMetricsSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{35}
+	return fileDescriptor_f22805646f4f62b6, []int{36}
 }
 func (m *SpanMetricsSummary) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2643,7 +2773,7 @@ func (m *SpanMetricsSummaryResponse) Reset()         { *m = SpanMetricsSummaryRe
 func (m *SpanMetricsSummaryResponse) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsSummaryResponse) ProtoMessage()    {}
 func (*SpanMetricsSummaryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{36}
+	return fileDescriptor_f22805646f4f62b6, []int{37}
 }
 func (m *SpanMetricsSummaryResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2694,7 +2824,7 @@ func (m *TraceQLStatic) Reset()         { *m = TraceQLStatic{} }
 func (m *TraceQLStatic) String() string { return proto.CompactTextString(m) }
 func (*TraceQLStatic) ProtoMessage()    {}
 func (*TraceQLStatic) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{37}
+	return fileDescriptor_f22805646f4f62b6, []int{38}
 }
 func (m *TraceQLStatic) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2788,7 +2918,7 @@ func (m *SpanMetricsData) Reset()         { *m = SpanMetricsData{} }
 func (m *SpanMetricsData) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsData) ProtoMessage()    {}
 func (*SpanMetricsData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{38}
+	return fileDescriptor_f22805646f4f62b6, []int{39}
 }
 func (m *SpanMetricsData) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2841,7 +2971,7 @@ func (m *SpanMetricsResult) Reset()         { *m = SpanMetricsResult{} }
 func (m *SpanMetricsResult) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsResult) ProtoMessage()    {}
 func (*SpanMetricsResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{39}
+	return fileDescriptor_f22805646f4f62b6, []int{40}
 }
 func (m *SpanMetricsResult) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2902,7 +3032,7 @@ func (m *SpanMetricsResultPoint) Reset()         { *m = SpanMetricsResultPoint{}
 func (m *SpanMetricsResultPoint) String() string { return proto.CompactTextString(m) }
 func (*SpanMetricsResultPoint) ProtoMessage()    {}
 func (*SpanMetricsResultPoint) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{40}
+	return fileDescriptor_f22805646f4f62b6, []int{41}
 }
 func (m *SpanMetricsResultPoint) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -2969,7 +3099,7 @@ func (m *QueryInstantRequest) Reset()         { *m = QueryInstantRequest{} }
 func (m *QueryInstantRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryInstantRequest) ProtoMessage()    {}
 func (*QueryInstantRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{41}
+	return fileDescriptor_f22805646f4f62b6, []int{42}
 }
 func (m *QueryInstantRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3028,7 +3158,7 @@ func (m *QueryInstantResponse) Reset()         { *m = QueryInstantResponse{} }
 func (m *QueryInstantResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryInstantResponse) ProtoMessage()    {}
 func (*QueryInstantResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{42}
+	return fileDescriptor_f22805646f4f62b6, []int{43}
 }
 func (m *QueryInstantResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3084,7 +3214,7 @@ func (m *InstantSeries) Reset()         { *m = InstantSeries{} }
 func (m *InstantSeries) String() string { return proto.CompactTextString(m) }
 func (*InstantSeries) ProtoMessage()    {}
 func (*InstantSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{43}
+	return fileDescriptor_f22805646f4f62b6, []int{44}
 }
 func (m *InstantSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3159,7 +3289,7 @@ func (m *QueryRangeRequest) Reset()         { *m = QueryRangeRequest{} }
 func (m *QueryRangeRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryRangeRequest) ProtoMessage()    {}
 func (*QueryRangeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{44}
+	return fileDescriptor_f22805646f4f62b6, []int{45}
 }
 func (m *QueryRangeRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3295,7 +3425,7 @@ func (m *QueryRangeResponse) Reset()         { *m = QueryRangeResponse{} }
 func (m *QueryRangeResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryRangeResponse) ProtoMessage()    {}
 func (*QueryRangeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{45}
+	return fileDescriptor_f22805646f4f62b6, []int{46}
 }
 func (m *QueryRangeResponse) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3349,7 +3479,7 @@ func (m *Exemplar) Reset()         { *m = Exemplar{} }
 func (m *Exemplar) String() string { return proto.CompactTextString(m) }
 func (*Exemplar) ProtoMessage()    {}
 func (*Exemplar) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{46}
+	return fileDescriptor_f22805646f4f62b6, []int{47}
 }
 func (m *Exemplar) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3409,7 +3539,7 @@ func (m *Sample) Reset()         { *m = Sample{} }
 func (m *Sample) String() string { return proto.CompactTextString(m) }
 func (*Sample) ProtoMessage()    {}
 func (*Sample) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{47}
+	return fileDescriptor_f22805646f4f62b6, []int{48}
 }
 func (m *Sample) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3469,7 +3599,7 @@ func (m *TimeSeries) Reset()         { *m = TimeSeries{} }
 func (m *TimeSeries) String() string { return proto.CompactTextString(m) }
 func (*TimeSeries) ProtoMessage()    {}
 func (*TimeSeries) Descriptor() ([]byte, []int) {
-	return fileDescriptor_f22805646f4f62b6, []int{48}
+	return fileDescriptor_f22805646f4f62b6, []int{49}
 }
 func (m *TimeSeries) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -3539,6 +3669,7 @@ func init() {
 	proto.RegisterType((*SearchBlockRequest)(nil), "tempopb.SearchBlockRequest")
 	proto.RegisterType((*DedicatedColumn)(nil), "tempopb.DedicatedColumn")
 	proto.RegisterType((*SearchResponse)(nil), "tempopb.SearchResponse")
+	proto.RegisterType((*SearchBlockAnalysis)(nil), "tempopb.SearchBlockAnalysis")
 	proto.RegisterType((*TraceSearchMetadata)(nil), "tempopb.TraceSearchMetadata")
 	proto.RegisterMapType((map[string]*ServiceStats)(nil), "tempopb.TraceSearchMetadata.ServiceStatsEntry")
 	proto.RegisterType((*ServiceStats)(nil), "tempopb.ServiceStats")
@@ -3587,191 +3718,199 @@ func init() {
 func init() { proto.RegisterFile("pkg/tempopb/tempo.proto", fileDescriptor_f22805646f4f62b6) }
 
 var fileDescriptor_f22805646f4f62b6 = []byte{
-	// 2935 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x3a, 0x4b, 0x6f, 0x23, 0xc7,
-	0xd1, 0x1a, 0xf1, 0xa9, 0x22, 0x29, 0x51, 0x2d, 0x59, 0xe6, 0x72, 0xd7, 0x92, 0x3c, 0x5e, 0x18,
-	0xfa, 0xfc, 0xa0, 0xb4, 0xf4, 0x1a, 0x9f, 0xd7, 0x4e, 0x1c, 0x48, 0x2b, 0x66, 0x2d, 0x5b, 0x2f,
-	0x37, 0x69, 0xd9, 0x08, 0x0c, 0x08, 0x23, 0xb2, 0x97, 0x3b, 0x10, 0x39, 0x43, 0xcf, 0x0c, 0x65,
-	0x29, 0x07, 0x23, 0x09, 0x90, 0x43, 0x80, 0x1c, 0x72, 0x48, 0x0e, 0xf9, 0x05, 0x41, 0x72, 0xc9,
-	0x21, 0xf9, 0x07, 0x41, 0x0c, 0x07, 0x41, 0x02, 0x03, 0xb9, 0x18, 0x39, 0x18, 0x81, 0x7d, 0x48,
-	0x7e, 0x46, 0xd0, 0xd5, 0xdd, 0xf3, 0xa6, 0xe4, 0xb5, 0xd7, 0x88, 0x0f, 0x3e, 0xb1, 0xab, 0xba,
-	0xba, 0xba, 0xba, 0xba, 0x9e, 0x3d, 0x84, 0xc7, 0x47, 0xa7, 0xfd, 0x75, 0x8f, 0x0d, 0x47, 0xf6,
-	0xe8, 0x44, 0xfc, 0x36, 0x46, 0x8e, 0xed, 0xd9, 0xa4, 0x20, 0x91, 0xf5, 0xa5, 0xae, 0x3d, 0x1c,
-	0xda, 0xd6, 0xfa, 0xd9, 0xad, 0x75, 0x31, 0x12, 0x04, 0xf5, 0xe7, 0xfb, 0xa6, 0xf7, 0x60, 0x7c,
-	0xd2, 0xe8, 0xda, 0xc3, 0xf5, 0xbe, 0xdd, 0xb7, 0xd7, 0x11, 0x7d, 0x32, 0xbe, 0x8f, 0x10, 0x02,
-	0x38, 0x92, 0xe4, 0x8b, 0x9e, 0x63, 0x74, 0x19, 0xe7, 0x82, 0x03, 0x81, 0xd5, 0xff, 0xa8, 0x41,
-	0xb5, 0xc3, 0xe1, 0xad, 0x8b, 0x9d, 0x6d, 0xca, 0xde, 0x1b, 0x33, 0xd7, 0x23, 0x35, 0x28, 0x20,
-	0xcd, 0xce, 0x76, 0x4d, 0x5b, 0xd5, 0xd6, 0xca, 0x54, 0x81, 0x64, 0x19, 0xe0, 0x64, 0x60, 0x77,
-	0x4f, 0xdb, 0x9e, 0xe1, 0x78, 0xb5, 0xe9, 0x55, 0x6d, 0x6d, 0x86, 0x86, 0x30, 0xa4, 0x0e, 0x45,
-	0x84, 0x5a, 0x56, 0xaf, 0x96, 0xc1, 0x59, 0x1f, 0x26, 0x37, 0x60, 0xe6, 0xbd, 0x31, 0x73, 0x2e,
-	0xf6, 0xec, 0x1e, 0xab, 0xe5, 0x70, 0x32, 0x40, 0x90, 0xe7, 0x60, 0xde, 0x18, 0x0c, 0xec, 0xf7,
-	0x0f, 0x0d, 0xc7, 0x33, 0x8d, 0x01, 0xca, 0x54, 0xcb, 0xaf, 0x6a, 0x6b, 0x45, 0x9a, 0x9c, 0xd0,
-	0xff, 0xa3, 0xc1, 0x7c, 0x48, 0x6c, 0x77, 0x64, 0x5b, 0x2e, 0x23, 0x37, 0x21, 0x87, 0x82, 0xa2,
-	0xd4, 0xa5, 0xe6, 0x6c, 0x43, 0xaa, 0xb0, 0x81, 0xa4, 0x54, 0x4c, 0x92, 0x17, 0xa0, 0x30, 0x64,
-	0x9e, 0x63, 0x76, 0x5d, 0x3c, 0x40, 0xa9, 0x79, 0x2d, 0x4a, 0xc7, 0x59, 0xee, 0x09, 0x02, 0xaa,
-	0x28, 0xc9, 0x1d, 0xc8, 0xbb, 0x9e, 0xe1, 0x8d, 0x5d, 0x3c, 0xd6, 0x6c, 0xf3, 0xc9, 0xe4, 0x1a,
-	0x25, 0x46, 0xa3, 0x8d, 0x84, 0x54, 0x2e, 0xe0, 0xda, 0x1c, 0x32, 0xd7, 0x35, 0xfa, 0xac, 0x96,
-	0xc5, 0x53, 0x2b, 0x50, 0x7f, 0x0a, 0xf2, 0x82, 0x96, 0x94, 0xa1, 0x78, 0xf7, 0x60, 0xef, 0x70,
-	0xb7, 0xd5, 0x69, 0x55, 0xa7, 0x48, 0x09, 0x0a, 0x87, 0x9b, 0xb4, 0xb3, 0xb3, 0xb9, 0x5b, 0xd5,
-	0x74, 0x12, 0xba, 0x20, 0x29, 0x96, 0xfe, 0xf7, 0x69, 0xa8, 0xb4, 0x99, 0xe1, 0x74, 0x1f, 0xa8,
-	0x2b, 0x7b, 0x19, 0xb2, 0x1d, 0xa3, 0xef, 0xd6, 0xb4, 0xd5, 0xcc, 0x5a, 0xa9, 0xb9, 0xea, 0x4b,
-	0x17, 0xa1, 0x6a, 0x70, 0x92, 0x96, 0xe5, 0x39, 0x17, 0x5b, 0xd9, 0x8f, 0x3e, 0x5d, 0x99, 0xa2,
-	0xb8, 0x86, 0xdc, 0x84, 0xca, 0x9e, 0x69, 0x6d, 0x8f, 0x1d, 0xc3, 0x33, 0x6d, 0x6b, 0x4f, 0xa8,
-	0xa5, 0x42, 0xa3, 0x48, 0xa4, 0x32, 0xce, 0x43, 0x54, 0x19, 0x49, 0x15, 0x46, 0x92, 0x45, 0xc8,
-	0xed, 0x9a, 0x43, 0xd3, 0xc3, 0xa3, 0x56, 0xa8, 0x00, 0x38, 0xd6, 0x45, 0x8b, 0xc9, 0x09, 0x2c,
-	0x02, 0xa4, 0x0a, 0x19, 0x66, 0xf5, 0xf0, 0x92, 0x2b, 0x94, 0x0f, 0x39, 0xdd, 0x9b, 0xdc, 0x22,
-	0x6a, 0x45, 0x54, 0x94, 0x00, 0xc8, 0x1a, 0xcc, 0xb5, 0x47, 0x86, 0xe5, 0x1e, 0x32, 0x87, 0xff,
-	0xb6, 0x99, 0x57, 0x9b, 0xc1, 0x35, 0x71, 0x74, 0xfd, 0xff, 0x61, 0xc6, 0x3f, 0x22, 0x67, 0x7f,
-	0xca, 0x2e, 0xd0, 0x16, 0x66, 0x28, 0x1f, 0x72, 0xf6, 0x67, 0xc6, 0x60, 0xcc, 0xa4, 0xe1, 0x0a,
-	0xe0, 0xe5, 0xe9, 0x97, 0x34, 0xfd, 0xc3, 0x0c, 0x10, 0xa1, 0xaa, 0x2d, 0x6e, 0xae, 0x4a, 0xab,
-	0xb7, 0x61, 0xc6, 0x55, 0x0a, 0x94, 0x46, 0xb5, 0x94, 0xae, 0x5a, 0x1a, 0x10, 0xf2, 0x0b, 0x47,
-	0xa3, 0xdf, 0xd9, 0x96, 0x1b, 0x29, 0x90, 0xbb, 0x00, 0x1e, 0xfd, 0x90, 0x1b, 0x83, 0xd0, 0x5f,
-	0x80, 0xe0, 0x1a, 0x1e, 0x19, 0x7d, 0xe6, 0x76, 0x6c, 0xc1, 0x5a, 0xea, 0x30, 0x8a, 0xe4, 0x2e,
-	0xc6, 0xac, 0xae, 0xdd, 0x33, 0xad, 0xbe, 0xf4, 0x22, 0x1f, 0xe6, 0x1c, 0x4c, 0xab, 0xc7, 0xce,
-	0x39, 0xbb, 0xb6, 0xf9, 0x43, 0x26, 0x75, 0x1b, 0x45, 0x12, 0x1d, 0xca, 0x9e, 0xed, 0x19, 0x03,
-	0xca, 0xba, 0xb6, 0xd3, 0x73, 0x6b, 0x05, 0x24, 0x8a, 0xe0, 0x38, 0x4d, 0xcf, 0xf0, 0x8c, 0x96,
-	0xda, 0x49, 0x5c, 0x48, 0x04, 0xc7, 0xcf, 0x79, 0xc6, 0x1c, 0xd7, 0xb4, 0x2d, 0xbc, 0x8f, 0x19,
-	0xaa, 0x40, 0x42, 0x20, 0xeb, 0xf2, 0xed, 0x61, 0x55, 0x5b, 0xcb, 0x52, 0x1c, 0xf3, 0xd0, 0x71,
-	0xdf, 0xb6, 0x3d, 0xe6, 0xa0, 0x60, 0x25, 0xdc, 0x33, 0x84, 0x21, 0xdb, 0x50, 0xed, 0xb1, 0x9e,
-	0xd9, 0x35, 0x3c, 0xd6, 0xbb, 0x6b, 0x0f, 0xc6, 0x43, 0xcb, 0xad, 0x95, 0xd1, 0x9a, 0x6b, 0xbe,
-	0xca, 0xb7, 0xa3, 0x04, 0x34, 0xb1, 0x42, 0xff, 0x93, 0x06, 0x73, 0x31, 0x2a, 0x72, 0x1b, 0x72,
-	0x6e, 0xd7, 0x1e, 0x31, 0xe9, 0xba, 0xcb, 0x93, 0xd8, 0x35, 0xda, 0x9c, 0x8a, 0x0a, 0x62, 0x7e,
-	0x06, 0xcb, 0x18, 0x2a, 0x5b, 0xc1, 0x31, 0xb9, 0x05, 0x59, 0xef, 0x62, 0x24, 0xe2, 0xcb, 0x6c,
-	0xf3, 0x89, 0x89, 0x8c, 0x3a, 0x17, 0x23, 0x46, 0x91, 0x54, 0x5f, 0x81, 0x1c, 0xb2, 0x25, 0x45,
-	0xc8, 0xb6, 0x0f, 0x37, 0xf7, 0xab, 0x53, 0xdc, 0xd9, 0x69, 0xab, 0x7d, 0xf0, 0x16, 0xbd, 0xdb,
-	0x42, 0xff, 0xce, 0x72, 0x72, 0x02, 0x90, 0x6f, 0x77, 0xe8, 0xce, 0xfe, 0xbd, 0xea, 0x94, 0x7e,
-	0x0e, 0xb3, 0xca, 0xba, 0x64, 0x68, 0xbb, 0x0d, 0x79, 0x8c, 0x5e, 0xca, 0xc3, 0x6f, 0x44, 0xe3,
-	0x8f, 0xa0, 0xde, 0x63, 0x9e, 0xc1, 0x6f, 0x88, 0x4a, 0x5a, 0xb2, 0x11, 0x0f, 0x75, 0x71, 0xeb,
-	0x8d, 0xc7, 0x39, 0xfd, 0x1f, 0x19, 0x58, 0x48, 0xe1, 0x18, 0x4f, 0x09, 0x33, 0x41, 0x4a, 0x58,
-	0x83, 0x39, 0xc7, 0xb6, 0xbd, 0x36, 0x73, 0xce, 0xcc, 0x2e, 0xdb, 0x0f, 0x54, 0x16, 0x47, 0x73,
-	0xeb, 0xe4, 0x28, 0x64, 0x8f, 0x74, 0x22, 0x43, 0x44, 0x91, 0x3c, 0x11, 0xa0, 0x4b, 0x74, 0xcc,
-	0x21, 0x7b, 0xcb, 0x32, 0xcf, 0xf7, 0x0d, 0xcb, 0x46, 0x4f, 0xc8, 0xd2, 0xe4, 0x04, 0xb7, 0xaa,
-	0x5e, 0x10, 0x92, 0x44, 0x78, 0x09, 0x61, 0xc8, 0x33, 0x50, 0x70, 0x65, 0xcc, 0xc8, 0xa3, 0x06,
-	0xaa, 0x81, 0x06, 0x04, 0x9e, 0x2a, 0x02, 0xf2, 0x1c, 0x14, 0xe5, 0x90, 0xfb, 0x44, 0x26, 0x95,
-	0xd8, 0xa7, 0x20, 0x14, 0xca, 0xae, 0x38, 0x1c, 0x8f, 0xe1, 0x6e, 0xad, 0x88, 0x2b, 0x1a, 0x97,
-	0xdd, 0x4b, 0xa3, 0x1d, 0x5a, 0x80, 0x41, 0x8a, 0x46, 0x78, 0xd4, 0x8f, 0x60, 0x3e, 0x41, 0x92,
-	0x12, 0xc7, 0x9e, 0x0d, 0xc7, 0xb1, 0x52, 0xf3, 0xb1, 0xd0, 0xa5, 0x06, 0x8b, 0xc3, 0xe1, 0x6d,
-	0x17, 0xca, 0xe1, 0x29, 0x8c, 0x43, 0x23, 0xc3, 0xba, 0x6b, 0x8f, 0x2d, 0x0f, 0x19, 0xf3, 0x38,
-	0xa4, 0x10, 0x5c, 0xa7, 0xcc, 0x71, 0x6c, 0x47, 0x4c, 0x8b, 0x64, 0x10, 0xc2, 0xe8, 0x3f, 0xd5,
-	0xa0, 0x20, 0xf5, 0x41, 0x9e, 0x82, 0x1c, 0x5f, 0xa8, 0xcc, 0xb2, 0x12, 0x51, 0x18, 0x15, 0x73,
-	0x98, 0x01, 0x0d, 0xaf, 0xfb, 0x80, 0xf5, 0x24, 0x37, 0x05, 0x92, 0x57, 0x00, 0x0c, 0xcf, 0x73,
-	0xcc, 0x93, 0xb1, 0xc7, 0x78, 0x46, 0xe1, 0x3c, 0xae, 0xfb, 0x3c, 0x64, 0xb9, 0x73, 0x76, 0xab,
-	0xf1, 0x06, 0xbb, 0x38, 0xe2, 0xa7, 0xa1, 0x21, 0x72, 0xee, 0xeb, 0x59, 0xbe, 0x0d, 0x59, 0x82,
-	0x3c, 0xdf, 0xc8, 0xb7, 0x4d, 0x09, 0xa5, 0xba, 0x70, 0xaa, 0x79, 0x65, 0x26, 0x99, 0xd7, 0x4d,
-	0xa8, 0x28, 0x63, 0xe2, 0xb0, 0x2b, 0x0d, 0x31, 0x8a, 0x8c, 0x9d, 0x22, 0xf7, 0x70, 0xa7, 0xf8,
-	0xb5, 0x9f, 0xcb, 0xa5, 0x33, 0x72, 0x8f, 0x32, 0x2d, 0x77, 0xc4, 0xba, 0x1e, 0xeb, 0x75, 0x94,
-	0xd3, 0x63, 0xbe, 0x8b, 0xa1, 0xc9, 0xd3, 0x30, 0xeb, 0xa3, 0xb6, 0x2e, 0xf8, 0xe6, 0xd3, 0x28,
-	0x5f, 0x0c, 0x4b, 0x56, 0xa1, 0x84, 0xd1, 0x1d, 0x93, 0x9b, 0xca, 0xdc, 0x61, 0x14, 0x3f, 0x68,
-	0xd7, 0x1e, 0x8e, 0x06, 0xcc, 0x63, 0xbd, 0xd7, 0xed, 0x13, 0x57, 0xe5, 0x9e, 0x08, 0x92, 0xdb,
-	0x0d, 0x2e, 0x42, 0x0a, 0xe1, 0x6c, 0x01, 0x82, 0xcb, 0x1d, 0xb0, 0x14, 0xe2, 0xe4, 0x51, 0x9c,
-	0x38, 0x3a, 0x22, 0x37, 0xe6, 0x70, 0xcc, 0x41, 0x61, 0xb9, 0x11, 0xab, 0xff, 0x59, 0xe3, 0x0e,
-	0xc1, 0x75, 0xc3, 0xd3, 0xba, 0xca, 0xca, 0x8b, 0x2a, 0x9e, 0x8b, 0xdb, 0x96, 0xf1, 0x7a, 0x11,
-	0x72, 0x58, 0x4d, 0xaa, 0xe4, 0x8e, 0x40, 0x50, 0x79, 0x64, 0x52, 0x2a, 0x8f, 0x6c, 0x50, 0x79,
-	0xac, 0xc1, 0xdc, 0xd0, 0x38, 0xe7, 0xbb, 0xf0, 0x72, 0x02, 0xb9, 0x8b, 0xf3, 0xc5, 0xd1, 0xa4,
-	0x09, 0x8b, 0xae, 0x67, 0x0c, 0x18, 0xde, 0xa4, 0xdb, 0x79, 0xe0, 0x30, 0xf7, 0x81, 0x3d, 0x50,
-	0x65, 0x4c, 0xea, 0x9c, 0xfe, 0xbb, 0x2c, 0x2c, 0x05, 0xe7, 0x88, 0x94, 0x18, 0x2f, 0x25, 0x4b,
-	0x8c, 0x7a, 0x2c, 0x48, 0x87, 0xce, 0xfe, 0x6d, 0x99, 0xf1, 0x8d, 0x28, 0x33, 0xd2, 0xcc, 0xa5,
-	0x92, 0x6e, 0x2e, 0x1b, 0xb0, 0x10, 0x98, 0x44, 0x60, 0x2d, 0xb3, 0x48, 0x9d, 0x36, 0xa5, 0x7f,
-	0x92, 0x81, 0xeb, 0xfe, 0xc5, 0x0b, 0x4b, 0x8a, 0x58, 0xcc, 0x77, 0x93, 0x16, 0xb3, 0x92, 0xb4,
-	0x18, 0xb1, 0xf0, 0x5b, 0xb3, 0xf9, 0x46, 0x55, 0xa7, 0x3d, 0xd5, 0x65, 0x08, 0x97, 0x96, 0xb5,
-	0x5d, 0x1d, 0x8a, 0x9e, 0xd1, 0xe7, 0xc5, 0x8f, 0x48, 0xa3, 0x33, 0xd4, 0x87, 0x49, 0x33, 0x5e,
-	0xc1, 0x05, 0xdb, 0xa9, 0xaa, 0x22, 0x51, 0xc3, 0x7d, 0x00, 0x8b, 0xc1, 0x2e, 0x47, 0x4d, 0x7f,
-	0x9f, 0x26, 0xe4, 0x31, 0x54, 0xaa, 0x64, 0x9d, 0x16, 0x67, 0x8e, 0x9a, 0xa2, 0x08, 0x96, 0x94,
-	0x5f, 0x6a, 0xff, 0x57, 0xc2, 0x41, 0x5b, 0x32, 0xf4, 0x73, 0xb1, 0x16, 0xca, 0xc5, 0x04, 0xb2,
-	0x1e, 0x6f, 0x5a, 0xa7, 0xf1, 0xd0, 0x38, 0xd6, 0x3f, 0xd4, 0x42, 0xa1, 0x32, 0x62, 0xc4, 0x58,
-	0x83, 0x0a, 0xbd, 0xf8, 0x35, 0xa8, 0x00, 0xaf, 0x8a, 0xfd, 0xd9, 0x94, 0xd8, 0x9f, 0x0b, 0x62,
-	0xbf, 0x0e, 0x65, 0xe1, 0xb5, 0x62, 0x3b, 0x69, 0x96, 0x11, 0xdc, 0x24, 0x37, 0x2e, 0x4c, 0x76,
-	0xe3, 0x53, 0x78, 0x3c, 0x71, 0x0e, 0x79, 0x11, 0x3c, 0x8d, 0xfa, 0xbb, 0x89, 0x1b, 0x0f, 0x10,
-	0x5f, 0x4a, 0xe5, 0xb7, 0xa1, 0xa8, 0xb6, 0x41, 0xad, 0x5e, 0xf8, 0xd9, 0x11, 0xc7, 0xe9, 0x9d,
-	0xaf, 0xfe, 0x23, 0x0d, 0xae, 0xc5, 0x64, 0x0c, 0x99, 0xcb, 0x7a, 0x5c, 0xca, 0x52, 0x73, 0x3e,
-	0xa8, 0x6e, 0xe5, 0xcc, 0x57, 0x15, 0xfc, 0x2f, 0x1a, 0xcc, 0xc5, 0x26, 0x53, 0xaa, 0x1a, 0x2d,
-	0xb5, 0xaa, 0x89, 0x54, 0x23, 0xd3, 0xf1, 0x6a, 0x24, 0x51, 0xd1, 0x64, 0xd2, 0x2a, 0x9a, 0x58,
-	0x65, 0x94, 0x4d, 0x56, 0x46, 0x29, 0x55, 0x4d, 0x2e, 0xb5, 0xaa, 0xd1, 0xf7, 0x21, 0x87, 0x75,
-	0x19, 0x69, 0x41, 0xc5, 0x61, 0xae, 0x3d, 0x76, 0xba, 0xac, 0x1d, 0x2a, 0x8e, 0x83, 0x28, 0x2d,
-	0x5e, 0xe0, 0xce, 0x6e, 0x35, 0x68, 0x98, 0x8c, 0x46, 0x57, 0xe9, 0xfb, 0x50, 0x3e, 0x1c, 0xbb,
-	0x41, 0x0f, 0xf8, 0x2a, 0x54, 0xb0, 0x0a, 0x77, 0xb7, 0x2e, 0x3a, 0xf2, 0x99, 0x2b, 0xb3, 0x36,
-	0x1b, 0xd2, 0x32, 0xa7, 0x6e, 0x71, 0x0a, 0xca, 0x0c, 0xd7, 0xb6, 0x68, 0x94, 0x5c, 0x6f, 0x43,
-	0x95, 0x53, 0xa0, 0xb0, 0xca, 0xa7, 0x9e, 0xf7, 0xfb, 0x4a, 0xee, 0x84, 0xe5, 0xad, 0xc7, 0x3e,
-	0xfa, 0x74, 0x65, 0xea, 0x9f, 0x9f, 0xae, 0x54, 0x0e, 0x1d, 0x66, 0x0c, 0x06, 0x76, 0x57, 0x50,
-	0xab, 0x86, 0xb2, 0x0a, 0x19, 0xb3, 0x27, 0x0a, 0xf5, 0x32, 0xe5, 0x43, 0x7d, 0x4f, 0x30, 0x15,
-	0x07, 0x90, 0x4c, 0xef, 0x40, 0xe1, 0x04, 0x0b, 0xfc, 0x2f, 0x7c, 0x72, 0x45, 0xaf, 0xdf, 0x04,
-	0x90, 0xaf, 0x5d, 0xfc, 0x86, 0x97, 0x22, 0x5d, 0x6f, 0x59, 0x89, 0xa1, 0xbf, 0x0a, 0x33, 0xbb,
-	0xa6, 0x75, 0xda, 0x1e, 0x98, 0x5d, 0xde, 0x94, 0xe7, 0x06, 0xa6, 0x75, 0xaa, 0xf6, 0xba, 0x9e,
-	0xdc, 0x8b, 0xef, 0xd1, 0xe0, 0x0b, 0xa8, 0xa0, 0xd4, 0x7f, 0xa2, 0x01, 0xe1, 0x48, 0x65, 0x8e,
-	0x41, 0x61, 0x29, 0xc2, 0x88, 0x16, 0x0e, 0x23, 0x35, 0x28, 0xf4, 0x1d, 0x7b, 0x3c, 0xda, 0x52,
-	0xe1, 0x45, 0x81, 0x9c, 0x7e, 0x80, 0x8f, 0x5d, 0xa2, 0x7f, 0x10, 0xc0, 0x17, 0x0d, 0x3b, 0xfa,
-	0xcf, 0xb8, 0xf7, 0x05, 0x42, 0xb4, 0xc7, 0xc3, 0xa1, 0xe1, 0x5c, 0xfc, 0x6f, 0x64, 0xf9, 0xad,
-	0x06, 0x0b, 0x11, 0x85, 0x04, 0x91, 0x8a, 0xb9, 0x9e, 0x39, 0xe4, 0x49, 0x0c, 0x25, 0x29, 0xd2,
-	0x00, 0x11, 0x6d, 0x23, 0x45, 0xe7, 0x11, 0x6a, 0x23, 0x9f, 0x86, 0x59, 0xb4, 0xbf, 0xb6, 0x4f,
-	0x22, 0x44, 0x8b, 0x61, 0x49, 0x23, 0x08, 0x1b, 0x59, 0xbc, 0xc1, 0xc5, 0x48, 0x13, 0x99, 0x08,
-	0x19, 0xdf, 0x81, 0x32, 0x35, 0xde, 0x7f, 0xcd, 0x74, 0x3d, 0xbb, 0xef, 0x18, 0x43, 0x6e, 0x24,
-	0x27, 0xe3, 0xee, 0x29, 0xf3, 0x64, 0x98, 0x90, 0x10, 0x3f, 0x7b, 0x37, 0x24, 0x99, 0x00, 0xf4,
-	0xd7, 0xa1, 0xa8, 0xda, 0xb0, 0x94, 0xce, 0xfa, 0xb9, 0x68, 0x67, 0xbd, 0x14, 0xed, 0xe6, 0xdf,
-	0xdc, 0xe5, 0xed, 0xb3, 0xd9, 0x55, 0xf1, 0xf3, 0x97, 0x1a, 0x94, 0x42, 0x22, 0x92, 0x2d, 0x98,
-	0x1f, 0x18, 0x1e, 0xb3, 0xba, 0x17, 0xc7, 0x0f, 0x94, 0x78, 0xd2, 0x2a, 0x83, 0x1e, 0x3d, 0x2c,
-	0x3b, 0xad, 0x4a, 0xfa, 0xe0, 0x34, 0xff, 0x07, 0x79, 0x97, 0x39, 0xa6, 0x74, 0xc8, 0x70, 0xc8,
-	0xf5, 0xbb, 0x47, 0x49, 0xc0, 0x0f, 0x2e, 0x1c, 0x5c, 0x2a, 0x56, 0x42, 0xfa, 0xdf, 0xa2, 0xd6,
-	0x2d, 0x0d, 0x2b, 0xd9, 0xf4, 0x5f, 0x71, 0x5b, 0xd3, 0xa9, 0xb7, 0x15, 0xc8, 0x97, 0xb9, 0x4a,
-	0xbe, 0x2a, 0x64, 0x46, 0x77, 0xee, 0xc8, 0x96, 0x99, 0x0f, 0x05, 0xe6, 0x45, 0x19, 0x3f, 0xf9,
-	0x50, 0x60, 0x36, 0x64, 0x9f, 0xc8, 0x87, 0x88, 0x79, 0x71, 0x43, 0x36, 0x84, 0x7c, 0xa8, 0xbf,
-	0x0d, 0xf5, 0x34, 0x3f, 0x91, 0x26, 0x7a, 0x07, 0x66, 0x5c, 0x44, 0x99, 0x2c, 0x19, 0x02, 0x52,
-	0xd6, 0x05, 0xd4, 0xfa, 0xaf, 0x34, 0xa8, 0x44, 0x2e, 0x36, 0x92, 0x3b, 0x73, 0x32, 0x77, 0x96,
-	0x41, 0xb3, 0x50, 0x19, 0x19, 0xaa, 0x59, 0x1c, 0xba, 0x8f, 0xfa, 0xd6, 0xa8, 0x76, 0x9f, 0x43,
-	0xae, 0x7c, 0xd5, 0xd7, 0x5c, 0x0e, 0x9d, 0xe0, 0xe1, 0x8a, 0x54, 0x3b, 0xe1, 0x50, 0x4f, 0x1e,
-	0x4c, 0xeb, 0xe1, 0x1b, 0x85, 0xf8, 0x80, 0x50, 0x40, 0xde, 0xea, 0xeb, 0x00, 0x81, 0xec, 0xa9,
-	0x69, 0xf5, 0xb0, 0x84, 0xcd, 0x51, 0x1c, 0xeb, 0x4c, 0x3c, 0x78, 0x4b, 0xc1, 0xb7, 0x0d, 0xcf,
-	0xe0, 0xf5, 0xa9, 0xc3, 0xdc, 0xf1, 0xc0, 0xeb, 0x04, 0xa9, 0x3d, 0x84, 0xe1, 0xb5, 0x9d, 0x80,
-	0xa4, 0xd9, 0xd4, 0x53, 0x7d, 0x08, 0x29, 0xa8, 0xa4, 0xe4, 0x51, 0x70, 0x3e, 0x31, 0xcb, 0xcd,
-	0x64, 0x60, 0x9c, 0xb0, 0x41, 0xa8, 0xce, 0x0a, 0x10, 0x5c, 0x0e, 0x04, 0x8e, 0x42, 0xd5, 0x44,
-	0x08, 0x43, 0xd6, 0x61, 0xda, 0x53, 0xa6, 0xb1, 0x32, 0x59, 0x86, 0x43, 0xdb, 0xb4, 0x3c, 0x3a,
-	0xed, 0xb9, 0xdc, 0x87, 0x96, 0xd2, 0xa7, 0xf1, 0x32, 0x4c, 0x29, 0x44, 0x85, 0xe2, 0x98, 0x5b,
-	0xc7, 0x99, 0x31, 0xc0, 0x8d, 0x35, 0xca, 0x87, 0x3c, 0x3f, 0xb3, 0x73, 0x36, 0x1c, 0x0d, 0x0c,
-	0xa7, 0x23, 0x5f, 0x28, 0x33, 0xf8, 0xd1, 0x2a, 0x8e, 0x26, 0xcf, 0x40, 0x55, 0xa1, 0xd4, 0x17,
-	0x0b, 0x69, 0x9c, 0x09, 0xbc, 0xde, 0x86, 0x05, 0xfc, 0xf8, 0xb0, 0x63, 0xb9, 0x9e, 0x61, 0x79,
-	0x97, 0x47, 0x65, 0x3f, 0xca, 0xca, 0x48, 0x13, 0x89, 0xb2, 0xc2, 0x37, 0x31, 0xca, 0x9e, 0xc3,
-	0x62, 0x94, 0xa9, 0x34, 0xe1, 0x86, 0xef, 0x53, 0xc2, 0x7e, 0x83, 0xb0, 0x23, 0x29, 0xdb, 0x38,
-	0xeb, 0x3b, 0xd6, 0xc3, 0x3f, 0xeb, 0xfe, 0x58, 0x83, 0x4a, 0x84, 0x17, 0xb9, 0x03, 0x79, 0xbc,
-	0xb6, 0xa4, 0xcf, 0x24, 0xdf, 0xab, 0xe4, 0xd7, 0x22, 0xb9, 0x20, 0x5a, 0x4c, 0x6a, 0x32, 0x18,
-	0x92, 0x15, 0x28, 0x8d, 0x1c, 0x7b, 0x78, 0x2c, 0xb9, 0x8a, 0xb7, 0x5d, 0xe0, 0xa8, 0x5d, 0xc4,
-	0xe8, 0xbf, 0xcf, 0xc0, 0x3c, 0x1e, 0x9f, 0x1a, 0x56, 0x9f, 0x3d, 0x12, 0x8d, 0x62, 0x2b, 0xe7,
-	0xb1, 0x91, 0xbc, 0x46, 0x1c, 0x47, 0xbf, 0x33, 0x16, 0xe2, 0xdf, 0x19, 0x43, 0xed, 0x6f, 0xf1,
-	0x92, 0xf6, 0x77, 0xe6, 0xca, 0xf6, 0x17, 0xd2, 0xda, 0xdf, 0x50, 0xd3, 0x59, 0x8a, 0x36, 0x9d,
-	0xe1, 0xc6, 0xb8, 0x1c, 0x6b, 0x8c, 0x55, 0x43, 0x5a, 0x99, 0xd8, 0x90, 0xce, 0x7e, 0xa1, 0x86,
-	0x74, 0xee, 0xa1, 0xdf, 0x31, 0x78, 0x7e, 0x97, 0xa6, 0xef, 0xd6, 0xaa, 0xe2, 0xcc, 0x3e, 0x42,
-	0x77, 0x81, 0x84, 0x2f, 0x4c, 0x5a, 0xeb, 0xb3, 0x31, 0x6b, 0x5d, 0x08, 0x92, 0xa4, 0x39, 0x64,
-	0x5f, 0xd9, 0x54, 0x3f, 0x80, 0x62, 0x4b, 0x4a, 0xf0, 0xe8, 0x8d, 0xf4, 0x49, 0x28, 0xf3, 0x30,
-	0xe2, 0x7a, 0xc6, 0x70, 0x74, 0x3c, 0x14, 0x56, 0x9a, 0xa1, 0x25, 0x1f, 0xb7, 0xe7, 0xea, 0x9b,
-	0x90, 0x6f, 0x1b, 0xbc, 0x45, 0x48, 0x10, 0x4f, 0x27, 0x88, 0x83, 0x5d, 0xb4, 0xd0, 0x2e, 0xfa,
-	0xc7, 0x1a, 0x40, 0xa0, 0x8b, 0xaf, 0x72, 0x8a, 0x75, 0x28, 0xb8, 0x28, 0x8c, 0x2a, 0x07, 0xe6,
-	0x02, 0xf5, 0x21, 0x5e, 0xd2, 0x2b, 0xaa, 0x2b, 0xbd, 0x90, 0xbc, 0x18, 0xbe, 0xf1, 0x6c, 0x2c,
-	0x85, 0x2b, 0xc5, 0x4b, 0xae, 0x01, 0xe5, 0x33, 0xef, 0xc2, 0x5c, 0xac, 0xbb, 0x20, 0x65, 0x28,
-	0xee, 0x1f, 0x1c, 0xb7, 0x28, 0x3d, 0xa0, 0xd5, 0x29, 0xb2, 0x00, 0x73, 0x7b, 0x9b, 0xef, 0x1c,
-	0xef, 0xee, 0x1c, 0xb5, 0x8e, 0x3b, 0x74, 0xf3, 0x6e, 0xab, 0x5d, 0xd5, 0x38, 0x12, 0xc7, 0xc7,
-	0x9d, 0x83, 0x83, 0xe3, 0xdd, 0x4d, 0x7a, 0xaf, 0x55, 0x9d, 0x26, 0xf3, 0x50, 0x79, 0x6b, 0xff,
-	0x8d, 0xfd, 0x83, 0xb7, 0xf7, 0xe5, 0xe2, 0x4c, 0xf3, 0xe7, 0x1a, 0xe4, 0x39, 0x7b, 0xe6, 0x90,
-	0xef, 0xc1, 0x8c, 0xdf, 0xa4, 0x90, 0x6b, 0x91, 0xd6, 0x26, 0xdc, 0xb8, 0xd4, 0x1f, 0x8b, 0x4c,
-	0x29, 0xe3, 0xd4, 0xa7, 0xc8, 0x26, 0x94, 0x7c, 0xe2, 0xa3, 0xe6, 0x97, 0x61, 0xd1, 0xfc, 0xb7,
-	0x06, 0x55, 0x69, 0x97, 0xf7, 0x98, 0xc5, 0x1c, 0xc3, 0xb3, 0x7d, 0xc1, 0xb0, 0x5f, 0x89, 0x71,
-	0x0d, 0x37, 0x3f, 0x93, 0x05, 0xdb, 0x01, 0xb8, 0xc7, 0x3c, 0x55, 0x2b, 0x5e, 0x4f, 0x4f, 0x8e,
-	0x82, 0xc7, 0x8d, 0x09, 0x99, 0x53, 0xb1, 0xba, 0x07, 0x10, 0x38, 0x26, 0x09, 0x72, 0x7d, 0x22,
-	0xbc, 0xd6, 0xaf, 0xa7, 0xce, 0xf9, 0x27, 0xfd, 0x4d, 0x16, 0x0a, 0x7c, 0xc2, 0x64, 0x0e, 0x79,
-	0x0d, 0x2a, 0xdf, 0x37, 0xad, 0x9e, 0xff, 0x67, 0x03, 0x72, 0x2d, 0xed, 0x3f, 0x0e, 0x82, 0x6d,
-	0x7d, 0xf2, 0xdf, 0x1f, 0xf0, 0x0a, 0xca, 0xea, 0xf3, 0x65, 0x97, 0x59, 0x1e, 0x99, 0xf0, 0xcd,
-	0xbc, 0xfe, 0x78, 0x02, 0xef, 0xb3, 0x68, 0x41, 0x29, 0xf4, 0x3d, 0x3e, 0xac, 0xad, 0xc4, 0x57,
-	0xfa, 0xcb, 0xd8, 0xdc, 0x03, 0x08, 0x9e, 0xa2, 0xc8, 0x25, 0x0f, 0xeb, 0xf5, 0xeb, 0xa9, 0x73,
-	0x3e, 0xa3, 0x37, 0xd4, 0x91, 0xc4, 0x9b, 0xd6, 0xa5, 0xac, 0x9e, 0x48, 0x7d, 0x57, 0x0b, 0x31,
-	0x3b, 0x82, 0xb9, 0xd8, 0xb3, 0x0b, 0xb9, 0xea, 0x05, 0xb7, 0xbe, 0x3a, 0x99, 0xc0, 0xe7, 0xfb,
-	0x83, 0xd0, 0xc3, 0x9b, 0x7a, 0xce, 0xb9, 0x9a, 0xb3, 0x3e, 0x89, 0x20, 0x2c, 0x73, 0xf3, 0xaf,
-	0x59, 0xa8, 0xb6, 0x3d, 0x87, 0x19, 0x43, 0xd3, 0xea, 0x2b, 0x93, 0x79, 0x05, 0xf2, 0x32, 0xf1,
-	0x3d, 0xec, 0x15, 0x6f, 0x68, 0xdc, 0x1f, 0x1e, 0xc9, 0xdd, 0x6c, 0x68, 0x64, 0xef, 0x11, 0xde,
-	0xce, 0x86, 0x46, 0xde, 0xf9, 0x7a, 0xee, 0x67, 0x43, 0x23, 0xef, 0x7e, 0x7d, 0x37, 0xb4, 0xa1,
-	0x91, 0x43, 0x98, 0x97, 0xb1, 0xe2, 0x91, 0x44, 0x87, 0x0d, 0x8d, 0x1c, 0xc1, 0x42, 0x98, 0xa3,
-	0x2c, 0x21, 0xc9, 0x8d, 0xe8, 0xba, 0x68, 0x91, 0x1c, 0xd2, 0x70, 0x5a, 0xb5, 0xcb, 0xf9, 0x36,
-	0xff, 0xa0, 0x41, 0x41, 0x45, 0xc2, 0xe3, 0xd4, 0x6e, 0x55, 0xbf, 0xac, 0x87, 0x93, 0x1b, 0x3d,
-	0x75, 0x29, 0xcd, 0x23, 0x8f, 0x96, 0x5b, 0xb5, 0x8f, 0x3e, 0x5b, 0xd6, 0x3e, 0xfe, 0x6c, 0x59,
-	0xfb, 0xd7, 0x67, 0xcb, 0xda, 0x2f, 0x3e, 0x5f, 0x9e, 0xfa, 0xf8, 0xf3, 0xe5, 0xa9, 0x4f, 0x3e,
-	0x5f, 0x9e, 0x3a, 0xc9, 0xe3, 0xbf, 0xe9, 0x5e, 0xf8, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x3c,
-	0x55, 0xe8, 0xef, 0xce, 0x27, 0x00, 0x00,
+	// 3063 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x5a, 0xcd, 0x6f, 0x23, 0xc7,
+	0xb1, 0xd7, 0x88, 0xdf, 0x45, 0x52, 0xa2, 0x5a, 0x5a, 0x99, 0xcb, 0x5d, 0x6b, 0xe5, 0xf1, 0xe2,
+	0x41, 0xcf, 0x1f, 0x94, 0x96, 0x5e, 0xe3, 0x79, 0xed, 0xf7, 0xfc, 0x20, 0xad, 0x98, 0xb5, 0x6c,
+	0x7d, 0xb9, 0x49, 0xcb, 0x4e, 0x10, 0x40, 0x18, 0x91, 0xbd, 0xd2, 0x40, 0xe4, 0x0c, 0x3d, 0xd3,
+	0x94, 0xa5, 0x1c, 0x8c, 0x24, 0x40, 0x0e, 0x01, 0x72, 0xc8, 0x21, 0x39, 0xe4, 0x2f, 0x08, 0x92,
+	0x4b, 0x0e, 0xf9, 0x13, 0x82, 0x18, 0x0e, 0x82, 0x04, 0x3e, 0x1a, 0x09, 0x60, 0x18, 0xf6, 0x21,
+	0xb9, 0xe4, 0x7f, 0x08, 0xaa, 0xbb, 0xe7, 0x7b, 0x28, 0x79, 0xed, 0x35, 0xe2, 0x83, 0x4f, 0xec,
+	0xae, 0xfe, 0x75, 0x75, 0x75, 0x75, 0x55, 0x75, 0x55, 0x0f, 0xe1, 0x89, 0xd1, 0xe9, 0xf1, 0x2a,
+	0x67, 0xc3, 0x91, 0x3d, 0x3a, 0x92, 0xbf, 0xcd, 0x91, 0x63, 0x73, 0x9b, 0x14, 0x14, 0xb1, 0xb1,
+	0xd8, 0xb3, 0x87, 0x43, 0xdb, 0x5a, 0x3d, 0xbb, 0xb3, 0x2a, 0x5b, 0x12, 0xd0, 0x78, 0xfe, 0xd8,
+	0xe4, 0x27, 0xe3, 0xa3, 0x66, 0xcf, 0x1e, 0xae, 0x1e, 0xdb, 0xc7, 0xf6, 0xaa, 0x20, 0x1f, 0x8d,
+	0x1f, 0x8a, 0x9e, 0xe8, 0x88, 0x96, 0x82, 0x2f, 0x70, 0xc7, 0xe8, 0x31, 0xe4, 0x22, 0x1a, 0x92,
+	0xaa, 0x7f, 0xaa, 0x41, 0xad, 0x8b, 0xfd, 0x8d, 0x8b, 0xad, 0x4d, 0xca, 0xde, 0x1d, 0x33, 0x97,
+	0x93, 0x3a, 0x14, 0x04, 0x66, 0x6b, 0xb3, 0xae, 0x2d, 0x6b, 0x2b, 0x15, 0xea, 0x75, 0xc9, 0x12,
+	0xc0, 0xd1, 0xc0, 0xee, 0x9d, 0x76, 0xb8, 0xe1, 0xf0, 0xfa, 0xf4, 0xb2, 0xb6, 0x52, 0xa2, 0x21,
+	0x0a, 0x69, 0x40, 0x51, 0xf4, 0xda, 0x56, 0xbf, 0x9e, 0x11, 0xa3, 0x7e, 0x9f, 0xdc, 0x84, 0xd2,
+	0xbb, 0x63, 0xe6, 0x5c, 0xec, 0xd8, 0x7d, 0x56, 0xcf, 0x89, 0xc1, 0x80, 0x40, 0x9e, 0x83, 0x39,
+	0x63, 0x30, 0xb0, 0xdf, 0xdb, 0x37, 0x1c, 0x6e, 0x1a, 0x03, 0x21, 0x53, 0x3d, 0xbf, 0xac, 0xad,
+	0x14, 0x69, 0x72, 0x80, 0x3c, 0x03, 0xb5, 0xa1, 0x71, 0xbe, 0x71, 0xc1, 0x99, 0xbb, 0xcf, 0x1c,
+	0x09, 0x2e, 0x2c, 0x6b, 0x2b, 0x59, 0x9a, 0xa0, 0xeb, 0xff, 0xd4, 0x60, 0x2e, 0xb4, 0x45, 0x77,
+	0x64, 0x5b, 0x2e, 0x23, 0xb7, 0x21, 0x27, 0x36, 0x25, 0x76, 0x58, 0x6e, 0xcd, 0x34, 0x95, 0xba,
+	0x9b, 0x02, 0x4a, 0xe5, 0x20, 0x79, 0x01, 0x0a, 0x43, 0xc6, 0x1d, 0xb3, 0xe7, 0x8a, 0xcd, 0x96,
+	0x5b, 0xd7, 0xa3, 0x38, 0x64, 0xb9, 0x23, 0x01, 0xd4, 0x43, 0x92, 0x7b, 0x90, 0x77, 0xb9, 0xc1,
+	0xc7, 0xae, 0x50, 0xc1, 0x4c, 0xeb, 0xa9, 0xe4, 0x1c, 0x4f, 0x8c, 0x66, 0x47, 0x00, 0xa9, 0x9a,
+	0x80, 0x9a, 0x1f, 0x32, 0xd7, 0x35, 0x8e, 0x59, 0x3d, 0x2b, 0x34, 0xe4, 0x75, 0xf5, 0xa7, 0x21,
+	0x2f, 0xb1, 0xa4, 0x02, 0xc5, 0xfb, 0x7b, 0x3b, 0xfb, 0xdb, 0xed, 0x6e, 0xbb, 0x36, 0x45, 0xca,
+	0x50, 0xd8, 0x5f, 0xa7, 0xdd, 0xad, 0xf5, 0xed, 0x9a, 0xa6, 0x13, 0xa8, 0xc5, 0xc5, 0xd2, 0xff,
+	0x3a, 0x0d, 0xd5, 0x0e, 0x33, 0x9c, 0xde, 0x89, 0x77, 0xbc, 0x2f, 0x43, 0xb6, 0x6b, 0x1c, 0xbb,
+	0x75, 0x6d, 0x39, 0xb3, 0x52, 0x6e, 0x2d, 0xfb, 0xd2, 0x45, 0x50, 0x4d, 0x84, 0xb4, 0x2d, 0xee,
+	0x5c, 0x6c, 0x64, 0x3f, 0xfc, 0xe4, 0xd6, 0x14, 0x15, 0x73, 0xc8, 0x6d, 0xa8, 0xee, 0x98, 0xd6,
+	0xe6, 0xd8, 0x31, 0xb8, 0x69, 0x5b, 0x3b, 0x52, 0x2d, 0x55, 0x1a, 0x25, 0x0a, 0x94, 0x71, 0x1e,
+	0x42, 0x65, 0x14, 0x2a, 0x4c, 0x24, 0x0b, 0x90, 0xdb, 0x36, 0x87, 0x26, 0x17, 0x5b, 0xad, 0x52,
+	0xd9, 0x41, 0xaa, 0x2b, 0xac, 0x2b, 0x27, 0xa9, 0xa2, 0x43, 0x6a, 0x90, 0x61, 0x56, 0x5f, 0x18,
+	0x44, 0x95, 0x62, 0x13, 0x71, 0x6f, 0xa2, 0xf5, 0xd4, 0x8b, 0x42, 0x51, 0xb2, 0x43, 0x56, 0x60,
+	0xb6, 0x33, 0x32, 0x2c, 0x3c, 0x7d, 0xfc, 0xed, 0x30, 0x5e, 0x2f, 0x89, 0x39, 0x71, 0x72, 0xe3,
+	0x7f, 0xa0, 0xe4, 0x6f, 0x11, 0xd9, 0x9f, 0xb2, 0x0b, 0x61, 0x0b, 0x25, 0x8a, 0x4d, 0x64, 0x7f,
+	0x66, 0x0c, 0xc6, 0x4c, 0x19, 0xb9, 0xec, 0xbc, 0x3c, 0xfd, 0x92, 0xa6, 0x7f, 0x90, 0x01, 0x22,
+	0x55, 0xb5, 0x81, 0xa6, 0xed, 0x69, 0xf5, 0x2e, 0x94, 0x5c, 0x4f, 0x81, 0xca, 0xa8, 0x16, 0xd3,
+	0x55, 0x4b, 0x03, 0x20, 0x1e, 0xb8, 0x70, 0x90, 0xad, 0x4d, 0xb5, 0x90, 0xd7, 0x45, 0x77, 0x11,
+	0x5b, 0xdf, 0x47, 0x63, 0x90, 0xfa, 0x0b, 0x08, 0xa8, 0xe1, 0x91, 0x71, 0xcc, 0xdc, 0xae, 0x2d,
+	0x59, 0x2b, 0x1d, 0x46, 0x89, 0xe8, 0x8e, 0xcc, 0xea, 0xd9, 0x7d, 0xd3, 0x3a, 0x56, 0x1e, 0xe7,
+	0xf7, 0x91, 0x83, 0x69, 0xf5, 0xd9, 0x39, 0xb2, 0xeb, 0x98, 0x3f, 0x60, 0x4a, 0xb7, 0x51, 0x22,
+	0xd1, 0xa1, 0xc2, 0x6d, 0x6e, 0x0c, 0x28, 0xeb, 0xd9, 0x4e, 0xdf, 0x15, 0x4e, 0x56, 0xa5, 0x11,
+	0x1a, 0x62, 0xfa, 0x06, 0x37, 0xda, 0xde, 0x4a, 0xf2, 0x40, 0x22, 0x34, 0xdc, 0xe7, 0x19, 0x73,
+	0x5c, 0xd3, 0xb6, 0xc4, 0x79, 0x94, 0xa8, 0xd7, 0x25, 0x04, 0xb2, 0x2e, 0x2e, 0x0f, 0xc2, 0x7d,
+	0x45, 0x1b, 0xc3, 0xcc, 0x43, 0xdb, 0xe6, 0xcc, 0x11, 0x82, 0x95, 0xc5, 0x9a, 0x21, 0x0a, 0xd9,
+	0x84, 0x5a, 0x9f, 0xf5, 0xcd, 0x9e, 0xc1, 0x59, 0xff, 0xbe, 0x3d, 0x18, 0x0f, 0x2d, 0xb7, 0x5e,
+	0x11, 0xd6, 0x5c, 0xf7, 0x55, 0xbe, 0x19, 0x05, 0xd0, 0xc4, 0x0c, 0xfd, 0x0f, 0x1a, 0xcc, 0xc6,
+	0x50, 0xe4, 0x2e, 0xe4, 0xdc, 0x9e, 0x3d, 0x62, 0xca, 0x75, 0x97, 0x26, 0xb1, 0x6b, 0x76, 0x10,
+	0x45, 0x25, 0x18, 0xf7, 0x60, 0x19, 0x43, 0xcf, 0x56, 0x44, 0x9b, 0xdc, 0x81, 0x2c, 0xbf, 0x18,
+	0xc9, 0xf8, 0x32, 0xd3, 0x7a, 0x72, 0x22, 0xa3, 0xee, 0xc5, 0x88, 0x51, 0x01, 0xd5, 0x6f, 0x41,
+	0x4e, 0xb0, 0x25, 0x45, 0xc8, 0x76, 0xf6, 0xd7, 0x77, 0x6b, 0x53, 0xe8, 0xec, 0xb4, 0xdd, 0xd9,
+	0x7b, 0x8b, 0xde, 0x6f, 0x0b, 0xff, 0xce, 0x22, 0x9c, 0x00, 0xe4, 0x3b, 0x5d, 0xba, 0xb5, 0xfb,
+	0xa0, 0x36, 0xa5, 0x7f, 0xac, 0xc1, 0x8c, 0x67, 0x5e, 0x2a, 0xb6, 0xdd, 0x85, 0xbc, 0x08, 0x5f,
+	0x9e, 0x8b, 0xdf, 0x8c, 0x06, 0x20, 0x89, 0xde, 0x61, 0xdc, 0xc0, 0x23, 0xa2, 0x0a, 0x4b, 0xd6,
+	0xe2, 0xb1, 0x2e, 0x6e, 0xbe, 0x89, 0x40, 0x77, 0x1b, 0xaa, 0xee, 0xa9, 0x39, 0x1a, 0xb1, 0xbe,
+	0xf0, 0x04, 0x74, 0xf3, 0xcc, 0x4a, 0x89, 0x46, 0x89, 0xe4, 0x25, 0x28, 0x1a, 0x96, 0x31, 0xb8,
+	0x70, 0x4d, 0xb7, 0x9e, 0x8d, 0xc9, 0x13, 0xf2, 0xa3, 0x75, 0x85, 0xa1, 0x3e, 0x5a, 0xff, 0xbb,
+	0x06, 0xf3, 0x29, 0x88, 0xb0, 0xd3, 0x68, 0x97, 0x38, 0xcd, 0x74, 0xdc, 0x69, 0xfe, 0x0b, 0x66,
+	0x4c, 0xcb, 0x1d, 0xb1, 0x1e, 0x67, 0x7d, 0x71, 0x47, 0x88, 0x53, 0xce, 0xd2, 0x18, 0x55, 0x98,
+	0x1f, 0xe3, 0xbd, 0x93, 0x5d, 0xc3, 0xb2, 0x5d, 0xe1, 0x59, 0x59, 0x1a, 0xa2, 0x90, 0x65, 0x28,
+	0x3f, 0x34, 0x07, 0x9c, 0x39, 0x12, 0x90, 0x13, 0x80, 0x30, 0x09, 0x5d, 0xa2, 0x67, 0x0f, 0x8f,
+	0x4c, 0x8b, 0x49, 0x48, 0x5e, 0x40, 0x22, 0x34, 0xfd, 0x5f, 0x19, 0x98, 0x4f, 0x39, 0x8f, 0xf8,
+	0xed, 0x5b, 0x0a, 0x6e, 0xdf, 0x15, 0x98, 0x75, 0x6c, 0x9b, 0x77, 0x98, 0x73, 0x66, 0xf6, 0xd8,
+	0x6e, 0x60, 0x71, 0x71, 0x32, 0x9e, 0x0c, 0x92, 0x04, 0x7b, 0x81, 0x93, 0x97, 0x71, 0x94, 0x88,
+	0x77, 0xae, 0x50, 0x4e, 0xd7, 0x1c, 0xb2, 0xb7, 0x2c, 0xf3, 0x1c, 0xe5, 0x52, 0xdb, 0x4d, 0x0e,
+	0xa0, 0x56, 0xfa, 0x41, 0x44, 0x97, 0xd1, 0x39, 0x44, 0x21, 0xcf, 0x40, 0xc1, 0x55, 0x21, 0x37,
+	0x2f, 0xec, 0xa7, 0x16, 0x1c, 0xb3, 0xa4, 0x53, 0x0f, 0x40, 0x9e, 0x83, 0xa2, 0x6a, 0x62, 0x48,
+	0xc9, 0xa4, 0x82, 0x7d, 0x04, 0xa1, 0x50, 0x71, 0xe5, 0xe6, 0xf0, 0x0a, 0x74, 0xeb, 0x45, 0x31,
+	0xa3, 0x79, 0x99, 0x55, 0x37, 0x3b, 0xa1, 0x09, 0x22, 0xc6, 0xd3, 0x08, 0x0f, 0xa1, 0x65, 0x66,
+	0x19, 0x16, 0x77, 0xeb, 0x25, 0x61, 0xb5, 0x5e, 0xb7, 0x71, 0x00, 0x73, 0x89, 0xc9, 0x29, 0x17,
+	0xc4, 0xb3, 0xe1, 0x0b, 0xa2, 0xdc, 0xba, 0x16, 0xb2, 0xe9, 0x60, 0x72, 0xf8, 0xde, 0xd8, 0x86,
+	0x4a, 0x78, 0x48, 0xd8, 0xea, 0xc8, 0xb0, 0xee, 0xdb, 0x63, 0x8b, 0xd7, 0x35, 0x65, 0xab, 0x1e,
+	0x01, 0xb5, 0xcd, 0x1c, 0xc7, 0x76, 0xe4, 0xb0, 0x34, 0xe5, 0x10, 0x45, 0xff, 0x89, 0x06, 0x05,
+	0xa5, 0x29, 0xf2, 0x34, 0xe4, 0x70, 0xa2, 0xe7, 0xee, 0xd5, 0x88, 0x2a, 0xa9, 0x1c, 0x13, 0xa9,
+	0x85, 0xc1, 0x7b, 0x27, 0xac, 0xaf, 0xb8, 0x79, 0x5d, 0xf2, 0x0a, 0x80, 0xc1, 0xb9, 0x63, 0x1e,
+	0x8d, 0xa5, 0x4b, 0x20, 0x8f, 0x1b, 0x3e, 0x0f, 0x95, 0x73, 0x9e, 0xdd, 0x69, 0xbe, 0xc1, 0x2e,
+	0x0e, 0x70, 0x37, 0x34, 0x04, 0xc7, 0x20, 0x9a, 0xc5, 0x65, 0xc8, 0x22, 0xe4, 0x71, 0x21, 0xdf,
+	0x6a, 0x55, 0x2f, 0x35, 0x36, 0xa6, 0x1a, 0x5e, 0x66, 0x92, 0xe1, 0xdd, 0x86, 0xaa, 0x67, 0x66,
+	0x61, 0x8f, 0x8c, 0x12, 0x63, 0xbb, 0xc8, 0x3d, 0xda, 0x2e, 0x7e, 0xe5, 0x27, 0x49, 0x2a, 0xc8,
+	0xa1, 0xaf, 0xf9, 0x51, 0xa1, 0xeb, 0x05, 0x53, 0x91, 0x48, 0xc4, 0xc8, 0x29, 0x51, 0x65, 0x3a,
+	0x35, 0xaa, 0x2c, 0x43, 0x59, 0x5c, 0x9b, 0x7e, 0xac, 0x44, 0x6e, 0x61, 0x12, 0x6e, 0xb4, 0x67,
+	0x0f, 0x47, 0x03, 0xc6, 0x59, 0xff, 0x75, 0xfb, 0xc8, 0xf5, 0x2e, 0xf5, 0x08, 0x11, 0xed, 0x46,
+	0x4c, 0x12, 0x08, 0xe9, 0x86, 0x01, 0x01, 0xe5, 0x0e, 0x58, 0x4a, 0x71, 0x64, 0xf0, 0x89, 0x93,
+	0x23, 0x72, 0x8b, 0xe4, 0x48, 0x65, 0xd0, 0x31, 0xaa, 0xfe, 0x47, 0x0d, 0xe6, 0xa4, 0x6e, 0x30,
+	0x5f, 0xf2, 0xd2, 0x9d, 0x05, 0xef, 0xa2, 0x94, 0xa7, 0x2d, 0x3b, 0x48, 0x15, 0x29, 0xbd, 0x97,
+	0x35, 0x89, 0x4e, 0x90, 0xd2, 0x65, 0x52, 0x52, 0xba, 0x6c, 0x90, 0xd2, 0xad, 0xc0, 0xec, 0xd0,
+	0x38, 0xc7, 0x55, 0x30, 0x4f, 0x13, 0xdc, 0xe5, 0xfe, 0xe2, 0x64, 0xd2, 0x82, 0x05, 0x97, 0x1b,
+	0x03, 0x26, 0x4e, 0xd2, 0xed, 0x9e, 0x38, 0xcc, 0x3d, 0xb1, 0x07, 0x5e, 0x7e, 0x98, 0x3a, 0xa6,
+	0xff, 0x36, 0x0b, 0x8b, 0xc1, 0x3e, 0x22, 0xb9, 0xdb, 0x4b, 0xc9, 0xdc, 0xad, 0x11, 0xbb, 0xa3,
+	0x42, 0x7b, 0xff, 0x36, 0x7f, 0xfb, 0x46, 0xe4, 0x6f, 0x69, 0xe6, 0x52, 0x4d, 0x37, 0x97, 0x35,
+	0x98, 0x0f, 0x4c, 0x22, 0xb0, 0x96, 0x19, 0x81, 0x4e, 0x1b, 0xd2, 0x3f, 0xce, 0xc0, 0x0d, 0xff,
+	0xe0, 0xc5, 0x58, 0xd4, 0x62, 0xfe, 0x2f, 0x69, 0x31, 0xb7, 0x92, 0x16, 0x23, 0x27, 0x7e, 0x6b,
+	0x36, 0xdf, 0xa8, 0xb4, 0xbf, 0xef, 0x95, 0x6f, 0xd2, 0xa5, 0x55, 0xce, 0xdc, 0x80, 0x22, 0x37,
+	0x8e, 0x31, 0x2d, 0x92, 0xd7, 0x68, 0x89, 0xfa, 0x7d, 0xd2, 0x8a, 0x67, 0xc6, 0xc1, 0x72, 0x5e,
+	0xbe, 0x11, 0xcf, 0x8d, 0xf5, 0xf7, 0x61, 0x21, 0x58, 0xe5, 0xa0, 0xe5, 0xaf, 0xd3, 0x82, 0xbc,
+	0x08, 0x95, 0xde, 0x65, 0x9d, 0x16, 0x67, 0x0e, 0x5a, 0xb2, 0xba, 0x50, 0xc8, 0x2f, 0xb5, 0xfe,
+	0x2b, 0x30, 0x97, 0x60, 0xe8, 0xdf, 0xc5, 0x5a, 0xe8, 0x2e, 0x26, 0x90, 0xe5, 0xf8, 0x1a, 0x30,
+	0x2d, 0x36, 0x2d, 0xda, 0xfa, 0x07, 0x1a, 0x2c, 0xa6, 0x1b, 0xb1, 0xc8, 0x9b, 0xa4, 0x5e, 0xfc,
+	0xec, 0x54, 0x76, 0xaf, 0x8a, 0xfd, 0xd9, 0x94, 0xd8, 0x9f, 0x0b, 0x62, 0xbf, 0x0e, 0x15, 0xe9,
+	0xb5, 0x72, 0x39, 0x65, 0x96, 0x11, 0xda, 0x24, 0x37, 0x2e, 0x4c, 0x76, 0xe3, 0x53, 0x78, 0x22,
+	0xb1, 0x0f, 0x75, 0x10, 0x78, 0x8d, 0xfa, 0xab, 0xc9, 0x13, 0x0f, 0x08, 0x5f, 0x4a, 0xe5, 0x77,
+	0xa1, 0xe8, 0x2d, 0x43, 0x48, 0xa8, 0xfa, 0x2b, 0xc9, 0xf2, 0x2e, 0xfd, 0x49, 0x41, 0xff, 0xa1,
+	0x06, 0xd7, 0x63, 0x32, 0x86, 0xcc, 0x65, 0x35, 0x2e, 0x65, 0xb9, 0x35, 0x17, 0xe4, 0xbd, 0x6a,
+	0xe4, 0xab, 0x0a, 0xfe, 0x27, 0x0d, 0x66, 0x63, 0x83, 0x29, 0x59, 0x8d, 0x96, 0x9a, 0xd5, 0x44,
+	0xb2, 0x91, 0xe9, 0x78, 0x36, 0x92, 0xc8, 0x68, 0x32, 0x69, 0x19, 0x4d, 0x2c, 0x33, 0xca, 0x26,
+	0x33, 0xa3, 0x94, 0xac, 0x26, 0x97, 0x9a, 0xd5, 0xe8, 0xbb, 0x90, 0x93, 0x4f, 0x84, 0x6d, 0xa8,
+	0x3a, 0xcc, 0xb5, 0xc7, 0x4e, 0x8f, 0x75, 0x42, 0xc9, 0x71, 0x10, 0xa5, 0xe5, 0x33, 0xe8, 0xd9,
+	0x9d, 0x26, 0x0d, 0xc3, 0x68, 0x74, 0x96, 0xbe, 0x0b, 0x95, 0xfd, 0xb1, 0x1b, 0xd4, 0xd6, 0xaf,
+	0x42, 0x55, 0x64, 0xe1, 0xee, 0xc6, 0x45, 0x57, 0xbd, 0x1f, 0x66, 0x56, 0x66, 0x42, 0x5a, 0x46,
+	0x74, 0x1b, 0x11, 0x94, 0x19, 0xae, 0x6d, 0xd1, 0x28, 0x5c, 0xef, 0x40, 0x0d, 0x11, 0x42, 0x58,
+	0xcf, 0xa7, 0x9e, 0xf7, 0xeb, 0x75, 0x74, 0xc2, 0xca, 0xc6, 0x35, 0x7c, 0x70, 0xfb, 0xdb, 0x27,
+	0xb7, 0xaa, 0xfb, 0x0e, 0xc3, 0xb7, 0xcf, 0x9e, 0x44, 0x2b, 0x10, 0x3a, 0x8f, 0xd9, 0x97, 0x89,
+	0x7a, 0x85, 0x62, 0x53, 0xdf, 0x91, 0x4c, 0xe5, 0x06, 0x14, 0xd3, 0x7b, 0x50, 0x38, 0x12, 0x09,
+	0xfe, 0x17, 0xde, 0xb9, 0x87, 0xd7, 0x6f, 0x03, 0xa8, 0x67, 0x44, 0x3c, 0xe1, 0xc5, 0xc8, 0x6b,
+	0x42, 0xc5, 0x13, 0x43, 0x7f, 0x15, 0x4a, 0xdb, 0xa6, 0x75, 0xda, 0x19, 0x98, 0x3d, 0x7c, 0xed,
+	0xc8, 0x0d, 0x4c, 0xeb, 0xd4, 0x5b, 0xeb, 0x46, 0x72, 0x2d, 0x5c, 0xa3, 0x89, 0x13, 0xa8, 0x44,
+	0xea, 0x3f, 0xd6, 0x80, 0x20, 0xd1, 0x33, 0xc7, 0x20, 0xb1, 0x94, 0x61, 0x44, 0x0b, 0x87, 0x91,
+	0x3a, 0x14, 0x8e, 0x1d, 0x7b, 0x3c, 0xda, 0xf0, 0xc2, 0x8b, 0xd7, 0x45, 0xfc, 0x40, 0xbc, 0x22,
+	0xca, 0xfa, 0x41, 0x76, 0xbe, 0x68, 0xd8, 0xd1, 0x7f, 0x8a, 0xde, 0x17, 0x08, 0xd1, 0x19, 0x0f,
+	0x87, 0x86, 0x73, 0xf1, 0x9f, 0x91, 0xe5, 0x37, 0xf8, 0xdc, 0x11, 0x56, 0x48, 0x10, 0xa9, 0x98,
+	0xcb, 0xcd, 0x21, 0x5e, 0x62, 0x42, 0x92, 0x22, 0x0d, 0x08, 0xd1, 0x32, 0x52, 0x56, 0x1e, 0x01,
+	0x01, 0xdd, 0x58, 0xd8, 0x5f, 0xc7, 0x87, 0xa8, 0x27, 0x8f, 0x28, 0x95, 0x34, 0x83, 0xb0, 0x21,
+	0xdf, 0x68, 0x16, 0x22, 0x45, 0x64, 0x22, 0x64, 0xfc, 0x2f, 0x54, 0xa8, 0xf1, 0xde, 0x6b, 0xa6,
+	0xcb, 0xed, 0x63, 0xc7, 0x18, 0xa2, 0x91, 0x1c, 0x8d, 0x7b, 0xa7, 0x8c, 0xab, 0x30, 0xa1, 0x7a,
+	0xb8, 0xf7, 0x5e, 0x48, 0x32, 0xd9, 0xd1, 0x5f, 0x87, 0xa2, 0x57, 0x86, 0xa5, 0x54, 0xd6, 0xcf,
+	0x45, 0x2b, 0xeb, 0xc5, 0x68, 0x9d, 0xff, 0xe6, 0x36, 0x96, 0xcf, 0x66, 0xcf, 0x8b, 0x9f, 0xbf,
+	0xd0, 0xa0, 0x1c, 0x12, 0x91, 0x6c, 0xc0, 0xdc, 0xc0, 0xe0, 0xcc, 0xea, 0x5d, 0x1c, 0x9e, 0x78,
+	0xe2, 0x29, 0xab, 0x0c, 0x6a, 0xf4, 0xb0, 0xec, 0xb4, 0xa6, 0xf0, 0xc1, 0x6e, 0xfe, 0x1b, 0xf2,
+	0x2e, 0x73, 0x4c, 0xe5, 0x90, 0xe1, 0x90, 0xeb, 0x57, 0x8f, 0x0a, 0x80, 0x1b, 0x97, 0x0e, 0xae,
+	0x14, 0xab, 0x7a, 0xfa, 0x5f, 0xa2, 0xd6, 0xad, 0x0c, 0x2b, 0x59, 0xf4, 0x5f, 0x71, 0x5a, 0xd3,
+	0xa9, 0xa7, 0x15, 0xc8, 0x97, 0xb9, 0x4a, 0xbe, 0x1a, 0x64, 0x46, 0xf7, 0xee, 0xa9, 0x92, 0x19,
+	0x9b, 0x92, 0xf2, 0xa2, 0x8a, 0x9f, 0xd8, 0x94, 0x94, 0x35, 0x55, 0x27, 0x62, 0x53, 0x50, 0x5e,
+	0x5c, 0x53, 0x05, 0x21, 0x36, 0xf5, 0xb7, 0xa1, 0x91, 0xe6, 0x27, 0xca, 0x44, 0xef, 0x41, 0xc9,
+	0x15, 0x24, 0x93, 0x25, 0x43, 0x40, 0xca, 0xbc, 0x00, 0xad, 0xff, 0x52, 0x83, 0x6a, 0xe4, 0x60,
+	0x23, 0x77, 0x67, 0x4e, 0xdd, 0x9d, 0x15, 0xd0, 0x2c, 0xa1, 0x8c, 0x0c, 0xd5, 0x2c, 0xec, 0x3d,
+	0x14, 0xfa, 0xd6, 0xa8, 0xf6, 0x10, 0x7b, 0xae, 0xfa, 0x5c, 0xa2, 0xe1, 0xe7, 0x11, 0xed, 0x48,
+	0x6c, 0xae, 0x48, 0xb5, 0x23, 0xec, 0xf5, 0xd5, 0xc6, 0xb4, 0x3e, 0x1e, 0x96, 0xfa, 0x32, 0x53,
+	0x10, 0xbc, 0x55, 0x0f, 0x57, 0x3c, 0x35, 0xad, 0xbe, 0x48, 0x61, 0x73, 0x54, 0xb4, 0x75, 0x06,
+	0xb3, 0x21, 0xc1, 0x37, 0x0d, 0x6e, 0x60, 0x7e, 0xea, 0x30, 0x77, 0x3c, 0xe0, 0xdd, 0xe0, 0x6a,
+	0x0f, 0x51, 0x30, 0xb7, 0x93, 0xbd, 0xfa, 0x74, 0x3c, 0xb7, 0x8b, 0xb8, 0xf5, 0x78, 0xc0, 0xa9,
+	0x42, 0x62, 0x14, 0x9c, 0x4b, 0x8c, 0xa2, 0x99, 0x0c, 0x8c, 0x23, 0x36, 0x08, 0xe5, 0x59, 0x01,
+	0x01, 0xe5, 0x10, 0x9d, 0x83, 0x50, 0x36, 0x11, 0xa2, 0x90, 0x55, 0x98, 0xe6, 0x9e, 0x69, 0xdc,
+	0x9a, 0x2c, 0xc3, 0xbe, 0x6d, 0x5a, 0x9c, 0x4e, 0x73, 0x17, 0x7d, 0x68, 0x31, 0x7d, 0x58, 0x1c,
+	0x86, 0xa9, 0x84, 0xa8, 0x52, 0xd1, 0x46, 0xeb, 0x38, 0x33, 0x06, 0x62, 0x61, 0x8d, 0x62, 0x13,
+	0xef, 0x67, 0x76, 0xce, 0x86, 0xa3, 0x81, 0x21, 0x3f, 0xba, 0x6d, 0x6d, 0x8a, 0xe3, 0xa9, 0xd0,
+	0x38, 0x19, 0xbf, 0xdc, 0x79, 0x24, 0xef, 0x53, 0x90, 0x32, 0xce, 0x04, 0x5d, 0xef, 0xc0, 0xbc,
+	0xf8, 0xaa, 0xb3, 0x65, 0xb9, 0xdc, 0xb0, 0xf8, 0xe5, 0x51, 0xd9, 0x8f, 0xb2, 0x2a, 0xd2, 0x44,
+	0xa2, 0xac, 0xf4, 0x4d, 0x6c, 0xea, 0xe7, 0xb0, 0x10, 0x65, 0xaa, 0x4c, 0xb8, 0xe9, 0xfb, 0x94,
+	0xb4, 0xdf, 0x20, 0xec, 0x28, 0x64, 0x47, 0x8c, 0xfa, 0x8e, 0xf5, 0xc8, 0xcf, 0xe5, 0xfa, 0x8f,
+	0x34, 0xa8, 0x46, 0x78, 0xe1, 0x97, 0x42, 0x71, 0x6c, 0x49, 0x9f, 0x49, 0xbe, 0x57, 0xa9, 0xcf,
+	0x70, 0x6a, 0x42, 0x34, 0x99, 0xd4, 0x54, 0x30, 0x24, 0xb7, 0xa0, 0x3c, 0x72, 0xec, 0xe1, 0xa1,
+	0xe2, 0x2a, 0x5f, 0x7d, 0x01, 0x49, 0xdb, 0x82, 0xa2, 0xff, 0x2e, 0x03, 0x73, 0x62, 0xfb, 0xd4,
+	0xb0, 0x8e, 0xd9, 0x63, 0xd1, 0xa8, 0x28, 0xe5, 0x38, 0x1b, 0xa9, 0x63, 0x14, 0xed, 0xe8, 0xc7,
+	0xde, 0x42, 0xfc, 0x63, 0x6f, 0xa8, 0xfc, 0x2d, 0x5e, 0x52, 0xfe, 0x96, 0xae, 0x2c, 0x7f, 0x21,
+	0xad, 0xfc, 0x0d, 0x15, 0x9d, 0xe5, 0x68, 0xd1, 0x19, 0x2e, 0x8c, 0x2b, 0xb1, 0xc2, 0xd8, 0x2b,
+	0x48, 0xab, 0x13, 0x0b, 0xd2, 0x99, 0x2f, 0x54, 0x90, 0xce, 0x3e, 0xf2, 0x3b, 0x06, 0xde, 0xef,
+	0xca, 0xf4, 0xdd, 0x7a, 0x4d, 0xee, 0xd9, 0x27, 0xe8, 0x2e, 0x90, 0xf0, 0x81, 0x29, 0x6b, 0x7d,
+	0x36, 0x66, 0xad, 0xf3, 0xc1, 0x25, 0x69, 0x0e, 0xd9, 0x57, 0x36, 0xd5, 0xf7, 0xa1, 0xd8, 0x56,
+	0x12, 0x3c, 0x7e, 0x23, 0x7d, 0x0a, 0x2a, 0x18, 0x46, 0x5c, 0x6e, 0x0c, 0x47, 0x87, 0x43, 0x69,
+	0xa5, 0x19, 0x5a, 0xf6, 0x69, 0x3b, 0xae, 0xbe, 0x0e, 0xf9, 0x8e, 0x81, 0x25, 0x42, 0x02, 0x3c,
+	0x9d, 0x00, 0x07, 0xab, 0x68, 0xa1, 0x55, 0xf4, 0x8f, 0x34, 0x80, 0x40, 0x17, 0x5f, 0x65, 0x17,
+	0xab, 0x50, 0x70, 0x85, 0x30, 0x5e, 0x3a, 0x30, 0x1b, 0xa8, 0x4f, 0xd0, 0x15, 0xde, 0x43, 0x5d,
+	0xe9, 0x85, 0xe4, 0xc5, 0xf0, 0x89, 0x67, 0x63, 0x57, 0xb8, 0xa7, 0x78, 0xc5, 0x35, 0x40, 0x3e,
+	0xf3, 0x1e, 0xcc, 0xc6, 0xaa, 0x0b, 0xfc, 0x3e, 0xb8, 0xbb, 0x77, 0xd8, 0xa6, 0x74, 0x8f, 0xd6,
+	0xa6, 0xc8, 0x3c, 0xcc, 0xee, 0xac, 0xbf, 0x73, 0xb8, 0xbd, 0x75, 0xd0, 0x3e, 0xec, 0xd2, 0xf5,
+	0xfb, 0xed, 0x4e, 0x4d, 0x43, 0xa2, 0x68, 0x1f, 0x76, 0xf7, 0xf6, 0x0e, 0xb7, 0xd7, 0xe9, 0x83,
+	0x76, 0x6d, 0x9a, 0xcc, 0x41, 0xf5, 0xad, 0xdd, 0x37, 0x76, 0xf7, 0xde, 0xde, 0x55, 0x93, 0x33,
+	0xe4, 0x3a, 0x5c, 0x8b, 0x4d, 0x3e, 0xdc, 0xf8, 0x6e, 0xb7, 0xdd, 0xa9, 0x65, 0x5b, 0x3f, 0xd3,
+	0x20, 0x8f, 0x2b, 0x33, 0x87, 0xfc, 0x3f, 0x94, 0xfc, 0xfa, 0x85, 0x5c, 0x8f, 0x54, 0x3d, 0xe1,
+	0x9a, 0xa6, 0x71, 0x2d, 0x32, 0xe4, 0xd9, 0xad, 0x3e, 0x45, 0xd6, 0xa1, 0xec, 0x83, 0x0f, 0x5a,
+	0x5f, 0x86, 0x45, 0xeb, 0x1f, 0x1a, 0xd4, 0x94, 0xc9, 0x3e, 0x60, 0x16, 0x73, 0x0c, 0x6e, 0xfb,
+	0x82, 0x89, 0x52, 0x26, 0xc6, 0x35, 0x5c, 0x17, 0x4d, 0x16, 0x6c, 0x0b, 0xe0, 0x01, 0xe3, 0x8a,
+	0x2f, 0xb9, 0x91, 0x7e, 0x6f, 0x4a, 0x1e, 0x37, 0xd3, 0x07, 0x7d, 0x56, 0x0f, 0x00, 0x02, 0x9f,
+	0x25, 0x41, 0x1a, 0x90, 0x88, 0xbc, 0x8d, 0x1b, 0xa9, 0x63, 0xfe, 0x4e, 0x7f, 0x9d, 0x85, 0x02,
+	0x0e, 0x98, 0xcc, 0x21, 0xaf, 0x41, 0xf5, 0x3b, 0xa6, 0xd5, 0xf7, 0xff, 0xe0, 0x41, 0xae, 0xa7,
+	0xfd, 0xaf, 0x44, 0xb2, 0x6d, 0x4c, 0xfe, 0xcb, 0x89, 0x38, 0x82, 0x8a, 0xf7, 0xc5, 0xb8, 0xc7,
+	0x2c, 0x4e, 0x26, 0xfc, 0x4f, 0xa1, 0xf1, 0x44, 0x82, 0xee, 0xb3, 0x68, 0x43, 0x39, 0xf4, 0x65,
+	0x36, 0xac, 0xad, 0xc4, 0x3f, 0x23, 0x2e, 0x63, 0xf3, 0x00, 0x20, 0x78, 0xa5, 0x22, 0x97, 0xbc,
+	0xb9, 0x37, 0x6e, 0xa4, 0x8e, 0xf9, 0x8c, 0xde, 0x80, 0x4a, 0x40, 0x3f, 0x68, 0x5d, 0xca, 0xea,
+	0xc9, 0xd4, 0x27, 0xb7, 0x10, 0xb3, 0x03, 0x98, 0x8d, 0xbd, 0xc8, 0x90, 0xab, 0x1e, 0x77, 0x1b,
+	0xcb, 0x93, 0x01, 0x3e, 0xdf, 0xef, 0xc1, 0x5c, 0x6c, 0xf0, 0xa0, 0x75, 0x35, 0x67, 0x7d, 0x12,
+	0x20, 0x2c, 0x73, 0xeb, 0xcf, 0x59, 0xa8, 0x75, 0xb8, 0xc3, 0x8c, 0xa1, 0x69, 0x1d, 0x7b, 0x26,
+	0xf3, 0x0a, 0xe4, 0xe5, 0x9c, 0x47, 0x3e, 0xe2, 0x35, 0x0d, 0xfd, 0xe1, 0xb1, 0x9c, 0xcd, 0x9a,
+	0x46, 0x76, 0x1e, 0xe3, 0xe9, 0xac, 0x69, 0xe4, 0x9d, 0xaf, 0xe7, 0x7c, 0xd6, 0x34, 0xf2, 0xfd,
+	0xaf, 0xef, 0x84, 0xd6, 0x34, 0xb2, 0x0f, 0x73, 0x2a, 0x56, 0x3c, 0x96, 0xe8, 0xb0, 0xa6, 0x91,
+	0x03, 0x98, 0x0f, 0x73, 0x54, 0xd9, 0x25, 0xb9, 0x19, 0x9d, 0x17, 0xcd, 0x9f, 0x1b, 0x4f, 0x4e,
+	0x18, 0x0d, 0xf8, 0xb6, 0x7e, 0xaf, 0x41, 0xc1, 0x8b, 0x84, 0x87, 0xa9, 0x85, 0xac, 0x7e, 0x59,
+	0x79, 0xa7, 0x16, 0x7a, 0xfa, 0x52, 0xcc, 0x63, 0x8f, 0x96, 0x1b, 0xf5, 0x0f, 0x3f, 0x5b, 0xd2,
+	0x3e, 0xfa, 0x6c, 0x49, 0xfb, 0xf4, 0xb3, 0x25, 0xed, 0xe7, 0x9f, 0x2f, 0x4d, 0x7d, 0xf4, 0xf9,
+	0xd2, 0xd4, 0xc7, 0x9f, 0x2f, 0x4d, 0x1d, 0xe5, 0xc5, 0xbf, 0x1d, 0x5f, 0xf8, 0xf7, 0x00, 0x29,
+	0x57, 0x42, 0xa1, 0x6e, 0x29, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -4931,6 +5070,11 @@ func (m *TraceByIDRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if m.MaxBytesPerTrace != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.MaxBytesPerTrace))
+		i--
+		dAtA[i] = 0x38
+	}
 	if m.AllowPartialTrace {
 		i--
 		if m.AllowPartialTrace {
@@ -5300,6 +5444,29 @@ func (m *SearchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Analysis) > 0 {
+		for iNdEx := len(m.Analysis) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Analysis[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintTempo(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if len(m.SkippedBlocks) > 0 {
+		for iNdEx := len(m.SkippedBlocks) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.SkippedBlocks[iNdEx])
+			copy(dAtA[i:], m.SkippedBlocks[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.SkippedBlocks[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if m.Metrics != nil {
 		{
 			size, err := m.Metrics.MarshalToSizedBuffer(dAtA[:i])
@@ -5329,6 +5496,61 @@ func (m *SearchResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
+func (m *SearchBlockAnalysis) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SearchBlockAnalysis) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *SearchBlockAnalysis) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.CombineNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.CombineNanos))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.FilterNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.FilterNanos))
+		i--
+		dAtA[i] = 0x28
+	}
+	if m.FetchNanos != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.FetchNanos))
+		i--
+		dAtA[i] = 0x20
+	}
+	if m.InspectedBytes != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.InspectedBytes))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.StartPage != 0 {
+		i = encodeVarintTempo(dAtA, i, uint64(m.StartPage))
+		i--
+		dAtA[i] = 0x10
+	}
+	if len(m.BlockID) > 0 {
+		i -= len(m.BlockID)
+		copy(dAtA[i:], m.BlockID)
+		i = encodeVarintTempo(dAtA, i, uint64(len(m.BlockID)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
 func (m *TraceSearchMetadata) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -5349,6 +5571,15 @@ func (m *TraceSearchMetadata) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	_ = i
 	var l int
 	_ = l
+	if len(m.Tenants) > 0 {
+		for iNdEx := len(m.Tenants) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Tenants[iNdEx])
+			copy(dAtA[i:], m.Tenants[iNdEx])
+			i = encodeVarintTempo(dAtA, i, uint64(len(m.Tenants[iNdEx])))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
 	if len(m.ServiceStats) > 0 {
 		for k := range m.ServiceStats {
 			v := m.ServiceStats[k]
@@ -7621,6 +7852,9 @@ func (m *TraceByIDRequest) Size() (n int) {
 	if m.AllowPartialTrace {
 		n += 2
 	}
+	if m.MaxBytesPerTrace != 0 {
+		n += 1 + sovTempo(uint64(m.MaxBytesPerTrace))
+	}
 	return n
 }
 
@@ -7784,6 +8018,46 @@ func (m *SearchResponse) Size() (n int) {
 		l = m.Metrics.Size()
 		n += 1 + l + sovTempo(uint64(l))
 	}
+	if len(m.SkippedBlocks) > 0 {
+		for _, s := range m.SkippedBlocks {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	if len(m.Analysis) > 0 {
+		for _, e := range m.Analysis {
+			l = e.Size()
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SearchBlockAnalysis) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.BlockID)
+	if l > 0 {
+		n += 1 + l + sovTempo(uint64(l))
+	}
+	if m.StartPage != 0 {
+		n += 1 + sovTempo(uint64(m.StartPage))
+	}
+	if m.InspectedBytes != 0 {
+		n += 1 + sovTempo(uint64(m.InspectedBytes))
+	}
+	if m.FetchNanos != 0 {
+		n += 1 + sovTempo(uint64(m.FetchNanos))
+	}
+	if m.FilterNanos != 0 {
+		n += 1 + sovTempo(uint64(m.FilterNanos))
+	}
+	if m.CombineNanos != 0 {
+		n += 1 + sovTempo(uint64(m.CombineNanos))
+	}
 	return n
 }
 
@@ -7834,8 +8108,14 @@ func (m *TraceSearchMetadata) Size() (n int) {
 			n += mapEntrySize + 1 + sovTempo(uint64(mapEntrySize))
 		}
 	}
-	return n
-}
+	if len(m.Tenants) > 0 {
+		for _, s := range m.Tenants {
+			l = len(s)
+			n += 1 + l + sovTempo(uint64(l))
+		}
+	}
+	return n
+}
 
 func (m *ServiceStats) Size() (n int) {
 	if m == nil {
@@ -9006,6 +9286,25 @@ func (m *TraceByIDRequest) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.AllowPartialTrace = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxBytesPerTrace", wireType)
+			}
+			m.MaxBytesPerTrace = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxBytesPerTrace |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -10154,6 +10453,249 @@ func (m *SearchResponse) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field SkippedBlocks", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.SkippedBlocks = append(m.SkippedBlocks, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Analysis", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Analysis = append(m.Analysis, &SearchBlockAnalysis{})
+			if err := m.Analysis[len(m.Analysis)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipTempo(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SearchBlockAnalysis) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowTempo
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SearchBlockAnalysis: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SearchBlockAnalysis: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BlockID", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.BlockID = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field StartPage", wireType)
+			}
+			m.StartPage = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.StartPage |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field InspectedBytes", wireType)
+			}
+			m.InspectedBytes = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.InspectedBytes |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FetchNanos", wireType)
+			}
+			m.FetchNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FetchNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FilterNanos", wireType)
+			}
+			m.FilterNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FilterNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CombineNanos", wireType)
+			}
+			m.CombineNanos = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CombineNanos |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -10537,6 +11079,38 @@ func (m *TraceSearchMetadata) Unmarshal(dAtA []byte) error {
 			}
 			m.ServiceStats[mapkey] = mapvalue
 			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Tenants", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowTempo
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthTempo
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthTempo
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Tenants = append(m.Tenants, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipTempo(dAtA[iNdEx:])
@@ -332,15 +332,27 @@ type SpansetExpression interface {
 }
 
 type SpansetOperation struct {
-	Op                  Operator
-	LHS                 SpansetExpression
-	RHS                 SpansetExpression
+	Op  Operator
+	LHS SpansetExpression
+	RHS SpansetExpression
+	// Depth is the max number of levels the RHS can be below the LHS of a descendant operation, 0 is unbounded.
+	Depth int
+	// On are the attributes that join the LHS and RHS of an and operation. Only pairs of distinct spans with equal
+	// values for all attributes match.
+	On                  []Attribute
 	matchingSpansBuffer []Span
 }
 
 func (o SpansetOperation) extractConditions(request *FetchSpansRequest) {
 	switch o.Op {
 	case OpSpansetDescendant, OpSpansetAncestor, OpSpansetNotDescendant, OpSpansetNotAncestor, OpSpansetUnionDescendant, OpSpansetUnionAncestor:
+		if o.Depth > 0 {
+			// bounded descendants are found by walking down the children level by level
+			request.Conditions = append(request.Conditions, Condition{
+				Attribute: NewIntrinsic(IntrinsicStructuralChild),
+			})
+			break
+		}
 		request.Conditions = append(request.Conditions, Condition{
 			Attribute: NewIntrinsic(IntrinsicStructuralDescendant),
 		})
@@ -354,6 +366,14 @@ func (o SpansetOperation) extractConditions(request *FetchSpansRequest) {
 		})
 	}
 
+	// the values of the join attributes are compared in the engine
+	for _, a := range o.On {
+		request.appendCondition(Condition{
+			Attribute: a,
+			Op:        OpNone,
+		})
+	}
+
 	o.LHS.extractConditions(request)
 	o.RHS.extractConditions(request)
 
@@ -368,6 +388,21 @@ func newSpansetOperation(op Operator, lhs, rhs SpansetExpression) SpansetOperati
 	}
 }
 
+// newSpansetDescendantOperation returns a descendant operation with the max depth of the descendants, 0 is unbounded.
+func newSpansetDescendantOperation(lhs SpansetExpression, depth int, rhs SpansetExpression) SpansetOperation {
+	o := newSpansetOperation(OpSpansetDescendant, lhs, rhs)
+	o.Depth = depth
+	return o
+}
+
+// newSpansetJoinOperation returns an and operation that only matches spans of the LHS and RHS with equal values for
+// the attributes.
+func newSpansetJoinOperation(lhs SpansetExpression, on []Attribute, rhs SpansetExpression) SpansetOperation {
+	o := newSpansetOperation(OpSpansetAnd, lhs, rhs)
+	o.On = on
+	return o
+}
+
 // nolint: revive
 func (SpansetOperation) __spansetExpression() {}
 
@@ -548,6 +583,83 @@ func (o UnaryOperation) referencesSpan() bool {
 	return o.Expression.referencesSpan()
 }
 
+// CalendarOperation is a calendar component of the span start time, e.g. hour(span:start)
+type CalendarOperation struct {
+	Function CalendarFunction
+}
+
+func newCalendarOperation(f CalendarFunction) CalendarOperation {
+	return CalendarOperation{
+		Function: f,
+	}
+}
+
+// nolint: revive
+func (CalendarOperation) __fieldExpression() {}
+
+func (CalendarOperation) impliedType() StaticType {
+	return TypeInt
+}
+
+func (CalendarOperation) referencesSpan() bool {
+	return true
+}
+
+// StringOperation applies a string function to an expression, e.g. replace(span.http.url, "/[0-9]+", "/{id}")
+type StringOperation struct {
+	Function   StringFunction
+	Expression FieldExpression
+	Args       []Static
+
+	parsed stringArgs
+	err    error
+}
+
+func newStringOperation(f StringFunction, e FieldExpression, args ...Static) FieldExpression {
+	strop := newGroupByStringOperation(f, e, args...)
+
+	if !strop.referencesSpan() && strop.validate() == nil {
+		if simplified, err := strop.execute(nil); err == nil {
+			return simplified
+		}
+	}
+
+	return strop
+}
+
+// newGroupByStringOperation returns the string operation without simplifying it. The by() clause of metrics queries
+// applies it to the values of an attribute.
+func newGroupByStringOperation(f StringFunction, e FieldExpression, args ...Static) *StringOperation {
+	strop := &StringOperation{
+		Function:   f,
+		Expression: e,
+		Args:       args,
+	}
+	// an invalid argument is reported by validate
+	strop.parsed, strop.err = f.parseArgs(args)
+
+	return strop
+}
+
+// nolint: revive
+func (*StringOperation) __fieldExpression() {}
+
+func (*StringOperation) impliedType() StaticType {
+	return TypeString
+}
+
+func (o *StringOperation) referencesSpan() bool {
+	return o.Expression.referencesSpan()
+}
+
+// apply returns the string function of the value. Values that aren't strings result in nil.
+func (o *StringOperation) apply(v Static) Static {
+	if v.Type != TypeString {
+		return NewStaticNil()
+	}
+	return NewStaticString(o.Function.apply(v.EncodeToString(false), o.parsed))
+}
+
 // **********************
 // Statics
 // **********************
@@ -1122,6 +1234,9 @@ type MetricsAggregate struct {
 	agg        SpanAggregator
 	seriesAgg  SeriesAggregator
 	exemplarFn getExemplar
+	byMissing  byMissingPolicy
+	// String functions applied to the values of the by() attributes
+	byTransforms []*StringOperation
 	// Type of operation for simple aggregatation in layers 2 and 3
 	simpleAggregationOp SimpleAggregationOp
 }
@@ -1221,9 +1336,17 @@ func (a *MetricsAggregate) init(q *tempopb.QueryRangeRequest, mode AggregateMode
 		return
 	}
 
-	a.agg = NewGroupingAggregator(a.op.String(), func() RangeAggregator {
+	a.agg = newGroupingAggregatorWithPolicy(a.op.String(), func() RangeAggregator {
 		return NewStepAggregator(q.Start, q.End, q.Step, innerAgg)
-	}, a.by, byFunc, byFuncLabel)
+	}, a.by, a.byTransforms, byFunc, byFuncLabel, a.byMissing)
+}
+
+func (a *MetricsAggregate) setByMissing(p byMissingPolicy) {
+	a.byMissing = p
+}
+
+func (a *MetricsAggregate) setByTransforms(t []*StringOperation) {
+	a.byTransforms = t
 }
 
 func bucketizeFnFor(attr Attribute) func(Span) (Static, bool) {
@@ -1352,14 +1475,14 @@ func (a *MetricsAggregate) validate() error {
 	case metricsAggregateMaxOverTime:
 	case metricsAggregateRate:
 	case metricsAggregateHistogramOverTime:
-		if len(a.by) >= maxGroupBys {
-			// We reserve a spot for the bucket so quantile has 1 less group by
-			return newUnsupportedError(fmt.Sprintf("metrics group by %v values", len(a.by)))
+		// We reserve a spot for the bucket so histogram has 1 less group by
+		if err := validateGroupBys(a.by, a.byTransforms, maxGroupBys-1); err != nil {
+			return err
 		}
 	case metricsAggregateQuantileOverTime:
-		if len(a.by) >= maxGroupBys {
-			// We reserve a spot for the bucket so quantile has 1 less group by
-			return newUnsupportedError(fmt.Sprintf("metrics group by %v values", len(a.by)))
+		// We reserve a spot for the bucket so quantile has 1 less group by
+		if err := validateGroupBys(a.by, a.byTransforms, maxGroupBys-1); err != nil {
+			return err
 		}
 		for _, q := range a.floats {
 			if q < 0 || q > 1 {
@@ -1370,11 +1493,27 @@ func (a *MetricsAggregate) validate() error {
 		return newUnsupportedError(fmt.Sprintf("metrics aggregate operation (%v)", a.op))
 	}
 
-	if len(a.by) > maxGroupBys {
-		return newUnsupportedError(fmt.Sprintf("metrics group by %v values", len(a.by)))
-	}
+	return validateGroupBys(a.by, a.byTransforms, maxGroupBys)
+}
 
+// validateGroupBys checks that the number of by() attributes is within the limit
+// and that their string functions are valid
+func validateGroupBys(by []Attribute, transforms []*StringOperation, limit int) error {
+	if len(by) > limit {
+		return newUnsupportedError(fmt.Sprintf("metrics group by %v values (max %v)", len(by), limit))
+	}
+	for _, t := range transforms {
+		if t == nil {
+			continue
+		}
+		if err := t.validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-var _ metricsFirstStageElement = (*MetricsAggregate)(nil)
+var (
+	_ metricsFirstStageElement = (*MetricsAggregate)(nil)
+	_ groupingElement          = (*MetricsAggregate)(nil)
+)
@@ -117,6 +117,18 @@ func (o UnaryOperation) extractConditions(request *FetchSpansRequest) {
 	o.Expression.extractConditions(request)
 }
 
+func (o CalendarOperation) extractConditions(request *FetchSpansRequest) {
+	request.appendCondition(Condition{
+		Attribute: IntrinsicSpanStartTimeAttribute,
+		Op:        OpNone,
+		Operands:  nil,
+	})
+}
+
+func (o *StringOperation) extractConditions(request *FetchSpansRequest) {
+	o.Expression.extractConditions(request)
+}
+
 func (s Static) extractConditions(*FetchSpansRequest) {
 }
 
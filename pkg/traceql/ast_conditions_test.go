@@ -105,6 +105,14 @@ func TestSpansetFilter_extractConditions(t *testing.T) {
 			},
 			allConditions: true,
 		},
+		{
+			query: `{ hour(span:start) >= 22 && .foo = 2 }`,
+			conditions: []Condition{
+				newCondition(IntrinsicSpanStartTimeAttribute, OpNone),
+				newCondition(NewAttribute("foo"), OpEqual, NewStaticInt(2)),
+			},
+			allConditions: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.query, func(t *testing.T) {
@@ -202,6 +210,15 @@ func TestStructuralNestedSet_extractConditions(t *testing.T) {
 			allConditions: true,
 		},
 
+		{
+			query: `{ .foo = "a" } && on(span.bar) { .foo = "b" }`,
+			conditions: []Condition{
+				newCondition(NewScopedAttribute(AttributeScopeSpan, false, "bar"), OpNone),
+				newCondition(NewAttribute("foo"), OpEqual, NewStaticString("a")),
+				newCondition(NewAttribute("foo"), OpEqual, NewStaticString("b")),
+			},
+			allConditions: false,
+		},
 		{
 			query: `{ nestedSetParent = 1 } > { nestedSetLeft < 3 }`,
 			conditions: []Condition{
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/grafana/tempo/pkg/regexp"
 )
@@ -90,6 +91,9 @@ func (o SpansetOperation) evaluate(input []*Spanset) (output []*Spanset, err err
 
 		switch o.Op {
 		case OpSpansetAnd:
+			if len(o.On) > 0 {
+				lhs, rhs = o.joinOn(lhs, rhs)
+			}
 			if len(lhs) > 0 && len(rhs) > 0 {
 				output = addSpanset(input[i], uniqueSpans(lhs, rhs), output)
 			}
@@ -117,6 +121,12 @@ func (o SpansetOperation) evaluate(input []*Spanset) (output []*Spanset, err err
 			relFn = func(s Span, l, r []Span) []Span {
 				return s.DescendantOf(l, r, falseForAll, invert, union, o.matchingSpansBuffer)
 			}
+			if o.Op == OpSpansetDescendant && o.Depth > 0 {
+				all := input[i].Spans
+				relFn = func(s Span, l, r []Span) []Span {
+					return o.boundedDescendantOf(s, l, r, all)
+				}
+			}
 
 		case OpSpansetNotChild: // !>
 			fallthrough
@@ -164,6 +174,100 @@ func (o SpansetOperation) evaluate(input []*Spanset) (output []*Spanset, err err
 	return output, nil
 }
 
+// boundedDescendantOf returns the spans in r that are at most o.Depth levels below a span in l. The descendants are
+// found by collecting the children of l in all, then their children, and so on.
+func (o *SpansetOperation) boundedDescendantOf(s Span, l, r, all []Span) []Span {
+	// the relationship functions may sort their inputs, so they get copies of the spansets
+	candidates := append([]Span(nil), all...)
+	level := append([]Span(nil), l...)
+
+	descendants := make(map[Span]struct{})
+	for depth := 0; depth < o.Depth && len(level) > 0; depth++ {
+		level = s.ChildOf(level, candidates, false, false, false, nil)
+		for _, d := range level {
+			descendants[d] = struct{}{}
+		}
+	}
+
+	buffer := o.matchingSpansBuffer[:0]
+	for _, span := range r {
+		if _, ok := descendants[span]; ok {
+			buffer = append(buffer, span)
+		}
+	}
+	return buffer
+}
+
+// joinOn returns the spans of lhs and rhs that have the same values for the join attributes as a different span of
+// the other side. Spans without a value for one of the attributes don't match.
+func (o *SpansetOperation) joinOn(lhs, rhs []*Spanset) ([]*Spanset, []*Spanset) {
+	lhsByKey := o.spansByJoinKey(lhs)
+	rhsByKey := o.spansByJoinKey(rhs)
+
+	return filterJoined(lhs, lhsByKey, rhsByKey), filterJoined(rhs, rhsByKey, lhsByKey)
+}
+
+// spansByJoinKey groups the spans by the values of the join attributes. Spans without a value for one of the
+// attributes are skipped.
+func (o *SpansetOperation) spansByJoinKey(input []*Spanset) map[string][]Span {
+	byKey := map[string][]Span{}
+	for _, ss := range input {
+		for _, s := range ss.Spans {
+			if key, ok := o.joinKey(s); ok {
+				byKey[key] = append(byKey[key], s)
+			}
+		}
+	}
+	return byKey
+}
+
+// joinKey encodes the values of the join attributes of the span. The values are length prefixed so the keys of
+// different values can't collide.
+func (o *SpansetOperation) joinKey(s Span) (string, bool) {
+	var sb strings.Builder
+	for _, a := range o.On {
+		v, ok := s.AttributeFor(a)
+		if !ok || v.Type == TypeNil {
+			return "", false
+		}
+		k := v.MapKey()
+		fmt.Fprintf(&sb, "%d:%d:%d:%s", k.typ, k.code, len(k.str), k.str)
+	}
+	return sb.String(), true
+}
+
+// filterJoined returns the spans of input that have the same key as a different span in other.
+func filterJoined(input []*Spanset, byKey, other map[string][]Span) []*Spanset {
+	matched := map[Span]struct{}{}
+	for key, spans := range byKey {
+		candidates := other[key]
+		for _, s := range spans {
+			if len(candidates) > 1 || (len(candidates) == 1 && candidates[0] != s) {
+				matched[s] = struct{}{}
+			}
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	output := make([]*Spanset, 0, len(input))
+	for _, ss := range input {
+		spans := make([]Span, 0, len(ss.Spans))
+		for _, s := range ss.Spans {
+			if _, ok := matched[s]; ok {
+				spans = append(spans, s)
+			}
+		}
+		if len(spans) > 0 {
+			joined := ss.clone()
+			joined.Spans = spans
+			output = append(output, joined)
+		}
+	}
+	return output
+}
+
 // joinSpansets compares all pairwise combinations of the inputs and returns the right-hand side
 // where the eval callback returns true.  For now the behavior is only defined when there is exactly one
 // spanset on both sides and will return an error if multiple spansets are present.
@@ -253,6 +357,10 @@ func (a Aggregate) evaluate(input []*Spanset) (output []*Spanset, err error) {
 				if err != nil {
 					return nil, err
 				}
+				// spans that don't have the value don't contribute to the average
+				if val.Type == TypeNil {
+					continue
+				}
 
 				if sum == nil {
 					sum = &val
@@ -263,7 +371,10 @@ func (a Aggregate) evaluate(input []*Spanset) (output []*Spanset, err error) {
 			}
 
 			cpy := ss.clone()
-			cpy.Scalar = sum.divideBy(float64(count))
+			cpy.Scalar = NewStaticNil()
+			if sum != nil {
+				cpy.Scalar = sum.divideBy(float64(count))
+			}
 			cpy.AddAttribute(a.String(), cpy.Scalar)
 			output = append(output, cpy)
 
@@ -274,12 +385,18 @@ func (a Aggregate) evaluate(input []*Spanset) (output []*Spanset, err error) {
 				if err != nil {
 					return nil, err
 				}
+				if val.Type == TypeNil {
+					continue
+				}
 				if maxS == nil || val.compare(maxS) > 0 {
 					maxS = &val
 				}
 			}
 			cpy := ss.clone()
-			cpy.Scalar = *maxS
+			cpy.Scalar = NewStaticNil()
+			if maxS != nil {
+				cpy.Scalar = *maxS
+			}
 			cpy.AddAttribute(a.String(), cpy.Scalar)
 			output = append(output, cpy)
 
@@ -290,12 +407,18 @@ func (a Aggregate) evaluate(input []*Spanset) (output []*Spanset, err error) {
 				if err != nil {
 					return nil, err
 				}
+				if val.Type == TypeNil {
+					continue
+				}
 				if minS == nil || val.compare(minS) == -1 {
 					minS = &val
 				}
 			}
 			cpy := ss.clone()
-			cpy.Scalar = *minS
+			cpy.Scalar = NewStaticNil()
+			if minS != nil {
+				cpy.Scalar = *minS
+			}
 			cpy.AddAttribute(a.String(), cpy.Scalar)
 			output = append(output, cpy)
 
@@ -306,6 +429,9 @@ func (a Aggregate) evaluate(input []*Spanset) (output []*Spanset, err error) {
 				if err != nil {
 					return nil, err
 				}
+				if val.Type == TypeNil {
+					continue
+				}
 				if sum == nil {
 					sum = &val
 				} else {
@@ -313,7 +439,40 @@ func (a Aggregate) evaluate(input []*Spanset) (output []*Spanset, err error) {
 				}
 			}
 			cpy := ss.clone()
-			cpy.Scalar = *sum
+			cpy.Scalar = NewStaticNil()
+			if sum != nil {
+				cpy.Scalar = *sum
+			}
+			cpy.AddAttribute(a.String(), cpy.Scalar)
+			output = append(output, cpy)
+
+		case aggregateStddev:
+			var vals []float64
+			durations := true
+			for _, s := range ss.Spans {
+				val, err := a.e.execute(s)
+				if err != nil {
+					return nil, err
+				}
+				if !val.isNumeric() {
+					continue
+				}
+				if val.Type != TypeDuration {
+					durations = false
+				}
+				vals = append(vals, val.Float())
+			}
+			cpy := ss.clone()
+			cpy.Scalar = NewStaticNil()
+			if len(vals) > 0 {
+				// the stddev of durations is a duration, everything else is a float
+				stddev := populationStddev(vals)
+				if durations {
+					cpy.Scalar = NewStaticDuration(time.Duration(stddev))
+				} else {
+					cpy.Scalar = NewStaticFloat(stddev)
+				}
+			}
 			cpy.AddAttribute(a.String(), cpy.Scalar)
 			output = append(output, cpy)
 
@@ -325,6 +484,20 @@ func (a Aggregate) evaluate(input []*Spanset) (output []*Spanset, err error) {
 	return output, nil
 }
 
+func populationStddev(vals []float64) float64 {
+	mean := 0.0
+	for _, v := range vals {
+		mean += v
+	}
+	mean /= float64(len(vals))
+
+	variance := 0.0
+	for _, v := range vals {
+		variance += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(variance / float64(len(vals)))
+}
+
 func (o *BinaryOperation) execute(span Span) (Static, error) {
 	recording := o.b.Recording
 	if recording {
@@ -641,6 +814,21 @@ func (o UnaryOperation) execute(span Span) (Static, error) {
 	return NewStaticNil(), errors.New("UnaryOperation has Op different from Not and Sub")
 }
 
+func (o CalendarOperation) execute(span Span) (Static, error) {
+	if span == nil {
+		return NewStaticNil(), fmt.Errorf("expression (%v) requires a span", o)
+	}
+	return NewStaticInt(o.Function.apply(time.Unix(0, int64(span.StartTimeUnixNanos())))), nil
+}
+
+func (o *StringOperation) execute(span Span) (Static, error) {
+	static, err := o.Expression.execute(span)
+	if err != nil {
+		return NewStaticNil(), err
+	}
+	return o.apply(static), nil
+}
+
 func (s Static) execute(Span) (Static, error) {
 	return s, nil
 }
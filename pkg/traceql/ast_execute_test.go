@@ -297,6 +297,43 @@ func TestSpansetOperationEvaluate(t *testing.T) {
 				}},
 			},
 		},
+		{
+			"{ .foo = `a` } && on(.k) { .foo = `b` }",
+			[]*Spanset{
+				{Spans: []Span{
+					// Spans 1 and 2 are kept because they share a value for .k, span 3 has no partner
+					&mockSpan{id: []byte{1}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("k"): NewStaticInt(1)}},
+					&mockSpan{id: []byte{2}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("b"), NewAttribute("k"): NewStaticInt(1)}},
+					&mockSpan{id: []byte{3}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("b"), NewAttribute("k"): NewStaticInt(2)}},
+				}},
+				{Spans: []Span{
+					// This spanset will be dropped, both sides match but .k differs
+					&mockSpan{id: []byte{4}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("k"): NewStaticInt(1)}},
+					&mockSpan{id: []byte{5}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("b"), NewAttribute("k"): NewStaticInt(2)}},
+				}},
+				{Spans: []Span{
+					// This spanset will be dropped, .k is missing
+					&mockSpan{id: []byte{6}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+					&mockSpan{id: []byte{7}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("b")}},
+				}},
+			},
+			[]*Spanset{
+				{Spans: []Span{
+					&mockSpan{id: []byte{2}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("b"), NewAttribute("k"): NewStaticInt(1)}},
+					&mockSpan{id: []byte{1}, attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("k"): NewStaticInt(1)}},
+				}},
+			},
+		},
+		{
+			"{ .k = 1 } && on(.k) { .k = 1 }",
+			[]*Spanset{
+				{Spans: []Span{
+					// A span can not be joined with itself
+					&mockSpan{id: []byte{1}, attributes: map[Attribute]Static{NewAttribute("k"): NewStaticInt(1)}},
+				}},
+			},
+			[]*Spanset{},
+		},
 		{
 			"{ .foo = `a` } || { .foo = `b` }",
 			[]*Spanset{
@@ -360,6 +397,39 @@ func TestSpansetOperationEvaluate(t *testing.T) {
 				}},
 			},
 		},
+		{
+			"{ .root } >>2 { .leaf }",
+			[]*Spanset{
+				{Spans: []Span{
+					newMockSpan([]byte{1}).WithAttrBool("root", true).WithNestedSetInfo(0, 1, 8),
+					newMockSpan([]byte{2}).WithNestedSetInfo(1, 2, 7),
+					newMockSpan([]byte{3}).WithAttrBool("leaf", true).WithNestedSetInfo(2, 3, 6),
+					newMockSpan([]byte{4}).WithAttrBool("leaf", true).WithNestedSetInfo(3, 4, 5),
+				}},
+			},
+			[]*Spanset{
+				{Spans: []Span{
+					newMockSpan([]byte{3}).WithAttrBool("leaf", true).WithNestedSetInfo(2, 3, 6),
+				}},
+			},
+		},
+		{
+			"{ .root } >>3 { .leaf }",
+			[]*Spanset{
+				{Spans: []Span{
+					newMockSpan([]byte{1}).WithAttrBool("root", true).WithNestedSetInfo(0, 1, 8),
+					newMockSpan([]byte{2}).WithNestedSetInfo(1, 2, 7),
+					newMockSpan([]byte{3}).WithAttrBool("leaf", true).WithNestedSetInfo(2, 3, 6),
+					newMockSpan([]byte{4}).WithAttrBool("leaf", true).WithNestedSetInfo(3, 4, 5),
+				}},
+			},
+			[]*Spanset{
+				{Spans: []Span{
+					newMockSpan([]byte{3}).WithAttrBool("leaf", true).WithNestedSetInfo(2, 3, 6),
+					newMockSpan([]byte{4}).WithAttrBool("leaf", true).WithNestedSetInfo(3, 4, 5),
+				}},
+			},
+		},
 		{
 			"{ .child } << { .parent }",
 			[]*Spanset{
@@ -1546,6 +1616,116 @@ func TestScalarFilterEvaluate(t *testing.T) {
 				},
 			},
 		},
+		// spans without the aggregated value are ignored
+		{
+			"{ .foo = `a` } | avg(.bar) = 4",
+			[]*Spanset{
+				{Spans: []Span{
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(4)}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+				}},
+				{Spans: []Span{
+					// no spans with the value
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+				}},
+			},
+			[]*Spanset{
+				{
+					Scalar: NewStaticFloat(4),
+					Spans: []Span{
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(4)}},
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+					},
+					Attributes: []*SpansetAttribute{{Name: "avg(.bar)", Val: NewStaticFloat(4)}},
+				},
+			},
+		},
+		{
+			"{ .foo = `a` } | max(.bar) = 4",
+			[]*Spanset{
+				{Spans: []Span{
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(4)}},
+				}},
+				{Spans: []Span{
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+				}},
+			},
+			[]*Spanset{
+				{
+					Scalar: NewStaticInt(4),
+					Spans: []Span{
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(4)}},
+					},
+					Attributes: []*SpansetAttribute{{Name: "max(.bar)", Val: NewStaticInt(4)}},
+				},
+			},
+		},
+		{
+			"{ .foo = `a` } | sum(.bar) = 7",
+			[]*Spanset{
+				{Spans: []Span{
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(3)}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(4)}},
+				}},
+			},
+			[]*Spanset{
+				{
+					Scalar: NewStaticInt(7),
+					Spans: []Span{
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(3)}},
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(4)}},
+					},
+					Attributes: []*SpansetAttribute{{Name: "sum(.bar)", Val: NewStaticInt(7)}},
+				},
+			},
+		},
+		{
+			"{ .foo = `a` } | stddev(.bar) = 2",
+			[]*Spanset{
+				{Spans: []Span{
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(2)}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(6)}},
+				}},
+				{Spans: []Span{
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(4)}},
+				}},
+			},
+			[]*Spanset{
+				{
+					Scalar: NewStaticFloat(2),
+					Spans: []Span{
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a")}},
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(2)}},
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewAttribute("bar"): NewStaticInt(6)}},
+					},
+					Attributes: []*SpansetAttribute{{Name: "stddev(.bar)", Val: NewStaticFloat(2)}},
+				},
+			},
+		},
+		{
+			"{ .foo = `a` } | stddev(duration) = 3ms",
+			[]*Spanset{
+				{Spans: []Span{
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewIntrinsic(IntrinsicDuration): NewStaticDuration(2 * time.Millisecond)}},
+					&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewIntrinsic(IntrinsicDuration): NewStaticDuration(8 * time.Millisecond)}},
+				}},
+			},
+			[]*Spanset{
+				{
+					Scalar: NewStaticDuration(3 * time.Millisecond),
+					Spans: []Span{
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewIntrinsic(IntrinsicDuration): NewStaticDuration(2 * time.Millisecond)}},
+						&mockSpan{attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("a"), NewIntrinsic(IntrinsicDuration): NewStaticDuration(8 * time.Millisecond)}},
+					},
+					Attributes: []*SpansetAttribute{{Name: "stddev(duration)", Val: NewStaticDuration(3 * time.Millisecond)}},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1899,6 +2079,92 @@ func TestArithmetic(t *testing.T) {
 	}
 }
 
+func TestCalendarFunctions(t *testing.T) {
+	// saturday 2024-06-01 23:30 UTC and monday 2024-06-03 10:15 UTC
+	saturday := uint64(time.Date(2024, 6, 1, 23, 30, 0, 0, time.UTC).UnixNano())
+	monday := uint64(time.Date(2024, 6, 3, 10, 15, 0, 0, time.UTC).UnixNano())
+
+	input := []*Spanset{{Spans: []Span{
+		newMockSpan([]byte{1}).WithStartTime(saturday),
+		newMockSpan([]byte{2}).WithStartTime(monday),
+	}}}
+
+	testCases := []evalTC{
+		{
+			"{ hour(span:start) >= 22 }",
+			input,
+			[]*Spanset{{Spans: []Span{newMockSpan([]byte{1}).WithStartTime(saturday)}}},
+		},
+		{
+			"{ minute(span:start) = 15 }",
+			input,
+			[]*Spanset{{Spans: []Span{newMockSpan([]byte{2}).WithStartTime(monday)}}},
+		},
+		{
+			"{ day_of_week(span:start) = 0 || day_of_week(span:start) = 6 }",
+			input,
+			[]*Spanset{{Spans: []Span{newMockSpan([]byte{1}).WithStartTime(saturday)}}},
+		},
+		{
+			"{ day_of_month(span:start) = 3 && month(span:start) = 6 && year(span:start) = 2024 }",
+			input,
+			[]*Spanset{{Spans: []Span{newMockSpan([]byte{2}).WithStartTime(monday)}}},
+		},
+		{
+			"{ hour(span:start) * 60 + minute(span:start) > 600 }",
+			input,
+			input,
+		},
+	}
+	for _, tc := range testCases {
+		testEvaluator(t, tc)
+	}
+}
+
+func TestStringFunctions(t *testing.T) {
+	input := []*Spanset{{Spans: []Span{
+		newMockSpan([]byte{1}).WithSpanString("url", "/users/123/orders/45").WithSpanString("method", "GET"),
+		newMockSpan([]byte{2}).WithSpanString("url", "/health").WithSpanString("method", "post"),
+		newMockSpan([]byte{3}).WithSpanInt("url", 1),
+	}}}
+
+	testCases := []evalTC{
+		{
+			`{ lower(span.method) = "get" }`,
+			input,
+			[]*Spanset{{Spans: []Span{input[0].Spans[0]}}},
+		},
+		{
+			`{ replace(span.url, "/[0-9]+", "/{id}") = "/users/{id}/orders/{id}" }`,
+			input,
+			[]*Spanset{{Spans: []Span{input[0].Spans[0]}}},
+		},
+		{
+			`{ replace(span.url, "^/([a-z]+)/.*", "$1") = "users" }`,
+			input,
+			[]*Spanset{{Spans: []Span{input[0].Spans[0]}}},
+		},
+		{
+			`{ substring(span.url, 1, 6) = "health" }`,
+			input,
+			[]*Spanset{{Spans: []Span{input[0].Spans[1]}}},
+		},
+		{
+			`{ substring(span.url, 20) = "" }`,
+			input,
+			[]*Spanset{{Spans: []Span{input[0].Spans[0], input[0].Spans[1]}}},
+		},
+		{
+			`{ lower(span.url) = nil }`,
+			input,
+			[]*Spanset{{Spans: []Span{input[0].Spans[2]}}},
+		},
+	}
+	for _, tc := range testCases {
+		testEvaluator(t, tc)
+	}
+}
+
 func TestSpansetExistence(t *testing.T) {
 	tests := []struct {
 		query   string
@@ -58,6 +58,16 @@ func (a Aggregate) String() string {
 }
 
 func (o SpansetOperation) String() string {
+	if len(o.On) > 0 {
+		on := make([]string, 0, len(o.On))
+		for _, a := range o.On {
+			on = append(on, a.String())
+		}
+		return wrapElement(o.LHS) + " " + o.Op.String() + " on(" + strings.Join(on, ", ") + ") " + wrapElement(o.RHS)
+	}
+	if o.Depth > 0 {
+		return wrapElement(o.LHS) + " " + o.Op.String() + strconv.Itoa(o.Depth) + " " + wrapElement(o.RHS)
+	}
 	return binaryOp(o.Op, o.LHS, o.RHS)
 }
 
@@ -77,6 +87,19 @@ func (o UnaryOperation) String() string {
 	return unaryOp(o.Op, o.Expression)
 }
 
+func (o CalendarOperation) String() string {
+	return o.Function.String() + "(span:start)"
+}
+
+func (o *StringOperation) String() string {
+	args := make([]string, 0, len(o.Args)+1)
+	args = append(args, o.Expression.String())
+	for _, a := range o.Args {
+		args = append(args, a.String())
+	}
+	return o.Function.String() + "(" + strings.Join(args, ", ") + ")"
+}
+
 func (s Static) String() string {
 	return s.EncodeToString(true)
 }
@@ -201,8 +224,8 @@ func (a MetricsAggregate) String() string {
 
 	if len(a.by) > 0 {
 		s.WriteString("by(")
-		for i, b := range a.by {
-			s.WriteString(b.String())
+		for i := range a.by {
+			s.WriteString(groupByString(a.by, a.byTransforms, i))
 			if i < len(a.by)-1 {
 				s.WriteString(",")
 			}
@@ -237,5 +260,13 @@ func wrapElement(e Element) string {
 	if ok {
 		return att.String()
 	}
+	calendar, ok := e.(CalendarOperation)
+	if ok {
+		return calendar.String()
+	}
+	strop, ok := e.(*StringOperation)
+	if ok {
+		return strop.String()
+	}
 	return "(" + e.String() + ")"
 }
@@ -24,6 +24,12 @@ func (r RootExpr) validate() error {
 		return err
 	}
 
+	if v, ok := r.Hints.GetString(HintByMissing, false); ok {
+		if _, err := byMissingPolicyFromString(v); err != nil {
+			return err
+		}
+	}
+
 	if r.MetricsPipeline != nil {
 		return r.MetricsPipeline.validate()
 	}
@@ -105,7 +111,7 @@ func (a Aggregate) validate() error {
 	}
 
 	switch a.op {
-	case aggregateCount, aggregateAvg, aggregateMin, aggregateMax, aggregateSum:
+	case aggregateCount, aggregateAvg, aggregateMin, aggregateMax, aggregateSum, aggregateStddev:
 	default:
 		return newUnsupportedError(fmt.Sprintf("aggregate operation (%v)", a.op))
 	}
@@ -241,6 +247,26 @@ func (o UnaryOperation) validate() error {
 	return nil
 }
 
+func (o CalendarOperation) validate() error {
+	return nil
+}
+
+func (o *StringOperation) validate() error {
+	if err := o.Expression.validate(); err != nil {
+		return err
+	}
+	if o.err != nil {
+		return o.err
+	}
+
+	t := o.Expression.impliedType()
+	if t != TypeString && t != TypeAttribute {
+		return fmt.Errorf("illegal operation for the given type: %s", o.String())
+	}
+
+	return nil
+}
+
 func (s Static) validate() error {
 	return nil
 }
@@ -1,6 +1,7 @@
 package traceql
 
 import (
+	"slices"
 	"sort"
 	"strings"
 
@@ -9,6 +10,14 @@ import (
 
 type MetadataCombiner struct {
 	trs map[string]*tempopb.TraceSearchMetadata
+
+	// keepMostRecent bounds the combiner to the limit most recent traces by start time. older traces
+	// are dropped as newer ones are added.
+	keepMostRecent bool
+	limit          int
+
+	// maxSpanSetsPerTrace bounds the spansets of each trace. 0 is unlimited
+	maxSpanSetsPerTrace int
 }
 
 func NewMetadataCombiner() *MetadataCombiner {
@@ -17,15 +26,66 @@ func NewMetadataCombiner() *MetadataCombiner {
 	}
 }
 
+// NewMostRecentMetadataCombiner returns a combiner that only keeps the limit most recent traces
+func NewMostRecentMetadataCombiner(limit int) *MetadataCombiner {
+	return &MetadataCombiner{
+		trs:            make(map[string]*tempopb.TraceSearchMetadata, limit),
+		keepMostRecent: true,
+		limit:          limit,
+	}
+}
+
+// SetMaxSpanSetsPerTrace bounds the number of spansets kept for each trace. Spansets beyond the limit are dropped
+// in the order they are added. 0 disables the limit.
+func (c *MetadataCombiner) SetMaxSpanSetsPerTrace(maxSpanSets int) {
+	c.maxSpanSetsPerTrace = maxSpanSets
+}
+
 // AddMetadata adds the new metadata to the map. if it already exists
-// use CombineSearchResults to combine the two
-func (c *MetadataCombiner) AddMetadata(new *tempopb.TraceSearchMetadata) {
+// use CombineSearchResults to combine the two. It returns false if the metadata
+// was dropped because the combiner only keeps the most recent traces.
+func (c *MetadataCombiner) AddMetadata(new *tempopb.TraceSearchMetadata) bool {
 	if existing, ok := c.trs[new.TraceID]; ok {
 		combineSearchResults(existing, new)
-		return
+		c.truncateSpanSets(existing)
+		return true
 	}
 
+	if c.keepMostRecent && c.limit > 0 && len(c.trs) >= c.limit {
+		oldest := c.oldest()
+		if new.StartTimeUnixNano <= oldest.StartTimeUnixNano {
+			return false
+		}
+		delete(c.trs, oldest.TraceID)
+	}
+
+	c.truncateSpanSets(new)
 	c.trs[new.TraceID] = new
+	return true
+}
+
+func (c *MetadataCombiner) truncateSpanSets(tr *tempopb.TraceSearchMetadata) {
+	if c.maxSpanSetsPerTrace > 0 && len(tr.SpanSets) > c.maxSpanSetsPerTrace {
+		tr.SpanSets = tr.SpanSets[:c.maxSpanSetsPerTrace]
+	}
+}
+
+// OldestStartTimeUnixNano returns the start time of the oldest trace in the combiner or 0 if it is empty
+func (c *MetadataCombiner) OldestStartTimeUnixNano() uint64 {
+	if oldest := c.oldest(); oldest != nil {
+		return oldest.StartTimeUnixNano
+	}
+	return 0
+}
+
+func (c *MetadataCombiner) oldest() *tempopb.TraceSearchMetadata {
+	var oldest *tempopb.TraceSearchMetadata
+	for _, tr := range c.trs {
+		if oldest == nil || tr.StartTimeUnixNano < oldest.StartTimeUnixNano {
+			oldest = tr
+		}
+	}
+	return oldest
 }
 
 func (c *MetadataCombiner) Count() int {
@@ -90,6 +150,14 @@ func combineSearchResults(existing *tempopb.TraceSearchMetadata, incoming *tempo
 		existingStats.ErrorCount = max(existingStats.ErrorCount, incomingStats.ErrorCount)
 	}
 
+	// a trace can be found in several tenants of a multi-tenant query
+	for _, t := range incoming.Tenants {
+		if !slices.Contains(existing.Tenants, t) {
+			existing.Tenants = append(existing.Tenants, t)
+		}
+	}
+	sort.Strings(existing.Tenants)
+
 	// make a map of existing Spansets
 	existingSS := make(map[string]*tempopb.SpanSet)
 	for _, ss := range existing.SpanSets {
@@ -183,6 +251,11 @@ func (q *QueryRangeCombiner) Combine(resp *tempopb.QueryRangeResponse) {
 	}
 }
 
+// SeriesCount returns the number of series in the combined results.
+func (q *QueryRangeCombiner) SeriesCount() int {
+	return len(q.eval.Results())
+}
+
 func (q *QueryRangeCombiner) Response() *tempopb.QueryRangeResponse {
 	return &tempopb.QueryRangeResponse{
 		Series:  q.eval.Results().ToProto(q.req),
@@ -240,6 +240,18 @@ func TestCombineResults(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "merge tenants",
+			existing: &tempopb.TraceSearchMetadata{
+				Tenants: []string{"tenant-b", "tenant-c"},
+			},
+			new: &tempopb.TraceSearchMetadata{
+				Tenants: []string{"tenant-a", "tenant-b"},
+			},
+			expected: &tempopb.TraceSearchMetadata{
+				Tenants: []string{"tenant-a", "tenant-b", "tenant-c"},
+			},
+		},
 	}
 
 	for _, tc := range tcs {
@@ -263,3 +275,45 @@ func TestCombineResults(t *testing.T) {
 		})
 	}
 }
+
+func TestMostRecentMetadataCombiner(t *testing.T) {
+	c := NewMostRecentMetadataCombiner(2)
+
+	require.True(t, c.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "1", StartTimeUnixNano: 10}))
+	require.True(t, c.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "2", StartTimeUnixNano: 20}))
+	require.Equal(t, uint64(10), c.OldestStartTimeUnixNano())
+
+	// older than everything kept
+	require.False(t, c.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "3", StartTimeUnixNano: 5}))
+
+	// replaces the oldest
+	require.True(t, c.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "4", StartTimeUnixNano: 30}))
+	require.Equal(t, uint64(20), c.OldestStartTimeUnixNano())
+
+	// existing traces are always combined
+	require.True(t, c.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "2", StartTimeUnixNano: 15, DurationMs: 100}))
+
+	require.Equal(t, []*tempopb.TraceSearchMetadata{
+		{TraceID: "4", StartTimeUnixNano: 30},
+		{TraceID: "2", StartTimeUnixNano: 15, DurationMs: 100},
+	}, c.Metadata())
+}
+
+func TestMetadataCombinerMaxSpanSetsPerTrace(t *testing.T) {
+	spanset := func(by string) *tempopb.SpanSet {
+		return &tempopb.SpanSet{Attributes: []*v1.KeyValue{{Key: "by(.foo)", Value: &v1.AnyValue{Value: &v1.AnyValue_StringValue{StringValue: by}}}}}
+	}
+
+	c := NewMetadataCombiner()
+	c.SetMaxSpanSetsPerTrace(2)
+
+	c.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "1", SpanSets: []*tempopb.SpanSet{spanset("a"), spanset("b"), spanset("c")}})
+	c.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "2", SpanSets: []*tempopb.SpanSet{spanset("a")}})
+	c.AddMetadata(&tempopb.TraceSearchMetadata{TraceID: "2", SpanSets: []*tempopb.SpanSet{spanset("b"), spanset("c")}})
+
+	for _, tr := range c.Metadata() {
+		require.Len(t, tr.SpanSets, 2)
+		require.Equal(t, spanset("a"), tr.SpanSets[0])
+		require.Equal(t, spanset("b"), tr.SpanSets[1])
+	}
+}
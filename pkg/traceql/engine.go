@@ -72,6 +72,11 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 	meta := SearchMetaConditionsWithout(fetchSpansRequest.Conditions, fetchSpansRequest.AllConditions)
 	fetchSpansRequest.SecondPassConditions = append(fetchSpansRequest.SecondPassConditions, meta...)
 
+	// the analyze hint returns the time spent in each stage of the search. the query is evaluated while fetching
+	// so the time of the filter stage is taken out of the fetch stage
+	analyze, _ := rootExpr.Hints.GetBool(HintAnalyze, false)
+	fetchTimer, filterTimer, combineTimer := stageTimer{enabled: analyze}, stageTimer{enabled: analyze}, stageTimer{enabled: analyze}
+
 	spansetsEvaluated := 0
 	// set up the expression evaluation as a filter to reduce data pulled
 	fetchSpansRequest.SecondPass = func(inSS *Spanset) ([]*Spanset, error) {
@@ -79,6 +84,9 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 			return nil, nil
 		}
 
+		start := filterTimer.start()
+		defer filterTimer.stop(start)
+
 		evalSS, err := rootExpr.Pipeline.evaluate([]*Spanset{inSS})
 		if err != nil {
 			span.RecordError(err, trace.WithAttributes(attribute.String("msg", "pipeline.evaluate")))
@@ -107,7 +115,9 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 		return evalSS, nil
 	}
 
+	start := fetchTimer.start()
 	fetchSpansResponse, err := spanSetFetcher.Fetch(ctx, *fetchSpansRequest)
+	fetchTimer.stop(start)
 	if err != nil {
 		return nil, err
 	}
@@ -118,9 +128,17 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 		Traces:  nil,
 		Metrics: &tempopb.SearchMetrics{},
 	}
+	// most recent searches must see every matching trace to return the newest ones. otherwise stop
+	// at the limit
+	mostRecent, _ := rootExpr.Hints.GetBool(HintMostRecent, false)
 	combiner := NewMetadataCombiner()
+	if mostRecent && searchReq.Limit > 0 {
+		combiner = NewMostRecentMetadataCombiner(int(searchReq.Limit))
+	}
 	for {
+		start := fetchTimer.start()
 		spanset, err := iterator.Next(ctx)
+		fetchTimer.stop(start)
 		if err != nil && !errors.Is(err, io.EOF) {
 			span.RecordError(err, trace.WithAttributes(attribute.String("msg", "iterator.Next")))
 			return nil, err
@@ -128,13 +146,18 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 		if spanset == nil {
 			break
 		}
+
+		start = combineTimer.start()
 		combiner.AddMetadata(e.asTraceSearchMetadata(spanset))
+		combineTimer.stop(start)
 
-		if combiner.Count() >= int(searchReq.Limit) && searchReq.Limit > 0 {
+		if !mostRecent && combiner.Count() >= int(searchReq.Limit) && searchReq.Limit > 0 {
 			break
 		}
 	}
+	start = combineTimer.start()
 	res.Traces = combiner.Metadata()
+	combineTimer.stop(start)
 
 	span.SetAttributes(attribute.Int("spansets_evaluated", spansetsEvaluated))
 	span.SetAttributes(attribute.Int("spansets_found", len(res.Traces)))
@@ -146,15 +169,47 @@ func (e *Engine) ExecuteSearch(ctx context.Context, searchReq *tempopb.SearchReq
 		span.SetAttributes(attribute.Int64("inspectedBytes", int64(res.Metrics.InspectedBytes)))
 	}
 
+	if analyze {
+		res.Analysis = []*tempopb.SearchBlockAnalysis{{
+			InspectedBytes: res.Metrics.InspectedBytes,
+			FetchNanos:     uint64(max(fetchTimer.total-filterTimer.total, 0)),
+			FilterNanos:    uint64(filterTimer.total),
+			CombineNanos:   uint64(combineTimer.total),
+		}}
+	}
+
 	return res, nil
 }
 
+// stageTimer adds up the time spent in a stage of a search. It does nothing unless enabled.
+type stageTimer struct {
+	enabled bool
+	total   time.Duration
+}
+
+func (t *stageTimer) start() time.Time {
+	if !t.enabled {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+func (t *stageTimer) stop(start time.Time) {
+	if t.enabled {
+		t.total += time.Since(start)
+	}
+}
+
+// ExecuteTagValues returns the values of tag on spans matching query. Queries that the tag values fetcher
+// can evaluate exactly are pushed down to it, all others are evaluated in full over the spans returned by
+// the spanset fetcher.
 func (e *Engine) ExecuteTagValues(
 	ctx context.Context,
 	tag Attribute,
 	query string,
 	cb FetchTagValuesCallback,
 	fetcher TagValuesFetcher,
+	spansetFetcher SpansetFetcher,
 ) error {
 	ctx, span := tracer.Start(ctx, "traceql.Engine.ExecuteTagValues")
 	defer span.End()
@@ -171,6 +226,19 @@ func (e *Engine) ExecuteTagValues(
 			return err
 		}
 	}
+	if err := rootExpr.validate(); err != nil {
+		// same for queries that can't be executed
+		rootExpr, _ = Parse("{ true }")
+	}
+	if rootExpr.IsNoop() {
+		return nil
+	}
+
+	if !tagValuesPushdownExact(tag, rootExpr.Pipeline) {
+		span.SetAttributes(attribute.String("pipeline", rootExpr.Pipeline.String()))
+		span.SetAttributes(attribute.Bool("pushdown", false))
+		return e.executeTagValuesSpans(ctx, tag, rootExpr, cb, spansetFetcher)
+	}
 
 	autocompleteReq := e.createAutocompleteRequest(tag, rootExpr.Pipeline)
 
@@ -188,6 +256,87 @@ func (e *Engine) ExecuteTagValues(
 	return fetcher.Fetch(ctx, autocompleteReq, cb)
 }
 
+// executeTagValuesSpans evaluates the full pipeline and collects the values of tag from the matching spans.
+func (e *Engine) executeTagValuesSpans(ctx context.Context, tag Attribute, rootExpr *RootExpr, cb FetchTagValuesCallback, fetcher SpansetFetcher) error {
+	req := &FetchSpansRequest{
+		AllConditions: true,
+	}
+	rootExpr.Pipeline.extractConditions(req)
+
+	// the tag is only needed on spans that matched
+	tagCondition := []Condition{{Attribute: tag, Op: OpNone}}
+	req.SecondPassConditions = append(req.SecondPassConditions, metaConditionsWithout(tagCondition, req.Conditions, req.AllConditions)...)
+
+	req.SecondPass = func(inSS *Spanset) ([]*Spanset, error) {
+		if len(inSS.Spans) == 0 {
+			return nil, nil
+		}
+		return rootExpr.Pipeline.evaluate([]*Spanset{inSS})
+	}
+
+	resp, err := fetcher.Fetch(ctx, *req)
+	if err != nil {
+		return err
+	}
+	iterator := resp.Results
+	defer iterator.Close()
+
+	for {
+		spanset, err := iterator.Next(ctx)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if spanset == nil {
+			return nil
+		}
+
+		for _, s := range spanset.Spans {
+			v, ok := s.AttributeFor(tag)
+			if !ok || v.Type == TypeNil {
+				continue
+			}
+			if cb(v) {
+				return nil // We have enough values
+			}
+		}
+	}
+}
+
+// tagValuesPushdownExact returns true if the tag values fetchers return exactly the values of tag for the
+// pipeline. They only support a single spanset filter with conditions that are all required to match and
+// that don't need to be looked up in more than one scope.
+func tagValuesPushdownExact(tag Attribute, pipeline Pipeline) bool {
+	if pipeline.String() == "{ true }" {
+		return true // unfiltered
+	}
+	if len(pipeline.Elements) != 1 {
+		return false
+	}
+	filter, ok := pipeline.Elements[0].(*SpansetFilter)
+	if !ok {
+		return false
+	}
+
+	req := &FetchSpansRequest{
+		AllConditions: true,
+	}
+	filter.extractConditions(req)
+	if !req.AllConditions {
+		return false
+	}
+
+	if tag.Scope == AttributeScopeNone && tag.Intrinsic == IntrinsicNone {
+		return false
+	}
+	for _, c := range req.Conditions {
+		if c.Attribute.Scope == AttributeScopeNone && c.Attribute.Intrinsic == IntrinsicNone {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (e *Engine) ExecuteTagNames(
 	ctx context.Context,
 	scope AttributeScope,
@@ -278,6 +427,7 @@ func (e *Engine) asTraceSearchMetadata(spanset *Spanset) *tempopb.TraceSearchMet
 		}
 
 		atts := span.AllAttributes()
+		linkIDs := linkIDsOf(span, atts)
 
 		if name, ok := atts[NewIntrinsic(IntrinsicName)]; ok {
 			tempopbSpan.Name = name.EncodeToString(false)
@@ -295,6 +445,9 @@ func (e *Engine) asTraceSearchMetadata(spanset *Spanset) *tempopb.TraceSearchMet
 				continue
 			}
 
+			if ids, ok := linkIDs[attribute]; ok {
+				static = ids
+			}
 			staticAnyValue := static.AsAnyValue()
 
 			keyValue := &common_v1.KeyValue{
@@ -335,6 +488,40 @@ func (e *Engine) asTraceSearchMetadata(spanset *Spanset) *tempopb.TraceSearchMet
 	return metadata
 }
 
+// linkIDsOf returns the linked trace and span ids of all links of the span if they are in the attributes. The
+// attributes only hold the ids of one link. The ids of a span with several links are returned as string arrays.
+func linkIDsOf(span Span, atts map[Attribute]Static) map[Attribute]Static {
+	_, hasTraceID := atts[IntrinsicLinkTraceIDAttribute]
+	_, hasSpanID := atts[IntrinsicLinkSpanIDAttribute]
+	if !hasTraceID && !hasSpanID {
+		return nil
+	}
+
+	links, ok := span.(spanWithLinks)
+	if !ok {
+		return nil
+	}
+
+	var traceIDs, spanIDs []string
+	links.LinkAttributesFunc(func(a Attribute, s Static) {
+		switch a {
+		case IntrinsicLinkTraceIDAttribute:
+			traceIDs = append(traceIDs, s.EncodeToString(false))
+		case IntrinsicLinkSpanIDAttribute:
+			spanIDs = append(spanIDs, s.EncodeToString(false))
+		}
+	})
+
+	ids := map[Attribute]Static{}
+	if len(traceIDs) > 1 {
+		ids[IntrinsicLinkTraceIDAttribute] = NewStaticStringArray(traceIDs)
+	}
+	if len(spanIDs) > 1 {
+		ids[IntrinsicLinkSpanIDAttribute] = NewStaticStringArray(spanIDs)
+	}
+	return ids
+}
+
 func unixSecToNano(ts uint32) uint64 {
 	return uint64(ts) * uint64(time.Second/time.Nanosecond)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -516,14 +517,88 @@ type StaticVals interface {
 	StaticVals1 | StaticVals2 | StaticVals3 | StaticVals4 | StaticVals5
 }
 
+// byMissingPolicy controls what happens to spans that are missing one or more of
+// the by() attributes. It is chosen with the by_missing query hint.
+type byMissingPolicy int
+
+const (
+	// byMissingDrop keeps the span but drops the missing values from the series labels.
+	byMissingDrop byMissingPolicy = iota
+	// byMissingSkip ignores spans that are missing any of the by() attributes.
+	byMissingSkip
+	// byMissingNil keeps a label for every by() attribute and buckets missing values under <nil>.
+	byMissingNil
+)
+
+func byMissingPolicyFromString(s string) (byMissingPolicy, error) {
+	switch s {
+	case "drop":
+		return byMissingDrop, nil
+	case "skip":
+		return byMissingSkip, nil
+	case "nil":
+		return byMissingNil, nil
+	}
+	return byMissingDrop, fmt.Errorf("invalid value for hint %s: %q, valid values: drop, skip, nil", HintByMissing, s)
+}
+
+// groupingElement is implemented by first stage elements that group spans by attributes.
+type groupingElement interface {
+	setByMissing(byMissingPolicy)
+	setByTransforms([]*StringOperation)
+}
+
+// groupByList is the by() clause of a metrics query. transforms has the string function applied to the values of each
+// attribute, or nil to group by the values as they are.
+type groupByList struct {
+	attrs      []Attribute
+	transforms []*StringOperation
+}
+
+func newGroupByList(attr Attribute, transform *StringOperation) groupByList {
+	return groupByList{
+		attrs:      []Attribute{attr},
+		transforms: []*StringOperation{transform},
+	}
+}
+
+func (l groupByList) append(o groupByList) groupByList {
+	return groupByList{
+		attrs:      append(l.attrs, o.attrs...),
+		transforms: append(l.transforms, o.transforms...),
+	}
+}
+
+// withByTransforms sets the string functions of the by() clause on the element. Elements grouping by the plain values
+// are left as they are.
+func withByTransforms(e metricsFirstStageElement, l groupByList) metricsFirstStageElement {
+	if !slices.ContainsFunc(l.transforms, func(t *StringOperation) bool { return t != nil }) {
+		return e
+	}
+	if g, ok := e.(groupingElement); ok {
+		g.setByTransforms(l.transforms)
+	}
+	return e
+}
+
+// groupByString returns the i-th attribute of the by() clause with its string function.
+func groupByString(by []Attribute, transforms []*StringOperation, i int) string {
+	if i < len(transforms) && transforms[i] != nil {
+		return transforms[i].String()
+	}
+	return by[i].String()
+}
+
 // GroupingAggregator groups spans into series based on attribute values.
 type GroupingAggregator[F FastStatic, S StaticVals] struct {
 	// Config
-	by          []Attribute               // Original attributes: .foo
-	byLookups   [][]Attribute             // Lookups: span.foo resource.foo
-	byFunc      func(Span) (Static, bool) // Dynamic label calculated by a callback
-	byFuncLabel string                    // Name of the dynamic label
-	innerAgg    func() RangeAggregator
+	by           []Attribute               // Original attributes: .foo
+	byLookups    [][]Attribute             // Lookups: span.foo resource.foo
+	byTransforms []*StringOperation        // String functions applied to the values: lower(.foo)
+	byFunc       func(Span) (Static, bool) // Dynamic label calculated by a callback
+	byFuncLabel  string                    // Name of the dynamic label
+	byMissing    byMissingPolicy           // What to do with spans missing by() attributes
+	innerAgg     func() RangeAggregator
 
 	// Data
 	series     map[F]aggregatorWitValues[S]
@@ -545,6 +620,10 @@ type fastStaticWithValues[F FastStatic, S StaticVals] struct {
 var _ SpanAggregator = (*GroupingAggregator[FastStatic1, StaticVals1])(nil)
 
 func NewGroupingAggregator(aggName string, innerAgg func() RangeAggregator, by []Attribute, byFunc func(Span) (Static, bool), byFuncLabel string) SpanAggregator {
+	return newGroupingAggregatorWithPolicy(aggName, innerAgg, by, nil, byFunc, byFuncLabel, byMissingDrop)
+}
+
+func newGroupingAggregatorWithPolicy(aggName string, innerAgg func() RangeAggregator, by []Attribute, byTransforms []*StringOperation, byFunc func(Span) (Static, bool), byFuncLabel string, byMissing byMissingPolicy) SpanAggregator {
 	if len(by) == 0 && byFunc == nil {
 		return &UngroupedAggregator{
 			name:     aggName,
@@ -573,28 +652,30 @@ func NewGroupingAggregator(aggName string, innerAgg func() RangeAggregator, by [
 
 	switch aggNum {
 	case 1:
-		return newGroupingAggregator[FastStatic1, StaticVals1](innerAgg, by, byFunc, byFuncLabel, lookups)
+		return newGroupingAggregator[FastStatic1, StaticVals1](innerAgg, by, byTransforms, byFunc, byFuncLabel, byMissing, lookups)
 	case 2:
-		return newGroupingAggregator[FastStatic2, StaticVals2](innerAgg, by, byFunc, byFuncLabel, lookups)
+		return newGroupingAggregator[FastStatic2, StaticVals2](innerAgg, by, byTransforms, byFunc, byFuncLabel, byMissing, lookups)
 	case 3:
-		return newGroupingAggregator[FastStatic3, StaticVals3](innerAgg, by, byFunc, byFuncLabel, lookups)
+		return newGroupingAggregator[FastStatic3, StaticVals3](innerAgg, by, byTransforms, byFunc, byFuncLabel, byMissing, lookups)
 	case 4:
-		return newGroupingAggregator[FastStatic4, StaticVals4](innerAgg, by, byFunc, byFuncLabel, lookups)
+		return newGroupingAggregator[FastStatic4, StaticVals4](innerAgg, by, byTransforms, byFunc, byFuncLabel, byMissing, lookups)
 	case 5:
-		return newGroupingAggregator[FastStatic5, StaticVals5](innerAgg, by, byFunc, byFuncLabel, lookups)
+		return newGroupingAggregator[FastStatic5, StaticVals5](innerAgg, by, byTransforms, byFunc, byFuncLabel, byMissing, lookups)
 	default:
 		panic("unsupported number of group-bys")
 	}
 }
 
-func newGroupingAggregator[F FastStatic, S StaticVals](innerAgg func() RangeAggregator, by []Attribute, byFunc func(Span) (Static, bool), byFuncLabel string, lookups [][]Attribute) SpanAggregator {
+func newGroupingAggregator[F FastStatic, S StaticVals](innerAgg func() RangeAggregator, by []Attribute, byTransforms []*StringOperation, byFunc func(Span) (Static, bool), byFuncLabel string, byMissing byMissingPolicy, lookups [][]Attribute) SpanAggregator {
 	return &GroupingAggregator[F, S]{
-		series:      map[F]aggregatorWitValues[S]{},
-		by:          by,
-		byFunc:      byFunc,
-		byFuncLabel: byFuncLabel,
-		byLookups:   lookups,
-		innerAgg:    innerAgg,
+		series:       map[F]aggregatorWitValues[S]{},
+		by:           by,
+		byFunc:       byFunc,
+		byFuncLabel:  byFuncLabel,
+		byMissing:    byMissing,
+		byLookups:    lookups,
+		byTransforms: byTransforms,
+		innerAgg:     innerAgg,
 	}
 }
 
@@ -607,6 +688,12 @@ func (g *GroupingAggregator[F, S]) getGroupingValues(span Span) bool {
 	// is fixed after creation.
 	for i, lookups := range g.byLookups {
 		val := lookup(lookups, span)
+		if g.byTransforms != nil && g.byTransforms[i] != nil {
+			val = g.byTransforms[i].apply(val)
+		}
+		if val.Type == TypeNil && g.byMissing == byMissingSkip {
+			return false
+		}
 		g.buf.vals[i] = val
 		g.buf.fast[i] = val.MapKey()
 	}
@@ -693,6 +780,17 @@ func (g *GroupingAggregator[F, S]) ObserveExemplar(span Span, value float64, ts
 //	{       y=...       }
 //	etc
 //
+// With the by_missing=nil hint nils are kept instead and every series has a label
+// for each group-by value:
+//
+//	Ex: rate() by (x,y) with (by_missing="nil") can yield:
+//	{x=a,y=b}
+//	{x=a,y=<nil>}
+//	{x=<nil>,y=<nil>}
+//
+// With the by_missing=skip hint spans missing any of the values are not counted,
+// so nils never occur.
+//
 // (3) Exceptional case: All Nils. For the TraceQL data-type aware labels we still drop
 // all nils which results in an empty label set. But Prometheus-style always have
 // at least 1 label, so in that case we have to force at least 1 label or else things
@@ -704,7 +802,7 @@ func (g *GroupingAggregator[F, S]) ObserveExemplar(span Span, value float64, ts
 func (g *GroupingAggregator[F, S]) labelsFor(vals S) (Labels, string) {
 	labels := make(Labels, 0, len(g.by)+1)
 	for i := range g.by {
-		if vals[i].Type == TypeNil {
+		if vals[i].Type == TypeNil && g.byMissing != byMissingNil {
 			continue
 		}
 		labels = append(labels, Label{g.by[i].String(), vals[i]})
@@ -834,6 +932,16 @@ func (e *Engine) CompileMetricsQueryRange(req *tempopb.QueryRangeRequest, exempl
 		exemplars = v
 	}
 
+	if v, ok := expr.Hints.GetString(HintByMissing, allowUnsafeQueryHints); ok {
+		policy, err := byMissingPolicyFromString(v)
+		if err != nil {
+			return nil, err
+		}
+		if g, ok := metricsPipeline.(groupingElement); ok {
+			g.setByMissing(policy)
+		}
+	}
+
 	// This initializes all step buffers, counters, etc
 	metricsPipeline.init(req, AggregateModeRaw)
 
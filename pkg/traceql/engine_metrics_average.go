@@ -1,7 +1,6 @@
 package traceql
 
 import (
-	"fmt"
 	"math"
 	"strings"
 	"time"
@@ -21,9 +20,15 @@ type averageOverTimeAggregator struct {
 	seriesAgg  SeriesAggregator
 	exemplarFn getExemplar
 	mode       AggregateMode
+	byMissing  byMissingPolicy
+	// String functions applied to the values of the by() attributes
+	byTransforms []*StringOperation
 }
 
-var _ metricsFirstStageElement = (*averageOverTimeAggregator)(nil)
+var (
+	_ metricsFirstStageElement = (*averageOverTimeAggregator)(nil)
+	_ groupingElement          = (*averageOverTimeAggregator)(nil)
+)
 
 func newAverageOverTimeMetricsAggregator(attr Attribute, by []Attribute) *averageOverTimeAggregator {
 	return &averageOverTimeAggregator{
@@ -43,13 +48,21 @@ func (a *averageOverTimeAggregator) init(q *tempopb.QueryRangeRequest, mode Aggr
 	}
 
 	if mode == AggregateModeRaw {
-		a.agg = newAvgOverTimeSpanAggregator(a.attr, a.by, q.Start, q.End, q.Step)
+		a.agg = newAvgOverTimeSpanAggregator(a.attr, a.by, a.byTransforms, a.byMissing, q.Start, q.End, q.Step)
 	}
 
 	a.mode = mode
 	a.exemplarFn = exemplarFnFor(a.attr)
 }
 
+func (a *averageOverTimeAggregator) setByMissing(p byMissingPolicy) {
+	a.byMissing = p
+}
+
+func (a *averageOverTimeAggregator) setByTransforms(t []*StringOperation) {
+	a.byTransforms = t
+}
+
 func (a *averageOverTimeAggregator) observe(span Span) {
 	a.agg.Observe(span)
 }
@@ -100,10 +113,7 @@ func (a *averageOverTimeAggregator) extractConditions(request *FetchSpansRequest
 }
 
 func (a *averageOverTimeAggregator) validate() error {
-	if len(a.by) >= maxGroupBys {
-		return newUnsupportedError(fmt.Sprintf("metrics group by %v values", len(a.by)))
-	}
-	return nil
+	return validateGroupBys(a.by, a.byTransforms, maxGroupBys-1)
 }
 
 func (a *averageOverTimeAggregator) String() string {
@@ -118,8 +128,8 @@ func (a *averageOverTimeAggregator) String() string {
 
 	if len(a.by) > 0 {
 		s.WriteString("by(")
-		for i, b := range a.by {
-			s.WriteString(b.String())
+		for i := range a.by {
+			s.WriteString(groupByString(a.by, a.byTransforms, i))
 			if i < len(a.by)-1 {
 				s.WriteString(",")
 			}
@@ -359,8 +369,10 @@ type avgOverTimeSeries[S StaticVals] struct {
 // First aggregation layer
 type avgOverTimeSpanAggregator[F FastStatic, S StaticVals] struct {
 	// Config
-	by              []Attribute   // Original attributes: .foo
-	byLookups       [][]Attribute // Lookups: span.foo resource.foo
+	by              []Attribute        // Original attributes: .foo
+	byLookups       [][]Attribute      // Lookups: span.foo resource.foo
+	byTransforms    []*StringOperation // String functions applied to the values: lower(.foo)
+	byMissing       byMissingPolicy
 	getSpanAttValue func(s Span) float64
 	start           uint64
 	end             uint64
@@ -375,7 +387,7 @@ type avgOverTimeSpanAggregator[F FastStatic, S StaticVals] struct {
 
 var _ SpanAggregator = (*avgOverTimeSpanAggregator[FastStatic1, StaticVals1])(nil)
 
-func newAvgOverTimeSpanAggregator(attr Attribute, by []Attribute, start, end, step uint64) SpanAggregator {
+func newAvgOverTimeSpanAggregator(attr Attribute, by []Attribute, byTransforms []*StringOperation, byMissing byMissingPolicy, start, end, step uint64) SpanAggregator {
 	lookups := make([][]Attribute, len(by))
 	for i, attr := range by {
 		if attr.Intrinsic == IntrinsicNone && attr.Scope == AttributeScopeNone {
@@ -394,19 +406,19 @@ func newAvgOverTimeSpanAggregator(attr Attribute, by []Attribute, start, end, st
 
 	switch aggNum {
 	case 2:
-		return newAvgAggregator[FastStatic2, StaticVals2](attr, by, lookups, start, end, step)
+		return newAvgAggregator[FastStatic2, StaticVals2](attr, by, byTransforms, byMissing, lookups, start, end, step)
 	case 3:
-		return newAvgAggregator[FastStatic3, StaticVals3](attr, by, lookups, start, end, step)
+		return newAvgAggregator[FastStatic3, StaticVals3](attr, by, byTransforms, byMissing, lookups, start, end, step)
 	case 4:
-		return newAvgAggregator[FastStatic4, StaticVals4](attr, by, lookups, start, end, step)
+		return newAvgAggregator[FastStatic4, StaticVals4](attr, by, byTransforms, byMissing, lookups, start, end, step)
 	case 5:
-		return newAvgAggregator[FastStatic5, StaticVals5](attr, by, lookups, start, end, step)
+		return newAvgAggregator[FastStatic5, StaticVals5](attr, by, byTransforms, byMissing, lookups, start, end, step)
 	default:
-		return newAvgAggregator[FastStatic1, StaticVals1](attr, by, lookups, start, end, step)
+		return newAvgAggregator[FastStatic1, StaticVals1](attr, by, byTransforms, byMissing, lookups, start, end, step)
 	}
 }
 
-func newAvgAggregator[F FastStatic, S StaticVals](attr Attribute, by []Attribute, lookups [][]Attribute, start, end, step uint64) SpanAggregator {
+func newAvgAggregator[F FastStatic, S StaticVals](attr Attribute, by []Attribute, byTransforms []*StringOperation, byMissing byMissingPolicy, lookups [][]Attribute, start, end, step uint64) SpanAggregator {
 	var fn func(s Span) float64
 
 	switch attr {
@@ -429,6 +441,8 @@ func newAvgAggregator[F FastStatic, S StaticVals](attr Attribute, by []Attribute
 		getSpanAttValue: fn,
 		by:              by,
 		byLookups:       lookups,
+		byTransforms:    byTransforms,
+		byMissing:       byMissing,
 		start:           start,
 		end:             end,
 		step:            step,
@@ -446,13 +460,16 @@ func (g *avgOverTimeSpanAggregator[F, S]) Observe(span Span) {
 		return
 	}
 
-	s := g.getSeries(span)
+	s, ok := g.getSeries(span)
+	if !ok {
+		return
+	}
 	s.average.addIncrementMean(interval, inc)
 }
 
 func (g *avgOverTimeSpanAggregator[F, S]) ObserveExemplar(span Span, value float64, ts uint64) {
-	s := g.getSeries(span)
-	if s.exemplarBuckets.testTotal() {
+	s, ok := g.getSeries(span)
+	if !ok || s.exemplarBuckets.testTotal() {
 		return
 	}
 	interval := IntervalOfMs(int64(ts), g.start, g.end, g.step)
@@ -482,7 +499,7 @@ func (g *avgOverTimeSpanAggregator[F, S]) labelsFor(vals S) (Labels, string) {
 	}
 	labels := make(Labels, 0, len(g.by)+1)
 	for i := range g.by {
-		if vals[i].Type == TypeNil {
+		if vals[i].Type == TypeNil && g.byMissing != byMissingNil {
 			continue
 		}
 		labels = append(labels, Label{g.by[i].String(), vals[i]})
@@ -516,17 +533,24 @@ func (g *avgOverTimeSpanAggregator[F, S]) Series() SeriesSet {
 
 // getSeries gets the series for the current span.
 // It will reuse the last series if possible.
-func (g *avgOverTimeSpanAggregator[F, S]) getSeries(span Span) avgOverTimeSeries[S] {
+// Returns false if the span should be dropped.
+func (g *avgOverTimeSpanAggregator[F, S]) getSeries(span Span) (avgOverTimeSeries[S], bool) {
 	// Get Grouping values
 	for i, lookups := range g.byLookups {
 		val := lookup(lookups, span)
+		if g.byTransforms != nil && g.byTransforms[i] != nil {
+			val = g.byTransforms[i].apply(val)
+		}
+		if val.Type == TypeNil && g.byMissing == byMissingSkip {
+			return avgOverTimeSeries[S]{}, false
+		}
 		g.buf.vals[i] = val
 		g.buf.fast[i] = val.MapKey()
 	}
 
 	// Fast path
 	if g.lastBuf.fast == g.buf.fast && g.lastSeries.initialized {
-		return g.lastSeries
+		return g.lastSeries, true
 	}
 
 	s, ok := g.series[g.buf.fast]
@@ -543,5 +567,5 @@ func (g *avgOverTimeSpanAggregator[F, S]) getSeries(span Span) avgOverTimeSeries
 
 	g.lastBuf = g.buf
 	g.lastSeries = s
-	return s
+	return s, true
 }
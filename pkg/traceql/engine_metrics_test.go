@@ -199,7 +199,7 @@ func TestCompileMetricsQueryRange(t *testing.T) {
 			end:         2,
 			step:        3,
 			q:           "{} | rate() by (.a,.b,.c,.d,.e,.f)",
-			expectedErr: fmt.Errorf("compiling query: metrics group by 6 values not yet supported"),
+			expectedErr: fmt.Errorf("compiling query: metrics group by 6 values (max 5) not yet supported"),
 		},
 		"ok": {
 			start: 1,
@@ -469,6 +469,186 @@ func TestCountOverTime(t *testing.T) {
 	require.Equal(t, out, result)
 }
 
+func TestCountOverTimeByMissing(t *testing.T) {
+	in := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "a").WithSpanString("bar", "b"),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "a"),
+		newMockSpan(nil).WithStartTime(uint64(2*time.Second)).WithSpanString("foo", "a"),
+		newMockSpan(nil).WithStartTime(uint64(2 * time.Second)),
+	}
+
+	tcs := []struct {
+		name  string
+		hints string
+		out   SeriesSet
+	}{
+		{
+			name: "default drops missing labels",
+			out: SeriesSet{
+				`{span.bar="b", span.foo="a"}`: TimeSeries{
+					Labels:    []Label{{Name: "span.foo", Value: NewStaticString("a")}, {Name: "span.bar", Value: NewStaticString("b")}},
+					Values:    []float64{1, 0, 0},
+					Exemplars: make([]Exemplar, 0),
+				},
+				`{span.foo="a"}`: TimeSeries{
+					Labels:    []Label{{Name: "span.foo", Value: NewStaticString("a")}},
+					Values:    []float64{1, 1, 0},
+					Exemplars: make([]Exemplar, 0),
+				},
+				`{span.foo="<nil>"}`: TimeSeries{
+					Labels:    []Label{{Name: "span.foo", Value: NewStaticString("nil")}},
+					Values:    []float64{0, 1, 0},
+					Exemplars: make([]Exemplar, 0),
+				},
+			},
+		},
+		{
+			name:  "skip",
+			hints: ` with (by_missing="skip")`,
+			out: SeriesSet{
+				`{span.bar="b", span.foo="a"}`: TimeSeries{
+					Labels:    []Label{{Name: "span.foo", Value: NewStaticString("a")}, {Name: "span.bar", Value: NewStaticString("b")}},
+					Values:    []float64{1, 0, 0},
+					Exemplars: make([]Exemplar, 0),
+				},
+			},
+		},
+		{
+			name:  "nil",
+			hints: ` with (by_missing="nil")`,
+			out: SeriesSet{
+				`{span.bar="b", span.foo="a"}`: TimeSeries{
+					Labels:    []Label{{Name: "span.foo", Value: NewStaticString("a")}, {Name: "span.bar", Value: NewStaticString("b")}},
+					Values:    []float64{1, 0, 0},
+					Exemplars: make([]Exemplar, 0),
+				},
+				`{span.bar="<nil>", span.foo="a"}`: TimeSeries{
+					Labels:    []Label{{Name: "span.foo", Value: NewStaticString("a")}, {Name: "span.bar", Value: NewStaticString("nil")}},
+					Values:    []float64{1, 1, 0},
+					Exemplars: make([]Exemplar, 0),
+				},
+				`{span.bar="<nil>", span.foo="<nil>"}`: TimeSeries{
+					Labels:    []Label{{Name: "span.foo", Value: NewStaticString("nil")}, {Name: "span.bar", Value: NewStaticString("nil")}},
+					Values:    []float64{0, 1, 0},
+					Exemplars: make([]Exemplar, 0),
+				},
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &tempopb.QueryRangeRequest{
+				Start: uint64(1 * time.Second),
+				End:   uint64(3 * time.Second),
+				Step:  uint64(1 * time.Second),
+				Query: "{ } | count_over_time() by (span.foo, span.bar)" + tc.hints,
+			}
+
+			result := runTraceQLMetric(t, req, in)
+			require.Equal(t, tc.out, result)
+		})
+	}
+}
+
+func TestCountOverTimeByStringFunction(t *testing.T) {
+	in := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("url", "/users/1"),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("url", "/users/22"),
+		newMockSpan(nil).WithStartTime(uint64(2*time.Second)).WithSpanString("url", "/Health"),
+		newMockSpan(nil).WithStartTime(uint64(2 * time.Second)),
+	}
+
+	req := &tempopb.QueryRangeRequest{
+		Start: uint64(1 * time.Second),
+		End:   uint64(3 * time.Second),
+		Step:  uint64(1 * time.Second),
+		Query: `{ } | count_over_time() by (replace(span.url, "/[0-9]+", "/{id}"))`,
+	}
+
+	out := SeriesSet{
+		`{span.url="/users/{id}"}`: TimeSeries{
+			Labels:    []Label{{Name: "span.url", Value: NewStaticString("/users/{id}")}},
+			Values:    []float64{2, 0, 0},
+			Exemplars: make([]Exemplar, 0),
+		},
+		`{span.url="/Health"}`: TimeSeries{
+			Labels:    []Label{{Name: "span.url", Value: NewStaticString("/Health")}},
+			Values:    []float64{0, 1, 0},
+			Exemplars: make([]Exemplar, 0),
+		},
+		`{span.url="<nil>"}`: TimeSeries{
+			Labels:    []Label{{Name: "span.url", Value: NewStaticString("nil")}},
+			Values:    []float64{0, 1, 0},
+			Exemplars: make([]Exemplar, 0),
+		},
+	}
+	require.Equal(t, out, runTraceQLMetric(t, req, in))
+
+	req.Query = `{ } | avg_over_time(duration) by (lower(span.url))`
+	result := runTraceQLMetric(t, req, in)
+	require.Contains(t, result, `{span.url="/health"}`)
+	require.Contains(t, result, `{span.url="/users/1"}`)
+}
+
+func TestAvgOverTimeByMissing(t *testing.T) {
+	in := []Span{
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "a").WithSpanString("bar", "b").WithDuration(100),
+		newMockSpan(nil).WithStartTime(uint64(1*time.Second)).WithSpanString("foo", "a").WithDuration(200),
+		newMockSpan(nil).WithStartTime(uint64(1 * time.Second)).WithDuration(300),
+	}
+
+	run := func(hints string) SeriesSet {
+		req := &tempopb.QueryRangeRequest{
+			Start: uint64(1 * time.Second),
+			End:   uint64(3 * time.Second),
+			Step:  uint64(1 * time.Second),
+			Query: "{ } | avg_over_time(duration) by (span.foo, span.bar)" + hints,
+		}
+		return runTraceQLMetric(t, req, in)
+	}
+
+	result := run(` with (by_missing="skip")`)
+	require.Len(t, result, 1)
+	require.Contains(t, result, `{span.bar="b", span.foo="a"}`)
+
+	result = run(` with (by_missing="nil")`)
+	require.Len(t, result, 3)
+	require.Contains(t, result, `{span.bar="b", span.foo="a"}`)
+	require.Contains(t, result, `{span.bar="<nil>", span.foo="a"}`)
+	require.Contains(t, result, `{span.bar="<nil>", span.foo="<nil>"}`)
+}
+
+func TestCompileMetricsQueryRangeGroupBy(t *testing.T) {
+	tcs := map[string]string{
+		"{} | rate() by (.a, .b, .c, .d, .e)":                           "",
+		"{} | rate() by (.a, .b, .c, .d, .e, .f)":                       "metrics group by 6 values (max 5) not yet supported",
+		"{} | quantile_over_time(duration, .5) by (.a, .b, .c, .d)":     "",
+		"{} | quantile_over_time(duration, .5) by (.a, .b, .c, .d, .e)": "metrics group by 5 values (max 4) not yet supported",
+		"{} | rate() by (.a) with (by_missing=\"skip\")":                "",
+		"{} | rate() by (.a) with (by_missing=\"nil\")":                 "",
+		"{} | rate() by (.a) with (by_missing=\"drop\")":                "",
+		"{} | rate() by (.a) with (by_missing=\"zero\")":                `invalid value for hint by_missing: "zero", valid values: drop, skip, nil`,
+	}
+
+	for q, expErr := range tcs {
+		t.Run(q, func(t *testing.T) {
+			_, err := NewEngine().CompileMetricsQueryRange(&tempopb.QueryRangeRequest{
+				Query: q,
+				Start: 1,
+				End:   2,
+				Step:  1,
+			}, 0, 0, false)
+
+			if expErr != "" {
+				require.ErrorContains(t, err, expErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
 func TestMinOverTimeForDuration(t *testing.T) {
 	req := &tempopb.QueryRangeRequest{
 		Start: uint64(1 * time.Second),
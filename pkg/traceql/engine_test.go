@@ -204,6 +204,85 @@ func TestEngine_Execute(t *testing.T) {
 	assert.Equal(t, uint64(100_00), response.Metrics.InspectedBytes)
 }
 
+func TestEngine_ExecuteMostRecent(t *testing.T) {
+	spansets := func() []*Spanset {
+		var ss []*Spanset
+		for i, start := range []uint64{30, 10, 50, 20, 40} {
+			ss = append(ss, &Spanset{
+				TraceID:            []byte{byte(i + 1)},
+				StartTimeUnixNanos: start,
+				Spans: []Span{
+					&mockSpan{
+						id:         []byte{byte(i + 1)},
+						attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("bar")},
+					},
+				},
+			})
+		}
+		return ss
+	}
+
+	tests := []struct {
+		query    string
+		expected []uint64
+	}{
+		{
+			// stops at the first traces found
+			query:    `{ .foo = "bar" }`,
+			expected: []uint64{30, 10},
+		},
+		{
+			query:    `{ .foo = "bar" } with (most_recent=true)`,
+			expected: []uint64{50, 40},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			fetcher := &MockSpanSetFetcher{iterator: &MockSpanSetIterator{results: spansets()}}
+			resp, err := NewEngine().ExecuteSearch(context.Background(), &tempopb.SearchRequest{Query: tc.query, Limit: 2}, fetcher)
+			require.NoError(t, err)
+
+			var actual []uint64
+			for _, tr := range resp.Traces {
+				actual = append(actual, tr.StartTimeUnixNano)
+			}
+			require.ElementsMatch(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestEngine_ExecuteAnalyze(t *testing.T) {
+	spansets := func() []*Spanset {
+		return []*Spanset{{
+			TraceID: []byte{1},
+			Spans: []Span{
+				&mockSpan{
+					id:         []byte{1},
+					attributes: map[Attribute]Static{NewAttribute("foo"): NewStaticString("bar")},
+				},
+			},
+		}}
+	}
+
+	fetcher := &MockSpanSetFetcher{iterator: &MockSpanSetIterator{results: spansets()}}
+	resp, err := NewEngine().ExecuteSearch(context.Background(), &tempopb.SearchRequest{Query: `{ .foo = "bar" }`}, fetcher)
+	require.NoError(t, err)
+	require.Len(t, resp.Traces, 1)
+	require.Nil(t, resp.Analysis)
+
+	fetcher = &MockSpanSetFetcher{iterator: &MockSpanSetIterator{results: spansets()}}
+	resp, err = NewEngine().ExecuteSearch(context.Background(), &tempopb.SearchRequest{Query: `{ .foo = "bar" } with (analyze=true)`}, fetcher)
+	require.NoError(t, err)
+	require.Len(t, resp.Traces, 1)
+	require.Len(t, resp.Analysis, 1)
+
+	analysis := resp.Analysis[0]
+	require.Equal(t, uint64(100_00), analysis.InspectedBytes)
+	require.NotZero(t, analysis.FilterNanos)
+	require.NotZero(t, analysis.CombineNanos)
+}
+
 func TestEngine_asTraceSearchMetadata(t *testing.T) {
 	now := time.Now()
 
@@ -564,6 +643,32 @@ func TestExamplesInEngine(t *testing.T) {
 	}
 }
 
+func TestTagValuesPushdownExact(t *testing.T) {
+	for _, tc := range []struct {
+		tag, query string
+		expected   bool
+	}{
+		{tag: "span.foo", query: `{}`, expected: true},
+		{tag: ".foo", query: `{}`, expected: true},
+		{tag: "span.foo", query: `{ resource.service.name = "a" }`, expected: true},
+		{tag: "name", query: `{ resource.service.name = "a" && span.bar > 1 && duration > 1s }`, expected: true},
+		{tag: ".foo", query: `{ resource.service.name = "a" }`, expected: false},
+		{tag: "span.foo", query: `{ .bar = "a" }`, expected: false},
+		{tag: "span.foo", query: `{ span.bar = "a" || span.bar = "b" }`, expected: false},
+		{tag: "span.foo", query: `{ span.bar = "a" } && { span.bar = "b" }`, expected: false},
+		{tag: "span.foo", query: `{ span.bar = "a" } >> { span.bar = "b" }`, expected: false},
+		{tag: "span.foo", query: `{ span.bar = "a" } | count() > 1`, expected: false},
+	} {
+		t.Run(tc.tag+" "+tc.query, func(t *testing.T) {
+			tag, err := ParseIdentifier(tc.tag)
+			require.NoError(t, err)
+			expr, err := Parse(tc.query)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, tagValuesPushdownExact(tag, expr.Pipeline))
+		})
+	}
+}
+
 func TestExecuteTagValues(t *testing.T) {
 	// TODO: This test is stupid, it's using the traceql engine to execute the query
 	//  and doesn't actually test the ExecuteTagValues function
@@ -666,6 +771,21 @@ func TestExecuteTagValues(t *testing.T) {
 				{Type: "string", Value: "redis call"},
 			},
 		},
+		{
+			name:      "multiple spansets",
+			attribute: "span.http.method",
+			query:     `{ span.http.target = "/api/v1/users" } || { span.http.target = "/status" }`,
+			expectedValues: []tempopb.TagValue{
+				{Type: "string", Value: "GET"},
+				{Type: "string", Value: "POST"},
+			},
+		},
+		{
+			name:           "unscoped attribute",
+			attribute:      "span.http.method",
+			query:          `{ .http.target = "/status" }`,
+			expectedValues: []tempopb.TagValue{{Type: "string", Value: "GET"}},
+		},
 		{
 			name:           "noop", // autocompleting an attribute already filtered by the query
 			attribute:      "name",
@@ -684,7 +804,9 @@ func TestExecuteTagValues(t *testing.T) {
 
 			tag, err := ParseIdentifier(tc.attribute)
 			assert.NoError(t, err)
-			assert.NoError(t, e.ExecuteTagValues(context.Background(), tag, tc.query, MakeCollectTagValueFunc(distinctValues.Collect), mockSpansetFetcher(fetcherQuery)))
+			fetcher := mockSpansetFetcher(fetcherQuery)
+			spansetFetcher := &MockSpanSetFetcher{iterator: fetcher.(*MockAutocompleteFetcher).iterator}
+			assert.NoError(t, e.ExecuteTagValues(context.Background(), tag, tc.query, MakeCollectTagValueFunc(distinctValues.Collect), fetcher, spansetFetcher))
 			values := distinctValues.Values()
 			sort.Slice(values, func(i, j int) bool {
 				return values[i].Value < values[j].Value
@@ -10,6 +10,7 @@ const (
 	aggregateMin
 	aggregateSum
 	aggregateAvg
+	aggregateStddev
 )
 
 func (a AggregateOp) String() string {
@@ -24,6 +25,8 @@ func (a AggregateOp) String() string {
 		return "sum"
 	case aggregateAvg:
 		return "avg"
+	case aggregateStddev:
+		return "stddev"
 	}
 
 	return fmt.Sprintf("aggregate(%d)", a)
@@ -0,0 +1,59 @@
+package traceql
+
+import (
+	"fmt"
+	"time"
+)
+
+// CalendarFunction extracts a calendar component from the start time of a span. Components are in UTC and follow the
+// Prometheus time functions, e.g. day_of_week is 0 for Sunday.
+type CalendarFunction int
+
+const (
+	calendarMinute CalendarFunction = iota
+	calendarHour
+	calendarDayOfWeek
+	calendarDayOfMonth
+	calendarMonth
+	calendarYear
+)
+
+func (f CalendarFunction) String() string {
+	switch f {
+	case calendarMinute:
+		return "minute"
+	case calendarHour:
+		return "hour"
+	case calendarDayOfWeek:
+		return "day_of_week"
+	case calendarDayOfMonth:
+		return "day_of_month"
+	case calendarMonth:
+		return "month"
+	case calendarYear:
+		return "year"
+	}
+
+	return fmt.Sprintf("calendar(%d)", f)
+}
+
+func (f CalendarFunction) apply(t time.Time) int {
+	t = t.UTC()
+
+	switch f {
+	case calendarMinute:
+		return t.Minute()
+	case calendarHour:
+		return t.Hour()
+	case calendarDayOfWeek:
+		return int(t.Weekday())
+	case calendarDayOfMonth:
+		return t.Day()
+	case calendarMonth:
+		return int(t.Month())
+	case calendarYear:
+		return t.Year()
+	}
+
+	return 0
+}
@@ -12,11 +12,14 @@ const (
 	HintTimeOverlapCutoff = "time_overlap_cutoff"
 	HintConcurrentBlocks  = "concurrent_blocks"
 	HintExemplars         = "exemplars"
+	HintByMissing         = "by_missing"
+	HintMostRecent        = "most_recent"
+	HintAnalyze           = "analyze"
 )
 
 func isUnsafe(h string) bool {
 	switch h {
-	case HintSample, HintExemplars:
+	case HintSample, HintExemplars, HintByMissing, HintMostRecent, HintAnalyze:
 		return false
 	default:
 		return true
@@ -81,6 +84,14 @@ func (h *Hints) GetBool(k string, allowUnsafe bool) (v, ok bool) {
 	return
 }
 
+func (h *Hints) GetString(k string, allowUnsafe bool) (v string, ok bool) {
+	if v, ok := h.Get(k, TypeString, allowUnsafe); ok {
+		return v.EncodeToString(false), ok
+	}
+
+	return
+}
+
 func (h *Hints) Get(k string, t StaticType, allowUnsafe bool) (v Static, ok bool) {
 	if h == nil {
 		return
@@ -0,0 +1,104 @@
+package traceql
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// StringFunction transforms a string value, e.g. lower(span.foo).
+type StringFunction int
+
+const (
+	stringLower StringFunction = iota
+	stringReplace
+	stringSubstring
+)
+
+func (f StringFunction) String() string {
+	switch f {
+	case stringLower:
+		return "lower"
+	case stringReplace:
+		return "replace"
+	case stringSubstring:
+		return "substring"
+	}
+
+	return fmt.Sprintf("string(%d)", f)
+}
+
+// stringArgs are the parsed arguments of a string function.
+type stringArgs struct {
+	pattern     *regexp.Regexp
+	replacement string
+	start       int
+	length      int // -1 is the rest of the string
+}
+
+// parseArgs parses the arguments of the function. replace takes a pattern and the replacement, which can reference
+// capture groups of the pattern with $1. substring takes the start and optionally the length in characters.
+func (f StringFunction) parseArgs(args []Static) (stringArgs, error) {
+	parsed := stringArgs{length: -1}
+
+	switch f {
+	case stringLower:
+		if len(args) != 0 {
+			return parsed, fmt.Errorf("%s takes no arguments", f)
+		}
+
+	case stringReplace:
+		if len(args) != 2 || args[0].Type != TypeString || args[1].Type != TypeString {
+			return parsed, fmt.Errorf("%s takes a pattern and a replacement string", f)
+		}
+		re, err := regexp.Compile(args[0].EncodeToString(false))
+		if err != nil {
+			return parsed, fmt.Errorf("%s has an invalid pattern: %w", f, err)
+		}
+		parsed.pattern = re
+		parsed.replacement = args[1].EncodeToString(false)
+
+	case stringSubstring:
+		if len(args) == 0 || len(args) > 2 {
+			return parsed, fmt.Errorf("%s takes a start and optionally a length", f)
+		}
+		start, ok := args[0].Int()
+		if !ok || start < 0 {
+			return parsed, errors.New("substring start must be a non-negative integer")
+		}
+		parsed.start = start
+		if len(args) == 2 {
+			length, ok := args[1].Int()
+			if !ok || length < 0 {
+				return parsed, errors.New("substring length must be a non-negative integer")
+			}
+			parsed.length = length
+		}
+
+	default:
+		return parsed, fmt.Errorf("unknown string function %s", f)
+	}
+
+	return parsed, nil
+}
+
+// apply transforms the string. The start and length of substring are clamped to the string.
+func (f StringFunction) apply(s string, args stringArgs) string {
+	switch f {
+	case stringLower:
+		return strings.ToLower(s)
+	case stringReplace:
+		return args.pattern.ReplaceAllString(s, args.replacement)
+	case stringSubstring:
+		runes := []rune(s)
+		from := min(args.start, len(runes))
+		to := len(runes)
+		if args.length >= 0 {
+			to = min(from+args.length, to)
+		}
+		return string(runes[from:to])
+	}
+
+	return s
+}
@@ -37,6 +37,9 @@ import (
     attributeField Attribute
     attribute Attribute
     scopedIntrinsicField Attribute
+    calendarField CalendarOperation
+    stringField FieldExpression
+    groupByList groupByList
 
     binOp       Operator
     staticInt   int
@@ -55,6 +58,7 @@ import (
 %type <coalesceOperation> coalesceOperation
 %type <selectOperation> selectOperation
 %type <attributeList> attributeList
+%type <groupByList> groupByList groupBy
 
 %type <spansetExpression> spansetExpression
 %type <spansetPipelineExpression> spansetPipelineExpression
@@ -77,6 +81,8 @@ import (
 %type <intrinsicField> intrinsicField
 %type <attributeField> attributeField
 %type <scopedIntrinsicField> scopedIntrinsicField
+%type <calendarField> calendarField
+%type <stringField> stringField
 %type <attribute> attribute
 
 %type <numericList> numericList
@@ -97,11 +103,13 @@ import (
                         TRACE_ID SPAN_ID TIMESINCESTART VERSION
                         PARENT_DOT RESOURCE_DOT SPAN_DOT TRACE_COLON SPAN_COLON 
                         EVENT_COLON EVENT_DOT LINK_COLON LINK_DOT INSTRUMENTATION_COLON INSTRUMENTATION_DOT
-                        COUNT AVG MAX MIN SUM
-                        BY COALESCE SELECT
+                        COUNT AVG MAX MIN SUM STDDEV
+                        BY COALESCE SELECT ON
                         END_ATTRIBUTE
                         RATE COUNT_OVER_TIME MIN_OVER_TIME MAX_OVER_TIME AVG_OVER_TIME QUANTILE_OVER_TIME HISTOGRAM_OVER_TIME COMPARE
                         WITH
+                        START MINUTE HOUR DAY_OF_WEEK DAY_OF_MONTH MONTH YEAR
+                        LOWER REPLACE SUBSTRING
 
 // Operators are listed with increasing precedence.
 %left <binOp> PIPE
@@ -130,9 +138,10 @@ root:
 spansetPipelineExpression: // shares the same operators as spansetExpression. split out for readability
     OPEN_PARENS spansetPipelineExpression CLOSE_PARENS           { $$ = $2 }
   | spansetPipelineExpression AND   spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetAnd, $1, $3) }
+  | spansetPipelineExpression AND ON OPEN_PARENS attributeList CLOSE_PARENS spansetPipelineExpression %prec AND { $$ = newSpansetJoinOperation($1, $5, $7) }
   | spansetPipelineExpression GT    spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetChild, $1, $3) }
   | spansetPipelineExpression LT    spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetParent, $1, $3) }
-  | spansetPipelineExpression DESC  spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetDescendant, $1, $3) }
+  | spansetPipelineExpression DESC  spansetPipelineExpression    { $$ = newSpansetDescendantOperation($1, $<staticInt>2, $3) }
   | spansetPipelineExpression ANCE  spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetAncestor, $1, $3) }
   | spansetPipelineExpression OR    spansetPipelineExpression    { $$ = newSpansetOperation(OpSpansetUnion, $1, $3) }
   | spansetPipelineExpression SIBL spansetPipelineExpression     { $$ = newSpansetOperation(OpSpansetSibling, $1, $3) }
@@ -187,6 +196,20 @@ attributeList:
   | attributeList COMMA attribute { $$ = append($1, $3) }
   ;
 
+// by() clause of metrics queries, attributes can be transformed with string functions
+groupBy:
+    attribute                                                              { $$ = newGroupByList($1, nil) }
+  | LOWER OPEN_PARENS attribute CLOSE_PARENS                               { $$ = newGroupByList($3, newGroupByStringOperation(stringLower, $3)) }
+  | REPLACE OPEN_PARENS attribute COMMA STRING COMMA STRING CLOSE_PARENS   { $$ = newGroupByList($3, newGroupByStringOperation(stringReplace, $3, NewStaticString($5), NewStaticString($7))) }
+  | SUBSTRING OPEN_PARENS attribute COMMA INTEGER CLOSE_PARENS             { $$ = newGroupByList($3, newGroupByStringOperation(stringSubstring, $3, NewStaticInt($5))) }
+  | SUBSTRING OPEN_PARENS attribute COMMA INTEGER COMMA INTEGER CLOSE_PARENS { $$ = newGroupByList($3, newGroupByStringOperation(stringSubstring, $3, NewStaticInt($5), NewStaticInt($7))) }
+  ;
+
+groupByList:
+    groupBy                   { $$ = $1 }
+  | groupByList COMMA groupBy { $$ = $1.append($3) }
+  ;
+
 // Comma-separated list of numeric values. Casts all to floats
 numericList:
   FLOAT                       { $$ = []float64{$1} }
@@ -198,9 +221,10 @@ numericList:
 spansetExpression: // shares the same operators as scalarPipelineExpression. split out for readability
     OPEN_PARENS spansetExpression CLOSE_PARENS   { $$ = $2 }
   | spansetExpression AND   spansetExpression    { $$ = newSpansetOperation(OpSpansetAnd, $1, $3) }
+  | spansetExpression AND ON OPEN_PARENS attributeList CLOSE_PARENS spansetExpression %prec AND { $$ = newSpansetJoinOperation($1, $5, $7) }
   | spansetExpression GT    spansetExpression    { $$ = newSpansetOperation(OpSpansetChild, $1, $3) }
   | spansetExpression LT    spansetExpression    { $$ = newSpansetOperation(OpSpansetParent, $1, $3) }
-  | spansetExpression DESC  spansetExpression    { $$ = newSpansetOperation(OpSpansetDescendant, $1, $3) }
+  | spansetExpression DESC  spansetExpression    { $$ = newSpansetDescendantOperation($1, $<staticInt>2, $3) }
   | spansetExpression ANCE  spansetExpression    { $$ = newSpansetOperation(OpSpansetAncestor, $1, $3) }
   | spansetExpression OR    spansetExpression    { $$ = newSpansetOperation(OpSpansetUnion, $1, $3) }
   | spansetExpression SIBL  spansetExpression    { $$ = newSpansetOperation(OpSpansetSibling, $1, $3) }
@@ -288,6 +312,7 @@ aggregate:
   | MIN OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateMin, $3) }
   | AVG OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateAvg, $3) }
   | SUM OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateSum, $3) }
+  | STDDEV OPEN_PARENS fieldExpression CLOSE_PARENS  { $$ = newAggregate(aggregateStddev, $3) }
   ;
 
 // **********************
@@ -295,19 +320,19 @@ aggregate:
 // **********************
 metricsAggregation:
       RATE            OPEN_PARENS CLOSE_PARENS                                                                          { $$ = newMetricsAggregate(metricsAggregateRate, nil) }
-    | RATE            OPEN_PARENS CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                                { $$ = newMetricsAggregate(metricsAggregateRate, $6) }
+    | RATE            OPEN_PARENS CLOSE_PARENS BY OPEN_PARENS groupByList CLOSE_PARENS                                  { $$ = withByTransforms(newMetricsAggregate(metricsAggregateRate, $6.attrs), $6) }
     | COUNT_OVER_TIME OPEN_PARENS CLOSE_PARENS                                                                          { $$ = newMetricsAggregate(metricsAggregateCountOverTime, nil) }
-    | COUNT_OVER_TIME OPEN_PARENS CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                                { $$ = newMetricsAggregate(metricsAggregateCountOverTime, $6) }
+    | COUNT_OVER_TIME OPEN_PARENS CLOSE_PARENS BY OPEN_PARENS groupByList CLOSE_PARENS                                  { $$ = withByTransforms(newMetricsAggregate(metricsAggregateCountOverTime, $6.attrs), $6) }
     | MIN_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                                  { $$ = newMetricsAggregateWithAttr(metricsAggregateMinOverTime, $3, nil) }
-    | MIN_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                        { $$ = newMetricsAggregateWithAttr(metricsAggregateMinOverTime, $3, $7) }
+    | MIN_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS groupByList CLOSE_PARENS                          { $$ = withByTransforms(newMetricsAggregateWithAttr(metricsAggregateMinOverTime, $3, $7.attrs), $7) }
     | MAX_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                                  { $$ = newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, $3, nil) }
-    | MAX_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                        { $$ = newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, $3, $7) }
+    | MAX_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS groupByList CLOSE_PARENS                          { $$ = withByTransforms(newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, $3, $7.attrs), $7) }
     | AVG_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                                  { $$ = newAverageOverTimeMetricsAggregator($3, nil) }
-    | AVG_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                        { $$ = newAverageOverTimeMetricsAggregator($3, $7) }
+    | AVG_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS groupByList CLOSE_PARENS                          { $$ = withByTransforms(newAverageOverTimeMetricsAggregator($3, $7.attrs), $7) }
     | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList CLOSE_PARENS                                           { $$ = newMetricsAggregateQuantileOverTime($3, $5, nil) }
-    | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS { $$ = newMetricsAggregateQuantileOverTime($3, $5, $9) }
+    | QUANTILE_OVER_TIME OPEN_PARENS attribute COMMA numericList CLOSE_PARENS BY OPEN_PARENS groupByList CLOSE_PARENS   { $$ = withByTransforms(newMetricsAggregateQuantileOverTime($3, $5, $9.attrs), $9) }
     | HISTOGRAM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS                                                            { $$ = newMetricsAggregateWithAttr(metricsAggregateHistogramOverTime, $3, nil) }
-    | HISTOGRAM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS attributeList CLOSE_PARENS                  { $$ = newMetricsAggregateWithAttr(metricsAggregateHistogramOverTime, $3, $7) }
+    | HISTOGRAM_OVER_TIME OPEN_PARENS attribute CLOSE_PARENS BY OPEN_PARENS groupByList CLOSE_PARENS                    { $$ = withByTransforms(newMetricsAggregateWithAttr(metricsAggregateHistogramOverTime, $3, $7.attrs), $7) }
     | COMPARE OPEN_PARENS spansetFilter CLOSE_PARENS                                                                    { $$ = newMetricsCompare($3, 10, 0, 0)}
     | COMPARE OPEN_PARENS spansetFilter COMMA INTEGER CLOSE_PARENS                                                      { $$ = newMetricsCompare($3, $5, 0, 0)}
     | COMPARE OPEN_PARENS spansetFilter COMMA INTEGER COMMA INTEGER COMMA INTEGER CLOSE_PARENS                          { $$ = newMetricsCompare($3, $5, $7, $9)}
@@ -357,6 +382,26 @@ fieldExpression:
   | intrinsicField                           { $$ = $1 }
   | attributeField                           { $$ = $1 }
   | scopedIntrinsicField                     { $$ = $1 }
+  | calendarField                            { $$ = $1 }
+  | stringField                              { $$ = $1 }
+  ;
+
+// calendar components of the span start time in UTC
+calendarField:
+    MINUTE OPEN_PARENS SPAN_COLON START CLOSE_PARENS       { $$ = newCalendarOperation(calendarMinute)     }
+  | HOUR OPEN_PARENS SPAN_COLON START CLOSE_PARENS         { $$ = newCalendarOperation(calendarHour)       }
+  | DAY_OF_WEEK OPEN_PARENS SPAN_COLON START CLOSE_PARENS  { $$ = newCalendarOperation(calendarDayOfWeek)  }
+  | DAY_OF_MONTH OPEN_PARENS SPAN_COLON START CLOSE_PARENS { $$ = newCalendarOperation(calendarDayOfMonth) }
+  | MONTH OPEN_PARENS SPAN_COLON START CLOSE_PARENS        { $$ = newCalendarOperation(calendarMonth)      }
+  | YEAR OPEN_PARENS SPAN_COLON START CLOSE_PARENS         { $$ = newCalendarOperation(calendarYear)       }
+  ;
+
+// string functions, replace patterns can reference capture groups with $1
+stringField:
+    LOWER OPEN_PARENS fieldExpression CLOSE_PARENS                                { $$ = newStringOperation(stringLower, $3) }
+  | REPLACE OPEN_PARENS fieldExpression COMMA STRING COMMA STRING CLOSE_PARENS    { $$ = newStringOperation(stringReplace, $3, NewStaticString($5), NewStaticString($7)) }
+  | SUBSTRING OPEN_PARENS fieldExpression COMMA INTEGER CLOSE_PARENS              { $$ = newStringOperation(stringSubstring, $3, NewStaticInt($5)) }
+  | SUBSTRING OPEN_PARENS fieldExpression COMMA INTEGER COMMA INTEGER CLOSE_PARENS { $$ = newStringOperation(stringSubstring, $3, NewStaticInt($5), NewStaticInt($7)) }
   ;
 
 // **********************
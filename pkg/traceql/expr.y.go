@@ -1,17 +1,17 @@
-// Code generated by goyacc -o pkg/traceql/expr.y.go pkg/traceql/expr.y. DO NOT EDIT.
+// Code generated by goyacc -o expr.y.go expr.y. DO NOT EDIT.
 
-//line pkg/traceql/expr.y:2
+//line expr.y:2
 package traceql
 
 import __yyfmt__ "fmt"
 
-//line pkg/traceql/expr.y:2
+//line expr.y:2
 
 import (
 	"time"
 )
 
-//line pkg/traceql/expr.y:11
+//line expr.y:11
 type yySymType struct {
 	yys               int
 	root              RootExpr
@@ -42,6 +42,9 @@ type yySymType struct {
 	attributeField       Attribute
 	attribute            Attribute
 	scopedIntrinsicField Attribute
+	calendarField        CalendarOperation
+	stringField          FieldExpression
+	groupByList          groupByList
 
 	binOp          Operator
 	staticInt      int
@@ -113,49 +116,61 @@ const AVG = 57400
 const MAX = 57401
 const MIN = 57402
 const SUM = 57403
-const BY = 57404
-const COALESCE = 57405
-const SELECT = 57406
-const END_ATTRIBUTE = 57407
-const RATE = 57408
-const COUNT_OVER_TIME = 57409
-const MIN_OVER_TIME = 57410
-const MAX_OVER_TIME = 57411
-const AVG_OVER_TIME = 57412
-const QUANTILE_OVER_TIME = 57413
-const HISTOGRAM_OVER_TIME = 57414
-const COMPARE = 57415
-const WITH = 57416
-const PIPE = 57417
-const AND = 57418
-const OR = 57419
-const EQ = 57420
-const NEQ = 57421
-const LT = 57422
-const LTE = 57423
-const GT = 57424
-const GTE = 57425
-const NRE = 57426
-const RE = 57427
-const DESC = 57428
-const ANCE = 57429
-const SIBL = 57430
-const NOT_CHILD = 57431
-const NOT_PARENT = 57432
-const NOT_DESC = 57433
-const NOT_ANCE = 57434
-const UNION_CHILD = 57435
-const UNION_PARENT = 57436
-const UNION_DESC = 57437
-const UNION_ANCE = 57438
-const UNION_SIBL = 57439
-const ADD = 57440
-const SUB = 57441
-const NOT = 57442
-const MUL = 57443
-const DIV = 57444
-const MOD = 57445
-const POW = 57446
+const STDDEV = 57404
+const BY = 57405
+const COALESCE = 57406
+const SELECT = 57407
+const ON = 57408
+const END_ATTRIBUTE = 57409
+const RATE = 57410
+const COUNT_OVER_TIME = 57411
+const MIN_OVER_TIME = 57412
+const MAX_OVER_TIME = 57413
+const AVG_OVER_TIME = 57414
+const QUANTILE_OVER_TIME = 57415
+const HISTOGRAM_OVER_TIME = 57416
+const COMPARE = 57417
+const WITH = 57418
+const START = 57419
+const MINUTE = 57420
+const HOUR = 57421
+const DAY_OF_WEEK = 57422
+const DAY_OF_MONTH = 57423
+const MONTH = 57424
+const YEAR = 57425
+const LOWER = 57426
+const REPLACE = 57427
+const SUBSTRING = 57428
+const PIPE = 57429
+const AND = 57430
+const OR = 57431
+const EQ = 57432
+const NEQ = 57433
+const LT = 57434
+const LTE = 57435
+const GT = 57436
+const GTE = 57437
+const NRE = 57438
+const RE = 57439
+const DESC = 57440
+const ANCE = 57441
+const SIBL = 57442
+const NOT_CHILD = 57443
+const NOT_PARENT = 57444
+const NOT_DESC = 57445
+const NOT_ANCE = 57446
+const UNION_CHILD = 57447
+const UNION_PARENT = 57448
+const UNION_DESC = 57449
+const UNION_ANCE = 57450
+const UNION_SIBL = 57451
+const ADD = 57452
+const SUB = 57453
+const NOT = 57454
+const MUL = 57455
+const DIV = 57456
+const MOD = 57457
+const POW = 57458
 
 var yyToknames = [...]string{
 	"$end",
@@ -219,9 +234,11 @@ var yyToknames = [...]string{
 	"MAX",
 	"MIN",
 	"SUM",
+	"STDDEV",
 	"BY",
 	"COALESCE",
 	"SELECT",
+	"ON",
 	"END_ATTRIBUTE",
 	"RATE",
 	"COUNT_OVER_TIME",
@@ -232,6 +249,16 @@ var yyToknames = [...]string{
 	"HISTOGRAM_OVER_TIME",
 	"COMPARE",
 	"WITH",
+	"START",
+	"MINUTE",
+	"HOUR",
+	"DAY_OF_WEEK",
+	"DAY_OF_MONTH",
+	"MONTH",
+	"YEAR",
+	"LOWER",
+	"REPLACE",
+	"SUBSTRING",
 	"PIPE",
 	"AND",
 	"OR",
@@ -274,303 +301,366 @@ var yyExca = [...]int{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 300,
-	13, 86,
-	-2, 94,
+	-1, 327,
+	13, 95,
+	-2, 103,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 994
+const yyLast = 1305
 
 var yyAct = [...]int{
 
-	101, 5, 6, 8, 7, 98, 100, 285, 18, 12,
-	248, 67, 90, 77, 338, 229, 206, 230, 205, 13,
-	298, 2, 94, 30, 99, 237, 238, 239, 248, 70,
-	66, 29, 153, 154, 157, 155, 235, 236, 205, 237,
-	238, 239, 248, 85, 86, 390, 87, 88, 89, 90,
-	186, 188, 189, 190, 191, 192, 193, 194, 195, 196,
-	197, 198, 199, 200, 201, 202, 203, 335, 351, 350,
-	371, 78, 79, 80, 81, 82, 83, 212, 72, 73,
-	348, 74, 75, 76, 77, 87, 88, 89, 90, 331,
-	367, 85, 86, 233, 87, 88, 89, 90, 330, 232,
-	206, 329, 210, 326, 220, 222, 223, 224, 225, 226,
-	227, 334, 402, 325, 228, 324, 323, 231, 251, 252,
-	253, 240, 241, 242, 243, 244, 245, 247, 246, 366,
-	249, 250, 240, 241, 242, 243, 244, 245, 247, 246,
-	365, 235, 236, 355, 237, 238, 239, 248, 74, 75,
-	76, 77, 235, 236, 333, 237, 238, 239, 248, 354,
-	257, 398, 210, 275, 276, 401, 305, 359, 295, 277,
-	280, 281, 282, 283, 249, 250, 240, 241, 242, 243,
-	244, 245, 247, 246, 360, 278, 296, 85, 86, 295,
-	87, 88, 89, 90, 208, 358, 235, 236, 273, 237,
-	238, 239, 248, 258, 259, 397, 305, 357, 153, 154,
-	157, 155, 356, 274, 347, 300, 340, 249, 250, 240,
-	241, 242, 243, 244, 245, 247, 246, 78, 79, 80,
-	81, 82, 83, 339, 302, 395, 305, 394, 305, 235,
-	236, 296, 237, 238, 239, 248, 279, 85, 86, 209,
-	87, 88, 89, 90, 393, 305, 384, 305, 306, 307,
-	308, 309, 310, 311, 312, 313, 314, 315, 316, 317,
-	318, 319, 320, 321, 383, 305, 381, 382, 396, 72,
-	73, 380, 74, 75, 76, 77, 379, 378, 361, 362,
-	233, 233, 233, 233, 233, 377, 232, 232, 232, 232,
-	232, 67, 376, 67, 346, 375, 233, 341, 342, 343,
-	344, 345, 232, 364, 231, 231, 231, 231, 231, 70,
-	363, 70, 302, 349, 336, 337, 304, 305, 297, 17,
-	231, 187, 294, 263, 78, 79, 80, 81, 82, 83,
-	264, 293, 265, 292, 353, 352, 291, 266, 290, 289,
-	153, 154, 157, 155, 72, 73, 288, 74, 75, 76,
-	77, 287, 213, 169, 233, 233, 151, 150, 149, 148,
-	232, 232, 147, 146, 92, 91, 233, 233, 233, 373,
-	374, 233, 232, 232, 232, 17, 84, 232, 231, 231,
-	400, 385, 386, 387, 389, 388, 391, 233, 71, 392,
-	231, 231, 231, 232, 267, 231, 268, 270, 271, 372,
-	269, 286, 399, 102, 103, 104, 108, 131, 272, 93,
-	95, 231, 328, 107, 105, 106, 110, 109, 111, 112,
-	113, 114, 115, 116, 117, 118, 119, 120, 121, 122,
-	124, 123, 125, 126, 327, 127, 128, 129, 130, 68,
-	11, 143, 144, 145, 134, 132, 133, 138, 139, 140,
-	135, 141, 136, 142, 137, 332, 370, 369, 102, 103,
-	104, 108, 131, 262, 261, 95, 260, 256, 107, 105,
-	106, 110, 109, 111, 112, 113, 114, 115, 116, 117,
-	118, 119, 120, 121, 122, 124, 123, 125, 126, 255,
-	127, 128, 129, 130, 322, 254, 28, 96, 97, 134,
-	132, 133, 138, 139, 140, 135, 141, 136, 142, 137,
-	284, 211, 214, 215, 216, 217, 218, 219, 249, 250,
-	240, 241, 242, 243, 244, 245, 247, 246, 303, 368,
-	69, 16, 4, 152, 10, 156, 234, 1, 0, 0,
-	235, 236, 0, 237, 238, 239, 248, 0, 0, 0,
-	0, 0, 96, 97, 0, 0, 0, 249, 250, 240,
-	241, 242, 243, 244, 245, 247, 246, 0, 0, 0,
-	0, 0, 0, 0, 207, 0, 0, 0, 0, 235,
-	236, 0, 237, 238, 239, 248, 0, 0, 0, 0,
-	0, 249, 250, 240, 241, 242, 243, 244, 245, 247,
-	246, 249, 250, 240, 241, 242, 243, 244, 245, 247,
-	246, 0, 0, 235, 236, 0, 237, 238, 239, 248,
-	0, 0, 0, 235, 236, 0, 237, 238, 239, 248,
-	19, 20, 21, 0, 17, 204, 166, 48, 53, 0,
-	0, 50, 0, 49, 0, 57, 0, 51, 52, 54,
-	55, 56, 59, 58, 60, 61, 64, 63, 62, 48,
-	53, 0, 0, 50, 0, 49, 0, 57, 0, 51,
-	52, 54, 55, 56, 59, 58, 60, 61, 64, 63,
-	62, 23, 26, 24, 25, 27, 14, 167, 15, 0,
-	158, 159, 160, 161, 162, 163, 164, 165, 31, 36,
-	0, 0, 33, 0, 32, 0, 42, 0, 34, 35,
-	37, 38, 39, 40, 41, 43, 44, 45, 46, 47,
-	31, 36, 0, 22, 33, 0, 32, 0, 42, 0,
-	34, 35, 37, 38, 39, 40, 41, 43, 44, 45,
-	46, 47, 19, 20, 21, 0, 17, 0, 166, 0,
-	19, 20, 21, 0, 17, 0, 301, 0, 19, 20,
-	21, 50, 17, 49, 299, 57, 0, 51, 52, 54,
-	55, 56, 59, 58, 60, 61, 64, 63, 62, 0,
-	0, 0, 0, 0, 0, 0, 19, 20, 21, 0,
-	17, 0, 9, 23, 26, 24, 25, 27, 14, 167,
-	15, 23, 26, 24, 25, 27, 14, 0, 15, 23,
-	26, 24, 25, 27, 14, 33, 15, 32, 0, 42,
-	0, 34, 35, 37, 38, 39, 40, 41, 43, 44,
-	45, 46, 47, 0, 0, 22, 0, 23, 26, 24,
-	25, 27, 14, 22, 15, 131, 19, 20, 21, 0,
-	17, 22, 166, 19, 20, 21, 0, 0, 0, 221,
-	0, 0, 0, 118, 119, 120, 121, 122, 124, 123,
-	125, 126, 0, 127, 128, 129, 130, 65, 3, 22,
-	0, 0, 134, 132, 133, 138, 139, 140, 135, 141,
-	136, 142, 137, 0, 0, 0, 0, 23, 26, 24,
-	25, 27, 0, 0, 23, 26, 24, 25, 27, 168,
-	170, 171, 172, 173, 174, 175, 176, 177, 178, 179,
-	180, 181, 182, 183, 184, 185, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 22,
-	102, 103, 104, 108, 0, 0, 22, 213, 0, 0,
-	107, 105, 106, 110, 109, 111, 112, 113, 114, 115,
-	116, 117, 102, 103, 104, 108, 0, 0, 0, 0,
-	0, 0, 107, 105, 106, 110, 109, 111, 112, 113,
-	114, 115, 116, 117,
+	102, 437, 6, 12, 8, 7, 324, 2, 18, 310,
+	244, 252, 253, 254, 263, 436, 67, 99, 263, 5,
+	88, 89, 90, 91, 91, 101, 78, 250, 251, 68,
+	252, 253, 254, 263, 167, 13, 170, 168, 86, 87,
+	375, 88, 89, 90, 91, 71, 75, 76, 77, 78,
+	100, 166, 73, 74, 399, 75, 76, 77, 78, 221,
+	31, 225, 396, 438, 66, 3, 395, 394, 393, 200,
+	203, 204, 205, 206, 207, 208, 209, 210, 211, 212,
+	213, 214, 215, 216, 217, 218, 220, 392, 391, 30,
+	227, 17, 390, 202, 248, 389, 358, 181, 184, 185,
+	186, 187, 188, 189, 190, 191, 192, 193, 194, 195,
+	196, 197, 198, 199, 19, 20, 21, 357, 17, 247,
+	179, 235, 237, 238, 239, 240, 241, 242, 264, 265,
+	255, 256, 257, 258, 259, 260, 262, 261, 79, 80,
+	81, 82, 83, 84, 246, 356, 220, 201, 183, 353,
+	250, 251, 352, 252, 253, 254, 263, 245, 86, 87,
+	386, 88, 89, 90, 91, 23, 26, 24, 25, 27,
+	28, 14, 180, 15, 398, 464, 171, 172, 173, 174,
+	175, 176, 177, 178, 397, 255, 256, 257, 258, 259,
+	260, 262, 261, 225, 351, 350, 364, 429, 425, 320,
+	424, 423, 182, 403, 402, 250, 251, 363, 252, 253,
+	254, 263, 362, 361, 360, 321, 359, 292, 488, 22,
+	221, 288, 320, 481, 167, 480, 170, 168, 372, 494,
+	327, 290, 291, 293, 272, 329, 289, 489, 490, 486,
+	453, 166, 477, 453, 475, 453, 474, 453, 264, 265,
+	255, 256, 257, 258, 259, 260, 262, 261, 478, 264,
+	265, 255, 256, 257, 258, 259, 260, 262, 261, 433,
+	250, 251, 321, 252, 253, 254, 263, 273, 274, 473,
+	453, 250, 251, 407, 252, 253, 254, 263, 458, 453,
+	86, 87, 493, 88, 89, 90, 91, 223, 454, 453,
+	449, 450, 487, 264, 265, 255, 256, 257, 258, 259,
+	260, 262, 261, 447, 446, 248, 248, 248, 248, 248,
+	434, 435, 479, 383, 248, 250, 251, 248, 252, 253,
+	254, 263, 476, 248, 385, 329, 468, 387, 412, 332,
+	247, 247, 247, 247, 247, 68, 278, 467, 68, 247,
+	411, 332, 247, 279, 418, 280, 409, 410, 247, 417,
+	281, 71, 373, 374, 71, 246, 246, 246, 246, 246,
+	331, 332, 416, 17, 246, 202, 457, 246, 378, 379,
+	380, 381, 382, 246, 400, 415, 414, 245, 413, 167,
+	245, 170, 168, 401, 73, 74, 388, 75, 76, 77,
+	78, 282, 408, 283, 285, 286, 166, 284, 406, 405,
+	404, 384, 95, 377, 376, 287, 79, 80, 81, 82,
+	83, 84, 248, 248, 303, 224, 456, 455, 448, 445,
+	444, 443, 432, 183, 422, 421, 86, 87, 442, 88,
+	89, 90, 91, 326, 248, 248, 248, 247, 247, 248,
+	323, 322, 319, 318, 248, 469, 248, 248, 248, 459,
+	460, 461, 317, 316, 465, 315, 314, 313, 312, 247,
+	247, 247, 246, 246, 247, 302, 431, 248, 301, 247,
+	300, 247, 247, 247, 299, 298, 297, 296, 295, 294,
+	228, 164, 482, 163, 246, 246, 246, 162, 161, 246,
+	160, 159, 247, 158, 246, 243, 246, 246, 246, 266,
+	267, 268, 93, 92, 85, 69, 11, 17, 492, 470,
+	471, 472, 371, 155, 156, 157, 72, 246, 463, 462,
+	428, 427, 485, 483, 466, 452, 105, 106, 107, 111,
+	134, 430, 94, 96, 420, 491, 110, 108, 109, 113,
+	112, 114, 115, 116, 117, 118, 119, 120, 121, 122,
+	123, 124, 125, 127, 126, 128, 129, 484, 130, 131,
+	132, 133, 304, 305, 306, 307, 308, 137, 135, 136,
+	141, 142, 143, 138, 144, 139, 145, 140, 226, 229,
+	230, 231, 232, 233, 234, 451, 419, 264, 265, 255,
+	256, 257, 258, 259, 260, 262, 261, 370, 311, 146,
+	147, 148, 149, 150, 151, 152, 153, 154, 355, 250,
+	251, 354, 252, 253, 254, 263, 79, 80, 81, 82,
+	83, 84, 277, 276, 275, 271, 270, 269, 29, 309,
+	426, 104, 97, 98, 103, 70, 73, 74, 16, 75,
+	76, 77, 78, 4, 165, 10, 169, 1, 0, 0,
+	0, 0, 0, 333, 334, 335, 336, 337, 338, 339,
+	340, 341, 342, 343, 344, 345, 346, 347, 348, 0,
+	0, 0, 264, 265, 255, 256, 257, 258, 259, 260,
+	262, 261, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 369, 0, 0, 250, 251, 0, 252, 253, 254,
+	263, 0, 0, 365, 366, 367, 105, 106, 107, 111,
+	134, 0, 0, 96, 0, 0, 110, 108, 109, 113,
+	112, 114, 115, 116, 117, 118, 119, 120, 121, 122,
+	123, 124, 125, 127, 126, 128, 129, 0, 130, 131,
+	132, 133, 368, 0, 0, 0, 0, 137, 135, 136,
+	141, 142, 143, 138, 144, 139, 145, 140, 0, 0,
+	0, 0, 0, 0, 0, 0, 264, 265, 255, 256,
+	257, 258, 259, 260, 262, 261, 349, 0, 0, 146,
+	147, 148, 149, 150, 151, 152, 153, 154, 250, 251,
+	0, 252, 253, 254, 263, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 330, 0, 0, 0, 0,
+	0, 0, 97, 98, 249, 0, 0, 264, 265, 255,
+	256, 257, 258, 259, 260, 262, 261, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 250,
+	251, 0, 252, 253, 254, 263, 222, 0, 0, 0,
+	0, 264, 265, 255, 256, 257, 258, 259, 260, 262,
+	261, 0, 0, 0, 0, 0, 0, 0, 219, 0,
+	0, 0, 0, 250, 251, 0, 252, 253, 254, 263,
+	264, 265, 255, 256, 257, 258, 259, 260, 262, 261,
+	0, 264, 265, 255, 256, 257, 258, 259, 260, 262,
+	261, 0, 250, 251, 0, 252, 253, 254, 263, 0,
+	0, 0, 0, 250, 251, 0, 252, 253, 254, 263,
+	0, 49, 54, 0, 0, 51, 0, 50, 0, 58,
+	0, 52, 53, 55, 56, 57, 60, 59, 61, 62,
+	65, 64, 63, 32, 37, 0, 0, 34, 0, 33,
+	0, 43, 0, 35, 36, 38, 39, 40, 41, 42,
+	44, 45, 46, 47, 48, 19, 20, 21, 0, 17,
+	0, 179, 49, 54, 0, 0, 51, 0, 50, 0,
+	58, 0, 52, 53, 55, 56, 57, 60, 59, 61,
+	62, 65, 64, 63, 32, 37, 0, 0, 34, 0,
+	33, 0, 43, 0, 35, 36, 38, 39, 40, 41,
+	42, 44, 45, 46, 47, 48, 23, 26, 24, 25,
+	27, 28, 14, 180, 15, 19, 20, 21, 0, 17,
+	0, 328, 0, 0, 19, 20, 21, 0, 17, 0,
+	325, 0, 0, 19, 20, 21, 51, 17, 50, 9,
+	58, 0, 52, 53, 55, 56, 57, 60, 59, 61,
+	62, 65, 64, 63, 0, 0, 0, 0, 0, 0,
+	22, 0, 0, 0, 0, 0, 23, 26, 24, 25,
+	27, 28, 14, 0, 15, 23, 26, 24, 25, 27,
+	28, 14, 0, 15, 23, 26, 24, 25, 27, 28,
+	14, 34, 15, 33, 0, 43, 0, 35, 36, 38,
+	39, 40, 41, 42, 44, 45, 46, 47, 48, 19,
+	20, 21, 0, 17, 0, 179, 19, 20, 21, 0,
+	22, 0, 236, 0, 0, 0, 0, 0, 0, 22,
+	105, 106, 107, 111, 0, 0, 0, 228, 22, 0,
+	110, 108, 109, 113, 112, 114, 115, 116, 117, 118,
+	119, 120, 0, 0, 0, 134, 0, 0, 0, 0,
+	23, 26, 24, 25, 27, 28, 0, 23, 26, 24,
+	25, 27, 28, 121, 122, 123, 124, 125, 127, 126,
+	128, 129, 0, 130, 131, 132, 133, 0, 0, 0,
+	0, 0, 137, 135, 136, 141, 142, 143, 138, 144,
+	139, 145, 140, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 22, 134, 0, 0, 0, 0,
+	0, 22, 0, 0, 0, 0, 0, 0, 0, 0,
+	439, 440, 441, 121, 122, 123, 124, 125, 127, 126,
+	128, 129, 0, 130, 131, 132, 133, 0, 0, 0,
+	0, 0, 137, 135, 136, 141, 142, 143, 138, 144,
+	139, 145, 140, 105, 106, 107, 111, 0, 0, 0,
+	0, 0, 0, 110, 108, 109, 113, 112, 114, 115,
+	116, 117, 118, 119, 120,
 }
 var yyPact = [...]int{
 
-	790, -43, -52, 654, -1000, 593, -1000, -1000, -1000, 790,
-	-1000, 256, -1000, -7, 363, 362, -1000, 408, -1000, -1000,
-	-1000, -1000, 445, 361, 360, 357, 356, 355, -1000, 354,
-	634, 351, 351, 351, 351, 351, 351, 351, 351, 351,
-	351, 351, 351, 351, 351, 351, 351, 351, 319, 319,
-	319, 319, 319, 319, 319, 319, 319, 319, 319, 319,
-	319, 319, 319, 319, 319, 632, 25, 571, 181, 236,
-	149, 945, 350, 350, 350, 350, 350, 350, -1000, -1000,
-	-1000, -1000, -1000, -1000, 857, 857, 857, 857, 857, 857,
-	857, 463, 846, -1000, 535, 463, 463, 463, -1000, -1000,
+	1047, 13, -27, 916, -1000, 894, -1000, -1000, -1000, 1047,
+	-1000, 536, -1000, 326, 501, 500, -1000, 531, -1000, -1000,
+	-1000, -1000, 517, 491, 489, 488, 486, 485, 481, -1000,
+	479, 108, 136, 421, 421, 421, 421, 421, 421, 421,
+	421, 421, 421, 421, 421, 421, 421, 421, 421, 81,
+	363, 363, 363, 363, 363, 363, 363, 363, 363, 363,
+	363, 363, 363, 363, 363, 363, 865, 133, 843, 284,
+	412, 48, 1145, 478, 478, 478, 478, 478, 478, -1000,
+	-1000, -1000, -1000, -1000, -1000, 1130, 1130, 1130, 1130, 1130,
+	1130, 1130, 711, 1226, -1000, 813, 711, 711, 711, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 501, 495, 473, 156, 472, 470, 469, 306, 377,
-	169, 121, 140, -1000, -1000, -1000, 233, 463, 463, 463,
-	463, 407, -1000, 593, -1000, -1000, -1000, -1000, 349, 344,
-	337, 336, 334, 331, 329, 320, 850, 316, 745, 762,
-	-1000, -1000, -1000, -1000, 745, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, 691, 319, -1000, -1000,
-	-1000, -1000, 691, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, 746, -1000, -1000, -1000,
-	-1000, -20, -1000, 754, 47, 47, -91, -91, -91, -91,
-	-55, 857, -16, -16, -92, -92, -92, -92, 525, 313,
-	-1000, -1000, -1000, -1000, -1000, 463, 463, 463, 463, 463,
-	463, 463, 463, 463, 463, 463, 463, 463, 463, 463,
-	463, 491, -76, -76, 51, 50, 48, 38, 440, 418,
-	36, 33, 24, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, 633, 632, 631, 230, 630, 629,
+	628, 319, 374, 192, 189, 188, 477, 476, 475, 474,
+	473, 472, 468, 466, 463, -1000, -1000, -1000, 411, 711,
+	711, 711, 711, 711, 604, -1000, 894, -1000, -1000, -1000,
+	-1000, 456, 455, 454, 453, 451, 450, 441, 440, 1123,
+	439, 1019, 438, 1038, -1000, -1000, -1000, -1000, 1019, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	452, 141, 98, 54, 311, -1000, -64, 220, 203, 846,
-	846, 846, 846, 846, 375, 571, 89, 201, 5, 762,
-	-1000, 754, -59, -1000, -1000, 846, -76, -76, -94, -94,
-	-94, -62, -62, -62, -62, -62, -62, -62, -62, -94,
-	43, 43, -1000, -1000, -1000, -1000, -1000, 4, 3, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, 407, 967, 97,
-	81, 199, 194, 182, 153, 171, 275, -1000, 746, -1000,
-	-1000, -1000, -1000, -1000, 308, 301, 78, 67, 28, 460,
-	8, -1000, 403, 846, 846, 293, 290, 283, 273, -1000,
-	-1000, 269, 263, 261, 243, 846, 846, 846, 388, -17,
-	846, -1000, 393, -1000, -1000, 241, 224, 222, -1000, -1000,
-	266, 192, 147, -1000, -1000, -1000, 846, -1000, 384, 152,
-	99, -1000, -1000,
+	964, 431, 363, -1000, -1000, -1000, -1000, 964, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, 969, -1000, -1000, -1000, -1000, -58, -1000, 1029, -67,
+	-67, -90, -90, -90, -90, -72, 1130, -93, -93, -92,
+	-92, -92, -92, 802, 357, -1000, -1000, -1000, -1000, -1000,
+	711, 711, 711, 711, 711, 711, 711, 711, 711, 711,
+	711, 711, 711, 711, 711, 711, 773, -102, -102, 128,
+	127, 85, 82, 617, 614, 78, 50, 29, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, 166, 164, 163, 162, 157, 146,
+	711, 711, 711, -1000, 739, 688, 594, 509, 215, 349,
+	-1000, -50, 401, 400, 1226, 1226, 1226, 1226, 1226, 507,
+	843, 180, 398, 1226, 73, 1038, 1226, -1000, 1029, -28,
+	-1000, -1000, 1226, -102, -102, -98, -98, -98, -83, -83,
+	-83, -83, -83, -83, -83, -83, -98, 95, 95, -1000,
+	-1000, -1000, -1000, -1000, 28, 25, -1000, -1000, -1000, 11,
+	10, -9, -10, -11, -15, 171, 160, 40, -1000, -1000,
+	-1000, -1000, -1000, -1000, 604, 1278, 141, 140, 397, 396,
+	395, 269, 389, 343, -1000, 337, 969, 325, -1000, -1000,
+	-1000, 375, 373, 372, 359, 346, 341, -1000, 591, 538,
+	-1000, -1000, 423, 422, 138, 137, 135, 524, 134, -1000,
+	535, 421, 363, -1000, -1000, -1000, -1000, -1000, -1000, 255,
+	307, 1166, 1166, 419, 418, 417, 300, -1000, -1000, 416,
+	287, 1019, 964, 590, -1000, 529, 285, -1000, -1000, 415,
+	414, 364, 275, 1166, 1166, 1166, 522, 112, 1166, -1000,
+	528, 334, 323, 1166, -1000, 1226, 1226, 1226, -1000, 266,
+	233, 231, -1000, -1000, 320, 229, 244, -1000, -1000, -1000,
+	309, 211, 209, -1000, -1000, -1000, 1166, -1000, 527, -1000,
+	562, 526, 226, 289, 204, 224, -1000, -1000, 540, -1000,
+	512, 279, 216, -1000, -1000,
 }
 var yyPgo = [...]int{
 
-	0, 547, 4, 545, 3, 15, 1, 887, 544, 20,
-	9, 2, 386, 543, 542, 449, 19, 541, 540, 8,
-	22, 5, 24, 6, 0, 17, 539, 7, 520, 506,
+	0, 657, 5, 656, 4, 10, 15, 1, 19, 64,
+	655, 6, 3, 2, 514, 654, 653, 515, 35, 648,
+	645, 8, 412, 17, 50, 25, 0, 644, 641, 63,
+	640, 9, 639, 638,
 }
 var yyR1 = [...]int{
 
-	0, 1, 1, 1, 1, 1, 7, 7, 7, 7,
-	7, 7, 7, 7, 7, 7, 7, 7, 7, 7,
-	7, 7, 7, 7, 7, 8, 9, 9, 9, 9,
-	9, 9, 9, 9, 9, 2, 3, 4, 25, 25,
-	25, 5, 5, 26, 26, 26, 26, 6, 6, 6,
-	6, 6, 6, 6, 6, 6, 6, 6, 6, 6,
-	6, 6, 6, 6, 6, 6, 10, 10, 11, 12,
-	12, 12, 12, 12, 12, 14, 14, 15, 15, 15,
-	15, 15, 15, 15, 15, 17, 18, 16, 16, 16,
-	16, 16, 16, 16, 16, 16, 16, 16, 16, 16,
-	16, 19, 19, 19, 19, 19, 13, 13, 13, 13,
-	13, 13, 13, 13, 13, 13, 13, 13, 13, 13,
-	13, 13, 13, 27, 29, 28, 28, 20, 20, 20,
-	20, 20, 20, 20, 20, 20, 20, 20, 20, 20,
-	20, 20, 20, 20, 20, 20, 20, 20, 20, 20,
-	21, 21, 21, 21, 21, 21, 21, 21, 21, 21,
-	21, 21, 21, 21, 21, 21, 22, 22, 22, 22,
-	22, 22, 22, 22, 22, 22, 22, 22, 22, 24,
+	0, 1, 1, 1, 1, 1, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 9, 9, 9, 9,
+	9, 9, 9, 9, 9, 9, 10, 11, 11, 11,
+	11, 11, 11, 11, 11, 11, 2, 3, 4, 29,
+	29, 29, 5, 5, 7, 7, 7, 7, 7, 6,
+	6, 30, 30, 30, 30, 8, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 8, 8, 8, 8, 8,
+	8, 8, 8, 8, 8, 12, 12, 13, 14, 14,
+	14, 14, 14, 14, 16, 16, 17, 17, 17, 17,
+	17, 17, 17, 17, 19, 20, 18, 18, 18, 18,
+	18, 18, 18, 18, 18, 18, 18, 18, 18, 18,
+	21, 21, 21, 21, 21, 21, 15, 15, 15, 15,
+	15, 15, 15, 15, 15, 15, 15, 15, 15, 15,
+	15, 15, 15, 31, 33, 32, 32, 22, 22, 22,
+	22, 22, 22, 22, 22, 22, 22, 22, 22, 22,
+	22, 22, 22, 22, 22, 22, 22, 22, 22, 22,
+	22, 22, 27, 27, 27, 27, 27, 27, 28, 28,
+	28, 28, 23, 23, 23, 23, 23, 23, 23, 23,
+	23, 23, 23, 23, 23, 23, 23, 23, 24, 24,
 	24, 24, 24, 24, 24, 24, 24, 24, 24, 24,
-	24, 24, 24, 24, 24, 23, 23, 23, 23, 23,
-	23, 23, 23, 23,
+	24, 26, 26, 26, 26, 26, 26, 26, 26, 26,
+	26, 26, 26, 26, 26, 26, 26, 25, 25, 25,
+	25, 25, 25, 25, 25, 25,
 }
 var yyR2 = [...]int{
 
-	0, 1, 1, 1, 3, 2, 3, 3, 3, 3,
+	0, 1, 1, 1, 3, 2, 3, 3, 7, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 1, 3, 1, 1, 1, 1,
-	3, 3, 3, 3, 3, 4, 3, 4, 1, 1,
-	1, 1, 3, 1, 1, 3, 3, 3, 3, 3,
+	3, 3, 3, 3, 3, 1, 3, 1, 1, 1,
+	1, 3, 3, 3, 3, 3, 4, 3, 4, 1,
+	1, 1, 1, 3, 1, 4, 8, 6, 8, 1,
+	3, 1, 1, 3, 3, 3, 3, 7, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 1, 2, 3, 3, 1,
-	1, 1, 1, 1, 1, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 1, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 1, 1, 1, 1, 2, 2,
-	2, 3, 4, 4, 4, 4, 3, 7, 3, 7,
+	3, 3, 3, 3, 1, 2, 3, 3, 1, 1,
+	1, 1, 1, 1, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 1, 3, 3, 3, 3, 3, 3,
+	3, 3, 3, 1, 1, 1, 1, 2, 2, 2,
+	3, 4, 4, 4, 4, 4, 3, 7, 3, 7,
 	4, 8, 4, 8, 4, 8, 6, 10, 4, 8,
 	4, 6, 10, 3, 4, 1, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 2, 2, 1, 1, 1, 1,
+	1, 1, 5, 5, 5, 5, 5, 5, 4, 8,
+	6, 8, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 1, 1, 1, 2,
-	2, 2, 2, 2, 2, 2, 2, 2, 2, 2,
-	2, 2, 2, 2, 2, 3, 3, 3, 3, 4,
-	4, 3, 3, 3,
+	1, 2, 2, 2, 2, 2, 2, 2, 2, 2,
+	2, 2, 2, 2, 2, 2, 2, 3, 3, 3,
+	3, 4, 4, 3, 3, 3,
 }
 var yyChk = [...]int{
 
-	-1000, -1, -9, -7, -14, -6, -11, -2, -4, 12,
-	-8, -15, -10, -16, 62, 64, -17, 10, -19, 6,
-	7, 8, 99, 57, 59, 60, 58, 61, -29, 74,
-	75, 76, 82, 80, 86, 87, 77, 88, 89, 90,
-	91, 92, 84, 93, 94, 95, 96, 97, 76, 82,
-	80, 86, 87, 77, 88, 89, 90, 84, 92, 91,
-	93, 94, 97, 96, 95, -7, -9, -6, -15, -18,
-	-16, -12, 98, 99, 101, 102, 103, 104, 78, 79,
-	80, 81, 82, 83, -12, 98, 99, 101, 102, 103,
-	104, 12, 12, 11, -20, 12, 99, 100, -21, -22,
-	-23, -24, 5, 6, 7, 16, 17, 15, 8, 19,
-	18, 20, 21, 22, 23, 24, 25, 26, 27, 28,
-	29, 30, 31, 33, 32, 34, 35, 37, 38, 39,
-	40, 9, 47, 48, 46, 52, 54, 56, 49, 50,
-	51, 53, 55, 6, 7, 8, 12, 12, 12, 12,
-	12, 12, -13, -6, -11, -2, -3, -4, 66, 67,
-	68, 69, 70, 71, 72, 73, 12, 63, -7, 12,
-	-7, -7, -7, -7, -7, -7, -7, -7, -7, -7,
-	-7, -7, -7, -7, -7, -7, -6, 12, -6, -6,
-	-6, -6, -6, -6, -6, -6, -6, -6, -6, -6,
-	-6, -6, -6, -6, 13, 13, 75, 13, 13, 13,
-	13, -15, -21, 12, -15, -15, -15, -15, -15, -15,
-	-16, 12, -16, -16, -16, -16, -16, -16, -20, -5,
-	-25, -22, -23, -24, 11, 98, 99, 101, 102, 103,
-	78, 79, 80, 81, 82, 83, 85, 84, 104, 76,
-	77, -20, -20, -20, 4, 4, 4, 4, 47, 48,
-	4, 4, 4, 27, 34, 36, 41, 27, 29, 33,
-	30, 31, 41, 29, 44, 42, 43, 29, 45, 13,
-	-20, -20, -20, -20, -28, -27, 4, 12, 12, 12,
-	12, 12, 12, 12, 12, -6, -16, 12, -9, 12,
-	-19, 12, -9, 13, 13, 14, -20, -20, -20, -20,
-	-20, -20, -20, -20, -20, -20, -20, -20, -20, -20,
-	-20, -20, 13, 65, 65, 65, 65, 4, 4, 65,
-	65, 65, 13, 13, 13, 13, 13, 14, 78, 13,
-	13, -25, -25, -25, -25, -25, -10, 13, 75, -25,
-	65, 65, -27, -21, 62, 62, 13, 13, 13, 14,
-	13, 13, 14, 12, 12, 62, 62, 62, -26, 7,
-	6, 62, 6, -5, -5, 12, 12, 12, 14, 13,
-	12, 13, 14, 13, 13, -5, -5, -5, 7, 6,
-	62, -5, 6, 13, 13, 13, 12, 13, 14, -5,
-	6, 13, 13,
+	-1000, -1, -11, -9, -16, -8, -13, -2, -4, 12,
+	-10, -17, -12, -18, 63, 65, -19, 10, -21, 6,
+	7, 8, 111, 57, 59, 60, 58, 61, 62, -33,
+	76, 87, 88, 94, 92, 98, 99, 89, 100, 101,
+	102, 103, 104, 96, 105, 106, 107, 108, 109, 88,
+	94, 92, 98, 99, 89, 100, 101, 102, 96, 104,
+	103, 105, 106, 109, 108, 107, -9, -11, -8, -17,
+	-20, -18, -14, 110, 111, 113, 114, 115, 116, 90,
+	91, 92, 93, 94, 95, -14, 110, 111, 113, 114,
+	115, 116, 12, 12, 11, -22, 12, 111, 112, -23,
+	-24, -25, -26, -27, -28, 5, 6, 7, 16, 17,
+	15, 8, 19, 18, 20, 21, 22, 23, 24, 25,
+	26, 27, 28, 29, 30, 31, 33, 32, 34, 35,
+	37, 38, 39, 40, 9, 47, 48, 46, 52, 54,
+	56, 49, 50, 51, 53, 55, 78, 79, 80, 81,
+	82, 83, 84, 85, 86, 6, 7, 8, 12, 12,
+	12, 12, 12, 12, 12, -15, -8, -13, -2, -3,
+	-4, 68, 69, 70, 71, 72, 73, 74, 75, 12,
+	64, -9, 66, 12, -9, -9, -9, -9, -9, -9,
+	-9, -9, -9, -9, -9, -9, -9, -9, -9, -9,
+	-8, 66, 12, -8, -8, -8, -8, -8, -8, -8,
+	-8, -8, -8, -8, -8, -8, -8, -8, -8, 13,
+	13, 87, 13, 13, 13, 13, -17, -23, 12, -17,
+	-17, -17, -17, -17, -17, -18, 12, -18, -18, -18,
+	-18, -18, -18, -22, -5, -29, -24, -25, -26, 11,
+	110, 111, 113, 114, 115, 90, 91, 92, 93, 94,
+	95, 97, 96, 116, 88, 89, -22, -22, -22, 4,
+	4, 4, 4, 47, 48, 4, 4, 4, 27, 34,
+	36, 41, 27, 29, 33, 30, 31, 41, 29, 44,
+	42, 43, 29, 45, 12, 12, 12, 12, 12, 12,
+	12, 12, 12, 13, -22, -22, -22, -22, -22, -32,
+	-31, 4, 12, 12, 12, 12, 12, 12, 12, 12,
+	-8, -18, 12, 12, -11, 12, 12, -21, 12, -11,
+	13, 13, 14, -22, -22, -22, -22, -22, -22, -22,
+	-22, -22, -22, -22, -22, -22, -22, -22, -22, 13,
+	67, 67, 67, 67, 4, 4, 67, 67, 67, 50,
+	50, 50, 50, 50, 50, -22, -22, -22, 13, 13,
+	13, 13, 13, 13, 14, 90, 13, 13, -29, -29,
+	-29, -29, -29, -12, 13, -5, 87, -5, -29, 67,
+	67, 77, 77, 77, 77, 77, 77, 13, 14, 14,
+	-31, -23, 63, 63, 13, 13, 13, 14, 13, 13,
+	14, 13, 13, 13, 13, 13, 13, 13, 13, 5,
+	6, 12, 12, 63, 63, 63, -30, 7, 6, 63,
+	6, -9, -8, 14, 13, 14, -6, -7, -29, 84,
+	85, 86, -6, 12, 12, 12, 14, 13, 12, 13,
+	14, 5, 6, 14, 13, 12, 12, 12, 13, -6,
+	-6, -6, 7, 6, 63, -6, 6, 13, 13, -7,
+	-29, -29, -29, 13, 13, 13, 12, 13, 14, 13,
+	14, 14, -6, 6, 5, 6, 13, 13, 14, 13,
+	14, 5, 6, 13, 13,
 }
 var yyDef = [...]int{
 
-	0, -2, 1, 2, 3, 26, 27, 28, 29, 0,
-	24, 0, 65, 0, 0, 0, 84, 0, 94, 95,
-	96, 97, 0, 0, 0, 0, 0, 0, 5, 0,
+	0, -2, 1, 2, 3, 27, 28, 29, 30, 0,
+	25, 0, 74, 0, 0, 0, 93, 0, 103, 104,
+	105, 106, 0, 0, 0, 0, 0, 0, 0, 5,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 26, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 69, 70,
-	71, 72, 73, 74, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 66, 0, 0, 0, 0, 146, 147,
-	148, 149, 150, 151, 152, 153, 154, 155, 156, 157,
-	158, 159, 160, 161, 162, 163, 164, 165, 166, 167,
-	168, 169, 170, 171, 172, 173, 174, 175, 176, 177,
-	178, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 98, 99, 100, 0, 0, 0, 0,
-	0, 0, 4, 30, 31, 32, 33, 34, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 7, 0,
-	8, 9, 10, 11, 12, 13, 14, 15, 16, 17,
-	18, 19, 20, 21, 22, 23, 48, 0, 49, 50,
-	51, 52, 53, 54, 55, 56, 57, 58, 59, 60,
-	61, 62, 63, 64, 6, 25, 0, 47, 77, 85,
-	87, 75, 76, 0, 78, 79, 80, 81, 82, 83,
-	68, 0, 88, 89, 90, 91, 92, 93, 0, 0,
-	41, 38, 39, 40, 67, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 27, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 78,
+	79, 80, 81, 82, 83, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 75, 0, 0, 0, 0, 156,
+	157, 158, 159, 160, 161, 172, 173, 174, 175, 176,
+	177, 178, 179, 180, 181, 182, 183, 184, 185, 186,
+	187, 188, 189, 190, 191, 192, 193, 194, 195, 196,
+	197, 198, 199, 200, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 144, 145, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 179, 180, 181, 182, 183, 184, 185,
-	186, 187, 188, 189, 190, 191, 192, 193, 194, 101,
-	0, 0, 0, 0, 0, 125, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 107, 108, 109, 0, 0,
+	0, 0, 0, 0, 0, 4, 31, 32, 33, 34,
+	35, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 7, 0, 0, 9, 10, 11, 12, 13, 14,
+	15, 16, 17, 18, 19, 20, 21, 22, 23, 24,
+	56, 0, 0, 58, 59, 60, 61, 62, 63, 64,
+	65, 66, 67, 68, 69, 70, 71, 72, 73, 6,
+	26, 0, 55, 86, 94, 96, 84, 85, 0, 87,
+	88, 89, 90, 91, 92, 77, 0, 97, 98, 99,
+	100, 101, 102, 0, 0, 42, 39, 40, 41, 76,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	-2, 0, 0, 35, 37, 0, 128, 129, 130, 131,
-	132, 133, 134, 135, 136, 137, 138, 139, 140, 141,
-	142, 143, 127, 195, 196, 197, 198, 0, 0, 201,
-	202, 203, 102, 103, 104, 105, 124, 0, 0, 106,
-	108, 0, 0, 0, 0, 0, 0, 36, 0, 42,
-	199, 200, 126, 123, 0, 0, 110, 112, 114, 0,
-	118, 120, 0, 0, 0, 0, 0, 0, 0, 43,
-	44, 0, 0, 0, 0, 0, 0, 0, 0, 116,
-	0, 121, 0, 107, 109, 0, 0, 0, 45, 46,
-	0, 0, 0, 111, 113, 115, 0, 119, 0, 0,
-	0, 117, 122,
+	0, 0, 0, 0, 0, 0, 0, 154, 155, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 201, 202,
+	203, 204, 205, 206, 207, 208, 209, 210, 211, 212,
+	213, 214, 215, 216, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 110, 0, 0, 0, 0, 0, 0,
+	135, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, -2, 0, 0,
+	36, 38, 0, 138, 139, 140, 141, 142, 143, 144,
+	145, 146, 147, 148, 149, 150, 151, 152, 153, 137,
+	217, 218, 219, 220, 0, 0, 223, 224, 225, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 111, 112,
+	113, 114, 115, 134, 0, 0, 116, 118, 0, 0,
+	0, 0, 0, 0, 37, 0, 0, 0, 43, 221,
+	222, 0, 0, 0, 0, 0, 0, 168, 0, 0,
+	136, 133, 0, 0, 120, 122, 124, 0, 128, 130,
+	0, 0, 0, 162, 163, 164, 165, 166, 167, 0,
+	0, 0, 0, 0, 0, 0, 0, 51, 52, 0,
+	0, 8, 57, 0, 170, 0, 0, 49, 44, 0,
+	0, 0, 0, 0, 0, 0, 0, 126, 0, 131,
+	0, 0, 0, 0, 117, 0, 0, 0, 119, 0,
+	0, 0, 53, 54, 0, 0, 0, 169, 171, 50,
+	0, 0, 0, 121, 123, 125, 0, 129, 0, 45,
+	0, 0, 0, 0, 0, 0, 127, 132, 0, 47,
+	0, 0, 0, 46, 48,
 }
 var yyTok1 = [...]int{
 
@@ -588,7 +678,8 @@ var yyTok2 = [...]int{
 	72, 73, 74, 75, 76, 77, 78, 79, 80, 81,
 	82, 83, 84, 85, 86, 87, 88, 89, 90, 91,
 	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
-	102, 103, 104,
+	102, 103, 104, 105, 106, 107, 108, 109, 110, 111,
+	112, 113, 114, 115, 116,
 }
 var yyTok3 = [...]int{
 	0,
@@ -933,1219 +1024,1351 @@ yydefault:
 
 	case 1:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:120
+//line expr.y:128
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].spansetPipeline)
 		}
 	case 2:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:121
+//line expr.y:129
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].spansetPipelineExpression)
 		}
 	case 3:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:122
+//line expr.y:130
 		{
 			yylex.(*lexer).expr = newRootExpr(yyDollar[1].scalarPipelineExpressionFilter)
 		}
 	case 4:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:123
+//line expr.y:131
 		{
 			yylex.(*lexer).expr = newRootExprWithMetrics(yyDollar[1].spansetPipeline, yyDollar[3].metricsAggregation)
 		}
 	case 5:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:124
+//line expr.y:132
 		{
 			yylex.(*lexer).expr.withHints(yyDollar[2].hints)
 		}
 	case 6:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:131
+//line expr.y:139
 		{
 			yyVAL.spansetPipelineExpression = yyDollar[2].spansetPipelineExpression
 		}
 	case 7:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:132
+//line expr.y:140
 		{
 			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAnd, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 8:
-		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:133
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line expr.y:141
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetJoinOperation(yyDollar[1].spansetPipelineExpression, yyDollar[5].attributeList, yyDollar[7].spansetPipelineExpression)
 		}
 	case 9:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:134
+//line expr.y:142
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 10:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:135
+//line expr.y:143
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 11:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:136
+//line expr.y:144
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetDescendantOperation(yyDollar[1].spansetPipelineExpression, yyDollar[2].staticInt, yyDollar[3].spansetPipelineExpression)
 		}
 	case 12:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:137
+//line expr.y:145
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 13:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:138
+//line expr.y:146
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 14:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:139
+//line expr.y:147
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 15:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:140
+//line expr.y:148
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 16:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:141
+//line expr.y:149
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 17:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:142
+//line expr.y:150
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 18:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:143
+//line expr.y:151
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 19:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:144
+//line expr.y:152
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetNotSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 20:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:145
+//line expr.y:153
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionChild, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 21:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:146
+//line expr.y:154
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionParent, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 22:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:147
+//line expr.y:155
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionDescendant, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 23:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:148
+//line expr.y:156
 		{
-			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionAncestor, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
 		}
 	case 24:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:157
+		{
+			yyVAL.spansetPipelineExpression = newSpansetOperation(OpSpansetUnionSibling, yyDollar[1].spansetPipelineExpression, yyDollar[3].spansetPipelineExpression)
+		}
+	case 25:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:149
+//line expr.y:158
 		{
 			yyVAL.spansetPipelineExpression = yyDollar[1].wrappedSpansetPipeline
 		}
-	case 25:
+	case 26:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:153
+//line expr.y:162
 		{
 			yyVAL.wrappedSpansetPipeline = yyDollar[2].spansetPipeline
 		}
-	case 26:
+	case 27:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:156
+//line expr.y:165
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].spansetExpression)
 		}
-	case 27:
+	case 28:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:157
+//line expr.y:166
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].scalarFilter)
 		}
-	case 28:
+	case 29:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:158
+//line expr.y:167
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].groupOperation)
 		}
-	case 29:
+	case 30:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:159
+//line expr.y:168
 		{
 			yyVAL.spansetPipeline = newPipeline(yyDollar[1].selectOperation)
 		}
-	case 30:
+	case 31:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:160
+//line expr.y:169
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].spansetExpression)
 		}
-	case 31:
+	case 32:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:161
+//line expr.y:170
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].scalarFilter)
 		}
-	case 32:
+	case 33:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:162
+//line expr.y:171
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].groupOperation)
 		}
-	case 33:
+	case 34:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:163
+//line expr.y:172
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].coalesceOperation)
 		}
-	case 34:
+	case 35:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:164
+//line expr.y:173
 		{
 			yyVAL.spansetPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].selectOperation)
 		}
-	case 35:
+	case 36:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:168
+//line expr.y:177
 		{
 			yyVAL.groupOperation = newGroupOperation(yyDollar[3].fieldExpression)
 		}
-	case 36:
+	case 37:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:172
+//line expr.y:181
 		{
 			yyVAL.coalesceOperation = newCoalesceOperation()
 		}
-	case 37:
+	case 38:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:176
+//line expr.y:185
 		{
 			yyVAL.selectOperation = newSelectOperation(yyDollar[3].attributeList)
 		}
-	case 38:
+	case 39:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:180
+//line expr.y:189
 		{
 			yyVAL.attribute = yyDollar[1].intrinsicField
 		}
-	case 39:
+	case 40:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:181
+//line expr.y:190
 		{
 			yyVAL.attribute = yyDollar[1].attributeField
 		}
-	case 40:
+	case 41:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:182
+//line expr.y:191
 		{
 			yyVAL.attribute = yyDollar[1].scopedIntrinsicField
 		}
-	case 41:
+	case 42:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:186
+//line expr.y:195
 		{
 			yyVAL.attributeList = []Attribute{yyDollar[1].attribute}
 		}
-	case 42:
+	case 43:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:187
+//line expr.y:196
 		{
 			yyVAL.attributeList = append(yyDollar[1].attributeList, yyDollar[3].attribute)
 		}
-	case 43:
+	case 44:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:201
+		{
+			yyVAL.groupByList = newGroupByList(yyDollar[1].attribute, nil)
+		}
+	case 45:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line expr.y:202
+		{
+			yyVAL.groupByList = newGroupByList(yyDollar[3].attribute, newGroupByStringOperation(stringLower, yyDollar[3].attribute))
+		}
+	case 46:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line expr.y:203
+		{
+			yyVAL.groupByList = newGroupByList(yyDollar[3].attribute, newGroupByStringOperation(stringReplace, yyDollar[3].attribute, NewStaticString(yyDollar[5].staticStr), NewStaticString(yyDollar[7].staticStr)))
+		}
+	case 47:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line expr.y:204
+		{
+			yyVAL.groupByList = newGroupByList(yyDollar[3].attribute, newGroupByStringOperation(stringSubstring, yyDollar[3].attribute, NewStaticInt(yyDollar[5].staticInt)))
+		}
+	case 48:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line expr.y:205
+		{
+			yyVAL.groupByList = newGroupByList(yyDollar[3].attribute, newGroupByStringOperation(stringSubstring, yyDollar[3].attribute, NewStaticInt(yyDollar[5].staticInt), NewStaticInt(yyDollar[7].staticInt)))
+		}
+	case 49:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:209
+		{
+			yyVAL.groupByList = yyDollar[1].groupByList
+		}
+	case 50:
+		yyDollar = yyS[yypt-3 : yypt+1]
+//line expr.y:210
+		{
+			yyVAL.groupByList = yyDollar[1].groupByList.append(yyDollar[3].groupByList)
+		}
+	case 51:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:192
+//line expr.y:215
 		{
 			yyVAL.numericList = []float64{yyDollar[1].staticFloat}
 		}
-	case 44:
+	case 52:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:193
+//line expr.y:216
 		{
 			yyVAL.numericList = []float64{float64(yyDollar[1].staticInt)}
 		}
-	case 45:
+	case 53:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:194
+//line expr.y:217
 		{
 			yyVAL.numericList = append(yyDollar[1].numericList, yyDollar[3].staticFloat)
 		}
-	case 46:
+	case 54:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:195
+//line expr.y:218
 		{
 			yyVAL.numericList = append(yyDollar[1].numericList, float64(yyDollar[3].staticInt))
 		}
-	case 47:
+	case 55:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:199
+//line expr.y:222
 		{
 			yyVAL.spansetExpression = yyDollar[2].spansetExpression
 		}
-	case 48:
+	case 56:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:200
+//line expr.y:223
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetAnd, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 49:
+	case 57:
+		yyDollar = yyS[yypt-7 : yypt+1]
+//line expr.y:224
+		{
+			yyVAL.spansetExpression = newSpansetJoinOperation(yyDollar[1].spansetExpression, yyDollar[5].attributeList, yyDollar[7].spansetExpression)
+		}
+	case 58:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:201
+//line expr.y:225
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 50:
+	case 59:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:202
+//line expr.y:226
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 51:
+	case 60:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:203
+//line expr.y:227
 		{
-			yyVAL.spansetExpression = newSpansetOperation(OpSpansetDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
+			yyVAL.spansetExpression = newSpansetDescendantOperation(yyDollar[1].spansetExpression, yyDollar[2].staticInt, yyDollar[3].spansetExpression)
 		}
-	case 52:
+	case 61:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:204
+//line expr.y:228
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 53:
+	case 62:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:205
+//line expr.y:229
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnion, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 54:
+	case 63:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:206
+//line expr.y:230
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 55:
+	case 64:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:208
+//line expr.y:232
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 56:
+	case 65:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:209
+//line expr.y:233
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 57:
+	case 66:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:210
+//line expr.y:234
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 58:
+	case 67:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:211
+//line expr.y:235
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 59:
+	case 68:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:212
+//line expr.y:236
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetNotDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 60:
+	case 69:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:214
+//line expr.y:238
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionChild, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 61:
+	case 70:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:215
+//line expr.y:239
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionParent, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 62:
+	case 71:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:216
+//line expr.y:240
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionSibling, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 63:
+	case 72:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:217
+//line expr.y:241
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionAncestor, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 64:
+	case 73:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:218
+//line expr.y:242
 		{
 			yyVAL.spansetExpression = newSpansetOperation(OpSpansetUnionDescendant, yyDollar[1].spansetExpression, yyDollar[3].spansetExpression)
 		}
-	case 65:
+	case 74:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:220
+//line expr.y:244
 		{
 			yyVAL.spansetExpression = yyDollar[1].spansetFilter
 		}
-	case 66:
+	case 75:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:224
+//line expr.y:248
 		{
 			yyVAL.spansetFilter = newSpansetFilter(NewStaticBool(true))
 		}
-	case 67:
+	case 76:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:225
+//line expr.y:249
 		{
 			yyVAL.spansetFilter = newSpansetFilter(yyDollar[2].fieldExpression)
 		}
-	case 68:
+	case 77:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:229
+//line expr.y:253
 		{
 			yyVAL.scalarFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 69:
+	case 78:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:233
+//line expr.y:257
 		{
 			yyVAL.scalarFilterOperation = OpEqual
 		}
-	case 70:
+	case 79:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:234
+//line expr.y:258
 		{
 			yyVAL.scalarFilterOperation = OpNotEqual
 		}
-	case 71:
+	case 80:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:235
+//line expr.y:259
 		{
 			yyVAL.scalarFilterOperation = OpLess
 		}
-	case 72:
+	case 81:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:236
+//line expr.y:260
 		{
 			yyVAL.scalarFilterOperation = OpLessEqual
 		}
-	case 73:
+	case 82:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:237
+//line expr.y:261
 		{
 			yyVAL.scalarFilterOperation = OpGreater
 		}
-	case 74:
+	case 83:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:238
+//line expr.y:262
 		{
 			yyVAL.scalarFilterOperation = OpGreaterEqual
 		}
-	case 75:
+	case 84:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:245
+//line expr.y:269
 		{
 			yyVAL.scalarPipelineExpressionFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 76:
+	case 85:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:246
+//line expr.y:270
 		{
 			yyVAL.scalarPipelineExpressionFilter = newScalarFilter(yyDollar[2].scalarFilterOperation, yyDollar[1].scalarPipelineExpression, yyDollar[3].static)
 		}
-	case 77:
+	case 86:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:250
+//line expr.y:274
 		{
 			yyVAL.scalarPipelineExpression = yyDollar[2].scalarPipelineExpression
 		}
-	case 78:
+	case 87:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:251
+//line expr.y:275
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpAdd, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 79:
+	case 88:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:252
+//line expr.y:276
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpSub, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 80:
+	case 89:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:253
+//line expr.y:277
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpMult, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 81:
+	case 90:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:254
+//line expr.y:278
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpDiv, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 82:
+	case 91:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:255
+//line expr.y:279
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpMod, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 83:
+	case 92:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:256
+//line expr.y:280
 		{
 			yyVAL.scalarPipelineExpression = newScalarOperation(OpPower, yyDollar[1].scalarPipelineExpression, yyDollar[3].scalarPipelineExpression)
 		}
-	case 84:
+	case 93:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:257
+//line expr.y:281
 		{
 			yyVAL.scalarPipelineExpression = yyDollar[1].wrappedScalarPipeline
 		}
-	case 85:
+	case 94:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:261
+//line expr.y:285
 		{
 			yyVAL.wrappedScalarPipeline = yyDollar[2].scalarPipeline
 		}
-	case 86:
+	case 95:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:265
+//line expr.y:289
 		{
 			yyVAL.scalarPipeline = yyDollar[1].spansetPipeline.addItem(yyDollar[3].aggregate)
 		}
-	case 87:
+	case 96:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:269
+//line expr.y:293
 		{
 			yyVAL.scalarExpression = yyDollar[2].scalarExpression
 		}
-	case 88:
+	case 97:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:270
+//line expr.y:294
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpAdd, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 89:
+	case 98:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:271
+//line expr.y:295
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpSub, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 90:
+	case 99:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:272
+//line expr.y:296
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpMult, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 91:
+	case 100:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:273
+//line expr.y:297
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpDiv, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 92:
+	case 101:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:274
+//line expr.y:298
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpMod, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 93:
+	case 102:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:275
+//line expr.y:299
 		{
 			yyVAL.scalarExpression = newScalarOperation(OpPower, yyDollar[1].scalarExpression, yyDollar[3].scalarExpression)
 		}
-	case 94:
+	case 103:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:276
+//line expr.y:300
 		{
 			yyVAL.scalarExpression = yyDollar[1].aggregate
 		}
-	case 95:
+	case 104:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:277
+//line expr.y:301
 		{
 			yyVAL.scalarExpression = NewStaticInt(yyDollar[1].staticInt)
 		}
-	case 96:
+	case 105:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:278
+//line expr.y:302
 		{
 			yyVAL.scalarExpression = NewStaticFloat(yyDollar[1].staticFloat)
 		}
-	case 97:
+	case 106:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:279
+//line expr.y:303
 		{
 			yyVAL.scalarExpression = NewStaticDuration(yyDollar[1].staticDuration)
 		}
-	case 98:
+	case 107:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:280
+//line expr.y:304
 		{
 			yyVAL.scalarExpression = NewStaticInt(-yyDollar[2].staticInt)
 		}
-	case 99:
+	case 108:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:281
+//line expr.y:305
 		{
 			yyVAL.scalarExpression = NewStaticFloat(-yyDollar[2].staticFloat)
 		}
-	case 100:
+	case 109:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:282
+//line expr.y:306
 		{
 			yyVAL.scalarExpression = NewStaticDuration(-yyDollar[2].staticDuration)
 		}
-	case 101:
+	case 110:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:286
+//line expr.y:310
 		{
 			yyVAL.aggregate = newAggregate(aggregateCount, nil)
 		}
-	case 102:
+	case 111:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:287
+//line expr.y:311
 		{
 			yyVAL.aggregate = newAggregate(aggregateMax, yyDollar[3].fieldExpression)
 		}
-	case 103:
+	case 112:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:288
+//line expr.y:312
 		{
 			yyVAL.aggregate = newAggregate(aggregateMin, yyDollar[3].fieldExpression)
 		}
-	case 104:
+	case 113:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:289
+//line expr.y:313
 		{
 			yyVAL.aggregate = newAggregate(aggregateAvg, yyDollar[3].fieldExpression)
 		}
-	case 105:
+	case 114:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:290
+//line expr.y:314
 		{
 			yyVAL.aggregate = newAggregate(aggregateSum, yyDollar[3].fieldExpression)
 		}
-	case 106:
+	case 115:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line expr.y:315
+		{
+			yyVAL.aggregate = newAggregate(aggregateStddev, yyDollar[3].fieldExpression)
+		}
+	case 116:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:297
+//line expr.y:322
 		{
 			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateRate, nil)
 		}
-	case 107:
+	case 117:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line pkg/traceql/expr.y:298
+//line expr.y:323
 		{
-			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateRate, yyDollar[6].attributeList)
+			yyVAL.metricsAggregation = withByTransforms(newMetricsAggregate(metricsAggregateRate, yyDollar[6].groupByList.attrs), yyDollar[6].groupByList)
 		}
-	case 108:
+	case 118:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:299
+//line expr.y:324
 		{
 			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateCountOverTime, nil)
 		}
-	case 109:
+	case 119:
 		yyDollar = yyS[yypt-7 : yypt+1]
-//line pkg/traceql/expr.y:300
+//line expr.y:325
 		{
-			yyVAL.metricsAggregation = newMetricsAggregate(metricsAggregateCountOverTime, yyDollar[6].attributeList)
+			yyVAL.metricsAggregation = withByTransforms(newMetricsAggregate(metricsAggregateCountOverTime, yyDollar[6].groupByList.attrs), yyDollar[6].groupByList)
 		}
-	case 110:
+	case 120:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:301
+//line expr.y:326
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMinOverTime, yyDollar[3].attribute, nil)
 		}
-	case 111:
+	case 121:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line pkg/traceql/expr.y:302
+//line expr.y:327
 		{
-			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMinOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
+			yyVAL.metricsAggregation = withByTransforms(newMetricsAggregateWithAttr(metricsAggregateMinOverTime, yyDollar[3].attribute, yyDollar[7].groupByList.attrs), yyDollar[7].groupByList)
 		}
-	case 112:
+	case 122:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:303
+//line expr.y:328
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, yyDollar[3].attribute, nil)
 		}
-	case 113:
+	case 123:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line pkg/traceql/expr.y:304
+//line expr.y:329
 		{
-			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
+			yyVAL.metricsAggregation = withByTransforms(newMetricsAggregateWithAttr(metricsAggregateMaxOverTime, yyDollar[3].attribute, yyDollar[7].groupByList.attrs), yyDollar[7].groupByList)
 		}
-	case 114:
+	case 124:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:305
+//line expr.y:330
 		{
 			yyVAL.metricsAggregation = newAverageOverTimeMetricsAggregator(yyDollar[3].attribute, nil)
 		}
-	case 115:
+	case 125:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line pkg/traceql/expr.y:306
+//line expr.y:331
 		{
-			yyVAL.metricsAggregation = newAverageOverTimeMetricsAggregator(yyDollar[3].attribute, yyDollar[7].attributeList)
+			yyVAL.metricsAggregation = withByTransforms(newAverageOverTimeMetricsAggregator(yyDollar[3].attribute, yyDollar[7].groupByList.attrs), yyDollar[7].groupByList)
 		}
-	case 116:
+	case 126:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line pkg/traceql/expr.y:307
+//line expr.y:332
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, nil)
 		}
-	case 117:
+	case 127:
 		yyDollar = yyS[yypt-10 : yypt+1]
-//line pkg/traceql/expr.y:308
+//line expr.y:333
 		{
-			yyVAL.metricsAggregation = newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, yyDollar[9].attributeList)
+			yyVAL.metricsAggregation = withByTransforms(newMetricsAggregateQuantileOverTime(yyDollar[3].attribute, yyDollar[5].numericList, yyDollar[9].groupByList.attrs), yyDollar[9].groupByList)
 		}
-	case 118:
+	case 128:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:309
+//line expr.y:334
 		{
 			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateHistogramOverTime, yyDollar[3].attribute, nil)
 		}
-	case 119:
+	case 129:
 		yyDollar = yyS[yypt-8 : yypt+1]
-//line pkg/traceql/expr.y:310
+//line expr.y:335
 		{
-			yyVAL.metricsAggregation = newMetricsAggregateWithAttr(metricsAggregateHistogramOverTime, yyDollar[3].attribute, yyDollar[7].attributeList)
+			yyVAL.metricsAggregation = withByTransforms(newMetricsAggregateWithAttr(metricsAggregateHistogramOverTime, yyDollar[3].attribute, yyDollar[7].groupByList.attrs), yyDollar[7].groupByList)
 		}
-	case 120:
+	case 130:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:311
+//line expr.y:336
 		{
 			yyVAL.metricsAggregation = newMetricsCompare(yyDollar[3].spansetFilter, 10, 0, 0)
 		}
-	case 121:
+	case 131:
 		yyDollar = yyS[yypt-6 : yypt+1]
-//line pkg/traceql/expr.y:312
+//line expr.y:337
 		{
 			yyVAL.metricsAggregation = newMetricsCompare(yyDollar[3].spansetFilter, yyDollar[5].staticInt, 0, 0)
 		}
-	case 122:
+	case 132:
 		yyDollar = yyS[yypt-10 : yypt+1]
-//line pkg/traceql/expr.y:313
+//line expr.y:338
 		{
 			yyVAL.metricsAggregation = newMetricsCompare(yyDollar[3].spansetFilter, yyDollar[5].staticInt, yyDollar[7].staticInt, yyDollar[9].staticInt)
 		}
-	case 123:
+	case 133:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:320
+//line expr.y:345
 		{
 			yyVAL.hint = newHint(yyDollar[1].staticStr, yyDollar[3].static)
 		}
-	case 124:
+	case 134:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:324
+//line expr.y:349
 		{
 			yyVAL.hints = newHints(yyDollar[3].hintList)
 		}
-	case 125:
+	case 135:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:328
+//line expr.y:353
 		{
 			yyVAL.hintList = []*Hint{yyDollar[1].hint}
 		}
-	case 126:
+	case 136:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:329
+//line expr.y:354
 		{
 			yyVAL.hintList = append(yyDollar[1].hintList, yyDollar[3].hint)
 		}
-	case 127:
+	case 137:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:337
+//line expr.y:362
 		{
 			yyVAL.fieldExpression = yyDollar[2].fieldExpression
 		}
-	case 128:
+	case 138:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:338
+//line expr.y:363
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpAdd, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 129:
+	case 139:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:339
+//line expr.y:364
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpSub, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 130:
+	case 140:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:340
+//line expr.y:365
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpMult, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 131:
+	case 141:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:341
+//line expr.y:366
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpDiv, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 132:
+	case 142:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:342
+//line expr.y:367
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpMod, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 133:
+	case 143:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:343
+//line expr.y:368
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 134:
+	case 144:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:344
+//line expr.y:369
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpNotEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 135:
+	case 145:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:345
+//line expr.y:370
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpLess, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 136:
+	case 146:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:346
+//line expr.y:371
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpLessEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 137:
+	case 147:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:347
+//line expr.y:372
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpGreater, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 138:
+	case 148:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:348
+//line expr.y:373
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpGreaterEqual, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 139:
+	case 149:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:349
+//line expr.y:374
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpRegex, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 140:
+	case 150:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:350
+//line expr.y:375
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpNotRegex, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 141:
+	case 151:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:351
+//line expr.y:376
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpPower, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 142:
+	case 152:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:352
+//line expr.y:377
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpAnd, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 143:
+	case 153:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:353
+//line expr.y:378
 		{
 			yyVAL.fieldExpression = newBinaryOperation(OpOr, yyDollar[1].fieldExpression, yyDollar[3].fieldExpression)
 		}
-	case 144:
+	case 154:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:354
+//line expr.y:379
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpSub, yyDollar[2].fieldExpression)
 		}
-	case 145:
+	case 155:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:355
+//line expr.y:380
 		{
 			yyVAL.fieldExpression = newUnaryOperation(OpNot, yyDollar[2].fieldExpression)
 		}
-	case 146:
+	case 156:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:356
+//line expr.y:381
 		{
 			yyVAL.fieldExpression = yyDollar[1].static
 		}
-	case 147:
+	case 157:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:357
+//line expr.y:382
 		{
 			yyVAL.fieldExpression = yyDollar[1].intrinsicField
 		}
-	case 148:
+	case 158:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:358
+//line expr.y:383
 		{
 			yyVAL.fieldExpression = yyDollar[1].attributeField
 		}
-	case 149:
+	case 159:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:359
+//line expr.y:384
 		{
 			yyVAL.fieldExpression = yyDollar[1].scopedIntrinsicField
 		}
-	case 150:
+	case 160:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:385
+		{
+			yyVAL.fieldExpression = yyDollar[1].calendarField
+		}
+	case 161:
+		yyDollar = yyS[yypt-1 : yypt+1]
+//line expr.y:386
+		{
+			yyVAL.fieldExpression = yyDollar[1].stringField
+		}
+	case 162:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line expr.y:391
+		{
+			yyVAL.calendarField = newCalendarOperation(calendarMinute)
+		}
+	case 163:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line expr.y:392
+		{
+			yyVAL.calendarField = newCalendarOperation(calendarHour)
+		}
+	case 164:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line expr.y:393
+		{
+			yyVAL.calendarField = newCalendarOperation(calendarDayOfWeek)
+		}
+	case 165:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line expr.y:394
+		{
+			yyVAL.calendarField = newCalendarOperation(calendarDayOfMonth)
+		}
+	case 166:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line expr.y:395
+		{
+			yyVAL.calendarField = newCalendarOperation(calendarMonth)
+		}
+	case 167:
+		yyDollar = yyS[yypt-5 : yypt+1]
+//line expr.y:396
+		{
+			yyVAL.calendarField = newCalendarOperation(calendarYear)
+		}
+	case 168:
+		yyDollar = yyS[yypt-4 : yypt+1]
+//line expr.y:401
+		{
+			yyVAL.stringField = newStringOperation(stringLower, yyDollar[3].fieldExpression)
+		}
+	case 169:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line expr.y:402
+		{
+			yyVAL.stringField = newStringOperation(stringReplace, yyDollar[3].fieldExpression, NewStaticString(yyDollar[5].staticStr), NewStaticString(yyDollar[7].staticStr))
+		}
+	case 170:
+		yyDollar = yyS[yypt-6 : yypt+1]
+//line expr.y:403
+		{
+			yyVAL.stringField = newStringOperation(stringSubstring, yyDollar[3].fieldExpression, NewStaticInt(yyDollar[5].staticInt))
+		}
+	case 171:
+		yyDollar = yyS[yypt-8 : yypt+1]
+//line expr.y:404
+		{
+			yyVAL.stringField = newStringOperation(stringSubstring, yyDollar[3].fieldExpression, NewStaticInt(yyDollar[5].staticInt), NewStaticInt(yyDollar[7].staticInt))
+		}
+	case 172:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:366
+//line expr.y:411
 		{
 			yyVAL.static = NewStaticString(yyDollar[1].staticStr)
 		}
-	case 151:
+	case 173:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:367
+//line expr.y:412
 		{
 			yyVAL.static = NewStaticInt(yyDollar[1].staticInt)
 		}
-	case 152:
+	case 174:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:368
+//line expr.y:413
 		{
 			yyVAL.static = NewStaticFloat(yyDollar[1].staticFloat)
 		}
-	case 153:
+	case 175:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:369
+//line expr.y:414
 		{
 			yyVAL.static = NewStaticBool(true)
 		}
-	case 154:
+	case 176:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:370
+//line expr.y:415
 		{
 			yyVAL.static = NewStaticBool(false)
 		}
-	case 155:
+	case 177:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:371
+//line expr.y:416
 		{
 			yyVAL.static = NewStaticNil()
 		}
-	case 156:
+	case 178:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:372
+//line expr.y:417
 		{
 			yyVAL.static = NewStaticDuration(yyDollar[1].staticDuration)
 		}
-	case 157:
+	case 179:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:373
+//line expr.y:418
 		{
 			yyVAL.static = NewStaticStatus(StatusOk)
 		}
-	case 158:
+	case 180:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:374
+//line expr.y:419
 		{
 			yyVAL.static = NewStaticStatus(StatusError)
 		}
-	case 159:
+	case 181:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:375
+//line expr.y:420
 		{
 			yyVAL.static = NewStaticStatus(StatusUnset)
 		}
-	case 160:
+	case 182:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:376
+//line expr.y:421
 		{
 			yyVAL.static = NewStaticKind(KindUnspecified)
 		}
-	case 161:
+	case 183:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:377
+//line expr.y:422
 		{
 			yyVAL.static = NewStaticKind(KindInternal)
 		}
-	case 162:
+	case 184:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:378
+//line expr.y:423
 		{
 			yyVAL.static = NewStaticKind(KindServer)
 		}
-	case 163:
+	case 185:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:379
+//line expr.y:424
 		{
 			yyVAL.static = NewStaticKind(KindClient)
 		}
-	case 164:
+	case 186:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:380
+//line expr.y:425
 		{
 			yyVAL.static = NewStaticKind(KindProducer)
 		}
-	case 165:
+	case 187:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:381
+//line expr.y:426
 		{
 			yyVAL.static = NewStaticKind(KindConsumer)
 		}
-	case 166:
+	case 188:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:387
+//line expr.y:432
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicDuration)
 		}
-	case 167:
+	case 189:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:388
+//line expr.y:433
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicChildCount)
 		}
-	case 168:
+	case 190:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:389
+//line expr.y:434
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicName)
 		}
-	case 169:
+	case 191:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:390
+//line expr.y:435
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicStatus)
 		}
-	case 170:
+	case 192:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:391
+//line expr.y:436
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicStatusMessage)
 		}
-	case 171:
+	case 193:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:392
+//line expr.y:437
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicKind)
 		}
-	case 172:
+	case 194:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:393
+//line expr.y:438
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicParent)
 		}
-	case 173:
+	case 195:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:394
+//line expr.y:439
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceRootSpan)
 		}
-	case 174:
+	case 196:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:395
+//line expr.y:440
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceRootService)
 		}
-	case 175:
+	case 197:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:396
+//line expr.y:441
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicTraceDuration)
 		}
-	case 176:
+	case 198:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:397
+//line expr.y:442
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicNestedSetLeft)
 		}
-	case 177:
+	case 199:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:398
+//line expr.y:443
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicNestedSetRight)
 		}
-	case 178:
+	case 200:
 		yyDollar = yyS[yypt-1 : yypt+1]
-//line pkg/traceql/expr.y:399
+//line expr.y:444
 		{
 			yyVAL.intrinsicField = NewIntrinsic(IntrinsicNestedSetParent)
 		}
-	case 179:
+	case 201:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:404
+//line expr.y:449
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceDuration)
 		}
-	case 180:
+	case 202:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:405
+//line expr.y:450
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceRootSpan)
 		}
-	case 181:
+	case 203:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:406
+//line expr.y:451
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceRootService)
 		}
-	case 182:
+	case 204:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:407
+//line expr.y:452
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicTraceID)
 		}
-	case 183:
+	case 205:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:409
+//line expr.y:454
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicDuration)
 		}
-	case 184:
+	case 206:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:410
+//line expr.y:455
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicName)
 		}
-	case 185:
+	case 207:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:411
+//line expr.y:456
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicKind)
 		}
-	case 186:
+	case 208:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:412
+//line expr.y:457
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicStatus)
 		}
-	case 187:
+	case 209:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:413
+//line expr.y:458
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicStatusMessage)
 		}
-	case 188:
+	case 210:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:414
+//line expr.y:459
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicSpanID)
 		}
-	case 189:
+	case 211:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:416
+//line expr.y:461
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicEventName)
 		}
-	case 190:
+	case 212:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:417
+//line expr.y:462
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicEventTimeSinceStart)
 		}
-	case 191:
+	case 213:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:419
+//line expr.y:464
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicLinkTraceID)
 		}
-	case 192:
+	case 214:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:420
+//line expr.y:465
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicLinkSpanID)
 		}
-	case 193:
+	case 215:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:422
+//line expr.y:467
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicInstrumentationName)
 		}
-	case 194:
+	case 216:
 		yyDollar = yyS[yypt-2 : yypt+1]
-//line pkg/traceql/expr.y:423
+//line expr.y:468
 		{
 			yyVAL.scopedIntrinsicField = NewIntrinsic(IntrinsicInstrumentationVersion)
 		}
-	case 195:
+	case 217:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:427
+//line expr.y:472
 		{
 			yyVAL.attributeField = NewAttribute(yyDollar[2].staticStr)
 		}
-	case 196:
+	case 218:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:428
+//line expr.y:473
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, false, yyDollar[2].staticStr)
 		}
-	case 197:
+	case 219:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:429
+//line expr.y:474
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, false, yyDollar[2].staticStr)
 		}
-	case 198:
+	case 220:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:430
+//line expr.y:475
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeNone, true, yyDollar[2].staticStr)
 		}
-	case 199:
+	case 221:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:431
+//line expr.y:476
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeResource, true, yyDollar[3].staticStr)
 		}
-	case 200:
+	case 222:
 		yyDollar = yyS[yypt-4 : yypt+1]
-//line pkg/traceql/expr.y:432
+//line expr.y:477
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeSpan, true, yyDollar[3].staticStr)
 		}
-	case 201:
+	case 223:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:433
+//line expr.y:478
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeEvent, false, yyDollar[2].staticStr)
 		}
-	case 202:
+	case 224:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:434
+//line expr.y:479
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeLink, false, yyDollar[2].staticStr)
 		}
-	case 203:
+	case 225:
 		yyDollar = yyS[yypt-3 : yypt+1]
-//line pkg/traceql/expr.y:435
+//line expr.y:480
 		{
 			yyVAL.attributeField = NewScopedAttribute(AttributeScopeInstrumentation, false, yyDollar[2].staticStr)
 		}
@@ -67,6 +67,23 @@ func ExtractMatchers(query string) string {
 	return q.String()
 }
 
+// TagValuesQuery returns the query used to filter tag values. Valid TraceQL is returned unchanged so that all
+// of its conditions are applied, matchers are extracted from anything else, e.g. a query that is still being typed.
+func TagValuesQuery(query string) string {
+	query = strings.TrimSpace(query)
+
+	expr, err := Parse(query)
+	if err != nil {
+		return ExtractMatchers(query)
+	}
+
+	if expr.Pipeline.String() == "{ true }" {
+		return emptyQuery
+	}
+
+	return query
+}
+
 func IsEmptyQuery(query string) bool {
 	return query == emptyQuery || len(query) == 0
 }
@@ -133,6 +133,38 @@ func TestExtractMatchers(t *testing.T) {
 	}
 }
 
+func TestTagValuesQuery(t *testing.T) {
+	testCases := []struct {
+		name, query, expected string
+	}{
+		{
+			name:     "empty query",
+			query:    " ",
+			expected: "{}",
+		},
+		{
+			name:     "unfiltered query",
+			query:    "{ }",
+			expected: "{}",
+		},
+		{
+			name:     "valid query is kept",
+			query:    ` { .foo = "bar" } || { .baz = "qux" } `,
+			expected: `{ .foo = "bar" } || { .baz = "qux" }`,
+		},
+		{
+			name:     "incomplete query",
+			query:    `{ .http.status_code = 200 && .http.method = }`,
+			expected: "{.http.status_code = 200}",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, TagValuesQuery(tc.query))
+		})
+	}
+}
+
 func BenchmarkExtractMatchers(b *testing.B) {
 	queries := []string{
 		`{.service_name = "foo"}`,
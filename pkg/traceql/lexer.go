@@ -2,6 +2,7 @@ package traceql
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 	"text/scanner"
@@ -97,9 +98,11 @@ var tokens = map[string]int{
 	"max":                 MAX,
 	"min":                 MIN,
 	"sum":                 SUM,
+	"stddev":              STDDEV,
 	"by":                  BY,
 	"coalesce":            COALESCE,
 	"select":              SELECT,
+	"on":                  ON,
 	"rate":                RATE,
 	"count_over_time":     COUNT_OVER_TIME,
 	"min_over_time":       MIN_OVER_TIME,
@@ -109,6 +112,16 @@ var tokens = map[string]int{
 	"histogram_over_time": HISTOGRAM_OVER_TIME,
 	"compare":             COMPARE,
 	"with":                WITH,
+	"start":               START,
+	"minute":              MINUTE,
+	"hour":                HOUR,
+	"day_of_week":         DAY_OF_WEEK,
+	"day_of_month":        DAY_OF_MONTH,
+	"month":               MONTH,
+	"year":                YEAR,
+	"lower":               LOWER,
+	"replace":             REPLACE,
+	"substring":           SUBSTRING,
 }
 
 type lexer struct {
@@ -231,6 +244,16 @@ func (l *lexer) Lex(lval *yySymType) int {
 		l.currentScope = multiTok
 	}
 
+	// the descendant operator can be followed by the max depth of the descendants, e.g. >>3
+	if multiTok == DESC {
+		var err error
+		lval.staticInt, err = scanDescendantDepth(&l.Scanner)
+		if err != nil {
+			l.Error(err.Error())
+			return 0
+		}
+	}
+
 	// did we find a combination token?
 	if multiTok != -1 {
 		l.parsingAttribute = startsAttribute(multiTok)
@@ -252,6 +275,27 @@ func (l *lexer) Error(msg string) {
 	l.errs = append(l.errs, newParseError(msg, l.Line, l.Column))
 }
 
+// scanDescendantDepth consumes the digits directly following a descendant operator and returns the depth they
+// represent, or 0 if there are none.
+func scanDescendantDepth(s *scanner.Scanner) (int, error) {
+	const maxDepth = 1000
+
+	digits := 0
+	depth := 0
+	for r := s.Peek(); r >= '0' && r <= '9'; r = s.Peek() {
+		depth = depth*10 + int(s.Next()-'0')
+		digits++
+		if depth > maxDepth {
+			return 0, fmt.Errorf("descendant depth must not be greater than %d", maxDepth)
+		}
+	}
+
+	if digits > 0 && depth == 0 {
+		return 0, errors.New("descendant depth must be greater than 0")
+	}
+	return depth, nil
+}
+
 func parseAttribute(s *scanner.Scanner) (string, error) {
 	var sb strings.Builder
 	r := s.Peek()
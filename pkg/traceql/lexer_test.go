@@ -113,6 +113,13 @@ func TestLexerScopedIntrinsic(t *testing.T) {
 		// instrumentation scoped intrinsics
 		{`instrumentation:name`, []int{INSTRUMENTATION_COLON, NAME}},
 		{`instrumentation:version`, []int{INSTRUMENTATION_COLON, VERSION}},
+		// calendar functions
+		{`hour(span:start)`, []int{HOUR, OPEN_PARENS, SPAN_COLON, START, CLOSE_PARENS}},
+		{`day_of_week(span:start)`, []int{DAY_OF_WEEK, OPEN_PARENS, SPAN_COLON, START, CLOSE_PARENS}},
+		// string functions
+		{`lower(span.foo)`, []int{LOWER, OPEN_PARENS, SPAN_DOT, IDENTIFIER, END_ATTRIBUTE, CLOSE_PARENS}},
+		{`replace(.foo, "a", "b")`, []int{REPLACE, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, COMMA, STRING, COMMA, STRING, CLOSE_PARENS}},
+		{`substring(.foo, 1, 2)`, []int{SUBSTRING, OPEN_PARENS, DOT, IDENTIFIER, END_ATTRIBUTE, COMMA, INTEGER, COMMA, INTEGER, CLOSE_PARENS}},
 	}))
 }
 
@@ -129,6 +136,7 @@ func TestLexerMultitokens(t *testing.T) {
 		// attributes
 		{`&&`, []int{AND}},
 		{`>>`, []int{DESC}},
+		{`>>3`, []int{DESC}},
 		{`!<<`, []int{NOT_ANCE}},
 		{`!`, []int{NOT}},
 		{`!~`, []int{NRE}},
@@ -531,7 +531,7 @@ func TestSpansetExpressionErrors(t *testing.T) {
 		in  string
 		err error
 	}{
-		{in: "{ true } &&", err: newParseError("syntax error: unexpected $end, expecting { or (", 1, 12)},
+		{in: "{ true } &&", err: newParseError("syntax error: unexpected $end, expecting { or ( or on", 1, 12)},
 	}
 
 	for _, tc := range tests {
@@ -604,6 +604,7 @@ func TestSpansetExpressionOperators(t *testing.T) {
 		{in: "{ true } > { false }", expected: newSpansetOperation(OpSpansetChild, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		{in: "{ true } < { false }", expected: newSpansetOperation(OpSpansetParent, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		{in: "{ true } >> { false }", expected: newSpansetOperation(OpSpansetDescendant, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } >>3 { false }", expected: newSpansetDescendantOperation(newSpansetFilter(NewStaticBool(true)), 3, newSpansetFilter(NewStaticBool(false)))},
 		{in: "{ true } << { false }", expected: newSpansetOperation(OpSpansetAncestor, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		{in: "{ true } || { false }", expected: newSpansetOperation(OpSpansetUnion, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		{in: "{ true } ~ { false }", expected: newSpansetOperation(OpSpansetSibling, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
@@ -619,6 +620,8 @@ func TestSpansetExpressionOperators(t *testing.T) {
 		{in: "{ true } &>> { false }", expected: newSpansetOperation(OpSpansetUnionDescendant, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		{in: "{ true } &<< { false }", expected: newSpansetOperation(OpSpansetUnionAncestor, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
 		{in: "{ true } &~ { false }", expected: newSpansetOperation(OpSpansetUnionSibling, newSpansetFilter(NewStaticBool(true)), newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } && on(span.foo) { false }", expected: newSpansetJoinOperation(newSpansetFilter(NewStaticBool(true)), []Attribute{NewScopedAttribute(AttributeScopeSpan, false, "foo")}, newSpansetFilter(NewStaticBool(false)))},
+		{in: "{ true } && on(.a, resource.b) { false }", expected: newSpansetJoinOperation(newSpansetFilter(NewStaticBool(true)), []Attribute{NewAttribute("a"), NewScopedAttribute(AttributeScopeResource, false, "b")}, newSpansetFilter(NewStaticBool(false)))},
 	}
 
 	for _, tc := range tests {
@@ -698,6 +701,7 @@ func TestScalarExpressionOperators(t *testing.T) {
 		{in: "min(1) > 1", expected: newScalarFilter(OpGreater, newAggregate(aggregateMin, NewStaticInt(1)), NewStaticInt(1))},
 		{in: "sum(true) > 1", expected: newScalarFilter(OpGreater, newAggregate(aggregateSum, NewStaticBool(true)), NewStaticInt(1))},
 		{in: "avg(`c`) > 1", expected: newScalarFilter(OpGreater, newAggregate(aggregateAvg, NewStaticString("c")), NewStaticInt(1))},
+		{in: "stddev(duration) > 1s", expected: newScalarFilter(OpGreater, newAggregate(aggregateStddev, NewIntrinsic(IntrinsicDuration)), NewStaticDuration(time.Second))},
 	}
 
 	for _, tc := range tests {
@@ -1250,6 +1254,71 @@ func TestScopedIntrinsics(t *testing.T) {
 	}
 }
 
+func TestParseCalendarFunctions(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected CalendarFunction
+	}{
+		{in: "minute(span:start)", expected: calendarMinute},
+		{in: "hour(span:start)", expected: calendarHour},
+		{in: "day_of_week(span:start)", expected: calendarDayOfWeek},
+		{in: "day_of_month(span:start)", expected: calendarDayOfMonth},
+		{in: "month(span:start)", expected: calendarMonth},
+		{in: "year(span:start)", expected: calendarYear},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			s := "{ " + tc.in + " = 1 }"
+			actual, err := Parse(s)
+
+			require.NoError(t, err)
+			require.Equal(t, newRootExpr(newPipeline(
+				newSpansetFilter(newBinaryOperation(OpEqual, newCalendarOperation(tc.expected), NewStaticInt(1))))), actual)
+			require.Equal(t, s, actual.String())
+		})
+	}
+}
+
+func TestParseStringFunctions(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected FieldExpression
+	}{
+		{in: `lower(.foo)`, expected: newStringOperation(stringLower, NewAttribute("foo"))},
+		{in: `replace(span.foo, "[0-9]+", "{id}")`, expected: newStringOperation(stringReplace, NewScopedAttribute(AttributeScopeSpan, false, "foo"), NewStaticString("[0-9]+"), NewStaticString("{id}"))},
+		{in: `substring(name, 1)`, expected: newStringOperation(stringSubstring, NewIntrinsic(IntrinsicName), NewStaticInt(1))},
+		{in: `substring(name, 1, 2)`, expected: newStringOperation(stringSubstring, NewIntrinsic(IntrinsicName), NewStaticInt(1), NewStaticInt(2))},
+		{in: `lower("FOO")`, expected: NewStaticString("foo")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			actual, err := Parse("{ " + tc.in + ` = "a" }`)
+
+			require.NoError(t, err)
+			require.Equal(t, newRootExpr(newPipeline(
+				newSpansetFilter(newBinaryOperation(OpEqual, tc.expected, NewStaticString("a"))))), actual)
+		})
+	}
+}
+
+func TestParseMetricsByStringFunctions(t *testing.T) {
+	actual, err := Parse(`{ } | rate() by (replace(span.url, "/[0-9]+", "/{id}"), resource.service.name)`)
+	require.NoError(t, err)
+
+	url := NewScopedAttribute(AttributeScopeSpan, false, "url")
+	service := NewScopedAttribute(AttributeScopeResource, false, "service.name")
+	expected := newMetricsAggregate(metricsAggregateRate, []Attribute{url, service})
+	expected.setByTransforms([]*StringOperation{
+		newGroupByStringOperation(stringReplace, url, NewStaticString("/[0-9]+"), NewStaticString("/{id}")),
+		nil,
+	})
+
+	require.Equal(t, newRootExprWithMetrics(newPipeline(newSpansetFilter(NewStaticBool(true))), expected), actual)
+	require.Equal(t, "{ true } | rate()by(replace(span.url, `/[0-9]+`, `/{id}`),resource.service.name)", actual.String())
+}
+
 func TestParseIdentifier(t *testing.T) {
 	testCases := map[string]Attribute{
 		"name":             NewIntrinsic(IntrinsicName),
@@ -171,6 +171,13 @@ type Span interface {
 	ChildOf(lhs []Span, rhs []Span, falseForAll bool, invert bool, union bool, buffer []Span) []Span
 }
 
+// spanWithLinks is implemented by spans that keep the attributes of each of their links. AllAttributes only holds
+// one value per attribute.
+type spanWithLinks interface {
+	// LinkAttributesFunc calls the callback for the attributes of every link of the span.
+	LinkAttributesFunc(func(Attribute, Static))
+}
+
 // should we just make matched a field on the spanset instead of a special attribute?
 const attributeMatched = "__matched"
 
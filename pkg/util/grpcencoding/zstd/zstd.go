@@ -0,0 +1,98 @@
+// Package zstd registers a zstd compressor with the gRPC encoding registry. Importing this package makes
+// "zstd" available as a value for grpc_compression on both the client and the server side.
+package zstd
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+)
+
+// Name is the name registered for the zstd compressor.
+const Name = "zstd"
+
+func init() {
+	encoding.RegisterCompressor(newCompressor())
+}
+
+type compressor struct {
+	writersPool sync.Pool
+	readersPool sync.Pool
+}
+
+func newCompressor() *compressor {
+	c := &compressor{}
+	c.writersPool = sync.Pool{
+		New: func() interface{} {
+			// concurrency of 1 keeps the encoder synchronous so it does not spawn goroutines per stream.
+			w, _ := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1), zstd.WithEncoderLevel(zstd.SpeedFastest))
+			return w
+		},
+	}
+	c.readersPool = sync.Pool{
+		New: func() interface{} {
+			r, _ := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+			return r
+		},
+	}
+	return c
+}
+
+func (c *compressor) Name() string {
+	return Name
+}
+
+func (c *compressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	wr := c.writersPool.Get().(*zstd.Encoder)
+	wr.Reset(w)
+	return writeCloser{wr, &c.writersPool}, nil
+}
+
+func (c *compressor) Decompress(r io.Reader) (io.Reader, error) {
+	dr := c.readersPool.Get().(*zstd.Decoder)
+	if err := dr.Reset(r); err != nil {
+		c.readersPool.Put(dr)
+		return nil, err
+	}
+	return &reader{dr, &c.readersPool}, nil
+}
+
+type writeCloser struct {
+	writer *zstd.Encoder
+	pool   *sync.Pool
+}
+
+func (w writeCloser) Write(p []byte) (n int, err error) {
+	return w.writer.Write(p)
+}
+
+func (w writeCloser) Close() error {
+	defer func() {
+		w.writer.Reset(nil)
+		w.pool.Put(w.writer)
+	}()
+
+	return w.writer.Close()
+}
+
+type reader struct {
+	reader *zstd.Decoder
+	pool   *sync.Pool
+}
+
+func (r *reader) Read(p []byte) (n int, err error) {
+	if r.reader == nil {
+		return 0, io.EOF
+	}
+
+	n, err = r.reader.Read(p)
+	if err == io.EOF {
+		// return the decoder to the pool only once. the reader must not be used after this
+		_ = r.reader.Reset(nil)
+		r.pool.Put(r.reader)
+		r.reader = nil
+	}
+	return n, err
+}
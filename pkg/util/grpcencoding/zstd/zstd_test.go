@@ -0,0 +1,49 @@
+package zstd
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/encoding"
+)
+
+func TestCompressorRegistered(t *testing.T) {
+	c := encoding.GetCompressor(Name)
+	require.NotNil(t, c)
+	require.Equal(t, Name, c.Name())
+}
+
+func TestCompressRoundTrip(t *testing.T) {
+	c := encoding.GetCompressor(Name)
+
+	tcs := []string{
+		"",
+		"short",
+		strings.Repeat("tempo distributor to ingester payload ", 10_000),
+	}
+
+	// run twice to exercise pooled encoders and decoders
+	for i := 0; i < 2; i++ {
+		for _, tc := range tcs {
+			buf := &bytes.Buffer{}
+			w, err := c.Compress(buf)
+			require.NoError(t, err)
+			_, err = w.Write([]byte(tc))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+
+			if len(tc) > 1000 {
+				require.Less(t, buf.Len(), len(tc))
+			}
+
+			r, err := c.Decompress(buf)
+			require.NoError(t, err)
+			actual, err := io.ReadAll(r)
+			require.NoError(t, err)
+			require.Equal(t, tc, string(actual))
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+}
+
+// TimeWindow is a recurring window of time, e.g. from 22:00 to 06:00 on saturdays. It must be parsed before
+// Contains is called.
+type TimeWindow struct {
+	// Days of the week the window starts on, every day if empty.
+	Days []string `yaml:"days,omitempty"`
+	// Start and End of the window in the format 15:04. The window spans midnight if End is not after Start.
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+	// Timezone is the IANA name of the time zone of the window, UTC if empty.
+	Timezone string `yaml:"timezone,omitempty"`
+
+	days     map[time.Weekday]struct{}
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+}
+
+// Parse validates the window and prepares it to be evaluated by Contains.
+func (w *TimeWindow) Parse() error {
+	var err error
+	if w.start, err = parseTimeOfDay(w.Start); err != nil {
+		return fmt.Errorf("invalid start: %w", err)
+	}
+	if w.end, err = parseTimeOfDay(w.End); err != nil {
+		return fmt.Errorf("invalid end: %w", err)
+	}
+
+	w.location = time.UTC
+	if w.Timezone != "" {
+		if w.location, err = time.LoadLocation(w.Timezone); err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+	}
+
+	w.days = nil
+	if len(w.Days) > 0 {
+		w.days = make(map[time.Weekday]struct{}, len(w.Days))
+		for _, d := range w.Days {
+			wd, ok := weekdays[strings.ToLower(d)]
+			if !ok {
+				return fmt.Errorf("invalid day %q", d)
+			}
+			w.days[wd] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not in the format hh:mm", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Contains returns true if t is within the window. A nil window contains all times.
+func (w *TimeWindow) Contains(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+
+	t = t.In(w.location)
+	timeOfDay := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+
+	if w.start < w.end {
+		return w.startsOn(t.Weekday()) && timeOfDay >= w.start && timeOfDay < w.end
+	}
+
+	// the window spans midnight, after midnight it belongs to the window that started the day before
+	if timeOfDay >= w.start {
+		return w.startsOn(t.Weekday())
+	}
+	if timeOfDay < w.end {
+		return w.startsOn((t.Weekday() + 6) % 7)
+	}
+	return false
+}
+
+func (w *TimeWindow) startsOn(d time.Weekday) bool {
+	if w.days == nil {
+		return true
+	}
+	_, ok := w.days[d]
+	return ok
+}
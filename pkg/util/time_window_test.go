@@ -0,0 +1,105 @@
+package util
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeWindow(t *testing.T) {
+	// 2024-01-06 is a Saturday
+	at := func(day int, hour, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		window   TimeWindow
+		active   []time.Time
+		inactive []time.Time
+	}{
+		{
+			name:     "every day",
+			window:   TimeWindow{Start: "08:00", End: "17:30"},
+			active:   []time.Time{at(6, 8, 0), at(7, 12, 0), at(8, 17, 29)},
+			inactive: []time.Time{at(6, 7, 59), at(6, 17, 30), at(6, 23, 0)},
+		},
+		{
+			name:     "weekend",
+			window:   TimeWindow{Days: []string{"Saturday", "sunday"}, Start: "08:00", End: "17:00"},
+			active:   []time.Time{at(6, 8, 0), at(7, 16, 59)},
+			inactive: []time.Time{at(5, 12, 0), at(8, 12, 0)},
+		},
+		{
+			name:     "spans midnight",
+			window:   TimeWindow{Start: "22:30", End: "04:00"},
+			active:   []time.Time{at(6, 22, 30), at(6, 23, 0), at(7, 0, 0), at(7, 3, 59)},
+			inactive: []time.Time{at(6, 4, 0), at(6, 12, 0), at(6, 22, 0)},
+		},
+		{
+			name:     "spans midnight on a day",
+			window:   TimeWindow{Days: []string{"friday"}, Start: "22:00", End: "06:00"},
+			active:   []time.Time{at(5, 22, 0), at(5, 23, 59), at(6, 0, 0), at(6, 5, 59)},
+			inactive: []time.Time{at(5, 5, 0), at(5, 21, 59), at(6, 6, 0), at(6, 22, 0)},
+		},
+		{
+			name:     "whole day",
+			window:   TimeWindow{Days: []string{"saturday"}, Start: "00:00", End: "00:00"},
+			active:   []time.Time{at(6, 0, 0), at(6, 23, 59)},
+			inactive: []time.Time{at(5, 23, 59), at(7, 0, 0)},
+		},
+		{
+			name:     "timezone",
+			window:   TimeWindow{Start: "08:00", End: "09:00", Timezone: "Etc/GMT-2"},
+			active:   []time.Time{at(6, 6, 0), at(6, 6, 59)},
+			inactive: []time.Time{at(6, 8, 0)},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, tc.window.Parse())
+
+			for _, ts := range tc.active {
+				assert.True(t, tc.window.Contains(ts), ts)
+			}
+			for _, ts := range tc.inactive {
+				assert.False(t, tc.window.Contains(ts), ts)
+			}
+		})
+	}
+
+	// a nil window contains all times
+	var w *TimeWindow
+	require.True(t, w.Contains(time.Now()))
+}
+
+func TestTimeWindowParse(t *testing.T) {
+	tests := []struct {
+		window TimeWindow
+		expErr string
+	}{
+		{
+			window: TimeWindow{Start: "8am", End: "09:00"},
+			expErr: `invalid start: "8am" is not in the format hh:mm`,
+		},
+		{
+			window: TimeWindow{Start: "08:00", End: "25:00"},
+			expErr: `invalid end: "25:00" is not in the format hh:mm`,
+		},
+		{
+			window: TimeWindow{Days: []string{"someday"}, Start: "08:00", End: "09:00"},
+			expErr: `invalid day "someday"`,
+		},
+		{
+			window: TimeWindow{Start: "08:00", End: "09:00", Timezone: "Nowhere/Nothing"},
+			expErr: "invalid timezone: unknown time zone Nowhere/Nothing",
+		},
+	}
+
+	for _, tc := range tests {
+		assert.EqualError(t, tc.window.Parse(), tc.expErr)
+	}
+}
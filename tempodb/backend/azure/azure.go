@@ -116,11 +116,20 @@ func readError(err error) error {
 
 // Write implements backend.Writer
 func (rw *Azure) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, _ int64, _ *backend.CacheInfo) error {
+	versioned := rw.versioned(keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 
 	derivedCtx, span := tracer.Start(ctx, "azure.Write")
 	defer span.End()
 
+	if versioned {
+		b, err := io.ReadAll(data)
+		if err != nil {
+			return err
+		}
+		return rw.writeVersion(derivedCtx, b, backend.ObjectFileName(keypath, name), false)
+	}
+
 	return rw.writer(derivedCtx, bufio.NewReader(data), backend.ObjectFileName(keypath, name))
 }
 
@@ -153,6 +162,11 @@ func (rw *Azure) CloseAppend(context.Context, backend.AppendTracker) error {
 }
 
 func (rw *Azure) Delete(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) error {
+	if rw.versioned(keypath) {
+		keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+		return rw.markDeleted(ctx, backend.ObjectFileName(keypath, name))
+	}
+
 	blobClient, err := getBlobClient(ctx, rw.cfg, backend.ObjectFileName(keypath, name))
 	if err != nil {
 		return fmt.Errorf("cannot get Azure blob client, name: %s: %w", backend.ObjectFileName(keypath, name), err)
@@ -256,11 +270,18 @@ func (rw *Azure) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []
 			}
 		}
 	}
+
+	// the meta of a compacted block is kept until its immutability period expired
+	if rw.cfg.ImmutableStorage.Enabled {
+		blockIDs = withoutCompacted(blockIDs, compactedBlockIDs)
+	}
+
 	return blockIDs, compactedBlockIDs, nil
 }
 
 // Find implements backend.Reader
 func (rw *Azure) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) (err error) {
+	versioned := rw.versioned(keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 
 	prefix := path.Join(keypath...)
@@ -268,6 +289,10 @@ func (rw *Azure) Find(ctx context.Context, keypath backend.KeyPath, f backend.Fi
 		prefix = prefix + dir
 	}
 
+	if versioned {
+		return rw.findVersioned(ctx, prefix, f)
+	}
+
 	pager := rw.containerClient.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
 		Prefix: &prefix,
 	})
@@ -299,12 +324,17 @@ func (rw *Azure) Find(ctx context.Context, keypath backend.KeyPath, f backend.Fi
 
 // Read implements backend.Reader
 func (rw *Azure) Read(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) (io.ReadCloser, int64, error) {
+	versioned := rw.versioned(keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 
 	derivedCtx, span := tracer.Start(ctx, "azure.Read")
 	defer span.End()
 
-	object := backend.ObjectFileName(keypath, name)
+	object, err := rw.objectName(derivedCtx, backend.ObjectFileName(keypath, name), versioned)
+	if err != nil {
+		return nil, 0, err
+	}
+
 	b, _, err := rw.readAll(derivedCtx, object)
 	if err != nil {
 		return nil, 0, readError(err)
@@ -376,12 +406,17 @@ func (rw *Azure) DeleteVersioned(ctx context.Context, name string, keypath backe
 }
 
 func (rw *Azure) ReadVersioned(ctx context.Context, name string, keypath backend.KeyPath) (io.ReadCloser, backend.Version, error) {
+	versioned := rw.versioned(keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 
 	derivedCtx, span := tracer.Start(ctx, "azure.ReadVersioned")
 	defer span.End()
 
-	object := backend.ObjectFileName(keypath, name)
+	object, err := rw.objectName(derivedCtx, backend.ObjectFileName(keypath, name), versioned)
+	if err != nil {
+		return nil, "", err
+	}
+
 	b, etag, err := rw.readAll(derivedCtx, object)
 	if err != nil {
 		return nil, "", readError(err)
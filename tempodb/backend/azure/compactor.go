@@ -3,6 +3,7 @@ package azure
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -34,6 +35,10 @@ func (rw *Azure) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
 	compactedMetaFilename := backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix)
 	ctx := context.TODO()
 
+	if rw.cfg.ImmutableStorage.Enabled {
+		return rw.markBlockCompactedImmutable(ctx, metaFilename, compactedMetaFilename)
+	}
+
 	src, _, err := rw.readAll(ctx, metaFilename)
 	if err != nil {
 		return err
@@ -48,6 +53,28 @@ func (rw *Azure) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
 	return rw.Delete(ctx, metaFilename, []string{}, nil)
 }
 
+// markBlockCompactedImmutable writes the compacted meta unless it exists already, e.g. because a previous attempt
+// failed. The meta is deleted once its immutability period expired, until then the block is listed as compacted.
+func (rw *Azure) markBlockCompactedImmutable(ctx context.Context, metaFilename, compactedMetaFilename string) error {
+	src, _, err := rw.readAll(ctx, metaFilename)
+	if err != nil {
+		return readError(err)
+	}
+
+	exists, err := rw.exists(ctx, compactedMetaFilename)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if err := rw.writeAll(ctx, compactedMetaFilename, src); err != nil {
+			return err
+		}
+	}
+
+	_, err = rw.deleteImmutable(ctx, metaFilename, time.Time{})
+	return err
+}
+
 func (rw *Azure) ClearBlock(blockID uuid.UUID, tenantID string) error {
 	var warning error
 	if len(tenantID) == 0 {
@@ -61,6 +88,9 @@ func (rw *Azure) ClearBlock(blockID uuid.UUID, tenantID string) error {
 	ctx := context.TODO()
 
 	prefix := backend.RootPath(blockID, tenantID, rw.cfg.Prefix)
+	compactedMetaFilename := backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix)
+	deferred := false
+
 	pager := rw.containerClient.NewListBlobsHierarchyPager("", &container.ListBlobsHierarchyOptions{
 		Include: container.ListBlobsInclude{},
 		Prefix:  &prefix,
@@ -78,15 +108,43 @@ func (rw *Azure) ClearBlock(blockID uuid.UUID, tenantID string) error {
 				return fmt.Errorf("unexpected empty blob name when listing %s: %w", prefix, err)
 			}
 
-			err = rw.Delete(ctx, *b.Name, []string{}, nil)
+			// the compacted meta is deleted last, the block is cleared again as long as it exists
+			if *b.Name == compactedMetaFilename {
+				continue
+			}
+
+			d, err := rw.clearBlob(ctx, *b.Name, b.Properties)
 			if err != nil {
 				warning = err
 				continue
 			}
+			deferred = deferred || d
 		}
 	}
 
-	return warning
+	if warning != nil || deferred {
+		return warning
+	}
+
+	_, err := rw.clearBlob(ctx, compactedMetaFilename, nil)
+	if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+// clearBlob deletes the blob. On immutable storage the delete is deferred until the immutability period expired.
+func (rw *Azure) clearBlob(ctx context.Context, name string, props *container.BlobProperties) (deferred bool, err error) {
+	if !rw.cfg.ImmutableStorage.Enabled {
+		return false, rw.Delete(ctx, name, []string{}, nil)
+	}
+
+	var lastModified time.Time
+	if props != nil && props.LastModified != nil {
+		lastModified = *props.LastModified
+	}
+	return rw.deleteImmutable(ctx, name, lastModified)
 }
 
 func (rw *Azure) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
@@ -22,6 +22,17 @@ type Config struct {
 	BufferSize         int            `yaml:"buffer_size"`
 	HedgeRequestsAt    time.Duration  `yaml:"hedge_requests_at"`
 	HedgeRequestsUpTo  int            `yaml:"hedge_requests_up_to"`
+
+	ImmutableStorage ImmutableStorageConfig `yaml:"immutable_storage"`
+}
+
+// ImmutableStorageConfig configures writes to containers with a time-based retention policy or a legal hold. Blobs
+// of these containers can't be overwritten and can't be deleted before their immutability period expired.
+type ImmutableStorageConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Period is the retention period of the immutability policy. Deletes of blobs that are younger are deferred.
+	// Deletes that are rejected, e.g. because of a legal hold, are deferred as well.
+	Period time.Duration `yaml:"period"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
@@ -0,0 +1,320 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blockblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/go-kit/log/level"
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/pkg/util/log"
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// Objects are versioned on containers with immutable storage. Every write of an object creates a new blob named
+// <object>.v<unix nanoseconds> and reads return the latest version. A delete writes an empty version with the
+// deleted metadata key set.
+const (
+	versionSeparator   = ".v"
+	versionDigits      = 20
+	deletedMetadataKey = "tempodeleted"
+)
+
+type objectVersion struct {
+	name         string
+	lastModified time.Time
+	deleted      bool
+}
+
+// versioned returns true if objects in the keypath are versioned. Objects of a block are written once and aren't
+// versioned. Tenant indexes, tombstones and overrides are rewritten and must be versioned.
+func (rw *Azure) versioned(keypath backend.KeyPath) bool {
+	if !rw.cfg.ImmutableStorage.Enabled {
+		return false
+	}
+
+	if len(keypath) == 2 {
+		if _, err := uuid.Parse(keypath[1]); err == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func versionName(name string, t time.Time) string {
+	return fmt.Sprintf("%s%s%0*d", name, versionSeparator, versionDigits, t.UnixNano())
+}
+
+// unversionedName returns the object name of the version or false if the name isn't a version.
+func unversionedName(name string) (string, bool) {
+	i := strings.LastIndex(name, versionSeparator)
+	if i < 0 {
+		return "", false
+	}
+
+	version := name[i+len(versionSeparator):]
+	if len(version) != versionDigits {
+		return "", false
+	}
+	if _, err := strconv.ParseUint(version, 10, 64); err != nil {
+		return "", false
+	}
+
+	return name[:i], true
+}
+
+func isDeleted(metadata map[string]*string) bool {
+	for k, v := range metadata {
+		// metadata keys are case-insensitive
+		if strings.EqualFold(k, deletedMetadataKey) && v != nil && *v == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// versions returns the versions of the object from oldest to newest.
+func (rw *Azure) versions(ctx context.Context, name string) ([]objectVersion, error) {
+	prefix := name + versionSeparator
+	pager := rw.containerClient.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Include: container.ListBlobsInclude{Metadata: true},
+		Prefix:  &prefix,
+	})
+
+	var versions []objectVersion
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("iterating versions of %s: %w", name, err)
+		}
+
+		for _, b := range page.Segment.BlobItems {
+			if b.Name == nil {
+				continue
+			}
+			if n, ok := unversionedName(*b.Name); !ok || n != name {
+				continue
+			}
+
+			v := objectVersion{
+				name:    *b.Name,
+				deleted: isDeleted(b.Metadata),
+			}
+			if b.Properties != nil && b.Properties.LastModified != nil {
+				v.lastModified = *b.Properties.LastModified
+			}
+			versions = append(versions, v)
+		}
+	}
+
+	// the versions have fixed width, so they sort by time
+	sort.Slice(versions, func(i, j int) bool { return versions[i].name < versions[j].name })
+
+	return versions, nil
+}
+
+// latestVersion returns the blob of the latest version of the object. Objects that were written before immutable
+// storage was enabled have no versions, their blob is the object itself.
+func (rw *Azure) latestVersion(ctx context.Context, name string) (string, error) {
+	versions, err := rw.versions(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if len(versions) == 0 {
+		return name, nil
+	}
+
+	latest := versions[len(versions)-1]
+	if latest.deleted {
+		return "", backend.ErrDoesNotExist
+	}
+	return latest.name, nil
+}
+
+// writeVersion writes a new version of the object and deletes the previous versions whose immutability period
+// has expired.
+func (rw *Azure) writeVersion(ctx context.Context, data []byte, name string, deleted bool) error {
+	previous, err := rw.versions(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	var metadata map[string]*string
+	if deleted {
+		v := "true"
+		metadata = map[string]*string{deletedMetadataKey: &v}
+	}
+
+	versionName := versionName(name, time.Now())
+	_, err = rw.containerClient.NewBlockBlobClient(versionName).UploadStream(ctx, bytes.NewReader(data), &blockblob.UploadStreamOptions{
+		BlockSize:   int64(rw.cfg.BufferSize),
+		Concurrency: rw.cfg.MaxBuffers,
+		Metadata:    metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot upload blob, name: %s: %w", versionName, err)
+	}
+
+	for _, v := range previous {
+		if _, err := rw.deleteImmutable(ctx, v.name, v.lastModified); err != nil {
+			level.Warn(log.Logger).Log("msg", "failed to delete previous version", "name", v.name, "err", err)
+		}
+	}
+
+	return nil
+}
+
+// deleteImmutable deletes the blob if its immutability period has expired. The delete is deferred otherwise or if
+// the blob is under a legal hold. Deferred returns true if the blob was kept, it has to be deleted again later.
+func (rw *Azure) deleteImmutable(ctx context.Context, name string, lastModified time.Time) (deferred bool, err error) {
+	if lastModified.IsZero() {
+		att, err := rw.getAttributes(ctx, name)
+		if err != nil {
+			return false, readError(err)
+		}
+		lastModified = att.LastModified
+	}
+
+	if time.Since(lastModified) < rw.cfg.ImmutableStorage.Period {
+		level.Debug(log.Logger).Log("msg", "deferring delete of immutable blob", "name", name, "lastModified", lastModified)
+		return true, nil
+	}
+
+	_, err = rw.containerClient.NewBlobClient(name).Delete(ctx, &blob.DeleteOptions{})
+	if bloberror.HasCode(err, bloberror.BlobImmutableDueToPolicy) {
+		level.Debug(log.Logger).Log("msg", "deferring delete of immutable blob", "name", name, "err", err)
+		return true, nil
+	}
+	if err != nil {
+		return false, readError(err)
+	}
+
+	return false, nil
+}
+
+// objectName returns the blob of the object. It's the latest version if the object is versioned.
+func (rw *Azure) objectName(ctx context.Context, name string, versioned bool) (string, error) {
+	if !versioned {
+		return name, nil
+	}
+	return rw.latestVersion(ctx, name)
+}
+
+// findVersioned calls f for the latest version of each object with the prefix. Objects whose latest version is a
+// delete marker are skipped.
+func (rw *Azure) findVersioned(ctx context.Context, prefix string, f backend.FindFunc) error {
+	pager := rw.containerClient.NewListBlobsFlatPager(&container.ListBlobsFlatOptions{
+		Include: container.ListBlobsInclude{Metadata: true},
+		Prefix:  &prefix,
+	})
+
+	latest := map[string]objectVersion{}
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("iterating objects: %w", err)
+		}
+
+		for _, b := range page.Segment.BlobItems {
+			if b == nil || b.Name == nil || b.Properties == nil || b.Properties.LastModified == nil {
+				continue
+			}
+
+			// objects written before immutable storage was enabled aren't versioned
+			name, ok := unversionedName(*b.Name)
+			if !ok {
+				name = *b.Name
+			}
+
+			v := objectVersion{
+				name:         *b.Name,
+				lastModified: *b.Properties.LastModified,
+				deleted:      isDeleted(b.Metadata),
+			}
+			if current, ok := latest[name]; !ok || isNewer(v, current) {
+				latest[name] = v
+			}
+		}
+	}
+
+	for name, v := range latest {
+		if v.deleted {
+			continue
+		}
+		f(backend.FindMatch{
+			Key:      strings.TrimSuffix(name, dir),
+			Modified: v.lastModified,
+		})
+	}
+
+	return nil
+}
+
+// isNewer returns true if the version a is newer than b. Versions are newer than the unversioned object.
+func isNewer(a, b objectVersion) bool {
+	_, aVersioned := unversionedName(a.name)
+	_, bVersioned := unversionedName(b.name)
+	if aVersioned != bVersioned {
+		return aVersioned
+	}
+	return a.name > b.name
+}
+
+func withoutCompacted(blockIDs, compactedBlockIDs []uuid.UUID) []uuid.UUID {
+	if len(compactedBlockIDs) == 0 {
+		return blockIDs
+	}
+
+	compacted := make(map[uuid.UUID]struct{}, len(compactedBlockIDs))
+	for _, id := range compactedBlockIDs {
+		compacted[id] = struct{}{}
+	}
+
+	live := blockIDs[:0]
+	for _, id := range blockIDs {
+		if _, ok := compacted[id]; !ok {
+			live = append(live, id)
+		}
+	}
+	return live
+}
+
+// markDeleted writes a delete marker version of the object.
+func (rw *Azure) markDeleted(ctx context.Context, name string) error {
+	latest, err := rw.latestVersion(ctx, name)
+	if err != nil {
+		return err
+	}
+	if latest == name {
+		ok, err := rw.exists(ctx, name)
+		if err != nil {
+			return readError(err)
+		}
+		if !ok {
+			return backend.ErrDoesNotExist
+		}
+	}
+
+	return rw.writeVersion(ctx, nil, name, true)
+}
+
+// exists returns true if the blob exists.
+func (rw *Azure) exists(ctx context.Context, name string) (bool, error) {
+	_, err := rw.containerClient.NewBlobClient(name).GetProperties(ctx, &blob.GetPropertiesOptions{})
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,286 @@
+package azure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/flagext"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestVersionName(t *testing.T) {
+	name := versionName("tenant/index.json.gz", time.Unix(0, 1234))
+	require.Equal(t, "tenant/index.json.gz.v00000000000000001234", name)
+
+	unversioned, ok := unversionedName(name)
+	require.True(t, ok)
+	require.Equal(t, "tenant/index.json.gz", unversioned)
+
+	for _, name := range []string{"tenant/index.json.gz", "tenant/file.v1", "tenant/file.vabcdefghijabcdefghij"} {
+		_, ok := unversionedName(name)
+		require.False(t, ok, name)
+	}
+
+	// versions sort by time
+	require.Less(t, versionName("a", time.Unix(9, 0)), versionName("a", time.Unix(10, 0)))
+}
+
+func TestImmutableStorage(t *testing.T) {
+	const (
+		tenant = "single-tenant"
+		period = time.Hour
+	)
+
+	fake := newFakeImmutableContainer(period)
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	rw, err := internalNew(&Config{
+		StorageAccountName: "testing",
+		StorageAccountKey:  flagext.SecretWithValue("YQo="),
+		MaxBuffers:         3,
+		BufferSize:         1000,
+		ContainerName:      "blerg",
+		Endpoint:           server.URL[7:], // [7:] -> strip http://,
+		ImmutableStorage: ImmutableStorageConfig{
+			Enabled: true,
+			Period:  period,
+		},
+	}, false)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	read := func(name string, keypath backend.KeyPath) (string, error) {
+		r, _, err := rw.Read(ctx, name, keypath, nil)
+		if err != nil {
+			return "", err
+		}
+		b, err := io.ReadAll(r)
+		return string(b), err
+	}
+
+	// objects outside of blocks can be overwritten
+	tenantKeyPath := backend.KeyPath{tenant}
+	for _, content := range []string{"first", "second"} {
+		require.NoError(t, rw.Write(ctx, backend.TenantIndexName, tenantKeyPath, strings.NewReader(content), -1, nil))
+	}
+	content, err := read(backend.TenantIndexName, tenantKeyPath)
+	require.NoError(t, err)
+	require.Equal(t, "second", content)
+
+	// find returns the objects, not their versions
+	var found []string
+	require.NoError(t, rw.Find(ctx, tenantKeyPath, func(m backend.FindMatch) { found = append(found, m.Key) }))
+	require.Equal(t, []string{tenant + "/" + backend.TenantIndexName}, found)
+
+	// deleted objects don't exist anymore
+	require.NoError(t, rw.Delete(ctx, backend.TenantIndexName, tenantKeyPath, nil))
+	_, err = read(backend.TenantIndexName, tenantKeyPath)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+	require.ErrorIs(t, rw.Delete(ctx, backend.TenantIndexName, tenantKeyPath, nil), backend.ErrDoesNotExist)
+
+	found = nil
+	require.NoError(t, rw.Find(ctx, tenantKeyPath, func(m backend.FindMatch) { found = append(found, m.Key) }))
+	require.Empty(t, found)
+
+	// a compacted block is listed as compacted although its meta can't be deleted yet
+	blockID := uuid.New()
+	blockKeyPath := backend.KeyPathForBlock(blockID, tenant)
+	require.NoError(t, rw.Write(ctx, "data.parquet", blockKeyPath, strings.NewReader("data"), -1, nil))
+	require.NoError(t, rw.Write(ctx, backend.MetaName, blockKeyPath, strings.NewReader("{}"), -1, nil))
+	require.NoError(t, rw.MarkBlockCompacted(blockID, tenant))
+	// marking it again doesn't overwrite the compacted meta
+	require.NoError(t, rw.MarkBlockCompacted(blockID, tenant))
+
+	blockIDs, compactedBlockIDs, err := rw.ListBlocks(ctx, tenant)
+	require.NoError(t, err)
+	require.Empty(t, blockIDs)
+	require.Equal(t, []uuid.UUID{blockID}, compactedBlockIDs)
+
+	// the block is cleared after the immutability period expired
+	require.NoError(t, rw.ClearBlock(blockID, tenant))
+	require.Len(t, fake.names(tenant+"/"+blockID.String()), 3)
+
+	fake.age(period)
+	require.NoError(t, rw.ClearBlock(blockID, tenant))
+	require.Empty(t, fake.names(tenant+"/"+blockID.String()))
+
+	// previous versions are deleted after the immutability period expired
+	require.Len(t, fake.names(tenant+"/"+backend.TenantIndexName), 3)
+	require.NoError(t, rw.Write(ctx, backend.TenantIndexName, tenantKeyPath, strings.NewReader("third"), -1, nil))
+	require.Len(t, fake.names(tenant+"/"+backend.TenantIndexName), 1)
+
+	content, err = read(backend.TenantIndexName, tenantKeyPath)
+	require.NoError(t, err)
+	require.Equal(t, "third", content)
+}
+
+type fakeBlob struct {
+	data     []byte
+	metadata map[string]string
+	modified time.Time
+}
+
+// fakeImmutableContainer is a container with a time-based retention policy. Blobs can't be overwritten and can't be
+// deleted before the period expired.
+type fakeImmutableContainer struct {
+	mtx    sync.Mutex
+	period time.Duration
+	blobs  map[string]*fakeBlob
+}
+
+func newFakeImmutableContainer(period time.Duration) *fakeImmutableContainer {
+	return &fakeImmutableContainer{
+		period: period,
+		blobs:  map[string]*fakeBlob{},
+	}
+}
+
+func (c *fakeImmutableContainer) names(prefix string) []string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	var names []string
+	for name := range c.blobs {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// age moves the modification time of all blobs back by d.
+func (c *fakeImmutableContainer) age(d time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, b := range c.blobs {
+		b.modified = b.modified.Add(-d)
+	}
+}
+
+func (c *fakeImmutableContainer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// path style urls: /<account>/<container>/<blob>
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 3)
+	if r.URL.Query().Get("comp") == "list" {
+		c.list(w, r.URL.Query().Get("prefix"))
+		return
+	}
+	if len(parts) != 3 {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	name := parts[2]
+	b, exists := c.blobs[name]
+
+	immutable := exists && time.Since(b.modified) < c.period
+	switch r.Method {
+	case http.MethodPut:
+		if immutable {
+			writeBlobError(w, http.StatusConflict, "BlobImmutableDueToPolicy")
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		b = &fakeBlob{data: data, metadata: map[string]string{}, modified: time.Now()}
+		for k, v := range r.Header {
+			if strings.HasPrefix(strings.ToLower(k), "x-ms-meta-") {
+				b.metadata[strings.ToLower(strings.TrimPrefix(strings.ToLower(k), "x-ms-meta-"))] = v[0]
+			}
+		}
+		c.blobs[name] = b
+		writeBlobHeaders(w, b)
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodHead, http.MethodGet:
+		if !exists {
+			writeBlobError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		data := b.data
+		status := http.StatusOK
+		if rng := r.Header.Get("x-ms-range"); rng != "" {
+			var start, end int
+			_, _ = fmt.Sscanf(rng, "bytes=%d-%d", &start, &end)
+			if end >= len(data) {
+				end = len(data) - 1
+			}
+			data = data[start : end+1]
+			status = http.StatusPartialContent
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(b.data)))
+		}
+		writeBlobHeaders(w, b)
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(status)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case http.MethodDelete:
+		if !exists {
+			writeBlobError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		if immutable {
+			writeBlobError(w, http.StatusConflict, "BlobImmutableDueToPolicy")
+			return
+		}
+		delete(c.blobs, name)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (c *fakeImmutableContainer) list(w http.ResponseWriter, prefix string) {
+	names := make([]string, 0, len(c.blobs))
+	for name := range c.blobs {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(`<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs>`)
+	for _, name := range names {
+		b := c.blobs[name]
+		fmt.Fprintf(buf, `<Blob><Name>%s</Name><Properties><Last-Modified>%s</Last-Modified><Content-Length>%d</Content-Length><BlobType>BlockBlob</BlobType></Properties><Metadata>`,
+			name, b.modified.UTC().Format(http.TimeFormat), len(b.data))
+		for k, v := range b.metadata {
+			fmt.Fprintf(buf, "<%s>%s</%s>", k, v, k)
+		}
+		buf.WriteString(`</Metadata></Blob>`)
+	}
+	buf.WriteString(`</Blobs><NextMarker /></EnumerationResults>`)
+
+	w.Header().Set("Content-Type", "application/xml")
+	_, _ = w.Write(buf.Bytes())
+}
+
+func writeBlobHeaders(w http.ResponseWriter, b *fakeBlob) {
+	w.Header().Set("Last-Modified", b.modified.UTC().Format(http.TimeFormat))
+	w.Header().Set("ETag", fmt.Sprintf(`"%d"`, b.modified.UnixNano()))
+	for k, v := range b.metadata {
+		w.Header().Set("x-ms-meta-"+k, v)
+	}
+}
+
+func writeBlobError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(status)
+}
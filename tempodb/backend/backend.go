@@ -16,6 +16,7 @@ const (
 	GCS   = "gcs"
 	S3    = "s3"
 	Azure = "azure"
+	Swift = "swift"
 )
 
 var (
@@ -52,6 +53,10 @@ type Writer interface {
 	CloseAppend(ctx context.Context, tracker AppendTracker) error
 	// WriteTenantIndex writes the two meta slices as a tenant index
 	WriteTenantIndex(ctx context.Context, tenantID string, meta []*BlockMeta, compactedMeta []*CompactedBlockMeta) error
+	// WriteTombstone writes a tombstone to its tenant, replacing the existing one with the same id
+	WriteTombstone(ctx context.Context, tombstone *Tombstone) error
+	// WriteRowGroupStats writes the row group stats of a tenant, replacing the existing ones
+	WriteRowGroupStats(ctx context.Context, tenantID string, stats *RowGroupStats) error
 	// Delete deletes an object.
 	Delete(ctx context.Context, name string, keypath KeyPath) error
 }
@@ -72,6 +77,10 @@ type Reader interface {
 	BlockMeta(ctx context.Context, blockID uuid.UUID, tenantID string) (*BlockMeta, error)
 	// TenantIndex returns lists of all metas given a tenant
 	TenantIndex(ctx context.Context, tenantID string) (*TenantIndex, error)
+	// Tombstones returns all tombstones of a tenant
+	Tombstones(ctx context.Context, tenantID string) ([]*Tombstone, error)
+	// RowGroupStats returns the row group stats of a tenant or ErrDoesNotExist
+	RowGroupStats(ctx context.Context, tenantID string) (*RowGroupStats, error)
 	// Find executes f for each object in the backend that matches the keypath.
 	Find(ctx context.Context, keypath KeyPath, f FindFunc) error
 	// Shutdown shuts...down?
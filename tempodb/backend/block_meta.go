@@ -31,6 +31,10 @@ const (
 
 	maxSupportedSpanColumns     = 10
 	maxSupportedResourceColumns = 10
+
+	// ProfileIDAttribute is the span attribute that profiling integrations like Pyroscope set to link a span to
+	// the profile recorded during the span.
+	ProfileIDAttribute = "pyroscope.profile.id"
 )
 
 func DedicatedColumnTypeFromTempopb(t tempopb.DedicatedColumn_Type) (DedicatedColumnType, error) {
@@ -261,6 +265,24 @@ func (dcs DedicatedColumns) Validate() error {
 	return nil
 }
 
+// WithProfileID returns the dedicated columns with an additional span column for the profile ID attribute. The
+// dedicated columns are returned unchanged if they already contain the profile ID attribute.
+func (dcs DedicatedColumns) WithProfileID() DedicatedColumns {
+	for _, dc := range dcs {
+		if dc.Scope == DedicatedColumnScopeSpan && dc.Name == ProfileIDAttribute {
+			return dcs
+		}
+	}
+
+	cols := make(DedicatedColumns, 0, len(dcs)+1)
+	cols = append(cols, dcs...)
+	return append(cols, DedicatedColumn{
+		Scope: DedicatedColumnScopeSpan,
+		Name:  ProfileIDAttribute,
+		Type:  DedicatedColumnTypeString,
+	})
+}
+
 func (dc *DedicatedColumn) Validate() error {
 	if dc.Name == "" {
 		return errors.New("dedicated column invalid: name must not be empty")
@@ -254,3 +254,19 @@ func TestDedicatedColumns_ToTempopb(t *testing.T) {
 		})
 	}
 }
+
+func TestDedicatedColumns_WithProfileID(t *testing.T) {
+	profileID := DedicatedColumn{Scope: DedicatedColumnScopeSpan, Name: ProfileIDAttribute, Type: DedicatedColumnTypeString}
+
+	cols := DedicatedColumns{
+		{Scope: DedicatedColumnScopeResource, Name: ProfileIDAttribute, Type: DedicatedColumnTypeString},
+	}
+	assert.Equal(t, append(DedicatedColumns{cols[0]}, profileID), cols.WithProfileID())
+	// the columns aren't modified
+	assert.Len(t, cols, 1)
+
+	cols = DedicatedColumns{profileID}
+	assert.Equal(t, cols, cols.WithProfileID())
+
+	assert.Equal(t, DedicatedColumns{profileID}, DedicatedColumns(nil).WithProfileID())
+}
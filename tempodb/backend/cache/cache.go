@@ -35,6 +35,7 @@ type readerWriter struct {
 	offsetIdxCache  cache.Cache
 	traceIDIdxCache cache.Cache
 	pageCache       cache.Cache
+	tagValuesCache  cache.Cache
 }
 
 func NewCache(cfgBloom *BloomConfig, nextReader backend.RawReader, nextWriter backend.RawWriter, cacheProvider cache.Provider, logger log.Logger) (backend.RawReader, backend.RawWriter, error) {
@@ -47,6 +48,7 @@ func NewCache(cfgBloom *BloomConfig, nextReader backend.RawReader, nextWriter ba
 		columnIdxCache:  cacheProvider.CacheFor(cache.RoleParquetColumnIdx),
 		traceIDIdxCache: cacheProvider.CacheFor(cache.RoleTraceIDIdx),
 		pageCache:       cacheProvider.CacheFor(cache.RoleParquetPage),
+		tagValuesCache:  cacheProvider.CacheFor(cache.RoleTagValuesIndex),
 
 		nextReader: nextReader,
 		nextWriter: nextWriter,
@@ -59,6 +61,7 @@ func NewCache(cfgBloom *BloomConfig, nextReader backend.RawReader, nextWriter ba
 		cache.RoleParquetColumnIdx, rw.columnIdxCache != nil,
 		cache.RoleTraceIDIdx, rw.traceIDIdxCache != nil,
 		cache.RoleParquetPage, rw.pageCache != nil,
+		cache.RoleTagValuesIndex, rw.tagValuesCache != nil,
 	)
 
 	return rw, rw, nil
@@ -191,6 +194,8 @@ func (r *readerWriter) cacheFor(cacheInfo *backend.CacheInfo) cache.Cache {
 		return r.pageCache
 	case cache.RoleTraceIDIdx:
 		return r.traceIDIdxCache
+	case cache.RoleTagValuesIndex:
+		return r.tagValuesCache
 	case cache.RoleBloom:
 		// if there is no bloom cfg then there are no restrictions on bloom filter caching
 		if r.cfgBloom == nil {
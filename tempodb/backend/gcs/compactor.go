@@ -25,8 +25,12 @@ func (rw *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) e
 		storage.WithPolicy(storage.RetryAlways),
 	)
 
+	// copies are encrypted with the default key of the bucket unless a key is passed
+	copier := dst.CopierFrom(src)
+	copier.DestinationKMSKeyName = rw.kmsKeyName(tenantID)
+
 	ctx := context.TODO()
-	_, err := dst.CopierFrom(src).Run(ctx)
+	_, err := copier.Run(ctx)
 	if err != nil {
 		return err
 	}
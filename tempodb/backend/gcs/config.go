@@ -18,14 +18,25 @@ type Config struct {
 	ObjectCacheControl    string            `yaml:"object_cache_control"`
 	ObjectMetadata        map[string]string `yaml:"object_metadata"`
 	ListBlocksConcurrency int               `yaml:"list_blocks_concurrency"`
+	// UseGRPC uses the GCS gRPC API instead of the JSON/XML HTTP APIs. Requests are not hedged.
+	UseGRPC bool `yaml:"use_grpc"`
+	// GRPCConnPoolSize is the number of gRPC connections requests are spread across.
+	GRPCConnPoolSize int `yaml:"grpc_conn_pool_size"`
+	// KMSKeyName is the Cloud KMS key uploaded objects are encrypted with. Leave empty to use the bucket default.
+	KMSKeyName string `yaml:"kms_key_name"`
+	// KMSKeyNameForTenant returns the Cloud KMS key to encrypt the objects of a tenant with, or an empty string to use
+	// KMSKeyName. It can only be set in code.
+	KMSKeyNameForTenant func(tenant string) string `yaml:"-"`
 }
 
 func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
 	f.StringVar(&cfg.BucketName, util.PrefixConfig(prefix, "gcs.bucket"), "", "gcs bucket to store traces in.")
 	f.StringVar(&cfg.Prefix, util.PrefixConfig(prefix, "gcs.prefix"), "", "gcs bucket prefix to store traces in.")
 	f.IntVar(&cfg.ListBlocksConcurrency, util.PrefixConfig(prefix, "gcs.list_blocks_concurrency"), 3, "number of concurrent list calls to make to backend")
+	f.StringVar(&cfg.KMSKeyName, util.PrefixConfig(prefix, "gcs.kms_key_name"), "", "Cloud KMS key to encrypt uploaded objects with. Leave empty to use the bucket default.")
 	cfg.ChunkBufferSize = 10 * 1024 * 1024
 	cfg.HedgeRequestsUpTo = 2
+	cfg.GRPCConnPoolSize = 8
 }
 
 func (cfg *Config) PathMatches(other *Config) bool {
@@ -26,6 +26,8 @@ import (
 	"google.golang.org/api/iterator"
 	"google.golang.org/api/option"
 	google_http "google.golang.org/api/transport/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 
 	"github.com/grafana/tempo/pkg/blockboundary"
 	tempo_io "github.com/grafana/tempo/pkg/io"
@@ -87,9 +89,13 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 		return nil, fmt.Errorf("creating bucket: %w", err)
 	}
 
-	hedgedBucket, err := createBucket(ctx, cfg, true)
-	if err != nil {
-		return nil, fmt.Errorf("creating hedged bucket: %w", err)
+	// requests over grpc are not hedged so both buckets can share the connection pool
+	hedgedBucket := bucket
+	if !cfg.UseGRPC {
+		hedgedBucket, err = createBucket(ctx, cfg, true)
+		if err != nil {
+			return nil, fmt.Errorf("creating hedged bucket: %w", err)
+		}
 	}
 
 	// Check bucket exists by getting attrs
@@ -110,13 +116,14 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 
 // Write implements backend.Writer
 func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, _ int64, _ *backend.CacheInfo) error {
+	tenant := backend.TenantFromKeyPath(keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	derivedCtx, span := tracer.Start(ctx, "gcs.Write")
 	defer span.End()
 
 	span.SetAttributes(attribute.String("object", name))
 
-	w := rw.writer(derivedCtx, backend.ObjectFileName(keypath, name), nil)
+	w := rw.writer(derivedCtx, backend.ObjectFileName(keypath, name), tenant, nil)
 
 	_, err := io.Copy(w, data)
 	if err != nil {
@@ -130,6 +137,7 @@ func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.
 
 // Append implements backend.Writer
 func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	tenant := backend.TenantFromKeyPath(keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	ctx, span := tracer.Start(ctx, "gcs.Append", trace.WithAttributes(
 		attribute.Int("len", len(buffer)),
@@ -138,7 +146,7 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 
 	var w *storage.Writer
 	if tracker == nil {
-		w = rw.writer(ctx, backend.ObjectFileName(keypath, name), nil)
+		w = rw.writer(ctx, backend.ObjectFileName(keypath, name), tenant, nil)
 	} else {
 		w = tracker.(*storage.Writer)
 	}
@@ -382,6 +390,7 @@ func (rw *readerWriter) Shutdown() {
 }
 
 func (rw *readerWriter) WriteVersioned(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, version backend.Version) (backend.Version, error) {
+	tenant := backend.TenantFromKeyPath(keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	derivedCtx, span := tracer.Start(ctx, "gcs.WriteVersioned", trace.WithAttributes(
 		attribute.String("object", name),
@@ -393,7 +402,7 @@ func (rw *readerWriter) WriteVersioned(ctx context.Context, name string, keypath
 		return "", err
 	}
 
-	w := rw.writer(derivedCtx, backend.ObjectFileName(keypath, name), &preconditions)
+	w := rw.writer(derivedCtx, backend.ObjectFileName(keypath, name), tenant, &preconditions)
 
 	_, err = io.Copy(w, data)
 	if err != nil {
@@ -441,7 +450,7 @@ func toVersion(generation int64) backend.Version {
 	return backend.Version(fmt.Sprint(generation))
 }
 
-func (rw *readerWriter) writer(ctx context.Context, name string, conditions *storage.Conditions) *storage.Writer {
+func (rw *readerWriter) writer(ctx context.Context, name, tenant string, conditions *storage.Conditions) *storage.Writer {
 	o := rw.bucket.Object(name)
 	if conditions != nil {
 		o = o.If(*conditions)
@@ -457,9 +466,22 @@ func (rw *readerWriter) writer(ctx context.Context, name string, conditions *sto
 		w.CacheControl = rw.cfg.ObjectCacheControl
 	}
 
+	w.KMSKeyName = rw.kmsKeyName(tenant)
+
 	return w
 }
 
+// kmsKeyName returns the Cloud KMS key to encrypt the objects of the tenant with. A key of the tenant overrides the
+// configured key. An empty name uses the default encryption of the bucket.
+func (rw *readerWriter) kmsKeyName(tenant string) string {
+	if tenant != "" && rw.cfg.KMSKeyNameForTenant != nil {
+		if name := rw.cfg.KMSKeyNameForTenant(tenant); name != "" {
+			return name
+		}
+	}
+	return rw.cfg.KMSKeyName
+}
+
 func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, *storage.ReaderObjectAttrs, error) {
 	r, err := rw.hedgedBucket.Object(name).NewReader(ctx)
 	if err != nil {
@@ -496,6 +518,10 @@ func (rw *readerWriter) readRange(ctx context.Context, name string, offset int64
 }
 
 func createBucket(ctx context.Context, cfg *Config, hedge bool) (*storage.BucketHandle, error) {
+	if cfg.UseGRPC {
+		return createGRPCBucket(ctx, cfg)
+	}
+
 	// start with default transport
 	customTransport := http.DefaultTransport.(*http.Transport).Clone()
 
@@ -545,6 +571,32 @@ func createBucket(ctx context.Context, cfg *Config, hedge bool) (*storage.Bucket
 	return client.Bucket(cfg.BucketName), nil
 }
 
+// createGRPCBucket creates a bucket that uses the GCS gRPC API. Queries issue many small parallel
+// range reads, so they are spread across a pool of connections rather than multiplexed over one.
+func createGRPCBucket(ctx context.Context, cfg *Config) (*storage.BucketHandle, error) {
+	storageClientOptions := []option.ClientOption{
+		option.WithScopes(storage.ScopeReadWrite),
+		option.WithGRPCConnectionPool(cfg.GRPCConnPoolSize),
+		option.WithGRPCDialOption(grpc.WithChainUnaryInterceptor(instrumentation.UnaryClientInterceptor())),
+		option.WithGRPCDialOption(grpc.WithChainStreamInterceptor(instrumentation.StreamClientInterceptor())),
+	}
+	if cfg.Insecure {
+		storageClientOptions = append(storageClientOptions,
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		)
+	}
+	if cfg.Endpoint != "" {
+		storageClientOptions = append(storageClientOptions, option.WithEndpoint(cfg.Endpoint))
+	}
+	client, err := storage.NewGRPCClient(ctx, storageClientOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("creating grpc storage client: %w", err)
+	}
+
+	return client.Bucket(cfg.BucketName), nil
+}
+
 func readError(err error) error {
 	if errors.Is(err, storage.ErrObjectNotExist) {
 		return backend.ErrDoesNotExist
@@ -178,6 +178,54 @@ func TestRetry_MarkBlockCompacted(t *testing.T) {
 	require.Equal(t, int32(2), atomic.LoadInt32(&count))
 }
 
+func TestObjectKMSKeyName(t *testing.T) {
+	var uploads, copies []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/upload/storage/v1/b/blerg/o"):
+			uploads = append(uploads, r.URL.Query().Get("kmsKeyName"))
+			_, _ = io.Copy(io.Discard, r.Body)
+		case strings.Contains(r.URL.Path, "/rewriteTo/"):
+			copies = append(copies, r.URL.Query().Get("destinationKmsKeyName"))
+			_, _ = w.Write([]byte(`{"done": true}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	_, w, c, err := New(&Config{
+		BucketName: "blerg",
+		Endpoint:   server.URL,
+		Insecure:   true,
+		KMSKeyName: "bucket-key",
+		KMSKeyNameForTenant: func(tenant string) string {
+			if tenant == "tenant-with-key" {
+				return "tenant-key"
+			}
+			return ""
+		},
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	data := []byte("data")
+	for _, tenant := range []string{"tenant", "tenant-with-key"} {
+		require.NoError(t, w.Write(ctx, "object", backend.KeyPath{tenant}, bytes.NewReader(data), int64(len(data)), nil))
+
+		tracker, err := w.Append(ctx, "object", backend.KeyPath{tenant}, nil, data)
+		require.NoError(t, err)
+		require.NoError(t, w.CloseAppend(ctx, tracker))
+
+		require.NoError(t, c.MarkBlockCompacted(uuid.New(), tenant))
+	}
+
+	// the key of a tenant overrides the configured key
+	require.Equal(t, []string{"bucket-key", "bucket-key", "tenant-key", "tenant-key"}, uploads)
+	require.Equal(t, []string{"bucket-key", "tenant-key"}, copies)
+}
+
 func fakeServer(t *testing.T, returnIn time.Duration, counter *int32) *httptest.Server {
 	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(returnIn)
@@ -419,3 +467,18 @@ func testServer(t *testing.T, httpHandler http.HandlerFunc) *httptest.Server {
 	t.Cleanup(server.Close)
 	return server
 }
+
+func TestGRPCSharesBucket(t *testing.T) {
+	rw, err := internalNew(&Config{
+		BucketName:       "blerg",
+		Endpoint:         "localhost:0",
+		Insecure:         true,
+		UseGRPC:          true,
+		GRPCConnPoolSize: 2,
+		HedgeRequestsAt:  time.Second,
+	}, false)
+	require.NoError(t, err)
+
+	// grpc requests are not hedged
+	require.Same(t, rw.bucket, rw.hedgedBucket)
+}
@@ -45,7 +45,7 @@ func (rw *Backend) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*back
 		return nil, readError(err)
 	}
 
-	bytes, err := os.ReadFile(filename)
+	bytes, err := rw.readFile(filename)
 	if err != nil {
 		return nil, readError(err)
 	}
@@ -0,0 +1,196 @@
+package local
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	encryptionMagic    = "TLE1"
+	encryptionKeyLen   = 32
+	encryptionKeyIDLen = 8
+	// the header of an encrypted object is the magic, the id of the key and the iv
+	encryptionHeaderLen = len(encryptionMagic) + encryptionKeyIDLen + aes.BlockSize
+)
+
+// ErrUnknownEncryptionKey is returned when reading an object that isn't encrypted with the key of the backend.
+var ErrUnknownEncryptionKey = errors.New("object is not encrypted with the configured key")
+
+// encryption encrypts objects with AES-256 in CTR mode, which allows decrypting any range of an object. Every object
+// has a random iv that is stored in its header together with the id of the key.
+type encryption struct {
+	block cipher.Block
+	keyID [encryptionKeyIDLen]byte
+}
+
+// newEncryption returns an encryption with the given AES-256 key. The id of the key is derived from the key, so
+// objects written with the same key can be read after a restart.
+func newEncryption(key []byte) (*encryption, error) {
+	if len(key) != encryptionKeyLen {
+		return nil, fmt.Errorf("encryption key must be %d bytes, got %d", encryptionKeyLen, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &encryption{block: block}
+	sum := sha256.Sum256(key)
+	copy(e.keyID[:], sum[:])
+
+	return e, nil
+}
+
+// loadOrCreateEncryptionKey reads the hex encoded key in path. If the file doesn't exist, a random key is generated
+// and written to it.
+func loadOrCreateEncryptionKey(path string) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(b)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode encryption key %s: %w", path, err)
+		}
+		return key, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read encryption key %s: %w", path, err)
+	}
+
+	key := make([]byte, encryptionKeyLen)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+
+	// write to a temporary file first so a crash never leaves a partial key behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(hex.EncodeToString(key)), 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write encryption key %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("failed to write encryption key %s: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// writeHeader writes the header of a new object to w and returns the stream to encrypt the object with.
+func (e *encryption) writeHeader(w io.Writer) (cipher.Stream, error) {
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, encryptionHeaderLen)
+	header = append(header, encryptionMagic...)
+	header = append(header, e.keyID[:]...)
+	header = append(header, iv...)
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return cipher.NewCTR(e.block, iv), nil
+}
+
+// readHeader reads the header of an object and returns its iv.
+func (e *encryption) readHeader(r io.ReaderAt) ([]byte, error) {
+	header := make([]byte, encryptionHeaderLen)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, ErrUnknownEncryptionKey
+		}
+		return nil, err
+	}
+
+	if string(header[:len(encryptionMagic)]) != encryptionMagic || string(header[len(encryptionMagic):len(encryptionMagic)+encryptionKeyIDLen]) != string(e.keyID[:]) {
+		return nil, ErrUnknownEncryptionKey
+	}
+
+	return header[len(encryptionMagic)+encryptionKeyIDLen:], nil
+}
+
+// streamAt returns the stream to decrypt an object with iv starting at offset.
+func (e *encryption) streamAt(iv []byte, offset uint64) cipher.Stream {
+	// the counter of the block containing offset is the iv incremented by the number of preceding blocks
+	counter := make([]byte, aes.BlockSize)
+	blocks := offset / aes.BlockSize
+	lo := binary.BigEndian.Uint64(iv[8:])
+	hi := binary.BigEndian.Uint64(iv[:8])
+	if lo+blocks < lo {
+		hi++
+	}
+	binary.BigEndian.PutUint64(counter[:8], hi)
+	binary.BigEndian.PutUint64(counter[8:], lo+blocks)
+
+	stream := cipher.NewCTR(e.block, counter)
+
+	// discard the key stream before offset in the block
+	skip := make([]byte, offset%aes.BlockSize)
+	stream.XORKeyStream(skip, skip)
+
+	return stream
+}
+
+// encryptedAppend is the append tracker of an encrypted object.
+type encryptedAppend struct {
+	f *os.File
+	w io.Writer
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// openDecrypted returns a reader of the decrypted object in f and its size. f is closed with the reader.
+func (e *encryption) openDecrypted(f *os.File, size int64) (io.ReadCloser, int64, error) {
+	iv, err := e.readHeader(f)
+	if err != nil {
+		return nil, -1, err
+	}
+
+	if _, err := f.Seek(int64(encryptionHeaderLen), io.SeekStart); err != nil {
+		return nil, -1, err
+	}
+
+	return readCloser{
+		Reader: cipher.StreamReader{S: cipher.NewCTR(e.block, iv), R: f},
+		Closer: f,
+	}, size - int64(encryptionHeaderLen), nil
+}
+
+// readFile reads the whole object in filename.
+func (rw *Backend) readFile(filename string) ([]byte, error) {
+	if rw.enc == nil {
+		return os.ReadFile(filename)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	r, _, err := rw.enc.openDecrypted(f, stat.Size())
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
@@ -2,6 +2,8 @@ package local
 
 import (
 	"context"
+	"crypto/cipher"
+	"errors"
 	"io"
 	"io/fs"
 	"os"
@@ -18,6 +20,7 @@ import (
 
 type Backend struct {
 	cfg *Config
+	enc *encryption
 }
 
 var tracer = otel.Tracer("tempodb/backend/local")
@@ -42,6 +45,28 @@ func NewBackend(cfg *Config) (*Backend, error) {
 	return l, nil
 }
 
+// NewEncryptedBackend returns a backend that encrypts all objects with the hex encoded AES-256 key in keyFile. If
+// keyFile doesn't exist, a random key is generated and persisted to it. Objects can only be read with the key they
+// were written with.
+func NewEncryptedBackend(cfg *Config, keyFile string) (*Backend, error) {
+	l, err := NewBackend(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := loadOrCreateEncryptionKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	l.enc, err = newEncryption(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
 func New(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
 	l, err := NewBackend(cfg)
 	return l, l, l, err
@@ -66,7 +91,16 @@ func (rw *Backend) Write(ctx context.Context, name string, keypath backend.KeyPa
 	}
 	defer dst.Close()
 
-	_, err = io.Copy(dst, data)
+	var w io.Writer = dst
+	if rw.enc != nil {
+		stream, err := rw.enc.writeHeader(dst)
+		if err != nil {
+			return err
+		}
+		w = cipher.StreamWriter{S: stream, W: dst}
+	}
+
+	_, err = io.Copy(w, data)
 	if err != nil {
 		return err
 	}
@@ -84,6 +118,10 @@ func (rw *Backend) Append(ctx context.Context, name string, keypath backend.KeyP
 	))
 	defer span.End()
 
+	if rw.enc != nil {
+		return rw.appendEncrypted(name, keypath, tracker, buffer)
+	}
+
 	var dst *os.File
 	if tracker == nil {
 		blockFolder := rw.rootPath(keypath)
@@ -109,6 +147,38 @@ func (rw *Backend) Append(ctx context.Context, name string, keypath backend.KeyP
 	return dst, nil
 }
 
+func (rw *Backend) appendEncrypted(name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	var dst *encryptedAppend
+	if tracker == nil {
+		blockFolder := rw.rootPath(keypath)
+		err := os.MkdirAll(blockFolder, 0o700)
+		if err != nil {
+			return nil, err
+		}
+
+		f, err := os.Create(rw.objectFileName(keypath, name))
+		if err != nil {
+			return nil, err
+		}
+
+		stream, err := rw.enc.writeHeader(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		dst = &encryptedAppend{f: f, w: cipher.StreamWriter{S: stream, W: f}}
+	} else {
+		dst = tracker.(*encryptedAppend)
+	}
+
+	_, err := dst.w.Write(buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
 // CloseAppend implements backend.Writer
 func (rw *Backend) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
 	if err := ctx.Err(); err != nil {
@@ -119,6 +189,10 @@ func (rw *Backend) CloseAppend(ctx context.Context, tracker backend.AppendTracke
 		return nil
 	}
 
+	if dst, ok := tracker.(*encryptedAppend); ok {
+		return dst.f.Close()
+	}
+
 	var dst *os.File = tracker.(*os.File)
 	return dst.Close()
 }
@@ -158,6 +232,12 @@ func (rw *Backend) List(ctx context.Context, keypath backend.KeyPath) ([]string,
 // ListBlocks implements backend.Reader
 func (rw *Backend) ListBlocks(_ context.Context, tenant string) (metas []uuid.UUID, compactedMetas []uuid.UUID, err error) {
 	rootPath := rw.rootPath(backend.KeyPath{tenant})
+
+	// a tenant without blocks has no directory, like an empty prefix in object storage
+	if _, err := os.Stat(rootPath); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+
 	fff := os.DirFS(rootPath)
 	err = fs.WalkDir(fff, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -197,6 +277,12 @@ func (rw *Backend) ListBlocks(_ context.Context, tenant string) (metas []uuid.UU
 // Find implements backend.Reader
 func (rw *Backend) Find(_ context.Context, keypath backend.KeyPath, f backend.FindFunc) (err error) {
 	path := rw.rootPath(keypath)
+
+	// nothing to find in a directory that doesn't exist, like an empty prefix in object storage
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
 	fff := os.DirFS(path)
 	err = fs.WalkDir(fff, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
@@ -245,6 +331,15 @@ func (rw *Backend) Read(ctx context.Context, name string, keypath backend.KeyPat
 		return nil, -1, err
 	}
 
+	if rw.enc != nil {
+		r, size, err := rw.enc.openDecrypted(f, stat.Size())
+		if err != nil {
+			f.Close()
+			return nil, -1, err
+		}
+		return r, size, nil
+	}
+
 	return f, stat.Size(), err
 }
 
@@ -268,6 +363,21 @@ func (rw *Backend) ReadRange(ctx context.Context, name string, keypath backend.K
 	}
 	defer f.Close()
 
+	if rw.enc != nil {
+		iv, err := rw.enc.readHeader(f)
+		if err != nil {
+			return err
+		}
+
+		_, err = f.ReadAt(buffer, int64(offset)+int64(encryptionHeaderLen))
+		if err != nil {
+			return err
+		}
+
+		rw.enc.streamAt(iv, offset).XORKeyStream(buffer, buffer)
+		return nil
+	}
+
 	_, err = f.ReadAt(buffer, int64(offset))
 	if err != nil {
 		return err
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -156,3 +158,116 @@ func blockExists(t *testing.T, blockID backend.UUID, tenant string, r backend.Ra
 	require.Len(t, blocks, 1)
 	require.Equal(t, blockID.String(), blocks[0])
 }
+
+func TestEncryptedReadWrite(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "key")
+	l, err := NewEncryptedBackend(&Config{
+		Path: dir,
+	}, keyFile)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	keypath := backend.KeyPathForBlock(uuid.New(), "fake")
+
+	object := make([]byte, 100)
+	_, err = crand.Read(object)
+	require.NoError(t, err)
+
+	err = l.Write(ctx, objectName, keypath, bytes.NewReader(object), int64(len(object)), nil)
+	require.NoError(t, err)
+
+	var tracker backend.AppendTracker
+	for i := 0; i < len(object); i += 30 {
+		tracker, err = l.Append(ctx, "appended", keypath, tracker, object[i:min(i+30, len(object))])
+		require.NoError(t, err)
+	}
+	require.NoError(t, l.CloseAppend(ctx, tracker))
+
+	for _, name := range []string{objectName, "appended"} {
+		// the object is not stored in plaintext
+		raw, err := os.ReadFile(l.objectFileName(keypath, name))
+		require.NoError(t, err)
+		require.Len(t, raw, len(object)+encryptionHeaderLen)
+		require.False(t, bytes.Contains(raw, object[:20]))
+
+		r, size, err := l.Read(ctx, name, keypath, nil)
+		require.NoError(t, err)
+		actual, err := io.ReadAllWithEstimate(r, size)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		require.Equal(t, object, actual)
+
+		// ranges that don't start or end at a cipher block boundary
+		for _, offset := range []uint64{0, 5, 16, 33, 99} {
+			buffer := make([]byte, min(20, uint64(len(object))-offset))
+			err = l.ReadRange(ctx, name, keypath, offset, buffer, nil)
+			require.NoError(t, err)
+			require.Equal(t, object[offset:offset+uint64(len(buffer))], buffer)
+		}
+	}
+
+	// objects can be read after a restart with the persisted key
+	restarted, err := NewEncryptedBackend(&Config{
+		Path: dir,
+	}, keyFile)
+	require.NoError(t, err)
+
+	r, size, err := restarted.Read(ctx, objectName, keypath, nil)
+	require.NoError(t, err)
+	actual, err := io.ReadAllWithEstimate(r, size)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	require.Equal(t, object, actual)
+
+	// objects can't be read with another key
+	other, err := NewEncryptedBackend(&Config{
+		Path: dir,
+	}, filepath.Join(t.TempDir(), "key"))
+	require.NoError(t, err)
+
+	_, _, err = other.Read(ctx, objectName, keypath, nil)
+	require.ErrorIs(t, err, ErrUnknownEncryptionKey)
+	err = other.ReadRange(ctx, objectName, keypath, 0, make([]byte, 10), nil)
+	require.ErrorIs(t, err, ErrUnknownEncryptionKey)
+}
+
+func TestEncryptionStreamAtCounterOverflow(t *testing.T) {
+	enc, err := newEncryption(bytes.Repeat([]byte{1}, encryptionKeyLen))
+	require.NoError(t, err)
+
+	// the low half of the counter overflows after the first block
+	iv := bytes.Repeat([]byte{0xff}, 16)
+	iv[0] = 0
+
+	expected := make([]byte, 48)
+	enc.streamAt(iv, 0).XORKeyStream(expected, expected)
+
+	actual := make([]byte, 20)
+	enc.streamAt(iv, 28).XORKeyStream(actual, actual)
+	require.Equal(t, expected[28:], actual)
+}
+
+func TestLoadOrCreateEncryptionKey(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+
+	// a key is generated and persisted on first use
+	key, err := loadOrCreateEncryptionKey(keyFile)
+	require.NoError(t, err)
+	require.Len(t, key, encryptionKeyLen)
+
+	loaded, err := loadOrCreateEncryptionKey(keyFile)
+	require.NoError(t, err)
+	require.Equal(t, key, loaded)
+
+	// a configured key is loaded
+	require.NoError(t, os.WriteFile(keyFile, []byte(strings.Repeat("ab", encryptionKeyLen)+"\n"), 0o600))
+	loaded, err = loadOrCreateEncryptionKey(keyFile)
+	require.NoError(t, err)
+	require.Equal(t, bytes.Repeat([]byte{0xab}, encryptionKeyLen), loaded)
+
+	// keys of the wrong size are rejected
+	require.NoError(t, os.WriteFile(keyFile, []byte("abcd"), 0o600))
+	_, err = NewEncryptedBackend(&Config{Path: t.TempDir()}, keyFile)
+	require.EqualError(t, err, "encryption key must be 32 bytes, got 2")
+}
@@ -228,6 +228,14 @@ func (m *MockReader) TenantIndex(ctx context.Context, tenantID string) (*TenantI
 	return &TenantIndex{}, nil
 }
 
+func (m *MockReader) Tombstones(context.Context, string) ([]*Tombstone, error) {
+	return nil, nil
+}
+
+func (m *MockReader) RowGroupStats(context.Context, string) (*RowGroupStats, error) {
+	return nil, ErrDoesNotExist
+}
+
 func (m *MockReader) Shutdown() {}
 
 // MockWriter
@@ -276,6 +284,14 @@ func (m *MockWriter) WriteTenantIndex(_ context.Context, tenantID string, meta [
 	return nil
 }
 
+func (m *MockWriter) WriteTombstone(context.Context, *Tombstone) error {
+	return nil
+}
+
+func (m *MockWriter) WriteRowGroupStats(context.Context, string, *RowGroupStats) error {
+	return nil
+}
+
 type MockBlocklist struct {
 	MetasFn          func(tenantID string) []*BlockMeta
 	CompactedMetasFn func(tenantID string) []*CompactedBlockMeta
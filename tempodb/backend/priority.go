@@ -0,0 +1,44 @@
+package backend
+
+import "context"
+
+// IOPriority classifies backend requests so that background work can be throttled in favor of
+// interactive queries.
+type IOPriority int
+
+const (
+	// IOPriorityInteractive is used for requests made on behalf of queries. Requests without a
+	// priority are interactive.
+	IOPriorityInteractive IOPriority = iota
+	// IOPriorityCompaction is used for requests made by the compactor.
+	IOPriorityCompaction
+	// IOPriorityPolling is used for requests made while polling the blocklist.
+	IOPriorityPolling
+)
+
+func (p IOPriority) String() string {
+	switch p {
+	case IOPriorityInteractive:
+		return "interactive"
+	case IOPriorityCompaction:
+		return "compaction"
+	case IOPriorityPolling:
+		return "polling"
+	}
+	return "unknown"
+}
+
+type ioPriorityKey struct{}
+
+// WithIOPriority returns a context that tags backend requests made with it with the given priority.
+func WithIOPriority(ctx context.Context, p IOPriority) context.Context {
+	return context.WithValue(ctx, ioPriorityKey{}, p)
+}
+
+// IOPriorityFromContext returns the priority of the context or IOPriorityInteractive if it has none.
+func IOPriorityFromContext(ctx context.Context) IOPriority {
+	if p, ok := ctx.Value(ioPriorityKey{}).(IOPriority); ok {
+		return p
+	}
+	return IOPriorityInteractive
+}
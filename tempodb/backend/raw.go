@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"path"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -163,6 +164,26 @@ func (w *writer) WriteTenantIndex(ctx context.Context, tenantID string, meta []*
 	return w.w.Write(ctx, TenantIndexName, KeyPath([]string{tenantID}), bytes.NewReader(indexBytesJSON), int64(len(indexBytesJSON)), nil)
 }
 
+// WriteTombstone implements backend.Writer
+func (w *writer) WriteTombstone(ctx context.Context, tombstone *Tombstone) error {
+	b, err := json.Marshal(tombstone)
+	if err != nil {
+		return err
+	}
+
+	return w.w.Write(ctx, TombstoneFileName(tombstone.ID), KeyPathForTombstones(tombstone.TenantID), bytes.NewReader(b), int64(len(b)), nil)
+}
+
+// WriteRowGroupStats implements backend.Writer
+func (w *writer) WriteRowGroupStats(ctx context.Context, tenantID string, stats *RowGroupStats) error {
+	b, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+
+	return w.w.Write(ctx, RowGroupStatsName, KeyPath([]string{tenantID}), bytes.NewReader(b), int64(len(b)), nil)
+}
+
 // Delete implements backend.Writer
 func (w *writer) Delete(ctx context.Context, name string, keypath KeyPath) error {
 	return w.w.Delete(ctx, name, keypath, nil)
@@ -298,6 +319,69 @@ func (r *reader) tenantIndexProto(ctx context.Context, tenantID string) (*Tenant
 	return out, nil
 }
 
+// RowGroupStats implements backend.Reader
+func (r *reader) RowGroupStats(ctx context.Context, tenantID string) (*RowGroupStats, error) {
+	reader, size, err := r.r.Read(ctx, RowGroupStatsName, KeyPath([]string{tenantID}), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	bytes, err := tempo_io.ReadAllWithEstimate(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &RowGroupStats{}
+	if err := json.Unmarshal(bytes, stats); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal row group stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Tombstones implements backend.Reader
+func (r *reader) Tombstones(ctx context.Context, tenantID string) ([]*Tombstone, error) {
+	var ids []string
+	err := r.r.Find(ctx, KeyPathForTombstones(tenantID), func(match FindMatch) {
+		if id, ok := tombstoneIDFromKey(match.Key); ok {
+			ids = append(ids, id)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tombstones := make([]*Tombstone, 0, len(ids))
+	for _, id := range ids {
+		reader, size, err := r.r.Read(ctx, TombstoneFileName(id), KeyPathForTombstones(tenantID), nil)
+		if errors.Is(err, ErrDoesNotExist) {
+			// deleted since it was listed
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		bytes, err := tempo_io.ReadAllWithEstimate(reader, size)
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		t := &Tombstone{}
+		if err := json.Unmarshal(bytes, t); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tombstone %s: %w", id, err)
+		}
+		tombstones = append(tombstones, t)
+	}
+
+	sort.Slice(tombstones, func(i, j int) bool {
+		return tombstones[i].CreatedAt.Before(tombstones[j].CreatedAt)
+	})
+
+	return tombstones, nil
+}
+
 // Find implements backend.Reader
 func (r *reader) Find(ctx context.Context, keypath KeyPath, f FindFunc) error {
 	return r.r.Find(ctx, keypath, f)
@@ -313,6 +397,14 @@ func KeyPathForBlock(blockID uuid.UUID, tenantID string) KeyPath {
 	return []string{tenantID, blockID.String()}
 }
 
+// TenantFromKeyPath returns the tenant of the object at keypath, or an empty string for objects outside of a tenant
+func TenantFromKeyPath(keypath KeyPath) string {
+	if len(keypath) == 0 {
+		return ""
+	}
+	return keypath[0]
+}
+
 // ObjectFileName returns a unique identifier for an object in object storage given its name and keypath
 func ObjectFileName(keypath KeyPath, name string) string {
 	return path.Join(path.Join(keypath...), name)
@@ -158,6 +158,12 @@ func TestKeyPathForBlock(t *testing.T) {
 	assert.Equal(t, KeyPath([]string{tid, b.String()}), keypath)
 }
 
+func TestTenantFromKeyPath(t *testing.T) {
+	assert.Equal(t, tenantID, TenantFromKeyPath(KeyPathForBlock(uuid.New(), tenantID)))
+	assert.Equal(t, tenantID, TenantFromKeyPath(KeyPath{tenantID}))
+	assert.Equal(t, "", TenantFromKeyPath(nil))
+}
+
 func TestMetaFileName(t *testing.T) {
 	// WithoutPrefix
 	b := uuid.New()
@@ -0,0 +1,35 @@
+package backend
+
+import (
+	"time"
+)
+
+const (
+	// RowGroupStatsName is the object beneath a tenant where the row group stats are stored.
+	RowGroupStatsName = "row_group_stats.json"
+
+	// maxRowGroupStatsSamples caps the weight of the stored selectivity, so recent searches keep moving it.
+	maxRowGroupStatsSamples = 10_000
+)
+
+// RowGroupStats are the selectivity of the searches of a tenant as observed by the queriers. Compactors use them to
+// tune the size of the row groups of the blocks they write.
+type RowGroupStats struct {
+	// Selectivity is the average fraction of the bytes of the searched row groups that a search read.
+	Selectivity float64 `json:"selectivity"`
+	// Samples is the number of searches the selectivity is averaged over.
+	Samples   int64     `json:"samples"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Merge adds the average selectivity of a number of searches to the stats.
+func (s *RowGroupStats) Merge(selectivity float64, samples int64, now time.Time) {
+	if samples <= 0 {
+		return
+	}
+
+	total := s.Samples + samples
+	s.Selectivity = (s.Selectivity*float64(s.Samples) + selectivity*float64(samples)) / float64(total)
+	s.Samples = min(total, maxRowGroupStatsSamples)
+	s.UpdatedAt = now
+}
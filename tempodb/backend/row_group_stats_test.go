@@ -0,0 +1,29 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowGroupStatsMerge(t *testing.T) {
+	now := time.Now()
+	stats := &RowGroupStats{}
+
+	stats.Merge(0.5, 0, now)
+	require.Equal(t, &RowGroupStats{}, stats)
+
+	stats.Merge(0.5, 10, now)
+	require.Equal(t, &RowGroupStats{Selectivity: 0.5, Samples: 10, UpdatedAt: now}, stats)
+
+	stats.Merge(1, 30, now)
+	require.InDelta(t, 0.875, stats.Selectivity, 0.0001)
+	require.Equal(t, int64(40), stats.Samples)
+
+	// the samples are capped, so new searches keep their weight
+	stats.Merge(0, maxRowGroupStatsSamples, now)
+	require.Equal(t, int64(maxRowGroupStatsSamples), stats.Samples)
+	stats.Merge(1, maxRowGroupStatsSamples, now)
+	require.InDelta(t, 0.5, stats.Selectivity, 0.01)
+}
@@ -20,11 +20,14 @@ func (rw *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) e
 		return backend.ErrEmptyBlockID
 	}
 
-	putObjectOptions := getPutObjectOptions(rw)
+	putObjectOptions, err := getPutObjectOptions(rw, tenantID)
+	if err != nil {
+		return err
+	}
 
 	metaFileName := backend.MetaFileName(blockID, tenantID, rw.cfg.Prefix)
 	// copy meta.json to meta.compacted.json
-	_, err := rw.core.CopyObject(
+	_, err = rw.core.CopyObject(
 		context.TODO(),
 		rw.cfg.Bucket,
 		metaFileName,
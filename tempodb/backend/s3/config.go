@@ -2,10 +2,13 @@ package s3
 
 import (
 	"flag"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/grafana/dskit/crypto/tls"
 	"github.com/grafana/dskit/flagext"
+	"github.com/minio/minio-go/v7"
 
 	"github.com/grafana/tempo/pkg/util"
 )
@@ -32,6 +35,21 @@ type Config struct {
 	Tags             map[string]string `yaml:"tags"`
 	StorageClass     string            `yaml:"storage_class"`
 	Metadata         map[string]string `yaml:"metadata"`
+	// ChecksumType forces an integrity checksum of the given algorithm on all uploads, including every part of a
+	// multipart upload. Leave empty to use the client default.
+	ChecksumType string `yaml:"checksum_type"`
+	// Headers are added to every request, for stores or gateways that require custom headers.
+	Headers map[string]string `yaml:"headers"`
+	// RequestSigning adds an HMAC signature to every request.
+	RequestSigning RequestSigningConfig `yaml:"request_signing"`
+	// RequestSigner adds a custom signature to every request. It can only be set in code and cannot be used with
+	// RequestSigning.
+	RequestSigner RequestSigner `yaml:"-"`
+	// SSE configures server side encryption of uploaded objects.
+	SSE SSEConfig `yaml:"sse"`
+	// SSEKMSKeyIDForTenant returns the KMS key to encrypt the objects of a tenant with, or an empty string to use the
+	// configured encryption. It can only be set in code.
+	SSEKMSKeyIDForTenant func(tenant string) string `yaml:"-"`
 	// Deprecated
 	// See https://github.com/grafana/tempo/pull/3006 for more details
 	NativeAWSAuthEnabled  bool `yaml:"native_aws_auth_enabled"`
@@ -47,9 +65,41 @@ func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet)
 	f.Var(&cfg.SecretKey, util.PrefixConfig(prefix, "s3.secret_key"), "s3 secret key.")
 	f.Var(&cfg.SessionToken, util.PrefixConfig(prefix, "s3.session_token"), "s3 session token.")
 	f.IntVar(&cfg.ListBlocksConcurrency, util.PrefixConfig(prefix, "s3.list_blocks_concurrency"), 3, "number of concurrent list calls to make to backend")
+	f.StringVar(&cfg.ChecksumType, util.PrefixConfig(prefix, "s3.checksum_type"), "", "checksum algorithm to use on uploads. One of crc32, crc32c, sha1, sha256 or crc64nvme. Leave empty to use the client default.")
+	f.StringVar(&cfg.SSE.Type, util.PrefixConfig(prefix, "s3.sse.type"), "", "server side encryption of uploaded objects. One of SSE-S3 or SSE-KMS. Leave empty to use the bucket default.")
+	f.StringVar(&cfg.SSE.KMSKeyID, util.PrefixConfig(prefix, "s3.sse.kms_key_id"), "", "KMS key to encrypt uploaded objects with when using SSE-KMS.")
 	cfg.HedgeRequestsUpTo = 2
 }
 
+// checksum returns the checksum type to force on uploads, or minio.ChecksumNone if none is configured.
+func (cfg *Config) checksum() (minio.ChecksumType, error) {
+	var checksum minio.ChecksumType
+
+	switch strings.ToLower(cfg.ChecksumType) {
+	case "":
+		return minio.ChecksumNone, nil
+	case "crc32":
+		checksum = minio.ChecksumCRC32
+	case "crc32c":
+		checksum = minio.ChecksumCRC32C
+	case "sha1":
+		checksum = minio.ChecksumSHA1
+	case "sha256":
+		checksum = minio.ChecksumSHA256
+	case "crc64nvme":
+		checksum = minio.ChecksumCRC64NVME
+	default:
+		return minio.ChecksumNone, fmt.Errorf("unknown checksum_type %q, must be one of crc32, crc32c, sha1, sha256 or crc64nvme", cfg.ChecksumType)
+	}
+
+	// checksums are sent as trailing headers which are only supported with V4 signing
+	if cfg.SignatureV2 {
+		return minio.ChecksumNone, fmt.Errorf("checksum_type cannot be used with signature_v2")
+	}
+
+	return checksum, nil
+}
+
 func (cfg *Config) PathMatches(other *Config) bool {
 	// S3 bucket names are globally unique
 	return cfg.Bucket == other.Bucket && cfg.Prefix == other.Prefix
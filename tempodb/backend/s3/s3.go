@@ -39,6 +39,7 @@ type readerWriter struct {
 	cfg        *Config
 	core       *minio.Core
 	hedgedCore *minio.Core
+	checksum   minio.ChecksumType
 }
 
 var tracer = otel.Tracer("tempodb/backend/s3")
@@ -118,6 +119,15 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 
 	l := log.Logger
 
+	checksum, err := cfg.checksum()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.SSE.validate(); err != nil {
+		return nil, err
+	}
+
 	core, err := createCore(cfg, false)
 	if err != nil {
 		return nil, fmt.Errorf("unexpected error creating core: %w", err)
@@ -141,16 +151,57 @@ func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
 		cfg:        cfg,
 		core:       core,
 		hedgedCore: hedgedCore,
+		checksum:   checksum,
 	}
 	return rw, nil
 }
 
-func getPutObjectOptions(rw *readerWriter) minio.PutObjectOptions {
+func getPutObjectOptions(rw *readerWriter, tenant string) (minio.PutObjectOptions, error) {
+	sse, err := rw.serverSideEncryption(tenant)
+	if err != nil {
+		return minio.PutObjectOptions{}, err
+	}
+
 	return minio.PutObjectOptions{
-		PartSize:     rw.cfg.PartSize,
-		UserTags:     rw.cfg.Tags,
-		StorageClass: rw.cfg.StorageClass,
-		UserMetadata: rw.cfg.Metadata,
+		PartSize:             rw.cfg.PartSize,
+		UserTags:             rw.cfg.Tags,
+		StorageClass:         rw.cfg.StorageClass,
+		UserMetadata:         rw.cfg.Metadata,
+		Checksum:             rw.checksum,
+		ServerSideEncryption: sse,
+	}, nil
+}
+
+// getNewMultipartUploadOptions returns the options to create a multipart upload with. Core.NewMultipartUpload does not
+// announce the checksum algorithm on its own, so it is passed along as metadata.
+func getNewMultipartUploadOptions(rw *readerWriter, tenant string) (minio.PutObjectOptions, error) {
+	options, err := getPutObjectOptions(rw, tenant)
+	if err != nil || !rw.checksum.IsSet() {
+		return options, err
+	}
+
+	options.UserMetadata = make(map[string]string, len(rw.cfg.Metadata)+1)
+	for k, v := range rw.cfg.Metadata {
+		options.UserMetadata[k] = v
+	}
+	options.UserMetadata["X-Amz-Checksum-Algorithm"] = rw.checksum.String()
+	return options, nil
+}
+
+// setPartChecksum records the checksum of a part so it can be passed on when completing the multipart upload. Not all
+// S3 compatible stores echo the checksum back when uploading a part.
+func setPartChecksum(part *minio.ObjectPart, checksum minio.Checksum) {
+	switch checksum.Type {
+	case minio.ChecksumCRC32:
+		part.ChecksumCRC32 = checksum.Encoded()
+	case minio.ChecksumCRC32C:
+		part.ChecksumCRC32C = checksum.Encoded()
+	case minio.ChecksumSHA1:
+		part.ChecksumSHA1 = checksum.Encoded()
+	case minio.ChecksumSHA256:
+		part.ChecksumSHA256 = checksum.Encoded()
+	case minio.ChecksumCRC64NVME:
+		part.ChecksumCRC64NVME = checksum.Encoded()
 	}
 }
 
@@ -161,11 +212,14 @@ func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.
 
 	span.SetAttributes(attribute.String("object", name))
 
+	putObjectOptions, err := getPutObjectOptions(rw, backend.TenantFromKeyPath(keypath))
+	if err != nil {
+		return err
+	}
+
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	objName := backend.ObjectFileName(keypath, name)
 
-	putObjectOptions := getPutObjectOptions(rw)
-
 	info, err := rw.core.Client.PutObject(
 		derivedCtx,
 		rw.cfg.Bucket,
@@ -175,6 +229,7 @@ func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.
 		putObjectOptions,
 	)
 	if err != nil {
+		observeKMSError("write", err)
 		span.SetStatus(codes.Error, "error writing object to s3 backend")
 		return fmt.Errorf("error writing object to s3 backend, object %s: %w", objName, err)
 	}
@@ -191,13 +246,18 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 	defer span.End()
 
 	var a appendTracker
+	tenant := backend.TenantFromKeyPath(keypath)
 	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
 	objectName := backend.ObjectFileName(keypath, name)
 
-	options := getPutObjectOptions(rw)
 	if tracker != nil {
 		a = tracker.(appendTracker)
 	} else {
+		options, err := getNewMultipartUploadOptions(rw, tenant)
+		if err != nil {
+			return nil, err
+		}
+
 		id, err := rw.core.NewMultipartUpload(
 			ctx,
 			rw.cfg.Bucket,
@@ -205,6 +265,7 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 			options,
 		)
 		if err != nil {
+			observeKMSError("append", err)
 			return nil, err
 		}
 		a.uploadID = id
@@ -213,6 +274,14 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 
 	level.Debug(rw.logger).Log("msg", "appending object to s3", "objectName", objectName)
 
+	partOptions := minio.PutObjectPartOptions{}
+	var checksum minio.Checksum
+	if rw.checksum.IsSet() {
+		checksum = rw.checksum.ChecksumBytes(buffer)
+		partOptions.CustomHeader = http.Header{}
+		partOptions.CustomHeader.Set(rw.checksum.Key(), checksum.Encoded())
+	}
+
 	a.partNum++
 	objPart, err := rw.core.PutObjectPart(
 		ctx,
@@ -222,11 +291,13 @@ func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend
 		a.partNum,
 		bytes.NewReader(buffer),
 		int64(len(buffer)),
-		minio.PutObjectPartOptions{},
+		partOptions,
 	)
 	if err != nil {
+		observeKMSError("append", err)
 		return a, fmt.Errorf("error in multipart upload: %w", err)
 	}
+	setPartChecksum(&objPart, checksum)
 	a.parts = append(a.parts, objPart)
 
 	return a, nil
@@ -242,8 +313,13 @@ func (rw *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendT
 	completeParts := make([]minio.CompletePart, 0)
 	for _, p := range a.parts {
 		completeParts = append(completeParts, minio.CompletePart{
-			PartNumber: p.PartNumber,
-			ETag:       p.ETag,
+			PartNumber:        p.PartNumber,
+			ETag:              p.ETag,
+			ChecksumCRC32:     p.ChecksumCRC32,
+			ChecksumCRC32C:    p.ChecksumCRC32C,
+			ChecksumSHA1:      p.ChecksumSHA1,
+			ChecksumSHA256:    p.ChecksumSHA256,
+			ChecksumCRC64NVME: p.ChecksumCRC64NVME,
 		})
 	}
 
@@ -256,6 +332,7 @@ func (rw *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendT
 		minio.PutObjectOptions{},
 	)
 	if err != nil {
+		observeKMSError("close_append", err)
 		return fmt.Errorf("error completing multipart upload, object: %s, obj etag: %s: %w", a.objectName, uploadInfo.ETag, err)
 	}
 
@@ -542,6 +619,7 @@ func (rw *readerWriter) ReadVersioned(ctx context.Context, name string, keypath
 func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, error) {
 	reader, info, _, err := rw.hedgedCore.GetObject(ctx, rw.cfg.Bucket, name, minio.GetObjectOptions{})
 	if err != nil {
+		observeKMSError("read", err)
 		// do not change or wrap this error
 		// we need to compare the specific err message
 		return nil, err
@@ -575,6 +653,7 @@ func (rw *readerWriter) readRange(ctx context.Context, objName string, offset in
 	}
 	reader, _, _, err := rw.hedgedCore.GetObject(ctx, rw.cfg.Bucket, objName, options)
 	if err != nil {
+		observeKMSError("read_range", err)
 		return fmt.Errorf("error in range read from s3 backend, bucket: %s, objName: %s: %w", rw.cfg.Bucket, objName, err)
 	}
 	defer reader.Close()
@@ -654,8 +733,14 @@ func createCore(cfg *Config, hedge bool) (*minio.Core, error) {
 		customTransport.TLSClientConfig = tlsConfig
 	}
 
+	// headers and signatures are added to every attempt of a request
+	signingTransport, err := newSigningTransport(cfg, customTransport)
+	if err != nil {
+		return nil, err
+	}
+
 	// add instrumentation
-	transport := instrumentation.NewTransport(customTransport)
+	transport := instrumentation.NewTransport(signingTransport)
 	var stats *hedgedhttp.Stats
 	if hedge && cfg.HedgeRequestsAt != 0 {
 		transport, stats, err = hedgedhttp.NewRoundTripperAndStats(cfg.HedgeRequestsAt, cfg.HedgeRequestsUpTo, transport)
@@ -670,6 +755,8 @@ func createCore(cfg *Config, hedge bool) (*minio.Core, error) {
 		Secure:    !cfg.Insecure,
 		Creds:     creds,
 		Transport: transport,
+		// forcing a checksum on uploads requires trailing header support
+		TrailingHeaders: cfg.ChecksumType != "",
 	}
 
 	if cfg.ForcePathStyle {
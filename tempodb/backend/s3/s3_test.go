@@ -3,10 +3,15 @@ package s3
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"io"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
@@ -24,6 +29,7 @@ import (
 	"github.com/grafana/dskit/flagext"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -605,6 +611,319 @@ func TestObjectStorageClass(t *testing.T) {
 	}
 }
 
+func TestObjectChecksum(t *testing.T) {
+	data := []byte("some data")
+	expectedChecksum := minio.ChecksumSHA256.ChecksumBytes(data).Encoded()
+
+	var (
+		putTrailer      string
+		uploadAlgorithm string
+		partChecksum    string
+		completeBody    string
+	)
+
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.Method == getMethod:
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<ListBucketResult>
+			</ListBucketResult>`))
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			uploadAlgorithm = r.Header.Get("X-Amz-Checksum-Algorithm")
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<InitiateMultipartUploadResult><UploadId>upload</UploadId></InitiateMultipartUploadResult>`))
+		case r.Method == putMethod && q.Has("uploadId"):
+			partChecksum = r.Header.Get("X-Amz-Checksum-Sha256")
+			w.Header().Set("ETag", "etag")
+		case r.Method == http.MethodPost && q.Has("uploadId"):
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			completeBody = string(body)
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<CompleteMultipartUploadResult><Bucket>blerg</Bucket></CompleteMultipartUploadResult>`))
+		case r.Method == putMethod:
+			putTrailer = r.Header.Get("X-Amz-Trailer")
+			_, _ = io.Copy(io.Discard, r.Body)
+		}
+	})
+
+	_, w, _, err := New(&Config{
+		Region:       "blerg",
+		AccessKey:    "test",
+		SecretKey:    flagext.SecretWithValue("test"),
+		Bucket:       "blerg",
+		Insecure:     true,
+		Endpoint:     server.URL[7:],
+		ChecksumType: "sha256",
+	})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = w.Write(ctx, "object", backend.KeyPath{"test"}, bytes.NewReader(data), int64(len(data)), nil)
+	require.NoError(t, err)
+	require.Equal(t, "x-amz-checksum-sha256", putTrailer)
+
+	tracker, err := w.Append(ctx, "object", backend.KeyPath{"test"}, nil, data)
+	require.NoError(t, err)
+	require.NoError(t, w.CloseAppend(ctx, tracker))
+	require.Equal(t, "SHA256", uploadAlgorithm)
+	require.Equal(t, expectedChecksum, partChecksum)
+	require.Contains(t, completeBody, "<ChecksumSHA256>"+expectedChecksum+"</ChecksumSHA256>")
+}
+
+func TestConfigChecksum(t *testing.T) {
+	cfg := &Config{}
+	checksum, err := cfg.checksum()
+	require.NoError(t, err)
+	require.False(t, checksum.IsSet())
+
+	cfg.ChecksumType = "CRC32C"
+	checksum, err = cfg.checksum()
+	require.NoError(t, err)
+	require.Equal(t, minio.ChecksumCRC32C, checksum)
+
+	cfg.ChecksumType = "md5"
+	_, err = cfg.checksum()
+	require.Error(t, err)
+
+	cfg.ChecksumType = "sha256"
+	cfg.SignatureV2 = true
+	_, err = cfg.checksum()
+	require.Error(t, err)
+}
+
+func TestObjectServerSideEncryption(t *testing.T) {
+	type sseHeaders struct {
+		sse, keyID string
+	}
+	var puts, uploads []sseHeaders
+
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		headers := sseHeaders{r.Header.Get("X-Amz-Server-Side-Encryption"), r.Header.Get("X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id")}
+		switch {
+		case r.Method == getMethod:
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<ListBucketResult>
+			</ListBucketResult>`))
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			uploads = append(uploads, headers)
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<InitiateMultipartUploadResult><UploadId>upload</UploadId></InitiateMultipartUploadResult>`))
+		case r.Method == putMethod && q.Has("uploadId"):
+			w.Header().Set("ETag", "etag")
+		case r.Method == http.MethodPost && q.Has("uploadId"):
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<CompleteMultipartUploadResult><Bucket>blerg</Bucket></CompleteMultipartUploadResult>`))
+		case r.Method == putMethod:
+			puts = append(puts, headers)
+			_, _ = io.Copy(io.Discard, r.Body)
+		}
+	})
+
+	cfg := &Config{
+		Region:    "blerg",
+		AccessKey: "test",
+		SecretKey: flagext.SecretWithValue("test"),
+		Bucket:    "blerg",
+		Insecure:  true,
+		Endpoint:  server.URL[7:],
+		SSE:       SSEConfig{Type: SSETypeS3},
+		SSEKMSKeyIDForTenant: func(tenant string) string {
+			if tenant == "tenant-with-key" {
+				return "tenant-key"
+			}
+			return ""
+		},
+	}
+	_, w, _, err := New(cfg)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	data := []byte("data")
+	for _, tenant := range []string{"tenant", "tenant-with-key"} {
+		err = w.Write(ctx, "object", backend.KeyPath{tenant}, bytes.NewReader(data), int64(len(data)), nil)
+		require.NoError(t, err)
+
+		tracker, err := w.Append(ctx, "object", backend.KeyPath{tenant}, nil, data)
+		require.NoError(t, err)
+		require.NoError(t, w.CloseAppend(ctx, tracker))
+	}
+
+	// the key of a tenant overrides the configured encryption
+	expected := []sseHeaders{{"AES256", ""}, {"aws:kms", "tenant-key"}}
+	require.Equal(t, expected, puts)
+	require.Equal(t, expected, uploads)
+}
+
+func TestConfigServerSideEncryption(t *testing.T) {
+	cfg := &SSEConfig{}
+	require.NoError(t, cfg.validate())
+
+	cfg.Type = SSETypeKMS
+	require.Error(t, cfg.validate(), "kms_key_id is required")
+
+	cfg.KMSKeyID = "key"
+	require.NoError(t, cfg.validate())
+
+	cfg.Type = "SSE-C"
+	require.Error(t, cfg.validate())
+}
+
+func TestKMSThrottledRequests(t *testing.T) {
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == getMethod && r.URL.Query().Has("delimiter") {
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<ListBucketResult>
+			</ListBucketResult>`))
+			return
+		}
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+		<Error><Code>KMS.ThrottlingException</Code><Message>Rate exceeded</Message></Error>`))
+	})
+
+	r, w, _, err := New(&Config{
+		Region:    "blerg",
+		AccessKey: "test",
+		SecretKey: flagext.SecretWithValue("test"),
+		Bucket:    "blerg",
+		Insecure:  true,
+		Endpoint:  server.URL[7:],
+		SSE:       SSEConfig{Type: SSETypeKMS, KMSKeyID: "key"},
+	})
+	require.NoError(t, err)
+
+	writes := testutil.ToFloat64(metricKMSThrottledRequests.WithLabelValues("write"))
+	reads := testutil.ToFloat64(metricKMSThrottledRequests.WithLabelValues("read"))
+
+	ctx := context.Background()
+	err = w.Write(ctx, "object", backend.KeyPath{"test"}, bytes.NewReader([]byte("data")), 4, nil)
+	require.Error(t, err)
+	_, _, err = r.Read(ctx, "object", backend.KeyPath{"test"}, nil)
+	require.Error(t, err)
+
+	require.Equal(t, writes+1, testutil.ToFloat64(metricKMSThrottledRequests.WithLabelValues("write")))
+	require.Equal(t, reads+1, testutil.ToFloat64(metricKMSThrottledRequests.WithLabelValues("read")))
+}
+
+func TestRequestHeadersAndSigning(t *testing.T) {
+	var (
+		requests   int
+		headers    []string
+		signatures []string
+	)
+
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		headers = append(headers, r.Header.Get("X-Gateway-Tenant"))
+
+		// the signature is checked the same way a gateway would
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(stringToSign(r.Method, r.Host, r.URL.EscapedPath(), r.URL.RawQuery, r.Header.Get("X-Signature-Date"), r.Header.Get(headerContentSHA256))))
+		if hex.EncodeToString(mac.Sum(nil)) == r.Header.Get("X-Signature") {
+			signatures = append(signatures, r.Header.Get("X-Signature"))
+		}
+
+		if r.Method == getMethod {
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+			<ListBucketResult>
+			</ListBucketResult>`))
+			return
+		}
+		_, _ = io.Copy(io.Discard, r.Body)
+	})
+
+	_, w, _, err := New(&Config{
+		Region:    "blerg",
+		AccessKey: "test",
+		SecretKey: flagext.SecretWithValue("test"),
+		Bucket:    "blerg",
+		Insecure:  true,
+		Endpoint:  server.URL[7:],
+		Headers:   map[string]string{"X-Gateway-Tenant": "tempo"},
+		RequestSigning: RequestSigningConfig{
+			Algorithm:       "hmac-sha256",
+			Secret:          flagext.SecretWithValue("secret"),
+			Header:          "X-Signature",
+			TimestampHeader: "X-Signature-Date",
+		},
+	})
+	require.NoError(t, err)
+
+	err = w.Write(context.Background(), "object", backend.KeyPath{"test"}, bytes.NewReader([]byte("data")), 4, nil)
+	require.NoError(t, err)
+
+	// the list request made by New and the write are both signed
+	require.Equal(t, 2, requests)
+	require.Equal(t, []string{"tempo", "tempo"}, headers)
+	require.Len(t, signatures, 2)
+}
+
+func TestCustomRequestSigner(t *testing.T) {
+	var signed atomic.Int32
+	server := testServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Custom-Signature") == "signed" {
+			signed.Add(1)
+		}
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+		<ListBucketResult>
+		</ListBucketResult>`))
+	})
+
+	cfg := &Config{
+		Region:    "blerg",
+		AccessKey: "test",
+		SecretKey: flagext.SecretWithValue("test"),
+		Bucket:    "blerg",
+		Insecure:  true,
+		Endpoint:  server.URL[7:],
+		RequestSigner: RequestSignerFunc(func(req *http.Request) error {
+			req.Header.Set("X-Custom-Signature", "signed")
+			return nil
+		}),
+	}
+	_, _, _, err := New(cfg)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), signed.Load())
+
+	// the custom signer replaces the configured signing
+	cfg.RequestSigning = RequestSigningConfig{Algorithm: "hmac-sha256", Secret: flagext.SecretWithValue("secret")}
+	_, _, _, err = New(cfg)
+	require.Error(t, err)
+}
+
+func TestRequestSigningConfig(t *testing.T) {
+	cfg := &RequestSigningConfig{}
+	signer, err := cfg.signer()
+	require.NoError(t, err)
+	require.Nil(t, signer)
+
+	cfg.Algorithm = "md5"
+	_, err = cfg.signer()
+	require.Error(t, err)
+
+	cfg.Algorithm = "HMAC-SHA512"
+	_, err = cfg.signer()
+	require.Error(t, err, "secret is required")
+
+	cfg.Secret = flagext.SecretWithValue("secret")
+	signer, err = cfg.signer()
+	require.NoError(t, err)
+
+	s := signer.(*hmacSigner)
+	s.now = timeNow
+	req := httptest.NewRequest(http.MethodGet, "http://blerg.s3.local/test/object?versionId=1", nil)
+	require.NoError(t, signer.SignRequest(req))
+
+	mac := hmac.New(sha512.New, []byte("secret"))
+	mac.Write([]byte("GET\nblerg.s3.local\n/test/object\nversionId=1\n2024-05-12T16:21:24Z\n"))
+	require.Equal(t, "2024-05-12T16:21:24Z", req.Header.Get(defaultSignatureTimestampHeader))
+	require.Equal(t, hex.EncodeToString(mac.Sum(nil)), req.Header.Get(defaultSignatureHeader))
+}
+
 func testServer(t *testing.T, httpHandler http.HandlerFunc) *httptest.Server {
 	t.Helper()
 	assert.NotNil(t, httpHandler)
@@ -0,0 +1,166 @@
+package s3
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+)
+
+const (
+	signingAlgorithmHMACSHA256 = "hmac-sha256"
+	signingAlgorithmHMACSHA512 = "hmac-sha512"
+
+	defaultSignatureHeader          = "X-Tempo-Signature"
+	defaultSignatureTimestampHeader = "X-Tempo-Signature-Date"
+
+	headerContentSHA256 = "X-Amz-Content-Sha256"
+)
+
+// RequestSigner adds a custom signature to every request made to the object store. It is called for every attempt
+// of a request, after the request has been signed by the S3 client, and is used for stores or gateways that require
+// a signature in addition to the S3 authentication.
+type RequestSigner interface {
+	SignRequest(req *http.Request) error
+}
+
+// RequestSignerFunc is a function that implements RequestSigner.
+type RequestSignerFunc func(req *http.Request) error
+
+func (f RequestSignerFunc) SignRequest(req *http.Request) error {
+	return f(req)
+}
+
+type RequestSigningConfig struct {
+	// Algorithm is the HMAC algorithm used to sign requests. Leave empty to not sign requests.
+	Algorithm string         `yaml:"algorithm"`
+	Secret    flagext.Secret `yaml:"secret"`
+	// Header is the header the hex encoded signature is sent in.
+	Header string `yaml:"header"`
+	// TimestampHeader is the header the signed timestamp is sent in.
+	TimestampHeader string `yaml:"timestamp_header"`
+}
+
+// signer returns the HMAC signer of the config, or nil if requests are not signed.
+func (cfg *RequestSigningConfig) signer() (RequestSigner, error) {
+	var h func() hash.Hash
+
+	switch strings.ToLower(cfg.Algorithm) {
+	case "":
+		return nil, nil
+	case signingAlgorithmHMACSHA256:
+		h = sha256.New
+	case signingAlgorithmHMACSHA512:
+		h = sha512.New
+	default:
+		return nil, fmt.Errorf("unknown request_signing.algorithm %q, must be one of %s or %s", cfg.Algorithm, signingAlgorithmHMACSHA256, signingAlgorithmHMACSHA512)
+	}
+
+	if cfg.Secret.String() == "" {
+		return nil, errors.New("request_signing.secret is required to sign requests")
+	}
+
+	header := cfg.Header
+	if header == "" {
+		header = defaultSignatureHeader
+	}
+	timestampHeader := cfg.TimestampHeader
+	if timestampHeader == "" {
+		timestampHeader = defaultSignatureTimestampHeader
+	}
+
+	return &hmacSigner{
+		hash:            h,
+		secret:          []byte(cfg.Secret.String()),
+		header:          header,
+		timestampHeader: timestampHeader,
+		now:             time.Now,
+	}, nil
+}
+
+// hmacSigner signs the method, host, path, query, a timestamp and the payload hash of the S3 client of a request.
+// The string to sign is the newline separated list of these values.
+type hmacSigner struct {
+	hash            func() hash.Hash
+	secret          []byte
+	header          string
+	timestampHeader string
+	now             func() time.Time
+}
+
+func (s *hmacSigner) SignRequest(req *http.Request) error {
+	timestamp := s.now().UTC().Format(time.RFC3339)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	mac := hmac.New(s.hash, s.secret)
+	mac.Write([]byte(stringToSign(req.Method, host, req.URL.EscapedPath(), req.URL.RawQuery, timestamp, req.Header.Get(headerContentSHA256))))
+
+	req.Header.Set(s.timestampHeader, timestamp)
+	req.Header.Set(s.header, hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
+
+func stringToSign(values ...string) string {
+	return strings.Join(values, "\n")
+}
+
+// signingTransport adds the static headers and the signature to every request.
+type signingTransport struct {
+	next    http.RoundTripper
+	headers map[string]string
+	signer  RequestSigner
+}
+
+// newSigningTransport wraps next to add the configured headers and signature, or returns next if there is nothing
+// to add.
+func newSigningTransport(cfg *Config, next http.RoundTripper) (http.RoundTripper, error) {
+	signer, err := cfg.RequestSigning.signer()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.RequestSigner != nil {
+		if signer != nil {
+			return nil, errors.New("request_signing cannot be used with a custom request signer")
+		}
+		signer = cfg.RequestSigner
+	}
+
+	if len(cfg.Headers) == 0 && signer == nil {
+		return next, nil
+	}
+
+	return &signingTransport{
+		next:    next,
+		headers: cfg.Headers,
+		signer:  signer,
+	}, nil
+}
+
+func (t *signingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a round tripper must not modify the request
+	req = req.Clone(req.Context())
+
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+
+	if t.signer != nil {
+		if err := t.signer.SignRequest(req); err != nil {
+			return nil, fmt.Errorf("failed to sign request: %w", err)
+		}
+	}
+
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,87 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/encrypt"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	SSETypeS3  = "SSE-S3"
+	SSETypeKMS = "SSE-KMS"
+)
+
+var metricKMSThrottledRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "tempodb",
+	Name:      "backend_s3_kms_throttled_requests_total",
+	Help:      "The total number of s3 requests that failed because KMS throttled the encryption or decryption of the object.",
+}, []string{"operation"})
+
+type SSEConfig struct {
+	// Type is the server side encryption of uploaded objects, SSE-S3 or SSE-KMS. Leave empty to use the bucket default.
+	Type string `yaml:"type"`
+	// KMSKeyID is the KMS key objects are encrypted with when the type is SSE-KMS.
+	KMSKeyID string `yaml:"kms_key_id"`
+	// KMSEncryptionContext is the encryption context passed to KMS when the type is SSE-KMS.
+	KMSEncryptionContext map[string]string `yaml:"kms_encryption_context"`
+}
+
+func (cfg *SSEConfig) validate() error {
+	switch cfg.Type {
+	case "", SSETypeS3:
+	case SSETypeKMS:
+		if cfg.KMSKeyID == "" {
+			return fmt.Errorf("sse.kms_key_id is required with sse.type %s", SSETypeKMS)
+		}
+	default:
+		return fmt.Errorf("unknown sse.type %q, must be one of %s or %s", cfg.Type, SSETypeS3, SSETypeKMS)
+	}
+	return nil
+}
+
+// serverSideEncryption returns the encryption of the objects of the tenant, or nil if objects are not encrypted by
+// the client. A KMS key of the tenant overrides the configured encryption.
+func (rw *readerWriter) serverSideEncryption(tenant string) (encrypt.ServerSide, error) {
+	keyID := ""
+	if tenant != "" && rw.cfg.SSEKMSKeyIDForTenant != nil {
+		keyID = rw.cfg.SSEKMSKeyIDForTenant(tenant)
+	}
+
+	if keyID == "" {
+		switch rw.cfg.SSE.Type {
+		case SSETypeS3:
+			return encrypt.NewSSE(), nil
+		case SSETypeKMS:
+			keyID = rw.cfg.SSE.KMSKeyID
+		default:
+			return nil, nil
+		}
+	}
+
+	var context interface{}
+	if len(rw.cfg.SSE.KMSEncryptionContext) > 0 {
+		context = rw.cfg.SSE.KMSEncryptionContext
+	}
+
+	sse, err := encrypt.NewSSEKMS(keyID, context)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create SSE-KMS encryption: %w", err)
+	}
+	return sse, nil
+}
+
+// observeKMSError counts requests that failed because KMS throttled them. These errors slow down flushes and
+// queries of encrypted objects and are retried by the client.
+func observeKMSError(operation string, err error) {
+	if err == nil {
+		return
+	}
+
+	switch minio.ToErrorResponse(err).Code {
+	case "KMS.ThrottlingException", "ThrottlingException":
+		metricKMSThrottledRequests.WithLabelValues(operation).Inc()
+	}
+}
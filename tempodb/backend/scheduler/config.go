@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"errors"
+	"flag"
+	"time"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	// InteractiveLatencyThreshold is the average latency of interactive requests above which
+	// background requests are throttled.
+	InteractiveLatencyThreshold time.Duration `yaml:"interactive_latency_threshold"`
+	// Window is the period over which the latency of interactive requests is averaged.
+	Window time.Duration `yaml:"window"`
+	// ThrottledConcurrency is the number of concurrent requests allowed per background class while
+	// throttled.
+	ThrottledConcurrency int `yaml:"throttled_concurrency"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.BoolVar(&cfg.Enabled, util.PrefixConfig(prefix, "io_scheduler.enabled"), false, "Throttle background backend requests when interactive requests are slow.")
+	cfg.InteractiveLatencyThreshold = time.Second
+	cfg.Window = 30 * time.Second
+	cfg.ThrottledConcurrency = 2
+}
+
+func (cfg *Config) Validate() error {
+	if !cfg.Enabled {
+		return nil
+	}
+	if cfg.InteractiveLatencyThreshold <= 0 {
+		return errors.New("interactive_latency_threshold must be greater than 0")
+	}
+	if cfg.Window <= 0 {
+		return errors.New("window must be greater than 0")
+	}
+	if cfg.ThrottledConcurrency <= 0 {
+		return errors.New("throttled_concurrency must be greater than 0")
+	}
+	return nil
+}
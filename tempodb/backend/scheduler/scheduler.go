@@ -0,0 +1,199 @@
+package scheduler
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+var (
+	metricRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace:                       "tempodb",
+		Name:                            "backend_scheduler_request_duration_seconds",
+		Help:                            "Time spent doing backend read requests by priority class.",
+		Buckets:                         prometheus.ExponentialBuckets(0.005, 4, 6),
+		NativeHistogramBucketFactor:     1.1,
+		NativeHistogramMaxBucketNumber:  100,
+		NativeHistogramMinResetDuration: 1 * time.Hour,
+	}, []string{"class"})
+	metricThrottledRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "backend_scheduler_throttled_requests_total",
+		Help:      "Total number of backend read requests that were throttled by priority class.",
+	}, []string{"class"})
+	metricThrottleWait = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "backend_scheduler_throttle_wait_seconds_total",
+		Help:      "Total time backend read requests waited while throttled by priority class.",
+	}, []string{"class"})
+	metricDegraded = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "backend_scheduler_interactive_degraded",
+		Help:      "1 if the latency of interactive backend requests is above the threshold and background requests are throttled.",
+	})
+)
+
+// scheduler is a backend.RawReader that tags requests with the priority class from their context
+// and throttles background classes while the latency of interactive requests is degraded.
+type scheduler struct {
+	cfg  *Config
+	next backend.RawReader
+
+	throttles map[backend.IOPriority]chan struct{}
+
+	mtx         sync.Mutex
+	degraded    bool
+	windowStart time.Time
+	windowSum   time.Duration
+	windowCount int
+
+	now func() time.Time
+}
+
+var _ backend.RawReader = (*scheduler)(nil)
+
+// New returns a backend.RawReader that schedules the requests of next by priority class.
+func New(cfg *Config, next backend.RawReader) backend.RawReader {
+	return newScheduler(cfg, next)
+}
+
+func newScheduler(cfg *Config, next backend.RawReader) *scheduler {
+	s := &scheduler{
+		cfg:       cfg,
+		next:      next,
+		throttles: map[backend.IOPriority]chan struct{}{},
+		now:       time.Now,
+	}
+	for _, p := range []backend.IOPriority{backend.IOPriorityCompaction, backend.IOPriorityPolling} {
+		s.throttles[p] = make(chan struct{}, cfg.ThrottledConcurrency)
+	}
+	s.windowStart = s.now()
+
+	return s
+}
+
+// List implements backend.RawReader
+func (s *scheduler) List(ctx context.Context, keypath backend.KeyPath) (objects []string, err error) {
+	err = s.do(ctx, func() error {
+		objects, err = s.next.List(ctx, keypath)
+		return err
+	})
+	return
+}
+
+// ListBlocks implements backend.RawReader
+func (s *scheduler) ListBlocks(ctx context.Context, tenant string) (blockIDs []uuid.UUID, compactedBlockIDs []uuid.UUID, err error) {
+	err = s.do(ctx, func() error {
+		blockIDs, compactedBlockIDs, err = s.next.ListBlocks(ctx, tenant)
+		return err
+	})
+	return
+}
+
+// Find implements backend.RawReader
+func (s *scheduler) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) error {
+	return s.do(ctx, func() error {
+		return s.next.Find(ctx, keypath, f)
+	})
+}
+
+// Read implements backend.RawReader
+func (s *scheduler) Read(ctx context.Context, name string, keypath backend.KeyPath, cacheInfo *backend.CacheInfo) (rc io.ReadCloser, size int64, err error) {
+	err = s.do(ctx, func() error {
+		rc, size, err = s.next.Read(ctx, name, keypath, cacheInfo)
+		return err
+	})
+	return
+}
+
+// ReadRange implements backend.RawReader
+func (s *scheduler) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, cacheInfo *backend.CacheInfo) error {
+	return s.do(ctx, func() error {
+		return s.next.ReadRange(ctx, name, keypath, offset, buffer, cacheInfo)
+	})
+}
+
+// Shutdown implements backend.RawReader
+func (s *scheduler) Shutdown() {
+	s.next.Shutdown()
+}
+
+func (s *scheduler) do(ctx context.Context, f func() error) error {
+	p := backend.IOPriorityFromContext(ctx)
+	class := p.String()
+
+	if throttle, ok := s.throttles[p]; ok && s.isDegraded() {
+		metricThrottledRequests.WithLabelValues(class).Inc()
+
+		start := time.Now()
+		select {
+		case throttle <- struct{}{}:
+			defer func() { <-throttle }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		metricThrottleWait.WithLabelValues(class).Add(time.Since(start).Seconds())
+	}
+
+	start := s.now()
+	err := f()
+	elapsed := s.now().Sub(start)
+	metricRequestDuration.WithLabelValues(class).Observe(elapsed.Seconds())
+
+	if p == backend.IOPriorityInteractive {
+		s.observeInteractive(elapsed)
+	}
+
+	return err
+}
+
+func (s *scheduler) observeInteractive(d time.Duration) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.rotate()
+	s.windowSum += d
+	s.windowCount++
+}
+
+func (s *scheduler) isDegraded() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.rotate()
+	return s.degraded
+}
+
+// rotate starts a new window once the current one has passed. Interactive requests are degraded
+// if their average latency in the last window was above the threshold. Requests of a window that
+// ended more than a window ago are stale and ignored. Must be called with the lock held.
+func (s *scheduler) rotate() {
+	now := s.now()
+	age := now.Sub(s.windowStart)
+	if age < s.cfg.Window {
+		return
+	}
+
+	degraded := false
+	if s.windowCount > 0 && age < 2*s.cfg.Window {
+		degraded = s.windowSum/time.Duration(s.windowCount) > s.cfg.InteractiveLatencyThreshold
+	}
+
+	s.degraded = degraded
+	if degraded {
+		metricDegraded.Set(1)
+	} else {
+		metricDegraded.Set(0)
+	}
+
+	s.windowStart = now
+	s.windowSum = 0
+	s.windowCount = 0
+}
@@ -0,0 +1,152 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestSchedulerDegraded(t *testing.T) {
+	cfg := &Config{
+		Enabled:                     true,
+		InteractiveLatencyThreshold: time.Second,
+		Window:                      time.Minute,
+		ThrottledConcurrency:        1,
+	}
+
+	now := time.Unix(0, 0)
+	s := newScheduler(cfg, &backend.MockRawReader{})
+	s.now = func() time.Time { return now }
+	s.windowStart = now
+
+	read := func(ctx context.Context, latency time.Duration) {
+		err := s.do(ctx, func() error {
+			now = now.Add(latency)
+			return nil
+		})
+		require.NoError(t, err)
+	}
+
+	// fast interactive requests
+	read(context.Background(), 100*time.Millisecond)
+	now = now.Add(time.Minute)
+	require.False(t, s.isDegraded())
+
+	// slow interactive requests degrade the next window. background requests don't count
+	read(context.Background(), 2*time.Second)
+	read(context.Background(), 500*time.Millisecond)
+	read(backend.WithIOPriority(context.Background(), backend.IOPriorityCompaction), 10*time.Second)
+	now = now.Add(time.Minute)
+	require.True(t, s.isDegraded())
+
+	// no interactive requests in the last window
+	now = now.Add(time.Minute)
+	require.False(t, s.isDegraded())
+
+	// stale requests are ignored
+	read(context.Background(), 2*time.Second)
+	now = now.Add(5 * time.Minute)
+	require.False(t, s.isDegraded())
+}
+
+func TestSchedulerThrottles(t *testing.T) {
+	cfg := &Config{
+		Enabled:                     true,
+		InteractiveLatencyThreshold: time.Second,
+		Window:                      time.Minute,
+		ThrottledConcurrency:        2,
+	}
+
+	s := newScheduler(cfg, &backend.MockRawReader{})
+	s.degraded = true
+	s.windowStart = time.Now().Add(time.Hour) // never rotate
+
+	for _, tc := range []struct {
+		priority       backend.IOPriority
+		maxConcurrency int64
+	}{
+		{priority: backend.IOPriorityInteractive, maxConcurrency: 10},
+		{priority: backend.IOPriorityCompaction, maxConcurrency: 2},
+		{priority: backend.IOPriorityPolling, maxConcurrency: 2},
+	} {
+		t.Run(tc.priority.String(), func(t *testing.T) {
+			var (
+				ctx     = backend.WithIOPriority(context.Background(), tc.priority)
+				current = atomic.NewInt64(0)
+				maxSeen = atomic.NewInt64(0)
+				wg      sync.WaitGroup
+			)
+
+			for i := 0; i < 10; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_ = s.do(ctx, func() error {
+						n := current.Inc()
+						for {
+							m := maxSeen.Load()
+							if n <= m || maxSeen.CompareAndSwap(m, n) {
+								break
+							}
+						}
+						time.Sleep(50 * time.Millisecond)
+						current.Dec()
+						return nil
+					})
+				}()
+			}
+			wg.Wait()
+
+			if tc.priority == backend.IOPriorityInteractive {
+				require.Greater(t, maxSeen.Load(), int64(2))
+			} else {
+				require.LessOrEqual(t, maxSeen.Load(), tc.maxConcurrency)
+			}
+		})
+	}
+
+	// a throttled request respects the context
+	throttle := s.throttles[backend.IOPriorityCompaction]
+	for i := 0; i < cfg.ThrottledConcurrency; i++ {
+		throttle <- struct{}{}
+	}
+	ctx, cancel := context.WithCancel(backend.WithIOPriority(context.Background(), backend.IOPriorityCompaction))
+	cancel()
+	err := s.do(ctx, func() error { return nil })
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSchedulerReader(t *testing.T) {
+	s := New(&Config{Window: time.Minute, InteractiveLatencyThreshold: time.Second, ThrottledConcurrency: 1}, &backend.MockRawReader{
+		L:     []string{"a", "b"},
+		Range: []byte("data"),
+	})
+
+	list, err := s.List(context.Background(), backend.KeyPath{"tenant"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, list)
+
+	buffer := make([]byte, 4)
+	err = s.ReadRange(backend.WithIOPriority(context.Background(), backend.IOPriorityCompaction), "name", backend.KeyPath{"tenant"}, 0, buffer, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), buffer)
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := &Config{}
+	require.NoError(t, cfg.Validate())
+
+	cfg.Enabled = true
+	require.Error(t, cfg.Validate())
+
+	cfg.InteractiveLatencyThreshold = time.Second
+	cfg.Window = time.Minute
+	cfg.ThrottledConcurrency = 1
+	require.NoError(t, cfg.Validate())
+}
@@ -0,0 +1,188 @@
+package swift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryMargin is the time before its expiry a token is renewed.
+const tokenExpiryMargin = time.Minute
+
+// keystone authenticates against Keystone v3 with an application credential and caches the token and the
+// object-store endpoint until the token expires.
+type keystone struct {
+	cfg    *Config
+	client *http.Client
+
+	mtx       sync.Mutex
+	token     string
+	endpoint  string
+	expiresAt time.Time
+}
+
+type authRequest struct {
+	Auth struct {
+		Identity struct {
+			Methods               []string              `json:"methods"`
+			ApplicationCredential applicationCredential `json:"application_credential"`
+		} `json:"identity"`
+	} `json:"auth"`
+}
+
+type applicationCredential struct {
+	ID     string    `json:"id,omitempty"`
+	Name   string    `json:"name,omitempty"`
+	Secret string    `json:"secret"`
+	User   *authUser `json:"user,omitempty"`
+}
+
+type authUser struct {
+	ID     string      `json:"id,omitempty"`
+	Name   string      `json:"name,omitempty"`
+	Domain *authDomain `json:"domain,omitempty"`
+}
+
+type authDomain struct {
+	Name string `json:"name"`
+}
+
+type authResponse struct {
+	Token struct {
+		ExpiresAt time.Time `json:"expires_at"`
+		Catalog   []struct {
+			Type      string `json:"type"`
+			Endpoints []struct {
+				Interface string `json:"interface"`
+				Region    string `json:"region"`
+				RegionID  string `json:"region_id"`
+				URL       string `json:"url"`
+			} `json:"endpoints"`
+		} `json:"catalog"`
+	} `json:"token"`
+}
+
+func newKeystone(cfg *Config, client *http.Client) (*keystone, error) {
+	if cfg.AuthURL == "" {
+		return nil, errors.New("auth_url must be set")
+	}
+	if cfg.ApplicationCredentialID == "" && cfg.ApplicationCredentialName == "" {
+		return nil, errors.New("application_credential_id or application_credential_name must be set")
+	}
+	if cfg.ApplicationCredentialID == "" && cfg.UserID == "" && (cfg.Username == "" || cfg.UserDomainName == "") {
+		return nil, errors.New("user_id or username and user_domain_name must be set to authenticate with application_credential_name")
+	}
+
+	return &keystone{
+		cfg:    cfg,
+		client: client,
+	}, nil
+}
+
+// get returns a valid token and the object-store endpoint, authenticating if necessary.
+func (k *keystone) get(ctx context.Context) (token, endpoint string, err error) {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	if k.token == "" || time.Now().Add(tokenExpiryMargin).After(k.expiresAt) {
+		if err := k.authenticate(ctx); err != nil {
+			return "", "", err
+		}
+	}
+
+	return k.token, k.endpoint, nil
+}
+
+// invalidate discards token, e.g. because it was revoked. The next call to get authenticates again.
+func (k *keystone) invalidate(token string) {
+	k.mtx.Lock()
+	defer k.mtx.Unlock()
+
+	if k.token == token {
+		k.token = ""
+	}
+}
+
+func (k *keystone) authenticate(ctx context.Context) error {
+	var req authRequest
+	req.Auth.Identity.Methods = []string{"application_credential"}
+	req.Auth.Identity.ApplicationCredential = applicationCredential{
+		ID:     k.cfg.ApplicationCredentialID,
+		Secret: k.cfg.ApplicationCredentialSecret.String(),
+	}
+	if k.cfg.ApplicationCredentialID == "" {
+		user := &authUser{ID: k.cfg.UserID}
+		if user.ID == "" {
+			user.Name = k.cfg.Username
+			user.Domain = &authDomain{Name: k.cfg.UserDomainName}
+		}
+		req.Auth.Identity.ApplicationCredential.Name = k.cfg.ApplicationCredentialName
+		req.Auth.Identity.ApplicationCredential.User = user
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(k.cfg.AuthURL, "/")+"/auth/tokens", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := k.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("authenticating with keystone: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("authenticating with keystone: unexpected status %s: %s", resp.Status, msg)
+	}
+
+	var authResp authResponse
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		return fmt.Errorf("decoding keystone token: %w", err)
+	}
+
+	endpoint := k.cfg.EndpointURL
+	if endpoint == "" {
+		endpoint, err = authResp.objectStoreEndpoint(k.cfg.RegionName, k.cfg.Interface)
+		if err != nil {
+			return err
+		}
+	}
+
+	k.token = resp.Header.Get("X-Subject-Token")
+	k.endpoint = strings.TrimSuffix(endpoint, "/")
+	k.expiresAt = authResp.Token.ExpiresAt
+	return nil
+}
+
+// objectStoreEndpoint returns the url of the object-store in the service catalog of the token.
+func (r *authResponse) objectStoreEndpoint(region, iface string) (string, error) {
+	for _, service := range r.Token.Catalog {
+		if service.Type != "object-store" {
+			continue
+		}
+		for _, e := range service.Endpoints {
+			if e.Interface != iface {
+				continue
+			}
+			if region != "" && e.Region != region && e.RegionID != region {
+				continue
+			}
+			return e.URL, nil
+		}
+	}
+	return "", fmt.Errorf("no object-store endpoint with interface %q in region %q in the service catalog", iface, region)
+}
@@ -0,0 +1,95 @@
+package swift
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func (rw *readerWriter) MarkBlockCompacted(blockID uuid.UUID, tenantID string) error {
+	if len(tenantID) == 0 {
+		return backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return backend.ErrEmptyBlockID
+	}
+
+	// move meta file to a new location
+	metaFilename := backend.MetaFileName(blockID, tenantID, rw.cfg.Prefix)
+	compactedMetaFilename := backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix)
+	ctx := context.TODO()
+
+	// copy server side
+	header := http.Header{}
+	header.Set("X-Copy-From", "/"+escapeObjectName(rw.cfg.ContainerName+"/"+metaFilename))
+
+	resp, err := rw.do(ctx, rw.client, http.MethodPut, rw.cfg.ContainerName, compactedMetaFilename, nil, header, nil, 0)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if err := statusError(resp, http.StatusCreated); err != nil {
+		return err
+	}
+
+	return rw.delete(ctx, rw.cfg.ContainerName, metaFilename)
+}
+
+func (rw *readerWriter) ClearBlock(blockID uuid.UUID, tenantID string) error {
+	if len(tenantID) == 0 {
+		return backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return backend.ErrEmptyBlockID
+	}
+
+	ctx := context.TODO()
+	prefix := backend.RootPath(blockID, tenantID, rw.cfg.Prefix) + "/"
+
+	var objects []string
+	err := rw.list(ctx, rw.cfg.ContainerName, prefix, "", func(e listEntry) {
+		objects = append(objects, e.Name)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range objects {
+		if err := rw.delete(ctx, rw.cfg.ContainerName, name); err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+			return err
+		}
+	}
+
+	return rw.deleteSegments(ctx, prefix)
+}
+
+func (rw *readerWriter) CompactedBlockMeta(blockID uuid.UUID, tenantID string) (*backend.CompactedBlockMeta, error) {
+	if len(tenantID) == 0 {
+		return nil, backend.ErrEmptyTenantID
+	}
+	if blockID == uuid.Nil {
+		return nil, backend.ErrEmptyBlockID
+	}
+
+	name := backend.CompactedMetaFileName(blockID, tenantID, rw.cfg.Prefix)
+
+	bytes, header, err := rw.readAll(context.Background(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &backend.CompactedBlockMeta{}
+	err = json.Unmarshal(bytes, out)
+	if err != nil {
+		return nil, err
+	}
+
+	out.CompactedTime, _ = http.ParseTime(header.Get("Last-Modified"))
+
+	return out, nil
+}
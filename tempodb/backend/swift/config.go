@@ -0,0 +1,62 @@
+package swift
+
+import (
+	"flag"
+	"time"
+
+	"github.com/grafana/dskit/flagext"
+
+	"github.com/grafana/tempo/pkg/util"
+)
+
+type Config struct {
+	// AuthURL is the Keystone v3 endpoint, e.g. https://keystone.example.com/v3
+	AuthURL string `yaml:"auth_url"`
+	// ApplicationCredentialID or ApplicationCredentialName with the user owning the credential identify the
+	// application credential to authenticate with.
+	ApplicationCredentialID     string         `yaml:"application_credential_id"`
+	ApplicationCredentialName   string         `yaml:"application_credential_name"`
+	ApplicationCredentialSecret flagext.Secret `yaml:"application_credential_secret"`
+	UserID                      string         `yaml:"user_id"`
+	Username                    string         `yaml:"username"`
+	UserDomainName              string         `yaml:"user_domain_name"`
+	// RegionName and Interface select the object-store endpoint from the service catalog. EndpointURL overrides the
+	// endpoint of the catalog.
+	RegionName  string `yaml:"region_name"`
+	Interface   string `yaml:"interface"`
+	EndpointURL string `yaml:"endpoint_url"`
+
+	ContainerName string `yaml:"container_name"`
+	// SegmentContainerName is the container segments of large objects are uploaded to, <container_name>_segments if
+	// empty.
+	SegmentContainerName string `yaml:"segment_container_name"`
+	Prefix               string `yaml:"prefix"`
+	// SegmentSize is the size above which objects are uploaded as static large objects in segments of this size.
+	SegmentSize int `yaml:"segment_size"`
+
+	HedgeRequestsAt   time.Duration `yaml:"hedge_requests_at"`
+	HedgeRequestsUpTo int           `yaml:"hedge_requests_up_to"`
+}
+
+func (cfg *Config) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
+	f.StringVar(&cfg.AuthURL, util.PrefixConfig(prefix, "swift.auth_url"), "", "Keystone v3 url to authenticate with.")
+	f.StringVar(&cfg.ApplicationCredentialID, util.PrefixConfig(prefix, "swift.application_credential_id"), "", "Keystone application credential id.")
+	f.Var(&cfg.ApplicationCredentialSecret, util.PrefixConfig(prefix, "swift.application_credential_secret"), "Keystone application credential secret.")
+	f.StringVar(&cfg.ContainerName, util.PrefixConfig(prefix, "swift.container_name"), "", "Swift container to store blocks in.")
+	f.StringVar(&cfg.Prefix, util.PrefixConfig(prefix, "swift.prefix"), "", "Swift container prefix to store blocks in.")
+	cfg.Interface = "public"
+	cfg.SegmentSize = 64 * 1024 * 1024
+	cfg.HedgeRequestsUpTo = 2
+}
+
+func (cfg *Config) PathMatches(other *Config) bool {
+	return cfg.AuthURL == other.AuthURL && cfg.RegionName == other.RegionName && cfg.EndpointURL == other.EndpointURL &&
+		cfg.ContainerName == other.ContainerName && cfg.Prefix == other.Prefix
+}
+
+func (cfg *Config) segmentContainerName() string {
+	if cfg.SegmentContainerName != "" {
+		return cfg.SegmentContainerName
+	}
+	return cfg.ContainerName + "_segments"
+}
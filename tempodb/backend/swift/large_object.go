@@ -0,0 +1,128 @@
+package swift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// largeObject uploads an object of unknown size. Data is buffered and uploaded in segments of the configured
+// segment size to the segment container. On close the object is written as static large object manifest
+// referencing the segments, or as a regular object if it fits into a single segment.
+type largeObject struct {
+	rw       *readerWriter
+	name     string
+	uploadID string
+
+	buf      []byte
+	segments []sloSegment
+}
+
+// sloSegment is an entry of a static large object manifest.
+type sloSegment struct {
+	Path      string `json:"path"`
+	Etag      string `json:"etag"`
+	SizeBytes int    `json:"size_bytes"`
+}
+
+func (rw *readerWriter) newLargeObject(name string) *largeObject {
+	return &largeObject{
+		rw:       rw,
+		name:     name,
+		uploadID: uuid.New().String(),
+	}
+}
+
+func (rw *readerWriter) writeLargeObject(ctx context.Context, name string, data io.Reader) error {
+	o := rw.newLargeObject(name)
+
+	chunk := make([]byte, rw.cfg.SegmentSize)
+	for {
+		n, err := io.ReadFull(data, chunk)
+		if n > 0 {
+			if err := o.write(ctx, chunk[:n]); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return o.close(ctx)
+}
+
+func (o *largeObject) write(ctx context.Context, p []byte) error {
+	o.buf = append(o.buf, p...)
+
+	segmentSize := o.rw.cfg.SegmentSize
+	uploaded := 0
+	for len(o.buf)-uploaded >= segmentSize {
+		if err := o.uploadSegment(ctx, o.buf[uploaded:uploaded+segmentSize]); err != nil {
+			return err
+		}
+		uploaded += segmentSize
+	}
+	o.buf = append(o.buf[:0], o.buf[uploaded:]...)
+
+	return nil
+}
+
+func (o *largeObject) close(ctx context.Context) error {
+	if len(o.segments) == 0 {
+		return o.rw.put(ctx, o.rw.cfg.ContainerName, o.name, nil, bytes.NewReader(o.buf), int64(len(o.buf)))
+	}
+
+	if len(o.buf) > 0 {
+		if err := o.uploadSegment(ctx, o.buf); err != nil {
+			return err
+		}
+		o.buf = nil
+	}
+
+	manifest, err := json.Marshal(o.segments)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("multipart-manifest", "put")
+	if err := o.rw.put(ctx, o.rw.cfg.ContainerName, o.name, query, bytes.NewReader(manifest), int64(len(manifest))); err != nil {
+		return fmt.Errorf("writing manifest of %s: %w", o.name, err)
+	}
+	return nil
+}
+
+func (o *largeObject) uploadSegment(ctx context.Context, segment []byte) error {
+	container := o.rw.cfg.segmentContainerName()
+	// segments are stored below the object name so they can be found when the object is deleted
+	name := fmt.Sprintf("%s/%s/%08d", o.name, o.uploadID, len(o.segments))
+
+	resp, err := o.rw.do(ctx, o.rw.client, http.MethodPut, container, name, nil, nil, bytes.NewReader(segment), int64(len(segment)))
+	if err != nil {
+		return fmt.Errorf("uploading segment %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+
+	if err := statusError(resp, http.StatusCreated); err != nil {
+		return fmt.Errorf("uploading segment %s: %w", name, err)
+	}
+
+	o.segments = append(o.segments, sloSegment{
+		Path:      "/" + container + "/" + name,
+		Etag:      strings.Trim(resp.Header.Get("Etag"), `"`),
+		SizeBytes: len(segment),
+	})
+	return nil
+}
@@ -0,0 +1,527 @@
+package swift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cristalhq/hedgedhttp"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/instrumentation"
+)
+
+const (
+	// listLimit is the maximum amount of objects returned per listing request, it is the default limit of Swift.
+	listLimit = 10000
+	// lastModifiedFormat is the format of last_modified in container listings, it is in UTC.
+	lastModifiedFormat = "2006-01-02T15:04:05.999999"
+)
+
+type readerWriter struct {
+	cfg          *Config
+	auth         *keystone
+	client       *http.Client
+	hedgedClient *http.Client
+}
+
+var tracer = otel.Tracer("tempodb/backend/swift")
+
+var (
+	_ backend.RawReader = (*readerWriter)(nil)
+	_ backend.RawWriter = (*readerWriter)(nil)
+	_ backend.Compactor = (*readerWriter)(nil)
+)
+
+// NewNoConfirm gets the Swift backend without testing it
+func NewNoConfirm(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	rw, err := internalNew(cfg, false)
+	return rw, rw, rw, err
+}
+
+// New gets the Swift backend
+func New(cfg *Config) (backend.RawReader, backend.RawWriter, backend.Compactor, error) {
+	rw, err := internalNew(cfg, true)
+	return rw, rw, rw, err
+}
+
+func internalNew(cfg *Config, confirm bool) (*readerWriter, error) {
+	if cfg.ContainerName == "" {
+		return nil, errors.New("container_name must be set")
+	}
+	if cfg.SegmentSize <= 0 {
+		return nil, errors.New("segment_size must be greater than 0")
+	}
+
+	client := &http.Client{
+		Transport: instrumentation.NewTransport(http.DefaultTransport.(*http.Transport).Clone()),
+	}
+
+	hedgedClient := client
+	if cfg.HedgeRequestsAt != 0 {
+		transport, stats, err := hedgedhttp.NewRoundTripperAndStats(cfg.HedgeRequestsAt, cfg.HedgeRequestsUpTo, client.Transport)
+		if err != nil {
+			return nil, err
+		}
+		instrumentation.PublishHedgedMetrics(stats)
+		hedgedClient = &http.Client{Transport: transport}
+	}
+
+	auth, err := newKeystone(cfg, client)
+	if err != nil {
+		return nil, err
+	}
+
+	rw := &readerWriter{
+		cfg:          cfg,
+		auth:         auth,
+		client:       client,
+		hedgedClient: hedgedClient,
+	}
+
+	// Check container exists
+	if confirm {
+		resp, err := rw.do(context.Background(), rw.client, http.MethodHead, cfg.ContainerName, "", nil, nil, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("getting container %s: %w", cfg.ContainerName, err)
+		}
+		resp.Body.Close()
+		if err := statusError(resp, http.StatusNoContent, http.StatusOK); err != nil {
+			return nil, fmt.Errorf("getting container %s: %w", cfg.ContainerName, err)
+		}
+	}
+
+	return rw, nil
+}
+
+// Write implements backend.Writer
+func (rw *readerWriter) Write(ctx context.Context, name string, keypath backend.KeyPath, data io.Reader, size int64, _ *backend.CacheInfo) error {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	derivedCtx, span := tracer.Start(ctx, "swift.Write")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("object", name))
+
+	objectName := backend.ObjectFileName(keypath, name)
+
+	var err error
+	if size >= 0 && size <= int64(rw.cfg.SegmentSize) {
+		err = rw.put(derivedCtx, rw.cfg.ContainerName, objectName, nil, data, size)
+	} else {
+		err = rw.writeLargeObject(derivedCtx, objectName, data)
+	}
+	if err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed to write: %w", err)
+	}
+	return nil
+}
+
+// Append implements backend.Writer
+func (rw *readerWriter) Append(ctx context.Context, name string, keypath backend.KeyPath, tracker backend.AppendTracker, buffer []byte) (backend.AppendTracker, error) {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	ctx, span := tracer.Start(ctx, "swift.Append", trace.WithAttributes(
+		attribute.Int("len", len(buffer)),
+	))
+	defer span.End()
+
+	var o *largeObject
+	if tracker == nil {
+		o = rw.newLargeObject(backend.ObjectFileName(keypath, name))
+	} else {
+		o = tracker.(*largeObject)
+	}
+
+	if err := o.write(ctx, buffer); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// CloseAppend implements backend.Writer
+func (rw *readerWriter) CloseAppend(ctx context.Context, tracker backend.AppendTracker) error {
+	if tracker == nil {
+		return nil
+	}
+
+	o := tracker.(*largeObject)
+	return o.close(ctx)
+}
+
+// Delete implements backend.Writer
+func (rw *readerWriter) Delete(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) error {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	objectName := backend.ObjectFileName(keypath, name)
+
+	if err := rw.delete(ctx, rw.cfg.ContainerName, objectName); err != nil {
+		return err
+	}
+	return rw.deleteSegments(ctx, objectName+"/")
+}
+
+// List implements backend.Reader
+func (rw *readerWriter) List(ctx context.Context, keypath backend.KeyPath) ([]string, error) {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	prefix := path.Join(keypath...)
+	if len(prefix) > 0 {
+		prefix = prefix + "/"
+	}
+
+	var objects []string
+	err := rw.list(ctx, rw.cfg.ContainerName, prefix, "/", func(e listEntry) {
+		if e.Subdir == "" {
+			return
+		}
+		objects = append(objects, strings.TrimSuffix(strings.TrimPrefix(e.Subdir, prefix), "/"))
+	})
+	if err != nil {
+		return nil, fmt.Errorf("iterating blocks: %w", err)
+	}
+
+	return objects, nil
+}
+
+// ListBlocks implements backend.Reader
+func (rw *readerWriter) ListBlocks(ctx context.Context, tenant string) ([]uuid.UUID, []uuid.UUID, error) {
+	ctx, span := tracer.Start(ctx, "readerWriter.ListBlocks")
+	defer span.End()
+
+	keypath := backend.KeyPathWithPrefix(backend.KeyPath{tenant}, rw.cfg.Prefix)
+	prefix := path.Join(keypath...)
+	if len(prefix) > 0 {
+		prefix += "/"
+	}
+
+	var (
+		blockIDs          = make([]uuid.UUID, 0, 1000)
+		compactedBlockIDs = make([]uuid.UUID, 0, 1000)
+	)
+
+	err := rw.list(ctx, rw.cfg.ContainerName, prefix, "", func(e listEntry) {
+		// ie: <blockID>/meta.json
+		parts := strings.Split(strings.TrimPrefix(e.Name, prefix), "/")
+		if len(parts) != 2 {
+			return
+		}
+
+		switch parts[1] {
+		case backend.MetaName, backend.CompactedMetaName:
+		default:
+			return
+		}
+
+		id, err := uuid.Parse(parts[0])
+		if err != nil {
+			return
+		}
+
+		switch parts[1] {
+		case backend.MetaName:
+			blockIDs = append(blockIDs, id)
+		case backend.CompactedMetaName:
+			compactedBlockIDs = append(compactedBlockIDs, id)
+		}
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("iterating blocks: %w", err)
+	}
+
+	return blockIDs, compactedBlockIDs, nil
+}
+
+// Find implements backend.Reader
+func (rw *readerWriter) Find(ctx context.Context, keypath backend.KeyPath, f backend.FindFunc) error {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	prefix := path.Join(keypath...)
+	if len(prefix) > 0 {
+		prefix = prefix + "/"
+	}
+
+	err := rw.list(ctx, rw.cfg.ContainerName, prefix, "", func(e listEntry) {
+		f(backend.FindMatch{
+			Key:      e.Name,
+			Modified: e.lastModified(),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("iterating objects: %w", err)
+	}
+
+	return nil
+}
+
+// Read implements backend.Reader
+func (rw *readerWriter) Read(ctx context.Context, name string, keypath backend.KeyPath, _ *backend.CacheInfo) (io.ReadCloser, int64, error) {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	derivedCtx, span := tracer.Start(ctx, "swift.Read")
+	defer span.End()
+
+	span.SetAttributes(attribute.String("object", name))
+
+	b, _, err := rw.readAll(derivedCtx, backend.ObjectFileName(keypath, name))
+	if err != nil {
+		span.SetStatus(codes.Error, "")
+		return nil, 0, err
+	}
+	return io.NopCloser(bytes.NewReader(b)), int64(len(b)), nil
+}
+
+// ReadRange implements backend.Reader
+func (rw *readerWriter) ReadRange(ctx context.Context, name string, keypath backend.KeyPath, offset uint64, buffer []byte, _ *backend.CacheInfo) error {
+	keypath = backend.KeyPathWithPrefix(keypath, rw.cfg.Prefix)
+	derivedCtx, span := tracer.Start(ctx, "swift.ReadRange", trace.WithAttributes(
+		attribute.Int("len", len(buffer)),
+		attribute.Int64("offset", int64(offset)),
+	))
+	defer span.End()
+
+	err := rw.readRange(derivedCtx, backend.ObjectFileName(keypath, name), offset, buffer)
+	if err != nil {
+		span.SetStatus(codes.Error, "")
+	}
+	return err
+}
+
+// Shutdown implements backend.Reader
+func (rw *readerWriter) Shutdown() {
+}
+
+func (rw *readerWriter) readAll(ctx context.Context, name string) ([]byte, http.Header, error) {
+	resp, err := rw.do(ctx, rw.hedgedClient, http.MethodGet, rw.cfg.ContainerName, name, nil, nil, nil, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := statusError(resp, http.StatusOK); err != nil {
+		return nil, nil, err
+	}
+
+	buf, err := tempo_io.ReadAllWithEstimate(resp.Body, resp.ContentLength)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return buf, resp.Header, nil
+}
+
+func (rw *readerWriter) readRange(ctx context.Context, name string, offset uint64, buffer []byte) error {
+	if len(buffer) == 0 {
+		return nil
+	}
+
+	header := http.Header{}
+	header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+uint64(len(buffer))-1))
+
+	resp, err := rw.do(ctx, rw.hedgedClient, http.MethodGet, rw.cfg.ContainerName, name, nil, header, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := statusError(resp, http.StatusPartialContent); err != nil {
+		return err
+	}
+
+	_, err = io.ReadFull(resp.Body, buffer)
+	return err
+}
+
+func (rw *readerWriter) put(ctx context.Context, container, name string, query url.Values, data io.Reader, size int64) error {
+	resp, err := rw.do(ctx, rw.client, http.MethodPut, container, name, query, nil, data, size)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return statusError(resp, http.StatusCreated)
+}
+
+func (rw *readerWriter) delete(ctx context.Context, container, name string) error {
+	resp, err := rw.do(ctx, rw.client, http.MethodDelete, container, name, nil, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return statusError(resp, http.StatusNoContent)
+}
+
+// deleteSegments deletes the segments of all large objects that start with prefix.
+func (rw *readerWriter) deleteSegments(ctx context.Context, prefix string) error {
+	var segments []string
+	err := rw.list(ctx, rw.cfg.segmentContainerName(), prefix, "", func(e listEntry) {
+		segments = append(segments, e.Name)
+	})
+	// the segment container only exists if large objects were written
+	if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+		return err
+	}
+
+	for _, segment := range segments {
+		if err := rw.delete(ctx, rw.cfg.segmentContainerName(), segment); err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+type listEntry struct {
+	Name         string `json:"name"`
+	Subdir       string `json:"subdir"`
+	Bytes        int64  `json:"bytes"`
+	LastModified string `json:"last_modified"`
+}
+
+func (e listEntry) lastModified() time.Time {
+	t, _ := time.ParseInLocation(lastModifiedFormat, e.LastModified, time.UTC)
+	return t
+}
+
+// list calls f for every object and, if delimiter is set, every pseudo-directory of the container that starts with
+// prefix.
+func (rw *readerWriter) list(ctx context.Context, container, prefix, delimiter string, f func(listEntry)) error {
+	query := url.Values{}
+	query.Set("format", "json")
+	query.Set("limit", fmt.Sprint(listLimit))
+	if prefix != "" {
+		query.Set("prefix", prefix)
+	}
+	if delimiter != "" {
+		query.Set("delimiter", delimiter)
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		entries, err := rw.listPage(ctx, container, query)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range entries {
+			f(e)
+		}
+
+		if len(entries) < listLimit {
+			return nil
+		}
+
+		last := entries[len(entries)-1]
+		if last.Subdir != "" {
+			query.Set("marker", last.Subdir)
+		} else {
+			query.Set("marker", last.Name)
+		}
+	}
+}
+
+func (rw *readerWriter) listPage(ctx context.Context, container string, query url.Values) ([]listEntry, error) {
+	resp, err := rw.do(ctx, rw.client, http.MethodGet, container, "", query, nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// an empty listing is returned without a body
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+	if err := statusError(resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+
+	var entries []listEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decoding listing of container %s: %w", container, err)
+	}
+	return entries, nil
+}
+
+// do executes a request against the object-store. The request is retried once with a new token if the token was
+// rejected and the body can be replayed.
+func (rw *readerWriter) do(ctx context.Context, client *http.Client, method, container, name string, query url.Values, header http.Header, body io.Reader, size int64) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		token, endpoint, err := rw.auth.get(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		u := endpoint + "/" + url.PathEscape(container)
+		if name != "" {
+			u += "/" + escapeObjectName(name)
+		}
+		if len(query) > 0 {
+			u += "?" + query.Encode()
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, u, body)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		req.Header.Set("X-Auth-Token", token)
+		if body != nil {
+			req.ContentLength = size
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusUnauthorized || attempt > 0 || (body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+		rw.auth.invalidate(token)
+		if body != nil {
+			if body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+func escapeObjectName(name string) string {
+	parts := strings.Split(name, "/")
+	for i, p := range parts {
+		parts[i] = url.PathEscape(p)
+	}
+	return strings.Join(parts, "/")
+}
+
+// statusError returns nil if the status of the response is one of the expected statuses.
+func statusError(resp *http.Response, expected ...int) error {
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return nil
+		}
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return backend.ErrDoesNotExist
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("unexpected status %s: %s", resp.Status, msg)
+}
@@ -0,0 +1,433 @@
+package swift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafana/dskit/flagext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestReadWrite(t *testing.T) {
+	server := newFakeSwift(t)
+	r, w, _, err := New(server.config())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	data := []byte("hello swift")
+
+	require.NoError(t, w.Write(ctx, "object", backend.KeyPath{"tenant", "block"}, bytes.NewReader(data), int64(len(data)), nil))
+	assert.Contains(t, server.objects, "tempo/prefix/tenant/block/object")
+
+	rc, size, err := r.Read(ctx, "object", backend.KeyPath{"tenant", "block"}, nil)
+	require.NoError(t, err)
+	actual, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, data, actual)
+	assert.Equal(t, int64(len(data)), size)
+
+	buffer := make([]byte, 5)
+	require.NoError(t, r.ReadRange(ctx, "object", backend.KeyPath{"tenant", "block"}, 6, buffer, nil))
+	assert.Equal(t, []byte("swift"), buffer)
+
+	_, _, err = r.Read(ctx, "missing", backend.KeyPath{"tenant", "block"}, nil)
+	assert.ErrorIs(t, err, backend.ErrDoesNotExist)
+
+	require.NoError(t, w.Delete(ctx, "object", backend.KeyPath{"tenant", "block"}, nil))
+	_, _, err = r.Read(ctx, "object", backend.KeyPath{"tenant", "block"}, nil)
+	assert.ErrorIs(t, err, backend.ErrDoesNotExist)
+}
+
+func TestLargeObjects(t *testing.T) {
+	server := newFakeSwift(t)
+	cfg := server.config()
+	cfg.SegmentSize = 4
+	r, w, _, err := New(cfg)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	keypath := backend.KeyPath{"tenant", "block"}
+
+	// append
+	var tracker backend.AppendTracker
+	for _, s := range []string{"ab", "cdefghi", "j"} {
+		tracker, err = w.Append(ctx, "appended", keypath, tracker, []byte(s))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.CloseAppend(ctx, tracker))
+	assert.Len(t, server.segments("appended"), 3)
+
+	rc, _, err := r.Read(ctx, "appended", keypath, nil)
+	require.NoError(t, err)
+	actual, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefghij", string(actual))
+
+	buffer := make([]byte, 4)
+	require.NoError(t, r.ReadRange(ctx, "appended", keypath, 3, buffer, nil))
+	assert.Equal(t, "defg", string(buffer))
+
+	// write
+	require.NoError(t, w.Write(ctx, "written", keypath, strings.NewReader("0123456789"), 10, nil))
+	assert.Len(t, server.segments("written"), 3)
+
+	rc, _, err = r.Read(ctx, "written", keypath, nil)
+	require.NoError(t, err)
+	actual, err = io.ReadAll(rc)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(actual))
+
+	// small objects are written without segments
+	tracker, err = w.Append(ctx, "small", keypath, nil, []byte("abc"))
+	require.NoError(t, err)
+	require.NoError(t, w.CloseAppend(ctx, tracker))
+	assert.Empty(t, server.segments("small"))
+	assert.Equal(t, "abc", string(server.objects["tempo/prefix/tenant/block/small"].data))
+
+	// segments are deleted with the object
+	require.NoError(t, w.Delete(ctx, "written", keypath, nil))
+	assert.Empty(t, server.segments("written"))
+	assert.Len(t, server.segments("appended"), 3)
+}
+
+func TestListBlocks(t *testing.T) {
+	server := newFakeSwift(t)
+	r, w, c, err := New(server.config())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	blockID := uuid.New()
+	compactedBlockID := uuid.New()
+
+	for _, id := range []uuid.UUID{blockID, compactedBlockID} {
+		meta := backend.NewBlockMeta("tenant", id, "v1", backend.EncNone, "")
+		require.NoError(t, backend.NewWriter(w).WriteBlockMeta(ctx, meta))
+		require.NoError(t, w.Write(ctx, "data", backend.KeyPathForBlock(id, "tenant"), strings.NewReader("data"), 4, nil))
+	}
+	require.NoError(t, c.MarkBlockCompacted(compactedBlockID, "tenant"))
+
+	tenants, err := r.List(ctx, backend.KeyPath{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant"}, tenants)
+
+	blockIDs, compactedBlockIDs, err := r.ListBlocks(ctx, "tenant")
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{blockID}, blockIDs)
+	assert.Equal(t, []uuid.UUID{compactedBlockID}, compactedBlockIDs)
+
+	compactedMeta, err := c.CompactedBlockMeta(compactedBlockID, "tenant")
+	require.NoError(t, err)
+	assert.Equal(t, compactedBlockID, (uuid.UUID)(compactedMeta.BlockID))
+	assert.False(t, compactedMeta.CompactedTime.IsZero())
+
+	var found []string
+	require.NoError(t, r.Find(ctx, backend.KeyPathForBlock(blockID, "tenant"), func(m backend.FindMatch) {
+		found = append(found, m.Key)
+		assert.False(t, m.Modified.IsZero())
+	}))
+	assert.ElementsMatch(t, []string{
+		"prefix/tenant/" + blockID.String() + "/data",
+		"prefix/tenant/" + blockID.String() + "/meta.json",
+	}, found)
+
+	require.NoError(t, c.ClearBlock(compactedBlockID, "tenant"))
+	blockIDs, compactedBlockIDs, err = r.ListBlocks(ctx, "tenant")
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{blockID}, blockIDs)
+	assert.Empty(t, compactedBlockIDs)
+}
+
+func TestListPagination(t *testing.T) {
+	server := newFakeSwift(t)
+	r, w, _, err := New(server.config())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < listLimit+10; i++ {
+		server.objects[fmt.Sprintf("tempo/prefix/tenant/%05d", i)] = &fakeObject{}
+	}
+	require.NoError(t, w.Write(ctx, "object", backend.KeyPath{"other"}, strings.NewReader("a"), 1, nil))
+
+	count := 0
+	require.NoError(t, r.Find(ctx, backend.KeyPath{"tenant"}, func(backend.FindMatch) { count++ }))
+	assert.Equal(t, listLimit+10, count)
+}
+
+func TestAuth(t *testing.T) {
+	server := newFakeSwift(t)
+
+	// authenticate by name
+	cfg := server.config()
+	cfg.ApplicationCredentialID = ""
+	cfg.ApplicationCredentialName = "tempo"
+	cfg.Username = "user"
+	cfg.UserDomainName = "Default"
+	_, w, _, err := New(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 1, server.authCount)
+
+	// authenticate again after the token was revoked
+	server.mtx.Lock()
+	server.tokens = map[string]struct{}{}
+	server.mtx.Unlock()
+
+	require.NoError(t, w.Write(context.Background(), "object", backend.KeyPath{"tenant"}, bytes.NewReader([]byte("a")), 1, nil))
+	assert.Equal(t, 2, server.authCount)
+
+	// invalid secret
+	cfg = server.config()
+	cfg.ApplicationCredentialSecret = flagext.SecretWithValue("wrong")
+	_, _, _, err = New(cfg)
+	assert.ErrorContains(t, err, "401")
+
+	// missing container
+	cfg = server.config()
+	cfg.ContainerName = "missing"
+	_, _, _, err = New(cfg)
+	assert.ErrorIs(t, err, backend.ErrDoesNotExist)
+}
+
+type fakeObject struct {
+	data     []byte
+	manifest []sloSegment
+	modified time.Time
+}
+
+// fakeSwift is a minimal Keystone and Swift server keeping objects in memory by <container>/<object>.
+type fakeSwift struct {
+	t      *testing.T
+	server *httptest.Server
+
+	mtx       sync.Mutex
+	objects   map[string]*fakeObject
+	tokens    map[string]struct{}
+	authCount int
+}
+
+func newFakeSwift(t *testing.T) *fakeSwift {
+	f := &fakeSwift{
+		t:       t,
+		objects: map[string]*fakeObject{},
+		tokens:  map[string]struct{}{},
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeSwift) config() *Config {
+	cfg := &Config{}
+	cfg.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+	cfg.AuthURL = f.server.URL + "/v3"
+	cfg.ApplicationCredentialID = "id"
+	cfg.ApplicationCredentialSecret = flagext.SecretWithValue("secret")
+	cfg.RegionName = "RegionOne"
+	cfg.ContainerName = "tempo"
+	cfg.Prefix = "prefix"
+	return cfg
+}
+
+func (f *fakeSwift) segments(name string) []string {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	var segments []string
+	for k := range f.objects {
+		if strings.HasPrefix(k, "tempo_segments/prefix/tenant/block/"+name+"/") {
+			segments = append(segments, k)
+		}
+	}
+	return segments
+}
+
+func (f *fakeSwift) handle(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if r.URL.Path == "/v3/auth/tokens" {
+		f.authenticate(w, r)
+		return
+	}
+
+	if _, ok := f.tokens[r.Header.Get("X-Auth-Token")]; !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/v1/AUTH_test/")
+	require.True(f.t, ok, r.URL.Path)
+
+	container, object, _ := strings.Cut(path, "/")
+	if container != "tempo" && container != "tempo_segments" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if object == "" {
+		switch r.Method {
+		case http.MethodHead:
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			f.list(w, r, container)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		f.put(w, r, path)
+	case http.MethodGet:
+		f.get(w, r, path)
+	case http.MethodDelete:
+		if _, ok := f.objects[path]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		delete(f.objects, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeSwift) authenticate(w http.ResponseWriter, r *http.Request) {
+	var req authRequest
+	require.NoError(f.t, json.NewDecoder(r.Body).Decode(&req))
+
+	cred := req.Auth.Identity.ApplicationCredential
+	if cred.Secret != "secret" || (cred.ID == "" && (cred.Name == "" || cred.User == nil)) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	f.authCount++
+	token := uuid.New().String()
+	f.tokens[token] = struct{}{}
+
+	w.Header().Set("X-Subject-Token", token)
+	w.WriteHeader(http.StatusCreated)
+	fmt.Fprintf(w, `{"token": {"expires_at": %q, "catalog": [
+		{"type": "identity", "endpoints": [{"interface": "public", "region": "RegionOne", "url": "http://wrong"}]},
+		{"type": "object-store", "endpoints": [
+			{"interface": "public", "region": "RegionTwo", "url": "http://wrong"},
+			{"interface": "internal", "region": "RegionOne", "url": "http://wrong"},
+			{"interface": "public", "region": "RegionOne", "url": "%s/v1/AUTH_test"}
+		]}
+	]}}`, time.Now().Add(time.Hour).Format(time.RFC3339), f.server.URL)
+}
+
+func (f *fakeSwift) put(w http.ResponseWriter, r *http.Request, path string) {
+	o := &fakeObject{modified: time.Now()}
+
+	if src := r.Header.Get("X-Copy-From"); src != "" {
+		srcObject, ok := f.objects[strings.TrimPrefix(src, "/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		o.data = srcObject.data
+	} else {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(f.t, err)
+		require.Equal(f.t, r.ContentLength, int64(len(data)))
+
+		if r.URL.Query().Get("multipart-manifest") == "put" {
+			require.NoError(f.t, json.Unmarshal(data, &o.manifest))
+		} else {
+			o.data = data
+		}
+	}
+
+	f.objects[path] = o
+	w.Header().Set("Etag", `"etag"`)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (f *fakeSwift) get(w http.ResponseWriter, r *http.Request, path string) {
+	o, ok := f.objects[path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	data := o.data
+	for _, s := range o.manifest {
+		segment, ok := f.objects[strings.TrimPrefix(s.Path, "/")]
+		require.True(f.t, ok, s.Path)
+		require.Equal(f.t, s.SizeBytes, len(segment.data))
+		data = append(data, segment.data...)
+	}
+
+	w.Header().Set("Last-Modified", o.modified.UTC().Format(http.TimeFormat))
+
+	if rng := r.Header.Get("Range"); rng != "" {
+		var start, end int
+		_, err := fmt.Sscanf(rng, "bytes=%d-%d", &start, &end)
+		require.NoError(f.t, err)
+		w.Header().Set("Content-Length", strconv.Itoa(end-start+1))
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write(data[start : end+1])
+		return
+	}
+
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	_, _ = w.Write(data)
+}
+
+func (f *fakeSwift) list(w http.ResponseWriter, r *http.Request, container string) {
+	query := r.URL.Query()
+	prefix, delimiter, marker := query.Get("prefix"), query.Get("delimiter"), query.Get("marker")
+	limit, err := strconv.Atoi(query.Get("limit"))
+	require.NoError(f.t, err)
+
+	var names []string
+	for k := range f.objects {
+		if name, ok := strings.CutPrefix(k, container+"/"); ok && strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	entries := []listEntry{}
+	for _, name := range names {
+		entry := listEntry{Name: name, LastModified: f.objects[container+"/"+name].modified.UTC().Format(lastModifiedFormat)}
+		if delimiter != "" {
+			if i := strings.Index(name[len(prefix):], delimiter); i >= 0 {
+				entry = listEntry{Subdir: name[:len(prefix)+i+1]}
+			}
+		}
+
+		key := entry.Name + entry.Subdir
+		if key <= marker || (len(entries) > 0 && entries[len(entries)-1].Subdir == key) {
+			continue
+		}
+		if len(entries) == limit {
+			break
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	require.NoError(f.t, json.NewEncoder(w).Encode(entries))
+}
@@ -0,0 +1,136 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
+)
+
+const (
+	// TombstonesPrefix is the keypath beneath a tenant where deletion requests are stored.
+	TombstonesPrefix = "_tombstones"
+
+	tombstoneExtension = ".json"
+)
+
+// Tombstone is a request to delete traces from a tenant's blocks. Traces are selected by ID or by a TraceQL
+// query. Compactors rewrite every block the tombstone applies to and record their progress in the tombstone
+// until it is completed.
+type Tombstone struct {
+	ID       string `json:"id"`
+	TenantID string `json:"tenantID"`
+
+	// TraceIDs are hex encoded IDs of the traces to delete.
+	TraceIDs []string `json:"traceIDs,omitempty"`
+	// Query is a TraceQL query. Every trace with a matching spanset is deleted.
+	Query string `json:"query,omitempty"`
+	// Start and End restrict the tombstone to blocks that overlap the range. They are required with a query.
+	Start time.Time `json:"start,omitempty"`
+	End   time.Time `json:"end,omitempty"`
+
+	CreatedAt   time.Time `json:"createdAt"`
+	CompletedAt time.Time `json:"completedAt,omitempty"`
+
+	// CheckedBlocks are the blocks that are known to no longer contain any of the deleted traces.
+	CheckedBlocks   []UUID `json:"checkedBlocks,omitempty"`
+	BlocksRewritten int    `json:"blocksRewritten"`
+	TracesDeleted   int    `json:"tracesDeleted"`
+}
+
+// NewTombstone returns a new pending tombstone for the tenant.
+func NewTombstone(tenantID string, traceIDs []string, query string, start, end time.Time) *Tombstone {
+	return &Tombstone{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		TraceIDs:  traceIDs,
+		Query:     query,
+		Start:     start,
+		End:       end,
+		CreatedAt: time.Now(),
+	}
+}
+
+// Validate returns an error if the tombstone can't be applied.
+func (t *Tombstone) Validate() error {
+	if t.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+	if _, err := uuid.Parse(t.ID); err != nil {
+		return fmt.Errorf("invalid tombstone id %s: %w", t.ID, err)
+	}
+
+	if len(t.TraceIDs) == 0 && t.Query == "" {
+		return errors.New("tombstone requires trace ids or a query")
+	}
+	if len(t.TraceIDs) > 0 && t.Query != "" {
+		return errors.New("tombstone can't have both trace ids and a query")
+	}
+
+	for _, id := range t.TraceIDs {
+		if _, err := util.HexStringToTraceID(id); err != nil {
+			return fmt.Errorf("invalid trace id %s: %w", id, err)
+		}
+	}
+
+	if t.Query != "" {
+		if _, err := traceql.Parse(t.Query); err != nil {
+			return fmt.Errorf("invalid query: %w", err)
+		}
+		if t.Start.IsZero() || t.End.IsZero() {
+			return errors.New("tombstone with a query requires a start and an end")
+		}
+	}
+
+	if !t.End.IsZero() && t.End.Before(t.Start) {
+		return errors.New("tombstone end must not be before start")
+	}
+
+	return nil
+}
+
+// Completed returns true if the tombstone has been applied to all blocks.
+func (t *Tombstone) Completed() bool {
+	return !t.CompletedAt.IsZero()
+}
+
+// Overlaps returns true if the block may contain traces deleted by the tombstone.
+func (t *Tombstone) Overlaps(meta *BlockMeta) bool {
+	if !t.Start.IsZero() && meta.EndTime.Before(t.Start) {
+		return false
+	}
+	if !t.End.IsZero() && meta.StartTime.After(t.End) {
+		return false
+	}
+	return true
+}
+
+// KeyPathForTombstones returns the keypath of the tenant's tombstones.
+func KeyPathForTombstones(tenantID string) KeyPath {
+	return []string{tenantID, TombstonesPrefix}
+}
+
+// TombstoneFileName returns the object name of the tombstone.
+func TombstoneFileName(id string) string {
+	return id + tombstoneExtension
+}
+
+// tombstoneIDFromKey returns the id of the tombstone stored under the key or false if the key isn't a tombstone.
+func tombstoneIDFromKey(key string) (string, bool) {
+	idx := strings.LastIndex(key, "/")
+	name := key[idx+1:]
+
+	if !strings.HasSuffix(name, tombstoneExtension) {
+		return "", false
+	}
+	id := strings.TrimSuffix(name, tombstoneExtension)
+	if _, err := uuid.Parse(id); err != nil {
+		return "", false
+	}
+	return id, true
+}
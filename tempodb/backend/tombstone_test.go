@@ -0,0 +1,97 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTombstoneValidate(t *testing.T) {
+	now := time.Now()
+
+	tcs := []struct {
+		name      string
+		tombstone *Tombstone
+		expected  string
+	}{
+		{
+			name:      "trace ids",
+			tombstone: NewTombstone("test", []string{"1234", "abcdef"}, "", time.Time{}, time.Time{}),
+		},
+		{
+			name:      "query",
+			tombstone: NewTombstone("test", nil, `{ resource.service.name = "foo" }`, now.Add(-time.Hour), now),
+		},
+		{
+			name:      "no tenant",
+			tombstone: NewTombstone("", []string{"1234"}, "", time.Time{}, time.Time{}),
+			expected:  ErrEmptyTenantID.Error(),
+		},
+		{
+			name:      "nothing to delete",
+			tombstone: NewTombstone("test", nil, "", time.Time{}, time.Time{}),
+			expected:  "tombstone requires trace ids or a query",
+		},
+		{
+			name:      "trace ids and query",
+			tombstone: NewTombstone("test", []string{"1234"}, "{ }", now.Add(-time.Hour), now),
+			expected:  "tombstone can't have both trace ids and a query",
+		},
+		{
+			name:      "invalid trace id",
+			tombstone: NewTombstone("test", []string{"xyz"}, "", time.Time{}, time.Time{}),
+			expected:  "invalid trace id xyz: trace IDs can only contain hex characters: invalid character 'x' at position 1",
+		},
+		{
+			name:      "invalid query",
+			tombstone: NewTombstone("test", nil, "{ .foo = }", now.Add(-time.Hour), now),
+			expected:  "invalid query: parse error at line 1, col 10: syntax error: unexpected }",
+		},
+		{
+			name:      "query without bounds",
+			tombstone: NewTombstone("test", nil, "{ }", time.Time{}, now),
+			expected:  "tombstone with a query requires a start and an end",
+		},
+		{
+			name:      "end before start",
+			tombstone: NewTombstone("test", []string{"1234"}, "", now, now.Add(-time.Hour)),
+			expected:  "tombstone end must not be before start",
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.tombstone.Validate()
+			if tc.expected == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expected)
+		})
+	}
+}
+
+func TestTombstoneOverlaps(t *testing.T) {
+	now := time.Now()
+	meta := &BlockMeta{StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour)}
+
+	require.True(t, (&Tombstone{}).Overlaps(meta))
+	require.True(t, (&Tombstone{Start: now.Add(-90 * time.Minute), End: now}).Overlaps(meta))
+	require.True(t, (&Tombstone{End: now.Add(-90 * time.Minute)}).Overlaps(meta))
+	require.False(t, (&Tombstone{Start: now.Add(-30 * time.Minute)}).Overlaps(meta))
+	require.False(t, (&Tombstone{Start: now.Add(-4 * time.Hour), End: now.Add(-3 * time.Hour)}).Overlaps(meta))
+}
+
+func TestTombstoneIDFromKey(t *testing.T) {
+	tombstone := NewTombstone("test", []string{"1234"}, "", time.Time{}, time.Time{})
+
+	id, ok := tombstoneIDFromKey(ObjectFileName(KeyPathForTombstones("test"), TombstoneFileName(tombstone.ID)))
+	require.True(t, ok)
+	require.Equal(t, tombstone.ID, id)
+
+	_, ok = tombstoneIDFromKey("test/_tombstones/foo.json")
+	require.False(t, ok)
+	_, ok = tombstoneIDFromKey("test/" + tombstone.ID + "/meta.json")
+	require.False(t, ok)
+}
@@ -146,7 +146,7 @@ func (p *Poller) Do(previous *List) (PerTenant, PerTenantCompacted, error) {
 		backend.ClearDedicatedColumns()
 	}()
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(backend.WithIOPriority(context.Background(), backend.IOPriorityPolling))
 	defer cancel()
 
 	ctx, span := tracer.Start(ctx, "Poller.Do")
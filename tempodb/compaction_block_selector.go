@@ -32,8 +32,13 @@ type timeWindowBlockSelector struct {
 	MaxCompactionRange   time.Duration // Size of the time window - say 6 hours
 	MaxCompactionObjects int           // maximum size of compacted objects
 	MaxBlockBytes        uint64        // maximum block size, estimate
+	MemoryBudget         JobMemoryBudget
 
 	entries []timeWindowBlockEntry
+
+	// overMemoryBudget is the number of blocks that were not compacted because compacting them with the next
+	// block exceeds the memory budget
+	overMemoryBudget int
 }
 
 type timeWindowBlockEntry struct {
@@ -45,13 +50,14 @@ type timeWindowBlockEntry struct {
 
 var _ (CompactionBlockSelector) = (*timeWindowBlockSelector)(nil)
 
-func newTimeWindowBlockSelector(blocklist []*backend.BlockMeta, maxCompactionRange time.Duration, maxCompactionObjects int, maxBlockBytes uint64, minInputBlocks, maxInputBlocks int) CompactionBlockSelector {
+func newTimeWindowBlockSelector(blocklist []*backend.BlockMeta, maxCompactionRange time.Duration, maxCompactionObjects int, maxBlockBytes uint64, memoryBudget JobMemoryBudget, minInputBlocks, maxInputBlocks int) CompactionBlockSelector {
 	twbs := &timeWindowBlockSelector{
 		MinInputBlocks:       minInputBlocks,
 		MaxInputBlocks:       maxInputBlocks,
 		MaxCompactionRange:   maxCompactionRange,
 		MaxCompactionObjects: maxCompactionObjects,
 		MaxBlockBytes:        maxBlockBytes,
+		MemoryBudget:         memoryBudget,
 	}
 
 	now := time.Now()
@@ -133,6 +139,13 @@ func (twbs *timeWindowBlockSelector) BlocksToCompact() ([]*backend.BlockMeta, st
 					len(stripe) <= twbs.MaxInputBlocks &&
 					totalObjects(stripe) <= twbs.MaxCompactionObjects &&
 					totalSize(stripe) <= twbs.MaxBlockBytes {
+					// a job over the memory budget is compacted with the blocks chosen so far
+					if !twbs.MemoryBudget.fits(entryMetas(stripe)) {
+						if len(chosen) == 0 {
+							twbs.overMemoryBudget++
+						}
+						break
+					}
 					chosen = stripe
 				} else {
 					break
@@ -150,17 +163,20 @@ func (twbs *timeWindowBlockSelector) BlocksToCompact() ([]*backend.BlockMeta, st
 		// did we find enough blocks?
 		if len(chosen) >= twbs.MinInputBlocks {
 
-			compactBlocks := make([]*backend.BlockMeta, 0)
-			for _, e := range chosen {
-				compactBlocks = append(compactBlocks, e.meta)
-			}
-
-			return compactBlocks, chosen[0].hash
+			return entryMetas(chosen), chosen[0].hash
 		}
 	}
 	return nil, ""
 }
 
+func entryMetas(entries []timeWindowBlockEntry) []*backend.BlockMeta {
+	metas := make([]*backend.BlockMeta, 0, len(entries))
+	for _, e := range entries {
+		metas = append(metas, e.meta)
+	}
+	return metas
+}
+
 func totalObjects(entries []timeWindowBlockEntry) int {
 	totalObjects := 0
 	for _, b := range entries {
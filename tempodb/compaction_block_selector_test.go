@@ -834,7 +834,7 @@ func TestTimeWindowBlockSelectorBlocksToCompact(t *testing.T) {
 				maxSize = tt.maxBlockBytes
 			}
 
-			selector := newTimeWindowBlockSelector(tt.blocklist, time.Second, 100, maxSize, min, max)
+			selector := newTimeWindowBlockSelector(tt.blocklist, time.Second, 100, maxSize, JobMemoryBudget{}, min, max)
 
 			actual, hash := selector.BlocksToCompact()
 			assert.Equal(t, tt.expected, actual)
@@ -0,0 +1,191 @@
+package tempodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/tempo/pkg/model/trace"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+var errConversionUnsupported = errors.New("block version doesn't support iterating traces")
+
+// convertBlocks compacts the blocks into a single block of the given version. The compactor of an encoding can only
+// read blocks of its own version, so the traces are read through the generic trace iterators and combined here
+// instead.
+func (rw *readerWriter) convertBlocks(ctx context.Context, blockMetas []*backend.BlockMeta, tenantID, version string, opts common.CompactionOptions) ([]*backend.BlockMeta, error) {
+	enc, err := encoding.FromVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	iter := newCombiningIterator(len(blockMetas), opts)
+	defer iter.Close()
+
+	inMeta := &backend.BlockMeta{
+		TenantID:          tenantID,
+		BlockID:           backend.NewUUID(),
+		DataEncoding:      blockMetas[0].DataEncoding,
+		DedicatedColumns:  blockMetas[0].DedicatedColumns,
+		ReplicationFactor: blockMetas[0].ReplicationFactor,
+		Encoding:          opts.BlockConfig.Encoding,
+	}
+
+	for _, meta := range blockMetas {
+		block, err := encoding.OpenBlock(meta, rw.r)
+		if err != nil {
+			return nil, fmt.Errorf("error opening block %s: %w", meta.BlockID.String(), err)
+		}
+
+		iterable, ok := block.(common.TraceIterable)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errConversionUnsupported, meta.Version)
+		}
+
+		it, err := iterable.TraceIterator(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error creating iterator for block %s: %w", meta.BlockID.String(), err)
+		}
+		iter.iters = append(iter.iters, it)
+
+		inMeta.TotalObjects += meta.TotalObjects
+		if meta.CompactionLevel >= inMeta.CompactionLevel {
+			inMeta.CompactionLevel = meta.CompactionLevel + 1
+		}
+		if inMeta.StartTime.IsZero() || meta.StartTime.Before(inMeta.StartTime) {
+			inMeta.StartTime = meta.StartTime
+		}
+		if meta.EndTime.After(inMeta.EndTime) {
+			inMeta.EndTime = meta.EndTime
+		}
+	}
+
+	iter.compactionLevel = int(inMeta.CompactionLevel) - 1
+
+	cfg := opts.BlockConfig
+	cfg.Version = version
+
+	newMeta, err := enc.CreateBlock(ctx, &cfg, inMeta, iter, rw.r, rw.w)
+	if err != nil {
+		return nil, fmt.Errorf("error creating block: %w", err)
+	}
+
+	// the encodings create blocks at compaction level 0
+	newMeta.CompactionLevel = inMeta.CompactionLevel
+	if err := rw.w.WriteBlockMeta(ctx, newMeta); err != nil {
+		return nil, fmt.Errorf("error writing block meta: %w", err)
+	}
+
+	if opts.ObjectsWritten != nil {
+		opts.ObjectsWritten(int(inMeta.CompactionLevel)-1, iter.written)
+	}
+	if opts.BytesWritten != nil {
+		opts.BytesWritten(int(inMeta.CompactionLevel)-1, int(newMeta.Size_))
+	}
+
+	return []*backend.BlockMeta{newMeta}, nil
+}
+
+// combiningIterator merges iterators that are sorted by trace ID into one, combining the traces that are found in
+// more than one of them.
+type combiningIterator struct {
+	iters  []common.Iterator
+	done   []bool
+	ids    []common.ID
+	traces []*tempopb.Trace
+
+	opts            common.CompactionOptions
+	compactionLevel int
+	written         int
+}
+
+var _ common.Iterator = (*combiningIterator)(nil)
+
+func newCombiningIterator(n int, opts common.CompactionOptions) *combiningIterator {
+	return &combiningIterator{
+		iters:  make([]common.Iterator, 0, n),
+		done:   make([]bool, n),
+		ids:    make([]common.ID, n),
+		traces: make([]*tempopb.Trace, n),
+		opts:   opts,
+	}
+}
+
+func (i *combiningIterator) Next(ctx context.Context) (common.ID, *tempopb.Trace, error) {
+	for {
+		// fill the heads of the iterators. a nil trace marks an exhausted iterator.
+		var lowest common.ID
+		for j, it := range i.iters {
+			if i.traces[j] == nil && !i.done[j] {
+				id, tr, err := it.Next(ctx)
+				if err != nil {
+					return nil, nil, err
+				}
+				if tr == nil {
+					i.done[j] = true
+					continue
+				}
+				i.ids[j], i.traces[j] = id, tr
+			}
+			if i.traces[j] != nil && (lowest == nil || bytes.Compare(i.ids[j], lowest) < 0) {
+				lowest = i.ids[j]
+			}
+		}
+
+		if lowest == nil {
+			return nil, nil, nil
+		}
+
+		cmb := trace.NewCombiner(i.opts.MaxBytesPerTrace, true)
+		consumed := 0
+		for j := range i.traces {
+			if i.traces[j] == nil || !bytes.Equal(i.ids[j], lowest) {
+				continue
+			}
+			// a trace that grows too large keeps the spans combined so far
+			_, _ = cmb.Consume(i.traces[j])
+			i.traces[j] = nil
+			consumed++
+		}
+
+		if i.opts.DropObject != nil && i.opts.DropObject(lowest) {
+			continue
+		}
+
+		tr, _ := cmb.Result()
+		if consumed > 1 && i.opts.ObjectsCombined != nil {
+			i.opts.ObjectsCombined(i.compactionLevel, 1)
+		}
+		i.written++
+
+		return lowest, tr, nil
+	}
+}
+
+func (i *combiningIterator) Close() {
+	for _, it := range i.iters {
+		it.Close()
+	}
+}
+
+// tenantBlockVersion returns the block version configured for the tenant or an empty string if the tenant uses the
+// version of the block config.
+func (rw *readerWriter) tenantBlockVersion(tenantID string) string {
+	if rw.cfg.BlockVersionForTenant == nil {
+		return ""
+	}
+	return rw.cfg.BlockVersionForTenant(tenantID)
+}
+
+// blockVersion returns the version new blocks of the tenant are created in.
+func (rw *readerWriter) blockVersion(tenantID string) string {
+	if v := rw.tenantBlockVersion(tenantID); v != "" {
+		return v
+	}
+	return rw.cfg.Block.Version
+}
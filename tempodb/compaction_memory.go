@@ -0,0 +1,47 @@
+package tempodb
+
+import (
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// JobMemoryBudget limits the estimated memory of a compaction job. Jobs that would exceed it are compacted with
+// fewer input blocks, and blocks that can't be compacted with any other block within the budget are skipped. The
+// zero value doesn't limit jobs.
+type JobMemoryBudget struct {
+	MaxBytes           uint64
+	IteratorBufferSize int
+	FlushSizeBytes     uint32
+	MaxBytesPerTrace   int
+}
+
+// Estimate returns the estimated peak memory of compacting the blocks. Every input block buffers up to
+// IteratorBufferSize traces of its average size and holds its largest trace while it's combined, and the output
+// block buffers up to FlushSizeBytes. Block metas don't record the largest trace, so it's bounded by
+// MaxBytesPerTrace, which the compactor enforces when combining traces. If it's not set the average trace size is
+// used instead.
+func (b JobMemoryBudget) Estimate(metas []*backend.BlockMeta) uint64 {
+	estimate := uint64(b.FlushSizeBytes)
+
+	for _, m := range metas {
+		if m.TotalObjects <= 0 {
+			continue
+		}
+
+		avgTraceSize := m.Size_ / uint64(m.TotalObjects)
+		buffered := min(uint64(m.TotalObjects), uint64(max(b.IteratorBufferSize, 0)))
+
+		largestTraceSize := avgTraceSize
+		if b.MaxBytesPerTrace > 0 {
+			largestTraceSize = max(largestTraceSize, uint64(b.MaxBytesPerTrace))
+		}
+
+		estimate += buffered*avgTraceSize + largestTraceSize
+	}
+
+	return estimate
+}
+
+// fits returns true if compacting the blocks is estimated to stay within the budget.
+func (b JobMemoryBudget) fits(metas []*backend.BlockMeta) bool {
+	return b.MaxBytes == 0 || b.Estimate(metas) <= b.MaxBytes
+}
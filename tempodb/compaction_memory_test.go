@@ -0,0 +1,64 @@
+package tempodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestJobMemoryBudgetEstimate(t *testing.T) {
+	budget := JobMemoryBudget{
+		IteratorBufferSize: 10,
+		FlushSizeBytes:     1000,
+	}
+
+	metas := []*backend.BlockMeta{
+		// 10 buffered traces of 10 bytes and a largest trace of 10 bytes
+		{TotalObjects: 100, Size_: 1000},
+		// both traces are buffered
+		{TotalObjects: 2, Size_: 200},
+		// empty blocks are ignored
+		{},
+	}
+	require.Equal(t, uint64(1000+10*10+10+2*100+100), budget.Estimate(metas))
+
+	// the largest trace is bounded by the max bytes per trace
+	budget.MaxBytesPerTrace = 50
+	require.Equal(t, uint64(1000+10*10+50+2*100+100), budget.Estimate(metas))
+
+	require.True(t, budget.fits(metas))
+	budget.MaxBytes = 1000
+	require.False(t, budget.fits(metas))
+}
+
+func TestTimeWindowBlockSelectorMemoryBudget(t *testing.T) {
+	now := time.Now()
+	block := func(id string, size uint64) *backend.BlockMeta {
+		return &backend.BlockMeta{BlockID: backend.MustParse(id), EndTime: now, TotalObjects: 1, Size_: size}
+	}
+
+	blocklist := []*backend.BlockMeta{
+		block("00000000-0000-0000-0000-000000000001", 10),
+		block("00000000-0000-0000-0000-000000000002", 10),
+		block("00000000-0000-0000-0000-000000000003", 10),
+		block("00000000-0000-0000-0000-000000000004", 10),
+		// a block with a mega-trace
+		block("00000000-0000-0000-0000-000000000005", 1000),
+	}
+
+	// every block is estimated at twice its size, a budget of 60 allows jobs of 3 small blocks
+	budget := JobMemoryBudget{MaxBytes: 60, IteratorBufferSize: 1}
+	twbs := newTimeWindowBlockSelector(blocklist, time.Second, 100, 1024*1024, budget, defaultMinInputBlocks, defaultMaxInputBlocks).(*timeWindowBlockSelector)
+
+	// the job is split into fewer blocks
+	blocks, _ := twbs.BlocksToCompact()
+	require.Equal(t, blocklist[:3], blocks)
+
+	// the block with the mega-trace is skipped
+	blocks, _ = twbs.BlocksToCompact()
+	require.Empty(t, blocks)
+	require.Equal(t, 1, twbs.overMemoryBudget)
+}
@@ -0,0 +1,99 @@
+package tempodb
+
+import (
+	"time"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// CompactionPlan lists the compaction jobs that the compactors would run for the blocks of a tenant.
+type CompactionPlan struct {
+	TenantID             string              `json:"tenantID"`
+	CompactionWindow     string              `json:"compactionWindow"`
+	MaxCompactionObjects int                 `json:"maxCompactionObjects"`
+	MaxBlockBytes        uint64              `json:"maxBlockBytes"`
+	TotalBlocks          int                 `json:"totalBlocks"`
+	Jobs                 []CompactionPlanJob `json:"jobs"`
+}
+
+// CompactionPlanJob is a set of blocks that would be compacted into a single block. Jobs with the same hash are
+// owned by the same compactor.
+type CompactionPlanJob struct {
+	Hash         string    `json:"hash"`
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	TotalObjects int64     `json:"totalObjects"`
+	Size         uint64    `json:"size"`
+	// EstimatedMemory is the estimated memory of compacting the blocks
+	EstimatedMemory uint64                `json:"estimatedMemory"`
+	Blocks          []CompactionPlanBlock `json:"blocks"`
+}
+
+type CompactionPlanBlock struct {
+	BlockID         string    `json:"blockID"`
+	CompactionLevel uint32    `json:"compactionLevel"`
+	Version         string    `json:"version"`
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	TotalObjects    int64     `json:"totalObjects"`
+	Size            uint64    `json:"size"`
+}
+
+// PlanCompaction selects the blocks to compact the same way the compactors do, without compacting them. owns
+// filters the jobs by their hash, all jobs are planned if it's nil.
+func PlanCompaction(tenantID string, blocklist []*backend.BlockMeta, window time.Duration, maxCompactionObjects int, maxBlockBytes uint64, memoryBudget JobMemoryBudget, owns func(hash string) bool) *CompactionPlan {
+	plan := &CompactionPlan{
+		TenantID:             tenantID,
+		CompactionWindow:     window.String(),
+		MaxCompactionObjects: maxCompactionObjects,
+		MaxBlockBytes:        maxBlockBytes,
+		TotalBlocks:          len(blocklist),
+		Jobs:                 []CompactionPlanJob{},
+	}
+
+	blockSelector := newTimeWindowBlockSelector(blocklist,
+		window,
+		maxCompactionObjects,
+		maxBlockBytes,
+		memoryBudget,
+		defaultMinInputBlocks,
+		defaultMaxInputBlocks)
+
+	for {
+		toBeCompacted, hashString := blockSelector.BlocksToCompact()
+		if len(toBeCompacted) == 0 {
+			return plan
+		}
+		if owns != nil && !owns(hashString) {
+			continue
+		}
+
+		job := CompactionPlanJob{
+			Hash:   hashString,
+			Blocks: make([]CompactionPlanBlock, 0, len(toBeCompacted)),
+		}
+		for _, meta := range toBeCompacted {
+			if job.Start.IsZero() || meta.StartTime.Before(job.Start) {
+				job.Start = meta.StartTime
+			}
+			if meta.EndTime.After(job.End) {
+				job.End = meta.EndTime
+			}
+			job.TotalObjects += meta.TotalObjects
+			job.Size += meta.Size_
+
+			job.Blocks = append(job.Blocks, CompactionPlanBlock{
+				BlockID:         meta.BlockID.String(),
+				CompactionLevel: meta.CompactionLevel,
+				Version:         meta.Version,
+				Start:           meta.StartTime,
+				End:             meta.EndTime,
+				TotalObjects:    meta.TotalObjects,
+				Size:            meta.Size_,
+			})
+		}
+
+		job.EstimatedMemory = memoryBudget.Estimate(toBeCompacted)
+		plan.Jobs = append(plan.Jobs, job)
+	}
+}
@@ -0,0 +1,41 @@
+package tempodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestPlanCompaction(t *testing.T) {
+	now := time.Now()
+	window := time.Hour
+
+	blocklist := []*backend.BlockMeta{
+		{BlockID: backend.MustParse("00000000-0000-0000-0000-000000000000"), StartTime: now.Add(-2 * time.Minute), EndTime: now, TotalObjects: 1, Size_: 10},
+		{BlockID: backend.MustParse("00000000-0000-0000-0000-000000000001"), StartTime: now.Add(-time.Minute), EndTime: now, TotalObjects: 2, Size_: 20},
+		// too big to be compacted with the others
+		{BlockID: backend.MustParse("00000000-0000-0000-0000-000000000002"), StartTime: now, EndTime: now, TotalObjects: 3, Size_: 1000},
+	}
+
+	plan := PlanCompaction("test", blocklist, window, 100, 100, JobMemoryBudget{}, nil)
+	require.Equal(t, "test", plan.TenantID)
+	require.Equal(t, "1h0m0s", plan.CompactionWindow)
+	require.Equal(t, 3, plan.TotalBlocks)
+	require.Len(t, plan.Jobs, 1)
+
+	job := plan.Jobs[0]
+	require.Equal(t, now.Add(-2*time.Minute), job.Start)
+	require.Equal(t, now, job.End)
+	require.Equal(t, int64(3), job.TotalObjects)
+	require.Equal(t, uint64(30), job.Size)
+	require.Len(t, job.Blocks, 2)
+	require.Equal(t, "00000000-0000-0000-0000-000000000000", job.Blocks[0].BlockID)
+	require.Equal(t, "00000000-0000-0000-0000-000000000001", job.Blocks[1].BlockID)
+
+	// jobs that aren't owned are not planned
+	plan = PlanCompaction("test", blocklist, window, 100, 100, JobMemoryBudget{}, func(string) bool { return false })
+	require.Empty(t, plan.Jobs)
+}
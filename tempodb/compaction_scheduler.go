@@ -0,0 +1,239 @@
+package tempodb
+
+import (
+	"sync"
+	"time"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+const (
+	// a tenant whose compaction cycles keep failing is not scheduled for an exponentially growing time, so it
+	// doesn't occupy the workers of the other tenants
+	tenantFailureBackoffMin = 30 * time.Second
+	tenantFailureBackoffMax = 10 * time.Minute
+)
+
+// compactionScheduler picks the tenant a compaction worker compacts next. Every tenant is weighted by its share of
+// the outstanding blocks and bytes of all tenants. The weight is multiplied by the number of cycles the tenant has
+// been waiting since it was last picked. Backlogged tenants are picked more often, and by more workers at once, but
+// every tenant is eventually picked no matter how large the backlog of the others is.
+type compactionScheduler struct {
+	mtx     sync.Mutex
+	cycle   uint64
+	tenants map[string]*scheduledTenant
+	now     func() time.Time
+}
+
+type scheduledTenant struct {
+	lastCycle uint64
+	running   int
+	// exclusive is set while a worker applies the tombstones of the tenant. No other worker is scheduled on the
+	// tenant until it's done.
+	exclusive bool
+	// blocks claimed by the workers of the tenant. a claim is released as soon as the job that compacts the blocks
+	// is done.
+	compacting map[backend.UUID]struct{}
+
+	// consecutive failed compaction cycles and the time until which the tenant isn't scheduled because of them
+	failures     int
+	backoffUntil time.Time
+
+	// outstanding work as measured at the end of the last compaction cycle of the tenant
+	measured          bool
+	outstandingBlocks int
+	outstandingBytes  uint64
+}
+
+// schedulerTenant is a tenant that can be scheduled.
+type schedulerTenant struct {
+	id string
+	// maximum number of workers compacting the tenant at the same time. 0 is unlimited
+	maxConcurrent int
+	// outstanding work of a tenant that hasn't been measured yet
+	estimateBlocks int
+	estimateBytes  uint64
+}
+
+func newCompactionScheduler() *compactionScheduler {
+	return &compactionScheduler{
+		tenants: map[string]*scheduledTenant{},
+		now:     time.Now,
+	}
+}
+
+// next returns the tenant to compact next or false if every tenant is compacted by its maximum number of workers or
+// backing off after failures.
+// exclusive is true if no other worker compacts the tenant. The worker must call endExclusive once it's ready for
+// other workers to join, and done once it's finished.
+func (s *compactionScheduler) next(tenants []schedulerTenant) (tenantID string, exclusive bool, ok bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	// forget tenants that are gone
+	known := make(map[string]struct{}, len(tenants))
+	for _, t := range tenants {
+		known[t.id] = struct{}{}
+	}
+	for id, t := range s.tenants {
+		if _, ok := known[id]; !ok && t.running == 0 {
+			delete(s.tenants, id)
+			metricCompactionTenantWorkers.DeleteLabelValues(id)
+		}
+	}
+
+	now := s.now()
+
+	var totalBlocks, totalBytes float64
+	for _, t := range tenants {
+		blocks, bytes := s.outstanding(t)
+		totalBlocks += float64(blocks)
+		totalBytes += float64(bytes)
+	}
+
+	var (
+		best      *schedulerTenant
+		bestScore float64
+	)
+	for i := range tenants {
+		t := &tenants[i]
+		st := s.tenants[t.id]
+		if st != nil && (st.exclusive || (t.maxConcurrent > 0 && st.running >= t.maxConcurrent)) {
+			continue
+		}
+		if st != nil && now.Before(st.backoffUntil) {
+			continue
+		}
+
+		blocks, bytes := s.outstanding(*t)
+		var share float64
+		if totalBlocks > 0 {
+			share += float64(blocks) / totalBlocks / 2
+		}
+		if totalBytes > 0 {
+			share += float64(bytes) / totalBytes / 2
+		}
+		weight := 1 + share*float64(len(tenants))
+
+		var lastCycle uint64
+		if st != nil {
+			lastCycle = st.lastCycle
+		}
+		score := float64(s.cycle+1-lastCycle) * weight
+
+		if best == nil || score > bestScore || (score == bestScore && t.id < best.id) {
+			best = t
+			bestScore = score
+		}
+	}
+
+	if best == nil {
+		return "", false, false
+	}
+
+	st := s.tenants[best.id]
+	if st == nil {
+		st = &scheduledTenant{compacting: map[backend.UUID]struct{}{}}
+		s.tenants[best.id] = st
+	}
+
+	s.cycle++
+	st.lastCycle = s.cycle
+	st.running++
+	st.exclusive = st.running == 1
+	metricCompactionTenantWorkers.WithLabelValues(best.id).Set(float64(st.running))
+
+	return best.id, st.exclusive, true
+}
+
+// endExclusive allows other workers to be scheduled on the tenant.
+func (s *compactionScheduler) endExclusive(tenantID string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if st, ok := s.tenants[tenantID]; ok {
+		st.exclusive = false
+	}
+}
+
+// done is called when a worker finished its compaction cycle of the tenant. If the cycle failed, the tenant isn't
+// scheduled again until it backed off.
+func (s *compactionScheduler) done(tenantID string, failed bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if st, ok := s.tenants[tenantID]; ok {
+		st.running--
+		st.exclusive = false
+		metricCompactionTenantWorkers.WithLabelValues(tenantID).Set(float64(st.running))
+
+		if failed {
+			st.failures++
+			st.backoffUntil = s.now().Add(tenantFailureBackoff(st.failures))
+		} else {
+			st.failures = 0
+		}
+	}
+}
+
+// setOutstanding records the outstanding work of the tenant. It's used to weight the tenant from now on.
+func (s *compactionScheduler) setOutstanding(tenantID string, blocks int, bytes uint64) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if st, ok := s.tenants[tenantID]; ok {
+		st.measured = true
+		st.outstandingBlocks = blocks
+		st.outstandingBytes = bytes
+	}
+}
+
+// claim marks the blocks as compacted by the calling worker. It returns false if any of them has been claimed by
+// another worker of the tenant already.
+func (s *compactionScheduler) claim(tenantID string, metas []*backend.BlockMeta) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	st, ok := s.tenants[tenantID]
+	if !ok {
+		return false
+	}
+
+	for _, m := range metas {
+		if _, ok := st.compacting[m.BlockID]; ok {
+			return false
+		}
+	}
+	for _, m := range metas {
+		st.compacting[m.BlockID] = struct{}{}
+	}
+	return true
+}
+
+// release removes the claims of a compaction job. It's called when the job is done, whether it succeeded or not.
+func (s *compactionScheduler) release(tenantID string, metas []*backend.BlockMeta) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if st, ok := s.tenants[tenantID]; ok {
+		for _, m := range metas {
+			delete(st.compacting, m.BlockID)
+		}
+	}
+}
+
+func (s *compactionScheduler) outstanding(t schedulerTenant) (int, uint64) {
+	if st, ok := s.tenants[t.id]; ok && st.measured {
+		return st.outstandingBlocks, st.outstandingBytes
+	}
+	return t.estimateBlocks, t.estimateBytes
+}
+
+// tenantFailureBackoff returns how long a tenant isn't scheduled after the given number of consecutive failures.
+func tenantFailureBackoff(failures int) time.Duration {
+	backoff := tenantFailureBackoffMin
+	for i := 1; i < failures && backoff < tenantFailureBackoffMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, tenantFailureBackoffMax)
+}
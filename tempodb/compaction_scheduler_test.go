@@ -0,0 +1,187 @@
+package tempodb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+func TestCompactionSchedulerRotatesTenants(t *testing.T) {
+	s := newCompactionScheduler()
+	tenants := []schedulerTenant{
+		{id: "a", estimateBlocks: 10, estimateBytes: 100},
+		{id: "b", estimateBlocks: 10, estimateBytes: 100},
+		{id: "c", estimateBlocks: 10, estimateBytes: 100},
+	}
+
+	var picked []string
+	for i := 0; i < 6; i++ {
+		tenantID, exclusive, ok := s.next(tenants)
+		require.True(t, ok)
+		require.True(t, exclusive)
+		s.done(tenantID, false)
+		picked = append(picked, tenantID)
+	}
+
+	require.Equal(t, []string{"a", "b", "c", "a", "b", "c"}, picked)
+}
+
+func TestCompactionSchedulerWeightsBacklog(t *testing.T) {
+	s := newCompactionScheduler()
+	tenants := []schedulerTenant{
+		{id: "backlogged", estimateBlocks: 1000, estimateBytes: 1000},
+		{id: "small-1", estimateBlocks: 1, estimateBytes: 1},
+		{id: "small-2", estimateBlocks: 1, estimateBytes: 1},
+	}
+
+	picks := map[string]int{}
+	for i := 0; i < 100; i++ {
+		tenantID, _, ok := s.next(tenants)
+		require.True(t, ok)
+		s.done(tenantID, false)
+		picks[tenantID]++
+	}
+
+	// the backlogged tenant is picked most, but the small ones aren't starved
+	require.Greater(t, picks["backlogged"], 50)
+	require.Greater(t, picks["small-1"], 10)
+	require.Greater(t, picks["small-2"], 10)
+
+	// once the backlog is measured to be gone the tenants are picked equally
+	s.setOutstanding("backlogged", 1, 1)
+
+	picks = map[string]int{}
+	for i := 0; i < 30; i++ {
+		tenantID, _, ok := s.next(tenants)
+		require.True(t, ok)
+		s.done(tenantID, false)
+		picks[tenantID]++
+	}
+	require.Equal(t, map[string]int{"backlogged": 10, "small-1": 10, "small-2": 10}, picks)
+}
+
+func TestCompactionSchedulerConcurrency(t *testing.T) {
+	s := newCompactionScheduler()
+	tenants := []schedulerTenant{
+		{id: "a", maxConcurrent: 2, estimateBlocks: 100},
+	}
+
+	// the first worker is exclusive until it applied the tombstones
+	tenantID, exclusive, ok := s.next(tenants)
+	require.True(t, ok)
+	require.True(t, exclusive)
+	require.Equal(t, "a", tenantID)
+
+	_, _, ok = s.next(tenants)
+	require.False(t, ok)
+
+	s.endExclusive("a")
+
+	// a second worker can join
+	tenantID, exclusive, ok = s.next(tenants)
+	require.True(t, ok)
+	require.False(t, exclusive)
+	require.Equal(t, "a", tenantID)
+
+	// but not a third
+	_, _, ok = s.next(tenants)
+	require.False(t, ok)
+
+	s.done("a", false)
+	_, _, ok = s.next(tenants)
+	require.True(t, ok)
+}
+
+func TestCompactionSchedulerClaims(t *testing.T) {
+	s := newCompactionScheduler()
+	tenants := []schedulerTenant{{id: "a"}}
+
+	metas := func(ids ...backend.UUID) []*backend.BlockMeta {
+		out := make([]*backend.BlockMeta, 0, len(ids))
+		for _, id := range ids {
+			out = append(out, &backend.BlockMeta{BlockID: id})
+		}
+		return out
+	}
+	b1, b2, b3 := backend.UUID(uuid.New()), backend.UUID(uuid.New()), backend.UUID(uuid.New())
+
+	_, _, ok := s.next(tenants)
+	require.True(t, ok)
+	s.endExclusive("a")
+	_, _, ok = s.next(tenants)
+	require.True(t, ok)
+
+	require.True(t, s.claim("a", metas(b1, b2)))
+	require.False(t, s.claim("a", metas(b2, b3)))
+	require.True(t, s.claim("a", metas(b3)))
+
+	// claims are released when the job is done, not when the workers are done
+	s.release("a", metas(b1, b2))
+	require.True(t, s.claim("a", metas(b1)))
+	require.False(t, s.claim("a", metas(b3)))
+
+	s.done("a", false)
+	s.done("a", false)
+	require.False(t, s.claim("a", metas(b1)))
+}
+
+func TestCompactionSchedulerFailureBackoff(t *testing.T) {
+	now := time.Now()
+	s := newCompactionScheduler()
+	s.now = func() time.Time { return now }
+	tenants := []schedulerTenant{
+		{id: "failing", estimateBlocks: 1000},
+		{id: "healthy", estimateBlocks: 1},
+	}
+
+	tenantID, _, ok := s.next(tenants)
+	require.True(t, ok)
+	require.Equal(t, "failing", tenantID)
+	s.done(tenantID, true)
+
+	// the failing tenant backs off, the other tenants are still compacted
+	for i := 0; i < 3; i++ {
+		tenantID, _, ok = s.next(tenants)
+		require.True(t, ok)
+		require.Equal(t, "healthy", tenantID)
+		s.done(tenantID, false)
+	}
+
+	now = now.Add(tenantFailureBackoffMin)
+	tenantID, _, ok = s.next(tenants)
+	require.True(t, ok)
+	require.Equal(t, "failing", tenantID)
+	s.done(tenantID, true)
+
+	// the backoff doubles with every consecutive failure
+	now = now.Add(tenantFailureBackoffMin)
+	tenantID, _, _ = s.next(tenants)
+	require.Equal(t, "healthy", tenantID)
+	s.done(tenantID, false)
+
+	now = now.Add(tenantFailureBackoffMin)
+	tenantID, _, _ = s.next(tenants)
+	require.Equal(t, "failing", tenantID)
+	s.done(tenantID, false)
+
+	// and is reset by a successful cycle
+	picked := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		tenantID, _, ok = s.next(tenants)
+		require.True(t, ok)
+		s.done(tenantID, false)
+		picked[tenantID] = true
+	}
+	require.Equal(t, map[string]bool{"failing": true, "healthy": true}, picked)
+}
+
+func TestTenantFailureBackoff(t *testing.T) {
+	require.Equal(t, tenantFailureBackoffMin, tenantFailureBackoff(1))
+	require.Equal(t, 2*tenantFailureBackoffMin, tenantFailureBackoff(2))
+	require.Equal(t, 4*tenantFailureBackoffMin, tenantFailureBackoff(3))
+	require.Equal(t, tenantFailureBackoffMax, tenantFailureBackoff(100))
+}
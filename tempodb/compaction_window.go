@@ -0,0 +1,16 @@
+package tempodb
+
+import (
+	"time"
+)
+
+// compactionWindowOpen returns true if blocks can be merged right now.
+func (rw *readerWriter) compactionWindowOpen() bool {
+	open := rw.compactorCfg.CompactionSchedule.Contains(time.Now())
+	if open {
+		metricCompactionWindowOpen.Set(1)
+	} else {
+		metricCompactionWindowOpen.Set(0)
+	}
+	return open
+}
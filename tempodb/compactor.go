@@ -2,11 +2,13 @@ package tempodb
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"sort"
+	"runtime/debug"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-kit/log/level"
@@ -56,6 +58,21 @@ var (
 		Name:      "compaction_errors_total",
 		Help:      "Total number of errors occurring during compaction.",
 	})
+	metricCompactionTenantErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_tenant_errors_total",
+		Help:      "Total number of errors occurring during compaction per tenant.",
+	}, []string{"tenant"})
+	metricCompactionTenantCycles = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_tenant_cycles_total",
+		Help:      "Total number of compaction cycles per tenant and result.",
+	}, []string{"tenant", "result"})
+	metricCompactionTenantWorkers = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_tenant_workers",
+		Help:      "Number of workers compacting the tenant right now.",
+	}, []string{"tenant"})
 	metricCompactionObjectsCombined = promauto.NewCounterVec(prometheus.CounterOpts{
 		Namespace: "tempodb",
 		Name:      "compaction_objects_combined_total",
@@ -66,6 +83,16 @@ var (
 		Name:      "compaction_outstanding_blocks",
 		Help:      "Number of blocks remaining to be compacted before next maintenance cycle",
 	}, []string{"tenant"})
+	metricCompactionBlocksOverMemoryBudget = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_blocks_over_memory_budget",
+		Help:      "Number of blocks not compacted in the last cycle because compacting them exceeds the job memory budget",
+	}, []string{"tenant"})
+	metricCompactionWindowOpen = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_window_open",
+		Help:      "1 if blocks are compacted right now, 0 if compaction is deferred until the compaction schedule opens.",
+	})
 	metricDedupedSpans = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "tempodb",
 		Name:      "compaction_spans_combined_total",
@@ -81,48 +108,83 @@ func (rw *readerWriter) compactionLoop(ctx context.Context) {
 		compactionCycle = rw.compactorCfg.CompactionCycle
 	}
 
-	for {
-		// if the context is cancelled, we're shutting down and need to stop compacting
-		if ctx.Err() != nil {
-			break
-		}
+	ctx = backend.WithIOPriority(ctx, backend.IOPriorityCompaction)
 
-		doForAtLeast(ctx, compactionCycle, func() {
-			rw.compactOneTenant(ctx)
-		})
+	// every worker runs a compaction cycle for the tenant picked by the scheduler
+	wg := sync.WaitGroup{}
+	for i := uint(0); i < rw.compactorCfg.CompactionConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for {
+				// if the context is cancelled, we're shutting down and need to stop compacting
+				if ctx.Err() != nil {
+					break
+				}
+
+				doForAtLeast(ctx, compactionCycle, func() {
+					rw.compactOneTenant(ctx)
+				})
+			}
+		}()
 	}
+	wg.Wait()
 }
 
-// compactOneTenant runs a compaction cycle every 30s
+// compactOneTenant runs a compaction cycle for the next tenant picked by the scheduler. Failures, including panics,
+// are confined to the tenant: the tenant backs off while the workers carry on with the other tenants.
 func (rw *readerWriter) compactOneTenant(ctx context.Context) {
-	// List of all tenants in the block list
-	// The block list is updated by constant polling the storage for tenant indexes and/or tenant blocks (and building the index)
-	tenants := rw.blocklist.Tenants()
-	if len(tenants) == 0 {
+	tenantID, exclusive, ok := rw.compactionScheduler.next(rw.schedulerTenants())
+	if !ok {
 		return
 	}
 
-	// Iterate through tenants each cycle
-	// Sort tenants for stability (since original map does not guarantee order)
-	sort.Slice(tenants, func(i, j int) bool { return tenants[i] < tenants[j] })
-	rw.compactorTenantOffset = (rw.compactorTenantOffset + 1) % uint(len(tenants))
+	failed := false
+	defer func() {
+		if r := recover(); r != nil {
+			level.Error(rw.logger).Log("msg", "panic during compaction cycle", "tenantID", tenantID, "panic", r, "stack", string(debug.Stack()))
+			metricCompactionErrors.Inc()
+			metricCompactionTenantErrors.WithLabelValues(tenantID).Inc()
+			failed = true
+		}
 
-	// Select the next tenant to run compaction for
-	tenantID := tenants[rw.compactorTenantOffset]
+		result := "success"
+		if failed {
+			result = "failure"
+		}
+		metricCompactionTenantCycles.WithLabelValues(tenantID, result).Inc()
+		rw.compactionScheduler.done(tenantID, failed)
+	}()
+
+	window := rw.compactorOverrides.MaxCompactionRangeForTenant(tenantID)
+	if window == 0 {
+		window = rw.compactorCfg.MaxCompactionRange
+	}
 
-	// Skip compaction for tenants which have it disabled.
-	if rw.compactorOverrides.CompactionDisabledForTenant(tenantID) {
+	// In dry run mode only log the jobs that would be run
+	if rw.compactorCfg.DryRun {
+		rw.logCompactionPlan(tenantID, window)
 		return
 	}
 
-	// Get the meta file of all non-compacted blocks for the given tenant
-	blocklist := rw.blocklist.Metas(tenantID)
+	// Apply deletion requests and retention rules before selecting blocks. Rewritten blocks are replaced in the
+	// blocklist. Only the first worker of the tenant applies them, other workers join once it's done
+	if exclusive {
+		rw.applyTombstones(ctx, tenantID)
+		rw.applyRetentionRules(ctx, tenantID)
+		rw.compactionScheduler.endExclusive(tenantID)
+	}
 
-	window := rw.compactorOverrides.MaxCompactionRangeForTenant(tenantID)
-	if window == 0 {
-		window = rw.compactorCfg.MaxCompactionRange
+	// Merging blocks is deferred outside the compaction schedule
+	if !rw.compactionWindowOpen() {
+		level.Debug(rw.logger).Log("msg", "outside of the compaction schedule. deferring compaction", "tenantID", tenantID)
+		return
 	}
 
+	// Get the meta file of all non-compacted blocks for the given tenant
+	blocklist := rw.blocklist.Metas(tenantID)
+
 	// Select which blocks to compact.
 	//
 	// Blocks are firstly divided by the active compaction window (default: most recent 24h)
@@ -134,12 +196,13 @@ func (rw *readerWriter) compactOneTenant(ctx context.Context) {
 		window,
 		rw.compactorCfg.MaxCompactionObjects,
 		rw.compactorCfg.MaxBlockBytes,
+		rw.jobMemoryBudget(tenantID),
 		defaultMinInputBlocks,
 		defaultMaxInputBlocks)
 
 	start := time.Now()
 
-	level.Info(rw.logger).Log("msg", "starting compaction cycle", "tenantID", tenantID, "offset", rw.compactorTenantOffset)
+	level.Info(rw.logger).Log("msg", "starting compaction cycle", "tenantID", tenantID)
 	for {
 		// this context is controlled by the service manager. it being cancelled means that the process is shutting down
 		if ctx.Err() != nil {
@@ -150,7 +213,7 @@ func (rw *readerWriter) compactOneTenant(ctx context.Context) {
 		// Pick up to defaultMaxInputBlocks (4) blocks to compact into a single one
 		toBeCompacted, hashString := blockSelector.BlocksToCompact()
 		if len(toBeCompacted) == 0 {
-			measureOutstandingBlocks(tenantID, blockSelector, rw.compactorSharder.Owns)
+			rw.measureOutstandingBlocks(tenantID, blockSelector)
 
 			level.Info(rw.logger).Log("msg", "compaction cycle complete. No more blocks to compact", "tenantID", tenantID)
 			return
@@ -163,20 +226,35 @@ func (rw *readerWriter) compactOneTenant(ctx context.Context) {
 			// continue on this tenant until we find something we own
 			continue
 		}
+		if !rw.compactionScheduler.claim(tenantID, toBeCompacted) {
+			// another worker compacts these blocks
+			continue
+		}
 
 		level.Info(rw.logger).Log("msg", "Compacting hash", "hashString", hashString)
 		err := rw.compactWhileOwns(ctx, toBeCompacted, tenantID, owns)
+		rw.compactionScheduler.release(tenantID, toBeCompacted)
 
 		if errors.Is(err, backend.ErrDoesNotExist) {
 			level.Warn(rw.logger).Log("msg", "unable to find meta during compaction. trying again on this block list", "err", err)
 		} else if err != nil {
-			level.Error(rw.logger).Log("msg", "error during compaction cycle", "err", err)
+			level.Error(rw.logger).Log("msg", "error during compaction cycle", "tenantID", tenantID, "err", err)
 			metricCompactionErrors.Inc()
+			metricCompactionTenantErrors.WithLabelValues(tenantID).Inc()
+			failed = true
+		}
+
+		// stop merging blocks once the compaction schedule closes
+		if !rw.compactionWindowOpen() {
+			rw.measureOutstandingBlocks(tenantID, blockSelector)
+
+			level.Info(rw.logger).Log("msg", "compaction schedule closed, bailing out", "tenantID", tenantID)
+			return
 		}
 
 		// after a maintenance cycle bail out
 		if start.Add(rw.compactorCfg.MaxTimePerTenant).Before(time.Now()) {
-			measureOutstandingBlocks(tenantID, blockSelector, rw.compactorSharder.Owns)
+			rw.measureOutstandingBlocks(tenantID, blockSelector)
 
 			level.Info(rw.logger).Log("msg", "compacted blocks for a maintenance cycle, bailing out", "tenantID", tenantID)
 			return
@@ -184,6 +262,33 @@ func (rw *readerWriter) compactOneTenant(ctx context.Context) {
 	}
 }
 
+func (rw *readerWriter) logCompactionPlan(tenantID string, window time.Duration) {
+	plan := PlanCompaction(tenantID, rw.blocklist.Metas(tenantID), window, rw.compactorCfg.MaxCompactionObjects, rw.compactorCfg.MaxBlockBytes, rw.jobMemoryBudget(tenantID), rw.compactorSharder.Owns)
+
+	data, err := json.Marshal(plan)
+	if err != nil {
+		level.Error(rw.logger).Log("msg", "failed to marshal compaction plan", "tenantID", tenantID, "err", err)
+		return
+	}
+
+	level.Info(rw.logger).Log("msg", "compaction plan (dry run)", "tenantID", tenantID, "jobs", len(plan.Jobs), "plan", string(data))
+}
+
+func (rw *readerWriter) jobMemoryBudget(tenantID string) JobMemoryBudget {
+	flushSizeBytes := rw.compactorCfg.FlushSizeBytes
+	if rw.compactorCfg.PartitionAttribute != "" {
+		// every partition and the shared partition buffer their own output block
+		flushSizeBytes *= uint32(rw.compactorCfg.MaxPartitions + 1)
+	}
+
+	return JobMemoryBudget{
+		MaxBytes:           rw.compactorCfg.MaxJobMemoryBytes,
+		IteratorBufferSize: rw.compactorCfg.IteratorBufferSize,
+		FlushSizeBytes:     flushSizeBytes,
+		MaxBytesPerTrace:   rw.compactorOverrides.MaxBytesPerTraceForTenant(tenantID),
+	}
+}
+
 func (rw *readerWriter) compactWhileOwns(ctx context.Context, blockMetas []*backend.BlockMeta, tenantID string, owns func() bool) error {
 	ownsCtx, cancel := context.WithCancelCause(ctx)
 
@@ -235,6 +340,12 @@ func (rw *readerWriter) compactWhileOwns(ctx context.Context, blockMetas []*back
 }
 
 func (rw *readerWriter) compactOneJob(ctx context.Context, blockMetas []*backend.BlockMeta, tenantID string) error {
+	return rw.compactOneJobWithDrop(ctx, blockMetas, tenantID, nil)
+}
+
+// compactOneJobWithDrop compacts the blocks and drops the traces for which drop returns true in addition to the
+// traces of the tenant's pending tombstones.
+func (rw *readerWriter) compactOneJobWithDrop(ctx context.Context, blockMetas []*backend.BlockMeta, tenantID string, drop func(common.ID) bool) error {
 	level.Debug(rw.logger).Log("msg", "beginning compaction", "num blocks compacting", len(blockMetas))
 
 	// todo - add timeout?
@@ -295,13 +406,16 @@ func (rw *readerWriter) compactOneJob(ctx context.Context, blockMetas []*backend
 	}
 
 	opts := common.CompactionOptions{
-		BlockConfig:        *rw.cfg.Block,
-		ChunkSizeBytes:     rw.compactorCfg.ChunkSizeBytes,
-		FlushSizeBytes:     rw.compactorCfg.FlushSizeBytes,
-		IteratorBufferSize: rw.compactorCfg.IteratorBufferSize,
-		OutputBlocks:       outputBlocks,
-		Combiner:           combiner,
-		MaxBytesPerTrace:   rw.compactorOverrides.MaxBytesPerTraceForTenant(tenantID),
+		BlockConfig:         *rw.compactionBlockConfig(ctx, tenantID),
+		ChunkSizeBytes:      rw.compactorCfg.ChunkSizeBytes,
+		FlushSizeBytes:      rw.compactorCfg.FlushSizeBytes,
+		IteratorBufferSize:  rw.compactorCfg.IteratorBufferSize,
+		OutputBlocks:        outputBlocks,
+		Combiner:            combiner,
+		MaxBytesPerTrace:    rw.compactorOverrides.MaxBytesPerTraceForTenant(tenantID),
+		SpanCombineStrategy: rw.compactorOverrides.SpanCombineStrategyForTenant(tenantID),
+		PartitionAttribute:  rw.compactorCfg.PartitionAttribute,
+		MaxPartitions:       rw.compactorCfg.MaxPartitions,
 		BytesWritten: func(compactionLevel, bytes int) {
 			metricCompactionBytesWritten.WithLabelValues(strconv.Itoa(compactionLevel)).Add(float64(bytes))
 		},
@@ -325,10 +439,27 @@ func (rw *readerWriter) compactOneJob(ctx context.Context, blockMetas []*backend
 		},
 	}
 
-	compactor := enc.NewCompactor(opts)
+	tombstoned := rw.tombstones.dropObject(tenantID)
+	switch {
+	case drop != nil:
+		opts.DropObject = func(id common.ID) bool {
+			return drop(id) || (tombstoned != nil && tombstoned(id))
+		}
+	case tombstoned != nil:
+		opts.DropObject = tombstoned
+	}
 
-	// Compact selected blocks into a larger one
-	newCompactedBlocks, err := compactor.Compact(ctx, rw.logger, rw.r, rw.w, blockMetas)
+	// Compact selected blocks into a larger one. Blocks of tenants with their own block version are converted to it.
+	var newCompactedBlocks []*backend.BlockMeta
+	if version := rw.tenantBlockVersion(tenantID); version != "" && version != blockMetas[0].Version {
+		newCompactedBlocks, err = rw.convertBlocks(ctx, blockMetas, tenantID, version, opts)
+		if errors.Is(err, errConversionUnsupported) {
+			level.Warn(rw.logger).Log("msg", "unable to convert blocks to the block version of the tenant, compacting them in their own version", "tenantID", tenantID, "version", version, "err", err)
+			newCompactedBlocks, err = enc.NewCompactor(opts).Compact(ctx, rw.logger, rw.r, rw.w, blockMetas)
+		}
+	} else {
+		newCompactedBlocks, err = enc.NewCompactor(opts).Compact(ctx, rw.logger, rw.r, rw.w, blockMetas)
+	}
 	if err != nil {
 		return err
 	}
@@ -385,21 +516,61 @@ func markCompacted(rw *readerWriter, tenantID string, oldBlocks, newBlocks []*ba
 	return nil
 }
 
-func measureOutstandingBlocks(tenantID string, blockSelector CompactionBlockSelector, owned func(hash string) bool) {
-	// count number of per-tenant outstanding blocks before next maintenance cycle
-	var totalOutstandingBlocks int
+// measureOutstandingBlocks counts the blocks and bytes left to compact before the next maintenance cycle. The
+// scheduler weights the tenant by them.
+func (rw *readerWriter) measureOutstandingBlocks(tenantID string, blockSelector CompactionBlockSelector) {
+	var (
+		totalOutstandingBlocks int
+		totalOutstandingBytes  uint64
+	)
 	for {
 		leftToBeCompacted, hashString := blockSelector.BlocksToCompact()
 		if len(leftToBeCompacted) == 0 {
 			break
 		}
-		if !owned(hashString) {
+		if !rw.compactorSharder.Owns(hashString) {
 			// continue on this tenant until we find something we own
 			continue
 		}
 		totalOutstandingBlocks += len(leftToBeCompacted)
+		for _, m := range leftToBeCompacted {
+			totalOutstandingBytes += m.Size_
+		}
 	}
 	metricCompactionOutstandingBlocks.WithLabelValues(tenantID).Set(float64(totalOutstandingBlocks))
+	rw.compactionScheduler.setOutstanding(tenantID, totalOutstandingBlocks, totalOutstandingBytes)
+
+	// every block has been considered once the selector is drained
+	if twbs, ok := blockSelector.(*timeWindowBlockSelector); ok {
+		metricCompactionBlocksOverMemoryBudget.WithLabelValues(tenantID).Set(float64(twbs.overMemoryBudget))
+		if twbs.overMemoryBudget > 0 {
+			level.Warn(rw.logger).Log("msg", "blocks skipped, compacting them exceeds the job memory budget", "tenantID", tenantID, "blocks", twbs.overMemoryBudget, "max_job_memory_bytes", rw.compactorCfg.MaxJobMemoryBytes)
+		}
+	}
+}
+
+// schedulerTenants returns the tenants the scheduler can pick from. Tenants with compaction disabled are skipped.
+func (rw *readerWriter) schedulerTenants() []schedulerTenant {
+	// The block list is updated by constant polling the storage for tenant indexes and/or tenant blocks (and building the index)
+	tenants := rw.blocklist.Tenants()
+
+	out := make([]schedulerTenant, 0, len(tenants))
+	for _, tenantID := range tenants {
+		if rw.compactorOverrides.CompactionDisabledForTenant(tenantID) {
+			continue
+		}
+
+		t := schedulerTenant{
+			id:            tenantID,
+			maxConcurrent: rw.compactorOverrides.MaxConcurrentCompactionsForTenant(tenantID),
+		}
+		for _, m := range rw.blocklist.Metas(tenantID) {
+			t.estimateBlocks++
+			t.estimateBytes += m.Size_
+		}
+		out = append(out, t)
+	}
+	return out
 }
 
 func compactionLevelForBlocks(blockMetas []*backend.BlockMeta) uint8 {
@@ -20,12 +20,15 @@ import (
 	"github.com/grafana/tempo/pkg/model/trace"
 	v1 "github.com/grafana/tempo/pkg/model/v1"
 	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/blocklist"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
 	"github.com/grafana/tempo/tempodb/pool"
 	"github.com/grafana/tempo/tempodb/wal"
 )
@@ -49,8 +52,12 @@ func (m *mockJobSharder) Owns(string) bool { return true }
 type mockOverrides struct {
 	blockRetention      time.Duration
 	disabled            bool
+	confirmDeleteAll    bool
 	maxBytesPerTrace    int
 	maxCompactionWindow time.Duration
+	maxConcurrent       int
+	spanCombineStrategy common.SpanCombineStrategy
+	retentionRules      []RetentionRule
 }
 
 func (m *mockOverrides) BlockRetentionForTenant(_ string) time.Duration {
@@ -61,6 +68,10 @@ func (m *mockOverrides) CompactionDisabledForTenant(_ string) bool {
 	return m.disabled
 }
 
+func (m *mockOverrides) ConfirmDeleteAllForTenant(_ string) bool {
+	return m.confirmDeleteAll
+}
+
 func (m *mockOverrides) MaxBytesPerTraceForTenant(_ string) int {
 	return m.maxBytesPerTrace
 }
@@ -69,6 +80,18 @@ func (m *mockOverrides) MaxCompactionRangeForTenant(_ string) time.Duration {
 	return m.maxCompactionWindow
 }
 
+func (m *mockOverrides) MaxConcurrentCompactionsForTenant(_ string) int {
+	return m.maxConcurrent
+}
+
+func (m *mockOverrides) SpanCombineStrategyForTenant(_ string) common.SpanCombineStrategy {
+	return m.spanCombineStrategy
+}
+
+func (m *mockOverrides) RetentionRulesForTenant(_ string) []RetentionRule {
+	return m.retentionRules
+}
+
 func TestCompactionRoundtrip(t *testing.T) {
 	for _, enc := range encoding.AllEncodings() {
 		version := enc.Version()
@@ -161,7 +184,7 @@ func testCompactionRoundtrip(t *testing.T, targetBlockVersion string) {
 	rw.pollBlocklist()
 
 	blocklist := rw.blocklist.Metas(testTenantID)
-	blockSelector := newTimeWindowBlockSelector(blocklist, rw.compactorCfg.MaxCompactionRange, 10000, 1024*1024*1024, defaultMinInputBlocks, 2)
+	blockSelector := newTimeWindowBlockSelector(blocklist, rw.compactorCfg.MaxCompactionRange, 10000, 1024*1024*1024, JobMemoryBudget{}, defaultMinInputBlocks, 2)
 
 	expectedCompactions := len(blocklist) / inputBlocks
 	compactions := 0
@@ -329,7 +352,7 @@ func testSameIDCompaction(t *testing.T, targetBlockVersion string) {
 
 	var blocks []*backend.BlockMeta
 	list := rw.blocklist.Metas(testTenantID)
-	blockSelector := newTimeWindowBlockSelector(list, rw.compactorCfg.MaxCompactionRange, 10000, 1024*1024*1024, defaultMinInputBlocks, blockCount)
+	blockSelector := newTimeWindowBlockSelector(list, rw.compactorCfg.MaxCompactionRange, 10000, 1024*1024*1024, JobMemoryBudget{}, defaultMinInputBlocks, blockCount)
 	blocks, _ = blockSelector.BlocksToCompact()
 	require.Len(t, blocks, blockCount)
 
@@ -444,6 +467,133 @@ func TestCompactionUpdatesBlocklist(t *testing.T) {
 	}
 }
 
+func TestCompactionDryRun(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncNone,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:       10,
+		MaxCompactionRange:   24 * time.Hour,
+		MaxCompactionObjects: 1000,
+		MaxBlockBytes:        1024 * 1024 * 1024,
+		DryRun:               true,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	blockCount := 4
+	cutTestBlocks(t, w, testTenantID, blockCount, 1)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+
+	// the blocks would be compacted, but nothing is written
+	plan := PlanCompaction(testTenantID, rw.blocklist.Metas(testTenantID), 24*time.Hour, 1000, 1024*1024*1024, JobMemoryBudget{}, nil)
+	require.Len(t, plan.Jobs, 1)
+
+	rw.compactOneTenant(ctx)
+	rw.pollBlocklist()
+
+	require.Len(t, rw.blocklist.Metas(testTenantID), blockCount)
+	require.Empty(t, rw.blocklist.CompactedMetas(testTenantID))
+}
+
+func TestCompactionSchedule(t *testing.T) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncNone,
+			IndexPageSizeBytes:   1000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	// a window that opens in two hours
+	now := time.Now().UTC()
+	schedule := &util.TimeWindow{Start: now.Add(2 * time.Hour).Format("15:04"), End: now.Add(3 * time.Hour).Format("15:04")}
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:       10,
+		MaxCompactionRange:   24 * time.Hour,
+		MaxCompactionObjects: 1000,
+		MaxBlockBytes:        1024 * 1024 * 1024,
+		CompactionSchedule:   schedule,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+
+	blockCount := 4
+	cutTestBlocks(t, w, testTenantID, blockCount, 1)
+
+	rw := r.(*readerWriter)
+	rw.pollBlocklist()
+
+	// outside of the window nothing is compacted
+	rw.compactOneTenant(ctx)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), blockCount)
+	open, err := test.GetGaugeValue(metricCompactionWindowOpen)
+	require.NoError(t, err)
+	require.Equal(t, float64(0), open)
+
+	// open the window
+	schedule = &util.TimeWindow{Start: now.Add(-time.Hour).Format("15:04"), End: now.Add(time.Hour).Format("15:04")}
+	require.NoError(t, schedule.Parse())
+	rw.compactorCfg.CompactionSchedule = schedule
+
+	rw.compactOneTenant(ctx)
+	rw.pollBlocklist()
+	require.Len(t, rw.blocklist.Metas(testTenantID), 1)
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), blockCount)
+	open, err = test.GetGaugeValue(metricCompactionWindowOpen)
+	require.NoError(t, err)
+	require.Equal(t, float64(1), open)
+}
+
 func TestCompactionMetrics(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -568,11 +718,10 @@ func TestCompactionIteratesThroughTenants(t *testing.T) {
 	assert.Equal(t, 2, len(rw.blocklist.Metas(testTenantID)))
 	assert.Equal(t, 2, len(rw.blocklist.Metas(testTenantID2)))
 
-	// Verify that tenant 2 compacted, tenant 1 is not
-	// Compaction starts at index 1 for simplicity
+	// Verify that one tenant compacted, the other is not. Which one is picked first depends on the size of their
+	// blocks
 	rw.compactOneTenant(ctx)
-	assert.Equal(t, 2, len(rw.blocklist.Metas(testTenantID)))
-	assert.Equal(t, 1, len(rw.blocklist.Metas(testTenantID2)))
+	assert.ElementsMatch(t, []int{1, 2}, []int{len(rw.blocklist.Metas(testTenantID)), len(rw.blocklist.Metas(testTenantID2))})
 
 	// Verify both tenants compacted after second run
 	rw.compactOneTenant(ctx)
@@ -778,6 +927,98 @@ func testCompactionDropsTraces(t *testing.T, targetBlockVersion string) {
 	}
 }
 
+func TestCompactionConvertsToTenantBlockVersion(t *testing.T) {
+	tempDir := t.TempDir()
+
+	tenantVersion := ""
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              vparquet3.VersionString,
+			Encoding:             backend.EncNone,
+			IndexPageSizeBytes:   1000,
+			RowGroupSizeBytes:    30_000_000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		BlockVersionForTenant: func(string) string { return tenantVersion },
+		BlocklistPoll:         0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	err = c.EnableCompaction(ctx, &CompactorConfig{
+		ChunkSizeBytes:          10_000_000,
+		FlushSizeBytes:          10_000_000,
+		MaxCompactionRange:      24 * time.Hour,
+		BlockRetention:          0,
+		CompactedBlockRetention: 0,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(ctx, &mockJobSharder{})
+	rw := r.(*readerWriter)
+
+	// blocks cut before the tenant is migrated are in the version of the block config. the same trace is in both.
+	sharedID := test.ValidTraceID(nil)
+	var ids []common.ID
+	var metas []*backend.BlockMeta
+	for i := 0; i < 2; i++ {
+		id := test.ValidTraceID(nil)
+		ids = append(ids, id)
+
+		b := cutTestBlockWithTraces(t, w, []testData{
+			{id: id, t: test.MakeTrace(5, id)},
+			{id: sharedID, t: test.MakeTrace(5, sharedID)},
+		})
+		require.Equal(t, vparquet3.VersionString, b.BlockMeta().Version)
+		metas = append(metas, b.BlockMeta())
+	}
+
+	// blocks cut after are in the version of the tenant
+	tenantVersion = vparquet4.VersionString
+	id := test.ValidTraceID(nil)
+	ids = append(ids, id)
+	b := cutTestBlockWithTraces(t, w, []testData{{id: id, t: test.MakeTrace(5, id)}})
+	require.Equal(t, vparquet4.VersionString, b.BlockMeta().Version)
+
+	rw.pollBlocklist()
+
+	// compacting the old blocks converts them
+	require.NoError(t, rw.compactOneJob(ctx, metas, testTenantID))
+
+	rw.pollBlocklist()
+	var converted *backend.BlockMeta
+	for _, meta := range rw.blocklist.Metas(testTenantID) {
+		require.Equal(t, vparquet4.VersionString, meta.Version)
+		if meta.CompactionLevel == 1 {
+			converted = meta
+		}
+	}
+	require.NotNil(t, converted)
+
+	block, err := encoding.OpenBlock(converted, rw.r)
+	require.NoError(t, err)
+
+	for _, id := range append(ids[:2], sharedID) {
+		tr, err := block.FindTraceByID(ctx, id, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		require.NotNil(t, tr)
+	}
+
+	// the trace found in both blocks is combined
+	tr, err := block.FindTraceByID(ctx, sharedID, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Len(t, tr.ResourceSpans, 10)
+}
+
 func TestDoForAtLeast(t *testing.T) {
 	// test that it runs for at least the duration
 	start := time.Now()
@@ -10,11 +10,14 @@ import (
 	"github.com/grafana/tempo/modules/cache/redis"
 
 	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/tempodb/backend/azure"
 	backend_cache "github.com/grafana/tempo/tempodb/backend/cache"
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/scheduler"
+	"github.com/grafana/tempo/tempodb/backend/swift"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/grafana/tempo/tempodb/pool"
@@ -27,12 +30,16 @@ const (
 	DefaultBlocklistPollConcurrency       = uint(50)
 	DefaultBlocklistPollTenantConcurrency = uint(1)
 	DefaultRetentionConcurrency           = uint(10)
+	DefaultCompactionConcurrency          = uint(1)
 	DefaultTenantIndexBuilders            = 2
 	DefaultTolerateConsecutiveErrors      = 1
 	DefaultTolerateTenantFailures         = 1
 
 	DefaultEmptyTenantDeletionAge = 12 * time.Hour
 
+	// MinUnconfirmedBlockRetention is the lowest per-tenant block retention that is applied without confirm_delete_all
+	MinUnconfirmedBlockRetention = time.Hour
+
 	DefaultPrefetchTraceCount   = 1000
 	DefaultSearchChunkSizeBytes = 1_000_000
 	DefaultReadBufferCount      = 32
@@ -47,6 +54,17 @@ type Config struct {
 	Block  *common.BlockConfig `yaml:"block"`
 	Search *SearchConfig       `yaml:"search"`
 
+	// BlockVersionForTenant returns the version new blocks of a tenant are created in, or an empty string to use the
+	// version of the block config. The wal blocks of the tenant are cut in the same version.
+	BlockVersionForTenant func(tenantID string) string `yaml:"-"`
+	// RowGroupSizeBytesForTenant and RowGroupSizeSpansForTenant return the row group size of a tenant's blocks, or 0
+	// to use the size of the block config.
+	RowGroupSizeBytesForTenant func(tenantID string) int `yaml:"-"`
+	RowGroupSizeSpansForTenant func(tenantID string) int `yaml:"-"`
+	// RowGroupAutoTuneForTenant returns true if the row group size of a tenant's compacted blocks is scaled by the
+	// selectivity of its searches.
+	RowGroupAutoTuneForTenant func(tenantID string) bool `yaml:"-"`
+
 	BlocklistPoll                          time.Duration `yaml:"blocklist_poll"`
 	BlocklistPollConcurrency               uint          `yaml:"blocklist_poll_concurrency"`
 	BlocklistPollTenantConcurrency         uint          `yaml:"blocklist_poll_tenant_concurrency"`
@@ -60,12 +78,15 @@ type Config struct {
 	EmptyTenantDeletionEnabled bool          `yaml:"empty_tenant_deletion_enabled"`
 	EmptyTenantDeletionAge     time.Duration `yaml:"empty_tenant_deletion_age"`
 
+	IOScheduler scheduler.Config `yaml:"io_scheduler"`
+
 	// backends
 	Backend string        `yaml:"backend"`
 	Local   *local.Config `yaml:"local"`
 	GCS     *gcs.Config   `yaml:"gcs"`
 	S3      *s3.Config    `yaml:"s3"`
 	Azure   *azure.Config `yaml:"azure"`
+	Swift   *swift.Config `yaml:"swift"`
 
 	// legacy cache config. this is loaded by tempodb and added to the cache
 	// provider on construction
@@ -124,17 +145,32 @@ func (c SearchConfig) ApplyToOptions(o *common.SearchOptions) {
 
 // CompactorConfig contains compaction configuration options
 type CompactorConfig struct {
-	ChunkSizeBytes          uint32        `yaml:"v2_in_buffer_bytes"`
-	FlushSizeBytes          uint32        `yaml:"v2_out_buffer_bytes"`
-	IteratorBufferSize      int           `yaml:"v2_prefetch_traces_count"`
-	MaxCompactionRange      time.Duration `yaml:"compaction_window"`
-	MaxCompactionObjects    int           `yaml:"max_compaction_objects"`
-	MaxBlockBytes           uint64        `yaml:"max_block_bytes"`
+	ChunkSizeBytes       uint32        `yaml:"v2_in_buffer_bytes"`
+	FlushSizeBytes       uint32        `yaml:"v2_out_buffer_bytes"`
+	IteratorBufferSize   int           `yaml:"v2_prefetch_traces_count"`
+	MaxCompactionRange   time.Duration `yaml:"compaction_window"`
+	MaxCompactionObjects int           `yaml:"max_compaction_objects"`
+	MaxBlockBytes        uint64        `yaml:"max_block_bytes"`
+	// MaxJobMemoryBytes limits the estimated memory of a compaction job. 0 disables the limit.
+	MaxJobMemoryBytes uint64 `yaml:"max_job_memory_bytes"`
+	// PartitionAttribute is a resource attribute the compacted blocks are partitioned by. Empty disables partitioning.
+	PartitionAttribute      string        `yaml:"partition_attribute"`
+	MaxPartitions           int           `yaml:"max_partitions"`
 	BlockRetention          time.Duration `yaml:"block_retention"`
+	RetentionFloor          time.Duration `yaml:"retention_floor"`
 	CompactedBlockRetention time.Duration `yaml:"compacted_block_retention"`
 	RetentionConcurrency    uint          `yaml:"retention_concurrency"`
+	CompactionConcurrency   uint          `yaml:"compaction_concurrency"`
 	MaxTimePerTenant        time.Duration `yaml:"max_time_per_tenant"`
 	CompactionCycle         time.Duration `yaml:"compaction_cycle"`
+	TombstoneGracePeriod    time.Duration `yaml:"tombstone_grace_period"`
+	// CompactionSchedule restricts merging blocks to a recurring time window. Blocks are merged at all times if it's
+	// nil. Retention and deletion requests are applied at all times.
+	CompactionSchedule *util.TimeWindow `yaml:"compaction_schedule,omitempty"`
+
+	// DryRun disables compaction and retention. The compaction plan of every tenant is logged instead. It's set
+	// by the compactor config.
+	DryRun bool `yaml:"-"`
 }
 
 func (compactorConfig CompactorConfig) validate() error {
@@ -142,6 +178,20 @@ func (compactorConfig CompactorConfig) validate() error {
 		return errors.New("Compaction window can't be 0")
 	}
 
+	if compactorConfig.RetentionFloor > 0 && compactorConfig.BlockRetention < compactorConfig.RetentionFloor {
+		return fmt.Errorf("block retention %s can't be lower than the retention floor %s", compactorConfig.BlockRetention, compactorConfig.RetentionFloor)
+	}
+
+	if compactorConfig.CompactionSchedule != nil {
+		if err := compactorConfig.CompactionSchedule.Parse(); err != nil {
+			return fmt.Errorf("invalid compaction schedule: %w", err)
+		}
+	}
+
+	if compactorConfig.PartitionAttribute != "" && compactorConfig.MaxPartitions <= 0 {
+		return errors.New("max partitions must be greater than 0 when partitioning compacted blocks")
+	}
+
 	return nil
 }
 
@@ -178,5 +228,10 @@ func validateConfig(cfg *Config) error {
 		return fmt.Errorf("block version validation failed: %w", err)
 	}
 
+	err = cfg.IOScheduler.Validate()
+	if err != nil {
+		return fmt.Errorf("io scheduler config validation failed: %w", err)
+	}
+
 	return nil
 }
@@ -3,7 +3,9 @@ package tempodb
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/grafana/tempo/tempodb/wal"
 	"github.com/stretchr/testify/assert"
@@ -187,3 +189,26 @@ func TestValidateCompactorConfig(t *testing.T) {
 
 	require.Equal(t, expected, actual)
 }
+
+func TestValidateCompactorConfigRetentionFloor(t *testing.T) {
+	compactorConfig := CompactorConfig{
+		MaxCompactionRange: time.Hour,
+		BlockRetention:     time.Hour,
+		RetentionFloor:     2 * time.Hour,
+	}
+	require.EqualError(t, compactorConfig.validate(), "block retention 1h0m0s can't be lower than the retention floor 2h0m0s")
+
+	compactorConfig.BlockRetention = 2 * time.Hour
+	require.NoError(t, compactorConfig.validate())
+}
+
+func TestValidateCompactorConfigCompactionSchedule(t *testing.T) {
+	compactorConfig := CompactorConfig{
+		MaxCompactionRange: time.Hour,
+		CompactionSchedule: &util.TimeWindow{Start: "02:00", End: "06:00", Timezone: "UTC"},
+	}
+	require.NoError(t, compactorConfig.validate())
+
+	compactorConfig.CompactionSchedule = &util.TimeWindow{Start: "2am", End: "6am"}
+	require.EqualError(t, compactorConfig.validate(), `invalid compaction schedule: invalid start: "2am" is not in the format hh:mm`)
+}
@@ -4,6 +4,7 @@ import (
 	"flag"
 	"fmt"
 
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/tempodb/backend"
 )
@@ -30,9 +31,15 @@ type BlockConfig struct {
 
 	// parquet fields
 	RowGroupSizeBytes int `yaml:"parquet_row_group_size_bytes"`
+	// RowGroupSizeSpans cuts a row group once it has this many spans. 0 disables the limit. Only used by vParquet4.
+	RowGroupSizeSpans int `yaml:"parquet_row_group_size_spans"`
 
 	// vParquet3 fields
 	DedicatedColumns backend.DedicatedColumns `yaml:"parquet_dedicated_columns"`
+
+	// TagValuesIndex are the attributes whose distinct values are indexed per block for tag value lookups. Only used
+	// by vParquet4.
+	TagValuesIndex []string `yaml:"parquet_tag_values_index"`
 }
 
 func (cfg *BlockConfig) RegisterFlagsAndApplyDefaults(prefix string, f *flag.FlagSet) {
@@ -65,5 +72,19 @@ func ValidateConfig(b *BlockConfig) error {
 		return fmt.Errorf("positive value required for bloom-filter shard size")
 	}
 
+	if b.RowGroupSizeSpans < 0 {
+		return fmt.Errorf("row group size in spans can't be negative")
+	}
+
+	for _, name := range b.TagValuesIndex {
+		att, err := traceql.ParseIdentifier(name)
+		if err != nil {
+			return fmt.Errorf("invalid tag values index attribute: %w", err)
+		}
+		if att.Intrinsic != traceql.IntrinsicNone {
+			return fmt.Errorf("tag values index attribute %s is an intrinsic, only attributes are supported", name)
+		}
+	}
+
 	return b.DedicatedColumns.Validate()
 }
@@ -79,6 +79,15 @@ type CompactionOptions struct {
 	BlockConfig        BlockConfig
 	Combiner           model.ObjectCombiner
 
+	// SpanCombineStrategy controls how duplicate spans with different contents are combined.
+	SpanCombineStrategy SpanCombineStrategy
+
+	// PartitionAttribute is a resource attribute the output blocks are partitioned by, so that the traces of every
+	// value are written to their own blocks. Up to MaxPartitions values are partitioned, traces with other values
+	// share blocks. Only supported by vParquet4 and only for attributes stored in well-known or dedicated columns.
+	PartitionAttribute string
+	MaxPartitions      int
+
 	// DropObject can be used to drop a trace from the compaction process. Currently it only receives the ID
 	// of the trace to be compacted. If the function returns true, the trace will be dropped.
 	DropObject func(ID) bool
@@ -105,6 +114,11 @@ type BackendBlock interface {
 	Validate(ctx context.Context) error
 }
 
+// TraceIterable is implemented by backend blocks that support iterating over every trace they contain.
+type TraceIterable interface {
+	TraceIterator(ctx context.Context) (Iterator, error)
+}
+
 // WALBlock represents a Write-Ahead Log (WAL) block interface that extends the BackendBlock interface.
 // It provides methods to append traces, manage ingestion slack, flush data, and iterate over the block's data.
 type WALBlock interface {
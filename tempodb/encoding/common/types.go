@@ -2,6 +2,7 @@ package common
 
 import (
 	"bytes"
+	"fmt"
 	"hash"
 	"hash/fnv"
 	"sort"
@@ -71,3 +72,41 @@ func (m *IDMap[T]) EntriesSortedByID() []IDMapEntry[T] {
 
 	return entries
 }
+
+// SpanCombineStrategy controls how spans with the same ID and kind but different contents are
+// combined when traces are compacted.
+type SpanCombineStrategy string
+
+const (
+	// SpanCombineStrategyFirst keeps the span that was encountered first. This is the default.
+	SpanCombineStrategyFirst SpanCombineStrategy = "first"
+	// SpanCombineStrategyNewest keeps the span with the latest end time.
+	SpanCombineStrategyNewest SpanCombineStrategy = "newest"
+	// SpanCombineStrategyMergeAttributes keeps the first span and adds the attributes of the
+	// other spans that it doesn't have yet.
+	SpanCombineStrategyMergeAttributes SpanCombineStrategy = "merge_attributes"
+	// SpanCombineStrategyKeepAll keeps every span with distinct contents. Identical spans are
+	// still deduped.
+	SpanCombineStrategyKeepAll SpanCombineStrategy = "keep_all"
+)
+
+var SupportedSpanCombineStrategies = []SpanCombineStrategy{
+	SpanCombineStrategyFirst,
+	SpanCombineStrategyNewest,
+	SpanCombineStrategyMergeAttributes,
+	SpanCombineStrategyKeepAll,
+}
+
+// Validate returns an error if the strategy is not supported. An empty strategy is valid and
+// means SpanCombineStrategyFirst.
+func (s SpanCombineStrategy) Validate() error {
+	if s == "" {
+		return nil
+	}
+	for _, supported := range SupportedSpanCombineStrategies {
+		if s == supported {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown span combine strategy %q, valid values: %v", s, SupportedSpanCombineStrategies)
+}
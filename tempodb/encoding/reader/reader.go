@@ -0,0 +1,164 @@
+// Package reader is the supported entry point for reading Tempo blocks from outside of Tempo. It wraps the
+// versioned block encodings so that external tools (exporters, connectors, offline analysis) can open a block
+// from any supported backend, iterate its traces, look up traces by ID and run TraceQL against it without
+// depending on encoding internals.
+//
+// A typical use looks like:
+//
+//	r, err := reader.NewBackendReader(reader.BackendConfig{Backend: backend.Local, Local: &local.Config{Path: "/var/tempo"}})
+//	blk, err := reader.OpenBlock(ctx, r, "single-tenant", blockID)
+//	resp, err := blk.Search(ctx, &tempopb.SearchRequest{Query: `{ resource.service.name = "api" }`, Limit: 20})
+package reader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/azure"
+	"github.com/grafana/tempo/tempodb/backend/gcs"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/swift"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+// ErrIterationUnsupported is returned by Block.Traces for block versions that cannot be iterated.
+var ErrIterationUnsupported = errors.New("trace iteration is not supported for this block version")
+
+// BackendConfig selects and configures the object store that holds the blocks. Only the section
+// matching Backend needs to be set.
+type BackendConfig struct {
+	Backend string        `yaml:"backend"`
+	Local   *local.Config `yaml:"local"`
+	GCS     *gcs.Config   `yaml:"gcs"`
+	S3      *s3.Config    `yaml:"s3"`
+	Azure   *azure.Config `yaml:"azure"`
+	Swift   *swift.Config `yaml:"swift"`
+}
+
+// NewBackendReader creates a backend reader for the given config. Where supported the backend is created
+// without confirming write access to the bucket, so read-only credentials are sufficient.
+func NewBackendReader(cfg BackendConfig) (backend.Reader, error) {
+	var (
+		r   backend.RawReader
+		err error
+	)
+
+	switch cfg.Backend {
+	case backend.Local:
+		if cfg.Local == nil {
+			return nil, errors.New("local backend selected but no local config provided")
+		}
+		r, _, _, err = local.New(cfg.Local)
+	case backend.GCS:
+		if cfg.GCS == nil {
+			return nil, errors.New("gcs backend selected but no gcs config provided")
+		}
+		r, _, _, err = gcs.NewNoConfirm(cfg.GCS)
+	case backend.S3:
+		if cfg.S3 == nil {
+			return nil, errors.New("s3 backend selected but no s3 config provided")
+		}
+		r, _, _, err = s3.NewNoConfirm(cfg.S3)
+	case backend.Azure:
+		if cfg.Azure == nil {
+			return nil, errors.New("azure backend selected but no azure config provided")
+		}
+		r, _, _, err = azure.NewNoConfirm(cfg.Azure)
+	case backend.Swift:
+		if cfg.Swift == nil {
+			return nil, errors.New("swift backend selected but no swift config provided")
+		}
+		r, _, _, err = swift.NewNoConfirm(cfg.Swift)
+	default:
+		err = fmt.Errorf("unknown backend %s", cfg.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.NewReader(r), nil
+}
+
+// Tenants returns all tenants that have data in the backend.
+func Tenants(ctx context.Context, r backend.Reader) ([]string, error) {
+	return r.Tenants(ctx)
+}
+
+// Blocks returns the IDs of all live (not compacted) blocks of the tenant.
+func Blocks(ctx context.Context, r backend.Reader, tenantID string) ([]uuid.UUID, error) {
+	ids, _, err := r.Blocks(ctx, tenantID)
+	return ids, err
+}
+
+// Block is a read-only handle to a single block in the backend.
+type Block struct {
+	block common.BackendBlock
+	opts  common.SearchOptions
+}
+
+// OpenBlock reads the meta of the given block and opens it with the encoding it was written with.
+func OpenBlock(ctx context.Context, r backend.Reader, tenantID string, blockID uuid.UUID) (*Block, error) {
+	meta, err := r.BlockMeta(ctx, blockID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("error reading meta for block %s: %w", blockID, err)
+	}
+
+	return OpenBlockWithMeta(meta, r)
+}
+
+// OpenBlockWithMeta opens a block for which the meta has already been read, e.g. from the tenant index.
+func OpenBlockWithMeta(meta *backend.BlockMeta, r backend.Reader) (*Block, error) {
+	blk, err := encoding.OpenBlock(meta, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Block{
+		block: blk,
+		opts:  common.DefaultSearchOptions(),
+	}, nil
+}
+
+// WithSearchOptions overrides the options used to read the block, e.g. to tune buffer sizes or to
+// restrict reads to a subset of pages.
+func (b *Block) WithSearchOptions(opts common.SearchOptions) *Block {
+	b.opts = opts
+	return b
+}
+
+// Meta returns the block meta.
+func (b *Block) Meta() *backend.BlockMeta {
+	return b.block.BlockMeta()
+}
+
+// FindTraceByID returns the trace with the given ID or nil if the block does not contain it.
+func (b *Block) FindTraceByID(ctx context.Context, id common.ID) (*tempopb.Trace, error) {
+	return b.block.FindTraceByID(ctx, id, b.opts)
+}
+
+// Traces returns an iterator over all traces in the block in trace ID order. Next returns a nil trace once
+// the block is exhausted. The iterator must be closed by the caller.
+func (b *Block) Traces(ctx context.Context) (common.Iterator, error) {
+	it, ok := b.block.(common.TraceIterable)
+	if !ok {
+		return nil, ErrIterationUnsupported
+	}
+	return it.TraceIterator(ctx)
+}
+
+// Search runs the TraceQL query in req against this block only.
+func (b *Block) Search(ctx context.Context, req *tempopb.SearchRequest) (*tempopb.SearchResponse, error) {
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.block.Fetch(ctx, req, b.opts)
+	})
+
+	return traceql.NewEngine().ExecuteSearch(ctx, req, fetcher)
+}
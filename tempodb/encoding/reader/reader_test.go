@@ -0,0 +1,137 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const testTenant = "test"
+
+func TestReader(t *testing.T) {
+	ctx := context.Background()
+	cfg := BackendConfig{
+		Backend: backend.Local,
+		Local:   &local.Config{Path: t.TempDir()},
+	}
+
+	ids, traces := writeTestBlock(t, cfg, 10)
+
+	r, err := NewBackendReader(cfg)
+	require.NoError(t, err)
+
+	tenants, err := Tenants(ctx, r)
+	require.NoError(t, err)
+	require.Equal(t, []string{testTenant}, tenants)
+
+	blockIDs, err := Blocks(ctx, r, testTenant)
+	require.NoError(t, err)
+	require.Len(t, blockIDs, 1)
+
+	blk, err := OpenBlock(ctx, r, testTenant, blockIDs[0])
+	require.NoError(t, err)
+	require.Equal(t, encoding.DefaultEncoding().Version(), blk.Meta().Version)
+
+	// find
+	for i, id := range ids {
+		tr, err := blk.FindTraceByID(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, tr)
+		require.Equal(t, len(traces[i].ResourceSpans), len(tr.ResourceSpans))
+	}
+
+	// iterate
+	it, err := blk.Traces(ctx)
+	require.NoError(t, err)
+	defer it.Close()
+
+	var iterated []common.ID
+	for {
+		id, tr, err := it.Next(ctx)
+		require.NoError(t, err)
+		if tr == nil {
+			break
+		}
+		iterated = append(iterated, append(common.ID(nil), id...))
+	}
+	require.Equal(t, ids, iterated)
+
+	// search
+	resp, err := blk.Search(ctx, &tempopb.SearchRequest{Query: `{ resource.service.name = "test-service" }`, Limit: 100})
+	require.NoError(t, err)
+	require.Len(t, resp.Traces, len(ids))
+
+	resp, err = blk.Search(ctx, &tempopb.SearchRequest{Query: `{ resource.service.name = "does-not-exist" }`, Limit: 100})
+	require.NoError(t, err)
+	require.Len(t, resp.Traces, 0)
+}
+
+func TestNewBackendReaderErrors(t *testing.T) {
+	_, err := NewBackendReader(BackendConfig{Backend: "unknown"})
+	require.Error(t, err)
+
+	_, err = NewBackendReader(BackendConfig{Backend: backend.S3})
+	require.Error(t, err)
+}
+
+func writeTestBlock(t *testing.T, cfg BackendConfig, count int) ([]common.ID, []*tempopb.Trace) {
+	rawR, rawW, _, err := local.New(cfg.Local)
+	require.NoError(t, err)
+
+	ids := make([]common.ID, 0, count)
+	for i := 0; i < count; i++ {
+		ids = append(ids, test.ValidTraceID(nil))
+	}
+	sort.Slice(ids, func(i, j int) bool { return bytes.Compare(ids[i], ids[j]) < 0 })
+
+	traces := make([]*tempopb.Trace, 0, count)
+	for _, id := range ids {
+		traces = append(traces, test.MakeTrace(2, id))
+	}
+
+	enc := encoding.DefaultEncoding()
+	meta := backend.NewBlockMeta(testTenant, uuid.New(), enc.Version(), backend.EncNone, "")
+	meta.TotalObjects = int64(count)
+	meta.StartTime = time.Unix(0, 0)
+	meta.EndTime = time.Now()
+
+	blockCfg := &common.BlockConfig{
+		BloomFP:             0.01,
+		BloomShardSizeBytes: 100 * 1024,
+		Version:             enc.Version(),
+		RowGroupSizeBytes:   10 * 1024 * 1024,
+	}
+
+	_, err = enc.CreateBlock(context.Background(), blockCfg, meta, &sliceIterator{ids: ids, traces: traces}, backend.NewReader(rawR), backend.NewWriter(rawW))
+	require.NoError(t, err)
+
+	return ids, traces
+}
+
+type sliceIterator struct {
+	ids    []common.ID
+	traces []*tempopb.Trace
+	i      int
+}
+
+func (s *sliceIterator) Next(context.Context) (common.ID, *tempopb.Trace, error) {
+	if s.i >= len(s.ids) {
+		return nil, nil, nil
+	}
+	s.i++
+	return s.ids[s.i-1], s.traces[s.i-1], nil
+}
+
+func (s *sliceIterator) Close() {}
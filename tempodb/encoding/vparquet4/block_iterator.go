@@ -10,6 +10,8 @@ import (
 
 	tempo_io "github.com/grafana/tempo/pkg/io"
 	"github.com/grafana/tempo/pkg/parquetquery"
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
@@ -93,3 +95,42 @@ func (i *rawIterator) peekNextID(context.Context) (common.ID, error) { // nolint
 func (i *rawIterator) Close() {
 	i.r.Close()
 }
+
+// TraceIterator returns an iterator over all traces in the block in trace ID order. The returned
+// traces are fully materialized and detached from the underlying parquet rows.
+func (b *backendBlock) TraceIterator(ctx context.Context) (common.Iterator, error) {
+	pf, _, err := b.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &traceIterator{
+		meta: b.meta,
+		r:    parquet.NewGenericReader[*Trace](pf),
+	}, nil
+}
+
+type traceIterator struct {
+	meta *backend.BlockMeta
+	r    *parquet.GenericReader[*Trace]
+}
+
+var _ common.Iterator = (*traceIterator)(nil)
+
+func (i *traceIterator) Next(context.Context) (common.ID, *tempopb.Trace, error) {
+	traces := []*Trace{{}}
+
+	n, err := i.r.Read(traces)
+	if n > 0 {
+		return traces[0].TraceID, parquetTraceToTempopbTrace(i.meta, traces[0]), nil
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, nil, fmt.Errorf("error iterating through block %s: %w", i.meta.BlockID.String(), err)
+	}
+
+	return nil, nil, nil
+}
+
+func (i *traceIterator) Close() {
+	_ = i.r.Close()
+}
@@ -243,12 +243,22 @@ func (b *backendBlock) SearchTagValuesV2(ctx context.Context, tag traceql.Attrib
 	if err != nil {
 		return fmt.Errorf("unexpected error opening parquet file: %w", err)
 	}
+	var indexBytes uint64
 	// capture bytes read into metrics callback and span
 	defer func() {
-		mcb(rr.BytesRead()) // report bytes read
-		span.SetAttributes(attribute.Int64("inspectedBytes", int64(rr.BytesRead())))
+		mcb(rr.BytesRead() + indexBytes) // report bytes read
+		span.SetAttributes(attribute.Int64("inspectedBytes", int64(rr.BytesRead()+indexBytes)))
 	}()
 
+	found, indexBytes, err := b.searchIndexedTagValues(derivedCtx, pf, tag, cb)
+	if err != nil {
+		return err
+	}
+	if found {
+		span.SetAttributes(attribute.Bool("tagValuesIndex", true))
+		return nil
+	}
+
 	return searchTagValues(derivedCtx, tag, cb, pf, b.meta.DedicatedColumns)
 }
 
@@ -116,6 +116,12 @@ func (s *span) AllAttributesFunc(cb func(traceql.Attribute, traceql.Static)) {
 	}
 }
 
+func (s *span) LinkAttributesFunc(cb func(traceql.Attribute, traceql.Static)) {
+	for _, a := range s.linkAttrs {
+		cb(a.a, a.s)
+	}
+}
+
 func (s *span) AttributeFor(a traceql.Attribute) (traceql.Static, bool) {
 	find := func(a traceql.Attribute, attrs []attrVal) *traceql.Static {
 		if len(attrs) == 1 {
@@ -686,6 +686,47 @@ func fullyPopulatedTestTrace(id common.ID) *Trace {
 	}
 }
 
+func TestBackendBlockSearchTraceQLLinks(t *testing.T) {
+	ctx := context.Background()
+
+	tr := fullyPopulatedTestTrace(test.ValidTraceID(nil))
+	linkTraceID, _ := util.HexStringToTraceID("fedcba0987654321fedcba0987654321")
+	linkSpanID, _ := util.HexStringToSpanID("fedcba0987654321")
+	sp := &tr.ResourceSpans[0].ScopeSpans[0].Spans[0]
+	sp.Links = append(sp.Links, Link{
+		TraceID: linkTraceID,
+		SpanID:  linkSpanID,
+		Attrs:   []Attribute{attr("messaging.operation", "publish")},
+	})
+
+	b := makeBackendBlockWithTraces(t, []*Trace{tr})
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return b.Fetch(ctx, req, common.DefaultSearchOptions())
+	})
+
+	for _, q := range []string{
+		`{ link.messaging.operation = "publish" } | select(link:traceID, link:spanID)`,
+		`{ name = "hello" } | select(link:traceID, link:spanID)`,
+	} {
+		t.Run(q, func(t *testing.T) {
+			res, err := traceql.NewEngine().ExecuteSearch(ctx, &tempopb.SearchRequest{Query: q}, fetcher)
+			require.NoError(t, err)
+			require.Len(t, res.Traces, 1)
+			require.Len(t, res.Traces[0].SpanSet.Spans, 1)
+
+			// the ids of all links of the span are returned
+			attrs := map[string][]string{}
+			for _, kv := range res.Traces[0].SpanSet.Spans[0].Attributes {
+				for _, v := range kv.Value.GetArrayValue().GetValues() {
+					attrs[kv.Key] = append(attrs[kv.Key], v.GetStringValue())
+				}
+			}
+			require.ElementsMatch(t, []string{"1234567890abcdef1234567890abcdef", "fedcba0987654321fedcba0987654321"}, attrs["link:traceID"])
+			require.ElementsMatch(t, []string{"1234567890abcdef", "fedcba0987654321"}, attrs["link:spanID"])
+		})
+	}
+}
+
 func TestBackendBlockSelectAll(t *testing.T) {
 	var (
 		ctx          = context.Background()
@@ -2,9 +2,11 @@ package vparquet4
 
 import (
 	"bytes"
+	"reflect"
 	"sort"
 
 	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 func combineTraces(traces ...*Trace) *Trace {
@@ -21,21 +23,33 @@ func combineTraces(traces ...*Trace) *Trace {
 }
 
 // Combiner combines multiple partial traces into one, deduping spans based on
-// ID and kind.  Note that it is destructive. There are design decisions for
+// ID and kind. Duplicate spans with different contents are combined according
+// to a SpanCombineStrategy, by default the first span wins. Note that it is destructive. There are design decisions for
 // efficiency:
 // * Only scan/hash the spans for each input once, which is reused across calls.
 // * Only sort the final result once and if needed.
 // * Don't scan/hash the spans for the last input (final=true).
 type Combiner struct {
 	result   *Trace
-	spans    map[uint64]struct{}
+	spans    map[uint64]*Span
 	combined bool
+
+	strategy common.SpanCombineStrategy
+	// variants holds the additional spans kept for an ID with the keep_all strategy
+	variants map[uint64][]*Span
+	kept     int
 }
 
 func NewCombiner() *Combiner {
 	return &Combiner{}
 }
 
+// NewCombinerWithStrategy returns a combiner that uses the given strategy to combine spans with
+// the same ID and kind but different contents.
+func NewCombinerWithStrategy(strategy common.SpanCombineStrategy) *Combiner {
+	return &Combiner{strategy: strategy}
+}
+
 // Consume the given trace and destructively combines its contents.
 func (c *Combiner) Consume(tr *Trace) (spanCount int) {
 	return c.ConsumeWithFinal(tr, false)
@@ -61,12 +75,13 @@ func (c *Combiner) ConsumeWithFinal(tr *Trace, final bool) (spanCount int) {
 				n += len(ils.Spans)
 			}
 		}
-		c.spans = make(map[uint64]struct{}, n)
+		c.spans = make(map[uint64]*Span, n)
 
 		for _, b := range c.result.ResourceSpans {
 			for _, ils := range b.ScopeSpans {
-				for _, s := range ils.Spans {
-					c.spans[util.SpanIDAndKindToToken(s.SpanID, s.Kind)] = struct{}{}
+				for i := range ils.Spans {
+					s := &ils.Spans[i]
+					c.spans[util.SpanIDAndKindToToken(s.SpanID, s.Kind)] = s
 				}
 			}
 		}
@@ -97,16 +112,32 @@ func (c *Combiner) ConsumeWithFinal(tr *Trace, final bool) (spanCount int) {
 			for _, s := range ils.Spans {
 				// if not already encountered, then keep
 				token := util.SpanIDAndKindToToken(s.SpanID, s.Kind)
-				_, ok := c.spans[token]
+				existing, ok := c.spans[token]
 				if !ok {
 					notFoundSpans = append(notFoundSpans, s)
 
 					// If last expected input, then we don't need to record
 					// the visited spans. Optimization has significant savings.
 					if !final {
-						c.spans[token] = struct{}{}
+						c.spans[token] = &notFoundSpans[len(notFoundSpans)-1]
+					}
+					continue
+				}
+
+				if c.strategy == common.SpanCombineStrategyKeepAll && c.isNewVariant(token, existing, &s) {
+					notFoundSpans = append(notFoundSpans, s)
+					c.kept++
+
+					if !final {
+						if c.variants == nil {
+							c.variants = map[uint64][]*Span{}
+						}
+						c.variants[token] = append(c.variants[token], &notFoundSpans[len(notFoundSpans)-1])
 					}
+					continue
 				}
+
+				c.combineSpan(existing, &s)
 			}
 
 			if len(notFoundSpans) > 0 {
@@ -137,12 +168,94 @@ func (c *Combiner) Result() (*Trace, int, bool) {
 		// Only if anything combined
 		SortTrace(c.result)
 		connected = assignNestedSetModelBoundsAndServiceStats(c.result)
-		spanCount = len(c.spans)
+		spanCount = len(c.spans) + c.kept
 	}
 
 	return c.result, spanCount, connected
 }
 
+// combineSpan combines the duplicate span s into the existing span according to the strategy
+// of the combiner.
+func (c *Combiner) combineSpan(existing, s *Span) {
+	switch c.strategy {
+	case common.SpanCombineStrategyNewest:
+		// ties go to the span consumed last
+		if s.StartTimeUnixNano+s.DurationNano >= existing.StartTimeUnixNano+existing.DurationNano {
+			*existing = *s
+		}
+	case common.SpanCombineStrategyMergeAttributes:
+		mergeSpanAttributes(existing, s)
+	}
+}
+
+// isNewVariant returns true if s differs from the existing span and all other spans kept for
+// the same token.
+func (c *Combiner) isNewVariant(token uint64, existing, s *Span) bool {
+	if spanContentsEqual(existing, s) {
+		return false
+	}
+	for _, v := range c.variants[token] {
+		if spanContentsEqual(v, s) {
+			return false
+		}
+	}
+	return true
+}
+
+// spanContentsEqual compares two spans ignoring the nested set model fields, which depend on
+// the rest of the trace the span was stored with.
+func spanContentsEqual(a, b *Span) bool {
+	ca, cb := *a, *b
+	ca.ParentID, ca.NestedSetLeft, ca.NestedSetRight = 0, 0, 0
+	cb.ParentID, cb.NestedSetLeft, cb.NestedSetRight = 0, 0, 0
+	return reflect.DeepEqual(ca, cb)
+}
+
+// mergeSpanAttributes adds the attributes of src that are missing in dst. Attributes that are
+// present in both keep the value of dst.
+func mergeSpanAttributes(dst, src *Span) {
+	for _, a := range src.Attrs {
+		found := false
+		for _, b := range dst.Attrs {
+			if a.Key == b.Key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			dst.Attrs = append(dst.Attrs, a)
+		}
+	}
+
+	if dst.HttpMethod == nil {
+		dst.HttpMethod = src.HttpMethod
+	}
+	if dst.HttpUrl == nil {
+		dst.HttpUrl = src.HttpUrl
+	}
+	if dst.HttpStatusCode == nil {
+		dst.HttpStatusCode = src.HttpStatusCode
+	}
+
+	dstDedicated := []**string{
+		&dst.DedicatedAttributes.String01, &dst.DedicatedAttributes.String02, &dst.DedicatedAttributes.String03,
+		&dst.DedicatedAttributes.String04, &dst.DedicatedAttributes.String05, &dst.DedicatedAttributes.String06,
+		&dst.DedicatedAttributes.String07, &dst.DedicatedAttributes.String08, &dst.DedicatedAttributes.String09,
+		&dst.DedicatedAttributes.String10,
+	}
+	srcDedicated := []*string{
+		src.DedicatedAttributes.String01, src.DedicatedAttributes.String02, src.DedicatedAttributes.String03,
+		src.DedicatedAttributes.String04, src.DedicatedAttributes.String05, src.DedicatedAttributes.String06,
+		src.DedicatedAttributes.String07, src.DedicatedAttributes.String08, src.DedicatedAttributes.String09,
+		src.DedicatedAttributes.String10,
+	}
+	for i := range dstDedicated {
+		if *dstDedicated[i] == nil {
+			*dstDedicated[i] = srcDedicated[i]
+		}
+	}
+}
+
 // SortTrace sorts a parquet *Trace
 func SortTrace(t *Trace) {
 	// Sort bottom up by span start times
@@ -5,9 +5,11 @@ import (
 
 	"github.com/dustin/go-humanize"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
 )
 
 func TestCombiner(t *testing.T) {
@@ -516,6 +518,99 @@ func TestCombinerReturnsDuplicates(t *testing.T) {
 	}
 }
 
+func TestCombinerSpanCombineStrategy(t *testing.T) {
+	spanID := []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01}
+	method := "GET"
+
+	makeTrace := func(end uint64, attrs ...Attribute) *Trace {
+		return &Trace{
+			TraceID: []byte{0x00, 0x01},
+			ResourceSpans: []ResourceSpans{{
+				Resource: Resource{ServiceName: "svc"},
+				ScopeSpans: []ScopeSpans{{
+					Spans: []Span{{
+						SpanID:            spanID,
+						StartTimeUnixNano: 10,
+						DurationNano:      end - 10,
+						Attrs:             attrs,
+					}},
+				}},
+			}},
+		}
+	}
+
+	tests := []struct {
+		strategy      common.SpanCombineStrategy
+		traces        func() []*Trace
+		expectedSpans []Span
+		expectedTotal int
+	}{
+		{
+			strategy: common.SpanCombineStrategyFirst,
+			traces: func() []*Trace {
+				return []*Trace{makeTrace(20, attr("a", "1")), makeTrace(30, attr("b", "2"))}
+			},
+			expectedSpans: []Span{{SpanID: spanID, StartTimeUnixNano: 10, DurationNano: 10, Attrs: []Attribute{attr("a", "1")}}},
+			expectedTotal: 1,
+		},
+		{
+			strategy: common.SpanCombineStrategyNewest,
+			traces: func() []*Trace {
+				return []*Trace{makeTrace(30, attr("a", "1")), makeTrace(20, attr("b", "2")), makeTrace(30, attr("c", "3"))}
+			},
+			expectedSpans: []Span{{SpanID: spanID, StartTimeUnixNano: 10, DurationNano: 20, Attrs: []Attribute{attr("c", "3")}}},
+			expectedTotal: 1,
+		},
+		{
+			strategy: common.SpanCombineStrategyMergeAttributes,
+			traces: func() []*Trace {
+				b := makeTrace(20, attr("a", "2"), attr("b", "2"))
+				b.ResourceSpans[0].ScopeSpans[0].Spans[0].HttpMethod = &method
+				return []*Trace{makeTrace(20, attr("a", "1")), b}
+			},
+			expectedSpans: []Span{{SpanID: spanID, StartTimeUnixNano: 10, DurationNano: 10, Attrs: []Attribute{attr("a", "1"), attr("b", "2")}, HttpMethod: &method}},
+			expectedTotal: 1,
+		},
+		{
+			strategy: common.SpanCombineStrategyKeepAll,
+			traces: func() []*Trace {
+				return []*Trace{makeTrace(20, attr("a", "1")), makeTrace(20, attr("b", "2")), makeTrace(20, attr("b", "2")), makeTrace(20, attr("a", "1"))}
+			},
+			expectedSpans: []Span{
+				{SpanID: spanID, StartTimeUnixNano: 10, DurationNano: 10, Attrs: []Attribute{attr("a", "1")}},
+				{SpanID: spanID, StartTimeUnixNano: 10, DurationNano: 10, Attrs: []Attribute{attr("b", "2")}},
+			},
+			expectedTotal: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			for _, final := range []bool{false, true} {
+				traces := tt.traces()
+
+				c := NewCombinerWithStrategy(tt.strategy)
+				for i, tr := range traces {
+					c.ConsumeWithFinal(tr, final && i == len(traces)-1)
+				}
+				actual, total, _ := c.Result()
+				require.Equal(t, tt.expectedTotal, total)
+
+				var spans []Span
+				for _, rs := range actual.ResourceSpans {
+					for _, ss := range rs.ScopeSpans {
+						for _, s := range ss.Spans {
+							s.ParentID, s.NestedSetLeft, s.NestedSetRight = 0, 0, 0
+							spans = append(spans, s)
+						}
+					}
+				}
+				require.Equal(t, tt.expectedSpans, spans)
+			}
+		})
+	}
+}
+
 func BenchmarkCombine(b *testing.B) {
 	batchCount := 100
 	spanCounts := []int{
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"runtime"
+	"sort"
 	"sync"
 	"time"
 
@@ -110,7 +111,7 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 		}
 
 		// Time to combine.
-		cmb := NewCombiner()
+		cmb := NewCombinerWithStrategy(c.opts.SpanCombineStrategy)
 		dedupedSpans := 0
 		for i, row := range rows {
 			tr := new(Trace)
@@ -134,10 +135,16 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 		return sch.Deconstruct(pool.Get(), tr), nil
 	}
 
+	partitioner, ok := newPartitioner(sch, c.opts.PartitionAttribute, inputs[0].DedicatedColumns, c.opts.MaxPartitions)
+	if !ok {
+		level.Warn(l).Log("msg", "partition attribute is not stored in a dedicated column, compacted blocks are not partitioned", "attribute", c.opts.PartitionAttribute)
+	}
+
 	var (
 		m               = newMultiblockIterator(bookmarks, combine)
 		recordsPerBlock = (totalRecords / int64(c.opts.OutputBlocks))
-		currentBlock    *streamingBlock
+		// the block that is being written for every partition
+		currentBlocks = map[string]*streamingBlock{}
 	)
 	defer m.Close()
 
@@ -155,6 +162,9 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 			continue
 		}
 
+		partition := partitioner.partition(lowestObject)
+		currentBlock := currentBlocks[partition]
+
 		// make a new block if necessary
 		if currentBlock == nil {
 			// Start with a copy and then customize
@@ -170,6 +180,7 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 			currentBlock = newStreamingBlock(ctx, &c.opts.BlockConfig, newMeta, r, w, tempo_io.NewBufferedWriter)
 			currentBlock.meta.CompactionLevel = nextCompactionLevel
 			newCompactedBlocks = append(newCompactedBlocks, currentBlock.meta)
+			currentBlocks[partition] = currentBlock
 		}
 
 		// Flush existing block data if the next trace can't fit
@@ -190,7 +201,7 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 		}
 
 		// Flush again if block is already full.
-		if currentBlock.EstimatedBufferedBytes() > c.opts.BlockConfig.RowGroupSizeBytes {
+		if currentBlock.rowGroupFull(&c.opts.BlockConfig) {
 			runtime.GC()
 			err = c.appendBlock(ctx, currentBlock, l)
 			if err != nil {
@@ -209,12 +220,19 @@ func (c *Compactor) Compact(ctx context.Context, l log.Logger, r backend.Reader,
 			if err != nil {
 				return nil, fmt.Errorf("error shipping block to backend, blockID %s: %w", currentBlockPtrCopy.meta.BlockID.String(), err)
 			}
-			currentBlock = nil
+			delete(currentBlocks, partition)
 		}
 	}
 
-	// ship final block to backend
-	if currentBlock != nil {
+	// ship final blocks to backend
+	partitions := make([]string, 0, len(currentBlocks))
+	for partition := range currentBlocks {
+		partitions = append(partitions, partition)
+	}
+	sort.Strings(partitions)
+
+	for _, partition := range partitions {
+		currentBlock := currentBlocks[partition]
 		currentBlock.meta.StartTime = minBlockStart
 		currentBlock.meta.EndTime = maxBlockEnd
 		err := c.finishBlock(ctx, currentBlock, l)
@@ -366,6 +384,16 @@ func estimateMarshalledSizeFromParquetRow(row parquet.Row) (size int) {
 	return
 }
 
+// countSpanIDs counts the values of the span ID column in the deconstructed row, which is the number of spans.
+func countSpanIDs(row parquet.Row, spanIDColumn int) (spans int) {
+	for _, v := range row {
+		if v.Column() == spanIDColumn {
+			spans++
+		}
+	}
+	return
+}
+
 // countSpans counts the number of spans in the given trace in deconstructed
 // parquet row format and returns traceId.
 // It simply counts the number of values for span ID, which is always present.
@@ -4,6 +4,7 @@ import (
 	"context"
 	crand "crypto/rand"
 	"flag"
+	"fmt"
 	"math/rand"
 	"testing"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	tempo_io "github.com/grafana/tempo/pkg/io"
+	v1_common "github.com/grafana/tempo/pkg/tempopb/common/v1"
 	tempoUtil "github.com/grafana/tempo/pkg/util"
 	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
@@ -213,3 +215,96 @@ func TestCompact(t *testing.T) {
 	require.Equal(t, uint32(1), newMeta[0].ReplicationFactor)
 	require.Equal(t, dedicatedColumns, newMeta[0].DedicatedColumns)
 }
+
+func TestCompactPartitioned(t *testing.T) {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+	ctx := context.Background()
+
+	blockConfig := common.BlockConfig{Version: VersionString}
+	blockConfig.RegisterFlagsAndApplyDefaults("", &flag.FlagSet{})
+
+	// traces with increasing ids and the services svc-0, svc-1, svc-2, svc-0, ...
+	inMeta := &backend.BlockMeta{
+		TenantID:     tenantID,
+		BlockID:      backend.NewUUID(),
+		TotalObjects: 10,
+	}
+	sb := newStreamingBlock(ctx, &blockConfig, inMeta, r, w, tempo_io.NewBufferedWriter)
+	for i := 0; i < 10; i++ {
+		id := make([]byte, 16)
+		id[15] = byte(i)
+
+		tr := test.MakeTrace(2, id)
+		for _, rs := range tr.ResourceSpans {
+			rs.Resource.Attributes[0].Value.Value = &v1_common.AnyValue_StringValue{StringValue: fmt.Sprintf("svc-%d", i%3)}
+		}
+		trp, _ := traceToParquet(inMeta, id, tr, nil)
+		require.NoError(t, sb.Add(trp, 0, 0))
+	}
+	_, err = sb.Complete()
+	require.NoError(t, err)
+
+	c := NewCompactor(common.CompactionOptions{
+		BlockConfig:        blockConfig,
+		OutputBlocks:       1,
+		FlushSizeBytes:     30_000_000,
+		ObjectsCombined:    func(compactionLevel, objects int) {},
+		PartitionAttribute: "service.name",
+		MaxPartitions:      2,
+	})
+
+	newMetas, err := c.Compact(ctx, log.NewNopLogger(), r, w, []*backend.BlockMeta{sb.meta})
+	require.NoError(t, err)
+	require.Len(t, newMetas, 3)
+
+	// svc-0 and svc-1 have their own blocks, svc-2 is written to the shared block
+	expected := []struct {
+		service string
+		traces  int64
+	}{
+		{"svc-0", 4},
+		{"svc-1", 3},
+		{"svc-2", 3},
+	}
+	for i, meta := range newMetas {
+		require.Equal(t, expected[i].traces, meta.TotalObjects)
+
+		iter, err := newBackendBlock(meta, r).TraceIterator(ctx)
+		require.NoError(t, err)
+		for {
+			_, tr, err := iter.Next(ctx)
+			require.NoError(t, err)
+			if tr == nil {
+				break
+			}
+			for _, rs := range tr.ResourceSpans {
+				require.Equal(t, expected[i].service, rs.Resource.Attributes[0].Value.GetStringValue())
+			}
+		}
+		iter.Close()
+	}
+}
+
+func TestPartitionerUnsupportedAttribute(t *testing.T) {
+	sch := parquet.SchemaOf(new(Trace))
+
+	_, ok := newPartitioner(sch, "service.name", nil, 1)
+	require.True(t, ok)
+
+	dedicatedColumns := backend.DedicatedColumns{
+		{Scope: "resource", Name: "dedicated.resource.1", Type: "string"},
+	}
+	_, ok = newPartitioner(sch, "dedicated.resource.1", dedicatedColumns, 1)
+	require.True(t, ok)
+
+	// attributes in the generic attribute columns can't be read from a row
+	p, ok := newPartitioner(sch, "foo", dedicatedColumns, 1)
+	require.False(t, ok)
+	require.Equal(t, "", p.partition(nil))
+}
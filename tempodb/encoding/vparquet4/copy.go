@@ -56,6 +56,12 @@ func CopyBlock(ctx context.Context, fromMeta, toMeta *backend.BlockMeta, from ba
 		return err
 	}
 
+	// Tag values index (may not exist)
+	err = cpy(TagValuesIndexFileName, &backend.CacheInfo{Role: cache.RoleTagValuesIndex})
+	if err != nil && !errors.Is(err, backend.ErrDoesNotExist) {
+		return err
+	}
+
 	// Meta
 	err = to.WriteBlockMeta(ctx, toMeta)
 	return err
@@ -6,9 +6,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/google/uuid"
 	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/pkg/traceql"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding/common"
 	"github.com/parquet-go/parquet-go"
@@ -75,7 +77,7 @@ func CreateBlock(ctx context.Context, cfg *common.BlockConfig, meta *backend.Blo
 		}
 		completeBlockRowPool.Put(row)
 
-		if s.EstimatedBufferedBytes() > cfg.RowGroupSizeBytes {
+		if s.rowGroupFull(cfg) {
 			_, err = s.Flush()
 			if err != nil {
 				return nil, err
@@ -102,8 +104,12 @@ type streamingBlock struct {
 	to    backend.Writer
 	index *index
 
+	tagValuesIndex []traceql.Attribute
+
 	currentBufferedTraces int
 	currentBufferedBytes  int
+	currentBufferedSpans  int
+	spanIDColumn          int
 }
 
 func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backend.BlockMeta, r backend.Reader, to backend.Writer, createBufferedWriter func(w io.Writer) tempo_io.BufferedWriteFlusher) *streamingBlock {
@@ -118,18 +124,30 @@ func newStreamingBlock(ctx context.Context, cfg *common.BlockConfig, meta *backe
 
 	w := &backendWriter{ctx, to, DataFileName, (uuid.UUID)(meta.BlockID), meta.TenantID, nil}
 	bw := createBufferedWriter(w)
-	pw := parquet.NewGenericWriter[*Trace](bw)
+	var writerOptions []parquet.WriterOption
+	tagValuesIndex := tagValuesIndexAttributes(cfg.TagValuesIndex)
+	if len(tagValuesIndex) > 0 {
+		writerOptions = append(writerOptions, parquet.KeyValueMetadata(tagValuesIndexKey, tagValuesIndexMetadata(tagValuesIndex)))
+	}
+	pw := parquet.NewGenericWriter[*Trace](bw, writerOptions...)
+
+	spanIDColumn := -1
+	if leaf, ok := pw.Schema().Lookup(strings.Split(columnPathSpanID, ".")...); ok {
+		spanIDColumn = leaf.ColumnIndex
+	}
 
 	return &streamingBlock{
-		ctx:   ctx,
-		meta:  newMeta,
-		bloom: bloom,
-		bw:    bw,
-		pw:    pw,
-		w:     w,
-		r:     r,
-		to:    to,
-		index: &index{},
+		ctx:            ctx,
+		meta:           newMeta,
+		bloom:          bloom,
+		bw:             bw,
+		pw:             pw,
+		w:              w,
+		r:              r,
+		to:             to,
+		index:          &index{},
+		tagValuesIndex: tagValuesIndex,
+		spanIDColumn:   spanIDColumn,
 	}
 }
 
@@ -145,6 +163,11 @@ func (b *streamingBlock) Add(tr *Trace, start, end uint32) error {
 	b.meta.ObjectAdded(start, end)
 	b.currentBufferedTraces++
 	b.currentBufferedBytes += estimateMarshalledSizeFromTrace(tr)
+	for _, rs := range tr.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			b.currentBufferedSpans += len(ss.Spans)
+		}
+	}
 
 	return nil
 }
@@ -160,6 +183,7 @@ func (b *streamingBlock) AddRaw(id []byte, row parquet.Row, start, end uint32) e
 	b.meta.ObjectAdded(start, end)
 	b.currentBufferedTraces++
 	b.currentBufferedBytes += estimateMarshalledSizeFromParquetRow(row)
+	b.currentBufferedSpans += countSpanIDs(row, b.spanIDColumn)
 
 	return nil
 }
@@ -172,6 +196,16 @@ func (b *streamingBlock) CurrentBufferedObjects() int {
 	return b.currentBufferedTraces
 }
 
+func (b *streamingBlock) CurrentBufferedSpans() int {
+	return b.currentBufferedSpans
+}
+
+// rowGroupFull returns true if the buffered traces exceed the row group size in bytes or spans.
+func (b *streamingBlock) rowGroupFull(cfg *common.BlockConfig) bool {
+	return b.currentBufferedBytes > cfg.RowGroupSizeBytes ||
+		(cfg.RowGroupSizeSpans > 0 && b.currentBufferedSpans >= cfg.RowGroupSizeSpans)
+}
+
 func (b *streamingBlock) Flush() (int, error) {
 	// Flush row group
 	b.index.Flush()
@@ -185,6 +219,7 @@ func (b *streamingBlock) Flush() (int, error) {
 	b.meta.TotalRecords++
 	b.currentBufferedTraces = 0
 	b.currentBufferedBytes = 0
+	b.currentBufferedSpans = 0
 
 	// Flush to underlying writer
 	return n, b.bw.Flush()
@@ -236,6 +271,13 @@ func (b *streamingBlock) Complete() (int, error) {
 
 	b.meta.BloomShardCount = uint32(b.bloom.GetShardCount())
 
+	if len(b.tagValuesIndex) > 0 {
+		err = writeTagValuesIndex(b.ctx, newBackendBlock(b.meta, b.r), b.to, b.tagValuesIndex)
+		if err != nil {
+			return 0, fmt.Errorf("error writing tag values index: %w", err)
+		}
+	}
+
 	return n, writeBlockMeta(b.ctx, b.to, b.meta, b.bloom, b.index)
 }
 
@@ -48,6 +48,44 @@ func TestCreateBlockHonorsTraceStartEndTimesFromWalMeta(t *testing.T) {
 	require.Equal(t, 305, int(outMeta.EndTime.Unix()))
 }
 
+func TestCreateBlockRowGroupSizeSpans(t *testing.T) {
+	ctx := context.Background()
+
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	iter := newTestIterator()
+	for i := 0; i < 4; i++ {
+		iter.Add(test.MakeTrace(2, nil), 0, 0)
+	}
+
+	cfg := &common.BlockConfig{
+		BloomFP:             0.01,
+		BloomShardSizeBytes: 100 * 1024,
+		RowGroupSizeBytes:   100_000_000,
+		// every trace has more than one span, so each is written to its own row group
+		RowGroupSizeSpans: 1,
+	}
+
+	meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = 4
+
+	outMeta, err := CreateBlock(ctx, cfg, meta, iter, r, w)
+	require.NoError(t, err)
+
+	pf, _, err := newBackendBlock(outMeta, r).openForSearch(ctx, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.Len(t, pf.RowGroups(), 4)
+	for _, rg := range pf.RowGroups() {
+		require.Equal(t, int64(1), rg.NumRows())
+	}
+}
+
 // func TestEstimateTraceSize(t *testing.T) {
 // 	f := "<put data.parquet file here>"
 // 	file, err := os.OpenFile(f, os.O_RDONLY, 0644)
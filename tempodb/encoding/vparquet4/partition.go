@@ -0,0 +1,75 @@
+package vparquet4
+
+import (
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/grafana/tempo/tempodb/backend"
+)
+
+// partitioner assigns the traces of a compaction to the output blocks. A trace is partitioned by the value of a
+// resource attribute on the first resource of the trace that has it. Only the first maxPartitions values get their
+// own partition, traces with other values or without the attribute share a partition.
+type partitioner struct {
+	columnIndex   int
+	maxPartitions int
+	partitions    map[string]struct{}
+}
+
+// newPartitioner returns a partitioner for the resource attribute. The attribute must be stored in a well-known or
+// dedicated column of the blocks, otherwise all traces share a partition and ok is false.
+func newPartitioner(sch *parquet.Schema, attr string, dedicatedColumns backend.DedicatedColumns, maxPartitions int) (p *partitioner, ok bool) {
+	p = &partitioner{
+		columnIndex:   -1,
+		maxPartitions: maxPartitions,
+		partitions:    map[string]struct{}{},
+	}
+
+	if attr == "" {
+		return p, true
+	}
+
+	columnPath, found := traceqlResourceLabelMappings[attr]
+	if !found {
+		dedicated := dedicatedColumnsToColumnMapping(dedicatedColumns, backend.DedicatedColumnScopeResource)
+		col, found := dedicated.get(attr)
+		if !found {
+			return p, false
+		}
+		columnPath = col.ColumnPath
+	}
+
+	leaf, found := sch.Lookup(strings.Split(columnPath, ".")...)
+	if !found {
+		return p, false
+	}
+	p.columnIndex = leaf.ColumnIndex
+
+	return p, true
+}
+
+// partition returns the partition of the trace in row.
+func (p *partitioner) partition(row parquet.Row) string {
+	if p.columnIndex < 0 {
+		return ""
+	}
+
+	for _, v := range row {
+		if v.Column() != p.columnIndex || v.IsNull() {
+			continue
+		}
+
+		value := string(v.ByteArray())
+		if _, ok := p.partitions[value]; ok {
+			return value
+		}
+		if len(p.partitions) >= p.maxPartitions {
+			return ""
+		}
+		p.partitions[value] = struct{}{}
+		return value
+	}
+
+	return ""
+}
@@ -0,0 +1,191 @@
+package vparquet4
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/grafana/tempo/pkg/cache"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const (
+	// TagValuesIndexFileName is the object of a block holding the distinct values of the indexed attributes.
+	TagValuesIndexFileName = "tag_values_index.json"
+
+	// tagValuesIndexKey is the key of the parquet key-value metadata that lists the attributes in the tag values
+	// index of the block. Blocks without it don't have a tag values index.
+	tagValuesIndexKey = "tempo.tag_values_index"
+
+	// maxTagValuesIndexValues is the max number of distinct values of an attribute in the tag values index. The values
+	// of attributes with more are searched in the parquet file.
+	maxTagValuesIndexValues = 10_000
+)
+
+// tagValuesIndex holds the distinct values of attributes in a block, keyed by the attribute.
+type tagValuesIndex struct {
+	Attributes map[string]*tagValuesIndexEntry `json:"attributes"`
+}
+
+type tagValuesIndexEntry struct {
+	Strings []string  `json:"strings,omitempty"`
+	Ints    []int64   `json:"ints,omitempty"`
+	Floats  []float64 `json:"floats,omitempty"`
+	Bools   []bool    `json:"bools,omitempty"`
+	// Truncated is set if the attribute has more than maxTagValuesIndexValues values. Its values aren't indexed.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+func (e *tagValuesIndexEntry) add(v traceql.Static) {
+	switch v.Type {
+	case traceql.TypeString:
+		e.Strings = append(e.Strings, v.EncodeToString(false))
+	case traceql.TypeInt:
+		i, _ := v.Int()
+		e.Ints = append(e.Ints, int64(i))
+	case traceql.TypeFloat:
+		e.Floats = append(e.Floats, v.Float())
+	case traceql.TypeBoolean:
+		b, _ := v.Bool()
+		e.Bools = append(e.Bools, b)
+	}
+}
+
+func (e *tagValuesIndexEntry) report(cb common.TagValuesCallbackV2) {
+	for _, s := range e.Strings {
+		if cb(traceql.NewStaticString(s)) {
+			return
+		}
+	}
+	for _, i := range e.Ints {
+		if cb(traceql.NewStaticInt(int(i))) {
+			return
+		}
+	}
+	for _, f := range e.Floats {
+		if cb(traceql.NewStaticFloat(f)) {
+			return
+		}
+	}
+	for _, b := range e.Bools {
+		if cb(traceql.NewStaticBool(b)) {
+			return
+		}
+	}
+}
+
+// tagValuesIndexAttributes parses the attributes to index. Invalid attributes and intrinsics are rejected by the
+// config validation and skipped here.
+func tagValuesIndexAttributes(names []string) []traceql.Attribute {
+	var attrs []traceql.Attribute
+	for _, name := range names {
+		att, err := traceql.ParseIdentifier(name)
+		if err != nil || att.Intrinsic != traceql.IntrinsicNone {
+			continue
+		}
+		attrs = append(attrs, att)
+	}
+	return attrs
+}
+
+// tagValuesIndexMetadata returns the parquet key-value metadata listing the indexed attributes.
+func tagValuesIndexMetadata(attrs []traceql.Attribute) string {
+	names := make([]string, 0, len(attrs))
+	for _, a := range attrs {
+		names = append(names, a.String())
+	}
+	b, _ := json.Marshal(names)
+	return string(b)
+}
+
+// writeTagValuesIndex collects the distinct values of the attributes from the completed block and writes them to its
+// tag values index.
+func writeTagValuesIndex(ctx context.Context, b *backendBlock, w backend.Writer, attrs []traceql.Attribute) error {
+	pf, _, err := b.openForSearch(ctx, common.DefaultSearchOptions())
+	if err != nil {
+		return fmt.Errorf("unexpected error opening parquet file: %w", err)
+	}
+
+	idx := &tagValuesIndex{Attributes: make(map[string]*tagValuesIndexEntry, len(attrs))}
+	for _, a := range attrs {
+		entry := &tagValuesIndexEntry{}
+		seen := map[traceql.StaticMapKey]struct{}{}
+
+		err := searchTagValues(ctx, a, func(v traceql.Static) bool {
+			if entry.Truncated {
+				return true
+			}
+			k := v.MapKey()
+			if _, ok := seen[k]; ok {
+				return false
+			}
+			if len(seen) >= maxTagValuesIndexValues {
+				*entry = tagValuesIndexEntry{Truncated: true}
+				return true
+			}
+			seen[k] = struct{}{}
+			entry.add(v)
+			return false
+		}, pf, b.meta.DedicatedColumns)
+		if err != nil {
+			return fmt.Errorf("error searching values of %s: %w", a, err)
+		}
+
+		idx.Attributes[a.String()] = entry
+	}
+
+	buf, err := json.Marshal(idx)
+	if err != nil {
+		return err
+	}
+
+	return w.Write(ctx, TagValuesIndexFileName, (uuid.UUID)(b.meta.BlockID), b.meta.TenantID, buf, &backend.CacheInfo{
+		Meta: b.meta,
+		Role: cache.RoleTagValuesIndex,
+	})
+}
+
+// searchIndexedTagValues reports the values of the tag from the tag values index of the block. It returns false if
+// the block has no index or the tag isn't indexed, in which case the values have to be searched in the parquet file.
+func (b *backendBlock) searchIndexedTagValues(ctx context.Context, pf *parquet.File, tag traceql.Attribute, cb common.TagValuesCallbackV2) (bool, uint64, error) {
+	v, ok := pf.Lookup(tagValuesIndexKey)
+	if !ok {
+		return false, 0, nil
+	}
+
+	var names []string
+	if err := json.Unmarshal([]byte(v), &names); err != nil || !slices.Contains(names, tag.String()) {
+		return false, 0, nil
+	}
+
+	buf, err := b.r.Read(ctx, TagValuesIndexFileName, (uuid.UUID)(b.meta.BlockID), b.meta.TenantID, &backend.CacheInfo{
+		Meta: b.meta,
+		Role: cache.RoleTagValuesIndex,
+	})
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		return false, 0, nil
+	}
+	if err != nil {
+		return false, 0, fmt.Errorf("error reading tag values index: %w", err)
+	}
+
+	idx := &tagValuesIndex{}
+	if err := json.Unmarshal(buf, idx); err != nil {
+		return false, 0, fmt.Errorf("error unmarshalling tag values index: %w", err)
+	}
+
+	entry, ok := idx.Attributes[tag.String()]
+	if !ok || entry.Truncated {
+		return false, uint64(len(buf)), nil
+	}
+
+	entry.report(cb)
+	return true, uint64(len(buf)), nil
+}
@@ -0,0 +1,153 @@
+package vparquet4
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	tempo_io "github.com/grafana/tempo/pkg/io"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+func TestTagValuesIndex(t *testing.T) {
+	ctx := context.Background()
+	indexed := []string{".http.status_code", "resource.service.name", "span.service.name", ".float", ".bool"}
+	block, w := makeBackendBlockWithTagValuesIndex(t, indexed)
+
+	pf, _, err := block.openForSearch(ctx, common.DefaultSearchOptions())
+	require.NoError(t, err)
+
+	collect := func(tag traceql.Attribute) []traceql.Static {
+		var vals []traceql.Static
+		err := block.SearchTagValuesV2(ctx, tag, func(s traceql.Static) bool {
+			vals = append(vals, s)
+			return false
+		}, func(uint64) {}, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		return vals
+	}
+	search := func(tag traceql.Attribute) []traceql.Static {
+		var vals []traceql.Static
+		err := searchTagValues(ctx, tag, func(s traceql.Static) bool {
+			vals = append(vals, s)
+			return false
+		}, pf, block.meta.DedicatedColumns)
+		require.NoError(t, err)
+		return vals
+	}
+
+	// the index holds the distinct values of the parquet file
+	for _, name := range indexed {
+		tag := traceql.MustParseIdentifier(name)
+		found, _, err := block.searchIndexedTagValues(ctx, pf, tag, func(traceql.Static) bool { return false })
+		require.NoError(t, err)
+		require.True(t, found, name)
+
+		want := map[traceql.StaticMapKey]traceql.Static{}
+		for _, v := range search(tag) {
+			want[v.MapKey()] = v
+		}
+		wantVals := make([]traceql.Static, 0, len(want))
+		for _, v := range want {
+			wantVals = append(wantVals, v)
+		}
+		require.ElementsMatch(t, wantVals, collect(tag), name)
+	}
+
+	// the values of the indexed attributes are read from the index
+	writeIndex := func(idx *tagValuesIndex) {
+		buf, err := json.Marshal(idx)
+		require.NoError(t, err)
+		require.NoError(t, w.Write(ctx, TagValuesIndexFileName, (uuid.UUID)(block.meta.BlockID), block.meta.TenantID, buf, nil))
+	}
+	writeIndex(&tagValuesIndex{Attributes: map[string]*tagValuesIndexEntry{
+		".http.status_code":     {Ints: []int64{200}},
+		"resource.service.name": {Truncated: true},
+	}})
+	require.Equal(t, []traceql.Static{traceql.NewStaticInt(200)}, collect(traceql.MustParseIdentifier(".http.status_code")))
+
+	// attributes with too many values, missing from the index or not indexed are searched in the parquet file
+	for _, name := range []string{"resource.service.name", "span.service.name", ".foo"} {
+		tag := traceql.MustParseIdentifier(name)
+		require.ElementsMatch(t, search(tag), collect(tag), name)
+	}
+}
+
+func TestTagValuesIndexTruncated(t *testing.T) {
+	ctx := context.Background()
+
+	traces := make([]*Trace, 0, maxTagValuesIndexValues+1)
+	for i := 0; i <= maxTagValuesIndexValues; i++ {
+		tr := fullyPopulatedTestTrace(test.ValidTraceID(nil))
+		tr.ResourceSpans[0].ScopeSpans[0].Spans[0].Attrs = append(tr.ResourceSpans[0].ScopeSpans[0].Spans[0].Attrs, attr("id", i))
+		traces = append(traces, tr)
+	}
+	block, _ := makeBackendBlockWithTagValuesIndex(t, []string{"span.id"}, traces...)
+
+	pf, _, err := block.openForSearch(ctx, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	found, _, err := block.searchIndexedTagValues(ctx, pf, traceql.MustParseIdentifier("span.id"), func(traceql.Static) bool { return false })
+	require.NoError(t, err)
+	require.False(t, found)
+
+	count := 0
+	err = block.SearchTagValuesV2(ctx, traceql.MustParseIdentifier("span.id"), func(traceql.Static) bool {
+		count++
+		return false
+	}, func(uint64) {}, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, count, maxTagValuesIndexValues+1)
+}
+
+func TestTagValuesIndexNotConfigured(t *testing.T) {
+	ctx := context.Background()
+	block, _ := makeBackendBlockWithTagValuesIndex(t, nil)
+
+	pf, _, err := block.openForSearch(ctx, common.DefaultSearchOptions())
+	require.NoError(t, err)
+	_, ok := pf.Lookup(tagValuesIndexKey)
+	require.False(t, ok)
+
+	_, err = block.r.Read(ctx, TagValuesIndexFileName, (uuid.UUID)(block.meta.BlockID), block.meta.TenantID, nil)
+	require.ErrorIs(t, err, backend.ErrDoesNotExist)
+}
+
+func makeBackendBlockWithTagValuesIndex(t *testing.T, attrs []string, trs ...*Trace) (*backendBlock, backend.Writer) {
+	rawR, rawW, _, err := local.New(&local.Config{
+		Path: t.TempDir(),
+	})
+	require.NoError(t, err)
+
+	r := backend.NewReader(rawR)
+	w := backend.NewWriter(rawW)
+
+	cfg := &common.BlockConfig{
+		BloomFP:             0.01,
+		BloomShardSizeBytes: 100 * 1024,
+		TagValuesIndex:      attrs,
+	}
+
+	if len(trs) == 0 {
+		trs = []*Trace{fullyPopulatedTestTrace(test.ValidTraceID(nil))}
+	}
+
+	meta := backend.NewBlockMeta("fake", uuid.New(), VersionString, backend.EncNone, "")
+	meta.TotalObjects = int64(len(trs))
+	meta.DedicatedColumns = test.MakeDedicatedColumns()
+
+	s := newStreamingBlock(context.Background(), cfg, meta, r, w, tempo_io.NewBufferedWriter)
+	for _, tr := range trs {
+		require.NoError(t, s.Add(tr, 0, 0))
+	}
+	_, err = s.Complete()
+	require.NoError(t, err)
+
+	return newBackendBlock(s.meta, r), w
+}
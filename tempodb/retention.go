@@ -2,6 +2,7 @@ package tempodb
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/go-kit/log/level"
@@ -48,15 +49,40 @@ func (rw *readerWriter) doRetention(ctx context.Context) {
 	bg.Wait()
 }
 
+// blockRetentionForTenant returns the block retention of the tenant, taking the overrides into account.
+func (rw *readerWriter) blockRetentionForTenant(tenantID string) (time.Duration, error) {
+	retention := rw.compactorCfg.BlockRetention // Default
+	if r := rw.compactorOverrides.BlockRetentionForTenant(tenantID); r != 0 {
+		retention = r
+
+		// overrides can't go below the retention floor
+		if floor := rw.compactorCfg.RetentionFloor; retention < floor {
+			level.Warn(rw.logger).Log("msg", "block retention override is below the retention floor, using the floor", "tenantID", tenantID, "retention", retention, "floor", floor)
+			retention = floor
+		}
+
+		// a very short retention deletes almost all data of the tenant. it's most likely a typo, so it must be confirmed
+		if retention < MinUnconfirmedBlockRetention && !rw.compactorOverrides.ConfirmDeleteAllForTenant(tenantID) {
+			return 0, fmt.Errorf("block retention override %s is under an hour and requires confirm_delete_all", retention)
+		}
+	}
+	return retention, nil
+}
+
 func (rw *readerWriter) retainTenant(ctx context.Context, tenantID string) {
 	start := time.Now()
 	defer func() { metricRetentionDuration.Observe(time.Since(start).Seconds()) }()
 
-	// Check for overrides
-	retention := rw.compactorCfg.BlockRetention // Default
-	if r := rw.compactorOverrides.BlockRetentionForTenant(tenantID); r != 0 {
-		retention = r
+	retention, err := rw.blockRetentionForTenant(tenantID)
+	if err != nil {
+		level.Error(rw.logger).Log("msg", "skipping retention for tenant", "tenantID", tenantID, "err", err)
+		metricRetentionErrors.Inc()
+		return
 	}
+
+	// blocks are kept until the longest retention rule has passed. the traces that don't match any rule are
+	// removed from them by applyRetentionRules
+	retention = maxRetention(retention, rw.compactorOverrides.RetentionRulesForTenant(tenantID))
 	level.Debug(rw.logger).Log("msg", "Performing block retention", "tenantID", tenantID, "retention", retention)
 
 	// iterate through block list.  make compacted anything that is past retention.
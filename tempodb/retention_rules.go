@@ -0,0 +1,218 @@
+package tempodb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+var (
+	metricRetentionRuleBlocksRewritten = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "retention_rule_blocks_rewritten_total",
+		Help:      "Total number of blocks rewritten to delete traces that don't match a retention rule.",
+	})
+	metricRetentionRuleTracesDeleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "retention_rule_traces_deleted_total",
+		Help:      "Total number of traces deleted from blocks because they don't match a retention rule.",
+	})
+)
+
+// RetentionRule keeps the traces matching the TraceQL query for the retention, even if it's longer than the
+// block retention of the tenant.
+type RetentionRule struct {
+	Query     string
+	Retention time.Duration
+}
+
+// maxRetention returns the longest of the block retention and the retention of the rules.
+func maxRetention(retention time.Duration, rules []RetentionRule) time.Duration {
+	for _, r := range rules {
+		retention = max(retention, r.Retention)
+	}
+	return retention
+}
+
+// activeRetentionRules returns the rules whose retention hasn't passed for a block of the given age.
+func activeRetentionRules(rules []RetentionRule, age time.Duration) []RetentionRule {
+	var active []RetentionRule
+	for _, r := range rules {
+		if r.Retention > age {
+			active = append(active, r)
+		}
+	}
+	return active
+}
+
+// retentionTierKey identifies the traces kept in a block by a set of active rules.
+func retentionTierKey(rules []RetentionRule) string {
+	keys := make([]string, 0, len(rules))
+	for _, r := range rules {
+		keys = append(keys, r.Query)
+	}
+	slices.Sort(keys)
+	return strings.Join(slices.Compact(keys), "\n")
+}
+
+// retentionTiers remembers by tenant and block which rules have been applied to a block, so every block is only
+// searched again once one of its rules has expired. It's kept in memory, after a restart every block is checked
+// once more.
+type retentionTiers struct {
+	mtx     sync.Mutex
+	tenants map[string]map[backend.UUID]string
+}
+
+func (t *retentionTiers) applied(tenantID string, blockID backend.UUID, key string) bool {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	applied, ok := t.tenants[tenantID][blockID]
+	return ok && applied == key
+}
+
+func (t *retentionTiers) set(tenantID string, blockID backend.UUID, key string) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.tenants == nil {
+		t.tenants = map[string]map[backend.UUID]string{}
+	}
+	if t.tenants[tenantID] == nil {
+		t.tenants[tenantID] = map[backend.UUID]string{}
+	}
+	t.tenants[tenantID][blockID] = key
+}
+
+// prune forgets the blocks of the tenant that are not in metas anymore.
+func (t *retentionTiers) prune(tenantID string, metas []*backend.BlockMeta) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	blocks := t.tenants[tenantID]
+	if len(metas) == 0 {
+		delete(t.tenants, tenantID)
+		return
+	}
+
+	live := make(map[backend.UUID]struct{}, len(metas))
+	for _, m := range metas {
+		live[m.BlockID] = struct{}{}
+	}
+	for id := range blocks {
+		if _, ok := live[id]; !ok {
+			delete(blocks, id)
+		}
+	}
+}
+
+// applyRetentionRules deletes the traces that are past the block retention of the tenant from the blocks it
+// owns, unless they match a retention rule that hasn't passed yet. Blocks are rewritten with only the matching
+// traces. The blocks themselves are deleted by retention once the longest rule has passed.
+func (rw *readerWriter) applyRetentionRules(ctx context.Context, tenantID string) {
+	rules := rw.compactorOverrides.RetentionRulesForTenant(tenantID)
+	if len(rules) == 0 {
+		rw.retentionTiers.prune(tenantID, nil)
+		return
+	}
+
+	// an invalid retention is reported by retention
+	retention, err := rw.blockRetentionForTenant(tenantID)
+	if err != nil {
+		return
+	}
+
+	metas := rw.blocklist.Metas(tenantID)
+	rw.retentionTiers.prune(tenantID, metas)
+
+	now := time.Now()
+	for _, meta := range metas {
+		if ctx.Err() != nil {
+			return
+		}
+
+		age := now.Sub(meta.EndTime)
+		if age <= retention || !rw.compactorSharder.Owns(meta.BlockID.String()) {
+			continue
+		}
+
+		// blocks past every rule are deleted by retention
+		active := activeRetentionRules(rules, age)
+		if len(active) == 0 {
+			continue
+		}
+
+		key := retentionTierKey(active)
+		if rw.retentionTiers.applied(tenantID, meta.BlockID, key) {
+			continue
+		}
+
+		if err := rw.applyRetentionTier(ctx, tenantID, meta, active); err != nil {
+			level.Error(rw.logger).Log("msg", "failed to apply retention rules", "tenantID", tenantID, "blockID", meta.BlockID, "err", err)
+			metricRetentionErrors.Inc()
+			continue
+		}
+		rw.retentionTiers.set(tenantID, meta.BlockID, key)
+	}
+}
+
+// applyRetentionTier rewrites the block with only the traces matching one of the rules.
+func (rw *readerWriter) applyRetentionTier(ctx context.Context, tenantID string, meta *backend.BlockMeta, rules []RetentionRule) error {
+	block, err := encoding.OpenBlock(meta, rw.r)
+	if err != nil {
+		return err
+	}
+
+	opts := common.DefaultSearchOptions()
+	if rw.cfg.Search != nil {
+		rw.cfg.Search.ApplyToOptions(&opts)
+	}
+
+	// the end is rounded up to include spans ending in the last second of the block
+	keep := map[string]struct{}{}
+	for _, r := range rules {
+		if err := searchTraceIDs(ctx, block, r.Query, meta.StartTime, meta.EndTime.Add(time.Second), opts, keep); err != nil {
+			return fmt.Errorf("failed to search for traces matching %q: %w", r.Query, err)
+		}
+	}
+
+	if len(keep) >= int(meta.TotalObjects) {
+		return nil
+	}
+
+	deleted := int(meta.TotalObjects) - len(keep)
+	if len(keep) == 0 {
+		level.Info(rw.logger).Log("msg", "marking block for deletion, no traces match a retention rule", "tenantID", tenantID, "blockID", meta.BlockID)
+		if err := markCompacted(rw, tenantID, []*backend.BlockMeta{meta}, nil); err != nil {
+			return err
+		}
+		metricMarkedForDeletion.Inc()
+	} else {
+		level.Info(rw.logger).Log("msg", "rewriting block to delete traces past retention", "tenantID", tenantID, "blockID", meta.BlockID, "traces", deleted)
+
+		// the output block only contains matching traces. it's searched once more on the next cycle without any
+		// traces to delete
+		isKept := containsTraceID(keep)
+		err = rw.compactOneJobWithDrop(ctx, []*backend.BlockMeta{meta}, tenantID, func(id common.ID) bool {
+			return !isKept(id)
+		})
+		if err != nil {
+			return err
+		}
+		metricRetentionRuleBlocksRewritten.Inc()
+	}
+
+	metricRetentionRuleTracesDeleted.Add(float64(deleted))
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/tempo/pkg/model"
+	"github.com/grafana/tempo/pkg/util/test"
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/encoding"
@@ -81,6 +82,104 @@ func TestRetention(t *testing.T) {
 	checkBlocklists(t, (uuid.UUID)(blockID), 0, 0, rw)
 }
 
+func TestRetentionOverrideProtection(t *testing.T) {
+	tests := []struct {
+		name              string
+		retentionFloor    time.Duration
+		overrides         *mockOverrides
+		expectedCompacted bool
+	}{
+		{
+			name:      "no override",
+			overrides: &mockOverrides{},
+		},
+		{
+			name:              "override",
+			overrides:         &mockOverrides{blockRetention: 2 * time.Hour},
+			expectedCompacted: true,
+		},
+		{
+			name:      "override under an hour without confirmation",
+			overrides: &mockOverrides{blockRetention: time.Minute},
+		},
+		{
+			name:              "override under an hour with confirmation",
+			overrides:         &mockOverrides{blockRetention: time.Minute, confirmDeleteAll: true},
+			expectedCompacted: true,
+		},
+		{
+			name:           "override below the floor",
+			retentionFloor: 4 * time.Hour,
+			overrides:      &mockOverrides{blockRetention: 2 * time.Hour},
+		},
+		{
+			name:              "override under an hour raised to the floor",
+			retentionFloor:    2 * time.Hour,
+			overrides:         &mockOverrides{blockRetention: time.Minute},
+			expectedCompacted: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+
+			r, w, c, err := New(&Config{
+				Backend: backend.Local,
+				Local: &local.Config{
+					Path: path.Join(tempDir, "traces"),
+				},
+				Block: &common.BlockConfig{
+					IndexDownsampleBytes: 17,
+					BloomFP:              0.01,
+					BloomShardSizeBytes:  100_000,
+					Version:              encoding.DefaultEncoding().Version(),
+					Encoding:             backend.EncLZ4_256k,
+					IndexPageSizeBytes:   1000,
+				},
+				WAL: &wal.Config{
+					Filepath: path.Join(tempDir, "wal"),
+				},
+				BlocklistPoll: 0,
+			}, nil, log.NewNopLogger())
+			require.NoError(t, err)
+
+			ctx := context.Background()
+			err = c.EnableCompaction(ctx, &CompactorConfig{
+				ChunkSizeBytes:          10,
+				MaxCompactionRange:      time.Hour,
+				BlockRetention:          24 * time.Hour,
+				RetentionFloor:          tc.retentionFloor,
+				CompactedBlockRetention: time.Hour,
+			}, &mockSharder{}, tc.overrides)
+			require.NoError(t, err)
+
+			r.EnablePolling(ctx, &mockJobSharder{})
+
+			head, err := w.WAL().NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: testTenantID}, model.CurrentEncoding)
+			require.NoError(t, err)
+			_, err = w.CompleteBlock(ctx, head)
+			require.NoError(t, err)
+
+			rw := r.(*readerWriter)
+			rw.pollBlocklist()
+			metas := rw.blocklist.Metas(testTenantID)
+			require.Len(t, metas, 1)
+			metas[0].EndTime = time.Now().Add(-3 * time.Hour)
+
+			rw.doRetention(ctx)
+
+			if tc.expectedCompacted {
+				require.Len(t, rw.blocklist.Metas(testTenantID), 0)
+				require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 1)
+			} else {
+				require.Len(t, rw.blocklist.Metas(testTenantID), 1)
+				require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 0)
+			}
+		})
+	}
+}
+
 func TestRetentionUpdatesBlocklistImmediately(t *testing.T) {
 	// Test that retention updates the in-memory blocklist
 	// immediately to reflect affected blocks and doesn't
@@ -213,9 +312,107 @@ func TestBlockRetentionOverride(t *testing.T) {
 	rw.pollBlocklist()
 	require.Equal(t, 10, len(rw.blocklist.Metas(testTenantID)))
 
-	// Retention = 1ns, deletes everything
+	// Retention = 1ns, deletes everything once confirmed
 	overrides.blockRetention = time.Nanosecond
+	overrides.confirmDeleteAll = true
 	r.(*readerWriter).doRetention(ctx)
 	rw.pollBlocklist()
 	require.Equal(t, 0, len(rw.blocklist.Metas(testTenantID)))
 }
+
+func TestApplyRetentionRules(t *testing.T) {
+	r, w, _ := testTombstonesStore(t)
+	rw := r.(*readerWriter)
+	ctx := context.Background()
+
+	rw.compactorCfg.BlockRetention = time.Minute
+	rw.compactorCfg.CompactedBlockRetention = time.Hour
+	overrides := rw.compactorOverrides.(*mockOverrides)
+	overrides.retentionRules = []RetentionRule{
+		{Query: `{ name = "keep-long" }`, Retention: 2 * time.Hour},
+		{Query: `{ name = "keep-short" }`, Retention: 5 * time.Minute},
+	}
+
+	// the blocks are past the block retention and the short rule, but not past the long rule
+	ts := time.Now().Add(-10 * time.Minute)
+
+	var (
+		kept    [][]byte
+		deleted [][]byte
+	)
+	for i := 0; i < 2; i++ {
+		data := make([]testData, 0, 9)
+		for j := 0; j < 9; j++ {
+			id := makeTraceID(i, j)
+			tr := test.MakeTraceWithTimeRange(1, id, uint64(ts.UnixNano()), uint64(ts.Add(time.Second).UnixNano()))
+
+			name := "other"
+			switch j % 3 {
+			case 0:
+				name = "keep-long"
+				kept = append(kept, id)
+			case 1:
+				name = "keep-short"
+				deleted = append(deleted, id)
+			default:
+				deleted = append(deleted, id)
+			}
+			for _, ss := range tr.ResourceSpans[0].ScopeSpans {
+				for _, s := range ss.Spans {
+					s.Name = name
+				}
+			}
+			data = append(data, testData{id: id, t: tr, start: uint32(ts.Unix()), end: uint32(ts.Unix()) + 1})
+		}
+		cutTestBlockWithTraces(t, w, data)
+	}
+	rw.pollBlocklist()
+
+	rw.applyRetentionRules(ctx, testTenantID)
+	metas := rw.blocklist.Metas(testTenantID)
+	require.Len(t, metas, 2)
+	for _, id := range deleted {
+		require.Zero(t, countTraceInBlocks(t, rw, id))
+	}
+	for _, id := range kept {
+		require.Equal(t, 1, countTraceInBlocks(t, rw, id))
+	}
+
+	// the rewritten blocks only contain matching traces and are only searched once more
+	rw.applyRetentionRules(ctx, testTenantID)
+	require.ElementsMatch(t, metas, rw.blocklist.Metas(testTenantID))
+	for _, m := range metas {
+		require.True(t, rw.retentionTiers.applied(testTenantID, m.BlockID, retentionTierKey(overrides.retentionRules[:1])))
+	}
+
+	// retention keeps the blocks until the longest rule has passed
+	rw.retainTenant(ctx, testTenantID)
+	require.Len(t, rw.blocklist.Metas(testTenantID), 2)
+
+	// blocks without any matching traces are deleted
+	overrides.retentionRules[0].Query = `{ name = "nothing" }`
+	rw.applyRetentionRules(ctx, testTenantID)
+	require.Empty(t, rw.blocklist.Metas(testTenantID))
+	require.Len(t, rw.blocklist.CompactedMetas(testTenantID), 4)
+
+	// deleted blocks are forgotten on the next cycle
+	rw.applyRetentionRules(ctx, testTenantID)
+	require.Empty(t, rw.retentionTiers.tenants)
+}
+
+func TestActiveRetentionRules(t *testing.T) {
+	rules := []RetentionRule{
+		{Query: `{ resource.deployment.environment = "prod" }`, Retention: 30 * 24 * time.Hour},
+		{Query: `{ status = error }`, Retention: 7 * 24 * time.Hour},
+	}
+
+	require.Equal(t, 30*24*time.Hour, maxRetention(72*time.Hour, rules))
+	require.Equal(t, 60*24*time.Hour, maxRetention(60*24*time.Hour, rules))
+
+	require.Equal(t, rules, activeRetentionRules(rules, 96*time.Hour))
+	require.Equal(t, rules[:1], activeRetentionRules(rules, 10*24*time.Hour))
+	require.Empty(t, activeRetentionRules(rules, 30*24*time.Hour))
+
+	require.Equal(t, retentionTierKey(rules), retentionTierKey([]RetentionRule{rules[1], rules[0], rules[1]}))
+	require.NotEqual(t, retentionTierKey(rules), retentionTierKey(rules[:1]))
+}
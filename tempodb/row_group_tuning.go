@@ -0,0 +1,103 @@
+package tempodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const (
+	// rowGroupTargetSelectivity is the fraction of the searched bytes that auto-tuning aims for searches to read.
+	rowGroupTargetSelectivity = 0.25
+	// rowGroupMinScale and rowGroupMaxScale limit how far auto-tuning moves the row group size from the configured one.
+	rowGroupMinScale = 0.25
+	rowGroupMaxScale = 4
+	// rowGroupMinSamples is the number of searches that must be observed before the row group size is tuned.
+	rowGroupMinSamples = 100
+	// rowGroupStatsMaxAge ignores the stats of tenants that haven't been searched for a while.
+	rowGroupStatsMaxAge = 7 * 24 * time.Hour
+)
+
+var metricCompactionRowGroupSizeBytes = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "tempodb",
+	Name:      "compaction_tuned_row_group_size_bytes",
+	Help:      "The row group size in bytes the blocks of tenants with row group auto-tuning are compacted with.",
+}, []string{"tenant"})
+
+// tenantBlockConfig returns the block config with the row group size of the tenant.
+func (rw *readerWriter) tenantBlockConfig(tenantID string) *common.BlockConfig {
+	cfg := *rw.cfg.Block
+
+	if rw.cfg.RowGroupSizeBytesForTenant != nil {
+		if size := rw.cfg.RowGroupSizeBytesForTenant(tenantID); size > 0 {
+			cfg.RowGroupSizeBytes = size
+		}
+	}
+	if rw.cfg.RowGroupSizeSpansForTenant != nil {
+		if size := rw.cfg.RowGroupSizeSpansForTenant(tenantID); size > 0 {
+			cfg.RowGroupSizeSpans = size
+		}
+	}
+
+	return &cfg
+}
+
+// compactionBlockConfig returns the block config blocks of the tenant are compacted with. The row group size of
+// tenants with auto-tuning is scaled by the selectivity of their searches.
+func (rw *readerWriter) compactionBlockConfig(ctx context.Context, tenantID string) *common.BlockConfig {
+	cfg := rw.tenantBlockConfig(tenantID)
+
+	if rw.cfg.RowGroupAutoTuneForTenant == nil || !rw.cfg.RowGroupAutoTuneForTenant(tenantID) {
+		return cfg
+	}
+
+	stats, err := rw.r.RowGroupStats(ctx, tenantID)
+	if err != nil {
+		if !errors.Is(err, backend.ErrDoesNotExist) {
+			level.Warn(rw.logger).Log("msg", "failed to read row group stats, using the configured row group size", "tenantID", tenantID, "err", err)
+		}
+		return cfg
+	}
+
+	scale := rowGroupScale(stats, time.Now())
+	cfg.RowGroupSizeBytes = int(float64(cfg.RowGroupSizeBytes) * scale)
+	if cfg.RowGroupSizeSpans > 0 {
+		cfg.RowGroupSizeSpans = max(int(float64(cfg.RowGroupSizeSpans)*scale), 1)
+	}
+	metricCompactionRowGroupSizeBytes.WithLabelValues(tenantID).Set(float64(cfg.RowGroupSizeBytes))
+
+	return cfg
+}
+
+// rowGroupScale returns the factor the row group size is scaled by. Searches that read most of the bytes of the row
+// groups they search don't benefit from the column statistics, smaller row groups have tighter statistics that let
+// more pages be skipped. Searches that read little already skip most pages, larger row groups reduce the size of the
+// footer and the number of requests.
+func rowGroupScale(stats *backend.RowGroupStats, now time.Time) float64 {
+	if stats.Samples < rowGroupMinSamples || stats.Selectivity <= 0 || now.Sub(stats.UpdatedAt) > rowGroupStatsMaxAge {
+		return 1
+	}
+
+	scale := rowGroupTargetSelectivity / stats.Selectivity
+	return min(max(scale, rowGroupMinScale), rowGroupMaxScale)
+}
+
+// RecordSearchSelectivity adds the average selectivity of a number of searches to the row group stats of the tenant.
+func (rw *readerWriter) RecordSearchSelectivity(ctx context.Context, tenantID string, selectivity float64, searches int64) error {
+	stats, err := rw.r.RowGroupStats(ctx, tenantID)
+	if errors.Is(err, backend.ErrDoesNotExist) {
+		stats = &backend.RowGroupStats{}
+	} else if err != nil {
+		return err
+	}
+
+	stats.Merge(selectivity, searches, time.Now())
+	return rw.w.WriteRowGroupStats(ctx, tenantID, stats)
+}
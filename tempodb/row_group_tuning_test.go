@@ -0,0 +1,134 @@
+package tempodb
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+func TestRowGroupScale(t *testing.T) {
+	now := time.Now()
+
+	tcs := []struct {
+		name     string
+		stats    backend.RowGroupStats
+		expected float64
+	}{
+		{
+			name:     "target selectivity",
+			stats:    backend.RowGroupStats{Selectivity: rowGroupTargetSelectivity, Samples: rowGroupMinSamples, UpdatedAt: now},
+			expected: 1,
+		},
+		{
+			name:     "searches read everything",
+			stats:    backend.RowGroupStats{Selectivity: 1, Samples: rowGroupMinSamples, UpdatedAt: now},
+			expected: 0.25,
+		},
+		{
+			name:     "searches skip most pages",
+			stats:    backend.RowGroupStats{Selectivity: 0.125, Samples: rowGroupMinSamples, UpdatedAt: now},
+			expected: 2,
+		},
+		{
+			name:     "limited to max scale",
+			stats:    backend.RowGroupStats{Selectivity: 0.001, Samples: rowGroupMinSamples, UpdatedAt: now},
+			expected: rowGroupMaxScale,
+		},
+		{
+			name:     "too few samples",
+			stats:    backend.RowGroupStats{Selectivity: 1, Samples: rowGroupMinSamples - 1, UpdatedAt: now},
+			expected: 1,
+		},
+		{
+			name:     "stale",
+			stats:    backend.RowGroupStats{Selectivity: 1, Samples: rowGroupMinSamples, UpdatedAt: now.Add(-rowGroupStatsMaxAge - time.Minute)},
+			expected: 1,
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, rowGroupScale(&tc.stats, now))
+		})
+	}
+}
+
+func TestCompactionBlockConfig(t *testing.T) {
+	tempDir := t.TempDir()
+
+	autoTune := map[string]bool{"tuned": true}
+	r, _, c, err := New(&Config{
+		Backend: backend.Local,
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncNone,
+			IndexPageSizeBytes:   1000,
+			RowGroupSizeBytes:    100_000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+		},
+		RowGroupSizeBytesForTenant: func(tenantID string) int {
+			if tenantID == "tuned" {
+				return 200_000
+			}
+			return 0
+		},
+		RowGroupSizeSpansForTenant: func(string) int { return 1000 },
+		RowGroupAutoTuneForTenant:  func(tenantID string) bool { return autoTune[tenantID] },
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+	rw := r.(*readerWriter)
+	ctx := context.Background()
+
+	// without stats the configured size is used
+	cfg := rw.compactionBlockConfig(ctx, "tuned")
+	require.Equal(t, 200_000, cfg.RowGroupSizeBytes)
+	require.Equal(t, 1000, cfg.RowGroupSizeSpans)
+
+	// searches read every byte, the row groups are shrunk
+	require.NoError(t, c.RecordSearchSelectivity(ctx, "tuned", 1, rowGroupMinSamples))
+	cfg = rw.compactionBlockConfig(ctx, "tuned")
+	require.Equal(t, 50_000, cfg.RowGroupSizeBytes)
+	require.Equal(t, 250, cfg.RowGroupSizeSpans)
+
+	// the block config is left untouched
+	require.Equal(t, 100_000, rw.cfg.Block.RowGroupSizeBytes)
+	require.Equal(t, 0, rw.cfg.Block.RowGroupSizeSpans)
+
+	// tenants without auto-tuning ignore the stats
+	require.NoError(t, c.RecordSearchSelectivity(ctx, "untuned", 1, rowGroupMinSamples))
+	cfg = rw.compactionBlockConfig(ctx, "untuned")
+	require.Equal(t, 100_000, cfg.RowGroupSizeBytes)
+	require.Equal(t, 1000, cfg.RowGroupSizeSpans)
+}
+
+func TestRecordSearchSelectivity(t *testing.T) {
+	_, _, c := testTombstonesStore(t)
+	rw := c.(*readerWriter)
+	ctx := context.Background()
+
+	require.NoError(t, c.RecordSearchSelectivity(ctx, testTenantID, 0.5, 10))
+	require.NoError(t, c.RecordSearchSelectivity(ctx, testTenantID, 0.2, 40))
+
+	stats, err := rw.r.RowGroupStats(ctx, testTenantID)
+	require.NoError(t, err)
+	require.InDelta(t, 0.26, stats.Selectivity, 0.0001)
+	require.Equal(t, int64(50), stats.Samples)
+}
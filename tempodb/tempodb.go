@@ -30,6 +30,8 @@ import (
 	"github.com/grafana/tempo/tempodb/backend/gcs"
 	"github.com/grafana/tempo/tempodb/backend/local"
 	"github.com/grafana/tempo/tempodb/backend/s3"
+	"github.com/grafana/tempo/tempodb/backend/scheduler"
+	"github.com/grafana/tempo/tempodb/backend/swift"
 	"github.com/grafana/tempo/tempodb/blocklist"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
@@ -100,6 +102,14 @@ type Reader interface {
 
 type Compactor interface {
 	EnableCompaction(ctx context.Context, cfg *CompactorConfig, sharder CompactorSharder, overrides CompactorOverrides) error
+
+	// CreateTombstone writes a request to delete traces. It is applied by the compactors.
+	CreateTombstone(ctx context.Context, tombstone *backend.Tombstone) error
+	// RecordSearchSelectivity adds the average selectivity of a number of searches to the row group stats of the
+	// tenant. The compactors tune the row group size of tenants with auto-tuning enabled to it.
+	RecordSearchSelectivity(ctx context.Context, tenantID string, selectivity float64, searches int64) error
+	// Tombstones returns the deletion requests of the tenant and their progress.
+	Tombstones(ctx context.Context, tenantID string) ([]*backend.Tombstone, error)
 }
 
 type CompactorSharder interface {
@@ -111,8 +121,12 @@ type CompactorSharder interface {
 type CompactorOverrides interface {
 	BlockRetentionForTenant(tenantID string) time.Duration
 	CompactionDisabledForTenant(tenantID string) bool
+	ConfirmDeleteAllForTenant(tenantID string) bool
 	MaxBytesPerTraceForTenant(tenantID string) int
 	MaxCompactionRangeForTenant(tenantID string) time.Duration
+	MaxConcurrentCompactionsForTenant(tenantID string) int
+	SpanCombineStrategyForTenant(tenantID string) common.SpanCombineStrategy
+	RetentionRulesForTenant(tenantID string) []RetentionRule
 }
 
 type WriteableBlock interface {
@@ -136,10 +150,13 @@ type readerWriter struct {
 	blocklistPoller *blocklist.Poller
 	blocklist       *blocklist.List
 
-	compactorCfg          *CompactorConfig
-	compactorSharder      CompactorSharder
-	compactorOverrides    CompactorOverrides
-	compactorTenantOffset uint
+	compactorCfg        *CompactorConfig
+	compactorSharder    CompactorSharder
+	compactorOverrides  CompactorOverrides
+	compactionScheduler *compactionScheduler
+
+	tombstones     tombstonedTraces
+	retentionTiers retentionTiers
 }
 
 // New creates a new tempodb
@@ -162,6 +179,8 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		rawR, rawW, c, err = s3.New(cfg.S3)
 	case backend.Azure:
 		rawR, rawW, c, err = azure.New(cfg.Azure)
+	case backend.Swift:
+		rawR, rawW, c, err = swift.New(cfg.Swift)
 	default:
 		err = fmt.Errorf("unknown backend %s", cfg.Backend)
 	}
@@ -170,6 +189,12 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		return nil, nil, nil, err
 	}
 
+	// schedule requests to the backend by priority. this sits below the cache so that
+	// cache hits are never throttled
+	if cfg.IOScheduler.Enabled {
+		rawR = scheduler.New(&cfg.IOScheduler, rawR)
+	}
+
 	// build a caching layer if we have a provider
 	if cacheProvider != nil {
 		legacyCache, roles, err := createLegacyCache(cfg, logger)
@@ -203,6 +228,10 @@ func New(cfg *Config, cacheProvider cache.Provider, logger gkLog.Logger) (Reader
 		blocklist: blocklist.New(),
 	}
 
+	if rw.cfg.WAL.VersionForTenant == nil {
+		rw.cfg.WAL.VersionForTenant = rw.cfg.BlockVersionForTenant
+	}
+
 	rw.wal, err = wal.New(rw.cfg.WAL)
 	if err != nil {
 		return nil, nil, nil, err
@@ -224,7 +253,7 @@ func (rw *readerWriter) CompleteBlock(ctx context.Context, block common.WALBlock
 // new block will have the same ID as the input block.
 func (rw *readerWriter) CompleteBlockWithBackend(ctx context.Context, block common.WALBlock, r backend.Reader, w backend.Writer) (common.BackendBlock, error) {
 	// The destination block format:
-	vers, err := encoding.FromVersion(rw.cfg.Block.Version)
+	vers, err := encoding.FromVersion(rw.blockVersion(block.BlockMeta().TenantID))
 	if err != nil {
 		return nil, err
 	}
@@ -257,7 +286,7 @@ func (rw *readerWriter) CompleteBlockWithBackend(ctx context.Context, block comm
 		Encoding: rw.cfg.Block.Encoding,
 	}
 
-	newMeta, err := vers.CreateBlock(ctx, rw.cfg.Block, inMeta, iter, r, w)
+	newMeta, err := vers.CreateBlock(ctx, rw.tenantBlockConfig(walMeta.TenantID), inMeta, iter, r, w)
 	if err != nil {
 		return nil, fmt.Errorf("error creating block: %w", err)
 	}
@@ -521,8 +550,15 @@ func (rw *readerWriter) EnableCompaction(ctx context.Context, cfg *CompactorConf
 	if cfg.RetentionConcurrency == 0 {
 		cfg.RetentionConcurrency = DefaultRetentionConcurrency
 	}
+	if cfg.TombstoneGracePeriod == 0 {
+		cfg.TombstoneGracePeriod = DefaultTombstoneGracePeriod
+	}
+	if cfg.CompactionConcurrency == 0 {
+		cfg.CompactionConcurrency = DefaultCompactionConcurrency
+	}
 
 	rw.compactorCfg = cfg
+	rw.compactionScheduler = newCompactionScheduler()
 	rw.compactorSharder = c
 	rw.compactorOverrides = overrides
 
@@ -531,6 +567,12 @@ func (rw *readerWriter) EnableCompaction(ctx context.Context, cfg *CompactorConf
 		return nil
 	}
 
+	if cfg != nil && cfg.DryRun {
+		level.Info(rw.logger).Log("msg", "compaction dry run enabled. compaction plans are logged, compaction and retention are disabled.")
+		go rw.compactionLoop(ctx)
+		return nil
+	}
+
 	if cfg != nil {
 		level.Info(rw.logger).Log("msg", "compaction and retention enabled.")
 		go rw.compactionLoop(ctx)
@@ -657,7 +699,11 @@ func createLegacyCache(cfg *Config, logger gkLog.Logger) (cache.Cache, []cache.R
 
 	switch cfg.Cache {
 	case "redis":
-		legacyCache = redis.NewClient(cfg.Redis, cfg.BackgroundCache, "legacy", logger)
+		var err error
+		legacyCache, err = redis.NewClient(cfg.Redis, cfg.BackgroundCache, "legacy", logger)
+		if err != nil {
+			return nil, nil, err
+		}
 	case "memcached":
 		legacyCache = memcached.NewClient(cfg.Memcached, cfg.BackgroundCache, "legacy", logger)
 	}
@@ -140,11 +140,13 @@ func advancedTraceQLRunner(t *testing.T, wantTr *tempopb.Trace, wantMeta *tempop
 
 	// collect some info about wantTr to use below
 	trueConditionsBySpan := [][]string{}
+	calendarConditionsBySpan := []string{}
 	durationBySpan := []uint64{}
 	falseConditions := []string{
 		fmt.Sprintf("name=`%v`", test.RandomString()),
 		fmt.Sprintf("duration>%dh", rand.Intn(10)+1),
 		fmt.Sprintf("rootServiceName=`%v`", test.RandomString()),
+		"year(span:start)=1",
 		// status? can't really construct a status condition that's false for all spans
 	}
 	trueTraceC := []string{
@@ -168,6 +170,10 @@ func advancedTraceQLRunner(t *testing.T, wantTr *tempopb.Trace, wantMeta *tempop
 				trueC = append(trueC, fmt.Sprintf("duration=%dns", s.EndTimeUnixNano-s.StartTimeUnixNano))
 				trueC = append(trueC, fmt.Sprintf("status=%s", status))
 				trueC = append(trueC, fmt.Sprintf("kind=%s", kind))
+				start := time.Unix(0, int64(s.StartTimeUnixNano)).UTC()
+				calendarC := fmt.Sprintf("hour(span:start)=%d && day_of_week(span:start)=%d", start.Hour(), start.Weekday())
+				trueC = append(trueC, calendarC)
+				calendarConditionsBySpan = append(calendarConditionsBySpan, calendarC)
 				trueC = append(trueC, trueResourceC...)
 				trueC = append(trueC, trueTraceC...)
 
@@ -187,6 +193,7 @@ func advancedTraceQLRunner(t *testing.T, wantTr *tempopb.Trace, wantMeta *tempop
 		{Query: fmt.Sprintf("{%s && %s && %s && %s && %s}", rando(trueConditionsBySpan[0]), rando(trueConditionsBySpan[0]), rando(trueConditionsBySpan[0]), rando(trueConditionsBySpan[0]), rando(trueConditionsBySpan[0]))},
 		{Query: fmt.Sprintf("{%s || %s || %s || %s || %s}", rando(falseConditions), rando(falseConditions), rando(falseConditions), rando(trueConditionsBySpan[0]), rando(falseConditions))},
 		{Query: fmt.Sprintf("{(%s && %s) || %s}", rando(falseConditions), rando(falseConditions), rando(trueConditionsBySpan[0]))},
+		{Query: fmt.Sprintf("{%s}", calendarConditionsBySpan[0])},
 		// spansets
 		{Query: fmt.Sprintf("{%s} && {%s}", rando(trueConditionsBySpan[0]), rando(trueConditionsBySpan[1]))},
 		{Query: fmt.Sprintf("{%s} || {%s}", rando(trueConditionsBySpan[0]), rando(falseConditions))},
@@ -509,6 +516,29 @@ func traceQLStructural(t *testing.T, _ *tempopb.Trace, wantMeta *tempopb.TraceSe
 	}
 
 	searchesThatMatch := []*test{
+		{
+			req: &tempopb.SearchRequest{Query: "{ .parent } >>1 { .child }"},
+			expected: []*tempopb.TraceSearchMetadata{
+				{
+					SpanSets: []*tempopb.SpanSet{
+						{
+							Spans: []*tempopb.Span{
+								{
+									SpanID:            "0000000000010203",
+									StartTimeUnixNano: 1000000000000,
+									DurationNanos:     1000000000,
+									Name:              "",
+									Attributes: []*v1_common.KeyValue{
+										{Key: "child", Value: &v1_common.AnyValue{Value: &v1_common.AnyValue_BoolValue{BoolValue: true}}},
+									},
+								},
+							},
+							Matched: 1,
+						},
+					},
+				},
+			},
+		},
 		{
 			req: &tempopb.SearchRequest{Query: "{ .parent } >> { .child }"},
 			expected: []*tempopb.TraceSearchMetadata{
@@ -938,6 +968,7 @@ func traceQLStructural(t *testing.T, _ *tempopb.Trace, wantMeta *tempopb.TraceSe
 
 	searchesThatDontMatch := []*tempopb.SearchRequest{
 		{Query: "{ .child } >> { .parent }"},
+		{Query: "{ .child } >>1 { .parent }"},
 		{Query: "{ .child } > { .parent }"},
 		{Query: "{ .child } ~ { .parent }"},
 		{Query: "{ .child } ~ { .child }"},
@@ -1372,15 +1403,29 @@ func tagValuesRunner(t *testing.T, _ *tempopb.Trace, _ *tempopb.TraceSearchMetad
 			},
 		},
 		{
-			name:  "unscoped not supported", // todo: add support for unscoped. currently it falls back to old logic and returns everything
+			name:  "unscoped",
 			tag:   traceql.NewScopedAttribute(traceql.AttributeScopeResource, false, "service.name"),
 			query: "{ .foo = `Bar` }",
 			expected: []tempopb.TagValue{
+				{Type: "string", Value: "MyService"},
 				{Type: "string", Value: "RootService"},
-				{Type: "string", Value: "Service3"},
-				{Type: "string", Value: "BrokenService"},
+			},
+		},
+		{
+			name:  "or conditions",
+			tag:   traceql.NewScopedAttribute(traceql.AttributeScopeSpan, false, "span-dedicated.01"),
+			query: "{ resource.cluster = `MyCluster` || span.foo = `nope` }",
+			expected: []tempopb.TagValue{
+				{Type: "string", Value: "span-1a"},
+			},
+		},
+		{
+			name:  "multiple spansets",
+			tag:   traceql.NewScopedAttribute(traceql.AttributeScopeResource, false, "service.name"),
+			query: "{ resource.cluster = `MyCluster` } || { resource.service.name = `RootService` }",
+			expected: []tempopb.TagValue{
 				{Type: "string", Value: "MyService"},
-				{Type: "string", Value: "test-service"},
+				{Type: "string", Value: "RootService"},
 			},
 		},
 	}
@@ -1393,11 +1438,21 @@ func tagValuesRunner(t *testing.T, _ *tempopb.Trace, _ *tempopb.TraceSearchMetad
 				return bb.FetchTagValues(ctx, req, cb, mc.Add, common.DefaultSearchOptions())
 			})
 
-			err := e.ExecuteTagValues(ctx, tc.tag, tc.query, traceql.MakeCollectTagValueFunc(valueCollector.Collect), fetcher)
+			var spansResp traceql.FetchSpansResponse
+			spansetFetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+				var err error
+				spansResp, err = bb.Fetch(ctx, req, common.DefaultSearchOptions())
+				return spansResp, err
+			})
+
+			err := e.ExecuteTagValues(ctx, tc.tag, tc.query, traceql.MakeCollectTagValueFunc(valueCollector.Collect), fetcher, spansetFetcher)
 			if errors.Is(err, common.ErrUnsupported) {
 				return
 			}
 			require.NoError(t, err, "autocomplete request: %+v", tc)
+			if spansResp.Bytes != nil {
+				mc.Add(spansResp.Bytes())
+			}
 
 			expected := tc.expected
 			sort.Slice(expected, func(i, j int) bool {
@@ -2399,11 +2454,14 @@ func TestSearchForTagsAndTagValues(t *testing.T) {
 	f := traceql.NewTagValuesFetcherWrapper(func(ctx context.Context, req traceql.FetchTagValuesRequest, cb traceql.FetchTagValuesCallback) error {
 		return r.FetchTagValues(ctx, block.BlockMeta(), req, cb, mc.Add, common.DefaultSearchOptions())
 	})
+	sf := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return r.Fetch(ctx, block.BlockMeta(), req, common.DefaultSearchOptions())
+	})
 
 	tag, err := traceql.ParseIdentifier("span.intTag")
 	require.NoError(t, err)
 
-	err = traceql.NewEngine().ExecuteTagValues(context.Background(), tag, `{resource.service.name="test-service-2"}`, traceql.MakeCollectTagValueFunc(valueCollector.Collect), f)
+	err = traceql.NewEngine().ExecuteTagValues(context.Background(), tag, `{resource.service.name="test-service-2"}`, traceql.MakeCollectTagValueFunc(valueCollector.Collect), f, sf)
 	require.NoError(t, err)
 
 	actual := valueCollector.Values()
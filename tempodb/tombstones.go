@@ -0,0 +1,305 @@
+package tempodb
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/tempo/pkg/tempopb"
+	"github.com/grafana/tempo/pkg/traceql"
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+)
+
+const DefaultTombstoneGracePeriod = time.Hour
+
+var (
+	metricTombstonesCompleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_tombstones_completed_total",
+		Help:      "Total number of tombstones that have been applied to all blocks.",
+	})
+	metricTombstoneTracesDeleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_tombstone_traces_deleted_total",
+		Help:      "Total number of traces deleted from blocks by tombstones.",
+	})
+	metricTombstoneErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "tempodb",
+		Name:      "compaction_tombstone_errors_total",
+		Help:      "Total number of errors occurring while applying tombstones.",
+	})
+)
+
+// CreateTombstone validates and writes a new tombstone. Compactors pick it up on their next cycle of the tenant.
+func (rw *readerWriter) CreateTombstone(ctx context.Context, tombstone *backend.Tombstone) error {
+	if err := tombstone.Validate(); err != nil {
+		return err
+	}
+
+	return rw.w.WriteTombstone(ctx, tombstone)
+}
+
+// Tombstones returns the pending and completed tombstones of the tenant.
+func (rw *readerWriter) Tombstones(ctx context.Context, tenantID string) ([]*backend.Tombstone, error) {
+	return rw.r.Tombstones(ctx, tenantID)
+}
+
+// tombstonedTraces are the IDs of the traces of pending tombstones by tenant. Compaction drops them from every
+// block it writes, so traces aren't brought back by compacting a block while a tombstone is applied to it.
+type tombstonedTraces struct {
+	mtx     sync.Mutex
+	tenants map[string]map[string]struct{}
+}
+
+func (t *tombstonedTraces) set(tenantID string, ids map[string]struct{}) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if t.tenants == nil {
+		t.tenants = map[string]map[string]struct{}{}
+	}
+	if len(ids) == 0 {
+		delete(t.tenants, tenantID)
+		return
+	}
+	t.tenants[tenantID] = ids
+}
+
+// containsTraceID returns a func that reports whether the trace ID is in the set of hex encoded IDs.
+func containsTraceID(ids map[string]struct{}) func(common.ID) bool {
+	return func(id common.ID) bool {
+		_, ok := ids[util.TraceIDToHexString(id)]
+		return ok
+	}
+}
+
+// dropObject returns a func for common.CompactionOptions that drops the tombstoned traces of the tenant. It
+// returns nil if there are none.
+func (t *tombstonedTraces) dropObject(tenantID string) func(common.ID) bool {
+	t.mtx.Lock()
+	ids := t.tenants[tenantID]
+	t.mtx.Unlock()
+
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return containsTraceID(ids)
+}
+
+// applyTombstones applies the pending tombstones of the tenant that are owned by this compactor. Every block
+// the tombstone overlaps is checked for the deleted traces and rewritten without them. A tombstone is completed
+// once all blocks have been checked and the grace period has passed, after which no more traces can be
+// flushed to the time range.
+func (rw *readerWriter) applyTombstones(ctx context.Context, tenantID string) {
+	tombstones, err := rw.r.Tombstones(ctx, tenantID)
+	if err != nil {
+		level.Error(rw.logger).Log("msg", "failed to list tombstones", "tenantID", tenantID, "err", err)
+		metricTombstoneErrors.Inc()
+		return
+	}
+
+	// the trace ids of all pending tombstones are dropped by every compaction of the tenant
+	ids := map[string]struct{}{}
+	for _, t := range tombstones {
+		if t.Completed() {
+			continue
+		}
+		for _, id := range t.TraceIDs {
+			traceID, err := util.HexStringToTraceID(id)
+			if err != nil {
+				continue
+			}
+			ids[util.TraceIDToHexString(traceID)] = struct{}{}
+		}
+	}
+	rw.tombstones.set(tenantID, ids)
+
+	for _, t := range tombstones {
+		if ctx.Err() != nil {
+			return
+		}
+
+		if !rw.compactorSharder.Owns(tenantID + t.ID) {
+			continue
+		}
+
+		if t.Completed() {
+			// completed tombstones are kept as a record of the deletion until the retention has passed
+			if retention := rw.compactorCfg.BlockRetention; retention > 0 && time.Since(t.CompletedAt) > retention {
+				err = rw.w.Delete(ctx, backend.TombstoneFileName(t.ID), backend.KeyPathForTombstones(tenantID))
+				if err != nil {
+					level.Error(rw.logger).Log("msg", "failed to delete tombstone", "tenantID", tenantID, "tombstone", t.ID, "err", err)
+					metricTombstoneErrors.Inc()
+				}
+			}
+			continue
+		}
+
+		if err := rw.applyTombstone(ctx, tenantID, t); err != nil {
+			level.Error(rw.logger).Log("msg", "failed to apply tombstone", "tenantID", tenantID, "tombstone", t.ID, "err", err)
+			metricTombstoneErrors.Inc()
+		}
+	}
+}
+
+func (rw *readerWriter) applyTombstone(ctx context.Context, tenantID string, t *backend.Tombstone) error {
+	metas := rw.blocklist.Metas(tenantID)
+
+	// forget blocks that are gone, they have been compacted and the output blocks are checked instead
+	checked := len(t.CheckedBlocks)
+	t.CheckedBlocks = slices.DeleteFunc(t.CheckedBlocks, func(id backend.UUID) bool {
+		return !slices.ContainsFunc(metas, func(m *backend.BlockMeta) bool { return m.BlockID == id })
+	})
+	changed := checked != len(t.CheckedBlocks)
+
+	// the time after which no more blocks with the deleted traces can appear
+	settled := t.CreatedAt
+	if t.End.After(settled) {
+		settled = t.End
+	}
+	settled = settled.Add(rw.compactorCfg.TombstoneGracePeriod)
+	pending := time.Now().Before(settled)
+
+	var applyErr error
+	for _, meta := range metas {
+		if ctx.Err() != nil {
+			applyErr = ctx.Err()
+			break
+		}
+
+		if !t.Overlaps(meta) || slices.Contains(t.CheckedBlocks, meta.BlockID) {
+			continue
+		}
+
+		ids, err := rw.tombstonedTracesInBlock(ctx, meta, t)
+		if err != nil {
+			applyErr = fmt.Errorf("failed to find tombstoned traces in block %s: %w", meta.BlockID, err)
+			break
+		}
+
+		if len(ids) > 0 {
+			level.Info(rw.logger).Log("msg", "rewriting block to delete tombstoned traces", "tenantID", tenantID, "tombstone", t.ID, "blockID", meta.BlockID, "traces", len(ids))
+
+			// the output block doesn't contain the traces anymore. it's checked on the next cycle which is cheap for
+			// trace ids thanks to the bloom filters
+			err = rw.compactOneJobWithDrop(ctx, []*backend.BlockMeta{meta}, tenantID, containsTraceID(ids))
+			if err != nil {
+				applyErr = fmt.Errorf("failed to rewrite block %s: %w", meta.BlockID, err)
+				break
+			}
+
+			t.BlocksRewritten++
+			t.TracesDeleted += len(ids)
+			metricTombstoneTracesDeleted.Add(float64(len(ids)))
+			changed = true
+			continue
+		}
+
+		t.CheckedBlocks = append(t.CheckedBlocks, meta.BlockID)
+		changed = true
+	}
+
+	if applyErr == nil && !pending && rw.tombstoneCheckedAllBlocks(tenantID, t) {
+		t.CompletedAt = time.Now()
+		t.CheckedBlocks = nil
+		changed = true
+		metricTombstonesCompleted.Inc()
+
+		level.Info(rw.logger).Log("msg", "tombstone completed", "tenantID", tenantID, "tombstone", t.ID, "blocksRewritten", t.BlocksRewritten, "tracesDeleted", t.TracesDeleted)
+	}
+
+	// record the progress even if the tombstone couldn't be fully applied
+	if changed {
+		if err := rw.w.WriteTombstone(ctx, t); err != nil {
+			return err
+		}
+	}
+
+	return applyErr
+}
+
+// tombstoneCheckedAllBlocks returns true if no block of the tenant can contain traces deleted by the tombstone.
+func (rw *readerWriter) tombstoneCheckedAllBlocks(tenantID string, t *backend.Tombstone) bool {
+	for _, meta := range rw.blocklist.Metas(tenantID) {
+		if t.Overlaps(meta) && !slices.Contains(t.CheckedBlocks, meta.BlockID) {
+			return false
+		}
+	}
+	return true
+}
+
+// tombstonedTracesInBlock returns the hex encoded IDs of the traces in the block that are deleted by the
+// tombstone.
+func (rw *readerWriter) tombstonedTracesInBlock(ctx context.Context, meta *backend.BlockMeta, t *backend.Tombstone) (map[string]struct{}, error) {
+	block, err := encoding.OpenBlock(meta, rw.r)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := common.DefaultSearchOptions()
+	if rw.cfg.Search != nil {
+		rw.cfg.Search.ApplyToOptions(&opts)
+	}
+
+	ids := map[string]struct{}{}
+
+	if t.Query == "" {
+		for _, id := range t.TraceIDs {
+			traceID, err := util.HexStringToTraceID(id)
+			if err != nil {
+				return nil, err
+			}
+
+			tr, err := block.FindTraceByID(ctx, traceID, opts)
+			if err != nil {
+				return nil, err
+			}
+			if tr != nil {
+				ids[util.TraceIDToHexString(traceID)] = struct{}{}
+			}
+		}
+		return ids, nil
+	}
+
+	if err := searchTraceIDs(ctx, block, t.Query, t.Start, t.End, opts, ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// searchTraceIDs adds the hex encoded IDs of all traces in the block matching the TraceQL query to ids.
+func searchTraceIDs(ctx context.Context, block common.BackendBlock, query string, start, end time.Time, opts common.SearchOptions, ids map[string]struct{}) error {
+	// a limit of 0 returns every matching trace
+	req := &tempopb.SearchRequest{
+		Query: query,
+		Start: uint32(start.Unix()),
+		End:   uint32(end.Unix()),
+	}
+	fetcher := traceql.NewSpansetFetcherWrapper(func(ctx context.Context, req traceql.FetchSpansRequest) (traceql.FetchSpansResponse, error) {
+		return block.Fetch(ctx, req, opts)
+	})
+
+	resp, err := traceql.NewEngine().ExecuteSearch(ctx, req, fetcher)
+	if err != nil {
+		return err
+	}
+	for _, tr := range resp.Traces {
+		traceID, err := util.HexStringToTraceID(tr.TraceID)
+		if err != nil {
+			return err
+		}
+		ids[util.TraceIDToHexString(traceID)] = struct{}{}
+	}
+
+	return nil
+}
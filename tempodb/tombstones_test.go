@@ -0,0 +1,220 @@
+package tempodb
+
+import (
+	"context"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/tempo/pkg/util"
+	"github.com/grafana/tempo/pkg/util/test"
+	"github.com/grafana/tempo/tempodb/backend"
+	"github.com/grafana/tempo/tempodb/backend/local"
+	"github.com/grafana/tempo/tempodb/encoding"
+	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/pool"
+	"github.com/grafana/tempo/tempodb/wal"
+)
+
+func TestApplyTombstones(t *testing.T) {
+	tcs := []struct {
+		name    string
+		deleted func(i, j int) bool
+		// creates the tombstone for the deleted traces
+		tombstone func(deleted [][]byte) *backend.Tombstone
+	}{
+		{
+			name:    "trace ids",
+			deleted: func(i, j int) bool { return j == 3 || (i == 1 && j == 5) },
+			tombstone: func(deleted [][]byte) *backend.Tombstone {
+				ids := make([]string, 0, len(deleted))
+				for _, id := range deleted {
+					ids = append(ids, util.TraceIDToHexString(id))
+				}
+				return backend.NewTombstone(testTenantID, ids, "", time.Time{}, time.Time{})
+			},
+		},
+		{
+			name:    "query",
+			deleted: func(_, j int) bool { return j%2 == 0 },
+			tombstone: func([][]byte) *backend.Tombstone {
+				return backend.NewTombstone(testTenantID, nil, `{ name = "delete-me" }`, time.Now().Add(-time.Hour), time.Now().Add(-time.Minute))
+			},
+		},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r, w, c := testTombstonesStore(t)
+			rw := r.(*readerWriter)
+			ctx := context.Background()
+
+			// a query tombstone is pending until its end has passed. write traces before it
+			ts := time.Now().Add(-10 * time.Minute)
+
+			var (
+				deleted [][]byte
+				kept    [][]byte
+			)
+			for i := 0; i < 2; i++ {
+				data := make([]testData, 0, 10)
+				for j := 0; j < 10; j++ {
+					id := makeTraceID(i, j)
+					tr := test.MakeTraceWithTimeRange(1, id, uint64(ts.UnixNano()), uint64(ts.Add(time.Second).UnixNano()))
+					if tc.deleted(i, j) {
+						for _, ss := range tr.ResourceSpans[0].ScopeSpans {
+							for _, s := range ss.Spans {
+								s.Name = "delete-me"
+							}
+						}
+						deleted = append(deleted, id)
+					} else {
+						kept = append(kept, id)
+					}
+					data = append(data, testData{id: id, t: tr, start: uint32(ts.Unix()), end: uint32(ts.Unix()) + 1})
+				}
+				cutTestBlockWithTraces(t, w, data)
+			}
+			rw.pollBlocklist()
+
+			tombstone := tc.tombstone(deleted)
+			require.NoError(t, c.CreateTombstone(ctx, tombstone))
+
+			// the first cycle rewrites the blocks, the second one checks the rewritten blocks and completes the tombstone
+			rw.applyTombstones(ctx, testTenantID)
+			tombstones, err := c.Tombstones(ctx, testTenantID)
+			require.NoError(t, err)
+			require.Len(t, tombstones, 1)
+			require.False(t, tombstones[0].Completed())
+			require.Equal(t, len(deleted), tombstones[0].TracesDeleted)
+
+			rw.applyTombstones(ctx, testTenantID)
+			tombstones, err = c.Tombstones(ctx, testTenantID)
+			require.NoError(t, err)
+			require.Len(t, tombstones, 1)
+			require.True(t, tombstones[0].Completed())
+			require.Equal(t, tombstone.ID, tombstones[0].ID)
+			require.Equal(t, 2, tombstones[0].BlocksRewritten)
+			require.Equal(t, len(deleted), tombstones[0].TracesDeleted)
+			require.Empty(t, tombstones[0].CheckedBlocks)
+
+			// the traces are gone from the blocks that replaced the rewritten ones
+			for _, id := range deleted {
+				require.Zero(t, countTraceInBlocks(t, rw, id))
+			}
+			for _, id := range kept {
+				require.Equal(t, 1, countTraceInBlocks(t, rw, id))
+			}
+
+			// completed tombstones are no longer applied
+			rw.applyTombstones(ctx, testTenantID)
+			require.Nil(t, rw.tombstones.dropObject(testTenantID))
+		})
+	}
+}
+
+func TestApplyTombstonesWaitsForGracePeriod(t *testing.T) {
+	r, w, c := testTombstonesStore(t)
+	rw := r.(*readerWriter)
+	rw.compactorCfg.TombstoneGracePeriod = time.Hour
+	ctx := context.Background()
+
+	id := makeTraceID(0, 0)
+	keptID := makeTraceID(0, 1)
+	cutTestBlockWithTraces(t, w, []testData{
+		{id: id, t: test.MakeTrace(1, id)},
+		{id: keptID, t: test.MakeTrace(1, keptID)},
+	})
+	rw.pollBlocklist()
+
+	tombstone := backend.NewTombstone(testTenantID, []string{util.TraceIDToHexString(id)}, "", time.Time{}, time.Time{})
+	require.NoError(t, c.CreateTombstone(ctx, tombstone))
+
+	rw.applyTombstones(ctx, testTenantID)
+	rw.applyTombstones(ctx, testTenantID)
+
+	tombstones, err := c.Tombstones(ctx, testTenantID)
+	require.NoError(t, err)
+	require.Len(t, tombstones, 1)
+	require.False(t, tombstones[0].Completed())
+	require.Equal(t, 1, tombstones[0].TracesDeleted)
+	require.Len(t, tombstones[0].CheckedBlocks, 1)
+
+	// pending trace ids are dropped by regular compactions as well
+	drop := rw.tombstones.dropObject(testTenantID)
+	require.NotNil(t, drop)
+	require.True(t, drop(id))
+	require.False(t, drop(keptID))
+}
+
+func TestCreateTombstoneValidates(t *testing.T) {
+	_, _, c := testTombstonesStore(t)
+
+	err := c.CreateTombstone(context.Background(), backend.NewTombstone(testTenantID, nil, "{ }", time.Time{}, time.Time{}))
+	require.EqualError(t, err, "tombstone with a query requires a start and an end")
+
+	tombstones, err := c.Tombstones(context.Background(), testTenantID)
+	require.NoError(t, err)
+	require.Empty(t, tombstones)
+}
+
+func countTraceInBlocks(t *testing.T, rw *readerWriter, id []byte) int {
+	count := 0
+	for _, meta := range rw.blocklist.Metas(testTenantID) {
+		block, err := encoding.OpenBlock(meta, rw.r)
+		require.NoError(t, err)
+
+		tr, err := block.FindTraceByID(context.Background(), id, common.DefaultSearchOptions())
+		require.NoError(t, err)
+		if tr != nil {
+			count++
+		}
+	}
+	return count
+}
+
+func testTombstonesStore(t *testing.T) (Reader, Writer, Compactor) {
+	tempDir := t.TempDir()
+
+	r, w, c, err := New(&Config{
+		Backend: backend.Local,
+		Pool: &pool.Config{
+			MaxWorkers: 10,
+			QueueDepth: 100,
+		},
+		Local: &local.Config{
+			Path: path.Join(tempDir, "traces"),
+		},
+		Block: &common.BlockConfig{
+			IndexDownsampleBytes: 11,
+			BloomFP:              .01,
+			BloomShardSizeBytes:  100_000,
+			Version:              encoding.DefaultEncoding().Version(),
+			Encoding:             backend.EncNone,
+			IndexPageSizeBytes:   1000,
+			RowGroupSizeBytes:    30_000_000,
+		},
+		WAL: &wal.Config{
+			Filepath: path.Join(tempDir, "wal"),
+			// keep the time ranges of the test traces
+			IngestionSlack: time.Hour,
+		},
+		BlocklistPoll: 0,
+	}, nil, log.NewNopLogger())
+	require.NoError(t, err)
+
+	err = c.EnableCompaction(context.Background(), &CompactorConfig{
+		ChunkSizeBytes:       10_000_000,
+		FlushSizeBytes:       10_000_000,
+		MaxCompactionRange:   24 * time.Hour,
+		TombstoneGracePeriod: time.Nanosecond,
+	}, &mockSharder{}, &mockOverrides{})
+	require.NoError(t, err)
+
+	r.EnablePolling(context.Background(), &mockJobSharder{})
+
+	return r, w, c
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-kit/log"
@@ -16,8 +18,11 @@ import (
 )
 
 const (
-	completedDir = "completed"
-	blocksDir    = "blocks"
+	completedDir  = "completed"
+	blocksDir     = "blocks"
+	liveTracesDir = "live_traces"
+	// default file of the key the completed blocks are encrypted with
+	encryptionKeyFile = "encryption.key"
 )
 
 type WAL struct {
@@ -31,6 +36,15 @@ type Config struct {
 	SearchEncoding backend.Encoding `yaml:"search_encoding"`
 	IngestionSlack time.Duration    `yaml:"ingestion_time_range_slack"`
 	Version        string           `yaml:"version,omitempty"`
+
+	// EncryptCompletedBlocks encrypts the completed blocks with the key in EncryptionKeyFile.
+	EncryptCompletedBlocks bool `yaml:"encrypt_completed_blocks"`
+	// EncryptionKeyFile holds the hex encoded AES-256 key of the completed blocks. If the file doesn't exist a random
+	// key is generated and persisted to it. Defaults to a file in the wal folder.
+	EncryptionKeyFile string `yaml:"encryption_key_file"`
+
+	// VersionForTenant returns the version new blocks of a tenant are created in, or an empty string to use Version.
+	VersionForTenant func(tenantID string) string `yaml:"-"`
 }
 
 func (c *Config) RegisterFlags(*flag.FlagSet) {
@@ -63,9 +77,19 @@ func New(c *Config) (*WAL, error) {
 		return nil, err
 	}
 
-	l, err := local.NewBackend(&local.Config{
+	var l *local.Backend
+	localCfg := &local.Config{
 		Path: blocksFolderPath,
-	})
+	}
+	if c.EncryptCompletedBlocks {
+		keyFile := c.EncryptionKeyFile
+		if keyFile == "" {
+			keyFile = filepath.Join(c.Filepath, encryptionKeyFile)
+		}
+		l, err = local.NewEncryptedBackend(localCfg, keyFile)
+	} else {
+		l, err = local.NewBackend(localCfg)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -78,14 +102,43 @@ func New(c *Config) (*WAL, error) {
 
 // RescanBlocks returns a slice of append blocks from the wal folder
 func (w *WAL) RescanBlocks(additionalStartSlack time.Duration, log log.Logger) ([]common.WALBlock, error) {
-	files, err := os.ReadDir(w.c.Filepath)
+	return w.RescanBlocksConcurrently(additionalStartSlack, 1, nil, log)
+}
+
+// ReplayProgress is notified about the progress of a wal replay. Total is called once with the number and size of
+// the files to replay, Replayed after every replayed file.
+type ReplayProgress interface {
+	Total(files int, bytes int64)
+	Replayed(bytes int64)
+}
+
+type walFile struct {
+	entry os.DirEntry
+	owner encoding.VersionedEncoding
+	size  int64
+}
+
+// RescanBlocksConcurrently returns a slice of append blocks from the wal folder. Up to concurrency files are
+// replayed at the same time. The files of different tenants are replayed in parallel, so a tenant with many files
+// doesn't hold up the others. The blocks are returned in the order of the files in the wal folder.
+func (w *WAL) RescanBlocksConcurrently(additionalStartSlack time.Duration, concurrency int, progress ReplayProgress, log log.Logger) ([]common.WALBlock, error) {
+	entries, err := os.ReadDir(w.c.Filepath)
 	if err != nil {
 		return nil, err
 	}
 
 	encodings := encoding.AllEncodings()
-	blocks := make([]common.WALBlock, 0, len(files))
-	for _, f := range files {
+	files := make([]walFile, 0, len(entries))
+	var totalBytes int64
+	for _, f := range entries {
+		// snapshots of live traces aren't wal blocks
+		if f.IsDir() && f.Name() == liveTracesDir {
+			continue
+		}
+		if !f.IsDir() && f.Name() == encryptionKeyFile {
+			continue
+		}
+
 		// find owner
 		var owner encoding.VersionedEncoding
 		for _, e := range encodings {
@@ -100,55 +153,152 @@ func (w *WAL) RescanBlocks(additionalStartSlack time.Duration, log log.Logger) (
 			continue
 		}
 
-		start := time.Now()
 		fileInfo, err := f.Info()
 		if err != nil {
 			return nil, err
 		}
 
-		level.Info(log).Log("msg", "beginning replay", "file", f.Name(), "size", fileInfo.Size())
-		b, warning, err := owner.OpenWALBlock(f.Name(), w.c.Filepath, w.c.IngestionSlack, additionalStartSlack)
+		files = append(files, walFile{entry: f, owner: owner, size: fileInfo.Size()})
+		totalBytes += fileInfo.Size()
+	}
 
-		remove := false
-		if err != nil {
-			// wal replay failed, clear and warn
-			level.Warn(log).Log("msg", "failed to replay block. removing.", "file", f.Name(), "err", err)
-			remove = true
-		}
+	if progress != nil {
+		progress.Total(len(files), totalBytes)
+	}
 
-		if b != nil && b.DataLength() == 0 {
-			level.Warn(log).Log("msg", "empty wal file. ignoring.", "file", f.Name(), "err", err)
-			remove = true
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		blocks = make([]common.WALBlock, len(files))
+		errs   = make([]error, len(files))
+		wg     sync.WaitGroup
+		next   = make(chan int)
+	)
+	for j := 0; j < concurrency; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				blocks[i], errs[i] = w.replayFile(files[i], additionalStartSlack, log)
+				if progress != nil {
+					progress.Replayed(files[i].size)
+				}
+			}
+		}()
+	}
+	for _, i := range tenantOrder(files) {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	replayed := make([]common.WALBlock, 0, len(blocks))
+	for i, b := range blocks {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if b != nil {
+			replayed = append(replayed, b)
 		}
+	}
+
+	return replayed, nil
+}
 
-		if warning != nil {
-			level.Warn(log).Log("msg", "received warning while replaying block. partial replay likely.", "file", f.Name(), "warning", warning, "length", b.DataLength())
+// tenantOrder returns the indexes of the files in the order they are replayed. The files of the tenants are
+// interleaved, the first file of every tenant is followed by the second file of every tenant and so on.
+func tenantOrder(files []walFile) []int {
+	var (
+		tenants  []string
+		byTenant = map[string][]int{}
+	)
+	for i, f := range files {
+		tenant := walFileTenant(f.entry.Name())
+		if _, ok := byTenant[tenant]; !ok {
+			tenants = append(tenants, tenant)
 		}
+		byTenant[tenant] = append(byTenant[tenant], i)
+	}
 
-		if remove {
-			err = os.RemoveAll(filepath.Join(w.c.Filepath, f.Name()))
-			if err != nil {
-				return nil, err
+	order := make([]int, 0, len(files))
+	for round := 0; len(order) < len(files); round++ {
+		for _, tenant := range tenants {
+			if idxs := byTenant[tenant]; round < len(idxs) {
+				order = append(order, idxs[round])
 			}
-			continue
 		}
+	}
+	return order
+}
 
-		level.Info(log).Log("msg", "replay complete", "file", f.Name(), "duration", time.Since(start))
+// walFileTenant returns the tenant of a wal file or folder named <blockID>+<tenantID>+... Files of all encodings
+// follow this scheme, old v2 files use : as separator. Names that don't are attributed to an empty tenant.
+func walFileTenant(name string) string {
+	sep := "+"
+	if !strings.Contains(name, sep) {
+		sep = ":"
+	}
+
+	splits := strings.SplitN(name, sep, 3)
+	if len(splits) < 3 {
+		return ""
+	}
+	return splits[1]
+}
 
-		blocks = append(blocks, b)
+// replayFile opens the wal block of the file. It returns a nil block if the file was removed because it couldn't
+// be replayed.
+func (w *WAL) replayFile(f walFile, additionalStartSlack time.Duration, log log.Logger) (common.WALBlock, error) {
+	start := time.Now()
+
+	level.Info(log).Log("msg", "beginning replay", "file", f.entry.Name(), "size", f.size)
+	b, warning, err := f.owner.OpenWALBlock(f.entry.Name(), w.c.Filepath, w.c.IngestionSlack, additionalStartSlack)
+
+	remove := false
+	if err != nil {
+		// wal replay failed, clear and warn
+		level.Warn(log).Log("msg", "failed to replay block. removing.", "file", f.entry.Name(), "err", err)
+		remove = true
 	}
 
-	return blocks, nil
+	if b != nil && b.DataLength() == 0 {
+		level.Warn(log).Log("msg", "empty wal file. ignoring.", "file", f.entry.Name(), "err", err)
+		remove = true
+	}
+
+	if warning != nil {
+		level.Warn(log).Log("msg", "received warning while replaying block. partial replay likely.", "file", f.entry.Name(), "warning", warning, "length", b.DataLength())
+	}
+
+	if remove {
+		return nil, os.RemoveAll(filepath.Join(w.c.Filepath, f.entry.Name()))
+	}
+
+	level.Info(log).Log("msg", "replay complete", "file", f.entry.Name(), "duration", time.Since(start))
+
+	return b, nil
 }
 
 func (w *WAL) NewBlock(meta *backend.BlockMeta, dataEncoding string) (common.WALBlock, error) {
-	v, err := encoding.FromVersion(w.c.Version)
+	v, err := encoding.FromVersion(w.version(meta.TenantID))
 	if err != nil {
 		return nil, err
 	}
 	return v.CreateWALBlock(meta, w.c.Filepath, dataEncoding, w.c.IngestionSlack)
 }
 
+// version returns the version of the tenant's new blocks.
+func (w *WAL) version(tenantID string) string {
+	if w.c.VersionForTenant != nil {
+		if v := w.c.VersionForTenant(tenantID); v != "" {
+			return v
+		}
+	}
+	return w.c.Version
+}
+
 func (w *WAL) GetFilepath() string {
 	return w.c.Filepath
 }
@@ -157,6 +307,11 @@ func (w *WAL) Clear() error {
 	return os.RemoveAll(w.c.Filepath)
 }
 
+// LiveTracesPath returns the folder for snapshots of the traces that haven't been written to the wal yet.
+func (w *WAL) LiveTracesPath() string {
+	return filepath.Join(w.c.Filepath, liveTracesDir)
+}
+
 func (w *WAL) LocalBackend() *local.Backend {
 	return w.l
 }
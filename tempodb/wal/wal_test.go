@@ -26,6 +26,8 @@ import (
 	"github.com/grafana/tempo/tempodb/backend"
 	"github.com/grafana/tempo/tempodb/encoding"
 	"github.com/grafana/tempo/tempodb/encoding/common"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet3"
+	"github.com/grafana/tempo/tempodb/encoding/vparquet4"
 )
 
 func TestAppendBlockStartEnd(t *testing.T) {
@@ -570,3 +572,51 @@ func runWALBenchmarkWithAppendMode(b *testing.B, encoding string, flushCount int
 		runner(ids, traces, block)
 	}
 }
+
+func TestTenantOrder(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a+1+v", "b+1+v", "c+2+v", "d+1+v", "e:3:v", "f"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0o600))
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	files := make([]walFile, 0, len(entries))
+	for _, e := range entries {
+		files = append(files, walFile{entry: e})
+	}
+
+	// the first files of tenants 1, 2, 3 and the unknown tenant, then the remaining files of tenant 1
+	require.Equal(t, []int{0, 2, 4, 5, 1, 3}, tenantOrder(files))
+}
+
+func TestWALFileTenant(t *testing.T) {
+	require.Equal(t, "tenant", walFileTenant("00000000-0000-0000-0000-000000000000+tenant+vParquet4"))
+	require.Equal(t, "tenant", walFileTenant("00000000-0000-0000-0000-000000000000:tenant:v2:snappy:v2"))
+	require.Equal(t, "", walFileTenant("00000000-0000-0000-0000-000000000000+tenant"))
+	require.Equal(t, "", walFileTenant("blocks"))
+}
+
+func TestNewBlockVersionForTenant(t *testing.T) {
+	wal, err := New(&Config{
+		Filepath: t.TempDir(),
+		Version:  vparquet3.VersionString,
+		VersionForTenant: func(tenantID string) string {
+			if tenantID == "migrated" {
+				return vparquet4.VersionString
+			}
+			return ""
+		},
+	})
+	require.NoError(t, err)
+
+	for tenantID, version := range map[string]string{
+		"migrated": vparquet4.VersionString,
+		"other":    vparquet3.VersionString,
+	} {
+		block, err := wal.NewBlock(&backend.BlockMeta{BlockID: backend.NewUUID(), TenantID: tenantID}, model.CurrentEncoding)
+		require.NoError(t, err)
+		require.Equal(t, version, block.BlockMeta().Version)
+	}
+}